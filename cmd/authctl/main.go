@@ -0,0 +1,236 @@
+// Command authctl runs maintenance operations against the data stores of the auth-microservice.
+// It reads the same environment configuration as the server.
+//
+// Usage:
+//
+//	authctl anonymize-user --id <user-id>
+//	authctl email-duplicates
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/user"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/config"
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/postgres"
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/rabbitmq"
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/redis"
+)
+
+// commandTimeout bounds the time a command may spend on the data stores
+const commandTimeout = 30 * time.Second
+
+func usage() {
+	fmt.Fprintf(os.Stderr, `Usage: authctl <command> [flags]
+
+Commands:
+  anonymize-user --id <user-id>   Erase the personal data of a user (GDPR erasure)
+  email-duplicates                List the users whose emails differ only by case
+`)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "anonymize-user":
+		err = runAnonymizeUser(os.Args[2:])
+	case "email-duplicates":
+		err = runEmailDuplicates(os.Args[2:])
+	case "help", "-h", "--help":
+		usage()
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// runAnonymizeUser scrubs the personal data of a user, revokes their sessions,
+// writes an audit record and publishes a user.anonymized event
+func runAnonymizeUser(args []string) error {
+	flags := flag.NewFlagSet("anonymize-user", flag.ContinueOnError)
+	id := flags.String("id", "", "ID of the user to anonymize")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *id == "" {
+		return errors.New("--id is required")
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	defer func() {
+		_ = logger.Sync()
+	}()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	db, err := postgres.NewDB(cfg.DatabaseConnectionString(), postgres.Naming{
+		Schema:      cfg.Database.Schema,
+		TablePrefix: cfg.Database.TablePrefix,
+	}, postgres.PoolConfig{
+		MaxOpenConns:    cfg.Database.MaxOpenConns,
+		MaxIdleConns:    cfg.Database.MaxIdleConns,
+		ConnMaxLifetime: cfg.Database.ConnMaxLifetime,
+	}, logger)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	redisClient, err := redis.NewRedisClient(cfg.RedisAddress(), cfg.Redis.Password, cfg.Redis.DB, cfg.Redis.CommandTimeout, logger)
+	if err != nil {
+		return fmt.Errorf("failed to connect to redis: %w", err)
+	}
+	defer func() {
+		_ = redisClient.Close()
+	}()
+
+	// Unlike the server, the event cannot be published later, so RabbitMQ must be reachable
+	rbClient, err := rabbitmq.NewRabbitMQClient(cfg.RabbitMQ)
+	if err != nil {
+		return fmt.Errorf("failed to connect to rabbitmq: %w", err)
+	}
+	defer func() {
+		_ = rbClient.Close()
+	}()
+	if rbClient.IsClosed() {
+		return errors.New("rabbitmq is not reachable")
+	}
+	rbPublisher, err := rabbitmq.NewRabbitMQPublisher(rbClient)
+	if err != nil {
+		return fmt.Errorf("failed to create rabbitmq publisher: %w", err)
+	}
+	defer func() {
+		_ = rbPublisher.Close()
+	}()
+
+	dbRetrier := postgres.NewRetrier(postgres.RetryPolicy{
+		MaxAttempts:        cfg.Database.RetryMaxAttempts,
+		InitialBackoff:     cfg.Database.RetryInitialBackoff,
+		MaxBackoff:         cfg.Database.RetryMaxBackoff,
+		BudgetRatio:        cfg.Database.RetryBudgetRatio,
+		OperationTimeout:   cfg.Database.OperationTimeout,
+		SlowQueryThreshold: cfg.Database.SlowQueryThreshold,
+
+		MaxConcurrentOperations: cfg.Database.MaxOpenConns,
+		PoolWaitTimeout:         cfg.Database.PoolWaitTimeout,
+	}, logger)
+
+	anonymizationService := services.NewAnonymizationService(
+		postgres.NewUserRepository(db, dbRetrier, cfg.Database.IDFormat.Generator(), logger),
+		redis.NewTokenRepository(redisClient, logger),
+		postgres.NewPhoneNumberRepository(db, dbRetrier, logger),
+		postgres.NewUserEmailRepository(db, dbRetrier, logger),
+		postgres.NewAvatarRepository(db, dbRetrier, logger),
+		postgres.NewAuditLogRepository(db, dbRetrier, logger),
+		// An event that cannot be published is left in the outbox for the server to relay
+		services.NewOutboxPublisher(rbPublisher, postgres.NewOutboxRepository(db, dbRetrier, logger), logger),
+		cfg.RabbitMQ.UserAnonymizedQueue,
+		logger,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	anonymized, err := anonymizationService.AnonymizeUser(ctx, *id, "authctl:"+operator())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("user %s anonymized\n", anonymized.ID)
+	return nil
+}
+
+// runEmailDuplicates reports the users whose emails differ only by case. Until they are resolved, by
+// anonymizing or changing the email of all but one account of each group, the server cannot create the
+// case-insensitive unique index on the email.
+func runEmailDuplicates(args []string) error {
+	flags := flag.NewFlagSet("email-duplicates", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	defer func() {
+		_ = logger.Sync()
+	}()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	db, err := postgres.NewDB(cfg.DatabaseConnectionString(), postgres.Naming{
+		Schema:      cfg.Database.Schema,
+		TablePrefix: cfg.Database.TablePrefix,
+	}, postgres.PoolConfig{
+		MaxOpenConns:    cfg.Database.MaxOpenConns,
+		MaxIdleConns:    cfg.Database.MaxIdleConns,
+		ConnMaxLifetime: cfg.Database.ConnMaxLifetime,
+	}, logger)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	duplicates, err := postgres.FindEmailCaseDuplicates(ctx, db)
+	if err != nil {
+		return err
+	}
+	if len(duplicates) == 0 {
+		fmt.Println("no emails differing only by case")
+		return nil
+	}
+
+	for _, duplicate := range duplicates {
+		fmt.Printf("%s\n", duplicate.Email)
+		for i, id := range duplicate.UserIDs {
+			fmt.Printf("  %s  %s\n", id, duplicate.Emails[i])
+		}
+	}
+	fmt.Printf("%d emails shared by several users\n", len(duplicates))
+	return nil
+}
+
+// operator returns the OS user running the command, recorded as the actor of the audit record
+func operator() string {
+	if current, err := user.Current(); err == nil && current.Username != "" {
+		return current.Username
+	}
+	return "unknown"
+}
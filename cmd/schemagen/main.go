@@ -0,0 +1,52 @@
+// Command schemagen writes the JSON Schema documents of the request and response DTOs, one file per DTO,
+// so consumer teams can validate their payloads in CI. It runs with go generate:
+//
+//	go generate ./internal/adapters/http/dto/schema
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/schema"
+)
+
+func main() {
+	out := flag.String("out", "docs/schemas", "directory the documents are written to")
+	flag.Parse()
+
+	if err := run(*out); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run replaces the documents in dir, so the schemas of removed DTOs don't linger
+func run(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	stale, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, path := range stale {
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+	}
+
+	for _, document := range schema.Documents() {
+		data, err := document.Marshal()
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", document.Name, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, document.Name+".json"), data, 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", document.Name, err)
+		}
+	}
+	return nil
+}
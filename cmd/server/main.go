@@ -2,24 +2,46 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"crypto/fips140"
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
+	"errors"
+	"flag"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
+	goredis "github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+	"golang.org/x/net/netutil"
 
 	httpAdapter "github.com/kristianrpo/auth-microservice/internal/adapters/http"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
 	"github.com/kristianrpo/auth-microservice/internal/application/ports"
 	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/auditsink"
 	"github.com/kristianrpo/auth-microservice/internal/infrastructure/config"
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/geoip"
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/hashing"
 	httpClient "github.com/kristianrpo/auth-microservice/internal/infrastructure/http"
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/kafka"
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/memory"
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/nats"
 	"github.com/kristianrpo/auth-microservice/internal/infrastructure/postgres"
 	"github.com/kristianrpo/auth-microservice/internal/infrastructure/rabbitmq"
 	"github.com/kristianrpo/auth-microservice/internal/infrastructure/redis"
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/sms"
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/storage"
+	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
 
 	_ "github.com/kristianrpo/auth-microservice/docs" // Swagger docs
 )
@@ -48,86 +70,159 @@ import (
 // @tag.description Endpoints related to authentication and authorization
 
 // @tag.name OAuth2
-// @tag.description OAuth2 endpoints for service-to-service communication
+// @tag.description OAuth2 endpoints for service-to-service communication and the device authorization grant
 
 // @tag.name Admin - OAuth Clients
 // @tag.description Admin endpoints for managing OAuth2 clients (requires ADMIN role)
 
+// @tag.name Admin - Users
+// @tag.description Admin endpoints for managing users (requires ADMIN role)
+
+// @tag.name Errors
+// @tag.description Catalog of the error codes returned by the API
+
 // @tag.name Health
 // @tag.description Endpoints for checking the service status
 
-// setupRabbitMQ initializes RabbitMQ client and consumer
-func setupRabbitMQ(
+// Warm-up steps of the readiness gate
+const (
+	warmUpSchema    = "database schema"
+	warmUpConsumers = "message consumers"
+)
+
+// newMessageConsumer registers every consumed queue with its handler and consumer settings in the consumer
+// of the configured broker
+func newMessageConsumer(
 	cfg *config.Config,
-	userRepo ports.UserRepository,
-	tokenRepo ports.TokenRepository,
-	logger *zap.Logger,
-) (*rabbitmq.RabbitMQClient, context.CancelFunc, error) {
-	// Initialize RabbitMQ client
-	rbClient, err := rabbitmq.NewRabbitMQClient(cfg.RabbitMQ)
-	if err != nil {
-		logger.Warn("RabbitMQ not available at startup; will reconnect in background", zap.Error(err))
+	consumer ports.MessageConsumer,
+	userTransferredConsumer *services.UserTransferredConsumer,
+	userSyncConsumer *services.UserSyncConsumer,
+) (ports.MessageConsumer, error) {
+	queues := []struct {
+		queue    string
+		handler  ports.MessageHandler
+		settings config.ConsumerConfig
+	}{
+		{cfg.RabbitMQ.ConsumerQueue, userTransferredConsumer.Handle, cfg.RabbitMQ.UserTransferredConsumer},
+		{cfg.RabbitMQ.UserUpdatedQueue, userSyncConsumer.HandleUserUpdated, cfg.RabbitMQ.UserUpdatedConsumer},
+		{cfg.RabbitMQ.UserRoleChangedQueue, userSyncConsumer.HandleUserRoleChanged, cfg.RabbitMQ.UserRoleChangedConsumer},
 	}
 
-	// Initialize RabbitMQ Consumer
-	rbConsumer, err := rabbitmq.NewRabbitMQConsumer(rbClient)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to create RabbitMQ consumer: %w", err)
+	for _, q := range queues {
+		err := consumer.Register(ports.QueueSubscription{
+			Queue:           q.queue,
+			Handler:         q.handler,
+			Prefetch:        q.settings.Prefetch,
+			Concurrency:     q.settings.Concurrency,
+			MaxRetries:      q.settings.MaxRetries,
+			RetryDelay:      q.settings.RetryDelay,
+			DeadLetterQueue: q.settings.DeadLetterQueue,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to register queue %s: %w", q.queue, err)
+		}
 	}
+	return consumer, nil
+}
 
-	consumeCtx, consumeCancel := context.WithCancel(context.Background())
-
-	// Subscribe to user.transferred events
-	handler := createUserTransferredHandler(userRepo, tokenRepo, logger)
-	if err := rbConsumer.SubscribeToQueue(consumeCtx, cfg.RabbitMQ.ConsumerQueue, handler); err != nil {
-		consumeCancel()
-		return nil, nil, fmt.Errorf("failed to subscribe to RabbitMQ queue: %w", err)
+// newHealthGauges declares the gauges of the detailed health: the depth of the consumed queues, which grows
+// when the consumers fall behind, and the backlog of the outbox, which grows while the broker rejects messages
+func newHealthGauges(cfg *config.Config, rbClient *rabbitmq.RabbitMQClient, natsClient *nats.NATSClient, outboxRepo *postgres.OutboxRepository) []services.HealthGauge {
+	gauges := []services.HealthGauge{{Name: "outbox_backlog", Read: outboxRepo.Backlog}}
+	for _, queue := range []string{cfg.RabbitMQ.ConsumerQueue, cfg.RabbitMQ.UserUpdatedQueue, cfg.RabbitMQ.UserRoleChangedQueue} {
+		if natsClient != nil {
+			gauges = append(gauges, services.HealthGauge{
+				Name: "nats_queue_depth." + queue,
+				Read: func(ctx context.Context) (int64, error) {
+					return natsClient.QueueDepth(ctx, queue)
+				},
+			})
+			continue
+		}
+		gauges = append(gauges, services.HealthGauge{
+			Name: "rabbitmq_queue_depth." + queue,
+			Read: func(ctx context.Context) (int64, error) {
+				depth, err := rbClient.QueueDepth(queue)
+				return int64(depth), err
+			},
+		})
 	}
-
-	return rbClient, consumeCancel, nil
+	return gauges
 }
 
-// createUserTransferredHandler creates the handler for user.transferred events
-func createUserTransferredHandler(
-	userRepo ports.UserRepository,
-	tokenRepo ports.TokenRepository,
+// newDependencyManager declares the dependencies checked at startup and by the health endpoints.
+// The message broker, RabbitMQ or NATS, reconnects in the background, so by default the service runs
+// degraded without it. In the in-memory development mode none of them is declared.
+func newDependencyManager(
+	cfg *config.Config,
+	db *sql.DB,
+	redisClient *goredis.Client,
+	rbClient *rabbitmq.RabbitMQClient,
+	natsClient *nats.NATSClient,
+	devInMemory bool,
 	logger *zap.Logger,
-) ports.MessageHandler {
-	return func(ctx context.Context, message []byte) error {
-		type payload struct {
-			IDCitizen int `json:"idCitizen"`
-		}
-		var p payload
-		if err := json.Unmarshal(message, &p); err != nil {
-			logger.Error("failed to unmarshal user.transferred payload", zap.Error(err))
-			return nil // ACK malformed messages to avoid infinite loop
-		}
-
-		// Find user by id_citizen and delete
-		user, err := userRepo.GetByIDCitizen(ctx, p.IDCitizen)
-		if err != nil {
-			// If user doesn't exist, that's fine - they're already gone
-			logger.Info("user not found or already deleted", zap.Int("idCitizen", p.IDCitizen))
-			return nil // ACK the message
-		}
-
-		// Delete user tokens (best effort, don't fail if error)
-		if err := tokenRepo.DeleteUserTokens(ctx, user.IDCitizen); err != nil {
-			logger.Warn("failed deleting user tokens", zap.String("user_id", user.ID), zap.Error(err))
-		}
+) *services.DependencyManager {
+	policy := services.DependencyStartupPolicy{
+		MaxAttempts:    cfg.Startup.MaxAttempts,
+		InitialBackoff: cfg.Startup.InitialBackoff,
+		MaxBackoff:     cfg.Startup.MaxBackoff,
+		CheckTimeout:   cfg.Startup.CheckTimeout,
+	}
+	if devInMemory {
+		return services.NewDependencyManager(policy, logger)
+	}
 
-		// Delete user from database
-		if err := userRepo.Delete(ctx, user.ID); err != nil {
-			logger.Warn("failed to delete user", zap.String("user_id", user.ID), zap.Int("idCitizen", p.IDCitizen), zap.Error(err))
-			return nil // ACK anyway to avoid infinite loop
+	rabbitMQCriticality := domain.DependencyDegradedOK
+	if cfg.Startup.RabbitMQRequired {
+		rabbitMQCriticality = domain.DependencyRequired
+	}
+	broker := services.Dependency{
+		Name:        "rabbitmq",
+		Criticality: rabbitMQCriticality,
+		Check: func(ctx context.Context) error {
+			if rbClient.IsClosed() {
+				return errors.New("connection is closed")
+			}
+			return nil
+		},
+	}
+	if natsClient != nil {
+		broker = services.Dependency{
+			Name:        "nats",
+			Criticality: rabbitMQCriticality,
+			Check: func(ctx context.Context) error {
+				if !natsClient.IsConnected() {
+					return errors.New("not connected")
+				}
+				return nil
+			},
 		}
-
-		logger.Info("successfully processed user.transferred event", zap.Int("idCitizen", p.IDCitizen), zap.String("user_id", user.ID))
-		return nil
 	}
+
+	return services.NewDependencyManager(
+		policy,
+		logger,
+		services.Dependency{
+			Name:        "database",
+			Criticality: domain.DependencyRequired,
+			Check:       db.PingContext,
+		},
+		services.Dependency{
+			Name:        "redis",
+			Criticality: domain.DependencyRequired,
+			Check: func(ctx context.Context) error {
+				return redisClient.Ping(ctx).Err()
+			},
+		},
+		broker,
+	)
 }
 
 func main() {
+	devInMemory := flag.Bool("dev-inmemory", false, "keep users, tokens and OAuth clients in memory and run without Postgres, Redis or RabbitMQ (development only)")
+	devFixtures := flag.String("dev-fixtures", "fixtures/dev-users.json", "JSON file of the sample users seeded in -dev-inmemory mode, none when empty")
+	flag.Parse()
+
 	// Inicializar logger
 	logger, err := initLogger()
 	if err != nil {
@@ -151,72 +246,454 @@ func main() {
 		zap.String("server_address", cfg.ServerAddress()),
 	)
 
-	// Inicializar base de datos
-	db, err := postgres.NewDB(cfg.DatabaseConnectionString(), logger)
+	// The in-memory mode keeps no data across restarts and skips the dependency checks, never in production
+	if *devInMemory {
+		if cfg.App.Environment == "production" {
+			logger.Fatal("The in-memory development mode can't run in production")
+		}
+		logger.Warn("Running in in-memory development mode, users, tokens and OAuth clients are lost on restart")
+	}
+
+	// The configuration only selects approved primitives in fips mode, they must also run in the validated module
+	if cfg.Crypto.FIPS() {
+		if !fips140.Enabled() {
+			logger.Fatal("CRYPTO_MODE is fips but the Go Cryptographic Module is not in FIPS 140-3 mode, run with GODEBUG=fips140=on or build with GOFIPS140")
+		}
+		logger.Info("Running in FIPS mode")
+	}
+
+	// Inicializar base de datos (connectivity is checked with the other dependencies below)
+	db, err := postgres.OpenDB(cfg.DatabaseConnectionString(), postgres.Naming{
+		Schema:      cfg.Database.Schema,
+		TablePrefix: cfg.Database.TablePrefix,
+	}, postgres.PoolConfig{
+		MaxOpenConns:    cfg.Database.MaxOpenConns,
+		MaxIdleConns:    cfg.Database.MaxIdleConns,
+		ConnMaxLifetime: cfg.Database.ConnMaxLifetime,
+	})
 	if err != nil {
-		logger.Fatal("Failed to connect to database", zap.Error(err))
+		logger.Fatal("Failed to open database", zap.Error(err))
 	}
+	metrics.RegisterDBPoolStats(db.DB, cfg.Database.DBName)
 	defer func() {
 		if err := db.Close(); err != nil {
 			logger.Error("Failed to close database connection", zap.Error(err))
 		}
 	}()
 
-	// Inicializar esquema de base de datos
-	if err := postgres.InitSchema(db); err != nil {
-		logger.Fatal("Failed to initialize database schema", zap.Error(err))
-	}
-	logger.Info("Database schema initialized")
-
 	// Inicializar Redis
-	redisClient, err := redis.NewRedisClient(cfg.RedisAddress(), cfg.Redis.Password, cfg.Redis.DB, logger)
-	if err != nil {
-		logger.Fatal("Failed to connect to Redis", zap.Error(err))
-	}
+	redisClient := redis.OpenRedisClient(cfg.RedisAddress(), cfg.Redis.Password, cfg.Redis.DB, cfg.Redis.CommandTimeout)
 	defer func() {
 		if err := redisClient.Close(); err != nil {
 			logger.Error("Failed to close Redis connection", zap.Error(err))
 		}
 	}()
 
+	// Retry transient database errors so brief failovers don't surface as 500s
+	dbRetrier := postgres.NewRetrier(postgres.RetryPolicy{
+		MaxAttempts:        cfg.Database.RetryMaxAttempts,
+		InitialBackoff:     cfg.Database.RetryInitialBackoff,
+		MaxBackoff:         cfg.Database.RetryMaxBackoff,
+		BudgetRatio:        cfg.Database.RetryBudgetRatio,
+		OperationTimeout:   cfg.Database.OperationTimeout,
+		SlowQueryThreshold: cfg.Database.SlowQueryThreshold,
+
+		MaxConcurrentOperations: cfg.Database.MaxOpenConns,
+		PoolWaitTimeout:         cfg.Database.PoolWaitTimeout,
+	}, logger)
+
 	// Inicializar repositorios
-	userRepo := postgres.NewUserRepository(db, logger)
-	tokenRepo := redis.NewTokenRepository(redisClient, logger)
-	oauthClientRepo := postgres.NewOAuthClientRepository(db, logger)
+	newID := cfg.Database.IDFormat.Generator()
+	postgresUserRepo := postgres.NewUserRepository(db, dbRetrier, newID, logger)
+	var userRepo ports.UserRepository = postgresUserRepo
+	var tokenRepo ports.TokenRepository = redis.NewTokenRepository(redisClient, logger)
+	var oauthClientRepo ports.OAuthClientRepository = postgres.NewOAuthClientRepository(db, dbRetrier, newID, logger)
 
-	// Initialize RabbitMQ
-	rbClient, consumeCancel, err := setupRabbitMQ(cfg, userRepo, tokenRepo, logger)
-	if err != nil {
-		logger.Fatal("Failed to setup RabbitMQ", zap.Error(err))
+	// Development mode: the stores of the sign-in flows are in memory, seeded with the sample users
+	if *devInMemory {
+		memoryUserRepo, err := newInMemoryUserRepository(*devFixtures, newPasswordHasher(cfg))
+		if err != nil {
+			logger.Fatal("Failed to seed in-memory users", zap.Error(err))
+		}
+		userRepo = memoryUserRepo
+		tokenRepo = memory.NewTokenRepository()
+		oauthClientRepo = memory.NewOAuthClientRepository()
 	}
-	defer func() {
-		consumeCancel()
-		_ = rbClient.Close()
-	}()
 
-	// Initialize RabbitMQ Publisher
-	rbPublisher, err := rabbitmq.NewRabbitMQPublisher(rbClient)
-	if err != nil {
-		logger.Fatal("Failed to create RabbitMQ publisher", zap.Error(err))
+	// Refresh tokens are optionally persisted in Postgres so a Redis flush doesn't sign out every user
+	var refreshTokenStore ports.RefreshTokenStore
+	if cfg.JWT.DurableRefreshTokens && !*devInMemory {
+		refreshTokenStore = postgres.NewRefreshTokenRepository(db, dbRetrier, logger)
+		tokenRepo = services.NewDurableTokenRepository(tokenRepo, refreshTokenStore, logger)
 	}
-	defer func() {
-		_ = rbPublisher.Close()
-	}()
 
-	// Initialize External Connectivity Client
-	externalConnectivityClient := httpClient.NewExternalConnectivityClient(
+	// Tokens blacklisted in this region are replicated to the other regions of a multi-region deployment.
+	// The stream is read with blocking commands, so its client has no command timeout.
+	var replicatedTokenRepo *services.ReplicatedTokenRepository
+	if cfg.Region.BlacklistReplicationEnabled() && !*devInMemory {
+		replicationRedisCfg := cfg.ReplicationRedis()
+		replicationRedis := redis.OpenRedisClient(replicationRedisCfg.Address, replicationRedisCfg.Password, replicationRedisCfg.DB, 0)
+		defer func() {
+			if err := replicationRedis.Close(); err != nil {
+				logger.Error("Failed to close replication Redis connection", zap.Error(err))
+			}
+		}()
+
+		consumerName, err := os.Hostname()
+		if err != nil {
+			consumerName = fmt.Sprintf("pid-%d", os.Getpid())
+		}
+		replicator := redis.NewBlacklistReplicator(replicationRedis, cfg.Region.ReplicationStream, cfg.Region.ID, consumerName, cfg.Region.ReplicationStreamMaxLen, logger)
+		replicatedTokenRepo = services.NewReplicatedTokenRepository(tokenRepo, replicator, cfg.Region.ID, logger)
+		tokenRepo = replicatedTokenRepo
+	}
+
+	// User lookups are cached for a short time, every write of a user evicts it from the cache
+	var userCache ports.UserCache
+	if cfg.Redis.UserCacheTTL > 0 && !*devInMemory {
+		userCache = redis.NewUserCache(redisClient, cfg.Redis.UserCacheTTL, logger)
+		userRepo = services.NewUserCacheInvalidator(userRepo, userCache, logger)
+	}
+
+	notificationPrefsRepo := postgres.NewNotificationPreferencesRepository(db, dbRetrier, logger)
+	deviceAuthorizationRepo := redis.NewDeviceAuthorizationRepository(redisClient, logger)
+	scopeRepo := postgres.NewScopeRepository(db, dbRetrier, logger)
+	consentRepo := postgres.NewConsentRepository(db, dbRetrier, logger)
+	rateLimiter := redis.NewRateLimiter(redisClient, cfg.RateLimit.Requests, cfg.RateLimit.Window, logger)
+	phoneNumberRepo := postgres.NewPhoneNumberRepository(db, dbRetrier, logger)
+	phoneVerificationRepo := redis.NewPhoneVerificationRepository(redisClient, logger)
+	phoneLoginCodeRepo := redis.NewPhoneLoginCodeRepository(redisClient, logger)
+	userEmailRepo := postgres.NewUserEmailRepository(db, dbRetrier, logger)
+	passwordResetRepo := redis.NewPasswordResetRepository(redisClient, logger)
+	avatarRepo := postgres.NewAvatarRepository(db, dbRetrier, logger)
+	auditLogRepo := postgres.NewAuditLogRepository(db, dbRetrier, logger)
+
+	// Audit records are streamed to the external sink (SIEM) in the background when the export is enabled
+	var auditLog ports.AuditLogRepository = auditLogRepo
+	var auditExporter *services.AuditExporter
+	if cfg.AuditExport.Enabled() {
+		auditExporter = services.NewAuditExporter(newAuditSink(cfg.AuditExport, logger), auditLogRepo, services.AuditExportPolicy{
+			PollInterval:   cfg.AuditExport.PollInterval,
+			BatchSize:      cfg.AuditExport.BatchSize,
+			InitialBackoff: cfg.AuditExport.InitialBackoff,
+			MaxBackoff:     cfg.AuditExport.MaxBackoff,
+		}, logger)
+		auditLog = services.NewExportingAuditLog(auditLogRepo, auditExporter)
+	}
+	quotaRepo := postgres.NewQuotaRepository(db, dbRetrier, logger)
+
+	// Messages are published to RabbitMQ, Kafka or NATS, in development mode they are kept in memory
+	var rbClient *rabbitmq.RabbitMQClient
+	var natsClient *nats.NATSClient
+	var publisher ports.MessagePublisher = memory.NewMessagePublisher()
+	var outboxRepo *postgres.OutboxRepository
+	var outboxRelay *services.OutboxRelay
+	if !*devInMemory {
+		if cfg.Messaging.NATSEnabled() {
+			// NATS replaces RabbitMQ, the client reconnects in the background while NATS is down
+			natsClient, err = nats.NewNATSClient(cfg.Messaging.NATS, logger)
+			if err != nil {
+				logger.Fatal("Failed to create NATS client", zap.Error(err))
+			}
+			defer func() {
+				_ = natsClient.Close()
+			}()
+		} else {
+			// Initialize RabbitMQ client (it reconnects in the background while RabbitMQ is down)
+			rbClient, err = rabbitmq.NewRabbitMQClient(cfg.RabbitMQ)
+			if err != nil {
+				logger.Warn("RabbitMQ not available at startup; will reconnect in background", zap.Error(err))
+			}
+			defer func() {
+				_ = rbClient.Close()
+			}()
+		}
+
+		// Initialize the publisher of the configured broker, with Kafka the events are consumed from RabbitMQ
+		var brokerPublisher ports.MessagePublisher
+		switch cfg.Messaging.Broker {
+		case config.MessageBrokerKafka:
+			brokerPublisher, err = kafka.NewKafkaPublisher(cfg.Messaging.Kafka, logger)
+			if err != nil {
+				logger.Fatal("Failed to create Kafka publisher", zap.Error(err))
+			}
+		case config.MessageBrokerNATS:
+			brokerPublisher = nats.NewNATSPublisher(natsClient, logger)
+		default:
+			brokerPublisher, err = rabbitmq.NewRabbitMQPublisher(rbClient)
+			if err != nil {
+				logger.Fatal("Failed to create RabbitMQ publisher", zap.Error(err))
+			}
+		}
+		defer func() {
+			_ = brokerPublisher.Close()
+		}()
+
+		// Messages whose publication fails are stored in the outbox and relayed in the background
+		outboxRepo = postgres.NewOutboxRepository(db, dbRetrier, logger)
+		publisher = services.NewOutboxPublisher(brokerPublisher, outboxRepo, logger)
+		outboxRelay = services.NewOutboxRelay(brokerPublisher, outboxRepo, services.OutboxRelayPolicy{
+			PollInterval:   cfg.Outbox.PollInterval,
+			BatchSize:      cfg.Outbox.BatchSize,
+			InitialBackoff: cfg.Outbox.InitialBackoff,
+			MaxBackoff:     cfg.Outbox.MaxBackoff,
+		}, logger)
+	}
+
+	// Inbound events, processed once each thanks to the processed message repository
+	processedMessageRepo := redis.NewProcessedMessageRepository(redisClient, cfg.RabbitMQ.ProcessedMessageTTL, logger)
+	userTransferredConsumer := services.NewUserTransferredConsumer(
+		userRepo,
+		tokenRepo,
+		processedMessageRepo,
+		cfg.RabbitMQ.ConsumerQueue,
+		cfg.JWT.AccessTokenDuration,
+		logger,
+	)
+
+	// Role changes revoke the tokens of the user, whether requested by an administrator or the citizen registry
+	roleService := services.NewRoleService(
+		userRepo,
+		tokenRepo,
+		auditLog,
+		publisher,
+		cfg.RabbitMQ.RoleChangedQueue,
+		cfg.JWT.AccessTokenDuration,
+		logger,
+	)
+	serviceAccountService := services.NewServiceAccountService(userRepo, auditLog, logger)
+	userSyncConsumer := services.NewUserSyncConsumer(
+		userRepo,
+		roleService,
+		auditLog,
+		processedMessageRepo,
+		cfg.RabbitMQ.UserUpdatedQueue,
+		cfg.RabbitMQ.UserRoleChangedQueue,
+		cfg.UserName.Limits(),
+		logger,
+	)
+	var messageConsumer ports.MessageConsumer
+	if !*devInMemory {
+		var brokerConsumer ports.MessageConsumer = rabbitmq.NewConsumerRegistry(rbClient)
+		if natsClient != nil {
+			brokerConsumer = nats.NewConsumerRegistry(natsClient, logger)
+		}
+		messageConsumer, err = newMessageConsumer(cfg, brokerConsumer, userTransferredConsumer, userSyncConsumer)
+		if err != nil {
+			logger.Fatal("Failed to setup message consumers", zap.Error(err))
+		}
+	}
+
+	// Check dependencies before serving: required ones fail fast, degraded-ok ones only degrade the service
+	dependencyManager := newDependencyManager(cfg, db.DB, redisClient, rbClient, natsClient, *devInMemory, logger)
+	if _, err := dependencyManager.WaitForStartup(context.Background()); err != nil {
+		logger.Fatal("Startup dependency checks failed", zap.Error(err))
+	}
+	var healthGauges []services.HealthGauge
+	if !*devInMemory {
+		healthGauges = newHealthGauges(cfg, rbClient, natsClient, outboxRepo)
+	}
+	healthDetailsService := services.NewHealthDetailsService(dependencyManager, logger, healthGauges...)
+
+	// Background components, started once the dependencies are available and stopped on shutdown:
+	// the consumers first and then the jobs, each group within its own deadline
+	consumers := services.NewLifecycleManager(logger)
+	jobs := services.NewLifecycleManager(logger)
+	if !*devInMemory {
+		consumers.Register("message consumers", messageConsumer)
+		jobs.Register("outbox relay", outboxRelay)
+	}
+	if auditExporter != nil {
+		jobs.Register("audit exporter", auditExporter)
+	}
+	if replicatedTokenRepo != nil {
+		consumers.Register("blacklist replication", replicatedTokenRepo)
+	}
+	if refreshTokenStore != nil {
+		jobs.Register("refresh token cleaner", services.NewRefreshTokenCleaner(refreshTokenStore, services.RefreshTokenCleanupPolicy{
+			Interval:  cfg.JWT.RefreshTokenCleanupInterval,
+			BatchSize: cfg.JWT.RefreshTokenCleanupBatchSize,
+		}, logger))
+	}
+	if cfg.OAuth.ClientSecretReminderInterval > 0 {
+		jobs.Register("client secret reminder", services.NewClientSecretReminder(oauthClientRepo, publisher, cfg.RabbitMQ.ClientSecretExpiringQueue, services.ClientSecretReminderPolicy{
+			Interval: cfg.OAuth.ClientSecretReminderInterval,
+			Window:   cfg.OAuth.ClientSecretReminderWindow,
+		}, logger))
+	}
+
+	// Avatars are stored in an S3-compatible bucket when one is configured, orphaned images are removed in the background
+	var avatarService *services.AvatarService
+	if cfg.Avatar.Enabled() {
+		avatarStorage, err := newS3Storage(cfg.Avatar.S3, logger)
+		if err != nil {
+			logger.Fatal("Failed to create avatar storage", zap.Error(err))
+		}
+		avatarService = services.NewAvatarService(userRepo, avatarRepo, avatarStorage, int64(cfg.Avatar.MaxSize), cfg.Avatar.URLExpiry, logger)
+		jobs.Register("avatar cleaner", services.NewAvatarCleaner(avatarStorage, avatarRepo, services.AvatarCleanupPolicy{
+			Interval:    cfg.Avatar.CleanupInterval,
+			GracePeriod: cfg.Avatar.CleanupGracePeriod,
+			BatchSize:   cfg.Avatar.CleanupBatchSize,
+		}, logger))
+	}
+
+	// Warm-up steps completed after the server starts, /health/ready answers 503 until all are done
+	readinessGate := services.NewReadinessGate(logger, warmUpSchema, warmUpConsumers)
+
+	// Initialize External Connectivity Client, optionally bounded by a concurrency limit
+	var externalConnectivityClient ports.ExternalConnectivityClient = httpClient.NewExternalConnectivityClient(
 		cfg.ExternalConnectivity.BaseURL,
 		cfg.ExternalConnectivity.AuthURL,
 		cfg.ExternalConnectivity.ClientID,
 		cfg.ExternalConnectivity.ClientSecret,
 		logger,
 	)
+	if *devInMemory {
+		// Every citizen is unknown to the centralizer, so any of them can register
+		externalConnectivityClient = memory.NewExternalConnectivityClient()
+	} else if cfg.ExternalConnectivity.MaxConcurrentCalls > 0 {
+		externalConnectivityClient = httpClient.NewLimitedExternalConnectivityClient(
+			externalConnectivityClient,
+			cfg.ExternalConnectivity.MaxConcurrentCalls,
+			cfg.ExternalConnectivity.MaxQueuedCalls,
+			cfg.ExternalConnectivity.QueueTimeout,
+			logger,
+		)
+	}
+
+	// Initialize password hashing, optionally bounded by a worker pool stopped after the HTTP server
+	passwordHasher := newPasswordHasher(cfg)
+	var hashingPool *hashing.WorkerPool
+	if cfg.PasswordHashing.Workers > 0 {
+		hashingPool = hashing.NewWorkerPool(passwordHasher, cfg.PasswordHashing.Workers, cfg.PasswordHashing.QueueSize, logger)
+		passwordHasher = hashingPool
+	}
 
 	// Inicializar servicios
+	tokenSigningPolicy := services.TokenSigningPolicy{
+		Algorithm:          cfg.JWT.SigningAlgorithm,
+		AcceptedAlgorithms: cfg.JWT.AcceptedAlgorithms,
+		KeyID:              cfg.JWT.KeyID,
+		MaxTokenSize:       cfg.JWT.MaxAccessTokenSize,
+		RequiredClaims:     cfg.JWT.RequiredClaims,
+		CompatibilityMode:  cfg.JWT.CompatibilityMode,
+		Region:             cfg.Region.ID,
+	}
+	for _, warning := range tokenSigningPolicy.CompatibilityWarnings() {
+		logger.Warn("Token settings may break a mixed-version cluster", zap.String("warning", warning))
+	}
 	jwtService := services.NewJWTService(
 		cfg.JWT.Secret,
 		cfg.JWT.AccessTokenDuration,
 		cfg.JWT.RefreshTokenDuration,
+		cfg.JWT.OpaqueRefreshTokens,
+		cfg.UserMetadata.ClaimKeys,
+		tokenSigningPolicy,
+		logger,
+	)
+	if err := jwtService.CheckTokenSize(); err != nil {
+		logger.Fatal("Access tokens exceed JWT_MAX_ACCESS_TOKEN_SIZE", zap.Error(err))
+	}
+
+	notificationService := services.NewNotificationService(
+		userRepo,
+		notificationPrefsRepo,
+		publisher,
+		cfg.RabbitMQ.SecurityNotificationQueue,
+		logger,
+	)
+
+	// Impossible-travel detection on login, enabled when a GeoIP database is configured
+	var geoRisk services.LoginRiskEvaluator
+	if cfg.GeoIP.Enabled() {
+		geoLocator, err := geoip.NewMaxMindLocator(cfg.GeoIP.DatabasePath, logger)
+		if err != nil {
+			logger.Fatal("Failed to open GeoIP database", zap.Error(err))
+		}
+		defer func() {
+			_ = geoLocator.Close()
+		}()
+
+		geoRisk = services.NewLoginAnomalyService(
+			geoLocator,
+			redis.NewLoginHistoryRepository(redisClient, cfg.GeoIP.HistorySize, cfg.GeoIP.HistoryWindow, logger),
+			notificationService,
+			domain.TravelPolicy{
+				MaxSpeedKmh:   cfg.GeoIP.MaxTravelSpeedKmh,
+				MinDistanceKm: cfg.GeoIP.MinDistanceKm,
+			},
+			cfg.GeoIP.HistoryWindow,
+			logger,
+		)
+	}
+
+	// Risk-based authentication policy evaluated on every login and refresh
+	riskPolicy, err := loadRiskPolicy(cfg.Risk.PolicyFile)
+	if err != nil {
+		logger.Fatal("Failed to load risk policy", zap.Error(err))
+	}
+
+	// Refresh token family analytics, feeding the risk policy with anomalous refresh patterns
+	var refreshAnomalies services.RefreshAnomalyDetector
+	refreshAnomalyPolicy := domain.RefreshAnomalyPolicy{
+		MaxDistinctIPs: cfg.Risk.RefreshMaxIPs,
+		MaxRefreshes:   cfg.Risk.RefreshMaxRefreshes,
+		Window:         cfg.Risk.RefreshWindow,
+	}
+	if refreshAnomalyPolicy.Enabled() {
+		refreshAnomalies = services.NewRefreshAnomalyService(
+			redis.NewRefreshActivityRepository(redisClient, cfg.Risk.RefreshWindow, logger),
+			auditLog,
+			refreshAnomalyPolicy,
+			logger,
+		)
+	}
+
+	knownDeviceRepo := redis.NewKnownDeviceRepository(redisClient, cfg.Risk.DeviceTTL, logger)
+
+	// Logins from a new device or IP address are notified through the configured channels
+	var loginNotifier services.LoginNotifier
+	loginNotificationChannels, err := domain.ParseLoginNotificationChannels(cfg.Risk.LoginNotificationChannels)
+	if err != nil {
+		logger.Fatal("Invalid login notification channels", zap.Error(err))
+	}
+	if len(loginNotificationChannels) > 0 {
+		loginNotifier = services.NewLoginNotificationService(
+			notificationService,
+			knownDeviceRepo,
+			publisher,
+			cfg.RabbitMQ.LoginNotificationQueue,
+			loginNotificationChannels,
+			logger,
+		)
+	}
+
+	// Failed logins are counted per user, IP address and email address within the failure window
+	loginFailureCounter := redis.NewRateLimiter(redisClient, 0, cfg.Risk.FailureWindow, logger)
+	riskEngine := services.NewRiskPolicyService(
+		riskPolicy,
+		geoRisk,
+		refreshAnomalies,
+		knownDeviceRepo,
+		loginFailureCounter,
+		services.NewBruteForceMonitor(loginFailureCounter, cfg.Risk.BruteForceAlertThreshold, cfg.Risk.FailureWindow, logger),
+		loginNotifier,
+		logger,
+	)
+
+	// Token issuance quotas of clients and users, the hourly usage is counted in Redis
+	quotaService := services.NewQuotaService(
+		quotaRepo,
+		redis.NewRateLimiter(redisClient, 0, time.Hour, logger),
+		tokenRepo,
+		oauthClientRepo,
+		userRepo,
+		auditLog,
+		services.QuotaDefaults{
+			ClientMaxTokensPerHour: cfg.Quota.ClientMaxTokensPerHour,
+			UserMaxTokensPerHour:   cfg.Quota.UserMaxTokensPerHour,
+			UserMaxActiveSessions:  cfg.Quota.UserMaxActiveSessions,
+		},
 		logger,
 	)
 
@@ -224,29 +701,325 @@ func main() {
 		userRepo,
 		tokenRepo,
 		jwtService,
-		rbPublisher,
-		externalConnectivityClient,
-		cfg.RabbitMQ.UserRegisteredQueue,
+		passwordHasher,
+		logger,
+		services.WithPublisher(publisher, cfg.RabbitMQ.UserRegisteredQueue),
+		services.WithConnectivityClient(externalConnectivityClient),
+		services.WithRiskEngine(riskEngine),
+		services.WithQuotaEnforcer(quotaService),
+		services.WithUserCache(userCache),
+		services.WithRegistrationApproval(cfg.Registration.RequireApproval),
+		services.WithNameLimits(cfg.UserName.Limits()),
+	)
+
+	requestReplayGuard := services.NewRequestReplayGuard(
+		redis.NewNonceRepository(redisClient, logger),
+		cfg.OAuth.SignedRequestMaxSkew,
+		logger,
+	)
+
+	// Usage of the clients is counted in Redis and flushed to Postgres in the background
+	clientUsageService := services.NewClientUsageService(
+		redis.NewClientUsageCounter(redisClient, logger),
+		postgres.NewClientUsageRepository(db, dbRetrier, logger),
+		services.ClientUsagePolicy{
+			FlushInterval: cfg.OAuth.ClientUsageFlushInterval,
+			ErrorWindow:   cfg.OAuth.ClientUsageErrorWindow,
+		},
 		logger,
 	)
+	jobs.Register("client usage flusher", clientUsageService)
+
+	var clientLockout services.ClientLockoutGuard
+	if cfg.OAuth.ClientLockoutThreshold > 0 {
+		clientLockout = services.NewClientLockoutService(
+			redis.NewRateLimiter(redisClient, 0, cfg.OAuth.ClientLockoutWindow, logger),
+			redis.NewRateLimiter(redisClient, 0, cfg.OAuth.ClientLockoutCooldown, logger),
+			services.ClientLockoutPolicy{
+				Threshold: cfg.OAuth.ClientLockoutThreshold,
+				Window:    cfg.OAuth.ClientLockoutWindow,
+				Cooldown:  cfg.OAuth.ClientLockoutCooldown,
+			},
+			logger,
+		)
+	}
 
 	oauth2Service := services.NewOAuth2Service(
 		oauthClientRepo,
+		scopeRepo,
 		cfg.JWT.Secret,
 		cfg.JWT.AccessTokenDuration,
+		tokenSigningPolicy,
+		logger,
+		services.WithClientTokenTTLJitter(cfg.OAuth.ClientTokenTTLJitterPercent),
+		services.WithClientQuotaEnforcer(quotaService),
+		services.WithClientRequestVerifier(requestReplayGuard),
+		services.WithClientTokenRepository(redis.NewClientTokenRepository(redisClient, logger)),
+		services.WithClientUsageTracker(clientUsageService),
+		services.WithClientLockout(clientLockout),
+		services.WithClientSecretHasher(passwordHasher),
+	)
+	// Bcrypt client secrets can't be verified by the PBKDF2 hasher of fips mode
+	if cfg.Crypto.FIPS() {
+		unapproved, err := oauth2Service.CountUnapprovedClientSecrets(context.Background(), func(hash string) bool {
+			return strings.HasPrefix(hash, hashing.PBKDF2Prefix)
+		})
+		if err != nil {
+			logger.Fatal("Failed to check the secret hashes of the OAuth clients", zap.Error(err))
+		}
+		if unapproved > 0 {
+			logger.Fatal("OAuth clients have client secrets hashed with an algorithm not allowed when CRYPTO_MODE is fips, recreate them in standard mode with PASSWORD_HASH_ALGORITHM=pbkdf2-sha256",
+				zap.Int("clients", unapproved))
+		}
+	}
+	if oversized, err := oauth2Service.CheckClientTokenSizes(context.Background()); err != nil {
+		logger.Warn("Failed to check the access token size of the OAuth clients", zap.Error(err))
+	} else if oversized > 0 {
+		logger.Warn("Access tokens of OAuth clients exceed JWT_MAX_ACCESS_TOKEN_SIZE, their configuration can only be updated to reduce them",
+			zap.Int("clients", oversized))
+	}
+
+	scopeService := services.NewScopeService(scopeRepo, logger)
+
+	consentService := services.NewConsentService(userRepo, consentRepo, logger)
+
+	deviceAuthorizationService := services.NewDeviceAuthorizationService(
+		oauth2Service,
+		deviceAuthorizationRepo,
+		authService,
+		consentService,
+		cfg.OAuth.DeviceCodeDuration,
+		cfg.OAuth.DevicePollInterval,
+		cfg.OAuth.DeviceVerificationURI,
+		logger,
+	)
+
+	passwordGrantService := services.NewPasswordGrantService(
+		oauth2Service,
+		authService,
+		cfg.OAuth.PasswordGrantEnabled,
+		cfg.OAuth.PasswordGrantClients,
+		quotaService,
+		logger,
+	)
+	if cfg.OAuth.PasswordGrantEnabled {
+		logger.Warn("Deprecated OAuth password grant is enabled", zap.Strings("clients", cfg.OAuth.PasswordGrantClients))
+	}
+
+	introspectionService := services.NewIntrospectionService(authService, oauth2Service, rateLimiter, logger)
+
+	// SMS delivery, limited per phone number and by a global daily quota to cap provider costs
+	phoneService := services.NewPhoneService(
+		userRepo,
+		phoneNumberRepo,
+		phoneVerificationRepo,
+		phoneLoginCodeRepo,
+		newSMSSender(cfg.SMS, logger),
+		redis.NewRateLimiter(redisClient, cfg.SMS.PerNumberLimit, cfg.SMS.PerNumberWindow, logger),
+		redis.NewRateLimiter(redisClient, cfg.SMS.DailyQuota, 24*time.Hour, logger),
+		cfg.SMS.CodeDuration,
+		logger,
+	)
+	phoneLoginService := services.NewPhoneLoginService(userRepo, phoneNumberRepo, phoneService, authService, riskEngine, cfg.SMS.PhoneLoginEnabled, logger)
+
+	// Verification codes and reset links share the per-address limit
+	emailLimiter := redis.NewRateLimiter(redisClient, cfg.Email.PerAddressLimit, cfg.Email.PerAddressWindow, logger)
+	userEmailService := services.NewUserEmailService(
+		userRepo,
+		userEmailRepo,
+		publisher,
+		cfg.RabbitMQ.SecurityNotificationQueue,
+		emailLimiter,
+		cfg.Email.CodeDuration,
+		logger,
+	)
+	passwordResetService := services.NewPasswordResetService(
+		userRepo,
+		userEmailRepo,
+		passwordResetRepo,
+		tokenRepo,
+		passwordHasher,
+		publisher,
+		cfg.RabbitMQ.SecurityNotificationQueue,
+		emailLimiter,
+		notificationService,
+		cfg.Email.ResetTokenDuration,
+		logger,
+	)
+	emailChangeService := services.NewEmailChangeService(
+		userRepo,
+		userEmailRepo,
+		publisher,
+		cfg.RabbitMQ.SecurityNotificationQueue,
+		emailLimiter,
+		cfg.Email.ChangeTokenDuration,
+		logger,
+	)
+
+	anonymizationService := services.NewAnonymizationService(
+		userRepo,
+		tokenRepo,
+		phoneNumberRepo,
+		userEmailRepo,
+		avatarRepo,
+		auditLog,
+		publisher,
+		cfg.RabbitMQ.UserAnonymizedQueue,
+		logger,
+	)
+
+	userMergeService := services.NewUserMergeService(
+		userRepo,
+		consentRepo,
+		userEmailRepo,
+		phoneNumberRepo,
+		tokenRepo,
+		auditLogRepo,
+		auditLog,
+		publisher,
+		cfg.RabbitMQ.UserMergedQueue,
+		cfg.JWT.AccessTokenDuration,
 		logger,
 	)
 
+	userProvisioningService := services.NewUserProvisioningService(
+		userRepo,
+		auditLog,
+		passwordHasher,
+		externalConnectivityClient,
+		publisher,
+		cfg.RabbitMQ.UserRegisteredQueue,
+		cfg.UserName.Limits(),
+		logger,
+	)
+
+	userMetadataService := services.NewUserMetadataService(userRepo, auditLog, services.UserMetadataPolicy{
+		Schema:          cfg.UserMetadata.Schema,
+		SelfServiceKeys: cfg.UserMetadata.SelfServiceKeys,
+		MaxSize:         cfg.UserMetadata.MaxSize,
+	}, logger)
+
+	registrationApprovalService := services.NewRegistrationApprovalService(userRepo, auditLog, notificationService, logger)
+
+	sudoService := services.NewSudoService(userRepo, passwordHasher, jwtService, auditLog, riskEngine, cfg.JWT.SudoTokenDuration, logger)
+
+	// Exports read the database directly, the user cache is not involved
+	exportService := services.NewExportService(postgresUserRepo, auditLogRepo, auditLog, logger)
+
+	// Users export their personal data to an S3-compatible bucket when one is configured, the archives are
+	// assembled by background workers and removed after the retention period
+	var userExportService *services.UserExportJobService
+	if cfg.UserExport.Enabled() {
+		exportStorage, err := newS3Storage(cfg.UserExport.S3, logger)
+		if err != nil {
+			logger.Fatal("Failed to create export storage", zap.Error(err))
+		}
+		userExportService = services.NewUserExportJobService(
+			userRepo,
+			userEmailRepo,
+			consentRepo,
+			notificationPrefsRepo,
+			auditLogRepo,
+			auditLog,
+			redis.NewUserExportJobRepository(redisClient, logger),
+			exportStorage,
+			services.UserExportPolicy{
+				Workers:          cfg.UserExport.Workers,
+				QueueSize:        cfg.UserExport.QueueSize,
+				Timeout:          cfg.UserExport.Timeout,
+				Retention:        cfg.UserExport.Retention,
+				URLExpiry:        cfg.UserExport.URLExpiry,
+				CleanupInterval:  cfg.UserExport.CleanupInterval,
+				CleanupBatchSize: cfg.UserExport.CleanupBatchSize,
+			},
+			logger,
+		)
+		jobs.Register("user exports", userExportService)
+	}
+
+	// Token responses are signed with their own key, with the algorithm and key ID of the tokens so they
+	// follow their rotation
+	var responseSigner middleware.ResponseSigner
+	if cfg.JWT.SignTokenResponses {
+		responseSigner = services.NewResponseSigningService(cfg.JWT.ResponseSigningSecret, tokenSigningPolicy, logger)
+	}
+
+	// Handler panics are alerted to the webhook when configured
+	var alertNotifier ports.AlertNotifier
+	if cfg.App.PanicWebhookURL != "" {
+		alertNotifier = httpClient.NewWebhookAlertNotifier(cfg.App.PanicWebhookURL, "auth-microservice", cfg.App.PanicWebhookTimeout)
+	}
+
 	// Inicializar router
-	router := httpAdapter.NewRouter(authService, oauth2Service, db, redisClient, logger)
+	router := httpAdapter.NewRouter(
+		authService,
+		oauth2Service,
+		notificationService,
+		deviceAuthorizationService,
+		passwordGrantService,
+		scopeService,
+		consentService,
+		introspectionService,
+		phoneService,
+		phoneLoginService,
+		userEmailService,
+		passwordResetService,
+		emailChangeService,
+		avatarService,
+		anonymizationService,
+		userMetadataService,
+		registrationApprovalService,
+		sudoService,
+		roleService,
+		userMergeService,
+		userProvisioningService,
+		services.NewTokenDebugService(jwtService, tokenRepo, logger),
+		serviceAccountService,
+		quotaService,
+		exportService,
+		userExportService,
+		rateLimiter,
+		cfg.Server.TrustProxyHeaders,
+		cfg.Server.TLS.ClientCertHeader,
+		httpAdapter.CORSConfig{
+			AllowedOrigins:      cfg.Server.CORS.AllowedOrigins,
+			AdminAllowedOrigins: cfg.Server.CORS.AdminAllowedOrigins,
+			ExposedHeaders:      cfg.Server.CORS.ExposedHeaders,
+			AllowCredentials:    cfg.Server.CORS.AllowCredentials,
+			MaxAge:              cfg.Server.CORS.MaxAge,
+		},
+		cfg.JWT.LoginIncludeUser,
+		cfg.JWT.RequireSudo,
+		cfg.Server.SchemasEnabled,
+		cfg.Server.AdminUIEnabled,
+		cfg.Server.AdminDebugEnabled,
+		httpAdapter.ForwardAuthConfig{
+			TrustedHosts: cfg.ForwardAuth.TrustedHosts,
+			LoginURL:     cfg.ForwardAuth.LoginURL,
+			CookieName:   cfg.ForwardAuth.CookieName,
+		},
+		httpAdapter.CookieConfig{
+			Enabled:  cfg.Cookie.Enabled,
+			Domain:   cfg.Cookie.Domain,
+			Path:     cfg.Cookie.Path,
+			SameSite: cfg.Cookie.SameSite,
+			Secure:   cfg.Cookie.Secure,
+		},
+		responseSigner,
+		alertNotifier,
+		dependencyManager,
+		readinessGate,
+		healthDetailsService,
+		cfg.Redacted(),
+		logger,
+	)
 
 	// Configurar servidor HTTP
-	server := &http.Server{
-		Addr:         cfg.ServerAddress(),
-		Handler:      router,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
+	// Requests in progress are counted to report how many were drained on shutdown
+	requestTracker := middleware.NewRequestTracker()
+	server, err := newHTTPServer(cfg.Server, cfg.ServerAddress(), requestTracker.Track(router))
+	if err != nil {
+		logger.Fatal("Failed to configure the HTTP server", zap.Error(err))
 	}
 
 	// Canal para errores del servidor
@@ -254,10 +1027,50 @@ func main() {
 
 	// Iniciar servidor en una goroutine
 	go func() {
-		logger.Info("Server starting", zap.String("address", server.Addr))
-		serverErrors <- server.ListenAndServe()
+		logger.Info("Server starting",
+			zap.String("address", server.Addr),
+			zap.Bool("tls", cfg.Server.TLS.Enabled()),
+			zap.Bool("autocert", cfg.Server.TLS.AutocertEnabled()),
+			zap.Bool("http2", cfg.Server.HTTP2Enabled),
+			zap.Int("max_connections", cfg.Server.MaxConnections),
+		)
+		serverErrors <- serve(server, cfg.Server)
 	}()
 
+	// Inicializar esquema de base de datos
+	if !*devInMemory {
+		if err := postgres.InitSchema(db); err != nil {
+			logger.Fatal("Failed to initialize database schema", zap.Error(err))
+		}
+		logger.Info("Database schema initialized")
+
+		// Accounts whose emails differ only by case must be resolved by hand before the index can be built,
+		// they are reported on every start until then
+		duplicates, err := postgres.MigrateEmailCase(context.Background(), db)
+		if err != nil {
+			logger.Fatal("Failed to migrate email case", zap.Error(err))
+		}
+		for _, duplicate := range duplicates {
+			logger.Warn("users with emails differing only by case, the case-insensitive email index is not created until they are resolved",
+				zap.String("email", domain.MaskEmail(duplicate.Email)),
+				zap.Strings("user_ids", duplicate.UserIDs),
+			)
+		}
+	}
+	readinessGate.Complete(warmUpSchema)
+
+	if err := jobs.Start(context.Background()); err != nil {
+		logger.Fatal("Failed to start background jobs", zap.Error(err))
+	}
+	if err := consumers.Start(context.Background()); err != nil {
+		logger.Fatal("Failed to start message consumers", zap.Error(err))
+	}
+	if *devInMemory {
+		readinessGate.Complete(warmUpConsumers)
+	} else {
+		go awaitConsumers(cfg.Startup, messageConsumer, readinessGate, logger)
+	}
+
 	// Canal para señales de sistema
 	shutdown := make(chan os.Signal, 1)
 	signal.Notify(shutdown, os.Interrupt, syscall.SIGTERM)
@@ -270,22 +1083,231 @@ func main() {
 	case sig := <-shutdown:
 		logger.Info("Shutdown signal received", zap.String("signal", sig.String()))
 
-		// Crear contexto con timeout para el shutdown
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
+		report := services.NewShutdownReport()
 
-		// Intentar graceful shutdown
-		if err := server.Shutdown(ctx); err != nil {
-			logger.Error("Server shutdown error", zap.Error(err))
-			if err := server.Close(); err != nil {
-				logger.Fatal("Server close error", zap.Error(err))
-			}
+		// Drain the requests in progress and then their password hashing operations
+		httpCtx, cancelHTTP := context.WithTimeout(context.Background(), cfg.Shutdown.HTTPTimeout)
+		report.Add(shutdownHTTP(httpCtx, server, requestTracker))
+		if hashingPool != nil {
+			report.Add(services.StopComponent(httpCtx, "password hashing", hashingPool))
 		}
+		cancelHTTP()
 
+		// Stop consuming once no request is in flight, letting the messages being processed finish
+		consumersCtx, cancelConsumers := context.WithTimeout(context.Background(), cfg.Shutdown.ConsumersTimeout)
+		report.Add(consumers.Shutdown(consumersCtx)...)
+		cancelConsumers()
+
+		// The outbox relay is stopped last to deliver the messages published by the consumers
+		jobsCtx, cancelJobs := context.WithTimeout(context.Background(), cfg.Shutdown.JobsTimeout)
+		report.Add(jobs.Shutdown(jobsCtx)...)
+		cancelJobs()
+
+		report.Log(logger)
 		logger.Info("Server stopped gracefully")
 	}
 }
 
+// shutdownHTTP stops accepting connections and waits for the requests in progress until the context is
+// done, then closes the remaining connections, cancelling their requests
+func shutdownHTTP(ctx context.Context, server *http.Server, tracker *middleware.RequestTracker) services.ShutdownStep {
+	start := time.Now()
+	inFlight := tracker.Active()
+	step := services.ShutdownStep{Name: "http server"}
+
+	if err := server.Shutdown(ctx); err != nil {
+		step.Cancelled = tracker.Active()
+		step.Err = err
+		if err := server.Close(); err != nil {
+			step.Err = errors.Join(step.Err, err)
+		}
+	}
+	step.Drained = max(inFlight-step.Cancelled, 0)
+	step.Duration = time.Since(start)
+	return step
+}
+
+// awaitConsumers completes the consumers warm-up step once every queue is subscribed. Unless RabbitMQ is
+// required, the wait is bounded so a broker outage degrades the service instead of keeping it not ready.
+func awaitConsumers(cfg config.StartupConfig, consumer ports.MessageConsumer, readinessGate *services.ReadinessGate, logger *zap.Logger) {
+	var timeout <-chan time.Time
+	if !cfg.RabbitMQRequired {
+		timeout = time.After(cfg.ConsumerReadyTimeout)
+	}
+
+	select {
+	case <-consumer.Subscribed():
+	case <-timeout:
+		logger.Warn("Message consumers not subscribed yet, receiving traffic in degraded mode",
+			zap.Duration("timeout", cfg.ConsumerReadyTimeout))
+	}
+	readinessGate.Complete(warmUpConsumers)
+}
+
+// newSMSSender creates the SMS sender of the configured provider
+func newSMSSender(cfg config.SMSConfig, logger *zap.Logger) ports.SMSSender {
+	switch cfg.Provider {
+	case "twilio":
+		return sms.NewTwilioSender(cfg.Twilio.BaseURL, cfg.Twilio.AccountSID, cfg.Twilio.AuthToken, cfg.Twilio.From, logger)
+	case "sns":
+		return sms.NewSNSSender(cfg.SNS.Endpoint, cfg.SNS.Region, cfg.SNS.AccessKeyID, cfg.SNS.SecretAccessKey, cfg.SNS.SessionToken, cfg.SNS.SenderID, logger)
+	default:
+		logger.Warn("SMS provider is log, SMS will not be delivered")
+		return sms.NewLogSender(logger)
+	}
+}
+
+// newS3Storage creates the blob storage of an S3-compatible bucket
+func newS3Storage(cfg config.S3Config, logger *zap.Logger) (*storage.S3Storage, error) {
+	return storage.NewS3Storage(
+		cfg.Endpoint,
+		cfg.Region,
+		cfg.Bucket,
+		cfg.AccessKeyID,
+		cfg.SecretAccessKey,
+		cfg.SessionToken,
+		cfg.UsePathStyle,
+		logger,
+	)
+}
+
+// newAuditSink creates the audit sink of the configured export
+func newAuditSink(cfg config.AuditExportConfig, logger *zap.Logger) ports.AuditSink {
+	if cfg.Sink == "http" {
+		return auditsink.NewHTTPSink(cfg.HTTPURL, cfg.HTTPAuthHeader, cfg.HTTPFormat, logger)
+	}
+	return auditsink.NewSyslogSink(cfg.SyslogNetwork, cfg.SyslogAddress, cfg.SyslogAppName, logger)
+}
+
+// newPasswordHasher creates the hasher of the configured algorithm. PBKDF2 keeps verifying the bcrypt hashes
+// stored before the algorithm changed, except in fips mode where bcrypt is not allowed.
+func newPasswordHasher(cfg *config.Config) ports.PasswordHasher {
+	bcryptHasher := hashing.NewBcryptHasher(cfg.PasswordHashing.BcryptCost)
+	if cfg.PasswordHashing.Algorithm != config.PasswordHashPBKDF2SHA256 {
+		return bcryptHasher
+	}
+	if cfg.Crypto.FIPS() {
+		return hashing.NewPBKDF2Hasher(cfg.PasswordHashing.PBKDF2Iterations, nil)
+	}
+	return hashing.NewPBKDF2Hasher(cfg.PasswordHashing.PBKDF2Iterations, bcryptHasher)
+}
+
+// newInMemoryUserRepository creates the user repository of the development mode, seeded with the users of
+// the fixtures file
+func newInMemoryUserRepository(fixturesPath string, hasher ports.PasswordHasher) (*memory.UserRepository, error) {
+	users := memory.NewUserRepository()
+	if fixturesPath == "" {
+		return users, nil
+	}
+
+	fixtures, err := memory.LoadFixtures(fixturesPath)
+	if err != nil {
+		return nil, err
+	}
+	err = fixtures.SeedUsers(context.Background(), users, func(password string) (string, error) {
+		return hasher.Hash(context.Background(), password)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// loadRiskPolicy reads the risk policy file, or returns the default policy when no file is configured
+func loadRiskPolicy(path string) (*domain.RiskPolicy, error) {
+	if path == "" {
+		return domain.DefaultRiskPolicy(), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read risk policy file: %w", err)
+	}
+	return domain.ParseRiskPolicy(data)
+}
+
+// newHTTPServer builds the HTTP server from the server configuration.
+// HTTP/2 is negotiated through ALPN when TLS is enabled and spoken with prior knowledge (h2c) otherwise.
+// With a client CA, clients may present a certificate, which must be signed by the CA; requests without one
+// are still accepted, the certificate only binds the tokens issued to the client.
+func newHTTPServer(cfg config.ServerConfig, addr string, handler http.Handler) (*http.Server, error) {
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	if cfg.HTTP2Enabled {
+		if cfg.TLS.Enabled() {
+			protocols.SetHTTP2(true)
+		} else {
+			protocols.SetUnencryptedHTTP2(true)
+		}
+	}
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+		Protocols:         protocols,
+	}
+
+	switch {
+	case cfg.TLS.AutocertEnabled():
+		// Certificates are requested on the first handshake and renewed automatically.
+		// The TLS-ALPN-01 challenge is answered on the same port, so no port 80 listener is needed.
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.TLS.AutocertDomains...),
+			Cache:      autocert.DirCache(cfg.TLS.AutocertCacheDir),
+			Email:      cfg.TLS.AutocertEmail,
+		}
+		tlsConfig := manager.TLSConfig()
+		tlsConfig.MinVersion = tls.VersionTLS12
+		if !cfg.HTTP2Enabled {
+			tlsConfig.NextProtos = []string{"http/1.1", acme.ALPNProto}
+		}
+		server.TLSConfig = tlsConfig
+
+	case cfg.TLS.Enabled():
+		server.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	if cfg.TLS.ClientCAFile != "" {
+		pem, err := os.ReadFile(cfg.TLS.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read TLS_CLIENT_CA_FILE: %w", err)
+		}
+		clientCAs := x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("TLS_CLIENT_CA_FILE has no PEM certificate")
+		}
+		server.TLSConfig.ClientCAs = clientCAs
+		server.TLSConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	return server, nil
+}
+
+// serve listens on the server address, limiting concurrent connections when configured,
+// and serves plain HTTP or TLS depending on the configuration
+func serve(server *http.Server, cfg config.ServerConfig) error {
+	listener, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", server.Addr, err)
+	}
+
+	if cfg.MaxConnections > 0 {
+		listener = netutil.LimitListener(listener, cfg.MaxConnections)
+	}
+
+	if cfg.TLS.Enabled() {
+		// Certificate and key are empty with autocert, which provides them through TLSConfig.GetCertificate
+		return server.ServeTLS(listener, cfg.TLS.CertFile, cfg.TLS.KeyFile)
+	}
+	return server.Serve(listener)
+}
+
 // initLogger inicializa el logger de Zap
 func initLogger() (*zap.Logger, error) {
 	env := os.Getenv("APP_ENV")
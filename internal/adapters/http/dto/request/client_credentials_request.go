@@ -1,8 +1,18 @@
 package request
 
-// ClientCredentialsRequest represents the OAuth2 client credentials request
+// ClientCredentialsRequest represents the OAuth2 token request.
+// ClientSecret is required for client_credentials and password, DeviceCode for the device_code grant
+// and Username/Password for the deprecated password grant.
+// Timestamp, Nonce and Signature sign a client_credentials request against replay, they are required
+// from the clients with a request signing key.
 type ClientCredentialsRequest struct {
-	ClientID     string `json:"client_id" form:"client_id" validate:"required"`
-	ClientSecret string `json:"client_secret" form:"client_secret" validate:"required"`
-	GrantType    string `json:"grant_type" form:"grant_type" validate:"required,eq=client_credentials"`
+	ClientID     string `json:"client_id" form:"client_id"` // or in the Authorization: Basic header
+	ClientSecret string `json:"client_secret" form:"client_secret"`
+	GrantType    string `json:"grant_type" form:"grant_type" validate:"required"`
+	DeviceCode   string `json:"device_code,omitempty" form:"device_code"`
+	Username     string `json:"username,omitempty" form:"username"`
+	Password     string `json:"password,omitempty" form:"password"`
+	Timestamp    int64  `json:"timestamp,omitempty" form:"timestamp"`
+	Nonce        string `json:"nonce,omitempty" form:"nonce"`
+	Signature    string `json:"signature,omitempty" form:"signature"`
 }
@@ -0,0 +1,9 @@
+package request
+
+import "time"
+
+// ClientSecretExpiryRequest represents the request to set when the secret of an OAuth client expires
+type ClientSecretExpiryRequest struct {
+	// SecretExpiresAt is when the secret stops being accepted, null for never
+	SecretExpiresAt *time.Time `json:"secret_expires_at"`
+}
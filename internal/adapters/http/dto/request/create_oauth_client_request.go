@@ -1,5 +1,7 @@
 package request
 
+import "time"
+
 // CreateOAuthClientRequest represents the request to create an OAuth client
 type CreateOAuthClientRequest struct {
 	ClientID     string   `json:"client_id" validate:"required,min=3"`
@@ -7,4 +9,13 @@ type CreateOAuthClientRequest struct {
 	Name         string   `json:"name" validate:"required,min=3"`
 	Description  string   `json:"description"`
 	Scopes       []string `json:"scopes"`
+	// GrantTypes are the grant types the client may use: client_credentials, authorization_code,
+	// device_code or token_exchange. Defaults to client_credentials and device_code.
+	GrantTypes []string `json:"grant_types,omitempty"`
+	// RedirectURIs are the redirect URIs of the authorization requests of the client, matched exactly
+	RedirectURIs []string `json:"redirect_uris,omitempty"`
+	// Tags are free-form labels of the owner of the client, e.g. team:payments or env:staging
+	Tags []string `json:"tags,omitempty"`
+	// SecretExpiresAt is when the secret stops being accepted, it never expires when omitted
+	SecretExpiresAt *time.Time `json:"secret_expires_at,omitempty"`
 }
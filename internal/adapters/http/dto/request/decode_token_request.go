@@ -0,0 +1,6 @@
+package request
+
+// DecodeTokenRequest represents the request to decode a token for a support investigation
+type DecodeTokenRequest struct {
+	Token string `json:"token" validate:"required"`
+}
@@ -0,0 +1,14 @@
+package request
+
+// DeviceCodeRequest represents the OAuth2 device authorization request (RFC 8628)
+type DeviceCodeRequest struct {
+	ClientID     string `json:"client_id" form:"client_id" validate:"required"`
+	ClientSecret string `json:"client_secret" form:"client_secret"` // or HTTP Basic authentication
+	Scope        string `json:"scope,omitempty" form:"scope"`
+}
+
+// DeviceVerificationRequest represents the decision of a user on a device authorization
+type DeviceVerificationRequest struct {
+	UserCode string `json:"user_code" validate:"required"`
+	Approve  bool   `json:"approve"`
+}
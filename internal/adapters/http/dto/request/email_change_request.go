@@ -0,0 +1,11 @@
+package request
+
+// EmailChangeRequest represents the request to change the primary email of the authenticated user
+type EmailChangeRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ConfirmEmailChangeRequest represents the request to confirm an email change with the token received
+type ConfirmEmailChangeRequest struct {
+	Token string `json:"token" validate:"required"`
+}
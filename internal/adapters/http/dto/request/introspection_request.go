@@ -0,0 +1,10 @@
+package request
+
+// IntrospectionRequest represents the OAuth2 token introspection request (RFC 7662).
+// Client credentials may also be sent with HTTP Basic authentication.
+type IntrospectionRequest struct {
+	Token         string `json:"token" form:"token" validate:"required"`
+	TokenTypeHint string `json:"token_type_hint,omitempty" form:"token_type_hint"`
+	ClientID      string `json:"client_id" form:"client_id"`
+	ClientSecret  string `json:"client_secret" form:"client_secret"`
+}
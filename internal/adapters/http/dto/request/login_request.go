@@ -1,7 +1,17 @@
 package request
 
-// LoginRequest represents the login request
+// PhoneLoginCodeRequest represents the request to receive a login code by SMS
+type PhoneLoginCodeRequest struct {
+	PhoneNumber string `json:"phone_number" validate:"required"`
+}
+
+// LoginRequest represents the login request. Users log in with their email and password, or, when phone
+// login is enabled, with their verified phone number and either their password or a login code.
 type LoginRequest struct {
-	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required"`
+	Email       string `json:"email" validate:"required_without=PhoneNumber,omitempty,email"`
+	PhoneNumber string `json:"phone_number,omitempty" validate:"required_without=Email"`
+	Password    string `json:"password" validate:"required_without=Code"`
+	Code        string `json:"code,omitempty" validate:"required_without=Password"`
+	// IncludeUser embeds the user profile in the response, overriding the configured default
+	IncludeUser *bool `json:"include_user,omitempty"`
 }
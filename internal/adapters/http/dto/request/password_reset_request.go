@@ -0,0 +1,12 @@
+package request
+
+// PasswordResetRequest represents the request to receive a password reset token
+type PasswordResetRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ConfirmPasswordResetRequest represents the request to set a new password with a password reset token
+type ConfirmPasswordResetRequest struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required,min=8"`
+}
@@ -0,0 +1,11 @@
+package request
+
+// EnrollPhoneRequest represents the request to enroll a phone number
+type EnrollPhoneRequest struct {
+	PhoneNumber string `json:"phone_number" validate:"required"`
+}
+
+// VerifyPhoneRequest represents the request to confirm a phone number with the code received by SMS
+type VerifyPhoneRequest struct {
+	Code string `json:"code" validate:"required"`
+}
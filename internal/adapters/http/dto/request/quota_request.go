@@ -0,0 +1,8 @@
+package request
+
+// UpdateQuotaRequest represents the request to set the issuance quota of a client or user.
+// Zero means unlimited.
+type UpdateQuotaRequest struct {
+	MaxTokensPerHour  int `json:"max_tokens_per_hour"`
+	MaxActiveSessions int `json:"max_active_sessions"` // users only
+}
@@ -0,0 +1,6 @@
+package request
+
+// RedirectURIRequest represents the request to register a redirect URI for an OAuth client
+type RedirectURIRequest struct {
+	RedirectURI string `json:"redirect_uri"`
+}
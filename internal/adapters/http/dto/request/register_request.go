@@ -6,4 +6,6 @@ type RegisterRequest struct {
 	Email     string `json:"email" validate:"required,email"`
 	Password  string `json:"password" validate:"required,min=8"`
 	Name      string `json:"name" validate:"required,min=2"`
+	// PhoneNumber is enrolled for phone login when given, it is confirmed with the code sent by SMS
+	PhoneNumber string `json:"phone_number,omitempty"`
 }
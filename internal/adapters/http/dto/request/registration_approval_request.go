@@ -0,0 +1,6 @@
+package request
+
+// RejectRegistrationRequest represents the request to reject a user waiting for approval
+type RejectRegistrationRequest struct {
+	Reason string `json:"reason,omitempty"` // optional, sent to the user
+}
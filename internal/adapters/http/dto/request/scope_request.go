@@ -0,0 +1,12 @@
+package request
+
+// CreateScopeRequest represents the request to register an OAuth2 scope
+type CreateScopeRequest struct {
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description"`
+}
+
+// UpdateScopeRequest represents the request to update an OAuth2 scope
+type UpdateScopeRequest struct {
+	Description string `json:"description"`
+}
@@ -0,0 +1,7 @@
+package request
+
+// CreateServiceAccountRequest represents the request to create a service account
+type CreateServiceAccountRequest struct {
+	Name      string `json:"name" validate:"required"`
+	IDCitizen int    `json:"id_citizen" validate:"required,gt=0"`
+}
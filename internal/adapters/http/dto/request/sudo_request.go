@@ -0,0 +1,6 @@
+package request
+
+// SudoRequest represents the request to obtain elevated access by re-entering the password
+type SudoRequest struct {
+	Password string `json:"password" validate:"required"`
+}
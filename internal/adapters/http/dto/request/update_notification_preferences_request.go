@@ -0,0 +1,9 @@
+package request
+
+// UpdateNotificationPreferencesRequest represents the request to update notification preferences.
+// Omitted fields keep their current value.
+type UpdateNotificationPreferencesRequest struct {
+	NewDevice      *bool `json:"new_device,omitempty"`
+	PasswordChange *bool `json:"password_change,omitempty"`
+	LoginAlert     *bool `json:"login_alert,omitempty"`
+}
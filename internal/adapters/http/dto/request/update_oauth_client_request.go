@@ -0,0 +1,18 @@
+package request
+
+// UpdateOAuthClientRequest represents the request to update an OAuth client.
+// Omitted fields keep their current value.
+type UpdateOAuthClientRequest struct {
+	Name        *string  `json:"name,omitempty"`
+	Description *string  `json:"description,omitempty"`
+	Scopes      []string `json:"scopes,omitempty"`
+	// TokenProfile is the claims profile of the user access tokens issued to the client: standard or minimal
+	TokenProfile *string `json:"token_profile,omitempty"`
+	// GrantTypes replace the grant types the client may use: client_credentials, authorization_code,
+	// device_code or token_exchange
+	GrantTypes []string `json:"grant_types,omitempty"`
+	// RedirectURIs replace the redirect URIs of the authorization requests of the client
+	RedirectURIs []string `json:"redirect_uris,omitempty"`
+	// Tags replace the labels of the owner of the client, e.g. team:payments or env:staging
+	Tags []string `json:"tags,omitempty"`
+}
@@ -0,0 +1,11 @@
+package request
+
+// AddEmailRequest represents the request to register a secondary email address
+type AddEmailRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// VerifyEmailRequest represents the request to confirm a secondary email address with the code received
+type VerifyEmailRequest struct {
+	Code string `json:"code" validate:"required"`
+}
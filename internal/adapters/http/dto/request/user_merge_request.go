@@ -0,0 +1,7 @@
+package request
+
+// MergeUserRequest represents the request to merge a duplicate account into a user
+type MergeUserRequest struct {
+	MergedUserID string `json:"merged_user_id" validate:"required"` // ID of the duplicate account, soft-deleted by the merge
+	DryRun       bool   `json:"dry_run"`                            // only preview what the merge moves
+}
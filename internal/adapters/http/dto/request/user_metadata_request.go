@@ -0,0 +1,7 @@
+package request
+
+// UpdateUserMetadataRequest represents the request to change the custom metadata of a user.
+// Keys set to null are removed, omitted keys keep their current value.
+type UpdateUserMetadataRequest struct {
+	Metadata map[string]interface{} `json:"metadata"`
+}
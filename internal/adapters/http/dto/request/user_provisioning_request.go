@@ -0,0 +1,15 @@
+package request
+
+// CreateUserRequest represents the request of an administrator to create a user with a temporary password
+type CreateUserRequest struct {
+	Email     string `json:"email" validate:"required,email"`
+	Name      string `json:"name" validate:"required"`
+	IDCitizen int    `json:"id_citizen" validate:"required,gt=0"`
+}
+
+// ChangeTemporaryPasswordRequest represents the request to replace the temporary password of a user
+type ChangeTemporaryPasswordRequest struct {
+	Email             string `json:"email" validate:"required,email"`
+	TemporaryPassword string `json:"temporary_password" validate:"required"`
+	NewPassword       string `json:"new_password" validate:"required,min=8"`
+}
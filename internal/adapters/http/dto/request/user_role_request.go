@@ -0,0 +1,6 @@
+package request
+
+// ChangeUserRoleRequest represents the request to change the role of a user
+type ChangeUserRoleRequest struct {
+	Role string `json:"role" validate:"required"` // USER or ADMIN
+}
@@ -0,0 +1,11 @@
+package response
+
+import "time"
+
+// AvatarResponse represents the uploaded avatar of the authenticated user
+type AvatarResponse struct {
+	AvatarURL   string    `json:"avatar_url"` // signed URL, valid for a limited time
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
@@ -1,8 +1,11 @@
 package response
 
+import "time"
+
 // ClientCredentialsResponse represents the OAuth2 token response
 type ClientCredentialsResponse struct {
-	AccessToken string `json:"access_token"`
-	TokenType   string `json:"token_type"`
-	ExpiresIn   int64  `json:"expires_in"`
+	AccessToken string    `json:"access_token"`
+	TokenType   string    `json:"token_type"`
+	ExpiresIn   int64     `json:"expires_in"`
+	ExpiresAt   time.Time `json:"expires_at,omitzero"` // absolute expiration, the lifetime includes a random jitter
 }
@@ -0,0 +1,8 @@
+package response
+
+// EffectiveConfigResponse represents the runtime configuration of a replica, secrets are redacted
+type EffectiveConfigResponse struct {
+	Replica string                 `json:"replica" example:"auth-service-7d9f8c6b5-x2k4p"`
+	Version string                 `json:"version" example:"1.0.0"`
+	Config  map[string]interface{} `json:"config"`
+}
@@ -0,0 +1,11 @@
+package response
+
+import "time"
+
+// ConsentResponse represents the scopes a user granted to an OAuth2 client
+type ConsentResponse struct {
+	ClientID  string    `json:"client_id"`
+	Scopes    []string  `json:"scopes"`
+	GrantedAt time.Time `json:"granted_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
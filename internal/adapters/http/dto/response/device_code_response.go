@@ -0,0 +1,20 @@
+package response
+
+// DeviceCodeResponse represents the OAuth2 device authorization response (RFC 8628)
+type DeviceCodeResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int64  `json:"expires_in"`
+	Interval                int64  `json:"interval"`
+}
+
+// DeviceVerificationResponse describes a pending device authorization to the user approving it
+type DeviceVerificationResponse struct {
+	UserCode        string   `json:"user_code"`
+	ClientID        string   `json:"client_id"`
+	Scopes          []string `json:"scopes"`
+	ExpiresIn       int64    `json:"expires_in"`
+	ConsentRequired bool     `json:"consent_required"`
+}
@@ -0,0 +1,9 @@
+package response
+
+import "time"
+
+// EmailChangeResponse represents an email change waiting for confirmation
+type EmailChangeResponse struct {
+	PendingEmail string    `json:"pending_email"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
@@ -0,0 +1,13 @@
+package response
+
+// ErrorCatalogEntry describes an error code the API can return
+type ErrorCatalogEntry struct {
+	Code        string `json:"code"`
+	Status      int    `json:"status"`
+	Description string `json:"description"`
+}
+
+// ErrorCatalogResponse represents the catalog of API error codes
+type ErrorCatalogResponse struct {
+	Errors []ErrorCatalogEntry `json:"errors"`
+}
@@ -5,4 +5,6 @@ type ErrorResponse struct {
 	Error   string `json:"error"`
 	Code    string `json:"code,omitempty"`
 	Details string `json:"details,omitempty"`
+	// RequestID identifies the request in the logs, it is set on unexpected server errors
+	RequestID string `json:"request_id,omitempty"`
 }
@@ -0,0 +1,41 @@
+package response
+
+import (
+	"time"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// UserExportRecord represents a user in the users export
+type UserExportRecord struct {
+	ID        string            `json:"id"`
+	IDCitizen int               `json:"id_citizen"`
+	Email     string            `json:"email"`
+	Name      string            `json:"name"`
+	Role      domain.Role       `json:"role"`
+	Status    domain.UserStatus `json:"status"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// AuditRecordExportRecord represents an audit record in the audit log export
+type AuditRecordExportRecord struct {
+	ID        string             `json:"id"`
+	Action    domain.AuditAction `json:"action"`
+	Actor     string             `json:"actor"`
+	TargetID  string             `json:"target_id"`
+	Details   map[string]string  `json:"details,omitempty"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+// UserExportJobResponse represents a personal data export of the authenticated user
+type UserExportJobResponse struct {
+	ID                   string                     `json:"id"`
+	Status               domain.UserExportJobStatus `json:"status"`                 // pending, running, completed or failed
+	Size                 int64                      `json:"size,omitempty"`         // of the archive, in bytes
+	DownloadURL          string                     `json:"download_url,omitempty"` // signed URL of the archive, once completed
+	DownloadURLExpiresAt *time.Time                 `json:"download_url_expires_at,omitempty"`
+	CreatedAt            time.Time                  `json:"created_at"`
+	CompletedAt          *time.Time                 `json:"completed_at,omitempty"`
+	ExpiresAt            time.Time                  `json:"expires_at"` // when the archive is removed
+}
@@ -0,0 +1,30 @@
+package response
+
+import "time"
+
+// HealthDetailsResponse represents the detailed health of the service, for operators
+type HealthDetailsResponse struct {
+	Status       string                     `json:"status"`
+	Timestamp    time.Time                  `json:"timestamp"`
+	Version      string                     `json:"version"`
+	Dependencies []DependencyHealthResponse `json:"dependencies"`
+	Gauges       []GaugeResponse            `json:"gauges"`
+	Goroutines   int                        `json:"goroutines"`
+}
+
+// DependencyHealthResponse represents the check of a dependency and how long it took
+type DependencyHealthResponse struct {
+	Name        string  `json:"name"`
+	Criticality string  `json:"criticality"`
+	Status      string  `json:"status"`
+	LatencyMs   float64 `json:"latency_ms"`
+	Error       string  `json:"error,omitempty"`
+}
+
+// GaugeResponse represents an operational gauge, e.g. the depth of a queue. Value is null when the gauge
+// could not be read.
+type GaugeResponse struct {
+	Name  string `json:"name"`
+	Value *int64 `json:"value"`
+	Error string `json:"error,omitempty"`
+}
@@ -0,0 +1,31 @@
+package response
+
+// IntrospectionResponse represents the OAuth2 token introspection response (RFC 7662)
+type IntrospectionResponse struct {
+	Active    bool               `json:"active"`
+	Sub       string             `json:"sub,omitempty"`
+	ClientID  string             `json:"client_id,omitempty"`
+	Scope     string             `json:"scope,omitempty"`
+	Email     string             `json:"email,omitempty"`
+	Role      string             `json:"role,omitempty"`
+	TokenType string             `json:"token_type,omitempty"`
+	Iat       int64              `json:"iat,omitempty"`
+	Exp       int64              `json:"exp,omitempty"`
+	RateLimit *RateLimitResponse `json:"rate_limit,omitempty"`
+
+	// Cnf is the certificate a client token is bound to, the resource server must only accept the token
+	// from a client presenting that certificate (RFC 8705 section 3.2)
+	Cnf *ConfirmationResponse `json:"cnf,omitempty"`
+}
+
+// ConfirmationResponse is the SHA-256 thumbprint of a certificate, base64url encoded
+type ConfirmationResponse struct {
+	X5TS256 string `json:"x5t#S256"`
+}
+
+// RateLimitResponse mirrors the X-RateLimit-* headers for the token's principal
+type RateLimitResponse struct {
+	Limit     int   `json:"limit"`
+	Remaining int   `json:"remaining"`
+	Reset     int64 `json:"reset"`
+}
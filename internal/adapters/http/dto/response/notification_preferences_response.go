@@ -0,0 +1,11 @@
+package response
+
+import "time"
+
+// NotificationPreferencesResponse represents the notification preferences of the authenticated user
+type NotificationPreferencesResponse struct {
+	NewDevice      bool      `json:"new_device"`
+	PasswordChange bool      `json:"password_change"`
+	LoginAlert     bool      `json:"login_alert"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
@@ -1,15 +1,63 @@
 package response
 
-import "time"
+import (
+	"time"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
 
 // OAuthClientResponse represents the response with OAuth client data
 type OAuthClientResponse struct {
-	ID          string    `json:"id"`
-	ClientID    string    `json:"client_id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Scopes      []string  `json:"scopes"`
-	Active      bool      `json:"active"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID                    string              `json:"id"`
+	ClientID              string              `json:"client_id"`
+	Name                  string              `json:"name"`
+	Description           string              `json:"description"`
+	Scopes                []string            `json:"scopes"`
+	Active                bool                `json:"active"`
+	RequireSignedRequests bool                `json:"require_signed_requests"`
+	RequestSigningKey     string              `json:"request_signing_key,omitempty"` // Only returned when generated
+	TokenProfile          domain.TokenProfile `json:"token_profile"`
+	GrantTypes            []string            `json:"grant_types"`
+	RedirectURIs          []string            `json:"redirect_uris"`
+	Tags                  []string            `json:"tags"`
+	SecretExpiresAt       *time.Time          `json:"secret_expires_at,omitempty"`
+	CreatedAt             time.Time           `json:"created_at"`
+	UpdatedAt             time.Time           `json:"updated_at"`
+	Version               int                 `json:"version,omitempty"` // the ETag of the updates, see If-Match
+
+	// Usage is only returned when listing the clients, and omitted when it could not be retrieved
+	Usage *OAuthClientUsageResponse `json:"usage,omitempty"`
+	// Lockout is only returned when listing the clients with recent authentication failures
+	Lockout *OAuthClientLockoutResponse `json:"lockout,omitempty"`
+}
+
+// OAuthClientUsageResponse represents the usage of an OAuth client, updated periodically
+type OAuthClientUsageResponse struct {
+	LastUsedAt        *time.Time `json:"last_used_at"`
+	TokensIssued      int64      `json:"tokens_issued"`
+	RecentErrors      int64      `json:"recent_errors"`
+	RecentErrorsSince *time.Time `json:"recent_errors_since,omitempty"`
+}
+
+// OAuthClientLockoutResponse represents the recent authentication failures of an OAuth client and its
+// lockout, if any
+type OAuthClientLockoutResponse struct {
+	RecentAuthFailures int        `json:"recent_auth_failures"`
+	LockedUntil        *time.Time `json:"locked_until,omitempty"`
+}
+
+// ExpiringClientSecretResponse represents an OAuth client whose secret is about to expire or has expired
+type ExpiringClientSecretResponse struct {
+	ID              string    `json:"id"`
+	ClientID        string    `json:"client_id"`
+	Name            string    `json:"name"`
+	Tags            []string  `json:"tags"`
+	SecretExpiresAt time.Time `json:"secret_expires_at"`
+	Expired         bool      `json:"expired"`
+}
+
+// RevokedClientTokensResponse represents the result of revoking the access tokens of an OAuth client
+type RevokedClientTokensResponse struct {
+	ID            string `json:"id"`
+	RevokedTokens int    `json:"revoked_tokens"`
 }
@@ -0,0 +1,7 @@
+package response
+
+// OAuthErrorResponse represents an error of the OAuth endpoints (RFC 6749 section 5.2)
+type OAuthErrorResponse struct {
+	Error            string `json:"error" example:"invalid_client"`
+	ErrorDescription string `json:"error_description,omitempty" example:"Client authentication failed"`
+}
@@ -0,0 +1,15 @@
+package response
+
+import "time"
+
+// PhoneNumberResponse represents the verified phone number of the authenticated user
+type PhoneNumberResponse struct {
+	PhoneNumber string    `json:"phone_number"`
+	VerifiedAt  time.Time `json:"verified_at"`
+}
+
+// PhoneVerificationResponse represents a pending phone-number enrollment
+type PhoneVerificationResponse struct {
+	PhoneNumber string `json:"phone_number"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
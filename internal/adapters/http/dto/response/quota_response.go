@@ -0,0 +1,21 @@
+package response
+
+import "time"
+
+// QuotaResponse represents the issuance quota of a client or user. Zero means unlimited.
+type QuotaResponse struct {
+	SubjectType       string     `json:"subject_type"`
+	SubjectID         string     `json:"subject_id"`
+	MaxTokensPerHour  int        `json:"max_tokens_per_hour"`
+	MaxActiveSessions int        `json:"max_active_sessions"`
+	UpdatedAt         *time.Time `json:"updated_at,omitempty"`
+}
+
+// QuotaUsageResponse represents the effective quota of a client or user along with its current usage
+type QuotaUsageResponse struct {
+	Quota          QuotaResponse `json:"quota"`
+	Default        bool          `json:"default"`
+	TokensIssued   int           `json:"tokens_issued"`
+	TokensResetAt  time.Time     `json:"tokens_reset_at"`
+	ActiveSessions int           `json:"active_sessions"`
+}
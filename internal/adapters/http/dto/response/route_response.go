@@ -0,0 +1,14 @@
+package response
+
+// RouteResponse describes a registered route along with the authentication and the rate limit it applies
+type RouteResponse struct {
+	Path      string   `json:"path" example:"/api/auth/me"`
+	Methods   []string `json:"methods" example:"GET"` // empty when the route matches any method
+	Auth      string   `json:"auth" example:"user" enums:"none,user,admin,client-scope"`
+	RateLimit string   `json:"rateLimit" example:"user" enums:"none,user,login,token-quota,email,sms"`
+}
+
+// RoutesResponse represents the routes registered in the router
+type RoutesResponse struct {
+	Routes []RouteResponse `json:"routes"`
+}
@@ -0,0 +1,13 @@
+package response
+
+// SchemaCatalogEntry describes the JSON Schema document of a request or response payload
+type SchemaCatalogEntry struct {
+	Name      string `json:"name"`
+	Direction string `json:"direction"` // request or response
+	URL       string `json:"url"`
+}
+
+// SchemaCatalogResponse represents the catalog of the JSON Schema documents of the API payloads
+type SchemaCatalogResponse struct {
+	Schemas []SchemaCatalogEntry `json:"schemas"`
+}
@@ -0,0 +1,8 @@
+package response
+
+// ScopeResponse represents a registered OAuth2 scope
+type ScopeResponse struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	System      bool   `json:"system"`
+}
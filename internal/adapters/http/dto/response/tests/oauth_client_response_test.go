@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
 )
 
 func TestOAuthClientResponse_JSON(t *testing.T) {
@@ -139,16 +140,19 @@ func TestOAuthClientResponse_Marshal(t *testing.T) {
 		{
 			name: "marshal complete client",
 			response: response.OAuthClientResponse{
-				ID:          "123e4567-e89b-12d3-a456-426614174000",
-				ClientID:    "test_client",
-				Name:        "Test Client",
-				Description: "A test client",
-				Scopes:      []string{"read", "write"},
-				Active:      true,
-				CreatedAt:   testTime,
-				UpdatedAt:   testTime,
+				ID:           "123e4567-e89b-12d3-a456-426614174000",
+				ClientID:     "test_client",
+				Name:         "Test Client",
+				Description:  "A test client",
+				Scopes:       []string{"read", "write"},
+				Active:       true,
+				TokenProfile: domain.TokenProfileMinimal,
+				GrantTypes:   []string{"client_credentials"},
+				RedirectURIs: []string{"https://app.example.com/callback"},
+				CreatedAt:    testTime,
+				UpdatedAt:    testTime,
 			},
-			want: `{"id":"123e4567-e89b-12d3-a456-426614174000","client_id":"test_client","name":"Test Client","description":"A test client","scopes":["read","write"],"active":true,"created_at":"` + testTimeStr + `","updated_at":"` + testTimeStr + `"}`,
+			want: `{"id":"123e4567-e89b-12d3-a456-426614174000","client_id":"test_client","name":"Test Client","description":"A test client","scopes":["read","write"],"active":true,"require_signed_requests":false,"token_profile":"minimal","grant_types":["client_credentials"],"redirect_uris":["https://app.example.com/callback"],"tags":null,"created_at":"` + testTimeStr + `","updated_at":"` + testTimeStr + `"}`,
 		},
 		{
 			name: "marshal inactive client with null scopes",
@@ -162,7 +166,7 @@ func TestOAuthClientResponse_Marshal(t *testing.T) {
 				CreatedAt:   testTime,
 				UpdatedAt:   testTime,
 			},
-			want: `{"id":"123e4567-e89b-12d3-a456-426614174001","client_id":"inactive","name":"Inactive","description":"","scopes":null,"active":false,"created_at":"` + testTimeStr + `","updated_at":"` + testTimeStr + `"}`,
+			want: `{"id":"123e4567-e89b-12d3-a456-426614174001","client_id":"inactive","name":"Inactive","description":"","scopes":null,"active":false,"require_signed_requests":false,"token_profile":"","grant_types":null,"redirect_uris":null,"tags":null,"created_at":"` + testTimeStr + `","updated_at":"` + testTimeStr + `"}`,
 		},
 	}
 
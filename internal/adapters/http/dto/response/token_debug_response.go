@@ -0,0 +1,17 @@
+package response
+
+// TokenCheckResponse is the outcome of a validation step of a decoded token
+type TokenCheckResponse struct {
+	Name   string `json:"name" example:"expiration"` // signature, expiration, type, blacklist or version
+	Status string `json:"status" example:"failed"`   // passed, failed, skipped or error
+	Detail string `json:"detail" example:"expired at 2026-01-01T00:00:00Z, 5m0s ago"`
+}
+
+// TokenDebugResponse represents a token decoded without validating it, with the outcome of each
+// validation step of the access tokens
+type TokenDebugResponse struct {
+	Valid  bool                   `json:"valid"`
+	Header map[string]interface{} `json:"header"`
+	Claims map[string]interface{} `json:"claims"`
+	Checks []TokenCheckResponse   `json:"checks"`
+}
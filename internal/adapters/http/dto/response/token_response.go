@@ -1,5 +1,7 @@
 package response
 
+import "time"
+
 // TokenResponse represents the response with tokens
 type TokenResponse struct {
 	AccessToken  string `json:"access_token"`
@@ -7,3 +9,17 @@ type TokenResponse struct {
 	TokenType    string `json:"token_type"`
 	ExpiresIn    int64  `json:"expires_in"`
 }
+
+// LoginResponse represents the login response, the user is only present when requested
+type LoginResponse struct {
+	TokenResponse
+	User *UserResponse `json:"user,omitempty"`
+}
+
+// SudoResponse represents the short-lived access token granting elevated access
+type SudoResponse struct {
+	AccessToken string    `json:"access_token"`
+	TokenType   string    `json:"token_type"`
+	ExpiresIn   int64     `json:"expires_in"` // seconds until the token and the elevated access expire
+	SudoUntil   time.Time `json:"sudo_until"`
+}
@@ -0,0 +1,12 @@
+package response
+
+import "time"
+
+// UserEmailResponse represents a secondary email address of a user
+type UserEmailResponse struct {
+	ID         string     `json:"id"`
+	Email      string     `json:"email"`
+	Verified   bool       `json:"verified"`
+	VerifiedAt *time.Time `json:"verified_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
@@ -0,0 +1,13 @@
+package response
+
+// UserMergeResponse represents the merge of a duplicate account into a user, or its preview
+type UserMergeResponse struct {
+	User         UserResponse `json:"user"`
+	MergedUser   UserResponse `json:"merged_user"`
+	DryRun       bool         `json:"dry_run"`
+	Consents     []string     `json:"consents"`               // client IDs of the consents moved
+	Emails       []string     `json:"emails"`                 // addresses added as verified secondary emails
+	PhoneNumber  string       `json:"phone_number,omitempty"` // phone number moved, masked
+	AuditRecords int          `json:"audit_records"`
+	Sessions     int          `json:"sessions"` // sessions of the duplicate, ended by the merge
+}
@@ -0,0 +1,9 @@
+package response
+
+// CreateUserResponse represents a user created by an administrator, with the temporary password that is
+// only returned once
+type CreateUserResponse struct {
+	User               AdminUserResponse `json:"user"`
+	TemporaryPassword  string            `json:"temporary_password"`
+	MustChangePassword bool              `json:"must_change_password"`
+}
@@ -8,11 +8,47 @@ import (
 
 // UserResponse represents the response with user data
 type UserResponse struct {
-	ID        string      `json:"id"`
-	IDCitizen int         `json:"id_citizen"`
-	Email     string      `json:"email"`
-	Name      string      `json:"name"`
-	Role      domain.Role `json:"role"`
-	CreatedAt time.Time   `json:"created_at"`
-	UpdatedAt time.Time   `json:"updated_at"`
+	ID        string                 `json:"id"`
+	IDCitizen int                    `json:"id_citizen"`
+	Email     string                 `json:"email"`
+	Name      string                 `json:"name"`
+	Role      domain.Role            `json:"role"`
+	AvatarURL string                 `json:"avatar_url,omitempty"` // signed URL, valid for a limited time
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+	UpdatedAt time.Time              `json:"updated_at"`
+	Version   int                    `json:"version,omitempty"` // only returned to administrators, the ETag of their updates
+
+	// Activity is only returned by /me when include_activity is true
+	Activity *AccountActivityResponse `json:"activity,omitempty"`
+}
+
+// AccountActivityResponse summarizes the security state of the account of the user. The times are null when
+// they are unknown: the user never signed in, or their password was set before it was recorded.
+type AccountActivityResponse struct {
+	LastLoginAt         *time.Time `json:"last_login_at"`
+	ActiveSessionsCount int        `json:"active_sessions_count"`
+	MFAEnabled          bool       `json:"mfa_enabled"`
+	PasswordChangedAt   *time.Time `json:"password_changed_at"`
+}
+
+// RegisterResponse represents the registered user, with the pending enrollment of the phone number when
+// one was given
+type RegisterResponse struct {
+	UserResponse
+	PhoneVerification *PhoneVerificationResponse `json:"phone_verification,omitempty"`
+}
+
+// AdminUserResponse represents a user as seen by administrators, with the status of the account
+type AdminUserResponse struct {
+	ID        string            `json:"id"`
+	IDCitizen int               `json:"id_citizen"`
+	Email     string            `json:"email"`
+	Name      string            `json:"name"`
+	Role      domain.Role       `json:"role"`
+	Status    domain.UserStatus `json:"status"`
+	Type      domain.UserType   `json:"type"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+	Version   int               `json:"version,omitempty"`
 }
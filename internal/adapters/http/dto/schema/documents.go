@@ -0,0 +1,129 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/request"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+)
+
+//go:generate go run ../../../../../cmd/schemagen -out ../../../../../docs/schemas
+
+// Document is the JSON Schema of a DTO
+type Document struct {
+	Name      string
+	Direction Direction
+	Schema    *Schema
+}
+
+// dtos are the payloads of the API, every request and response DTO must be listed
+var dtos = []struct {
+	dto       any
+	direction Direction
+}{
+	{request.AddEmailRequest{}, Request},
+	{request.ChangeTemporaryPasswordRequest{}, Request},
+	{request.ChangeUserRoleRequest{}, Request},
+	{request.ClientCredentialsRequest{}, Request},
+	{request.ClientSecretExpiryRequest{}, Request},
+	{request.ConfirmEmailChangeRequest{}, Request},
+	{request.ConfirmPasswordResetRequest{}, Request},
+	{request.CreateOAuthClientRequest{}, Request},
+	{request.CreateScopeRequest{}, Request},
+	{request.CreateServiceAccountRequest{}, Request},
+	{request.CreateUserRequest{}, Request},
+	{request.DecodeTokenRequest{}, Request},
+	{request.DeviceCodeRequest{}, Request},
+	{request.DeviceVerificationRequest{}, Request},
+	{request.EmailChangeRequest{}, Request},
+	{request.EnrollPhoneRequest{}, Request},
+	{request.IntrospectionRequest{}, Request},
+	{request.LoginRequest{}, Request},
+	{request.LogoutRequest{}, Request},
+	{request.MergeUserRequest{}, Request},
+	{request.PasswordResetRequest{}, Request},
+	{request.PhoneLoginCodeRequest{}, Request},
+	{request.RedirectURIRequest{}, Request},
+	{request.RefreshTokenRequest{}, Request},
+	{request.RegisterRequest{}, Request},
+	{request.RejectRegistrationRequest{}, Request},
+	{request.SudoRequest{}, Request},
+	{request.UpdateNotificationPreferencesRequest{}, Request},
+	{request.UpdateOAuthClientRequest{}, Request},
+	{request.UpdateQuotaRequest{}, Request},
+	{request.UpdateScopeRequest{}, Request},
+	{request.UpdateUserMetadataRequest{}, Request},
+	{request.VerifyEmailRequest{}, Request},
+	{request.VerifyPhoneRequest{}, Request},
+	{response.AccountActivityResponse{}, Response},
+	{response.AdminUserResponse{}, Response},
+	{response.AuditRecordExportRecord{}, Response},
+	{response.AvatarResponse{}, Response},
+	{response.ClientCredentialsResponse{}, Response},
+	{response.ConfirmationResponse{}, Response},
+	{response.ConsentResponse{}, Response},
+	{response.CreateUserResponse{}, Response},
+	{response.DependencyHealthResponse{}, Response},
+	{response.DeviceCodeResponse{}, Response},
+	{response.DeviceVerificationResponse{}, Response},
+	{response.EffectiveConfigResponse{}, Response},
+	{response.EmailChangeResponse{}, Response},
+	{response.ErrorCatalogEntry{}, Response},
+	{response.ErrorCatalogResponse{}, Response},
+	{response.ErrorResponse{}, Response},
+	{response.ExpiringClientSecretResponse{}, Response},
+	{response.GaugeResponse{}, Response},
+	{response.HealthDetailsResponse{}, Response},
+	{response.HealthResponse{}, Response},
+	{response.IntrospectionResponse{}, Response},
+	{response.LoginResponse{}, Response},
+	{response.MessageResponse{}, Response},
+	{response.NotificationPreferencesResponse{}, Response},
+	{response.OAuthClientLockoutResponse{}, Response},
+	{response.OAuthClientResponse{}, Response},
+	{response.OAuthClientUsageResponse{}, Response},
+	{response.OAuthErrorResponse{}, Response},
+	{response.PhoneNumberResponse{}, Response},
+	{response.PhoneVerificationResponse{}, Response},
+	{response.QuotaResponse{}, Response},
+	{response.QuotaUsageResponse{}, Response},
+	{response.RateLimitResponse{}, Response},
+	{response.RegisterResponse{}, Response},
+	{response.RevokedClientTokensResponse{}, Response},
+	{response.RouteResponse{}, Response},
+	{response.RoutesResponse{}, Response},
+	{response.SchemaCatalogEntry{}, Response},
+	{response.SchemaCatalogResponse{}, Response},
+	{response.ScopeResponse{}, Response},
+	{response.SudoResponse{}, Response},
+	{response.TokenCheckResponse{}, Response},
+	{response.TokenDebugResponse{}, Response},
+	{response.TokenResponse{}, Response},
+	{response.UserEmailResponse{}, Response},
+	{response.UserExportJobResponse{}, Response},
+	{response.UserExportRecord{}, Response},
+	{response.UserMergeResponse{}, Response},
+	{response.UserResponse{}, Response},
+}
+
+// Documents returns the JSON Schema documents of the DTOs, sorted by name
+func Documents() []Document {
+	documents := make([]Document, 0, len(dtos))
+	for _, d := range dtos {
+		name := reflect.TypeOf(d.dto).Name()
+		documents = append(documents, Document{Name: name, Direction: d.direction, Schema: Generate(name, d.dto, d.direction)})
+	}
+	sort.Slice(documents, func(i, j int) bool { return documents[i].Name < documents[j].Name })
+	return documents
+}
+
+// Marshal encodes the schema of a document the way it is written to docs/schemas
+func (d Document) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(d.Schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
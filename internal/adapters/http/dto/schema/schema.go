@@ -0,0 +1,161 @@
+// Package schema describes the request and response DTOs of the API as JSON Schema documents, so consumer
+// teams can validate their payloads against our contracts.
+package schema
+
+import (
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Dialect is the JSON Schema version of the documents
+const Dialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema document, limited to the keywords the DTOs need
+type Schema struct {
+	Dialect              string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	ExclusiveMinimum     *float64           `json:"exclusiveMinimum,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+}
+
+// Direction tells whether a DTO is sent by the clients or by the API, which decides the required properties
+type Direction string
+
+const (
+	// Request DTOs require the properties validated as required
+	Request Direction = "request"
+	// Response DTOs always carry the properties that aren't omitted when empty
+	Response Direction = "response"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Generate describes the JSON encoding of a DTO. Nested structs are inlined so each document stands alone.
+func Generate(name string, dto any, direction Direction) *Schema {
+	s := generate(reflect.TypeOf(dto), direction)
+	s.Dialect = Dialect
+	s.Title = name
+	return s
+}
+
+func generate(t reflect.Type, direction Direction) *Schema {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Kind() == reflect.Struct:
+		s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+		addFields(s, t, direction)
+		return s
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: generate(t.Elem(), direction)}
+	case reflect.Map:
+		s := &Schema{Type: "object"}
+		if t.Elem().Kind() != reflect.Interface {
+			s.AdditionalProperties = generate(t.Elem(), direction)
+		}
+		return s
+	default:
+		// Interfaces accept any value
+		return &Schema{}
+	}
+}
+
+// addFields adds the exported fields of a struct to s, the fields of embedded structs are promoted like
+// encoding/json does
+func addFields(s *Schema, t reflect.Type, direction Direction) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				addFields(s, embedded, direction)
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := generate(field.Type, direction)
+		rules := strings.Split(field.Tag.Get("validate"), ",")
+		applyRules(property, rules)
+
+		omitted := strings.Contains(options, "omitempty") || strings.Contains(options, "omitzero")
+		if field.Type.Kind() == reflect.Pointer && !omitted {
+			// A nil pointer is encoded as null
+			property = &Schema{AnyOf: []*Schema{property, {Type: "null"}}}
+		}
+		s.Properties[name] = property
+
+		if (direction == Request && slices.Contains(rules, "required")) || (direction == Response && !omitted) {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// applyRules translates the validate tag rules that have a JSON Schema equivalent
+func applyRules(s *Schema, rules []string) {
+	for _, rule := range rules {
+		key, value, _ := strings.Cut(rule, "=")
+		switch key {
+		case "email":
+			s.Format = "email"
+		case "min":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				continue
+			}
+			switch s.Type {
+			case "string":
+				s.MinLength = &n
+			case "array":
+				s.MinItems = &n
+			default:
+				minimum := float64(n)
+				s.Minimum = &minimum
+			}
+		case "gt":
+			n, err := strconv.ParseFloat(value, 64)
+			if err == nil {
+				s.ExclusiveMinimum = &n
+			}
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package tests
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/schema"
+)
+
+type address struct {
+	City string `json:"city"`
+}
+
+type base struct {
+	ID string `json:"id"`
+}
+
+type payload struct {
+	base
+	Email     string            `json:"email" validate:"required,email"`
+	Name      string            `json:"name,omitempty" validate:"min=2"`
+	Age       int               `json:"age" validate:"gt=0"`
+	Tags      []string          `json:"tags" validate:"min=1"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Extra     map[string]any    `json:"extra,omitempty"`
+	Address   *address          `json:"address"`
+	CreatedAt time.Time         `json:"created_at"`
+	Secret    string            `json:"-"`
+	internal  string
+}
+
+func TestGenerate(t *testing.T) {
+	s := schema.Generate("Payload", payload{}, schema.Request)
+
+	if s.Dialect != schema.Dialect || s.Title != "Payload" || s.Type != "object" {
+		t.Fatalf("Generate() = %+v, want an object document titled Payload", s)
+	}
+	if !reflect.DeepEqual(s.Required, []string{"email"}) {
+		t.Errorf("request Required = %v, want the properties validated as required", s.Required)
+	}
+
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	want := []string{"address", "age", "created_at", "email", "extra", "id", "labels", "name", "tags"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("properties = %v, want %v", names, want)
+	}
+
+	if p := s.Properties["email"]; p.Type != "string" || p.Format != "email" {
+		t.Errorf("email = %+v, want a string with the email format", p)
+	}
+	if p := s.Properties["name"]; p.MinLength == nil || *p.MinLength != 2 {
+		t.Errorf("name = %+v, want minLength 2", p)
+	}
+	if p := s.Properties["age"]; p.Type != "integer" || p.ExclusiveMinimum == nil || *p.ExclusiveMinimum != 0 {
+		t.Errorf("age = %+v, want an integer above 0", p)
+	}
+	if p := s.Properties["tags"]; p.Type != "array" || p.Items.Type != "string" || p.MinItems == nil || *p.MinItems != 1 {
+		t.Errorf("tags = %+v, want a non-empty array of strings", p)
+	}
+	if p := s.Properties["labels"]; p.Type != "object" || p.AdditionalProperties == nil || p.AdditionalProperties.Type != "string" {
+		t.Errorf("labels = %+v, want an object of strings", p)
+	}
+	if p := s.Properties["extra"]; p.Type != "object" || p.AdditionalProperties != nil {
+		t.Errorf("extra = %+v, want an object of any values", p)
+	}
+	if p := s.Properties["created_at"]; p.Type != "string" || p.Format != "date-time" {
+		t.Errorf("created_at = %+v, want a date-time string", p)
+	}
+	if p := s.Properties["address"]; len(p.AnyOf) != 2 || p.AnyOf[0].Properties["city"] == nil || p.AnyOf[1].Type != "null" {
+		t.Errorf("address = %+v, want a nullable object", p)
+	}
+
+	response := schema.Generate("Payload", payload{}, schema.Response)
+	wantRequired := []string{"id", "email", "age", "tags", "address", "created_at"}
+	if !reflect.DeepEqual(response.Required, wantRequired) {
+		t.Errorf("response Required = %v, want %v", response.Required, wantRequired)
+	}
+}
+
+// dtoDirs are the packages of the DTOs, relative to this directory
+var dtoDirs = []string{"../../request", "../../response"}
+
+func TestDocuments_CoverEveryDTO(t *testing.T) {
+	registered := make(map[string]bool)
+	for _, document := range schema.Documents() {
+		registered[document.Name] = true
+	}
+
+	fset := token.NewFileSet()
+	for _, dir := range dtoDirs {
+		files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, path := range files {
+			file, err := parser.ParseFile(fset, path, nil, 0)
+			if err != nil {
+				t.Fatalf("failed to parse %s: %v", path, err)
+			}
+			for _, decl := range file.Decls {
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok || gen.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range gen.Specs {
+					name := spec.(*ast.TypeSpec).Name.Name
+					if ast.IsExported(name) && !registered[name] {
+						t.Errorf("%s in %s has no schema document, add it to the DTOs of the schema package", name, path)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestDocuments_MatchDocsSchemas(t *testing.T) {
+	dir := filepath.Join("..", "..", "..", "..", "..", "..", "docs", "schemas")
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	documents := schema.Documents()
+	if len(files) != len(documents) {
+		t.Errorf("docs/schemas has %d documents, want %d, run go generate ./internal/adapters/http/dto/schema", len(files), len(documents))
+	}
+	for _, document := range documents {
+		want, err := document.Marshal()
+		if err != nil {
+			t.Fatalf("Marshal(%s) error = %v", document.Name, err)
+		}
+		got, err := os.ReadFile(filepath.Join(dir, document.Name+".json"))
+		if err != nil || !bytes.Equal(got, want) {
+			t.Errorf("docs/schemas/%s.json is outdated, run go generate ./internal/adapters/http/dto/schema", document.Name)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package errors
 import (
 	"errors"
 	nethttp "net/http"
+	"sort"
 
 	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
 )
@@ -28,24 +29,114 @@ func NewHTTPError(statusCode int, message, code string) *HTTPError {
 	}
 }
 
+// catalog holds every predefined HTTP error, see Catalog
+var catalog []*HTTPError
+
+// define creates a predefined HTTP error and registers it in the catalog
+func define(statusCode int, message, code string) *HTTPError {
+	err := NewHTTPError(statusCode, message, code)
+	catalog = append(catalog, err)
+	return err
+}
+
+// Catalog returns every predefined HTTP error sorted by code
+func Catalog() []*HTTPError {
+	errs := make([]*HTTPError, len(catalog))
+	copy(errs, catalog)
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Code < errs[j].Code })
+	return errs
+}
+
 // Predefined HTTP errors
 var (
-	ErrBadRequest                  = NewHTTPError(nethttp.StatusBadRequest, "Bad request", "BAD_REQUEST")
-	ErrUnauthorized                = NewHTTPError(nethttp.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
-	ErrForbidden                   = NewHTTPError(nethttp.StatusForbidden, "Forbidden", "FORBIDDEN")
-	ErrNotFound                    = NewHTTPError(nethttp.StatusNotFound, "Resource not found", "NOT_FOUND")
-	ErrConflict                    = NewHTTPError(nethttp.StatusConflict, "Resource conflict", "CONFLICT")
-	ErrInternalServer              = NewHTTPError(nethttp.StatusInternalServerError, "Internal server error", "INTERNAL_SERVER_ERROR")
-	ErrInvalidCredentials          = NewHTTPError(nethttp.StatusUnauthorized, "Invalid credentials", "INVALID_CREDENTIALS")
-	ErrInvalidToken                = NewHTTPError(nethttp.StatusUnauthorized, "Invalid or expired token", "INVALID_TOKEN")
-	ErrTokenRevoked                = NewHTTPError(nethttp.StatusUnauthorized, "Token has been revoked", "TOKEN_REVOKED")
-	ErrUserAlreadyExists           = NewHTTPError(nethttp.StatusConflict, "User already exists", "USER_ALREADY_EXISTS")
-	ErrCitizenExistsInCentralizer  = NewHTTPError(nethttp.StatusConflict, "Citizen already exists in centralizer", "CITIZEN_EXISTS_IN_CENTRALIZER")
-	ErrUserNotFound                = NewHTTPError(nethttp.StatusNotFound, "User not found", "USER_NOT_FOUND")
-	ErrMissingAuthHeader           = NewHTTPError(nethttp.StatusUnauthorized, "Missing authorization header", "MISSING_AUTH_HEADER")
-	ErrInvalidAuthHeader           = NewHTTPError(nethttp.StatusUnauthorized, "Invalid authorization header format", "INVALID_AUTH_HEADER")
-	ErrRequiredField               = NewHTTPError(nethttp.StatusBadRequest, "Required field is missing", "REQUIRED_FIELD")
-	ErrInvalidRequestBody          = NewHTTPError(nethttp.StatusBadRequest, "Invalid request body", "INVALID_REQUEST_BODY")
+	ErrBadRequest                  = define(nethttp.StatusBadRequest, "Bad request", "BAD_REQUEST")
+	ErrUnauthorized                = define(nethttp.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
+	ErrForbidden                   = define(nethttp.StatusForbidden, "Forbidden", "FORBIDDEN")
+	ErrNotFound                    = define(nethttp.StatusNotFound, "Resource not found", "NOT_FOUND")
+	ErrConflict                    = define(nethttp.StatusConflict, "Resource conflict", "CONFLICT")
+	ErrInternalServer              = define(nethttp.StatusInternalServerError, "Internal server error", "INTERNAL_SERVER_ERROR")
+	ErrInvalidCredentials          = define(nethttp.StatusUnauthorized, "Invalid credentials", "INVALID_CREDENTIALS")
+	ErrInvalidToken                = define(nethttp.StatusUnauthorized, "Invalid or expired token", "INVALID_TOKEN")
+	ErrTokenRevoked                = define(nethttp.StatusUnauthorized, "Token has been revoked", "TOKEN_REVOKED")
+	ErrUserAlreadyExists           = define(nethttp.StatusConflict, "User already exists", "USER_ALREADY_EXISTS")
+	ErrCitizenExistsInCentralizer  = define(nethttp.StatusConflict, "Citizen already exists in centralizer", "CITIZEN_EXISTS_IN_CENTRALIZER")
+	ErrUserNotFound                = define(nethttp.StatusNotFound, "User not found", "USER_NOT_FOUND")
+	ErrUserSuspended               = define(nethttp.StatusForbidden, "User account is suspended", "USER_SUSPENDED")
+	ErrAuthenticationDenied        = define(nethttp.StatusForbidden, "Authentication denied, contact support if the problem persists", "AUTHENTICATION_DENIED")
+	ErrUserAlreadyAnonymized       = define(nethttp.StatusConflict, "User is already anonymized", "USER_ALREADY_ANONYMIZED")
+	ErrInvalidUserMerge            = define(nethttp.StatusConflict, "Users can't be merged, they must be two distinct human accounts that are not anonymized", "INVALID_USER_MERGE")
+	ErrPasswordChangeRequired      = define(nethttp.StatusForbidden, "The temporary password must be changed at /login/password-change before signing in", "PASSWORD_CHANGE_REQUIRED")
+	ErrPasswordChangeNotRequired   = define(nethttp.StatusConflict, "The password of the user is not temporary", "PASSWORD_CHANGE_NOT_REQUIRED")
+	ErrMissingAuthHeader           = define(nethttp.StatusUnauthorized, "Missing authorization header", "MISSING_AUTH_HEADER")
+	ErrInvalidAuthHeader           = define(nethttp.StatusUnauthorized, "Invalid authorization header format", "INVALID_AUTH_HEADER")
+	ErrRequiredField               = define(nethttp.StatusBadRequest, "Required field is missing", "REQUIRED_FIELD")
+	ErrInvalidRequestBody          = define(nethttp.StatusBadRequest, "Invalid request body", "INVALID_REQUEST_BODY")
+	ErrInvalidClient               = define(nethttp.StatusUnauthorized, "Invalid client", "INVALID_CLIENT")
+	ErrUnsupportedGrantType        = define(nethttp.StatusBadRequest, "Unsupported grant_type", "UNSUPPORTED_GRANT_TYPE")
+	ErrUnauthorizedClient          = define(nethttp.StatusBadRequest, "Client is not authorized to use this grant_type", "UNAUTHORIZED_CLIENT")
+	ErrInvalidRedirectURI          = define(nethttp.StatusBadRequest, "Invalid redirect_uri, it must be an absolute https, loopback http or private-use scheme URI registered for the client", "INVALID_REDIRECT_URI")
+	ErrRedirectURINotFound         = define(nethttp.StatusNotFound, "Redirect URI is not registered for the client", "REDIRECT_URI_NOT_FOUND")
+	ErrAccessTokenTooLarge         = define(nethttp.StatusBadRequest, "The access tokens of the client would exceed the maximum token size", "ACCESS_TOKEN_TOO_LARGE")
+	ErrScopeNotFound               = define(nethttp.StatusNotFound, "Scope not found", "SCOPE_NOT_FOUND")
+	ErrScopeAlreadyExists          = define(nethttp.StatusConflict, "Scope already exists", "SCOPE_ALREADY_EXISTS")
+	ErrInvalidScopeName            = define(nethttp.StatusBadRequest, "Invalid scope name", "INVALID_SCOPE_NAME")
+	ErrUnknownScope                = define(nethttp.StatusBadRequest, "Scope is not registered", "INVALID_SCOPE")
+	ErrSystemScope                 = define(nethttp.StatusForbidden, "System scopes cannot be modified or deleted", "SYSTEM_SCOPE")
+	ErrScopeInUse                  = define(nethttp.StatusConflict, "Scope is assigned to one or more clients", "SCOPE_IN_USE")
+	ErrConsentNotFound             = define(nethttp.StatusNotFound, "Consent not found", "CONSENT_NOT_FOUND")
+	ErrAuthorizationPending        = define(nethttp.StatusBadRequest, "Authorization request is still pending", "AUTHORIZATION_PENDING")
+	ErrSlowDown                    = define(nethttp.StatusBadRequest, "Polling too frequently, slow down", "SLOW_DOWN")
+	ErrAccessDenied                = define(nethttp.StatusBadRequest, "Authorization request was denied", "ACCESS_DENIED")
+	ErrExpiredDeviceCode           = define(nethttp.StatusBadRequest, "Device code is invalid or has expired", "EXPIRED_TOKEN")
+	ErrInvalidUserCode             = define(nethttp.StatusBadRequest, "Invalid or expired user code", "INVALID_USER_CODE")
+	ErrInvalidPhoneNumber          = define(nethttp.StatusBadRequest, "Phone number must be in E.164 format", "INVALID_PHONE_NUMBER")
+	ErrPhoneNotFound               = define(nethttp.StatusNotFound, "Phone number not found", "PHONE_NOT_FOUND")
+	ErrInvalidVerificationCode     = define(nethttp.StatusBadRequest, "Invalid or expired verification code", "INVALID_VERIFICATION_CODE")
+	ErrSMSRateLimited              = define(nethttp.StatusTooManyRequests, "Too many SMS sent to this phone number, try again later", "SMS_RATE_LIMITED")
+	ErrSMSQuotaExceeded            = define(nethttp.StatusServiceUnavailable, "SMS sending is temporarily unavailable", "SMS_QUOTA_EXCEEDED")
+	ErrSMSDeliveryFailed           = define(nethttp.StatusBadGateway, "Failed to deliver SMS", "SMS_DELIVERY_FAILED")
+	ErrPhoneAlreadyRegistered      = define(nethttp.StatusConflict, "Phone number already registered by another user", "PHONE_ALREADY_REGISTERED")
+	ErrPhoneLoginDisabled          = define(nethttp.StatusBadRequest, "Login with a phone number is disabled", "PHONE_LOGIN_DISABLED")
+	ErrTokenQuotaExceeded          = define(nethttp.StatusTooManyRequests, "Token issuance quota exceeded, try again later", "TOKEN_QUOTA_EXCEEDED")
+	ErrClientLockedOut             = define(nethttp.StatusTooManyRequests, "Client temporarily locked out after repeated authentication failures, try again later", "CLIENT_LOCKED_OUT")
+	ErrClientSecretExpired         = define(nethttp.StatusUnauthorized, "Client secret has expired", "CLIENT_SECRET_EXPIRED")
+	ErrSessionQuotaExceeded        = define(nethttp.StatusForbidden, "Maximum number of active sessions reached", "SESSION_QUOTA_EXCEEDED")
+	ErrQuotaNotFound               = define(nethttp.StatusNotFound, "Quota not found", "QUOTA_NOT_FOUND")
+	ErrInvalidQuota                = define(nethttp.StatusBadRequest, "Invalid quota, limits must not be negative and active sessions only apply to users", "INVALID_QUOTA")
+	ErrSignedRequestRequired       = define(nethttp.StatusUnauthorized, "The client must sign its token requests with a timestamp, nonce and signature", "SIGNED_REQUEST_REQUIRED")
+	ErrInvalidRequestSignature     = define(nethttp.StatusUnauthorized, "Invalid request signature", "INVALID_REQUEST_SIGNATURE")
+	ErrStaleRequest                = define(nethttp.StatusUnauthorized, "Request timestamp is outside the allowed window", "STALE_REQUEST")
+	ErrReplayedRequest             = define(nethttp.StatusUnauthorized, "Request nonce has already been used", "REPLAYED_REQUEST")
+	ErrInvalidEmail                = define(nethttp.StatusBadRequest, "Invalid email address", "INVALID_EMAIL")
+	ErrWeakPassword                = define(nethttp.StatusBadRequest, "Password must be at least 8 characters", "WEAK_PASSWORD")
+	ErrInvalidName                 = define(nethttp.StatusBadRequest, "Name is empty, too short or too long", "INVALID_NAME")
+	ErrEmailNotFound               = define(nethttp.StatusNotFound, "Email address not found", "EMAIL_NOT_FOUND")
+	ErrEmailAlreadyRegistered      = define(nethttp.StatusConflict, "Email address is already registered", "EMAIL_ALREADY_REGISTERED")
+	ErrTooManyEmails               = define(nethttp.StatusConflict, "Maximum number of email addresses reached", "TOO_MANY_EMAILS")
+	ErrEmailRateLimited            = define(nethttp.StatusTooManyRequests, "Too many emails sent to this address, try again later", "EMAIL_RATE_LIMITED")
+	ErrInvalidResetToken           = define(nethttp.StatusBadRequest, "Invalid or expired password reset token", "INVALID_RESET_TOKEN")
+	ErrInvalidEmailChangeToken     = define(nethttp.StatusBadRequest, "Invalid or expired email change token", "INVALID_EMAIL_CHANGE_TOKEN")
+	ErrInvalidExportFilter         = define(nethttp.StatusBadRequest, "Invalid export filter, check the format, the filter values and the time range", "INVALID_EXPORT_FILTER")
+	ErrExportJobNotFound           = define(nethttp.StatusNotFound, "Export not found or expired", "EXPORT_JOB_NOT_FOUND")
+	ErrExportsBusy                 = define(nethttp.StatusServiceUnavailable, "Too many exports in progress, try again later", "EXPORTS_BUSY")
+	ErrAvatarNotFound              = define(nethttp.StatusNotFound, "Avatar not found", "AVATAR_NOT_FOUND")
+	ErrAvatarTooLarge              = define(nethttp.StatusRequestEntityTooLarge, "Avatar exceeds the maximum size", "AVATAR_TOO_LARGE")
+	ErrUnsupportedAvatarType       = define(nethttp.StatusUnsupportedMediaType, "Avatar must be a PNG, JPEG or WebP image", "UNSUPPORTED_AVATAR_TYPE")
+	ErrInvalidUserMetadata         = define(nethttp.StatusBadRequest, "Invalid metadata, keys must be declared in the metadata schema and values must have the declared type", "INVALID_USER_METADATA")
+	ErrUserMetadataTooLarge        = define(nethttp.StatusRequestEntityTooLarge, "User metadata exceeds the maximum size", "USER_METADATA_TOO_LARGE")
+	ErrMetadataKeyNotWritable      = define(nethttp.StatusForbidden, "The metadata key cannot be changed by the user", "METADATA_KEY_NOT_WRITABLE")
+	ErrCentralizerBusy             = define(nethttp.StatusServiceUnavailable, "The citizen registry is busy, try again later", "CENTRALIZER_BUSY")
+	ErrDatabaseBusy                = define(nethttp.StatusServiceUnavailable, "The service is busy, try again later", "DB_BUSY")
+	ErrUserPendingApproval         = define(nethttp.StatusForbidden, "User registration is pending approval by an administrator", "USER_PENDING_APPROVAL")
+	ErrUserRejected                = define(nethttp.StatusForbidden, "User registration was rejected", "USER_REJECTED")
+	ErrUserNotPendingApproval      = define(nethttp.StatusConflict, "User registration is not pending approval", "USER_NOT_PENDING_APPROVAL")
+	ErrInvalidUserListFilter       = define(nethttp.StatusBadRequest, "Invalid users filter, check the status and the pagination", "INVALID_USER_LIST_FILTER")
+	ErrInvalidRole                 = define(nethttp.StatusBadRequest, "Invalid role, it must be USER or ADMIN", "INVALID_ROLE")
+	ErrSudoRequired                = define(nethttp.StatusForbidden, "This operation requires elevated access, re-enter your password at /sudo", "SUDO_REQUIRED")
+	ErrMalformedToken              = define(nethttp.StatusBadRequest, "The token is not a JWT and can't be decoded", "MALFORMED_TOKEN")
+	ErrVersionConflict             = define(nethttp.StatusConflict, "The resource was modified by another request, reload it and retry", "VERSION_CONFLICT")
+	ErrInvalidIfMatch              = define(nethttp.StatusBadRequest, "Invalid If-Match header, it must be the ETag of the resource", "INVALID_IF_MATCH")
+	ErrPreconditionRequired        = define(nethttp.StatusPreconditionRequired, "This update requires an If-Match header with the ETag of the resource", "PRECONDITION_REQUIRED")
 )
 
 // MapDomainError maps domain errors to HTTP errors
@@ -58,12 +149,26 @@ func MapDomainError(err error) *HTTPError {
 	switch {
 	case errors.Is(err, domainerrors.ErrUserNotFound):
 		return ErrUserNotFound
+	case errors.Is(err, domainerrors.ErrUserSuspended):
+		return ErrUserSuspended
+	case errors.Is(err, domainerrors.ErrAuthenticationDenied):
+		return ErrAuthenticationDenied
+	case errors.Is(err, domainerrors.ErrUserAlreadyAnonymized):
+		return ErrUserAlreadyAnonymized
+	case errors.Is(err, domainerrors.ErrInvalidUserMerge):
+		return ErrInvalidUserMerge
+	case errors.Is(err, domainerrors.ErrPasswordChangeRequired):
+		return ErrPasswordChangeRequired
+	case errors.Is(err, domainerrors.ErrPasswordChangeNotRequired):
+		return ErrPasswordChangeNotRequired
 	case errors.Is(err, domainerrors.ErrUserAlreadyExists):
 		return ErrUserAlreadyExists
 	case errors.Is(err, domainerrors.ErrCitizenExistsInCentralizer):
 		return ErrCitizenExistsInCentralizer
 	case errors.Is(err, domainerrors.ErrInvalidCredentials):
 		return ErrInvalidCredentials
+	case errors.Is(err, domainerrors.ErrInvalidGrant):
+		return ErrInvalidCredentials
 	case errors.Is(err, domainerrors.ErrInvalidToken):
 		return ErrInvalidToken
 	case errors.Is(err, domainerrors.ErrExpiredToken):
@@ -78,6 +183,130 @@ func MapDomainError(err error) *HTTPError {
 		return ErrForbidden
 	case errors.Is(err, domainerrors.ErrBadRequest):
 		return ErrBadRequest
+	case errors.Is(err, domainerrors.ErrInvalidClient):
+		return ErrInvalidClient
+	case errors.Is(err, domainerrors.ErrUnsupportedGrantType):
+		return ErrUnsupportedGrantType
+	case errors.Is(err, domainerrors.ErrUnauthorizedClient):
+		return ErrUnauthorizedClient
+	case errors.Is(err, domainerrors.ErrInvalidRedirectURI):
+		return ErrInvalidRedirectURI
+	case errors.Is(err, domainerrors.ErrRedirectURINotFound):
+		return ErrRedirectURINotFound
+	case errors.Is(err, domainerrors.ErrAccessTokenTooLarge):
+		return ErrAccessTokenTooLarge
+	case errors.Is(err, domainerrors.ErrScopeNotFound):
+		return ErrScopeNotFound
+	case errors.Is(err, domainerrors.ErrScopeAlreadyExists):
+		return ErrScopeAlreadyExists
+	case errors.Is(err, domainerrors.ErrInvalidScopeName):
+		return ErrInvalidScopeName
+	case errors.Is(err, domainerrors.ErrUnknownScope):
+		return ErrUnknownScope
+	case errors.Is(err, domainerrors.ErrSystemScope):
+		return ErrSystemScope
+	case errors.Is(err, domainerrors.ErrScopeInUse):
+		return ErrScopeInUse
+	case errors.Is(err, domainerrors.ErrClientNotFound):
+		return ErrNotFound
+	case errors.Is(err, domainerrors.ErrConsentNotFound):
+		return ErrConsentNotFound
+	case errors.Is(err, domainerrors.ErrAuthorizationPending):
+		return ErrAuthorizationPending
+	case errors.Is(err, domainerrors.ErrSlowDown):
+		return ErrSlowDown
+	case errors.Is(err, domainerrors.ErrAccessDenied):
+		return ErrAccessDenied
+	case errors.Is(err, domainerrors.ErrDeviceCodeExpired):
+		return ErrExpiredDeviceCode
+	case errors.Is(err, domainerrors.ErrInvalidUserCode):
+		return ErrInvalidUserCode
+	case errors.Is(err, domainerrors.ErrInvalidPhoneNumber):
+		return ErrInvalidPhoneNumber
+	case errors.Is(err, domainerrors.ErrPhoneNotFound):
+		return ErrPhoneNotFound
+	case errors.Is(err, domainerrors.ErrInvalidVerificationCode):
+		return ErrInvalidVerificationCode
+	case errors.Is(err, domainerrors.ErrSMSRateLimited):
+		return ErrSMSRateLimited
+	case errors.Is(err, domainerrors.ErrSMSQuotaExceeded):
+		return ErrSMSQuotaExceeded
+	case errors.Is(err, domainerrors.ErrSMSDeliveryFailed):
+		return ErrSMSDeliveryFailed
+	case errors.Is(err, domainerrors.ErrPhoneAlreadyRegistered):
+		return ErrPhoneAlreadyRegistered
+	case errors.Is(err, domainerrors.ErrPhoneLoginDisabled):
+		return ErrPhoneLoginDisabled
+	case errors.Is(err, domainerrors.ErrTokenQuotaExceeded):
+		return ErrTokenQuotaExceeded
+	case errors.Is(err, domainerrors.ErrClientLockedOut):
+		return ErrClientLockedOut
+	case errors.Is(err, domainerrors.ErrClientSecretExpired):
+		return ErrClientSecretExpired
+	case errors.Is(err, domainerrors.ErrSessionQuotaExceeded):
+		return ErrSessionQuotaExceeded
+	case errors.Is(err, domainerrors.ErrQuotaNotFound):
+		return ErrQuotaNotFound
+	case errors.Is(err, domainerrors.ErrInvalidQuota):
+		return ErrInvalidQuota
+	case errors.Is(err, domainerrors.ErrSignedRequestRequired):
+		return ErrSignedRequestRequired
+	case errors.Is(err, domainerrors.ErrInvalidRequestSignature):
+		return ErrInvalidRequestSignature
+	case errors.Is(err, domainerrors.ErrStaleRequest):
+		return ErrStaleRequest
+	case errors.Is(err, domainerrors.ErrReplayedRequest):
+		return ErrReplayedRequest
+	case errors.Is(err, domainerrors.ErrInvalidEmail):
+		return ErrInvalidEmail
+	case errors.Is(err, domainerrors.ErrWeakPassword):
+		return ErrWeakPassword
+	case errors.Is(err, domainerrors.ErrInvalidName):
+		return ErrInvalidName
+	case errors.Is(err, domainerrors.ErrEmailNotFound):
+		return ErrEmailNotFound
+	case errors.Is(err, domainerrors.ErrEmailAlreadyRegistered):
+		return ErrEmailAlreadyRegistered
+	case errors.Is(err, domainerrors.ErrTooManyEmails):
+		return ErrTooManyEmails
+	case errors.Is(err, domainerrors.ErrEmailRateLimited):
+		return ErrEmailRateLimited
+	case errors.Is(err, domainerrors.ErrInvalidResetToken):
+		return ErrInvalidResetToken
+	case errors.Is(err, domainerrors.ErrInvalidEmailChangeToken):
+		return ErrInvalidEmailChangeToken
+	case errors.Is(err, domainerrors.ErrInvalidExportFilter):
+		return ErrInvalidExportFilter
+	case errors.Is(err, domainerrors.ErrExportJobNotFound):
+		return ErrExportJobNotFound
+	case errors.Is(err, domainerrors.ErrExportsBusy):
+		return ErrExportsBusy
+	case errors.Is(err, domainerrors.ErrAvatarNotFound):
+		return ErrAvatarNotFound
+	case errors.Is(err, domainerrors.ErrAvatarTooLarge):
+		return ErrAvatarTooLarge
+	case errors.Is(err, domainerrors.ErrUnsupportedAvatarType):
+		return ErrUnsupportedAvatarType
+	case errors.Is(err, domainerrors.ErrInvalidUserMetadata):
+		return ErrInvalidUserMetadata
+	case errors.Is(err, domainerrors.ErrUserMetadataTooLarge):
+		return ErrUserMetadataTooLarge
+	case errors.Is(err, domainerrors.ErrMetadataKeyNotWritable):
+		return ErrMetadataKeyNotWritable
+	case errors.Is(err, domainerrors.ErrCentralizerBusy):
+		return ErrCentralizerBusy
+	case errors.Is(err, domainerrors.ErrDatabaseBusy):
+		return ErrDatabaseBusy
+	case errors.Is(err, domainerrors.ErrVersionConflict):
+		return ErrVersionConflict
+	case errors.Is(err, domainerrors.ErrVersionRequired):
+		return ErrPreconditionRequired
+	case errors.Is(err, domainerrors.ErrUserPendingApproval):
+		return ErrUserPendingApproval
+	case errors.Is(err, domainerrors.ErrUserRejected):
+		return ErrUserRejected
+	case errors.Is(err, domainerrors.ErrUserNotPendingApproval):
+		return ErrUserNotPendingApproval
 	default:
 		// Error genérico
 		return ErrInternalServer
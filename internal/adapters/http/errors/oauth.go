@@ -0,0 +1,137 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	nethttp "net/http"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+)
+
+// OAuthError represents an error of the OAuth endpoints, with the error codes of RFC 6749 section 5.2
+// and RFC 8628 section 3.5 that OAuth client libraries understand
+type OAuthError struct {
+	StatusCode  int
+	Code        string
+	Description string
+}
+
+// Error implements the error interface
+func (e *OAuthError) Error() string {
+	return e.Code
+}
+
+// NewOAuthError creates a new OAuthError
+func NewOAuthError(statusCode int, code, description string) *OAuthError {
+	return &OAuthError{
+		StatusCode:  statusCode,
+		Code:        code,
+		Description: description,
+	}
+}
+
+// Predefined OAuth errors
+var (
+	ErrOAuthInvalidRequest       = NewOAuthError(nethttp.StatusBadRequest, "invalid_request", "A required parameter is missing")
+	ErrOAuthMalformedRequest     = NewOAuthError(nethttp.StatusBadRequest, "invalid_request", "The request body is malformed")
+	ErrOAuthMultipleClientAuth   = NewOAuthError(nethttp.StatusBadRequest, "invalid_request", "The client must authenticate with a single method, either HTTP Basic or the request body")
+	ErrOAuthInvalidClient        = NewOAuthError(nethttp.StatusUnauthorized, "invalid_client", "Client authentication failed")
+	ErrOAuthUnsupportedGrantType = NewOAuthError(nethttp.StatusBadRequest, "unsupported_grant_type", "The grant_type is not supported")
+	ErrOAuthServerError          = NewOAuthError(nethttp.StatusInternalServerError, "server_error", "Internal server error")
+)
+
+// MapOAuthError maps a domain error to an OAuth error. The description is the message of the HTTP
+// error of the other endpoints, so both read the same.
+func MapOAuthError(err error) *OAuthError {
+	if err == nil {
+		return nil
+	}
+
+	var status int
+	var code string
+	switch {
+	case errors.Is(err, domainerrors.ErrInvalidGrant),
+		errors.Is(err, domainerrors.ErrUserSuspended),
+		errors.Is(err, domainerrors.ErrAuthenticationDenied),
+		errors.Is(err, domainerrors.ErrUserPendingApproval),
+		errors.Is(err, domainerrors.ErrUserRejected),
+		errors.Is(err, domainerrors.ErrPasswordChangeRequired),
+		errors.Is(err, domainerrors.ErrSessionQuotaExceeded):
+		// The resource owner credentials or the user they belong to can't be used
+		status, code = nethttp.StatusBadRequest, "invalid_grant"
+	case errors.Is(err, domainerrors.ErrInvalidClient),
+		errors.Is(err, domainerrors.ErrInvalidCredentials),
+		errors.Is(err, domainerrors.ErrClientSecretExpired),
+		errors.Is(err, domainerrors.ErrSignedRequestRequired),
+		errors.Is(err, domainerrors.ErrInvalidRequestSignature),
+		errors.Is(err, domainerrors.ErrStaleRequest),
+		errors.Is(err, domainerrors.ErrReplayedRequest):
+		status, code = nethttp.StatusUnauthorized, "invalid_client"
+	case errors.Is(err, domainerrors.ErrUnauthorizedClient):
+		status, code = nethttp.StatusBadRequest, "unauthorized_client"
+	case errors.Is(err, domainerrors.ErrUnsupportedGrantType):
+		status, code = nethttp.StatusBadRequest, "unsupported_grant_type"
+	case errors.Is(err, domainerrors.ErrUnknownScope),
+		errors.Is(err, domainerrors.ErrInvalidScopeName):
+		status, code = nethttp.StatusBadRequest, "invalid_scope"
+	case errors.Is(err, domainerrors.ErrAuthorizationPending):
+		status, code = nethttp.StatusBadRequest, "authorization_pending"
+	case errors.Is(err, domainerrors.ErrSlowDown):
+		status, code = nethttp.StatusBadRequest, "slow_down"
+	case errors.Is(err, domainerrors.ErrAccessDenied):
+		status, code = nethttp.StatusBadRequest, "access_denied"
+	case errors.Is(err, domainerrors.ErrDeviceCodeExpired):
+		status, code = nethttp.StatusBadRequest, "expired_token"
+	case errors.Is(err, domainerrors.ErrBadRequest),
+		errors.Is(err, domainerrors.ErrValidation):
+		status, code = nethttp.StatusBadRequest, "invalid_request"
+	case errors.Is(err, domainerrors.ErrTokenQuotaExceeded),
+		errors.Is(err, domainerrors.ErrClientLockedOut):
+		status, code = nethttp.StatusTooManyRequests, "temporarily_unavailable"
+	case errors.Is(err, domainerrors.ErrCentralizerBusy),
+		errors.Is(err, domainerrors.ErrDatabaseBusy):
+		status, code = nethttp.StatusServiceUnavailable, "temporarily_unavailable"
+	default:
+		return ErrOAuthServerError
+	}
+
+	return NewOAuthError(status, code, MapDomainError(err).Message)
+}
+
+// RespondWithOAuthError sends an OAuth error response, 401 responses carry the Basic challenge
+// (RFC 6749 section 5.2)
+func RespondWithOAuthError(w nethttp.ResponseWriter, err *OAuthError) {
+	if err.StatusCode == nethttp.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", `Basic realm="oauth", error="`+err.Code+`"`)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	w.WriteHeader(err.StatusCode)
+	_ = json.NewEncoder(w).Encode(response.OAuthErrorResponse{
+		Error:            err.Code,
+		ErrorDescription: err.Description,
+	})
+}
+
+// RespondWithOAuthDomainError maps a domain error to an OAuth error and sends the response.
+// Exhausted quotas are described in the error description, along with Retry-After when the quota resets.
+// Locked out clients get Retry-After with the end of the lockout.
+func RespondWithOAuthDomainError(w nethttp.ResponseWriter, err error) {
+	oauthErr := MapOAuthError(err)
+
+	var lockedOutErr *domainerrors.ClientLockedOutError
+	if errors.As(err, &lockedOutErr) {
+		setRetryAfter(w, lockedOutErr.RetryAfterSeconds())
+	}
+
+	var quotaErr *domainerrors.QuotaExceededError
+	if errors.As(err, &quotaErr) {
+		setRetryAfter(w, quotaErr.RetryAfterSeconds())
+		oauthErr = NewOAuthError(oauthErr.StatusCode, oauthErr.Code, quotaErr.Details())
+	}
+
+	RespondWithOAuthError(w, oauthErr)
+}
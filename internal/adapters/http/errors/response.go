@@ -2,17 +2,39 @@ package errors
 
 import (
 	"encoding/json"
+	"errors"
 	nethttp "net/http"
+	"strconv"
 
 	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
 )
 
 // RespondWithError sends an HTTP error response
 func RespondWithError(w nethttp.ResponseWriter, err *HTTPError) {
+	respondWithDetails(w, err, "")
+}
+
+// respondWithDetails sends an HTTP error response with details about the error
+func respondWithDetails(w nethttp.ResponseWriter, err *HTTPError, details string) {
 	resp := response.ErrorResponse{
 		Error:   err.Message,
 		Code:    err.Code,
-		Details: "",
+		Details: details,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(err.StatusCode)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// RespondWithRequestID sends an HTTP error response along with the ID of the request, so the caller can
+// report it and the error be found in the logs
+func RespondWithRequestID(w nethttp.ResponseWriter, err *HTTPError, requestID string) {
+	resp := response.ErrorResponse{
+		Error:     err.Message,
+		Code:      err.Code,
+		RequestID: requestID,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -33,8 +55,30 @@ func RespondWithErrorMessage(w nethttp.ResponseWriter, statusCode int, message s
 	_ = json.NewEncoder(w).Encode(resp)
 }
 
-// RespondWithDomainError maps a domain error and sends the HTTP response
+// RespondWithDomainError maps a domain error and sends the HTTP response.
+// Exhausted quotas are described in the details, along with Retry-After when the quota resets.
+// Locked out clients get Retry-After with the end of the lockout.
 func RespondWithDomainError(w nethttp.ResponseWriter, err error) {
 	httpErr := MapDomainError(err)
+
+	var lockedOutErr *domainerrors.ClientLockedOutError
+	if errors.As(err, &lockedOutErr) {
+		setRetryAfter(w, lockedOutErr.RetryAfterSeconds())
+	}
+
+	var quotaErr *domainerrors.QuotaExceededError
+	if errors.As(err, &quotaErr) {
+		setRetryAfter(w, quotaErr.RetryAfterSeconds())
+		respondWithDetails(w, httpErr, quotaErr.Details())
+		return
+	}
+
 	RespondWithError(w, httpErr)
 }
+
+// setRetryAfter sets the Retry-After header, unless the wait is already over
+func setRetryAfter(w nethttp.ResponseWriter, seconds int64) {
+	if seconds > 0 {
+		w.Header().Set("Retry-After", strconv.FormatInt(seconds, 10))
+	}
+}
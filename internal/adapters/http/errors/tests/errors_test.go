@@ -305,6 +305,166 @@ func TestMapDomainError(t *testing.T) {
 			domainErr:   domainerrors.ErrBadRequest,
 			wantHTTPErr: httperrors.ErrBadRequest,
 		},
+		{
+			name:        "ErrInvalidClient maps to ErrInvalidClient",
+			domainErr:   domainerrors.ErrInvalidClient,
+			wantHTTPErr: httperrors.ErrInvalidClient,
+		},
+		{
+			name:        "ErrUnsupportedGrantType maps to ErrUnsupportedGrantType",
+			domainErr:   domainerrors.ErrUnsupportedGrantType,
+			wantHTTPErr: httperrors.ErrUnsupportedGrantType,
+		},
+		{
+			name:        "ErrUnauthorizedClient maps to ErrUnauthorizedClient",
+			domainErr:   domainerrors.ErrUnauthorizedClient,
+			wantHTTPErr: httperrors.ErrUnauthorizedClient,
+		},
+		{
+			name:        "ErrUnknownScope maps to ErrUnknownScope",
+			domainErr:   domainerrors.ErrUnknownScope,
+			wantHTTPErr: httperrors.ErrUnknownScope,
+		},
+		{
+			name:        "ErrSystemScope maps to ErrSystemScope",
+			domainErr:   domainerrors.ErrSystemScope,
+			wantHTTPErr: httperrors.ErrSystemScope,
+		},
+		{
+			name:        "ErrScopeNotFound maps to ErrScopeNotFound",
+			domainErr:   domainerrors.ErrScopeNotFound,
+			wantHTTPErr: httperrors.ErrScopeNotFound,
+		},
+		{
+			name:        "ErrClientNotFound maps to ErrNotFound",
+			domainErr:   domainerrors.ErrClientNotFound,
+			wantHTTPErr: httperrors.ErrNotFound,
+		},
+		{
+			name:        "ErrConsentNotFound maps to ErrConsentNotFound",
+			domainErr:   domainerrors.ErrConsentNotFound,
+			wantHTTPErr: httperrors.ErrConsentNotFound,
+		},
+		{
+			name:        "ErrAuthorizationPending maps to ErrAuthorizationPending",
+			domainErr:   domainerrors.ErrAuthorizationPending,
+			wantHTTPErr: httperrors.ErrAuthorizationPending,
+		},
+		{
+			name:        "ErrSlowDown maps to ErrSlowDown",
+			domainErr:   domainerrors.ErrSlowDown,
+			wantHTTPErr: httperrors.ErrSlowDown,
+		},
+		{
+			name:        "ErrAccessDenied maps to ErrAccessDenied",
+			domainErr:   domainerrors.ErrAccessDenied,
+			wantHTTPErr: httperrors.ErrAccessDenied,
+		},
+		{
+			name:        "ErrDeviceCodeExpired maps to ErrExpiredDeviceCode",
+			domainErr:   domainerrors.ErrDeviceCodeExpired,
+			wantHTTPErr: httperrors.ErrExpiredDeviceCode,
+		},
+		{
+			name:        "ErrInvalidUserCode maps to ErrInvalidUserCode",
+			domainErr:   domainerrors.ErrInvalidUserCode,
+			wantHTTPErr: httperrors.ErrInvalidUserCode,
+		},
+		{
+			name:        "ErrInvalidPhoneNumber maps to ErrInvalidPhoneNumber",
+			domainErr:   domainerrors.ErrInvalidPhoneNumber,
+			wantHTTPErr: httperrors.ErrInvalidPhoneNumber,
+		},
+		{
+			name:        "ErrPhoneNotFound maps to ErrPhoneNotFound",
+			domainErr:   domainerrors.ErrPhoneNotFound,
+			wantHTTPErr: httperrors.ErrPhoneNotFound,
+		},
+		{
+			name:        "ErrInvalidVerificationCode maps to ErrInvalidVerificationCode",
+			domainErr:   domainerrors.ErrInvalidVerificationCode,
+			wantHTTPErr: httperrors.ErrInvalidVerificationCode,
+		},
+		{
+			name:        "ErrSMSRateLimited maps to ErrSMSRateLimited",
+			domainErr:   domainerrors.ErrSMSRateLimited,
+			wantHTTPErr: httperrors.ErrSMSRateLimited,
+		},
+		{
+			name:        "ErrSMSQuotaExceeded maps to ErrSMSQuotaExceeded",
+			domainErr:   domainerrors.ErrSMSQuotaExceeded,
+			wantHTTPErr: httperrors.ErrSMSQuotaExceeded,
+		},
+		{
+			name:        "ErrSMSDeliveryFailed maps to ErrSMSDeliveryFailed",
+			domainErr:   domainerrors.ErrSMSDeliveryFailed,
+			wantHTTPErr: httperrors.ErrSMSDeliveryFailed,
+		},
+		{
+			name:        "ErrAvatarNotFound maps to ErrAvatarNotFound",
+			domainErr:   domainerrors.ErrAvatarNotFound,
+			wantHTTPErr: httperrors.ErrAvatarNotFound,
+		},
+		{
+			name:        "ErrAvatarTooLarge maps to ErrAvatarTooLarge",
+			domainErr:   domainerrors.ErrAvatarTooLarge,
+			wantHTTPErr: httperrors.ErrAvatarTooLarge,
+		},
+		{
+			name:        "ErrUnsupportedAvatarType maps to ErrUnsupportedAvatarType",
+			domainErr:   domainerrors.ErrUnsupportedAvatarType,
+			wantHTTPErr: httperrors.ErrUnsupportedAvatarType,
+		},
+		{
+			name:        "ErrInvalidUserMetadata maps to ErrInvalidUserMetadata",
+			domainErr:   domainerrors.ErrInvalidUserMetadata,
+			wantHTTPErr: httperrors.ErrInvalidUserMetadata,
+		},
+		{
+			name:        "ErrUserMetadataTooLarge maps to ErrUserMetadataTooLarge",
+			domainErr:   domainerrors.ErrUserMetadataTooLarge,
+			wantHTTPErr: httperrors.ErrUserMetadataTooLarge,
+		},
+		{
+			name:        "ErrMetadataKeyNotWritable maps to ErrMetadataKeyNotWritable",
+			domainErr:   domainerrors.ErrMetadataKeyNotWritable,
+			wantHTTPErr: httperrors.ErrMetadataKeyNotWritable,
+		},
+		{
+			name:        "ErrCentralizerBusy maps to ErrCentralizerBusy",
+			domainErr:   domainerrors.ErrCentralizerBusy,
+			wantHTTPErr: httperrors.ErrCentralizerBusy,
+		},
+		{
+			name:        "ErrDatabaseBusy maps to ErrDatabaseBusy",
+			domainErr:   domainerrors.ErrDatabaseBusy,
+			wantHTTPErr: httperrors.ErrDatabaseBusy,
+		},
+		{
+			name:        "ErrUserPendingApproval maps to ErrUserPendingApproval",
+			domainErr:   domainerrors.ErrUserPendingApproval,
+			wantHTTPErr: httperrors.ErrUserPendingApproval,
+		},
+		{
+			name:        "ErrUserRejected maps to ErrUserRejected",
+			domainErr:   domainerrors.ErrUserRejected,
+			wantHTTPErr: httperrors.ErrUserRejected,
+		},
+		{
+			name:        "ErrUserNotPendingApproval maps to ErrUserNotPendingApproval",
+			domainErr:   domainerrors.ErrUserNotPendingApproval,
+			wantHTTPErr: httperrors.ErrUserNotPendingApproval,
+		},
+		{
+			name:        "ErrAuthenticationDenied maps to ErrAuthenticationDenied",
+			domainErr:   domainerrors.ErrAuthenticationDenied,
+			wantHTTPErr: httperrors.ErrAuthenticationDenied,
+		},
+		{
+			name:        "ErrUserAlreadyAnonymized maps to ErrUserAlreadyAnonymized",
+			domainErr:   domainerrors.ErrUserAlreadyAnonymized,
+			wantHTTPErr: httperrors.ErrUserAlreadyAnonymized,
+		},
 		{
 			name:        "unknown error maps to ErrInternalServer",
 			domainErr:   errors.New("some unknown error"),
@@ -345,3 +505,33 @@ func TestMapDomainError(t *testing.T) {
 		})
 	}
 }
+
+func TestCatalog(t *testing.T) {
+	catalog := httperrors.Catalog()
+
+	seen := make(map[string]bool, len(catalog))
+	for i, err := range catalog {
+		if err.Code == "" || err.Message == "" || err.StatusCode < 400 {
+			t.Errorf("Catalog() entry %+v is incomplete", err)
+		}
+		if seen[err.Code] {
+			t.Errorf("Catalog() code %v is duplicated", err.Code)
+		}
+		seen[err.Code] = true
+
+		if i > 0 && catalog[i-1].Code > err.Code {
+			t.Errorf("Catalog() is not sorted: %v before %v", catalog[i-1].Code, err.Code)
+		}
+	}
+
+	for _, want := range []*httperrors.HTTPError{httperrors.ErrBadRequest, httperrors.ErrInvalidCredentials, httperrors.ErrInvalidUserCode} {
+		if !seen[want.Code] {
+			t.Errorf("Catalog() is missing %v", want.Code)
+		}
+	}
+
+	// Errors returned for domain errors are listed too
+	if mapped := httperrors.MapDomainError(domainerrors.ErrConsentNotFound); !seen[mapped.Code] {
+		t.Errorf("Catalog() is missing mapped code %v", mapped.Code)
+	}
+}
@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+)
+
+func TestMapOAuthError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "invalid client", err: domainerrors.ErrInvalidClient, wantStatus: http.StatusUnauthorized, wantCode: "invalid_client"},
+		{name: "invalid client secret", err: domainerrors.ErrInvalidCredentials, wantStatus: http.StatusUnauthorized, wantCode: "invalid_client"},
+		{name: "replayed request", err: domainerrors.ErrReplayedRequest, wantStatus: http.StatusUnauthorized, wantCode: "invalid_client"},
+		{name: "expired client secret", err: domainerrors.ErrClientSecretExpired, wantStatus: http.StatusUnauthorized, wantCode: "invalid_client"},
+		{name: "invalid user credentials", err: fmt.Errorf("%w: %w", domainerrors.ErrInvalidGrant, domainerrors.ErrInvalidCredentials), wantStatus: http.StatusBadRequest, wantCode: "invalid_grant"},
+		{name: "suspended user", err: domainerrors.ErrUserSuspended, wantStatus: http.StatusBadRequest, wantCode: "invalid_grant"},
+		{name: "unauthorized client", err: domainerrors.ErrUnauthorizedClient, wantStatus: http.StatusBadRequest, wantCode: "unauthorized_client"},
+		{name: "unsupported grant type", err: domainerrors.ErrUnsupportedGrantType, wantStatus: http.StatusBadRequest, wantCode: "unsupported_grant_type"},
+		{name: "unknown scope", err: domainerrors.ErrUnknownScope, wantStatus: http.StatusBadRequest, wantCode: "invalid_scope"},
+		{name: "authorization pending", err: domainerrors.ErrAuthorizationPending, wantStatus: http.StatusBadRequest, wantCode: "authorization_pending"},
+		{name: "expired device code", err: domainerrors.ErrDeviceCodeExpired, wantStatus: http.StatusBadRequest, wantCode: "expired_token"},
+		{name: "token quota exceeded", err: domainerrors.ErrTokenQuotaExceeded, wantStatus: http.StatusTooManyRequests, wantCode: "temporarily_unavailable"},
+		{name: "client locked out", err: &domainerrors.ClientLockedOutError{RetryAfter: time.Minute}, wantStatus: http.StatusTooManyRequests, wantCode: "temporarily_unavailable"},
+		{name: "database busy", err: domainerrors.ErrDatabaseBusy, wantStatus: http.StatusServiceUnavailable, wantCode: "temporarily_unavailable"},
+		{name: "unknown error", err: errors.New("database error"), wantStatus: http.StatusInternalServerError, wantCode: "server_error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := httperrors.MapOAuthError(tt.err)
+			if got.StatusCode != tt.wantStatus || got.Code != tt.wantCode || got.Description == "" {
+				t.Errorf("MapOAuthError() = %+v, want %d %s with a description", got, tt.wantStatus, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestRespondWithOAuthError(t *testing.T) {
+	w := httptest.NewRecorder()
+	httperrors.RespondWithOAuthError(w, httperrors.ErrOAuthInvalidClient)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("status code = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+	if got := w.Header().Get("WWW-Authenticate"); got == "" {
+		t.Error("WWW-Authenticate header is missing on 401")
+	}
+	if got := w.Header().Get("Cache-Control"); got != "no-store" {
+		t.Errorf("Cache-Control = %q, want no-store", got)
+	}
+
+	var resp response.OAuthErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Error != "invalid_client" || resp.ErrorDescription == "" {
+		t.Errorf("response = %+v, want invalid_client with a description", resp)
+	}
+}
+
+func TestRespondWithOAuthDomainError_Quota(t *testing.T) {
+	w := httptest.NewRecorder()
+	httperrors.RespondWithOAuthDomainError(w, &domainerrors.QuotaExceededError{
+		Err:        domainerrors.ErrTokenQuotaExceeded,
+		Subject:    "client",
+		Limit:      100,
+		RetryAfter: 90 * time.Second,
+	})
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("status code = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+	if got := w.Header().Get("Retry-After"); got != "90" {
+		t.Errorf("Retry-After = %q, want 90", got)
+	}
+	if got := w.Header().Get("WWW-Authenticate"); got != "" {
+		t.Errorf("WWW-Authenticate = %q, want none outside 401", got)
+	}
+}
+
+func TestRespondWithOAuthDomainError_ClientLockedOut(t *testing.T) {
+	w := httptest.NewRecorder()
+	httperrors.RespondWithOAuthDomainError(w, &domainerrors.ClientLockedOutError{RetryAfter: 10*time.Minute + 500*time.Millisecond})
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("status code = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+	if got := w.Header().Get("Retry-After"); got != "601" {
+		t.Errorf("Retry-After = %q, want 601", got)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
 	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
@@ -278,3 +279,53 @@ func TestRespondWithDomainError(t *testing.T) {
 		})
 	}
 }
+
+func TestRespondWithDomainError_QuotaExceeded(t *testing.T) {
+	tests := []struct {
+		name           string
+		domainErr      error
+		wantStatusCode int
+		wantCode       string
+		wantDetails    string
+		wantRetryAfter string
+	}{
+		{
+			name:           "token quota sets Retry-After",
+			domainErr:      &domainerrors.QuotaExceededError{Err: domainerrors.ErrTokenQuotaExceeded, Subject: "client", Limit: 100, RetryAfter: 90*time.Second + time.Millisecond},
+			wantStatusCode: http.StatusTooManyRequests,
+			wantCode:       "TOKEN_QUOTA_EXCEEDED",
+			wantDetails:    "client quota of 100 tokens per hour reached, retry in 91 seconds",
+			wantRetryAfter: "91",
+		},
+		{
+			name:           "session quota",
+			domainErr:      &domainerrors.QuotaExceededError{Err: domainerrors.ErrSessionQuotaExceeded, Subject: "user", Limit: 3},
+			wantStatusCode: http.StatusForbidden,
+			wantCode:       "SESSION_QUOTA_EXCEEDED",
+			wantDetails:    "user quota of 3 active sessions reached, log out from another session first",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			httperrors.RespondWithDomainError(w, tt.domainErr)
+
+			if w.Code != tt.wantStatusCode {
+				t.Errorf("RespondWithDomainError() status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+			if retryAfter := w.Header().Get("Retry-After"); retryAfter != tt.wantRetryAfter {
+				t.Errorf("RespondWithDomainError() Retry-After = %q, want %q", retryAfter, tt.wantRetryAfter)
+			}
+
+			var errResp response.ErrorResponse
+			if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
+				t.Fatalf("Failed to decode response body: %v", err)
+			}
+			if errResp.Code != tt.wantCode || errResp.Details != tt.wantDetails {
+				t.Errorf("RespondWithDomainError() code = %v, details = %q, want %v, %q", errResp.Code, errResp.Details, tt.wantCode, tt.wantDetails)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package admin
+
+import (
+	"fmt"
+	nethttp "net/http"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+)
+
+// AnonymizeUser erases the personal data of a user (ADMIN only)
+// @Summary Anonymize User
+// @Description Scrubs the personal data of a user (GDPR erasure): the email is replaced by its hash and the name is redacted.
+// @Description The user row is kept for referential integrity, all sessions are revoked and a user.anonymized event is published.
+// @Tags Admin - Users
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} response.UserResponse "User anonymized successfully"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden - Admin role required"
+// @Failure 404 {object} response.ErrorResponse "User not found"
+// @Failure 409 {object} response.ErrorResponse "User is already anonymized"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /admin/users/{id}/anonymize [post]
+func AnonymizeUser(h *shared.AdminUsersHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		claims, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			httperrors.RespondWithError(w, httperrors.ErrUnauthorized)
+			return
+		}
+
+		id := mux.Vars(r)["id"]
+		user, err := h.AnonymizationService.AnonymizeUser(r.Context(), id, fmt.Sprintf("admin:%d", claims.IDCitizen))
+		if err != nil {
+			h.Logger.Warn("failed to anonymize user", zap.Error(err), logging.String("id", id))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		resp := response.UserResponse{
+			ID:        user.ID,
+			IDCitizen: user.IDCitizen,
+			Email:     user.Email,
+			Name:      user.Name,
+			Role:      user.Role,
+			CreatedAt: user.CreatedAt,
+			UpdatedAt: user.UpdatedAt,
+			Version:   user.Version,
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusOK, resp)
+	}
+}
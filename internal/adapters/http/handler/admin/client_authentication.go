@@ -0,0 +1,50 @@
+package admin
+
+import (
+	nethttp "net/http"
+	"net/url"
+
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+)
+
+// clientBasicAuth returns the client credentials of the Authorization: Basic header. Both are
+// form-urlencoded before being joined (RFC 6749 section 2.3.1), so "a%3Ab" is the client ID "a:b".
+// ok is false when the request has no Basic credentials.
+func clientBasicAuth(r *nethttp.Request) (clientID, clientSecret string, ok bool, err *httperrors.OAuthError) {
+	encodedID, encodedSecret, ok := r.BasicAuth()
+	if !ok {
+		return "", "", false, nil
+	}
+
+	clientID, decodeErr := url.QueryUnescape(encodedID)
+	if decodeErr != nil || clientID == "" {
+		return "", "", true, httperrors.ErrOAuthInvalidClient
+	}
+	clientSecret, decodeErr = url.QueryUnescape(encodedSecret)
+	if decodeErr != nil {
+		return "", "", true, httperrors.ErrOAuthInvalidClient
+	}
+	return clientID, clientSecret, true, nil
+}
+
+// authenticateClient resolves the client credentials of a request whose body credentials were already
+// parsed. The Basic header is preferred, the body is only used without it. A client must use a single
+// authentication method (RFC 6749 section 2.3), so a secret in both is rejected, as is a body client_id
+// that is not the authenticated one.
+func authenticateClient(r *nethttp.Request, clientID, clientSecret *string) *httperrors.OAuthError {
+	basicID, basicSecret, ok, err := clientBasicAuth(r)
+	if !ok || err != nil {
+		return err
+	}
+
+	if *clientSecret != "" {
+		return httperrors.ErrOAuthMultipleClientAuth
+	}
+	if *clientID != "" && *clientID != basicID {
+		return httperrors.ErrOAuthInvalidClient
+	}
+
+	*clientID = basicID
+	*clientSecret = basicSecret
+	return nil
+}
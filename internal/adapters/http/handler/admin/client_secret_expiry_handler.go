@@ -0,0 +1,128 @@
+package admin
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/request"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+)
+
+const (
+	// defaultExpiringSecretsDays is how many days ahead the expiring secrets are listed when not given
+	defaultExpiringSecretsDays = 30
+	maxExpiringSecretsDays     = 365
+)
+
+// SetClientSecretExpiry sets when the secret of an OAuth2 client expires (ADMIN only)
+// @Summary Set OAuth2 client secret expiry
+// @Description Sets when the secret of the client stops being accepted, null for never. Token requests with an expired secret are
+// @Description rejected with CLIENT_SECRET_EXPIRED. An expiry in the past makes the secret expire immediately.
+// @Tags Admin - OAuth Clients
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "OAuth client ID"
+// @Param request body request.ClientSecretExpiryRequest true "Secret expiry"
+// @Success 200 {object} response.OAuthClientResponse "Secret expiry updated"
+// @Failure 400 {object} response.ErrorResponse "Invalid request body"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden - Admin role required"
+// @Failure 404 {object} response.ErrorResponse "Client not found"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /admin/oauth-clients/{id}/secret-expiry [put]
+func SetClientSecretExpiry(h *shared.AdminOAuthClientsHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		id := mux.Vars(r)["id"]
+
+		var req request.ClientSecretExpiryRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.Logger.Debug("invalid request body", zap.Error(err))
+			httperrors.RespondWithError(w, httperrors.ErrInvalidRequestBody)
+			return
+		}
+
+		client, err := h.OAuth2Service.SetSecretExpiry(r.Context(), id, req.SecretExpiresAt)
+		if err != nil {
+			h.Logger.Warn("failed to update oauth client secret expiry", zap.Error(err), logging.String("id", id))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusOK, response.OAuthClientResponse{
+			ID:                    client.ID,
+			ClientID:              client.ClientID,
+			Name:                  client.Name,
+			Description:           client.Description,
+			Scopes:                client.Scopes,
+			Active:                client.Active,
+			RequireSignedRequests: client.RequireSignedRequests,
+			TokenProfile:          client.TokenProfile,
+			GrantTypes:            client.AllowedGrantTypes(),
+			RedirectURIs:          client.RedirectURIs,
+			Tags:                  client.Tags,
+			SecretExpiresAt:       client.SecretExpiresAt,
+			CreatedAt:             client.CreatedAt,
+			UpdatedAt:             client.UpdatedAt,
+			Version:               client.Version,
+		})
+	}
+}
+
+// ListExpiringClientSecrets lists the OAuth2 clients whose secret expires soon (ADMIN only)
+// @Summary List expiring OAuth2 client secrets
+// @Description Lists the active clients whose secret expires within the given number of days, including the ones already expired,
+// @Description the soonest first, so they can be rotated in time.
+// @Tags Admin - OAuth Clients
+// @Produce json
+// @Security BearerAuth
+// @Param days query int false "Days ahead, 30 by default and at most 365"
+// @Success 200 {array} response.ExpiringClientSecretResponse "Clients with an expiring secret"
+// @Failure 400 {object} response.ErrorResponse "Invalid number of days"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden - Admin role required"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /admin/oauth-clients/expiring-secrets [get]
+func ListExpiringClientSecrets(h *shared.AdminOAuthClientsHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		days := defaultExpiringSecretsDays
+		if value := r.URL.Query().Get("days"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed < 1 || parsed > maxExpiringSecretsDays {
+				httperrors.RespondWithError(w, httperrors.ErrBadRequest)
+				return
+			}
+			days = parsed
+		}
+
+		clients, err := h.OAuth2Service.ListExpiringSecrets(r.Context(), time.Duration(days)*24*time.Hour)
+		if err != nil {
+			h.Logger.Error("failed to list expiring oauth client secrets", zap.Error(err))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		now := time.Now()
+		resp := make([]response.ExpiringClientSecretResponse, 0, len(clients))
+		for _, client := range clients {
+			resp = append(resp, response.ExpiringClientSecretResponse{
+				ID:              client.ID,
+				ClientID:        client.ClientID,
+				Name:            client.Name,
+				Tags:            client.Tags,
+				SecretExpiresAt: *client.SecretExpiresAt,
+				Expired:         client.SecretExpired(now),
+			})
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusOK, resp)
+	}
+}
@@ -0,0 +1,37 @@
+package admin
+
+import (
+	nethttp "net/http"
+	"os"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+)
+
+// GetConfig returns the effective configuration of the replica serving the request (ADMIN only)
+// @Summary Get Effective Configuration
+// @Description Retrieves the configuration resolved at startup by the replica serving the request, keyed by configuration field,
+// @Description with the state of the optional features. Secrets are redacted and the passwords of URLs are masked.
+// @Tags Admin - Configuration
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.EffectiveConfigResponse "Effective configuration"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden - Admin role required"
+// @Router /admin/config [get]
+func GetConfig(h *shared.AdminConfigHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		replica, err := os.Hostname()
+		if err != nil {
+			h.Logger.Warn("failed to get hostname", zap.Error(err))
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusOK, response.EffectiveConfigResponse{
+			Replica: replica,
+			Version: h.Version,
+			Config:  h.Config,
+		})
+	}
+}
@@ -15,13 +15,16 @@ import (
 // CreateOAuthClient creates a new OAuth2 client (ADMIN only)
 // @Summary Create OAuth2 Client
 // @Description Creates a new OAuth2 client for service-to-service authentication. Only administrators can create clients.
+// @Description grant_types lists the grant types the client may use (client_credentials, authorization_code, device_code, token_exchange), client_credentials and device_code when omitted.
+// @Description redirect_uris must be absolute https URIs, http URIs on a loopback IP address (any port is then accepted) or private-use scheme URIs of native apps.
+// @Description secret_expires_at is when the secret stops being accepted, it must be in the future; the secret never expires when omitted.
 // @Tags Admin - OAuth Clients
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param request body request.CreateOAuthClientRequest true "OAuth Client data"
 // @Success 201 {object} response.OAuthClientResponse "OAuth client created successfully"
-// @Failure 400 {object} response.ErrorResponse "Invalid request"
+// @Failure 400 {object} response.ErrorResponse "Invalid request, unknown grant type, invalid redirect URI or secret expiry in the past"
 // @Failure 401 {object} response.ErrorResponse "Unauthorized"
 // @Failure 403 {object} response.ErrorResponse "Forbidden - Admin role required"
 // @Failure 409 {object} response.ErrorResponse "Client already exists"
@@ -50,6 +53,10 @@ func CreateOAuthClient(h *shared.AdminOAuthClientsHandler) nethttp.HandlerFunc {
 			req.Name,
 			req.Description,
 			req.Scopes,
+			req.GrantTypes,
+			req.RedirectURIs,
+			req.Tags,
+			req.SecretExpiresAt,
 		)
 		if err != nil {
 			h.Logger.Error("failed to create oauth client", zap.Error(err))
@@ -59,14 +66,21 @@ func CreateOAuthClient(h *shared.AdminOAuthClientsHandler) nethttp.HandlerFunc {
 
 		// Convert to DTO
 		resp := response.OAuthClientResponse{
-			ID:          client.ID,
-			ClientID:    client.ClientID,
-			Name:        client.Name,
-			Description: client.Description,
-			Scopes:      client.Scopes,
-			Active:      client.Active,
-			CreatedAt:   client.CreatedAt,
-			UpdatedAt:   client.UpdatedAt,
+			ID:                    client.ID,
+			ClientID:              client.ClientID,
+			Name:                  client.Name,
+			Description:           client.Description,
+			Scopes:                client.Scopes,
+			Active:                client.Active,
+			RequireSignedRequests: client.RequireSignedRequests,
+			TokenProfile:          client.TokenProfile,
+			GrantTypes:            client.AllowedGrantTypes(),
+			RedirectURIs:          client.RedirectURIs,
+			Tags:                  client.Tags,
+			SecretExpiresAt:       client.SecretExpiresAt,
+			CreatedAt:             client.CreatedAt,
+			UpdatedAt:             client.UpdatedAt,
+			Version:               client.Version,
 		}
 
 		shared.RespondWithJSON(w, nethttp.StatusCreated, resp)
@@ -0,0 +1,71 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	nethttp "net/http"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/request"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+)
+
+// CreateUser creates a user with a temporary password (ADMIN only)
+// @Summary Create User
+// @Description Creates the account of a person who can't register by themselves, e.g. a citizen onboarded by the helpdesk.
+// @Description The response carries a generated temporary password that is only returned once. The user can't sign in until
+// @Description they replace it at /login/password-change. The creation is recorded in the audit log.
+// @Tags Admin - Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.CreateUserRequest true "User"
+// @Success 201 {object} response.CreateUserResponse "User created"
+// @Failure 400 {object} response.ErrorResponse "Invalid request body or missing fields"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden - Admin role required"
+// @Failure 409 {object} response.ErrorResponse "User already exists"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Failure 503 {object} response.ErrorResponse "Citizen registry busy"
+// @Router /admin/users [post]
+func CreateUser(h *shared.UserProvisioningHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		claims, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			httperrors.RespondWithError(w, httperrors.ErrUnauthorized)
+			return
+		}
+
+		var req request.CreateUserRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.Logger.Debug("invalid request body", zap.Error(err))
+			httperrors.RespondWithError(w, httperrors.ErrInvalidRequestBody)
+			return
+		}
+
+		email := strings.TrimSpace(req.Email)
+		name := strings.TrimSpace(req.Name)
+		if email == "" || name == "" || req.IDCitizen <= 0 {
+			httperrors.RespondWithError(w, httperrors.ErrRequiredField)
+			return
+		}
+
+		user, password, err := h.UserProvisioningService.CreateUser(r.Context(), email, name, req.IDCitizen, fmt.Sprintf("admin:%d", claims.IDCitizen))
+		if err != nil {
+			h.Logger.Warn("failed to create user", zap.Error(err), zap.Int("id_citizen", req.IDCitizen))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusCreated, response.CreateUserResponse{
+			User:               toAdminUserResponse(user),
+			TemporaryPassword:  password,
+			MustChangePassword: user.MustChangePassword,
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	nethttp "net/http"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/request"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// DecodeToken decodes a token and reports which validation steps would reject it (ADMIN only)
+// @Summary Decode Token
+// @Description Decodes a JWT without validating it and runs each validation step of the access tokens: signature, expiration, type,
+// @Description blacklist and version. Every step runs even after one fails, so a support investigation gets the whole picture at once.
+// @Description The token is never logged. Disabled in production unless SERVER_ADMIN_DEBUG_ENABLED is set.
+// @Tags Admin - Debug
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.DecodeTokenRequest true "Token to decode"
+// @Success 200 {object} response.TokenDebugResponse "Decoded token and the outcome of each validation step"
+// @Failure 400 {object} response.ErrorResponse "Invalid request body or the token is not a JWT"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden - Admin role required"
+// @Router /admin/debug/decode-token [post]
+func DecodeToken(h *shared.TokenDebugHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		claims, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			httperrors.RespondWithError(w, httperrors.ErrUnauthorized)
+			return
+		}
+
+		var req request.DecodeTokenRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.Logger.Debug("invalid request body", zap.Error(err))
+			httperrors.RespondWithError(w, httperrors.ErrInvalidRequestBody)
+			return
+		}
+
+		token := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(req.Token), "Bearer "))
+		if token == "" {
+			httperrors.RespondWithError(w, httperrors.ErrRequiredField)
+			return
+		}
+
+		report, err := h.TokenDebugService.DecodeToken(r.Context(), token)
+		if err != nil {
+			if errors.Is(err, domainerrors.ErrInvalidToken) {
+				httperrors.RespondWithError(w, httperrors.ErrMalformedToken)
+				return
+			}
+			h.Logger.Error("failed to decode token", zap.Error(err))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		h.Logger.Info("token decoded for debugging", zap.Int("admin_id_citizen", claims.IDCitizen), zap.Bool("valid", report.Valid()))
+		shared.RespondWithJSON(w, nethttp.StatusOK, toTokenDebugResponse(report))
+	}
+}
+
+func toTokenDebugResponse(report *domain.TokenDebugReport) response.TokenDebugResponse {
+	checks := make([]response.TokenCheckResponse, 0, len(report.Checks))
+	for _, check := range report.Checks {
+		checks = append(checks, response.TokenCheckResponse{
+			Name:   check.Name,
+			Status: string(check.Status),
+			Detail: check.Detail,
+		})
+	}
+	return response.TokenDebugResponse{
+		Valid:  report.Valid(),
+		Header: report.Header,
+		Claims: report.Claims,
+		Checks: checks,
+	}
+}
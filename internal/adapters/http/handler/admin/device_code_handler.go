@@ -0,0 +1,86 @@
+package admin
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/request"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+)
+
+// DeviceCode starts the OAuth2 device authorization flow (RFC 8628)
+// @Summary OAuth2 Device Authorization
+// @Description Issues a device_code and a short user_code for input-constrained clients (CLIs, IoT devices).
+// @Description The user enters the user_code at the verification URI while the device polls /token
+// @Description with grant_type `urn:ietf:params:oauth:grant-type:device_code`.
+// @Tags OAuth2
+// @Accept json
+// @Accept application/x-www-form-urlencoded
+// @Produce json
+// @Param request body request.DeviceCodeRequest true "Device authorization request"
+// @Success 200 {object} response.DeviceCodeResponse "Device code issued"
+// @Failure 400 {object} response.ErrorResponse "Invalid request or scope"
+// @Failure 401 {object} response.ErrorResponse "Invalid client"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /oauth/device/code [post]
+func DeviceCode(h *shared.OAuth2Handler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		var req request.DeviceCodeRequest
+
+		if strings.Contains(r.Header.Get("Content-Type"), "application/json") {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				h.Logger.Debug("invalid request body (JSON)", zap.Error(err))
+				httperrors.RespondWithError(w, httperrors.ErrInvalidRequestBody)
+				return
+			}
+		} else {
+			if err := r.ParseForm(); err != nil {
+				h.Logger.Debug("failed to parse form", zap.Error(err))
+				httperrors.RespondWithError(w, httperrors.ErrInvalidRequestBody)
+				return
+			}
+
+			req.ClientID = r.FormValue("client_id")
+			req.ClientSecret = r.FormValue("client_secret")
+			req.Scope = r.FormValue("scope")
+		}
+
+		// Clients may authenticate with HTTP Basic instead of the body (RFC 6749 section 2.3.1)
+		if err := authenticateClient(r, &req.ClientID, &req.ClientSecret); err != nil {
+			httperrors.RespondWithOAuthError(w, err)
+			return
+		}
+
+		if req.ClientID == "" || req.ClientSecret == "" {
+			httperrors.RespondWithError(w, httperrors.ErrRequiredField)
+			return
+		}
+
+		auth, err := h.DeviceAuthorizationService.RequestDeviceCode(r.Context(), req.ClientID, req.ClientSecret, strings.Fields(req.Scope))
+		if err != nil {
+			h.Logger.Warn("device authorization request failed", zap.Error(err), logging.String("client_id", req.ClientID))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		verificationURI := h.DeviceAuthorizationService.VerificationURI()
+		resp := response.DeviceCodeResponse{
+			DeviceCode:              auth.DeviceCode,
+			UserCode:                auth.UserCode,
+			VerificationURI:         verificationURI,
+			VerificationURIComplete: verificationURI + "?user_code=" + url.QueryEscape(auth.UserCode),
+			ExpiresIn:               int64(time.Until(auth.ExpiresAt).Seconds()),
+			Interval:                int64(auth.Interval.Seconds()),
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusOK, resp)
+	}
+}
@@ -0,0 +1,115 @@
+package admin
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/request"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+)
+
+// GetDeviceVerification describes a pending device authorization to the logged-in user
+// @Summary Get device authorization
+// @Description Returns the client and scopes of the pending device authorization identified by the user code, and whether the user still has to consent to them
+// @Tags OAuth2
+// @Produce json
+// @Security BearerAuth
+// @Param user_code query string true "User code displayed on the device"
+// @Success 200 {object} response.DeviceVerificationResponse "Pending device authorization"
+// @Failure 400 {object} response.ErrorResponse "Invalid or expired user code"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized or invalid token"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /oauth/device/verify [get]
+func GetDeviceVerification(h *shared.OAuth2Handler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		claims, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			httperrors.RespondWithError(w, httperrors.ErrUnauthorized)
+			return
+		}
+
+		userCode := r.URL.Query().Get("user_code")
+		if userCode == "" {
+			httperrors.RespondWithError(w, httperrors.ErrRequiredField)
+			return
+		}
+
+		auth, err := h.DeviceAuthorizationService.GetByUserCode(r.Context(), userCode)
+		if err != nil {
+			h.Logger.Debug("device authorization lookup failed", zap.Error(err))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		consentRequired, err := h.DeviceAuthorizationService.RequiresConsent(r.Context(), auth, claims.IDCitizen)
+		if err != nil {
+			h.Logger.Error("failed to check consent", zap.Error(err), zap.Int("id_citizen", claims.IDCitizen))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		resp := response.DeviceVerificationResponse{
+			UserCode:        auth.UserCode,
+			ClientID:        auth.ClientID,
+			Scopes:          auth.Scopes,
+			ExpiresIn:       int64(time.Until(auth.ExpiresAt).Seconds()),
+			ConsentRequired: consentRequired,
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusOK, resp)
+	}
+}
+
+// VerifyDevice approves or denies a device authorization on behalf of the logged-in user
+// @Summary Verify device authorization
+// @Description Approves or denies the device authorization identified by the user code. Once approved, the device receives tokens for the authenticated user on its next poll.
+// @Tags OAuth2
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.DeviceVerificationRequest true "User code and decision"
+// @Success 200 {object} response.MessageResponse "Decision recorded"
+// @Failure 400 {object} response.ErrorResponse "Invalid request or expired user code"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized or invalid token"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /oauth/device/verify [post]
+func VerifyDevice(h *shared.OAuth2Handler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		claims, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			httperrors.RespondWithError(w, httperrors.ErrUnauthorized)
+			return
+		}
+
+		var req request.DeviceVerificationRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.Logger.Debug("invalid request body", zap.Error(err))
+			httperrors.RespondWithError(w, httperrors.ErrInvalidRequestBody)
+			return
+		}
+
+		if req.UserCode == "" {
+			httperrors.RespondWithError(w, httperrors.ErrRequiredField)
+			return
+		}
+
+		if err := h.DeviceAuthorizationService.Verify(r.Context(), req.UserCode, claims.IDCitizen, req.Approve); err != nil {
+			h.Logger.Warn("device verification failed", zap.Error(err), zap.Int("id_citizen", claims.IDCitizen))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		message := "Device authorization denied"
+		if req.Approve {
+			message = "Device authorization approved"
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusOK, response.MessageResponse{Message: message})
+	}
+}
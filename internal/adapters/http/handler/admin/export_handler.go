@@ -0,0 +1,315 @@
+package admin
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	nethttp "net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// exportFlushRecords is the number of records written between two flushes of an export
+const exportFlushRecords = 500
+
+// Columns of the CSV exports
+var (
+	userExportColumns        = []string{"id", "id_citizen", "email", "name", "role", "status", "created_at", "updated_at"}
+	auditRecordExportColumns = []string{"id", "action", "actor", "target_id", "details", "created_at"}
+)
+
+// ExportUsers streams the users as CSV or NDJSON (ADMIN only)
+// @Summary Export Users
+// @Description Streams the users matching the filters, oldest first, with chunked transfer encoding. Passwords are never exported.
+// @Description A failure after the first record aborts the connection, so a truncated export can't be mistaken for a complete one.
+// @Tags Admin - Users
+// @Produce text/csv,application/x-ndjson
+// @Security BearerAuth
+// @Param format query string false "Export format" Enums(csv, ndjson) default(csv)
+// @Param role query string false "Role of the users" Enums(USER, ADMIN)
+// @Param status query string false "Status of the users" Enums(ACTIVE, SUSPENDED, ANONYMIZED)
+// @Param created_after query string false "Users created at or after this time (RFC 3339)"
+// @Param created_before query string false "Users created before this time (RFC 3339)"
+// @Success 200 {array} response.UserExportRecord "Users, a CSV row or JSON line per user"
+// @Failure 400 {object} response.ErrorResponse "Invalid export filter"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden - Admin role required"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /admin/users/export [get]
+func ExportUsers(h *shared.AdminExportHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		claims, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			httperrors.RespondWithError(w, httperrors.ErrUnauthorized)
+			return
+		}
+
+		query := r.URL.Query()
+		format, formatErr := exportFormat(query)
+		createdAfter, afterErr := exportTime(query, "created_after")
+		createdBefore, beforeErr := exportTime(query, "created_before")
+		if err := errors.Join(formatErr, afterErr, beforeErr); err != nil {
+			h.Logger.Warn("invalid users export filter", zap.Error(err))
+			httperrors.RespondWithError(w, httperrors.ErrInvalidExportFilter)
+			return
+		}
+		filter := domain.UserExportFilter{
+			Role:          domain.Role(query.Get("role")),
+			Status:        domain.UserStatus(query.Get("status")),
+			CreatedAfter:  createdAfter,
+			CreatedBefore: createdBefore,
+		}
+
+		stream := newExportStream(w, format, "users", userExportColumns)
+		err := h.ExportService.ExportUsers(r.Context(), filter, fmt.Sprintf("admin:%d", claims.IDCitizen), func(user *domain.User) error {
+			record := response.UserExportRecord{
+				ID:        user.ID,
+				IDCitizen: user.IDCitizen,
+				Email:     user.Email,
+				Name:      user.Name,
+				Role:      user.Role,
+				Status:    user.Status,
+				CreatedAt: user.CreatedAt,
+				UpdatedAt: user.UpdatedAt,
+			}
+			return stream.write(record, []string{
+				record.ID,
+				strconv.Itoa(record.IDCitizen),
+				record.Email,
+				record.Name,
+				record.Role.String(),
+				record.Status.String(),
+				record.CreatedAt.UTC().Format(time.RFC3339),
+				record.UpdatedAt.UTC().Format(time.RFC3339),
+			})
+		})
+		stream.finish(h, err)
+	}
+}
+
+// ExportAuditLog streams the audit log as CSV or NDJSON (ADMIN only)
+// @Summary Export Audit Log
+// @Description Streams the audit records matching the filters, oldest first, with chunked transfer encoding.
+// @Description In CSV the details are a JSON object. A failure after the first record aborts the connection,
+// @Description so a truncated export can't be mistaken for a complete one.
+// @Tags Admin - Audit Log
+// @Produce text/csv,application/x-ndjson
+// @Security BearerAuth
+// @Param format query string false "Export format" Enums(csv, ndjson) default(csv)
+// @Param action query string false "Action of the records, e.g. user.anonymized"
+// @Param actor query string false "Actor of the records, e.g. admin:12345"
+// @Param target_id query string false "ID of the affected resource"
+// @Param after query string false "Records created at or after this time (RFC 3339)"
+// @Param before query string false "Records created before this time (RFC 3339)"
+// @Success 200 {array} response.AuditRecordExportRecord "Audit records, a CSV row or JSON line per record"
+// @Failure 400 {object} response.ErrorResponse "Invalid export filter"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden - Admin role required"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /admin/audit-logs/export [get]
+func ExportAuditLog(h *shared.AdminExportHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		claims, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			httperrors.RespondWithError(w, httperrors.ErrUnauthorized)
+			return
+		}
+
+		query := r.URL.Query()
+		format, formatErr := exportFormat(query)
+		after, afterErr := exportTime(query, "after")
+		before, beforeErr := exportTime(query, "before")
+		if err := errors.Join(formatErr, afterErr, beforeErr); err != nil {
+			h.Logger.Warn("invalid audit log export filter", zap.Error(err))
+			httperrors.RespondWithError(w, httperrors.ErrInvalidExportFilter)
+			return
+		}
+		filter := domain.AuditLogFilter{
+			Action:   domain.AuditAction(query.Get("action")),
+			Actor:    query.Get("actor"),
+			TargetID: query.Get("target_id"),
+			After:    after,
+			Before:   before,
+		}
+
+		stream := newExportStream(w, format, "audit-log", auditRecordExportColumns)
+		err := h.ExportService.ExportAuditLog(r.Context(), filter, fmt.Sprintf("admin:%d", claims.IDCitizen), func(auditRecord *domain.AuditRecord) error {
+			record := response.AuditRecordExportRecord{
+				ID:        auditRecord.ID,
+				Action:    auditRecord.Action,
+				Actor:     auditRecord.Actor,
+				TargetID:  auditRecord.TargetID,
+				Details:   auditRecord.Details,
+				CreatedAt: auditRecord.CreatedAt,
+			}
+			details, err := json.Marshal(record.Details)
+			if err != nil {
+				return fmt.Errorf("failed to marshal audit details: %w", err)
+			}
+			return stream.write(record, []string{
+				record.ID,
+				record.Action.String(),
+				record.Actor,
+				record.TargetID,
+				string(details),
+				record.CreatedAt.UTC().Format(time.RFC3339),
+			})
+		})
+		stream.finish(h, err)
+	}
+}
+
+// exportFormat returns the format of the format query parameter, CSV by default
+func exportFormat(query url.Values) (domain.ExportFormat, error) {
+	if value := query.Get("format"); value != "" {
+		return domain.ParseExportFormat(value)
+	}
+	return domain.ExportFormatCSV, nil
+}
+
+// exportTime parses an RFC 3339 time query parameter, the zero time when it is absent
+func exportTime(query url.Values, key string) (time.Time, error) {
+	value := query.Get(key)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return t, nil
+}
+
+// exportStream writes the records of an export as CSV or NDJSON, flushing them in chunks.
+// The response starts with the first record, so a failure before it still gets an error response.
+type exportStream struct {
+	w          nethttp.ResponseWriter
+	controller *nethttp.ResponseController
+	format     domain.ExportFormat
+	dataset    string
+	columns    []string
+	csv        *csv.Writer
+	json       *json.Encoder
+	started    bool
+	pending    int
+}
+
+func newExportStream(w nethttp.ResponseWriter, format domain.ExportFormat, dataset string, columns []string) *exportStream {
+	return &exportStream{
+		w:          w,
+		controller: nethttp.NewResponseController(w),
+		format:     format,
+		dataset:    dataset,
+		columns:    columns,
+	}
+}
+
+// start sends the headers and, in CSV, the header row
+func (s *exportStream) start() error {
+	s.started = true
+
+	contentType, extension := "text/csv; charset=utf-8", "csv"
+	if s.format == domain.ExportFormatNDJSON {
+		contentType, extension = "application/x-ndjson", "ndjson"
+	}
+	s.w.Header().Set("Content-Type", contentType)
+	s.w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s.%s"`, s.dataset, time.Now().UTC().Format("20060102T150405Z"), extension))
+	s.w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	// Large exports outlive the write timeout of the server
+	if err := s.controller.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, nethttp.ErrNotSupported) {
+		return err
+	}
+	s.w.WriteHeader(nethttp.StatusOK)
+
+	if s.format == domain.ExportFormatNDJSON {
+		s.json = json.NewEncoder(s.w)
+		return nil
+	}
+	s.csv = csv.NewWriter(s.w)
+	return s.csv.Write(s.columns)
+}
+
+// write writes a record, as a JSON line in NDJSON or as the row in CSV
+func (s *exportStream) write(record interface{}, row []string) error {
+	if !s.started {
+		if err := s.start(); err != nil {
+			return err
+		}
+	}
+
+	if s.json != nil {
+		if err := s.json.Encode(record); err != nil {
+			return err
+		}
+	} else {
+		for i, value := range row {
+			row[i] = csvSafe(value)
+		}
+		if err := s.csv.Write(row); err != nil {
+			return err
+		}
+	}
+
+	s.pending++
+	if s.pending < exportFlushRecords {
+		return nil
+	}
+	return s.flush()
+}
+
+// flush sends the buffered records to the client as a chunk
+func (s *exportStream) flush() error {
+	s.pending = 0
+	if s.csv != nil {
+		s.csv.Flush()
+		if err := s.csv.Error(); err != nil {
+			return err
+		}
+	}
+	if err := s.controller.Flush(); err != nil && !errors.Is(err, nethttp.ErrNotSupported) {
+		return err
+	}
+	return nil
+}
+
+// finish completes the export. Once the first record is sent the status can't change anymore, so a failure
+// aborts the connection and the client sees an incomplete chunked response instead of a complete export.
+func (s *exportStream) finish(h *shared.AdminExportHandler, err error) {
+	if err == nil && !s.started {
+		err = s.start()
+	}
+	if err == nil {
+		err = s.flush()
+	}
+	if err == nil {
+		return
+	}
+
+	if !s.started {
+		h.Logger.Warn("failed to export dataset", zap.Error(err), zap.String("dataset", s.dataset))
+		httperrors.RespondWithDomainError(s.w, err)
+		return
+	}
+	h.Logger.Error("export interrupted", zap.Error(err), zap.String("dataset", s.dataset))
+	panic(nethttp.ErrAbortHandler)
+}
+
+// csvSafe neutralizes the values spreadsheets would evaluate as formulas (CSV injection)
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
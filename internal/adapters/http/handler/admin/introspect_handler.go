@@ -0,0 +1,99 @@
+package admin
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/request"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+)
+
+// Introspect handles the OAuth2 token introspection endpoint (RFC 7662)
+// @Summary OAuth2 Token Introspection
+// @Description Reports whether a user or client access token is active and describes it. Intended for the API gateway and resource servers.
+// @Description The caller authenticates with its client credentials, in the body or with HTTP Basic authentication.
+// @Description Errors follow RFC 6749 section 5.2, `{"error":"invalid_client","error_description":"..."}`.
+// @Description Active tokens include the current rate-limit status of their principal, matching the X-RateLimit-* headers.
+// @Tags OAuth2
+// @Accept json
+// @Accept application/x-www-form-urlencoded
+// @Produce json
+// @Param request body request.IntrospectionRequest true "Token to introspect"
+// @Success 200 {object} response.IntrospectionResponse "Token state"
+// @Failure 400 {object} response.OAuthErrorResponse "invalid_request: missing parameters or client credentials in both the header and the body"
+// @Failure 401 {object} response.OAuthErrorResponse "invalid_client: invalid client credentials"
+// @Failure 429 {object} response.OAuthErrorResponse "temporarily_unavailable: client locked out after repeated authentication failures, see the Retry-After header"
+// @Failure 500 {object} response.OAuthErrorResponse "server_error"
+// @Router /oauth/introspect [post]
+func Introspect(h *shared.IntrospectionHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		var req request.IntrospectionRequest
+
+		if strings.Contains(r.Header.Get("Content-Type"), "application/json") {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				h.Logger.Debug("invalid request body (JSON)", zap.Error(err))
+				httperrors.RespondWithOAuthError(w, httperrors.ErrOAuthMalformedRequest)
+				return
+			}
+		} else {
+			if err := r.ParseForm(); err != nil {
+				h.Logger.Debug("failed to parse form", zap.Error(err))
+				httperrors.RespondWithOAuthError(w, httperrors.ErrOAuthMalformedRequest)
+				return
+			}
+
+			req.Token = r.FormValue("token")
+			req.TokenTypeHint = r.FormValue("token_type_hint")
+			req.ClientID = r.FormValue("client_id")
+			req.ClientSecret = r.FormValue("client_secret")
+		}
+
+		if err := authenticateClient(r, &req.ClientID, &req.ClientSecret); err != nil {
+			httperrors.RespondWithOAuthError(w, err)
+			return
+		}
+
+		if req.ClientID == "" || req.Token == "" {
+			httperrors.RespondWithOAuthError(w, httperrors.ErrOAuthInvalidRequest)
+			return
+		}
+
+		introspection, err := h.IntrospectionService.Introspect(r.Context(), req.ClientID, req.ClientSecret, req.Token)
+		if err != nil {
+			h.Logger.Warn("token introspection failed", zap.Error(err), logging.String("client_id", req.ClientID))
+			httperrors.RespondWithOAuthDomainError(w, err)
+			return
+		}
+
+		resp := response.IntrospectionResponse{
+			Active:    introspection.Active,
+			Sub:       introspection.Subject,
+			ClientID:  introspection.ClientID,
+			Scope:     strings.Join(introspection.Scopes, " "),
+			Email:     introspection.Email,
+			Role:      string(introspection.Role),
+			TokenType: introspection.TokenType,
+			Iat:       introspection.IssuedAt,
+			Exp:       introspection.ExpiresAt,
+		}
+		if status := introspection.RateLimit; status != nil {
+			resp.RateLimit = &response.RateLimitResponse{
+				Limit:     status.Limit,
+				Remaining: status.Remaining(),
+				Reset:     status.ResetSeconds(time.Now()),
+			}
+		}
+		if introspection.CertificateThumbprint != "" {
+			resp.Cnf = &response.ConfirmationResponse{X5TS256: introspection.CertificateThumbprint}
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusOK, resp)
+	}
+}
@@ -8,14 +8,16 @@ import (
 	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
 	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
 	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
 )
 
-// ListOAuthClients retrieves all OAuth2 clients (ADMIN only)
+// ListOAuthClients retrieves all OAuth2 clients, optionally filtered by tags (ADMIN only)
 // @Summary List OAuth2 Clients
-// @Description Retrieves all OAuth2 clients. Only administrators can list clients.
+// @Description Retrieves all OAuth2 clients with their usage: the last time each got a token, the tokens issued and its recent errors. Usage is updated periodically and omitted when it can't be retrieved. Clients with recent authentication failures also have their lockout: the failures within the lockout window and, once locked out, the end of the cooldown. Repeat the tag parameter to list only the clients having all the given tags, e.g. ?tag=team:payments&tag=env:staging. Only administrators can list clients.
 // @Tags Admin - OAuth Clients
 // @Accept json
 // @Produce json
+// @Param tag query []string false "Tags the clients must have, e.g. team:payments" collectionFormat(multi)
 // @Security BearerAuth
 // @Success 200 {array} response.OAuthClientResponse "List of OAuth clients"
 // @Failure 401 {object} response.ErrorResponse "Unauthorized"
@@ -24,28 +26,81 @@ import (
 // @Router /admin/oauth-clients [get]
 func ListOAuthClients(h *shared.AdminOAuthClientsHandler) nethttp.HandlerFunc {
 	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
-		clients, err := h.OAuth2Service.ListClients(r.Context())
+		clients, err := h.OAuth2Service.ListClients(r.Context(), r.URL.Query()["tag"])
 		if err != nil {
 			h.Logger.Error("failed to list oauth clients", zap.Error(err))
 			httperrors.RespondWithError(w, httperrors.ErrInternalServer)
 			return
 		}
 
+		// Usage statistics are secondary, the clients are listed without them if they can't be retrieved
+		usage, err := h.OAuth2Service.ListClientUsage(r.Context())
+		if err != nil {
+			h.Logger.Warn("failed to list oauth client usage", zap.Error(err))
+		}
+
+		clientIDs := make([]string, 0, len(clients))
+		for _, client := range clients {
+			clientIDs = append(clientIDs, client.ClientID)
+		}
+		lockouts, err := h.OAuth2Service.ListClientLockouts(r.Context(), clientIDs)
+		if err != nil {
+			h.Logger.Warn("failed to list oauth client lockouts", zap.Error(err))
+		}
+
 		// Convert to DTOs
 		var clientResponses []response.OAuthClientResponse
 		for _, client := range clients {
+			var clientUsage *response.OAuthClientUsageResponse
+			if usage != nil {
+				clientUsage = toOAuthClientUsageResponse(usage[client.ClientID])
+			}
+
 			clientResponses = append(clientResponses, response.OAuthClientResponse{
-				ID:          client.ID,
-				ClientID:    client.ClientID,
-				Name:        client.Name,
-				Description: client.Description,
-				Scopes:      client.Scopes,
-				Active:      client.Active,
-				CreatedAt:   client.CreatedAt,
-				UpdatedAt:   client.UpdatedAt,
+				ID:                    client.ID,
+				ClientID:              client.ClientID,
+				Name:                  client.Name,
+				Description:           client.Description,
+				Scopes:                client.Scopes,
+				Active:                client.Active,
+				RequireSignedRequests: client.RequireSignedRequests,
+				TokenProfile:          client.TokenProfile,
+				GrantTypes:            client.AllowedGrantTypes(),
+				RedirectURIs:          client.RedirectURIs,
+				Tags:                  client.Tags,
+				SecretExpiresAt:       client.SecretExpiresAt,
+				CreatedAt:             client.CreatedAt,
+				UpdatedAt:             client.UpdatedAt,
+				Version:               client.Version,
+				Usage:                 clientUsage,
+				Lockout:               toOAuthClientLockoutResponse(lockouts[client.ClientID]),
 			})
 		}
 
 		shared.RespondWithJSON(w, nethttp.StatusOK, clientResponses)
 	}
 }
+
+// toOAuthClientUsageResponse converts the usage of a client, a client never used has none
+func toOAuthClientUsageResponse(usage *domain.ClientUsage) *response.OAuthClientUsageResponse {
+	if usage == nil {
+		return &response.OAuthClientUsageResponse{}
+	}
+	return &response.OAuthClientUsageResponse{
+		LastUsedAt:        usage.LastUsedAt,
+		TokensIssued:      usage.TokensIssued,
+		RecentErrors:      usage.RecentErrors,
+		RecentErrorsSince: usage.RecentErrorsSince,
+	}
+}
+
+// toOAuthClientLockoutResponse converts the lockout of a client, clients without recent failures have none
+func toOAuthClientLockoutResponse(lockout *domain.ClientLockout) *response.OAuthClientLockoutResponse {
+	if lockout == nil {
+		return nil
+	}
+	return &response.OAuthClientLockoutResponse{
+		RecentAuthFailures: lockout.RecentFailures,
+		LockedUntil:        lockout.LockedUntil,
+	}
+}
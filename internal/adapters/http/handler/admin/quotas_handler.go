@@ -0,0 +1,189 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	nethttp "net/http"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/request"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// ListQuotas lists the issuance quota overrides of clients and users (ADMIN only)
+// @Summary List Issuance Quotas
+// @Description Retrieves every quota override. Clients and users without an override get the default quotas.
+// @Tags Admin - Quotas
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} response.QuotaResponse "List of quota overrides"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden - Admin role required"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /admin/quotas [get]
+func ListQuotas(h *shared.QuotasHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		quotas, err := h.QuotaService.ListQuotas(r.Context())
+		if err != nil {
+			h.Logger.Error("failed to list quotas", zap.Error(err))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		quotaResponses := make([]response.QuotaResponse, 0, len(quotas))
+		for _, quota := range quotas {
+			quotaResponses = append(quotaResponses, toQuotaResponse(quota))
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusOK, quotaResponses)
+	}
+}
+
+// GetQuota retrieves the effective issuance quota of a client or user and its usage (ADMIN only)
+// @Summary Get Issuance Quota
+// @Description Retrieves the quota applied to a client (by client_id) or user (by ID), along with the tokens issued in the current hour
+// @Description and, for users, the active sessions.
+// @Tags Admin - Quotas
+// @Produce json
+// @Security BearerAuth
+// @Param subject_type path string true "Subject type" Enums(client, user)
+// @Param subject_id path string true "Client ID or user ID"
+// @Success 200 {object} response.QuotaUsageResponse "Quota and usage"
+// @Failure 400 {object} response.ErrorResponse "Unknown subject type"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden - Admin role required"
+// @Failure 404 {object} response.ErrorResponse "Client or user not found"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /admin/quotas/{subject_type}/{subject_id} [get]
+func GetQuota(h *shared.QuotasHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		vars := mux.Vars(r)
+		subjectType, subjectID := vars["subject_type"], vars["subject_id"]
+
+		usage, err := h.QuotaService.GetQuota(r.Context(), subjectType, subjectID)
+		if err != nil {
+			h.Logger.Warn("failed to get quota", zap.Error(err), zap.String("subject_type", subjectType), zap.String("subject_id", subjectID))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		resp := response.QuotaUsageResponse{
+			Quota:          toQuotaResponse(&usage.Quota),
+			Default:        usage.Default,
+			TokensIssued:   usage.TokensIssued,
+			TokensResetAt:  usage.ResetAt,
+			ActiveSessions: usage.ActiveSessions,
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusOK, resp)
+	}
+}
+
+// UpdateQuota sets the issuance quota of a client or user (ADMIN only)
+// @Summary Update Issuance Quota
+// @Description Sets the quota override of a client (by client_id) or user (by ID). Zero means unlimited.
+// @Description Active sessions can only be limited for users.
+// @Tags Admin - Quotas
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param subject_type path string true "Subject type" Enums(client, user)
+// @Param subject_id path string true "Client ID or user ID"
+// @Param request body request.UpdateQuotaRequest true "Quota limits"
+// @Success 200 {object} response.QuotaResponse "Quota updated successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid request or quota"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden - Admin role required"
+// @Failure 404 {object} response.ErrorResponse "Client or user not found"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /admin/quotas/{subject_type}/{subject_id} [put]
+func UpdateQuota(h *shared.QuotasHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		claims, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			httperrors.RespondWithError(w, httperrors.ErrUnauthorized)
+			return
+		}
+
+		var req request.UpdateQuotaRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.Logger.Debug("invalid request body", zap.Error(err))
+			httperrors.RespondWithError(w, httperrors.ErrInvalidRequestBody)
+			return
+		}
+
+		vars := mux.Vars(r)
+		quota := &domain.IssuanceQuota{
+			SubjectType:       vars["subject_type"],
+			SubjectID:         vars["subject_id"],
+			MaxTokensPerHour:  req.MaxTokensPerHour,
+			MaxActiveSessions: req.MaxActiveSessions,
+		}
+
+		quota, err := h.QuotaService.SetQuota(r.Context(), quota, fmt.Sprintf("admin:%d", claims.IDCitizen))
+		if err != nil {
+			h.Logger.Warn("failed to update quota", zap.Error(err), zap.String("subject_type", vars["subject_type"]), zap.String("subject_id", vars["subject_id"]))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusOK, toQuotaResponse(quota))
+	}
+}
+
+// DeleteQuota removes the issuance quota override of a client or user (ADMIN only)
+// @Summary Delete Issuance Quota
+// @Description Removes the quota override of a client or user, which gets the default quotas again.
+// @Tags Admin - Quotas
+// @Produce json
+// @Security BearerAuth
+// @Param subject_type path string true "Subject type" Enums(client, user)
+// @Param subject_id path string true "Client ID or user ID"
+// @Success 204 "Quota override removed"
+// @Failure 400 {object} response.ErrorResponse "Unknown subject type"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden - Admin role required"
+// @Failure 404 {object} response.ErrorResponse "Quota override not found"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /admin/quotas/{subject_type}/{subject_id} [delete]
+func DeleteQuota(h *shared.QuotasHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		claims, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			httperrors.RespondWithError(w, httperrors.ErrUnauthorized)
+			return
+		}
+
+		vars := mux.Vars(r)
+		subjectType, subjectID := vars["subject_type"], vars["subject_id"]
+
+		if err := h.QuotaService.DeleteQuota(r.Context(), subjectType, subjectID, fmt.Sprintf("admin:%d", claims.IDCitizen)); err != nil {
+			h.Logger.Warn("failed to delete quota", zap.Error(err), zap.String("subject_type", subjectType), zap.String("subject_id", subjectID))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		w.WriteHeader(nethttp.StatusNoContent)
+	}
+}
+
+// toQuotaResponse converts a domain quota to the response DTO
+func toQuotaResponse(quota *domain.IssuanceQuota) response.QuotaResponse {
+	resp := response.QuotaResponse{
+		SubjectType:       quota.SubjectType,
+		SubjectID:         quota.SubjectID,
+		MaxTokensPerHour:  quota.MaxTokensPerHour,
+		MaxActiveSessions: quota.MaxActiveSessions,
+	}
+	if !quota.UpdatedAt.IsZero() {
+		updatedAt := quota.UpdatedAt
+		resp.UpdatedAt = &updatedAt
+	}
+	return resp
+}
@@ -0,0 +1,116 @@
+package admin
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/request"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+)
+
+// AddRedirectURI registers a redirect URI for an OAuth2 client (ADMIN only)
+// @Summary Add OAuth2 client redirect URI
+// @Description Registers a redirect URI for the authorization requests of the client. It must be an absolute https URI, an http URI
+// @Description on a loopback IP address (any port is then accepted, RFC 8252) or a private-use scheme URI of a native app, without fragment.
+// @Tags Admin - OAuth Clients
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "OAuth client ID"
+// @Param request body request.RedirectURIRequest true "Redirect URI"
+// @Success 200 {object} response.OAuthClientResponse "Redirect URI registered"
+// @Failure 400 {object} response.ErrorResponse "Invalid redirect URI or too many redirect URIs"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden - Admin role required"
+// @Failure 404 {object} response.ErrorResponse "Client not found"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /admin/oauth-clients/{id}/redirect-uris [post]
+func AddRedirectURI(h *shared.AdminOAuthClientsHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		id := mux.Vars(r)["id"]
+
+		var req request.RedirectURIRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.Logger.Debug("invalid request body", zap.Error(err))
+			httperrors.RespondWithError(w, httperrors.ErrInvalidRequestBody)
+			return
+		}
+		if req.RedirectURI == "" {
+			httperrors.RespondWithError(w, httperrors.ErrRequiredField)
+			return
+		}
+
+		client, err := h.OAuth2Service.AddRedirectURI(r.Context(), id, req.RedirectURI)
+		if err != nil {
+			h.Logger.Warn("failed to add oauth client redirect uri", zap.Error(err), logging.String("id", id))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusOK, redirectURIsResponse(client))
+	}
+}
+
+// RemoveRedirectURI unregisters a redirect URI of an OAuth2 client (ADMIN only)
+// @Summary Remove OAuth2 client redirect URI
+// @Description Unregisters a redirect URI of the client, its authorization requests can no longer redirect to it.
+// @Tags Admin - OAuth Clients
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "OAuth client ID"
+// @Param redirect_uri query string true "Redirect URI"
+// @Success 200 {object} response.OAuthClientResponse "Redirect URI removed"
+// @Failure 400 {object} response.ErrorResponse "Missing redirect URI"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden - Admin role required"
+// @Failure 404 {object} response.ErrorResponse "Client or redirect URI not found"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /admin/oauth-clients/{id}/redirect-uris [delete]
+func RemoveRedirectURI(h *shared.AdminOAuthClientsHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		id := mux.Vars(r)["id"]
+
+		redirectURI := r.URL.Query().Get("redirect_uri")
+		if redirectURI == "" {
+			httperrors.RespondWithError(w, httperrors.ErrRequiredField)
+			return
+		}
+
+		client, err := h.OAuth2Service.RemoveRedirectURI(r.Context(), id, redirectURI)
+		if err != nil {
+			h.Logger.Warn("failed to remove oauth client redirect uri", zap.Error(err), logging.String("id", id))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusOK, redirectURIsResponse(client))
+	}
+}
+
+// redirectURIsResponse converts the client whose redirect URIs were updated to its DTO
+func redirectURIsResponse(client *domain.OAuthClient) response.OAuthClientResponse {
+	return response.OAuthClientResponse{
+		ID:                    client.ID,
+		ClientID:              client.ClientID,
+		Name:                  client.Name,
+		Description:           client.Description,
+		Scopes:                client.Scopes,
+		Active:                client.Active,
+		RequireSignedRequests: client.RequireSignedRequests,
+		TokenProfile:          client.TokenProfile,
+		GrantTypes:            client.AllowedGrantTypes(),
+		RedirectURIs:          client.RedirectURIs,
+		Tags:                  client.Tags,
+		SecretExpiresAt:       client.SecretExpiresAt,
+		CreatedAt:             client.CreatedAt,
+		UpdatedAt:             client.UpdatedAt,
+		Version:               client.Version,
+	}
+}
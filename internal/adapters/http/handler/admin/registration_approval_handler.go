@@ -0,0 +1,189 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	nethttp "net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/request"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+)
+
+const (
+	defaultUserListLimit = 50
+	maxUserListLimit     = 200
+)
+
+// ListUsers lists the users in a status, by default the registrations waiting for approval (ADMIN only)
+// @Summary List Users By Status
+// @Description Retrieves a page of the users in the given status, oldest first. "pending" is an alias of PENDING_APPROVAL.
+// @Tags Admin - Users
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Status of the users" Enums(pending, PENDING_APPROVAL, ACTIVE, SUSPENDED, REJECTED, ANONYMIZED) default(pending)
+// @Param limit query int false "Maximum number of users, up to 200" default(50)
+// @Param offset query int false "Number of users to skip" default(0)
+// @Success 200 {array} response.AdminUserResponse "List of users"
+// @Failure 400 {object} response.ErrorResponse "Invalid status or pagination"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden - Admin role required"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /admin/users [get]
+func ListUsers(h *shared.RegistrationApprovalHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		query := r.URL.Query()
+		status, statusErr := userListStatus(query)
+		limit, limitErr := userListInt(query, "limit", defaultUserListLimit)
+		offset, offsetErr := userListInt(query, "offset", 0)
+		if err := errors.Join(statusErr, limitErr, offsetErr); err != nil {
+			h.Logger.Debug("invalid users filter", zap.Error(err))
+			httperrors.RespondWithError(w, httperrors.ErrInvalidUserListFilter)
+			return
+		}
+		limit = min(limit, maxUserListLimit)
+
+		users, err := h.RegistrationApprovalService.ListUsers(r.Context(), status, limit, offset)
+		if err != nil {
+			h.Logger.Error("failed to list users", zap.Error(err), zap.String("status", status.String()))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		userResponses := make([]response.AdminUserResponse, 0, len(users))
+		for _, user := range users {
+			userResponses = append(userResponses, toAdminUserResponse(user))
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusOK, userResponses)
+	}
+}
+
+// ApproveUser approves a registration waiting for approval (ADMIN only)
+// @Summary Approve Registration
+// @Description Activates a self-registered user waiting for approval, who can log in from then on. The user is notified.
+// @Tags Admin - Users
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} response.AdminUserResponse "Registration approved"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden - Admin role required"
+// @Failure 404 {object} response.ErrorResponse "User not found"
+// @Failure 409 {object} response.ErrorResponse "User is not pending approval"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /admin/users/{id}/approve [post]
+func ApproveUser(h *shared.RegistrationApprovalHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		claims, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			httperrors.RespondWithError(w, httperrors.ErrUnauthorized)
+			return
+		}
+
+		id := mux.Vars(r)["id"]
+		user, err := h.RegistrationApprovalService.ApproveUser(r.Context(), id, fmt.Sprintf("admin:%d", claims.IDCitizen))
+		if err != nil {
+			h.Logger.Warn("failed to approve registration", zap.Error(err), logging.String("id", id))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusOK, toAdminUserResponse(user))
+	}
+}
+
+// RejectUser rejects a registration waiting for approval (ADMIN only)
+// @Summary Reject Registration
+// @Description Rejects a self-registered user waiting for approval, who can never log in. The user is notified with the optional reason.
+// @Tags Admin - Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param request body request.RejectRegistrationRequest false "Reason of the rejection"
+// @Success 200 {object} response.AdminUserResponse "Registration rejected"
+// @Failure 400 {object} response.ErrorResponse "Invalid request body"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden - Admin role required"
+// @Failure 404 {object} response.ErrorResponse "User not found"
+// @Failure 409 {object} response.ErrorResponse "User is not pending approval"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /admin/users/{id}/reject [post]
+func RejectUser(h *shared.RegistrationApprovalHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		claims, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			httperrors.RespondWithError(w, httperrors.ErrUnauthorized)
+			return
+		}
+
+		// The body is optional, an empty one rejects without a reason
+		var req request.RejectRegistrationRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+			h.Logger.Debug("invalid request body", zap.Error(err))
+			httperrors.RespondWithError(w, httperrors.ErrInvalidRequestBody)
+			return
+		}
+
+		id := mux.Vars(r)["id"]
+		user, err := h.RegistrationApprovalService.RejectUser(r.Context(), id, strings.TrimSpace(req.Reason), fmt.Sprintf("admin:%d", claims.IDCitizen))
+		if err != nil {
+			h.Logger.Warn("failed to reject registration", zap.Error(err), logging.String("id", id))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusOK, toAdminUserResponse(user))
+	}
+}
+
+// userListStatus returns the status filter of the users list, the registrations waiting for approval by default
+func userListStatus(query url.Values) (domain.UserStatus, error) {
+	value := strings.ToUpper(query.Get("status"))
+	if value == "" || value == "PENDING" {
+		return domain.UserStatusPendingApproval, nil
+	}
+	return domain.ParseUserStatus(value)
+}
+
+// userListInt parses a non-negative pagination parameter, returning the default when it's not set
+func userListInt(query url.Values, name string, defaultValue int) (int, error) {
+	value := query.Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		return 0, fmt.Errorf("invalid %s: %q", name, value)
+	}
+	return parsed, nil
+}
+
+// toAdminUserResponse converts a user to its administration response
+func toAdminUserResponse(user *domain.User) response.AdminUserResponse {
+	return response.AdminUserResponse{
+		ID:        user.ID,
+		IDCitizen: user.IDCitizen,
+		Email:     user.Email,
+		Name:      user.Name,
+		Role:      user.Role,
+		Status:    user.Status,
+		Type:      user.Type,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+		Version:   user.Version,
+	}
+}
@@ -0,0 +1,46 @@
+package admin
+
+import (
+	nethttp "net/http"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+)
+
+// RevokeClientTokens revokes all the access tokens issued to an OAuth2 client (ADMIN only)
+// @Summary Revoke OAuth2 Client Tokens
+// @Description Revokes every unexpired access token issued to the client, e.g. when its secret was compromised.
+// @Description Revoked tokens are rejected by validation and reported inactive by introspection.
+// @Description Tokens issued after the revocation remain valid, so deactivate the client or change its secret first.
+// @Tags Admin - OAuth Clients
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "OAuth client ID"
+// @Success 200 {object} response.RevokedClientTokensResponse "Tokens revoked, with the number of revoked tokens"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden - Admin role required"
+// @Failure 404 {object} response.ErrorResponse "Client not found"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /admin/oauth-clients/{id}/revoke-tokens [post]
+func RevokeClientTokens(h *shared.AdminOAuthClientsHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		id := mux.Vars(r)["id"]
+
+		revoked, err := h.OAuth2Service.RevokeClientTokens(r.Context(), id)
+		if err != nil {
+			h.Logger.Warn("failed to revoke oauth client tokens", zap.Error(err), logging.String("id", id))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusOK, response.RevokedClientTokensResponse{
+			ID:            id,
+			RevokedTokens: revoked,
+		})
+	}
+}
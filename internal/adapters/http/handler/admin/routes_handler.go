@@ -0,0 +1,46 @@
+package admin
+
+import (
+	nethttp "net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+)
+
+// ListRoutes lists the registered routes with the authentication and the rate limit they apply (ADMIN only)
+// @Summary List Routes
+// @Description Enumerates the routes registered in the router with their methods, the authentication they require
+// @Description (none, user, admin or client-scope) and their rate-limit class, for security reviews and gateway configuration.
+// @Description The auth parameter filters the routes, e.g. auth=none lists the public endpoints.
+// @Tags Admin - Configuration
+// @Produce json
+// @Security BearerAuth
+// @Param auth query string false "Required authentication" Enums(none, user, admin, client-scope)
+// @Success 200 {object} response.RoutesResponse "Registered routes"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden - Admin role required"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /admin/routes [get]
+func ListRoutes(h *shared.AdminRoutesHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		routes, err := h.ListRoutes()
+		if err != nil {
+			h.Logger.Error("failed to list routes", zap.Error(err))
+			httperrors.RespondWithError(w, httperrors.ErrInternalServer)
+			return
+		}
+
+		auth := r.URL.Query().Get("auth")
+		filtered := make([]response.RouteResponse, 0, len(routes))
+		for _, route := range routes {
+			if auth == "" || route.Auth == auth {
+				filtered = append(filtered, route)
+			}
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusOK, response.RoutesResponse{Routes: filtered})
+	}
+}
@@ -0,0 +1,157 @@
+package admin
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/request"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+)
+
+// ListScopes lists the registered OAuth2 scopes
+// @Summary List OAuth2 Scopes
+// @Description Retrieves every registered OAuth2 scope so documentation and consent screens can describe them.
+// @Tags OAuth2
+// @Produce json
+// @Success 200 {array} response.ScopeResponse "List of scopes"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /oauth/scopes [get]
+func ListScopes(h *shared.ScopesHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		scopes, err := h.ScopeService.ListScopes(r.Context())
+		if err != nil {
+			h.Logger.Error("failed to list scopes", zap.Error(err))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		scopeResponses := make([]response.ScopeResponse, 0, len(scopes))
+		for _, scope := range scopes {
+			scopeResponses = append(scopeResponses, toScopeResponse(scope))
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusOK, scopeResponses)
+	}
+}
+
+// CreateScope registers a new OAuth2 scope (ADMIN only)
+// @Summary Create OAuth2 Scope
+// @Description Registers a new scope that can then be assigned to OAuth2 clients.
+// @Tags Admin - OAuth Clients
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.CreateScopeRequest true "Scope data"
+// @Success 201 {object} response.ScopeResponse "Scope created successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid request or scope name"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden - Admin role required"
+// @Failure 409 {object} response.ErrorResponse "Scope already exists"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /admin/scopes [post]
+func CreateScope(h *shared.ScopesHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		var req request.CreateScopeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.Logger.Debug("invalid request body", zap.Error(err))
+			httperrors.RespondWithError(w, httperrors.ErrInvalidRequestBody)
+			return
+		}
+
+		if req.Name == "" {
+			httperrors.RespondWithError(w, httperrors.ErrRequiredField)
+			return
+		}
+
+		scope, err := h.ScopeService.CreateScope(r.Context(), req.Name, req.Description)
+		if err != nil {
+			h.Logger.Warn("failed to create scope", zap.Error(err), logging.String("scope", req.Name))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusCreated, toScopeResponse(scope))
+	}
+}
+
+// UpdateScope updates the description of an OAuth2 scope (ADMIN only)
+// @Summary Update OAuth2 Scope
+// @Description Updates the description of a registered scope. System scopes cannot be modified.
+// @Tags Admin - OAuth Clients
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "Scope name"
+// @Param request body request.UpdateScopeRequest true "Scope data"
+// @Success 200 {object} response.ScopeResponse "Scope updated successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid request"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden - Admin role required or system scope"
+// @Failure 404 {object} response.ErrorResponse "Scope not found"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /admin/scopes/{name} [put]
+func UpdateScope(h *shared.ScopesHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		name := mux.Vars(r)["name"]
+
+		var req request.UpdateScopeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.Logger.Debug("invalid request body", zap.Error(err))
+			httperrors.RespondWithError(w, httperrors.ErrInvalidRequestBody)
+			return
+		}
+
+		scope, err := h.ScopeService.UpdateScope(r.Context(), name, req.Description)
+		if err != nil {
+			h.Logger.Warn("failed to update scope", zap.Error(err), logging.String("scope", name))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusOK, toScopeResponse(scope))
+	}
+}
+
+// DeleteScope removes an OAuth2 scope from the registry (ADMIN only)
+// @Summary Delete OAuth2 Scope
+// @Description Removes a scope from the registry. System scopes and scopes assigned to clients cannot be deleted.
+// @Tags Admin - OAuth Clients
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "Scope name"
+// @Success 204 "Scope deleted successfully"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden - Admin role required or system scope"
+// @Failure 404 {object} response.ErrorResponse "Scope not found"
+// @Failure 409 {object} response.ErrorResponse "Scope assigned to clients"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /admin/scopes/{name} [delete]
+func DeleteScope(h *shared.ScopesHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		name := mux.Vars(r)["name"]
+
+		if err := h.ScopeService.DeleteScope(r.Context(), name); err != nil {
+			h.Logger.Warn("failed to delete scope", zap.Error(err), logging.String("scope", name))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		w.WriteHeader(nethttp.StatusNoContent)
+	}
+}
+
+// toScopeResponse converts a domain scope to the response DTO
+func toScopeResponse(scope *domain.Scope) response.ScopeResponse {
+	return response.ScopeResponse{
+		Name:        scope.Name,
+		Description: scope.Description,
+		System:      scope.System,
+	}
+}
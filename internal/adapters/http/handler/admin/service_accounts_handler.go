@@ -0,0 +1,62 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	nethttp "net/http"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/request"
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+)
+
+// CreateServiceAccount creates a service account (ADMIN only)
+// @Summary Create Service Account
+// @Description Creates the identity of an automation. Service accounts can't sign in with a password and their email is a reserved address that receives no mail. The creation is recorded in the audit log.
+// @Tags Admin - Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.CreateServiceAccountRequest true "Service account"
+// @Success 201 {object} response.AdminUserResponse "Service account created"
+// @Failure 400 {object} response.ErrorResponse "Invalid request body or missing fields"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden - Admin role required"
+// @Failure 409 {object} response.ErrorResponse "A user with the citizen ID already exists"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /admin/service-accounts [post]
+func CreateServiceAccount(h *shared.ServiceAccountsHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		claims, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			httperrors.RespondWithError(w, httperrors.ErrUnauthorized)
+			return
+		}
+
+		var req request.CreateServiceAccountRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.Logger.Debug("invalid request body", zap.Error(err))
+			httperrors.RespondWithError(w, httperrors.ErrInvalidRequestBody)
+			return
+		}
+
+		name := strings.TrimSpace(req.Name)
+		if name == "" || req.IDCitizen <= 0 {
+			httperrors.RespondWithError(w, httperrors.ErrRequiredField)
+			return
+		}
+
+		user, err := h.ServiceAccountService.CreateServiceAccount(r.Context(), name, req.IDCitizen, fmt.Sprintf("admin:%d", claims.IDCitizen))
+		if err != nil {
+			h.Logger.Warn("failed to create service account", zap.Error(err), zap.Int("id_citizen", req.IDCitizen))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusCreated, toAdminUserResponse(user))
+	}
+}
@@ -0,0 +1,83 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	admin "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/admin"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestAnonymizeUserHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		noClaims       bool
+		anonymizeErr   error
+		wantStatusCode int
+		wantCode       string
+	}{
+		{name: "successful anonymization", wantStatusCode: http.StatusOK},
+		{name: "missing claims", noClaims: true, wantStatusCode: http.StatusUnauthorized, wantCode: "UNAUTHORIZED"},
+		{name: "user not found", anonymizeErr: domainerrors.ErrUserNotFound, wantStatusCode: http.StatusNotFound, wantCode: "USER_NOT_FOUND"},
+		{name: "already anonymized", anonymizeErr: domainerrors.ErrUserAlreadyAnonymized, wantStatusCode: http.StatusConflict, wantCode: "USER_ALREADY_ANONYMIZED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockAnonymizationService{
+				AnonymizeUserFunc: func(ctx context.Context, userID, actor string) (*domain.UserPublic, error) {
+					if userID != "user-123" || actor != "admin:999" {
+						t.Errorf("AnonymizeUser() userID = %v, actor = %v, want user-123, admin:999", userID, actor)
+					}
+					if tt.anonymizeErr != nil {
+						return nil, tt.anonymizeErr
+					}
+					return &domain.UserPublic{ID: userID, IDCitizen: 12345, Email: "hash@anonymized.invalid", Name: domain.AnonymizedName}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/users/user-123/anonymize", nil)
+			req = mux.SetURLVars(req, map[string]string{"id": "user-123"})
+			if !tt.noClaims {
+				claims := &domain.TokenClaims{IDCitizen: 999, Role: domain.RoleAdmin}
+				req = req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, claims))
+			}
+			w := httptest.NewRecorder()
+
+			admin.AnonymizeUser(shared.NewAdminUsersHandler(mockService, nil, nil, nil, zap.NewNop()))(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+
+			if tt.wantCode != "" {
+				var resp response.ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("error code = %v, want %v", resp.Code, tt.wantCode)
+				}
+				return
+			}
+
+			var resp response.UserResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.ID != "user-123" || resp.Name != domain.AnonymizedName {
+				t.Errorf("response = %+v", resp)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	admin "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/admin"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestListExpiringClientSecretsHandler(t *testing.T) {
+	expired := time.Now().Add(-time.Hour)
+	expiring := time.Now().Add(48 * time.Hour)
+
+	tests := []struct {
+		name           string
+		query          string
+		wantWithin     time.Duration
+		wantStatusCode int
+	}{
+		{name: "default days", wantWithin: 30 * 24 * time.Hour, wantStatusCode: http.StatusOK},
+		{name: "given days", query: "?days=7", wantWithin: 7 * 24 * time.Hour, wantStatusCode: http.StatusOK},
+		{name: "zero days", query: "?days=0", wantStatusCode: http.StatusBadRequest},
+		{name: "too many days", query: "?days=366", wantStatusCode: http.StatusBadRequest},
+		{name: "not a number", query: "?days=week", wantStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotWithin time.Duration
+			mockOAuth2Service := &MockOAuth2Service{
+				ListExpiringSecretsFunc: func(ctx context.Context, within time.Duration) ([]*domain.OAuthClient, error) {
+					gotWithin = within
+					return []*domain.OAuthClient{
+						{ID: "id-1", ClientID: "expired", Name: "Expired", SecretExpiresAt: &expired},
+						{ID: "id-2", ClientID: "expiring", Name: "Expiring", SecretExpiresAt: &expiring},
+					}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/oauth-clients/expiring-secrets"+tt.query, nil)
+			w := httptest.NewRecorder()
+			admin.ListExpiringClientSecrets(shared.NewAdminOAuthClientsHandler(mockOAuth2Service, zap.NewNop()))(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+			if tt.wantStatusCode != http.StatusOK {
+				return
+			}
+			if gotWithin != tt.wantWithin {
+				t.Errorf("ListExpiringSecrets() within = %v, want %v", gotWithin, tt.wantWithin)
+			}
+
+			var resp []response.ExpiringClientSecretResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(resp) != 2 || !resp[0].Expired || resp[1].Expired {
+				t.Errorf("response = %+v, want the expired client then the expiring one", resp)
+			}
+		})
+	}
+}
+
+func TestSetClientSecretExpiryHandler(t *testing.T) {
+	var gotExpiresAt *time.Time
+	mockOAuth2Service := &MockOAuth2Service{
+		SetSecretExpiryFunc: func(ctx context.Context, id string, expiresAt *time.Time) (*domain.OAuthClient, error) {
+			gotExpiresAt = expiresAt
+			return &domain.OAuthClient{ID: id, ClientID: "client-123", SecretExpiresAt: expiresAt}, nil
+		},
+	}
+	handler := admin.SetClientSecretExpiry(shared.NewAdminOAuthClientsHandler(mockOAuth2Service, zap.NewNop()))
+
+	req := httptest.NewRequest(http.MethodPut, "/admin/oauth-clients/id-123/secret-expiry", strings.NewReader(`{"secret_expires_at": "2030-01-02T03:04:05Z"}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "id-123"})
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status code = %v, want %v", w.Code, http.StatusOK)
+	}
+	if want := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC); gotExpiresAt == nil || !gotExpiresAt.Equal(want) {
+		t.Errorf("SetSecretExpiry() expiresAt = %v, want %v", gotExpiresAt, want)
+	}
+
+	// null removes the expiry
+	req = httptest.NewRequest(http.MethodPut, "/admin/oauth-clients/id-123/secret-expiry", strings.NewReader(`{"secret_expires_at": null}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "id-123"})
+	w = httptest.NewRecorder()
+	handler(w, req)
+
+	if w.Code != http.StatusOK || gotExpiresAt != nil {
+		t.Errorf("status code = %v, expiresAt = %v, want 200 without expiry", w.Code, gotExpiresAt)
+	}
+}
@@ -0,0 +1,45 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	admin "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/admin"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+)
+
+func TestGetConfigHandler(t *testing.T) {
+	config := map[string]interface{}{
+		"JWT":      map[string]interface{}{"Secret": "[REDACTED]", "AccessTokenDuration": "15m0s"},
+		"Features": map[string]bool{"Avatars": true},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/config", nil)
+	w := httptest.NewRecorder()
+
+	admin.GetConfig(shared.NewAdminConfigHandler(config, "1.0.0", zap.NewNop()))(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status code = %v, want %v", w.Code, http.StatusOK)
+	}
+
+	var resp response.EffectiveConfigResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Version != "1.0.0" {
+		t.Errorf("Version = %v, want 1.0.0", resp.Version)
+	}
+	jwt, ok := resp.Config["JWT"].(map[string]interface{})
+	if !ok || jwt["Secret"] != "[REDACTED]" || jwt["AccessTokenDuration"] != "15m0s" {
+		t.Errorf("Config[JWT] = %v", resp.Config["JWT"])
+	}
+	if features, ok := resp.Config["Features"].(map[string]interface{}); !ok || features["Avatars"] != true {
+		t.Errorf("Config[Features] = %v", resp.Config["Features"])
+	}
+}
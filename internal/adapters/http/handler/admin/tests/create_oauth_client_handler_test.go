@@ -40,7 +40,7 @@ func TestCreateOAuthClientHandler(t *testing.T) {
 				Scopes:       []string{"read", "write"},
 			},
 			mockSetup: func(m *MockOAuth2Service) {
-				m.CreateClientFunc = func(ctx context.Context, clientID, clientSecret, name, description string, scopes []string) (*domain.OAuthClient, error) {
+				m.CreateClientFunc = func(ctx context.Context, clientID, clientSecret, name, description string, scopes, grantTypes, redirectURIs, tags []string, secretExpiresAt *time.Time) (*domain.OAuthClient, error) {
 					return &domain.OAuthClient{
 						ID:          "client-123",
 						ClientID:    clientID,
@@ -158,7 +158,7 @@ func TestCreateOAuthClientHandler(t *testing.T) {
 				Name:         "Existing Client",
 			},
 			mockSetup: func(m *MockOAuth2Service) {
-				m.CreateClientFunc = func(ctx context.Context, clientID, clientSecret, name, description string, scopes []string) (*domain.OAuthClient, error) {
+				m.CreateClientFunc = func(ctx context.Context, clientID, clientSecret, name, description string, scopes, grantTypes, redirectURIs, tags []string, secretExpiresAt *time.Time) (*domain.OAuthClient, error) {
 					return nil, errors.New("client with id existing_client already exists")
 				}
 			},
@@ -182,7 +182,7 @@ func TestCreateOAuthClientHandler(t *testing.T) {
 				Name:         "Test Client",
 			},
 			mockSetup: func(m *MockOAuth2Service) {
-				m.CreateClientFunc = func(ctx context.Context, clientID, clientSecret, name, description string, scopes []string) (*domain.OAuthClient, error) {
+				m.CreateClientFunc = func(ctx context.Context, clientID, clientSecret, name, description string, scopes, grantTypes, redirectURIs, tags []string, secretExpiresAt *time.Time) (*domain.OAuthClient, error) {
 					return nil, errors.New("database error")
 				}
 			},
@@ -206,7 +206,7 @@ func TestCreateOAuthClientHandler(t *testing.T) {
 				Name:         "Minimal Client",
 			},
 			mockSetup: func(m *MockOAuth2Service) {
-				m.CreateClientFunc = func(ctx context.Context, clientID, clientSecret, name, description string, scopes []string) (*domain.OAuthClient, error) {
+				m.CreateClientFunc = func(ctx context.Context, clientID, clientSecret, name, description string, scopes, grantTypes, redirectURIs, tags []string, secretExpiresAt *time.Time) (*domain.OAuthClient, error) {
 					return &domain.OAuthClient{
 						ID:          "client-456",
 						ClientID:    clientID,
@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	admin "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/admin"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestCreateUserHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		noClaims       bool
+		createErr      error
+		wantStatusCode int
+		wantCode       string
+	}{
+		{name: "successful creation", body: `{"email":" citizen@example.com ","name":"Citizen","id_citizen":12345}`, wantStatusCode: http.StatusCreated},
+		{name: "missing claims", body: `{"email":"citizen@example.com","name":"Citizen","id_citizen":12345}`, noClaims: true, wantStatusCode: http.StatusUnauthorized, wantCode: "UNAUTHORIZED"},
+		{name: "invalid body", body: `{`, wantStatusCode: http.StatusBadRequest, wantCode: "INVALID_REQUEST_BODY"},
+		{name: "missing email", body: `{"name":"Citizen","id_citizen":12345}`, wantStatusCode: http.StatusBadRequest, wantCode: "REQUIRED_FIELD"},
+		{name: "missing citizen ID", body: `{"email":"citizen@example.com","name":"Citizen"}`, wantStatusCode: http.StatusBadRequest, wantCode: "REQUIRED_FIELD"},
+		{name: "user exists", body: `{"email":"citizen@example.com","name":"Citizen","id_citizen":12345}`, createErr: domainerrors.ErrUserAlreadyExists, wantStatusCode: http.StatusConflict, wantCode: "USER_ALREADY_EXISTS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockUserProvisioningService{
+				CreateUserFunc: func(ctx context.Context, email, name string, idCitizen int, actor string) (*domain.User, string, error) {
+					if email != "citizen@example.com" || name != "Citizen" || idCitizen != 12345 || actor != "admin:999" {
+						t.Errorf("CreateUser(%q, %q, %d, %q), want (citizen@example.com, Citizen, 12345, admin:999)", email, name, idCitizen, actor)
+					}
+					if tt.createErr != nil {
+						return nil, "", tt.createErr
+					}
+					user, err := domain.NewUser(email, "Temp0rary-pw", name, idCitizen)
+					user.MustChangePassword = true
+					return user, "Temp0rary-pw", err
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/users", bytes.NewBufferString(tt.body))
+			if !tt.noClaims {
+				claims := &domain.TokenClaims{IDCitizen: 999, Role: domain.RoleAdmin}
+				req = req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, claims))
+			}
+			w := httptest.NewRecorder()
+
+			admin.CreateUser(shared.NewUserProvisioningHandler(mockService, zap.NewNop()))(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+
+			if tt.wantCode != "" {
+				var resp response.ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("error code = %v, want %v", resp.Code, tt.wantCode)
+				}
+				return
+			}
+
+			var resp response.CreateUserResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.User.IDCitizen != 12345 || resp.TemporaryPassword != "Temp0rary-pw" || !resp.MustChangePassword {
+				t.Errorf("response = %+v, want user 12345 with its temporary password", resp)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	admin "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/admin"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestDecodeTokenHandler(t *testing.T) {
+	report := &domain.TokenDebugReport{
+		Header: map[string]interface{}{"alg": "HS256"},
+		Claims: map[string]interface{}{"id_citizen": float64(12345)},
+		Checks: []domain.TokenCheck{
+			{Name: domain.TokenCheckSignature, Status: domain.TokenCheckPassed},
+			{Name: domain.TokenCheckExpiration, Status: domain.TokenCheckFailed, Detail: "expired"},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		noClaims       bool
+		decodeErr      error
+		wantStatusCode int
+		wantCode       string
+	}{
+		{name: "decoded token", body: `{"token":"Bearer header.payload.signature"}`, wantStatusCode: http.StatusOK},
+		{name: "missing claims", body: `{"token":"header.payload.signature"}`, noClaims: true, wantStatusCode: http.StatusUnauthorized, wantCode: "UNAUTHORIZED"},
+		{name: "invalid body", body: `{`, wantStatusCode: http.StatusBadRequest, wantCode: "INVALID_REQUEST_BODY"},
+		{name: "missing token", body: `{"token":" "}`, wantStatusCode: http.StatusBadRequest, wantCode: "REQUIRED_FIELD"},
+		{name: "not a JWT", body: `{"token":"header.payload.signature"}`, decodeErr: domainerrors.ErrInvalidToken, wantStatusCode: http.StatusBadRequest, wantCode: "MALFORMED_TOKEN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockTokenDebugService{
+				DecodeTokenFunc: func(ctx context.Context, token string) (*domain.TokenDebugReport, error) {
+					if token != "header.payload.signature" {
+						t.Errorf("DecodeToken(%q), want the token without the Bearer prefix", token)
+					}
+					return report, tt.decodeErr
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/debug/decode-token", bytes.NewBufferString(tt.body))
+			if !tt.noClaims {
+				claims := &domain.TokenClaims{IDCitizen: 999, Role: domain.RoleAdmin}
+				req = req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, claims))
+			}
+			w := httptest.NewRecorder()
+
+			admin.DecodeToken(shared.NewTokenDebugHandler(mockService, zap.NewNop()))(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+
+			if tt.wantCode != "" {
+				var resp response.ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("error code = %v, want %v", resp.Code, tt.wantCode)
+				}
+				return
+			}
+
+			var resp response.TokenDebugResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Valid || len(resp.Checks) != 2 || resp.Checks[1].Status != "failed" || resp.Header["alg"] != "HS256" {
+				t.Errorf("response = %+v, want the report of the token", resp)
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	admin "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/admin"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestDeviceCodeHandler(t *testing.T) {
+	logger := zap.NewNop()
+
+	tests := []struct {
+		name           string
+		contentType    string
+		body           string
+		mockSetup      func(*MockDeviceAuthorizationService)
+		wantStatusCode int
+		wantCode       string
+		checkResponse  func(*testing.T, response.DeviceCodeResponse)
+	}{
+		{
+			name:        "device code issued with form data",
+			contentType: "application/x-www-form-urlencoded",
+			body:        url.Values{"client_id": {"cli"}, "client_secret": {"secret"}, "scope": {"read write"}}.Encode(),
+			mockSetup: func(m *MockDeviceAuthorizationService) {
+				m.RequestDeviceCodeFunc = func(ctx context.Context, clientID, clientSecret string, scopes []string) (*domain.DeviceAuthorization, error) {
+					if clientID != "cli" || clientSecret != "secret" || len(scopes) != 2 {
+						t.Errorf("RequestDeviceCode() got client %q secret %q scopes %v", clientID, clientSecret, scopes)
+					}
+					return &domain.DeviceAuthorization{
+						DeviceCode: "device-code",
+						UserCode:   "BCDF-GHJK",
+						Interval:   5 * time.Second,
+						ExpiresAt:  time.Now().Add(10 * time.Minute),
+					}, nil
+				}
+			},
+			wantStatusCode: http.StatusOK,
+			checkResponse: func(t *testing.T, resp response.DeviceCodeResponse) {
+				if resp.DeviceCode != "device-code" || resp.UserCode != "BCDF-GHJK" {
+					t.Errorf("unexpected codes: %+v", resp)
+				}
+				if resp.VerificationURIComplete != "https://auth.example.com/device?user_code=BCDF-GHJK" {
+					t.Errorf("VerificationURIComplete = %v", resp.VerificationURIComplete)
+				}
+				if resp.Interval != 5 {
+					t.Errorf("Interval = %v, want 5", resp.Interval)
+				}
+				if resp.ExpiresIn <= 0 || resp.ExpiresIn > 600 {
+					t.Errorf("ExpiresIn = %v, want (0, 600]", resp.ExpiresIn)
+				}
+			},
+		},
+		{
+			name:           "missing client_id",
+			contentType:    "application/json",
+			body:           `{"scope":"read"}`,
+			mockSetup:      func(m *MockDeviceAuthorizationService) {},
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "REQUIRED_FIELD",
+		},
+		{
+			name:           "missing client_secret",
+			contentType:    "application/json",
+			body:           `{"client_id":"cli","scope":"read"}`,
+			mockSetup:      func(m *MockDeviceAuthorizationService) {},
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "REQUIRED_FIELD",
+		},
+		{
+			name:           "invalid JSON",
+			contentType:    "application/json",
+			body:           `{invalid`,
+			mockSetup:      func(m *MockDeviceAuthorizationService) {},
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "INVALID_REQUEST_BODY",
+		},
+		{
+			name:        "unknown client",
+			contentType: "application/json",
+			body:        `{"client_id":"unknown","client_secret":"secret"}`,
+			mockSetup: func(m *MockDeviceAuthorizationService) {
+				m.RequestDeviceCodeFunc = func(ctx context.Context, clientID, clientSecret string, scopes []string) (*domain.DeviceAuthorization, error) {
+					return nil, domainerrors.ErrInvalidClient
+				}
+			},
+			wantStatusCode: http.StatusUnauthorized,
+			wantCode:       "INVALID_CLIENT",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockDeviceAuthorizationService{}
+			tt.mockSetup(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/oauth/device/code", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			w := httptest.NewRecorder()
+
+			h := shared.NewOAuth2Handler(&MockOAuth2Service{}, mockService, nil, "", false, logger)
+			admin.DeviceCode(h)(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+
+			if tt.wantCode != "" {
+				var resp response.ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("Error code = %v, want %v", resp.Code, tt.wantCode)
+				}
+				return
+			}
+
+			var resp response.DeviceCodeResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			tt.checkResponse(t, resp)
+		})
+	}
+}
+
+func TestTokenHandler_DeviceCodeGrant(t *testing.T) {
+	logger := zap.NewNop()
+
+	tests := []struct {
+		name           string
+		formData       url.Values
+		pollErr        error
+		wantStatusCode int
+		wantCode       string
+	}{
+		{
+			name:           "approved device code returns tokens",
+			formData:       url.Values{"client_id": {"cli"}, "client_secret": {"secret"}, "grant_type": {domain.GrantTypeDeviceCode}, "device_code": {"device-code"}},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "missing device_code",
+			formData:       url.Values{"client_id": {"cli"}, "client_secret": {"secret"}, "grant_type": {domain.GrantTypeDeviceCode}},
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "invalid_request",
+		},
+		{
+			name:           "missing client_secret",
+			formData:       url.Values{"client_id": {"cli"}, "grant_type": {domain.GrantTypeDeviceCode}, "device_code": {"device-code"}},
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "invalid_request",
+		},
+		{
+			name:           "invalid client secret",
+			formData:       url.Values{"client_id": {"cli"}, "client_secret": {"wrong"}, "grant_type": {domain.GrantTypeDeviceCode}, "device_code": {"device-code"}},
+			pollErr:        domainerrors.ErrInvalidCredentials,
+			wantStatusCode: http.StatusUnauthorized,
+			wantCode:       "invalid_client",
+		},
+		{
+			name:           "authorization pending",
+			formData:       url.Values{"client_id": {"cli"}, "client_secret": {"secret"}, "grant_type": {domain.GrantTypeDeviceCode}, "device_code": {"device-code"}},
+			pollErr:        domainerrors.ErrAuthorizationPending,
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "authorization_pending",
+		},
+		{
+			name:           "slow down",
+			formData:       url.Values{"client_id": {"cli"}, "client_secret": {"secret"}, "grant_type": {domain.GrantTypeDeviceCode}, "device_code": {"device-code"}},
+			pollErr:        domainerrors.ErrSlowDown,
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "slow_down",
+		},
+		{
+			name:           "access denied",
+			formData:       url.Values{"client_id": {"cli"}, "client_secret": {"secret"}, "grant_type": {domain.GrantTypeDeviceCode}, "device_code": {"device-code"}},
+			pollErr:        domainerrors.ErrAccessDenied,
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "access_denied",
+		},
+		{
+			name:           "expired device code",
+			formData:       url.Values{"client_id": {"cli"}, "client_secret": {"secret"}, "grant_type": {domain.GrantTypeDeviceCode}, "device_code": {"device-code"}},
+			pollErr:        domainerrors.ErrDeviceCodeExpired,
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "expired_token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockDeviceAuthorizationService{
+				PollTokenFunc: func(ctx context.Context, clientID, clientSecret, deviceCode string) (*domain.TokenPair, error) {
+					if tt.pollErr != nil {
+						return nil, tt.pollErr
+					}
+					return &domain.TokenPair{AccessToken: "access", RefreshToken: "refresh", TokenType: "Bearer", ExpiresIn: 900}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/token", strings.NewReader(tt.formData.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			w := httptest.NewRecorder()
+
+			h := shared.NewOAuth2Handler(&MockOAuth2Service{}, mockService, nil, "", false, logger)
+			admin.Token(h)(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+
+			if tt.wantCode != "" {
+				var resp response.OAuthErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Error != tt.wantCode {
+					t.Errorf("error = %v, want %v", resp.Error, tt.wantCode)
+				}
+				return
+			}
+
+			var resp response.TokenResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.AccessToken != "access" || resp.RefreshToken != "refresh" {
+				t.Errorf("TokenResponse = %+v, want issued tokens", resp)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	admin "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/admin"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestGetDeviceVerificationHandler(t *testing.T) {
+	logger := zap.NewNop()
+
+	tests := []struct {
+		name                string
+		query               string
+		authenticated       bool
+		lookupErr           error
+		consentRequired     bool
+		consentErr          error
+		wantStatusCode      int
+		wantConsentRequired bool
+	}{
+		{name: "pending authorization", query: "?user_code=BCDF-GHJK", authenticated: true, consentRequired: true, wantStatusCode: http.StatusOK, wantConsentRequired: true},
+		{name: "scopes already consented", query: "?user_code=BCDF-GHJK", authenticated: true, wantStatusCode: http.StatusOK},
+		{name: "unauthenticated", query: "?user_code=BCDF-GHJK", wantStatusCode: http.StatusUnauthorized},
+		{name: "missing user code", query: "", authenticated: true, wantStatusCode: http.StatusBadRequest},
+		{name: "invalid user code", query: "?user_code=ZZZZ-ZZZZ", authenticated: true, lookupErr: domainerrors.ErrInvalidUserCode, wantStatusCode: http.StatusBadRequest},
+		{name: "consent lookup error", query: "?user_code=BCDF-GHJK", authenticated: true, consentErr: domainerrors.ErrInternal, wantStatusCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockDeviceAuthorizationService{
+				GetByUserCodeFunc: func(ctx context.Context, userCode string) (*domain.DeviceAuthorization, error) {
+					if tt.lookupErr != nil {
+						return nil, tt.lookupErr
+					}
+					return &domain.DeviceAuthorization{UserCode: userCode, ClientID: "cli", Scopes: []string{"read"}, ExpiresAt: time.Now().Add(time.Minute)}, nil
+				},
+				RequiresConsentFunc: func(ctx context.Context, auth *domain.DeviceAuthorization, idCitizen int) (bool, error) {
+					return tt.consentRequired, tt.consentErr
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/oauth/device/verify"+tt.query, nil)
+			if tt.authenticated {
+				claims := &domain.TokenClaims{IDCitizen: 12345, Email: "test@example.com", Role: domain.RoleUser}
+				req = req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, claims))
+			}
+			w := httptest.NewRecorder()
+
+			h := shared.NewOAuth2Handler(&MockOAuth2Service{}, mockService, nil, "", false, logger)
+			admin.GetDeviceVerification(h)(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+
+			if tt.wantStatusCode == http.StatusOK {
+				var resp response.DeviceVerificationResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.ClientID != "cli" || resp.UserCode != "BCDF-GHJK" {
+					t.Errorf("DeviceVerificationResponse = %+v", resp)
+				}
+				if resp.ConsentRequired != tt.wantConsentRequired {
+					t.Errorf("ConsentRequired = %v, want %v", resp.ConsentRequired, tt.wantConsentRequired)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyDeviceHandler(t *testing.T) {
+	logger := zap.NewNop()
+
+	tests := []struct {
+		name           string
+		body           string
+		authenticated  bool
+		verifyErr      error
+		wantStatusCode int
+		wantApprove    bool
+	}{
+		{name: "approve", body: `{"user_code":"BCDF-GHJK","approve":true}`, authenticated: true, wantStatusCode: http.StatusOK, wantApprove: true},
+		{name: "deny", body: `{"user_code":"BCDF-GHJK","approve":false}`, authenticated: true, wantStatusCode: http.StatusOK},
+		{name: "unauthenticated", body: `{"user_code":"BCDF-GHJK","approve":true}`, wantStatusCode: http.StatusUnauthorized},
+		{name: "missing user code", body: `{"approve":true}`, authenticated: true, wantStatusCode: http.StatusBadRequest},
+		{name: "invalid JSON", body: `{invalid`, authenticated: true, wantStatusCode: http.StatusBadRequest},
+		{name: "expired user code", body: `{"user_code":"BCDF-GHJK","approve":true}`, authenticated: true, verifyErr: domainerrors.ErrInvalidUserCode, wantStatusCode: http.StatusBadRequest, wantApprove: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockDeviceAuthorizationService{
+				VerifyFunc: func(ctx context.Context, userCode string, idCitizen int, approve bool) error {
+					if idCitizen != 12345 {
+						t.Errorf("Verify() idCitizen = %v, want 12345", idCitizen)
+					}
+					if approve != tt.wantApprove {
+						t.Errorf("Verify() approve = %v, want %v", approve, tt.wantApprove)
+					}
+					return tt.verifyErr
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/oauth/device/verify", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.authenticated {
+				claims := &domain.TokenClaims{IDCitizen: 12345, Email: "test@example.com", Role: domain.RoleUser}
+				req = req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, claims))
+			}
+			w := httptest.NewRecorder()
+
+			h := shared.NewOAuth2Handler(&MockOAuth2Service{}, mockService, nil, "", false, logger)
+			admin.VerifyDevice(h)(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Errorf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+		})
+	}
+}
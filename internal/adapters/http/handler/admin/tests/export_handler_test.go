@@ -0,0 +1,238 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	admin "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/admin"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func newExportRequest(target string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	claims := &domain.TokenClaims{IDCitizen: 999, Role: domain.RoleAdmin}
+	return req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, claims))
+}
+
+func TestExportUsersHandler(t *testing.T) {
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	users := []*domain.User{
+		{ID: "user-1", IDCitizen: 1, Email: "a@example.com", Name: "Ana", Role: domain.RoleUser, Status: domain.UserStatusActive, CreatedAt: createdAt, UpdatedAt: createdAt},
+		{ID: "user-2", IDCitizen: 2, Email: "b@example.com", Name: "=HYPERLINK(\"x\")", Role: domain.RoleAdmin, Status: domain.UserStatusSuspended, CreatedAt: createdAt, UpdatedAt: createdAt},
+	}
+
+	tests := []struct {
+		name            string
+		target          string
+		users           []*domain.User
+		serviceErr      error
+		wantFilter      domain.UserExportFilter
+		wantStatusCode  int
+		wantContentType string
+		wantBody        string
+		wantCode        string
+	}{
+		{
+			name:            "csv export",
+			target:          "/admin/users/export?role=USER&created_after=2026-01-01T00:00:00Z",
+			users:           users,
+			wantFilter:      domain.UserExportFilter{Role: domain.RoleUser, CreatedAfter: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+			wantStatusCode:  http.StatusOK,
+			wantContentType: "text/csv; charset=utf-8",
+			wantBody: "id,id_citizen,email,name,role,status,created_at,updated_at\n" +
+				"user-1,1,a@example.com,Ana,USER,ACTIVE,2026-01-02T03:04:05Z,2026-01-02T03:04:05Z\n" +
+				"user-2,2,b@example.com,\"'=HYPERLINK(\"\"x\"\")\",ADMIN,SUSPENDED,2026-01-02T03:04:05Z,2026-01-02T03:04:05Z\n",
+		},
+		{
+			name:            "empty csv export has the header row",
+			target:          "/admin/users/export?status=ANONYMIZED",
+			wantFilter:      domain.UserExportFilter{Status: domain.UserStatusAnonymized},
+			wantStatusCode:  http.StatusOK,
+			wantContentType: "text/csv; charset=utf-8",
+			wantBody:        "id,id_citizen,email,name,role,status,created_at,updated_at\n",
+		},
+		{
+			name:            "ndjson export",
+			target:          "/admin/users/export?format=ndjson",
+			users:           users[:1],
+			wantStatusCode:  http.StatusOK,
+			wantContentType: "application/x-ndjson",
+			wantBody: `{"id":"user-1","id_citizen":1,"email":"a@example.com","name":"Ana","role":"USER","status":"ACTIVE",` +
+				`"created_at":"2026-01-02T03:04:05Z","updated_at":"2026-01-02T03:04:05Z"}` + "\n",
+		},
+		{
+			name:           "unknown format",
+			target:         "/admin/users/export?format=xml",
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "INVALID_EXPORT_FILTER",
+		},
+		{
+			name:           "invalid time",
+			target:         "/admin/users/export?created_before=yesterday",
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "INVALID_EXPORT_FILTER",
+		},
+		{
+			name:           "filter rejected by the service",
+			target:         "/admin/users/export?role=ROOT",
+			wantFilter:     domain.UserExportFilter{Role: "ROOT"},
+			serviceErr:     domainerrors.ErrInvalidExportFilter,
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "INVALID_EXPORT_FILTER",
+		},
+		{
+			name:           "failure before the first record",
+			target:         "/admin/users/export",
+			serviceErr:     domainerrors.ErrInternal,
+			wantStatusCode: http.StatusInternalServerError,
+			wantCode:       "INTERNAL_SERVER_ERROR",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockExportService{
+				ExportUsersFunc: func(ctx context.Context, filter domain.UserExportFilter, actor string, fn func(*domain.User) error) error {
+					if filter != tt.wantFilter {
+						t.Errorf("ExportUsers() filter = %+v, want %+v", filter, tt.wantFilter)
+					}
+					if actor != "admin:999" {
+						t.Errorf("ExportUsers() actor = %v, want admin:999", actor)
+					}
+					for _, user := range tt.users {
+						if err := fn(user); err != nil {
+							return err
+						}
+					}
+					return tt.serviceErr
+				},
+			}
+			w := httptest.NewRecorder()
+
+			admin.ExportUsers(shared.NewAdminExportHandler(mockService, zap.NewNop()))(w, newExportRequest(tt.target))
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+
+			if tt.wantCode != "" {
+				var resp response.ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("Error code = %v, want %v", resp.Code, tt.wantCode)
+				}
+				return
+			}
+
+			if got := w.Header().Get("Content-Type"); got != tt.wantContentType {
+				t.Errorf("Content-Type = %v, want %v", got, tt.wantContentType)
+			}
+			if got := w.Header().Get("Content-Disposition"); !strings.HasPrefix(got, `attachment; filename="users-`) {
+				t.Errorf("Content-Disposition = %v, want an attachment", got)
+			}
+			if got := w.Body.String(); got != tt.wantBody {
+				t.Errorf("body = %q, want %q", got, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestExportUsersHandler_FailureAfterFirstRecordAborts(t *testing.T) {
+	mockService := &MockExportService{
+		ExportUsersFunc: func(ctx context.Context, filter domain.UserExportFilter, actor string, fn func(*domain.User) error) error {
+			if err := fn(&domain.User{ID: "user-1"}); err != nil {
+				return err
+			}
+			return domainerrors.ErrInternal
+		},
+	}
+	w := httptest.NewRecorder()
+
+	defer func() {
+		if recovered := recover(); recovered != http.ErrAbortHandler {
+			t.Errorf("recovered = %v, want http.ErrAbortHandler", recovered)
+		}
+	}()
+	admin.ExportUsers(shared.NewAdminExportHandler(mockService, zap.NewNop()))(w, newExportRequest("/admin/users/export"))
+	t.Error("ExportUsers() completed the truncated export")
+}
+
+func TestExportAuditLogHandler(t *testing.T) {
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	record := &domain.AuditRecord{
+		ID:        "record-1",
+		Action:    domain.AuditActionUserAnonymized,
+		Actor:     "admin:1",
+		TargetID:  "user-1",
+		Details:   map[string]string{"reason": "request"},
+		CreatedAt: createdAt,
+	}
+	wantFilter := domain.AuditLogFilter{
+		Action:   domain.AuditActionUserAnonymized,
+		Actor:    "admin:1",
+		TargetID: "user-1",
+		Before:   time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name            string
+		format          string
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name:            "csv export",
+			format:          "csv",
+			wantContentType: "text/csv; charset=utf-8",
+			wantBody: "id,action,actor,target_id,details,created_at\n" +
+				"record-1,user.anonymized,admin:1,user-1,\"{\"\"reason\"\":\"\"request\"\"}\",2026-01-02T03:04:05Z\n",
+		},
+		{
+			name:            "ndjson export",
+			format:          "ndjson",
+			wantContentType: "application/x-ndjson",
+			wantBody: `{"id":"record-1","action":"user.anonymized","actor":"admin:1","target_id":"user-1",` +
+				`"details":{"reason":"request"},"created_at":"2026-01-02T03:04:05Z"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockExportService{
+				ExportAuditLogFunc: func(ctx context.Context, filter domain.AuditLogFilter, actor string, fn func(*domain.AuditRecord) error) error {
+					if filter != wantFilter {
+						t.Errorf("ExportAuditLog() filter = %+v, want %+v", filter, wantFilter)
+					}
+					return fn(record)
+				},
+			}
+			target := "/admin/audit-logs/export?format=" + tt.format + "&action=user.anonymized&actor=admin:1&target_id=user-1&before=2026-02-01T00:00:00Z"
+			w := httptest.NewRecorder()
+
+			admin.ExportAuditLog(shared.NewAdminExportHandler(mockService, zap.NewNop()))(w, newExportRequest(target))
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status code = %v, want %v", w.Code, http.StatusOK)
+			}
+			if got := w.Header().Get("Content-Type"); got != tt.wantContentType {
+				t.Errorf("Content-Type = %v, want %v", got, tt.wantContentType)
+			}
+			if got := w.Body.String(); got != tt.wantBody {
+				t.Errorf("body = %q, want %q", got, tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	admin "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/admin"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestIntrospectHandler(t *testing.T) {
+	logger := zap.NewNop()
+
+	activeIntrospection := &domain.TokenIntrospection{
+		Active:    true,
+		Subject:   "12345",
+		Email:     "test@example.com",
+		Role:      domain.RoleUser,
+		TokenType: domain.TokenTypeAccess,
+		RateLimit: &domain.RateLimitStatus{Limit: 100, Used: 40, ResetAt: time.Now().Add(30 * time.Second)},
+	}
+
+	tests := []struct {
+		name           string
+		newRequest     func() *http.Request
+		introspection  *domain.TokenIntrospection
+		introspectErr  error
+		wantStatusCode int
+		wantActive     bool
+		wantRemaining  int
+	}{
+		{
+			name: "form request with active token",
+			newRequest: func() *http.Request {
+				form := url.Values{"token": {"user-token"}, "client_id": {"gateway"}, "client_secret": {"secret"}}
+				req := httptest.NewRequest(http.MethodPost, "/oauth/introspect", strings.NewReader(form.Encode()))
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				return req
+			},
+			introspection:  activeIntrospection,
+			wantStatusCode: http.StatusOK,
+			wantActive:     true,
+			wantRemaining:  60,
+		},
+		{
+			name: "JSON request with basic auth",
+			newRequest: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/oauth/introspect", bytes.NewBufferString(`{"token":"user-token"}`))
+				req.Header.Set("Content-Type", "application/json")
+				req.SetBasicAuth("gateway", "secret")
+				return req
+			},
+			introspection:  activeIntrospection,
+			wantStatusCode: http.StatusOK,
+			wantActive:     true,
+			wantRemaining:  60,
+		},
+		{
+			name: "inactive token",
+			newRequest: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/oauth/introspect", bytes.NewBufferString(`{"token":"expired","client_id":"gateway","client_secret":"secret"}`))
+				req.Header.Set("Content-Type", "application/json")
+				return req
+			},
+			introspection:  &domain.TokenIntrospection{Active: false},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name: "missing token",
+			newRequest: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/oauth/introspect", bytes.NewBufferString(`{"client_id":"gateway","client_secret":"secret"}`))
+				req.Header.Set("Content-Type", "application/json")
+				return req
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "invalid client credentials",
+			newRequest: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/oauth/introspect", bytes.NewBufferString(`{"token":"user-token","client_id":"gateway","client_secret":"wrong"}`))
+				req.Header.Set("Content-Type", "application/json")
+				return req
+			},
+			introspectErr:  domainerrors.ErrInvalidCredentials,
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name: "client secret in both basic auth and the body",
+			newRequest: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/oauth/introspect", bytes.NewBufferString(`{"token":"user-token","client_secret":"secret"}`))
+				req.Header.Set("Content-Type", "application/json")
+				req.SetBasicAuth("gateway", "secret")
+				return req
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "invalid JSON",
+			newRequest: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/oauth/introspect", bytes.NewBufferString(`{invalid`))
+				req.Header.Set("Content-Type", "application/json")
+				return req
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockIntrospectionService{
+				IntrospectFunc: func(ctx context.Context, clientID, clientSecret, token string) (*domain.TokenIntrospection, error) {
+					if clientID != "gateway" {
+						t.Errorf("Introspect() clientID = %v, want gateway", clientID)
+					}
+					return tt.introspection, tt.introspectErr
+				},
+			}
+
+			w := httptest.NewRecorder()
+			h := shared.NewIntrospectionHandler(mockService, logger)
+			admin.Introspect(h)(w, tt.newRequest())
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+			if tt.wantStatusCode != http.StatusOK {
+				return
+			}
+
+			var resp response.IntrospectionResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Active != tt.wantActive {
+				t.Errorf("Active = %v, want %v", resp.Active, tt.wantActive)
+			}
+			if !tt.wantActive {
+				if resp.RateLimit != nil || resp.Sub != "" {
+					t.Errorf("inactive response should be empty, got %+v", resp)
+				}
+				return
+			}
+			if resp.RateLimit == nil || resp.RateLimit.Limit != 100 || resp.RateLimit.Remaining != tt.wantRemaining {
+				t.Errorf("RateLimit = %+v, want limit 100 and %d remaining", resp.RateLimit, tt.wantRemaining)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 	"time"
 
@@ -30,7 +31,7 @@ func TestListOAuthClientsHandler(t *testing.T) {
 		{
 			name: "successful list with multiple clients",
 			mockSetup: func(m *MockOAuth2Service) {
-				m.ListClientsFunc = func(ctx context.Context) ([]*domain.OAuthClient, error) {
+				m.ListClientsFunc = func(ctx context.Context, tags []string) ([]*domain.OAuthClient, error) {
 					return []*domain.OAuthClient{
 						{
 							ID:          "client-1",
@@ -76,7 +77,7 @@ func TestListOAuthClientsHandler(t *testing.T) {
 		{
 			name: "successful list with empty result",
 			mockSetup: func(m *MockOAuth2Service) {
-				m.ListClientsFunc = func(ctx context.Context) ([]*domain.OAuthClient, error) {
+				m.ListClientsFunc = func(ctx context.Context, tags []string) ([]*domain.OAuthClient, error) {
 					return []*domain.OAuthClient{}, nil
 				}
 			},
@@ -95,7 +96,7 @@ func TestListOAuthClientsHandler(t *testing.T) {
 		{
 			name: "successful list with single client",
 			mockSetup: func(m *MockOAuth2Service) {
-				m.ListClientsFunc = func(ctx context.Context) ([]*domain.OAuthClient, error) {
+				m.ListClientsFunc = func(ctx context.Context, tags []string) ([]*domain.OAuthClient, error) {
 					return []*domain.OAuthClient{
 						{
 							ID:          "client-single",
@@ -128,7 +129,7 @@ func TestListOAuthClientsHandler(t *testing.T) {
 		{
 			name: "internal server error",
 			mockSetup: func(m *MockOAuth2Service) {
-				m.ListClientsFunc = func(ctx context.Context) ([]*domain.OAuthClient, error) {
+				m.ListClientsFunc = func(ctx context.Context, tags []string) ([]*domain.OAuthClient, error) {
 					return nil, errors.New("database error")
 				}
 			},
@@ -144,10 +145,97 @@ func TestListOAuthClientsHandler(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "list with usage",
+			mockSetup: func(m *MockOAuth2Service) {
+				m.ListClientsFunc = func(ctx context.Context, tags []string) ([]*domain.OAuthClient, error) {
+					return []*domain.OAuthClient{
+						{ID: "client-used", ClientID: "used_client", Active: true},
+						{ID: "client-unused", ClientID: "unused_client", Active: true},
+					}, nil
+				}
+				m.ListClientUsageFunc = func(ctx context.Context) (map[string]*domain.ClientUsage, error) {
+					lastUsedAt := time.Now()
+					return map[string]*domain.ClientUsage{
+						"used_client": {ClientID: "used_client", LastUsedAt: &lastUsedAt, TokensIssued: 42, RecentErrors: 3},
+					}, nil
+				}
+			},
+			wantStatusCode: http.StatusOK,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				var resp []response.OAuthClientResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if len(resp) != 2 || resp[0].Usage == nil || resp[1].Usage == nil {
+					t.Fatalf("response = %+v, want 2 clients with usage", resp)
+				}
+				if resp[0].Usage.TokensIssued != 42 || resp[0].Usage.RecentErrors != 3 || resp[0].Usage.LastUsedAt == nil {
+					t.Errorf("used client usage = %+v, want 42 tokens and 3 errors", resp[0].Usage)
+				}
+				if resp[1].Usage.TokensIssued != 0 || resp[1].Usage.LastUsedAt != nil {
+					t.Errorf("unused client usage = %+v, want none", resp[1].Usage)
+				}
+			},
+		},
+		{
+			name: "usage unavailable",
+			mockSetup: func(m *MockOAuth2Service) {
+				m.ListClientsFunc = func(ctx context.Context, tags []string) ([]*domain.OAuthClient, error) {
+					return []*domain.OAuthClient{{ID: "client-1", ClientID: "test_client_1", Active: true}}, nil
+				}
+				m.ListClientUsageFunc = func(ctx context.Context) (map[string]*domain.ClientUsage, error) {
+					return nil, errors.New("database error")
+				}
+			},
+			wantStatusCode: http.StatusOK,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				var resp []response.OAuthClientResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if len(resp) != 1 || resp[0].Usage != nil {
+					t.Errorf("response = %+v, want the client without usage", resp)
+				}
+			},
+		},
+		{
+			name: "list with lockouts",
+			mockSetup: func(m *MockOAuth2Service) {
+				m.ListClientsFunc = func(ctx context.Context, tags []string) ([]*domain.OAuthClient, error) {
+					return []*domain.OAuthClient{
+						{ID: "client-locked", ClientID: "locked_client", Active: true},
+						{ID: "client-healthy", ClientID: "healthy_client", Active: true},
+					}, nil
+				}
+				m.ListClientLockoutsFunc = func(ctx context.Context, clientIDs []string) (map[string]*domain.ClientLockout, error) {
+					lockedUntil := time.Now().Add(15 * time.Minute)
+					return map[string]*domain.ClientLockout{
+						"locked_client": {ClientID: "locked_client", RecentFailures: 10, LockedUntil: &lockedUntil},
+					}, nil
+				}
+			},
+			wantStatusCode: http.StatusOK,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				var resp []response.OAuthClientResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if len(resp) != 2 || resp[0].Lockout == nil {
+					t.Fatalf("response = %+v, want the locked out client with its lockout", resp)
+				}
+				if resp[0].Lockout.RecentAuthFailures != 10 || resp[0].Lockout.LockedUntil == nil {
+					t.Errorf("locked client lockout = %+v, want 10 failures and locked out", resp[0].Lockout)
+				}
+				if resp[1].Lockout != nil {
+					t.Errorf("healthy client lockout = %+v, want none", resp[1].Lockout)
+				}
+			},
+		},
 		{
 			name: "list with inactive clients",
 			mockSetup: func(m *MockOAuth2Service) {
-				m.ListClientsFunc = func(ctx context.Context) ([]*domain.OAuthClient, error) {
+				m.ListClientsFunc = func(ctx context.Context, tags []string) ([]*domain.OAuthClient, error) {
 					return []*domain.OAuthClient{
 						{
 							ID:          "client-active",
@@ -220,3 +308,34 @@ func TestListOAuthClientsHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestListOAuthClientsHandler_FilterByTags(t *testing.T) {
+	var gotTags []string
+	mockOAuth2Service := &MockOAuth2Service{
+		ListClientsFunc: func(ctx context.Context, tags []string) ([]*domain.OAuthClient, error) {
+			gotTags = tags
+			return []*domain.OAuthClient{
+				{ID: "client-1", ClientID: "payments", Name: "Payments", Tags: []string{"team:payments", "env:staging"}, Active: true},
+			}, nil
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/oauth-clients?tag=team:payments&tag=env:staging", nil)
+	w := httptest.NewRecorder()
+	admin.ListOAuthClients(shared.NewAdminOAuthClientsHandler(mockOAuth2Service, zap.NewNop()))(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status code = %v, want %v", w.Code, http.StatusOK)
+	}
+	if want := []string{"team:payments", "env:staging"}; !reflect.DeepEqual(gotTags, want) {
+		t.Errorf("ListClients() tags = %v, want %v", gotTags, want)
+	}
+
+	var resp []response.OAuthClientResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp) != 1 || !reflect.DeepEqual(resp[0].Tags, []string{"team:payments", "env:staging"}) {
+		t.Errorf("response = %+v, want the client with its tags", resp)
+	}
+}
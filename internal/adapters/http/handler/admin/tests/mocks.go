@@ -2,46 +2,106 @@ package tests
 
 import (
 	"context"
+	"time"
 
 	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
 )
 
 // OAuth2ServiceInterface defines the interface for OAuth2 operations used by handlers
 type OAuth2ServiceInterface interface {
-	CreateClient(ctx context.Context, clientID, clientSecret, name, description string, scopes []string) (*domain.OAuthClient, error)
-	ListClients(ctx context.Context) ([]*domain.OAuthClient, error)
-	ClientCredentials(ctx context.Context, clientID, clientSecret string) (string, int64, error)
+	CreateClient(ctx context.Context, clientID, clientSecret, name, description string, scopes, grantTypes, redirectURIs, tags []string, secretExpiresAt *time.Time) (*domain.OAuthClient, error)
+	ListClients(ctx context.Context, tags []string) ([]*domain.OAuthClient, error)
+	ClientCredentials(ctx context.Context, clientID, clientSecret string, signed *domain.SignedClientRequest, certThumbprint string) (string, time.Time, error)
 }
 
 // MockOAuth2Service is a mock implementation of OAuth2Service
 type MockOAuth2Service struct {
-	CreateClientFunc      func(ctx context.Context, clientID, clientSecret, name, description string, scopes []string) (*domain.OAuthClient, error)
-	ListClientsFunc       func(ctx context.Context) ([]*domain.OAuthClient, error)
-	ClientCredentialsFunc func(ctx context.Context, clientID, clientSecret string) (string, int64, error)
+	CreateClientFunc        func(ctx context.Context, clientID, clientSecret, name, description string, scopes, grantTypes, redirectURIs, tags []string, secretExpiresAt *time.Time) (*domain.OAuthClient, error)
+	ListClientsFunc         func(ctx context.Context, tags []string) ([]*domain.OAuthClient, error)
+	ClientCredentialsFunc   func(ctx context.Context, clientID, clientSecret string, signed *domain.SignedClientRequest, certThumbprint string) (string, time.Time, error)
+	UpdateClientFunc        func(ctx context.Context, id string, expectedVersion int, name, description *string, scopes []string, tokenProfile *domain.TokenProfile, grantTypes, redirectURIs, tags []string) (*domain.OAuthClient, error)
+	AddRedirectURIFunc      func(ctx context.Context, id, redirectURI string) (*domain.OAuthClient, error)
+	RemoveRedirectURIFunc   func(ctx context.Context, id, redirectURI string) (*domain.OAuthClient, error)
+	SetSignedRequestsFunc   func(ctx context.Context, id string, required bool) (*domain.OAuthClient, error)
+	RevokeClientTokensFunc  func(ctx context.Context, id string) (int, error)
+	ListClientUsageFunc     func(ctx context.Context) (map[string]*domain.ClientUsage, error)
+	ListClientLockoutsFunc  func(ctx context.Context, clientIDs []string) (map[string]*domain.ClientLockout, error)
+	SetSecretExpiryFunc     func(ctx context.Context, id string, expiresAt *time.Time) (*domain.OAuthClient, error)
+	ListExpiringSecretsFunc func(ctx context.Context, within time.Duration) ([]*domain.OAuthClient, error)
 }
 
-func (m *MockOAuth2Service) CreateClient(ctx context.Context, clientID, clientSecret, name, description string, scopes []string) (*domain.OAuthClient, error) {
+func (m *MockOAuth2Service) CreateClient(ctx context.Context, clientID, clientSecret, name, description string, scopes, grantTypes, redirectURIs, tags []string, secretExpiresAt *time.Time) (*domain.OAuthClient, error) {
 	if m.CreateClientFunc != nil {
-		return m.CreateClientFunc(ctx, clientID, clientSecret, name, description, scopes)
+		return m.CreateClientFunc(ctx, clientID, clientSecret, name, description, scopes, grantTypes, redirectURIs, tags, secretExpiresAt)
 	}
 	return nil, nil
 }
 
-func (m *MockOAuth2Service) ListClients(ctx context.Context) ([]*domain.OAuthClient, error) {
+func (m *MockOAuth2Service) ListClients(ctx context.Context, tags []string) ([]*domain.OAuthClient, error) {
 	if m.ListClientsFunc != nil {
-		return m.ListClientsFunc(ctx)
+		return m.ListClientsFunc(ctx, tags)
 	}
 	return nil, nil
 }
 
-func (m *MockOAuth2Service) ClientCredentials(ctx context.Context, clientID, clientSecret string) (string, int64, error) {
+func (m *MockOAuth2Service) UpdateClient(ctx context.Context, id string, expectedVersion int, name, description *string, scopes []string, tokenProfile *domain.TokenProfile, grantTypes, redirectURIs, tags []string) (*domain.OAuthClient, error) {
+	if m.UpdateClientFunc != nil {
+		return m.UpdateClientFunc(ctx, id, expectedVersion, name, description, scopes, tokenProfile, grantTypes, redirectURIs, tags)
+	}
+	return nil, nil
+}
+
+func (m *MockOAuth2Service) ClientCredentials(ctx context.Context, clientID, clientSecret string, signed *domain.SignedClientRequest, certThumbprint string) (string, time.Time, error) {
 	if m.ClientCredentialsFunc != nil {
-		return m.ClientCredentialsFunc(ctx, clientID, clientSecret)
+		return m.ClientCredentialsFunc(ctx, clientID, clientSecret, signed, certThumbprint)
+	}
+	return "", time.Time{}, nil
+}
+
+func (m *MockOAuth2Service) AddRedirectURI(ctx context.Context, id, redirectURI string) (*domain.OAuthClient, error) {
+	if m.AddRedirectURIFunc != nil {
+		return m.AddRedirectURIFunc(ctx, id, redirectURI)
+	}
+	return nil, nil
+}
+
+func (m *MockOAuth2Service) RemoveRedirectURI(ctx context.Context, id, redirectURI string) (*domain.OAuthClient, error) {
+	if m.RemoveRedirectURIFunc != nil {
+		return m.RemoveRedirectURIFunc(ctx, id, redirectURI)
+	}
+	return nil, nil
+}
+
+func (m *MockOAuth2Service) ResolveRedirectURI(ctx context.Context, clientID, redirectURI string) (string, error) {
+	return redirectURI, nil
+}
+
+func (m *MockOAuth2Service) SetSignedRequests(ctx context.Context, id string, required bool) (*domain.OAuthClient, error) {
+	if m.SetSignedRequestsFunc != nil {
+		return m.SetSignedRequestsFunc(ctx, id, required)
+	}
+	return nil, nil
+}
+
+func (m *MockOAuth2Service) SetSecretExpiry(ctx context.Context, id string, expiresAt *time.Time) (*domain.OAuthClient, error) {
+	if m.SetSecretExpiryFunc != nil {
+		return m.SetSecretExpiryFunc(ctx, id, expiresAt)
+	}
+	return nil, nil
+}
+
+func (m *MockOAuth2Service) ListExpiringSecrets(ctx context.Context, within time.Duration) ([]*domain.OAuthClient, error) {
+	if m.ListExpiringSecretsFunc != nil {
+		return m.ListExpiringSecretsFunc(ctx, within)
 	}
-	return "", 0, nil
+	return []*domain.OAuthClient{}, nil
 }
 
 // Additional stub methods to satisfy the OAuth2ServiceInterface used by handlers
+func (m *MockOAuth2Service) AuthenticateClient(ctx context.Context, clientID, clientSecret string) (*domain.OAuthClient, error) {
+	return &domain.OAuthClient{ClientID: clientID, Name: "Test", Active: true}, nil
+}
+
 func (m *MockOAuth2Service) ValidateAccessToken(ctx context.Context, tokenString string) (*domain.OAuthTokenClaims, error) {
 	return &domain.OAuthTokenClaims{ClientID: "client-123", Scopes: []string{"read"}, TokenID: "jti", IssuedAt: 0, ExpireAt: 0, Type: "client_credentials"}, nil
 }
@@ -53,3 +113,361 @@ func (m *MockOAuth2Service) GetClient(ctx context.Context, id string) (*domain.O
 func (m *MockOAuth2Service) DeleteClient(ctx context.Context, id string) error {
 	return nil
 }
+
+func (m *MockOAuth2Service) RevokeClientTokens(ctx context.Context, id string) (int, error) {
+	if m.RevokeClientTokensFunc != nil {
+		return m.RevokeClientTokensFunc(ctx, id)
+	}
+	return 0, nil
+}
+
+func (m *MockOAuth2Service) ListClientUsage(ctx context.Context) (map[string]*domain.ClientUsage, error) {
+	if m.ListClientUsageFunc != nil {
+		return m.ListClientUsageFunc(ctx)
+	}
+	return map[string]*domain.ClientUsage{}, nil
+}
+
+func (m *MockOAuth2Service) ListClientLockouts(ctx context.Context, clientIDs []string) (map[string]*domain.ClientLockout, error) {
+	if m.ListClientLockoutsFunc != nil {
+		return m.ListClientLockoutsFunc(ctx, clientIDs)
+	}
+	return map[string]*domain.ClientLockout{}, nil
+}
+
+// MockDeviceAuthorizationService is a mock implementation of services.DeviceAuthorizationServiceInterface
+type MockDeviceAuthorizationService struct {
+	RequestDeviceCodeFunc func(ctx context.Context, clientID, clientSecret string, scopes []string) (*domain.DeviceAuthorization, error)
+	GetByUserCodeFunc     func(ctx context.Context, userCode string) (*domain.DeviceAuthorization, error)
+	RequiresConsentFunc   func(ctx context.Context, auth *domain.DeviceAuthorization, idCitizen int) (bool, error)
+	VerifyFunc            func(ctx context.Context, userCode string, idCitizen int, approve bool) error
+	PollTokenFunc         func(ctx context.Context, clientID, clientSecret, deviceCode string) (*domain.TokenPair, error)
+}
+
+func (m *MockDeviceAuthorizationService) RequestDeviceCode(ctx context.Context, clientID, clientSecret string, scopes []string) (*domain.DeviceAuthorization, error) {
+	if m.RequestDeviceCodeFunc != nil {
+		return m.RequestDeviceCodeFunc(ctx, clientID, clientSecret, scopes)
+	}
+	return nil, nil
+}
+
+func (m *MockDeviceAuthorizationService) GetByUserCode(ctx context.Context, userCode string) (*domain.DeviceAuthorization, error) {
+	if m.GetByUserCodeFunc != nil {
+		return m.GetByUserCodeFunc(ctx, userCode)
+	}
+	return nil, nil
+}
+
+func (m *MockDeviceAuthorizationService) RequiresConsent(ctx context.Context, auth *domain.DeviceAuthorization, idCitizen int) (bool, error) {
+	if m.RequiresConsentFunc != nil {
+		return m.RequiresConsentFunc(ctx, auth, idCitizen)
+	}
+	// Default behavior: user has not consented yet
+	return true, nil
+}
+
+func (m *MockDeviceAuthorizationService) Verify(ctx context.Context, userCode string, idCitizen int, approve bool) error {
+	if m.VerifyFunc != nil {
+		return m.VerifyFunc(ctx, userCode, idCitizen, approve)
+	}
+	return nil
+}
+
+func (m *MockDeviceAuthorizationService) PollToken(ctx context.Context, clientID, clientSecret, deviceCode string) (*domain.TokenPair, error) {
+	if m.PollTokenFunc != nil {
+		return m.PollTokenFunc(ctx, clientID, clientSecret, deviceCode)
+	}
+	return nil, nil
+}
+
+func (m *MockDeviceAuthorizationService) VerificationURI() string {
+	return "https://auth.example.com/device"
+}
+
+// MockPasswordGrantService is a mock implementation of services.PasswordGrantServiceInterface
+type MockPasswordGrantService struct {
+	PasswordGrantFunc func(ctx context.Context, clientID, clientSecret, username, password string) (*domain.TokenPair, error)
+}
+
+func (m *MockPasswordGrantService) PasswordGrant(ctx context.Context, clientID, clientSecret, username, password string) (*domain.TokenPair, error) {
+	if m.PasswordGrantFunc != nil {
+		return m.PasswordGrantFunc(ctx, clientID, clientSecret, username, password)
+	}
+	return nil, nil
+}
+
+// MockScopeService is a mock implementation of services.ScopeServiceInterface
+type MockScopeService struct {
+	ListScopesFunc  func(ctx context.Context) ([]*domain.Scope, error)
+	CreateScopeFunc func(ctx context.Context, name, description string) (*domain.Scope, error)
+	UpdateScopeFunc func(ctx context.Context, name, description string) (*domain.Scope, error)
+	DeleteScopeFunc func(ctx context.Context, name string) error
+}
+
+func (m *MockScopeService) ListScopes(ctx context.Context) ([]*domain.Scope, error) {
+	if m.ListScopesFunc != nil {
+		return m.ListScopesFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *MockScopeService) CreateScope(ctx context.Context, name, description string) (*domain.Scope, error) {
+	if m.CreateScopeFunc != nil {
+		return m.CreateScopeFunc(ctx, name, description)
+	}
+	return nil, nil
+}
+
+func (m *MockScopeService) UpdateScope(ctx context.Context, name, description string) (*domain.Scope, error) {
+	if m.UpdateScopeFunc != nil {
+		return m.UpdateScopeFunc(ctx, name, description)
+	}
+	return nil, nil
+}
+
+func (m *MockScopeService) DeleteScope(ctx context.Context, name string) error {
+	if m.DeleteScopeFunc != nil {
+		return m.DeleteScopeFunc(ctx, name)
+	}
+	return nil
+}
+
+// MockIntrospectionService is a mock implementation of services.IntrospectionServiceInterface
+type MockIntrospectionService struct {
+	IntrospectFunc func(ctx context.Context, clientID, clientSecret, token string) (*domain.TokenIntrospection, error)
+}
+
+func (m *MockIntrospectionService) Introspect(ctx context.Context, clientID, clientSecret, token string) (*domain.TokenIntrospection, error) {
+	if m.IntrospectFunc != nil {
+		return m.IntrospectFunc(ctx, clientID, clientSecret, token)
+	}
+	return &domain.TokenIntrospection{Active: false}, nil
+}
+
+// MockAnonymizationService is a mock implementation of services.AnonymizationServiceInterface
+type MockAnonymizationService struct {
+	AnonymizeUserFunc func(ctx context.Context, userID, actor string) (*domain.UserPublic, error)
+}
+
+func (m *MockAnonymizationService) AnonymizeUser(ctx context.Context, userID, actor string) (*domain.UserPublic, error) {
+	if m.AnonymizeUserFunc != nil {
+		return m.AnonymizeUserFunc(ctx, userID, actor)
+	}
+	return nil, nil
+}
+
+// MockUserMergeService is a mock implementation of services.UserMergeServiceInterface
+type MockUserMergeService struct {
+	MergeUsersFunc func(ctx context.Context, survivorID, mergedID, actor string, dryRun bool) (*domain.UserMerge, error)
+}
+
+func (m *MockUserMergeService) MergeUsers(ctx context.Context, survivorID, mergedID, actor string, dryRun bool) (*domain.UserMerge, error) {
+	if m.MergeUsersFunc != nil {
+		return m.MergeUsersFunc(ctx, survivorID, mergedID, actor, dryRun)
+	}
+	return nil, nil
+}
+
+// MockUserMetadataService is a mock implementation of services.UserMetadataServiceInterface
+type MockUserMetadataService struct {
+	UpdateUserMetadataFunc func(ctx context.Context, userID string, expectedVersion int, changes domain.UserMetadata, actor string) (*domain.UserPublic, error)
+	UpdateOwnMetadataFunc  func(ctx context.Context, idCitizen int, changes domain.UserMetadata) (*domain.UserPublic, error)
+}
+
+func (m *MockUserMetadataService) UpdateUserMetadata(ctx context.Context, userID string, expectedVersion int, changes domain.UserMetadata, actor string) (*domain.UserPublic, error) {
+	if m.UpdateUserMetadataFunc != nil {
+		return m.UpdateUserMetadataFunc(ctx, userID, expectedVersion, changes, actor)
+	}
+	return nil, nil
+}
+
+func (m *MockUserMetadataService) UpdateOwnMetadata(ctx context.Context, idCitizen int, changes domain.UserMetadata) (*domain.UserPublic, error) {
+	if m.UpdateOwnMetadataFunc != nil {
+		return m.UpdateOwnMetadataFunc(ctx, idCitizen, changes)
+	}
+	return nil, nil
+}
+
+// MockRegistrationApprovalService is a mock implementation of services.RegistrationApprovalServiceInterface
+type MockRegistrationApprovalService struct {
+	ListUsersFunc   func(ctx context.Context, status domain.UserStatus, limit, offset int) ([]*domain.User, error)
+	ApproveUserFunc func(ctx context.Context, userID, actor string) (*domain.User, error)
+	RejectUserFunc  func(ctx context.Context, userID, reason, actor string) (*domain.User, error)
+}
+
+func (m *MockRegistrationApprovalService) ListUsers(ctx context.Context, status domain.UserStatus, limit, offset int) ([]*domain.User, error) {
+	if m.ListUsersFunc != nil {
+		return m.ListUsersFunc(ctx, status, limit, offset)
+	}
+	return nil, nil
+}
+
+func (m *MockRegistrationApprovalService) ApproveUser(ctx context.Context, userID, actor string) (*domain.User, error) {
+	if m.ApproveUserFunc != nil {
+		return m.ApproveUserFunc(ctx, userID, actor)
+	}
+	return nil, nil
+}
+
+func (m *MockRegistrationApprovalService) RejectUser(ctx context.Context, userID, reason, actor string) (*domain.User, error) {
+	if m.RejectUserFunc != nil {
+		return m.RejectUserFunc(ctx, userID, reason, actor)
+	}
+	return nil, nil
+}
+
+// MockQuotaService is a mock implementation of services.QuotaServiceInterface
+type MockQuotaService struct {
+	ListQuotasFunc  func(ctx context.Context) ([]*domain.IssuanceQuota, error)
+	GetQuotaFunc    func(ctx context.Context, subjectType, subjectID string) (*domain.QuotaUsage, error)
+	SetQuotaFunc    func(ctx context.Context, quota *domain.IssuanceQuota, actor string) (*domain.IssuanceQuota, error)
+	DeleteQuotaFunc func(ctx context.Context, subjectType, subjectID, actor string) error
+}
+
+func (m *MockQuotaService) ListQuotas(ctx context.Context) ([]*domain.IssuanceQuota, error) {
+	if m.ListQuotasFunc != nil {
+		return m.ListQuotasFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *MockQuotaService) GetQuota(ctx context.Context, subjectType, subjectID string) (*domain.QuotaUsage, error) {
+	if m.GetQuotaFunc != nil {
+		return m.GetQuotaFunc(ctx, subjectType, subjectID)
+	}
+	return nil, nil
+}
+
+func (m *MockQuotaService) SetQuota(ctx context.Context, quota *domain.IssuanceQuota, actor string) (*domain.IssuanceQuota, error) {
+	if m.SetQuotaFunc != nil {
+		return m.SetQuotaFunc(ctx, quota, actor)
+	}
+	return quota, nil
+}
+
+func (m *MockQuotaService) DeleteQuota(ctx context.Context, subjectType, subjectID, actor string) error {
+	if m.DeleteQuotaFunc != nil {
+		return m.DeleteQuotaFunc(ctx, subjectType, subjectID, actor)
+	}
+	return nil
+}
+
+// MockExportService is a mock implementation of services.ExportServiceInterface
+type MockExportService struct {
+	ExportUsersFunc    func(ctx context.Context, filter domain.UserExportFilter, actor string, fn func(*domain.User) error) error
+	ExportAuditLogFunc func(ctx context.Context, filter domain.AuditLogFilter, actor string, fn func(*domain.AuditRecord) error) error
+}
+
+func (m *MockExportService) ExportUsers(ctx context.Context, filter domain.UserExportFilter, actor string, fn func(*domain.User) error) error {
+	if m.ExportUsersFunc != nil {
+		return m.ExportUsersFunc(ctx, filter, actor, fn)
+	}
+	return nil
+}
+
+func (m *MockExportService) ExportAuditLog(ctx context.Context, filter domain.AuditLogFilter, actor string, fn func(*domain.AuditRecord) error) error {
+	if m.ExportAuditLogFunc != nil {
+		return m.ExportAuditLogFunc(ctx, filter, actor, fn)
+	}
+	return nil
+}
+
+// MockUserEmailService is a mock implementation of services.UserEmailServiceInterface
+type MockUserEmailService struct {
+	ListEmailsFunc     func(ctx context.Context, idCitizen int) ([]*domain.UserEmail, error)
+	AddEmailFunc       func(ctx context.Context, idCitizen int, email string) (*domain.UserEmail, error)
+	VerifyEmailFunc    func(ctx context.Context, idCitizen int, id, code string) (*domain.UserEmail, error)
+	RemoveEmailFunc    func(ctx context.Context, idCitizen int, id string) error
+	ListUserEmailsFunc func(ctx context.Context, userID string) ([]*domain.UserEmail, error)
+}
+
+func (m *MockUserEmailService) ListEmails(ctx context.Context, idCitizen int) ([]*domain.UserEmail, error) {
+	if m.ListEmailsFunc != nil {
+		return m.ListEmailsFunc(ctx, idCitizen)
+	}
+	return nil, nil
+}
+
+func (m *MockUserEmailService) AddEmail(ctx context.Context, idCitizen int, email string) (*domain.UserEmail, error) {
+	if m.AddEmailFunc != nil {
+		return m.AddEmailFunc(ctx, idCitizen, email)
+	}
+	return nil, nil
+}
+
+func (m *MockUserEmailService) VerifyEmail(ctx context.Context, idCitizen int, id, code string) (*domain.UserEmail, error) {
+	if m.VerifyEmailFunc != nil {
+		return m.VerifyEmailFunc(ctx, idCitizen, id, code)
+	}
+	return nil, nil
+}
+
+func (m *MockUserEmailService) RemoveEmail(ctx context.Context, idCitizen int, id string) error {
+	if m.RemoveEmailFunc != nil {
+		return m.RemoveEmailFunc(ctx, idCitizen, id)
+	}
+	return nil
+}
+
+func (m *MockUserEmailService) ListUserEmails(ctx context.Context, userID string) ([]*domain.UserEmail, error) {
+	if m.ListUserEmailsFunc != nil {
+		return m.ListUserEmailsFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+// MockRoleService is a mock implementation of services.RoleServiceInterface
+type MockRoleService struct {
+	ChangeUserRoleFunc func(ctx context.Context, userID string, expectedVersion int, role domain.Role, actor string) (*domain.UserPublic, error)
+}
+
+func (m *MockRoleService) ChangeUserRole(ctx context.Context, userID string, expectedVersion int, role domain.Role, actor string) (*domain.UserPublic, error) {
+	if m.ChangeUserRoleFunc != nil {
+		return m.ChangeUserRoleFunc(ctx, userID, expectedVersion, role, actor)
+	}
+	return nil, nil
+}
+
+// MockServiceAccountService is a mock implementation of services.ServiceAccountServiceInterface
+type MockServiceAccountService struct {
+	CreateServiceAccountFunc func(ctx context.Context, name string, idCitizen int, actor string) (*domain.User, error)
+}
+
+func (m *MockServiceAccountService) CreateServiceAccount(ctx context.Context, name string, idCitizen int, actor string) (*domain.User, error) {
+	if m.CreateServiceAccountFunc != nil {
+		return m.CreateServiceAccountFunc(ctx, name, idCitizen, actor)
+	}
+	return nil, nil
+}
+
+// MockUserProvisioningService is a mock implementation of services.UserProvisioningServiceInterface
+type MockUserProvisioningService struct {
+	CreateUserFunc              func(ctx context.Context, email, name string, idCitizen int, actor string) (*domain.User, string, error)
+	ChangeTemporaryPasswordFunc func(ctx context.Context, email, temporaryPassword, newPassword string) error
+}
+
+func (m *MockUserProvisioningService) CreateUser(ctx context.Context, email, name string, idCitizen int, actor string) (*domain.User, string, error) {
+	if m.CreateUserFunc != nil {
+		return m.CreateUserFunc(ctx, email, name, idCitizen, actor)
+	}
+	return nil, "", nil
+}
+
+func (m *MockUserProvisioningService) ChangeTemporaryPassword(ctx context.Context, email, temporaryPassword, newPassword string) error {
+	if m.ChangeTemporaryPasswordFunc != nil {
+		return m.ChangeTemporaryPasswordFunc(ctx, email, temporaryPassword, newPassword)
+	}
+	return nil
+}
+
+// MockTokenDebugService is a mock implementation of services.TokenDebugServiceInterface
+type MockTokenDebugService struct {
+	DecodeTokenFunc func(ctx context.Context, token string) (*domain.TokenDebugReport, error)
+}
+
+func (m *MockTokenDebugService) DecodeToken(ctx context.Context, token string) (*domain.TokenDebugReport, error) {
+	if m.DecodeTokenFunc != nil {
+		return m.DecodeTokenFunc(ctx, token)
+	}
+	return nil, nil
+}
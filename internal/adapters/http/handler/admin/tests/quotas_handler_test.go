@@ -0,0 +1,182 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	admin "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/admin"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestGetQuotaHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		getErr         error
+		wantStatusCode int
+		wantCode       string
+	}{
+		{name: "default quota with usage", wantStatusCode: http.StatusOK},
+		{name: "user not found", getErr: domainerrors.ErrUserNotFound, wantStatusCode: http.StatusNotFound, wantCode: "USER_NOT_FOUND"},
+		{name: "unknown subject type", getErr: domainerrors.ErrInvalidQuota, wantStatusCode: http.StatusBadRequest, wantCode: "INVALID_QUOTA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockQuotaService{
+				GetQuotaFunc: func(ctx context.Context, subjectType, subjectID string) (*domain.QuotaUsage, error) {
+					if tt.getErr != nil {
+						return nil, tt.getErr
+					}
+					return &domain.QuotaUsage{
+						Quota:          domain.IssuanceQuota{SubjectType: subjectType, SubjectID: subjectID, MaxTokensPerHour: 20, MaxActiveSessions: 3},
+						Default:        true,
+						TokensIssued:   7,
+						ResetAt:        time.Now().Add(time.Hour),
+						ActiveSessions: 2,
+					}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/quotas/user/user-123", nil)
+			req = mux.SetURLVars(req, map[string]string{"subject_type": "user", "subject_id": "user-123"})
+			w := httptest.NewRecorder()
+
+			admin.GetQuota(shared.NewQuotasHandler(mockService, zap.NewNop()))(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+
+			if tt.wantCode != "" {
+				var resp response.ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("error code = %v, want %v", resp.Code, tt.wantCode)
+				}
+				return
+			}
+
+			var resp response.QuotaUsageResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if !resp.Default || resp.Quota.MaxActiveSessions != 3 || resp.TokensIssued != 7 || resp.ActiveSessions != 2 || resp.Quota.UpdatedAt != nil {
+				t.Errorf("response = %+v", resp)
+			}
+		})
+	}
+}
+
+func TestUpdateQuotaHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		noClaims       bool
+		setErr         error
+		wantStatusCode int
+		wantCode       string
+	}{
+		{name: "successful update", body: `{"max_tokens_per_hour":500}`, wantStatusCode: http.StatusOK},
+		{name: "missing claims", body: `{}`, noClaims: true, wantStatusCode: http.StatusUnauthorized, wantCode: "UNAUTHORIZED"},
+		{name: "invalid body", body: `{`, wantStatusCode: http.StatusBadRequest, wantCode: "INVALID_REQUEST_BODY"},
+		{name: "invalid quota", body: `{"max_tokens_per_hour":-1}`, setErr: domainerrors.ErrInvalidQuota, wantStatusCode: http.StatusBadRequest, wantCode: "INVALID_QUOTA"},
+		{name: "client not found", body: `{"max_tokens_per_hour":500}`, setErr: domainerrors.ErrClientNotFound, wantStatusCode: http.StatusNotFound, wantCode: "NOT_FOUND"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockQuotaService{
+				SetQuotaFunc: func(ctx context.Context, quota *domain.IssuanceQuota, actor string) (*domain.IssuanceQuota, error) {
+					if quota.SubjectType != domain.QuotaSubjectClient || quota.SubjectID != "client-123" || actor != "admin:999" {
+						t.Errorf("SetQuota() quota = %+v, actor = %v", quota, actor)
+					}
+					if tt.setErr != nil {
+						return nil, tt.setErr
+					}
+					quota.UpdatedAt = time.Now()
+					return quota, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPut, "/admin/quotas/client/client-123", strings.NewReader(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"subject_type": "client", "subject_id": "client-123"})
+			if !tt.noClaims {
+				claims := &domain.TokenClaims{IDCitizen: 999, Role: domain.RoleAdmin}
+				req = req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, claims))
+			}
+			w := httptest.NewRecorder()
+
+			admin.UpdateQuota(shared.NewQuotasHandler(mockService, zap.NewNop()))(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+
+			if tt.wantCode != "" {
+				var resp response.ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("error code = %v, want %v", resp.Code, tt.wantCode)
+				}
+				return
+			}
+
+			var resp response.QuotaResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.MaxTokensPerHour != 500 || resp.UpdatedAt == nil {
+				t.Errorf("response = %+v", resp)
+			}
+		})
+	}
+}
+
+func TestDeleteQuotaHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		deleteErr      error
+		wantStatusCode int
+	}{
+		{name: "successful deletion", wantStatusCode: http.StatusNoContent},
+		{name: "no override", deleteErr: domainerrors.ErrQuotaNotFound, wantStatusCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockQuotaService{
+				DeleteQuotaFunc: func(ctx context.Context, subjectType, subjectID, actor string) error {
+					return tt.deleteErr
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, "/admin/quotas/user/user-123", nil)
+			req = mux.SetURLVars(req, map[string]string{"subject_type": "user", "subject_id": "user-123"})
+			claims := &domain.TokenClaims{IDCitizen: 999, Role: domain.RoleAdmin}
+			req = req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, claims))
+			w := httptest.NewRecorder()
+
+			admin.DeleteQuota(shared.NewQuotasHandler(mockService, zap.NewNop()))(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Errorf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	admin "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/admin"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestRedirectURIsHandlers(t *testing.T) {
+	const redirectURI = "https://app.example.com/callback?x=1"
+
+	tests := []struct {
+		name             string
+		method           string
+		body             string
+		query            string
+		serviceErr       error
+		wantStatusCode   int
+		wantCode         string
+		wantRedirectURIs []string
+	}{
+		{name: "add", method: http.MethodPost, body: `{"redirect_uri":"` + redirectURI + `"}`, wantStatusCode: http.StatusOK, wantRedirectURIs: []string{redirectURI}},
+		{name: "add invalid", method: http.MethodPost, body: `{"redirect_uri":"` + redirectURI + `"}`, serviceErr: domainerrors.ErrInvalidRedirectURI, wantStatusCode: http.StatusBadRequest, wantCode: "INVALID_REDIRECT_URI"},
+		{name: "add without redirect uri", method: http.MethodPost, body: `{}`, wantStatusCode: http.StatusBadRequest, wantCode: "REQUIRED_FIELD"},
+		{name: "add invalid body", method: http.MethodPost, body: `{`, wantStatusCode: http.StatusBadRequest, wantCode: "INVALID_REQUEST_BODY"},
+		{name: "remove", method: http.MethodDelete, query: "?redirect_uri=" + url.QueryEscape(redirectURI), wantStatusCode: http.StatusOK, wantRedirectURIs: []string{}},
+		{name: "remove unregistered", method: http.MethodDelete, query: "?redirect_uri=" + url.QueryEscape(redirectURI), serviceErr: domainerrors.ErrRedirectURINotFound, wantStatusCode: http.StatusNotFound, wantCode: "REDIRECT_URI_NOT_FOUND"},
+		{name: "remove without redirect uri", method: http.MethodDelete, wantStatusCode: http.StatusBadRequest, wantCode: "REQUIRED_FIELD"},
+		{name: "client not found", method: http.MethodPost, body: `{"redirect_uri":"` + redirectURI + `"}`, serviceErr: domainerrors.ErrClientNotFound, wantStatusCode: http.StatusNotFound, wantCode: "NOT_FOUND"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			update := func(ctx context.Context, id, uri string) (*domain.OAuthClient, error) {
+				if id != "id-123" || uri != redirectURI {
+					t.Errorf("redirect uri update id = %v, redirect uri = %v, want id-123, %v", id, uri, redirectURI)
+				}
+				if tt.serviceErr != nil {
+					return nil, tt.serviceErr
+				}
+				return &domain.OAuthClient{ID: id, ClientID: "client-123", Name: "Test", Active: true, RedirectURIs: tt.wantRedirectURIs}, nil
+			}
+			mockService := &MockOAuth2Service{AddRedirectURIFunc: update, RemoveRedirectURIFunc: update}
+			h := shared.NewAdminOAuthClientsHandler(mockService, zap.NewNop())
+			handler := admin.RemoveRedirectURI(h)
+			if tt.method == http.MethodPost {
+				handler = admin.AddRedirectURI(h)
+			}
+
+			req := httptest.NewRequest(tt.method, "/admin/oauth-clients/id-123/redirect-uris"+tt.query, strings.NewReader(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"id": "id-123"})
+			w := httptest.NewRecorder()
+			handler(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+
+			if tt.wantCode != "" {
+				var resp response.ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("Error code = %v, want %v", resp.Code, tt.wantCode)
+				}
+				return
+			}
+
+			var resp response.OAuthClientResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(resp.RedirectURIs) != len(tt.wantRedirectURIs) {
+				t.Errorf("RedirectURIs = %v, want %v", resp.RedirectURIs, tt.wantRedirectURIs)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	admin "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/admin"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestListUsersHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		listErr        error
+		wantStatus     domain.UserStatus
+		wantLimit      int
+		wantOffset     int
+		wantStatusCode int
+		wantCode       string
+	}{
+		{name: "pending by default", wantStatus: domain.UserStatusPendingApproval, wantLimit: 50, wantStatusCode: http.StatusOK},
+		{name: "pending alias", query: "?status=pending&limit=10&offset=20", wantStatus: domain.UserStatusPendingApproval, wantLimit: 10, wantOffset: 20, wantStatusCode: http.StatusOK},
+		{name: "other status", query: "?status=REJECTED", wantStatus: domain.UserStatusRejected, wantLimit: 50, wantStatusCode: http.StatusOK},
+		{name: "limit capped", query: "?limit=1000", wantStatus: domain.UserStatusPendingApproval, wantLimit: 200, wantStatusCode: http.StatusOK},
+		{name: "unknown status", query: "?status=deleted", wantStatusCode: http.StatusBadRequest, wantCode: "INVALID_USER_LIST_FILTER"},
+		{name: "invalid limit", query: "?limit=ten", wantStatusCode: http.StatusBadRequest, wantCode: "INVALID_USER_LIST_FILTER"},
+		{name: "negative offset", query: "?offset=-1", wantStatusCode: http.StatusBadRequest, wantCode: "INVALID_USER_LIST_FILTER"},
+		{name: "service error", listErr: domainerrors.ErrInternal, wantStatus: domain.UserStatusPendingApproval, wantLimit: 50, wantStatusCode: http.StatusInternalServerError, wantCode: "INTERNAL_SERVER_ERROR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockRegistrationApprovalService{
+				ListUsersFunc: func(ctx context.Context, status domain.UserStatus, limit, offset int) ([]*domain.User, error) {
+					if status != tt.wantStatus || limit != tt.wantLimit || offset != tt.wantOffset {
+						t.Errorf("ListUsers(%q, %d, %d), want (%q, %d, %d)", status, limit, offset, tt.wantStatus, tt.wantLimit, tt.wantOffset)
+					}
+					if tt.listErr != nil {
+						return nil, tt.listErr
+					}
+					return []*domain.User{{ID: "user-123", IDCitizen: 12345, Status: status}}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/users"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			admin.ListUsers(shared.NewRegistrationApprovalHandler(mockService, zap.NewNop()))(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+
+			if tt.wantCode != "" {
+				var resp response.ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("error code = %v, want %v", resp.Code, tt.wantCode)
+				}
+				return
+			}
+
+			var resp []response.AdminUserResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(resp) != 1 || resp[0].ID != "user-123" || resp[0].Status != tt.wantStatus {
+				t.Errorf("response = %+v, want user-123 with status %q", resp, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestApproveUserHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		noClaims       bool
+		approveErr     error
+		wantStatusCode int
+		wantCode       string
+	}{
+		{name: "successful approval", wantStatusCode: http.StatusOK},
+		{name: "missing claims", noClaims: true, wantStatusCode: http.StatusUnauthorized, wantCode: "UNAUTHORIZED"},
+		{name: "user not found", approveErr: domainerrors.ErrUserNotFound, wantStatusCode: http.StatusNotFound, wantCode: "USER_NOT_FOUND"},
+		{name: "not pending", approveErr: domainerrors.ErrUserNotPendingApproval, wantStatusCode: http.StatusConflict, wantCode: "USER_NOT_PENDING_APPROVAL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockRegistrationApprovalService{
+				ApproveUserFunc: func(ctx context.Context, userID, actor string) (*domain.User, error) {
+					if userID != "user-123" || actor != "admin:999" {
+						t.Errorf("ApproveUser() userID = %v, actor = %v, want user-123, admin:999", userID, actor)
+					}
+					if tt.approveErr != nil {
+						return nil, tt.approveErr
+					}
+					return &domain.User{ID: userID, IDCitizen: 12345, Status: domain.UserStatusActive}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/users/user-123/approve", nil)
+			req = mux.SetURLVars(req, map[string]string{"id": "user-123"})
+			if !tt.noClaims {
+				claims := &domain.TokenClaims{IDCitizen: 999, Role: domain.RoleAdmin}
+				req = req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, claims))
+			}
+			w := httptest.NewRecorder()
+
+			admin.ApproveUser(shared.NewRegistrationApprovalHandler(mockService, zap.NewNop()))(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+
+			if tt.wantCode != "" {
+				var resp response.ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("error code = %v, want %v", resp.Code, tt.wantCode)
+				}
+				return
+			}
+
+			var resp response.AdminUserResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Status != domain.UserStatusActive {
+				t.Errorf("status = %q, want %q", resp.Status, domain.UserStatusActive)
+			}
+		})
+	}
+}
+
+func TestRejectUserHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		rejectErr      error
+		wantReason     string
+		wantStatusCode int
+		wantCode       string
+	}{
+		{name: "rejection with reason", body: `{"reason":" unverified identity "}`, wantReason: "unverified identity", wantStatusCode: http.StatusOK},
+		{name: "rejection without body", wantStatusCode: http.StatusOK},
+		{name: "invalid json body", body: `{"reason":`, wantStatusCode: http.StatusBadRequest, wantCode: "INVALID_REQUEST_BODY"},
+		{name: "not pending", rejectErr: domainerrors.ErrUserNotPendingApproval, wantStatusCode: http.StatusConflict, wantCode: "USER_NOT_PENDING_APPROVAL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockRegistrationApprovalService{
+				RejectUserFunc: func(ctx context.Context, userID, reason, actor string) (*domain.User, error) {
+					if userID != "user-123" || reason != tt.wantReason || actor != "admin:999" {
+						t.Errorf("RejectUser() = %v, %q, %v, want user-123, %q, admin:999", userID, reason, actor, tt.wantReason)
+					}
+					if tt.rejectErr != nil {
+						return nil, tt.rejectErr
+					}
+					return &domain.User{ID: userID, IDCitizen: 12345, Status: domain.UserStatusRejected}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/users/user-123/reject", bytes.NewBufferString(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"id": "user-123"})
+			claims := &domain.TokenClaims{IDCitizen: 999, Role: domain.RoleAdmin}
+			req = req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, claims))
+			w := httptest.NewRecorder()
+
+			admin.RejectUser(shared.NewRegistrationApprovalHandler(mockService, zap.NewNop()))(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+
+			if tt.wantCode != "" {
+				var resp response.ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("error code = %v, want %v", resp.Code, tt.wantCode)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	admin "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/admin"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestRequestSigningKeyHandlers(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		serviceErr     error
+		wantRequired   bool
+		wantStatusCode int
+		wantCode       string
+	}{
+		{name: "rotate key", method: http.MethodPost, wantRequired: true, wantStatusCode: http.StatusOK},
+		{name: "delete key", method: http.MethodDelete, wantStatusCode: http.StatusOK},
+		{name: "client not found", method: http.MethodPost, wantRequired: true, serviceErr: domainerrors.ErrClientNotFound, wantStatusCode: http.StatusNotFound, wantCode: "NOT_FOUND"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockOAuth2Service{
+				SetSignedRequestsFunc: func(ctx context.Context, id string, required bool) (*domain.OAuthClient, error) {
+					if id != "id-123" || required != tt.wantRequired {
+						t.Errorf("SetSignedRequests() id = %v, required = %v, want id-123, %v", id, required, tt.wantRequired)
+					}
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					client := &domain.OAuthClient{ID: id, ClientID: "client-123", Name: "Test", Active: true, RequireSignedRequests: required}
+					if required {
+						client.RequestSigningKey = "signing-key"
+					}
+					return client, nil
+				},
+			}
+			h := shared.NewAdminOAuthClientsHandler(mockService, zap.NewNop())
+			handler := admin.DeleteRequestSigningKey(h)
+			if tt.method == http.MethodPost {
+				handler = admin.RotateRequestSigningKey(h)
+			}
+
+			req := httptest.NewRequest(tt.method, "/admin/oauth-clients/id-123/request-signing-key", nil)
+			req = mux.SetURLVars(req, map[string]string{"id": "id-123"})
+			w := httptest.NewRecorder()
+			handler(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+
+			if tt.wantCode != "" {
+				var resp response.ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("Error code = %v, want %v", resp.Code, tt.wantCode)
+				}
+				return
+			}
+
+			var resp response.OAuthClientResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.RequireSignedRequests != tt.wantRequired {
+				t.Errorf("RequireSignedRequests = %v, want %v", resp.RequireSignedRequests, tt.wantRequired)
+			}
+			if (resp.RequestSigningKey != "") != tt.wantRequired {
+				t.Errorf("RequestSigningKey = %q, want it only when signed requests are required", resp.RequestSigningKey)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	admin "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/admin"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+)
+
+func TestRevokeClientTokensHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		revoked        int
+		serviceErr     error
+		wantStatusCode int
+		wantCode       string
+	}{
+		{name: "tokens revoked", revoked: 3, wantStatusCode: http.StatusOK},
+		{name: "no tokens to revoke", wantStatusCode: http.StatusOK},
+		{name: "client not found", serviceErr: domainerrors.ErrClientNotFound, wantStatusCode: http.StatusNotFound, wantCode: "NOT_FOUND"},
+		{name: "service error", serviceErr: domainerrors.ErrInternal, wantStatusCode: http.StatusInternalServerError, wantCode: "INTERNAL_SERVER_ERROR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockOAuth2Service{
+				RevokeClientTokensFunc: func(ctx context.Context, id string) (int, error) {
+					if id != "id-123" {
+						t.Errorf("RevokeClientTokens() id = %v, want id-123", id)
+					}
+					return tt.revoked, tt.serviceErr
+				},
+			}
+			handler := admin.RevokeClientTokens(shared.NewAdminOAuthClientsHandler(mockService, zap.NewNop()))
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/oauth-clients/id-123/revoke-tokens", nil)
+			req = mux.SetURLVars(req, map[string]string{"id": "id-123"})
+			w := httptest.NewRecorder()
+			handler(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+
+			if tt.wantCode != "" {
+				var resp response.ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("Error code = %v, want %v", resp.Code, tt.wantCode)
+				}
+				return
+			}
+
+			var resp response.RevokedClientTokensResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.ID != "id-123" || resp.RevokedTokens != tt.revoked {
+				t.Errorf("response = %+v, want id-123 with %d revoked tokens", resp, tt.revoked)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	admin "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/admin"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+)
+
+func TestListRoutesHandler(t *testing.T) {
+	routes := []response.RouteResponse{
+		{Path: "/api/auth/login", Methods: []string{http.MethodPost}, Auth: "none", RateLimit: "login"},
+		{Path: "/api/auth/me", Methods: []string{http.MethodGet}, Auth: "user", RateLimit: "user"},
+		{Path: "/api/auth/oauth/scopes", Methods: []string{http.MethodGet}, Auth: "none", RateLimit: "none"},
+	}
+	handler := shared.NewAdminRoutesHandler(func() ([]response.RouteResponse, error) {
+		return routes, nil
+	}, zap.NewNop())
+
+	tests := []struct {
+		name      string
+		query     string
+		wantPaths []string
+	}{
+		{name: "all routes", query: "", wantPaths: []string{"/api/auth/login", "/api/auth/me", "/api/auth/oauth/scopes"}},
+		{name: "public endpoints", query: "?auth=none", wantPaths: []string{"/api/auth/login", "/api/auth/oauth/scopes"}},
+		{name: "no match", query: "?auth=admin", wantPaths: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/admin/routes"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			admin.ListRoutes(handler)(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status code = %v, want %v", w.Code, http.StatusOK)
+			}
+			var resp response.RoutesResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(resp.Routes) != len(tt.wantPaths) {
+				t.Fatalf("routes = %+v, want paths %v", resp.Routes, tt.wantPaths)
+			}
+			for i, route := range resp.Routes {
+				if route.Path != tt.wantPaths[i] {
+					t.Errorf("routes[%d].Path = %v, want %v", i, route.Path, tt.wantPaths[i])
+				}
+			}
+		})
+	}
+}
+
+func TestListRoutesHandler_Error(t *testing.T) {
+	handler := shared.NewAdminRoutesHandler(func() ([]response.RouteResponse, error) {
+		return nil, errors.New("walk failed")
+	}, zap.NewNop())
+
+	w := httptest.NewRecorder()
+	admin.ListRoutes(handler)(w, httptest.NewRequest(http.MethodGet, "/admin/routes", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status code = %v, want %v", w.Code, http.StatusInternalServerError)
+	}
+}
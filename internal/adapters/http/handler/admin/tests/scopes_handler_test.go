@@ -0,0 +1,164 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	admin "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/admin"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestListScopesHandler(t *testing.T) {
+	mockService := &MockScopeService{
+		ListScopesFunc: func(ctx context.Context) ([]*domain.Scope, error) {
+			return []*domain.Scope{
+				{Name: "read", Description: "Read access", System: true},
+				{Name: "users:write", Description: "Manage users"},
+			}, nil
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/oauth/scopes", nil)
+	w := httptest.NewRecorder()
+
+	admin.ListScopes(shared.NewScopesHandler(mockService, zap.NewNop()))(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status code = %v, want %v", w.Code, http.StatusOK)
+	}
+
+	var resp []response.ScopeResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp) != 2 || !resp[0].System || resp[1].Name != "users:write" {
+		t.Errorf("ListScopes() = %+v", resp)
+	}
+}
+
+func TestCreateScopeHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		createErr      error
+		wantStatusCode int
+		wantCode       string
+	}{
+		{name: "successful creation", body: `{"name":"users:read","description":"Read users"}`, wantStatusCode: http.StatusCreated},
+		{name: "missing name", body: `{"description":"Read users"}`, wantStatusCode: http.StatusBadRequest, wantCode: "REQUIRED_FIELD"},
+		{name: "invalid JSON", body: `{invalid`, wantStatusCode: http.StatusBadRequest, wantCode: "INVALID_REQUEST_BODY"},
+		{name: "invalid name", body: `{"name":"Users Read"}`, createErr: domainerrors.ErrInvalidScopeName, wantStatusCode: http.StatusBadRequest, wantCode: "INVALID_SCOPE_NAME"},
+		{name: "already exists", body: `{"name":"read"}`, createErr: domainerrors.ErrScopeAlreadyExists, wantStatusCode: http.StatusConflict, wantCode: "SCOPE_ALREADY_EXISTS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockScopeService{
+				CreateScopeFunc: func(ctx context.Context, name, description string) (*domain.Scope, error) {
+					if tt.createErr != nil {
+						return nil, tt.createErr
+					}
+					return &domain.Scope{Name: name, Description: description}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/scopes", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			admin.CreateScope(shared.NewScopesHandler(mockService, zap.NewNop()))(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+			if tt.wantCode != "" {
+				var resp response.ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("Error code = %v, want %v", resp.Code, tt.wantCode)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateScopeHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		updateErr      error
+		wantStatusCode int
+	}{
+		{name: "successful update", wantStatusCode: http.StatusOK},
+		{name: "system scope", updateErr: domainerrors.ErrSystemScope, wantStatusCode: http.StatusForbidden},
+		{name: "not found", updateErr: domainerrors.ErrScopeNotFound, wantStatusCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockScopeService{
+				UpdateScopeFunc: func(ctx context.Context, name, description string) (*domain.Scope, error) {
+					if name != "users:read" {
+						t.Errorf("UpdateScope() name = %v, want users:read", name)
+					}
+					if tt.updateErr != nil {
+						return nil, tt.updateErr
+					}
+					return &domain.Scope{Name: name, Description: description}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPut, "/admin/scopes/users:read", bytes.NewBufferString(`{"description":"Read users"}`))
+			req = mux.SetURLVars(req, map[string]string{"name": "users:read"})
+			w := httptest.NewRecorder()
+
+			admin.UpdateScope(shared.NewScopesHandler(mockService, zap.NewNop()))(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Errorf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+		})
+	}
+}
+
+func TestDeleteScopeHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		deleteErr      error
+		wantStatusCode int
+	}{
+		{name: "successful deletion", wantStatusCode: http.StatusNoContent},
+		{name: "scope in use", deleteErr: domainerrors.ErrScopeInUse, wantStatusCode: http.StatusConflict},
+		{name: "system scope", deleteErr: domainerrors.ErrSystemScope, wantStatusCode: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockScopeService{
+				DeleteScopeFunc: func(ctx context.Context, name string) error {
+					return tt.deleteErr
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, "/admin/scopes/users:read", nil)
+			req = mux.SetURLVars(req, map[string]string{"name": "users:read"})
+			w := httptest.NewRecorder()
+
+			admin.DeleteScope(shared.NewScopesHandler(mockService, zap.NewNop()))(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Errorf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	admin "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/admin"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestCreateServiceAccountHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		noClaims       bool
+		createErr      error
+		wantStatusCode int
+		wantCode       string
+	}{
+		{name: "successful creation", body: `{"name":" CI pipeline ","id_citizen":90001}`, wantStatusCode: http.StatusCreated},
+		{name: "missing claims", body: `{"name":"CI pipeline","id_citizen":90001}`, noClaims: true, wantStatusCode: http.StatusUnauthorized, wantCode: "UNAUTHORIZED"},
+		{name: "invalid body", body: `{`, wantStatusCode: http.StatusBadRequest, wantCode: "INVALID_REQUEST_BODY"},
+		{name: "missing name", body: `{"name":"  ","id_citizen":90001}`, wantStatusCode: http.StatusBadRequest, wantCode: "REQUIRED_FIELD"},
+		{name: "missing citizen ID", body: `{"name":"CI pipeline"}`, wantStatusCode: http.StatusBadRequest, wantCode: "REQUIRED_FIELD"},
+		{name: "citizen ID taken", body: `{"name":"CI pipeline","id_citizen":90001}`, createErr: domainerrors.ErrUserAlreadyExists, wantStatusCode: http.StatusConflict, wantCode: "USER_ALREADY_EXISTS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockServiceAccountService{
+				CreateServiceAccountFunc: func(ctx context.Context, name string, idCitizen int, actor string) (*domain.User, error) {
+					if name != "CI pipeline" || idCitizen != 90001 || actor != "admin:999" {
+						t.Errorf("CreateServiceAccount(%q, %d, %q), want (CI pipeline, 90001, admin:999)", name, idCitizen, actor)
+					}
+					if tt.createErr != nil {
+						return nil, tt.createErr
+					}
+					return domain.NewServiceAccount(name, idCitizen)
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/service-accounts", bytes.NewBufferString(tt.body))
+			if !tt.noClaims {
+				claims := &domain.TokenClaims{IDCitizen: 999, Role: domain.RoleAdmin}
+				req = req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, claims))
+			}
+			w := httptest.NewRecorder()
+
+			admin.CreateServiceAccount(shared.NewServiceAccountsHandler(mockService, zap.NewNop()))(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+
+			if tt.wantCode != "" {
+				var resp response.ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("error code = %v, want %v", resp.Code, tt.wantCode)
+				}
+				return
+			}
+
+			var resp response.AdminUserResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Type != domain.UserTypeServiceAccount || resp.IDCitizen != 90001 {
+				t.Errorf("response = %+v, want service account 90001", resp)
+			}
+		})
+	}
+}
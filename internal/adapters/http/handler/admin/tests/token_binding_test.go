@@ -0,0 +1,134 @@
+package tests
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	admin "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/admin"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// newClientCertificate creates a self-signed client certificate
+func newClientCertificate(t *testing.T) *x509.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "svc"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func TestTokenHandler_CertificateBoundTokens(t *testing.T) {
+	cert := newClientCertificate(t)
+	escapedPEM := url.QueryEscape(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})))
+
+	tests := []struct {
+		name           string
+		certHeader     string
+		trustProxy     bool
+		peerCert       bool
+		headers        map[string]string
+		wantThumbprint string
+		wantStatusCode int
+	}{
+		{name: "without certificate", wantStatusCode: http.StatusOK},
+		{name: "mTLS connection", peerCert: true, wantThumbprint: domain.CertificateThumbprint(cert), wantStatusCode: http.StatusOK},
+		{
+			name:           "certificate forwarded by the proxy",
+			certHeader:     "X-Client-Cert",
+			trustProxy:     true,
+			headers:        map[string]string{"X-Client-Cert": escapedPEM},
+			wantThumbprint: domain.CertificateThumbprint(cert),
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "forwarded certificate without trusted proxy",
+			certHeader:     "X-Client-Cert",
+			headers:        map[string]string{"X-Client-Cert": escapedPEM},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "mTLS connection ignores the forwarded certificate",
+			certHeader:     "X-Client-Cert",
+			peerCert:       true,
+			headers:        map[string]string{"X-Client-Cert": "not-a-certificate"},
+			wantThumbprint: domain.CertificateThumbprint(cert),
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "forwarded header not configured",
+			headers:        map[string]string{"X-Client-Cert": escapedPEM},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "malformed forwarded certificate",
+			certHeader:     "X-Client-Cert",
+			trustProxy:     true,
+			headers:        map[string]string{"X-Client-Cert": "not-a-certificate"},
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotThumbprint string
+			mockOAuth2Service := &MockOAuth2Service{
+				ClientCredentialsFunc: func(ctx context.Context, clientID, clientSecret string, signed *domain.SignedClientRequest, certThumbprint string) (string, time.Time, error) {
+					gotThumbprint = certThumbprint
+					return "access_token", time.Now().Add(15 * time.Minute), nil
+				},
+			}
+
+			form := url.Values{"client_id": {"svc"}, "client_secret": {"secret"}, "grant_type": {"client_credentials"}}
+			req := httptest.NewRequest(http.MethodPost, "/auth/token", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			if tt.peerCert {
+				req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+			}
+			w := httptest.NewRecorder()
+
+			admin.Token(shared.NewOAuth2Handler(mockOAuth2Service, nil, nil, tt.certHeader, tt.trustProxy, zap.NewNop()))(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Errorf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+			if gotThumbprint != tt.wantThumbprint {
+				t.Errorf("certificate thumbprint = %q, want %q", gotThumbprint, tt.wantThumbprint)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package tests
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -10,6 +11,7 @@ import (
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -18,10 +20,12 @@ import (
 	admin "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/admin"
 	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
 	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
 )
 
 func TestTokenHandler(t *testing.T) {
 	logger := zap.NewNop()
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
 
 	tests := []struct {
 		name           string
@@ -42,8 +46,8 @@ func TestTokenHandler(t *testing.T) {
 				GrantType:    "client_credentials",
 			},
 			mockSetup: func(m *MockOAuth2Service) {
-				m.ClientCredentialsFunc = func(ctx context.Context, clientID, clientSecret string) (string, int64, error) {
-					return "access_token_123", 3600, nil
+				m.ClientCredentialsFunc = func(ctx context.Context, clientID, clientSecret string, signed *domain.SignedClientRequest, certThumbprint string) (string, time.Time, error) {
+					return "access_token_123", expiresAt, nil
 				}
 			},
 			wantStatusCode: http.StatusOK,
@@ -59,9 +63,13 @@ func TestTokenHandler(t *testing.T) {
 				if resp.TokenType != "Bearer" {
 					t.Errorf("TokenType = %v, want Bearer", resp.TokenType)
 				}
-				if resp.ExpiresIn != 3600 {
+				// expires_in counts down from the absolute expiration
+				if resp.ExpiresIn < 3599 || resp.ExpiresIn > 3600 {
 					t.Errorf("ExpiresIn = %v, want 3600", resp.ExpiresIn)
 				}
+				if !resp.ExpiresAt.Equal(expiresAt) {
+					t.Errorf("ExpiresAt = %v, want %v", resp.ExpiresAt, expiresAt)
+				}
 			},
 		},
 		{
@@ -73,8 +81,8 @@ func TestTokenHandler(t *testing.T) {
 				"grant_type":    []string{"client_credentials"},
 			},
 			mockSetup: func(m *MockOAuth2Service) {
-				m.ClientCredentialsFunc = func(ctx context.Context, clientID, clientSecret string) (string, int64, error) {
-					return "access_token_456", 7200, nil
+				m.ClientCredentialsFunc = func(ctx context.Context, clientID, clientSecret string, signed *domain.SignedClientRequest, certThumbprint string) (string, time.Time, error) {
+					return "access_token_456", time.Now().Add(2 * time.Hour), nil
 				}
 			},
 			wantStatusCode: http.StatusOK,
@@ -97,12 +105,12 @@ func TestTokenHandler(t *testing.T) {
 			wantStatusCode: http.StatusBadRequest,
 			wantError:      true,
 			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
-				var resp response.ErrorResponse
+				var resp response.OAuthErrorResponse
 				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
 					t.Fatalf("failed to decode response: %v", err)
 				}
-				if resp.Code != "INVALID_REQUEST_BODY" {
-					t.Errorf("Error code = %v, want INVALID_REQUEST_BODY", resp.Code)
+				if resp.Error != "invalid_request" {
+					t.Errorf("error = %v, want invalid_request", resp.Error)
 				}
 			},
 		},
@@ -118,12 +126,12 @@ func TestTokenHandler(t *testing.T) {
 			wantStatusCode: http.StatusBadRequest,
 			wantError:      true,
 			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
-				var resp response.ErrorResponse
+				var resp response.OAuthErrorResponse
 				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
 					t.Fatalf("failed to decode response: %v", err)
 				}
-				if resp.Code != "REQUIRED_FIELD" {
-					t.Errorf("Error code = %v, want REQUIRED_FIELD", resp.Code)
+				if resp.Error != "invalid_request" {
+					t.Errorf("error = %v, want invalid_request", resp.Error)
 				}
 			},
 		},
@@ -139,12 +147,12 @@ func TestTokenHandler(t *testing.T) {
 			wantStatusCode: http.StatusBadRequest,
 			wantError:      true,
 			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
-				var resp response.ErrorResponse
+				var resp response.OAuthErrorResponse
 				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
 					t.Fatalf("failed to decode response: %v", err)
 				}
-				if resp.Code != "REQUIRED_FIELD" {
-					t.Errorf("Error code = %v, want REQUIRED_FIELD", resp.Code)
+				if resp.Error != "invalid_request" {
+					t.Errorf("error = %v, want invalid_request", resp.Error)
 				}
 			},
 		},
@@ -160,12 +168,12 @@ func TestTokenHandler(t *testing.T) {
 			wantStatusCode: http.StatusBadRequest,
 			wantError:      true,
 			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
-				var resp response.ErrorResponse
+				var resp response.OAuthErrorResponse
 				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
 					t.Fatalf("failed to decode response: %v", err)
 				}
-				if resp.Code != "REQUIRED_FIELD" {
-					t.Errorf("Error code = %v, want REQUIRED_FIELD", resp.Code)
+				if resp.Error != "invalid_request" {
+					t.Errorf("error = %v, want invalid_request", resp.Error)
 				}
 			},
 		},
@@ -181,12 +189,12 @@ func TestTokenHandler(t *testing.T) {
 			wantStatusCode: http.StatusBadRequest,
 			wantError:      true,
 			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
-				var resp response.ErrorResponse
+				var resp response.OAuthErrorResponse
 				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
 					t.Fatalf("failed to decode response: %v", err)
 				}
-				if !strings.Contains(resp.Error, "grant_type") {
-					t.Errorf("Error message should mention grant_type")
+				if resp.Error != "unsupported_grant_type" {
+					t.Errorf("error = %v, want unsupported_grant_type", resp.Error)
 				}
 			},
 		},
@@ -199,19 +207,19 @@ func TestTokenHandler(t *testing.T) {
 				GrantType:    "client_credentials",
 			},
 			mockSetup: func(m *MockOAuth2Service) {
-				m.ClientCredentialsFunc = func(ctx context.Context, clientID, clientSecret string) (string, int64, error) {
-					return "", 0, domainerrors.ErrInvalidCredentials
+				m.ClientCredentialsFunc = func(ctx context.Context, clientID, clientSecret string, signed *domain.SignedClientRequest, certThumbprint string) (string, time.Time, error) {
+					return "", time.Time{}, domainerrors.ErrInvalidCredentials
 				}
 			},
 			wantStatusCode: http.StatusUnauthorized,
 			wantError:      true,
 			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
-				var resp response.ErrorResponse
+				var resp response.OAuthErrorResponse
 				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
 					t.Fatalf("failed to decode response: %v", err)
 				}
-				if resp.Code != "INVALID_CREDENTIALS" {
-					t.Errorf("Error code = %v, want INVALID_CREDENTIALS", resp.Code)
+				if resp.Error != "invalid_client" {
+					t.Errorf("error = %v, want invalid_client", resp.Error)
 				}
 			},
 		},
@@ -224,19 +232,19 @@ func TestTokenHandler(t *testing.T) {
 				GrantType:    "client_credentials",
 			},
 			mockSetup: func(m *MockOAuth2Service) {
-				m.ClientCredentialsFunc = func(ctx context.Context, clientID, clientSecret string) (string, int64, error) {
-					return "", 0, errors.New("database error")
+				m.ClientCredentialsFunc = func(ctx context.Context, clientID, clientSecret string, signed *domain.SignedClientRequest, certThumbprint string) (string, time.Time, error) {
+					return "", time.Time{}, errors.New("database error")
 				}
 			},
 			wantStatusCode: http.StatusInternalServerError,
 			wantError:      true,
 			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
-				var resp response.ErrorResponse
+				var resp response.OAuthErrorResponse
 				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
 					t.Fatalf("failed to decode response: %v", err)
 				}
-				if resp.Code != "INTERNAL_SERVER_ERROR" {
-					t.Errorf("Error code = %v, want INTERNAL_SERVER_ERROR", resp.Code)
+				if resp.Error != "server_error" {
+					t.Errorf("error = %v, want server_error", resp.Error)
 				}
 			},
 		},
@@ -271,7 +279,7 @@ func TestTokenHandler(t *testing.T) {
 			// Create response recorder
 			w := httptest.NewRecorder()
 
-			h := shared.NewOAuth2Handler(mockOAuth2Service, logger)
+			h := shared.NewOAuth2Handler(mockOAuth2Service, nil, nil, "", false, logger)
 			handler := admin.Token(h)
 			handler(w, req)
 
@@ -287,3 +295,289 @@ func TestTokenHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestTokenHandler_PasswordGrant(t *testing.T) {
+	logger := zap.NewNop()
+
+	tests := []struct {
+		name           string
+		formData       url.Values
+		grantErr       error
+		wantStatusCode int
+		wantCode       string
+	}{
+		{
+			name:           "allowlisted client receives tokens",
+			formData:       url.Values{"client_id": {"legacy"}, "client_secret": {"secret"}, "grant_type": {"password"}, "username": {"test@example.com"}, "password": {"password123"}},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "missing user credentials",
+			formData:       url.Values{"client_id": {"legacy"}, "client_secret": {"secret"}, "grant_type": {"password"}},
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "invalid_request",
+		},
+		{
+			name:           "grant disabled",
+			formData:       url.Values{"client_id": {"legacy"}, "client_secret": {"secret"}, "grant_type": {"password"}, "username": {"test@example.com"}, "password": {"password123"}},
+			grantErr:       domainerrors.ErrUnsupportedGrantType,
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "unsupported_grant_type",
+		},
+		{
+			name:           "client not allowlisted",
+			formData:       url.Values{"client_id": {"other"}, "client_secret": {"secret"}, "grant_type": {"password"}, "username": {"test@example.com"}, "password": {"password123"}},
+			grantErr:       domainerrors.ErrUnauthorizedClient,
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "unauthorized_client",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockPasswordGrantService{
+				PasswordGrantFunc: func(ctx context.Context, clientID, clientSecret, username, password string) (*domain.TokenPair, error) {
+					if tt.grantErr != nil {
+						return nil, tt.grantErr
+					}
+					return &domain.TokenPair{AccessToken: "access", RefreshToken: "refresh", TokenType: "Bearer", ExpiresIn: 900}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/token", strings.NewReader(tt.formData.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			w := httptest.NewRecorder()
+
+			h := shared.NewOAuth2Handler(&MockOAuth2Service{}, nil, mockService, "", false, logger)
+			admin.Token(h)(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+
+			if tt.wantCode != "" {
+				var resp response.OAuthErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Error != tt.wantCode {
+					t.Errorf("error = %v, want %v", resp.Error, tt.wantCode)
+				}
+				return
+			}
+
+			if w.Header().Get("Deprecation") != "true" {
+				t.Error("expected Deprecation header on password grant response")
+			}
+		})
+	}
+}
+
+func TestTokenHandler_SignedRequest(t *testing.T) {
+	logger := zap.NewNop()
+
+	tests := []struct {
+		name           string
+		formData       url.Values
+		grantErr       error
+		wantSigned     *domain.SignedClientRequest
+		wantStatusCode int
+		wantCode       string
+	}{
+		{
+			name:           "unsigned request",
+			formData:       url.Values{"client_id": {"svc"}, "client_secret": {"secret"}, "grant_type": {"client_credentials"}},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "signed request",
+			formData:       url.Values{"client_id": {"svc"}, "client_secret": {"secret"}, "grant_type": {"client_credentials"}, "timestamp": {"1760601600"}, "nonce": {"0123456789abcdef"}, "signature": {"abc"}},
+			wantSigned:     &domain.SignedClientRequest{Timestamp: 1760601600, Nonce: "0123456789abcdef", Signature: "abc"},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "incomplete signature fields",
+			formData:       url.Values{"client_id": {"svc"}, "client_secret": {"secret"}, "grant_type": {"client_credentials"}, "nonce": {"0123456789abcdef"}},
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "invalid_request",
+		},
+		{
+			name:           "invalid timestamp",
+			formData:       url.Values{"client_id": {"svc"}, "client_secret": {"secret"}, "grant_type": {"client_credentials"}, "timestamp": {"yesterday"}},
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "invalid_request",
+		},
+		{
+			name:           "replayed request",
+			formData:       url.Values{"client_id": {"svc"}, "client_secret": {"secret"}, "grant_type": {"client_credentials"}, "timestamp": {"1760601600"}, "nonce": {"0123456789abcdef"}, "signature": {"abc"}},
+			grantErr:       domainerrors.ErrReplayedRequest,
+			wantSigned:     &domain.SignedClientRequest{Timestamp: 1760601600, Nonce: "0123456789abcdef", Signature: "abc"},
+			wantStatusCode: http.StatusUnauthorized,
+			wantCode:       "invalid_client",
+		},
+		{
+			name:           "signed request required",
+			formData:       url.Values{"client_id": {"svc"}, "client_secret": {"secret"}, "grant_type": {"client_credentials"}},
+			grantErr:       domainerrors.ErrSignedRequestRequired,
+			wantStatusCode: http.StatusUnauthorized,
+			wantCode:       "invalid_client",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOAuth2Service := &MockOAuth2Service{
+				ClientCredentialsFunc: func(ctx context.Context, clientID, clientSecret string, signed *domain.SignedClientRequest, certThumbprint string) (string, time.Time, error) {
+					if (signed == nil) != (tt.wantSigned == nil) || (signed != nil && *signed != *tt.wantSigned) {
+						t.Errorf("signed = %+v, want %+v", signed, tt.wantSigned)
+					}
+					if tt.grantErr != nil {
+						return "", time.Time{}, tt.grantErr
+					}
+					return "access_token", time.Now().Add(15 * time.Minute), nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/token", strings.NewReader(tt.formData.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			w := httptest.NewRecorder()
+
+			admin.Token(shared.NewOAuth2Handler(mockOAuth2Service, nil, nil, "", false, logger))(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Errorf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+			if tt.wantCode != "" {
+				var resp response.OAuthErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Error != tt.wantCode {
+					t.Errorf("error = %v, want %v", resp.Error, tt.wantCode)
+				}
+			}
+		})
+	}
+}
+
+// basicAuthHeader encodes the credentials like golang.org/x/oauth2 and other RFC 6749 clients do,
+// form-urlencoding both before joining them
+func basicAuthHeader(clientID, clientSecret string) string {
+	credentials := url.QueryEscape(clientID) + ":" + url.QueryEscape(clientSecret)
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials))
+}
+
+func TestTokenHandler_BasicAuth(t *testing.T) {
+	logger := zap.NewNop()
+
+	tests := []struct {
+		name           string
+		authorization  string
+		formData       url.Values
+		grantErr       error
+		wantClientID   string
+		wantSecret     string
+		wantStatusCode int
+		wantCode       string
+		wantChallenge  bool
+	}{
+		{
+			name:           "credentials in the header",
+			authorization:  basicAuthHeader("svc:prod", "p@ss word+/="),
+			formData:       url.Values{"grant_type": {"client_credentials"}},
+			wantClientID:   "svc:prod",
+			wantSecret:     "p@ss word+/=",
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "client_id repeated in the body",
+			authorization:  basicAuthHeader("svc", "secret"),
+			formData:       url.Values{"grant_type": {"client_credentials"}, "client_id": {"svc"}},
+			wantClientID:   "svc",
+			wantSecret:     "secret",
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "credentials in the body",
+			formData:       url.Values{"grant_type": {"client_credentials"}, "client_id": {"svc"}, "client_secret": {"secret"}},
+			wantClientID:   "svc",
+			wantSecret:     "secret",
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "secret in both the header and the body",
+			authorization:  basicAuthHeader("svc", "secret"),
+			formData:       url.Values{"grant_type": {"client_credentials"}, "client_secret": {"secret"}},
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "invalid_request",
+		},
+		{
+			name:           "different client_id in the body",
+			authorization:  basicAuthHeader("svc", "secret"),
+			formData:       url.Values{"grant_type": {"client_credentials"}, "client_id": {"other"}},
+			wantStatusCode: http.StatusUnauthorized,
+			wantCode:       "invalid_client",
+			wantChallenge:  true,
+		},
+		{
+			name:           "malformed encoding",
+			authorization:  "Basic " + base64.StdEncoding.EncodeToString([]byte("svc%zz:secret")),
+			formData:       url.Values{"grant_type": {"client_credentials"}},
+			wantStatusCode: http.StatusUnauthorized,
+			wantCode:       "invalid_client",
+			wantChallenge:  true,
+		},
+		{
+			name:           "invalid secret in the header",
+			authorization:  basicAuthHeader("svc", "wrong"),
+			formData:       url.Values{"grant_type": {"client_credentials"}},
+			grantErr:       domainerrors.ErrInvalidClient,
+			wantClientID:   "svc",
+			wantSecret:     "wrong",
+			wantStatusCode: http.StatusUnauthorized,
+			wantCode:       "invalid_client",
+			wantChallenge:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOAuth2Service := &MockOAuth2Service{
+				ClientCredentialsFunc: func(ctx context.Context, clientID, clientSecret string, signed *domain.SignedClientRequest, certThumbprint string) (string, time.Time, error) {
+					if clientID != tt.wantClientID || clientSecret != tt.wantSecret {
+						t.Errorf("credentials = %q/%q, want %q/%q", clientID, clientSecret, tt.wantClientID, tt.wantSecret)
+					}
+					if tt.grantErr != nil {
+						return "", time.Time{}, tt.grantErr
+					}
+					return "access_token", time.Now().Add(15 * time.Minute), nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/token", strings.NewReader(tt.formData.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+
+			admin.Token(shared.NewOAuth2Handler(mockOAuth2Service, nil, nil, "", false, logger))(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Errorf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+			if got := w.Header().Get("WWW-Authenticate") != ""; got != tt.wantChallenge {
+				t.Errorf("WWW-Authenticate set = %v, want %v", got, tt.wantChallenge)
+			}
+			if tt.wantCode != "" {
+				var resp response.OAuthErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Error != tt.wantCode {
+					t.Errorf("error = %v, want %v", resp.Error, tt.wantCode)
+				}
+			}
+		})
+	}
+}
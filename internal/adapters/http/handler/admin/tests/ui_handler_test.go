@@ -0,0 +1,47 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/admin"
+)
+
+func TestUIHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedType   string
+		expectedBody   string
+	}{
+		{name: "index", path: "/", expectedStatus: http.StatusOK, expectedType: "text/html", expectedBody: `<script src="app.js">`},
+		{name: "script", path: "/app.js", expectedStatus: http.StatusOK, expectedType: "javascript", expectedBody: "audit-logs/export"},
+		{name: "stylesheet", path: "/app.css", expectedStatus: http.StatusOK, expectedType: "text/css"},
+		{name: "unknown asset", path: "/missing.js", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			admin.UI().ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.expectedStatus)
+			}
+			if !strings.Contains(w.Header().Get("Content-Type"), tt.expectedType) {
+				t.Errorf("Content-Type = %q, want %q", w.Header().Get("Content-Type"), tt.expectedType)
+			}
+			if !strings.Contains(w.Body.String(), tt.expectedBody) {
+				t.Errorf("body does not contain %q", tt.expectedBody)
+			}
+			if csp := w.Header().Get("Content-Security-Policy"); !strings.Contains(csp, "frame-ancestors 'none'") {
+				t.Errorf("Content-Security-Policy = %q, want framing denied", csp)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	admin "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/admin"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestUpdateOAuthClientHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		ifMatch        string
+		updateErr      error
+		wantStatusCode int
+		wantCode       string
+	}{
+		{name: "successful update", body: `{"scopes":["read","write"]}`, ifMatch: `"2"`, wantStatusCode: http.StatusOK},
+		{name: "invalid JSON", body: `{invalid`, ifMatch: `"2"`, wantStatusCode: http.StatusBadRequest, wantCode: "INVALID_REQUEST_BODY"},
+		{name: "unregistered scope", body: `{"scopes":["admin"]}`, ifMatch: `"2"`, updateErr: domainerrors.ErrUnknownScope, wantStatusCode: http.StatusBadRequest, wantCode: "INVALID_SCOPE"},
+		{name: "client not found", body: `{"name":"New"}`, ifMatch: `"2"`, updateErr: domainerrors.ErrClientNotFound, wantStatusCode: http.StatusNotFound, wantCode: "NOT_FOUND"},
+		{name: "missing if-match", body: `{"name":"New"}`, wantStatusCode: http.StatusPreconditionRequired, wantCode: "PRECONDITION_REQUIRED"},
+		{name: "any version", body: `{"name":"New"}`, ifMatch: "*", wantStatusCode: http.StatusPreconditionRequired, wantCode: "PRECONDITION_REQUIRED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockOAuth2Service{
+				UpdateClientFunc: func(ctx context.Context, id string, expectedVersion int, name, description *string, scopes []string, tokenProfile *domain.TokenProfile, grantTypes, redirectURIs, tags []string) (*domain.OAuthClient, error) {
+					if id != "id-123" || expectedVersion != 2 {
+						t.Errorf("UpdateClient() id = %v, expectedVersion = %d, want id-123, 2", id, expectedVersion)
+					}
+					if tt.updateErr != nil {
+						return nil, tt.updateErr
+					}
+					return &domain.OAuthClient{ID: id, ClientID: "client-123", Name: "Test", Scopes: scopes, Active: true}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPut, "/admin/oauth-clients/id-123", bytes.NewBufferString(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"id": "id-123"})
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			w := httptest.NewRecorder()
+
+			admin.UpdateOAuthClient(shared.NewAdminOAuthClientsHandler(mockService, zap.NewNop()))(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+
+			if tt.wantCode != "" {
+				var resp response.ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("Error code = %v, want %v", resp.Code, tt.wantCode)
+				}
+				return
+			}
+
+			var resp response.OAuthClientResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(resp.Scopes) != 2 {
+				t.Errorf("Scopes = %v, want [read write]", resp.Scopes)
+			}
+		})
+	}
+}
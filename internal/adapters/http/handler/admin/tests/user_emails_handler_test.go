@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	admin "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/admin"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestListUserEmailsHandler(t *testing.T) {
+	verifiedAt := time.Now()
+
+	tests := []struct {
+		name           string
+		emails         []*domain.UserEmail
+		listErr        error
+		wantStatusCode int
+		wantCount      int
+	}{
+		{
+			name: "lists emails",
+			emails: []*domain.UserEmail{
+				{ID: "email-1", Email: "recovery@example.org", VerifiedAt: &verifiedAt},
+				{ID: "email-2", Email: "pending@example.org"},
+			},
+			wantStatusCode: http.StatusOK,
+			wantCount:      2,
+		},
+		{name: "no emails", wantStatusCode: http.StatusOK},
+		{name: "service failure", listErr: domainerrors.ErrInternal, wantStatusCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockUserEmailService{
+				ListUserEmailsFunc: func(ctx context.Context, userID string) ([]*domain.UserEmail, error) {
+					if userID != "user-123" {
+						return nil, errors.New("unexpected user")
+					}
+					return tt.emails, tt.listErr
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/users/user-123/emails", nil)
+			req = mux.SetURLVars(req, map[string]string{"id": "user-123"})
+			w := httptest.NewRecorder()
+
+			admin.ListUserEmails(shared.NewAdminUsersHandler(&MockAnonymizationService{}, mockService, nil, nil, zap.NewNop()))(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+			if tt.wantStatusCode != http.StatusOK {
+				return
+			}
+
+			var resp []response.UserEmailResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp == nil || len(resp) != tt.wantCount {
+				t.Errorf("response = %+v, want %d emails", resp, tt.wantCount)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	admin "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/admin"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestMergeUserHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		noClaims       bool
+		mergeErr       error
+		wantDryRun     bool
+		wantStatusCode int
+		wantCode       string
+	}{
+		{name: "successful merge", body: `{"merged_user_id":"user-456"}`, wantStatusCode: http.StatusOK},
+		{name: "dry run", body: `{"merged_user_id":"user-456","dry_run":true}`, wantDryRun: true, wantStatusCode: http.StatusOK},
+		{name: "missing claims", body: `{"merged_user_id":"user-456"}`, noClaims: true, wantStatusCode: http.StatusUnauthorized, wantCode: "UNAUTHORIZED"},
+		{name: "invalid body", body: `{`, wantStatusCode: http.StatusBadRequest, wantCode: "INVALID_REQUEST_BODY"},
+		{name: "missing merged user", body: `{}`, wantStatusCode: http.StatusBadRequest, wantCode: "REQUIRED_FIELD"},
+		{name: "user not found", body: `{"merged_user_id":"user-456"}`, mergeErr: domainerrors.ErrUserNotFound, wantStatusCode: http.StatusNotFound, wantCode: "USER_NOT_FOUND"},
+		{name: "invalid merge", body: `{"merged_user_id":"user-456"}`, mergeErr: domainerrors.ErrInvalidUserMerge, wantStatusCode: http.StatusConflict, wantCode: "INVALID_USER_MERGE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockUserMergeService{
+				MergeUsersFunc: func(ctx context.Context, survivorID, mergedID, actor string, dryRun bool) (*domain.UserMerge, error) {
+					if survivorID != "user-123" || mergedID != "user-456" || actor != "admin:999" || dryRun != tt.wantDryRun {
+						t.Errorf("MergeUsers() = %v, %v, %v, %v, want user-123, user-456, admin:999, %v", survivorID, mergedID, actor, dryRun, tt.wantDryRun)
+					}
+					if tt.mergeErr != nil {
+						return nil, tt.mergeErr
+					}
+					return &domain.UserMerge{
+						Survivor:     &domain.User{ID: survivorID, IDCitizen: 1001},
+						Merged:       &domain.User{ID: mergedID, IDCitizen: 2002},
+						DryRun:       dryRun,
+						Consents:     []string{"legacy-app"},
+						PhoneNumber:  "+573001234567",
+						AuditRecords: 5,
+					}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/users/user-123/merge", strings.NewReader(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"id": "user-123"})
+			if !tt.noClaims {
+				claims := &domain.TokenClaims{IDCitizen: 999, Role: domain.RoleAdmin}
+				req = req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, claims))
+			}
+			w := httptest.NewRecorder()
+
+			admin.MergeUser(shared.NewAdminUsersHandler(&MockAnonymizationService{}, nil, nil, mockService, zap.NewNop()))(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+
+			if tt.wantCode != "" {
+				var resp response.ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("error code = %v, want %v", resp.Code, tt.wantCode)
+				}
+				return
+			}
+
+			var resp response.UserMergeResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.User.IDCitizen != 1001 || resp.MergedUser.IDCitizen != 2002 || resp.DryRun != tt.wantDryRun {
+				t.Errorf("response = %+v, want the merge of 2002 into 1001", resp)
+			}
+			if len(resp.Consents) != 1 || resp.Emails == nil || resp.AuditRecords != 5 {
+				t.Errorf("response = %+v, want 1 consent, no emails and 5 audit records", resp)
+			}
+			if resp.PhoneNumber == "+573001234567" || resp.PhoneNumber == "" {
+				t.Errorf("phone number = %q, want it masked", resp.PhoneNumber)
+			}
+		})
+	}
+}
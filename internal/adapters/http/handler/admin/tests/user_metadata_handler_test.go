@@ -0,0 +1,98 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	admin "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/admin"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestUpdateUserMetadataHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		ifMatch        string
+		noClaims       bool
+		updateErr      error
+		wantStatusCode int
+		wantCode       string
+	}{
+		{name: "successful update", body: `{"metadata":{"department":"sales","newsletter":null}}`, ifMatch: `"1"`, wantStatusCode: http.StatusOK},
+		{name: "missing claims", body: `{"metadata":{"department":"sales"}}`, noClaims: true, wantStatusCode: http.StatusUnauthorized, wantCode: "UNAUTHORIZED"},
+		{name: "invalid json body", body: `{"metadata":`, wantStatusCode: http.StatusBadRequest, wantCode: "INVALID_REQUEST_BODY"},
+		{name: "no changes", body: `{"metadata":{}}`, wantStatusCode: http.StatusBadRequest, wantCode: "REQUIRED_FIELD"},
+		{name: "invalid metadata", body: `{"metadata":{"nickname":"bob"}}`, ifMatch: `"1"`, updateErr: domainerrors.ErrInvalidUserMetadata, wantStatusCode: http.StatusBadRequest, wantCode: "INVALID_USER_METADATA"},
+		{name: "metadata too large", body: `{"metadata":{"department":"sales"}}`, ifMatch: `"1"`, updateErr: domainerrors.ErrUserMetadataTooLarge, wantStatusCode: http.StatusRequestEntityTooLarge, wantCode: "USER_METADATA_TOO_LARGE"},
+		{name: "missing if-match", body: `{"metadata":{"department":"sales"}}`, wantStatusCode: http.StatusPreconditionRequired, wantCode: "PRECONDITION_REQUIRED"},
+		{name: "any version", body: `{"metadata":{"department":"sales"}}`, ifMatch: "*", wantStatusCode: http.StatusPreconditionRequired, wantCode: "PRECONDITION_REQUIRED"},
+		{name: "user not found", body: `{"metadata":{"department":"sales"}}`, ifMatch: `"1"`, updateErr: domainerrors.ErrUserNotFound, wantStatusCode: http.StatusNotFound, wantCode: "USER_NOT_FOUND"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockUserMetadataService{
+				UpdateUserMetadataFunc: func(ctx context.Context, userID string, expectedVersion int, changes domain.UserMetadata, actor string) (*domain.UserPublic, error) {
+					if userID != "user-123" || expectedVersion != 1 || actor != "admin:999" {
+						t.Errorf("UpdateUserMetadata() userID = %v, expectedVersion = %d, actor = %v, want user-123, 1, admin:999", userID, expectedVersion, actor)
+					}
+					if tt.updateErr != nil {
+						return nil, tt.updateErr
+					}
+					if _, ok := changes["newsletter"]; !ok || changes["newsletter"] != nil {
+						t.Errorf("UpdateUserMetadata() changes = %v, want newsletter removed", changes)
+					}
+					return &domain.UserPublic{ID: userID, IDCitizen: 12345, Metadata: domain.UserMetadata{"department": "sales"}}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPut, "/admin/users/user-123/metadata", bytes.NewBufferString(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"id": "user-123"})
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			if !tt.noClaims {
+				claims := &domain.TokenClaims{IDCitizen: 999, Role: domain.RoleAdmin}
+				req = req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, claims))
+			}
+			w := httptest.NewRecorder()
+
+			admin.UpdateUserMetadata(shared.NewUserMetadataHandler(mockService, zap.NewNop()))(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+
+			if tt.wantCode != "" {
+				var resp response.ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("error code = %v, want %v", resp.Code, tt.wantCode)
+				}
+				return
+			}
+
+			var resp response.UserResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if want := map[string]interface{}{"department": "sales"}; resp.ID != "user-123" || !reflect.DeepEqual(resp.Metadata, want) {
+				t.Errorf("response = %+v, want metadata %v", resp, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	admin "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/admin"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestChangeUserRoleHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		ifMatch        string
+		noClaims       bool
+		changeErr      error
+		wantRole       domain.Role
+		wantVersion    int
+		wantStatusCode int
+		wantCode       string
+	}{
+		{name: "promotes user", body: `{"role":"ADMIN"}`, ifMatch: `"4"`, wantRole: domain.RoleAdmin, wantVersion: 4, wantStatusCode: http.StatusOK},
+		{name: "role is case insensitive", body: `{"role":" user "}`, ifMatch: `"4"`, wantRole: domain.RoleUser, wantVersion: 4, wantStatusCode: http.StatusOK},
+		{name: "missing claims", body: `{"role":"ADMIN"}`, noClaims: true, wantStatusCode: http.StatusUnauthorized, wantCode: "UNAUTHORIZED"},
+		{name: "invalid json body", body: `{"role":`, wantStatusCode: http.StatusBadRequest, wantCode: "INVALID_REQUEST_BODY"},
+		{name: "unknown role", body: `{"role":"ROOT"}`, wantStatusCode: http.StatusBadRequest, wantCode: "INVALID_ROLE"},
+		{name: "user not found", body: `{"role":"ADMIN"}`, ifMatch: `"4"`, changeErr: domainerrors.ErrUserNotFound, wantRole: domain.RoleAdmin, wantVersion: 4, wantStatusCode: http.StatusNotFound, wantCode: "USER_NOT_FOUND"},
+		{name: "anonymized user", body: `{"role":"ADMIN"}`, ifMatch: `"4"`, changeErr: domainerrors.ErrUserAlreadyAnonymized, wantRole: domain.RoleAdmin, wantVersion: 4, wantStatusCode: http.StatusConflict, wantCode: "USER_ALREADY_ANONYMIZED"},
+		{name: "if-match version", body: `{"role":"ADMIN"}`, ifMatch: `"4"`, wantRole: domain.RoleAdmin, wantVersion: 4, wantStatusCode: http.StatusOK},
+		{name: "invalid if-match", body: `{"role":"ADMIN"}`, ifMatch: "4", wantStatusCode: http.StatusBadRequest, wantCode: "INVALID_IF_MATCH"},
+		{name: "missing if-match", body: `{"role":"ADMIN"}`, wantStatusCode: http.StatusPreconditionRequired, wantCode: "PRECONDITION_REQUIRED"},
+		{name: "any version", body: `{"role":"ADMIN"}`, ifMatch: "*", wantStatusCode: http.StatusPreconditionRequired, wantCode: "PRECONDITION_REQUIRED"},
+		{name: "version conflict", body: `{"role":"ADMIN"}`, ifMatch: `"3"`, changeErr: domainerrors.ErrVersionConflict, wantRole: domain.RoleAdmin, wantVersion: 3, wantStatusCode: http.StatusConflict, wantCode: "VERSION_CONFLICT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockRoleService{
+				ChangeUserRoleFunc: func(ctx context.Context, userID string, expectedVersion int, role domain.Role, actor string) (*domain.UserPublic, error) {
+					if userID != "user-123" || role != tt.wantRole || actor != "admin:999" {
+						t.Errorf("ChangeUserRole(%v, %v, %v), want user-123, %v, admin:999", userID, role, actor, tt.wantRole)
+					}
+					if expectedVersion != tt.wantVersion {
+						t.Errorf("ChangeUserRole() expectedVersion = %d, want %d", expectedVersion, tt.wantVersion)
+					}
+					if tt.changeErr != nil {
+						return nil, tt.changeErr
+					}
+					return &domain.UserPublic{ID: userID, IDCitizen: 12345, Role: role, Version: 5}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPut, "/admin/users/user-123/role", bytes.NewBufferString(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"id": "user-123"})
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			if !tt.noClaims {
+				claims := &domain.TokenClaims{IDCitizen: 999, Role: domain.RoleAdmin}
+				req = req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, claims))
+			}
+			w := httptest.NewRecorder()
+
+			admin.ChangeUserRole(shared.NewAdminUsersHandler(&MockAnonymizationService{}, nil, mockService, nil, zap.NewNop()))(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+
+			if tt.wantCode != "" {
+				var resp response.ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("error code = %v, want %v", resp.Code, tt.wantCode)
+				}
+				return
+			}
+
+			var resp response.UserResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.ID != "user-123" || resp.Role != tt.wantRole || resp.Version != 5 {
+				t.Errorf("response = %+v, want user-123 with role %v at version 5", resp, tt.wantRole)
+			}
+			if etag := w.Header().Get("ETag"); etag != `"5"` {
+				t.Errorf("ETag = %s, want \"5\"", etag)
+			}
+		})
+	}
+}
@@ -3,7 +3,9 @@ package admin
 import (
 	"encoding/json"
 	nethttp "net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -11,12 +13,27 @@ import (
 	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
 	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
 	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
 	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
 )
 
-// Token handles OAuth2 Client Credentials flow
-// @Summary OAuth2 Client Credentials
+// Token handles the OAuth2 token endpoint (Client Credentials, Device Code and legacy Password grants)
+// @Summary OAuth2 Token
 // @Description Authenticates a client application and returns an access token for service-to-service communication.
+// @Description With grant_type `urn:ietf:params:oauth:grant-type:device_code` the client polls with its device_code
+// @Description and receives a user token pair once the user approves the request.
+// @Description grant_type `password` is deprecated and only available to allowlisted legacy clients when enabled.
+// @Description Clients with a request signing key sign their client_credentials requests against replay: `timestamp` is the current
+// @Description Unix time in seconds, `nonce` a random string of 16 to 128 characters never reused, and `signature` the hex
+// @Description HMAC-SHA256 of `<client_id>.<timestamp>.<nonce>` with the request signing key.
+// @Description The client authenticates with `client_id` and `client_secret` in the body or with HTTP Basic authentication,
+// @Description where both are form-urlencoded before being joined (RFC 6749 section 2.3.1), but not with both.
+// @Description Errors follow RFC 6749 section 5.2, `{"error":"invalid_client","error_description":"..."}`.
+// @Description A client whose secret is rejected too many times in a row is locked out for a cooldown, even with the right secret.
+// @Description A client_credentials token requested over mTLS is bound to the client certificate with a `cnf` claim holding its
+// @Description `x5t#S256` thumbprint (RFC 8705), resource servers only accept it from a client presenting the same certificate.
 // @Description
 // @Description **Test Credentials (use in Swagger):**
 // @Description ```json
@@ -32,9 +49,13 @@ import (
 // @Produce json
 // @Param request body request.ClientCredentialsRequest true "Client Credentials"
 // @Success 200 {object} response.ClientCredentialsResponse "Access token generated successfully"
-// @Failure 400 {object} response.ErrorResponse "Invalid request or missing parameters"
-// @Failure 401 {object} response.ErrorResponse "Invalid client credentials"
-// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Success 200 {object} response.TokenResponse "Token pair issued for an approved device code"
+// @Header 200 {string} X-JWS-Signature "Detached JWS of the response body, when response signing is enabled"
+// @Failure 400 {object} response.OAuthErrorResponse "invalid_request, invalid_grant (including the active sessions quota), unauthorized_client, unsupported_grant_type, authorization_pending, slow_down, access_denied or expired_token"
+// @Failure 401 {object} response.OAuthErrorResponse "invalid_client: invalid client credentials, missing or invalid request signature, stale timestamp or replayed nonce"
+// @Failure 429 {object} response.OAuthErrorResponse "temporarily_unavailable: token issuance quota exceeded or client locked out after repeated authentication failures, see the Retry-After header"
+// @Failure 500 {object} response.OAuthErrorResponse "server_error"
+// @Failure 503 {object} response.OAuthErrorResponse "temporarily_unavailable"
 // @Router /token [post]
 func Token(h *shared.OAuth2Handler) nethttp.HandlerFunc {
 	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
@@ -50,49 +71,162 @@ func Token(h *shared.OAuth2Handler) nethttp.HandlerFunc {
 			// Parse JSON
 			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 				h.Logger.Debug("invalid request body (JSON)", zap.Error(err))
-				httperrors.RespondWithError(w, httperrors.ErrInvalidRequestBody)
+				httperrors.RespondWithOAuthError(w, httperrors.ErrOAuthMalformedRequest)
 				return
 			}
 		} else {
 			// Parse form data (default for OAuth2)
 			if err := r.ParseForm(); err != nil {
 				h.Logger.Debug("failed to parse form", zap.Error(err))
-				httperrors.RespondWithError(w, httperrors.ErrInvalidRequestBody)
+				httperrors.RespondWithOAuthError(w, httperrors.ErrOAuthMalformedRequest)
 				return
 			}
 
 			req.ClientID = r.FormValue("client_id")
 			req.ClientSecret = r.FormValue("client_secret")
 			req.GrantType = r.FormValue("grant_type")
+			req.DeviceCode = r.FormValue("device_code")
+			req.Username = r.FormValue("username")
+			req.Password = r.FormValue("password")
+			req.Nonce = r.FormValue("nonce")
+			req.Signature = r.FormValue("signature")
+			if timestamp := r.FormValue("timestamp"); timestamp != "" {
+				parsed, err := strconv.ParseInt(timestamp, 10, 64)
+				if err != nil {
+					h.Logger.Debug("invalid timestamp", zap.Error(err))
+					httperrors.RespondWithOAuthError(w, httperrors.ErrOAuthMalformedRequest)
+					return
+				}
+				req.Timestamp = parsed
+			}
 		}
 
-		// Validate required fields
-		if req.ClientID == "" || req.ClientSecret == "" || req.GrantType == "" {
-			httperrors.RespondWithError(w, httperrors.ErrRequiredField)
+		// Clients may authenticate with HTTP Basic instead of the body (RFC 6749 section 2.3.1)
+		if err := authenticateClient(r, &req.ClientID, &req.ClientSecret); err != nil {
+			httperrors.RespondWithOAuthError(w, err)
 			return
 		}
 
-		// Validate grant_type
-		if req.GrantType != "client_credentials" {
-			httperrors.RespondWithErrorMessage(w, nethttp.StatusBadRequest, "unsupported grant_type, must be 'client_credentials'")
+		// Validate required fields
+		if req.ClientID == "" || req.GrantType == "" {
+			httperrors.RespondWithOAuthError(w, httperrors.ErrOAuthInvalidRequest)
 			return
 		}
 
-		// Authenticate client and generate token
-		accessToken, expiresIn, err := h.OAuth2Service.ClientCredentials(r.Context(), req.ClientID, req.ClientSecret)
-		if err != nil {
-			h.Logger.Warn("client credentials authentication failed", zap.Error(err), zap.String("client_id", req.ClientID))
-			httperrors.RespondWithDomainError(w, err)
-			return
+		// Dispatch on grant_type
+		switch req.GrantType {
+		case domain.GrantTypeClientCredentials:
+			clientCredentialsGrant(w, r, h, req)
+		case domain.GrantTypeDeviceCode:
+			deviceCodeGrant(w, r, h, req)
+		case domain.GrantTypePassword:
+			passwordGrant(w, r, h, req)
+		default:
+			httperrors.RespondWithOAuthError(w, httperrors.ErrOAuthUnsupportedGrantType)
 		}
+	}
+}
+
+// clientCredentialsGrant authenticates a client with its secret and returns a client access token
+func clientCredentialsGrant(w nethttp.ResponseWriter, r *nethttp.Request, h *shared.OAuth2Handler, req request.ClientCredentialsRequest) {
+	if req.ClientSecret == "" {
+		httperrors.RespondWithOAuthError(w, httperrors.ErrOAuthInvalidRequest)
+		return
+	}
 
-		// Return token response
-		resp := response.ClientCredentialsResponse{
-			AccessToken: accessToken,
-			TokenType:   "Bearer",
-			ExpiresIn:   expiresIn,
+	// A request is signed when it carries any of the replay protection fields, which then are all required
+	var signed *domain.SignedClientRequest
+	if req.Timestamp != 0 || req.Nonce != "" || req.Signature != "" {
+		if req.Timestamp == 0 || req.Nonce == "" || req.Signature == "" {
+			httperrors.RespondWithOAuthError(w, httperrors.ErrOAuthInvalidRequest)
+			return
 		}
+		signed = &domain.SignedClientRequest{
+			Timestamp: req.Timestamp,
+			Nonce:     req.Nonce,
+			Signature: req.Signature,
+		}
+	}
+
+	// Tokens requested over mTLS are bound to the certificate of the client (RFC 8705)
+	var certThumbprint string
+	cert, err := middleware.ClientCertificate(r, h.ClientCertHeader, h.TrustProxyHeaders)
+	if err != nil {
+		h.Logger.Warn("invalid client certificate", zap.Error(err), logging.String("client_id", req.ClientID))
+		httperrors.RespondWithOAuthError(w, httperrors.ErrOAuthInvalidRequest)
+		return
+	}
+	if cert != nil {
+		certThumbprint = domain.CertificateThumbprint(cert)
+	}
+
+	// Authenticate client and generate token
+	accessToken, expiresAt, err := h.OAuth2Service.ClientCredentials(r.Context(), req.ClientID, req.ClientSecret, signed, certThumbprint)
+	if err != nil {
+		h.Logger.Warn("client credentials authentication failed", zap.Error(err), logging.String("client_id", req.ClientID))
+		httperrors.RespondWithOAuthDomainError(w, err)
+		return
+	}
 
-		shared.RespondWithJSON(w, nethttp.StatusOK, resp)
+	// Return token response, expires_at lets clients cache the token without tracking when it was received
+	resp := response.ClientCredentialsResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(time.Until(expiresAt).Seconds()),
+		ExpiresAt:   expiresAt,
 	}
+
+	shared.RespondWithJSON(w, nethttp.StatusOK, resp)
+}
+
+// deviceCodeGrant exchanges an approved device code for a user token pair (RFC 8628 section 3.4)
+func deviceCodeGrant(w nethttp.ResponseWriter, r *nethttp.Request, h *shared.OAuth2Handler, req request.ClientCredentialsRequest) {
+	if req.ClientSecret == "" || req.DeviceCode == "" {
+		httperrors.RespondWithOAuthError(w, httperrors.ErrOAuthInvalidRequest)
+		return
+	}
+
+	tokenPair, err := h.DeviceAuthorizationService.PollToken(r.Context(), req.ClientID, req.ClientSecret, req.DeviceCode)
+	if err != nil {
+		// authorization_pending and slow_down are part of the normal polling flow
+		h.Logger.Debug("device code token request not fulfilled", zap.Error(err), logging.String("client_id", req.ClientID))
+		httperrors.RespondWithOAuthDomainError(w, err)
+		return
+	}
+
+	resp := response.TokenResponse{
+		AccessToken:  tokenPair.AccessToken,
+		RefreshToken: tokenPair.RefreshToken,
+		TokenType:    tokenPair.TokenType,
+		ExpiresIn:    tokenPair.ExpiresIn,
+	}
+
+	shared.RespondWithJSON(w, nethttp.StatusOK, resp)
+}
+
+// passwordGrant exchanges user credentials for a token pair on behalf of an allowlisted legacy client.
+// The grant is deprecated and disabled unless explicitly enabled in configuration.
+func passwordGrant(w nethttp.ResponseWriter, r *nethttp.Request, h *shared.OAuth2Handler, req request.ClientCredentialsRequest) {
+	if req.ClientSecret == "" || req.Username == "" || req.Password == "" {
+		httperrors.RespondWithOAuthError(w, httperrors.ErrOAuthInvalidRequest)
+		return
+	}
+
+	tokenPair, err := h.PasswordGrantService.PasswordGrant(r.Context(), req.ClientID, req.ClientSecret, req.Username, req.Password)
+	if err != nil {
+		h.Logger.Warn("password grant failed", zap.Error(err), logging.String("client_id", req.ClientID))
+		httperrors.RespondWithOAuthDomainError(w, err)
+		return
+	}
+
+	resp := response.TokenResponse{
+		AccessToken:  tokenPair.AccessToken,
+		RefreshToken: tokenPair.RefreshToken,
+		TokenType:    tokenPair.TokenType,
+		ExpiresIn:    tokenPair.ExpiresIn,
+	}
+
+	// Signal deprecation to clients still using this grant
+	w.Header().Set("Deprecation", "true")
+	shared.RespondWithJSON(w, nethttp.StatusOK, resp)
 }
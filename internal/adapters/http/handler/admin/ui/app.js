@@ -0,0 +1,166 @@
+// Admin UI of the auth-microservice. It calls the admin API with the bearer token of an administrator,
+// kept in the session storage of the tab. The API is resolved relative to the page, /api/auth/admin/ui/,
+// so the UI also works behind a gateway that prefixes the routes.
+"use strict";
+
+const api = new URL("../", document.baseURI);
+const tokenKey = "auth-admin-token";
+const maxAuditRecords = 500;
+
+const status = document.getElementById("status");
+
+function setStatus(message, error) {
+  status.textContent = message;
+  status.classList.toggle("error", Boolean(error));
+}
+
+async function request(path) {
+  const token = sessionStorage.getItem(tokenKey);
+  if (!token) {
+    throw new Error("Enter the bearer token of an administrator first");
+  }
+
+  const response = await fetch(new URL(path, api), {
+    headers: { Authorization: "Bearer " + token },
+    credentials: "omit",
+  });
+  if (!response.ok) {
+    let message = response.status + " " + response.statusText;
+    try {
+      const body = await response.json();
+      if (body.error) {
+        message += ": " + body.error + (body.code ? " (" + body.code + ")" : "");
+      }
+    } catch (_) {
+      // Not a JSON error response
+    }
+    throw new Error(message);
+  }
+  return response;
+}
+
+// render fills the rows of the table of a section, the values are set as text so they are never parsed as HTML
+function render(section, rows) {
+  const body = document.querySelector("#" + section + " tbody");
+  body.replaceChildren(
+    ...rows.map((values) => {
+      const row = document.createElement("tr");
+      for (const value of values) {
+        const cell = document.createElement("td");
+        cell.textContent = value === undefined || value === null ? "" : String(value);
+        row.append(cell);
+      }
+      return row;
+    })
+  );
+}
+
+function formatTime(value) {
+  return value ? new Date(value).toLocaleString() : "";
+}
+
+const loaders = {
+  async users(form) {
+    const query = new URLSearchParams({
+      status: form.status.value,
+      limit: form.limit.value,
+      offset: form.offset.value,
+    });
+    const users = await (await request("users?" + query)).json();
+    render("users", users.map((u) => [u.id, u.id_citizen, u.email, u.name, u.role, u.status, u.type, formatTime(u.created_at)]));
+    return users.length + " users";
+  },
+
+  async clients() {
+    const clients = await (await request("oauth-clients")).json();
+    render("clients", clients.map((c) => [
+      c.client_id,
+      c.name,
+      (c.scopes || []).join(" "),
+      (c.grant_types || []).join(" "),
+      c.token_profile,
+      c.require_signed_requests ? "yes" : "no",
+      formatTime(c.created_at),
+    ]));
+    return clients.length + " OAuth clients";
+  },
+
+  async sessions(form) {
+    const userID = form.user_id.value.trim();
+    const usage = await (await request("quotas/user/" + encodeURIComponent(userID))).json();
+    render("sessions", [[
+      userID,
+      usage.active_sessions,
+      usage.quota.max_active_sessions || "unlimited",
+      usage.tokens_issued,
+      usage.quota.max_tokens_per_hour || "unlimited",
+      formatTime(usage.tokens_reset_at),
+    ]]);
+    return "Sessions of user " + userID;
+  },
+
+  async audit(form) {
+    const query = new URLSearchParams({ format: "ndjson" });
+    for (const name of ["action", "actor", "target_id"]) {
+      if (form[name].value.trim()) {
+        query.set(name, form[name].value.trim());
+      }
+    }
+    const after = new Date(Date.now() - Number(form.hours.value) * 3600 * 1000);
+    query.set("after", after.toISOString());
+
+    // The export streams every matching record oldest first, the UI shows the newest ones
+    const lines = (await (await request("audit-logs/export?" + query)).text()).split("\n").filter(Boolean);
+    const records = lines.slice(-maxAuditRecords).map((line) => JSON.parse(line)).reverse();
+    render("audit", records.map((r) => [
+      formatTime(r.created_at),
+      r.action,
+      r.actor,
+      r.target_id,
+      r.details ? JSON.stringify(r.details) : "",
+    ]));
+    if (lines.length > maxAuditRecords) {
+      return "Newest " + maxAuditRecords + " of " + lines.length + " audit records";
+    }
+    return lines.length + " audit records";
+  },
+};
+
+for (const form of document.querySelectorAll("form[data-load]")) {
+  form.addEventListener("submit", async (event) => {
+    event.preventDefault();
+    setStatus("Loading...");
+    try {
+      setStatus(await loaders[form.dataset.load](form));
+    } catch (error) {
+      setStatus(error.message, true);
+    }
+  });
+}
+
+for (const tab of document.querySelectorAll("nav button")) {
+  tab.addEventListener("click", () => {
+    for (const other of document.querySelectorAll("nav button")) {
+      other.classList.toggle("active", other === tab);
+      document.getElementById(other.dataset.tab).hidden = other !== tab;
+    }
+    setStatus("");
+  });
+}
+
+document.getElementById("token-form").addEventListener("submit", (event) => {
+  event.preventDefault();
+  const input = document.getElementById("token");
+  sessionStorage.setItem(tokenKey, input.value.trim().replace(/^Bearer\s+/i, ""));
+  input.value = "";
+  setStatus("Token set for this tab");
+});
+
+document.getElementById("forget-token").addEventListener("click", () => {
+  sessionStorage.removeItem(tokenKey);
+  setStatus("Token forgotten");
+});
+
+if (sessionStorage.getItem(tokenKey)) {
+  setStatus("Using the token set in this tab");
+}
@@ -0,0 +1,36 @@
+package admin
+
+import (
+	"embed"
+	"io/fs"
+	nethttp "net/http"
+)
+
+// uiFiles are the static assets of the admin UI, the pages call the admin API from the browser
+//
+//go:embed ui
+var uiFiles embed.FS
+
+// uiContentSecurityPolicy only lets the UI load its own assets and call the API of its own origin
+const uiContentSecurityPolicy = "default-src 'none'; script-src 'self'; style-src 'self'; connect-src 'self'; img-src 'self'; " +
+	"base-uri 'none'; form-action 'none'; frame-ancestors 'none'"
+
+// UI serves the embedded admin web UI, for environments without a separate console.
+// The assets are public, the pages ask for the bearer token of an administrator and send it to the admin API,
+// so the UI can't do anything the token can't. The handler expects the path without the route prefix.
+func UI() nethttp.Handler {
+	assets, err := fs.Sub(uiFiles, "ui")
+	if err != nil {
+		panic(err) // the directory is embedded at build time
+	}
+	files := nethttp.FileServer(nethttp.FS(assets))
+
+	return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		w.Header().Set("Content-Security-Policy", uiContentSecurityPolicy)
+		w.Header().Set("X-Frame-Options", "DENY")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("Referrer-Policy", "no-referrer")
+		w.Header().Set("Cache-Control", "no-cache")
+		files.ServeHTTP(w, r)
+	})
+}
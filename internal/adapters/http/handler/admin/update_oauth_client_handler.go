@@ -0,0 +1,95 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	nethttp "net/http"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/request"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+)
+
+// UpdateOAuthClient updates an OAuth2 client (ADMIN only)
+// @Summary Update OAuth2 Client
+// @Description Updates the name, description, scopes, token profile, grant types or redirect URIs of an OAuth2 client. Only registered scopes can be assigned.
+// @Description The minimal token profile issues user access tokens carrying only sub, exp, jti and role.
+// @Tags Admin - OAuth Clients
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "OAuth client ID"
+// @Param If-Match header string true "ETag of the client the change was decided on, e.g. \"3\""
+// @Param request body request.UpdateOAuthClientRequest true "OAuth Client data"
+// @Success 200 {object} response.OAuthClientResponse "OAuth client updated successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid request, unregistered scope, unknown token profile, unknown grant type, invalid redirect URI or invalid If-Match header"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden - Admin role required"
+// @Failure 404 {object} response.ErrorResponse "Client not found"
+// @Failure 409 {object} response.ErrorResponse "Client was modified since the If-Match version"
+// @Failure 428 {object} response.ErrorResponse "Missing If-Match header"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /admin/oauth-clients/{id} [put]
+func UpdateOAuthClient(h *shared.AdminOAuthClientsHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		id := mux.Vars(r)["id"]
+
+		var req request.UpdateOAuthClientRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.Logger.Debug("invalid request body", zap.Error(err))
+			httperrors.RespondWithError(w, httperrors.ErrInvalidRequestBody)
+			return
+		}
+
+		expectedVersion, err := shared.ExpectedVersion(r)
+		if errors.Is(err, domainerrors.ErrVersionRequired) {
+			httperrors.RespondWithError(w, httperrors.ErrPreconditionRequired)
+			return
+		}
+		if err != nil {
+			httperrors.RespondWithError(w, httperrors.ErrInvalidIfMatch)
+			return
+		}
+
+		var tokenProfile *domain.TokenProfile
+		if req.TokenProfile != nil {
+			profile := domain.TokenProfile(*req.TokenProfile)
+			tokenProfile = &profile
+		}
+
+		client, err := h.OAuth2Service.UpdateClient(r.Context(), id, expectedVersion, req.Name, req.Description, req.Scopes, tokenProfile, req.GrantTypes, req.RedirectURIs, req.Tags)
+		if err != nil {
+			h.Logger.Warn("failed to update oauth client", zap.Error(err), logging.String("id", id))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		resp := response.OAuthClientResponse{
+			ID:                    client.ID,
+			ClientID:              client.ClientID,
+			Name:                  client.Name,
+			Description:           client.Description,
+			Scopes:                client.Scopes,
+			Active:                client.Active,
+			RequireSignedRequests: client.RequireSignedRequests,
+			TokenProfile:          client.TokenProfile,
+			GrantTypes:            client.AllowedGrantTypes(),
+			RedirectURIs:          client.RedirectURIs,
+			Tags:                  client.Tags,
+			SecretExpiresAt:       client.SecretExpiresAt,
+			CreatedAt:             client.CreatedAt,
+			UpdatedAt:             client.UpdatedAt,
+			Version:               client.Version,
+		}
+
+		shared.SetVersionETag(w, client.Version)
+		shared.RespondWithJSON(w, nethttp.StatusOK, resp)
+	}
+}
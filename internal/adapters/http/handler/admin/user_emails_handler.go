@@ -0,0 +1,50 @@
+package admin
+
+import (
+	nethttp "net/http"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+)
+
+// ListUserEmails retrieves the secondary email addresses of a user (ADMIN only)
+// @Summary List User Emails
+// @Description List the secondary (recovery) email addresses of a user and their verification state
+// @Tags Admin - Users
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {array} response.UserEmailResponse "Secondary email addresses"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden - Admin role required"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /admin/users/{id}/emails [get]
+func ListUserEmails(h *shared.AdminUsersHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		id := mux.Vars(r)["id"]
+		emails, err := h.UserEmailService.ListUserEmails(r.Context(), id)
+		if err != nil {
+			h.Logger.Error("failed to list user emails", zap.Error(err), logging.String("id", id))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		resp := make([]response.UserEmailResponse, 0, len(emails))
+		for _, email := range emails {
+			resp = append(resp, response.UserEmailResponse{
+				ID:         email.ID,
+				Email:      email.Email,
+				Verified:   email.IsVerified(),
+				VerifiedAt: email.VerifiedAt,
+				CreatedAt:  email.CreatedAt,
+			})
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusOK, resp)
+	}
+}
@@ -0,0 +1,103 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	nethttp "net/http"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/request"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+)
+
+// MergeUser merges a duplicate account into a user (ADMIN only)
+// @Summary Merge User
+// @Description Merges a duplicate account, e.g. a second citizen ID of the same person from a legacy import, into the user.
+// @Description The consents, the primary and verified emails, the phone number and the audit records of the duplicate move to the user,
+// @Description the sessions of the duplicate are ended, the duplicate is soft-deleted and a user.merged event is published.
+// @Description With dry_run nothing changes and the response previews what the merge would move.
+// @Tags Admin - Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID of the surviving user"
+// @Param request body request.MergeUserRequest true "Duplicate account"
+// @Success 200 {object} response.UserMergeResponse "Users merged, or merge previewed"
+// @Failure 400 {object} response.ErrorResponse "Invalid request body"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden - Admin role required"
+// @Failure 404 {object} response.ErrorResponse "User not found"
+// @Failure 409 {object} response.ErrorResponse "Users can't be merged"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /admin/users/{id}/merge [post]
+func MergeUser(h *shared.AdminUsersHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		claims, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			httperrors.RespondWithError(w, httperrors.ErrUnauthorized)
+			return
+		}
+
+		var req request.MergeUserRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.Logger.Debug("invalid request body", zap.Error(err))
+			httperrors.RespondWithError(w, httperrors.ErrInvalidRequestBody)
+			return
+		}
+
+		if req.MergedUserID == "" {
+			httperrors.RespondWithError(w, httperrors.ErrRequiredField)
+			return
+		}
+
+		id := mux.Vars(r)["id"]
+		merge, err := h.UserMergeService.MergeUsers(r.Context(), id, req.MergedUserID, fmt.Sprintf("admin:%d", claims.IDCitizen), req.DryRun)
+		if err != nil {
+			h.Logger.Warn("failed to merge users", zap.Error(err), logging.String("id", id), logging.String("merged_user_id", req.MergedUserID))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		resp := response.UserMergeResponse{
+			User:         toMergedUserResponse(merge.Survivor),
+			MergedUser:   toMergedUserResponse(merge.Merged),
+			DryRun:       merge.DryRun,
+			Consents:     merge.Consents,
+			Emails:       merge.Emails,
+			AuditRecords: merge.AuditRecords,
+			Sessions:     merge.Sessions,
+		}
+		if resp.Consents == nil {
+			resp.Consents = []string{}
+		}
+		if resp.Emails == nil {
+			resp.Emails = []string{}
+		}
+		if merge.PhoneNumber != "" {
+			resp.PhoneNumber = domain.MaskPhoneNumber(merge.PhoneNumber)
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusOK, resp)
+	}
+}
+
+// toMergedUserResponse converts a user of a merge
+func toMergedUserResponse(user *domain.User) response.UserResponse {
+	return response.UserResponse{
+		ID:        user.ID,
+		IDCitizen: user.IDCitizen,
+		Email:     user.Email,
+		Name:      user.Name,
+		Role:      user.Role,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+		Version:   user.Version,
+	}
+}
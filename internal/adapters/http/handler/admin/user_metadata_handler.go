@@ -0,0 +1,96 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	nethttp "net/http"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/request"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+)
+
+// UpdateUserMetadata changes the custom metadata of a user (ADMIN only)
+// @Summary Update User Metadata
+// @Description Sets custom key-value attributes of a user. Keys must be declared in the metadata schema with the type of their values
+// @Description (string, number or boolean). Keys set to null are removed, omitted keys keep their current value.
+// @Description Keys in the claims allowlist are copied to the access tokens issued afterwards.
+// @Tags Admin - Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param If-Match header string true "ETag of the user the change was decided on, e.g. \"3\""
+// @Param request body request.UpdateUserMetadataRequest true "Metadata changes"
+// @Success 200 {object} response.UserResponse "Metadata updated successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid request, undeclared key, value of the wrong type or invalid If-Match header"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden - Admin role required"
+// @Failure 404 {object} response.ErrorResponse "User not found"
+// @Failure 409 {object} response.ErrorResponse "User is anonymized, or was modified since the If-Match version"
+// @Failure 413 {object} response.ErrorResponse "Metadata exceeds the maximum size"
+// @Failure 428 {object} response.ErrorResponse "Missing If-Match header"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /admin/users/{id}/metadata [put]
+func UpdateUserMetadata(h *shared.UserMetadataHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		claims, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			httperrors.RespondWithError(w, httperrors.ErrUnauthorized)
+			return
+		}
+
+		var req request.UpdateUserMetadataRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.Logger.Debug("invalid request body", zap.Error(err))
+			httperrors.RespondWithError(w, httperrors.ErrInvalidRequestBody)
+			return
+		}
+
+		if len(req.Metadata) == 0 {
+			httperrors.RespondWithError(w, httperrors.ErrRequiredField)
+			return
+		}
+
+		expectedVersion, err := shared.ExpectedVersion(r)
+		if errors.Is(err, domainerrors.ErrVersionRequired) {
+			httperrors.RespondWithError(w, httperrors.ErrPreconditionRequired)
+			return
+		}
+		if err != nil {
+			httperrors.RespondWithError(w, httperrors.ErrInvalidIfMatch)
+			return
+		}
+
+		id := mux.Vars(r)["id"]
+		user, err := h.UserMetadataService.UpdateUserMetadata(r.Context(), id, expectedVersion, req.Metadata, fmt.Sprintf("admin:%d", claims.IDCitizen))
+		if err != nil {
+			h.Logger.Warn("failed to update user metadata", zap.Error(err), logging.String("id", id))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		resp := response.UserResponse{
+			ID:        user.ID,
+			IDCitizen: user.IDCitizen,
+			Email:     user.Email,
+			Name:      user.Name,
+			Role:      user.Role,
+			Metadata:  user.Metadata,
+			CreatedAt: user.CreatedAt,
+			UpdatedAt: user.UpdatedAt,
+			Version:   user.Version,
+		}
+
+		shared.SetVersionETag(w, user.Version)
+		shared.RespondWithJSON(w, nethttp.StatusOK, resp)
+	}
+}
@@ -0,0 +1,97 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	nethttp "net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/request"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+)
+
+// ChangeUserRole changes the role of a user (ADMIN only)
+// @Summary Change User Role
+// @Description Changes the role of a user. All the tokens of the user are revoked, so the user must log in again
+// @Description and gets the new role, and a user.role_changed event is published. Nothing changes when the user already has the role.
+// @Tags Admin - Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param If-Match header string true "ETag of the user the change was decided on, e.g. \"3\""
+// @Param request body request.ChangeUserRoleRequest true "New role"
+// @Success 200 {object} response.UserResponse "Role changed successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid request body, role or If-Match header"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden - Admin role required"
+// @Failure 404 {object} response.ErrorResponse "User not found"
+// @Failure 409 {object} response.ErrorResponse "User is anonymized, or was modified since the If-Match version"
+// @Failure 428 {object} response.ErrorResponse "Missing If-Match header"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /admin/users/{id}/role [put]
+func ChangeUserRole(h *shared.AdminUsersHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		claims, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			httperrors.RespondWithError(w, httperrors.ErrUnauthorized)
+			return
+		}
+
+		var req request.ChangeUserRoleRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.Logger.Debug("invalid request body", zap.Error(err))
+			httperrors.RespondWithError(w, httperrors.ErrInvalidRequestBody)
+			return
+		}
+
+		role, err := domain.ParseRole(strings.ToUpper(strings.TrimSpace(req.Role)))
+		if err != nil {
+			h.Logger.Debug("invalid role", zap.Error(err))
+			httperrors.RespondWithError(w, httperrors.ErrInvalidRole)
+			return
+		}
+
+		expectedVersion, err := shared.ExpectedVersion(r)
+		if errors.Is(err, domainerrors.ErrVersionRequired) {
+			httperrors.RespondWithError(w, httperrors.ErrPreconditionRequired)
+			return
+		}
+		if err != nil {
+			httperrors.RespondWithError(w, httperrors.ErrInvalidIfMatch)
+			return
+		}
+
+		id := mux.Vars(r)["id"]
+		user, err := h.RoleService.ChangeUserRole(r.Context(), id, expectedVersion, role, fmt.Sprintf("admin:%d", claims.IDCitizen))
+		if err != nil {
+			h.Logger.Warn("failed to change user role", zap.Error(err), logging.String("id", id))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		resp := response.UserResponse{
+			ID:        user.ID,
+			IDCitizen: user.IDCitizen,
+			Email:     user.Email,
+			Name:      user.Name,
+			Role:      user.Role,
+			CreatedAt: user.CreatedAt,
+			UpdatedAt: user.UpdatedAt,
+			Version:   user.Version,
+		}
+
+		shared.SetVersionETag(w, user.Version)
+		shared.RespondWithJSON(w, nethttp.StatusOK, resp)
+	}
+}
@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"errors"
+	"io"
+	nethttp "net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+)
+
+const (
+	// AvatarFormField is the multipart form field of the avatar image
+	AvatarFormField = "avatar"
+
+	// avatarMultipartOverhead is the room left for the multipart boundaries and part headers
+	avatarMultipartOverhead = 16 << 10
+)
+
+// UploadAvatar replaces the avatar of the authenticated user
+// @Summary Upload avatar
+// @Description Upload a PNG, JPEG or WebP image as the avatar of the authenticated user, in the avatar field of a multipart form.
+// @Description The format is detected from the content of the image. The response carries a signed URL of the avatar, valid for a limited time.
+// @Description Only available when an avatar storage is configured.
+// @Tags Authentication
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param avatar formData file true "Avatar image"
+// @Success 200 {object} response.AvatarResponse "Avatar uploaded"
+// @Failure 400 {object} response.ErrorResponse "Invalid multipart body or missing avatar"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized or invalid token"
+// @Failure 413 {object} response.ErrorResponse "Avatar exceeds the maximum size"
+// @Failure 415 {object} response.ErrorResponse "Avatar is not a PNG, JPEG or WebP image"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /me/avatar [put]
+func UploadAvatar(h *shared.AuthHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		claims, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			httperrors.RespondWithError(w, httperrors.ErrUnauthorized)
+			return
+		}
+
+		maxSize := h.AvatarService.MaxSize()
+		r.Body = nethttp.MaxBytesReader(w, r.Body, maxSize+avatarMultipartOverhead)
+
+		data, httpErr := readAvatar(r, maxSize)
+		if httpErr != nil {
+			h.Logger.Debug("invalid avatar upload", zap.String("error", httpErr.Message), zap.Int("id_citizen", claims.IDCitizen))
+			httperrors.RespondWithError(w, httpErr)
+			return
+		}
+
+		avatar, avatarURL, err := h.AvatarService.UploadAvatar(r.Context(), claims.IDCitizen, data)
+		if err != nil {
+			h.Logger.Warn("failed to upload avatar", zap.Error(err), zap.Int("id_citizen", claims.IDCitizen))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusOK, response.AvatarResponse{
+			AvatarURL:   avatarURL,
+			ContentType: avatar.ContentType,
+			Size:        avatar.Size,
+			UpdatedAt:   avatar.UpdatedAt,
+		})
+	}
+}
+
+// readAvatar reads the avatar field of a multipart body. Up to maxSize+1 bytes are read so the service
+// can reject larger images without the whole file being buffered.
+func readAvatar(r *nethttp.Request, maxSize int64) ([]byte, *httperrors.HTTPError) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, httperrors.ErrInvalidRequestBody
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, httperrors.ErrRequiredField
+		}
+		if err != nil {
+			return nil, bodyReadError(err)
+		}
+		if part.FormName() != AvatarFormField {
+			continue
+		}
+
+		data, err := io.ReadAll(io.LimitReader(part, maxSize+1))
+		if err != nil {
+			return nil, bodyReadError(err)
+		}
+		return data, nil
+	}
+}
+
+// bodyReadError maps an error reading the request body, which fails once the body size limit is reached
+func bodyReadError(err error) *httperrors.HTTPError {
+	var maxBytesErr *nethttp.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return httperrors.ErrAvatarTooLarge
+	}
+	return httperrors.ErrInvalidRequestBody
+}
@@ -0,0 +1,85 @@
+package auth
+
+import (
+	nethttp "net/http"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+)
+
+// ListConsents lists the OAuth2 consents granted by the authenticated user
+// @Summary List consents
+// @Description List the third-party clients the authenticated user has authorized and the scopes granted to each
+// @Tags Authentication
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} response.ConsentResponse "Consents granted by the user"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized or invalid token"
+// @Failure 404 {object} response.ErrorResponse "User not found"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /me/consents [get]
+func ListConsents(h *shared.ConsentHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		claims, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			httperrors.RespondWithError(w, httperrors.ErrUnauthorized)
+			return
+		}
+
+		consents, err := h.ConsentService.ListConsents(r.Context(), claims.IDCitizen)
+		if err != nil {
+			h.Logger.Error("failed to list consents", zap.Error(err), zap.Int("id_citizen", claims.IDCitizen))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		consentResponses := make([]response.ConsentResponse, 0, len(consents))
+		for _, consent := range consents {
+			consentResponses = append(consentResponses, response.ConsentResponse{
+				ClientID:  consent.ClientID,
+				Scopes:    consent.Scopes,
+				GrantedAt: consent.GrantedAt,
+				UpdatedAt: consent.UpdatedAt,
+			})
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusOK, consentResponses)
+	}
+}
+
+// RevokeConsent revokes the consent granted by the authenticated user to a client
+// @Summary Revoke consent
+// @Description Revoke the consent granted to a third-party client. The user is prompted again on the next authorization.
+// @Tags Authentication
+// @Produce json
+// @Security BearerAuth
+// @Param client_id path string true "OAuth client ID"
+// @Success 204 "Consent revoked"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized or invalid token"
+// @Failure 404 {object} response.ErrorResponse "Consent or user not found"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /me/consents/{client_id} [delete]
+func RevokeConsent(h *shared.ConsentHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		claims, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			httperrors.RespondWithError(w, httperrors.ErrUnauthorized)
+			return
+		}
+
+		clientID := mux.Vars(r)["client_id"]
+		if err := h.ConsentService.RevokeConsent(r.Context(), claims.IDCitizen, clientID); err != nil {
+			h.Logger.Warn("failed to revoke consent", zap.Error(err), zap.Int("id_citizen", claims.IDCitizen), logging.String("client_id", clientID))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		w.WriteHeader(nethttp.StatusNoContent)
+	}
+}
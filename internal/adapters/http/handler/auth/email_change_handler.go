@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/request"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+)
+
+// RequestEmailChange starts the change of the primary email of the authenticated user
+// @Summary Request email change
+// @Description Send a single-use confirmation link to the new address and alert the current address of the request. The email only changes once the link is confirmed with /email-change/confirm; a new request replaces the pending one. Requires sudo mode when it is enforced. Emails are rate limited per address.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.EmailChangeRequest true "New email address"
+// @Success 202 {object} response.EmailChangeResponse "Confirmation link sent"
+// @Failure 400 {object} response.ErrorResponse "Invalid email address"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized or invalid token"
+// @Failure 403 {object} response.ErrorResponse "Sudo mode required"
+// @Failure 404 {object} response.ErrorResponse "User not found"
+// @Failure 409 {object} response.ErrorResponse "Email already registered"
+// @Failure 429 {object} response.ErrorResponse "Too many emails sent to this address"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /me/email [put]
+func RequestEmailChange(h *shared.EmailChangeHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		claims, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			httperrors.RespondWithError(w, httperrors.ErrUnauthorized)
+			return
+		}
+
+		var req request.EmailChangeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.Logger.Debug("invalid request body", zap.Error(err))
+			httperrors.RespondWithError(w, httperrors.ErrInvalidRequestBody)
+			return
+		}
+
+		if req.Email == "" {
+			httperrors.RespondWithError(w, httperrors.ErrRequiredField)
+			return
+		}
+
+		user, err := h.EmailChangeService.RequestEmailChange(r.Context(), claims.IDCitizen, req.Email)
+		if err != nil {
+			h.Logger.Warn("failed to request email change", zap.Error(err), zap.Int("id_citizen", claims.IDCitizen))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		resp := response.EmailChangeResponse{PendingEmail: user.PendingEmail}
+		if user.PendingEmailExpiresAt != nil {
+			resp.ExpiresAt = *user.PendingEmailExpiresAt
+		}
+		shared.RespondWithJSON(w, nethttp.StatusAccepted, resp)
+	}
+}
+
+// ConfirmEmailChange applies a pending email change with its confirmation token
+// @Summary Confirm email change
+// @Description Replace the primary email of the account with the new address the token was sent to. Tokens are single use and expire, an expired token discards the pending change.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body request.ConfirmEmailChangeRequest true "Confirmation token"
+// @Success 204 "Email changed"
+// @Failure 400 {object} response.ErrorResponse "Invalid or expired token"
+// @Failure 409 {object} response.ErrorResponse "Email already registered"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /email-change/confirm [post]
+func ConfirmEmailChange(h *shared.EmailChangeHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		var req request.ConfirmEmailChangeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.Logger.Debug("invalid request body", zap.Error(err))
+			httperrors.RespondWithError(w, httperrors.ErrInvalidRequestBody)
+			return
+		}
+
+		if req.Token == "" {
+			httperrors.RespondWithError(w, httperrors.ErrRequiredField)
+			return
+		}
+
+		if err := h.EmailChangeService.ConfirmEmailChange(r.Context(), req.Token); err != nil {
+			h.Logger.Warn("failed to confirm email change", zap.Error(err))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		w.WriteHeader(nethttp.StatusNoContent)
+	}
+}
@@ -0,0 +1,34 @@
+package auth
+
+import (
+	nethttp "net/http"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+)
+
+// ErrorCatalog lists every error code the API can return
+// @Summary List API error codes
+// @Description Get the machine-readable catalog of error codes, with their HTTP status and description, found in the code field of error responses
+// @Tags Errors
+// @Produce json
+// @Success 200 {object} response.ErrorCatalogResponse "Error catalog"
+// @Router /errors/catalog [get]
+func ErrorCatalog() nethttp.HandlerFunc {
+	// The catalog is fixed at startup, build the response once
+	catalog := httperrors.Catalog()
+	resp := response.ErrorCatalogResponse{Errors: make([]response.ErrorCatalogEntry, 0, len(catalog))}
+	for _, err := range catalog {
+		resp.Errors = append(resp.Errors, response.ErrorCatalogEntry{
+			Code:        err.Code,
+			Status:      err.StatusCode,
+			Description: err.Message,
+		})
+	}
+
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+		shared.RespondWithJSON(w, nethttp.StatusOK, resp)
+	}
+}
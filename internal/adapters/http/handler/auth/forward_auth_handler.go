@@ -0,0 +1,140 @@
+package auth
+
+import (
+	nethttp "net/http"
+	"net/url"
+	"strings"
+
+	"go.uber.org/zap"
+
+	_ "github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response" // Used in Swagger annotations
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+)
+
+// Identity headers of the oauth2-proxy convention, returned by the forward auth endpoint in addition to the
+// X-User-* headers
+const (
+	HeaderAuthRequestUser  = "X-Auth-Request-User"
+	HeaderAuthRequestEmail = "X-Auth-Request-Email"
+)
+
+// redirectParam is the query parameter of the login URL holding the URL requested by the browser
+const redirectParam = "rd"
+
+// ForwardAuth authenticates the requests forwarded by a reverse proxy on behalf of a protected application
+// @Summary Forward authentication
+// @Description Authenticates a request forwarded by Traefik ForwardAuth, Envoy ext_authz or nginx auth_request.
+// @Description The original request is described by the X-Forwarded-Host, X-Forwarded-Proto and X-Forwarded-Uri headers,
+// @Description and the forwarded host must be one of the trusted hosts.
+// @Description The access token is read from the Authorization header, or from the session cookie for browsers.
+// @Description On success the identity of the user is returned in headers, to be copied to the upstream request.
+// @Description Unauthenticated browser requests (Accept: text/html) get a Location header with the login URL and the original URL in the rd parameter.
+// @Tags Authentication
+// @Security BearerAuth
+// @Param X-Forwarded-Host header string true "Host of the original request"
+// @Param X-Forwarded-Proto header string false "Scheme of the original request"
+// @Param X-Forwarded-Uri header string false "URI of the original request"
+// @Success 200 "Request authenticated"
+// @Header 200 {string} X-User-Id "ID of the user"
+// @Header 200 {string} X-User-Role "Role of the user"
+// @Header 200 {integer} X-Citizen-Id "Citizen ID of the user"
+// @Header 200 {string} X-Auth-Request-User "ID of the user, citizen ID for tokens issued without it"
+// @Header 200 {string} X-Auth-Request-Email "Email of the user"
+// @Failure 401 {object} response.ErrorResponse "Missing, invalid, expired or revoked token"
+// @Failure 403 {object} response.ErrorResponse "Forwarded host is not trusted"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /forward-auth [get]
+func ForwardAuth(h *shared.ForwardAuthHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		forwardedHost := r.Header.Get("X-Forwarded-Host")
+		if !h.IsTrustedHost(forwardedHost) {
+			h.Logger.Warn("forward auth request for untrusted host", logging.String("host", forwardedHost))
+			httperrors.RespondWithError(w, httperrors.ErrForbidden)
+			return
+		}
+
+		token, httpErr := forwardAuthToken(r, h.CookieName)
+		if httpErr != nil {
+			respondUnauthenticated(w, r, h, httpErr)
+			return
+		}
+
+		// Signature, expiration and blacklist are checked, no database access is needed
+		claims, err := h.AuthService.ValidateAccessToken(r.Context(), token)
+		if err != nil {
+			respondUnauthenticated(w, r, h, httperrors.MapDomainError(err))
+			return
+		}
+
+		setIdentityHeaders(w, claims)
+		user := claims.UserID
+		if user == "" {
+			user = w.Header().Get(HeaderCitizenID)
+		}
+		w.Header().Set(HeaderAuthRequestUser, user)
+		w.Header().Set(HeaderAuthRequestEmail, claims.Email)
+		w.WriteHeader(nethttp.StatusOK)
+	}
+}
+
+// forwardAuthToken returns the access token of the Authorization header, or of the session cookie when
+// the request has no Authorization header
+func forwardAuthToken(r *nethttp.Request, cookieName string) (string, *httperrors.HTTPError) {
+	if authHeader := r.Header.Get("Authorization"); authHeader != "" {
+		token, ok := strings.CutPrefix(authHeader, "Bearer ")
+		if !ok || token == "" {
+			return "", httperrors.ErrInvalidAuthHeader
+		}
+		return token, nil
+	}
+
+	if cookieName != "" {
+		if cookie, err := r.Cookie(cookieName); err == nil && cookie.Value != "" {
+			return cookie.Value, nil
+		}
+	}
+	return "", httperrors.ErrMissingAuthHeader
+}
+
+// respondUnauthenticated responds with the authentication error, adding the login location for browsers.
+// Errors other than authentication failures (e.g. the blacklist being unavailable) are returned as they are.
+func respondUnauthenticated(w nethttp.ResponseWriter, r *nethttp.Request, h *shared.ForwardAuthHandler, httpErr *httperrors.HTTPError) {
+	if httpErr.StatusCode == nethttp.StatusUnauthorized && h.LoginURL != "" && isBrowserRequest(r) {
+		if location, err := loginLocation(h.LoginURL, r); err == nil {
+			w.Header().Set("Location", location)
+		} else {
+			h.Logger.Error("invalid forward auth login URL", zap.Error(err))
+		}
+	}
+	httperrors.RespondWithError(w, httpErr)
+}
+
+// isBrowserRequest returns true if the original request expects an HTML page
+func isBrowserRequest(r *nethttp.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/html")
+}
+
+// loginLocation returns the login URL with the original URL of the forwarded request in the rd parameter
+func loginLocation(loginURL string, r *nethttp.Request) (string, error) {
+	location, err := url.Parse(loginURL)
+	if err != nil {
+		return "", err
+	}
+
+	proto := r.Header.Get("X-Forwarded-Proto")
+	if proto == "" {
+		proto = "https"
+	}
+	uri := r.Header.Get("X-Forwarded-Uri")
+	if uri == "" {
+		uri = "/"
+	}
+	original := proto + "://" + r.Header.Get("X-Forwarded-Host") + uri
+
+	query := location.Query()
+	query.Set(redirectParam, original)
+	location.RawQuery = query.Encode()
+	return location.String(), nil
+}
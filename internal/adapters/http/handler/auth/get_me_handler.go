@@ -2,6 +2,7 @@ package auth
 
 import (
 	nethttp "net/http"
+	"strconv"
 
 	"go.uber.org/zap"
 
@@ -9,16 +10,28 @@ import (
 	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
 	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
 	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
 )
 
+// HeaderCacheBypass skips the user cache when set to "true", for debugging. A dedicated header is used
+// rather than Cache-Control, which browsers send on every hard reload.
+const HeaderCacheBypass = "X-Cache-Bypass"
+
 // GetMe retrieves authenticated user information
 // @Summary Get current user
 // @Description Get the authenticated user's information using the JWT token
+// @Description The user is served from a short-lived cache, the X-Cache-Bypass header reads it from the database.
+// @Description avatar_url is a signed URL of the avatar, valid for a limited time, omitted when the user has none.
+// @Description metadata holds the custom attributes of the user, omitted when none is set.
+// @Description With include_activity=true the response carries the activity of the account, for security panels: last login, active sessions, MFA and last password change.
 // @Tags Authentication
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Param X-Cache-Bypass header string false "Set to true to skip the user cache"
+// @Param include_activity query bool false "Include the activity of the account"
 // @Success 200 {object} response.UserResponse "User information"
+// @Failure 400 {object} response.ErrorResponse "Invalid include_activity"
 // @Failure 401 {object} response.ErrorResponse "Unauthorized or invalid token"
 // @Failure 404 {object} response.ErrorResponse "User not found"
 // @Failure 500 {object} response.ErrorResponse "Internal server error"
@@ -32,8 +45,22 @@ func GetMe(h *shared.AuthHandler) nethttp.HandlerFunc {
 			return
 		}
 
+		includeActivity := false
+		if value := r.URL.Query().Get("include_activity"); value != "" {
+			var err error
+			if includeActivity, err = strconv.ParseBool(value); err != nil {
+				httperrors.RespondWithError(w, httperrors.ErrBadRequest)
+				return
+			}
+		}
+
+		ctx := r.Context()
+		if r.Header.Get(HeaderCacheBypass) == "true" {
+			ctx = domain.ContextWithCacheBypass(ctx)
+		}
+
 		// Get complete user
-		user, err := h.AuthService.GetUserByIDCitizen(r.Context(), claims.IDCitizen)
+		user, err := h.AuthService.GetUserByIDCitizen(ctx, claims.IDCitizen)
 		if err != nil {
 			h.Logger.Error("failed to get user", zap.Error(err), zap.Int("id_citizen", claims.IDCitizen))
 			httperrors.RespondWithDomainError(w, err)
@@ -46,10 +73,35 @@ func GetMe(h *shared.AuthHandler) nethttp.HandlerFunc {
 			Email:     user.Email,
 			Name:      user.Name,
 			Role:      user.Role,
+			Metadata:  user.Metadata,
 			CreatedAt: user.CreatedAt,
 			UpdatedAt: user.UpdatedAt,
 		}
 
+		// The profile is still served when the avatar can't be
+		if h.AvatarService != nil {
+			avatarURL, err := h.AvatarService.AvatarURL(r.Context(), user.ID)
+			if err != nil {
+				h.Logger.Warn("failed to get avatar url", zap.Error(err), zap.String("user_id", user.ID))
+			}
+			resp.AvatarURL = avatarURL
+		}
+
+		if includeActivity {
+			activity, err := h.AuthService.GetAccountActivity(r.Context(), claims.IDCitizen)
+			if err != nil {
+				h.Logger.Error("failed to get account activity", zap.Error(err), zap.Int("id_citizen", claims.IDCitizen))
+				httperrors.RespondWithDomainError(w, err)
+				return
+			}
+			resp.Activity = &response.AccountActivityResponse{
+				LastLoginAt:         activity.LastLoginAt,
+				ActiveSessionsCount: activity.ActiveSessionsCount,
+				MFAEnabled:          activity.MFAEnabled,
+				PasswordChangedAt:   activity.PasswordChangedAt,
+			}
+		}
+
 		shared.RespondWithJSON(w, nethttp.StatusOK, resp)
 	}
 }
@@ -3,6 +3,8 @@ package auth
 import (
 	"encoding/json"
 	nethttp "net/http"
+	"strconv"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -10,19 +12,30 @@ import (
 	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
 	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
 	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
 	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
 )
 
 // Login handles user authentication
 // @Summary User login
-// @Description Authenticates a user and returns access and refresh tokens
+// @Description Authenticates a user and returns access and refresh tokens.
+// @Description The user profile is embedded when include_user is true (query parameter or body field), saving a /me call.
+// @Description When phone login is enabled, users can log in with their verified phone_number instead of their email,
+// @Description with their password or with a code requested at /login/phone/code.
+// @Description In cookie mode, the access token is also set in an HttpOnly cookie.
 // @Tags Authentication
 // @Accept json
 // @Produce json
 // @Param request body request.LoginRequest true "Login credentials"
-// @Success 200 {object} response.TokenResponse "Login successful, tokens generated"
-// @Failure 400 {object} response.ErrorResponse "Invalid request or missing data"
+// @Param include_user query bool false "Embed the user profile in the response (defaults to the service configuration)"
+// @Success 200 {object} response.LoginResponse "Login successful, tokens generated"
+// @Header 200 {string} X-JWS-Signature "Detached JWS of the response body, when response signing is enabled"
+// @Failure 400 {object} response.ErrorResponse "Invalid request or missing data, or phone login disabled"
 // @Failure 401 {object} response.ErrorResponse "Invalid credentials"
+// @Failure 403 {object} response.ErrorResponse "User account is suspended, authentication denied by the risk policy or maximum number of active sessions reached"
+// @Failure 429 {object} response.ErrorResponse "Token issuance quota exceeded, see the Retry-After header"
 // @Failure 500 {object} response.ErrorResponse "Internal server error"
 // @Router /login [post]
 func Login(h *shared.AuthHandler) nethttp.HandlerFunc {
@@ -37,27 +50,136 @@ func Login(h *shared.AuthHandler) nethttp.HandlerFunc {
 		}
 
 		// Basic validations
-		if req.Email == "" || req.Password == "" {
+		if req.PhoneNumber == "" && (req.Email == "" || req.Password == "") {
 			httperrors.RespondWithError(w, httperrors.ErrRequiredField)
 			return
 		}
+		if req.PhoneNumber != "" && req.Password == "" && req.Code == "" {
+			httperrors.RespondWithError(w, httperrors.ErrRequiredField)
+			return
+		}
+
+		includeUser, err := includeUserOnLogin(r, req, h.IncludeUserOnLogin)
+		if err != nil {
+			httperrors.RespondWithError(w, httperrors.ErrInvalidRequestBody)
+			return
+		}
 
 		// Authenticate user
-		tokenPair, err := h.AuthService.Login(r.Context(), req.Email, req.Password)
+		var tokenPair *domain.TokenPair
+		var user *domain.UserPublic
+		switch {
+		case req.PhoneNumber != "":
+			tokenPair, user, err = phoneLogin(r, h, req)
+			if !includeUser {
+				user = nil
+			}
+		case includeUser:
+			tokenPair, user, err = h.AuthService.LoginWithUser(r.Context(), req.Email, req.Password)
+		default:
+			tokenPair, err = h.AuthService.Login(r.Context(), req.Email, req.Password)
+		}
 		if err != nil {
-			h.Logger.Warn("login failed", zap.Error(err), zap.String("email", req.Email))
+			h.Logger.Warn("login failed", zap.Error(err), logging.String("email", req.Email), logging.String("phone_number", domain.MaskPhoneNumber(req.PhoneNumber)))
 			httperrors.RespondWithDomainError(w, err)
 			return
 		}
 
 		// Convert to DTO
-		resp := response.TokenResponse{
-			AccessToken:  tokenPair.AccessToken,
-			RefreshToken: tokenPair.RefreshToken,
-			TokenType:    tokenPair.TokenType,
-			ExpiresIn:    tokenPair.ExpiresIn,
+		resp := response.LoginResponse{
+			TokenResponse: response.TokenResponse{
+				AccessToken:  tokenPair.AccessToken,
+				RefreshToken: tokenPair.RefreshToken,
+				TokenType:    tokenPair.TokenType,
+				ExpiresIn:    tokenPair.ExpiresIn,
+			},
+		}
+		if user != nil {
+			resp.User = &response.UserResponse{
+				ID:        user.ID,
+				IDCitizen: user.IDCitizen,
+				Email:     user.Email,
+				Name:      user.Name,
+				Role:      user.Role,
+				Metadata:  user.Metadata,
+				CreatedAt: user.CreatedAt,
+				UpdatedAt: user.UpdatedAt,
+			}
+		}
+
+		if h.SessionCookie != nil {
+			h.SessionCookie.Set(w, tokenPair.AccessToken, time.Duration(tokenPair.ExpiresIn)*time.Second)
 		}
 
 		shared.RespondWithJSON(w, nethttp.StatusOK, resp)
 	}
 }
+
+// RequestPhoneLoginCode sends a login code by SMS
+// @Summary Request phone login code
+// @Description Send a one-time login code by SMS to a verified phone number, to log in at /login with phone_number and code.
+// @Description The response is the same whether the number is registered or not. SMS are rate limited per number and subject to a global quota.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body request.PhoneLoginCodeRequest true "Phone number"
+// @Success 202 {object} response.MessageResponse "Login code sent if the number is registered"
+// @Failure 400 {object} response.ErrorResponse "Invalid phone number or phone login disabled"
+// @Failure 429 {object} response.ErrorResponse "Too many SMS sent to this phone number"
+// @Failure 502 {object} response.ErrorResponse "SMS provider failure"
+// @Failure 503 {object} response.ErrorResponse "SMS quota exceeded"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /login/phone/code [post]
+func RequestPhoneLoginCode(h *shared.AuthHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		var req request.PhoneLoginCodeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.Logger.Debug("invalid request body", zap.Error(err))
+			httperrors.RespondWithError(w, httperrors.ErrInvalidRequestBody)
+			return
+		}
+
+		if req.PhoneNumber == "" {
+			httperrors.RespondWithError(w, httperrors.ErrRequiredField)
+			return
+		}
+
+		if h.PhoneLoginService == nil {
+			httperrors.RespondWithError(w, httperrors.ErrPhoneLoginDisabled)
+			return
+		}
+
+		if err := h.PhoneLoginService.RequestLoginCode(r.Context(), req.PhoneNumber); err != nil {
+			h.Logger.Warn("failed to send phone login code", zap.Error(err), logging.String("phone_number", domain.MaskPhoneNumber(req.PhoneNumber)))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusAccepted, response.MessageResponse{
+			Message: "If the phone number is registered, a login code has been sent to it",
+		})
+	}
+}
+
+// phoneLogin authenticates the user by phone number, with the password when given and the login code otherwise
+func phoneLogin(r *nethttp.Request, h *shared.AuthHandler, req request.LoginRequest) (*domain.TokenPair, *domain.UserPublic, error) {
+	if h.PhoneLoginService == nil {
+		return nil, nil, domainerrors.ErrPhoneLoginDisabled
+	}
+	if req.Password != "" {
+		return h.PhoneLoginService.LoginWithPassword(r.Context(), req.PhoneNumber, req.Password)
+	}
+	return h.PhoneLoginService.LoginWithCode(r.Context(), req.PhoneNumber, req.Code)
+}
+
+// includeUserOnLogin resolves whether the user is embedded in the login response:
+// the query parameter wins over the body field, which wins over the configured default
+func includeUserOnLogin(r *nethttp.Request, req request.LoginRequest, defaultValue bool) (bool, error) {
+	if value := r.URL.Query().Get("include_user"); value != "" {
+		return strconv.ParseBool(value)
+	}
+	if req.IncludeUser != nil {
+		return *req.IncludeUser, nil
+	}
+	return defaultValue, nil
+}
@@ -16,7 +16,8 @@ import (
 
 // Logout handles user logout
 // @Summary User logout
-// @Description Invalidates user tokens (access and refresh)
+// @Description Invalidates user tokens (access and refresh). In cookie mode, the access token can be sent in
+// @Description the cookie instead of the Authorization header, and the cookie is cleared.
 // @Tags Authentication
 // @Accept json
 // @Produce json
@@ -41,6 +42,13 @@ func Logout(h *shared.AuthHandler) nethttp.HandlerFunc {
 			}
 		}
 
+		// Browser sessions in cookie mode send the access token in the cookie
+		if accessToken == "" && h.SessionCookie != nil {
+			if cookie, err := r.Cookie(h.SessionCookie.Name); err == nil {
+				accessToken = cookie.Value
+			}
+		}
+
 		// Get refresh token from body (optional)
 		var req request.LogoutRequest
 		_ = json.NewDecoder(r.Body).Decode(&req)
@@ -57,6 +65,10 @@ func Logout(h *shared.AuthHandler) nethttp.HandlerFunc {
 			return
 		}
 
+		if h.SessionCookie != nil {
+			h.SessionCookie.Clear(w)
+		}
+
 		resp := response.MessageResponse{Message: "logout successful"}
 		shared.RespondWithJSON(w, nethttp.StatusOK, resp)
 	}
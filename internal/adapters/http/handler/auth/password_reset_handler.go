@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/request"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+)
+
+// RequestPasswordReset sends a password reset token by email
+// @Summary Request password reset
+// @Description Send a single-use password reset token to the address, which can be the primary email of the account or a verified secondary email.
+// @Description The response is the same whether the address is registered or not. Emails are rate limited per address.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body request.PasswordResetRequest true "Email address"
+// @Success 202 {object} response.MessageResponse "Reset token sent if the address is registered"
+// @Failure 400 {object} response.ErrorResponse "Invalid email address"
+// @Failure 429 {object} response.ErrorResponse "Too many emails sent to this address"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /password-reset [post]
+func RequestPasswordReset(h *shared.PasswordResetHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		var req request.PasswordResetRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.Logger.Debug("invalid request body", zap.Error(err))
+			httperrors.RespondWithError(w, httperrors.ErrInvalidRequestBody)
+			return
+		}
+
+		if req.Email == "" {
+			httperrors.RespondWithError(w, httperrors.ErrRequiredField)
+			return
+		}
+
+		if err := h.PasswordResetService.RequestReset(r.Context(), req.Email); err != nil {
+			h.Logger.Warn("failed to request password reset", zap.Error(err))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusAccepted, response.MessageResponse{
+			Message: "If the address is registered, a password reset token has been sent to it",
+		})
+	}
+}
+
+// ConfirmPasswordReset sets a new password with a password reset token
+// @Summary Confirm password reset
+// @Description Set a new password with the token received by email. Tokens are single use and every session of the user is revoked.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body request.ConfirmPasswordResetRequest true "Reset token and new password"
+// @Success 204 "Password reset"
+// @Failure 400 {object} response.ErrorResponse "Invalid or expired token, or weak password"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /password-reset/confirm [post]
+func ConfirmPasswordReset(h *shared.PasswordResetHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		var req request.ConfirmPasswordResetRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.Logger.Debug("invalid request body", zap.Error(err))
+			httperrors.RespondWithError(w, httperrors.ErrInvalidRequestBody)
+			return
+		}
+
+		if req.Token == "" || req.NewPassword == "" {
+			httperrors.RespondWithError(w, httperrors.ErrRequiredField)
+			return
+		}
+
+		if err := h.PasswordResetService.ResetPassword(r.Context(), req.Token, req.NewPassword); err != nil {
+			h.Logger.Warn("failed to reset password", zap.Error(err))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		w.WriteHeader(nethttp.StatusNoContent)
+	}
+}
@@ -0,0 +1,181 @@
+package auth
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/request"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// GetPhone retrieves the verified phone number of the authenticated user
+// @Summary Get phone number
+// @Description Get the verified phone number used to receive one-time codes by SMS
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.PhoneNumberResponse "Phone number"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized or invalid token"
+// @Failure 404 {object} response.ErrorResponse "User or phone number not found"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /me/phone [get]
+func GetPhone(h *shared.PhoneHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		claims, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			httperrors.RespondWithError(w, httperrors.ErrUnauthorized)
+			return
+		}
+
+		phone, err := h.PhoneService.GetPhoneNumber(r.Context(), claims.IDCitizen)
+		if err != nil {
+			h.Logger.Debug("failed to get phone number", zap.Error(err), zap.Int("id_citizen", claims.IDCitizen))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusOK, toPhoneNumberResponse(phone))
+	}
+}
+
+// EnrollPhone starts the enrollment of a phone number
+// @Summary Enroll phone number
+// @Description Send a verification code by SMS to the phone number (E.164 format). The number is saved once the code is confirmed with /me/phone/verify. SMS are rate limited per number and subject to a global quota.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.EnrollPhoneRequest true "Phone number"
+// @Success 202 {object} response.PhoneVerificationResponse "Verification code sent"
+// @Failure 400 {object} response.ErrorResponse "Invalid phone number"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized or invalid token"
+// @Failure 404 {object} response.ErrorResponse "User not found"
+// @Failure 429 {object} response.ErrorResponse "Too many SMS sent to this phone number"
+// @Failure 502 {object} response.ErrorResponse "SMS provider failure"
+// @Failure 503 {object} response.ErrorResponse "SMS quota exceeded"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /me/phone [post]
+func EnrollPhone(h *shared.PhoneHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		claims, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			httperrors.RespondWithError(w, httperrors.ErrUnauthorized)
+			return
+		}
+
+		var req request.EnrollPhoneRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.Logger.Debug("invalid request body", zap.Error(err))
+			httperrors.RespondWithError(w, httperrors.ErrInvalidRequestBody)
+			return
+		}
+
+		if req.PhoneNumber == "" {
+			httperrors.RespondWithError(w, httperrors.ErrRequiredField)
+			return
+		}
+
+		verification, err := h.PhoneService.StartEnrollment(r.Context(), claims.IDCitizen, req.PhoneNumber)
+		if err != nil {
+			h.Logger.Warn("failed to start phone enrollment", zap.Error(err), zap.Int("id_citizen", claims.IDCitizen))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusAccepted, response.PhoneVerificationResponse{
+			PhoneNumber: domain.MaskPhoneNumber(verification.Number),
+			ExpiresIn:   int64(time.Until(verification.ExpiresAt).Seconds()),
+		})
+	}
+}
+
+// VerifyPhone confirms the phone number being enrolled
+// @Summary Verify phone number
+// @Description Confirm the phone number with the code received by SMS. The pending enrollment is discarded after too many wrong codes.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.VerifyPhoneRequest true "Verification code"
+// @Success 200 {object} response.PhoneNumberResponse "Phone number verified"
+// @Failure 400 {object} response.ErrorResponse "Invalid or expired verification code"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized or invalid token"
+// @Failure 404 {object} response.ErrorResponse "User not found"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /me/phone/verify [post]
+func VerifyPhone(h *shared.PhoneHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		claims, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			httperrors.RespondWithError(w, httperrors.ErrUnauthorized)
+			return
+		}
+
+		var req request.VerifyPhoneRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.Logger.Debug("invalid request body", zap.Error(err))
+			httperrors.RespondWithError(w, httperrors.ErrInvalidRequestBody)
+			return
+		}
+
+		if req.Code == "" {
+			httperrors.RespondWithError(w, httperrors.ErrRequiredField)
+			return
+		}
+
+		phone, err := h.PhoneService.VerifyEnrollment(r.Context(), claims.IDCitizen, req.Code)
+		if err != nil {
+			h.Logger.Warn("failed to verify phone number", zap.Error(err), zap.Int("id_citizen", claims.IDCitizen))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusOK, toPhoneNumberResponse(phone))
+	}
+}
+
+// DeletePhone removes the phone number of the authenticated user
+// @Summary Delete phone number
+// @Description Remove the phone number of the authenticated user
+// @Tags Authentication
+// @Produce json
+// @Security BearerAuth
+// @Success 204 "Phone number removed"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized or invalid token"
+// @Failure 403 {object} response.ErrorResponse "Elevated access required (when sudo mode is enforced)"
+// @Failure 404 {object} response.ErrorResponse "User or phone number not found"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /me/phone [delete]
+func DeletePhone(h *shared.PhoneHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		claims, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			httperrors.RespondWithError(w, httperrors.ErrUnauthorized)
+			return
+		}
+
+		if err := h.PhoneService.RemovePhoneNumber(r.Context(), claims.IDCitizen); err != nil {
+			h.Logger.Warn("failed to remove phone number", zap.Error(err), zap.Int("id_citizen", claims.IDCitizen))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		w.WriteHeader(nethttp.StatusNoContent)
+	}
+}
+
+// toPhoneNumberResponse converts the domain phone number to the response DTO
+func toPhoneNumberResponse(phone *domain.PhoneNumber) response.PhoneNumberResponse {
+	return response.PhoneNumberResponse{
+		PhoneNumber: phone.Number,
+		VerifiedAt:  phone.VerifiedAt,
+	}
+}
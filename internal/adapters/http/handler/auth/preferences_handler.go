@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/request"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// GetPreferences retrieves the notification preferences of the authenticated user
+// @Summary Get notification preferences
+// @Description Get which security notifications (new device, password change, login alert) the authenticated user receives
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.NotificationPreferencesResponse "Notification preferences"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized or invalid token"
+// @Failure 404 {object} response.ErrorResponse "User not found"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /me/preferences [get]
+func GetPreferences(h *shared.NotificationPreferencesHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		claims, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			httperrors.RespondWithError(w, httperrors.ErrUnauthorized)
+			return
+		}
+
+		prefs, err := h.NotificationService.GetPreferences(r.Context(), claims.IDCitizen)
+		if err != nil {
+			h.Logger.Error("failed to get notification preferences", zap.Error(err), zap.Int("id_citizen", claims.IDCitizen))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusOK, toNotificationPreferencesResponse(prefs))
+	}
+}
+
+// UpdatePreferences updates the notification preferences of the authenticated user
+// @Summary Update notification preferences
+// @Description Enable or disable security notifications for the authenticated user. Omitted fields keep their current value.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.UpdateNotificationPreferencesRequest true "Notification preferences"
+// @Success 200 {object} response.NotificationPreferencesResponse "Notification preferences updated"
+// @Failure 400 {object} response.ErrorResponse "Invalid request"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized or invalid token"
+// @Failure 404 {object} response.ErrorResponse "User not found"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /me/preferences [put]
+func UpdatePreferences(h *shared.NotificationPreferencesHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		claims, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			httperrors.RespondWithError(w, httperrors.ErrUnauthorized)
+			return
+		}
+
+		var req request.UpdateNotificationPreferencesRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.Logger.Debug("invalid request body", zap.Error(err))
+			httperrors.RespondWithError(w, httperrors.ErrInvalidRequestBody)
+			return
+		}
+
+		prefs, err := h.NotificationService.UpdatePreferences(r.Context(), claims.IDCitizen, req.NewDevice, req.PasswordChange, req.LoginAlert)
+		if err != nil {
+			h.Logger.Error("failed to update notification preferences", zap.Error(err), zap.Int("id_citizen", claims.IDCitizen))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusOK, toNotificationPreferencesResponse(prefs))
+	}
+}
+
+// toNotificationPreferencesResponse converts the domain preferences to the response DTO
+func toNotificationPreferencesResponse(prefs *domain.NotificationPreferences) response.NotificationPreferencesResponse {
+	return response.NotificationPreferencesResponse{
+		NewDevice:      prefs.NewDevice,
+		PasswordChange: prefs.PasswordChange,
+		LoginAlert:     prefs.LoginAlert,
+		UpdatedAt:      prefs.UpdatedAt,
+	}
+}
@@ -3,6 +3,7 @@ package auth
 import (
 	"encoding/json"
 	nethttp "net/http"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -15,14 +16,17 @@ import (
 
 // Refresh handles token renewal
 // @Summary Refresh tokens
-// @Description Generate a new token pair using a valid refresh token
+// @Description Generate a new token pair using a valid refresh token. In cookie mode, the new access token is also set in the cookie.
 // @Tags Authentication
 // @Accept json
 // @Produce json
 // @Param request body request.RefreshTokenRequest true "Refresh token"
 // @Success 200 {object} response.TokenResponse "Tokens refreshed successfully"
+// @Header 200 {string} X-JWS-Signature "Detached JWS of the response body, when response signing is enabled"
 // @Failure 400 {object} response.ErrorResponse "Invalid request or missing data"
 // @Failure 401 {object} response.ErrorResponse "Invalid or expired token"
+// @Failure 403 {object} response.ErrorResponse "User account is suspended or authentication denied by the risk policy"
+// @Failure 429 {object} response.ErrorResponse "Token issuance quota exceeded, see the Retry-After header"
 // @Failure 500 {object} response.ErrorResponse "Internal server error"
 // @Router /refresh [post]
 func Refresh(h *shared.AuthHandler) nethttp.HandlerFunc {
@@ -57,6 +61,10 @@ func Refresh(h *shared.AuthHandler) nethttp.HandlerFunc {
 			ExpiresIn:    tokenPair.ExpiresIn,
 		}
 
+		if h.SessionCookie != nil {
+			h.SessionCookie.Set(w, tokenPair.AccessToken, time.Duration(tokenPair.ExpiresIn)*time.Second)
+		}
+
 		shared.RespondWithJSON(w, nethttp.StatusOK, resp)
 	}
 }
@@ -3,6 +3,7 @@ package auth
 import (
 	"encoding/json"
 	nethttp "net/http"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -10,20 +11,24 @@ import (
 	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
 	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
 	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
 	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
 )
 
 // Register handles new user registration
 // @Summary Register a new user
-// @Description Create a new user account in the system
+// @Description Create a new user account in the system.
+// @Description When phone login is enabled, the optional phone_number is enrolled: a verification code is sent to it by SMS, to be confirmed at /me/phone/verify after logging in.
 // @Tags Authentication
 // @Accept json
 // @Produce json
 // @Param request body request.RegisterRequest true "User registration data"
-// @Success 201 {object} response.UserResponse "User created successfully"
-// @Failure 400 {object} response.ErrorResponse "Invalid request or missing data"
+// @Success 201 {object} response.RegisterResponse "User created successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid request or missing data, invalid phone number or phone login disabled"
 // @Failure 409 {object} response.ErrorResponse "User already exists"
 // @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Failure 503 {object} response.ErrorResponse "Citizen registry busy, try again later"
 // @Router /register [post]
 func Register(h *shared.AuthHandler) nethttp.HandlerFunc {
 	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
@@ -42,24 +47,51 @@ func Register(h *shared.AuthHandler) nethttp.HandlerFunc {
 			return
 		}
 
+		// The phone number is checked before the user is created, its enrollment can't fail on the format
+		if req.PhoneNumber != "" {
+			if h.PhoneLoginService == nil || !h.PhoneLoginService.Enabled() {
+				httperrors.RespondWithError(w, httperrors.ErrPhoneLoginDisabled)
+				return
+			}
+			if _, ok := domain.NormalizePhoneNumber(req.PhoneNumber); !ok {
+				httperrors.RespondWithError(w, httperrors.ErrInvalidPhoneNumber)
+				return
+			}
+		}
+
 		// Register user
 		user, err := h.AuthService.Register(r.Context(), req.Email, req.Password, req.Name, req.IDCitizen)
 		if err != nil {
 			// Use Warn for expected business errors (like user already exists), Error for unexpected failures
-			h.Logger.Warn("failed to register user", zap.Error(err), zap.String("email", req.Email))
+			h.Logger.Warn("failed to register user", zap.Error(err), logging.String("email", req.Email))
 			httperrors.RespondWithDomainError(w, err)
 			return
 		}
 
 		// Convert to DTO
-		resp := response.UserResponse{
-			ID:        user.ID,
-			IDCitizen: user.IDCitizen,
-			Email:     user.Email,
-			Name:      user.Name,
-			Role:      user.Role,
-			CreatedAt: user.CreatedAt,
-			UpdatedAt: user.UpdatedAt,
+		resp := response.RegisterResponse{
+			UserResponse: response.UserResponse{
+				ID:        user.ID,
+				IDCitizen: user.IDCitizen,
+				Email:     user.Email,
+				Name:      user.Name,
+				Role:      user.Role,
+				CreatedAt: user.CreatedAt,
+				UpdatedAt: user.UpdatedAt,
+			},
+		}
+
+		// The user is registered either way, a failed enrollment can be retried at /me/phone
+		if req.PhoneNumber != "" {
+			verification, err := h.PhoneLoginService.EnrollPhone(r.Context(), user.IDCitizen, req.PhoneNumber)
+			if err != nil {
+				h.Logger.Warn("failed to enroll phone number at registration", zap.Error(err), zap.String("user_id", user.ID))
+			} else {
+				resp.PhoneVerification = &response.PhoneVerificationResponse{
+					PhoneNumber: domain.MaskPhoneNumber(verification.Number),
+					ExpiresIn:   int64(time.Until(verification.ExpiresAt).Seconds()),
+				}
+			}
 		}
 
 		shared.RespondWithJSON(w, nethttp.StatusCreated, resp)
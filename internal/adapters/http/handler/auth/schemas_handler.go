@@ -0,0 +1,74 @@
+package auth
+
+import (
+	nethttp "net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/schema"
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+)
+
+// ListSchemas lists the JSON Schema documents of the request and response payloads
+// @Summary List payload schemas
+// @Description Get the names of the JSON Schema documents of every request and response payload, so client teams can validate their payloads against the API contracts.
+// @Description The same documents are published in docs/schemas.
+// @Tags Schemas
+// @Produce json
+// @Success 200 {object} response.SchemaCatalogResponse "Schema catalog"
+// @Router /schemas [get]
+func ListSchemas() nethttp.HandlerFunc {
+	documents := schema.Documents()
+
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		base := strings.TrimSuffix(r.URL.Path, "/")
+		resp := response.SchemaCatalogResponse{Schemas: make([]response.SchemaCatalogEntry, 0, len(documents))}
+		for _, document := range documents {
+			resp.Schemas = append(resp.Schemas, response.SchemaCatalogEntry{
+				Name:      document.Name,
+				Direction: string(document.Direction),
+				URL:       base + "/" + document.Name,
+			})
+		}
+
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+		shared.RespondWithJSON(w, nethttp.StatusOK, resp)
+	}
+}
+
+// GetSchema returns the JSON Schema document of a request or response payload
+// @Summary Get a payload schema
+// @Description Get the JSON Schema (draft 2020-12) document of a request or response payload
+// @Tags Schemas
+// @Produce json
+// @Param name path string true "Name of the payload, e.g. RegisterRequest"
+// @Success 200 {object} object "JSON Schema document"
+// @Failure 404 {object} response.ErrorResponse "Schema not found"
+// @Router /schemas/{name} [get]
+func GetSchema() nethttp.HandlerFunc {
+	// The documents are fixed at build time, encode them once
+	documents := make(map[string][]byte)
+	for _, document := range schema.Documents() {
+		// Schemas only hold strings, numbers and nested schemas, encoding them can't fail
+		if data, err := document.Marshal(); err == nil {
+			documents[document.Name] = data
+		}
+	}
+
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		name := strings.TrimSuffix(mux.Vars(r)["name"], ".json")
+		data, ok := documents[name]
+		if !ok {
+			httperrors.RespondWithError(w, httperrors.ErrNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/schema+json")
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+		w.WriteHeader(nethttp.StatusOK)
+		_, _ = w.Write(data)
+	}
+}
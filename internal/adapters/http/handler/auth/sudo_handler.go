@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/request"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// Sudo grants elevated access to the authenticated user
+// @Summary Enter sudo mode
+// @Description Re-authenticates the user with their password and returns a short-lived access token carrying the sudo_until claim.
+// @Description Destructive operations require this token; it can't be refreshed and the regular tokens keep working.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.SudoRequest true "Password of the user"
+// @Success 200 {object} response.SudoResponse "Elevated access token"
+// @Failure 400 {object} response.ErrorResponse "Invalid request"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized or invalid password"
+// @Failure 403 {object} response.ErrorResponse "User is not active"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /sudo [post]
+func Sudo(h *shared.SudoHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		claims, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			httperrors.RespondWithError(w, httperrors.ErrUnauthorized)
+			return
+		}
+
+		var req request.SudoRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.Logger.Debug("invalid request body", zap.Error(err))
+			httperrors.RespondWithError(w, httperrors.ErrInvalidRequestBody)
+			return
+		}
+
+		if req.Password == "" {
+			httperrors.RespondWithError(w, httperrors.ErrRequiredField)
+			return
+		}
+
+		grant, err := h.SudoService.Elevate(r.Context(), claims.IDCitizen, req.Password)
+		if err != nil {
+			h.Logger.Warn("failed to grant sudo mode", zap.Error(err), zap.Int("id_citizen", claims.IDCitizen))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		resp := response.SudoResponse{
+			AccessToken: grant.AccessToken,
+			TokenType:   domain.TokenTypeBearer,
+			ExpiresIn:   int64(time.Until(grant.SudoUntil).Seconds()),
+			SudoUntil:   grant.SudoUntil,
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusOK, resp)
+	}
+}
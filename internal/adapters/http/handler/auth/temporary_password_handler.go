@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/request"
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+)
+
+// ChangeTemporaryPassword replaces the temporary password of a user created by an administrator
+// @Summary Change temporary password
+// @Description Replace the temporary password given by an administrator, which login rejects with PASSWORD_CHANGE_REQUIRED until it is changed. Sign in with the new password afterwards.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body request.ChangeTemporaryPasswordRequest true "Email, temporary password and new password"
+// @Success 204 "Password changed"
+// @Failure 400 {object} response.ErrorResponse "Invalid request body or weak password"
+// @Failure 401 {object} response.ErrorResponse "Invalid credentials"
+// @Failure 409 {object} response.ErrorResponse "The password is not temporary"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /login/password-change [post]
+func ChangeTemporaryPassword(h *shared.UserProvisioningHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		var req request.ChangeTemporaryPasswordRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.Logger.Debug("invalid request body", zap.Error(err))
+			httperrors.RespondWithError(w, httperrors.ErrInvalidRequestBody)
+			return
+		}
+
+		if req.Email == "" || req.TemporaryPassword == "" || req.NewPassword == "" {
+			httperrors.RespondWithError(w, httperrors.ErrRequiredField)
+			return
+		}
+
+		if err := h.UserProvisioningService.ChangeTemporaryPassword(r.Context(), req.Email, req.TemporaryPassword, req.NewPassword); err != nil {
+			h.Logger.Warn("failed to change temporary password", zap.Error(err))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		w.WriteHeader(nethttp.StatusNoContent)
+	}
+}
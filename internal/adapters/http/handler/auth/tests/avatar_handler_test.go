@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	authhandler "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/auth"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// newAvatarRequest builds a multipart request with padding bytes in another field, then the image in the given field
+func newAvatarRequest(t *testing.T, field string, image []byte, padding int) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if padding > 0 {
+		_ = writer.WriteField("padding", string(make([]byte, padding)))
+	}
+	part, err := writer.CreateFormFile(field, "avatar.png")
+	if err != nil {
+		t.Fatalf("failed to create form file: %v", err)
+	}
+	_, _ = part.Write(image)
+	_ = writer.Close()
+
+	req := httptest.NewRequest(http.MethodPut, "/auth/me/avatar", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestUploadAvatarHandler(t *testing.T) {
+	updatedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		field          string
+		image          []byte
+		padding        int
+		rawBody        bool
+		withClaims     bool
+		uploadErr      error
+		wantStatusCode int
+		wantCode       string
+		wantReceived   int
+	}{
+		{name: "avatar uploaded", field: "avatar", image: []byte("png image"), withClaims: true, wantStatusCode: http.StatusOK, wantReceived: 9},
+		{name: "missing claims", field: "avatar", image: []byte("png image"), wantStatusCode: http.StatusUnauthorized},
+		{name: "missing avatar field", field: "picture", image: []byte("png image"), withClaims: true, wantStatusCode: http.StatusBadRequest, wantCode: "REQUIRED_FIELD"},
+		{name: "not a multipart body", rawBody: true, withClaims: true, wantStatusCode: http.StatusBadRequest, wantCode: "INVALID_REQUEST_BODY"},
+		{name: "larger images reach the service truncated", field: "avatar", image: make([]byte, 200), withClaims: true, uploadErr: domainerrors.ErrAvatarTooLarge, wantStatusCode: http.StatusRequestEntityTooLarge, wantCode: "AVATAR_TOO_LARGE", wantReceived: 101},
+		{name: "body over the limit", field: "avatar", image: []byte("png image"), padding: 64 << 10, withClaims: true, wantStatusCode: http.StatusRequestEntityTooLarge, wantCode: "AVATAR_TOO_LARGE"},
+		{name: "unsupported image", field: "avatar", image: []byte("gif"), withClaims: true, uploadErr: domainerrors.ErrUnsupportedAvatarType, wantStatusCode: http.StatusUnsupportedMediaType, wantCode: "UNSUPPORTED_AVATAR_TYPE", wantReceived: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received := 0
+			avatarService := &MockAvatarService{
+				MaxSizeValue: 100,
+				UploadAvatarFunc: func(ctx context.Context, idCitizen int, data []byte) (*domain.Avatar, string, error) {
+					received = len(data)
+					if idCitizen != 12345 {
+						t.Errorf("UploadAvatar() idCitizen = %v, want 12345", idCitizen)
+					}
+					if tt.uploadErr != nil {
+						return nil, "", tt.uploadErr
+					}
+					return &domain.Avatar{UserID: "user-123", ContentType: "image/png", Size: int64(len(data)), UpdatedAt: updatedAt},
+						"https://storage.example.com/avatars/user-123/a.png?signature=abc", nil
+				},
+			}
+
+			var req *http.Request
+			if tt.rawBody {
+				req = httptest.NewRequest(http.MethodPut, "/auth/me/avatar", bytes.NewBufferString("png image"))
+				req.Header.Set("Content-Type", "image/png")
+			} else {
+				req = newAvatarRequest(t, tt.field, tt.image, tt.padding)
+			}
+			if tt.withClaims {
+				req = req.WithContext(withUserClaims(req.Context()))
+			}
+			w := httptest.NewRecorder()
+
+			h := shared.NewAuthHandler(&MockAuthService{}, nil, avatarService, false, nil, zap.NewNop())
+			authhandler.UploadAvatar(h)(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v (%s)", w.Code, tt.wantStatusCode, w.Body.String())
+			}
+			if received != tt.wantReceived {
+				t.Errorf("bytes received by the service = %v, want %v", received, tt.wantReceived)
+			}
+
+			if tt.wantCode != "" {
+				var resp response.ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("Error code = %v, want %v", resp.Code, tt.wantCode)
+				}
+				return
+			}
+			if tt.wantStatusCode != http.StatusOK {
+				return
+			}
+
+			var resp response.AvatarResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.AvatarURL == "" || resp.ContentType != "image/png" || resp.Size != 9 || !resp.UpdatedAt.Equal(updatedAt) {
+				t.Errorf("response = %+v", resp)
+			}
+		})
+	}
+}
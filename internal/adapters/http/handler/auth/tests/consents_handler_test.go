@@ -0,0 +1,140 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	authhandler "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/auth"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestListConsentsHandler(t *testing.T) {
+	logger := zap.NewNop()
+
+	tests := []struct {
+		name           string
+		withClaims     bool
+		mockSetup      func(*MockConsentService)
+		wantStatusCode int
+		wantCount      int
+	}{
+		{
+			name:       "successful list consents",
+			withClaims: true,
+			mockSetup: func(m *MockConsentService) {
+				m.ListConsentsFunc = func(ctx context.Context, idCitizen int) ([]*domain.Consent, error) {
+					return []*domain.Consent{{UserID: "user-123", ClientID: "cli", Scopes: []string{"read"}}}, nil
+				}
+			},
+			wantStatusCode: http.StatusOK,
+			wantCount:      1,
+		},
+		{
+			name:       "no consents",
+			withClaims: true,
+			mockSetup: func(m *MockConsentService) {
+				m.ListConsentsFunc = func(ctx context.Context, idCitizen int) ([]*domain.Consent, error) {
+					return []*domain.Consent{}, nil
+				}
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "missing user context",
+			mockSetup:      func(m *MockConsentService) {},
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:       "internal error",
+			withClaims: true,
+			mockSetup: func(m *MockConsentService) {
+				m.ListConsentsFunc = func(ctx context.Context, idCitizen int) ([]*domain.Consent, error) {
+					return nil, domainerrors.ErrInternal
+				}
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockConsentService{}
+			tt.mockSetup(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, "/me/consents", nil)
+			if tt.withClaims {
+				req = req.WithContext(withUserClaims(req.Context()))
+			}
+			w := httptest.NewRecorder()
+
+			h := shared.NewConsentHandler(mockService, logger)
+			authhandler.ListConsents(h)(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+
+			if tt.wantStatusCode == http.StatusOK {
+				var resp []response.ConsentResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if len(resp) != tt.wantCount {
+					t.Errorf("returned %d consents, want %d", len(resp), tt.wantCount)
+				}
+			}
+		})
+	}
+}
+
+func TestRevokeConsentHandler(t *testing.T) {
+	logger := zap.NewNop()
+
+	tests := []struct {
+		name           string
+		withClaims     bool
+		revokeErr      error
+		wantStatusCode int
+	}{
+		{name: "successful revoke", withClaims: true, wantStatusCode: http.StatusNoContent},
+		{name: "missing user context", wantStatusCode: http.StatusUnauthorized},
+		{name: "consent not found", withClaims: true, revokeErr: domainerrors.ErrConsentNotFound, wantStatusCode: http.StatusNotFound},
+		{name: "internal error", withClaims: true, revokeErr: domainerrors.ErrInternal, wantStatusCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockConsentService{
+				RevokeConsentFunc: func(ctx context.Context, idCitizen int, clientID string) error {
+					if clientID != "cli" {
+						t.Errorf("RevokeConsent() clientID = %v, want cli", clientID)
+					}
+					return tt.revokeErr
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, "/me/consents/cli", nil)
+			req = mux.SetURLVars(req, map[string]string{"client_id": "cli"})
+			if tt.withClaims {
+				req = req.WithContext(withUserClaims(req.Context()))
+			}
+			w := httptest.NewRecorder()
+
+			h := shared.NewConsentHandler(mockService, logger)
+			authhandler.RevokeConsent(h)(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Errorf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	authhandler "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/auth"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestRequestEmailChangeHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		withClaims     bool
+		requestErr     error
+		wantStatusCode int
+	}{
+		{name: "confirmation sent", body: `{"email":"new@example.org"}`, withClaims: true, wantStatusCode: http.StatusAccepted},
+		{name: "missing user context", body: `{"email":"new@example.org"}`, wantStatusCode: http.StatusUnauthorized},
+		{name: "invalid JSON", body: `{invalid`, withClaims: true, wantStatusCode: http.StatusBadRequest},
+		{name: "missing email", body: `{}`, withClaims: true, wantStatusCode: http.StatusBadRequest},
+		{name: "invalid email", body: `{"email":"nope"}`, withClaims: true, requestErr: domainerrors.ErrInvalidEmail, wantStatusCode: http.StatusBadRequest},
+		{name: "already registered", body: `{"email":"new@example.org"}`, withClaims: true, requestErr: domainerrors.ErrEmailAlreadyRegistered, wantStatusCode: http.StatusConflict},
+		{name: "rate limited", body: `{"email":"new@example.org"}`, withClaims: true, requestErr: domainerrors.ErrEmailRateLimited, wantStatusCode: http.StatusTooManyRequests},
+	}
+
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockEmailChangeService{
+				RequestEmailChangeFunc: func(ctx context.Context, idCitizen int, email string) (*domain.User, error) {
+					if tt.requestErr != nil {
+						return nil, tt.requestErr
+					}
+					user := &domain.User{IDCitizen: idCitizen, Email: "test@example.com"}
+					user.SetPendingEmail(email, "token-hash", expiresAt)
+					return user, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPut, "/me/email", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.withClaims {
+				req = req.WithContext(withUserClaims(req.Context()))
+			}
+			w := httptest.NewRecorder()
+
+			authhandler.RequestEmailChange(shared.NewEmailChangeHandler(mockService, zap.NewNop()))(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+			if w.Code != http.StatusAccepted {
+				return
+			}
+
+			var resp response.EmailChangeResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.PendingEmail != "new@example.org" || !resp.ExpiresAt.Equal(expiresAt) {
+				t.Errorf("response = %+v, want the pending email and its expiry", resp)
+			}
+		})
+	}
+}
+
+func TestConfirmEmailChangeHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		confirmErr     error
+		wantStatusCode int
+	}{
+		{name: "email changed", body: `{"token":"abc"}`, wantStatusCode: http.StatusNoContent},
+		{name: "invalid JSON", body: `{invalid`, wantStatusCode: http.StatusBadRequest},
+		{name: "missing token", body: `{}`, wantStatusCode: http.StatusBadRequest},
+		{name: "invalid token", body: `{"token":"abc"}`, confirmErr: domainerrors.ErrInvalidEmailChangeToken, wantStatusCode: http.StatusBadRequest},
+		{name: "email taken", body: `{"token":"abc"}`, confirmErr: domainerrors.ErrEmailAlreadyRegistered, wantStatusCode: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockEmailChangeService{
+				ConfirmEmailChangeFunc: func(ctx context.Context, token string) error {
+					return tt.confirmErr
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/email-change/confirm", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			authhandler.ConfirmEmailChange(shared.NewEmailChangeHandler(mockService, zap.NewNop()))(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	authhandler "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/auth"
+)
+
+func TestErrorCatalogHandler(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/errors/catalog", nil)
+	w := httptest.NewRecorder()
+
+	authhandler.ErrorCatalog()(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status code = %v, want %v", w.Code, http.StatusOK)
+	}
+
+	var resp response.ErrorCatalogResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(resp.Errors) != len(httperrors.Catalog()) {
+		t.Fatalf("len(Errors) = %v, want %v", len(resp.Errors), len(httperrors.Catalog()))
+	}
+
+	var found bool
+	for _, entry := range resp.Errors {
+		if entry.Code == httperrors.ErrInvalidCredentials.Code {
+			found = true
+			if entry.Status != http.StatusUnauthorized || entry.Description != httperrors.ErrInvalidCredentials.Message {
+				t.Errorf("INVALID_CREDENTIALS entry = %+v", entry)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("catalog is missing %v", httperrors.ErrInvalidCredentials.Code)
+	}
+}
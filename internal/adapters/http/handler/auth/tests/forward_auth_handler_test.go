@@ -0,0 +1,150 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+
+	authhandler "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/auth"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestForwardAuthHandler(t *testing.T) {
+	validClaims := &domain.TokenClaims{
+		IDCitizen: 12345,
+		UserID:    "user-123",
+		Email:     "test@example.com",
+		Role:      domain.RoleUser,
+		Type:      domain.TokenTypeAccess,
+	}
+
+	tests := []struct {
+		name           string
+		headers        map[string]string
+		cookie         *http.Cookie
+		validateErr    error
+		wantToken      string
+		wantStatusCode int
+		wantHeaders    map[string]string
+	}{
+		{
+			name: "bearer token returns identity headers",
+			headers: map[string]string{
+				"X-Forwarded-Host": "app.example.com",
+				"Authorization":    "Bearer access_token_123",
+			},
+			wantToken:      "access_token_123",
+			wantStatusCode: http.StatusOK,
+			wantHeaders: map[string]string{
+				authhandler.HeaderUserID:           "user-123",
+				authhandler.HeaderUserRole:         "USER",
+				authhandler.HeaderCitizenID:        "12345",
+				authhandler.HeaderAuthRequestUser:  "user-123",
+				authhandler.HeaderAuthRequestEmail: "test@example.com",
+			},
+		},
+		{
+			name:           "session cookie authenticates browsers",
+			headers:        map[string]string{"X-Forwarded-Host": "app.example.com", "Accept": "text/html"},
+			cookie:         &http.Cookie{Name: "access_token", Value: "cookie_token"},
+			wantToken:      "cookie_token",
+			wantStatusCode: http.StatusOK,
+			wantHeaders:    map[string]string{authhandler.HeaderUserID: "user-123"},
+		},
+		{
+			name:           "untrusted host is forbidden",
+			headers:        map[string]string{"X-Forwarded-Host": "evil.com", "Authorization": "Bearer access_token_123"},
+			wantStatusCode: http.StatusForbidden,
+			wantHeaders:    map[string]string{authhandler.HeaderUserID: ""},
+		},
+		{
+			name:           "missing forwarded host is forbidden",
+			headers:        map[string]string{"Authorization": "Bearer access_token_123"},
+			wantStatusCode: http.StatusForbidden,
+		},
+		{
+			name: "unauthenticated browser gets the login location",
+			headers: map[string]string{
+				"X-Forwarded-Host":  "app.example.com",
+				"X-Forwarded-Proto": "https",
+				"X-Forwarded-Uri":   "/dashboard?tab=1",
+				"Accept":            "text/html,application/xhtml+xml",
+			},
+			wantStatusCode: http.StatusUnauthorized,
+			wantHeaders: map[string]string{
+				"Location": "https://auth.example.com/login?rd=https%3A%2F%2Fapp.example.com%2Fdashboard%3Ftab%3D1",
+			},
+		},
+		{
+			name:           "unauthenticated API client gets no login location",
+			headers:        map[string]string{"X-Forwarded-Host": "app.example.com", "Accept": "application/json"},
+			wantStatusCode: http.StatusUnauthorized,
+			wantHeaders:    map[string]string{"Location": ""},
+		},
+		{
+			name:           "invalid authorization header format",
+			headers:        map[string]string{"X-Forwarded-Host": "app.example.com", "Authorization": "Basic dXNlcjpwYXNz"},
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:           "revoked token of a browser gets the login location",
+			headers:        map[string]string{"X-Forwarded-Host": "app.example.com", "Accept": "text/html"},
+			cookie:         &http.Cookie{Name: "access_token", Value: "revoked_token"},
+			validateErr:    domainerrors.ErrTokenRevoked,
+			wantToken:      "revoked_token",
+			wantStatusCode: http.StatusUnauthorized,
+			wantHeaders:    map[string]string{"Location": "https://auth.example.com/login?rd=https%3A%2F%2Fapp.example.com%2F"},
+		},
+		{
+			name:           "blacklist unavailable is not a login redirect",
+			headers:        map[string]string{"X-Forwarded-Host": "app.example.com", "Accept": "text/html"},
+			cookie:         &http.Cookie{Name: "access_token", Value: "access_token_123"},
+			validateErr:    domainerrors.ErrInternal,
+			wantToken:      "access_token_123",
+			wantStatusCode: http.StatusInternalServerError,
+			wantHeaders:    map[string]string{"Location": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAuthService := &MockAuthService{
+				ValidateAccessTokenFunc: func(ctx context.Context, token string) (*domain.TokenClaims, error) {
+					if token != tt.wantToken {
+						t.Errorf("token = %q, want %q", token, tt.wantToken)
+					}
+					if tt.validateErr != nil {
+						return nil, tt.validateErr
+					}
+					return validClaims, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/forward-auth", nil)
+			for header, value := range tt.headers {
+				req.Header.Set(header, value)
+			}
+			if tt.cookie != nil {
+				req.AddCookie(tt.cookie)
+			}
+			w := httptest.NewRecorder()
+
+			h := shared.NewForwardAuthHandler(mockAuthService, []string{"app.example.com"}, "https://auth.example.com/login", "access_token", zap.NewNop())
+			authhandler.ForwardAuth(h)(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Errorf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+			for header, want := range tt.wantHeaders {
+				if got := w.Header().Get(header); got != want {
+					t.Errorf("%s = %q, want %q", header, got, want)
+				}
+			}
+		})
+	}
+}
@@ -198,7 +198,7 @@ func TestGetMeHandler(t *testing.T) {
 			w := httptest.NewRecorder()
 
 			// Use real handler with mock service injected
-			h := shared.NewAuthHandler(mockAuthService, logger)
+			h := shared.NewAuthHandler(mockAuthService, nil, nil, false, nil, logger)
 			handler := authhandler.GetMe(h)
 			handler(w, req)
 
@@ -212,3 +212,166 @@ func TestGetMeHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestGetMeHandler_CacheBypass(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		wantBypass bool
+	}{
+		{name: "cache used by default"},
+		{name: "bypass header skips the cache", header: "true", wantBypass: true},
+		{name: "other header values use the cache", header: "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var bypassed bool
+			mockAuthService := &MockAuthService{
+				GetUserByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.UserPublic, error) {
+					bypassed = domain.CacheBypassFromContext(ctx)
+					return &domain.UserPublic{ID: "user-123", IDCitizen: idCitizen}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/auth/me", nil)
+			if tt.header != "" {
+				req.Header.Set(authhandler.HeaderCacheBypass, tt.header)
+			}
+			claims := &domain.TokenClaims{IDCitizen: 12345, Role: domain.RoleUser}
+			req = req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, claims))
+			w := httptest.NewRecorder()
+
+			authhandler.GetMe(shared.NewAuthHandler(mockAuthService, nil, nil, false, nil, zap.NewNop()))(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status code = %v, want %v", w.Code, http.StatusOK)
+			}
+			if bypassed != tt.wantBypass {
+				t.Errorf("cache bypassed = %v, want %v", bypassed, tt.wantBypass)
+			}
+		})
+	}
+}
+
+func TestGetMeHandler_AvatarURL(t *testing.T) {
+	tests := []struct {
+		name          string
+		avatarService *MockAvatarService
+		wantURL       string
+	}{
+		{name: "avatars disabled"},
+		{
+			name: "signed avatar url",
+			avatarService: &MockAvatarService{AvatarURLFunc: func(ctx context.Context, userID string) (string, error) {
+				return "https://storage.example.com/avatars/" + userID + ".png?signature=abc", nil
+			}},
+			wantURL: "https://storage.example.com/avatars/user-123.png?signature=abc",
+		},
+		{
+			name: "user served when the avatar fails",
+			avatarService: &MockAvatarService{AvatarURLFunc: func(ctx context.Context, userID string) (string, error) {
+				return "", domainerrors.ErrInternal
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAuthService := &MockAuthService{
+				GetUserByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.UserPublic, error) {
+					return &domain.UserPublic{ID: "user-123", IDCitizen: idCitizen}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/auth/me", nil)
+			req = req.WithContext(withUserClaims(req.Context()))
+			w := httptest.NewRecorder()
+
+			h := shared.NewAuthHandler(mockAuthService, nil, nil, false, nil, zap.NewNop())
+			if tt.avatarService != nil {
+				h.AvatarService = tt.avatarService
+			}
+			authhandler.GetMe(h)(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status code = %v, want %v", w.Code, http.StatusOK)
+			}
+			var resp response.UserResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.AvatarURL != tt.wantURL {
+				t.Errorf("AvatarURL = %v, want %v", resp.AvatarURL, tt.wantURL)
+			}
+		})
+	}
+}
+
+func TestGetMeHandler_Activity(t *testing.T) {
+	lastLogin := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		query        string
+		activityErr  error
+		wantStatus   int
+		wantActivity bool
+	}{
+		{name: "not requested", wantStatus: http.StatusOK},
+		{name: "requested", query: "?include_activity=true", wantStatus: http.StatusOK, wantActivity: true},
+		{name: "explicitly not requested", query: "?include_activity=false", wantStatus: http.StatusOK},
+		{name: "invalid flag", query: "?include_activity=maybe", wantStatus: http.StatusBadRequest},
+		{name: "activity unavailable", query: "?include_activity=1", activityErr: domainerrors.ErrInternal, wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			mockAuthService := &MockAuthService{
+				GetUserByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.UserPublic, error) {
+					return &domain.UserPublic{ID: "user-123", IDCitizen: idCitizen}, nil
+				},
+				GetAccountActivityFunc: func(ctx context.Context, idCitizen int) (*domain.AccountActivity, error) {
+					called = true
+					if tt.activityErr != nil {
+						return nil, tt.activityErr
+					}
+					return &domain.AccountActivity{LastLoginAt: &lastLogin, ActiveSessionsCount: 2}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/auth/me"+tt.query, nil)
+			req = req.WithContext(withUserClaims(req.Context()))
+			w := httptest.NewRecorder()
+
+			authhandler.GetMe(shared.NewAuthHandler(mockAuthService, nil, nil, false, nil, zap.NewNop()))(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if called != (tt.wantActivity || tt.activityErr != nil) {
+				t.Errorf("activity looked up = %v, want %v", called, !called)
+			}
+			if w.Code != http.StatusOK {
+				return
+			}
+
+			var body map[string]json.RawMessage
+			if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			raw, ok := body["activity"]
+			if ok != tt.wantActivity {
+				t.Fatalf("activity present = %v, want %v", ok, tt.wantActivity)
+			}
+			if !ok {
+				return
+			}
+			want := `{"last_login_at":"2026-03-01T09:30:00Z","active_sessions_count":2,"mfa_enabled":false,"password_changed_at":null}`
+			if string(raw) != want {
+				t.Errorf("activity = %s, want %s", raw, want)
+			}
+		})
+	}
+}
@@ -217,7 +217,7 @@ func TestLoginHandler(t *testing.T) {
 			w := httptest.NewRecorder()
 
 			// Use real handler with mock service injected
-			h := shared.NewAuthHandler(mockAuthService, logger)
+			h := shared.NewAuthHandler(mockAuthService, nil, nil, false, nil, logger)
 			handler := authhandler.Login(h)
 			handler(w, req)
 
@@ -233,3 +233,87 @@ func TestLoginHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestLoginHandler_IncludeUser(t *testing.T) {
+	includeUser := true
+
+	tests := []struct {
+		name           string
+		query          string
+		includeUser    *bool
+		defaultInclude bool
+		wantStatusCode int
+		wantUser       bool
+	}{
+		{
+			name:           "user not included by default",
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "user included by the configured default",
+			defaultInclude: true,
+			wantStatusCode: http.StatusOK,
+			wantUser:       true,
+		},
+		{
+			name:           "user included by the body field",
+			includeUser:    &includeUser,
+			wantStatusCode: http.StatusOK,
+			wantUser:       true,
+		},
+		{
+			name:           "query parameter overrides the configured default",
+			query:          "?include_user=false",
+			defaultInclude: true,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "invalid query parameter",
+			query:          "?include_user=maybe",
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	tokenPair := &domain.TokenPair{AccessToken: "access_token_123", RefreshToken: "refresh_token_123", TokenType: "Bearer", ExpiresIn: 3600}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAuthService := &MockAuthService{
+				LoginFunc: func(ctx context.Context, email, password string) (*domain.TokenPair, error) {
+					return tokenPair, nil
+				},
+				LoginWithUserFunc: func(ctx context.Context, email, password string) (*domain.TokenPair, *domain.UserPublic, error) {
+					return tokenPair, &domain.UserPublic{ID: "user-123", IDCitizen: 12345, Email: email, Name: "Test User", Role: domain.RoleUser}, nil
+				},
+			}
+
+			body, _ := json.Marshal(request.LoginRequest{Email: "test@example.com", Password: "password123", IncludeUser: tt.includeUser})
+			req := httptest.NewRequest(http.MethodPost, "/auth/login"+tt.query, bytes.NewBuffer(body))
+			w := httptest.NewRecorder()
+
+			h := shared.NewAuthHandler(mockAuthService, nil, nil, tt.defaultInclude, nil, zap.NewNop())
+			authhandler.Login(h)(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+			if tt.wantStatusCode != http.StatusOK {
+				return
+			}
+
+			var resp response.LoginResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.AccessToken != "access_token_123" {
+				t.Errorf("AccessToken = %v, want access_token_123", resp.AccessToken)
+			}
+			if (resp.User != nil) != tt.wantUser {
+				t.Fatalf("User = %v, wantUser %v", resp.User, tt.wantUser)
+			}
+			if tt.wantUser && (resp.User.ID != "user-123" || resp.User.IDCitizen != 12345) {
+				t.Errorf("User = %+v, want user-123 with IDCitizen 12345", resp.User)
+			}
+		})
+	}
+}
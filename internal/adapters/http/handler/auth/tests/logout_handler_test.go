@@ -196,7 +196,7 @@ func TestLogoutHandler(t *testing.T) {
 			w := httptest.NewRecorder()
 
 			// Use real handler with mock service injected
-			h := shared.NewAuthHandler(mockAuthService, logger)
+			h := shared.NewAuthHandler(mockAuthService, nil, nil, false, nil, logger)
 			handler := authhandler.Logout(h)
 			handler(w, req)
 
@@ -18,10 +18,13 @@ type AuthServiceInterface interface {
 // MockAuthService is a mock implementation of AuthService
 type MockAuthService struct {
 	LoginFunc              func(ctx context.Context, email, password string) (*domain.TokenPair, error)
+	LoginWithUserFunc      func(ctx context.Context, email, password string) (*domain.TokenPair, *domain.UserPublic, error)
+	LoginForClientFunc     func(ctx context.Context, email, password string, profile domain.TokenProfile) (*domain.TokenPair, error)
 	RegisterFunc           func(ctx context.Context, email, password, name string, idCitizen int) (*domain.UserPublic, error)
 	RefreshTokenFunc       func(ctx context.Context, refreshToken string) (*domain.TokenPair, error)
 	LogoutFunc             func(ctx context.Context, accessToken, refreshToken string) error
 	GetUserByIDCitizenFunc func(ctx context.Context, idCitizen int) (*domain.UserPublic, error)
+	GetAccountActivityFunc func(ctx context.Context, idCitizen int) (*domain.AccountActivity, error)
 	// Additional mocked functions to satisfy services.AuthServiceInterface
 	ValidateAccessTokenFunc func(ctx context.Context, token string) (*domain.TokenClaims, error)
 	RevokeAllUserTokensFunc func(ctx context.Context, idCitizen int) error
@@ -34,6 +37,20 @@ func (m *MockAuthService) Login(ctx context.Context, email, password string) (*d
 	return nil, nil
 }
 
+func (m *MockAuthService) LoginWithUser(ctx context.Context, email, password string) (*domain.TokenPair, *domain.UserPublic, error) {
+	if m.LoginWithUserFunc != nil {
+		return m.LoginWithUserFunc(ctx, email, password)
+	}
+	return nil, nil, nil
+}
+
+func (m *MockAuthService) LoginForClient(ctx context.Context, email, password string, profile domain.TokenProfile) (*domain.TokenPair, error) {
+	if m.LoginForClientFunc != nil {
+		return m.LoginForClientFunc(ctx, email, password, profile)
+	}
+	return nil, nil
+}
+
 func (m *MockAuthService) Register(ctx context.Context, email, password, name string, idCitizen int) (*domain.UserPublic, error) {
 	if m.RegisterFunc != nil {
 		return m.RegisterFunc(ctx, email, password, name, idCitizen)
@@ -62,6 +79,13 @@ func (m *MockAuthService) GetUserByIDCitizen(ctx context.Context, idCitizen int)
 	return nil, nil
 }
 
+func (m *MockAuthService) GetAccountActivity(ctx context.Context, idCitizen int) (*domain.AccountActivity, error) {
+	if m.GetAccountActivityFunc != nil {
+		return m.GetAccountActivityFunc(ctx, idCitizen)
+	}
+	return &domain.AccountActivity{}, nil
+}
+
 func (m *MockAuthService) ValidateAccessToken(ctx context.Context, token string) (*domain.TokenClaims, error) {
 	if m.ValidateAccessTokenFunc != nil {
 		return m.ValidateAccessTokenFunc(ctx, token)
@@ -75,3 +99,334 @@ func (m *MockAuthService) RevokeAllUserTokens(ctx context.Context, idCitizen int
 	}
 	return nil
 }
+
+// MockNotificationService is a mock implementation of services.NotificationServiceInterface
+type MockNotificationService struct {
+	GetPreferencesFunc    func(ctx context.Context, idCitizen int) (*domain.NotificationPreferences, error)
+	UpdatePreferencesFunc func(ctx context.Context, idCitizen int, newDevice, passwordChange, loginAlert *bool) (*domain.NotificationPreferences, error)
+	NotifyFunc            func(ctx context.Context, user *domain.User, notificationType domain.NotificationType, details map[string]string) error
+	AllowsFunc            func(ctx context.Context, user *domain.User, notificationType domain.NotificationType) (bool, error)
+}
+
+func (m *MockNotificationService) GetPreferences(ctx context.Context, idCitizen int) (*domain.NotificationPreferences, error) {
+	if m.GetPreferencesFunc != nil {
+		return m.GetPreferencesFunc(ctx, idCitizen)
+	}
+	return nil, nil
+}
+
+func (m *MockNotificationService) UpdatePreferences(ctx context.Context, idCitizen int, newDevice, passwordChange, loginAlert *bool) (*domain.NotificationPreferences, error) {
+	if m.UpdatePreferencesFunc != nil {
+		return m.UpdatePreferencesFunc(ctx, idCitizen, newDevice, passwordChange, loginAlert)
+	}
+	return nil, nil
+}
+
+func (m *MockNotificationService) Notify(ctx context.Context, user *domain.User, notificationType domain.NotificationType, details map[string]string) error {
+	if m.NotifyFunc != nil {
+		return m.NotifyFunc(ctx, user, notificationType, details)
+	}
+	return nil
+}
+
+// Allows accepts every notification by default, like the default preferences
+func (m *MockNotificationService) Allows(ctx context.Context, user *domain.User, notificationType domain.NotificationType) (bool, error) {
+	if m.AllowsFunc != nil {
+		return m.AllowsFunc(ctx, user, notificationType)
+	}
+	return true, nil
+}
+
+// MockConsentService is a mock implementation of services.ConsentServiceInterface
+type MockConsentService struct {
+	ListConsentsFunc    func(ctx context.Context, idCitizen int) ([]*domain.Consent, error)
+	RevokeConsentFunc   func(ctx context.Context, idCitizen int, clientID string) error
+	RequiresConsentFunc func(ctx context.Context, idCitizen int, clientID string, scopes []string) (bool, error)
+	RecordConsentFunc   func(ctx context.Context, idCitizen int, clientID string, scopes []string) error
+}
+
+func (m *MockConsentService) ListConsents(ctx context.Context, idCitizen int) ([]*domain.Consent, error) {
+	if m.ListConsentsFunc != nil {
+		return m.ListConsentsFunc(ctx, idCitizen)
+	}
+	return nil, nil
+}
+
+func (m *MockConsentService) RevokeConsent(ctx context.Context, idCitizen int, clientID string) error {
+	if m.RevokeConsentFunc != nil {
+		return m.RevokeConsentFunc(ctx, idCitizen, clientID)
+	}
+	return nil
+}
+
+func (m *MockConsentService) RequiresConsent(ctx context.Context, idCitizen int, clientID string, scopes []string) (bool, error) {
+	if m.RequiresConsentFunc != nil {
+		return m.RequiresConsentFunc(ctx, idCitizen, clientID, scopes)
+	}
+	return true, nil
+}
+
+func (m *MockConsentService) RecordConsent(ctx context.Context, idCitizen int, clientID string, scopes []string) error {
+	if m.RecordConsentFunc != nil {
+		return m.RecordConsentFunc(ctx, idCitizen, clientID, scopes)
+	}
+	return nil
+}
+
+// MockPhoneService is a mock implementation of services.PhoneServiceInterface
+type MockPhoneService struct {
+	GetPhoneNumberFunc    func(ctx context.Context, idCitizen int) (*domain.PhoneNumber, error)
+	StartEnrollmentFunc   func(ctx context.Context, idCitizen int, phoneNumber string) (*domain.PhoneVerification, error)
+	VerifyEnrollmentFunc  func(ctx context.Context, idCitizen int, code string) (*domain.PhoneNumber, error)
+	RemovePhoneNumberFunc func(ctx context.Context, idCitizen int) error
+}
+
+func (m *MockPhoneService) GetPhoneNumber(ctx context.Context, idCitizen int) (*domain.PhoneNumber, error) {
+	if m.GetPhoneNumberFunc != nil {
+		return m.GetPhoneNumberFunc(ctx, idCitizen)
+	}
+	return nil, nil
+}
+
+func (m *MockPhoneService) StartEnrollment(ctx context.Context, idCitizen int, phoneNumber string) (*domain.PhoneVerification, error) {
+	if m.StartEnrollmentFunc != nil {
+		return m.StartEnrollmentFunc(ctx, idCitizen, phoneNumber)
+	}
+	return nil, nil
+}
+
+func (m *MockPhoneService) VerifyEnrollment(ctx context.Context, idCitizen int, code string) (*domain.PhoneNumber, error) {
+	if m.VerifyEnrollmentFunc != nil {
+		return m.VerifyEnrollmentFunc(ctx, idCitizen, code)
+	}
+	return nil, nil
+}
+
+func (m *MockPhoneService) RemovePhoneNumber(ctx context.Context, idCitizen int) error {
+	if m.RemovePhoneNumberFunc != nil {
+		return m.RemovePhoneNumberFunc(ctx, idCitizen)
+	}
+	return nil
+}
+
+// MockUserEmailService is a mock implementation of services.UserEmailServiceInterface
+type MockUserEmailService struct {
+	ListEmailsFunc     func(ctx context.Context, idCitizen int) ([]*domain.UserEmail, error)
+	AddEmailFunc       func(ctx context.Context, idCitizen int, email string) (*domain.UserEmail, error)
+	VerifyEmailFunc    func(ctx context.Context, idCitizen int, id, code string) (*domain.UserEmail, error)
+	RemoveEmailFunc    func(ctx context.Context, idCitizen int, id string) error
+	ListUserEmailsFunc func(ctx context.Context, userID string) ([]*domain.UserEmail, error)
+}
+
+func (m *MockUserEmailService) ListEmails(ctx context.Context, idCitizen int) ([]*domain.UserEmail, error) {
+	if m.ListEmailsFunc != nil {
+		return m.ListEmailsFunc(ctx, idCitizen)
+	}
+	return nil, nil
+}
+
+func (m *MockUserEmailService) AddEmail(ctx context.Context, idCitizen int, email string) (*domain.UserEmail, error) {
+	if m.AddEmailFunc != nil {
+		return m.AddEmailFunc(ctx, idCitizen, email)
+	}
+	return nil, nil
+}
+
+func (m *MockUserEmailService) VerifyEmail(ctx context.Context, idCitizen int, id, code string) (*domain.UserEmail, error) {
+	if m.VerifyEmailFunc != nil {
+		return m.VerifyEmailFunc(ctx, idCitizen, id, code)
+	}
+	return nil, nil
+}
+
+func (m *MockUserEmailService) RemoveEmail(ctx context.Context, idCitizen int, id string) error {
+	if m.RemoveEmailFunc != nil {
+		return m.RemoveEmailFunc(ctx, idCitizen, id)
+	}
+	return nil
+}
+
+func (m *MockUserEmailService) ListUserEmails(ctx context.Context, userID string) ([]*domain.UserEmail, error) {
+	if m.ListUserEmailsFunc != nil {
+		return m.ListUserEmailsFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+// MockPasswordResetService is a mock implementation of services.PasswordResetServiceInterface
+type MockPasswordResetService struct {
+	RequestResetFunc  func(ctx context.Context, email string) error
+	ResetPasswordFunc func(ctx context.Context, token, newPassword string) error
+}
+
+func (m *MockPasswordResetService) RequestReset(ctx context.Context, email string) error {
+	if m.RequestResetFunc != nil {
+		return m.RequestResetFunc(ctx, email)
+	}
+	return nil
+}
+
+func (m *MockPasswordResetService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	if m.ResetPasswordFunc != nil {
+		return m.ResetPasswordFunc(ctx, token, newPassword)
+	}
+	return nil
+}
+
+// MockUserProvisioningService is a mock implementation of services.UserProvisioningServiceInterface
+type MockUserProvisioningService struct {
+	CreateUserFunc              func(ctx context.Context, email, name string, idCitizen int, actor string) (*domain.User, string, error)
+	ChangeTemporaryPasswordFunc func(ctx context.Context, email, temporaryPassword, newPassword string) error
+}
+
+func (m *MockUserProvisioningService) CreateUser(ctx context.Context, email, name string, idCitizen int, actor string) (*domain.User, string, error) {
+	if m.CreateUserFunc != nil {
+		return m.CreateUserFunc(ctx, email, name, idCitizen, actor)
+	}
+	return nil, "", nil
+}
+
+func (m *MockUserProvisioningService) ChangeTemporaryPassword(ctx context.Context, email, temporaryPassword, newPassword string) error {
+	if m.ChangeTemporaryPasswordFunc != nil {
+		return m.ChangeTemporaryPasswordFunc(ctx, email, temporaryPassword, newPassword)
+	}
+	return nil
+}
+
+// MockEmailChangeService is a mock implementation of services.EmailChangeServiceInterface
+type MockEmailChangeService struct {
+	RequestEmailChangeFunc func(ctx context.Context, idCitizen int, email string) (*domain.User, error)
+	ConfirmEmailChangeFunc func(ctx context.Context, token string) error
+}
+
+func (m *MockEmailChangeService) RequestEmailChange(ctx context.Context, idCitizen int, email string) (*domain.User, error) {
+	if m.RequestEmailChangeFunc != nil {
+		return m.RequestEmailChangeFunc(ctx, idCitizen, email)
+	}
+	return nil, nil
+}
+
+func (m *MockEmailChangeService) ConfirmEmailChange(ctx context.Context, token string) error {
+	if m.ConfirmEmailChangeFunc != nil {
+		return m.ConfirmEmailChangeFunc(ctx, token)
+	}
+	return nil
+}
+
+// MockPhoneLoginService is a mock implementation of services.PhoneLoginServiceInterface
+type MockPhoneLoginService struct {
+	Disabled              bool
+	EnrollPhoneFunc       func(ctx context.Context, idCitizen int, phoneNumber string) (*domain.PhoneVerification, error)
+	RequestLoginCodeFunc  func(ctx context.Context, phoneNumber string) error
+	LoginWithPasswordFunc func(ctx context.Context, phoneNumber, password string) (*domain.TokenPair, *domain.UserPublic, error)
+	LoginWithCodeFunc     func(ctx context.Context, phoneNumber, code string) (*domain.TokenPair, *domain.UserPublic, error)
+}
+
+func (m *MockPhoneLoginService) Enabled() bool {
+	return !m.Disabled
+}
+
+func (m *MockPhoneLoginService) EnrollPhone(ctx context.Context, idCitizen int, phoneNumber string) (*domain.PhoneVerification, error) {
+	if m.EnrollPhoneFunc != nil {
+		return m.EnrollPhoneFunc(ctx, idCitizen, phoneNumber)
+	}
+	return nil, nil
+}
+
+func (m *MockPhoneLoginService) RequestLoginCode(ctx context.Context, phoneNumber string) error {
+	if m.RequestLoginCodeFunc != nil {
+		return m.RequestLoginCodeFunc(ctx, phoneNumber)
+	}
+	return nil
+}
+
+func (m *MockPhoneLoginService) LoginWithPassword(ctx context.Context, phoneNumber, password string) (*domain.TokenPair, *domain.UserPublic, error) {
+	if m.LoginWithPasswordFunc != nil {
+		return m.LoginWithPasswordFunc(ctx, phoneNumber, password)
+	}
+	return nil, nil, nil
+}
+
+func (m *MockPhoneLoginService) LoginWithCode(ctx context.Context, phoneNumber, code string) (*domain.TokenPair, *domain.UserPublic, error) {
+	if m.LoginWithCodeFunc != nil {
+		return m.LoginWithCodeFunc(ctx, phoneNumber, code)
+	}
+	return nil, nil, nil
+}
+
+// MockAvatarService is a mock implementation of services.AvatarServiceInterface
+type MockAvatarService struct {
+	MaxSizeValue     int64
+	UploadAvatarFunc func(ctx context.Context, idCitizen int, data []byte) (*domain.Avatar, string, error)
+	AvatarURLFunc    func(ctx context.Context, userID string) (string, error)
+}
+
+func (m *MockAvatarService) MaxSize() int64 {
+	return m.MaxSizeValue
+}
+
+func (m *MockAvatarService) UploadAvatar(ctx context.Context, idCitizen int, data []byte) (*domain.Avatar, string, error) {
+	if m.UploadAvatarFunc != nil {
+		return m.UploadAvatarFunc(ctx, idCitizen, data)
+	}
+	return nil, "", nil
+}
+
+func (m *MockAvatarService) AvatarURL(ctx context.Context, userID string) (string, error) {
+	if m.AvatarURLFunc != nil {
+		return m.AvatarURLFunc(ctx, userID)
+	}
+	return "", nil
+}
+
+// MockUserMetadataService is a mock implementation of services.UserMetadataServiceInterface
+type MockUserMetadataService struct {
+	UpdateUserMetadataFunc func(ctx context.Context, userID string, expectedVersion int, changes domain.UserMetadata, actor string) (*domain.UserPublic, error)
+	UpdateOwnMetadataFunc  func(ctx context.Context, idCitizen int, changes domain.UserMetadata) (*domain.UserPublic, error)
+}
+
+func (m *MockUserMetadataService) UpdateUserMetadata(ctx context.Context, userID string, expectedVersion int, changes domain.UserMetadata, actor string) (*domain.UserPublic, error) {
+	if m.UpdateUserMetadataFunc != nil {
+		return m.UpdateUserMetadataFunc(ctx, userID, expectedVersion, changes, actor)
+	}
+	return nil, nil
+}
+
+func (m *MockUserMetadataService) UpdateOwnMetadata(ctx context.Context, idCitizen int, changes domain.UserMetadata) (*domain.UserPublic, error) {
+	if m.UpdateOwnMetadataFunc != nil {
+		return m.UpdateOwnMetadataFunc(ctx, idCitizen, changes)
+	}
+	return nil, nil
+}
+
+// MockSudoService is a mock implementation of services.SudoServiceInterface
+type MockSudoService struct {
+	ElevateFunc func(ctx context.Context, idCitizen int, password string) (*domain.SudoGrant, error)
+}
+
+func (m *MockSudoService) Elevate(ctx context.Context, idCitizen int, password string) (*domain.SudoGrant, error) {
+	if m.ElevateFunc != nil {
+		return m.ElevateFunc(ctx, idCitizen, password)
+	}
+	return nil, nil
+}
+
+// MockUserExportService is a mock implementation of services.UserExportJobServiceInterface
+type MockUserExportService struct {
+	RequestExportFunc func(ctx context.Context, idCitizen int) (*domain.UserExportJob, error)
+	GetExportFunc     func(ctx context.Context, idCitizen int, jobID string) (*domain.UserExportJob, *domain.UserExportDownload, error)
+}
+
+func (m *MockUserExportService) RequestExport(ctx context.Context, idCitizen int) (*domain.UserExportJob, error) {
+	if m.RequestExportFunc != nil {
+		return m.RequestExportFunc(ctx, idCitizen)
+	}
+	return nil, nil
+}
+
+func (m *MockUserExportService) GetExport(ctx context.Context, idCitizen int, jobID string) (*domain.UserExportJob, *domain.UserExportDownload, error) {
+	if m.GetExportFunc != nil {
+		return m.GetExportFunc(ctx, idCitizen, jobID)
+	}
+	return nil, nil, nil
+}
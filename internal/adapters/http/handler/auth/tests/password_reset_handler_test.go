@@ -0,0 +1,82 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+
+	authhandler "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/auth"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+)
+
+func TestRequestPasswordResetHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		requestErr     error
+		wantStatusCode int
+	}{
+		{name: "reset requested", body: `{"email":"test@example.com"}`, wantStatusCode: http.StatusAccepted},
+		{name: "invalid JSON", body: `{invalid`, wantStatusCode: http.StatusBadRequest},
+		{name: "missing email", body: `{}`, wantStatusCode: http.StatusBadRequest},
+		{name: "invalid email", body: `{"email":"nope"}`, requestErr: domainerrors.ErrInvalidEmail, wantStatusCode: http.StatusBadRequest},
+		{name: "rate limited", body: `{"email":"test@example.com"}`, requestErr: domainerrors.ErrEmailRateLimited, wantStatusCode: http.StatusTooManyRequests},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockPasswordResetService{
+				RequestResetFunc: func(ctx context.Context, email string) error {
+					return tt.requestErr
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/password-reset", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			authhandler.RequestPasswordReset(shared.NewPasswordResetHandler(mockService, zap.NewNop()))(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+		})
+	}
+}
+
+func TestConfirmPasswordResetHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		resetErr       error
+		wantStatusCode int
+	}{
+		{name: "password reset", body: `{"token":"abc","new_password":"new-password"}`, wantStatusCode: http.StatusNoContent},
+		{name: "missing token", body: `{"new_password":"new-password"}`, wantStatusCode: http.StatusBadRequest},
+		{name: "invalid token", body: `{"token":"abc","new_password":"new-password"}`, resetErr: domainerrors.ErrInvalidResetToken, wantStatusCode: http.StatusBadRequest},
+		{name: "weak password", body: `{"token":"abc","new_password":"short"}`, resetErr: domainerrors.ErrWeakPassword, wantStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockPasswordResetService{
+				ResetPasswordFunc: func(ctx context.Context, token, newPassword string) error {
+					return tt.resetErr
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/password-reset/confirm", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			authhandler.ConfirmPasswordReset(shared.NewPasswordResetHandler(mockService, zap.NewNop()))(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+		})
+	}
+}
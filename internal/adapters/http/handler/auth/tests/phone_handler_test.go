@@ -0,0 +1,171 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	authhandler "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/auth"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestEnrollPhoneHandler(t *testing.T) {
+	logger := zap.NewNop()
+
+	tests := []struct {
+		name           string
+		body           string
+		withClaims     bool
+		enrollErr      error
+		wantStatusCode int
+	}{
+		{name: "code sent", body: `{"phone_number":"+573001234567"}`, withClaims: true, wantStatusCode: http.StatusAccepted},
+		{name: "missing user context", body: `{"phone_number":"+573001234567"}`, wantStatusCode: http.StatusUnauthorized},
+		{name: "invalid JSON", body: `{invalid`, withClaims: true, wantStatusCode: http.StatusBadRequest},
+		{name: "missing phone number", body: `{}`, withClaims: true, wantStatusCode: http.StatusBadRequest},
+		{name: "invalid phone number", body: `{"phone_number":"123"}`, withClaims: true, enrollErr: domainerrors.ErrInvalidPhoneNumber, wantStatusCode: http.StatusBadRequest},
+		{name: "rate limited", body: `{"phone_number":"+573001234567"}`, withClaims: true, enrollErr: domainerrors.ErrSMSRateLimited, wantStatusCode: http.StatusTooManyRequests},
+		{name: "quota exceeded", body: `{"phone_number":"+573001234567"}`, withClaims: true, enrollErr: domainerrors.ErrSMSQuotaExceeded, wantStatusCode: http.StatusServiceUnavailable},
+		{name: "provider failure", body: `{"phone_number":"+573001234567"}`, withClaims: true, enrollErr: domainerrors.ErrSMSDeliveryFailed, wantStatusCode: http.StatusBadGateway},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockPhoneService{
+				StartEnrollmentFunc: func(ctx context.Context, idCitizen int, phoneNumber string) (*domain.PhoneVerification, error) {
+					if tt.enrollErr != nil {
+						return nil, tt.enrollErr
+					}
+					return &domain.PhoneVerification{Number: phoneNumber, ExpiresAt: time.Now().Add(10 * time.Minute)}, nil
+				},
+			}
+			handler := shared.NewPhoneHandler(mockService, logger)
+
+			req := httptest.NewRequest(http.MethodPost, "/me/phone", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.withClaims {
+				req = req.WithContext(withUserClaims(req.Context()))
+			}
+			w := httptest.NewRecorder()
+
+			authhandler.EnrollPhone(handler)(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+
+			if tt.wantStatusCode == http.StatusAccepted {
+				var resp response.PhoneVerificationResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.PhoneNumber != "*********4567" {
+					t.Errorf("PhoneNumber = %v, want masked number", resp.PhoneNumber)
+				}
+				if resp.ExpiresIn <= 0 {
+					t.Errorf("ExpiresIn = %v, want positive", resp.ExpiresIn)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyPhoneHandler(t *testing.T) {
+	logger := zap.NewNop()
+
+	tests := []struct {
+		name           string
+		body           string
+		withClaims     bool
+		verifyErr      error
+		wantStatusCode int
+	}{
+		{name: "verified", body: `{"code":"123456"}`, withClaims: true, wantStatusCode: http.StatusOK},
+		{name: "missing user context", body: `{"code":"123456"}`, wantStatusCode: http.StatusUnauthorized},
+		{name: "missing code", body: `{}`, withClaims: true, wantStatusCode: http.StatusBadRequest},
+		{name: "wrong code", body: `{"code":"000000"}`, withClaims: true, verifyErr: domainerrors.ErrInvalidVerificationCode, wantStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockPhoneService{
+				VerifyEnrollmentFunc: func(ctx context.Context, idCitizen int, code string) (*domain.PhoneNumber, error) {
+					if tt.verifyErr != nil {
+						return nil, tt.verifyErr
+					}
+					return &domain.PhoneNumber{UserID: "user-123", Number: "+573001234567", VerifiedAt: time.Now()}, nil
+				},
+			}
+			handler := shared.NewPhoneHandler(mockService, logger)
+
+			req := httptest.NewRequest(http.MethodPost, "/me/phone/verify", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.withClaims {
+				req = req.WithContext(withUserClaims(req.Context()))
+			}
+			w := httptest.NewRecorder()
+
+			authhandler.VerifyPhone(handler)(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Errorf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+		})
+	}
+}
+
+func TestGetAndDeletePhoneHandler(t *testing.T) {
+	logger := zap.NewNop()
+
+	tests := []struct {
+		name           string
+		method         string
+		serviceErr     error
+		wantStatusCode int
+	}{
+		{name: "get phone number", method: http.MethodGet, wantStatusCode: http.StatusOK},
+		{name: "get without phone number", method: http.MethodGet, serviceErr: domainerrors.ErrPhoneNotFound, wantStatusCode: http.StatusNotFound},
+		{name: "delete phone number", method: http.MethodDelete, wantStatusCode: http.StatusNoContent},
+		{name: "delete without phone number", method: http.MethodDelete, serviceErr: domainerrors.ErrPhoneNotFound, wantStatusCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockPhoneService{
+				GetPhoneNumberFunc: func(ctx context.Context, idCitizen int) (*domain.PhoneNumber, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &domain.PhoneNumber{UserID: "user-123", Number: "+573001234567", VerifiedAt: time.Now()}, nil
+				},
+				RemovePhoneNumberFunc: func(ctx context.Context, idCitizen int) error {
+					return tt.serviceErr
+				},
+			}
+			handler := shared.NewPhoneHandler(mockService, logger)
+
+			req := httptest.NewRequest(tt.method, "/me/phone", nil)
+			req = req.WithContext(withUserClaims(req.Context()))
+			w := httptest.NewRecorder()
+
+			if tt.method == http.MethodGet {
+				authhandler.GetPhone(handler)(w, req)
+			} else {
+				authhandler.DeletePhone(handler)(w, req)
+			}
+
+			if w.Code != tt.wantStatusCode {
+				t.Errorf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,301 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/request"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	authhandler "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/auth"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestLoginHandler_Phone(t *testing.T) {
+	tokenPair := &domain.TokenPair{AccessToken: "access_token_123", RefreshToken: "refresh_token_123", TokenType: "Bearer", ExpiresIn: 3600}
+	user := &domain.UserPublic{ID: "user-123", IDCitizen: 12345, Email: "test@example.com", Name: "Test User", Role: domain.RoleUser}
+
+	tests := []struct {
+		name           string
+		requestBody    request.LoginRequest
+		noService      bool
+		loginErr       error
+		wantStatusCode int
+		wantCode       string
+		wantMethod     string
+	}{
+		{
+			name:           "login with phone and password",
+			requestBody:    request.LoginRequest{PhoneNumber: "+573001234567", Password: "password123"},
+			wantStatusCode: http.StatusOK,
+			wantMethod:     "password",
+		},
+		{
+			name:           "login with phone and code",
+			requestBody:    request.LoginRequest{PhoneNumber: "+573001234567", Code: "123456"},
+			wantStatusCode: http.StatusOK,
+			wantMethod:     "code",
+		},
+		{
+			name:           "missing password and code",
+			requestBody:    request.LoginRequest{PhoneNumber: "+573001234567"},
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "REQUIRED_FIELD",
+		},
+		{
+			name:           "wrong code",
+			requestBody:    request.LoginRequest{PhoneNumber: "+573001234567", Code: "000000"},
+			loginErr:       domainerrors.ErrInvalidCredentials,
+			wantStatusCode: http.StatusUnauthorized,
+			wantCode:       "INVALID_CREDENTIALS",
+			wantMethod:     "code",
+		},
+		{
+			name:           "invalid phone number",
+			requestBody:    request.LoginRequest{PhoneNumber: "3001234567", Password: "password123"},
+			loginErr:       domainerrors.ErrInvalidPhoneNumber,
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "INVALID_PHONE_NUMBER",
+			wantMethod:     "password",
+		},
+		{
+			name:           "phone login not configured",
+			requestBody:    request.LoginRequest{PhoneNumber: "+573001234567", Password: "password123"},
+			noService:      true,
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "PHONE_LOGIN_DISABLED",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := ""
+			phoneLoginService := &MockPhoneLoginService{
+				LoginWithPasswordFunc: func(ctx context.Context, phoneNumber, password string) (*domain.TokenPair, *domain.UserPublic, error) {
+					method = "password"
+					if tt.loginErr != nil {
+						return nil, nil, tt.loginErr
+					}
+					return tokenPair, user, nil
+				},
+				LoginWithCodeFunc: func(ctx context.Context, phoneNumber, code string) (*domain.TokenPair, *domain.UserPublic, error) {
+					method = "code"
+					if tt.loginErr != nil {
+						return nil, nil, tt.loginErr
+					}
+					return tokenPair, user, nil
+				},
+			}
+
+			h := shared.NewAuthHandler(&MockAuthService{}, phoneLoginService, nil, false, nil, zap.NewNop())
+			if tt.noService {
+				h.PhoneLoginService = nil
+			}
+
+			body, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest(http.MethodPost, "/auth/login", bytes.NewBuffer(body))
+			w := httptest.NewRecorder()
+			authhandler.Login(h)(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+			if method != tt.wantMethod {
+				t.Errorf("login method = %q, want %q", method, tt.wantMethod)
+			}
+
+			if tt.wantCode != "" {
+				var resp response.ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("Error code = %v, want %v", resp.Code, tt.wantCode)
+				}
+				return
+			}
+
+			var resp response.LoginResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.AccessToken != "access_token_123" {
+				t.Errorf("AccessToken = %v, want access_token_123", resp.AccessToken)
+			}
+			if resp.User != nil {
+				t.Errorf("User = %+v, want none unless include_user is set", resp.User)
+			}
+		})
+	}
+}
+
+func TestRequestPhoneLoginCodeHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		requestErr     error
+		wantStatusCode int
+		wantCode       string
+	}{
+		{
+			name:           "code requested",
+			requestBody:    request.PhoneLoginCodeRequest{PhoneNumber: "+573001234567"},
+			wantStatusCode: http.StatusAccepted,
+		},
+		{
+			name:           "missing phone number",
+			requestBody:    request.PhoneLoginCodeRequest{},
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "REQUIRED_FIELD",
+		},
+		{
+			name:           "invalid json body",
+			requestBody:    "invalid json",
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "INVALID_REQUEST_BODY",
+		},
+		{
+			name:           "phone login disabled",
+			requestBody:    request.PhoneLoginCodeRequest{PhoneNumber: "+573001234567"},
+			requestErr:     domainerrors.ErrPhoneLoginDisabled,
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "PHONE_LOGIN_DISABLED",
+		},
+		{
+			name:           "rate limited",
+			requestBody:    request.PhoneLoginCodeRequest{PhoneNumber: "+573001234567"},
+			requestErr:     domainerrors.ErrSMSRateLimited,
+			wantStatusCode: http.StatusTooManyRequests,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			phoneLoginService := &MockPhoneLoginService{
+				RequestLoginCodeFunc: func(ctx context.Context, phoneNumber string) error {
+					return tt.requestErr
+				},
+			}
+
+			var body []byte
+			if str, ok := tt.requestBody.(string); ok {
+				body = []byte(str)
+			} else {
+				body, _ = json.Marshal(tt.requestBody)
+			}
+			req := httptest.NewRequest(http.MethodPost, "/auth/login/phone/code", bytes.NewBuffer(body))
+			w := httptest.NewRecorder()
+
+			h := shared.NewAuthHandler(&MockAuthService{}, phoneLoginService, nil, false, nil, zap.NewNop())
+			authhandler.RequestPhoneLoginCode(h)(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+			if tt.wantCode != "" {
+				var resp response.ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("Error code = %v, want %v", resp.Code, tt.wantCode)
+				}
+			}
+		})
+	}
+}
+
+func TestRegisterHandler_Phone(t *testing.T) {
+	tests := []struct {
+		name             string
+		phoneNumber      string
+		disabled         bool
+		enrollErr        error
+		wantStatusCode   int
+		wantCode         string
+		wantRegistered   bool
+		wantVerification bool
+	}{
+		{name: "enrolls the phone number", phoneNumber: "+573001234567", wantStatusCode: http.StatusCreated, wantRegistered: true, wantVerification: true},
+		{name: "registered when the enrollment fails", phoneNumber: "+573001234567", enrollErr: domainerrors.ErrSMSDeliveryFailed, wantStatusCode: http.StatusCreated, wantRegistered: true},
+		{name: "invalid phone number", phoneNumber: "3001234567", wantStatusCode: http.StatusBadRequest, wantCode: "INVALID_PHONE_NUMBER"},
+		{name: "phone login disabled", phoneNumber: "+573001234567", disabled: true, wantStatusCode: http.StatusBadRequest, wantCode: "PHONE_LOGIN_DISABLED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registered := false
+			authService := &MockAuthService{
+				RegisterFunc: func(ctx context.Context, email, password, name string, idCitizen int) (*domain.UserPublic, error) {
+					registered = true
+					return &domain.UserPublic{ID: "user-123", IDCitizen: idCitizen, Email: email, Name: name, Role: domain.RoleUser}, nil
+				},
+			}
+			phoneLoginService := &MockPhoneLoginService{
+				Disabled: tt.disabled,
+				EnrollPhoneFunc: func(ctx context.Context, idCitizen int, phoneNumber string) (*domain.PhoneVerification, error) {
+					if idCitizen != 12345 {
+						t.Errorf("EnrollPhone() idCitizen = %v, want 12345", idCitizen)
+					}
+					if tt.enrollErr != nil {
+						return nil, tt.enrollErr
+					}
+					return &domain.PhoneVerification{Number: phoneNumber, ExpiresAt: time.Now().Add(10 * time.Minute)}, nil
+				},
+			}
+
+			body, _ := json.Marshal(request.RegisterRequest{
+				IDCitizen:   12345,
+				Email:       "newuser@example.com",
+				Password:    "password123",
+				Name:        "New User",
+				PhoneNumber: tt.phoneNumber,
+			})
+			req := httptest.NewRequest(http.MethodPost, "/auth/register", bytes.NewBuffer(body))
+			w := httptest.NewRecorder()
+
+			h := shared.NewAuthHandler(authService, phoneLoginService, nil, false, nil, zap.NewNop())
+			authhandler.Register(h)(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+			if registered != tt.wantRegistered {
+				t.Errorf("registered = %v, want %v", registered, tt.wantRegistered)
+			}
+
+			if tt.wantCode != "" {
+				var resp response.ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("Error code = %v, want %v", resp.Code, tt.wantCode)
+				}
+				return
+			}
+
+			var resp response.RegisterResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.ID != "user-123" {
+				t.Errorf("ID = %v, want user-123", resp.ID)
+			}
+			if (resp.PhoneVerification != nil) != tt.wantVerification {
+				t.Fatalf("PhoneVerification = %+v, want %v", resp.PhoneVerification, tt.wantVerification)
+			}
+			if tt.wantVerification && (resp.PhoneVerification.PhoneNumber == "+573001234567" || resp.PhoneVerification.ExpiresIn <= 0) {
+				t.Errorf("PhoneVerification = %+v, want masked number and remaining time", resp.PhoneVerification)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	authhandler "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/auth"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func withUserClaims(ctx context.Context) context.Context {
+	claims := &domain.TokenClaims{IDCitizen: 12345, Email: "test@example.com", Role: domain.RoleUser}
+	return context.WithValue(ctx, middleware.UserContextKey, claims)
+}
+
+func TestGetPreferencesHandler(t *testing.T) {
+	logger := zap.NewNop()
+
+	tests := []struct {
+		name           string
+		withClaims     bool
+		mockSetup      func(*MockNotificationService)
+		wantStatusCode int
+	}{
+		{
+			name:       "successful get preferences",
+			withClaims: true,
+			mockSetup: func(m *MockNotificationService) {
+				m.GetPreferencesFunc = func(ctx context.Context, idCitizen int) (*domain.NotificationPreferences, error) {
+					return domain.DefaultNotificationPreferences("user-123"), nil
+				}
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "missing user context",
+			withClaims:     false,
+			mockSetup:      func(m *MockNotificationService) {},
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:       "user not found",
+			withClaims: true,
+			mockSetup: func(m *MockNotificationService) {
+				m.GetPreferencesFunc = func(ctx context.Context, idCitizen int) (*domain.NotificationPreferences, error) {
+					return nil, domainerrors.ErrUserNotFound
+				}
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockNotificationService{}
+			tt.mockSetup(mockService)
+			handler := shared.NewNotificationPreferencesHandler(mockService, logger)
+
+			req := httptest.NewRequest(http.MethodGet, "/me/preferences", nil)
+			if tt.withClaims {
+				req = req.WithContext(withUserClaims(req.Context()))
+			}
+			w := httptest.NewRecorder()
+
+			authhandler.GetPreferences(handler)(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Errorf("status = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+		})
+	}
+}
+
+func TestUpdatePreferencesHandler(t *testing.T) {
+	logger := zap.NewNop()
+
+	tests := []struct {
+		name           string
+		body           string
+		mockSetup      func(*testing.T, *MockNotificationService)
+		wantStatusCode int
+		checkResponse  func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name: "successful partial update",
+			body: `{"login_alert": false}`,
+			mockSetup: func(t *testing.T, m *MockNotificationService) {
+				m.UpdatePreferencesFunc = func(ctx context.Context, idCitizen int, newDevice, passwordChange, loginAlert *bool) (*domain.NotificationPreferences, error) {
+					if newDevice != nil || passwordChange != nil {
+						t.Error("omitted fields should be passed as nil")
+					}
+					if loginAlert == nil || *loginAlert {
+						t.Error("login_alert should be false")
+					}
+					prefs := domain.DefaultNotificationPreferences("user-123")
+					prefs.LoginAlert = false
+					return prefs, nil
+				}
+			},
+			wantStatusCode: http.StatusOK,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				var resp response.NotificationPreferencesResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.LoginAlert || !resp.NewDevice {
+					t.Errorf("unexpected response %+v", resp)
+				}
+			},
+		},
+		{
+			name:           "invalid body",
+			body:           `{invalid`,
+			mockSetup:      func(t *testing.T, m *MockNotificationService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "service error",
+			body: `{"new_device": true}`,
+			mockSetup: func(t *testing.T, m *MockNotificationService) {
+				m.UpdatePreferencesFunc = func(ctx context.Context, idCitizen int, newDevice, passwordChange, loginAlert *bool) (*domain.NotificationPreferences, error) {
+					return nil, domainerrors.ErrInternal
+				}
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockNotificationService{}
+			tt.mockSetup(t, mockService)
+			handler := shared.NewNotificationPreferencesHandler(mockService, logger)
+
+			req := httptest.NewRequest(http.MethodPut, "/me/preferences", bytes.NewBufferString(tt.body))
+			req = req.WithContext(withUserClaims(req.Context()))
+			w := httptest.NewRecorder()
+
+			authhandler.UpdatePreferences(handler)(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Errorf("status = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+			if tt.checkResponse != nil {
+				tt.checkResponse(t, w)
+			}
+		})
+	}
+}
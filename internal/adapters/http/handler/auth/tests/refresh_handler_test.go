@@ -209,7 +209,7 @@ func TestRefreshHandler(t *testing.T) {
 			w := httptest.NewRecorder()
 
 			// Use real handler with mock service injected
-			h := shared.NewAuthHandler(mockAuthService, logger)
+			h := shared.NewAuthHandler(mockAuthService, nil, nil, false, nil, logger)
 			handler := authhandler.Refresh(h)
 			handler(w, req)
 
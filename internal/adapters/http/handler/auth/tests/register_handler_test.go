@@ -253,7 +253,7 @@ func TestRegisterHandler(t *testing.T) {
 			w := httptest.NewRecorder()
 
 			// Use real handler with mock service injected
-			h := shared.NewAuthHandler(mockAuthService, logger)
+			h := shared.NewAuthHandler(mockAuthService, nil, nil, false, nil, logger)
 			handler := authhandler.Register(h)
 			handler(w, req)
 
@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/schema"
+	authhandler "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/auth"
+)
+
+func TestListSchemasHandler(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/dev/api/auth/schemas", nil)
+	w := httptest.NewRecorder()
+
+	authhandler.ListSchemas()(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status code = %v, want %v", w.Code, http.StatusOK)
+	}
+
+	var resp response.SchemaCatalogResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Schemas) != len(schema.Documents()) {
+		t.Fatalf("len(Schemas) = %v, want %v", len(resp.Schemas), len(schema.Documents()))
+	}
+
+	var found bool
+	for _, entry := range resp.Schemas {
+		if entry.Name == "RegisterRequest" {
+			found = true
+			if entry.Direction != "request" || entry.URL != "/dev/api/auth/schemas/RegisterRequest" {
+				t.Errorf("RegisterRequest entry = %+v", entry)
+			}
+		}
+	}
+	if !found {
+		t.Error("catalog is missing RegisterRequest")
+	}
+}
+
+func TestGetSchemaHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		schemaName     string
+		expectedStatus int
+	}{
+		{name: "request schema", schemaName: "RegisterRequest", expectedStatus: http.StatusOK},
+		{name: "response schema with extension", schemaName: "TokenResponse.json", expectedStatus: http.StatusOK},
+		{name: "unknown schema", schemaName: "UnknownRequest", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/schemas/"+tt.schemaName, nil)
+			req = mux.SetURLVars(req, map[string]string{"name": tt.schemaName})
+			w := httptest.NewRecorder()
+
+			authhandler.GetSchema()(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.expectedStatus)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			if got := w.Header().Get("Content-Type"); got != "application/schema+json" {
+				t.Errorf("Content-Type = %q, want application/schema+json", got)
+			}
+			var document map[string]interface{}
+			if err := json.NewDecoder(w.Body).Decode(&document); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if document["$schema"] != schema.Dialect || document["type"] != "object" {
+				t.Errorf("document = %v, want a JSON Schema of an object", document)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/request"
+	authhandler "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/auth"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func newTestSessionCookie() *shared.SessionCookie {
+	return shared.NewSessionCookie("access_token", "example.com", "/api", "strict", true)
+}
+
+func TestLoginHandler_SessionCookie(t *testing.T) {
+	mockService := &MockAuthService{
+		LoginFunc: func(ctx context.Context, email, password string) (*domain.TokenPair, error) {
+			return &domain.TokenPair{AccessToken: "access_token_123", RefreshToken: "refresh_token_123", TokenType: "Bearer", ExpiresIn: 900}, nil
+		},
+	}
+	handler := shared.NewAuthHandler(mockService, nil, nil, false, newTestSessionCookie(), zap.NewNop())
+
+	body, _ := json.Marshal(request.LoginRequest{Email: "test@example.com", Password: "password123"})
+	req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+	authhandler.Login(handler)(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("cookies = %v, want the access token cookie", cookies)
+	}
+	cookie := cookies[0]
+	if cookie.Name != "access_token" || cookie.Value != "access_token_123" || cookie.MaxAge != 900 ||
+		cookie.Domain != "example.com" || cookie.Path != "/api" || cookie.SameSite != http.SameSiteStrictMode ||
+		!cookie.Secure || !cookie.HttpOnly {
+		t.Errorf("cookie = %+v, want the access token with the configured attributes", cookie)
+	}
+}
+
+func TestLoginHandler_NoSessionCookie(t *testing.T) {
+	mockService := &MockAuthService{
+		LoginFunc: func(ctx context.Context, email, password string) (*domain.TokenPair, error) {
+			return &domain.TokenPair{AccessToken: "access_token_123", RefreshToken: "refresh_token_123", TokenType: "Bearer", ExpiresIn: 900}, nil
+		},
+	}
+	handler := shared.NewAuthHandler(mockService, nil, nil, false, nil, zap.NewNop())
+
+	body, _ := json.Marshal(request.LoginRequest{Email: "test@example.com", Password: "password123"})
+	req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+	authhandler.Login(handler)(w, req)
+
+	if cookies := w.Result().Cookies(); len(cookies) != 0 {
+		t.Errorf("cookies = %v, want none outside cookie mode", cookies)
+	}
+}
+
+func TestLogoutHandler_SessionCookie(t *testing.T) {
+	var loggedOut string
+	mockService := &MockAuthService{
+		LogoutFunc: func(ctx context.Context, accessToken, refreshToken string) error {
+			loggedOut = accessToken
+			return nil
+		},
+	}
+	handler := shared.NewAuthHandler(mockService, nil, nil, false, newTestSessionCookie(), zap.NewNop())
+
+	req := httptest.NewRequest(http.MethodPost, "/logout", bytes.NewBufferString("{}"))
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: "access_token_123"})
+	w := httptest.NewRecorder()
+	authhandler.Logout(handler)(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if loggedOut != "access_token_123" {
+		t.Errorf("Logout() access token = %q, want the token of the cookie", loggedOut)
+	}
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != "access_token" || cookies[0].MaxAge >= 0 {
+		t.Errorf("cookies = %v, want the access token cookie cleared", cookies)
+	}
+}
@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	authhandler "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/auth"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestSudoHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		noClaims       bool
+		elevateErr     error
+		wantStatusCode int
+		wantCode       string
+	}{
+		{name: "successful elevation", body: `{"password":"password123"}`, wantStatusCode: http.StatusOK},
+		{name: "missing claims", body: `{"password":"password123"}`, noClaims: true, wantStatusCode: http.StatusUnauthorized, wantCode: "UNAUTHORIZED"},
+		{name: "invalid json body", body: `not json`, wantStatusCode: http.StatusBadRequest, wantCode: "INVALID_REQUEST_BODY"},
+		{name: "missing password", body: `{}`, wantStatusCode: http.StatusBadRequest, wantCode: "REQUIRED_FIELD"},
+		{name: "invalid password", body: `{"password":"wrong"}`, elevateErr: domainerrors.ErrInvalidCredentials, wantStatusCode: http.StatusUnauthorized, wantCode: "INVALID_CREDENTIALS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sudoUntil := time.Now().Add(5 * time.Minute).Truncate(time.Second)
+			mockService := &MockSudoService{
+				ElevateFunc: func(ctx context.Context, idCitizen int, password string) (*domain.SudoGrant, error) {
+					if idCitizen != 12345 {
+						t.Errorf("Elevate() idCitizen = %v, want 12345", idCitizen)
+					}
+					if tt.elevateErr != nil {
+						return nil, tt.elevateErr
+					}
+					return &domain.SudoGrant{AccessToken: "sudo-token", SudoUntil: sudoUntil}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/sudo", bytes.NewBufferString(tt.body))
+			if !tt.noClaims {
+				req = req.WithContext(withUserClaims(req.Context()))
+			}
+			w := httptest.NewRecorder()
+
+			authhandler.Sudo(shared.NewSudoHandler(mockService, zap.NewNop()))(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+
+			if tt.wantCode != "" {
+				var resp response.ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("error code = %v, want %v", resp.Code, tt.wantCode)
+				}
+				return
+			}
+
+			var resp response.SudoResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.AccessToken != "sudo-token" || resp.TokenType != domain.TokenTypeBearer || !resp.SudoUntil.Equal(sudoUntil) {
+				t.Errorf("response = %+v, want the sudo token until %v", resp, sudoUntil)
+			}
+			if resp.ExpiresIn <= 0 || resp.ExpiresIn > 300 {
+				t.Errorf("ExpiresIn = %d, want within the sudo duration", resp.ExpiresIn)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+
+	authhandler "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/auth"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+)
+
+func TestChangeTemporaryPasswordHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		changeErr      error
+		wantStatusCode int
+	}{
+		{name: "password changed", body: `{"email":"citizen@example.com","temporary_password":"Temp0rary-pw","new_password":"new-password"}`, wantStatusCode: http.StatusNoContent},
+		{name: "invalid body", body: `{`, wantStatusCode: http.StatusBadRequest},
+		{name: "missing temporary password", body: `{"email":"citizen@example.com","new_password":"new-password"}`, wantStatusCode: http.StatusBadRequest},
+		{name: "invalid credentials", body: `{"email":"citizen@example.com","temporary_password":"wrong","new_password":"new-password"}`, changeErr: domainerrors.ErrInvalidCredentials, wantStatusCode: http.StatusUnauthorized},
+		{name: "weak password", body: `{"email":"citizen@example.com","temporary_password":"Temp0rary-pw","new_password":"short"}`, changeErr: domainerrors.ErrWeakPassword, wantStatusCode: http.StatusBadRequest},
+		{name: "not temporary", body: `{"email":"citizen@example.com","temporary_password":"Temp0rary-pw","new_password":"new-password"}`, changeErr: domainerrors.ErrPasswordChangeNotRequired, wantStatusCode: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockUserProvisioningService{
+				ChangeTemporaryPasswordFunc: func(ctx context.Context, email, temporaryPassword, newPassword string) error {
+					return tt.changeErr
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/login/password-change", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			authhandler.ChangeTemporaryPassword(shared.NewUserProvisioningHandler(mockService, zap.NewNop()))(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+		})
+	}
+}
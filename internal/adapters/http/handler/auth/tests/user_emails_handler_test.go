@@ -0,0 +1,170 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	authhandler "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/auth"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestListEmailsHandler(t *testing.T) {
+	verifiedAt := time.Now()
+	mockService := &MockUserEmailService{
+		ListEmailsFunc: func(ctx context.Context, idCitizen int) ([]*domain.UserEmail, error) {
+			return []*domain.UserEmail{
+				{ID: "email-1", Email: "recovery@example.org", VerifiedAt: &verifiedAt, CodeHash: "secret"},
+				{ID: "email-2", Email: "pending@example.org"},
+			}, nil
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/me/emails", nil)
+	req = req.WithContext(withUserClaims(req.Context()))
+	w := httptest.NewRecorder()
+
+	authhandler.ListEmails(shared.NewUserEmailsHandler(mockService, zap.NewNop()))(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status code = %v, want %v", w.Code, http.StatusOK)
+	}
+	if bytes.Contains(w.Body.Bytes(), []byte("secret")) {
+		t.Errorf("response leaks the code hash: %s", w.Body.String())
+	}
+
+	var resp []response.UserEmailResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp) != 2 || !resp[0].Verified || resp[1].Verified || resp[1].VerifiedAt != nil {
+		t.Errorf("response = %+v, want one verified and one pending email", resp)
+	}
+}
+
+func TestAddEmailHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		withClaims     bool
+		addErr         error
+		wantStatusCode int
+	}{
+		{name: "code sent", body: `{"email":"recovery@example.org"}`, withClaims: true, wantStatusCode: http.StatusAccepted},
+		{name: "missing user context", body: `{"email":"recovery@example.org"}`, wantStatusCode: http.StatusUnauthorized},
+		{name: "invalid JSON", body: `{invalid`, withClaims: true, wantStatusCode: http.StatusBadRequest},
+		{name: "missing email", body: `{}`, withClaims: true, wantStatusCode: http.StatusBadRequest},
+		{name: "invalid email", body: `{"email":"nope"}`, withClaims: true, addErr: domainerrors.ErrInvalidEmail, wantStatusCode: http.StatusBadRequest},
+		{name: "already registered", body: `{"email":"recovery@example.org"}`, withClaims: true, addErr: domainerrors.ErrEmailAlreadyRegistered, wantStatusCode: http.StatusConflict},
+		{name: "too many emails", body: `{"email":"recovery@example.org"}`, withClaims: true, addErr: domainerrors.ErrTooManyEmails, wantStatusCode: http.StatusConflict},
+		{name: "rate limited", body: `{"email":"recovery@example.org"}`, withClaims: true, addErr: domainerrors.ErrEmailRateLimited, wantStatusCode: http.StatusTooManyRequests},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockUserEmailService{
+				AddEmailFunc: func(ctx context.Context, idCitizen int, email string) (*domain.UserEmail, error) {
+					if tt.addErr != nil {
+						return nil, tt.addErr
+					}
+					return &domain.UserEmail{ID: "email-1", Email: email}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/me/emails", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.withClaims {
+				req = req.WithContext(withUserClaims(req.Context()))
+			}
+			w := httptest.NewRecorder()
+
+			authhandler.AddEmail(shared.NewUserEmailsHandler(mockService, zap.NewNop()))(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+		})
+	}
+}
+
+func TestVerifyEmailHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		verifyErr      error
+		wantStatusCode int
+	}{
+		{name: "verified", body: `{"code":"123456"}`, wantStatusCode: http.StatusOK},
+		{name: "missing code", body: `{}`, wantStatusCode: http.StatusBadRequest},
+		{name: "wrong code", body: `{"code":"000000"}`, verifyErr: domainerrors.ErrInvalidVerificationCode, wantStatusCode: http.StatusBadRequest},
+		{name: "email not found", body: `{"code":"123456"}`, verifyErr: domainerrors.ErrEmailNotFound, wantStatusCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockUserEmailService{
+				VerifyEmailFunc: func(ctx context.Context, idCitizen int, id, code string) (*domain.UserEmail, error) {
+					if id != "email-1" {
+						t.Errorf("VerifyEmail() id = %v, want email-1", id)
+					}
+					if tt.verifyErr != nil {
+						return nil, tt.verifyErr
+					}
+					verifiedAt := time.Now()
+					return &domain.UserEmail{ID: id, Email: "recovery@example.org", VerifiedAt: &verifiedAt}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/me/emails/email-1/verify", bytes.NewBufferString(tt.body))
+			req = mux.SetURLVars(req.WithContext(withUserClaims(req.Context())), map[string]string{"id": "email-1"})
+			w := httptest.NewRecorder()
+
+			authhandler.VerifyEmail(shared.NewUserEmailsHandler(mockService, zap.NewNop()))(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+		})
+	}
+}
+
+func TestDeleteEmailHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		removeErr      error
+		wantStatusCode int
+	}{
+		{name: "removed", wantStatusCode: http.StatusNoContent},
+		{name: "email not found", removeErr: domainerrors.ErrEmailNotFound, wantStatusCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockUserEmailService{
+				RemoveEmailFunc: func(ctx context.Context, idCitizen int, id string) error {
+					return tt.removeErr
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, "/me/emails/email-1", nil)
+			req = mux.SetURLVars(req.WithContext(withUserClaims(req.Context())), map[string]string{"id": "email-1"})
+			w := httptest.NewRecorder()
+
+			authhandler.DeleteEmail(shared.NewUserEmailsHandler(mockService, zap.NewNop()))(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+		})
+	}
+}
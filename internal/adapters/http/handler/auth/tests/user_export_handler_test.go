@@ -0,0 +1,119 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	authhandler "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/auth"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestRequestExportHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		withClaims     bool
+		requestErr     error
+		wantStatusCode int
+	}{
+		{name: "export started", withClaims: true, wantStatusCode: http.StatusAccepted},
+		{name: "missing user context", wantStatusCode: http.StatusUnauthorized},
+		{name: "too many exports", withClaims: true, requestErr: domainerrors.ErrExportsBusy, wantStatusCode: http.StatusServiceUnavailable},
+		{name: "service failure", withClaims: true, requestErr: domainerrors.ErrInternal, wantStatusCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockUserExportService{
+				RequestExportFunc: func(ctx context.Context, idCitizen int) (*domain.UserExportJob, error) {
+					if tt.requestErr != nil {
+						return nil, tt.requestErr
+					}
+					return &domain.UserExportJob{ID: "job-1", UserID: "user-123", Status: domain.UserExportJobPending, ObjectKey: "exports/user-123/job-1.zip"}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/me/export", nil)
+			if tt.withClaims {
+				req = req.WithContext(withUserClaims(req.Context()))
+			}
+			w := httptest.NewRecorder()
+
+			authhandler.RequestExport(shared.NewUserExportHandler(mockService, zap.NewNop()))(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+			if tt.wantStatusCode != http.StatusAccepted {
+				return
+			}
+			var resp response.UserExportJobResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.ID != "job-1" || resp.Status != domain.UserExportJobPending || resp.DownloadURL != "" {
+				t.Errorf("response = %+v, want the pending job without a download URL", resp)
+			}
+		})
+	}
+}
+
+func TestGetExportHandler(t *testing.T) {
+	completedAt := time.Now()
+	completed := &domain.UserExportJob{ID: "job-1", Status: domain.UserExportJobCompleted, Size: 2048, CompletedAt: &completedAt}
+	download := &domain.UserExportDownload{URL: "https://storage.example.com/exports/user-123/job-1.zip?signature=abc", ExpiresAt: completedAt.Add(15 * time.Minute)}
+
+	tests := []struct {
+		name           string
+		getErr         error
+		wantStatusCode int
+	}{
+		{name: "completed export", wantStatusCode: http.StatusOK},
+		{name: "unknown or foreign export", getErr: domainerrors.ErrExportJobNotFound, wantStatusCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockUserExportService{
+				GetExportFunc: func(ctx context.Context, idCitizen int, jobID string) (*domain.UserExportJob, *domain.UserExportDownload, error) {
+					if jobID != "job-1" {
+						t.Errorf("GetExport() jobID = %v, want job-1", jobID)
+					}
+					if tt.getErr != nil {
+						return nil, nil, tt.getErr
+					}
+					return completed, download, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/me/export/job-1", nil)
+			req = mux.SetURLVars(req.WithContext(withUserClaims(req.Context())), map[string]string{"job": "job-1"})
+			w := httptest.NewRecorder()
+
+			authhandler.GetExport(shared.NewUserExportHandler(mockService, zap.NewNop()))(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+			if tt.wantStatusCode != http.StatusOK {
+				return
+			}
+			var resp response.UserExportJobResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Status != domain.UserExportJobCompleted || resp.Size != 2048 || resp.DownloadURL != download.URL || resp.DownloadURLExpiresAt == nil {
+				t.Errorf("response = %+v, want the completed job with its download URL", resp)
+			}
+		})
+	}
+}
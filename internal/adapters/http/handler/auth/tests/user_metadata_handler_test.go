@@ -0,0 +1,84 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	authhandler "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/auth"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestUpdateMetadataHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		noClaims       bool
+		updateErr      error
+		wantStatusCode int
+		wantCode       string
+	}{
+		{name: "successful update", body: `{"metadata":{"newsletter":false}}`, wantStatusCode: http.StatusOK},
+		{name: "missing claims", body: `{"metadata":{"newsletter":false}}`, noClaims: true, wantStatusCode: http.StatusUnauthorized, wantCode: "UNAUTHORIZED"},
+		{name: "invalid json body", body: `not json`, wantStatusCode: http.StatusBadRequest, wantCode: "INVALID_REQUEST_BODY"},
+		{name: "no changes", body: `{}`, wantStatusCode: http.StatusBadRequest, wantCode: "REQUIRED_FIELD"},
+		{name: "key not writable", body: `{"metadata":{"department":"sales"}}`, updateErr: domainerrors.ErrMetadataKeyNotWritable, wantStatusCode: http.StatusForbidden, wantCode: "METADATA_KEY_NOT_WRITABLE"},
+		{name: "invalid metadata", body: `{"metadata":{"newsletter":"no"}}`, updateErr: domainerrors.ErrInvalidUserMetadata, wantStatusCode: http.StatusBadRequest, wantCode: "INVALID_USER_METADATA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockUserMetadataService{
+				UpdateOwnMetadataFunc: func(ctx context.Context, idCitizen int, changes domain.UserMetadata) (*domain.UserPublic, error) {
+					if idCitizen != 12345 {
+						t.Errorf("UpdateOwnMetadata() idCitizen = %v, want 12345", idCitizen)
+					}
+					if tt.updateErr != nil {
+						return nil, tt.updateErr
+					}
+					return &domain.UserPublic{ID: "user-123", IDCitizen: idCitizen, Metadata: changes}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPut, "/auth/me/metadata", bytes.NewBufferString(tt.body))
+			if !tt.noClaims {
+				req = req.WithContext(withUserClaims(req.Context()))
+			}
+			w := httptest.NewRecorder()
+
+			authhandler.UpdateMetadata(shared.NewUserMetadataHandler(mockService, zap.NewNop()))(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+
+			if tt.wantCode != "" {
+				var resp response.ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("error code = %v, want %v", resp.Code, tt.wantCode)
+				}
+				return
+			}
+
+			var resp response.UserResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if want := map[string]interface{}{"newsletter": false}; !reflect.DeepEqual(resp.Metadata, want) {
+				t.Errorf("Metadata = %v, want %v", resp.Metadata, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+
+	authhandler "github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/auth"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestValidateHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		authHeader     string
+		claims         *domain.TokenClaims
+		validateErr    error
+		wantStatusCode int
+		wantHeaders    map[string]string
+	}{
+		{
+			name:       "valid token returns identity headers",
+			authHeader: "Bearer access_token_123",
+			claims: &domain.TokenClaims{
+				IDCitizen: 12345,
+				UserID:    "user-123",
+				Role:      domain.RoleAdmin,
+				Type:      domain.TokenTypeAccess,
+			},
+			wantStatusCode: http.StatusNoContent,
+			wantHeaders: map[string]string{
+				authhandler.HeaderUserID:    "user-123",
+				authhandler.HeaderUserRole:  "ADMIN",
+				authhandler.HeaderCitizenID: "12345",
+			},
+		},
+		{
+			name:           "token without user ID omits the user header",
+			authHeader:     "Bearer access_token_123",
+			claims:         &domain.TokenClaims{IDCitizen: 12345, Role: domain.RoleUser, Type: domain.TokenTypeAccess},
+			wantStatusCode: http.StatusNoContent,
+			wantHeaders: map[string]string{
+				authhandler.HeaderUserID:    "",
+				authhandler.HeaderUserRole:  "USER",
+				authhandler.HeaderCitizenID: "12345",
+			},
+		},
+		{
+			name:           "missing authorization header",
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:           "invalid authorization header format",
+			authHeader:     "Basic dXNlcjpwYXNz",
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:           "revoked token",
+			authHeader:     "Bearer revoked_token",
+			validateErr:    domainerrors.ErrTokenRevoked,
+			wantStatusCode: http.StatusUnauthorized,
+			wantHeaders:    map[string]string{authhandler.HeaderCitizenID: ""},
+		},
+		{
+			name:           "blacklist unavailable",
+			authHeader:     "Bearer access_token_123",
+			validateErr:    domainerrors.ErrInternal,
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAuthService := &MockAuthService{
+				ValidateAccessTokenFunc: func(ctx context.Context, token string) (*domain.TokenClaims, error) {
+					return tt.claims, tt.validateErr
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/validate", nil)
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+			w := httptest.NewRecorder()
+
+			h := shared.NewAuthHandler(mockAuthService, nil, nil, false, nil, zap.NewNop())
+			authhandler.Validate(h)(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Errorf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+			for header, want := range tt.wantHeaders {
+				if got := w.Header().Get(header); got != want {
+					t.Errorf("%s = %q, want %q", header, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package auth
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/request"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// ListEmails retrieves the secondary email addresses of the authenticated user
+// @Summary List secondary emails
+// @Description List the secondary email addresses of the authenticated user and their verification state
+// @Tags Authentication
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} response.UserEmailResponse "Secondary email addresses"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized or invalid token"
+// @Failure 404 {object} response.ErrorResponse "User not found"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /me/emails [get]
+func ListEmails(h *shared.UserEmailsHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		claims, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			httperrors.RespondWithError(w, httperrors.ErrUnauthorized)
+			return
+		}
+
+		emails, err := h.UserEmailService.ListEmails(r.Context(), claims.IDCitizen)
+		if err != nil {
+			h.Logger.Debug("failed to list user emails", zap.Error(err), zap.Int("id_citizen", claims.IDCitizen))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		resp := make([]response.UserEmailResponse, 0, len(emails))
+		for _, email := range emails {
+			resp = append(resp, toUserEmailResponse(email))
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusOK, resp)
+	}
+}
+
+// AddEmail registers a secondary email address
+// @Summary Add secondary email
+// @Description Register a recovery email address and send it a verification code. Once confirmed with /me/emails/{id}/verify, the address can receive password reset tokens when the primary email is inaccessible. Emails are rate limited per address.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.AddEmailRequest true "Email address"
+// @Success 202 {object} response.UserEmailResponse "Verification code sent"
+// @Failure 400 {object} response.ErrorResponse "Invalid email address"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized or invalid token"
+// @Failure 404 {object} response.ErrorResponse "User not found"
+// @Failure 409 {object} response.ErrorResponse "Email already registered or too many emails"
+// @Failure 429 {object} response.ErrorResponse "Too many emails sent to this address"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /me/emails [post]
+func AddEmail(h *shared.UserEmailsHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		claims, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			httperrors.RespondWithError(w, httperrors.ErrUnauthorized)
+			return
+		}
+
+		var req request.AddEmailRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.Logger.Debug("invalid request body", zap.Error(err))
+			httperrors.RespondWithError(w, httperrors.ErrInvalidRequestBody)
+			return
+		}
+
+		if req.Email == "" {
+			httperrors.RespondWithError(w, httperrors.ErrRequiredField)
+			return
+		}
+
+		email, err := h.UserEmailService.AddEmail(r.Context(), claims.IDCitizen, req.Email)
+		if err != nil {
+			h.Logger.Warn("failed to add user email", zap.Error(err), zap.Int("id_citizen", claims.IDCitizen))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusAccepted, toUserEmailResponse(email))
+	}
+}
+
+// VerifyEmail confirms a secondary email address
+// @Summary Verify secondary email
+// @Description Confirm a secondary email address with the code it received. The code is discarded after too many wrong codes.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Email ID"
+// @Param request body request.VerifyEmailRequest true "Verification code"
+// @Success 200 {object} response.UserEmailResponse "Email verified"
+// @Failure 400 {object} response.ErrorResponse "Invalid or expired verification code"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized or invalid token"
+// @Failure 404 {object} response.ErrorResponse "User or email not found"
+// @Failure 409 {object} response.ErrorResponse "Email already verified by another user"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /me/emails/{id}/verify [post]
+func VerifyEmail(h *shared.UserEmailsHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		claims, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			httperrors.RespondWithError(w, httperrors.ErrUnauthorized)
+			return
+		}
+
+		var req request.VerifyEmailRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.Logger.Debug("invalid request body", zap.Error(err))
+			httperrors.RespondWithError(w, httperrors.ErrInvalidRequestBody)
+			return
+		}
+
+		if req.Code == "" {
+			httperrors.RespondWithError(w, httperrors.ErrRequiredField)
+			return
+		}
+
+		id := mux.Vars(r)["id"]
+		email, err := h.UserEmailService.VerifyEmail(r.Context(), claims.IDCitizen, id, req.Code)
+		if err != nil {
+			h.Logger.Warn("failed to verify user email", zap.Error(err), zap.String("email_id", id))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusOK, toUserEmailResponse(email))
+	}
+}
+
+// DeleteEmail removes a secondary email address of the authenticated user
+// @Summary Delete secondary email
+// @Description Remove a secondary email address of the authenticated user
+// @Tags Authentication
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Email ID"
+// @Success 204 "Email removed"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized or invalid token"
+// @Failure 403 {object} response.ErrorResponse "Elevated access required (when sudo mode is enforced)"
+// @Failure 404 {object} response.ErrorResponse "User or email not found"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /me/emails/{id} [delete]
+func DeleteEmail(h *shared.UserEmailsHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		claims, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			httperrors.RespondWithError(w, httperrors.ErrUnauthorized)
+			return
+		}
+
+		id := mux.Vars(r)["id"]
+		if err := h.UserEmailService.RemoveEmail(r.Context(), claims.IDCitizen, id); err != nil {
+			h.Logger.Warn("failed to remove user email", zap.Error(err), zap.String("email_id", id))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		w.WriteHeader(nethttp.StatusNoContent)
+	}
+}
+
+// toUserEmailResponse converts the domain user email to the response DTO
+func toUserEmailResponse(email *domain.UserEmail) response.UserEmailResponse {
+	return response.UserEmailResponse{
+		ID:         email.ID,
+		Email:      email.Email,
+		Verified:   email.IsVerified(),
+		VerifiedAt: email.VerifiedAt,
+		CreatedAt:  email.CreatedAt,
+	}
+}
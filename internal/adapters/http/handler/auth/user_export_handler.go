@@ -0,0 +1,96 @@
+package auth
+
+import (
+	nethttp "net/http"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// RequestExport starts an export of the personal data of the authenticated user
+// @Summary Request personal data export
+// @Description Start assembling a zip archive of the personal data of the authenticated user: profile, secondary emails, consents, notification preferences and audit records.
+// @Description The archive is assembled in the background, poll /me/export/{job} with the returned job ID until it is completed. While an export is in progress it is returned instead of starting another one.
+// @Description Only available when an export storage is configured.
+// @Tags Authentication
+// @Produce json
+// @Security BearerAuth
+// @Success 202 {object} response.UserExportJobResponse "Export started"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized or invalid token"
+// @Failure 404 {object} response.ErrorResponse "User not found"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Failure 503 {object} response.ErrorResponse "Too many exports in progress"
+// @Router /me/export [post]
+func RequestExport(h *shared.UserExportHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		claims, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			httperrors.RespondWithError(w, httperrors.ErrUnauthorized)
+			return
+		}
+
+		job, err := h.UserExportService.RequestExport(r.Context(), claims.IDCitizen)
+		if err != nil {
+			h.Logger.Warn("failed to request export", zap.Error(err), zap.Int("id_citizen", claims.IDCitizen))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusAccepted, toUserExportJobResponse(job, nil))
+	}
+}
+
+// GetExport reports the status of an export of the authenticated user
+// @Summary Get personal data export
+// @Description Get the status of an export of the authenticated user. Once completed, the response carries a signed URL of the archive, valid for a limited time.
+// @Description Exports and their archives are removed after the retention period.
+// @Tags Authentication
+// @Produce json
+// @Security BearerAuth
+// @Param job path string true "Export job ID"
+// @Success 200 {object} response.UserExportJobResponse "Export status"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized or invalid token"
+// @Failure 404 {object} response.ErrorResponse "Export not found or expired"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /me/export/{job} [get]
+func GetExport(h *shared.UserExportHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		claims, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			httperrors.RespondWithError(w, httperrors.ErrUnauthorized)
+			return
+		}
+
+		job, download, err := h.UserExportService.GetExport(r.Context(), claims.IDCitizen, mux.Vars(r)["job"])
+		if err != nil {
+			h.Logger.Debug("failed to get export", zap.Error(err), zap.Int("id_citizen", claims.IDCitizen))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusOK, toUserExportJobResponse(job, download))
+	}
+}
+
+// toUserExportJobResponse converts the domain export job and its download to the response DTO
+func toUserExportJobResponse(job *domain.UserExportJob, download *domain.UserExportDownload) response.UserExportJobResponse {
+	resp := response.UserExportJobResponse{
+		ID:          job.ID,
+		Status:      job.Status,
+		Size:        job.Size,
+		CreatedAt:   job.CreatedAt,
+		CompletedAt: job.CompletedAt,
+		ExpiresAt:   job.ExpiresAt,
+	}
+	if download != nil {
+		resp.DownloadURL = download.URL
+		resp.DownloadURLExpiresAt = &download.ExpiresAt
+	}
+	return resp
+}
@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/request"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+)
+
+// UpdateMetadata changes the custom metadata of the authenticated user
+// @Summary Update own metadata
+// @Description Sets the custom attributes of the authenticated user. Only the self-service keys of the metadata schema can be changed.
+// @Description Keys set to null are removed, omitted keys keep their current value.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.UpdateUserMetadataRequest true "Metadata changes"
+// @Success 200 {object} response.UserResponse "Metadata updated"
+// @Failure 400 {object} response.ErrorResponse "Invalid request or value of the wrong type"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized or invalid token"
+// @Failure 403 {object} response.ErrorResponse "The metadata key cannot be changed by the user"
+// @Failure 404 {object} response.ErrorResponse "User not found"
+// @Failure 413 {object} response.ErrorResponse "Metadata exceeds the maximum size"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /me/metadata [put]
+func UpdateMetadata(h *shared.UserMetadataHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		claims, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			httperrors.RespondWithError(w, httperrors.ErrUnauthorized)
+			return
+		}
+
+		var req request.UpdateUserMetadataRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.Logger.Debug("invalid request body", zap.Error(err))
+			httperrors.RespondWithError(w, httperrors.ErrInvalidRequestBody)
+			return
+		}
+
+		if len(req.Metadata) == 0 {
+			httperrors.RespondWithError(w, httperrors.ErrRequiredField)
+			return
+		}
+
+		user, err := h.UserMetadataService.UpdateOwnMetadata(r.Context(), claims.IDCitizen, req.Metadata)
+		if err != nil {
+			h.Logger.Warn("failed to update user metadata", zap.Error(err), zap.Int("id_citizen", claims.IDCitizen))
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		resp := response.UserResponse{
+			ID:        user.ID,
+			IDCitizen: user.IDCitizen,
+			Email:     user.Email,
+			Name:      user.Name,
+			Role:      user.Role,
+			Metadata:  user.Metadata,
+			CreatedAt: user.CreatedAt,
+			UpdatedAt: user.UpdatedAt,
+		}
+
+		shared.RespondWithJSON(w, nethttp.StatusOK, resp)
+	}
+}
@@ -0,0 +1,68 @@
+package auth
+
+import (
+	nethttp "net/http"
+	"strconv"
+	"strings"
+
+	_ "github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response" // Used in Swagger annotations
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// Identity headers returned by the validate endpoint, forwarded by the gateway to the upstream services
+const (
+	HeaderUserID    = "X-User-Id"
+	HeaderUserRole  = "X-User-Role"
+	HeaderCitizenID = "X-Citizen-Id"
+)
+
+// Validate validates the access token of the Authorization header for gateway authentication
+// @Summary Validate access token
+// @Description Validates the bearer access token, including revocation, and returns the identity of the user in headers.
+// @Description Intended for nginx auth_request and Envoy ext_authz: 204 allows the request, 401 rejects it.
+// @Description X-User-Id is only returned for tokens issued with the user ID.
+// @Tags Authentication
+// @Security BearerAuth
+// @Success 204 "Token valid"
+// @Header 204 {string} X-User-Id "ID of the user"
+// @Header 204 {string} X-User-Role "Role of the user"
+// @Header 204 {integer} X-Citizen-Id "Citizen ID of the user"
+// @Failure 401 {object} response.ErrorResponse "Missing, invalid, expired or revoked token"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /validate [get]
+func Validate(h *shared.AuthHandler) nethttp.HandlerFunc {
+	return func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		authHeader := r.Header.Get("Authorization")
+		if authHeader == "" {
+			httperrors.RespondWithError(w, httperrors.ErrMissingAuthHeader)
+			return
+		}
+
+		token, ok := strings.CutPrefix(authHeader, "Bearer ")
+		if !ok || token == "" {
+			httperrors.RespondWithError(w, httperrors.ErrInvalidAuthHeader)
+			return
+		}
+
+		// Signature, expiration and blacklist are checked, no database access is needed
+		claims, err := h.AuthService.ValidateAccessToken(r.Context(), token)
+		if err != nil {
+			httperrors.RespondWithDomainError(w, err)
+			return
+		}
+
+		setIdentityHeaders(w, claims)
+		w.WriteHeader(nethttp.StatusNoContent)
+	}
+}
+
+// setIdentityHeaders sets the identity headers of the token claims, X-User-Id only when the token has it
+func setIdentityHeaders(w nethttp.ResponseWriter, claims *domain.TokenClaims) {
+	if claims.UserID != "" {
+		w.Header().Set(HeaderUserID, claims.UserID)
+	}
+	w.Header().Set(HeaderUserRole, claims.Role.String())
+	w.Header().Set(HeaderCitizenID, strconv.Itoa(claims.IDCitizen))
+}
@@ -0,0 +1,62 @@
+package health
+
+import (
+	"context"
+	nethttp "net/http"
+	"time"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// Details reports the detailed health of the service (ADMIN only)
+// @Summary Detailed health check
+// @Description Reports the status of each dependency with the latency of its check, gauges such as the depth of the
+// @Description consumed RabbitMQ queues and the outbox backlog, and the number of goroutines. A gauge that can't be read
+// @Description has a null value and an error, it doesn't change the status.
+// @Tags Health
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.HealthDetailsResponse "Service is healthy or degraded"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden - Admin role required"
+// @Failure 503 {object} response.HealthDetailsResponse "Service is unhealthy"
+// @Router /health/details [get]
+func (h *HealthHandler) Details(w nethttp.ResponseWriter, r *nethttp.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	details := h.details.Details(ctx)
+
+	resp := response.HealthDetailsResponse{
+		Status:       details.Status,
+		Timestamp:    time.Now(),
+		Version:      h.version,
+		Dependencies: make([]response.DependencyHealthResponse, 0, len(details.Dependencies)),
+		Gauges:       make([]response.GaugeResponse, 0, len(details.Gauges)),
+		Goroutines:   details.Goroutines,
+	}
+	for _, dependency := range details.Dependencies {
+		resp.Dependencies = append(resp.Dependencies, response.DependencyHealthResponse{
+			Name:        dependency.Name,
+			Criticality: string(dependency.Criticality),
+			Status:      dependency.Status(),
+			LatencyMs:   float64(dependency.Latency.Microseconds()) / 1000,
+			Error:       dependency.Error,
+		})
+	}
+	for _, gauge := range details.Gauges {
+		gaugeResp := response.GaugeResponse{Name: gauge.Name, Error: gauge.Error}
+		if gauge.Error == "" {
+			gaugeResp.Value = &gauge.Value
+		}
+		resp.Gauges = append(resp.Gauges, gaugeResp)
+	}
+
+	statusCode := nethttp.StatusOK
+	if details.Status == domain.HealthStatusUnhealthy {
+		statusCode = nethttp.StatusServiceUnavailable
+	}
+	shared.RespondWithJSON(w, statusCode, resp)
+}
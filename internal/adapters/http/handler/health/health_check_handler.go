@@ -9,48 +9,40 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
 )
 
 // Health checks service health status
 // @Summary Complete health check
-// @Description Check the health status of the service and its dependencies (database, Redis)
+// @Description Check the health status of the service and its dependencies (database, Redis, RabbitMQ).
+// @Description The status is degraded when only dependencies the service can run without are down.
 // @Tags Health
 // @Accept json
 // @Produce json
-// @Success 200 {object} response.HealthResponse "Service is healthy"
+// @Success 200 {object} response.HealthResponse "Service is healthy or degraded"
 // @Failure 503 {object} response.HealthResponse "Service is unhealthy"
 // @Router /health [get]
 func (h *HealthHandler) Health(w nethttp.ResponseWriter, r *nethttp.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 
-	services := make(map[string]string)
+	statuses := h.dependencies.CheckDependencies(ctx)
 
-	// Check database
-	if err := h.db.PingContext(ctx); err != nil {
-		h.logger.Warn("database health check failed", zap.Error(err))
-		services["database"] = "unhealthy"
-	} else {
-		services["database"] = "healthy"
-	}
-
-	// Check Redis
-	if err := h.redisClient.Ping(ctx).Err(); err != nil {
-		h.logger.Warn("redis health check failed", zap.Error(err))
-		services["redis"] = "unhealthy"
-	} else {
-		services["redis"] = "healthy"
-	}
-
-	// Determine overall status
-	overallStatus := "healthy"
-	for _, status := range services {
-		if status == "unhealthy" {
-			overallStatus = "unhealthy"
-			break
+	services := make(map[string]string, len(statuses))
+	for _, status := range statuses {
+		if !status.Healthy {
+			h.logger.Warn("dependency health check failed",
+				zap.String("dependency", status.Name),
+				zap.String("criticality", string(status.Criticality)),
+				zap.String("error", status.Error),
+			)
 		}
+		services[status.Name] = status.Status()
 	}
 
+	// Degraded-ok dependencies being down degrade the service without making it unhealthy
+	overallStatus := domain.OverallHealthStatus(statuses)
+
 	resp := response.HealthResponse{
 		Status:    overallStatus,
 		Timestamp: time.Now(),
@@ -59,7 +51,7 @@ func (h *HealthHandler) Health(w nethttp.ResponseWriter, r *nethttp.Request) {
 	}
 
 	statusCode := nethttp.StatusOK
-	if overallStatus == "unhealthy" {
+	if overallStatus == domain.HealthStatusUnhealthy {
 		statusCode = nethttp.StatusServiceUnavailable
 	}
 
@@ -4,37 +4,48 @@ import (
 	"context"
 	"encoding/json"
 	nethttp "net/http"
+	"strings"
 	"time"
 
 	_ "github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response" // Used in Swagger annotations
 	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
 )
 
 // Ready checks if the service is ready to receive traffic
 // @Summary Readiness check
-// @Description Check if the service is ready to receive traffic (used by Kubernetes)
+// @Description Check if the service is ready to receive traffic (used by Kubernetes).
+// @Description The service is not ready until its warm-up completes (schema migration, consumer registration).
 // @Tags Health
 // @Accept json
 // @Produce json
-// @Success 200 {object} map[string]string "Service is ready"
+// @Success 200 {object} map[string]string "Service is ready (status is degraded when optional dependencies are down)"
 // @Failure 503 {object} response.ErrorResponse "Service is not ready"
 // @Router /health/ready [get]
 func (h *HealthHandler) Ready(w nethttp.ResponseWriter, r *nethttp.Request) {
+	if pending := h.readiness.PendingSteps(); len(pending) > 0 {
+		httperrors.RespondWithErrorMessage(w, nethttp.StatusServiceUnavailable, "warming up: "+strings.Join(pending, ", "))
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
 	defer cancel()
 
-	// Check critical connections
-	if err := h.db.PingContext(ctx); err != nil {
-		httperrors.RespondWithErrorMessage(w, nethttp.StatusServiceUnavailable, "database not ready")
-		return
+	// Only required dependencies make the service not ready
+	statuses := h.dependencies.CheckDependencies(ctx)
+	for _, status := range statuses {
+		if !status.Healthy && status.Criticality == domain.DependencyRequired {
+			httperrors.RespondWithErrorMessage(w, nethttp.StatusServiceUnavailable, status.Name+" not ready")
+			return
+		}
 	}
 
-	if err := h.redisClient.Ping(ctx).Err(); err != nil {
-		httperrors.RespondWithErrorMessage(w, nethttp.StatusServiceUnavailable, "redis not ready")
-		return
+	readiness := "ready"
+	if domain.OverallHealthStatus(statuses) == domain.HealthStatusDegraded {
+		readiness = domain.HealthStatusDegraded
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(nethttp.StatusOK)
-	_ = json.NewEncoder(w).Encode(map[string]string{"status": "ready"})
+	_ = json.NewEncoder(w).Encode(map[string]string{"status": readiness})
 }
@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/health"
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+)
+
+func TestHealthHandler_Details(t *testing.T) {
+	tests := []struct {
+		name           string
+		redisErr       error
+		wantStatusCode int
+		wantStatus     string
+	}{
+		{name: "healthy", wantStatusCode: http.StatusOK, wantStatus: "healthy"},
+		{name: "required dependency down", redisErr: errors.New("connection refused"), wantStatusCode: http.StatusServiceUnavailable, wantStatus: "unhealthy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zap.NewNop()
+			dependencies := NewMockDependencies(NewMockDB(nil), NewMockRedisClient(tt.redisErr), nil)
+			details := services.NewHealthDetailsService(dependencies, logger,
+				services.HealthGauge{Name: "outbox_backlog", Read: func(ctx context.Context) (int64, error) { return 7, nil }},
+				services.HealthGauge{Name: "rabbitmq_queue_depth.auth_user_transferred", Read: func(ctx context.Context) (int64, error) {
+					return 0, errors.New("connection is closed")
+				}},
+			)
+
+			req := httptest.NewRequest(http.MethodGet, "/health/details", nil)
+			w := httptest.NewRecorder()
+			health.NewHealthHandler(dependencies, services.NewReadinessGate(logger), details, logger, "1.0.0-test").Details(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatusCode)
+			}
+
+			var resp response.HealthDetailsResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Status != tt.wantStatus || resp.Version != "1.0.0-test" {
+				t.Errorf("status = %v, version = %v, want %v, 1.0.0-test", resp.Status, resp.Version, tt.wantStatus)
+			}
+			if len(resp.Dependencies) != 3 || resp.Dependencies[0].Name != "database" || resp.Dependencies[0].LatencyMs < 0 {
+				t.Errorf("dependencies = %+v, want database, redis and rabbitmq with their latency", resp.Dependencies)
+			}
+			if len(resp.Gauges) != 2 {
+				t.Fatalf("gauges = %+v, want 2", resp.Gauges)
+			}
+			if gauge := resp.Gauges[0]; gauge.Value == nil || *gauge.Value != 7 || gauge.Error != "" {
+				t.Errorf("outbox gauge = %+v, want 7", gauge)
+			}
+			if gauge := resp.Gauges[1]; gauge.Value != nil || gauge.Error == "" {
+				t.Errorf("queue gauge = %+v, want a null value with the error", gauge)
+			}
+			if resp.Goroutines < 1 {
+				t.Errorf("goroutines = %d, want at least 1", resp.Goroutines)
+			}
+		})
+	}
+}
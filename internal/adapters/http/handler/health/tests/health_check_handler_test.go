@@ -12,6 +12,7 @@ import (
 
 	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
 	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/health"
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
 )
 
 func TestHealthCheckHandler(t *testing.T) {
@@ -21,6 +22,7 @@ func TestHealthCheckHandler(t *testing.T) {
 		name           string
 		dbPingFunc     func(ctx context.Context) error
 		redisErr       error
+		rabbitErr      error
 		wantStatusCode int
 		checkResponse  func(*testing.T, *httptest.ResponseRecorder)
 	}{
@@ -119,6 +121,29 @@ func TestHealthCheckHandler(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "rabbitmq down degrades the service",
+			dbPingFunc: func(ctx context.Context) error {
+				return nil
+			},
+			rabbitErr:      errors.New("connection is closed"),
+			wantStatusCode: http.StatusOK,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				var resp response.HealthResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Status != "degraded" {
+					t.Errorf("Status = %v, want degraded", resp.Status)
+				}
+				if resp.Services["rabbitmq"] != "unhealthy" {
+					t.Errorf("Services[rabbitmq] = %v, want unhealthy", resp.Services["rabbitmq"])
+				}
+				if resp.Services["database"] != "healthy" {
+					t.Errorf("Services[database] = %v, want healthy", resp.Services["database"])
+				}
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -129,7 +154,7 @@ func TestHealthCheckHandler(t *testing.T) {
 			req := httptest.NewRequest(http.MethodGet, "/health", nil)
 			w := httptest.NewRecorder()
 
-			handler := health.NewHealthHandler(mockDB, mockRedis, logger, "1.0.0-test")
+			handler := health.NewHealthHandler(NewMockDependencies(mockDB, mockRedis, tt.rabbitErr), services.NewReadinessGate(logger), nil, logger, "1.0.0-test")
 			handler.Health(w, req)
 
 			if w.Code != tt.wantStatusCode {
@@ -37,7 +37,7 @@ func TestLiveHandler(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Live only reports alive; DB/Redis are not required for Live.
-			handler := health.NewHealthHandler(nil, nil, logger, "1.0.0")
+			handler := health.NewHealthHandler(nil, nil, nil, logger, "1.0.0")
 
 			req := httptest.NewRequest(http.MethodGet, "/health/live", nil)
 			w := httptest.NewRecorder()
@@ -5,6 +5,10 @@ import (
 	"database/sql"
 
 	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
 )
 
 type MockDB struct {
@@ -53,6 +57,22 @@ func NewMockRedisClient(err error) *MockRedisClient {
 	return &MockRedisClient{PingErr: err}
 }
 
+// NewMockDependencies declares the database and Redis mocks as required dependencies
+// and a RabbitMQ check failing with rabbitErr as a degraded-ok dependency
+func NewMockDependencies(db *MockDB, redisClient *MockRedisClient, rabbitErr error) *services.DependencyManager {
+	return services.NewDependencyManager(
+		services.DependencyStartupPolicy{MaxAttempts: 1},
+		zap.NewNop(),
+		services.Dependency{Name: "database", Criticality: domain.DependencyRequired, Check: db.PingContext},
+		services.Dependency{Name: "redis", Criticality: domain.DependencyRequired, Check: func(ctx context.Context) error {
+			return redisClient.Ping(ctx).Err()
+		}},
+		services.Dependency{Name: "rabbitmq", Criticality: domain.DependencyDegradedOK, Check: func(ctx context.Context) error {
+			return rabbitErr
+		}},
+	)
+}
+
 func DBFromMock(mock *MockDB) *sql.DB {
 	return nil
 }
@@ -11,6 +11,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/health"
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
 )
 
 func TestReadyHandler(t *testing.T) {
@@ -20,6 +21,8 @@ func TestReadyHandler(t *testing.T) {
 		name           string
 		dbPingFunc     func(ctx context.Context) error
 		redisErr       error
+		rabbitErr      error
+		pendingSteps   []string
 		wantStatusCode int
 		checkResponse  func(*testing.T, *httptest.ResponseRecorder)
 	}{
@@ -97,6 +100,40 @@ func TestReadyHandler(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "ready in degraded mode when rabbitmq is down",
+			dbPingFunc: func(ctx context.Context) error {
+				return nil
+			},
+			rabbitErr:      errors.New("connection is closed"),
+			wantStatusCode: http.StatusOK,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				var resp map[string]string
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp["status"] != "degraded" {
+					t.Errorf("status = %v, want degraded", resp["status"])
+				}
+			},
+		},
+		{
+			name: "not ready while warming up",
+			dbPingFunc: func(ctx context.Context) error {
+				return nil
+			},
+			pendingSteps:   []string{"database schema", "message consumers"},
+			wantStatusCode: http.StatusServiceUnavailable,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				var resp map[string]interface{}
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if errMsg, _ := resp["error"].(string); errMsg != "warming up: database schema, message consumers" {
+					t.Errorf("error = %v, want warming up: database schema, message consumers", errMsg)
+				}
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -107,7 +144,7 @@ func TestReadyHandler(t *testing.T) {
 			req := httptest.NewRequest(http.MethodGet, "/health/ready", nil)
 			w := httptest.NewRecorder()
 
-			handler := health.NewHealthHandler(mockDB, mockRedis, logger, "1.0.0-test")
+			handler := health.NewHealthHandler(NewMockDependencies(mockDB, mockRedis, tt.rabbitErr), services.NewReadinessGate(logger, tt.pendingSteps...), nil, logger, "1.0.0-test")
 			handler.Ready(w, req)
 
 			if w.Code != tt.wantStatusCode {
@@ -0,0 +1,21 @@
+package shared
+
+import (
+	"go.uber.org/zap"
+)
+
+// AdminConfigHandler exposes the effective configuration of the replica (ADMIN only)
+type AdminConfigHandler struct {
+	Config  map[string]interface{} // resolved at startup, with the secrets already redacted
+	Version string
+	Logger  *zap.Logger
+}
+
+// NewAdminConfigHandler creates a new instance of AdminConfigHandler
+func NewAdminConfigHandler(config map[string]interface{}, version string, logger *zap.Logger) *AdminConfigHandler {
+	return &AdminConfigHandler{
+		Config:  config,
+		Version: version,
+		Logger:  logger,
+	}
+}
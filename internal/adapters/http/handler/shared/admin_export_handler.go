@@ -0,0 +1,21 @@
+package shared
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+)
+
+// AdminExportHandler streams dataset exports (ADMIN only)
+type AdminExportHandler struct {
+	ExportService services.ExportServiceInterface
+	Logger        *zap.Logger
+}
+
+// NewAdminExportHandler creates a new instance of AdminExportHandler
+func NewAdminExportHandler(exportService services.ExportServiceInterface, logger *zap.Logger) *AdminExportHandler {
+	return &AdminExportHandler{
+		ExportService: exportService,
+		Logger:        logger,
+	}
+}
@@ -0,0 +1,21 @@
+package shared
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+)
+
+// AdminRoutesHandler exposes the routes registered in the router with their metadata (ADMIN only)
+type AdminRoutesHandler struct {
+	ListRoutes func() ([]response.RouteResponse, error)
+	Logger     *zap.Logger
+}
+
+// NewAdminRoutesHandler creates a new instance of AdminRoutesHandler
+func NewAdminRoutesHandler(listRoutes func() ([]response.RouteResponse, error), logger *zap.Logger) *AdminRoutesHandler {
+	return &AdminRoutesHandler{
+		ListRoutes: listRoutes,
+		Logger:     logger,
+	}
+}
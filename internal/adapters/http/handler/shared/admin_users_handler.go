@@ -0,0 +1,33 @@
+package shared
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+)
+
+// AdminUsersHandler manages users administration (ADMIN only)
+type AdminUsersHandler struct {
+	AnonymizationService services.AnonymizationServiceInterface
+	UserEmailService     services.UserEmailServiceInterface
+	RoleService          services.RoleServiceInterface
+	UserMergeService     services.UserMergeServiceInterface
+	Logger               *zap.Logger
+}
+
+// NewAdminUsersHandler creates a new instance of AdminUsersHandler
+func NewAdminUsersHandler(
+	anonymizationService services.AnonymizationServiceInterface,
+	userEmailService services.UserEmailServiceInterface,
+	roleService services.RoleServiceInterface,
+	userMergeService services.UserMergeServiceInterface,
+	logger *zap.Logger,
+) *AdminUsersHandler {
+	return &AdminUsersHandler{
+		AnonymizationService: anonymizationService,
+		UserEmailService:     userEmailService,
+		RoleService:          roleService,
+		UserMergeService:     userMergeService,
+		Logger:               logger,
+	}
+}
@@ -0,0 +1,21 @@
+package shared
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+)
+
+// ConsentHandler manages the requests related to the OAuth2 consents granted by users
+type ConsentHandler struct {
+	ConsentService services.ConsentServiceInterface
+	Logger         *zap.Logger
+}
+
+// NewConsentHandler creates a new instance of ConsentHandler
+func NewConsentHandler(consentService services.ConsentServiceInterface, logger *zap.Logger) *ConsentHandler {
+	return &ConsentHandler{
+		ConsentService: consentService,
+		Logger:         logger,
+	}
+}
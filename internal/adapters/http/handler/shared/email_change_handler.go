@@ -0,0 +1,21 @@
+package shared
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+)
+
+// EmailChangeHandler manages the requests to change the primary email of a user
+type EmailChangeHandler struct {
+	EmailChangeService services.EmailChangeServiceInterface
+	Logger             *zap.Logger
+}
+
+// NewEmailChangeHandler creates a new instance of EmailChangeHandler
+func NewEmailChangeHandler(emailChangeService services.EmailChangeServiceInterface, logger *zap.Logger) *EmailChangeHandler {
+	return &EmailChangeHandler{
+		EmailChangeService: emailChangeService,
+		Logger:             logger,
+	}
+}
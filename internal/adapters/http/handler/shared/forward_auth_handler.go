@@ -0,0 +1,60 @@
+package shared
+
+import (
+	"net"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+)
+
+// ForwardAuthHandler manages the forward authentication requests of reverse proxies (Traefik ForwardAuth,
+// Envoy ext_authz, nginx auth_request) protecting other applications
+type ForwardAuthHandler struct {
+	AuthService services.AuthServiceInterface
+	Logger      *zap.Logger
+
+	// TrustedHosts are the forwarded hosts the service authenticates for, "*.example.com" matches subdomains
+	TrustedHosts []string
+	// LoginURL is where browsers are sent when they are not authenticated, empty disables the redirect
+	LoginURL string
+	// CookieName is the cookie holding the access token of browser sessions
+	CookieName string
+}
+
+// NewForwardAuthHandler creates a new instance of ForwardAuthHandler
+func NewForwardAuthHandler(authService services.AuthServiceInterface, trustedHosts []string, loginURL, cookieName string, logger *zap.Logger) *ForwardAuthHandler {
+	return &ForwardAuthHandler{
+		AuthService:  authService,
+		Logger:       logger,
+		TrustedHosts: trustedHosts,
+		LoginURL:     loginURL,
+		CookieName:   cookieName,
+	}
+}
+
+// IsTrustedHost returns true if the host, with or without port, is one of the trusted hosts
+func (h *ForwardAuthHandler) IsTrustedHost(host string) bool {
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	host = strings.ToLower(host)
+	if host == "" {
+		return false
+	}
+
+	for _, trusted := range h.TrustedHosts {
+		trusted = strings.ToLower(trusted)
+		if suffix, ok := strings.CutPrefix(trusted, "*"); ok {
+			if strings.HasSuffix(host, suffix) && len(host) > len(suffix) {
+				return true
+			}
+			continue
+		}
+		if host == trusted {
+			return true
+		}
+	}
+	return false
+}
@@ -10,12 +10,35 @@ import (
 type AuthHandler struct {
 	AuthService services.AuthServiceInterface
 	Logger      *zap.Logger
+
+	// PhoneLoginService logs users in with their phone number, nil or disabled when phone login is off
+	PhoneLoginService services.PhoneLoginServiceInterface
+
+	// AvatarService serves the avatars of users, nil when no avatar storage is configured
+	AvatarService services.AvatarServiceInterface
+
+	// IncludeUserOnLogin embeds the user profile in login responses unless the request says otherwise
+	IncludeUserOnLogin bool
+
+	// SessionCookie delivers the access token in a cookie as well, nil when cookie mode is off
+	SessionCookie *SessionCookie
 }
 
 // NewAuthHandler creates a new instance of AuthHandler
-func NewAuthHandler(authService services.AuthServiceInterface, logger *zap.Logger) *AuthHandler {
+func NewAuthHandler(
+	authService services.AuthServiceInterface,
+	phoneLoginService services.PhoneLoginServiceInterface,
+	avatarService services.AvatarServiceInterface,
+	includeUserOnLogin bool,
+	sessionCookie *SessionCookie,
+	logger *zap.Logger,
+) *AuthHandler {
 	return &AuthHandler{
-		AuthService: authService,
-		Logger:      logger,
+		AuthService:        authService,
+		Logger:             logger,
+		PhoneLoginService:  phoneLoginService,
+		AvatarService:      avatarService,
+		IncludeUserOnLogin: includeUserOnLogin,
+		SessionCookie:      sessionCookie,
 	}
 }
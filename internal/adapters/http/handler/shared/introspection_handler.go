@@ -0,0 +1,21 @@
+package shared
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+)
+
+// IntrospectionHandler manages the OAuth2 token introspection requests
+type IntrospectionHandler struct {
+	IntrospectionService services.IntrospectionServiceInterface
+	Logger               *zap.Logger
+}
+
+// NewIntrospectionHandler creates a new instance of IntrospectionHandler
+func NewIntrospectionHandler(introspectionService services.IntrospectionServiceInterface, logger *zap.Logger) *IntrospectionHandler {
+	return &IntrospectionHandler{
+		IntrospectionService: introspectionService,
+		Logger:               logger,
+	}
+}
@@ -0,0 +1,21 @@
+package shared
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+)
+
+// NotificationPreferencesHandler manages the requests related to notification preferences
+type NotificationPreferencesHandler struct {
+	NotificationService services.NotificationServiceInterface
+	Logger              *zap.Logger
+}
+
+// NewNotificationPreferencesHandler creates a new instance of NotificationPreferencesHandler
+func NewNotificationPreferencesHandler(notificationService services.NotificationServiceInterface, logger *zap.Logger) *NotificationPreferencesHandler {
+	return &NotificationPreferencesHandler{
+		NotificationService: notificationService,
+		Logger:              logger,
+	}
+}
@@ -8,14 +8,32 @@ import (
 
 // OAuth2Handler manages OAuth2-related requests
 type OAuth2Handler struct {
-	OAuth2Service services.OAuth2ServiceInterface
-	Logger        *zap.Logger
+	OAuth2Service              services.OAuth2ServiceInterface
+	DeviceAuthorizationService services.DeviceAuthorizationServiceInterface
+	PasswordGrantService       services.PasswordGrantServiceInterface
+	Logger                     *zap.Logger
+
+	// ClientCertHeader is the header where the TLS terminating proxy forwards the client certificate, empty
+	// when the service terminates TLS itself. It is only honored with TrustProxyHeaders.
+	ClientCertHeader  string
+	TrustProxyHeaders bool
 }
 
 // NewOAuth2Handler creates a new instance of OAuth2Handler
-func NewOAuth2Handler(oauth2Service services.OAuth2ServiceInterface, logger *zap.Logger) *OAuth2Handler {
+func NewOAuth2Handler(
+	oauth2Service services.OAuth2ServiceInterface,
+	deviceAuthorizationService services.DeviceAuthorizationServiceInterface,
+	passwordGrantService services.PasswordGrantServiceInterface,
+	clientCertHeader string,
+	trustProxyHeaders bool,
+	logger *zap.Logger,
+) *OAuth2Handler {
 	return &OAuth2Handler{
-		OAuth2Service: oauth2Service,
-		Logger:        logger,
+		OAuth2Service:              oauth2Service,
+		DeviceAuthorizationService: deviceAuthorizationService,
+		PasswordGrantService:       passwordGrantService,
+		Logger:                     logger,
+		ClientCertHeader:           clientCertHeader,
+		TrustProxyHeaders:          trustProxyHeaders,
 	}
 }
@@ -0,0 +1,21 @@
+package shared
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+)
+
+// PasswordResetHandler manages the requests to reset a forgotten password
+type PasswordResetHandler struct {
+	PasswordResetService services.PasswordResetServiceInterface
+	Logger               *zap.Logger
+}
+
+// NewPasswordResetHandler creates a new instance of PasswordResetHandler
+func NewPasswordResetHandler(passwordResetService services.PasswordResetServiceInterface, logger *zap.Logger) *PasswordResetHandler {
+	return &PasswordResetHandler{
+		PasswordResetService: passwordResetService,
+		Logger:               logger,
+	}
+}
@@ -0,0 +1,21 @@
+package shared
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+)
+
+// PhoneHandler manages the requests related to the phone number of the authenticated user
+type PhoneHandler struct {
+	PhoneService services.PhoneServiceInterface
+	Logger       *zap.Logger
+}
+
+// NewPhoneHandler creates a new instance of PhoneHandler
+func NewPhoneHandler(phoneService services.PhoneServiceInterface, logger *zap.Logger) *PhoneHandler {
+	return &PhoneHandler{
+		PhoneService: phoneService,
+		Logger:       logger,
+	}
+}
@@ -0,0 +1,21 @@
+package shared
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+)
+
+// QuotasHandler manages the token issuance quotas of clients and users (ADMIN only)
+type QuotasHandler struct {
+	QuotaService services.QuotaServiceInterface
+	Logger       *zap.Logger
+}
+
+// NewQuotasHandler creates a new instance of QuotasHandler
+func NewQuotasHandler(quotaService services.QuotaServiceInterface, logger *zap.Logger) *QuotasHandler {
+	return &QuotasHandler{
+		QuotaService: quotaService,
+		Logger:       logger,
+	}
+}
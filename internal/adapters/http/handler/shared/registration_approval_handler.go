@@ -0,0 +1,21 @@
+package shared
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+)
+
+// RegistrationApprovalHandler lets administrators review the registrations waiting for approval (ADMIN only)
+type RegistrationApprovalHandler struct {
+	RegistrationApprovalService services.RegistrationApprovalServiceInterface
+	Logger                      *zap.Logger
+}
+
+// NewRegistrationApprovalHandler creates a new instance of RegistrationApprovalHandler
+func NewRegistrationApprovalHandler(registrationApprovalService services.RegistrationApprovalServiceInterface, logger *zap.Logger) *RegistrationApprovalHandler {
+	return &RegistrationApprovalHandler{
+		RegistrationApprovalService: registrationApprovalService,
+		Logger:                      logger,
+	}
+}
@@ -0,0 +1,21 @@
+package shared
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+)
+
+// ScopesHandler manages the OAuth2 scope registry
+type ScopesHandler struct {
+	ScopeService services.ScopeServiceInterface
+	Logger       *zap.Logger
+}
+
+// NewScopesHandler creates a new instance of ScopesHandler
+func NewScopesHandler(scopeService services.ScopeServiceInterface, logger *zap.Logger) *ScopesHandler {
+	return &ScopesHandler{
+		ScopeService: scopeService,
+		Logger:       logger,
+	}
+}
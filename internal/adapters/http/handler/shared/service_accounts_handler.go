@@ -0,0 +1,21 @@
+package shared
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+)
+
+// ServiceAccountsHandler lets administrators manage the service accounts (ADMIN only)
+type ServiceAccountsHandler struct {
+	ServiceAccountService services.ServiceAccountServiceInterface
+	Logger                *zap.Logger
+}
+
+// NewServiceAccountsHandler creates a new instance of ServiceAccountsHandler
+func NewServiceAccountsHandler(serviceAccountService services.ServiceAccountServiceInterface, logger *zap.Logger) *ServiceAccountsHandler {
+	return &ServiceAccountsHandler{
+		ServiceAccountService: serviceAccountService,
+		Logger:                logger,
+	}
+}
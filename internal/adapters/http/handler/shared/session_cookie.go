@@ -0,0 +1,63 @@
+package shared
+
+import (
+	nethttp "net/http"
+	"strings"
+	"time"
+)
+
+// SessionCookie is the cookie delivering the access token to browsers in cookie mode. It is HttpOnly,
+// so scripts can't read the token.
+type SessionCookie struct {
+	Name     string
+	Domain   string
+	Path     string
+	SameSite nethttp.SameSite
+	Secure   bool
+}
+
+// NewSessionCookie creates a new instance of SessionCookie. sameSite is strict, lax or none.
+func NewSessionCookie(name, domain, path, sameSite string, secure bool) *SessionCookie {
+	mode := nethttp.SameSiteLaxMode
+	switch strings.ToLower(sameSite) {
+	case "strict":
+		mode = nethttp.SameSiteStrictMode
+	case "none":
+		mode = nethttp.SameSiteNoneMode
+	}
+
+	return &SessionCookie{
+		Name:     name,
+		Domain:   domain,
+		Path:     path,
+		SameSite: mode,
+		Secure:   secure,
+	}
+}
+
+// Set sets the cookie to the access token, expiring with it
+func (c *SessionCookie) Set(w nethttp.ResponseWriter, accessToken string, expiresIn time.Duration) {
+	cookie := c.cookie(accessToken)
+	cookie.MaxAge = int(expiresIn.Seconds())
+	nethttp.SetCookie(w, cookie)
+}
+
+// Clear removes the cookie from the browser
+func (c *SessionCookie) Clear(w nethttp.ResponseWriter) {
+	cookie := c.cookie("")
+	cookie.MaxAge = -1
+	nethttp.SetCookie(w, cookie)
+}
+
+// cookie returns the cookie with the given value and the configured attributes
+func (c *SessionCookie) cookie(value string) *nethttp.Cookie {
+	return &nethttp.Cookie{
+		Name:     c.Name,
+		Value:    value,
+		Domain:   c.Domain,
+		Path:     c.Path,
+		SameSite: c.SameSite,
+		Secure:   c.Secure,
+		HttpOnly: true,
+	}
+}
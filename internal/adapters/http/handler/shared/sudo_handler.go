@@ -0,0 +1,21 @@
+package shared
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+)
+
+// SudoHandler grants elevated access to users who re-enter their password
+type SudoHandler struct {
+	SudoService services.SudoServiceInterface
+	Logger      *zap.Logger
+}
+
+// NewSudoHandler creates a new instance of SudoHandler
+func NewSudoHandler(sudoService services.SudoServiceInterface, logger *zap.Logger) *SudoHandler {
+	return &SudoHandler{
+		SudoService: sudoService,
+		Logger:      logger,
+	}
+}
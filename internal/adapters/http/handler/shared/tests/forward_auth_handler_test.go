@@ -0,0 +1,36 @@
+package tests
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+)
+
+func TestForwardAuthHandler_IsTrustedHost(t *testing.T) {
+	handler := shared.NewForwardAuthHandler(nil, []string{"app.example.com", "*.internal.example.com"}, "", "", zap.NewNop())
+
+	tests := []struct {
+		name string
+		host string
+		want bool
+	}{
+		{name: "exact host", host: "app.example.com", want: true},
+		{name: "exact host with port", host: "app.example.com:8443", want: true},
+		{name: "host is case insensitive", host: "App.Example.COM", want: true},
+		{name: "subdomain of wildcard", host: "grafana.internal.example.com", want: true},
+		{name: "wildcard does not match its apex", host: "internal.example.com", want: false},
+		{name: "wildcard does not match a lookalike", host: "evilinternal.example.com", want: false},
+		{name: "other host", host: "evil.com", want: false},
+		{name: "empty host", host: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := handler.IsTrustedHost(tt.host); got != tt.want {
+				t.Errorf("IsTrustedHost(%q) = %v, want %v", tt.host, got, tt.want)
+			}
+		})
+	}
+}
@@ -34,7 +34,7 @@ func TestNewAuthHandler(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			handler := shared.NewAuthHandler(tt.authService, tt.logger)
+			handler := shared.NewAuthHandler(tt.authService, nil, nil, false, nil, tt.logger)
 
 			if tt.wantNil {
 				if handler != nil {
@@ -61,7 +61,7 @@ func TestNewAuthHandler(t *testing.T) {
 
 func TestAuthHandler_Fields(t *testing.T) {
 	logger := zap.NewNop()
-	handler := shared.NewAuthHandler(nil, logger)
+	handler := shared.NewAuthHandler(nil, nil, nil, false, nil, logger)
 
 	if handler.Logger != logger {
 		t.Errorf("AuthHandler.Logger = %v, want %v", handler.Logger, logger)
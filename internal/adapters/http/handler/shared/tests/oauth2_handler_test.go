@@ -34,7 +34,7 @@ func TestNewOAuth2Handler(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			handler := shared.NewOAuth2Handler(tt.oauth2Service, tt.logger)
+			handler := shared.NewOAuth2Handler(tt.oauth2Service, nil, nil, "", false, tt.logger)
 
 			if tt.wantNil {
 				if handler != nil {
@@ -61,7 +61,7 @@ func TestNewOAuth2Handler(t *testing.T) {
 
 func TestOAuth2Handler_Fields(t *testing.T) {
 	logger := zap.NewNop()
-	handler := shared.NewOAuth2Handler(nil, logger)
+	handler := shared.NewOAuth2Handler(nil, nil, nil, "", false, logger)
 
 	if handler.Logger != logger {
 		t.Errorf("OAuth2Handler.Logger = %v, want %v", handler.Logger, logger)
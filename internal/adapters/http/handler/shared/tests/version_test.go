@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+)
+
+func TestExpectedVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		ifMatch string
+		want    int
+		wantErr bool
+		// wantRequired is set when the header names no version, which updates must
+		wantRequired bool
+	}{
+		{name: "no header", wantErr: true, wantRequired: true},
+		{name: "any version", ifMatch: "*", wantErr: true, wantRequired: true},
+		{name: "strong ETag", ifMatch: `"3"`, want: 3},
+		{name: "weak ETag", ifMatch: `W/"12"`, want: 12},
+		{name: "unquoted", ifMatch: "3", wantErr: true},
+		{name: "not a version", ifMatch: `"abc"`, wantErr: true},
+		{name: "zero", ifMatch: `"0"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/", nil)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			got, err := shared.ExpectedVersion(req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExpectedVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if errors.Is(err, domainerrors.ErrVersionRequired) != tt.wantRequired {
+				t.Errorf("ExpectedVersion() error = %v, want ErrVersionRequired %v", err, tt.wantRequired)
+			}
+			if got != tt.want {
+				t.Errorf("ExpectedVersion() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetVersionETag(t *testing.T) {
+	w := httptest.NewRecorder()
+	shared.SetVersionETag(w, 7)
+	if got := w.Header().Get("ETag"); got != `"7"` {
+		t.Errorf("ETag = %s, want \"7\"", got)
+	}
+
+	w = httptest.NewRecorder()
+	shared.SetVersionETag(w, 0)
+	if got := w.Header().Get("ETag"); got != "" {
+		t.Errorf("ETag = %s for an unknown version, want none", got)
+	}
+}
@@ -0,0 +1,21 @@
+package shared
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+)
+
+// TokenDebugHandler lets administrators find out why a token is rejected (ADMIN only)
+type TokenDebugHandler struct {
+	TokenDebugService services.TokenDebugServiceInterface
+	Logger            *zap.Logger
+}
+
+// NewTokenDebugHandler creates a new instance of TokenDebugHandler
+func NewTokenDebugHandler(tokenDebugService services.TokenDebugServiceInterface, logger *zap.Logger) *TokenDebugHandler {
+	return &TokenDebugHandler{
+		TokenDebugService: tokenDebugService,
+		Logger:            logger,
+	}
+}
@@ -0,0 +1,21 @@
+package shared
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+)
+
+// UserEmailsHandler manages the requests related to the secondary email addresses of the authenticated user
+type UserEmailsHandler struct {
+	UserEmailService services.UserEmailServiceInterface
+	Logger           *zap.Logger
+}
+
+// NewUserEmailsHandler creates a new instance of UserEmailsHandler
+func NewUserEmailsHandler(userEmailService services.UserEmailServiceInterface, logger *zap.Logger) *UserEmailsHandler {
+	return &UserEmailsHandler{
+		UserEmailService: userEmailService,
+		Logger:           logger,
+	}
+}
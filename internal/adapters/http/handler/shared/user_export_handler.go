@@ -0,0 +1,21 @@
+package shared
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+)
+
+// UserExportHandler handles the personal data exports users request for themselves
+type UserExportHandler struct {
+	UserExportService services.UserExportJobServiceInterface
+	Logger            *zap.Logger
+}
+
+// NewUserExportHandler creates a new instance of UserExportHandler
+func NewUserExportHandler(userExportService services.UserExportJobServiceInterface, logger *zap.Logger) *UserExportHandler {
+	return &UserExportHandler{
+		UserExportService: userExportService,
+		Logger:            logger,
+	}
+}
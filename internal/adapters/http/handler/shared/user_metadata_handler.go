@@ -0,0 +1,21 @@
+package shared
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+)
+
+// UserMetadataHandler manages the custom metadata of users, by administrators and by the users themselves
+type UserMetadataHandler struct {
+	UserMetadataService services.UserMetadataServiceInterface
+	Logger              *zap.Logger
+}
+
+// NewUserMetadataHandler creates a new instance of UserMetadataHandler
+func NewUserMetadataHandler(userMetadataService services.UserMetadataServiceInterface, logger *zap.Logger) *UserMetadataHandler {
+	return &UserMetadataHandler{
+		UserMetadataService: userMetadataService,
+		Logger:              logger,
+	}
+}
@@ -0,0 +1,21 @@
+package shared
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+)
+
+// UserProvisioningHandler manages the users created by administrators and their temporary passwords
+type UserProvisioningHandler struct {
+	UserProvisioningService services.UserProvisioningServiceInterface
+	Logger                  *zap.Logger
+}
+
+// NewUserProvisioningHandler creates a new instance of UserProvisioningHandler
+func NewUserProvisioningHandler(userProvisioningService services.UserProvisioningServiceInterface, logger *zap.Logger) *UserProvisioningHandler {
+	return &UserProvisioningHandler{
+		UserProvisioningService: userProvisioningService,
+		Logger:                  logger,
+	}
+}
@@ -0,0 +1,46 @@
+package shared
+
+import (
+	"errors"
+	nethttp "net/http"
+	"strconv"
+	"strings"
+
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+)
+
+// errInvalidIfMatch is returned by ExpectedVersion for an If-Match header that is not a version ETag
+var errInvalidIfMatch = errors.New("invalid If-Match header")
+
+// VersionETag returns the ETag of a resource at a version, e.g. "3"
+func VersionETag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// SetVersionETag sets the ETag header to the version of the resource of the response, so the next update can
+// be made conditional on it with If-Match
+func SetVersionETag(w nethttp.ResponseWriter, version int) {
+	if version > 0 {
+		w.Header().Set("ETag", VersionETag(version))
+	}
+}
+
+// ExpectedVersion returns the version required by the If-Match header of an update. Updates must name the
+// version they were made on, so an absent header or "*" returns ErrVersionRequired. Weak ETags are accepted,
+// the versions are compared as is.
+func ExpectedVersion(r *nethttp.Request) (int, error) {
+	ifMatch := strings.TrimSpace(r.Header.Get("If-Match"))
+	if ifMatch == "" || ifMatch == "*" {
+		return 0, domainerrors.ErrVersionRequired
+	}
+
+	tag := strings.TrimPrefix(ifMatch, "W/")
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, errInvalidIfMatch
+	}
+	version, err := strconv.Atoi(tag[1 : len(tag)-1])
+	if err != nil || version < 1 {
+		return 0, errInvalidIfMatch
+	}
+	return version, nil
+}
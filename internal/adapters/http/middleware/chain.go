@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"fmt"
+	nethttp "net/http"
+	"slices"
+)
+
+// Stage is the position of a middleware in the canonical order of a Chain
+type Stage int
+
+const (
+	// StageRecovery recovers from the panics of every later middleware and of the handler
+	StageRecovery Stage = iota
+
+	// StageRequestID stores the metadata of the request in its context: the request ID and the client information
+	StageRequestID
+
+	// StageLogging observes the request: logs and metrics
+	StageLogging
+
+	// StageCORS answers the preflight requests and sets the CORS headers
+	StageCORS
+
+	// StageRateLimit limits the requests before they are authenticated
+	StageRateLimit
+
+	// StageAuth authenticates the request. Middlewares that need the authenticated principal, like the
+	// rate-limit headers of the users, run in this stage after the authentication.
+	StageAuth
+
+	// StageScope authorizes the authenticated principal: roles, scopes and sudo mode
+	StageScope
+
+	stageCount
+)
+
+var stageNames = [stageCount]string{"recovery", "request-id", "logging", "cors", "rate-limit", "auth", "scope"}
+
+// String returns the name of the stage
+func (s Stage) String() string {
+	if s < 0 || s >= stageCount {
+		return fmt.Sprintf("stage(%d)", int(s))
+	}
+	return stageNames[s]
+}
+
+// Chain composes middlewares in the canonical order of their stages, whatever the order they are added
+// in: recovery → request-id → logging → CORS → rate-limit → auth → scope. Middlewares of the same stage
+// run in the order they were added. A new middleware thus can't run before the recovery or after the
+// authorization by accident.
+type Chain struct {
+	entries []chainEntry
+}
+
+type chainEntry struct {
+	stage      Stage
+	middleware func(nethttp.Handler) nethttp.Handler
+}
+
+// NewChain creates an empty chain
+func NewChain() *Chain {
+	return &Chain{}
+}
+
+// Use adds a middleware to a stage of the chain. It panics on an unknown stage, a programming error.
+func (c *Chain) Use(stage Stage, middleware func(nethttp.Handler) nethttp.Handler) *Chain {
+	if stage < 0 || stage >= stageCount {
+		panic(fmt.Sprintf("middleware chain: unknown %s", stage))
+	}
+	c.entries = append(c.entries, chainEntry{stage: stage, middleware: middleware})
+	return c
+}
+
+// Stages returns the stage of each middleware in the order they run
+func (c *Chain) Stages() []Stage {
+	entries := c.ordered()
+	stages := make([]Stage, len(entries))
+	for i, entry := range entries {
+		stages[i] = entry.stage
+	}
+	return stages
+}
+
+// Then wraps the handler with the middlewares of the chain, the first stage being the outermost
+func (c *Chain) Then(handler nethttp.Handler) nethttp.Handler {
+	entries := c.ordered()
+	for i := len(entries) - 1; i >= 0; i-- {
+		handler = entries[i].middleware(handler)
+	}
+	return handler
+}
+
+// Middleware returns the chain as a single middleware, e.g. for the Use of a router
+func (c *Chain) Middleware() func(nethttp.Handler) nethttp.Handler {
+	return c.Then
+}
+
+// ordered returns the entries sorted by stage, keeping the order they were added in within a stage
+func (c *Chain) ordered() []chainEntry {
+	entries := slices.Clone(c.entries)
+	slices.SortStableFunc(entries, func(a, b chainEntry) int {
+		return int(a.stage) - int(b.stage)
+	})
+	return entries
+}
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	nethttp "net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSPolicy describes the cross-origin requests allowed on a group of routes
+type CORSPolicy struct {
+	AllowedOrigins   []string // "*" allows any origin
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string      // response headers readable by the browser
+	AllowCredentials bool          // only honored for explicitly allowed origins
+	MaxAge           time.Duration // how long browsers may cache the preflight response
+}
+
+// CORSRule applies a CORS policy to the routes under a path prefix
+type CORSRule struct {
+	PathPrefix string
+	Policy     CORSPolicy
+}
+
+// CORSMiddleware handles CORS with the policy of the rule with the longest matching path prefix.
+// Requests matching no rule get no CORS headers. Preflight requests are answered without reaching
+// the handlers; the router must route OPTIONS requests for them to get here.
+func CORSMiddleware(rules ...CORSRule) func(nethttp.Handler) nethttp.Handler {
+	// Longest prefix first so route groups override their parents
+	rules = slices.Clone(rules)
+	slices.SortStableFunc(rules, func(a, b CORSRule) int {
+		return len(b.PathPrefix) - len(a.PathPrefix)
+	})
+
+	return func(next nethttp.Handler) nethttp.Handler {
+		return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+			for _, rule := range rules {
+				if strings.HasPrefix(r.URL.Path, rule.PathPrefix) {
+					rule.Policy.apply(w, r)
+					break
+				}
+			}
+
+			if r.Method == nethttp.MethodOptions {
+				w.WriteHeader(nethttp.StatusOK)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// apply sets the CORS headers of the request when its origin is allowed
+func (p CORSPolicy) apply(w nethttp.ResponseWriter, r *nethttp.Request) {
+	header := w.Header()
+	anyOrigin := slices.Contains(p.AllowedOrigins, "*")
+	if !anyOrigin {
+		// The response depends on the origin, caches must not share it across origins
+		header.Add("Vary", "Origin")
+	}
+
+	origin := r.Header.Get("Origin")
+	switch {
+	case anyOrigin:
+		// Browsers never send credentials to a wildcard origin
+		header.Set("Access-Control-Allow-Origin", "*")
+	case origin != "" && slices.Contains(p.AllowedOrigins, origin):
+		header.Set("Access-Control-Allow-Origin", origin)
+		if p.AllowCredentials {
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
+	default:
+		return
+	}
+
+	if r.Method == nethttp.MethodOptions {
+		header.Set("Access-Control-Allow-Methods", strings.Join(p.AllowedMethods, ", "))
+		header.Set("Access-Control-Allow-Headers", strings.Join(p.AllowedHeaders, ", "))
+		if p.MaxAge > 0 {
+			header.Set("Access-Control-Max-Age", strconv.Itoa(int(p.MaxAge.Seconds())))
+		}
+		return
+	}
+
+	if len(p.ExposedHeaders) > 0 {
+		header.Set("Access-Control-Expose-Headers", strings.Join(p.ExposedHeaders, ", "))
+	}
+}
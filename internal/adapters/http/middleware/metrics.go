@@ -20,21 +20,37 @@ func (r *statusRecorder) WriteHeader(code int) {
 	r.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap exposes the underlying writer to http.ResponseController, e.g. to flush streamed responses
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
 // MetricsMiddleware records basic HTTP metrics for each request.
 func MetricsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
 		start := time.Now()
-		next.ServeHTTP(recorder, r)
-		duration := time.Since(start)
-
-		endpoint := r.URL.Path
-		if route := mux.CurrentRoute(r); route != nil {
-			if template, err := route.GetPathTemplate(); err == nil {
-				endpoint = template
+		completed := false
+		defer func() {
+			// A panicking handler is answered with a 500 by the recovery middleware, which runs outside this one
+			status := recorder.status
+			if !completed {
+				status = http.StatusInternalServerError
 			}
-		}
+			metrics.ObserveHTTPRequest(r.Method, routeEndpoint(r), strconv.Itoa(status), time.Since(start))
+		}()
 
-		metrics.ObserveHTTPRequest(r.Method, endpoint, strconv.Itoa(recorder.status), duration)
+		next.ServeHTTP(recorder, r)
+		completed = true
 	})
 }
+
+// routeEndpoint returns the path template of the matched route, so metrics don't get a label per user ID
+func routeEndpoint(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			return template
+		}
+	}
+	return r.URL.Path
+}
@@ -2,7 +2,13 @@ package middleware
 
 import (
 	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
 	nethttp "net/http"
+	"net/url"
 	"strings"
 
 	"go.uber.org/zap"
@@ -10,6 +16,7 @@ import (
 	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
 	"github.com/kristianrpo/auth-microservice/internal/application/services"
 	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
 )
 
 type contextKey string
@@ -48,7 +55,7 @@ func (m *AuthMiddleware) Authenticate(next nethttp.Handler) nethttp.Handler {
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
 			m.logger.Debug("invalid authorization header format",
-				zap.String("header", authHeader),
+				logging.String("header", authHeader),
 				zap.Int("parts_count", len(parts)),
 				zap.Strings("parts", parts))
 			httperrors.RespondWithError(w, httperrors.ErrInvalidAuthHeader)
@@ -71,56 +78,89 @@ func (m *AuthMiddleware) Authenticate(next nethttp.Handler) nethttp.Handler {
 	})
 }
 
-// CORSMiddleware maneja CORS
-func CORSMiddleware(next nethttp.Handler) nethttp.Handler {
-	return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-		w.Header().Set("Access-Control-Max-Age", "3600")
-
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(nethttp.StatusOK)
-			return
-		}
-
-		next.ServeHTTP(w, r)
-	})
-}
-
 // LoggingMiddleware logs HTTP requests
 func LoggingMiddleware(logger *zap.Logger) func(nethttp.Handler) nethttp.Handler {
 	return func(next nethttp.Handler) nethttp.Handler {
 		return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
 			logger.Info("http request",
 				zap.String("method", r.Method),
-				zap.String("path", r.URL.Path),
+				logging.String("path", r.URL.Path),
 				zap.String("remote_addr", r.RemoteAddr),
-				zap.String("user_agent", r.UserAgent()),
+				logging.String("user_agent", r.UserAgent()),
+				zap.String("request_id", GetRequestIDFromContext(r.Context())),
 			)
 			next.ServeHTTP(w, r)
 		})
 	}
 }
 
-// RecoveryMiddleware recovers from panics
-func RecoveryMiddleware(logger *zap.Logger) func(nethttp.Handler) nethttp.Handler {
+// ClientInfoMiddleware stores the IP address and user agent of the client in the request context.
+// Forwarding headers are only honored when trustProxyHeaders is set, i.e. when the service is
+// reachable exclusively through a proxy that overwrites them.
+func ClientInfoMiddleware(trustProxyHeaders bool) func(nethttp.Handler) nethttp.Handler {
 	return func(next nethttp.Handler) nethttp.Handler {
 		return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
-			defer func() {
-				if err := recover(); err != nil {
-					logger.Error("panic recovered",
-						zap.Any("error", err),
-						zap.String("path", r.URL.Path),
-					)
-					httperrors.RespondWithError(w, httperrors.ErrInternalServer)
-				}
-			}()
-			next.ServeHTTP(w, r)
+			ctx := domain.ContextWithClientInfo(r.Context(), domain.ClientInfo{
+				IP:        ClientIP(r, trustProxyHeaders),
+				UserAgent: r.UserAgent(),
+			})
+			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
+// ClientIP returns the IP address of the client that sent the request.
+// With trustProxyHeaders, the left-most valid X-Forwarded-For entry or X-Real-IP is preferred.
+func ClientIP(r *nethttp.Request, trustProxyHeaders bool) string {
+	if trustProxyHeaders {
+		for _, candidate := range strings.Split(r.Header.Get("X-Forwarded-For"), ",") {
+			if ip := net.ParseIP(strings.TrimSpace(candidate)); ip != nil {
+				return ip.String()
+			}
+		}
+		if ip := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); ip != nil {
+			return ip.String()
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.String()
+	}
+	return ""
+}
+
+// ClientCertificate returns the TLS client certificate of the request, nil when the client presented none.
+// When the TLS connection is terminated by a proxy, header is the header where the proxy forwards the
+// certificate it verified, URL-encoded PEM like nginx $ssl_client_escaped_cert; empty when the service
+// terminates TLS itself. Like the forwarding headers, it is only honored with trustProxyHeaders, since any
+// client could set it otherwise. An error is returned when the forwarded certificate can't be parsed.
+func ClientCertificate(r *nethttp.Request, header string, trustProxyHeaders bool) (*x509.Certificate, error) {
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		return r.TLS.PeerCertificates[0], nil
+	}
+	if header == "" || !trustProxyHeaders {
+		return nil, nil
+	}
+
+	forwarded := r.Header.Get(header)
+	if forwarded == "" {
+		return nil, nil
+	}
+	decoded, err := url.QueryUnescape(forwarded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid forwarded client certificate: %w", err)
+	}
+	block, _ := pem.Decode([]byte(decoded))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("forwarded client certificate is not a PEM certificate")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
 // GetUserFromContext retrieves the user claims from the context
 func GetUserFromContext(ctx context.Context) (*domain.TokenClaims, bool) {
 	claims, ok := ctx.Value(UserContextKey).(*domain.TokenClaims)
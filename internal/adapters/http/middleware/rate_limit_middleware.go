@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	nethttp "net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
+)
+
+const (
+	// HeaderRateLimitLimit is the number of requests allowed per window
+	HeaderRateLimitLimit = "X-RateLimit-Limit"
+	// HeaderRateLimitRemaining is the number of requests left in the current window
+	HeaderRateLimitRemaining = "X-RateLimit-Remaining"
+	// HeaderRateLimitReset is the number of seconds until the current window resets
+	HeaderRateLimitReset = "X-RateLimit-Reset"
+)
+
+// RateLimitMiddleware reports the rate-limit state of authenticated principals
+type RateLimitMiddleware struct {
+	limiter ports.RateLimiter
+	logger  *zap.Logger
+}
+
+// NewRateLimitMiddleware creates a new instance of the rate-limit middleware
+func NewRateLimitMiddleware(limiter ports.RateLimiter, logger *zap.Logger) *RateLimitMiddleware {
+	return &RateLimitMiddleware{
+		limiter: limiter,
+		logger:  logger,
+	}
+}
+
+// Headers counts the request against the authenticated user and sets the X-RateLimit-* headers.
+// The limit is soft: requests over it are still served so clients can back off before being rejected.
+// Must run after Authenticate, in the auth stage of a chain.
+func (m *RateLimitMiddleware) Headers(next nethttp.Handler) nethttp.Handler {
+	return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		claims, ok := GetUserFromContext(r.Context())
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		status, err := m.limiter.Hit(r.Context(), domain.UserRateLimitKey(claims.IDCitizen))
+		if err != nil {
+			// Fail open, the headers are informative only
+			m.logger.Warn("failed to record rate limit hit", zap.Error(err), zap.Int("id_citizen", claims.IDCitizen))
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if status.Exceeded() {
+			metrics.IncRateLimitExceeded()
+			m.logger.Debug("soft rate limit exceeded", zap.Int("id_citizen", claims.IDCitizen), zap.Int("used", status.Used))
+		}
+
+		w.Header().Set(HeaderRateLimitLimit, strconv.Itoa(status.Limit))
+		w.Header().Set(HeaderRateLimitRemaining, strconv.Itoa(status.Remaining()))
+		w.Header().Set(HeaderRateLimitReset, strconv.FormatInt(status.ResetSeconds(time.Now()), 10))
+
+		next.ServeHTTP(w, r)
+	})
+}
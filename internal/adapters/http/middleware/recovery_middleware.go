@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	nethttp "net/http"
+	"runtime/debug"
+	"time"
+
+	"go.uber.org/zap"
+
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
+)
+
+// RecoveryMiddleware recovers from handler panics. The panic is logged with its stack and counted, the
+// caller gets a 500 error with the request ID to report, and the notifier, when set, is alerted without
+// delaying the response.
+func RecoveryMiddleware(notifier ports.AlertNotifier, logger *zap.Logger) func(nethttp.Handler) nethttp.Handler {
+	return func(next nethttp.Handler) nethttp.Handler {
+		return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+			defer func() {
+				if err := recover(); err != nil {
+					// Aborted handlers (e.g. a failed streaming response) must close the connection
+					if err == nethttp.ErrAbortHandler {
+						panic(err)
+					}
+
+					// Outside of the request ID middleware, the ID is only in the response headers
+					requestID := GetRequestIDFromContext(r.Context())
+					if requestID == "" {
+						requestID = w.Header().Get(HeaderRequestID)
+					}
+
+					alert := ports.PanicAlert{
+						RequestID: requestID,
+						Method:    r.Method,
+						Endpoint:  routeEndpoint(r),
+						Panic:     fmt.Sprint(err),
+						Stack:     string(debug.Stack()),
+						Time:      time.Now(),
+					}
+
+					logger.Error("panic recovered",
+						zap.String("panic", alert.Panic),
+						zap.String("request_id", alert.RequestID),
+						zap.String("method", alert.Method),
+						logging.String("path", r.URL.Path),
+						zap.String("stack", alert.Stack),
+					)
+					metrics.IncHTTPPanics(alert.Method, alert.Endpoint)
+
+					if notifier != nil {
+						ctx := context.WithoutCancel(r.Context())
+						go func() {
+							if err := notifier.NotifyPanic(ctx, alert); err != nil {
+								logger.Warn("failed to send panic alert", zap.Error(err), zap.String("request_id", alert.RequestID))
+							}
+						}()
+					}
+
+					httperrors.RespondWithRequestID(w, httperrors.ErrInternalServer, alert.RequestID)
+				}
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	nethttp "net/http"
+)
+
+// HeaderRequestID is the header carrying the ID of the request, echoed in the response
+const HeaderRequestID = "X-Request-ID"
+
+// maxRequestIDLength bounds the request IDs accepted from the caller, they end up in logs and responses
+const maxRequestIDLength = 128
+
+// RequestIDContextKey is the key to get the request ID from context
+const RequestIDContextKey contextKey = "request_id"
+
+// RequestIDMiddleware stores the ID of the request in the context and echoes it in the response, so errors
+// reported by clients can be matched with the logs. The X-Request-ID set by a proxy is kept when valid,
+// otherwise a random one is generated.
+func RequestIDMiddleware(next nethttp.Handler) nethttp.Handler {
+	return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		requestID := r.Header.Get(HeaderRequestID)
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+		}
+
+		w.Header().Set(HeaderRequestID, requestID)
+		ctx := context.WithValue(r.Context(), RequestIDContextKey, requestID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// GetRequestIDFromContext retrieves the request ID from the context
+func GetRequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(RequestIDContextKey).(string)
+	return requestID
+}
+
+// validRequestID checks the request ID is non-empty printable ASCII without spaces
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(requestID); i++ {
+		if requestID[i] <= ' ' || requestID[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
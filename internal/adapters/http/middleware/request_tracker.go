@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// RequestTracker counts the requests in progress, so shutdown can report how many were drained
+type RequestTracker struct {
+	active atomic.Int64
+}
+
+// NewRequestTracker creates a new instance of RequestTracker
+func NewRequestTracker() *RequestTracker {
+	return &RequestTracker{}
+}
+
+// Track counts the requests of the handler while they are in progress
+func (t *RequestTracker) Track(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.active.Add(1)
+		defer t.active.Add(-1)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Active returns the number of requests in progress
+func (t *RequestTracker) Active() int {
+	return int(t.active.Load())
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"bytes"
+	nethttp "net/http"
+
+	"go.uber.org/zap"
+
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+)
+
+// HeaderJWSSignature is the detached JWS of the response body
+const HeaderJWSSignature = "X-JWS-Signature"
+
+// ResponseSigner signs response bodies with a detached JWS
+type ResponseSigner interface {
+	SignDetached(payload []byte) (string, error)
+}
+
+// ResponseSigningMiddleware signs the responses of the token endpoints, so high-assurance consumers can
+// detect intermediaries tampering with the tokens or their metadata
+type ResponseSigningMiddleware struct {
+	signer ResponseSigner
+	logger *zap.Logger
+}
+
+// NewResponseSigningMiddleware creates a new instance of the response signing middleware
+func NewResponseSigningMiddleware(signer ResponseSigner, logger *zap.Logger) *ResponseSigningMiddleware {
+	return &ResponseSigningMiddleware{
+		signer: signer,
+		logger: logger,
+	}
+}
+
+// bufferedResponse holds the response until the body is complete and can be signed
+type bufferedResponse struct {
+	nethttp.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *bufferedResponse) WriteHeader(code int) {
+	if r.status == 0 {
+		r.status = code
+	}
+}
+
+func (r *bufferedResponse) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = nethttp.StatusOK
+	}
+	return r.body.Write(b)
+}
+
+// Sign buffers the response and sends it with its detached JWS in the X-JWS-Signature header.
+// Error responses are signed too, so a rejection can't be forged either.
+func (m *ResponseSigningMiddleware) Sign(next nethttp.Handler) nethttp.Handler {
+	return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		buffered := &bufferedResponse{ResponseWriter: w}
+		next.ServeHTTP(buffered, r)
+		if buffered.status == 0 {
+			buffered.status = nethttp.StatusOK
+		}
+
+		signature, err := m.signer.SignDetached(buffered.body.Bytes())
+		if err != nil {
+			// Consumers requiring signed responses would reject the response anyway
+			m.logger.Error("failed to sign response", zap.Error(err), logging.String("path", r.URL.Path))
+			w.Header().Del("Content-Length")
+			httperrors.RespondWithError(w, httperrors.ErrInternalServer)
+			return
+		}
+
+		w.Header().Set(HeaderJWSSignature, signature)
+		w.WriteHeader(buffered.status)
+		_, _ = w.Write(buffered.body.Bytes())
+	})
+}
@@ -21,7 +21,9 @@ func NewRoleMiddleware(logger *zap.Logger) *RoleMiddleware {
 	}
 }
 
-// RequireRole creates a middleware that checks if the user has one of the required roles
+// RequireRole creates a middleware that checks if the user has one of the required roles.
+// Tokens issued to an OAuth client on behalf of the user only grant the scopes the user approved, never
+// the roles of the user.
 func (m *RoleMiddleware) RequireRole(allowedRoles ...domain.Role) func(nethttp.Handler) nethttp.Handler {
 	return func(next nethttp.Handler) nethttp.Handler {
 		return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
@@ -33,6 +35,14 @@ func (m *RoleMiddleware) RequireRole(allowedRoles ...domain.Role) func(nethttp.H
 				return
 			}
 
+			if claims.IsDelegated() {
+				m.logger.Warn("delegated token used on a role protected route",
+					zap.Int("id_citizen", claims.IDCitizen),
+					zap.String("client_id", claims.ClientID))
+				httperrors.RespondWithError(w, httperrors.ErrForbidden)
+				return
+			}
+
 			// Check if user has one of the allowed roles
 			hasRole := false
 			for _, allowedRole := range allowedRoles {
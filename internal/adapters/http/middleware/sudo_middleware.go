@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	nethttp "net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	httperrors "github.com/kristianrpo/auth-microservice/internal/adapters/http/errors"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+)
+
+// SudoMiddleware restricts destructive operations to tokens granting elevated access ("sudo mode")
+type SudoMiddleware struct {
+	logger *zap.Logger
+}
+
+// NewSudoMiddleware creates a new instance of SudoMiddleware
+func NewSudoMiddleware(logger *zap.Logger) *SudoMiddleware {
+	return &SudoMiddleware{
+		logger: logger,
+	}
+}
+
+// RequireSudo rejects the requests whose token does not grant elevated access or whose elevated access expired
+func (m *SudoMiddleware) RequireSudo(next nethttp.Handler) nethttp.Handler {
+	return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		// Get user claims from context (set by AuthMiddleware)
+		claims, ok := GetUserFromContext(r.Context())
+		if !ok {
+			m.logger.Debug("no user claims in context")
+			httperrors.RespondWithError(w, httperrors.ErrUnauthorized)
+			return
+		}
+
+		if !claims.IsElevated(time.Now()) {
+			m.logger.Info("elevated access required",
+				zap.Int("id_citizen", claims.IDCitizen),
+				logging.String("path", r.URL.Path))
+			httperrors.RespondWithError(w, httperrors.ErrSudoRequired)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+)
+
+// recordingMiddleware appends its name to calls when it runs
+func recordingMiddleware(name string, calls *[]string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*calls = append(*calls, name)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestChain_RunsStagesInCanonicalOrder(t *testing.T) {
+	var calls []string
+	chain := middleware.NewChain().
+		Use(middleware.StageScope, recordingMiddleware("scope", &calls)).
+		Use(middleware.StageAuth, recordingMiddleware("auth", &calls)).
+		Use(middleware.StageCORS, recordingMiddleware("cors", &calls)).
+		Use(middleware.StageRateLimit, recordingMiddleware("rate-limit", &calls)).
+		Use(middleware.StageLogging, recordingMiddleware("logging", &calls)).
+		Use(middleware.StageRequestID, recordingMiddleware("request-id", &calls)).
+		Use(middleware.StageRecovery, recordingMiddleware("recovery", &calls))
+
+	handler := chain.Then(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "handler")
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	want := []string{"recovery", "request-id", "logging", "cors", "rate-limit", "auth", "scope", "handler"}
+	if !slices.Equal(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+
+	wantStages := []middleware.Stage{
+		middleware.StageRecovery, middleware.StageRequestID, middleware.StageLogging, middleware.StageCORS,
+		middleware.StageRateLimit, middleware.StageAuth, middleware.StageScope,
+	}
+	if stages := chain.Stages(); !slices.Equal(stages, wantStages) {
+		t.Errorf("stages = %v, want %v", stages, wantStages)
+	}
+}
+
+func TestChain_KeepsInsertionOrderWithinAStage(t *testing.T) {
+	var calls []string
+	handler := middleware.NewChain().
+		Use(middleware.StageAuth, recordingMiddleware("authenticate", &calls)).
+		Use(middleware.StageRecovery, recordingMiddleware("recovery", &calls)).
+		Use(middleware.StageAuth, recordingMiddleware("rate-limit-headers", &calls)).
+		Then(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	want := []string{"recovery", "authenticate", "rate-limit-headers"}
+	if !slices.Equal(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestChain_RecoveryCatchesPanicsOfLaterStages(t *testing.T) {
+	panicking := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		})
+	}
+
+	// The panicking middleware is added before the recovery, the chain still runs it inside
+	handler := middleware.NewChain().
+		Use(middleware.StageAuth, panicking).
+		Use(middleware.StageRequestID, middleware.RequestIDMiddleware).
+		Use(middleware.StageRecovery, middleware.RecoveryMiddleware(nil, zap.NewNop())).
+		Then(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(middleware.HeaderRequestID, "req-7")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+	if got := w.Header().Get(middleware.HeaderRequestID); got != "req-7" {
+		t.Errorf("request ID header = %q, want req-7", got)
+	}
+}
+
+func TestChain_UseUnknownStagePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for an unknown stage")
+		}
+	}()
+	middleware.NewChain().Use(middleware.Stage(42), recordingMiddleware("unknown", new([]string)))
+}
+
+func TestChain_EmptyChainReturnsHandler(t *testing.T) {
+	called := false
+	handler := middleware.NewChain().Then(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if !called {
+		t.Error("handler was not called")
+	}
+}
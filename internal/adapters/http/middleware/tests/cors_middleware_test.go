@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+)
+
+func TestCORSMiddleware(t *testing.T) {
+	publicPolicy := middleware.CORSPolicy{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{http.MethodGet, http.MethodPost},
+		AllowedHeaders: []string{"Content-Type", "Authorization"},
+		ExposedHeaders: []string{"X-Request-ID"},
+		MaxAge:         10 * time.Minute,
+	}
+	adminPolicy := middleware.CORSPolicy{
+		AllowedOrigins:   []string{"https://admin.example.com"},
+		AllowedMethods:   []string{http.MethodGet, http.MethodPut},
+		AllowedHeaders:   []string{"Content-Type", "Authorization"},
+		AllowCredentials: true,
+		MaxAge:           time.Minute,
+	}
+	rules := []middleware.CORSRule{
+		{PathPrefix: "/api/auth/admin", Policy: adminPolicy},
+		{PathPrefix: "/api/auth", Policy: publicPolicy},
+	}
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		origin      string
+		wantStatus  int
+		wantHeaders map[string]string
+	}{
+		{
+			name:       "preflight on public route is cached",
+			method:     http.MethodOptions,
+			path:       "/api/auth/login",
+			origin:     "https://app.example.com",
+			wantStatus: http.StatusOK,
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin":  "*",
+				"Access-Control-Allow-Methods": "GET, POST",
+				"Access-Control-Max-Age":       "600",
+			},
+		},
+		{
+			name:       "public request exposes custom headers",
+			method:     http.MethodGet,
+			path:       "/api/auth/me",
+			origin:     "https://app.example.com",
+			wantStatus: http.StatusTeapot,
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin":   "*",
+				"Access-Control-Expose-Headers": "X-Request-ID",
+				"Access-Control-Max-Age":        "",
+			},
+		},
+		{
+			name:       "admin route allows its own origin with credentials",
+			method:     http.MethodOptions,
+			path:       "/api/auth/admin/scopes",
+			origin:     "https://admin.example.com",
+			wantStatus: http.StatusOK,
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "https://admin.example.com",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Allow-Methods":     "GET, PUT",
+				"Access-Control-Max-Age":           "60",
+				"Vary":                             "Origin",
+			},
+		},
+		{
+			name:       "admin route rejects other origins",
+			method:     http.MethodGet,
+			path:       "/api/auth/admin/scopes",
+			origin:     "https://app.example.com",
+			wantStatus: http.StatusTeapot,
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "",
+				"Access-Control-Allow-Credentials": "",
+			},
+		},
+		{
+			name:       "route without rule gets no CORS headers",
+			method:     http.MethodGet,
+			path:       "/",
+			origin:     "https://app.example.com",
+			wantStatus: http.StatusTeapot,
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin": "",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := middleware.CORSMiddleware(rules...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusTeapot)
+			}))
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Origin", tt.origin)
+			if tt.method == http.MethodOptions {
+				req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			for header, want := range tt.wantHeaders {
+				if got := w.Header().Get(header); got != want {
+					t.Errorf("%s = %q, want %q", header, got, want)
+				}
+			}
+		})
+	}
+}
@@ -21,34 +21,6 @@ func (m *mockAuthSvc) ValidateAccessToken(ctx context.Context, token string) (*d
 	return m.validate(ctx, token)
 }
 
-func TestCORSMiddleware_Options(t *testing.T) {
-	handler := middleware.CORSMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusAccepted)
-	}))
-
-	req := httptest.NewRequest(http.MethodOptions, "/", nil)
-	w := httptest.NewRecorder()
-
-	handler.ServeHTTP(w, req)
-	if w.Code != http.StatusOK {
-		t.Fatalf("expected 200 for OPTIONS, got %d", w.Code)
-	}
-}
-
-func TestCORSMiddleware_PassThrough(t *testing.T) {
-	handler := middleware.CORSMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusTeapot)
-	}))
-
-	req := httptest.NewRequest(http.MethodGet, "/", nil)
-	w := httptest.NewRecorder()
-
-	handler.ServeHTTP(w, req)
-	if w.Code != http.StatusTeapot {
-		t.Fatalf("expected 418, got %d", w.Code)
-	}
-}
-
 func TestLoggingMiddleware_InvokesNext(t *testing.T) {
 	logger := zap.NewNop()
 	lm := middleware.LoggingMiddleware(logger)
@@ -68,7 +40,7 @@ func TestLoggingMiddleware_InvokesNext(t *testing.T) {
 
 func TestRecoveryMiddleware_Recovers(t *testing.T) {
 	logger := zap.NewNop()
-	rm := middleware.RecoveryMiddleware(logger)
+	rm := middleware.RecoveryMiddleware(nil, logger)
 
 	handler := rm(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		panic("boom")
@@ -112,3 +84,50 @@ func TestGetUserFromContext(t *testing.T) {
 		t.Fatalf("unexpected id_citizen %d", got.IDCitizen)
 	}
 }
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		trusted    bool
+		want       string
+	}{
+		{name: "remote address", remoteAddr: "203.0.113.7:4321", want: "203.0.113.7"},
+		{name: "IPv6 remote address", remoteAddr: "[2001:db8::1]:4321", want: "2001:db8::1"},
+		{name: "untrusted forwarded header", remoteAddr: "10.0.0.1:4321", headers: map[string]string{"X-Forwarded-For": "203.0.113.7"}, want: "10.0.0.1"},
+		{name: "trusted forwarded header", remoteAddr: "10.0.0.1:4321", headers: map[string]string{"X-Forwarded-For": "203.0.113.7, 10.0.0.2"}, trusted: true, want: "203.0.113.7"},
+		{name: "trusted real IP header", remoteAddr: "10.0.0.1:4321", headers: map[string]string{"X-Real-IP": "203.0.113.8"}, trusted: true, want: "203.0.113.8"},
+		{name: "invalid forwarded header", remoteAddr: "10.0.0.1:4321", headers: map[string]string{"X-Forwarded-For": "unknown"}, trusted: true, want: "10.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			if got := middleware.ClientIP(req, tt.trusted); got != tt.want {
+				t.Errorf("ClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientInfoMiddleware(t *testing.T) {
+	var info domain.ClientInfo
+	var ok bool
+	handler := middleware.ClientInfoMiddleware(false)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info, ok = domain.ClientInfoFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "203.0.113.7:4321"
+	req.Header.Set("User-Agent", "test-agent")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if !ok || info.IP != "203.0.113.7" || info.UserAgent != "test-agent" {
+		t.Errorf("ClientInfoFromContext() = %+v, %v", info, ok)
+	}
+}
@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+type mockRateLimiter struct {
+	hit func(ctx context.Context, key string) (*domain.RateLimitStatus, error)
+}
+
+func (m *mockRateLimiter) Hit(ctx context.Context, key string) (*domain.RateLimitStatus, error) {
+	return m.hit(ctx, key)
+}
+
+func (m *mockRateLimiter) Peek(ctx context.Context, key string) (*domain.RateLimitStatus, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestRateLimitMiddleware_Headers(t *testing.T) {
+	tests := []struct {
+		name          string
+		withClaims    bool
+		status        *domain.RateLimitStatus
+		hitErr        error
+		wantLimit     string
+		wantRemaining string
+		wantReset     string
+	}{
+		{
+			name:          "sets headers for authenticated user",
+			withClaims:    true,
+			status:        &domain.RateLimitStatus{Limit: 100, Used: 40, ResetAt: time.Now().Add(30 * time.Second)},
+			wantLimit:     "100",
+			wantRemaining: "60",
+			wantReset:     "30",
+		},
+		{
+			name:          "over the soft limit is still served",
+			withClaims:    true,
+			status:        &domain.RateLimitStatus{Limit: 100, Used: 150, ResetAt: time.Now().Add(10 * time.Second)},
+			wantLimit:     "100",
+			wantRemaining: "0",
+			wantReset:     "10",
+		},
+		{name: "limiter error fails open", withClaims: true, hitErr: errors.New("redis down")},
+		{name: "no claims skips limiter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := &mockRateLimiter{hit: func(ctx context.Context, key string) (*domain.RateLimitStatus, error) {
+				if !tt.withClaims {
+					t.Errorf("Hit() called without claims")
+				}
+				if key != "user:12345" {
+					t.Errorf("Hit() key = %v, want user:12345", key)
+				}
+				return tt.status, tt.hitErr
+			}}
+			m := middleware.NewRateLimitMiddleware(limiter, zap.NewNop())
+
+			called := false
+			handler := m.Headers(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			if tt.withClaims {
+				claims := &domain.TokenClaims{IDCitizen: 12345, Email: "test@example.com", Role: domain.RoleUser}
+				req = req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, claims))
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			if !called || w.Code != http.StatusOK {
+				t.Fatalf("expected next handler to be served, got %d", w.Code)
+			}
+			if got := w.Header().Get(middleware.HeaderRateLimitLimit); got != tt.wantLimit {
+				t.Errorf("%s = %q, want %q", middleware.HeaderRateLimitLimit, got, tt.wantLimit)
+			}
+			if got := w.Header().Get(middleware.HeaderRateLimitRemaining); got != tt.wantRemaining {
+				t.Errorf("%s = %q, want %q", middleware.HeaderRateLimitRemaining, got, tt.wantRemaining)
+			}
+			if got := w.Header().Get(middleware.HeaderRateLimitReset); got != tt.wantReset {
+				t.Errorf("%s = %q, want %q", middleware.HeaderRateLimitReset, got, tt.wantReset)
+			}
+		})
+	}
+}
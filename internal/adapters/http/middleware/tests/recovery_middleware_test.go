@@ -0,0 +1,73 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+)
+
+type notifierFunc func(ctx context.Context, alert ports.PanicAlert) error
+
+func (f notifierFunc) NotifyPanic(ctx context.Context, alert ports.PanicAlert) error {
+	return f(ctx, alert)
+}
+
+func TestRecoveryMiddleware_RespondsWithRequestIDAndNotifies(t *testing.T) {
+	alerts := make(chan ports.PanicAlert, 1)
+	notifier := notifierFunc(func(ctx context.Context, alert ports.PanicAlert) error {
+		alerts <- alert
+		return nil
+	})
+
+	handler := middleware.RequestIDMiddleware(middleware.RecoveryMiddleware(notifier, zap.NewNop())(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		})))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/login", nil)
+	req.Header.Set(middleware.HeaderRequestID, "req-42")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+	var resp response.ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.RequestID != "req-42" || resp.Code == "" {
+		t.Errorf("response = %+v, want the request ID and an error code", resp)
+	}
+
+	select {
+	case alert := <-alerts:
+		if alert.RequestID != "req-42" || alert.Panic != "boom" || alert.Method != http.MethodPost || alert.Stack == "" {
+			t.Errorf("alert = %+v, want the request, the panic and its stack", alert)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("notifier was not called")
+	}
+}
+
+func TestRecoveryMiddleware_RepanicsAbortedHandlers(t *testing.T) {
+	handler := middleware.RecoveryMiddleware(nil, zap.NewNop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	defer func() {
+		if recovered := recover(); recovered != http.ErrAbortHandler {
+			t.Errorf("recovered %v, want %v", recovered, http.ErrAbortHandler)
+		}
+	}()
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
@@ -0,0 +1,47 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		wantHeader bool // the incoming ID is kept
+	}{
+		{name: "generated when missing"},
+		{name: "kept from the proxy", header: "abc-123", wantHeader: true},
+		{name: "replaced when invalid", header: "bad id\n"},
+		{name: "replaced when too long", header: strings.Repeat("a", 200)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fromContext string
+			handler := middleware.RequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fromContext = middleware.GetRequestIDFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(middleware.HeaderRequestID, tt.header)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			got := w.Header().Get(middleware.HeaderRequestID)
+			if got == "" || got != fromContext {
+				t.Fatalf("response request ID = %q, context request ID = %q, want the same non-empty ID", got, fromContext)
+			}
+			if (got == tt.header) != tt.wantHeader {
+				t.Errorf("request ID = %q, incoming %q kept = %v, want %v", got, tt.header, got == tt.header, tt.wantHeader)
+			}
+		})
+	}
+}
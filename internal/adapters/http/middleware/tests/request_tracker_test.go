@@ -0,0 +1,27 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+)
+
+func TestRequestTracker_CountsActiveRequests(t *testing.T) {
+	tracker := middleware.NewRequestTracker()
+	activeDuringRequest := -1
+	handler := tracker.Track(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		activeDuringRequest = tracker.Active()
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if activeDuringRequest != 1 {
+		t.Errorf("Active() during request = %d, want 1", activeDuringRequest)
+	}
+	if got := tracker.Active(); got != 0 {
+		t.Errorf("Active() after request = %d, want 0", got)
+	}
+}
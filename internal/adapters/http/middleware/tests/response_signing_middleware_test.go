@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+)
+
+type mockResponseSigner struct {
+	signed []byte
+	err    error
+}
+
+func (m *mockResponseSigner) SignDetached(payload []byte) (string, error) {
+	m.signed = payload
+	if m.err != nil {
+		return "", m.err
+	}
+	return "header..signature", nil
+}
+
+func TestResponseSigningMiddleware_Sign(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		body          string
+		signErr       error
+		wantStatus    int
+		wantSignature string
+	}{
+		{name: "signs token response", status: http.StatusOK, body: `{"access_token":"abc"}`, wantStatus: http.StatusOK, wantSignature: "header..signature"},
+		{name: "signs error response", status: http.StatusUnauthorized, body: `{"code":"INVALID_CREDENTIALS"}`, wantStatus: http.StatusUnauthorized, wantSignature: "header..signature"},
+		{name: "signing failure", status: http.StatusOK, body: `{"access_token":"abc"}`, signErr: errors.New("no key"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer := &mockResponseSigner{err: tt.signErr}
+			handler := middleware.NewResponseSigningMiddleware(signer, zap.NewNop()).Sign(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/auth/login", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get(middleware.HeaderJWSSignature); got != tt.wantSignature {
+				t.Errorf("%s = %q, want %q", middleware.HeaderJWSSignature, got, tt.wantSignature)
+			}
+			if string(signer.signed) != tt.body {
+				t.Errorf("signed payload = %q, want the response body %q", signer.signed, tt.body)
+			}
+			if tt.signErr == nil && rec.Body.String() != tt.body {
+				t.Errorf("body = %q, want %q", rec.Body.String(), tt.body)
+			}
+		})
+	}
+}
@@ -52,3 +52,24 @@ func TestRequireRole_UserAllowed(t *testing.T) {
 		t.Fatalf("expected status 200 got %d", w.Result().StatusCode)
 	}
 }
+
+func TestRequireRole_DelegatedTokenForbidden(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	am := middleware.NewRoleMiddleware(logger)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	// Put the claims of a token issued to a client on behalf of an admin in context
+	ctx := context.WithValue(req.Context(), middleware.UserContextKey, &domain.TokenClaims{IDCitizen: 123, Role: domain.RoleAdmin, ClientID: "tv-app", Scopes: []string{"profile"}})
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+
+	handler := am.RequireAdmin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	handler.ServeHTTP(w, req)
+	if w.Result().StatusCode != http.StatusForbidden {
+		t.Fatalf("expected status 403 got %d", w.Result().StatusCode)
+	}
+}
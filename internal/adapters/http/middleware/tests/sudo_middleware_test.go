@@ -0,0 +1,46 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	middleware "github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestRequireSudo(t *testing.T) {
+	tests := []struct {
+		name       string
+		claims     *domain.TokenClaims
+		wantStatus int
+	}{
+		{name: "elevated token", claims: &domain.TokenClaims{IDCitizen: 123, SudoUntil: time.Now().Add(time.Minute).Unix()}, wantStatus: http.StatusOK},
+		{name: "regular token", claims: &domain.TokenClaims{IDCitizen: 123}, wantStatus: http.StatusForbidden},
+		{name: "elevated access expired", claims: &domain.TokenClaims{IDCitizen: 123, SudoUntil: time.Now().Add(-time.Second).Unix()}, wantStatus: http.StatusForbidden},
+		{name: "no claims", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodDelete, "/me/phone", nil)
+			if tt.claims != nil {
+				req = req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, tt.claims))
+			}
+			w := httptest.NewRecorder()
+
+			handler := middleware.NewSudoMiddleware(zap.NewNop()).RequireSudo(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package http
+
+import (
+	"sort"
+
+	"github.com/gorilla/mux"
+)
+
+// RouteAuth is the authentication a route requires
+type RouteAuth string
+
+const (
+	// RouteAuthNone marks public routes
+	RouteAuthNone RouteAuth = "none"
+
+	// RouteAuthUser requires the access token of a user
+	RouteAuthUser RouteAuth = "user"
+
+	// RouteAuthAdmin requires the access token of a user with the ADMIN role
+	RouteAuthAdmin RouteAuth = "admin"
+
+	// RouteAuthClientScope requires an OAuth client, limited to the scopes it is allowed
+	RouteAuthClientScope RouteAuth = "client-scope"
+)
+
+// RateLimitClass is the rate limit applied to the requests of a route
+type RateLimitClass string
+
+const (
+	// RateLimitNone marks routes without rate limit
+	RateLimitNone RateLimitClass = "none"
+
+	// RateLimitUser is the soft limit of the authenticated user, reported in the X-RateLimit-* headers
+	RateLimitUser RateLimitClass = "user"
+
+	// RateLimitLogin is the brute-force lockout of the credentials along with the token quota of the user
+	RateLimitLogin RateLimitClass = "login"
+
+	// RateLimitTokenQuota is the token issuance quota of the user or the client
+	RateLimitTokenQuota RateLimitClass = "token-quota"
+
+	// RateLimitEmail limits the emails sent to an address
+	RateLimitEmail RateLimitClass = "email"
+
+	// RateLimitSMS limits the SMS sent to a phone number, within the global SMS quota
+	RateLimitSMS RateLimitClass = "sms"
+)
+
+// RouteMetadata describes the security of a route
+type RouteMetadata struct {
+	Auth      RouteAuth
+	RateLimit RateLimitClass
+}
+
+// RouteInfo is a registered route along with its metadata
+type RouteInfo struct {
+	Path    string
+	Methods []string // empty when the route matches any method
+	RouteMetadata
+}
+
+// RouteRegistry holds the metadata of the routes of a router, so they can be listed for security reviews
+// and gateway configuration
+type RouteRegistry struct {
+	metadata map[*mux.Route]RouteMetadata
+}
+
+// NewRouteRegistry creates an empty route registry
+func NewRouteRegistry() *RouteRegistry {
+	return &RouteRegistry{metadata: make(map[*mux.Route]RouteMetadata)}
+}
+
+// Describe sets the metadata of a route. The metadata of the route of a subrouter applies to its routes
+// that have none of their own.
+func (r *RouteRegistry) Describe(route *mux.Route, auth RouteAuth, rateLimit RateLimitClass) *mux.Route {
+	r.metadata[route] = RouteMetadata{Auth: auth, RateLimit: rateLimit}
+	return route
+}
+
+// Routes lists the routes of the router sorted by path. Routes described neither themselves nor through
+// their subrouter are public and without rate limit.
+func (r *RouteRegistry) Routes(router *mux.Router) ([]RouteInfo, error) {
+	var routes []RouteInfo
+	err := router.Walk(func(route *mux.Route, _ *mux.Router, ancestors []*mux.Route) error {
+		// Routes of subrouters have no handler, their own routes are walked next
+		if route.GetHandler() == nil {
+			return nil
+		}
+
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return err
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			methods = []string{}
+		}
+
+		routes = append(routes, RouteInfo{
+			Path:          path,
+			Methods:       methods,
+			RouteMetadata: r.lookup(route, ancestors),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		return routes[i].Path < routes[j].Path
+	})
+	return routes, nil
+}
+
+// lookup returns the metadata of the route, or of its closest described subrouter
+func (r *RouteRegistry) lookup(route *mux.Route, ancestors []*mux.Route) RouteMetadata {
+	if metadata, ok := r.metadata[route]; ok {
+		return metadata
+	}
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		if metadata, ok := r.metadata[ancestors[i]]; ok {
+			return metadata
+		}
+	}
+	return RouteMetadata{Auth: RouteAuthNone, RateLimit: RateLimitNone}
+}
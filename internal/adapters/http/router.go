@@ -1,38 +1,132 @@
 package http
 
 import (
-	"database/sql"
 	"encoding/json"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
-	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 
 	docs "github.com/kristianrpo/auth-microservice/docs"
 	httpSwagger "github.com/swaggo/http-swagger"
 
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
 	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/admin"
 	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/auth"
 	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/health"
 	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
 	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
 	"github.com/kristianrpo/auth-microservice/internal/application/services"
 )
 
 const version = "1.0.0"
 
+// CORSConfig contains the cross-origin settings of the route groups
+type CORSConfig struct {
+	AllowedOrigins      []string // public and user routes
+	AdminAllowedOrigins []string // admin routes
+	ExposedHeaders      []string // exposed in addition to X-Request-ID and the rate limit headers
+	AllowCredentials    bool
+	MaxAge              time.Duration
+}
+
+// ForwardAuthConfig contains the settings of the forward authentication endpoint used by reverse proxies
+type ForwardAuthConfig struct {
+	TrustedHosts []string
+	LoginURL     string
+	CookieName   string
+}
+
+// CookieConfig contains the settings of the access token cookie of cookie mode, named after the cookie
+// read by the forward authentication
+type CookieConfig struct {
+	Enabled  bool
+	Domain   string
+	Path     string
+	SameSite string
+	Secure   bool
+}
+
+// corsRules returns the CORS rules of the route groups, admin routes only accept their own origins
+func corsRules(cfg CORSConfig) []middleware.CORSRule {
+	exposedHeaders := append([]string{
+		middleware.HeaderRequestID,
+		middleware.HeaderRateLimitLimit,
+		middleware.HeaderRateLimitRemaining,
+		middleware.HeaderRateLimitReset,
+		middleware.HeaderJWSSignature,
+	}, cfg.ExposedHeaders...)
+
+	policy := func(origins []string) middleware.CORSPolicy {
+		return middleware.CORSPolicy{
+			AllowedOrigins:   origins,
+			AllowedMethods:   []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodOptions},
+			AllowedHeaders:   []string{"Content-Type", "Authorization"},
+			ExposedHeaders:   exposedHeaders,
+			AllowCredentials: cfg.AllowCredentials,
+			MaxAge:           cfg.MaxAge,
+		}
+	}
+
+	return []middleware.CORSRule{
+		{PathPrefix: "/", Policy: policy(cfg.AllowedOrigins)},
+		{PathPrefix: "/api/auth/admin", Policy: policy(cfg.AdminAllowedOrigins)},
+	}
+}
+
 // NewRouter creates and configures the main router
 func NewRouter(
 	authService *services.AuthService,
 	oauth2Service *services.OAuth2Service,
-	db *sql.DB,
-	redisClient *redis.Client,
+	notificationService *services.NotificationService,
+	deviceAuthorizationService *services.DeviceAuthorizationService,
+	passwordGrantService *services.PasswordGrantService,
+	scopeService *services.ScopeService,
+	consentService *services.ConsentService,
+	introspectionService *services.IntrospectionService,
+	phoneService *services.PhoneService,
+	phoneLoginService *services.PhoneLoginService,
+	userEmailService *services.UserEmailService,
+	passwordResetService *services.PasswordResetService,
+	emailChangeService *services.EmailChangeService,
+	avatarService *services.AvatarService,
+	anonymizationService *services.AnonymizationService,
+	userMetadataService *services.UserMetadataService,
+	registrationApprovalService *services.RegistrationApprovalService,
+	sudoService *services.SudoService,
+	roleService *services.RoleService,
+	userMergeService *services.UserMergeService,
+	userProvisioningService *services.UserProvisioningService,
+	tokenDebugService *services.TokenDebugService,
+	serviceAccountService *services.ServiceAccountService,
+	quotaService *services.QuotaService,
+	exportService *services.ExportService,
+	userExportService *services.UserExportJobService,
+	rateLimiter ports.RateLimiter,
+	trustProxyHeaders bool,
+	clientCertHeader string,
+	cors CORSConfig,
+	includeUserOnLogin bool,
+	requireSudo bool,
+	serveSchemas bool,
+	serveAdminUI bool,
+	serveAdminDebug bool,
+	forwardAuth ForwardAuthConfig,
+	cookie CookieConfig,
+	responseSigner middleware.ResponseSigner,
+	alertNotifier ports.AlertNotifier,
+	dependencyManager *services.DependencyManager,
+	readinessGate *services.ReadinessGate,
+	healthDetailsService *services.HealthDetailsService,
+	effectiveConfig map[string]interface{},
 	logger *zap.Logger,
 ) *mux.Router {
 	router := mux.NewRouter()
+	routes := NewRouteRegistry()
 
 	docs.SwaggerInfo.Host = ""
 	docs.SwaggerInfo.Schemes = []string{"https", "http"}
@@ -43,21 +137,74 @@ func NewRouter(
 	}
 	docs.SwaggerInfo.BasePath = stage + "/api/auth"
 
+	// Avatars and user exports are disabled without a storage, the nil pointers must not become non-nil interfaces
+	var avatars services.AvatarServiceInterface
+	if avatarService != nil {
+		avatars = avatarService
+	}
+	var userExports services.UserExportJobServiceInterface
+	if userExportService != nil {
+		userExports = userExportService
+	}
+
+	var sessionCookie *shared.SessionCookie
+	if cookie.Enabled {
+		sessionCookie = shared.NewSessionCookie(forwardAuth.CookieName, cookie.Domain, cookie.Path, cookie.SameSite, cookie.Secure)
+	}
+
 	// Handlers
-	authHandler := shared.NewAuthHandler(authService, logger)
-	oauth2Handler := shared.NewOAuth2Handler(oauth2Service, logger)
+	authHandler := shared.NewAuthHandler(authService, phoneLoginService, avatars, includeUserOnLogin, sessionCookie, logger)
+	forwardAuthHandler := shared.NewForwardAuthHandler(authService, forwardAuth.TrustedHosts, forwardAuth.LoginURL, forwardAuth.CookieName, logger)
+	oauth2Handler := shared.NewOAuth2Handler(oauth2Service, deviceAuthorizationService, passwordGrantService, clientCertHeader, trustProxyHeaders, logger)
 	adminOAuthHandler := shared.NewAdminOAuthClientsHandler(oauth2Service, logger)
-	healthHandler := health.NewHealthHandler(db, redisClient, logger, version)
+	adminUsersHandler := shared.NewAdminUsersHandler(anonymizationService, userEmailService, roleService, userMergeService, logger)
+	userMetadataHandler := shared.NewUserMetadataHandler(userMetadataService, logger)
+	userExportHandler := shared.NewUserExportHandler(userExports, logger)
+	registrationApprovalHandler := shared.NewRegistrationApprovalHandler(registrationApprovalService, logger)
+	serviceAccountsHandler := shared.NewServiceAccountsHandler(serviceAccountService, logger)
+	userProvisioningHandler := shared.NewUserProvisioningHandler(userProvisioningService, logger)
+	sudoHandler := shared.NewSudoHandler(sudoService, logger)
+	adminExportHandler := shared.NewAdminExportHandler(exportService, logger)
+	quotasHandler := shared.NewQuotasHandler(quotaService, logger)
+	tokenDebugHandler := shared.NewTokenDebugHandler(tokenDebugService, logger)
+	preferencesHandler := shared.NewNotificationPreferencesHandler(notificationService, logger)
+	scopesHandler := shared.NewScopesHandler(scopeService, logger)
+	consentHandler := shared.NewConsentHandler(consentService, logger)
+	introspectionHandler := shared.NewIntrospectionHandler(introspectionService, logger)
+	phoneHandler := shared.NewPhoneHandler(phoneService, logger)
+	userEmailsHandler := shared.NewUserEmailsHandler(userEmailService, logger)
+	passwordResetHandler := shared.NewPasswordResetHandler(passwordResetService, logger)
+	emailChangeHandler := shared.NewEmailChangeHandler(emailChangeService, logger)
+	adminConfigHandler := shared.NewAdminConfigHandler(effectiveConfig, version, logger)
+	adminRoutesHandler := shared.NewAdminRoutesHandler(func() ([]response.RouteResponse, error) {
+		return toRouteResponses(routes, router)
+	}, logger)
+	healthHandler := health.NewHealthHandler(dependencyManager, readinessGate, healthDetailsService, logger, version)
 
 	// Middleware
 	authMiddleware := middleware.NewAuthMiddleware(authService, logger)
 	roleMiddleware := middleware.NewRoleMiddleware(logger)
+	rateLimitMiddleware := middleware.NewRateLimitMiddleware(rateLimiter, logger)
+	sudoMiddleware := middleware.NewSudoMiddleware(logger)
 
-	// Global middleware
-	router.Use(middleware.CORSMiddleware)
-	router.Use(middleware.LoggingMiddleware(logger))
-	router.Use(middleware.MetricsMiddleware)
-	router.Use(middleware.RecoveryMiddleware(logger))
+	// Global middleware, run in the canonical order of the chain
+	router.Use(middleware.NewChain().
+		Use(middleware.StageRecovery, middleware.RecoveryMiddleware(alertNotifier, logger)).
+		Use(middleware.StageRequestID, middleware.RequestIDMiddleware).
+		Use(middleware.StageRequestID, middleware.ClientInfoMiddleware(trustProxyHeaders)).
+		Use(middleware.StageLogging, middleware.LoggingMiddleware(logger)).
+		Use(middleware.StageLogging, middleware.MetricsMiddleware).
+		Use(middleware.StageCORS, middleware.CORSMiddleware(corsRules(cors)...)).
+		Middleware())
+
+	// Authenticated routes, the rate-limit headers need the authenticated user
+	authenticated := middleware.NewChain().
+		Use(middleware.StageAuth, authMiddleware.Authenticate).
+		Use(middleware.StageAuth, rateLimitMiddleware.Headers)
+	admins := middleware.NewChain().
+		Use(middleware.StageAuth, authMiddleware.Authenticate).
+		Use(middleware.StageAuth, rateLimitMiddleware.Headers).
+		Use(middleware.StageScope, roleMiddleware.RequireAdmin)
 
 	// API auth routes
 	api := router.PathPrefix("/api/auth").Subrouter()
@@ -71,34 +218,149 @@ func NewRouter(
 		httpSwagger.DomID("swagger-ui"),
 	))
 
+	// Token endpoints, their responses carry a detached JWS when response signing is enabled
+	tokenRoutes := api.PathPrefix("/").Subrouter()
+	if responseSigner != nil {
+		tokenRoutes.Use(middleware.NewResponseSigningMiddleware(responseSigner, logger).Sign)
+	}
+	routes.Describe(tokenRoutes.HandleFunc("/login", auth.Login(authHandler)).Methods(http.MethodPost), RouteAuthNone, RateLimitLogin)
+	routes.Describe(api.HandleFunc("/login/phone/code", auth.RequestPhoneLoginCode(authHandler)).Methods(http.MethodPost), RouteAuthNone, RateLimitSMS)
+	api.HandleFunc("/login/password-change", auth.ChangeTemporaryPassword(userProvisioningHandler)).Methods(http.MethodPost)
+	routes.Describe(tokenRoutes.HandleFunc("/refresh", auth.Refresh(authHandler)).Methods(http.MethodPost), RouteAuthNone, RateLimitTokenQuota)
+	routes.Describe(tokenRoutes.HandleFunc("/token", admin.Token(oauth2Handler)).Methods(http.MethodPost), RouteAuthClientScope, RateLimitTokenQuota)
+
 	// Public routes - Authentication routes
 	api.HandleFunc("/register", auth.Register(authHandler)).Methods(http.MethodPost)
-	api.HandleFunc("/login", auth.Login(authHandler)).Methods(http.MethodPost)
-	api.HandleFunc("/refresh", auth.Refresh(authHandler)).Methods(http.MethodPost)
 
-	// OAuth2 Client Credentials endpoint
-	api.HandleFunc("/token", admin.Token(oauth2Handler)).Methods(http.MethodPost)
+	// Password reset by email, with the primary email or a verified secondary email
+	routes.Describe(api.HandleFunc("/password-reset", auth.RequestPasswordReset(passwordResetHandler)).Methods(http.MethodPost), RouteAuthNone, RateLimitEmail)
+	api.HandleFunc("/password-reset/confirm", auth.ConfirmPasswordReset(passwordResetHandler)).Methods(http.MethodPost)
+
+	// Email change confirmation, with the link sent to the new address
+	api.HandleFunc("/email-change/confirm", auth.ConfirmEmailChange(emailChangeHandler)).Methods(http.MethodPost)
+
+	// Token validation for gateway header-based authentication (nginx auth_request, Envoy ext_authz)
+	routes.Describe(api.HandleFunc("/validate", auth.Validate(authHandler)).Methods(http.MethodGet, http.MethodHead), RouteAuthUser, RateLimitNone)
+
+	// Forward authentication of the applications protected by a reverse proxy (Traefik ForwardAuth, oauth2-proxy style)
+	routes.Describe(api.HandleFunc("/forward-auth", auth.ForwardAuth(forwardAuthHandler)).Methods(http.MethodGet, http.MethodHead), RouteAuthUser, RateLimitNone)
+
+	// OAuth2 Device Authorization endpoint (RFC 8628), the client authenticates with its secret and may request its allowed scopes only
+	routes.Describe(api.HandleFunc("/oauth/device/code", admin.DeviceCode(oauth2Handler)).Methods(http.MethodPost), RouteAuthClientScope, RateLimitNone)
+
+	// OAuth2 Token Introspection endpoint (RFC 7662, used by the API gateway)
+	routes.Describe(api.HandleFunc("/oauth/introspect", admin.Introspect(introspectionHandler)).Methods(http.MethodPost), RouteAuthClientScope, RateLimitNone)
+
+	// Error code catalog (public, consumed by client teams)
+	api.HandleFunc("/errors/catalog", auth.ErrorCatalog()).Methods(http.MethodGet)
+
+	// JSON Schemas of the payloads (public, consumed by client teams to validate their payloads in CI)
+	if serveSchemas {
+		api.HandleFunc("/schemas", auth.ListSchemas()).Methods(http.MethodGet)
+		api.HandleFunc("/schemas/{name}", auth.GetSchema()).Methods(http.MethodGet)
+	}
+
+	// OAuth2 scope registry (public, consumed by documentation and consent screens)
+	api.HandleFunc("/oauth/scopes", admin.ListScopes(scopesHandler)).Methods(http.MethodGet)
+
+	// Destructive operations, the creation of credentials and of users, and the changes of the clients that
+	// decide where their tokens go or how long their secrets last, require elevated access when sudo mode is
+	// enforced
+	destructive := func(handler http.HandlerFunc) http.Handler {
+		if !requireSudo {
+			return handler
+		}
+		return sudoMiddleware.RequireSudo(handler)
+	}
 
 	// Protected routes - Authentication required routes
-	protected := api.PathPrefix("/").Subrouter()
-	protected.Use(authMiddleware.Authenticate)
+	protected := routes.Describe(api.PathPrefix("/"), RouteAuthUser, RateLimitUser).Subrouter()
+	protected.Use(authenticated.Middleware())
 	protected.HandleFunc("/logout", auth.Logout(authHandler)).Methods(http.MethodPost)
+	protected.HandleFunc("/sudo", auth.Sudo(sudoHandler)).Methods(http.MethodPost)
 	protected.HandleFunc("/me", auth.GetMe(authHandler)).Methods(http.MethodGet)
+	protected.HandleFunc("/me/preferences", auth.GetPreferences(preferencesHandler)).Methods(http.MethodGet)
+	protected.HandleFunc("/me/preferences", auth.UpdatePreferences(preferencesHandler)).Methods(http.MethodPut)
+	protected.HandleFunc("/me/consents", auth.ListConsents(consentHandler)).Methods(http.MethodGet)
+	protected.HandleFunc("/me/consents/{client_id}", auth.RevokeConsent(consentHandler)).Methods(http.MethodDelete)
+	protected.HandleFunc("/me/phone", auth.GetPhone(phoneHandler)).Methods(http.MethodGet)
+	protected.HandleFunc("/me/phone", auth.EnrollPhone(phoneHandler)).Methods(http.MethodPost)
+	protected.Handle("/me/phone", destructive(auth.DeletePhone(phoneHandler))).Methods(http.MethodDelete)
+	protected.HandleFunc("/me/phone/verify", auth.VerifyPhone(phoneHandler)).Methods(http.MethodPost)
+	protected.Handle("/me/email", destructive(auth.RequestEmailChange(emailChangeHandler))).Methods(http.MethodPut)
+	protected.HandleFunc("/me/emails", auth.ListEmails(userEmailsHandler)).Methods(http.MethodGet)
+	protected.HandleFunc("/me/emails", auth.AddEmail(userEmailsHandler)).Methods(http.MethodPost)
+	protected.Handle("/me/emails/{id}", destructive(auth.DeleteEmail(userEmailsHandler))).Methods(http.MethodDelete)
+	protected.HandleFunc("/me/emails/{id}/verify", auth.VerifyEmail(userEmailsHandler)).Methods(http.MethodPost)
+	protected.HandleFunc("/me/metadata", auth.UpdateMetadata(userMetadataHandler)).Methods(http.MethodPut)
+	if avatars != nil {
+		protected.HandleFunc("/me/avatar", auth.UploadAvatar(authHandler)).Methods(http.MethodPut)
+	}
+	if userExports != nil {
+		protected.HandleFunc("/me/export", auth.RequestExport(userExportHandler)).Methods(http.MethodPost)
+		protected.HandleFunc("/me/export/{job}", auth.GetExport(userExportHandler)).Methods(http.MethodGet)
+	}
+	protected.HandleFunc("/oauth/device/verify", admin.GetDeviceVerification(oauth2Handler)).Methods(http.MethodGet)
+	protected.HandleFunc("/oauth/device/verify", admin.VerifyDevice(oauth2Handler)).Methods(http.MethodPost)
 
 	// Health checks
 	api.HandleFunc("/health", healthHandler.Health).Methods(http.MethodGet)
 	api.HandleFunc("/health/ready", healthHandler.Ready).Methods(http.MethodGet)
 	api.HandleFunc("/health/live", healthHandler.Live).Methods(http.MethodGet)
+	routes.Describe(api.Handle("/health/details", middleware.NewChain().
+		Use(middleware.StageAuth, authMiddleware.Authenticate).
+		Use(middleware.StageScope, roleMiddleware.RequireAdmin).
+		Then(http.HandlerFunc(healthHandler.Details))).Methods(http.MethodGet), RouteAuthAdmin, RateLimitNone)
 
 	// Metrics (Prometheus)
 	api.Handle("/metrics", promhttp.Handler()).Methods(http.MethodGet)
 
+	// Admin web UI, its assets are public and its pages call the admin routes with the token of an administrator
+	if serveAdminUI {
+		api.Handle("/admin/ui", http.RedirectHandler("ui/", http.StatusMovedPermanently)).Methods(http.MethodGet)
+		api.PathPrefix("/admin/ui/").Handler(http.StripPrefix("/api/auth/admin/ui", admin.UI())).Methods(http.MethodGet, http.MethodHead)
+	}
+
 	// Admin routes (require ADMIN role)
-	adminRoutes := api.PathPrefix("/admin").Subrouter()
-	adminRoutes.Use(authMiddleware.Authenticate)
-	adminRoutes.Use(roleMiddleware.RequireAdmin)
-	adminRoutes.HandleFunc("/oauth-clients", admin.CreateOAuthClient(adminOAuthHandler)).Methods(http.MethodPost)
+	adminRoutes := routes.Describe(api.PathPrefix("/admin"), RouteAuthAdmin, RateLimitUser).Subrouter()
+	adminRoutes.Use(admins.Middleware())
+	adminRoutes.HandleFunc("/config", admin.GetConfig(adminConfigHandler)).Methods(http.MethodGet)
+	adminRoutes.HandleFunc("/routes", admin.ListRoutes(adminRoutesHandler)).Methods(http.MethodGet)
+	adminRoutes.Handle("/oauth-clients", destructive(admin.CreateOAuthClient(adminOAuthHandler))).Methods(http.MethodPost)
 	adminRoutes.HandleFunc("/oauth-clients", admin.ListOAuthClients(adminOAuthHandler)).Methods(http.MethodGet)
+	adminRoutes.HandleFunc("/oauth-clients/expiring-secrets", admin.ListExpiringClientSecrets(adminOAuthHandler)).Methods(http.MethodGet)
+	adminRoutes.Handle("/oauth-clients/{id}", destructive(admin.UpdateOAuthClient(adminOAuthHandler))).Methods(http.MethodPut)
+	adminRoutes.Handle("/oauth-clients/{id}/request-signing-key", destructive(admin.RotateRequestSigningKey(adminOAuthHandler))).Methods(http.MethodPost)
+	adminRoutes.Handle("/oauth-clients/{id}/request-signing-key", destructive(admin.DeleteRequestSigningKey(adminOAuthHandler))).Methods(http.MethodDelete)
+	adminRoutes.Handle("/oauth-clients/{id}/redirect-uris", destructive(admin.AddRedirectURI(adminOAuthHandler))).Methods(http.MethodPost)
+	adminRoutes.Handle("/oauth-clients/{id}/redirect-uris", destructive(admin.RemoveRedirectURI(adminOAuthHandler))).Methods(http.MethodDelete)
+	adminRoutes.Handle("/oauth-clients/{id}/revoke-tokens", destructive(admin.RevokeClientTokens(adminOAuthHandler))).Methods(http.MethodPost)
+	adminRoutes.Handle("/oauth-clients/{id}/secret-expiry", destructive(admin.SetClientSecretExpiry(adminOAuthHandler))).Methods(http.MethodPut)
+	adminRoutes.HandleFunc("/scopes", admin.ListScopes(scopesHandler)).Methods(http.MethodGet)
+	adminRoutes.HandleFunc("/scopes", admin.CreateScope(scopesHandler)).Methods(http.MethodPost)
+	adminRoutes.HandleFunc("/scopes/{name}", admin.UpdateScope(scopesHandler)).Methods(http.MethodPut)
+	adminRoutes.Handle("/scopes/{name}", destructive(admin.DeleteScope(scopesHandler))).Methods(http.MethodDelete)
+	adminRoutes.HandleFunc("/users", admin.ListUsers(registrationApprovalHandler)).Methods(http.MethodGet)
+	adminRoutes.Handle("/users", destructive(admin.CreateUser(userProvisioningHandler))).Methods(http.MethodPost)
+	adminRoutes.HandleFunc("/users/export", admin.ExportUsers(adminExportHandler)).Methods(http.MethodGet)
+	adminRoutes.HandleFunc("/users/{id}/approve", admin.ApproveUser(registrationApprovalHandler)).Methods(http.MethodPost)
+	adminRoutes.HandleFunc("/users/{id}/reject", admin.RejectUser(registrationApprovalHandler)).Methods(http.MethodPost)
+	adminRoutes.Handle("/users/{id}/anonymize", destructive(admin.AnonymizeUser(adminUsersHandler))).Methods(http.MethodPost)
+	adminRoutes.Handle("/users/{id}/role", destructive(admin.ChangeUserRole(adminUsersHandler))).Methods(http.MethodPut)
+	adminRoutes.Handle("/users/{id}/merge", destructive(admin.MergeUser(adminUsersHandler))).Methods(http.MethodPost)
+	adminRoutes.HandleFunc("/users/{id}/emails", admin.ListUserEmails(adminUsersHandler)).Methods(http.MethodGet)
+	adminRoutes.HandleFunc("/users/{id}/metadata", admin.UpdateUserMetadata(userMetadataHandler)).Methods(http.MethodPut)
+	adminRoutes.Handle("/service-accounts", destructive(admin.CreateServiceAccount(serviceAccountsHandler))).Methods(http.MethodPost)
+	adminRoutes.HandleFunc("/audit-logs/export", admin.ExportAuditLog(adminExportHandler)).Methods(http.MethodGet)
+	adminRoutes.HandleFunc("/quotas", admin.ListQuotas(quotasHandler)).Methods(http.MethodGet)
+	adminRoutes.HandleFunc("/quotas/{subject_type}/{subject_id}", admin.GetQuota(quotasHandler)).Methods(http.MethodGet)
+	adminRoutes.HandleFunc("/quotas/{subject_type}/{subject_id}", admin.UpdateQuota(quotasHandler)).Methods(http.MethodPut)
+	adminRoutes.HandleFunc("/quotas/{subject_type}/{subject_id}", admin.DeleteQuota(quotasHandler)).Methods(http.MethodDelete)
+
+	// Debugging endpoints for support investigations, disabled in production by default
+	if serveAdminDebug {
+		adminRoutes.HandleFunc("/debug/decode-token", admin.DecodeToken(tokenDebugHandler)).Methods(http.MethodPost)
+	}
 
 	// Root endpoint route
 	router.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
@@ -111,5 +373,27 @@ func NewRouter(
 		})
 	}).Methods(http.MethodGet)
 
+	// Preflight requests of every route, answered by the CORS middleware
+	router.PathPrefix("/").Methods(http.MethodOptions).HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
 	return router
 }
+
+// toRouteResponses lists the routes of the router with their metadata
+func toRouteResponses(routes *RouteRegistry, router *mux.Router) ([]response.RouteResponse, error) {
+	infos, err := routes.Routes(router)
+	if err != nil {
+		return nil, err
+	}
+
+	routeResponses := make([]response.RouteResponse, 0, len(infos))
+	for _, info := range infos {
+		routeResponses = append(routeResponses, response.RouteResponse{
+			Path:      info.Path,
+			Methods:   info.Methods,
+			Auth:      string(info.Auth),
+			RateLimit: string(info.RateLimit),
+		})
+	}
+	return routeResponses, nil
+}
@@ -0,0 +1,48 @@
+package tests
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	httpadapter "github.com/kristianrpo/auth-microservice/internal/adapters/http"
+)
+
+func noopHandler(w http.ResponseWriter, r *http.Request) {}
+
+func TestRouteRegistry_Routes(t *testing.T) {
+	router := mux.NewRouter()
+	routes := httpadapter.NewRouteRegistry()
+
+	api := router.PathPrefix("/api/auth").Subrouter()
+	routes.Describe(api.HandleFunc("/login", noopHandler).Methods(http.MethodPost), httpadapter.RouteAuthNone, httpadapter.RateLimitLogin)
+	api.HandleFunc("/health", noopHandler).Methods(http.MethodGet)
+
+	protected := routes.Describe(api.PathPrefix("/"), httpadapter.RouteAuthUser, httpadapter.RateLimitUser).Subrouter()
+	protected.HandleFunc("/me", noopHandler).Methods(http.MethodGet, http.MethodHead)
+
+	adminRoutes := routes.Describe(api.PathPrefix("/admin"), httpadapter.RouteAuthAdmin, httpadapter.RateLimitUser).Subrouter()
+	adminRoutes.HandleFunc("/users", noopHandler).Methods(http.MethodGet)
+	routes.Describe(adminRoutes.HandleFunc("/debug", noopHandler).Methods(http.MethodPost), httpadapter.RouteAuthAdmin, httpadapter.RateLimitNone)
+
+	router.PathPrefix("/").HandlerFunc(noopHandler)
+
+	got, err := routes.Routes(router)
+	if err != nil {
+		t.Fatalf("Routes() error = %v", err)
+	}
+
+	want := []httpadapter.RouteInfo{
+		{Path: "/", Methods: []string{}, RouteMetadata: httpadapter.RouteMetadata{Auth: httpadapter.RouteAuthNone, RateLimit: httpadapter.RateLimitNone}},
+		{Path: "/api/auth/admin/debug", Methods: []string{http.MethodPost}, RouteMetadata: httpadapter.RouteMetadata{Auth: httpadapter.RouteAuthAdmin, RateLimit: httpadapter.RateLimitNone}},
+		{Path: "/api/auth/admin/users", Methods: []string{http.MethodGet}, RouteMetadata: httpadapter.RouteMetadata{Auth: httpadapter.RouteAuthAdmin, RateLimit: httpadapter.RateLimitUser}},
+		{Path: "/api/auth/health", Methods: []string{http.MethodGet}, RouteMetadata: httpadapter.RouteMetadata{Auth: httpadapter.RouteAuthNone, RateLimit: httpadapter.RateLimitNone}},
+		{Path: "/api/auth/login", Methods: []string{http.MethodPost}, RouteMetadata: httpadapter.RouteMetadata{Auth: httpadapter.RouteAuthNone, RateLimit: httpadapter.RateLimitLogin}},
+		{Path: "/api/auth/me", Methods: []string{http.MethodGet, http.MethodHead}, RouteMetadata: httpadapter.RouteMetadata{Auth: httpadapter.RouteAuthUser, RateLimit: httpadapter.RateLimitUser}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Routes() = %+v\nwant %+v", got, want)
+	}
+}
@@ -0,0 +1,259 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	httpadapter "github.com/kristianrpo/auth-microservice/internal/adapters/http"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/memory"
+)
+
+// allowAllRateLimiter never limits, the rate limits are not under test
+type allowAllRateLimiter struct{}
+
+func (allowAllRateLimiter) Hit(ctx context.Context, key string) (*domain.RateLimitStatus, error) {
+	return &domain.RateLimitStatus{Limit: 1000, Used: 1, ResetAt: time.Now().Add(time.Minute)}, nil
+}
+
+func (allowAllRateLimiter) Peek(ctx context.Context, key string) (*domain.RateLimitStatus, error) {
+	return &domain.RateLimitStatus{Limit: 1000, ResetAt: time.Now().Add(time.Minute)}, nil
+}
+
+// newTestRouter builds the router of the service with sudo mode enforced. Tokens and client credentials are
+// validated for real, against in-memory stores, but the other services are missing: requests reaching their
+// handlers fail, so only the authentication of the routes can be checked.
+func newTestRouter(t *testing.T) (*mux.Router, *services.JWTService) {
+	t.Helper()
+	logger := zap.NewNop()
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, logger)
+	authService := services.NewAuthService(memory.NewUserRepository(), memory.NewTokenRepository(), jwtService, nil, logger)
+	oauth2Service := services.NewOAuth2Service(memory.NewOAuthClientRepository(), nil, "test-secret-key-at-least-32-chars-long", 15*time.Minute, services.TokenSigningPolicy{}, logger)
+	deviceAuthorizationService := services.NewDeviceAuthorizationService(oauth2Service, nil, authService, nil, 10*time.Minute, 5*time.Second, "", logger)
+	introspectionService := services.NewIntrospectionService(authService, oauth2Service, allowAllRateLimiter{}, logger)
+
+	router := httpadapter.NewRouter(
+		authService,
+		oauth2Service,
+		nil,
+		deviceAuthorizationService,
+		nil, nil, nil,
+		introspectionService,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		allowAllRateLimiter{},
+		false,
+		"",
+		httpadapter.CORSConfig{},
+		false,
+		true, // requireSudo
+		true,
+		true,
+		true,
+		httpadapter.ForwardAuthConfig{TrustedHosts: []string{"app.example.com"}},
+		httpadapter.CookieConfig{},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		logger,
+	)
+	return router, jwtService
+}
+
+// routeVariable matches the variables of a path template
+var routeVariable = regexp.MustCompile(`\{[^}]+\}`)
+
+// routeRequest is a method and path template of the router
+type routeRequest struct {
+	method string
+	path   string
+}
+
+// routeRequests lists the methods and path templates of the routes of the router
+func routeRequests(t *testing.T, router *mux.Router) []routeRequest {
+	t.Helper()
+	var requests []routeRequest
+	err := router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		if route.GetHandler() == nil {
+			return nil
+		}
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return err
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			// Routes matching any method, e.g. the preflight requests, are not routes of the API
+			return nil
+		}
+		for _, method := range methods {
+			if method != http.MethodOptions && method != http.MethodHead {
+				requests = append(requests, routeRequest{method: method, path: path})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk() error = %v", err)
+	}
+	return requests
+}
+
+// serveRoute sends a request to the route with the access token, and returns the status and the error code
+// of the response
+func serveRoute(router *mux.Router, route routeRequest, accessToken string) (int, string) {
+	req := httptest.NewRequest(route.method, routeVariable.ReplaceAllString(route.path, "x"), nil)
+	// The forward authentication answers the trusted hosts only
+	req.Header.Set("X-Forwarded-Host", "app.example.com")
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var resp response.ErrorResponse
+	_ = json.NewDecoder(w.Body).Decode(&resp)
+	return w.Code, resp.Code
+}
+
+func TestNewRouter_SudoProtectedRoutes(t *testing.T) {
+	router, jwtService := newTestRouter(t)
+	admin := &domain.User{ID: "admin-1", IDCitizen: 1, Email: "admin@example.com", Role: domain.RoleAdmin}
+
+	var got []string
+	for _, route := range routeRequests(t, router) {
+		// A new token every time, since routes like /logout revoke it
+		tokenPair, err := jwtService.GenerateUserTokenPair(admin)
+		if err != nil {
+			t.Fatalf("GenerateUserTokenPair() error = %v", err)
+		}
+		if status, code := serveRoute(router, route, tokenPair.AccessToken); status == http.StatusForbidden && code == "SUDO_REQUIRED" {
+			got = append(got, route.method+" "+route.path)
+		}
+	}
+	sort.Strings(got)
+
+	want := []string{
+		"DELETE /api/auth/admin/oauth-clients/{id}/redirect-uris",
+		"DELETE /api/auth/admin/oauth-clients/{id}/request-signing-key",
+		"DELETE /api/auth/admin/scopes/{name}",
+		"DELETE /api/auth/me/emails/{id}",
+		"DELETE /api/auth/me/phone",
+		"POST /api/auth/admin/oauth-clients",
+		"POST /api/auth/admin/oauth-clients/{id}/redirect-uris",
+		"POST /api/auth/admin/oauth-clients/{id}/request-signing-key",
+		"POST /api/auth/admin/oauth-clients/{id}/revoke-tokens",
+		"POST /api/auth/admin/service-accounts",
+		"POST /api/auth/admin/users",
+		"POST /api/auth/admin/users/{id}/anonymize",
+		"POST /api/auth/admin/users/{id}/merge",
+		"PUT /api/auth/admin/oauth-clients/{id}",
+		"PUT /api/auth/admin/oauth-clients/{id}/secret-expiry",
+		"PUT /api/auth/admin/users/{id}/role",
+		"PUT /api/auth/me/email",
+	}
+	sort.Strings(want)
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sudo protected routes = %v\nwant %v", got, want)
+	}
+}
+
+// listRoutes returns the routes of the router with the authentication they declare, as listed by GET /admin/routes
+func listRoutes(t *testing.T, router *mux.Router, accessToken string) []response.RouteResponse {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/api/auth/admin/routes", nil)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /admin/routes status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	var resp response.RoutesResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode the routes: %v", err)
+	}
+	return resp.Routes
+}
+
+// serveClientRoute sends a request to the route authenticated with the credentials of an unknown OAuth client,
+// and returns the status of the response
+func serveClientRoute(router *mux.Router, route routeRequest) int {
+	form := url.Values{"grant_type": {"client_credentials"}, "device_code": {"x"}, "token": {"x"}}
+	req := httptest.NewRequest(route.method, routeVariable.ReplaceAllString(route.path, "x"), strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth("unknown-client", "wrong-secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestNewRouter_DeclaredAuth(t *testing.T) {
+	router, jwtService := newTestRouter(t)
+	admin := &domain.User{ID: "admin-1", IDCitizen: 1, Email: "admin@example.com", Role: domain.RoleAdmin}
+	user := &domain.User{ID: "user-1", IDCitizen: 2, Email: "user@example.com", Role: domain.RoleUser}
+
+	adminTokens, err := jwtService.GenerateUserTokenPair(admin)
+	if err != nil {
+		t.Fatalf("GenerateUserTokenPair() error = %v", err)
+	}
+	routes := listRoutes(t, router, adminTokens.AccessToken)
+	if len(routes) == 0 {
+		t.Fatal("GET /admin/routes listed no routes")
+	}
+
+	for _, described := range routes {
+		for _, method := range described.Methods {
+			if method == http.MethodOptions || method == http.MethodHead {
+				continue
+			}
+			route := routeRequest{method: method, path: described.Path}
+			name := method + " " + described.Path
+
+			switch auth := httpadapter.RouteAuth(described.Auth); auth {
+			case httpadapter.RouteAuthNone:
+				if status, code := serveRoute(router, route, ""); code == "MISSING_AUTH_HEADER" {
+					t.Errorf("%s is declared %s but answers %d %s without a token", name, auth, status, code)
+				}
+			case httpadapter.RouteAuthUser, httpadapter.RouteAuthAdmin:
+				if status, code := serveRoute(router, route, ""); status != http.StatusUnauthorized {
+					t.Errorf("%s is declared %s but answers %d %s without a token, want %d", name, auth, status, code, http.StatusUnauthorized)
+				}
+				if auth != httpadapter.RouteAuthAdmin {
+					continue
+				}
+				// A new token every time, since routes like /logout revoke it
+				userTokens, err := jwtService.GenerateUserTokenPair(user)
+				if err != nil {
+					t.Fatalf("GenerateUserTokenPair() error = %v", err)
+				}
+				if status, code := serveRoute(router, route, userTokens.AccessToken); status != http.StatusForbidden {
+					t.Errorf("%s is declared %s but answers %d %s to a user, want %d", name, auth, status, code, http.StatusForbidden)
+				}
+			case httpadapter.RouteAuthClientScope:
+				if status := serveClientRoute(router, route); status != http.StatusUnauthorized {
+					t.Errorf("%s is declared %s but answers %d to an unknown client, want %d", name, auth, status, http.StatusUnauthorized)
+				}
+			default:
+				t.Errorf("%s declares an unknown authentication %q", name, described.Auth)
+			}
+		}
+	}
+}
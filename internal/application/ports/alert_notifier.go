@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// PanicAlert describes a request whose handler panicked
+type PanicAlert struct {
+	RequestID string
+	Method    string
+	Endpoint  string // route template of the request, e.g. /api/auth/admin/users/{id}
+	Panic     string
+	Stack     string
+	Time      time.Time
+}
+
+// AlertNotifier delivers critical alerts to the operators, e.g. through a chat or paging webhook
+type AlertNotifier interface {
+	NotifyPanic(ctx context.Context, alert PanicAlert) error
+}
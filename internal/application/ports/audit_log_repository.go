@@ -0,0 +1,40 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// AuditLogRepository defines the persistence operations for the audit log
+type AuditLogRepository interface {
+	// Record appends a record to the audit log
+	Record(ctx context.Context, record *domain.AuditRecord) error
+}
+
+// AuditExportRepository tracks which audit records were delivered to the external audit sink
+type AuditExportRepository interface {
+	// ClaimUnexported returns up to limit records not exported yet and due for export, oldest first, and hides
+	// them from other claims for the lease, so several instances can export the audit log concurrently
+	ClaimUnexported(ctx context.Context, limit int, lease time.Duration) ([]*domain.AuditRecord, error)
+
+	// MarkExported records the delivery of the records
+	MarkExported(ctx context.Context, ids []string) error
+
+	// MarkExportFailed schedules the next export attempt of the records
+	MarkExportFailed(ctx context.Context, ids []string, nextAttemptAt time.Time) error
+
+	// CountUnexported returns the number of records not exported yet
+	CountUnexported(ctx context.Context) (int, error)
+}
+
+// AuditReferenceRepository defines the operations on the audit records about a user, e.g. to move them
+// to the surviving account when duplicate accounts are merged
+type AuditReferenceRepository interface {
+	// CountByTarget returns the number of audit records about the target
+	CountByTarget(ctx context.Context, targetID string) (int, error)
+
+	// ReassignTarget moves the audit records about a target to another one and returns how many were moved
+	ReassignTarget(ctx context.Context, fromTargetID, toTargetID string) (int, error)
+}
@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// AuditSink defines the operations of an external audit log collector (syslog server, SIEM HTTP collector, etc.)
+type AuditSink interface {
+	// Send delivers a batch of audit records. A nil error means the collector accepted every record.
+	Send(ctx context.Context, records []*domain.AuditRecord) error
+
+	// Name returns the name of the sink, used in logs and metrics
+	Name() string
+}
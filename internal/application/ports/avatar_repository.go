@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// AvatarRepository defines the persistence operations for the avatars of users
+type AvatarRepository interface {
+	// Get retrieves the avatar of a user, returning ErrAvatarNotFound when they have none
+	Get(ctx context.Context, userID string) (*domain.Avatar, error)
+
+	// Upsert sets the avatar of a user, replacing the previous one
+	Upsert(ctx context.Context, avatar *domain.Avatar) error
+
+	// DeleteByUserID removes the avatar of a user, if any
+	DeleteByUserID(ctx context.Context, userID string) error
+
+	// ReferencedKeys returns which of the object keys are the avatar of a user
+	ReferencedKeys(ctx context.Context, keys []string) (map[string]bool, error)
+}
@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"context"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// BlacklistReplicator replicates the blacklisted tokens between the regions of a multi-region deployment,
+// e.g. through a Redis stream or a Kafka topic shared by the regions
+type BlacklistReplicator interface {
+	// Publish sends a token blacklisted in this region to the other regions
+	Publish(ctx context.Context, token *domain.BlacklistedToken) error
+
+	// Subscribe delivers the tokens published by every region, this one included, to handle until the
+	// context is cancelled. A token whose handling fails may be delivered again.
+	Subscribe(ctx context.Context, handle func(ctx context.Context, token *domain.BlacklistedToken) error) error
+}
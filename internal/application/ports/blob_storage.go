@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+	"io"
+	"time"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// BlobStorage defines the operations of an object storage (S3, MinIO)
+type BlobStorage interface {
+	// Put stores size bytes read from body under key, replacing any object with the same key
+	Put(ctx context.Context, key, contentType string, body io.Reader, size int64) error
+
+	// Delete removes the object stored under key, deleting a missing object is not an error
+	Delete(ctx context.Context, key string) error
+
+	// List returns up to limit objects whose key starts with prefix, in key order after startAfter
+	List(ctx context.Context, prefix, startAfter string, limit int) ([]domain.BlobObject, error)
+
+	// SignedURL returns a URL granting read access to the object stored under key until it expires
+	SignedURL(key string, expiresIn time.Duration) (string, error)
+}
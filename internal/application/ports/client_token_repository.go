@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// ClientTokenRepository defines the tracking of the access tokens issued to OAuth2 clients, so all the
+// tokens of a client can be revoked at once
+type ClientTokenRepository interface {
+	// Track records the ID (jti) of a token issued to the client until the token expires
+	Track(ctx context.Context, clientID, tokenID string, expiresAt time.Time) error
+
+	// RevokeAll revokes the unexpired tokens tracked for the client and returns how many were revoked
+	RevokeAll(ctx context.Context, clientID string) (int, error)
+
+	// IsRevoked verifies if a token of the client was revoked
+	IsRevoked(ctx context.Context, clientID, tokenID string) (bool, error)
+}
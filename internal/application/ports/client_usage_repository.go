@@ -0,0 +1,31 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// ClientUsageCounter defines the counters of the usage of OAuth2 clients, cheap enough to be updated on
+// every request and drained periodically into the ClientUsageRepository
+type ClientUsageCounter interface {
+	// RecordIssuance counts a token issued to the client at the time
+	RecordIssuance(ctx context.Context, clientID string, at time.Time) error
+
+	// RecordError counts a failed request of the client
+	RecordError(ctx context.Context, clientID string) error
+
+	// Drain returns the usage counted since the last drain and resets the counters
+	Drain(ctx context.Context) ([]*domain.ClientUsageDelta, error)
+}
+
+// ClientUsageRepository defines the durable storage of the usage of OAuth2 clients
+type ClientUsageRepository interface {
+	// Add adds the usage to the totals of the client. The recent errors restart from the delta when they
+	// were counted since before errorWindow ago. The usage of clients that no longer exist is ignored.
+	Add(ctx context.Context, delta *domain.ClientUsageDelta, errorWindow time.Duration) error
+
+	// List returns the usage of every client that was used, keyed by client ID
+	List(ctx context.Context) (map[string]*domain.ClientUsage, error)
+}
@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// ConsentRepository defines the interface for user consent persistence operations
+type ConsentRepository interface {
+	// Get retrieves the consent a user granted to a client
+	Get(ctx context.Context, userID, clientID string) (*domain.Consent, error)
+
+	// ListByUser retrieves every consent granted by a user
+	ListByUser(ctx context.Context, userID string) ([]*domain.Consent, error)
+
+	// Upsert creates or replaces the consent of a user for a client
+	Upsert(ctx context.Context, consent *domain.Consent) error
+
+	// Delete revokes the consent of a user for a client
+	Delete(ctx context.Context, userID, clientID string) error
+}
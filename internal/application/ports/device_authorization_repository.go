@@ -0,0 +1,33 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// DeviceAuthorizationRepository defines the cache operations for device authorization requests
+type DeviceAuthorizationRepository interface {
+	// Store stores a new device authorization until it expires
+	Store(ctx context.Context, auth *domain.DeviceAuthorization) error
+
+	// GetByDeviceCode retrieves a device authorization by its device code
+	GetByDeviceCode(ctx context.Context, deviceCode string) (*domain.DeviceAuthorization, error)
+
+	// GetByUserCode retrieves a device authorization by its user code
+	GetByUserCode(ctx context.Context, userCode string) (*domain.DeviceAuthorization, error)
+
+	// Update replaces a pending device authorization keeping its expiration.
+	// It returns ErrDeviceCodeExpired when the authorization is gone or no longer pending.
+	Update(ctx context.Context, auth *domain.DeviceAuthorization) error
+
+	// RecordPoll stores the polling time and interval of a device authorization that is still pending
+	RecordPoll(ctx context.Context, deviceCode string, polledAt time.Time, interval time.Duration) error
+
+	// Consume atomically removes a device authorization and returns it; only the first caller gets it
+	Consume(ctx context.Context, deviceCode string) (*domain.DeviceAuthorization, error)
+
+	// Delete removes a device authorization
+	Delete(ctx context.Context, auth *domain.DeviceAuthorization) error
+}
@@ -0,0 +1,12 @@
+package ports
+
+// DrainStats counts the in-flight work of a component when it was stopped
+type DrainStats struct {
+	Drained   int // work completed while stopping
+	Cancelled int // work abandoned because the stop deadline was exceeded
+}
+
+// DrainReporter is implemented by components that report the in-flight work handled by their last Stop
+type DrainReporter interface {
+	DrainStats() DrainStats
+}
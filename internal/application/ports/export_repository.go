@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// UserExportRepository streams users for the admin exports
+type UserExportRepository interface {
+	// StreamUsers calls fn for every user matching the filter, oldest first, without loading them all in
+	// memory. An error returned by fn stops the stream and is returned.
+	StreamUsers(ctx context.Context, filter domain.UserExportFilter, fn func(*domain.User) error) error
+}
+
+// AuditLogQueryRepository streams audit records for the admin exports
+type AuditLogQueryRepository interface {
+	// StreamRecords calls fn for every audit record matching the filter, oldest first, without loading them
+	// all in memory. An error returned by fn stops the stream and is returned.
+	StreamRecords(ctx context.Context, filter domain.AuditLogFilter, fn func(*domain.AuditRecord) error) error
+}
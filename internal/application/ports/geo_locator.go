@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// GeoLocator defines the IP geolocation operations
+type GeoLocator interface {
+	// Locate returns the approximate location of an IP address.
+	// It returns ErrLocationNotFound for private, reserved or unknown addresses.
+	Locate(ctx context.Context, ip string) (*domain.GeoLocation, error)
+}
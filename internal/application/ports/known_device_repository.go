@@ -0,0 +1,12 @@
+package ports
+
+import "context"
+
+// KnownDeviceRepository defines the cache operations for the devices users have authenticated from
+type KnownDeviceRepository interface {
+	// IsKnown checks if the user has recently authenticated from the device
+	IsKnown(ctx context.Context, userID, deviceID string) (bool, error)
+
+	// Remember marks the device as known for the user
+	Remember(ctx context.Context, userID, deviceID string) error
+}
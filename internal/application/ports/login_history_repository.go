@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// LoginHistoryRepository defines the cache operations for the recent login locations of users
+type LoginHistoryRepository interface {
+	// Record adds a login location to the history of a user
+	Record(ctx context.Context, userID string, location *domain.LoginLocation) error
+
+	// Recent returns the login locations of a user since the given time, most recent first
+	Recent(ctx context.Context, userID string, since time.Time) ([]*domain.LoginLocation, error)
+}
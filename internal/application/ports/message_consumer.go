@@ -1,15 +1,39 @@
 package ports
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // MessageHandler defines the function signature for message handlers
 type MessageHandler func(ctx context.Context, message []byte) error
 
+// QueueSubscription declares how the messages of a queue are consumed
+type QueueSubscription struct {
+	Queue   string
+	Handler MessageHandler
+
+	Prefetch    int // unacknowledged messages delivered at once, defaults to Concurrency
+	Concurrency int // workers processing messages of the queue in parallel
+
+	// A failed message is retried MaxRetries times, RetryDelay apart, and then moved to the
+	// DeadLetterQueue (or discarded when none is configured)
+	MaxRetries      int
+	RetryDelay      time.Duration
+	DeadLetterQueue string
+}
+
 // MessageConsumer defines the interface for consuming messages from message queues (RabbitMQ, Kafka, SQS, etc.)
 type MessageConsumer interface {
-	// SubscribeToQueue starts consuming messages from a specific queue with the provided handler
-	SubscribeToQueue(ctx context.Context, queueName string, handler MessageHandler) error
+	// Register adds a queue subscription, it must be called before Start
+	Register(subscription QueueSubscription) error
+
+	// Start starts consuming every registered queue
+	Start(ctx context.Context) error
+
+	// Subscribed returns a channel closed once every registered queue has been subscribed
+	Subscribed() <-chan struct{}
 
-	// Close closes the connection to the message broker
-	Close() error
+	// Stop stops consuming and waits for the messages being processed, until the context is done
+	Stop(ctx context.Context) error
 }
@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// NonceRepository remembers the nonces of the signed requests of the OAuth clients
+type NonceRepository interface {
+	// Use marks the nonce of the client as used for the TTL and returns false if it already was
+	Use(ctx context.Context, clientID, nonce string, ttl time.Duration) (bool, error)
+}
@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// NotificationPreferencesRepository defines the persistence operations for notification preferences
+type NotificationPreferencesRepository interface {
+	// GetByUserID retrieves the preferences of a user
+	GetByUserID(ctx context.Context, userID string) (*domain.NotificationPreferences, error)
+
+	// Upsert creates or replaces the preferences of a user
+	Upsert(ctx context.Context, prefs *domain.NotificationPreferences) error
+}
@@ -17,7 +17,8 @@ type OAuthClientRepository interface {
 	// GetByID retrieves an OAuth client by ID
 	GetByID(ctx context.Context, id string) (*domain.OAuthClient, error)
 
-	// Update updates an existing OAuth client
+	// Update updates an existing OAuth client and increments its version. It returns ErrVersionConflict
+	// when the client was modified since it was read.
 	Update(ctx context.Context, client *domain.OAuthClient) error
 
 	// Delete soft deletes an OAuth client
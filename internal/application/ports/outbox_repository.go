@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// OutboxRepository stores the messages whose publication failed until they are delivered
+type OutboxRepository interface {
+	// Enqueue stores a message for later delivery
+	Enqueue(ctx context.Context, message *domain.OutboxMessage) error
+
+	// ClaimDue returns up to limit messages due for delivery and hides them from other claims for the lease,
+	// so several instances can relay the outbox concurrently
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*domain.OutboxMessage, error)
+
+	// Delete removes a delivered message
+	Delete(ctx context.Context, id string) error
+
+	// MarkFailed records a failed delivery attempt and schedules the next one
+	MarkFailed(ctx context.Context, id, lastError string, nextAttemptAt time.Time) error
+}
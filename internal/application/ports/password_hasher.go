@@ -0,0 +1,13 @@
+package ports
+
+import "context"
+
+// PasswordHasher defines the password hashing operations
+type PasswordHasher interface {
+	// Hash returns the hash of a plain-text password
+	Hash(ctx context.Context, password string) (string, error)
+
+	// Compare reports whether the password matches the hash.
+	// Mismatches and malformed hashes return false without error.
+	Compare(ctx context.Context, hash, password string) (bool, error)
+}
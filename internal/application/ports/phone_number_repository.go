@@ -0,0 +1,40 @@
+package ports
+
+import (
+	"context"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// PhoneNumberRepository defines the persistence operations for verified phone numbers
+type PhoneNumberRepository interface {
+	// GetByUserID retrieves the verified phone number of a user
+	GetByUserID(ctx context.Context, userID string) (*domain.PhoneNumber, error)
+
+	// GetByNumber retrieves the verified phone number matching number, whoever it belongs to
+	GetByNumber(ctx context.Context, number string) (*domain.PhoneNumber, error)
+
+	// Upsert creates or replaces the phone number of a user.
+	// A number can only belong to one user, ErrPhoneAlreadyRegistered is returned otherwise.
+	Upsert(ctx context.Context, phone *domain.PhoneNumber) error
+
+	// Delete removes the phone number of a user
+	Delete(ctx context.Context, userID string) error
+}
+
+// PhoneVerificationRepository defines the cache operations for pending phone-number verifications
+type PhoneVerificationRepository interface {
+	// Store stores a pending verification until it expires, replacing any previous one of the user
+	Store(ctx context.Context, verification *domain.PhoneVerification) error
+
+	// Consume atomically removes the pending verification of a user and returns it, so a code is checked by
+	// one request at a time. Only the first caller gets it; later callers get ErrInvalidVerificationCode.
+	Consume(ctx context.Context, userID string) (*domain.PhoneVerification, error)
+
+	// Restore stores back a verification taken by Consume until it expires, unless another one was stored
+	// in the meantime
+	Restore(ctx context.Context, verification *domain.PhoneVerification) error
+
+	// Delete removes the pending verification of a user
+	Delete(ctx context.Context, userID string) error
+}
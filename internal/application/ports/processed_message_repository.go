@@ -0,0 +1,12 @@
+package ports
+
+import "context"
+
+// ProcessedMessageRepository tracks the consumed messages so redeliveries are processed only once
+type ProcessedMessageRepository interface {
+	// MarkProcessed marks the message as processed and returns false if it already was
+	MarkProcessed(ctx context.Context, queue, messageID string) (bool, error)
+
+	// Unmark forgets the message so its redelivery is processed again
+	Unmark(ctx context.Context, queue, messageID string) error
+}
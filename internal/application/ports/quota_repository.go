@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// QuotaRepository defines the persistence operations for the issuance quota overrides of clients and users
+type QuotaRepository interface {
+	// Get retrieves the quota override of a subject, ErrQuotaNotFound when the default quota applies
+	Get(ctx context.Context, subjectType, subjectID string) (*domain.IssuanceQuota, error)
+
+	// Upsert creates or replaces the quota override of a subject
+	Upsert(ctx context.Context, quota *domain.IssuanceQuota) error
+
+	// Delete removes the quota override of a subject, ErrQuotaNotFound when there is none
+	Delete(ctx context.Context, subjectType, subjectID string) error
+
+	// List retrieves every quota override
+	List(ctx context.Context) ([]*domain.IssuanceQuota, error)
+}
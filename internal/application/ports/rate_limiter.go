@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// RateLimiter defines the operations of the request rate limiter
+type RateLimiter interface {
+	// Hit records a request for the key and returns the updated status
+	Hit(ctx context.Context, key string) (*domain.RateLimitStatus, error)
+	// Peek returns the current status for the key without recording a request
+	Peek(ctx context.Context, key string) (*domain.RateLimitStatus, error)
+}
@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// RefreshActivityRepository defines the cache operations for the refresh activity of refresh token families
+type RefreshActivityRepository interface {
+	// Record adds a refresh of the family from the IP address, which may be empty, and returns the
+	// activity of the family within the activity window
+	Record(ctx context.Context, familyID, ip string) (*domain.RefreshActivity, error)
+}
@@ -0,0 +1,35 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// RefreshTokenStore defines the durable storage of refresh tokens. Tokens are keyed by their SHA-256
+// hash, so a leak of the storage doesn't expose usable tokens.
+type RefreshTokenStore interface {
+	// Store stores the data of a refresh token until it expires, replacing the data stored for the hash
+	Store(ctx context.Context, tokenHash string, data *domain.RefreshTokenData, expiresAt time.Time) error
+
+	// Get returns the data of a refresh token that has not expired and its expiration.
+	// It returns ErrInvalidToken when there is none.
+	Get(ctx context.Context, tokenHash string) (*domain.RefreshTokenData, time.Time, error)
+
+	// Delete deletes a refresh token, if stored
+	Delete(ctx context.Context, tokenHash string) error
+
+	// Rotate atomically replaces a refresh token with a new one
+	Rotate(ctx context.Context, oldTokenHash, newTokenHash string, data *domain.RefreshTokenData, expiresAt time.Time) error
+
+	// DeleteByUser deletes all refresh tokens of a user, matched by either identifier. userID may be empty
+	// when only the citizen ID is known.
+	DeleteByUser(ctx context.Context, userID string, idCitizen int) error
+
+	// CountActive returns the number of refresh tokens of a user that have not expired
+	CountActive(ctx context.Context, idCitizen int) (int, error)
+
+	// DeleteExpired deletes up to limit refresh tokens expired before the time and returns how many were deleted
+	DeleteExpired(ctx context.Context, before time.Time, limit int) (int, error)
+}
@@ -0,0 +1,28 @@
+package ports
+
+import (
+	"context"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// ScopeRepository defines the interface for OAuth2 scope registry persistence operations
+type ScopeRepository interface {
+	// Create registers a new scope
+	Create(ctx context.Context, scope *domain.Scope) error
+
+	// GetByName retrieves a scope by name
+	GetByName(ctx context.Context, name string) (*domain.Scope, error)
+
+	// GetByNames retrieves the registered scopes among the given names
+	GetByNames(ctx context.Context, names []string) ([]*domain.Scope, error)
+
+	// List retrieves all registered scopes
+	List(ctx context.Context) ([]*domain.Scope, error)
+
+	// Update updates the description of a scope
+	Update(ctx context.Context, scope *domain.Scope) error
+
+	// Delete removes a scope from the registry, returning ErrScopeInUse when it is assigned to a client
+	Delete(ctx context.Context, name string) error
+}
@@ -0,0 +1,12 @@
+package ports
+
+import "context"
+
+// SMSSender defines the operations of an SMS delivery provider
+type SMSSender interface {
+	// Send delivers a text message to a phone number in E.164 format
+	Send(ctx context.Context, phoneNumber, message string) error
+
+	// Provider returns the name of the provider, used in logs and metrics
+	Provider() string
+}
@@ -0,0 +1,27 @@
+package ports
+
+import (
+	"time"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// TokenIssuer defines the operations issuing and validating the tokens of users
+type TokenIssuer interface {
+	// GenerateUserTokenPairWithProfile issues an access and refresh token pair for the user, the access
+	// token carrying the claims of the token profile. With a delegation, the access token is limited to its
+	// client and scopes.
+	GenerateUserTokenPairWithProfile(user *domain.User, profile domain.TokenProfile, delegation *domain.DelegatedAccess) (*domain.TokenPair, error)
+
+	// ValidateAccessToken validates the signature, expiration and type of an access token
+	ValidateAccessToken(token string) (*domain.TokenClaims, error)
+
+	// ValidateRefreshToken validates the signature, expiration and type of a refresh token
+	ValidateRefreshToken(token string) (*domain.TokenClaims, error)
+
+	// GetTokenExpiration returns the expiration time of a token
+	GetTokenExpiration(token string) (time.Time, error)
+
+	// RefreshTokenDuration returns the lifetime of the refresh tokens
+	RefreshTokenDuration() time.Duration
+}
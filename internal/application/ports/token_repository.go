@@ -24,6 +24,29 @@ type TokenRepository interface {
 	// IsTokenBlacklisted verifies if a token is in the blacklist
 	IsTokenBlacklisted(ctx context.Context, token string) (bool, error)
 
-	// DeleteUserTokens deletes all refresh tokens of a user
-	DeleteUserTokens(ctx context.Context, idCitizen int) error
+	// GetActiveRefreshToken retrieves the data of a refresh token that is stored and not blacklisted,
+	// in a single round-trip. It returns ErrTokenRevoked or ErrInvalidToken otherwise.
+	GetActiveRefreshToken(ctx context.Context, token string) (*domain.RefreshTokenData, error)
+
+	// RotateRefreshToken atomically replaces a refresh token with a new one
+	RotateRefreshToken(ctx context.Context, oldToken, newToken string, data *domain.RefreshTokenData, ttl time.Duration) error
+
+	// RevokeSession blacklists an access token for ttl and deletes a refresh token in a single round-trip.
+	// The blacklist is skipped when ttl is not positive and the deletion when refreshToken is empty.
+	RevokeSession(ctx context.Context, accessToken string, ttl time.Duration, refreshToken string) error
+
+	// DeleteUserTokens deletes all refresh tokens of a user, matched by either identifier so tokens issued
+	// before a change of the citizen ID or without a user ID are deleted as well. userID may be empty when
+	// only the citizen ID is known.
+	DeleteUserTokens(ctx context.Context, userID string, idCitizen int) error
+
+	// SetTokenVersion sets the minimum token version of the access tokens of a user for ttl, which must
+	// cover the lifetime of the access tokens issued before
+	SetTokenVersion(ctx context.Context, idCitizen int, version int, ttl time.Duration) error
+
+	// GetTokenVersion returns the minimum token version of the access tokens of a user, 0 when none is set
+	GetTokenVersion(ctx context.Context, idCitizen int) (int, error)
+
+	// CountActiveSessions returns the number of refresh tokens of a user that are still stored
+	CountActiveSessions(ctx context.Context, idCitizen int) (int, error)
 }
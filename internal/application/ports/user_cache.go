@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// UserCache defines the operations of the short-lived cache of user lookups
+type UserCache interface {
+	// Get returns the cached user with the citizen ID, nil when it is not cached
+	Get(ctx context.Context, idCitizen int) (*domain.UserPublic, error)
+
+	// Set caches the user until the cache TTL expires
+	Set(ctx context.Context, user *domain.UserPublic) error
+
+	// Delete removes the cached user with the citizen ID, if any
+	Delete(ctx context.Context, idCitizen int) error
+}
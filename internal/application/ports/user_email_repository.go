@@ -0,0 +1,43 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// UserEmailRepository defines the persistence operations for the secondary email addresses of users
+type UserEmailRepository interface {
+	// Create adds an address to a user, returning ErrEmailAlreadyRegistered when the user already has it
+	Create(ctx context.Context, email *domain.UserEmail) error
+
+	// GetByID retrieves an address of a user
+	GetByID(ctx context.Context, userID, id string) (*domain.UserEmail, error)
+
+	// ListByUserID retrieves the addresses of a user, oldest first
+	ListByUserID(ctx context.Context, userID string) ([]*domain.UserEmail, error)
+
+	// GetVerified retrieves the verified address matching email, whoever it belongs to
+	GetVerified(ctx context.Context, email string) (*domain.UserEmail, error)
+
+	// Update saves the verification state of an address, returning ErrEmailAlreadyRegistered when another
+	// user verified the same address first
+	Update(ctx context.Context, email *domain.UserEmail) error
+
+	// Delete removes an address of a user
+	Delete(ctx context.Context, userID, id string) error
+
+	// DeleteByUserID removes every address of a user
+	DeleteByUserID(ctx context.Context, userID string) error
+}
+
+// PasswordResetRepository defines the cache operations for pending password resets
+type PasswordResetRepository interface {
+	// Store stores a pending reset under the hash of its token until it expires
+	Store(ctx context.Context, tokenHash string, reset *domain.PasswordReset, ttl time.Duration) error
+
+	// Consume retrieves and deletes a pending reset, so its token can only be used once.
+	// It returns ErrInvalidResetToken when the token is unknown or expired.
+	Consume(ctx context.Context, tokenHash string) (*domain.PasswordReset, error)
+}
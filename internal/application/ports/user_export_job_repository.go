@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// UserExportJobRepository defines the cache operations for the personal data exports requested by users
+type UserExportJobRepository interface {
+	// Store stores a new job until it expires, as the latest job of its user
+	Store(ctx context.Context, job *domain.UserExportJob) error
+
+	// Get retrieves a job by its ID, returning ErrExportJobNotFound when it is unknown or expired
+	Get(ctx context.Context, id string) (*domain.UserExportJob, error)
+
+	// GetLatestByUser retrieves the latest job of a user, returning ErrExportJobNotFound when there is none
+	GetLatestByUser(ctx context.Context, userID string) (*domain.UserExportJob, error)
+
+	// Update replaces a job keeping its expiration
+	Update(ctx context.Context, job *domain.UserExportJob) error
+}
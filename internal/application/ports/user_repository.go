@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
 )
@@ -20,12 +21,22 @@ type UserRepository interface {
 	// GetByIDCitizen retrieves a user by their citizen ID
 	GetByIDCitizen(ctx context.Context, idCitizen int) (*domain.User, error)
 
-	// Update updates an existing user
+	// GetByPendingEmailToken retrieves the user with a pending email change confirmed by the token hash
+	GetByPendingEmailToken(ctx context.Context, tokenHash string) (*domain.User, error)
+
+	// Update updates an existing user and increments its version. It returns ErrVersionConflict when the
+	// user was modified since it was read.
 	Update(ctx context.Context, user *domain.User) error
 
+	// RecordLogin records the time of a successful login of a user, without changing its version
+	RecordLogin(ctx context.Context, id string, at time.Time) error
+
 	// Delete deletes a user (soft 	delete)
 	Delete(ctx context.Context, id string) error
 
 	// Exists verifies if a user exists by email
 	Exists(ctx context.Context, email string) (bool, error)
+
+	// ListByStatus retrieves a page of the users in a status, oldest first
+	ListByStatus(ctx context.Context, status domain.UserStatus, limit, offset int) ([]*domain.User, error)
 }
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	"github.com/kristianrpo/auth-microservice/internal/domain/events"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// AnonymizationServiceInterface defines the methods of AnonymizationService used by handlers and the CLI.
+type AnonymizationServiceInterface interface {
+	AnonymizeUser(ctx context.Context, userID, actor string) (*domain.UserPublic, error)
+}
+
+// AnonymizationService erases the personal data of users (GDPR right to erasure).
+// The user row is kept, scrubbed, so consents and other references stay valid.
+type AnonymizationService struct {
+	userRepo            ports.UserRepository
+	tokenRepo           ports.TokenRepository
+	phoneRepo           ports.PhoneNumberRepository
+	emailRepo           ports.UserEmailRepository
+	avatarRepo          ports.AvatarRepository
+	auditRepo           ports.AuditLogRepository
+	publisher           ports.MessagePublisher
+	userAnonymizedQueue string
+	logger              *zap.Logger
+}
+
+// NewAnonymizationService creates a new instance of AnonymizationService
+func NewAnonymizationService(
+	userRepo ports.UserRepository,
+	tokenRepo ports.TokenRepository,
+	phoneRepo ports.PhoneNumberRepository,
+	emailRepo ports.UserEmailRepository,
+	avatarRepo ports.AvatarRepository,
+	auditRepo ports.AuditLogRepository,
+	publisher ports.MessagePublisher,
+	userAnonymizedQueue string,
+	logger *zap.Logger,
+) *AnonymizationService {
+	return &AnonymizationService{
+		userRepo:            userRepo,
+		tokenRepo:           tokenRepo,
+		phoneRepo:           phoneRepo,
+		emailRepo:           emailRepo,
+		avatarRepo:          avatarRepo,
+		auditRepo:           auditRepo,
+		publisher:           publisher,
+		userAnonymizedQueue: userAnonymizedQueue,
+		logger:              logger,
+	}
+}
+
+// AnonymizeUser scrubs the personal data of a user, revokes their sessions, writes an audit record
+// and publishes a user.anonymized event. actor identifies who requested the erasure.
+func (s *AnonymizationService) AnonymizeUser(ctx context.Context, userID, actor string) (*domain.UserPublic, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrUserNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to get user", zap.Error(err), zap.String("user_id", userID))
+		return nil, internalError(err)
+	}
+
+	if user.IsAnonymized() {
+		return nil, domainerrors.ErrUserAlreadyAnonymized
+	}
+
+	// The phone number, secondary emails and avatar are erased first so a failure leaves the user untouched
+	// and the erasure can be retried. The avatar image is removed from storage by the orphan cleanup.
+	if err := s.phoneRepo.Delete(ctx, user.ID); err != nil && !errors.Is(err, domainerrors.ErrPhoneNotFound) {
+		s.logger.Error("failed to delete phone number", zap.Error(err), zap.String("user_id", user.ID))
+		return nil, internalError(err)
+	}
+	if err := s.emailRepo.DeleteByUserID(ctx, user.ID); err != nil {
+		s.logger.Error("failed to delete user emails", zap.Error(err), zap.String("user_id", user.ID))
+		return nil, internalError(err)
+	}
+	if err := s.avatarRepo.DeleteByUserID(ctx, user.ID); err != nil {
+		s.logger.Error("failed to delete avatar", zap.Error(err), zap.String("user_id", user.ID))
+		return nil, internalError(err)
+	}
+
+	user.Anonymize()
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		if errors.Is(err, domainerrors.ErrUserNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to anonymize user", zap.Error(err), zap.String("user_id", user.ID))
+		return nil, internalError(err)
+	}
+
+	// Best effort: refreshes are rejected anyway because the user is no longer active
+	if err := s.tokenRepo.DeleteUserTokens(ctx, user.ID, user.IDCitizen); err != nil {
+		s.logger.Error("failed to revoke tokens of anonymized user", zap.Error(err), zap.String("user_id", user.ID))
+	}
+
+	record := domain.NewAuditRecord(domain.AuditActionUserAnonymized, actor, user.ID, map[string]string{
+		"id_citizen": strconv.Itoa(user.IDCitizen),
+	})
+	if err := s.auditRepo.Record(ctx, record); err != nil {
+		s.logger.Error("failed to write audit record", zap.Error(err), zap.String("user_id", user.ID), zap.String("actor", actor))
+	}
+
+	s.publishUserAnonymized(ctx, user)
+
+	s.logger.Info("user anonymized", zap.String("user_id", user.ID), zap.String("actor", actor))
+	return user.ToPublic(), nil
+}
+
+// publishUserAnonymized publishes the user.anonymized event (best effort)
+func (s *AnonymizationService) publishUserAnonymized(ctx context.Context, user *domain.User) {
+	event := events.NewUserAnonymizedEvent(user.ID, user.IDCitizen)
+	eventData, err := event.ToJSON()
+	if err != nil {
+		s.logger.Error("failed to serialize user anonymized event", zap.Error(err))
+		return
+	}
+
+	if err := s.publisher.Publish(ctx, s.userAnonymizedQueue, eventData); err != nil {
+		s.logger.Error("failed to publish user anonymized event", zap.Error(err), zap.String("user_id", user.ID))
+		return
+	}
+
+	s.logger.Info("user anonymized event published", zap.String("message_id", event.MessageID), zap.String("queue", s.userAnonymizedQueue))
+}
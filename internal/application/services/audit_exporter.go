@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
+	"github.com/kristianrpo/auth-microservice/internal/retry"
+)
+
+// auditExportClaimLease is how long claimed records stay hidden from other exporters, it must exceed
+// the time taken to send a batch
+const auditExportClaimLease = 2 * time.Minute
+
+// AuditExportPolicy controls how the audit log is exported
+type AuditExportPolicy struct {
+	PollInterval   time.Duration
+	BatchSize      int
+	InitialBackoff time.Duration // delay before retrying after the first failed batch
+	MaxBackoff     time.Duration
+}
+
+// AuditExporter streams the audit log to an external sink (syslog server, SIEM HTTP collector).
+// PostgreSQL stays the source of truth: records are marked exported only once the sink accepted them,
+// so every record is delivered at least once, and a slow or unavailable sink only delays the export
+// without blocking the requests that write the audit log.
+type AuditExporter struct {
+	sink       ports.AuditSink
+	exportRepo ports.AuditExportRepository
+	policy     AuditExportPolicy
+	logger     *zap.Logger
+
+	wake     chan struct{}
+	failures int
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewAuditExporter creates a new instance of AuditExporter
+func NewAuditExporter(sink ports.AuditSink, exportRepo ports.AuditExportRepository, policy AuditExportPolicy, logger *zap.Logger) *AuditExporter {
+	return &AuditExporter{
+		sink:       sink,
+		exportRepo: exportRepo,
+		policy:     policy,
+		logger:     logger,
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+// Notify wakes the exporter up so new records are exported without waiting for the next poll.
+// It never blocks: notifications received while the exporter is busy are coalesced.
+func (e *AuditExporter) Notify() {
+	select {
+	case e.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run exports the audit log every poll interval, and when notified, until the context is cancelled
+func (e *AuditExporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.policy.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-e.wake:
+		}
+
+		if _, err := e.ExportPending(ctx); err != nil && ctx.Err() == nil {
+			e.logger.Error("failed to export audit log", zap.Error(err), zap.String("sink", e.sink.Name()))
+		}
+	}
+}
+
+// Start runs the exporter in the background, it implements Component
+func (e *AuditExporter) Start(ctx context.Context) error {
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	e.cancel = cancel
+	e.done = make(chan struct{})
+	go func() {
+		defer close(e.done)
+		e.Run(runCtx)
+	}()
+	return nil
+}
+
+// Stop stops the background exporter and waits for the batch in progress, until the context is done
+func (e *AuditExporter) Stop(ctx context.Context) error {
+	if e.cancel == nil {
+		return nil
+	}
+	e.cancel()
+	select {
+	case <-e.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ExportPending sends the records due for export in batches until none is left, and returns how many
+// were exported. A failed batch is rescheduled with exponential backoff and stops the export.
+func (e *AuditExporter) ExportPending(ctx context.Context) (int, error) {
+	defer e.observeBacklog(ctx)
+
+	exported := 0
+	for ctx.Err() == nil {
+		records, err := e.exportRepo.ClaimUnexported(ctx, e.policy.BatchSize, auditExportClaimLease)
+		if err != nil {
+			return exported, err
+		}
+		if len(records) == 0 {
+			break
+		}
+
+		ids := recordIDs(records)
+		if err := e.sink.Send(ctx, records); err != nil {
+			metrics.IncAuditExportRecords(e.sink.Name(), "failed", len(records))
+			nextAttemptAt := time.Now().Add(e.backoff(e.failures))
+			e.failures++
+			e.logger.Warn("failed to export audit records",
+				zap.String("sink", e.sink.Name()),
+				zap.Int("records", len(records)),
+				zap.Int("consecutive_failures", e.failures),
+				zap.Time("next_attempt_at", nextAttemptAt),
+				zap.Error(err),
+			)
+			if err := e.exportRepo.MarkExportFailed(ctx, ids, nextAttemptAt); err != nil {
+				e.logger.Error("failed to reschedule audit records export", zap.Error(err))
+			}
+			return exported, err
+		}
+
+		e.failures = 0
+		metrics.IncAuditExportRecords(e.sink.Name(), "exported", len(records))
+		exported += len(records)
+		if err := e.exportRepo.MarkExported(ctx, ids); err != nil {
+			// The records will be exported again once the claim lease expires
+			e.logger.Error("failed to mark audit records as exported", zap.Error(err))
+		}
+
+		if len(records) < e.policy.BatchSize {
+			break
+		}
+	}
+
+	if exported > 0 {
+		e.logger.Debug("audit records exported", zap.String("sink", e.sink.Name()), zap.Int("exported", exported))
+	}
+	return exported, nil
+}
+
+// observeBacklog reports the number of records waiting for export
+func (e *AuditExporter) observeBacklog(ctx context.Context) {
+	if ctx.Err() != nil {
+		return
+	}
+	backlog, err := e.exportRepo.CountUnexported(ctx)
+	if err != nil {
+		return
+	}
+	metrics.SetAuditExportBacklog(backlog)
+}
+
+// backoff returns the delay before the next export after the given number of consecutive failures
+func (e *AuditExporter) backoff(failures int) time.Duration {
+	return retry.Policy{InitialBackoff: e.policy.InitialBackoff, MaxBackoff: e.policy.MaxBackoff}.Backoff(failures + 1)
+}
+
+func recordIDs(records []*domain.AuditRecord) []string {
+	ids := make([]string, 0, len(records))
+	for _, record := range records {
+		ids = append(ids, record.ID)
+	}
+	return ids
+}
+
+// ExportingAuditLog is an AuditLogRepository that wakes the AuditExporter up after every record,
+// so the records reach the external sink in near real time
+type ExportingAuditLog struct {
+	auditRepo ports.AuditLogRepository
+	exporter  *AuditExporter
+}
+
+// NewExportingAuditLog creates a new instance of ExportingAuditLog
+func NewExportingAuditLog(auditRepo ports.AuditLogRepository, exporter *AuditExporter) *ExportingAuditLog {
+	return &ExportingAuditLog{
+		auditRepo: auditRepo,
+		exporter:  exporter,
+	}
+}
+
+// Record appends the record to the audit log and notifies the exporter
+func (l *ExportingAuditLog) Record(ctx context.Context, record *domain.AuditRecord) error {
+	if err := l.auditRepo.Record(ctx, record); err != nil {
+		return err
+	}
+	l.exporter.Notify()
+	return nil
+}
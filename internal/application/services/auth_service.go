@@ -6,12 +6,14 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 
 	"github.com/kristianrpo/auth-microservice/internal/application/ports"
 	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
 	"github.com/kristianrpo/auth-microservice/internal/domain/events"
 	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
 	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
 )
 
@@ -20,9 +22,12 @@ import (
 type AuthServiceInterface interface {
 	Register(ctx context.Context, email, password, name string, idCitizen int) (*domain.UserPublic, error)
 	Login(ctx context.Context, email, password string) (*domain.TokenPair, error)
+	LoginWithUser(ctx context.Context, email, password string) (*domain.TokenPair, *domain.UserPublic, error)
+	LoginForClient(ctx context.Context, email, password string, profile domain.TokenProfile) (*domain.TokenPair, error)
 	RefreshToken(ctx context.Context, refreshToken string) (*domain.TokenPair, error)
 	Logout(ctx context.Context, accessToken, refreshToken string) error
 	GetUserByIDCitizen(ctx context.Context, idCitizen int) (*domain.UserPublic, error)
+	GetAccountActivity(ctx context.Context, idCitizen int) (*domain.AccountActivity, error)
 	ValidateAccessToken(ctx context.Context, token string) (*domain.TokenClaims, error)
 	RevokeAllUserTokens(ctx context.Context, idCitizen int) error
 }
@@ -31,51 +36,127 @@ type AuthServiceInterface interface {
 type AuthService struct {
 	userRepo                    ports.UserRepository
 	tokenRepo                   ports.TokenRepository
-	jwtService                  *JWTService
+	tokenIssuer                 ports.TokenIssuer
 	publisher                   ports.MessagePublisher
 	externalConnectivityClient  ports.ExternalConnectivityClient
 	userRegisteredQueue         string
+	passwordHasher              ports.PasswordHasher
+	riskEngine                  RiskEngine
+	quotaEnforcer               QuotaEnforcer
+	userCache                   ports.UserCache
+	requireApproval             bool // new registrations wait for an administrator before they can sign in
+	nameLimits                  domain.NameLimits
+	now                         func() time.Time
 	logger                      *zap.Logger
 }
 
-// NewAuthService creates a new instance of AuthService
+// AuthServiceOption configures an optional dependency of AuthService, so new dependencies don't change the
+// signature of NewAuthService
+type AuthServiceOption func(*AuthService)
+
+// WithPublisher publishes the user registered events to the queue. Without a publisher no event is published.
+func WithPublisher(publisher ports.MessagePublisher, userRegisteredQueue string) AuthServiceOption {
+	return func(s *AuthService) {
+		s.publisher = publisher
+		s.userRegisteredQueue = userRegisteredQueue
+	}
+}
+
+// WithConnectivityClient rejects the registration of citizens already registered in the centralizer.
+// Without a client the centralizer is not checked.
+func WithConnectivityClient(client ports.ExternalConnectivityClient) AuthServiceOption {
+	return func(s *AuthService) {
+		s.externalConnectivityClient = client
+	}
+}
+
+// WithRiskEngine assesses the risk of logins and refreshes. Without an engine every authentication is allowed.
+func WithRiskEngine(riskEngine RiskEngine) AuthServiceOption {
+	return func(s *AuthService) {
+		s.riskEngine = riskEngine
+	}
+}
+
+// WithQuotaEnforcer enforces the token issuance quotas of the users
+func WithQuotaEnforcer(quotaEnforcer QuotaEnforcer) AuthServiceOption {
+	return func(s *AuthService) {
+		s.quotaEnforcer = quotaEnforcer
+	}
+}
+
+// WithUserCache caches the users looked up by ID citizen
+func WithUserCache(userCache ports.UserCache) AuthServiceOption {
+	return func(s *AuthService) {
+		s.userCache = userCache
+	}
+}
+
+// WithRegistrationApproval makes new registrations wait for an administrator before they can sign in
+func WithRegistrationApproval(requireApproval bool) AuthServiceOption {
+	return func(s *AuthService) {
+		s.requireApproval = requireApproval
+	}
+}
+
+// WithNameLimits sets the length limits of the names of new users, domain.DefaultNameLimits by default
+func WithNameLimits(nameLimits domain.NameLimits) AuthServiceOption {
+	return func(s *AuthService) {
+		s.nameLimits = nameLimits
+	}
+}
+
+// WithClock sets the clock of the logins and of the sessions, time.Now by default
+func WithClock(now func() time.Time) AuthServiceOption {
+	return func(s *AuthService) {
+		s.now = now
+	}
+}
+
+// NewAuthService creates a new instance of AuthService with its required dependencies, the optional ones
+// are set with options
 func NewAuthService(
 	userRepo ports.UserRepository,
 	tokenRepo ports.TokenRepository,
-	jwtService *JWTService,
-	publisher ports.MessagePublisher,
-	externalConnectivityClient ports.ExternalConnectivityClient,
-	userRegisteredQueue string,
+	tokenIssuer ports.TokenIssuer,
+	passwordHasher ports.PasswordHasher,
 	logger *zap.Logger,
+	opts ...AuthServiceOption,
 ) *AuthService {
-	return &AuthService{
-		userRepo:                   userRepo,
-		tokenRepo:                  tokenRepo,
-		jwtService:                 jwtService,
-		publisher:                  publisher,
-		externalConnectivityClient: externalConnectivityClient,
-		userRegisteredQueue:        userRegisteredQueue,
-		logger:                     logger,
+	s := &AuthService{
+		userRepo:       userRepo,
+		tokenRepo:      tokenRepo,
+		tokenIssuer:    tokenIssuer,
+		passwordHasher: passwordHasher,
+		nameLimits:     domain.DefaultNameLimits(),
+		now:            time.Now,
+		logger:         logger,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // Register registers a new user
 func (s *AuthService) Register(ctx context.Context, email, password, name string, idCitizen int) (*domain.UserPublic, error) {
-	s.logger.Info("attempting to register user", zap.String("email", email), zap.Int("id_citizen", idCitizen))
+	s.logger.Info("attempting to register user", logging.String("email", email), zap.Int("id_citizen", idCitizen))
 
 	// Check if citizen exists in centralizer via external-connectivity service
-	citizenExists, err := s.externalConnectivityClient.CheckCitizenExists(ctx, idCitizen)
+	citizenExists, err := s.checkCitizenExists(ctx, idCitizen)
 	if err != nil {
 		s.logger.Error("failed to check citizen in centralizer",
 			zap.Error(err),
 			zap.Int("id_citizen", idCitizen))
+		if errors.Is(err, domainerrors.ErrCentralizerBusy) {
+			return nil, err
+		}
 		return nil, domainerrors.ErrInternal
 	}
 
 	if citizenExists {
 		s.logger.Warn("citizen already exists in centralizer, registration not allowed",
 			zap.Int("id_citizen", idCitizen),
-			zap.String("email", email))
+			logging.String("email", email))
 		return nil, domainerrors.ErrCitizenExistsInCentralizer
 	}
 
@@ -83,11 +164,11 @@ func (s *AuthService) Register(ctx context.Context, email, password, name string
 	exists, err := s.userRepo.Exists(ctx, email)
 	if err != nil {
 		s.logger.Error("failed to check user existence", zap.Error(err))
-		return nil, domainerrors.ErrInternal
+		return nil, internalError(err)
 	}
 
 	if exists {
-		s.logger.Warn("user already exists", zap.String("email", email))
+		s.logger.Warn("user already exists", logging.String("email", email))
 		return nil, domainerrors.ErrUserAlreadyExists
 	}
 
@@ -98,16 +179,21 @@ func (s *AuthService) Register(ctx context.Context, email, password, name string
 			return nil, domainerrors.ErrUserAlreadyExists
 		} else if err != nil && err != domainerrors.ErrUserNotFound {
 			s.logger.Error("failed to check user by id_citizen", zap.Error(err), zap.Int("id_citizen", idCitizen))
-			return nil, domainerrors.ErrInternal
+			return nil, internalError(err)
 		}
 	}
 
 	// Create new user
-	user, err := domain.NewUser(email, password, name, idCitizen)
+	user, err := domain.NewUserWithHasher(email, password, name, idCitizen, s.nameLimits, func(password string) (string, error) {
+		return s.passwordHasher.Hash(ctx, password)
+	})
 	if err != nil {
 		s.logger.Error("failed to create user entity", zap.Error(err))
 		return nil, err
 	}
+	if s.requireApproval {
+		user.Status = domain.UserStatusPendingApproval
+	}
 
 	// Save user to database
 	if err := s.userRepo.Create(ctx, user); err != nil {
@@ -118,13 +204,15 @@ func (s *AuthService) Register(ctx context.Context, email, password, name string
 		}
 
 		s.logger.Error("failed to save user", zap.Error(err))
-		return nil, domainerrors.ErrInternal
+		return nil, internalError(err)
 	}
 
 	// Publish user registered event to RabbitMQ
-	event := events.NewUserRegisteredEvent(user.IDCitizen, user.Name, user.Email)
+	event := events.NewUserRegisteredEvent(user)
 	eventData, err := event.ToJSON()
-	if err != nil {
+	if s.publisher == nil {
+		s.logger.Debug("user registered event not published: no publisher configured")
+	} else if err != nil {
 		s.logger.Error("failed to serialize user registered event", zap.Error(err))
 		// Don't fail the registration if event publishing fails
 	} else {
@@ -136,36 +224,206 @@ func (s *AuthService) Register(ctx context.Context, email, password, name string
 		}
 	}
 
-	s.logger.Info("user registered successfully", zap.String("user_id", user.ID), zap.String("email", email), zap.Int("id_citizen", idCitizen))
+	s.logger.Info("user registered successfully", zap.String("user_id", user.ID), logging.String("email", email), zap.Int("id_citizen", idCitizen), zap.String("status", user.Status.String()))
 	return user.ToPublic(), nil
 }
 
+// checkCitizenExists checks if the citizen is registered in the centralizer, never when no connectivity
+// client is configured
+func (s *AuthService) checkCitizenExists(ctx context.Context, idCitizen int) (bool, error) {
+	if s.externalConnectivityClient == nil {
+		return false, nil
+	}
+	return s.externalConnectivityClient.CheckCitizenExists(ctx, idCitizen)
+}
+
 // Login authenticates a user and generates tokens
 func (s *AuthService) Login(ctx context.Context, email, password string) (*domain.TokenPair, error) {
-	s.logger.Info("attempting login", zap.String("email", email))
+	tokenPair, _, err := s.login(ctx, email, password, domain.TokenProfileStandard)
+	return tokenPair, err
+}
+
+// LoginForClient authenticates a user on behalf of an OAuth2 client and generates tokens with the
+// token profile of the client
+func (s *AuthService) LoginForClient(ctx context.Context, email, password string, profile domain.TokenProfile) (*domain.TokenPair, error) {
+	tokenPair, _, err := s.login(ctx, email, password, profile)
+	return tokenPair, err
+}
+
+// LoginWithUser authenticates a user and generates tokens, returning the user along with them
+func (s *AuthService) LoginWithUser(ctx context.Context, email, password string) (*domain.TokenPair, *domain.UserPublic, error) {
+	tokenPair, user, err := s.login(ctx, email, password, domain.TokenProfileStandard)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tokenPair, user.ToPublic(), nil
+}
+
+// login authenticates a user and generates tokens with the given token profile
+func (s *AuthService) login(ctx context.Context, email, password string, profile domain.TokenProfile) (*domain.TokenPair, *domain.User, error) {
+	s.logger.Info("attempting login", logging.String("email", email))
 
 	// Get user by email
 	user, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
 		if err == domainerrors.ErrUserNotFound {
-			s.logger.Warn("login failed: user not found", zap.String("email", email))
-			return nil, domainerrors.ErrInvalidCredentials
+			s.logger.Warn("login failed: user not found", logging.String("email", email))
+			if s.riskEngine != nil {
+				s.riskEngine.RecordLoginFailure(ctx, email, nil)
+			}
+			return nil, nil, domainerrors.ErrInvalidCredentials
 		}
 		s.logger.Error("failed to get user", zap.Error(err))
-		return nil, domainerrors.ErrInternal
+		return nil, nil, internalError(err)
+	}
+
+	// Service accounts have no password, telling them apart would reveal the account exists
+	if user.IsServiceAccount() {
+		s.logger.Warn("login failed: service accounts can't sign in with a password", zap.String("user_id", user.ID))
+		return nil, nil, domainerrors.ErrInvalidCredentials
 	}
 
 	// Verify password
-	if err := user.ComparePassword(password); err != nil {
-		s.logger.Warn("login failed: invalid password", zap.String("email", email))
-		return nil, domainerrors.ErrInvalidCredentials
+	match, err := s.passwordHasher.Compare(ctx, user.Password, password)
+	if err != nil {
+		s.logger.Error("failed to compare password", zap.Error(err))
+		return nil, nil, internalError(err)
+	}
+	if !match {
+		s.logger.Warn("login failed: invalid password", logging.String("email", email))
+		if s.riskEngine != nil {
+			s.riskEngine.RecordLoginFailure(ctx, email, user)
+		}
+		return nil, nil, domainerrors.ErrInvalidCredentials
+	}
+
+	tokenPair, err := s.completeLogin(ctx, user, profile)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tokenPair, user, nil
+}
+
+// LoginVerifiedUser generates tokens for a user who proved their identity with another factor than the
+// password (e.g. a one-time code sent by SMS), with the same account status and risk checks as a login
+func (s *AuthService) LoginVerifiedUser(ctx context.Context, user *domain.User) (*domain.TokenPair, *domain.UserPublic, error) {
+	s.logger.Info("attempting login with a verified factor", zap.String("user_id", user.ID))
+
+	if user.IsServiceAccount() {
+		s.logger.Warn("login failed: service accounts can't sign in interactively", zap.String("user_id", user.ID))
+		return nil, nil, domainerrors.ErrInvalidCredentials
+	}
+
+	tokenPair, err := s.completeLogin(ctx, user, domain.TokenProfileStandard)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tokenPair, user.ToPublic(), nil
+}
+
+// completeLogin checks the account status and the risk policy of an authenticated user and generates tokens
+func (s *AuthService) completeLogin(ctx context.Context, user *domain.User, profile domain.TokenProfile) (*domain.TokenPair, error) {
+	risk, err := s.authorizeSession(ctx, user)
+	if err != nil {
+		// A locked out account answers like a wrong password, so guesses during the lockout can't tell
+		// whether they were right
+		if risk != nil && risk.LockedOut {
+			return nil, domainerrors.ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	tokenPair, err := s.issueTokenPair(ctx, user, risk, profile, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// The session is issued even when the time of the login can't be recorded
+	if err := s.userRepo.RecordLogin(ctx, user.ID, s.now()); err != nil {
+		s.logger.Warn("failed to record login", zap.Error(err), zap.String("user_id", user.ID))
+	}
+
+	s.logger.Info("login successful", zap.String("user_id", user.ID), zap.Bool("step_up_required", risk.RequiresStepUp()))
+	return tokenPair, nil
+}
+
+// authorizeSession checks that an authenticated user may open a session: the account is active, has no
+// temporary password to replace and the risk policy does not deny it. It returns the risk assessment, also
+// when the risk policy denies the session.
+func (s *AuthService) authorizeSession(ctx context.Context, user *domain.User) (*domain.RiskAssessment, error) {
+	if !user.IsActive() {
+		s.logger.Warn("login failed: user is not active", zap.String("user_id", user.ID), zap.String("status", user.Status.String()))
+		switch user.Status {
+		case domain.UserStatusPendingApproval:
+			return nil, domainerrors.ErrUserPendingApproval
+		case domain.UserStatusRejected:
+			return nil, domainerrors.ErrUserRejected
+		default:
+			return nil, domainerrors.ErrUserSuspended
+		}
+	}
+
+	// Users created by an administrator replace their temporary password before their first session
+	if user.MustChangePassword {
+		s.logger.Warn("login failed: user must change their temporary password", zap.String("user_id", user.ID))
+		return nil, domainerrors.ErrPasswordChangeRequired
+	}
+
+	// Risky logins are denied or their session is marked for step-up, as decided by the risk policy
+	if s.riskEngine == nil {
+		return nil, nil
+	}
+	risk := s.riskEngine.AssessLogin(ctx, user)
+	if risk.LockedOut {
+		s.logger.Warn("login failed: locked out by failed logins", zap.String("user_id", user.ID), zap.Strings("rules", risk.Rules))
+		return risk, domainerrors.ErrAuthenticationDenied
+	}
+	if risk.Decision == domain.RiskDecisionDeny {
+		s.logger.Warn("login failed: denied by risk policy", zap.String("user_id", user.ID), zap.Strings("rules", risk.Rules))
+		return risk, domainerrors.ErrAuthenticationDenied
+	}
+	return risk, nil
+}
+
+// IssueTokenPair generates and stores a token pair for an already authenticated user.
+// It is used by grants where the user proved their identity out of band (e.g. device authorization), so
+// the account status and the risk policy are checked as at login, without recording a login. The access
+// token carries the claims of the token profile of the client and is limited to the delegation, also
+// kept by the refreshes of the session.
+func (s *AuthService) IssueTokenPair(ctx context.Context, idCitizen int, profile domain.TokenProfile, delegation *domain.DelegatedAccess) (*domain.TokenPair, error) {
+	user, err := s.userRepo.GetByIDCitizen(ctx, idCitizen)
+	if err != nil {
+		s.logger.Error("failed to get user", zap.Error(err), zap.Int("id_citizen", idCitizen))
+		return nil, err
+	}
+
+	if user.IsServiceAccount() {
+		s.logger.Warn("token pair denied: service accounts can't sign in interactively", zap.String("user_id", user.ID))
+		return nil, domainerrors.ErrInvalidGrant
+	}
+
+	risk, err := s.authorizeSession(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return s.issueTokenPair(ctx, user, risk, profile, delegation)
+}
+
+// issueTokenPair generates a token pair for the user and stores the refresh token along with the risk assessment,
+// the token profile and the delegation
+func (s *AuthService) issueTokenPair(ctx context.Context, user *domain.User, risk *domain.RiskAssessment, profile domain.TokenProfile, delegation *domain.DelegatedAccess) (*domain.TokenPair, error) {
+	// Every token pair opens a new session
+	if s.quotaEnforcer != nil {
+		if err := s.quotaEnforcer.EnforceUserIssuance(ctx, user, true); err != nil {
+			return nil, err
+		}
 	}
 
 	// Generate token pair
-	tokenPair, err := s.jwtService.GenerateTokenPair(user.IDCitizen, user.Email, user.Role)
+	tokenPair, err := s.tokenIssuer.GenerateUserTokenPairWithProfile(user, profile, delegation)
 	if err != nil {
 		s.logger.Error("failed to generate token pair", zap.Error(err))
-		return nil, domainerrors.ErrInternal
+		return nil, internalError(err)
 	}
 
 	metrics.AddJWTTokensGenerated(2)
@@ -173,23 +431,30 @@ func (s *AuthService) Login(ctx context.Context, email, password string) (*domai
 	// Store refresh token in Redis
 	refreshTokenData := &domain.RefreshTokenData{
 		IDCitizen: user.IDCitizen,
+		UserID:    user.ID,
 		Email:     user.Email,
-		IssuedAt:  time.Now(),
-		ExpiresAt: time.Now().Add(s.jwtService.refreshTokenDuration),
+		Role:      user.Role,
+		FamilyID:  uuid.New().String(),
+		IssuedAt:  s.now(),
+		ExpiresAt: s.now().Add(s.tokenIssuer.RefreshTokenDuration()),
+		Risk:      risk,
+
+		TokenProfile: profile,
+		TokenVersion: user.TokenVersion,
+		Delegation:   delegation,
 	}
 
 	err = s.tokenRepo.StoreRefreshToken(
 		ctx,
 		tokenPair.RefreshToken,
 		refreshTokenData,
-		s.jwtService.refreshTokenDuration,
+		s.tokenIssuer.RefreshTokenDuration(),
 	)
 	if err != nil {
 		s.logger.Error("failed to store refresh token", zap.Error(err))
-		// No retornamos error aquí, el login fue exitoso
+		// No retornamos error aquí, los tokens fueron emitidos
 	}
 
-	s.logger.Info("login successful", zap.String("user_id", user.ID))
 	return tokenPair, nil
 }
 
@@ -197,103 +462,202 @@ func (s *AuthService) Login(ctx context.Context, email, password string) (*domai
 func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*domain.TokenPair, error) {
 	s.logger.Debug("attempting to refresh token")
 
-	// Validate refresh token
-	claims, err := s.jwtService.ValidateRefreshToken(refreshToken)
+	// Validate JWT refresh tokens, opaque refresh tokens are only known by their stored data
+	var claims *domain.TokenClaims
+	if !domain.IsOpaqueRefreshToken(refreshToken) {
+		var err error
+		claims, err = s.tokenIssuer.ValidateRefreshToken(refreshToken)
+		if err != nil {
+			s.logger.Warn("invalid refresh token", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	// Verify the refresh token exists in Redis and is not blacklisted
+	storedData, err := s.tokenRepo.GetActiveRefreshToken(ctx, refreshToken)
 	if err != nil {
-		s.logger.Warn("invalid refresh token", zap.Error(err))
-		return nil, err
+		switch {
+		case errors.Is(err, domainerrors.ErrTokenRevoked):
+			s.logger.Warn("refresh token is blacklisted")
+			return nil, domainerrors.ErrTokenRevoked
+		case errors.Is(err, domainerrors.ErrInvalidToken):
+			s.logger.Warn("refresh token not found in cache", zap.Error(err))
+			return nil, domainerrors.ErrInvalidToken
+		default:
+			s.logger.Error("failed to get refresh token", zap.Error(err))
+			return nil, internalError(err)
+		}
 	}
 
-	// Verify if the token is in the blacklist
-	blacklisted, err := s.tokenRepo.IsTokenBlacklisted(ctx, refreshToken)
+	// The claims of an opaque refresh token are the stored ones
+	if claims == nil {
+		if storedData.IsExpired(s.now()) {
+			s.revokeRefreshToken(ctx, refreshToken)
+			return nil, domainerrors.ErrExpiredToken
+		}
+		claims = storedData.Claims()
+	}
+
+	// Reload the user so that role changes, suspensions and deletions apply on the next refresh
+	user, err := s.userRepo.GetByIDCitizen(ctx, claims.IDCitizen)
 	if err != nil {
-		s.logger.Error("failed to check token blacklist", zap.Error(err))
-		return nil, domainerrors.ErrInternal
+		if errors.Is(err, domainerrors.ErrUserNotFound) {
+			s.logger.Warn("refresh token of deleted user", zap.Int("id_citizen", claims.IDCitizen))
+			s.revokeRefreshToken(ctx, refreshToken)
+			return nil, domainerrors.ErrInvalidToken
+		}
+		s.logger.Error("failed to get user", zap.Error(err), zap.Int("id_citizen", claims.IDCitizen))
+		return nil, internalError(err)
 	}
 
-	if blacklisted {
-		s.logger.Warn("refresh token is blacklisted", zap.Int("id_citizen", claims.IDCitizen))
+	if !user.IsActive() {
+		s.logger.Warn("refresh token of inactive user", zap.Int("id_citizen", claims.IDCitizen), zap.String("status", user.Status.String()))
+		s.revokeRefreshToken(ctx, refreshToken)
+		return nil, domainerrors.ErrUserSuspended
+	}
+
+	// The tokens of the user were revoked after the session was created (e.g. on a role change)
+	if storedData.TokenVersion < user.TokenVersion {
+		s.logger.Warn("refresh token issued before the tokens of the user were revoked",
+			zap.Int("id_citizen", claims.IDCitizen), zap.Int("token_version", storedData.TokenVersion))
+		s.revokeRefreshToken(ctx, refreshToken)
 		return nil, domainerrors.ErrTokenRevoked
 	}
 
-	// Verify if the refresh token exists in Redis
-	_, err = s.tokenRepo.GetRefreshToken(ctx, refreshToken)
-	if err != nil {
-		s.logger.Warn("refresh token not found in cache", zap.Error(err))
-		return nil, domainerrors.ErrInvalidToken
+	if claims.Role != "" && user.Role != claims.Role {
+		s.logger.Info("role changed since token was issued",
+			zap.Int("id_citizen", claims.IDCitizen),
+			zap.String("old_role", claims.Role.String()),
+			zap.String("new_role", user.Role.String()))
 	}
 
-	// Generate new token pair
-	tokenPair, err := s.jwtService.GenerateTokenPair(claims.IDCitizen, claims.Email, claims.Role)
+	// Sessions created before token families were tracked start their family on this refresh
+	if storedData.FamilyID == "" {
+		storedData.FamilyID = uuid.New().String()
+	}
+
+	// Re-evaluate the risk policy; the session keeps the latest assessment
+	risk := storedData.Risk
+	if s.riskEngine != nil {
+		risk = s.riskEngine.AssessRefresh(ctx, user, storedData)
+		if risk.Decision == domain.RiskDecisionDeny {
+			s.logger.Warn("refresh denied by risk policy", zap.Int("id_citizen", claims.IDCitizen), zap.Strings("rules", risk.Rules))
+			s.revokeRefreshToken(ctx, refreshToken)
+			return nil, domainerrors.ErrAuthenticationDenied
+		}
+	}
+
+	// The refresh continues the session, only the hourly token quota applies
+	if s.quotaEnforcer != nil {
+		if err := s.quotaEnforcer.EnforceUserIssuance(ctx, user, false); err != nil {
+			return nil, err
+		}
+	}
+
+	// Generate new token pair from the current user data, with the token profile and the delegation of the session
+	tokenPair, err := s.tokenIssuer.GenerateUserTokenPairWithProfile(user, storedData.TokenProfile, storedData.Delegation)
 	if err != nil {
 		s.logger.Error("failed to generate new token pair", zap.Error(err))
-		return nil, domainerrors.ErrInternal
+		return nil, internalError(err)
 	}
 
 	metrics.AddJWTTokensGenerated(2)
 
-	// Delete old refresh token
-	if err := s.tokenRepo.DeleteRefreshToken(ctx, refreshToken); err != nil {
-		s.logger.Error("failed to delete old refresh token", zap.Error(err))
-	}
-
-	// Store new refresh token
+	// Replace the old refresh token with the new one
 	refreshTokenData := &domain.RefreshTokenData{
-		IDCitizen: claims.IDCitizen,
-		Email:     claims.Email,
-		IssuedAt:  time.Now(),
-		ExpiresAt: time.Now().Add(s.jwtService.refreshTokenDuration),
+		IDCitizen: user.IDCitizen,
+		UserID:    user.ID,
+		Email:     user.Email,
+		Role:      user.Role,
+		FamilyID:  storedData.FamilyID,
+		IssuedAt:  s.now(),
+		ExpiresAt: s.now().Add(s.tokenIssuer.RefreshTokenDuration()),
+		Risk:      risk,
+
+		TokenProfile: storedData.TokenProfile,
+		TokenVersion: user.TokenVersion,
+		Delegation:   storedData.Delegation,
 	}
 
-	err = s.tokenRepo.StoreRefreshToken(
+	err = s.tokenRepo.RotateRefreshToken(
 		ctx,
+		refreshToken,
 		tokenPair.RefreshToken,
 		refreshTokenData,
-		s.jwtService.refreshTokenDuration,
+		s.tokenIssuer.RefreshTokenDuration(),
 	)
 	if err != nil {
-		s.logger.Error("failed to store new refresh token", zap.Error(err))
+		s.logger.Error("failed to rotate refresh token", zap.Error(err))
 	}
 
 	s.logger.Info("token refreshed successfully", zap.Int("id_citizen", claims.IDCitizen))
 	return tokenPair, nil
 }
 
+// revokeRefreshToken deletes a refresh token that must not be used again (best effort)
+func (s *AuthService) revokeRefreshToken(ctx context.Context, refreshToken string) {
+	if err := s.tokenRepo.DeleteRefreshToken(ctx, refreshToken); err != nil {
+		s.logger.Error("failed to delete refresh token", zap.Error(err))
+	}
+}
+
 // Logout invalidates the tokens of a user
 func (s *AuthService) Logout(ctx context.Context, accessToken, refreshToken string) error {
 	s.logger.Debug("attempting logout")
 
 	// Validate access token
-	claims, err := s.jwtService.ValidateAccessToken(accessToken)
+	claims, err := s.tokenIssuer.ValidateAccessToken(accessToken)
 	if err != nil {
 		s.logger.Warn("invalid access token on logout", zap.Error(err))
 		return err
 	}
 
-	// Add access token to blacklist
-	expiresAt, err := s.jwtService.GetTokenExpiration(accessToken)
-	if err == nil {
-		ttl := time.Until(expiresAt)
-		if ttl > 0 {
-			if err := s.tokenRepo.BlacklistToken(ctx, accessToken, ttl); err != nil {
-				s.logger.Error("failed to blacklist access token", zap.Error(err))
-			}
-		}
+	// Blacklist the access token until it expires and delete the refresh token if provided
+	var ttl time.Duration
+	if expiresAt, err := s.tokenIssuer.GetTokenExpiration(accessToken); err == nil {
+		ttl = time.Until(expiresAt)
 	}
 
-	// Delete refresh token if provided
-	if refreshToken != "" {
-		if err := s.tokenRepo.DeleteRefreshToken(ctx, refreshToken); err != nil {
-			s.logger.Error("failed to delete refresh token", zap.Error(err))
-		}
+	if err := s.tokenRepo.RevokeSession(ctx, accessToken, ttl, refreshToken); err != nil {
+		s.logger.Error("failed to revoke session tokens", zap.Error(err))
 	}
 
 	s.logger.Info("logout successful", zap.Int("id_citizen", claims.IDCitizen))
 	return nil
 }
 
-// GetUserByIDCitizen retrieves a user by their id_citizen
+// GetUserByIDCitizen retrieves a user by their id_citizen.
+// With a user cache, users are read from it unless the context bypasses the caches; bypassed lookups
+// read the database and refresh the cached user.
 func (s *AuthService) GetUserByIDCitizen(ctx context.Context, idCitizen int) (*domain.UserPublic, error) {
+	if s.userCache == nil {
+		return s.getUserByIDCitizen(ctx, idCitizen)
+	}
+
+	if domain.CacheBypassFromContext(ctx) {
+		metrics.IncUserCacheLookups(userCacheBypass)
+	} else {
+		// A failing cache falls back to the database
+		if cached, err := s.userCache.Get(ctx, idCitizen); err == nil && cached != nil {
+			metrics.IncUserCacheLookups(userCacheHit)
+			return cached, nil
+		}
+		metrics.IncUserCacheLookups(userCacheMiss)
+	}
+
+	user, err := s.getUserByIDCitizen(ctx, idCitizen)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userCache.Set(ctx, user); err != nil {
+		s.logger.Warn("failed to cache user", zap.Error(err), zap.Int("id_citizen", idCitizen))
+	}
+	return user, nil
+}
+
+// getUserByIDCitizen retrieves a user by their id_citizen from the database
+func (s *AuthService) getUserByIDCitizen(ctx context.Context, idCitizen int) (*domain.UserPublic, error) {
 	user, err := s.userRepo.GetByIDCitizen(ctx, idCitizen)
 	if err != nil {
 		s.logger.Error("failed to get user", zap.Error(err), zap.Int("id_citizen", idCitizen))
@@ -303,10 +667,35 @@ func (s *AuthService) GetUserByIDCitizen(ctx context.Context, idCitizen int) (*d
 	return user.ToPublic(), nil
 }
 
+// GetAccountActivity summarizes the security state of the account of a user. It always reads the database,
+// the cached users don't carry the activity.
+func (s *AuthService) GetAccountActivity(ctx context.Context, idCitizen int) (*domain.AccountActivity, error) {
+	user, err := s.userRepo.GetByIDCitizen(ctx, idCitizen)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrUserNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to get user", zap.Error(err), zap.Int("id_citizen", idCitizen))
+		return nil, internalError(err)
+	}
+
+	sessions, err := s.tokenRepo.CountActiveSessions(ctx, idCitizen)
+	if err != nil {
+		s.logger.Error("failed to count active sessions", zap.Error(err), zap.Int("id_citizen", idCitizen))
+		return nil, internalError(err)
+	}
+
+	return &domain.AccountActivity{
+		LastLoginAt:         user.LastLoginAt,
+		ActiveSessionsCount: sessions,
+		PasswordChangedAt:   user.PasswordChangedAt,
+	}, nil
+}
+
 // ValidateAccessToken validates an access token and verifies that it is not revoked
 func (s *AuthService) ValidateAccessToken(ctx context.Context, token string) (*domain.TokenClaims, error) {
 	// Validate token
-	claims, err := s.jwtService.ValidateAccessToken(token)
+	claims, err := s.tokenIssuer.ValidateAccessToken(token)
 	if err != nil {
 		return nil, err
 	}
@@ -315,13 +704,24 @@ func (s *AuthService) ValidateAccessToken(ctx context.Context, token string) (*d
 	blacklisted, err := s.tokenRepo.IsTokenBlacklisted(ctx, token)
 	if err != nil {
 		s.logger.Error("failed to check token blacklist", zap.Error(err))
-		return nil, domainerrors.ErrInternal
+		return nil, internalError(err)
 	}
 
 	if blacklisted {
 		return nil, domainerrors.ErrTokenRevoked
 	}
 
+	// Verify the token was issued after the last revocation of the tokens of the user
+	version, err := s.tokenRepo.GetTokenVersion(ctx, claims.IDCitizen)
+	if err != nil {
+		s.logger.Error("failed to check token version", zap.Error(err))
+		return nil, internalError(err)
+	}
+
+	if claims.TokenVersion < version {
+		return nil, domainerrors.ErrTokenRevoked
+	}
+
 	return claims, nil
 }
 
@@ -329,7 +729,7 @@ func (s *AuthService) ValidateAccessToken(ctx context.Context, token string) (*d
 func (s *AuthService) RevokeAllUserTokens(ctx context.Context, idCitizen int) error {
 	s.logger.Info("revoking all user tokens", zap.Int("id_citizen", idCitizen))
 
-	if err := s.tokenRepo.DeleteUserTokens(ctx, idCitizen); err != nil {
+	if err := s.tokenRepo.DeleteUserTokens(ctx, "", idCitizen); err != nil {
 		s.logger.Error("failed to revoke user tokens", zap.Error(err), zap.Int("id_citizen", idCitizen))
 		return fmt.Errorf("failed to revoke tokens: %w", err)
 	}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+)
+
+// AvatarCleanupPolicy controls how orphaned avatar images are removed
+type AvatarCleanupPolicy struct {
+	Interval    time.Duration
+	GracePeriod time.Duration // minimum age of a removed image, so uploads in progress are not removed
+	BatchSize   int
+}
+
+// AvatarCleaner periodically removes from storage the avatar images no user references anymore:
+// images replaced while their deletion failed, and avatars of anonymized users.
+type AvatarCleaner struct {
+	storage    ports.BlobStorage
+	avatarRepo ports.AvatarRepository
+	policy     AvatarCleanupPolicy
+	logger     *zap.Logger
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewAvatarCleaner creates a new instance of AvatarCleaner
+func NewAvatarCleaner(storage ports.BlobStorage, avatarRepo ports.AvatarRepository, policy AvatarCleanupPolicy, logger *zap.Logger) *AvatarCleaner {
+	return &AvatarCleaner{
+		storage:    storage,
+		avatarRepo: avatarRepo,
+		policy:     policy,
+		logger:     logger,
+	}
+}
+
+// Run removes the orphaned avatar images every interval until the context is cancelled
+func (c *AvatarCleaner) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.policy.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := c.RemoveOrphans(ctx); err != nil && ctx.Err() == nil {
+				c.logger.Error("failed to remove orphaned avatars", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Start runs the cleaner in the background, it implements Component
+func (c *AvatarCleaner) Start(ctx context.Context) error {
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	c.cancel = cancel
+	c.done = make(chan struct{})
+	go func() {
+		defer close(c.done)
+		c.Run(runCtx)
+	}()
+	return nil
+}
+
+// Stop stops the background cleaner and waits for the batch in progress, until the context is done
+func (c *AvatarCleaner) Stop(ctx context.Context) error {
+	if c.cancel == nil {
+		return nil
+	}
+	c.cancel()
+	select {
+	case <-c.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RemoveOrphans scans the avatar images in batches, removes the unreferenced ones older than the grace
+// period and returns how many were removed. Images that fail to be removed are retried on the next run.
+func (c *AvatarCleaner) RemoveOrphans(ctx context.Context) (int, error) {
+	cutoff := time.Now().Add(-c.policy.GracePeriod)
+	removed := 0
+	startAfter := ""
+
+	for ctx.Err() == nil {
+		objects, err := c.storage.List(ctx, domain.AvatarKeyPrefix, startAfter, c.policy.BatchSize)
+		if err != nil {
+			return removed, err
+		}
+		if len(objects) == 0 {
+			break
+		}
+		startAfter = objects[len(objects)-1].Key
+
+		var keys []string
+		for _, object := range objects {
+			if object.LastModified.Before(cutoff) {
+				keys = append(keys, object.Key)
+			}
+		}
+
+		referenced, err := c.avatarRepo.ReferencedKeys(ctx, keys)
+		if err != nil {
+			return removed, err
+		}
+
+		for _, key := range keys {
+			if referenced[key] {
+				continue
+			}
+			if err := c.storage.Delete(ctx, key); err != nil {
+				c.logger.Warn("failed to remove orphaned avatar", zap.Error(err), logging.String("key", key))
+				continue
+			}
+			removed++
+		}
+
+		if len(objects) < c.policy.BatchSize {
+			break
+		}
+	}
+
+	if removed > 0 {
+		c.logger.Info("orphaned avatars removed", zap.Int("count", removed))
+	}
+	return removed, ctx.Err()
+}
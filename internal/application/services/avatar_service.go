@@ -0,0 +1,141 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+)
+
+// AvatarServiceInterface defines the methods of AvatarService used by handlers.
+type AvatarServiceInterface interface {
+	MaxSize() int64
+	UploadAvatar(ctx context.Context, idCitizen int, data []byte) (*domain.Avatar, string, error)
+	AvatarURL(ctx context.Context, userID string) (string, error)
+}
+
+// AvatarService manages the profile pictures of users. Images are kept in blob storage and served
+// through short-lived signed URLs, so the bucket stays private.
+type AvatarService struct {
+	userRepo   ports.UserRepository
+	avatarRepo ports.AvatarRepository
+	storage    ports.BlobStorage
+	maxSize    int64
+	urlExpiry  time.Duration
+	logger     *zap.Logger
+}
+
+// NewAvatarService creates a new instance of AvatarService
+func NewAvatarService(
+	userRepo ports.UserRepository,
+	avatarRepo ports.AvatarRepository,
+	storage ports.BlobStorage,
+	maxSize int64,
+	urlExpiry time.Duration,
+	logger *zap.Logger,
+) *AvatarService {
+	return &AvatarService{
+		userRepo:   userRepo,
+		avatarRepo: avatarRepo,
+		storage:    storage,
+		maxSize:    maxSize,
+		urlExpiry:  urlExpiry,
+		logger:     logger,
+	}
+}
+
+// MaxSize returns the maximum size of an avatar in bytes
+func (s *AvatarService) MaxSize() int64 {
+	return s.maxSize
+}
+
+// UploadAvatar stores a PNG, JPEG or WebP image as the avatar of a user, replacing the previous one,
+// and returns it with a signed URL. The format is detected from the content of the image.
+func (s *AvatarService) UploadAvatar(ctx context.Context, idCitizen int, data []byte) (*domain.Avatar, string, error) {
+	if int64(len(data)) > s.maxSize {
+		return nil, "", domainerrors.ErrAvatarTooLarge
+	}
+	contentType, ok := domain.DetectAvatarContentType(data)
+	if !ok {
+		return nil, "", domainerrors.ErrUnsupportedAvatarType
+	}
+
+	user, err := s.userRepo.GetByIDCitizen(ctx, idCitizen)
+	if err != nil {
+		s.logger.Error("failed to get user", zap.Error(err), zap.Int("id_citizen", idCitizen))
+		return nil, "", err
+	}
+
+	previous, err := s.avatarRepo.Get(ctx, user.ID)
+	if err != nil && !errors.Is(err, domainerrors.ErrAvatarNotFound) {
+		s.logger.Error("failed to get avatar", zap.Error(err), zap.String("user_id", user.ID))
+		return nil, "", internalError(err)
+	}
+
+	avatar := &domain.Avatar{
+		UserID:      user.ID,
+		ObjectKey:   domain.NewAvatarObjectKey(user.ID, contentType),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+	}
+	if err := s.storage.Put(ctx, avatar.ObjectKey, contentType, bytes.NewReader(data), avatar.Size); err != nil {
+		s.logger.Error("failed to store avatar", zap.Error(err), zap.String("user_id", user.ID))
+		return nil, "", internalError(err)
+	}
+
+	if err := s.avatarRepo.Upsert(ctx, avatar); err != nil {
+		s.logger.Error("failed to save avatar", zap.Error(err), zap.String("user_id", user.ID))
+		s.deleteObject(ctx, avatar.ObjectKey)
+		return nil, "", internalError(err)
+	}
+
+	// The previous image is no longer referenced, the orphan cleanup removes it if this fails
+	if previous != nil {
+		s.deleteObject(ctx, previous.ObjectKey)
+	}
+
+	url, err := s.storage.SignedURL(avatar.ObjectKey, s.urlExpiry)
+	if err != nil {
+		s.logger.Error("failed to sign avatar url", zap.Error(err), zap.String("user_id", user.ID))
+		return nil, "", internalError(err)
+	}
+
+	s.logger.Info("avatar uploaded",
+		zap.String("user_id", user.ID),
+		zap.String("content_type", contentType),
+		zap.Int64("size", avatar.Size))
+	return avatar, url, nil
+}
+
+// AvatarURL returns a signed URL of the avatar of a user, or an empty string when they have none
+func (s *AvatarService) AvatarURL(ctx context.Context, userID string) (string, error) {
+	avatar, err := s.avatarRepo.Get(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrAvatarNotFound) {
+			return "", nil
+		}
+		s.logger.Error("failed to get avatar", zap.Error(err), zap.String("user_id", userID))
+		return "", internalError(err)
+	}
+
+	url, err := s.storage.SignedURL(avatar.ObjectKey, s.urlExpiry)
+	if err != nil {
+		s.logger.Error("failed to sign avatar url", zap.Error(err), zap.String("user_id", userID))
+		return "", internalError(err)
+	}
+	return url, nil
+}
+
+// deleteObject removes an avatar image from storage, failures are only logged
+func (s *AvatarService) deleteObject(ctx context.Context, key string) {
+	if err := s.storage.Delete(ctx, key); err != nil {
+		s.logger.Warn("failed to delete avatar object", zap.Error(err), logging.String("key", key))
+	}
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
+)
+
+// Reasons of the failed logins
+const (
+	LoginFailureUnknownUser     = "unknown_user"
+	LoginFailureInvalidPassword = "invalid_password"
+)
+
+// BruteForceMonitor counts the failed logins per IP address and per email address within the failure
+// window. Once the failures of an IP or an email exceed the alert threshold, it logs a high severity
+// event meant to be routed to the alerting (e.g. Alertmanager through the log pipeline).
+type BruteForceMonitor struct {
+	failureCounter ports.RateLimiter
+	alertThreshold int
+	window         time.Duration
+	logger         *zap.Logger
+}
+
+// NewBruteForceMonitor creates a new instance of BruteForceMonitor.
+// failureCounter counts within window, and a zero alertThreshold disables the alerts.
+func NewBruteForceMonitor(failureCounter ports.RateLimiter, alertThreshold int, window time.Duration, logger *zap.Logger) *BruteForceMonitor {
+	return &BruteForceMonitor{
+		failureCounter: failureCounter,
+		alertThreshold: alertThreshold,
+		window:         window,
+		logger:         logger,
+	}
+}
+
+// RecordLoginFailure counts a failed login for the email, from the IP of the client (best effort)
+func (m *BruteForceMonitor) RecordLoginFailure(ctx context.Context, email, reason string) {
+	metrics.IncLoginFailures(reason)
+
+	if info, ok := domain.ClientInfoFromContext(ctx); ok && info.IP != "" {
+		if failures, ok := m.hit(ctx, domain.LoginFailureIPRateLimitKey(info.IP)); ok {
+			metrics.ObserveLoginFailuresPerIP(failures)
+			m.alert(failures, "ip", zap.String("ip", info.IP), logging.String("email", email))
+		}
+	}
+
+	if failures, ok := m.hit(ctx, domain.LoginFailureEmailRateLimitKey(email)); ok {
+		metrics.ObserveLoginFailuresPerEmail(failures)
+		m.alert(failures, "email", logging.String("email", email))
+	}
+}
+
+// hit counts a failed login on the key and returns the failures within the window
+func (m *BruteForceMonitor) hit(ctx context.Context, key string) (int, bool) {
+	status, err := m.failureCounter.Hit(ctx, key)
+	if err != nil {
+		m.logger.Error("failed to count login failure", zap.Error(err), logging.String("key", key))
+		return 0, false
+	}
+	return status.Used, true
+}
+
+// alert logs the alert event the first time the failures exceed the threshold within the window
+func (m *BruteForceMonitor) alert(failures int, dimension string, fields ...zap.Field) {
+	if m.alertThreshold <= 0 || failures != m.alertThreshold+1 {
+		return
+	}
+
+	metrics.IncBruteForceAlerts(dimension)
+	m.logger.Error("brute force alert threshold exceeded", append([]zap.Field{
+		zap.String("alert", "brute_force"),
+		zap.String("severity", "high"),
+		zap.String("dimension", dimension),
+		zap.Int("failures", failures),
+		zap.Int("threshold", m.alertThreshold),
+		zap.Duration("window", m.window),
+	}, fields...)...)
+}
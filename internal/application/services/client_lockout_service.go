@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
+)
+
+// ClientLockoutGuard locks OAuth2 clients out of the token endpoints after repeated authentication failures
+type ClientLockoutGuard interface {
+	// CheckClientLockout returns a ClientLockedOutError while the client is locked out
+	CheckClientLockout(ctx context.Context, clientID string) error
+	// RecordAuthFailure counts a failed authentication of the client, locking it out past the threshold
+	RecordAuthFailure(ctx context.Context, clientID string)
+	// ListLockouts returns the lockout state of the clients with recent failures, keyed by client ID
+	ListLockouts(ctx context.Context, clientIDs []string) (map[string]*domain.ClientLockout, error)
+}
+
+// ClientLockoutPolicy controls when OAuth2 clients are locked out
+type ClientLockoutPolicy struct {
+	Threshold int           // failed authentications within the window that lock the client out
+	Window    time.Duration // how long failed authentications are counted
+	Cooldown  time.Duration // how long the client stays locked out
+}
+
+// ClientLockoutService locks an OAuth2 client out for a cooldown once its failed authentications reach
+// the threshold within the failure window, so a leaked client ID can't be used to guess its secret.
+// Each lockout logs a high severity event meant to be routed to the alerting. Failures of the counters
+// are logged and the clients let through, a Redis outage must not lock every client out.
+type ClientLockoutService struct {
+	failureCounter ports.RateLimiter
+	lockouts       ports.RateLimiter
+	policy         ClientLockoutPolicy
+	logger         *zap.Logger
+}
+
+// NewClientLockoutService creates a new instance of ClientLockoutService.
+// failureCounter counts within the failure window and lockouts within the cooldown.
+func NewClientLockoutService(failureCounter, lockouts ports.RateLimiter, policy ClientLockoutPolicy, logger *zap.Logger) *ClientLockoutService {
+	return &ClientLockoutService{
+		failureCounter: failureCounter,
+		lockouts:       lockouts,
+		policy:         policy,
+		logger:         logger,
+	}
+}
+
+// CheckClientLockout returns a ClientLockedOutError while the client is locked out
+func (s *ClientLockoutService) CheckClientLockout(ctx context.Context, clientID string) error {
+	status, err := s.lockouts.Peek(ctx, domain.ClientLockoutRateLimitKey(clientID))
+	if err != nil {
+		s.logger.Error("failed to check client lockout", zap.Error(err), logging.String("client_id", clientID))
+		return nil
+	}
+	if status.Used == 0 {
+		return nil
+	}
+	return &domainerrors.ClientLockedOutError{RetryAfter: time.Until(status.ResetAt)}
+}
+
+// RecordAuthFailure counts a failed authentication of the client and locks it out once the failures
+// reach the threshold within the window
+func (s *ClientLockoutService) RecordAuthFailure(ctx context.Context, clientID string) {
+	status, err := s.failureCounter.Hit(ctx, domain.ClientAuthFailureRateLimitKey(clientID))
+	if err != nil {
+		s.logger.Error("failed to count client authentication failure", zap.Error(err), logging.String("client_id", clientID))
+		return
+	}
+	if status.Used < s.policy.Threshold {
+		return
+	}
+
+	lockout, err := s.lockouts.Hit(ctx, domain.ClientLockoutRateLimitKey(clientID))
+	if err != nil {
+		s.logger.Error("failed to lock client out", zap.Error(err), logging.String("client_id", clientID))
+		return
+	}
+	// Failures keep being counted while locked out, only the first one starts a lockout
+	if lockout.Used != 1 {
+		return
+	}
+
+	metrics.IncOAuthClientLockouts()
+	s.logger.Error("oauth client locked out after repeated authentication failures",
+		zap.String("alert", "client_lockout"),
+		zap.String("severity", "high"),
+		logging.String("client_id", clientID),
+		zap.Int("failures", status.Used),
+		zap.Int("threshold", s.policy.Threshold),
+		zap.Duration("window", s.policy.Window),
+		zap.Time("locked_until", lockout.ResetAt),
+	)
+}
+
+// ListLockouts returns the lockout state of the clients with recent failures or locked out, keyed by
+// client ID. Clients without either are left out.
+func (s *ClientLockoutService) ListLockouts(ctx context.Context, clientIDs []string) (map[string]*domain.ClientLockout, error) {
+	lockouts := make(map[string]*domain.ClientLockout)
+	for _, clientID := range clientIDs {
+		failures, err := s.failureCounter.Peek(ctx, domain.ClientAuthFailureRateLimitKey(clientID))
+		if err != nil {
+			return nil, err
+		}
+		lockout, err := s.lockouts.Peek(ctx, domain.ClientLockoutRateLimitKey(clientID))
+		if err != nil {
+			return nil, err
+		}
+		if failures.Used == 0 && lockout.Used == 0 {
+			continue
+		}
+
+		state := &domain.ClientLockout{ClientID: clientID, RecentFailures: failures.Used}
+		if lockout.Used > 0 {
+			lockedUntil := lockout.ResetAt
+			state.LockedUntil = &lockedUntil
+		}
+		lockouts[clientID] = state
+	}
+	return lockouts, nil
+}
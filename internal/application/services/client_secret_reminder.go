@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	"github.com/kristianrpo/auth-microservice/internal/domain/events"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+)
+
+// ClientSecretReminderPolicy controls the reminders of the client secrets about to expire
+type ClientSecretReminderPolicy struct {
+	Interval time.Duration
+	Window   time.Duration // how long before and after the expiry of a secret the reminders are published
+}
+
+// ClientSecretReminder periodically publishes a reminder event for every OAuth client whose secret expires
+// within the window, and for the ones that expired less than a window ago, so their owners rotate them
+type ClientSecretReminder struct {
+	clientRepo ports.OAuthClientRepository
+	publisher  ports.MessagePublisher
+	queue      string
+	policy     ClientSecretReminderPolicy
+	logger     *zap.Logger
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewClientSecretReminder creates a new instance of ClientSecretReminder publishing to queue
+func NewClientSecretReminder(clientRepo ports.OAuthClientRepository, publisher ports.MessagePublisher, queue string, policy ClientSecretReminderPolicy, logger *zap.Logger) *ClientSecretReminder {
+	return &ClientSecretReminder{
+		clientRepo: clientRepo,
+		publisher:  publisher,
+		queue:      queue,
+		policy:     policy,
+		logger:     logger,
+	}
+}
+
+// Run publishes the reminders every interval until the context is cancelled
+func (r *ClientSecretReminder) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.policy.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.SendReminders(ctx); err != nil && ctx.Err() == nil {
+				r.logger.Error("failed to send client secret reminders", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Start runs the reminder in the background, it implements Component
+func (r *ClientSecretReminder) Start(ctx context.Context) error {
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	r.cancel = cancel
+	r.done = make(chan struct{})
+	go func() {
+		defer close(r.done)
+		r.Run(runCtx)
+	}()
+	return nil
+}
+
+// Stop stops the background reminder and waits for the reminders in progress, until the context is done
+func (r *ClientSecretReminder) Stop(ctx context.Context) error {
+	if r.cancel == nil {
+		return nil
+	}
+	r.cancel()
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// SendReminders publishes the reminders of the secrets expiring within the window and returns how many were
+// published. A reminder that fails to be published is sent again on the next run.
+func (r *ClientSecretReminder) SendReminders(ctx context.Context) (int, error) {
+	clients, err := r.clientRepo.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	sent := 0
+	for _, client := range expiringSecrets(clients, now, r.policy.Window) {
+		if client.SecretExpiresAt.Before(now.Add(-r.policy.Window)) {
+			continue
+		}
+
+		message, err := events.NewClientSecretExpiringEvent(client, now).ToJSON()
+		if err != nil {
+			return sent, err
+		}
+		if err := r.publisher.Publish(ctx, r.queue, message); err != nil {
+			r.logger.Warn("failed to publish client secret reminder", zap.Error(err), logging.String("client_id", client.ClientID))
+			continue
+		}
+		sent++
+	}
+
+	if sent > 0 {
+		r.logger.Info("client secret reminders sent", zap.Int("count", sent))
+	}
+	return sent, nil
+}
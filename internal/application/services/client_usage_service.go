@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+)
+
+// ClientUsageTracker records the usage of OAuth2 clients and reports it
+type ClientUsageTracker interface {
+	// RecordIssuance counts a token issued to the client
+	RecordIssuance(ctx context.Context, clientID string)
+	// RecordError counts a failed request of an existing client
+	RecordError(ctx context.Context, clientID string)
+	// ListUsage returns the usage of every client that was used, keyed by client ID
+	ListUsage(ctx context.Context) (map[string]*domain.ClientUsage, error)
+}
+
+// ClientUsagePolicy controls how the usage of OAuth2 clients is flushed and reported
+type ClientUsagePolicy struct {
+	FlushInterval time.Duration
+	ErrorWindow   time.Duration // how long errors are counted as recent
+}
+
+// ClientUsageService tracks the usage of OAuth2 clients: the last time each client got a token, the
+// tokens issued and the recent errors. Usage is counted in cheap counters on every request and flushed
+// to the usage repository every interval, so the reported usage lags behind by up to that interval.
+type ClientUsageService struct {
+	counter ports.ClientUsageCounter
+	repo    ports.ClientUsageRepository
+	policy  ClientUsagePolicy
+	logger  *zap.Logger
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewClientUsageService creates a new instance of ClientUsageService
+func NewClientUsageService(counter ports.ClientUsageCounter, repo ports.ClientUsageRepository, policy ClientUsagePolicy, logger *zap.Logger) *ClientUsageService {
+	return &ClientUsageService{
+		counter: counter,
+		repo:    repo,
+		policy:  policy,
+		logger:  logger,
+	}
+}
+
+// RecordIssuance counts a token issued to the client. Failures are only logged, usage statistics never
+// fail a request.
+func (s *ClientUsageService) RecordIssuance(ctx context.Context, clientID string) {
+	if err := s.counter.RecordIssuance(ctx, clientID, time.Now()); err != nil {
+		s.logger.Warn("failed to record client usage", zap.Error(err), logging.String("client_id", clientID))
+	}
+}
+
+// RecordError counts a failed request of the client. Failures are only logged.
+func (s *ClientUsageService) RecordError(ctx context.Context, clientID string) {
+	if err := s.counter.RecordError(ctx, clientID); err != nil {
+		s.logger.Warn("failed to record client error", zap.Error(err), logging.String("client_id", clientID))
+	}
+}
+
+// ListUsage returns the flushed usage of every client that was used, keyed by client ID
+func (s *ClientUsageService) ListUsage(ctx context.Context) (map[string]*domain.ClientUsage, error) {
+	return s.repo.List(ctx)
+}
+
+// Run flushes the usage every interval until the context is cancelled
+func (s *ClientUsageService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.policy.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.Flush(ctx); err != nil && ctx.Err() == nil {
+				s.logger.Error("failed to flush client usage", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Start runs the flusher in the background, it implements Component
+func (s *ClientUsageService) Start(ctx context.Context) error {
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	go func() {
+		defer close(s.done)
+		s.Run(runCtx)
+	}()
+	return nil
+}
+
+// Stop stops the background flusher, then flushes the usage counted since the last flush until the
+// context is done
+func (s *ClientUsageService) Stop(ctx context.Context) error {
+	if s.cancel == nil {
+		return nil
+	}
+	s.cancel()
+	select {
+	case <-s.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	_, err := s.Flush(ctx)
+	return err
+}
+
+// Flush drains the counters into the usage repository and returns the number of clients flushed. The
+// usage that fails to be stored is lost, the counters are statistics rather than records.
+func (s *ClientUsageService) Flush(ctx context.Context) (int, error) {
+	deltas, err := s.counter.Drain(ctx)
+	flushed := 0
+	for _, delta := range deltas {
+		if addErr := s.repo.Add(ctx, delta, s.policy.ErrorWindow); addErr != nil {
+			s.logger.Warn("client usage lost", zap.Error(addErr), logging.String("client_id", delta.ClientID),
+				zap.Int64("tokens_issued", delta.TokensIssued), zap.Int64("errors", delta.Errors))
+			err = errors.Join(err, addErr)
+			continue
+		}
+		flushed++
+	}
+
+	if flushed > 0 {
+		s.logger.Debug("client usage flushed", zap.Int("clients", flushed))
+	}
+	return flushed, err
+}
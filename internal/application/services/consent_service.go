@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+)
+
+// ConsentServiceInterface defines the methods of ConsentService used by handlers and other services.
+type ConsentServiceInterface interface {
+	ListConsents(ctx context.Context, idCitizen int) ([]*domain.Consent, error)
+	RevokeConsent(ctx context.Context, idCitizen int, clientID string) error
+	RequiresConsent(ctx context.Context, idCitizen int, clientID string, scopes []string) (bool, error)
+	RecordConsent(ctx context.Context, idCitizen int, clientID string, scopes []string) error
+}
+
+// ConsentService manages the scopes users have granted to third-party OAuth2 clients
+type ConsentService struct {
+	userRepo    ports.UserRepository
+	consentRepo ports.ConsentRepository
+	logger      *zap.Logger
+}
+
+// NewConsentService creates a new instance of ConsentService
+func NewConsentService(userRepo ports.UserRepository, consentRepo ports.ConsentRepository, logger *zap.Logger) *ConsentService {
+	return &ConsentService{
+		userRepo:    userRepo,
+		consentRepo: consentRepo,
+		logger:      logger,
+	}
+}
+
+// ListConsents retrieves every consent granted by a user
+func (s *ConsentService) ListConsents(ctx context.Context, idCitizen int) ([]*domain.Consent, error) {
+	user, err := s.userRepo.GetByIDCitizen(ctx, idCitizen)
+	if err != nil {
+		s.logger.Error("failed to get user", zap.Error(err), zap.Int("id_citizen", idCitizen))
+		return nil, err
+	}
+
+	consents, err := s.consentRepo.ListByUser(ctx, user.ID)
+	if err != nil {
+		s.logger.Error("failed to list consents", zap.Error(err), zap.String("user_id", user.ID))
+		return nil, internalError(err)
+	}
+
+	return consents, nil
+}
+
+// RevokeConsent removes the consent a user granted to a client, so the next authorization prompts again
+func (s *ConsentService) RevokeConsent(ctx context.Context, idCitizen int, clientID string) error {
+	user, err := s.userRepo.GetByIDCitizen(ctx, idCitizen)
+	if err != nil {
+		s.logger.Error("failed to get user", zap.Error(err), zap.Int("id_citizen", idCitizen))
+		return err
+	}
+
+	if err := s.consentRepo.Delete(ctx, user.ID, clientID); err != nil {
+		if errors.Is(err, domainerrors.ErrConsentNotFound) {
+			return err
+		}
+		s.logger.Error("failed to revoke consent", zap.Error(err), zap.String("user_id", user.ID), logging.String("client_id", clientID))
+		return internalError(err)
+	}
+
+	s.logger.Info("consent revoked", zap.String("user_id", user.ID), logging.String("client_id", clientID))
+	return nil
+}
+
+// RequiresConsent reports whether the user must be prompted before granting the scopes to the client.
+// Users are not prompted again when a previous consent already covers every requested scope.
+func (s *ConsentService) RequiresConsent(ctx context.Context, idCitizen int, clientID string, scopes []string) (bool, error) {
+	user, err := s.userRepo.GetByIDCitizen(ctx, idCitizen)
+	if err != nil {
+		s.logger.Error("failed to get user", zap.Error(err), zap.Int("id_citizen", idCitizen))
+		return false, err
+	}
+
+	consent, err := s.consentRepo.Get(ctx, user.ID, clientID)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrConsentNotFound) {
+			return true, nil
+		}
+		s.logger.Error("failed to get consent", zap.Error(err), zap.String("user_id", user.ID), logging.String("client_id", clientID))
+		return false, internalError(err)
+	}
+
+	return !consent.Covers(scopes), nil
+}
+
+// RecordConsent stores the scopes the user granted to the client, merged with any previous consent
+func (s *ConsentService) RecordConsent(ctx context.Context, idCitizen int, clientID string, scopes []string) error {
+	user, err := s.userRepo.GetByIDCitizen(ctx, idCitizen)
+	if err != nil {
+		s.logger.Error("failed to get user", zap.Error(err), zap.Int("id_citizen", idCitizen))
+		return err
+	}
+
+	consent, err := s.consentRepo.Get(ctx, user.ID, clientID)
+	if err != nil {
+		if !errors.Is(err, domainerrors.ErrConsentNotFound) {
+			s.logger.Error("failed to get consent", zap.Error(err), zap.String("user_id", user.ID), logging.String("client_id", clientID))
+			return internalError(err)
+		}
+		consent = &domain.Consent{UserID: user.ID, ClientID: clientID, Scopes: []string{}}
+	}
+
+	consent.Grant(scopes)
+
+	if err := s.consentRepo.Upsert(ctx, consent); err != nil {
+		s.logger.Error("failed to save consent", zap.Error(err), zap.String("user_id", user.ID), logging.String("client_id", clientID))
+		return internalError(err)
+	}
+
+	s.logger.Info("consent recorded", zap.String("user_id", user.ID), logging.String("client_id", clientID))
+	return nil
+}
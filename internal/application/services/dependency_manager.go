@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/retry"
+)
+
+// Dependency is an external system the service relies on
+type Dependency struct {
+	Name        string
+	Criticality domain.DependencyCriticality
+	// Check returns an error when the dependency is not available
+	Check func(ctx context.Context) error
+}
+
+// DependencyStartupPolicy controls how dependencies are awaited at startup
+type DependencyStartupPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	CheckTimeout   time.Duration // timeout of a single check
+}
+
+// DependencyChecker reports the current status of the dependencies of the service
+type DependencyChecker interface {
+	CheckDependencies(ctx context.Context) []domain.DependencyStatus
+}
+
+// DependencyManager checks the dependencies of the service at startup and on demand.
+// At startup every dependency is retried with exponential backoff; only required dependencies
+// make startup fail, degraded-ok ones leave the service running in degraded mode.
+type DependencyManager struct {
+	dependencies []Dependency
+	policy       DependencyStartupPolicy
+	logger       *zap.Logger
+}
+
+// NewDependencyManager creates a new instance of DependencyManager
+func NewDependencyManager(policy DependencyStartupPolicy, logger *zap.Logger, dependencies ...Dependency) *DependencyManager {
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+	return &DependencyManager{
+		dependencies: dependencies,
+		policy:       policy,
+		logger:       logger,
+	}
+}
+
+// WaitForStartup checks every dependency concurrently, retrying failed checks, and logs a summary.
+// It returns an error when a required dependency is still unavailable after the last attempt.
+func (m *DependencyManager) WaitForStartup(ctx context.Context) ([]domain.DependencyStatus, error) {
+	statuses := m.checkAll(ctx, m.policy.MaxAttempts)
+
+	var failed []string
+	for _, status := range statuses {
+		fields := []zap.Field{
+			zap.String("dependency", status.Name),
+			zap.String("criticality", string(status.Criticality)),
+			zap.String("status", status.Status()),
+			zap.Int("attempts", status.Attempts),
+		}
+		switch {
+		case status.Healthy:
+			m.logger.Info("dependency available", fields...)
+		case status.Criticality == domain.DependencyRequired:
+			failed = append(failed, status.Name)
+			m.logger.Error("required dependency unavailable", append(fields, zap.String("error", status.Error))...)
+		default:
+			m.logger.Warn("dependency unavailable, running in degraded mode", append(fields, zap.String("error", status.Error))...)
+		}
+	}
+
+	overall := domain.OverallHealthStatus(statuses)
+	m.logger.Info("dependency startup checks completed",
+		zap.String("status", overall),
+		zap.Strings("healthy", dependencyNames(statuses, true)),
+		zap.Strings("unhealthy", dependencyNames(statuses, false)),
+	)
+
+	if len(failed) > 0 {
+		return statuses, fmt.Errorf("required dependencies unavailable: %s", strings.Join(failed, ", "))
+	}
+	return statuses, nil
+}
+
+// CheckDependencies checks every dependency once
+func (m *DependencyManager) CheckDependencies(ctx context.Context) []domain.DependencyStatus {
+	return m.checkAll(ctx, 1)
+}
+
+// checkAll checks the dependencies concurrently, keeping their registration order
+func (m *DependencyManager) checkAll(ctx context.Context, maxAttempts int) []domain.DependencyStatus {
+	statuses := make([]domain.DependencyStatus, len(m.dependencies))
+
+	var wg sync.WaitGroup
+	for i, dependency := range m.dependencies {
+		wg.Add(1)
+		go func(i int, dependency Dependency) {
+			defer wg.Done()
+			statuses[i] = m.check(ctx, dependency, maxAttempts)
+		}(i, dependency)
+	}
+	wg.Wait()
+
+	return statuses
+}
+
+// check runs the check of a dependency until it succeeds or the attempts are exhausted
+func (m *DependencyManager) check(ctx context.Context, dependency Dependency, maxAttempts int) domain.DependencyStatus {
+	status := domain.DependencyStatus{
+		Name:        dependency.Name,
+		Criticality: dependency.Criticality,
+	}
+
+	backoffPolicy := retry.Policy{InitialBackoff: m.policy.InitialBackoff, MaxBackoff: m.policy.MaxBackoff}
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		status.Attempts = attempt
+		start := time.Now()
+		err = m.checkOnce(ctx, dependency)
+		status.Latency = time.Since(start)
+		if err == nil {
+			break
+		}
+		if attempt == maxAttempts {
+			break
+		}
+
+		backoff := backoffPolicy.Backoff(attempt)
+		m.logger.Warn("dependency check failed, retrying",
+			zap.String("dependency", dependency.Name),
+			zap.Int("attempt", attempt),
+			zap.Int("max_attempts", maxAttempts),
+			zap.Duration("backoff", backoff),
+			zap.Error(err),
+		)
+		if sleepErr := retry.Sleep(ctx, backoff); sleepErr != nil {
+			err = errors.Join(err, sleepErr)
+			break
+		}
+	}
+
+	status.Healthy = err == nil
+	if err != nil {
+		status.Error = err.Error()
+	}
+	status.CheckedAt = time.Now()
+	return status
+}
+
+// checkOnce runs a single check bounded by the check timeout
+func (m *DependencyManager) checkOnce(ctx context.Context, dependency Dependency) error {
+	if m.policy.CheckTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.policy.CheckTimeout)
+		defer cancel()
+	}
+	return dependency.Check(ctx)
+}
+
+// dependencyNames returns the names of the dependencies with the given health
+func dependencyNames(statuses []domain.DependencyStatus, healthy bool) []string {
+	names := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		if status.Healthy == healthy {
+			names = append(names, status.Name)
+		}
+	}
+	return names
+}
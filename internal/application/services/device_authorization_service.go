@@ -0,0 +1,314 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+)
+
+// userCodeAlphabet avoids vowels and ambiguous characters as recommended by RFC 8628 section 6.1
+const userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+
+// slowDownIncrement is added to the polling interval every time a device polls too fast (RFC 8628 section 3.5)
+const slowDownIncrement = 5 * time.Second
+
+// DeviceAuthorizationServiceInterface defines the methods of DeviceAuthorizationService used by handlers.
+type DeviceAuthorizationServiceInterface interface {
+	RequestDeviceCode(ctx context.Context, clientID, clientSecret string, scopes []string) (*domain.DeviceAuthorization, error)
+	GetByUserCode(ctx context.Context, userCode string) (*domain.DeviceAuthorization, error)
+	RequiresConsent(ctx context.Context, auth *domain.DeviceAuthorization, idCitizen int) (bool, error)
+	Verify(ctx context.Context, userCode string, idCitizen int, approve bool) error
+	PollToken(ctx context.Context, clientID, clientSecret, deviceCode string) (*domain.TokenPair, error)
+	VerificationURI() string
+}
+
+// DeviceAuthorizationService handles the OAuth2 device authorization grant (RFC 8628).
+// Registered clients are confidential, they authenticate with their secret to request and poll device codes.
+type DeviceAuthorizationService struct {
+	oauth2Service   OAuth2ServiceInterface
+	deviceRepo      ports.DeviceAuthorizationRepository
+	authService     *AuthService
+	consentService  ConsentServiceInterface
+	codeDuration    time.Duration
+	pollInterval    time.Duration
+	verificationURI string
+	logger          *zap.Logger
+}
+
+// NewDeviceAuthorizationService creates a new instance of DeviceAuthorizationService
+func NewDeviceAuthorizationService(
+	oauth2Service OAuth2ServiceInterface,
+	deviceRepo ports.DeviceAuthorizationRepository,
+	authService *AuthService,
+	consentService ConsentServiceInterface,
+	codeDuration time.Duration,
+	pollInterval time.Duration,
+	verificationURI string,
+	logger *zap.Logger,
+) *DeviceAuthorizationService {
+	return &DeviceAuthorizationService{
+		oauth2Service:   oauth2Service,
+		deviceRepo:      deviceRepo,
+		authService:     authService,
+		consentService:  consentService,
+		codeDuration:    codeDuration,
+		pollInterval:    pollInterval,
+		verificationURI: verificationURI,
+		logger:          logger,
+	}
+}
+
+// VerificationURI returns the URI users visit to enter their user code
+func (s *DeviceAuthorizationService) VerificationURI() string {
+	return s.verificationURI
+}
+
+// RequestDeviceCode authenticates the client and issues a new device_code/user_code pair for it
+func (s *DeviceAuthorizationService) RequestDeviceCode(ctx context.Context, clientID, clientSecret string, scopes []string) (*domain.DeviceAuthorization, error) {
+	client, err := s.oauth2Service.AuthenticateClient(ctx, clientID, clientSecret)
+	if err != nil {
+		s.logger.Warn("device code requested by a client that failed to authenticate", zap.Error(err), logging.String("client_id", clientID))
+		return nil, err
+	}
+
+	if !client.AllowsGrantType(domain.GrantTypeDeviceCode) {
+		s.logger.Warn("device code requested by client not allowed to use the device_code grant", logging.String("client_id", clientID))
+		return nil, domainerrors.ErrUnauthorizedClient
+	}
+
+	// Requested scopes must be a subset of the client's scopes; default to all of them
+	if len(scopes) == 0 {
+		scopes = client.Scopes
+	}
+	for _, scope := range scopes {
+		if !client.HasScope(scope) {
+			s.logger.Warn("device code requested with scope not granted to client",
+				logging.String("client_id", clientID),
+				logging.String("scope", scope))
+			return nil, domainerrors.ErrBadRequest
+		}
+	}
+
+	deviceCode, err := generateDeviceCode()
+	if err != nil {
+		s.logger.Error("failed to generate device code", zap.Error(err))
+		return nil, internalError(err)
+	}
+
+	userCode, err := generateUserCode()
+	if err != nil {
+		s.logger.Error("failed to generate user code", zap.Error(err))
+		return nil, internalError(err)
+	}
+
+	now := time.Now()
+	auth := &domain.DeviceAuthorization{
+		DeviceCode: deviceCode,
+		UserCode:   userCode,
+		ClientID:   client.ClientID,
+		Scopes:     scopes,
+		Status:     domain.DeviceAuthorizationPending,
+		Interval:   s.pollInterval,
+		ExpiresAt:  now.Add(s.codeDuration),
+		CreatedAt:  now,
+	}
+
+	if err := s.deviceRepo.Store(ctx, auth); err != nil {
+		s.logger.Error("failed to store device authorization", zap.Error(err), logging.String("client_id", clientID))
+		return nil, internalError(err)
+	}
+
+	s.logger.Info("device authorization issued", logging.String("client_id", clientID))
+	return auth, nil
+}
+
+// GetByUserCode retrieves a pending device authorization so the verification page can describe it
+func (s *DeviceAuthorizationService) GetByUserCode(ctx context.Context, userCode string) (*domain.DeviceAuthorization, error) {
+	auth, err := s.deviceRepo.GetByUserCode(ctx, normalizeUserCode(userCode))
+	if err != nil {
+		return nil, err
+	}
+
+	if auth.IsExpired() || auth.Status != domain.DeviceAuthorizationPending {
+		return nil, domainerrors.ErrInvalidUserCode
+	}
+
+	return auth, nil
+}
+
+// RequiresConsent reports whether the user must review the requested scopes before approving.
+// Users that already consented to every requested scope for the client are not prompted again.
+func (s *DeviceAuthorizationService) RequiresConsent(ctx context.Context, auth *domain.DeviceAuthorization, idCitizen int) (bool, error) {
+	return s.consentService.RequiresConsent(ctx, idCitizen, auth.ClientID, auth.Scopes)
+}
+
+// Verify records the decision of the logged-in user for the given user code
+func (s *DeviceAuthorizationService) Verify(ctx context.Context, userCode string, idCitizen int, approve bool) error {
+	auth, err := s.GetByUserCode(ctx, userCode)
+	if err != nil {
+		s.logger.Warn("device verification with invalid user code", zap.Int("id_citizen", idCitizen), zap.Error(err))
+		return err
+	}
+
+	if approve {
+		if err := s.consentService.RecordConsent(ctx, idCitizen, auth.ClientID, auth.Scopes); err != nil {
+			s.logger.Error("failed to record consent", zap.Error(err), zap.Int("id_citizen", idCitizen))
+			return err
+		}
+		auth.Status = domain.DeviceAuthorizationApproved
+		auth.IDCitizen = idCitizen
+	} else {
+		auth.Status = domain.DeviceAuthorizationDenied
+	}
+
+	if err := s.deviceRepo.Update(ctx, auth); err != nil {
+		if errors.Is(err, domainerrors.ErrDeviceCodeExpired) {
+			return domainerrors.ErrInvalidUserCode
+		}
+		s.logger.Error("failed to update device authorization", zap.Error(err))
+		return internalError(err)
+	}
+
+	s.logger.Info("device authorization verified",
+		logging.String("client_id", auth.ClientID),
+		zap.Int("id_citizen", idCitizen),
+		zap.String("status", string(auth.Status)))
+	return nil
+}
+
+// PollToken authenticates the client and exchanges an approved device code for a token pair.
+// It returns ErrAuthorizationPending until the user decides and ErrSlowDown when polled too often.
+func (s *DeviceAuthorizationService) PollToken(ctx context.Context, clientID, clientSecret, deviceCode string) (*domain.TokenPair, error) {
+	// The client may have been deactivated or its grant types changed since it requested the device code
+	client, err := s.oauth2Service.AuthenticateClient(ctx, clientID, clientSecret)
+	if err != nil {
+		s.logger.Warn("device code polled by a client that failed to authenticate", zap.Error(err), logging.String("client_id", clientID))
+		return nil, err
+	}
+	if !client.AllowsGrantType(domain.GrantTypeDeviceCode) {
+		s.logger.Warn("client not allowed to use the device_code grant", logging.String("client_id", clientID))
+		return nil, domainerrors.ErrUnauthorizedClient
+	}
+
+	auth, err := s.deviceRepo.GetByDeviceCode(ctx, deviceCode)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrDeviceCodeExpired) {
+			return nil, err
+		}
+		s.logger.Error("failed to get device authorization", zap.Error(err))
+		return nil, internalError(err)
+	}
+
+	if auth.ClientID != clientID {
+		s.logger.Warn("device code presented by a different client", logging.String("client_id", clientID))
+		return nil, domainerrors.ErrInvalidClient
+	}
+
+	if auth.IsExpired() {
+		return nil, domainerrors.ErrDeviceCodeExpired
+	}
+
+	switch auth.Status {
+	case domain.DeviceAuthorizationDenied:
+		s.deleteAuthorization(ctx, auth)
+		return nil, domainerrors.ErrAccessDenied
+
+	case domain.DeviceAuthorizationApproved:
+		// Device codes are single use: only the poll that consumes the code gets tokens
+		consumed, err := s.deviceRepo.Consume(ctx, deviceCode)
+		if err != nil {
+			if errors.Is(err, domainerrors.ErrDeviceCodeExpired) {
+				return nil, err
+			}
+			s.logger.Error("failed to consume device authorization", zap.Error(err))
+			return nil, internalError(err)
+		}
+		if consumed.Status != domain.DeviceAuthorizationApproved || consumed.ClientID != clientID {
+			return nil, domainerrors.ErrDeviceCodeExpired
+		}
+
+		// The tokens only grant the scopes the user approved, to the client they were approved for
+		delegation := &domain.DelegatedAccess{ClientID: consumed.ClientID, Scopes: consumed.Scopes}
+		tokenPair, err := s.authService.IssueTokenPair(ctx, consumed.IDCitizen, client.TokenProfile, delegation)
+		if err != nil {
+			s.logger.Error("failed to issue tokens for device", zap.Error(err), logging.String("client_id", clientID))
+			return nil, err
+		}
+
+		s.logger.Info("device authorization completed", logging.String("client_id", clientID), zap.Int("id_citizen", consumed.IDCitizen))
+		return tokenPair, nil
+
+	default:
+		now := time.Now()
+		tooSoon := auth.PolledTooSoon(now)
+		if tooSoon {
+			auth.Interval += slowDownIncrement
+		}
+		auth.LastPolledAt = now
+
+		// Only the polling fields are written, and only while the code is still pending,
+		// so a concurrent approval is never reverted
+		if err := s.deviceRepo.RecordPoll(ctx, deviceCode, auth.LastPolledAt, auth.Interval); err != nil && !errors.Is(err, domainerrors.ErrDeviceCodeExpired) {
+			s.logger.Error("failed to record device poll", zap.Error(err))
+		}
+
+		if tooSoon {
+			return nil, domainerrors.ErrSlowDown
+		}
+		return nil, domainerrors.ErrAuthorizationPending
+	}
+}
+
+// deleteAuthorization removes a device authorization (best effort)
+func (s *DeviceAuthorizationService) deleteAuthorization(ctx context.Context, auth *domain.DeviceAuthorization) {
+	if err := s.deviceRepo.Delete(ctx, auth); err != nil {
+		s.logger.Error("failed to delete device authorization", zap.Error(err))
+	}
+}
+
+// generateDeviceCode generates a high-entropy opaque device code
+func generateDeviceCode() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// generateUserCode generates a short user code formatted as XXXX-XXXX
+func generateUserCode() (string, error) {
+	var sb strings.Builder
+	alphabetSize := big.NewInt(int64(len(userCodeAlphabet)))
+	for i := 0; i < 8; i++ {
+		if i == 4 {
+			sb.WriteByte('-')
+		}
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate user code: %w", err)
+		}
+		sb.WriteByte(userCodeAlphabet[n.Int64()])
+	}
+	return sb.String(), nil
+}
+
+// normalizeUserCode makes user code input case and dash insensitive
+func normalizeUserCode(userCode string) string {
+	code := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(userCode), "-", ""))
+	if len(code) == 8 {
+		return code[:4] + "-" + code[4:]
+	}
+	return code
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// DurableTokenRepository decorates a token repository so that refresh tokens are persisted in a durable
+// store, with the decorated repository as their cache. Refresh tokens missing from the cache, e.g. after
+// a Redis flush, are read from the store and cached again, so users are not signed out. Blacklists and
+// token versions are kept in the decorated repository only.
+type DurableTokenRepository struct {
+	ports.TokenRepository
+	store  ports.RefreshTokenStore
+	logger *zap.Logger
+}
+
+// NewDurableTokenRepository creates a new instance of DurableTokenRepository
+func NewDurableTokenRepository(cache ports.TokenRepository, store ports.RefreshTokenStore, logger *zap.Logger) *DurableTokenRepository {
+	return &DurableTokenRepository{
+		TokenRepository: cache,
+		store:           store,
+		logger:          logger,
+	}
+}
+
+// StoreRefreshToken stores the refresh token in the store and then caches it. A failure to cache it is
+// only logged, the token is read from the store on the next use.
+func (r *DurableTokenRepository) StoreRefreshToken(ctx context.Context, token string, data *domain.RefreshTokenData, ttl time.Duration) error {
+	if err := r.store.Store(ctx, hashSecret(token), data, time.Now().Add(ttl)); err != nil {
+		return err
+	}
+
+	if err := r.TokenRepository.StoreRefreshToken(ctx, token, data, ttl); err != nil {
+		r.logger.Warn("failed to cache refresh token", zap.Error(err), zap.Int("id_citizen", data.IDCitizen))
+	}
+	return nil
+}
+
+// GetRefreshToken retrieves the data of a refresh token from the cache, or from the store when it is
+// not cached
+func (r *DurableTokenRepository) GetRefreshToken(ctx context.Context, token string) (*domain.RefreshTokenData, error) {
+	data, err := r.TokenRepository.GetRefreshToken(ctx, token)
+	if !errors.Is(err, domainerrors.ErrInvalidToken) {
+		return data, err
+	}
+	return r.load(ctx, token)
+}
+
+// GetActiveRefreshToken retrieves a refresh token that is not blacklisted from the cache, or from the
+// store when it is not cached
+func (r *DurableTokenRepository) GetActiveRefreshToken(ctx context.Context, token string) (*domain.RefreshTokenData, error) {
+	data, err := r.TokenRepository.GetActiveRefreshToken(ctx, token)
+	if !errors.Is(err, domainerrors.ErrInvalidToken) {
+		return data, err
+	}
+	return r.load(ctx, token)
+}
+
+// DeleteRefreshToken deletes the refresh token from the store and the cache
+func (r *DurableTokenRepository) DeleteRefreshToken(ctx context.Context, token string) error {
+	if err := r.store.Delete(ctx, hashSecret(token)); err != nil {
+		return err
+	}
+	return r.TokenRepository.DeleteRefreshToken(ctx, token)
+}
+
+// RotateRefreshToken replaces the refresh token in the store and then in the cache
+func (r *DurableTokenRepository) RotateRefreshToken(ctx context.Context, oldToken, newToken string, data *domain.RefreshTokenData, ttl time.Duration) error {
+	if err := r.store.Rotate(ctx, hashSecret(oldToken), hashSecret(newToken), data, time.Now().Add(ttl)); err != nil {
+		return err
+	}
+	return r.TokenRepository.RotateRefreshToken(ctx, oldToken, newToken, data, ttl)
+}
+
+// RevokeSession deletes the refresh token from the store, then blacklists the access token and deletes
+// the refresh token from the cache
+func (r *DurableTokenRepository) RevokeSession(ctx context.Context, accessToken string, ttl time.Duration, refreshToken string) error {
+	if refreshToken != "" {
+		if err := r.store.Delete(ctx, hashSecret(refreshToken)); err != nil {
+			return err
+		}
+	}
+	return r.TokenRepository.RevokeSession(ctx, accessToken, ttl, refreshToken)
+}
+
+// DeleteUserTokens deletes all refresh tokens of a user from the store and the cache
+func (r *DurableTokenRepository) DeleteUserTokens(ctx context.Context, userID string, idCitizen int) error {
+	if err := r.store.DeleteByUser(ctx, userID, idCitizen); err != nil {
+		return err
+	}
+	return r.TokenRepository.DeleteUserTokens(ctx, userID, idCitizen)
+}
+
+// CountActiveSessions counts the refresh tokens of a user in the store, which holds them all
+func (r *DurableTokenRepository) CountActiveSessions(ctx context.Context, idCitizen int) (int, error) {
+	return r.store.CountActive(ctx, idCitizen)
+}
+
+// load reads a refresh token from the store and caches it again for the rest of its lifetime
+func (r *DurableTokenRepository) load(ctx context.Context, token string) (*domain.RefreshTokenData, error) {
+	data, expiresAt, err := r.store.Get(ctx, hashSecret(token))
+	if err != nil {
+		return nil, err
+	}
+
+	if ttl := time.Until(expiresAt); ttl > 0 {
+		if err := r.TokenRepository.StoreRefreshToken(ctx, token, data, ttl); err != nil {
+			r.logger.Warn("failed to cache refresh token", zap.Error(err), zap.Int("id_citizen", data.IDCitizen))
+		}
+	}
+
+	r.logger.Debug("refresh token restored from durable store", zap.Int("id_citizen", data.IDCitizen))
+	return data, nil
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+)
+
+// EmailChangeServiceInterface defines the methods of EmailChangeService used by handlers.
+type EmailChangeServiceInterface interface {
+	RequestEmailChange(ctx context.Context, idCitizen int, email string) (*domain.User, error)
+	ConfirmEmailChange(ctx context.Context, token string) error
+}
+
+// EmailChangeService changes the primary email of users. The new address only replaces the current one
+// once it is confirmed with a single-use link sent to it, and the current address is alerted of the
+// request, so a hijacked session can't silently take over the account recovery.
+type EmailChangeService struct {
+	userRepo          ports.UserRepository
+	emailRepo         ports.UserEmailRepository
+	publisher         ports.MessagePublisher
+	notificationQueue string
+	emailLimiter      ports.RateLimiter
+	tokenDuration     time.Duration
+	logger            *zap.Logger
+}
+
+// NewEmailChangeService creates a new instance of EmailChangeService
+func NewEmailChangeService(
+	userRepo ports.UserRepository,
+	emailRepo ports.UserEmailRepository,
+	publisher ports.MessagePublisher,
+	notificationQueue string,
+	emailLimiter ports.RateLimiter,
+	tokenDuration time.Duration,
+	logger *zap.Logger,
+) *EmailChangeService {
+	return &EmailChangeService{
+		userRepo:          userRepo,
+		emailRepo:         emailRepo,
+		publisher:         publisher,
+		notificationQueue: notificationQueue,
+		emailLimiter:      emailLimiter,
+		tokenDuration:     tokenDuration,
+		logger:            logger,
+	}
+}
+
+// RequestEmailChange records the new email as pending, sends a confirmation link to it and alerts the
+// current email. A new request replaces the pending one. It returns the user with the pending change.
+func (s *EmailChangeService) RequestEmailChange(ctx context.Context, idCitizen int, email string) (*domain.User, error) {
+	address, ok := domain.NormalizeEmail(email)
+	if !ok {
+		return nil, domainerrors.ErrInvalidEmail
+	}
+
+	user, err := s.userRepo.GetByIDCitizen(ctx, idCitizen)
+	if err != nil {
+		s.logger.Error("failed to get user", zap.Error(err), zap.Int("id_citizen", idCitizen))
+		return nil, err
+	}
+	if strings.EqualFold(user.Email, address) {
+		return nil, domainerrors.ErrEmailAlreadyRegistered
+	}
+
+	if err := s.checkAvailable(ctx, user, address); err != nil {
+		return nil, err
+	}
+
+	if err := reserveEmail(ctx, s.emailLimiter, address, s.logger); err != nil {
+		return nil, err
+	}
+
+	token, err := generateResetToken()
+	if err != nil {
+		s.logger.Error("failed to generate email change token", zap.Error(err))
+		return nil, internalError(err)
+	}
+
+	user.SetPendingEmail(address, hashSecret(token), time.Now().Add(s.tokenDuration))
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		s.logger.Error("failed to save pending email", zap.Error(err), zap.String("user_id", user.ID))
+		return nil, internalError(err)
+	}
+
+	err = publishEmailNotification(ctx, s.publisher, s.notificationQueue, user, address, domain.NotificationEmailChangeConfirmation, map[string]string{
+		"token":              token,
+		"expires_in_minutes": strconv.Itoa(int(s.tokenDuration.Minutes())),
+	})
+	if err != nil {
+		s.logger.Error("failed to publish email change confirmation", zap.Error(err), zap.String("user_id", user.ID))
+		user.ClearPendingEmail()
+		if updateErr := s.userRepo.Update(ctx, user); updateErr != nil {
+			s.logger.Error("failed to discard pending email", zap.Error(updateErr), zap.String("user_id", user.ID))
+		}
+		return nil, domainerrors.ErrInternal
+	}
+
+	// The current address is the one that can tell if the change is legitimate
+	err = publishEmailNotification(ctx, s.publisher, s.notificationQueue, user, user.Email, domain.NotificationEmailChangeRequested, map[string]string{
+		"new_email": domain.MaskEmail(address),
+	})
+	if err != nil {
+		s.logger.Error("failed to publish email change alert", zap.Error(err), zap.String("user_id", user.ID))
+	}
+
+	s.logger.Info("email change requested",
+		zap.String("user_id", user.ID),
+		logging.String("email", domain.MaskEmail(address)))
+	return user, nil
+}
+
+// ConfirmEmailChange replaces the email of the user with the pending one confirmed by the token.
+// Tokens are single use, an expired token discards the pending change.
+func (s *EmailChangeService) ConfirmEmailChange(ctx context.Context, token string) error {
+	user, err := s.userRepo.GetByPendingEmailToken(ctx, hashSecret(token))
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrUserNotFound) {
+			return domainerrors.ErrInvalidEmailChangeToken
+		}
+		s.logger.Error("failed to get user by email change token", zap.Error(err))
+		return internalError(err)
+	}
+
+	if !user.HasPendingEmail(time.Now()) {
+		user.ClearPendingEmail()
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			s.logger.Error("failed to discard expired pending email", zap.Error(err), zap.String("user_id", user.ID))
+		}
+		return domainerrors.ErrInvalidEmailChangeToken
+	}
+	if !user.IsActive() {
+		return domainerrors.ErrInvalidEmailChangeToken
+	}
+
+	previous := user.ConfirmPendingEmail()
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		// Another account took the address while the change was pending
+		if errors.Is(err, domainerrors.ErrUserAlreadyExists) {
+			return domainerrors.ErrEmailAlreadyRegistered
+		}
+		s.logger.Error("failed to update user email", zap.Error(err), zap.String("user_id", user.ID))
+		return internalError(err)
+	}
+
+	s.logger.Info("email changed",
+		zap.String("user_id", user.ID),
+		logging.String("previous_email", domain.MaskEmail(previous)),
+		logging.String("email", domain.MaskEmail(user.Email)))
+	return nil
+}
+
+// checkAvailable checks that the address is neither the email of another user nor a verified secondary
+// email of another user, which would make the owner of password reset links ambiguous
+func (s *EmailChangeService) checkAvailable(ctx context.Context, user *domain.User, address string) error {
+	exists, err := s.userRepo.Exists(ctx, address)
+	if err != nil {
+		s.logger.Error("failed to check user existence", zap.Error(err))
+		return internalError(err)
+	}
+	if exists {
+		return domainerrors.ErrEmailAlreadyRegistered
+	}
+
+	userEmail, err := s.emailRepo.GetVerified(ctx, address)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrEmailNotFound) {
+			return nil
+		}
+		s.logger.Error("failed to get verified user email", zap.Error(err))
+		return internalError(err)
+	}
+	if userEmail.UserID != user.ID {
+		return domainerrors.ErrEmailAlreadyRegistered
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
+)
+
+// Exported datasets, used as metric labels and audit targets
+const (
+	exportDatasetUsers    = "users"
+	exportDatasetAuditLog = "audit_log"
+)
+
+// ExportServiceInterface defines the methods of ExportService used by handlers.
+type ExportServiceInterface interface {
+	ExportUsers(ctx context.Context, filter domain.UserExportFilter, actor string, fn func(*domain.User) error) error
+	ExportAuditLog(ctx context.Context, filter domain.AuditLogFilter, actor string, fn func(*domain.AuditRecord) error) error
+}
+
+// ExportService streams the users and the audit log to administrators, so compliance teams can pull
+// datasets without database access. Every export is recorded in the audit log.
+type ExportService struct {
+	userRepo  ports.UserExportRepository
+	auditLog  ports.AuditLogQueryRepository
+	auditRepo ports.AuditLogRepository
+	logger    *zap.Logger
+}
+
+// NewExportService creates a new instance of ExportService
+func NewExportService(
+	userRepo ports.UserExportRepository,
+	auditLog ports.AuditLogQueryRepository,
+	auditRepo ports.AuditLogRepository,
+	logger *zap.Logger,
+) *ExportService {
+	return &ExportService{
+		userRepo:  userRepo,
+		auditLog:  auditLog,
+		auditRepo: auditRepo,
+		logger:    logger,
+	}
+}
+
+// ExportUsers calls fn for every user matching the filter, oldest first. Users carry no password.
+// An error returned by fn (e.g. the client disconnected) stops the export and is returned as is.
+func (s *ExportService) ExportUsers(ctx context.Context, filter domain.UserExportFilter, actor string, fn func(*domain.User) error) error {
+	if err := filter.Validate(); err != nil {
+		s.logger.Warn("invalid users export filter", zap.Error(err))
+		return domainerrors.ErrInvalidExportFilter
+	}
+
+	records := 0
+	var fnErr error
+	err := s.userRepo.StreamUsers(ctx, filter, func(user *domain.User) error {
+		user.Password = ""
+		if fnErr = fn(user); fnErr != nil {
+			return fnErr
+		}
+		records++
+		return nil
+	})
+
+	details := map[string]string{}
+	if filter.Role != "" {
+		details["role"] = filter.Role.String()
+	}
+	if filter.Status != "" {
+		details["status"] = filter.Status.String()
+	}
+	addTimeDetail(details, "created_after", filter.CreatedAfter)
+	addTimeDetail(details, "created_before", filter.CreatedBefore)
+
+	return s.finishExport(ctx, exportDatasetUsers, actor, details, records, err, fnErr)
+}
+
+// ExportAuditLog calls fn for every audit record matching the filter, oldest first.
+// An error returned by fn (e.g. the client disconnected) stops the export and is returned as is.
+func (s *ExportService) ExportAuditLog(ctx context.Context, filter domain.AuditLogFilter, actor string, fn func(*domain.AuditRecord) error) error {
+	if err := filter.Validate(); err != nil {
+		s.logger.Warn("invalid audit log export filter", zap.Error(err))
+		return domainerrors.ErrInvalidExportFilter
+	}
+
+	records := 0
+	var fnErr error
+	err := s.auditLog.StreamRecords(ctx, filter, func(record *domain.AuditRecord) error {
+		if fnErr = fn(record); fnErr != nil {
+			return fnErr
+		}
+		records++
+		return nil
+	})
+
+	details := map[string]string{}
+	if filter.Action != "" {
+		details["action"] = filter.Action.String()
+	}
+	if filter.Actor != "" {
+		details["actor"] = filter.Actor
+	}
+	if filter.TargetID != "" {
+		details["target_id"] = filter.TargetID
+	}
+	addTimeDetail(details, "after", filter.After)
+	addTimeDetail(details, "before", filter.Before)
+
+	return s.finishExport(ctx, exportDatasetAuditLog, actor, details, records, err, fnErr)
+}
+
+// finishExport counts the export in metrics and writes its audit record (best effort). Incomplete exports
+// are recorded too, since the records already streamed have left the service.
+func (s *ExportService) finishExport(ctx context.Context, dataset, actor string, details map[string]string, records int, err, fnErr error) error {
+	result := "completed"
+	if err != nil {
+		result = "failed"
+	}
+	metrics.IncAdminExports(dataset, result, records)
+
+	details["records"] = strconv.Itoa(records)
+	details["completed"] = strconv.FormatBool(err == nil)
+	record := domain.NewAuditRecord(domain.AuditActionDatasetExported, actor, dataset, details)
+	// The request context is canceled when the client disconnects mid-export, the record is written anyway
+	if auditErr := s.auditRepo.Record(context.WithoutCancel(ctx), record); auditErr != nil {
+		s.logger.Error("failed to write audit record", zap.Error(auditErr), zap.String("dataset", dataset))
+	}
+
+	switch {
+	case fnErr != nil:
+		return fnErr
+	case err != nil:
+		s.logger.Error("failed to export dataset", zap.Error(err), zap.String("dataset", dataset), zap.Int("records", records))
+		return internalError(err)
+	}
+
+	s.logger.Info("dataset exported", zap.String("dataset", dataset), zap.String("actor", actor), zap.Int("records", records))
+	return nil
+}
+
+// addTimeDetail adds a time filter to the audit details when it is set
+func addTimeDetail(details map[string]string, key string, value time.Time) {
+	if !value.IsZero() {
+		details[key] = value.UTC().Format(time.RFC3339)
+	}
+}
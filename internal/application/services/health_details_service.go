@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"runtime"
+	"sync"
+
+	"go.uber.org/zap"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// HealthGauge is an operational measure reported by the detailed health, e.g. the depth of a queue
+type HealthGauge struct {
+	Name string
+	Read func(ctx context.Context) (int64, error)
+}
+
+// HealthDetailsProvider reports the detailed health of the service
+type HealthDetailsProvider interface {
+	Details(ctx context.Context) *domain.HealthDetails
+}
+
+// HealthDetailsService reports the status of the dependencies along with the gauges operators look at
+// when the service misbehaves: queue depths, outbox backlog and goroutines
+type HealthDetailsService struct {
+	dependencies DependencyChecker
+	gauges       []HealthGauge
+	logger       *zap.Logger
+}
+
+// NewHealthDetailsService creates a new instance of HealthDetailsService
+func NewHealthDetailsService(dependencies DependencyChecker, logger *zap.Logger, gauges ...HealthGauge) *HealthDetailsService {
+	return &HealthDetailsService{
+		dependencies: dependencies,
+		gauges:       gauges,
+		logger:       logger,
+	}
+}
+
+// Details checks the dependencies and reads the gauges concurrently. A gauge that can't be read is
+// reported with its error, it doesn't change the status of the service.
+func (s *HealthDetailsService) Details(ctx context.Context) *domain.HealthDetails {
+	details := &domain.HealthDetails{
+		Gauges:     make([]domain.GaugeReading, len(s.gauges)),
+		Goroutines: runtime.NumGoroutine(),
+	}
+
+	var wg sync.WaitGroup
+	for i, gauge := range s.gauges {
+		wg.Add(1)
+		go func(i int, gauge HealthGauge) {
+			defer wg.Done()
+			details.Gauges[i] = s.read(ctx, gauge)
+		}(i, gauge)
+	}
+	details.Dependencies = s.dependencies.CheckDependencies(ctx)
+	wg.Wait()
+
+	details.Status = domain.OverallHealthStatus(details.Dependencies)
+	return details
+}
+
+// read reads a gauge, logging the failures
+func (s *HealthDetailsService) read(ctx context.Context, gauge HealthGauge) domain.GaugeReading {
+	reading := domain.GaugeReading{Name: gauge.Name}
+	value, err := gauge.Read(ctx)
+	if err != nil {
+		s.logger.Warn("failed to read health gauge", zap.String("gauge", gauge.Name), zap.Error(err))
+		reading.Error = err.Error()
+		return reading
+	}
+	reading.Value = value
+	return reading
+}
@@ -0,0 +1,21 @@
+package services
+
+import (
+	"errors"
+
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+)
+
+// internalError returns the error reported for an unexpected failure of a dependency, which has been logged
+// by the caller. A database with an exhausted connection pool is reported as ErrDatabaseBusy so clients
+// know they can retry later, an update that lost a race with another one as ErrVersionConflict so they
+// can reload the resource and retry, anything else as ErrInternal.
+func internalError(err error) error {
+	if errors.Is(err, domainerrors.ErrDatabaseBusy) {
+		return domainerrors.ErrDatabaseBusy
+	}
+	if errors.Is(err, domainerrors.ErrVersionConflict) {
+		return domainerrors.ErrVersionConflict
+	}
+	return domainerrors.ErrInternal
+}
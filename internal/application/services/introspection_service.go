@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+)
+
+// IntrospectionServiceInterface defines the methods of IntrospectionService used by handlers.
+type IntrospectionServiceInterface interface {
+	Introspect(ctx context.Context, clientID, clientSecret, token string) (*domain.TokenIntrospection, error)
+}
+
+// IntrospectionService reports the state of user and client tokens to resource servers and the API gateway
+type IntrospectionService struct {
+	authService   AuthServiceInterface
+	oauth2Service OAuth2ServiceInterface
+	rateLimiter   ports.RateLimiter
+	logger        *zap.Logger
+}
+
+// NewIntrospectionService creates a new instance of IntrospectionService
+func NewIntrospectionService(
+	authService AuthServiceInterface,
+	oauth2Service OAuth2ServiceInterface,
+	rateLimiter ports.RateLimiter,
+	logger *zap.Logger,
+) *IntrospectionService {
+	return &IntrospectionService{
+		authService:   authService,
+		oauth2Service: oauth2Service,
+		rateLimiter:   rateLimiter,
+		logger:        logger,
+	}
+}
+
+// Introspect authenticates the calling client and describes the given token.
+// Invalid, expired or revoked tokens are reported as inactive rather than as errors (RFC 7662 section 2.2).
+func (s *IntrospectionService) Introspect(ctx context.Context, clientID, clientSecret, token string) (*domain.TokenIntrospection, error) {
+	if _, err := s.oauth2Service.AuthenticateClient(ctx, clientID, clientSecret); err != nil {
+		return nil, err
+	}
+
+	introspection, rateLimitKey := s.describe(ctx, token)
+	if !introspection.Active {
+		s.logger.Debug("introspected inactive token", logging.String("client_id", clientID))
+		return introspection, nil
+	}
+
+	status, err := s.rateLimiter.Peek(ctx, rateLimitKey)
+	if err != nil {
+		// Rate-limit numbers are informative, introspection must not fail because of them
+		s.logger.Warn("failed to get rate limit status", zap.Error(err), logging.String("key", rateLimitKey))
+	} else {
+		introspection.RateLimit = status
+	}
+
+	return introspection, nil
+}
+
+// describe validates the token as a user access token first and as a client token otherwise
+func (s *IntrospectionService) describe(ctx context.Context, token string) (*domain.TokenIntrospection, string) {
+	if token == "" {
+		return &domain.TokenIntrospection{Active: false}, ""
+	}
+
+	if claims, err := s.authService.ValidateAccessToken(ctx, token); err == nil {
+		// Minimal access tokens carry no email, resource servers get it from the introspection
+		if claims.Email == "" {
+			if user, err := s.authService.GetUserByIDCitizen(ctx, claims.IDCitizen); err == nil {
+				claims.Email = user.Email
+			} else {
+				s.logger.Warn("failed to get user of introspected token", zap.Error(err), zap.Int("id_citizen", claims.IDCitizen))
+			}
+		}
+
+		return &domain.TokenIntrospection{
+			Active:    true,
+			Subject:   strconv.Itoa(claims.IDCitizen),
+			Email:     claims.Email,
+			Role:      claims.Role,
+			TokenType: claims.Type,
+		}, domain.UserRateLimitKey(claims.IDCitizen)
+	}
+
+	if claims, err := s.oauth2Service.ValidateAccessToken(ctx, token); err == nil {
+		introspection := &domain.TokenIntrospection{
+			Active:    true,
+			Subject:   claims.ClientID,
+			ClientID:  claims.ClientID,
+			Scopes:    claims.Scopes,
+			TokenType: claims.Type,
+			IssuedAt:  claims.IssuedAt,
+			ExpiresAt: claims.ExpireAt,
+		}
+		if claims.Confirmation != nil {
+			introspection.CertificateThumbprint = claims.Confirmation.CertificateThumbprint
+		}
+		return introspection, domain.ClientRateLimitKey(claims.ClientID)
+	}
+
+	return &domain.TokenIntrospection{Active: false}, ""
+}
@@ -1,82 +1,174 @@
 package services
 
 import (
+	"crypto/rand"
+	"encoding/base64"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 
 	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
 	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
 )
 
+// opaqueRefreshTokenBytes is the entropy of the opaque refresh tokens
+const opaqueRefreshTokenBytes = 32
+
+// minimalAccessTokenType is the "typ" header of the minimal access tokens (RFC 9068), which tells them
+// apart from the other tokens since they carry no "type" claim
+const minimalAccessTokenType = "at+jwt"
+
 // JWTService handles the generation and validation of JWT tokens
 type JWTService struct {
 	secret               []byte
 	accessTokenDuration  time.Duration
 	refreshTokenDuration time.Duration
+	opaqueRefreshTokens  bool
+	metadataClaims       []string
+	signing              TokenSigningPolicy
 	logger               *zap.Logger
 }
 
 // CustomClaims extends the standard JWT claims
 type CustomClaims struct {
-	IDCitizen int         `json:"id_citizen"`
-	Email     string      `json:"email"`
-	Role      domain.Role `json:"role"`
-	Type      string      `json:"type"`
+	IDCitizen int                 `json:"id_citizen"`
+	UserID    string              `json:"uid,omitempty"`
+	Email     string              `json:"email"`
+	Role      domain.Role         `json:"role"`
+	Type      string              `json:"type"`
+	Metadata  domain.UserMetadata `json:"metadata,omitempty"`
+	SudoUntil int64               `json:"sudo_until,omitempty"`
+	Version   int                 `json:"tv,omitempty"`
+	Region    string              `json:"region,omitempty"`
+	ClientID  string              `json:"client_id,omitempty"`
+	Scopes    []string            `json:"scopes,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// NewJWTService creates a new instance of JWTService
-func NewJWTService(secret string, accessDuration, refreshDuration time.Duration, logger *zap.Logger) *JWTService {
+// MinimalClaims are the claims of the access tokens of the minimal token profile: sub (the citizen ID),
+// exp, jti and role, plus client_id and scopes for delegated tokens
+type MinimalClaims struct {
+	Role     domain.Role `json:"role"`
+	Version  int         `json:"tv,omitempty"`
+	ClientID string      `json:"client_id,omitempty"`
+	Scopes   []string    `json:"scopes,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// NewJWTService creates a new instance of JWTService.
+// With opaqueRefreshTokens, refresh tokens are random strings whose claims are only stored server-side,
+// so they leak no information; access tokens remain JWTs.
+// The user metadata keys in metadataClaims are copied to the "metadata" claim of the access tokens of users.
+// signing defines the algorithm and key ID of the issued tokens and which tokens are accepted.
+func NewJWTService(secret string, accessDuration, refreshDuration time.Duration, opaqueRefreshTokens bool, metadataClaims []string, signing TokenSigningPolicy, logger *zap.Logger) *JWTService {
 	return &JWTService{
 		secret:               []byte(secret),
 		accessTokenDuration:  accessDuration,
 		refreshTokenDuration: refreshDuration,
+		opaqueRefreshTokens:  opaqueRefreshTokens,
+		metadataClaims:       metadataClaims,
+		signing:              signing,
 		logger:               logger,
 	}
 }
 
 // GenerateAccessToken generates a new access token
 func (s *JWTService) GenerateAccessToken(idCitizen int, email string, role domain.Role) (string, error) {
-	return s.generateToken(idCitizen, email, role, domain.TokenTypeAccess, s.accessTokenDuration)
+	return s.generateToken(idCitizen, "", email, role, 0, nil, nil, domain.TokenTypeAccess, s.accessTokenDuration)
 }
 
 // GenerateRefreshToken generates a new refresh token
 func (s *JWTService) GenerateRefreshToken(idCitizen int, email string, role domain.Role) (string, error) {
-	return s.generateToken(idCitizen, email, role, domain.TokenTypeRefresh, s.refreshTokenDuration)
+	return s.generateRefreshToken(idCitizen, "", email, role, 0)
 }
 
 // GenerateTokenPair generates a token pair (access and refresh)
 func (s *JWTService) GenerateTokenPair(idCitizen int, email string, role domain.Role) (*domain.TokenPair, error) {
-	accessToken, err := s.GenerateAccessToken(idCitizen, email, role)
+	return s.generateTokenPair(idCitizen, "", email, role, 0, nil, nil)
+}
+
+// GenerateUserTokenPair generates a token pair carrying the ID of the user and the allowlisted metadata,
+// so gateways can identify the user without a lookup. Both tokens carry the token version of the user.
+func (s *JWTService) GenerateUserTokenPair(user *domain.User) (*domain.TokenPair, error) {
+	return s.generateTokenPair(user.IDCitizen, user.ID, user.Email, user.Role, user.TokenVersion, user.Metadata.Select(s.metadataClaims), nil)
+}
+
+// GenerateUserTokenPairWithProfile generates a token pair whose access token carries the claims of the
+// token profile. Refresh tokens are never sent to resource servers, so they are the same for every profile.
+// With a delegation, the access token carries its client_id and scopes claims.
+func (s *JWTService) GenerateUserTokenPairWithProfile(user *domain.User, profile domain.TokenProfile, delegation *domain.DelegatedAccess) (*domain.TokenPair, error) {
+	if !profile.IsMinimal() {
+		return s.generateTokenPair(user.IDCitizen, user.ID, user.Email, user.Role, user.TokenVersion, user.Metadata.Select(s.metadataClaims), delegation)
+	}
+
+	accessToken, err := s.generateMinimalAccessToken(user.IDCitizen, user.Role, user.TokenVersion, delegation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate access token: %w", err)
+	}
+
+	refreshToken, err := s.generateRefreshToken(user.IDCitizen, user.ID, user.Email, user.Role, user.TokenVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	return s.newTokenPair(accessToken, refreshToken), nil
+}
+
+// GenerateSudoToken generates an access token of the user granting elevated access for the given duration,
+// after which it expires
+func (s *JWTService) GenerateSudoToken(user *domain.User, duration time.Duration) (*domain.SudoGrant, error) {
+	now := time.Now()
+	sudoUntil := now.Add(duration).Truncate(time.Second)
+
+	claims := s.newCustomClaims(user.IDCitizen, user.ID, user.Email, user.Role, user.Metadata.Select(s.metadataClaims), domain.TokenTypeAccess, now, sudoUntil)
+	claims.SudoUntil = sudoUntil.Unix()
+	claims.Version = user.TokenVersion
+
+	token, err := s.signToken(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Debug("sudo token generated successfully",
+		zap.Int("id_citizen", user.IDCitizen),
+		zap.Time("sudo_until", sudoUntil))
+
+	return &domain.SudoGrant{AccessToken: token, SudoUntil: sudoUntil}, nil
+}
+
+// generateTokenPair generates an access token carrying the metadata and the delegation, and a refresh token
+func (s *JWTService) generateTokenPair(idCitizen int, userID, email string, role domain.Role, tokenVersion int, metadata domain.UserMetadata, delegation *domain.DelegatedAccess) (*domain.TokenPair, error) {
+	accessToken, err := s.generateToken(idCitizen, userID, email, role, tokenVersion, metadata, delegation, domain.TokenTypeAccess, s.accessTokenDuration)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
 
-	refreshToken, err := s.GenerateRefreshToken(idCitizen, email, role)
+	refreshToken, err := s.generateRefreshToken(idCitizen, userID, email, role, tokenVersion)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
 	}
 
+	return s.newTokenPair(accessToken, refreshToken), nil
+}
+
+// newTokenPair returns the token pair of the given tokens
+func (s *JWTService) newTokenPair(accessToken, refreshToken string) *domain.TokenPair {
 	return &domain.TokenPair{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
 		TokenType:    domain.TokenTypeBearer,
 		ExpiresIn:    int64(s.accessTokenDuration.Seconds()),
-	}, nil
+	}
 }
 
 // ValidateToken validates a token and returns the claims
 func (s *JWTService) ValidateToken(tokenString string) (*domain.TokenClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, func(token *jwt.Token) (interface{}, error) {
-		// Verify the signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return s.secret, nil
-	})
+	token, err := s.signing.parser().ParseWithClaims(tokenString, &CustomClaims{}, s.signing.keyFunc(s.secret))
 
 	if err != nil {
 		s.logger.Debug("token validation failed", zap.Error(err))
@@ -92,16 +184,38 @@ func (s *JWTService) ValidateToken(tokenString string) (*domain.TokenClaims, err
 		return nil, domainerrors.ErrInvalidToken
 	}
 
+	if claim := s.signing.missingClaim(token, claims); claim != "" {
+		s.logger.Debug("token validation failed", zap.String("missing_claim", claim))
+		return nil, domainerrors.ErrInvalidToken
+	}
+
 	// Verify expiration
 	if claims.ExpiresAt != nil && claims.ExpiresAt.Before(time.Now()) {
 		return nil, domainerrors.ErrExpiredToken
 	}
 
+	// Minimal access tokens only identify the user by the subject
+	if claims.Type == "" && token.Header["typ"] == minimalAccessTokenType {
+		idCitizen, err := strconv.Atoi(claims.Subject)
+		if err != nil {
+			return nil, domainerrors.ErrInvalidToken
+		}
+		claims.IDCitizen = idCitizen
+		claims.Type = domain.TokenTypeAccess
+	}
+
 	return &domain.TokenClaims{
 		IDCitizen: claims.IDCitizen,
+		UserID:    claims.UserID,
 		Email:     claims.Email,
 		Role:      claims.Role,
 		Type:      claims.Type,
+		SudoUntil: claims.SudoUntil,
+
+		TokenVersion: claims.Version,
+
+		ClientID: claims.ClientID,
+		Scopes:   claims.Scopes,
 	}, nil
 }
 
@@ -135,9 +249,7 @@ func (s *JWTService) ValidateRefreshToken(tokenString string) (*domain.TokenClai
 
 // GetTokenExpiration returns the expiration time of a token
 func (s *JWTService) GetTokenExpiration(tokenString string) (time.Time, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return s.secret, nil
-	})
+	token, err := s.signing.parser().ParseWithClaims(tokenString, &CustomClaims{}, s.signing.keyFunc(s.secret))
 
 	if err != nil {
 		return time.Time{}, err
@@ -151,36 +263,159 @@ func (s *JWTService) GetTokenExpiration(tokenString string) (time.Time, error) {
 	return claims.ExpiresAt.Time, nil
 }
 
+// RefreshTokenDuration returns the lifetime of the refresh tokens
+func (s *JWTService) RefreshTokenDuration() time.Duration {
+	return s.refreshTokenDuration
+}
+
+// Decode returns the header and the claims of a token without validating it
+func (s *JWTService) Decode(tokenString string) (map[string]interface{}, map[string]interface{}, error) {
+	claims := jwt.MapClaims{}
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, claims)
+	if err != nil {
+		return nil, nil, domainerrors.ErrInvalidToken
+	}
+	return token.Header, claims, nil
+}
+
+// VerifySignature checks the algorithm, the key ID and the signature of a token, but not its claims
+func (s *JWTService) VerifySignature(tokenString string) error {
+	_, err := s.signing.parser(jwt.WithoutClaimsValidation()).Parse(tokenString, s.signing.keyFunc(s.secret))
+	return err
+}
+
+// generateRefreshToken generates a JWT refresh token, or an opaque one when enabled.
+// Opaque refresh tokens carry no token version, the stored session data holds it.
+func (s *JWTService) generateRefreshToken(idCitizen int, userID, email string, role domain.Role, tokenVersion int) (string, error) {
+	if !s.opaqueRefreshTokens {
+		return s.generateToken(idCitizen, userID, email, role, tokenVersion, nil, nil, domain.TokenTypeRefresh, s.refreshTokenDuration)
+	}
+
+	token := make([]byte, opaqueRefreshTokenBytes)
+	if _, err := rand.Read(token); err != nil {
+		s.logger.Error("failed to generate opaque refresh token", zap.Error(err))
+		return "", fmt.Errorf("failed to generate opaque refresh token: %w", err)
+	}
+	return domain.OpaqueRefreshTokenPrefix + base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+// generateMinimalAccessToken generates an access token of the minimal token profile
+func (s *JWTService) generateMinimalAccessToken(idCitizen int, role domain.Role, tokenVersion int, delegation *domain.DelegatedAccess) (string, error) {
+	expiresAt := time.Now().Add(s.accessTokenDuration)
+
+	claims := MinimalClaims{
+		Role:    role,
+		Version: tokenVersion,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.Itoa(idCitizen),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			ID:        s.signing.newTokenID(),
+		},
+	}
+	if delegation != nil {
+		claims.ClientID = delegation.ClientID
+		claims.Scopes = delegation.Scopes
+	}
+
+	token := s.signing.newToken(claims)
+	token.Header["typ"] = minimalAccessTokenType
+	tokenString, err := token.SignedString(s.secret)
+	if err != nil {
+		s.logger.Error("failed to sign token", zap.Error(err), zap.String("type", domain.TokenTypeAccess))
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+	s.signing.checkSize(tokenString, domain.TokenTypeAccess, s.logger, zap.Int("id_citizen", idCitizen))
+
+	s.logger.Debug("minimal access token generated successfully",
+		zap.Int("id_citizen", idCitizen),
+		zap.Time("expires_at", expiresAt))
+
+	return tokenString, nil
+}
+
 // generateToken is a helper method to generate tokens
-func (s *JWTService) generateToken(idCitizen int, email string, role domain.Role, tokenType string, duration time.Duration) (string, error) {
+func (s *JWTService) generateToken(idCitizen int, userID, email string, role domain.Role, tokenVersion int, metadata domain.UserMetadata, delegation *domain.DelegatedAccess, tokenType string, duration time.Duration) (string, error) {
 	now := time.Now()
 	expiresAt := now.Add(duration)
 
-	claims := CustomClaims{
+	claims := s.newCustomClaims(idCitizen, userID, email, role, metadata, tokenType, now, expiresAt)
+	claims.Version = tokenVersion
+	if delegation != nil {
+		claims.ClientID = delegation.ClientID
+		claims.Scopes = delegation.Scopes
+	}
+	tokenString, err := s.signToken(claims)
+	if err != nil {
+		return "", err
+	}
+
+	s.logger.Debug("token generated successfully",
+		zap.String("type", tokenType),
+		zap.Int("id_citizen", idCitizen),
+		zap.Time("expires_at", expiresAt))
+
+	return tokenString, nil
+}
+
+// newCustomClaims returns the claims of a token issued now and valid until expiresAt
+func (s *JWTService) newCustomClaims(idCitizen int, userID, email string, role domain.Role, metadata domain.UserMetadata, tokenType string, now, expiresAt time.Time) CustomClaims {
+	return CustomClaims{
 		IDCitizen: idCitizen,
+		UserID:    userID,
 		Email:     email,
 		Role:      role,
 		Type:      tokenType,
+		Metadata:  metadata,
+		Region:    s.signing.Region,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
 			Issuer:    "auth-microservice",
 			Subject:   fmt.Sprintf("%d", idCitizen),
+			ID:        s.signing.newTokenID(),
 		},
 	}
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+// signToken signs the claims with the token signing key
+func (s *JWTService) signToken(claims CustomClaims) (string, error) {
+	token := s.signing.newToken(claims)
 	tokenString, err := token.SignedString(s.secret)
 	if err != nil {
-		s.logger.Error("failed to sign token", zap.Error(err), zap.String("type", tokenType))
+		s.logger.Error("failed to sign token", zap.Error(err), zap.String("type", claims.Type))
 		return "", fmt.Errorf("failed to sign token: %w", err)
 	}
+	if claims.Type == domain.TokenTypeAccess {
+		s.signing.checkSize(tokenString, claims.Type, s.logger, zap.Int("id_citizen", claims.IDCitizen))
+	}
+	return tokenString, nil
+}
+
+// maxEmailLength is the longest email address (RFC 5321), used to size the largest access token of a user
+const maxEmailLength = 254
+
+// CheckTokenSize checks that the access tokens of users fit the maximum token size, at least without
+// the values of their metadata claims. It returns an error otherwise, since every token would exceed it.
+func (s *JWTService) CheckTokenSize() error {
+	if s.signing.MaxTokenSize <= 0 {
+		return nil
+	}
 
-	s.logger.Debug("token generated successfully",
-		zap.String("type", tokenType),
-		zap.Int("id_citizen", idCitizen),
-		zap.Time("expires_at", expiresAt))
+	metadata := make(domain.UserMetadata, len(s.metadataClaims))
+	for _, key := range s.metadataClaims {
+		metadata[key] = ""
+	}
+	now := time.Now()
+	claims := s.newCustomClaims(1<<31-1, uuid.New().String(), strings.Repeat("a", maxEmailLength), domain.RoleAdmin, metadata, domain.TokenTypeAccess, now, now.Add(s.accessTokenDuration))
+	claims.Version = 1<<31 - 1
 
-	return tokenString, nil
+	tokenString, err := s.signing.newToken(claims).SignedString(s.secret)
+	if err != nil {
+		return fmt.Errorf("failed to sign token: %w", err)
+	}
+	if s.signing.exceedsMaxSize(tokenString) {
+		return fmt.Errorf("access tokens of users are %d bytes, over the maximum token size of %d", len(tokenString), s.signing.MaxTokenSize)
+	}
+	return nil
 }
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// Component is a background part of the service that is started after the dependencies are
+// available and stopped on shutdown (message consumers, relays, etc.)
+type Component interface {
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+}
+
+type namedComponent struct {
+	name      string
+	component Component
+}
+
+// LifecycleManager starts the registered components in registration order and stops them in reverse order
+type LifecycleManager struct {
+	components []namedComponent
+	started    []namedComponent
+	logger     *zap.Logger
+}
+
+// NewLifecycleManager creates a new instance of LifecycleManager
+func NewLifecycleManager(logger *zap.Logger) *LifecycleManager {
+	return &LifecycleManager{logger: logger}
+}
+
+// Register adds a component, it must be called before Start
+func (m *LifecycleManager) Register(name string, component Component) {
+	m.components = append(m.components, namedComponent{name: name, component: component})
+}
+
+// Start starts every component. When a component fails to start, the components already started
+// are stopped and the error is returned.
+func (m *LifecycleManager) Start(ctx context.Context) error {
+	for _, c := range m.components {
+		if err := c.component.Start(ctx); err != nil {
+			startErr := fmt.Errorf("failed to start %s: %w", c.name, err)
+			if stopErr := m.Stop(ctx); stopErr != nil {
+				return errors.Join(startErr, stopErr)
+			}
+			return startErr
+		}
+		m.started = append(m.started, c)
+		m.logger.Info("component started", zap.String("component", c.name))
+	}
+	return nil
+}
+
+// Stop stops the started components in reverse order. Every component is stopped even if
+// another one fails, and the errors are joined.
+func (m *LifecycleManager) Stop(ctx context.Context) error {
+	var errs []error
+	for _, step := range m.Shutdown(ctx) {
+		if step.Err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop %s: %w", step.Name, step.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Shutdown stops the started components in reverse order, like Stop, and reports the outcome of each one
+func (m *LifecycleManager) Shutdown(ctx context.Context) []ShutdownStep {
+	steps := make([]ShutdownStep, 0, len(m.started))
+	for i := len(m.started) - 1; i >= 0; i-- {
+		c := m.started[i]
+		step := StopComponent(ctx, c.name, c.component)
+		steps = append(steps, step)
+		if step.Err != nil {
+			m.logger.Error("failed to stop component", zap.String("component", c.name), zap.Error(step.Err))
+			continue
+		}
+		m.logger.Info("component stopped", zap.String("component", c.name), zap.Duration("duration", step.Duration))
+	}
+	m.started = nil
+	return steps
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
+)
+
+// LoginRiskEvaluator assesses the risk of a successful login before tokens are issued
+type LoginRiskEvaluator interface {
+	// EvaluateLogin returns the risk of the login, or nil when it cannot be assessed.
+	// It never fails the login.
+	EvaluateLogin(ctx context.Context, user *domain.User) *domain.LoginRisk
+}
+
+// LoginAnomalyService detects geographic anomalies ("impossible travel") on login.
+// The location of every login is compared with the recent history of the user; risky logins
+// are reported to the user through a mandatory security notification.
+type LoginAnomalyService struct {
+	locator     ports.GeoLocator
+	historyRepo ports.LoginHistoryRepository
+	notifier    NotificationServiceInterface
+	policy      domain.TravelPolicy
+	window      time.Duration
+	logger      *zap.Logger
+}
+
+// NewLoginAnomalyService creates a new instance of LoginAnomalyService
+func NewLoginAnomalyService(
+	locator ports.GeoLocator,
+	historyRepo ports.LoginHistoryRepository,
+	notifier NotificationServiceInterface,
+	policy domain.TravelPolicy,
+	window time.Duration,
+	logger *zap.Logger,
+) *LoginAnomalyService {
+	return &LoginAnomalyService{
+		locator:     locator,
+		historyRepo: historyRepo,
+		notifier:    notifier,
+		policy:      policy,
+		window:      window,
+		logger:      logger,
+	}
+}
+
+// EvaluateLogin locates the client of the login and compares it with the logins of the user
+// within the history window. Lookup and storage failures are logged and yield a nil risk.
+func (s *LoginAnomalyService) EvaluateLogin(ctx context.Context, user *domain.User) *domain.LoginRisk {
+	info, ok := domain.ClientInfoFromContext(ctx)
+	if !ok || info.IP == "" {
+		return nil
+	}
+
+	location, err := s.locator.Locate(ctx, info.IP)
+	if err != nil {
+		if !errors.Is(err, domainerrors.ErrLocationNotFound) {
+			s.logger.Error("failed to locate login", zap.Error(err), zap.String("user_id", user.ID))
+		}
+		return nil
+	}
+
+	now := time.Now()
+	current := &domain.LoginLocation{IP: info.IP, Location: *location, At: now}
+	risk := &domain.LoginRisk{
+		IP:         info.IP,
+		Country:    location.Country,
+		City:       location.City,
+		AssessedAt: now,
+	}
+
+	history, err := s.historyRepo.Recent(ctx, user.ID, now.Add(-s.window))
+	if err != nil {
+		s.logger.Error("failed to get login history", zap.Error(err), zap.String("user_id", user.ID))
+		history = nil
+	}
+
+	// History is most recent first; the first anomalous login is the most relevant one
+	for _, previous := range history {
+		reason, distance, speed := s.policy.Evaluate(*previous, *current)
+		if reason == "" {
+			continue
+		}
+		risk.Risky = true
+		risk.Reasons = []string{reason}
+		risk.PreviousIP = previous.IP
+		risk.PreviousCountry = previous.Location.Country
+		risk.PreviousLoginAt = previous.At
+		risk.DistanceKm = distance
+		risk.SpeedKmh = speed
+		break
+	}
+
+	if err := s.historyRepo.Record(ctx, user.ID, current); err != nil {
+		s.logger.Error("failed to record login location", zap.Error(err), zap.String("user_id", user.ID))
+	}
+
+	s.logger.Info("login risk assessed",
+		zap.String("user_id", user.ID),
+		zap.Int("id_citizen", user.IDCitizen),
+		zap.Bool("risky", risk.Risky),
+		zap.Strings("reasons", risk.Reasons),
+		zap.String("ip", risk.IP),
+		zap.String("country", risk.Country),
+		zap.String("city", risk.City),
+		zap.String("previous_country", risk.PreviousCountry),
+		zap.Float64("distance_km", risk.DistanceKm),
+		zap.Float64("speed_kmh", risk.SpeedKmh))
+
+	if risk.Risky {
+		metrics.IncLoginAnomalies(risk.Reasons[0])
+		s.notifySuspiciousLogin(ctx, user, risk)
+	}
+
+	return risk
+}
+
+// notifySuspiciousLogin notifies the user about a risky login (best effort)
+func (s *LoginAnomalyService) notifySuspiciousLogin(ctx context.Context, user *domain.User, risk *domain.LoginRisk) {
+	details := map[string]string{
+		"reason":           risk.Reasons[0],
+		"ip":               risk.IP,
+		"country":          risk.Country,
+		"city":             risk.City,
+		"previous_country": risk.PreviousCountry,
+		"distance_km":      strconv.FormatFloat(risk.DistanceKm, 'f', 0, 64),
+		"assessed_at":      risk.AssessedAt.UTC().Format(time.RFC3339),
+	}
+
+	if err := s.notifier.Notify(ctx, user, domain.NotificationSuspiciousLogin, details); err != nil {
+		s.logger.Error("failed to notify suspicious login", zap.Error(err), zap.String("user_id", user.ID))
+	}
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	"github.com/kristianrpo/auth-microservice/internal/domain/events"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
+)
+
+// LoginNotifier notifies users of their successful logins
+type LoginNotifier interface {
+	// NotifyLogin is called with the assessment of every login that is not denied. It never fails the login.
+	NotifyLogin(ctx context.Context, user *domain.User, assessment *domain.RiskAssessment)
+}
+
+// Results of the login notifications reported in the metrics
+const (
+	loginNotificationSent       = "sent"
+	loginNotificationSuppressed = "suppressed"
+	loginNotificationFailed     = "failed"
+)
+
+// LoginNotificationService notifies users of logins from a device or IP address they did not use recently,
+// through the channels enabled in the configuration: a login notification event and/or a new_device
+// security notification the notifier delivers by email. Users opt out with their new_device preference.
+// New devices are detected by the risk policy; IP addresses are remembered among the known devices of the
+// user under their own identifiers.
+type LoginNotificationService struct {
+	notifier  NotificationServiceInterface
+	ipRepo    ports.KnownDeviceRepository
+	publisher ports.MessagePublisher
+	queue     string
+	channels  []domain.LoginNotificationChannel
+	logger    *zap.Logger
+}
+
+// NewLoginNotificationService creates a new instance of LoginNotificationService
+func NewLoginNotificationService(
+	notifier NotificationServiceInterface,
+	ipRepo ports.KnownDeviceRepository,
+	publisher ports.MessagePublisher,
+	queue string,
+	channels []domain.LoginNotificationChannel,
+	logger *zap.Logger,
+) *LoginNotificationService {
+	return &LoginNotificationService{
+		notifier:  notifier,
+		ipRepo:    ipRepo,
+		publisher: publisher,
+		queue:     queue,
+		channels:  channels,
+		logger:    logger,
+	}
+}
+
+// NotifyLogin notifies the user when the login comes from a new device or IP address (best effort)
+func (s *LoginNotificationService) NotifyLogin(ctx context.Context, user *domain.User, assessment *domain.RiskAssessment) {
+	if len(s.channels) == 0 || assessment == nil {
+		return
+	}
+
+	info, _ := domain.ClientInfoFromContext(ctx)
+	newDevice := assessment.Signals.NewDevice
+	newIP := s.rememberIP(ctx, user, info.IP)
+	if !newDevice && !newIP {
+		return
+	}
+
+	allowed, err := s.notifier.Allows(ctx, user, domain.NotificationNewDevice)
+	if err != nil {
+		s.logger.Error("failed to check login notification preferences", zap.Error(err), zap.String("user_id", user.ID))
+		return
+	}
+	if !allowed {
+		for _, channel := range s.channels {
+			metrics.IncLoginNotifications(string(channel), loginNotificationSuppressed)
+		}
+		s.logger.Debug("login notification suppressed by user preferences", zap.String("user_id", user.ID))
+		return
+	}
+
+	notification := domain.NewLoginNotification(info, assessment.Geo, newDevice, newIP, time.Now())
+	for _, channel := range s.channels {
+		var err error
+		switch channel {
+		case domain.LoginNotificationChannelEvent:
+			err = s.publishEvent(ctx, user, notification)
+		case domain.LoginNotificationChannelEmail:
+			err = s.notifier.Notify(ctx, user, domain.NotificationNewDevice, notification.Details())
+		}
+
+		if err != nil {
+			metrics.IncLoginNotifications(string(channel), loginNotificationFailed)
+			s.logger.Error("failed to send login notification",
+				zap.Error(err),
+				zap.String("channel", string(channel)),
+				zap.String("user_id", user.ID))
+			continue
+		}
+		metrics.IncLoginNotifications(string(channel), loginNotificationSent)
+	}
+
+	s.logger.Info("login notification sent",
+		zap.String("user_id", user.ID),
+		zap.Bool("new_device", newDevice),
+		zap.Bool("new_ip", newIP),
+		zap.String("network", notification.Network))
+}
+
+// rememberIP reports whether the IP address is new for the user and remembers it. A failing store is
+// logged and the address taken as known, so outages don't flood users with notifications.
+func (s *LoginNotificationService) rememberIP(ctx context.Context, user *domain.User, ip string) bool {
+	if ip == "" {
+		return false
+	}
+
+	id := domain.KnownIPDeviceID(ip)
+	known, err := s.ipRepo.IsKnown(ctx, user.ID, id)
+	if err != nil {
+		s.logger.Error("failed to check known IP address", zap.Error(err), zap.String("user_id", user.ID))
+		return false
+	}
+	if known {
+		return false
+	}
+
+	if err := s.ipRepo.Remember(ctx, user.ID, id); err != nil {
+		s.logger.Error("failed to remember IP address", zap.Error(err), zap.String("user_id", user.ID))
+	}
+	return true
+}
+
+// publishEvent publishes the login notification event
+func (s *LoginNotificationService) publishEvent(ctx context.Context, user *domain.User, notification *domain.LoginNotification) error {
+	event := events.NewLoginNotificationEvent(user, notification)
+	eventData, err := event.ToJSON()
+	if err != nil {
+		return err
+	}
+
+	if err := s.publisher.Publish(ctx, s.queue, eventData); err != nil {
+		return err
+	}
+
+	s.logger.Info("login notification event published", zap.String("message_id", event.MessageID), zap.String("user_id", user.ID))
+	return nil
+}
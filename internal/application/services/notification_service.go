@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	"github.com/kristianrpo/auth-microservice/internal/domain/events"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// NotificationServiceInterface defines the methods of NotificationService used by handlers and other services.
+type NotificationServiceInterface interface {
+	GetPreferences(ctx context.Context, idCitizen int) (*domain.NotificationPreferences, error)
+	UpdatePreferences(ctx context.Context, idCitizen int, newDevice, passwordChange, loginAlert *bool) (*domain.NotificationPreferences, error)
+	Notify(ctx context.Context, user *domain.User, notificationType domain.NotificationType, details map[string]string) error
+	Allows(ctx context.Context, user *domain.User, notificationType domain.NotificationType) (bool, error)
+}
+
+// NotificationService manages user notification preferences and dispatches security notifications
+type NotificationService struct {
+	userRepo          ports.UserRepository
+	prefsRepo         ports.NotificationPreferencesRepository
+	publisher         ports.MessagePublisher
+	notificationQueue string
+	logger            *zap.Logger
+}
+
+// NewNotificationService creates a new instance of NotificationService
+func NewNotificationService(
+	userRepo ports.UserRepository,
+	prefsRepo ports.NotificationPreferencesRepository,
+	publisher ports.MessagePublisher,
+	notificationQueue string,
+	logger *zap.Logger,
+) *NotificationService {
+	return &NotificationService{
+		userRepo:          userRepo,
+		prefsRepo:         prefsRepo,
+		publisher:         publisher,
+		notificationQueue: notificationQueue,
+		logger:            logger,
+	}
+}
+
+// GetPreferences retrieves the notification preferences of a user, falling back to defaults
+func (s *NotificationService) GetPreferences(ctx context.Context, idCitizen int) (*domain.NotificationPreferences, error) {
+	user, err := s.userRepo.GetByIDCitizen(ctx, idCitizen)
+	if err != nil {
+		s.logger.Error("failed to get user", zap.Error(err), zap.Int("id_citizen", idCitizen))
+		return nil, err
+	}
+
+	return s.preferencesFor(ctx, user.ID)
+}
+
+// UpdatePreferences updates the notification preferences of a user.
+// Nil flags keep their current value.
+func (s *NotificationService) UpdatePreferences(ctx context.Context, idCitizen int, newDevice, passwordChange, loginAlert *bool) (*domain.NotificationPreferences, error) {
+	user, err := s.userRepo.GetByIDCitizen(ctx, idCitizen)
+	if err != nil {
+		s.logger.Error("failed to get user", zap.Error(err), zap.Int("id_citizen", idCitizen))
+		return nil, err
+	}
+
+	prefs, err := s.preferencesFor(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if newDevice != nil {
+		prefs.NewDevice = *newDevice
+	}
+	if passwordChange != nil {
+		prefs.PasswordChange = *passwordChange
+	}
+	if loginAlert != nil {
+		prefs.LoginAlert = *loginAlert
+	}
+
+	if err := s.prefsRepo.Upsert(ctx, prefs); err != nil {
+		s.logger.Error("failed to save notification preferences", zap.Error(err), zap.String("user_id", user.ID))
+		return nil, internalError(err)
+	}
+
+	s.logger.Info("notification preferences updated", zap.String("user_id", user.ID))
+	return prefs, nil
+}
+
+// Notify publishes a security notification for the user if their preferences allow it
+func (s *NotificationService) Notify(ctx context.Context, user *domain.User, notificationType domain.NotificationType, details map[string]string) error {
+	prefs, err := s.preferencesFor(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	if !prefs.Allows(notificationType) {
+		s.logger.Debug("notification suppressed by user preferences",
+			zap.String("user_id", user.ID),
+			zap.String("type", string(notificationType)))
+		return nil
+	}
+
+	event := events.NewSecurityNotificationEvent(string(notificationType), user.IDCitizen, user.Name, user.Email, details)
+	eventData, err := event.ToJSON()
+	if err != nil {
+		s.logger.Error("failed to serialize security notification event", zap.Error(err))
+		return internalError(err)
+	}
+
+	if err := s.publisher.Publish(ctx, s.notificationQueue, eventData); err != nil {
+		s.logger.Error("failed to publish security notification", zap.Error(err), zap.String("user_id", user.ID))
+		return err
+	}
+
+	s.logger.Info("security notification published",
+		zap.String("message_id", event.MessageID),
+		zap.String("type", string(notificationType)),
+		zap.String("user_id", user.ID))
+	return nil
+}
+
+// Allows reports whether the preferences of the user accept notifications of the given type
+func (s *NotificationService) Allows(ctx context.Context, user *domain.User, notificationType domain.NotificationType) (bool, error) {
+	prefs, err := s.preferencesFor(ctx, user.ID)
+	if err != nil {
+		return false, err
+	}
+	return prefs.Allows(notificationType), nil
+}
+
+// preferencesFor loads stored preferences or returns the defaults when none exist
+func (s *NotificationService) preferencesFor(ctx context.Context, userID string) (*domain.NotificationPreferences, error) {
+	prefs, err := s.prefsRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrPreferencesNotFound) {
+			return domain.DefaultNotificationPreferences(userID), nil
+		}
+		s.logger.Error("failed to get notification preferences", zap.Error(err), zap.String("user_id", userID))
+		return nil, internalError(err)
+	}
+
+	return prefs, nil
+}
@@ -2,7 +2,10 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand/v2"
+	"slices"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -12,113 +15,367 @@ import (
 	"github.com/kristianrpo/auth-microservice/internal/application/ports"
 	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
 	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
 	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
 )
 
 // OAuth2Service handles OAuth2 Client Credentials flow
 type OAuth2Service struct {
 	clientRepo        ports.OAuthClientRepository
+	scopeRepo         ports.ScopeRepository
 	jwtSecret         string
 	accessTokenExpiry time.Duration
+	ttlJitterPercent  int
+	quotaEnforcer     QuotaEnforcer
+	requestVerifier   ClientRequestVerifier
+	clientTokenRepo   ports.ClientTokenRepository
+	usage             ClientUsageTracker
+	lockout           ClientLockoutGuard
+	signing           TokenSigningPolicy
+	secretHasher      ports.PasswordHasher
 	logger            *zap.Logger
 }
 
 // OAuth2ServiceInterface defines the subset of methods used by handlers so tests can inject mocks.
 type OAuth2ServiceInterface interface {
-	CreateClient(ctx context.Context, clientID, clientSecret, name, description string, scopes []string) (*domain.OAuthClient, error)
-	ListClients(ctx context.Context) ([]*domain.OAuthClient, error)
-	ClientCredentials(ctx context.Context, clientID, clientSecret string) (string, int64, error)
+	CreateClient(ctx context.Context, clientID, clientSecret, name, description string, scopes, grantTypes, redirectURIs, tags []string, secretExpiresAt *time.Time) (*domain.OAuthClient, error)
+	ListClients(ctx context.Context, tags []string) ([]*domain.OAuthClient, error)
+	UpdateClient(ctx context.Context, id string, expectedVersion int, name, description *string, scopes []string, tokenProfile *domain.TokenProfile, grantTypes, redirectURIs, tags []string) (*domain.OAuthClient, error)
+	ClientCredentials(ctx context.Context, clientID, clientSecret string, signed *domain.SignedClientRequest, certThumbprint string) (string, time.Time, error)
+	AddRedirectURI(ctx context.Context, id, redirectURI string) (*domain.OAuthClient, error)
+	RemoveRedirectURI(ctx context.Context, id, redirectURI string) (*domain.OAuthClient, error)
+	ResolveRedirectURI(ctx context.Context, clientID, redirectURI string) (string, error)
+	SetSignedRequests(ctx context.Context, id string, required bool) (*domain.OAuthClient, error)
+	SetSecretExpiry(ctx context.Context, id string, expiresAt *time.Time) (*domain.OAuthClient, error)
+	ListExpiringSecrets(ctx context.Context, within time.Duration) ([]*domain.OAuthClient, error)
+	AuthenticateClient(ctx context.Context, clientID, clientSecret string) (*domain.OAuthClient, error)
 	ValidateAccessToken(ctx context.Context, tokenString string) (*domain.OAuthTokenClaims, error)
 	GetClient(ctx context.Context, id string) (*domain.OAuthClient, error)
 	DeleteClient(ctx context.Context, id string) error
+	RevokeClientTokens(ctx context.Context, id string) (int, error)
+	ListClientUsage(ctx context.Context) (map[string]*domain.ClientUsage, error)
+	ListClientLockouts(ctx context.Context, clientIDs []string) (map[string]*domain.ClientLockout, error)
 }
 
-// NewOAuth2Service creates a new instance of OAuth2Service
+// OAuth2ServiceOption configures an optional dependency of OAuth2Service
+type OAuth2ServiceOption func(*OAuth2Service)
+
+// WithClientTokenTTLJitter shortens the lifetime of each client token by a random amount of up to
+// ttlJitterPercent of the access token expiry, so clients caching their tokens don't all refresh at the
+// same time. Without it every token lives for the full expiry.
+func WithClientTokenTTLJitter(ttlJitterPercent int) OAuth2ServiceOption {
+	return func(s *OAuth2Service) {
+		s.ttlJitterPercent = ttlJitterPercent
+	}
+}
+
+// WithClientQuotaEnforcer enforces the token issuance quotas of the clients
+func WithClientQuotaEnforcer(quotaEnforcer QuotaEnforcer) OAuth2ServiceOption {
+	return func(s *OAuth2Service) {
+		s.quotaEnforcer = quotaEnforcer
+	}
+}
+
+// WithClientRequestVerifier verifies the signed token requests of the clients that require them
+func WithClientRequestVerifier(requestVerifier ClientRequestVerifier) OAuth2ServiceOption {
+	return func(s *OAuth2Service) {
+		s.requestVerifier = requestVerifier
+	}
+}
+
+// WithClientTokenRepository tracks the issued client tokens so they can be revoked. Without it client
+// tokens cannot be revoked before they expire.
+func WithClientTokenRepository(clientTokenRepo ports.ClientTokenRepository) OAuth2ServiceOption {
+	return func(s *OAuth2Service) {
+		s.clientTokenRepo = clientTokenRepo
+	}
+}
+
+// WithClientUsageTracker records the tokens issued to the clients and their failed requests
+func WithClientUsageTracker(usage ClientUsageTracker) OAuth2ServiceOption {
+	return func(s *OAuth2Service) {
+		s.usage = usage
+	}
+}
+
+// WithClientLockout locks clients out after repeated authentication failures
+func WithClientLockout(lockout ClientLockoutGuard) OAuth2ServiceOption {
+	return func(s *OAuth2Service) {
+		s.lockout = lockout
+	}
+}
+
+// WithClientSecretHasher hashes and verifies the client secrets with the configured password hasher.
+// Without it client secrets are hashed with bcrypt.
+func WithClientSecretHasher(secretHasher ports.PasswordHasher) OAuth2ServiceOption {
+	return func(s *OAuth2Service) {
+		s.secretHasher = secretHasher
+	}
+}
+
+// NewOAuth2Service creates a new instance of OAuth2Service with its required dependencies, the optional
+// ones are set with options.
+// signing defines the algorithm and key ID of the client tokens and which tokens are accepted.
 func NewOAuth2Service(
 	clientRepo ports.OAuthClientRepository,
+	scopeRepo ports.ScopeRepository,
 	jwtSecret string,
 	accessTokenExpiry time.Duration,
+	signing TokenSigningPolicy,
 	logger *zap.Logger,
+	opts ...OAuth2ServiceOption,
 ) *OAuth2Service {
-	return &OAuth2Service{
+	s := &OAuth2Service{
 		clientRepo:        clientRepo,
+		scopeRepo:         scopeRepo,
 		jwtSecret:         jwtSecret,
 		accessTokenExpiry: accessTokenExpiry,
+		signing:           signing,
 		logger:            logger,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
-// ClientCredentials authenticates a client and generates an access token
-func (s *OAuth2Service) ClientCredentials(ctx context.Context, clientID, clientSecret string) (string, int64, error) {
-	// Retrieve client from database
-	client, err := s.clientRepo.GetByClientID(ctx, clientID)
+// ClientCredentials authenticates a client and generates an access token, returned with its expiration.
+// signed is the replay protection of the request, nil when the client didn't sign it. certThumbprint is the
+// thumbprint of the TLS client certificate of the request, the token is then bound to it (RFC 8705), empty
+// when the request was not sent over mTLS.
+func (s *OAuth2Service) ClientCredentials(ctx context.Context, clientID, clientSecret string, signed *domain.SignedClientRequest, certThumbprint string) (string, time.Time, error) {
+	client, err := s.AuthenticateClient(ctx, clientID, clientSecret)
 	if err != nil {
-		s.logger.Warn("client not found", zap.String("client_id", clientID), zap.Error(err))
-		return "", 0, domainerrors.ErrInvalidCredentials
+		return "", time.Time{}, err
 	}
 
-	// Validate client is active
-	if !client.Active {
-		s.logger.Warn("inactive client attempted authentication", zap.String("client_id", clientID))
-		return "", 0, domainerrors.ErrInvalidClient
+	if !client.AllowsGrantType(domain.GrantTypeClientCredentials) {
+		s.logger.Warn("client not allowed to use the client_credentials grant", logging.String("client_id", clientID))
+		s.recordError(ctx, client.ClientID)
+		return "", time.Time{}, domainerrors.ErrUnauthorizedClient
 	}
 
-	// Validate client secret
-	if !client.ValidateSecret(clientSecret) {
-		s.logger.Warn("invalid client secret", zap.String("client_id", clientID))
-		return "", 0, domainerrors.ErrInvalidCredentials
+	if s.requestVerifier != nil {
+		if err := s.requestVerifier.VerifyClientRequest(ctx, client, signed); err != nil {
+			s.recordError(ctx, client.ClientID)
+			return "", time.Time{}, err
+		}
+	}
+
+	if s.quotaEnforcer != nil {
+		if err := s.quotaEnforcer.EnforceClientIssuance(ctx, client.ClientID); err != nil {
+			s.recordError(ctx, client.ClientID)
+			return "", time.Time{}, err
+		}
+	}
+
+	// Track the token before handing it out, an untracked token could not be revoked with the others.
+	// The exp claim has a precision of seconds, so does the expiration returned to the client.
+	tokenID := s.signing.newTokenID()
+	expiresAt := time.Now().Add(s.tokenTTL()).Truncate(time.Second)
+	if s.clientTokenRepo != nil {
+		if err := s.clientTokenRepo.Track(ctx, client.ClientID, tokenID, expiresAt); err != nil {
+			s.logger.Error("failed to track client token", zap.Error(err), logging.String("client_id", clientID))
+			return "", time.Time{}, internalError(err)
+		}
 	}
 
 	// Generate access token
-	accessToken, expiresIn, err := s.generateAccessToken(client)
+	accessToken, err := s.generateAccessToken(client, tokenID, expiresAt, certThumbprint)
 	if err != nil {
-		s.logger.Error("failed to generate access token", zap.Error(err), zap.String("client_id", clientID))
-		return "", 0, fmt.Errorf("failed to generate access token: %w", err)
+		s.logger.Error("failed to generate access token", zap.Error(err), logging.String("client_id", clientID))
+		return "", time.Time{}, fmt.Errorf("failed to generate access token: %w", err)
 	}
+	s.signing.checkSize(accessToken, "client_credentials", s.logger, logging.String("client_id", clientID))
 
 	metrics.AddJWTTokensGenerated(1)
+	metrics.IncOAuthClientTokensIssued(client.TagValue(domain.ClientTagTeam), client.TagValue(domain.ClientTagEnv))
+	if s.usage != nil {
+		s.usage.RecordIssuance(ctx, client.ClientID)
+	}
 
 	s.logger.Info("client credentials token generated successfully",
-		zap.String("client_id", clientID),
-		zap.Int64("expires_in", expiresIn),
+		logging.String("client_id", clientID),
+		zap.Time("expires_at", expiresAt),
+		zap.Bool("certificate_bound", certThumbprint != ""),
 	)
 
-	return accessToken, expiresIn, nil
+	return accessToken, expiresAt, nil
+}
+
+// tokenTTL returns the lifetime of a new client token, shortened by the random jitter
+func (s *OAuth2Service) tokenTTL() time.Duration {
+	maxJitter := s.accessTokenExpiry * time.Duration(s.ttlJitterPercent) / 100
+	if maxJitter <= 0 {
+		return s.accessTokenExpiry
+	}
+	return s.accessTokenExpiry - rand.N(maxJitter+1)
 }
 
-// generateAccessToken creates a JWT access token for the OAuth client
-func (s *OAuth2Service) generateAccessToken(client *domain.OAuthClient) (string, int64, error) {
-	now := time.Now()
-	expiresAt := now.Add(s.accessTokenExpiry)
+// AuthenticateClient verifies the credentials of an active OAuth client. A client locked out after
+// repeated authentication failures is rejected until the end of the cooldown, even with valid credentials,
+// and a client whose secret has expired gets ErrClientSecretExpired.
+func (s *OAuth2Service) AuthenticateClient(ctx context.Context, clientID, clientSecret string) (*domain.OAuthClient, error) {
+	// Retrieve client from database
+	client, err := s.clientRepo.GetByClientID(ctx, clientID)
+	if err != nil {
+		s.logger.Warn("client not found", logging.String("client_id", clientID), zap.Error(err))
+		return nil, domainerrors.ErrInvalidCredentials
+	}
+
+	if s.lockout != nil {
+		if err := s.lockout.CheckClientLockout(ctx, client.ClientID); err != nil {
+			s.logger.Warn("locked out client attempted authentication", logging.String("client_id", clientID))
+			s.recordError(ctx, client.ClientID)
+			return nil, err
+		}
+	}
+
+	// Validate client secret
+	if !s.validateSecret(ctx, client, clientSecret) {
+		s.logger.Warn("invalid client secret", logging.String("client_id", clientID))
+		s.recordError(ctx, client.ClientID)
+		if s.lockout != nil {
+			s.lockout.RecordAuthFailure(ctx, client.ClientID)
+		}
+		return nil, domainerrors.ErrInvalidCredentials
+	}
+
+	// Only checked once the secret is valid, so neither the deactivation nor the expiry is disclosed to
+	// whoever guesses a client ID
+	if !client.Active {
+		s.logger.Warn("inactive client attempted authentication", logging.String("client_id", clientID))
+		s.recordError(ctx, client.ClientID)
+		return nil, domainerrors.ErrInvalidClient
+	}
+
+	if client.SecretExpired(time.Now()) {
+		s.logger.Warn("client with an expired secret attempted authentication",
+			logging.String("client_id", clientID),
+			zap.Time("secret_expires_at", *client.SecretExpiresAt))
+		s.recordError(ctx, client.ClientID)
+		return nil, domainerrors.ErrClientSecretExpired
+	}
+
+	return client, nil
+}
+
+// checkClientTokenSize returns ErrAccessTokenTooLarge if the access tokens of the client, with its
+// configuration, would exceed the maximum token size
+func (s *OAuth2Service) checkClientTokenSize(client *domain.OAuthClient) error {
+	if s.signing.MaxTokenSize <= 0 {
+		return nil
+	}
 
+	token, err := s.generateAccessToken(client, uuid.New().String(), time.Now().Add(s.accessTokenExpiry), "")
+	if err != nil {
+		s.logger.Error("failed to generate access token", zap.Error(err), logging.String("client_id", client.ClientID))
+		return internalError(err)
+	}
+	if s.signing.exceedsMaxSize(token) {
+		s.logger.Warn("access tokens of the oauth client would exceed the maximum token size",
+			logging.String("client_id", client.ClientID),
+			zap.Int("size", len(token)),
+			zap.Int("max_size", s.signing.MaxTokenSize))
+		return domainerrors.ErrAccessTokenTooLarge
+	}
+	return nil
+}
+
+// CheckClientTokenSizes logs the clients whose access tokens exceed the maximum token size, e.g. after
+// the limit was lowered, and returns how many do
+func (s *OAuth2Service) CheckClientTokenSizes(ctx context.Context) (int, error) {
+	if s.signing.MaxTokenSize <= 0 {
+		return 0, nil
+	}
+
+	clients, err := s.clientRepo.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	oversized := 0
+	for _, client := range clients {
+		if errors.Is(s.checkClientTokenSize(client), domainerrors.ErrAccessTokenTooLarge) {
+			oversized++
+		}
+	}
+	return oversized, nil
+}
+
+// CountUnapprovedClientSecrets returns how many clients have a secret hash that approved does not accept,
+// e.g. the bcrypt hashes stored before fips mode was enabled, which can no longer be verified
+func (s *OAuth2Service) CountUnapprovedClientSecrets(ctx context.Context, approved func(hash string) bool) (int, error) {
+	clients, err := s.clientRepo.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	unapproved := 0
+	for _, client := range clients {
+		if !approved(client.ClientSecret) {
+			unapproved++
+		}
+	}
+	return unapproved, nil
+}
+
+// newClient creates a client with its secret hashed by the secret hasher, bcrypt without one
+func (s *OAuth2Service) newClient(ctx context.Context, clientID, clientSecret, name, description string, scopes []string) (*domain.OAuthClient, error) {
+	if s.secretHasher == nil {
+		return domain.NewOAuthClient(clientID, clientSecret, name, description, scopes)
+	}
+	return domain.NewOAuthClientWithHasher(clientID, clientSecret, name, description, scopes, func(secret string) (string, error) {
+		return s.secretHasher.Hash(ctx, secret)
+	})
+}
+
+// validateSecret checks the secret of the client with the secret hasher, bcrypt without one
+func (s *OAuth2Service) validateSecret(ctx context.Context, client *domain.OAuthClient, clientSecret string) bool {
+	if s.secretHasher == nil {
+		return client.ValidateSecret(clientSecret)
+	}
+	ok, err := s.secretHasher.Compare(ctx, client.ClientSecret, clientSecret)
+	if err != nil {
+		s.logger.Error("failed to verify client secret", zap.Error(err), logging.String("client_id", client.ClientID))
+		return false
+	}
+	return ok
+}
+
+// recordError counts a failed request of an existing client. Unknown client IDs are not counted, so
+// they can't fill the usage counters.
+func (s *OAuth2Service) recordError(ctx context.Context, clientID string) {
+	if s.usage != nil {
+		s.usage.RecordError(ctx, clientID)
+	}
+}
+
+// generateAccessToken creates a JWT access token for the OAuth client, bound to the certificate of
+// certThumbprint when set
+func (s *OAuth2Service) generateAccessToken(client *domain.OAuthClient, tokenID string, expiresAt time.Time, certThumbprint string) (string, error) {
 	claims := jwt.MapClaims{
 		"client_id": client.ClientID,
 		"scopes":    client.Scopes,
-		"jti":       uuid.New().String(),
-		"iat":       now.Unix(),
+		"jti":       tokenID,
+		"iat":       time.Now().Unix(),
 		"exp":       expiresAt.Unix(),
 		"type":      "client_credentials",
 	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(s.jwtSecret))
-	if err != nil {
-		return "", 0, err
+	if s.signing.Region != "" {
+		claims["region"] = s.signing.Region
+	}
+	if certThumbprint != "" {
+		claims["cnf"] = domain.Confirmation{CertificateThumbprint: certThumbprint}
 	}
 
-	expiresIn := int64(s.accessTokenExpiry.Seconds())
-	return tokenString, expiresIn, nil
+	token := s.signing.newToken(claims)
+	return token.SignedString([]byte(s.jwtSecret))
 }
 
 // ValidateAccessToken validates an OAuth2 access token
 func (s *OAuth2Service) ValidateAccessToken(ctx context.Context, tokenString string) (*domain.OAuthTokenClaims, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		// Validate signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(s.jwtSecret), nil
-	})
+	token, err := s.signing.parser().Parse(tokenString, s.signing.keyFunc([]byte(s.jwtSecret)))
 
 	if err != nil {
 		s.logger.Debug("token parsing failed", zap.Error(err))
@@ -169,6 +426,19 @@ func (s *OAuth2Service) ValidateAccessToken(ctx context.Context, tokenString str
 	jti, _ := claims["jti"].(string)
 	iat, _ := claims["iat"].(float64)
 
+	// Verify the token was not revoked along with the other tokens of the client
+	if s.clientTokenRepo != nil && jti != "" {
+		revoked, err := s.clientTokenRepo.IsRevoked(ctx, clientID, jti)
+		if err != nil {
+			s.logger.Error("failed to check revoked client token", zap.Error(err), logging.String("client_id", clientID))
+			return nil, internalError(err)
+		}
+		if revoked {
+			s.logger.Warn("attempt to use revoked client token", logging.String("client_id", clientID))
+			return nil, domainerrors.ErrTokenRevoked
+		}
+	}
+
 	tokenClaims := &domain.OAuthTokenClaims{
 		ClientID: clientID,
 		Scopes:   scopes,
@@ -178,35 +448,364 @@ func (s *OAuth2Service) ValidateAccessToken(ctx context.Context, tokenString str
 		Type:     tokenType,
 	}
 
+	// Certificate-bound tokens must only be accepted from the holder of the certificate
+	if cnf, present := claims["cnf"]; present {
+		confirmation, ok := cnf.(map[string]interface{})
+		if !ok {
+			return nil, domainerrors.ErrInvalidToken
+		}
+		thumbprint, ok := confirmation["x5t#S256"].(string)
+		if !ok || thumbprint == "" {
+			return nil, domainerrors.ErrInvalidToken
+		}
+		tokenClaims.Confirmation = &domain.Confirmation{CertificateThumbprint: thumbprint}
+	}
+
 	return tokenClaims, nil
 }
 
-// CreateClient creates a new OAuth2 client
-func (s *OAuth2Service) CreateClient(ctx context.Context, clientID, clientSecret, name, description string, scopes []string) (*domain.OAuthClient, error) {
+// CreateClient creates a new OAuth2 client. secretExpiresAt is when its secret expires, nil for never.
+func (s *OAuth2Service) CreateClient(ctx context.Context, clientID, clientSecret, name, description string, scopes, grantTypes, redirectURIs, tags []string, secretExpiresAt *time.Time) (*domain.OAuthClient, error) {
 	// Check if client already exists
 	existing, err := s.clientRepo.GetByClientID(ctx, clientID)
 	if err == nil && existing != nil {
 		return nil, fmt.Errorf("client with id %s already exists", clientID)
 	}
 
-	// Create new client
-	client, err := domain.NewOAuthClient(clientID, clientSecret, name, description, scopes)
+	// Only registered scopes can be assigned
+	if err := validateRegisteredScopes(ctx, s.scopeRepo, scopes, s.logger); err != nil {
+		return nil, err
+	}
+
+	// Create new client, with the default grant types unless given
+	client, err := s.newClient(ctx, clientID, clientSecret, name, description, scopes)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create oauth client: %w", err)
 	}
+	if grantTypes != nil {
+		parsed, err := domain.ParseGrantTypes(grantTypes)
+		if err != nil {
+			return nil, domainerrors.ErrBadRequest
+		}
+		client.GrantTypes = parsed
+	}
+	if redirectURIs != nil {
+		parsed, err := domain.ParseRedirectURIs(redirectURIs)
+		if err != nil {
+			return nil, domainerrors.ErrInvalidRedirectURI
+		}
+		client.RedirectURIs = parsed
+	}
+	if tags != nil {
+		parsed, err := domain.ParseClientTags(tags)
+		if err != nil {
+			return nil, domainerrors.ErrBadRequest
+		}
+		client.Tags = parsed
+	}
+	if secretExpiresAt != nil {
+		if !secretExpiresAt.After(time.Now()) {
+			return nil, domainerrors.ErrBadRequest
+		}
+		client.SecretExpiresAt = secretExpiresAt
+	}
+
+	if err := s.checkClientTokenSize(client); err != nil {
+		return nil, err
+	}
 
 	// Save to database
 	if err := s.clientRepo.Create(ctx, client); err != nil {
 		return nil, fmt.Errorf("failed to save oauth client: %w", err)
 	}
 
-	s.logger.Info("oauth client created", zap.String("client_id", clientID))
+	s.logger.Info("oauth client created", logging.String("client_id", clientID), logging.Strings("tags", client.Tags))
+	return client, nil
+}
+
+// UpdateClient updates the name, description, scopes, token profile, grant types, redirect URIs and tags of
+// an OAuth2 client.
+// Nil fields keep their current value. expectedVersion is the version of the client the changes were decided
+// on, 0 for any.
+func (s *OAuth2Service) UpdateClient(ctx context.Context, id string, expectedVersion int, name, description *string, scopes []string, tokenProfile *domain.TokenProfile, grantTypes, redirectURIs, tags []string) (*domain.OAuthClient, error) {
+	client, err := s.clientRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrClientNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to get oauth client", zap.Error(err), logging.String("id", id))
+		return nil, internalError(err)
+	}
+
+	if err := checkExpectedVersion(expectedVersion, client.Version); err != nil {
+		return nil, err
+	}
+	if name != nil {
+		if *name == "" {
+			return nil, domainerrors.ErrBadRequest
+		}
+		client.Name = *name
+	}
+	if description != nil {
+		client.Description = *description
+	}
+	if scopes != nil {
+		if err := validateRegisteredScopes(ctx, s.scopeRepo, scopes, s.logger); err != nil {
+			return nil, err
+		}
+		client.Scopes = scopes
+	}
+	if tokenProfile != nil {
+		if !tokenProfile.IsValid() {
+			return nil, domainerrors.ErrBadRequest
+		}
+		client.TokenProfile = *tokenProfile
+	}
+	if grantTypes != nil {
+		parsed, err := domain.ParseGrantTypes(grantTypes)
+		if err != nil {
+			return nil, domainerrors.ErrBadRequest
+		}
+		client.GrantTypes = parsed
+	}
+	if redirectURIs != nil {
+		parsed, err := domain.ParseRedirectURIs(redirectURIs)
+		if err != nil {
+			return nil, domainerrors.ErrInvalidRedirectURI
+		}
+		client.RedirectURIs = parsed
+	}
+	if tags != nil {
+		parsed, err := domain.ParseClientTags(tags)
+		if err != nil {
+			return nil, domainerrors.ErrBadRequest
+		}
+		client.Tags = parsed
+	}
+
+	if err := s.checkClientTokenSize(client); err != nil {
+		return nil, err
+	}
+
+	if err := s.clientRepo.Update(ctx, client); err != nil {
+		if errors.Is(err, domainerrors.ErrClientNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to update oauth client", zap.Error(err), logging.String("id", id))
+		return nil, internalError(err)
+	}
+
+	s.logger.Info("oauth client updated", logging.String("client_id", client.ClientID), logging.Strings("tags", client.Tags))
+	return client, nil
+}
+
+// AddRedirectURI registers a redirect URI for an OAuth2 client
+func (s *OAuth2Service) AddRedirectURI(ctx context.Context, id, redirectURI string) (*domain.OAuthClient, error) {
+	return s.updateRedirectURIs(ctx, id, func(redirectURIs []string) ([]string, error) {
+		if err := domain.ValidateRedirectURI(redirectURI); err != nil {
+			return nil, domainerrors.ErrInvalidRedirectURI
+		}
+		if slices.Contains(redirectURIs, redirectURI) {
+			return redirectURIs, nil
+		}
+		if len(redirectURIs) >= domain.MaxRedirectURIs {
+			return nil, domainerrors.ErrInvalidRedirectURI
+		}
+		return append(redirectURIs, redirectURI), nil
+	})
+}
+
+// RemoveRedirectURI unregisters a redirect URI of an OAuth2 client
+func (s *OAuth2Service) RemoveRedirectURI(ctx context.Context, id, redirectURI string) (*domain.OAuthClient, error) {
+	return s.updateRedirectURIs(ctx, id, func(redirectURIs []string) ([]string, error) {
+		i := slices.Index(redirectURIs, redirectURI)
+		if i < 0 {
+			return nil, domainerrors.ErrRedirectURINotFound
+		}
+		return slices.Delete(redirectURIs, i, i+1), nil
+	})
+}
+
+// updateRedirectURIs replaces the redirect URIs of an OAuth2 client with the ones returned by update
+func (s *OAuth2Service) updateRedirectURIs(ctx context.Context, id string, update func([]string) ([]string, error)) (*domain.OAuthClient, error) {
+	client, err := s.clientRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrClientNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to get oauth client", zap.Error(err), logging.String("id", id))
+		return nil, internalError(err)
+	}
+
+	redirectURIs, err := update(slices.Clone(client.RedirectURIs))
+	if err != nil {
+		return nil, err
+	}
+	client.RedirectURIs = redirectURIs
+
+	if err := s.clientRepo.Update(ctx, client); err != nil {
+		if errors.Is(err, domainerrors.ErrClientNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to update oauth client", zap.Error(err), logging.String("id", id))
+		return nil, internalError(err)
+	}
+
+	s.logger.Info("oauth client redirect uris updated",
+		logging.String("client_id", client.ClientID),
+		logging.Strings("redirect_uris", client.RedirectURIs))
+	return client, nil
+}
+
+// ResolveRedirectURI returns the redirect URI of an authorization request of the client, to be checked
+// before redirecting the user agent anywhere. A requested redirect URI must match one of the registered
+// ones exactly, the only registered one is used when none is requested. On error the authorization
+// endpoint must not redirect, the redirect URI can't be trusted.
+func (s *OAuth2Service) ResolveRedirectURI(ctx context.Context, clientID, redirectURI string) (string, error) {
+	client, err := s.clientRepo.GetByClientID(ctx, clientID)
+	if err != nil || client == nil || !client.Active {
+		s.logger.Warn("authorization requested by unknown client", zap.Error(err), logging.String("client_id", clientID))
+		return "", domainerrors.ErrInvalidClient
+	}
+
+	if redirectURI == "" {
+		if len(client.RedirectURIs) == 1 {
+			return client.RedirectURIs[0], nil
+		}
+		return "", domainerrors.ErrInvalidRedirectURI
+	}
+
+	if !client.MatchesRedirectURI(redirectURI) {
+		s.logger.Warn("authorization requested with unregistered redirect uri",
+			logging.String("client_id", clientID),
+			logging.String("redirect_uri", redirectURI))
+		return "", domainerrors.ErrInvalidRedirectURI
+	}
+	return redirectURI, nil
+}
+
+// SetSignedRequests requires or stops requiring signed token requests from an OAuth2 client.
+// Requiring them generates a new request signing key, replacing the previous one, which is returned
+// in the client so it can be handed to its owner.
+func (s *OAuth2Service) SetSignedRequests(ctx context.Context, id string, required bool) (*domain.OAuthClient, error) {
+	client, err := s.clientRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrClientNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to get oauth client", zap.Error(err), logging.String("id", id))
+		return nil, internalError(err)
+	}
+
+	client.RequireSignedRequests = required
+	client.RequestSigningKey = ""
+	if required {
+		key, err := domain.GenerateRequestSigningKey()
+		if err != nil {
+			s.logger.Error("failed to generate request signing key", zap.Error(err))
+			return nil, internalError(err)
+		}
+		client.RequestSigningKey = key
+	}
+
+	if err := s.clientRepo.Update(ctx, client); err != nil {
+		if errors.Is(err, domainerrors.ErrClientNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to update oauth client", zap.Error(err), logging.String("id", id))
+		return nil, internalError(err)
+	}
+
+	s.logger.Info("oauth client signed requests updated",
+		logging.String("client_id", client.ClientID),
+		zap.Bool("require_signed_requests", required))
+	return client, nil
+}
+
+// SetSecretExpiry sets when the secret of an OAuth2 client expires, nil for never. An expiry in the past
+// makes the secret expire immediately.
+func (s *OAuth2Service) SetSecretExpiry(ctx context.Context, id string, expiresAt *time.Time) (*domain.OAuthClient, error) {
+	client, err := s.clientRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrClientNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to get oauth client", zap.Error(err), logging.String("id", id))
+		return nil, internalError(err)
+	}
+
+	client.SecretExpiresAt = expiresAt
+	if err := s.clientRepo.Update(ctx, client); err != nil {
+		if errors.Is(err, domainerrors.ErrClientNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to update oauth client", zap.Error(err), logging.String("id", id))
+		return nil, internalError(err)
+	}
+
+	fields := []zap.Field{logging.String("client_id", client.ClientID)}
+	if expiresAt != nil {
+		fields = append(fields, zap.Time("secret_expires_at", *expiresAt))
+	}
+	s.logger.Info("oauth client secret expiry updated", fields...)
 	return client, nil
 }
 
-// ListClients retrieves all OAuth2 clients
-func (s *OAuth2Service) ListClients(ctx context.Context) ([]*domain.OAuthClient, error) {
-	return s.clientRepo.List(ctx)
+// ListExpiringSecrets retrieves the active OAuth2 clients whose secret expires within the given duration,
+// including the ones already expired, the soonest first
+func (s *OAuth2Service) ListExpiringSecrets(ctx context.Context, within time.Duration) ([]*domain.OAuthClient, error) {
+	clients, err := s.clientRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return expiringSecrets(clients, time.Now(), within), nil
+}
+
+// expiringSecrets returns the clients whose secret expires within the window of now, the soonest first
+func expiringSecrets(clients []*domain.OAuthClient, now time.Time, window time.Duration) []*domain.OAuthClient {
+	expiring := make([]*domain.OAuthClient, 0)
+	for _, client := range clients {
+		if client.SecretExpiresWithin(now, window) {
+			expiring = append(expiring, client)
+		}
+	}
+	slices.SortStableFunc(expiring, func(a, b *domain.OAuthClient) int {
+		return a.SecretExpiresAt.Compare(*b.SecretExpiresAt)
+	})
+	return expiring
+}
+
+// ListClients retrieves the OAuth2 clients that have all the given tags, all of them when tags is empty
+func (s *OAuth2Service) ListClients(ctx context.Context, tags []string) ([]*domain.OAuthClient, error) {
+	clients, err := s.clientRepo.List(ctx)
+	if err != nil || len(tags) == 0 {
+		return clients, err
+	}
+
+	filtered := make([]*domain.OAuthClient, 0, len(clients))
+	for _, client := range clients {
+		if client.HasTags(tags) {
+			filtered = append(filtered, client)
+		}
+	}
+	return filtered, nil
+}
+
+// ListClientUsage retrieves the usage of the OAuth2 clients keyed by client ID, empty when usage is not tracked
+func (s *OAuth2Service) ListClientUsage(ctx context.Context) (map[string]*domain.ClientUsage, error) {
+	if s.usage == nil {
+		return map[string]*domain.ClientUsage{}, nil
+	}
+	return s.usage.ListUsage(ctx)
+}
+
+// ListClientLockouts retrieves the lockout state of the clients with recent authentication failures,
+// keyed by client ID, empty when lockouts are disabled
+func (s *OAuth2Service) ListClientLockouts(ctx context.Context, clientIDs []string) (map[string]*domain.ClientLockout, error) {
+	if s.lockout == nil {
+		return map[string]*domain.ClientLockout{}, nil
+	}
+	return s.lockout.ListLockouts(ctx, clientIDs)
 }
 
 // GetClient retrieves an OAuth2 client by ID
@@ -218,3 +817,30 @@ func (s *OAuth2Service) GetClient(ctx context.Context, id string) (*domain.OAuth
 func (s *OAuth2Service) DeleteClient(ctx context.Context, id string) error {
 	return s.clientRepo.Delete(ctx, id)
 }
+
+// RevokeClientTokens revokes all the unexpired access tokens issued to an OAuth2 client, e.g. when its
+// secret was compromised, and returns how many were revoked. Tokens issued afterwards remain valid.
+func (s *OAuth2Service) RevokeClientTokens(ctx context.Context, id string) (int, error) {
+	if s.clientTokenRepo == nil {
+		s.logger.Error("client token revocation requested without client token tracking", logging.String("id", id))
+		return 0, domainerrors.ErrInternal
+	}
+
+	client, err := s.clientRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrClientNotFound) {
+			return 0, err
+		}
+		s.logger.Error("failed to get oauth client", zap.Error(err), logging.String("id", id))
+		return 0, internalError(err)
+	}
+
+	revoked, err := s.clientTokenRepo.RevokeAll(ctx, client.ClientID)
+	if err != nil {
+		s.logger.Error("failed to revoke client tokens", zap.Error(err), logging.String("client_id", client.ClientID))
+		return 0, internalError(err)
+	}
+
+	s.logger.Warn("oauth client tokens revoked", logging.String("client_id", client.ClientID), zap.Int("revoked", revoked))
+	return revoked, nil
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
+	"github.com/kristianrpo/auth-microservice/internal/retry"
+)
+
+// outboxClaimLease is how long claimed messages stay hidden from other relays, it must exceed
+// the time taken to publish a batch
+const outboxClaimLease = 5 * time.Minute
+
+// OutboxPublisher is a MessagePublisher that stores the messages whose publication permanently failed
+// in the outbox, from where the OutboxRelay delivers them later. A message accepted by the outbox is
+// reported as published.
+type OutboxPublisher struct {
+	publisher  ports.MessagePublisher
+	outboxRepo ports.OutboxRepository
+	logger     *zap.Logger
+}
+
+// NewOutboxPublisher creates a new instance of OutboxPublisher
+func NewOutboxPublisher(publisher ports.MessagePublisher, outboxRepo ports.OutboxRepository, logger *zap.Logger) *OutboxPublisher {
+	return &OutboxPublisher{
+		publisher:  publisher,
+		outboxRepo: outboxRepo,
+		logger:     logger,
+	}
+}
+
+// Publish publishes the message, falling back to the outbox when the publication fails
+func (p *OutboxPublisher) Publish(ctx context.Context, queueName string, message []byte) error {
+	publishErr := p.publisher.Publish(ctx, queueName, message)
+	if publishErr == nil {
+		return nil
+	}
+
+	// The message must not be lost because the request that produced it was cancelled
+	outboxMessage := domain.NewOutboxMessage(queueName, message, publishErr.Error())
+	if err := p.outboxRepo.Enqueue(context.WithoutCancel(ctx), outboxMessage); err != nil {
+		metrics.IncOutboxMessages("enqueue_failed")
+		return fmt.Errorf("failed to store message in the outbox: %w", errors.Join(publishErr, err))
+	}
+
+	metrics.IncOutboxMessages("enqueued")
+	p.logger.Warn("publish failed, message stored in the outbox",
+		zap.String("queue", queueName),
+		zap.String("outbox_id", outboxMessage.ID),
+		zap.Error(publishErr),
+	)
+	return nil
+}
+
+// Close closes the underlying publisher
+func (p *OutboxPublisher) Close() error {
+	return p.publisher.Close()
+}
+
+// OutboxRelayPolicy controls how the outbox is relayed
+type OutboxRelayPolicy struct {
+	PollInterval   time.Duration
+	BatchSize      int
+	InitialBackoff time.Duration // delay before retrying a message after its first failed relay
+	MaxBackoff     time.Duration
+}
+
+// OutboxRelay periodically delivers the messages of the outbox
+type OutboxRelay struct {
+	publisher  ports.MessagePublisher
+	outboxRepo ports.OutboxRepository
+	policy     OutboxRelayPolicy
+	logger     *zap.Logger
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewOutboxRelay creates a new instance of OutboxRelay. publisher must publish directly to the broker,
+// not through an OutboxPublisher.
+func NewOutboxRelay(publisher ports.MessagePublisher, outboxRepo ports.OutboxRepository, policy OutboxRelayPolicy, logger *zap.Logger) *OutboxRelay {
+	return &OutboxRelay{
+		publisher:  publisher,
+		outboxRepo: outboxRepo,
+		policy:     policy,
+		logger:     logger,
+	}
+}
+
+// Run relays the outbox every poll interval until the context is cancelled
+func (r *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.policy.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.RelayDue(ctx); err != nil && ctx.Err() == nil {
+				r.logger.Error("failed to relay outbox", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Start runs the relay in the background, it implements Component
+func (r *OutboxRelay) Start(ctx context.Context) error {
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	r.cancel = cancel
+	r.done = make(chan struct{})
+	go func() {
+		defer close(r.done)
+		r.Run(runCtx)
+	}()
+	return nil
+}
+
+// Stop stops the background relay and waits for the batch in progress, until the context is done
+func (r *OutboxRelay) Stop(ctx context.Context) error {
+	if r.cancel == nil {
+		return nil
+	}
+	r.cancel()
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RelayDue delivers the messages due for delivery and returns how many were delivered.
+// Failed messages are rescheduled with exponential backoff.
+func (r *OutboxRelay) RelayDue(ctx context.Context) (int, error) {
+	messages, err := r.outboxRepo.ClaimDue(ctx, r.policy.BatchSize, outboxClaimLease)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, message := range messages {
+		if err := r.publisher.Publish(ctx, message.Queue, message.Payload); err != nil {
+			metrics.IncOutboxMessages("delivery_failed")
+			nextAttemptAt := time.Now().Add(r.backoff(message.Attempts))
+			r.logger.Warn("failed to relay outbox message",
+				zap.String("outbox_id", message.ID),
+				zap.String("queue", message.Queue),
+				zap.Int("attempts", message.Attempts+1),
+				zap.Time("next_attempt_at", nextAttemptAt),
+				zap.Error(err),
+			)
+			if err := r.outboxRepo.MarkFailed(ctx, message.ID, err.Error(), nextAttemptAt); err != nil {
+				r.logger.Error("failed to reschedule outbox message", zap.Error(err), zap.String("outbox_id", message.ID))
+			}
+			continue
+		}
+
+		metrics.IncOutboxMessages("delivered")
+		delivered++
+		if err := r.outboxRepo.Delete(ctx, message.ID); err != nil {
+			// The message will be delivered again once the claim lease expires
+			r.logger.Error("failed to delete relayed outbox message", zap.Error(err), zap.String("outbox_id", message.ID))
+		}
+	}
+
+	if delivered > 0 {
+		r.logger.Info("outbox messages relayed", zap.Int("delivered", delivered), zap.Int("claimed", len(messages)))
+	}
+	return delivered, nil
+}
+
+// backoff returns the delay before the next relay of a message that already failed the given number of times
+func (r *OutboxRelay) backoff(attempts int) time.Duration {
+	return retry.Policy{InitialBackoff: r.policy.InitialBackoff, MaxBackoff: r.policy.MaxBackoff}.Backoff(attempts + 1)
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"go.uber.org/zap"
+
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
+)
+
+// Password grant outcomes reported to metrics
+const (
+	passwordGrantOutcomeSuccess  = "success"
+	passwordGrantOutcomeDisabled = "disabled"
+	passwordGrantOutcomeRejected = "rejected"
+	passwordGrantOutcomeFailed   = "failed"
+)
+
+// PasswordGrantServiceInterface defines the methods of PasswordGrantService used by handlers.
+type PasswordGrantServiceInterface interface {
+	PasswordGrant(ctx context.Context, clientID, clientSecret, username, password string) (*domain.TokenPair, error)
+}
+
+// PasswordGrantService handles the deprecated OAuth2 resource-owner password grant.
+// It only exists so legacy internal apps can migrate; it is disabled unless explicitly enabled
+// and restricted to an allowlist of clients.
+// Clients authenticate like at the token endpoint, so the client lockout applies.
+type PasswordGrantService struct {
+	oauth2Service  OAuth2ServiceInterface
+	authService    AuthServiceInterface
+	enabled        bool
+	allowedClients []string
+	quotaEnforcer  QuotaEnforcer
+	logger         *zap.Logger
+}
+
+// NewPasswordGrantService creates a new instance of PasswordGrantService
+func NewPasswordGrantService(
+	oauth2Service OAuth2ServiceInterface,
+	authService AuthServiceInterface,
+	enabled bool,
+	allowedClients []string,
+	quotaEnforcer QuotaEnforcer,
+	logger *zap.Logger,
+) *PasswordGrantService {
+	return &PasswordGrantService{
+		oauth2Service:  oauth2Service,
+		authService:    authService,
+		enabled:        enabled,
+		allowedClients: allowedClients,
+		quotaEnforcer:  quotaEnforcer,
+		logger:         logger,
+	}
+}
+
+// PasswordGrant authenticates an allowlisted client and exchanges the user's credentials for a token pair
+func (s *PasswordGrantService) PasswordGrant(ctx context.Context, clientID, clientSecret, username, password string) (*domain.TokenPair, error) {
+	// The client ID is only a metric label once the client is known to be registered, so arbitrary client
+	// IDs can't grow the cardinality of the metric
+	if !s.enabled {
+		metrics.IncPasswordGrantRequests("", passwordGrantOutcomeDisabled)
+		s.logger.Warn("password grant requested while disabled", logging.String("client_id", clientID))
+		return nil, domainerrors.ErrUnsupportedGrantType
+	}
+
+	// Clients that fail to authenticate may not exist, so they are not labeled
+	client, err := s.oauth2Service.AuthenticateClient(ctx, clientID, clientSecret)
+	if err != nil {
+		metrics.IncPasswordGrantRequests("", passwordGrantOutcomeRejected)
+		s.logger.Warn("password grant requested by a client that failed to authenticate", zap.Error(err), logging.String("client_id", clientID))
+		return nil, err
+	}
+
+	if !slices.Contains(s.allowedClients, client.ClientID) {
+		metrics.IncPasswordGrantRequests(clientID, passwordGrantOutcomeRejected)
+		s.logger.Warn("client not allowlisted for password grant", logging.String("client_id", clientID))
+		return nil, domainerrors.ErrUnauthorizedClient
+	}
+
+	s.logger.Warn("deprecated password grant used, migrate this client to another grant",
+		logging.String("client_id", clientID))
+
+	if s.quotaEnforcer != nil {
+		if err := s.quotaEnforcer.EnforceClientIssuance(ctx, client.ClientID); err != nil {
+			metrics.IncPasswordGrantRequests(clientID, passwordGrantOutcomeRejected)
+			return nil, err
+		}
+	}
+
+	tokenPair, err := s.authService.LoginForClient(ctx, username, password, client.TokenProfile)
+	if err != nil {
+		metrics.IncPasswordGrantRequests(clientID, passwordGrantOutcomeFailed)
+		// Invalid user credentials must not be taken for invalid client credentials
+		if errors.Is(err, domainerrors.ErrInvalidCredentials) {
+			return nil, fmt.Errorf("%w: %w", domainerrors.ErrInvalidGrant, err)
+		}
+		return nil, err
+	}
+
+	metrics.IncPasswordGrantRequests(clientID, passwordGrantOutcomeSuccess)
+	return tokenPair, nil
+}
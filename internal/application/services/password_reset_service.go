@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+)
+
+// PasswordResetServiceInterface defines the methods of PasswordResetService used by handlers.
+type PasswordResetServiceInterface interface {
+	RequestReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
+}
+
+// PasswordResetService resets forgotten passwords with a single-use token sent by email. The token can be
+// requested with the primary email of the account or with a verified secondary email, and is sent to the
+// address it was requested with, so accounts whose primary email is inaccessible can be recovered.
+type PasswordResetService struct {
+	userRepo          ports.UserRepository
+	emailRepo         ports.UserEmailRepository
+	resetRepo         ports.PasswordResetRepository
+	tokenRepo         ports.TokenRepository
+	passwordHasher    ports.PasswordHasher
+	publisher         ports.MessagePublisher
+	notificationQueue string
+	emailLimiter      ports.RateLimiter
+	notifier          NotificationServiceInterface
+	tokenDuration     time.Duration
+	logger            *zap.Logger
+}
+
+// NewPasswordResetService creates a new instance of PasswordResetService
+func NewPasswordResetService(
+	userRepo ports.UserRepository,
+	emailRepo ports.UserEmailRepository,
+	resetRepo ports.PasswordResetRepository,
+	tokenRepo ports.TokenRepository,
+	passwordHasher ports.PasswordHasher,
+	publisher ports.MessagePublisher,
+	notificationQueue string,
+	emailLimiter ports.RateLimiter,
+	notifier NotificationServiceInterface,
+	tokenDuration time.Duration,
+	logger *zap.Logger,
+) *PasswordResetService {
+	return &PasswordResetService{
+		userRepo:          userRepo,
+		emailRepo:         emailRepo,
+		resetRepo:         resetRepo,
+		tokenRepo:         tokenRepo,
+		passwordHasher:    passwordHasher,
+		publisher:         publisher,
+		notificationQueue: notificationQueue,
+		emailLimiter:      emailLimiter,
+		notifier:          notifier,
+		tokenDuration:     tokenDuration,
+		logger:            logger,
+	}
+}
+
+// RequestReset sends a password reset token to the address when it is the primary email of an active user
+// or a verified secondary email. The outcome doesn't reveal whether the address is registered: unknown
+// addresses and delivery failures are only logged.
+func (s *PasswordResetService) RequestReset(ctx context.Context, email string) error {
+	address, ok := domain.NormalizeEmail(email)
+	if !ok {
+		return domainerrors.ErrInvalidEmail
+	}
+
+	// The limit applies to every address, registered or not
+	if err := reserveEmail(ctx, s.emailLimiter, address, s.logger); err != nil {
+		return err
+	}
+
+	user, recipient, err := s.findUser(ctx, strings.TrimSpace(email), address)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrUserNotFound) {
+			s.logger.Debug("password reset requested for an unknown address", logging.String("email", domain.MaskEmail(address)))
+			return nil
+		}
+		return err
+	}
+	if !user.IsActive() {
+		s.logger.Warn("password reset requested for an inactive user", zap.String("user_id", user.ID))
+		return nil
+	}
+
+	token, err := generateResetToken()
+	if err != nil {
+		s.logger.Error("failed to generate password reset token", zap.Error(err))
+		return internalError(err)
+	}
+
+	reset := &domain.PasswordReset{
+		UserID:    user.ID,
+		Email:     recipient,
+		CreatedAt: time.Now(),
+	}
+	if err := s.resetRepo.Store(ctx, hashSecret(token), reset, s.tokenDuration); err != nil {
+		s.logger.Error("failed to store password reset", zap.Error(err), zap.String("user_id", user.ID))
+		return internalError(err)
+	}
+
+	err = publishEmailNotification(ctx, s.publisher, s.notificationQueue, user, recipient, domain.NotificationPasswordReset, map[string]string{
+		"token":              token,
+		"expires_in_minutes": strconv.Itoa(int(s.tokenDuration.Minutes())),
+	})
+	if err != nil {
+		s.logger.Error("failed to publish password reset", zap.Error(err), zap.String("user_id", user.ID))
+		return nil
+	}
+
+	s.logger.Info("password reset token sent",
+		zap.String("user_id", user.ID),
+		logging.String("email", domain.MaskEmail(recipient)))
+	return nil
+}
+
+// ResetPassword sets a new password with a token sent by RequestReset and revokes every session of the user.
+// Tokens are single use, a weak password is rejected before the token is consumed.
+func (s *PasswordResetService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	if len(newPassword) < domain.MinPasswordLength {
+		return domainerrors.ErrWeakPassword
+	}
+
+	reset, err := s.resetRepo.Consume(ctx, hashSecret(token))
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrInvalidResetToken) {
+			return err
+		}
+		s.logger.Error("failed to consume password reset", zap.Error(err))
+		return internalError(err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, reset.UserID)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrUserNotFound) {
+			return domainerrors.ErrInvalidResetToken
+		}
+		s.logger.Error("failed to get user", zap.Error(err), zap.String("user_id", reset.UserID))
+		return internalError(err)
+	}
+	if !user.IsActive() {
+		return domainerrors.ErrInvalidResetToken
+	}
+
+	hash, err := s.passwordHasher.Hash(ctx, newPassword)
+	if err != nil {
+		s.logger.Error("failed to hash password", zap.Error(err))
+		return internalError(err)
+	}
+
+	user.SetPassword(hash, time.Now())
+	user.MustChangePassword = false
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		s.logger.Error("failed to update user password", zap.Error(err), zap.String("user_id", user.ID))
+		return internalError(err)
+	}
+
+	// Whoever knew the old password must not keep their sessions
+	if err := s.tokenRepo.DeleteUserTokens(ctx, user.ID, user.IDCitizen); err != nil {
+		s.logger.Error("failed to revoke user sessions", zap.Error(err), zap.String("user_id", user.ID))
+	}
+
+	if err := s.notifier.Notify(ctx, user, domain.NotificationPasswordChange, map[string]string{"method": "reset"}); err != nil {
+		s.logger.Error("failed to notify password change", zap.Error(err), zap.String("user_id", user.ID))
+	}
+
+	s.logger.Info("password reset",
+		zap.String("user_id", user.ID),
+		logging.String("email", domain.MaskEmail(reset.Email)))
+	return nil
+}
+
+// findUser returns the user owning the primary email (matched as entered, like at login) or the verified
+// secondary email (matched normalized), and the address to send the token to
+func (s *PasswordResetService) findUser(ctx context.Context, email, address string) (*domain.User, string, error) {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err == nil {
+		return user, user.Email, nil
+	}
+	if !errors.Is(err, domainerrors.ErrUserNotFound) {
+		s.logger.Error("failed to get user by email", zap.Error(err))
+		return nil, "", internalError(err)
+	}
+
+	userEmail, err := s.emailRepo.GetVerified(ctx, address)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrEmailNotFound) {
+			return nil, "", domainerrors.ErrUserNotFound
+		}
+		s.logger.Error("failed to get verified user email", zap.Error(err))
+		return nil, "", internalError(err)
+	}
+
+	user, err = s.userRepo.GetByID(ctx, userEmail.UserID)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrUserNotFound) {
+			return nil, "", err
+		}
+		s.logger.Error("failed to get user", zap.Error(err), zap.String("user_id", userEmail.UserID))
+		return nil, "", internalError(err)
+	}
+	return user, userEmail.Email, nil
+}
+
+// generateResetToken generates a high-entropy opaque password reset token
+func generateResetToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
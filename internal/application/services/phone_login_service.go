@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+)
+
+// PhoneLoginServiceInterface defines the methods of PhoneLoginService used by handlers.
+type PhoneLoginServiceInterface interface {
+	Enabled() bool
+	EnrollPhone(ctx context.Context, idCitizen int, phoneNumber string) (*domain.PhoneVerification, error)
+	RequestLoginCode(ctx context.Context, phoneNumber string) error
+	LoginWithPassword(ctx context.Context, phoneNumber, password string) (*domain.TokenPair, *domain.UserPublic, error)
+	LoginWithCode(ctx context.Context, phoneNumber, code string) (*domain.TokenPair, *domain.UserPublic, error)
+}
+
+// PhoneLoginAuthenticator is the part of AuthService used by PhoneLoginService
+type PhoneLoginAuthenticator interface {
+	LoginWithUser(ctx context.Context, email, password string) (*domain.TokenPair, *domain.UserPublic, error)
+	LoginVerifiedUser(ctx context.Context, user *domain.User) (*domain.TokenPair, *domain.UserPublic, error)
+}
+
+// PhoneLoginCodes is the part of PhoneService used by PhoneLoginService
+type PhoneLoginCodes interface {
+	StartEnrollment(ctx context.Context, idCitizen int, phoneNumber string) (*domain.PhoneVerification, error)
+	SendLoginCode(ctx context.Context, user *domain.User, number string) error
+	VerifyLoginCode(ctx context.Context, user *domain.User, code string) error
+}
+
+// PhoneLoginService lets users log in with their verified phone number instead of their email, either with
+// their password or with a one-time code sent by SMS. It is disabled unless explicitly enabled.
+// Wrong codes are counted by riskEngine like wrong passwords, so they lead to the same lockout.
+type PhoneLoginService struct {
+	userRepo    ports.UserRepository
+	phoneRepo   ports.PhoneNumberRepository
+	phoneCodes  PhoneLoginCodes
+	authService PhoneLoginAuthenticator
+	riskEngine  RiskEngine
+	enabled     bool
+	logger      *zap.Logger
+}
+
+// NewPhoneLoginService creates a new instance of PhoneLoginService
+func NewPhoneLoginService(
+	userRepo ports.UserRepository,
+	phoneRepo ports.PhoneNumberRepository,
+	phoneCodes PhoneLoginCodes,
+	authService PhoneLoginAuthenticator,
+	riskEngine RiskEngine,
+	enabled bool,
+	logger *zap.Logger,
+) *PhoneLoginService {
+	return &PhoneLoginService{
+		userRepo:    userRepo,
+		phoneRepo:   phoneRepo,
+		phoneCodes:  phoneCodes,
+		authService: authService,
+		riskEngine:  riskEngine,
+		enabled:     enabled,
+		logger:      logger,
+	}
+}
+
+// Enabled reports whether users can log in with their phone number
+func (s *PhoneLoginService) Enabled() bool {
+	return s.enabled
+}
+
+// EnrollPhone starts the enrollment of the phone number given at registration. The number is only usable
+// at login once the user confirms it with the code sent by SMS.
+func (s *PhoneLoginService) EnrollPhone(ctx context.Context, idCitizen int, phoneNumber string) (*domain.PhoneVerification, error) {
+	if !s.enabled {
+		return nil, domainerrors.ErrPhoneLoginDisabled
+	}
+	return s.phoneCodes.StartEnrollment(ctx, idCitizen, phoneNumber)
+}
+
+// RequestLoginCode sends a one-time login code to a verified phone number. The outcome doesn't reveal
+// whether the number is registered: unknown numbers and inactive users are only logged.
+func (s *PhoneLoginService) RequestLoginCode(ctx context.Context, phoneNumber string) error {
+	user, number, err := s.findUser(ctx, phoneNumber)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrPhoneNotFound) {
+			s.logger.Debug("login code requested for an unknown phone number", logging.String("phone_number", domain.MaskPhoneNumber(number)))
+			return nil
+		}
+		return err
+	}
+	if !user.IsActive() {
+		s.logger.Warn("login code requested for an inactive user", zap.String("user_id", user.ID))
+		return nil
+	}
+
+	return s.phoneCodes.SendLoginCode(ctx, user, number)
+}
+
+// LoginWithPassword authenticates a user by their verified phone number and password
+func (s *PhoneLoginService) LoginWithPassword(ctx context.Context, phoneNumber, password string) (*domain.TokenPair, *domain.UserPublic, error) {
+	user, _, err := s.findUser(ctx, phoneNumber)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrPhoneNotFound) {
+			return nil, nil, domainerrors.ErrInvalidCredentials
+		}
+		return nil, nil, err
+	}
+
+	// The password check, the risk policy and the failure accounting are the ones of the email login
+	return s.authService.LoginWithUser(ctx, user.Email, password)
+}
+
+// LoginWithCode authenticates a user by their verified phone number and the code sent by RequestLoginCode
+func (s *PhoneLoginService) LoginWithCode(ctx context.Context, phoneNumber, code string) (*domain.TokenPair, *domain.UserPublic, error) {
+	user, _, err := s.findUser(ctx, phoneNumber)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrPhoneNotFound) {
+			return nil, nil, domainerrors.ErrInvalidCredentials
+		}
+		return nil, nil, err
+	}
+
+	if err := s.phoneCodes.VerifyLoginCode(ctx, user, code); err != nil {
+		if errors.Is(err, domainerrors.ErrInvalidVerificationCode) {
+			s.logger.Warn("login failed: invalid phone login code", zap.String("user_id", user.ID))
+			s.riskEngine.RecordLoginFailure(ctx, user.Email, user)
+			return nil, nil, domainerrors.ErrInvalidCredentials
+		}
+		return nil, nil, err
+	}
+
+	return s.authService.LoginVerifiedUser(ctx, user)
+}
+
+// findUser returns the user owning the verified phone number and the normalized number.
+// ErrPhoneNotFound is returned when no user owns it.
+func (s *PhoneLoginService) findUser(ctx context.Context, phoneNumber string) (*domain.User, string, error) {
+	if !s.enabled {
+		return nil, "", domainerrors.ErrPhoneLoginDisabled
+	}
+
+	number, ok := domain.NormalizePhoneNumber(phoneNumber)
+	if !ok {
+		return nil, "", domainerrors.ErrInvalidPhoneNumber
+	}
+
+	phone, err := s.phoneRepo.GetByNumber(ctx, number)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrPhoneNotFound) {
+			return nil, number, err
+		}
+		s.logger.Error("failed to get phone number", zap.Error(err))
+		return nil, "", internalError(err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, phone.UserID)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrUserNotFound) {
+			return nil, number, domainerrors.ErrPhoneNotFound
+		}
+		s.logger.Error("failed to get user", zap.Error(err), zap.String("user_id", phone.UserID))
+		return nil, "", internalError(err)
+	}
+
+	return user, number, nil
+}
@@ -0,0 +1,356 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
+)
+
+// verificationCodeDigits is the length of the one-time codes sent by SMS or email
+const verificationCodeDigits = 6
+
+// PhoneServiceInterface defines the methods of PhoneService used by handlers.
+type PhoneServiceInterface interface {
+	GetPhoneNumber(ctx context.Context, idCitizen int) (*domain.PhoneNumber, error)
+	StartEnrollment(ctx context.Context, idCitizen int, phoneNumber string) (*domain.PhoneVerification, error)
+	VerifyEnrollment(ctx context.Context, idCitizen int, code string) (*domain.PhoneNumber, error)
+	RemovePhoneNumber(ctx context.Context, idCitizen int) error
+}
+
+// PhoneService manages the enrollment of the phone number used to receive one-time codes by SMS, and the
+// login codes sent to it. Every SMS is subject to a per-number rate limit and a global quota that caps
+// provider costs.
+type PhoneService struct {
+	userRepo         ports.UserRepository
+	phoneRepo        ports.PhoneNumberRepository
+	verificationRepo ports.PhoneVerificationRepository
+	loginCodeRepo    ports.PhoneVerificationRepository
+	smsSender        ports.SMSSender
+	numberLimiter    ports.RateLimiter
+	quotaLimiter     ports.RateLimiter
+	codeDuration     time.Duration
+	logger           *zap.Logger
+}
+
+// NewPhoneService creates a new instance of PhoneService
+func NewPhoneService(
+	userRepo ports.UserRepository,
+	phoneRepo ports.PhoneNumberRepository,
+	verificationRepo ports.PhoneVerificationRepository,
+	loginCodeRepo ports.PhoneVerificationRepository,
+	smsSender ports.SMSSender,
+	numberLimiter ports.RateLimiter,
+	quotaLimiter ports.RateLimiter,
+	codeDuration time.Duration,
+	logger *zap.Logger,
+) *PhoneService {
+	return &PhoneService{
+		userRepo:         userRepo,
+		phoneRepo:        phoneRepo,
+		verificationRepo: verificationRepo,
+		loginCodeRepo:    loginCodeRepo,
+		smsSender:        smsSender,
+		numberLimiter:    numberLimiter,
+		quotaLimiter:     quotaLimiter,
+		codeDuration:     codeDuration,
+		logger:           logger,
+	}
+}
+
+// GetPhoneNumber retrieves the verified phone number of a user
+func (s *PhoneService) GetPhoneNumber(ctx context.Context, idCitizen int) (*domain.PhoneNumber, error) {
+	user, err := s.userRepo.GetByIDCitizen(ctx, idCitizen)
+	if err != nil {
+		s.logger.Error("failed to get user", zap.Error(err), zap.Int("id_citizen", idCitizen))
+		return nil, err
+	}
+
+	phone, err := s.phoneRepo.GetByUserID(ctx, user.ID)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrPhoneNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to get phone number", zap.Error(err), zap.String("user_id", user.ID))
+		return nil, internalError(err)
+	}
+
+	return phone, nil
+}
+
+// StartEnrollment sends a verification code to the phone number.
+// The number is only saved once the code is confirmed with VerifyEnrollment.
+func (s *PhoneService) StartEnrollment(ctx context.Context, idCitizen int, phoneNumber string) (*domain.PhoneVerification, error) {
+	number, ok := domain.NormalizePhoneNumber(phoneNumber)
+	if !ok {
+		return nil, domainerrors.ErrInvalidPhoneNumber
+	}
+
+	user, err := s.userRepo.GetByIDCitizen(ctx, idCitizen)
+	if err != nil {
+		s.logger.Error("failed to get user", zap.Error(err), zap.Int("id_citizen", idCitizen))
+		return nil, err
+	}
+
+	// Numbers identify users at login, no SMS is spent on a number of another user
+	owner, err := s.phoneRepo.GetByNumber(ctx, number)
+	if err == nil && owner.UserID != user.ID {
+		return nil, domainerrors.ErrPhoneAlreadyRegistered
+	}
+	if err != nil && !errors.Is(err, domainerrors.ErrPhoneNotFound) {
+		s.logger.Error("failed to get phone number", zap.Error(err))
+		return nil, internalError(err)
+	}
+
+	if err := s.reserveSMS(ctx, number); err != nil {
+		return nil, err
+	}
+
+	code, err := generateVerificationCode()
+	if err != nil {
+		s.logger.Error("failed to generate verification code", zap.Error(err))
+		return nil, internalError(err)
+	}
+
+	now := time.Now()
+	verification := &domain.PhoneVerification{
+		UserID:    user.ID,
+		Number:    number,
+		CodeHash:  hashSecret(code),
+		ExpiresAt: now.Add(s.codeDuration),
+		CreatedAt: now,
+	}
+
+	if err := s.verificationRepo.Store(ctx, verification); err != nil {
+		s.logger.Error("failed to store phone verification", zap.Error(err), zap.String("user_id", user.ID))
+		return nil, internalError(err)
+	}
+
+	message := fmt.Sprintf("Your verification code is %s. It expires in %d minutes.", code, int(s.codeDuration.Minutes()))
+	if err := s.deliverSMS(ctx, number, message); err != nil {
+		if delErr := s.verificationRepo.Delete(ctx, user.ID); delErr != nil {
+			s.logger.Error("failed to delete phone verification", zap.Error(delErr), zap.String("user_id", user.ID))
+		}
+		return nil, err
+	}
+
+	s.logger.Info("phone verification code sent",
+		zap.String("user_id", user.ID),
+		logging.String("phone_number", domain.MaskPhoneNumber(number)))
+	return verification, nil
+}
+
+// VerifyEnrollment checks the code sent by StartEnrollment and saves the phone number as verified.
+// The pending verification is discarded after MaxPhoneVerificationAttempts wrong codes.
+func (s *PhoneService) VerifyEnrollment(ctx context.Context, idCitizen int, code string) (*domain.PhoneNumber, error) {
+	user, err := s.userRepo.GetByIDCitizen(ctx, idCitizen)
+	if err != nil {
+		s.logger.Error("failed to get user", zap.Error(err), zap.Int("id_citizen", idCitizen))
+		return nil, err
+	}
+
+	verification, err := s.checkCode(ctx, s.verificationRepo, user.ID, code)
+	if err != nil {
+		return nil, err
+	}
+
+	phone := &domain.PhoneNumber{
+		UserID:     user.ID,
+		Number:     verification.Number,
+		VerifiedAt: time.Now(),
+	}
+	if err := s.phoneRepo.Upsert(ctx, phone); err != nil {
+		if errors.Is(err, domainerrors.ErrPhoneAlreadyRegistered) {
+			return nil, err
+		}
+		s.logger.Error("failed to save phone number", zap.Error(err), zap.String("user_id", user.ID))
+		// The code was right, the user may retry with it
+		s.restoreVerification(ctx, s.verificationRepo, verification)
+		return nil, internalError(err)
+	}
+
+	s.logger.Info("phone number verified",
+		zap.String("user_id", user.ID),
+		logging.String("phone_number", domain.MaskPhoneNumber(phone.Number)))
+	return phone, nil
+}
+
+// RemovePhoneNumber deletes the phone number of a user
+func (s *PhoneService) RemovePhoneNumber(ctx context.Context, idCitizen int) error {
+	user, err := s.userRepo.GetByIDCitizen(ctx, idCitizen)
+	if err != nil {
+		s.logger.Error("failed to get user", zap.Error(err), zap.Int("id_citizen", idCitizen))
+		return err
+	}
+
+	if err := s.phoneRepo.Delete(ctx, user.ID); err != nil {
+		if errors.Is(err, domainerrors.ErrPhoneNotFound) {
+			return err
+		}
+		s.logger.Error("failed to delete phone number", zap.Error(err), zap.String("user_id", user.ID))
+		return internalError(err)
+	}
+
+	s.logger.Info("phone number removed", zap.String("user_id", user.ID))
+	return nil
+}
+
+// SendLoginCode sends a one-time login code to the verified phone number of a user
+func (s *PhoneService) SendLoginCode(ctx context.Context, user *domain.User, number string) error {
+	if err := s.reserveSMS(ctx, number); err != nil {
+		return err
+	}
+
+	code, err := generateVerificationCode()
+	if err != nil {
+		s.logger.Error("failed to generate login code", zap.Error(err))
+		return internalError(err)
+	}
+
+	now := time.Now()
+	loginCode := &domain.PhoneVerification{
+		UserID:    user.ID,
+		Number:    number,
+		CodeHash:  hashSecret(code),
+		ExpiresAt: now.Add(s.codeDuration),
+		CreatedAt: now,
+	}
+	if err := s.loginCodeRepo.Store(ctx, loginCode); err != nil {
+		s.logger.Error("failed to store login code", zap.Error(err), zap.String("user_id", user.ID))
+		return internalError(err)
+	}
+
+	message := fmt.Sprintf("Your login code is %s. It expires in %d minutes.", code, int(s.codeDuration.Minutes()))
+	if err := s.deliverSMS(ctx, number, message); err != nil {
+		s.deleteVerification(ctx, s.loginCodeRepo, user.ID)
+		return err
+	}
+
+	s.logger.Info("phone login code sent",
+		zap.String("user_id", user.ID),
+		logging.String("phone_number", domain.MaskPhoneNumber(number)))
+	return nil
+}
+
+// VerifyLoginCode checks the code sent by SendLoginCode. Like enrollment codes, login codes are single use
+// and discarded after MaxPhoneVerificationAttempts wrong codes.
+func (s *PhoneService) VerifyLoginCode(ctx context.Context, user *domain.User, code string) error {
+	_, err := s.checkCode(ctx, s.loginCodeRepo, user.ID, code)
+	return err
+}
+
+// checkCode compares a code with the pending verification of a user stored in repo. The verification is
+// consumed before the comparison, so concurrent requests can't check codes against it past the attempt
+// limit: a wrong code stores it back with one more attempt, until the attempts are exhausted.
+func (s *PhoneService) checkCode(ctx context.Context, repo ports.PhoneVerificationRepository, userID, code string) (*domain.PhoneVerification, error) {
+	verification, err := repo.Consume(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrInvalidVerificationCode) {
+			return nil, err
+		}
+		s.logger.Error("failed to consume phone verification", zap.Error(err), zap.String("user_id", userID))
+		return nil, internalError(err)
+	}
+
+	if verification.IsExpired() {
+		return nil, domainerrors.ErrInvalidVerificationCode
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashSecret(code)), []byte(verification.CodeHash)) != 1 {
+		verification.Attempts++
+		if verification.AttemptsExhausted() {
+			s.logger.Warn("phone verification attempts exhausted", zap.String("user_id", userID))
+		} else {
+			s.restoreVerification(ctx, repo, verification)
+		}
+		return nil, domainerrors.ErrInvalidVerificationCode
+	}
+
+	return verification, nil
+}
+
+// reserveSMS checks the per-number rate limit and the global quota before an SMS is sent.
+// The per-number limit is checked first so a flooded number does not consume the quota.
+func (s *PhoneService) reserveSMS(ctx context.Context, number string) error {
+	status, err := s.numberLimiter.Hit(ctx, domain.SMSNumberRateLimitKey(number))
+	if err != nil {
+		s.logger.Error("failed to check sms rate limit", zap.Error(err))
+		return internalError(err)
+	}
+	if status.Exceeded() {
+		metrics.IncSMSRejected("number_rate_limit")
+		s.logger.Warn("sms rate limit exceeded", logging.String("phone_number", domain.MaskPhoneNumber(number)))
+		return domainerrors.ErrSMSRateLimited
+	}
+
+	status, err = s.quotaLimiter.Hit(ctx, domain.SMSQuotaRateLimitKey())
+	if err != nil {
+		s.logger.Error("failed to check sms quota", zap.Error(err))
+		return internalError(err)
+	}
+	if status.Exceeded() {
+		metrics.IncSMSRejected("quota")
+		s.logger.Error("sms quota exceeded", zap.Int("quota", status.Limit), zap.Time("reset_at", status.ResetAt))
+		return domainerrors.ErrSMSQuotaExceeded
+	}
+
+	return nil
+}
+
+// deliverSMS hands a message to the SMS provider
+func (s *PhoneService) deliverSMS(ctx context.Context, number, message string) error {
+	if err := s.smsSender.Send(ctx, number, message); err != nil {
+		metrics.IncSMSMessages(s.smsSender.Provider(), "failed")
+		s.logger.Error("failed to send sms", zap.Error(err), zap.String("provider", s.smsSender.Provider()))
+		return domainerrors.ErrSMSDeliveryFailed
+	}
+
+	metrics.IncSMSMessages(s.smsSender.Provider(), "sent")
+	return nil
+}
+
+// restoreVerification stores back a verification consumed by checkCode (best effort)
+func (s *PhoneService) restoreVerification(ctx context.Context, repo ports.PhoneVerificationRepository, verification *domain.PhoneVerification) {
+	if err := repo.Restore(ctx, verification); err != nil {
+		s.logger.Error("failed to restore phone verification", zap.Error(err), zap.String("user_id", verification.UserID))
+	}
+}
+
+// deleteVerification removes a pending verification from repo (best effort)
+func (s *PhoneService) deleteVerification(ctx context.Context, repo ports.PhoneVerificationRepository, userID string) {
+	if err := repo.Delete(ctx, userID); err != nil {
+		s.logger.Error("failed to delete phone verification", zap.Error(err), zap.String("user_id", userID))
+	}
+}
+
+// generateVerificationCode generates a numeric one-time code
+func generateVerificationCode() (string, error) {
+	max := big.NewInt(1)
+	for i := 0; i < verificationCodeDigits; i++ {
+		max.Mul(max, big.NewInt(10))
+	}
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate verification code: %w", err)
+	}
+	return fmt.Sprintf("%0*d", verificationCodeDigits, n.Int64()), nil
+}
+
+// hashSecret hashes a one-time code or token so it is never stored in clear text
+func hashSecret(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+)
+
+// processOnce runs process unless the message was already processed. The message is claimed before
+// processing and released when process fails, so a redelivery after a transient error is processed again.
+func processOnce(
+	ctx context.Context,
+	processedRepo ports.ProcessedMessageRepository,
+	queue, messageID string,
+	logger *zap.Logger,
+	process func(ctx context.Context) error,
+) error {
+	first, err := processedRepo.MarkProcessed(ctx, queue, messageID)
+	if err != nil {
+		return fmt.Errorf("failed to check processed message: %w", err)
+	}
+	if !first {
+		logger.Info("skipping duplicate message", zap.String("queue", queue), zap.String("message_id", messageID))
+		return nil
+	}
+
+	if err := process(ctx); err != nil {
+		if unmarkErr := processedRepo.Unmark(ctx, queue, messageID); unmarkErr != nil {
+			logger.Error("failed to unmark message, its redelivery will be skipped",
+				zap.Error(unmarkErr), zap.String("queue", queue), zap.String("message_id", messageID))
+		}
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,328 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
+)
+
+// Quota names, used as metric labels
+const (
+	quotaTokensPerHour  = "tokens_per_hour"
+	quotaActiveSessions = "active_sessions"
+)
+
+// QuotaEnforcer enforces the issuance quotas before tokens are issued
+type QuotaEnforcer interface {
+	// EnforceClientIssuance counts a token issued to an OAuth2 client, returning a *QuotaExceededError
+	// when its hourly quota is exhausted
+	EnforceClientIssuance(ctx context.Context, clientID string) error
+	// EnforceUserIssuance counts the tokens issued to a user, returning a *QuotaExceededError when its
+	// hourly quota is exhausted or, for a new session, when the user has too many active sessions
+	EnforceUserIssuance(ctx context.Context, user *domain.User, newSession bool) error
+}
+
+// QuotaServiceInterface defines the methods of QuotaService used by handlers.
+type QuotaServiceInterface interface {
+	ListQuotas(ctx context.Context) ([]*domain.IssuanceQuota, error)
+	GetQuota(ctx context.Context, subjectType, subjectID string) (*domain.QuotaUsage, error)
+	SetQuota(ctx context.Context, quota *domain.IssuanceQuota, actor string) (*domain.IssuanceQuota, error)
+	DeleteQuota(ctx context.Context, subjectType, subjectID, actor string) error
+}
+
+// QuotaDefaults are the quotas applied to the clients and users without an override. Zero means unlimited.
+type QuotaDefaults struct {
+	ClientMaxTokensPerHour int
+	UserMaxTokensPerHour   int
+	UserMaxActiveSessions  int
+}
+
+// QuotaService enforces the token issuance quotas of OAuth2 clients and users, and manages their overrides.
+// Quotas fail open: when the quota or usage stores are unavailable the tokens are issued anyway.
+type QuotaService struct {
+	quotaRepo    ports.QuotaRepository
+	usageCounter ports.RateLimiter
+	tokenRepo    ports.TokenRepository
+	clientRepo   ports.OAuthClientRepository
+	userRepo     ports.UserRepository
+	auditRepo    ports.AuditLogRepository
+	defaults     QuotaDefaults
+	logger       *zap.Logger
+}
+
+// NewQuotaService creates a new instance of QuotaService. usageCounter counts the tokens issued within
+// the hour, its own limit is ignored.
+func NewQuotaService(
+	quotaRepo ports.QuotaRepository,
+	usageCounter ports.RateLimiter,
+	tokenRepo ports.TokenRepository,
+	clientRepo ports.OAuthClientRepository,
+	userRepo ports.UserRepository,
+	auditRepo ports.AuditLogRepository,
+	defaults QuotaDefaults,
+	logger *zap.Logger,
+) *QuotaService {
+	return &QuotaService{
+		quotaRepo:    quotaRepo,
+		usageCounter: usageCounter,
+		tokenRepo:    tokenRepo,
+		clientRepo:   clientRepo,
+		userRepo:     userRepo,
+		auditRepo:    auditRepo,
+		defaults:     defaults,
+		logger:       logger,
+	}
+}
+
+// EnforceClientIssuance counts a token issued to the client against its hourly quota
+func (s *QuotaService) EnforceClientIssuance(ctx context.Context, clientID string) error {
+	quota, _, err := s.effectiveQuota(ctx, domain.QuotaSubjectClient, clientID)
+	if err != nil {
+		s.logger.Error("failed to get quota, issuing without it", zap.Error(err), logging.String("client_id", clientID))
+		return nil
+	}
+	return s.enforceTokensPerHour(ctx, quota)
+}
+
+// EnforceUserIssuance checks the active sessions of the user when a new session is opened, then counts
+// the issuance against its hourly quota
+func (s *QuotaService) EnforceUserIssuance(ctx context.Context, user *domain.User, newSession bool) error {
+	quota, _, err := s.effectiveQuota(ctx, domain.QuotaSubjectUser, user.ID)
+	if err != nil {
+		s.logger.Error("failed to get quota, issuing without it", zap.Error(err), zap.String("user_id", user.ID))
+		return nil
+	}
+
+	if newSession && quota.MaxActiveSessions > 0 {
+		active, err := s.tokenRepo.CountActiveSessions(ctx, user.IDCitizen)
+		if err != nil {
+			s.logger.Error("failed to count active sessions, issuing without session quota", zap.Error(err), zap.String("user_id", user.ID))
+		} else if active >= quota.MaxActiveSessions {
+			metrics.IncQuotaRejections(domain.QuotaSubjectUser, quotaActiveSessions)
+			s.logger.Warn("session quota exceeded",
+				zap.String("user_id", user.ID),
+				zap.Int("active_sessions", active),
+				zap.Int("limit", quota.MaxActiveSessions))
+			return &domainerrors.QuotaExceededError{
+				Err:     domainerrors.ErrSessionQuotaExceeded,
+				Subject: domain.QuotaSubjectUser,
+				Limit:   quota.MaxActiveSessions,
+			}
+		}
+	}
+
+	return s.enforceTokensPerHour(ctx, quota)
+}
+
+// enforceTokensPerHour counts an issuance in the hourly window of the subject
+func (s *QuotaService) enforceTokensPerHour(ctx context.Context, quota *domain.IssuanceQuota) error {
+	if quota.MaxTokensPerHour == 0 {
+		return nil
+	}
+
+	status, err := s.usageCounter.Hit(ctx, domain.QuotaUsageKey(quota.SubjectType, quota.SubjectID))
+	if err != nil {
+		s.logger.Error("failed to count token issuance, issuing without token quota", zap.Error(err),
+			zap.String("subject_type", quota.SubjectType),
+			zap.String("subject_id", quota.SubjectID))
+		return nil
+	}
+	if status.Used <= quota.MaxTokensPerHour {
+		return nil
+	}
+
+	metrics.IncQuotaRejections(quota.SubjectType, quotaTokensPerHour)
+	s.logger.Warn("token quota exceeded",
+		zap.String("subject_type", quota.SubjectType),
+		zap.String("subject_id", quota.SubjectID),
+		zap.Int("limit", quota.MaxTokensPerHour),
+		zap.Time("reset_at", status.ResetAt))
+
+	retryAfter := time.Until(status.ResetAt)
+	if retryAfter < time.Second {
+		retryAfter = time.Second
+	}
+	return &domainerrors.QuotaExceededError{
+		Err:        domainerrors.ErrTokenQuotaExceeded,
+		Subject:    quota.SubjectType,
+		Limit:      quota.MaxTokensPerHour,
+		RetryAfter: retryAfter,
+	}
+}
+
+// ListQuotas retrieves every quota override
+func (s *QuotaService) ListQuotas(ctx context.Context) ([]*domain.IssuanceQuota, error) {
+	quotas, err := s.quotaRepo.List(ctx)
+	if err != nil {
+		s.logger.Error("failed to list quotas", zap.Error(err))
+		return nil, internalError(err)
+	}
+	return quotas, nil
+}
+
+// GetQuota retrieves the effective quota of a client or user along with its current usage
+func (s *QuotaService) GetQuota(ctx context.Context, subjectType, subjectID string) (*domain.QuotaUsage, error) {
+	user, err := s.getSubject(ctx, subjectType, subjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	quota, isDefault, err := s.effectiveQuota(ctx, subjectType, subjectID)
+	if err != nil {
+		s.logger.Error("failed to get quota", zap.Error(err), zap.String("subject_type", subjectType), zap.String("subject_id", subjectID))
+		return nil, internalError(err)
+	}
+
+	status, err := s.usageCounter.Peek(ctx, domain.QuotaUsageKey(subjectType, subjectID))
+	if err != nil {
+		s.logger.Error("failed to get quota usage", zap.Error(err), zap.String("subject_type", subjectType), zap.String("subject_id", subjectID))
+		return nil, internalError(err)
+	}
+
+	usage := &domain.QuotaUsage{
+		Quota:        *quota,
+		Default:      isDefault,
+		TokensIssued: status.Used,
+		ResetAt:      status.ResetAt,
+	}
+
+	if user != nil {
+		usage.ActiveSessions, err = s.tokenRepo.CountActiveSessions(ctx, user.IDCitizen)
+		if err != nil {
+			s.logger.Error("failed to count active sessions", zap.Error(err), zap.String("user_id", user.ID))
+			return nil, internalError(err)
+		}
+	}
+
+	return usage, nil
+}
+
+// SetQuota creates or replaces the quota override of a client or user. actor identifies who changed it.
+func (s *QuotaService) SetQuota(ctx context.Context, quota *domain.IssuanceQuota, actor string) (*domain.IssuanceQuota, error) {
+	if _, err := s.getSubject(ctx, quota.SubjectType, quota.SubjectID); err != nil {
+		return nil, err
+	}
+
+	if err := quota.Validate(); err != nil {
+		return nil, domainerrors.ErrInvalidQuota
+	}
+
+	if err := s.quotaRepo.Upsert(ctx, quota); err != nil {
+		s.logger.Error("failed to update quota", zap.Error(err), zap.String("subject_type", quota.SubjectType), zap.String("subject_id", quota.SubjectID))
+		return nil, internalError(err)
+	}
+
+	s.recordQuotaUpdated(ctx, quota.SubjectType, quota.SubjectID, actor, map[string]string{
+		"subject_type":        quota.SubjectType,
+		"max_tokens_per_hour": strconv.Itoa(quota.MaxTokensPerHour),
+		"max_active_sessions": strconv.Itoa(quota.MaxActiveSessions),
+	})
+
+	s.logger.Info("quota updated",
+		zap.String("subject_type", quota.SubjectType),
+		zap.String("subject_id", quota.SubjectID),
+		zap.String("actor", actor))
+	return quota, nil
+}
+
+// DeleteQuota removes the quota override of a client or user, restoring the default quota
+func (s *QuotaService) DeleteQuota(ctx context.Context, subjectType, subjectID, actor string) error {
+	if !domain.IsValidQuotaSubject(subjectType) {
+		return domainerrors.ErrInvalidQuota
+	}
+
+	if err := s.quotaRepo.Delete(ctx, subjectType, subjectID); err != nil {
+		if errors.Is(err, domainerrors.ErrQuotaNotFound) {
+			return err
+		}
+		s.logger.Error("failed to delete quota", zap.Error(err), zap.String("subject_type", subjectType), zap.String("subject_id", subjectID))
+		return internalError(err)
+	}
+
+	s.recordQuotaUpdated(ctx, subjectType, subjectID, actor, map[string]string{
+		"subject_type": subjectType,
+		"reset":        "default",
+	})
+
+	s.logger.Info("quota reset to default",
+		zap.String("subject_type", subjectType),
+		zap.String("subject_id", subjectID),
+		zap.String("actor", actor))
+	return nil
+}
+
+// effectiveQuota returns the quota override of the subject, or the default quota when none is set
+func (s *QuotaService) effectiveQuota(ctx context.Context, subjectType, subjectID string) (*domain.IssuanceQuota, bool, error) {
+	quota, err := s.quotaRepo.Get(ctx, subjectType, subjectID)
+	if err == nil {
+		return quota, false, nil
+	}
+	if !errors.Is(err, domainerrors.ErrQuotaNotFound) {
+		return nil, false, err
+	}
+
+	quota = &domain.IssuanceQuota{SubjectType: subjectType, SubjectID: subjectID}
+	switch subjectType {
+	case domain.QuotaSubjectClient:
+		quota.MaxTokensPerHour = s.defaults.ClientMaxTokensPerHour
+	case domain.QuotaSubjectUser:
+		quota.MaxTokensPerHour = s.defaults.UserMaxTokensPerHour
+		quota.MaxActiveSessions = s.defaults.UserMaxActiveSessions
+	}
+	return quota, true, nil
+}
+
+// getSubject checks that the client or user exists, returning the user for user subjects
+func (s *QuotaService) getSubject(ctx context.Context, subjectType, subjectID string) (*domain.User, error) {
+	switch subjectType {
+	case domain.QuotaSubjectClient:
+		if _, err := s.clientRepo.GetByClientID(ctx, subjectID); err != nil {
+			if errors.Is(err, domainerrors.ErrClientNotFound) {
+				return nil, err
+			}
+			s.logger.Error("failed to get oauth client", zap.Error(err), logging.String("client_id", subjectID))
+			return nil, internalError(err)
+		}
+		return nil, nil
+	case domain.QuotaSubjectUser:
+		user, err := s.userRepo.GetByID(ctx, subjectID)
+		if err != nil {
+			if errors.Is(err, domainerrors.ErrUserNotFound) {
+				return nil, err
+			}
+			s.logger.Error("failed to get user", zap.Error(err), zap.String("user_id", subjectID))
+			return nil, internalError(err)
+		}
+		return user, nil
+	default:
+		return nil, domainerrors.ErrInvalidQuota
+	}
+}
+
+// recordQuotaUpdated writes the audit record of a quota change (best effort). The records of clients carry
+// their tags, so the changes can be traced to the team owning the client.
+func (s *QuotaService) recordQuotaUpdated(ctx context.Context, subjectType, subjectID, actor string, details map[string]string) {
+	if subjectType == domain.QuotaSubjectClient {
+		if client, err := s.clientRepo.GetByClientID(ctx, subjectID); err == nil && len(client.Tags) > 0 {
+			details["client_tags"] = strings.Join(client.Tags, ",")
+		}
+	}
+
+	record := domain.NewAuditRecord(domain.AuditActionQuotaUpdated, actor, subjectID, details)
+	if err := s.auditRepo.Record(ctx, record); err != nil {
+		s.logger.Error("failed to write audit record", zap.Error(err),
+			zap.String("subject_type", subjectType),
+			zap.String("subject_id", subjectID),
+			zap.String("actor", actor))
+	}
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// ReadinessChecker reports the warm-up steps still pending before the service can receive traffic
+type ReadinessChecker interface {
+	PendingSteps() []string
+}
+
+// ReadinessGate tracks the warm-up steps (schema migration, consumer registration, key loading, etc.)
+// that must complete before the replica is reported ready, so load balancers don't send traffic to a
+// half-initialized replica
+type ReadinessGate struct {
+	mu      sync.RWMutex
+	steps   []string
+	pending map[string]bool
+	logger  *zap.Logger
+}
+
+// NewReadinessGate creates a new instance of ReadinessGate with the given steps pending
+func NewReadinessGate(logger *zap.Logger, steps ...string) *ReadinessGate {
+	pending := make(map[string]bool, len(steps))
+	for _, step := range steps {
+		pending[step] = true
+	}
+	return &ReadinessGate{
+		steps:   steps,
+		pending: pending,
+		logger:  logger,
+	}
+}
+
+// Complete marks a warm-up step as done
+func (g *ReadinessGate) Complete(step string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !g.pending[step] {
+		return
+	}
+	delete(g.pending, step)
+
+	g.logger.Info("warm-up step completed", zap.String("step", step), zap.Int("pending", len(g.pending)))
+	if len(g.pending) == 0 {
+		g.logger.Info("warm-up completed, service is ready to receive traffic")
+	}
+}
+
+// PendingSteps returns the steps not completed yet, in declaration order
+func (g *ReadinessGate) PendingSteps() []string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var pending []string
+	for _, step := range g.steps {
+		if g.pending[step] {
+			pending = append(pending, step)
+		}
+	}
+	return pending
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
+)
+
+// RefreshAnomalyDetector detects anomalous refresh patterns of a session
+type RefreshAnomalyDetector interface {
+	// EvaluateRefresh records the refresh of the session and returns its risk reasons, empty when the
+	// refresh pattern is normal. It never fails the refresh.
+	EvaluateRefresh(ctx context.Context, user *domain.User, session *domain.RefreshTokenData) []string
+}
+
+// RefreshAnomalyService analyzes the refresh activity of refresh token families: a family refreshed from
+// many IP addresses suggests a stolen refresh token, and a family refreshed unusually often suggests a
+// misbehaving or automated client. Anomalous refreshes are counted in metrics and recorded in the audit log.
+type RefreshAnomalyService struct {
+	activityRepo ports.RefreshActivityRepository
+	auditRepo    ports.AuditLogRepository
+	policy       domain.RefreshAnomalyPolicy
+	logger       *zap.Logger
+}
+
+// NewRefreshAnomalyService creates a new instance of RefreshAnomalyService
+func NewRefreshAnomalyService(
+	activityRepo ports.RefreshActivityRepository,
+	auditRepo ports.AuditLogRepository,
+	policy domain.RefreshAnomalyPolicy,
+	logger *zap.Logger,
+) *RefreshAnomalyService {
+	return &RefreshAnomalyService{
+		activityRepo: activityRepo,
+		auditRepo:    auditRepo,
+		policy:       policy,
+		logger:       logger,
+	}
+}
+
+// EvaluateRefresh records the refresh in the activity of the token family and evaluates the policy.
+// Sessions without a family and storage failures yield no reasons.
+func (s *RefreshAnomalyService) EvaluateRefresh(ctx context.Context, user *domain.User, session *domain.RefreshTokenData) []string {
+	if session == nil || session.FamilyID == "" {
+		return nil
+	}
+
+	var ip string
+	if info, ok := domain.ClientInfoFromContext(ctx); ok {
+		ip = info.IP
+	}
+
+	activity, err := s.activityRepo.Record(ctx, session.FamilyID, ip)
+	if err != nil {
+		s.logger.Error("failed to record refresh activity", zap.Error(err), zap.String("user_id", user.ID))
+		return nil
+	}
+	metrics.ObserveRefreshFamilyActivity(activity.DistinctIPs, activity.Refreshes)
+
+	reasons := s.policy.Evaluate(*activity)
+	if len(reasons) == 0 {
+		return nil
+	}
+
+	for _, reason := range reasons {
+		metrics.IncRefreshAnomalies(reason)
+	}
+	s.logger.Warn("anomalous refresh activity",
+		zap.String("user_id", user.ID),
+		zap.Int("id_citizen", user.IDCitizen),
+		zap.String("family_id", session.FamilyID),
+		zap.Strings("reasons", reasons),
+		zap.String("ip", ip),
+		zap.Int("distinct_ips", activity.DistinctIPs),
+		zap.Int("refreshes", activity.Refreshes),
+		zap.Duration("window", s.policy.Window))
+
+	record := domain.NewAuditRecord(domain.AuditActionRefreshAnomaly, "system:refresh-analytics", user.ID, map[string]string{
+		"reasons":      strings.Join(reasons, ","),
+		"family_id":    session.FamilyID,
+		"ip":           ip,
+		"distinct_ips": strconv.Itoa(activity.DistinctIPs),
+		"refreshes":    strconv.Itoa(activity.Refreshes),
+		"window":       s.policy.Window.String(),
+	})
+	if err := s.auditRepo.Record(ctx, record); err != nil {
+		s.logger.Error("failed to write audit record", zap.Error(err), zap.String("user_id", user.ID))
+	}
+
+	return reasons
+}
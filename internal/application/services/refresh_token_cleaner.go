@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+)
+
+// RefreshTokenCleanupPolicy controls how expired refresh tokens are removed from the durable store
+type RefreshTokenCleanupPolicy struct {
+	Interval  time.Duration
+	BatchSize int
+}
+
+// RefreshTokenCleaner periodically deletes the expired refresh tokens from the durable store. Expired
+// tokens are never returned by the store, they are only deleted to keep the table small.
+type RefreshTokenCleaner struct {
+	store  ports.RefreshTokenStore
+	policy RefreshTokenCleanupPolicy
+	logger *zap.Logger
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewRefreshTokenCleaner creates a new instance of RefreshTokenCleaner
+func NewRefreshTokenCleaner(store ports.RefreshTokenStore, policy RefreshTokenCleanupPolicy, logger *zap.Logger) *RefreshTokenCleaner {
+	return &RefreshTokenCleaner{
+		store:  store,
+		policy: policy,
+		logger: logger,
+	}
+}
+
+// Run deletes the expired refresh tokens every interval until the context is cancelled
+func (c *RefreshTokenCleaner) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.policy.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := c.RemoveExpired(ctx); err != nil && ctx.Err() == nil {
+				c.logger.Error("failed to remove expired refresh tokens", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Start runs the cleaner in the background, it implements Component
+func (c *RefreshTokenCleaner) Start(ctx context.Context) error {
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	c.cancel = cancel
+	c.done = make(chan struct{})
+	go func() {
+		defer close(c.done)
+		c.Run(runCtx)
+	}()
+	return nil
+}
+
+// Stop stops the background cleaner and waits for the batch in progress, until the context is done
+func (c *RefreshTokenCleaner) Stop(ctx context.Context) error {
+	if c.cancel == nil {
+		return nil
+	}
+	c.cancel()
+	select {
+	case <-c.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RemoveExpired deletes the expired refresh tokens in batches and returns how many were deleted
+func (c *RefreshTokenCleaner) RemoveExpired(ctx context.Context) (int, error) {
+	now := time.Now()
+	removed := 0
+
+	for ctx.Err() == nil {
+		deleted, err := c.store.DeleteExpired(ctx, now, c.policy.BatchSize)
+		if err != nil {
+			return removed, err
+		}
+		removed += deleted
+
+		if deleted < c.policy.BatchSize {
+			break
+		}
+	}
+
+	if removed > 0 {
+		c.logger.Info("expired refresh tokens removed", zap.Int("count", removed))
+	}
+	return removed, ctx.Err()
+}
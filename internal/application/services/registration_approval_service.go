@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// RegistrationApprovalServiceInterface defines the methods of RegistrationApprovalService used by handlers.
+type RegistrationApprovalServiceInterface interface {
+	ListUsers(ctx context.Context, status domain.UserStatus, limit, offset int) ([]*domain.User, error)
+	ApproveUser(ctx context.Context, userID, actor string) (*domain.User, error)
+	RejectUser(ctx context.Context, userID, reason, actor string) (*domain.User, error)
+}
+
+// RegistrationApprovalService lets administrators review the self-registrations waiting for approval
+type RegistrationApprovalService struct {
+	userRepo  ports.UserRepository
+	auditRepo ports.AuditLogRepository
+	notifier  NotificationServiceInterface
+	logger    *zap.Logger
+}
+
+// NewRegistrationApprovalService creates a new instance of RegistrationApprovalService
+func NewRegistrationApprovalService(
+	userRepo ports.UserRepository,
+	auditRepo ports.AuditLogRepository,
+	notifier NotificationServiceInterface,
+	logger *zap.Logger,
+) *RegistrationApprovalService {
+	return &RegistrationApprovalService{
+		userRepo:  userRepo,
+		auditRepo: auditRepo,
+		notifier:  notifier,
+		logger:    logger,
+	}
+}
+
+// ListUsers retrieves a page of the users in a status, oldest first
+func (s *RegistrationApprovalService) ListUsers(ctx context.Context, status domain.UserStatus, limit, offset int) ([]*domain.User, error) {
+	users, err := s.userRepo.ListByStatus(ctx, status, limit, offset)
+	if err != nil {
+		s.logger.Error("failed to list users", zap.Error(err), zap.String("status", status.String()))
+		return nil, internalError(err)
+	}
+	return users, nil
+}
+
+// ApproveUser activates a user waiting for approval, who can log in from then on
+func (s *RegistrationApprovalService) ApproveUser(ctx context.Context, userID, actor string) (*domain.User, error) {
+	user, err := s.transition(ctx, userID, domain.UserStatusActive)
+	if err != nil {
+		return nil, err
+	}
+
+	s.record(ctx, domain.AuditActionUserApproved, actor, user, nil)
+
+	if err := s.notifier.Notify(ctx, user, domain.NotificationRegistrationApproved, nil); err != nil {
+		s.logger.Error("failed to notify registration approval", zap.Error(err), zap.String("user_id", user.ID))
+	}
+
+	s.logger.Info("registration approved", zap.String("user_id", user.ID), zap.String("actor", actor))
+	return user, nil
+}
+
+// RejectUser rejects a user waiting for approval, who can never log in.
+// The reason is optional and is sent to the user.
+func (s *RegistrationApprovalService) RejectUser(ctx context.Context, userID, reason, actor string) (*domain.User, error) {
+	user, err := s.transition(ctx, userID, domain.UserStatusRejected)
+	if err != nil {
+		return nil, err
+	}
+
+	var details map[string]string
+	if reason != "" {
+		details = map[string]string{"reason": reason}
+	}
+	s.record(ctx, domain.AuditActionUserRejected, actor, user, details)
+
+	if err := s.notifier.Notify(ctx, user, domain.NotificationRegistrationRejected, details); err != nil {
+		s.logger.Error("failed to notify registration rejection", zap.Error(err), zap.String("user_id", user.ID))
+	}
+
+	s.logger.Info("registration rejected", zap.String("user_id", user.ID), zap.String("actor", actor))
+	return user, nil
+}
+
+// transition moves a user waiting for approval to the given status
+func (s *RegistrationApprovalService) transition(ctx context.Context, userID string, status domain.UserStatus) (*domain.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrUserNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to get user", zap.Error(err), zap.String("user_id", userID))
+		return nil, internalError(err)
+	}
+
+	if !user.IsPendingApproval() {
+		return nil, domainerrors.ErrUserNotPendingApproval
+	}
+
+	user.Status = status
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		if errors.Is(err, domainerrors.ErrUserNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to update user status", zap.Error(err), zap.String("user_id", user.ID))
+		return nil, internalError(err)
+	}
+	return user, nil
+}
+
+// record writes the audit record of a decision, a failure does not undo it
+func (s *RegistrationApprovalService) record(ctx context.Context, action domain.AuditAction, actor string, user *domain.User, details map[string]string) {
+	record := domain.NewAuditRecord(action, actor, user.ID, details)
+	if err := s.auditRepo.Record(ctx, record); err != nil {
+		s.logger.Error("failed to write audit record", zap.Error(err), zap.String("user_id", user.ID), zap.String("actor", actor))
+	}
+}
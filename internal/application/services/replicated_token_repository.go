@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
+)
+
+// blacklistSubscriptionRetryDelay is the wait before subscribing again to the replicated blacklist after
+// the subscription failed
+const blacklistSubscriptionRetryDelay = 5 * time.Second
+
+// ReplicatedTokenRepository decorates a token repository so that the tokens blacklisted in a region are
+// blacklisted in the other regions as well, and a token revoked in one region is rejected everywhere.
+// The blacklisted tokens are published through the replicator once blacklisted locally; a failure to
+// publish is logged and counted but does not fail the revocation. The tokens of the other regions are
+// applied to the decorated repository by the background subscription, until their expiration.
+type ReplicatedTokenRepository struct {
+	ports.TokenRepository
+	replicator ports.BlacklistReplicator
+	region     string
+	logger     *zap.Logger
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewReplicatedTokenRepository creates a new instance of ReplicatedTokenRepository for the region
+func NewReplicatedTokenRepository(tokenRepo ports.TokenRepository, replicator ports.BlacklistReplicator, region string, logger *zap.Logger) *ReplicatedTokenRepository {
+	return &ReplicatedTokenRepository{
+		TokenRepository: tokenRepo,
+		replicator:      replicator,
+		region:          region,
+		logger:          logger,
+	}
+}
+
+// BlacklistToken blacklists the token and replicates it to the other regions
+func (r *ReplicatedTokenRepository) BlacklistToken(ctx context.Context, token string, ttl time.Duration) error {
+	if err := r.TokenRepository.BlacklistToken(ctx, token, ttl); err != nil {
+		return err
+	}
+	r.publish(ctx, token, ttl)
+	return nil
+}
+
+// RevokeSession revokes the session and replicates the blacklisted access token to the other regions
+func (r *ReplicatedTokenRepository) RevokeSession(ctx context.Context, accessToken string, ttl time.Duration, refreshToken string) error {
+	if err := r.TokenRepository.RevokeSession(ctx, accessToken, ttl, refreshToken); err != nil {
+		return err
+	}
+	if ttl > 0 {
+		r.publish(ctx, accessToken, ttl)
+	}
+	return nil
+}
+
+// Apply blacklists a token replicated from another region for the rest of its lifetime. The tokens of
+// this region and the expired ones are skipped.
+func (r *ReplicatedTokenRepository) Apply(ctx context.Context, token *domain.BlacklistedToken) error {
+	if token.Region == r.region {
+		return nil
+	}
+	ttl := time.Until(token.ExpiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+
+	if err := r.TokenRepository.BlacklistToken(ctx, token.Token, ttl); err != nil {
+		metrics.IncBlacklistReplications("applied", "failed")
+		r.logger.Error("failed to apply replicated blacklisted token", zap.Error(err), zap.String("source_region", token.Region))
+		return err
+	}
+
+	metrics.IncBlacklistReplications("applied", "success")
+	r.logger.Debug("replicated blacklisted token applied", zap.String("source_region", token.Region))
+	return nil
+}
+
+// Run subscribes to the tokens blacklisted in the other regions until the context is cancelled, subscribing
+// again after a failure
+func (r *ReplicatedTokenRepository) Run(ctx context.Context) {
+	for {
+		err := r.replicator.Subscribe(ctx, r.Apply)
+		if ctx.Err() != nil {
+			return
+		}
+		r.logger.Error("blacklist replication subscription failed", zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(blacklistSubscriptionRetryDelay):
+		}
+	}
+}
+
+// Start runs the subscription in the background, it implements Component
+func (r *ReplicatedTokenRepository) Start(ctx context.Context) error {
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	r.cancel = cancel
+	r.done = make(chan struct{})
+	go func() {
+		defer close(r.done)
+		r.Run(runCtx)
+	}()
+	return nil
+}
+
+// Stop stops the subscription and waits for the token being applied, until the context is done
+func (r *ReplicatedTokenRepository) Stop(ctx context.Context) error {
+	if r.cancel == nil {
+		return nil
+	}
+	r.cancel()
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// publish sends a token blacklisted in this region to the other regions
+func (r *ReplicatedTokenRepository) publish(ctx context.Context, token string, ttl time.Duration) {
+	blacklisted := &domain.BlacklistedToken{
+		Token:     token,
+		ExpiresAt: time.Now().Add(ttl),
+		Region:    r.region,
+	}
+	if err := r.replicator.Publish(ctx, blacklisted); err != nil {
+		metrics.IncBlacklistReplications("published", "failed")
+		r.logger.Error("failed to replicate blacklisted token", zap.Error(err), zap.String("region", r.region))
+		return
+	}
+	metrics.IncBlacklistReplications("published", "success")
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+)
+
+// detachedJWSHeader is the protected header of the detached signatures of HTTP responses
+type detachedJWSHeader struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+	Kid string `json:"kid,omitempty"`
+}
+
+// ResponseSigningService signs the bodies of HTTP responses with a detached JWS. It has its own key, so
+// the consumers verifying the responses can't mint tokens, and follows the algorithm and key ID of the
+// token signing policy, so the signatures rotate with the tokens.
+type ResponseSigningService struct {
+	secret  []byte
+	signing TokenSigningPolicy
+	logger  *zap.Logger
+}
+
+// NewResponseSigningService creates a new instance of ResponseSigningService signing with secret, which
+// must not be the secret of the tokens
+func NewResponseSigningService(secret string, signing TokenSigningPolicy, logger *zap.Logger) *ResponseSigningService {
+	return &ResponseSigningService{
+		secret:  []byte(secret),
+		signing: signing,
+		logger:  logger,
+	}
+}
+
+// SignDetached signs the payload and returns a JWS in compact serialization with a detached payload
+// (RFC 7515, appendix F): "<header>..<signature>". The verifier rebuilds the signing input from the
+// payload it received, so any change to the payload invalidates the signature.
+func (s *ResponseSigningService) SignDetached(payload []byte) (string, error) {
+	method := jwt.GetSigningMethod(s.signing.algorithm())
+	if method == nil {
+		method = jwt.SigningMethodHS256
+	}
+
+	header, err := json.Marshal(detachedJWSHeader{Alg: method.Alg(), Typ: "JOSE", Kid: s.signing.KeyID})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode JWS header: %w", err)
+	}
+
+	encodedHeader := base64.RawURLEncoding.EncodeToString(header)
+	signingInput := encodedHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	signature, err := method.Sign(signingInput, s.secret)
+	if err != nil {
+		s.logger.Error("failed to sign payload", zap.Error(err))
+		return "", fmt.Errorf("failed to sign payload: %w", err)
+	}
+
+	return encodedHeader + ".." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// VerifyDetached verifies a detached JWS produced by SignDetached against the payload. Like the tokens, the
+// algorithm must be accepted by the signing policy and the key ID match when rotation is enabled.
+func (s *ResponseSigningService) VerifyDetached(jws string, payload []byte) error {
+	encodedHeader, encodedSignature, ok := strings.Cut(jws, "..")
+	if !ok || encodedHeader == "" || encodedSignature == "" {
+		return domainerrors.ErrInvalidToken
+	}
+
+	var header detachedJWSHeader
+	rawHeader, err := base64.RawURLEncoding.DecodeString(encodedHeader)
+	if err != nil || json.Unmarshal(rawHeader, &header) != nil || !slices.Contains(s.signing.acceptedAlgorithms(), header.Alg) {
+		return domainerrors.ErrInvalidToken
+	}
+	method := jwt.GetSigningMethod(header.Alg)
+	if method == nil {
+		return domainerrors.ErrInvalidToken
+	}
+
+	key, err := s.signing.keyFunc(s.secret)(&jwt.Token{Method: method, Header: map[string]interface{}{"kid": header.Kid}})
+	if err != nil {
+		return domainerrors.ErrInvalidToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return domainerrors.ErrInvalidToken
+	}
+
+	signingInput := encodedHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	if err := method.Verify(signingInput, signature, key); err != nil {
+		return domainerrors.ErrInvalidToken
+	}
+	return nil
+}
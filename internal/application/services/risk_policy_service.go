@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
+)
+
+// RiskEngine decides whether a login, refresh or sudo elevation may proceed based on its risk
+type RiskEngine interface {
+	// AssessLogin evaluates a login whose credentials were already verified
+	AssessLogin(ctx context.Context, user *domain.User) *domain.RiskAssessment
+	// AssessRefresh evaluates the refresh of a session
+	AssessRefresh(ctx context.Context, user *domain.User, session *domain.RefreshTokenData) *domain.RiskAssessment
+	// AssessSudo evaluates a sudo elevation whose password was already verified
+	AssessSudo(ctx context.Context, user *domain.User) *domain.RiskAssessment
+	// RecordLoginFailure records a failed login for the email, used as a signal of later assessments.
+	// user is nil when no user has the email.
+	RecordLoginFailure(ctx context.Context, email string, user *domain.User)
+}
+
+// RiskPolicyService is the RiskEngine backed by a configurable RiskPolicy.
+// Signals are the IP reputation lists of the policy, whether the device is new for the user,
+// the failed logins within the failure window and, when configured, the geographic anomalies
+// detected by a LoginRiskEvaluator and the refresh anomalies detected by a RefreshAnomalyDetector.
+// Signal lookups fail open: a failing store never denies access.
+// Failed logins are also reported to the BruteForceMonitor and logins that are not denied to the
+// LoginNotifier, when configured.
+type RiskPolicyService struct {
+	policy         *domain.RiskPolicy
+	geo            LoginRiskEvaluator
+	refresh        RefreshAnomalyDetector
+	deviceRepo     ports.KnownDeviceRepository
+	failureCounter ports.RateLimiter
+	bruteForce     *BruteForceMonitor
+	loginNotifier  LoginNotifier
+	logger         *zap.Logger
+}
+
+// NewRiskPolicyService creates a new instance of RiskPolicyService. geo, refresh, bruteForce and
+// loginNotifier are optional.
+func NewRiskPolicyService(
+	policy *domain.RiskPolicy,
+	geo LoginRiskEvaluator,
+	refresh RefreshAnomalyDetector,
+	deviceRepo ports.KnownDeviceRepository,
+	failureCounter ports.RateLimiter,
+	bruteForce *BruteForceMonitor,
+	loginNotifier LoginNotifier,
+	logger *zap.Logger,
+) *RiskPolicyService {
+	return &RiskPolicyService{
+		policy:         policy,
+		geo:            geo,
+		refresh:        refresh,
+		deviceRepo:     deviceRepo,
+		failureCounter: failureCounter,
+		bruteForce:     bruteForce,
+		loginNotifier:  loginNotifier,
+		logger:         logger,
+	}
+}
+
+// AssessLogin evaluates the policy for a login
+func (s *RiskPolicyService) AssessLogin(ctx context.Context, user *domain.User) *domain.RiskAssessment {
+	signals := s.collectSignals(ctx, user, domain.RiskFlowLogin)
+
+	var geo *domain.LoginRisk
+	if s.geo != nil {
+		geo = s.geo.EvaluateLogin(ctx, user)
+		if geo != nil && geo.Risky {
+			signals.GeoReasons = geo.Reasons
+		}
+	}
+
+	assessment := s.decide(ctx, user, signals, geo)
+	if s.loginNotifier != nil && assessment.Decision != domain.RiskDecisionDeny {
+		s.loginNotifier.NotifyLogin(ctx, user, assessment)
+	}
+	return assessment
+}
+
+// AssessRefresh evaluates the policy for a refresh. Geographic anomalies are only detected at login,
+// so the ones of the session are carried over.
+func (s *RiskPolicyService) AssessRefresh(ctx context.Context, user *domain.User, session *domain.RefreshTokenData) *domain.RiskAssessment {
+	signals := s.collectSignals(ctx, user, domain.RiskFlowRefresh)
+
+	var geo *domain.LoginRisk
+	if session != nil && session.Risk != nil && session.Risk.Geo != nil {
+		geo = session.Risk.Geo
+		if geo.Risky {
+			signals.GeoReasons = geo.Reasons
+		}
+	}
+
+	if s.refresh != nil {
+		signals.RefreshReasons = s.refresh.EvaluateRefresh(ctx, user, session)
+	}
+
+	return s.decide(ctx, user, signals, geo)
+}
+
+// AssessSudo evaluates the policy for a sudo elevation. The failed logins and failed elevations share
+// their counter, so the rules locking out logins lock out elevations too.
+func (s *RiskPolicyService) AssessSudo(ctx context.Context, user *domain.User) *domain.RiskAssessment {
+	signals := s.collectSignals(ctx, user, domain.RiskFlowSudo)
+	return s.decide(ctx, user, signals, nil)
+}
+
+// RecordLoginFailure counts a failed login of the user and reports it to the brute force monitor (best effort)
+func (s *RiskPolicyService) RecordLoginFailure(ctx context.Context, email string, user *domain.User) {
+	reason := LoginFailureUnknownUser
+	if user != nil {
+		reason = LoginFailureInvalidPassword
+		if _, err := s.failureCounter.Hit(ctx, domain.LoginFailureRateLimitKey(user.ID)); err != nil {
+			s.logger.Error("failed to record login failure", zap.Error(err), zap.String("user_id", user.ID))
+		}
+	}
+
+	if s.bruteForce != nil {
+		s.bruteForce.RecordLoginFailure(ctx, email, reason)
+	}
+}
+
+// collectSignals gathers the signals shared by every flow
+func (s *RiskPolicyService) collectSignals(ctx context.Context, user *domain.User, flow string) domain.RiskSignals {
+	signals := domain.RiskSignals{Flow: flow}
+
+	if info, ok := domain.ClientInfoFromContext(ctx); ok {
+		signals.IP = info.IP
+		signals.IPReputation = s.policy.IPReputation(info.IP)
+		signals.DeviceID = domain.DeviceFingerprint(info.UserAgent)
+	}
+
+	if signals.DeviceID != "" {
+		known, err := s.deviceRepo.IsKnown(ctx, user.ID, signals.DeviceID)
+		if err != nil {
+			s.logger.Error("failed to check known device", zap.Error(err), zap.String("user_id", user.ID))
+		} else {
+			signals.NewDevice = !known
+		}
+	}
+
+	status, err := s.failureCounter.Peek(ctx, domain.LoginFailureRateLimitKey(user.ID))
+	if err != nil {
+		s.logger.Error("failed to get login failures", zap.Error(err), zap.String("user_id", user.ID))
+	} else {
+		signals.RecentFailures = status.Used
+	}
+
+	return signals
+}
+
+// decide evaluates the policy, remembers the device of allowed authentications and logs the decision
+func (s *RiskPolicyService) decide(ctx context.Context, user *domain.User, signals domain.RiskSignals, geo *domain.LoginRisk) *domain.RiskAssessment {
+	decision, rules := s.policy.Evaluate(signals)
+	assessment := &domain.RiskAssessment{
+		Decision:   decision,
+		Rules:      rules,
+		Signals:    signals,
+		Geo:        geo,
+		AssessedAt: time.Now(),
+	}
+
+	if decision != domain.RiskDecisionDeny && signals.NewDevice {
+		if err := s.deviceRepo.Remember(ctx, user.ID, signals.DeviceID); err != nil {
+			s.logger.Error("failed to remember device", zap.Error(err), zap.String("user_id", user.ID))
+		}
+	}
+
+	metrics.IncRiskDecision(signals.Flow, decision.String())
+	if (signals.Flow == domain.RiskFlowLogin || signals.Flow == domain.RiskFlowSudo) && s.policy.TriggeredByFailures(rules) {
+		switch decision {
+		case domain.RiskDecisionDeny:
+			assessment.LockedOut = true
+			metrics.IncLoginLockouts()
+		case domain.RiskDecisionStepUp:
+			metrics.IncLoginChallenges()
+		}
+	}
+
+	fields := []zap.Field{
+		zap.String("user_id", user.ID),
+		zap.String("flow", signals.Flow),
+		zap.String("decision", decision.String()),
+		zap.Strings("rules", rules),
+		zap.String("ip", signals.IP),
+		zap.Strings("ip_reputation", signals.IPReputation),
+		zap.Bool("new_device", signals.NewDevice),
+		zap.Int("recent_failures", signals.RecentFailures),
+		zap.Strings("geo_reasons", signals.GeoReasons),
+		zap.Strings("refresh_reasons", signals.RefreshReasons),
+	}
+	if decision == domain.RiskDecisionAllow {
+		s.logger.Info("risk decision", fields...)
+	} else {
+		s.logger.Warn("risk decision", fields...)
+	}
+
+	return assessment
+}
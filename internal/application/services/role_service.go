@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	"github.com/kristianrpo/auth-microservice/internal/domain/events"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// RoleServiceInterface defines the methods of RoleService used by handlers.
+type RoleServiceInterface interface {
+	ChangeUserRole(ctx context.Context, userID string, expectedVersion int, role domain.Role, actor string) (*domain.UserPublic, error)
+}
+
+// RoleService changes the role of users. A role change revokes every token of the user, so the
+// privileges of the previous role can't outlive it and the user signs in again with the new one.
+type RoleService struct {
+	userRepo            ports.UserRepository
+	tokenRepo           ports.TokenRepository
+	auditRepo           ports.AuditLogRepository
+	publisher           ports.MessagePublisher
+	roleChangedQueue    string
+	accessTokenDuration time.Duration
+	logger              *zap.Logger
+}
+
+// NewRoleService creates a new instance of RoleService.
+// accessTokenDuration is the lifetime of the access tokens, during which the revoked ones are rejected.
+func NewRoleService(
+	userRepo ports.UserRepository,
+	tokenRepo ports.TokenRepository,
+	auditRepo ports.AuditLogRepository,
+	publisher ports.MessagePublisher,
+	roleChangedQueue string,
+	accessTokenDuration time.Duration,
+	logger *zap.Logger,
+) *RoleService {
+	return &RoleService{
+		userRepo:            userRepo,
+		tokenRepo:           tokenRepo,
+		auditRepo:           auditRepo,
+		publisher:           publisher,
+		roleChangedQueue:    roleChangedQueue,
+		accessTokenDuration: accessTokenDuration,
+		logger:              logger,
+	}
+}
+
+// ChangeUserRole changes the role of a user, revokes their tokens, writes an audit record and publishes
+// a user.role_changed event. Nothing changes when the user already has the role. expectedVersion is the
+// version of the user the change was decided on, 0 for any. actor identifies who requested the change.
+func (s *RoleService) ChangeUserRole(ctx context.Context, userID string, expectedVersion int, role domain.Role, actor string) (*domain.UserPublic, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrUserNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to get user", zap.Error(err), zap.String("user_id", userID))
+		return nil, internalError(err)
+	}
+
+	if err := checkExpectedVersion(expectedVersion, user.Version); err != nil {
+		return nil, err
+	}
+	if user.IsAnonymized() {
+		return nil, domainerrors.ErrUserAlreadyAnonymized
+	}
+
+	if err := s.changeUserRole(ctx, user, role, actor); err != nil {
+		return nil, err
+	}
+	return user.ToPublic(), nil
+}
+
+// changeUserRole applies the role change to a user already loaded, it is shared with the consumer of the
+// user.role_changed events of the citizen registry
+func (s *RoleService) changeUserRole(ctx context.Context, user *domain.User, role domain.Role, actor string) error {
+	if user.Role == role {
+		return nil
+	}
+
+	previousRole := user.Role
+	user.Role = role
+	user.RevokeTokens()
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		if errors.Is(err, domainerrors.ErrUserNotFound) {
+			return err
+		}
+		s.logger.Error("failed to update user role", zap.Error(err), zap.String("user_id", user.ID))
+		return internalError(err)
+	}
+
+	// Best effort: the role change is already stored, and refreshes of the remaining sessions are rejected
+	// anyway because their token version is outdated
+	if err := s.tokenRepo.SetTokenVersion(ctx, user.IDCitizen, user.TokenVersion, s.accessTokenDuration); err != nil {
+		s.logger.Error("failed to revoke access tokens after role change", zap.Error(err), zap.String("user_id", user.ID))
+	}
+	if err := s.tokenRepo.DeleteUserTokens(ctx, user.ID, user.IDCitizen); err != nil {
+		s.logger.Error("failed to revoke tokens after role change", zap.Error(err), zap.String("user_id", user.ID))
+	}
+
+	record := domain.NewAuditRecord(domain.AuditActionUserRoleChanged, actor, user.ID, map[string]string{
+		"id_citizen":    strconv.Itoa(user.IDCitizen),
+		"previous_role": previousRole.String(),
+		"role":          user.Role.String(),
+	})
+	if err := s.auditRepo.Record(ctx, record); err != nil {
+		s.logger.Error("failed to write audit record", zap.Error(err), zap.String("user_id", user.ID), zap.String("actor", actor))
+	}
+
+	s.publishRoleChanged(ctx, user, previousRole)
+
+	s.logger.Info("user role changed",
+		zap.String("user_id", user.ID), zap.String("previous_role", previousRole.String()),
+		zap.String("role", user.Role.String()), zap.String("actor", actor))
+	return nil
+}
+
+// publishRoleChanged publishes the user.role_changed event (best effort)
+func (s *RoleService) publishRoleChanged(ctx context.Context, user *domain.User, previousRole domain.Role) {
+	event := events.NewUserRoleChangedEvent(user.ID, user.IDCitizen, previousRole, user.Role)
+	eventData, err := event.ToJSON()
+	if err != nil {
+		s.logger.Error("failed to serialize user role changed event", zap.Error(err))
+		return
+	}
+
+	if err := s.publisher.Publish(ctx, s.roleChangedQueue, eventData); err != nil {
+		s.logger.Error("failed to publish user role changed event", zap.Error(err), zap.String("user_id", user.ID))
+		return
+	}
+
+	s.logger.Info("user role changed event published", zap.String("message_id", event.MessageID), zap.String("queue", s.roleChangedQueue))
+}
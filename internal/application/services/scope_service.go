@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+)
+
+// ScopeServiceInterface defines the methods of ScopeService used by handlers.
+type ScopeServiceInterface interface {
+	ListScopes(ctx context.Context) ([]*domain.Scope, error)
+	CreateScope(ctx context.Context, name, description string) (*domain.Scope, error)
+	UpdateScope(ctx context.Context, name, description string) (*domain.Scope, error)
+	DeleteScope(ctx context.Context, name string) error
+}
+
+// ScopeService manages the registry of OAuth2 scopes that can be assigned to clients
+type ScopeService struct {
+	scopeRepo ports.ScopeRepository
+	logger    *zap.Logger
+}
+
+// NewScopeService creates a new instance of ScopeService
+func NewScopeService(scopeRepo ports.ScopeRepository, logger *zap.Logger) *ScopeService {
+	return &ScopeService{
+		scopeRepo: scopeRepo,
+		logger:    logger,
+	}
+}
+
+// ListScopes retrieves all registered scopes
+func (s *ScopeService) ListScopes(ctx context.Context) ([]*domain.Scope, error) {
+	scopes, err := s.scopeRepo.List(ctx)
+	if err != nil {
+		s.logger.Error("failed to list scopes", zap.Error(err))
+		return nil, internalError(err)
+	}
+	return scopes, nil
+}
+
+// CreateScope registers a new scope
+func (s *ScopeService) CreateScope(ctx context.Context, name, description string) (*domain.Scope, error) {
+	scope, err := domain.NewScope(name, description)
+	if err != nil {
+		return nil, domainerrors.ErrInvalidScopeName
+	}
+
+	if err := s.scopeRepo.Create(ctx, scope); err != nil {
+		if errors.Is(err, domainerrors.ErrScopeAlreadyExists) {
+			return nil, err
+		}
+		s.logger.Error("failed to create scope", zap.Error(err), logging.String("scope", name))
+		return nil, internalError(err)
+	}
+
+	s.logger.Info("scope created", logging.String("scope", name))
+	return scope, nil
+}
+
+// UpdateScope updates the description of a non-system scope
+func (s *ScopeService) UpdateScope(ctx context.Context, name, description string) (*domain.Scope, error) {
+	scope, err := s.getScope(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if scope.System {
+		return nil, domainerrors.ErrSystemScope
+	}
+
+	scope.Description = description
+	if err := s.scopeRepo.Update(ctx, scope); err != nil {
+		if errors.Is(err, domainerrors.ErrScopeNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to update scope", zap.Error(err), logging.String("scope", name))
+		return nil, internalError(err)
+	}
+
+	s.logger.Info("scope updated", logging.String("scope", name))
+	return scope, nil
+}
+
+// DeleteScope removes a non-system scope that is not assigned to any client
+func (s *ScopeService) DeleteScope(ctx context.Context, name string) error {
+	scope, err := s.getScope(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if scope.System {
+		return domainerrors.ErrSystemScope
+	}
+
+	if err := s.scopeRepo.Delete(ctx, name); err != nil {
+		if errors.Is(err, domainerrors.ErrScopeInUse) {
+			s.logger.Warn("attempted to delete scope in use", logging.String("scope", name))
+			return err
+		}
+		if errors.Is(err, domainerrors.ErrScopeNotFound) {
+			return err
+		}
+		s.logger.Error("failed to delete scope", zap.Error(err), logging.String("scope", name))
+		return internalError(err)
+	}
+
+	s.logger.Info("scope deleted", logging.String("scope", name))
+	return nil
+}
+
+// getScope loads a scope mapping repository failures to domain errors
+func (s *ScopeService) getScope(ctx context.Context, name string) (*domain.Scope, error) {
+	scope, err := s.scopeRepo.GetByName(ctx, name)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrScopeNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to get scope", zap.Error(err), logging.String("scope", name))
+		return nil, internalError(err)
+	}
+	return scope, nil
+}
+
+// validateRegisteredScopes checks that every requested scope exists in the registry
+func validateRegisteredScopes(ctx context.Context, scopeRepo ports.ScopeRepository, scopes []string, logger *zap.Logger) error {
+	if len(scopes) == 0 {
+		return nil
+	}
+
+	registered, err := scopeRepo.GetByNames(ctx, scopes)
+	if err != nil {
+		logger.Error("failed to load registered scopes", zap.Error(err))
+		return internalError(err)
+	}
+
+	for _, scope := range scopes {
+		if !slices.ContainsFunc(registered, func(r *domain.Scope) bool { return r.Name == scope }) {
+			logger.Warn("attempted to assign unregistered scope", logging.String("scope", scope))
+			return domainerrors.ErrUnknownScope
+		}
+	}
+
+	return nil
+}
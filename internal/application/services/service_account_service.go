@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// ServiceAccountServiceInterface defines the methods of ServiceAccountService used by handlers.
+type ServiceAccountServiceInterface interface {
+	CreateServiceAccount(ctx context.Context, name string, idCitizen int, actor string) (*domain.User, error)
+}
+
+// ServiceAccountService manages the service accounts, the identities of automation. They are kept apart
+// from the accounts of people: only administrators create them and they can't sign in with a password.
+type ServiceAccountService struct {
+	userRepo  ports.UserRepository
+	auditRepo ports.AuditLogRepository
+	logger    *zap.Logger
+}
+
+// NewServiceAccountService creates a new instance of ServiceAccountService
+func NewServiceAccountService(userRepo ports.UserRepository, auditRepo ports.AuditLogRepository, logger *zap.Logger) *ServiceAccountService {
+	return &ServiceAccountService{
+		userRepo:  userRepo,
+		auditRepo: auditRepo,
+		logger:    logger,
+	}
+}
+
+// CreateServiceAccount creates a service account and writes an audit record. actor identifies the
+// administrator who requested it.
+func (s *ServiceAccountService) CreateServiceAccount(ctx context.Context, name string, idCitizen int, actor string) (*domain.User, error) {
+	user, err := domain.NewServiceAccount(name, idCitizen)
+	if err != nil {
+		return nil, domainerrors.ErrBadRequest
+	}
+
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		if errors.Is(err, domainerrors.ErrUserAlreadyExists) {
+			return nil, err
+		}
+		s.logger.Error("failed to save service account", zap.Error(err), zap.Int("id_citizen", idCitizen))
+		return nil, internalError(err)
+	}
+
+	record := domain.NewAuditRecord(domain.AuditActionServiceAccountCreated, actor, user.ID, map[string]string{
+		"id_citizen": strconv.Itoa(user.IDCitizen),
+		"name":       user.Name,
+	})
+	if err := s.auditRepo.Record(ctx, record); err != nil {
+		s.logger.Error("failed to write audit record", zap.Error(err), zap.String("user_id", user.ID), zap.String("actor", actor))
+	}
+
+	s.logger.Info("service account created",
+		zap.String("user_id", user.ID), zap.Int("id_citizen", user.IDCitizen), zap.String("actor", actor))
+	return user, nil
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+)
+
+// ShutdownStep is the outcome of stopping a subsystem on shutdown
+type ShutdownStep struct {
+	Name      string
+	Duration  time.Duration
+	Drained   int // in-flight work completed while stopping
+	Cancelled int // in-flight work abandoned because the deadline was exceeded
+	Err       error
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler to log the step as a structured object
+func (s ShutdownStep) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("name", s.Name)
+	enc.AddDuration("duration", s.Duration)
+	enc.AddInt("drained", s.Drained)
+	enc.AddInt("cancelled", s.Cancelled)
+	if s.Err != nil {
+		enc.AddString("error", s.Err.Error())
+	}
+	return nil
+}
+
+// StopComponent stops the component and reports how long it took, and the in-flight work it
+// drained and cancelled when it implements ports.DrainReporter
+func StopComponent(ctx context.Context, name string, component Component) ShutdownStep {
+	start := time.Now()
+	err := component.Stop(ctx)
+	step := ShutdownStep{Name: name, Duration: time.Since(start), Err: err}
+
+	if reporter, ok := component.(ports.DrainReporter); ok {
+		stats := reporter.DrainStats()
+		step.Drained = stats.Drained
+		step.Cancelled = stats.Cancelled
+	}
+	return step
+}
+
+// ShutdownReport collects the outcome of the subsystems stopped on shutdown, to log a single summary
+type ShutdownReport struct {
+	startedAt time.Time
+	steps     []ShutdownStep
+}
+
+// NewShutdownReport creates a report whose duration starts now
+func NewShutdownReport() *ShutdownReport {
+	return &ShutdownReport{startedAt: time.Now()}
+}
+
+// Add records the outcome of stopped subsystems
+func (r *ShutdownReport) Add(steps ...ShutdownStep) {
+	r.steps = append(r.steps, steps...)
+}
+
+// Steps returns the recorded steps in the order they were stopped
+func (r *ShutdownReport) Steps() []ShutdownStep {
+	return r.steps
+}
+
+// Drained returns the in-flight work completed by every subsystem
+func (r *ShutdownReport) Drained() int {
+	drained := 0
+	for _, step := range r.steps {
+		drained += step.Drained
+	}
+	return drained
+}
+
+// Cancelled returns the in-flight work abandoned by every subsystem
+func (r *ShutdownReport) Cancelled() int {
+	cancelled := 0
+	for _, step := range r.steps {
+		cancelled += step.Cancelled
+	}
+	return cancelled
+}
+
+// Failed returns the number of subsystems that did not stop cleanly
+func (r *ShutdownReport) Failed() int {
+	failed := 0
+	for _, step := range r.steps {
+		if step.Err != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Log writes the summary of the shutdown, as a warning when work was cancelled or a subsystem failed to stop
+func (r *ShutdownReport) Log(logger *zap.Logger) {
+	level := zapcore.InfoLevel
+	if r.Cancelled() > 0 || r.Failed() > 0 {
+		level = zapcore.WarnLevel
+	}
+
+	logger.Log(level, "shutdown summary",
+		zap.Duration("duration", time.Since(r.startedAt)),
+		zap.Int("drained", r.Drained()),
+		zap.Int("cancelled", r.Cancelled()),
+		zap.Int("failed", r.Failed()),
+		zap.Array("subsystems", zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+			for _, step := range r.steps {
+				if err := enc.AppendObject(step); err != nil {
+					return err
+				}
+			}
+			return nil
+		})),
+	)
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// SudoServiceInterface defines the methods of SudoService used by handlers.
+type SudoServiceInterface interface {
+	Elevate(ctx context.Context, idCitizen int, password string) (*domain.SudoGrant, error)
+}
+
+// SudoService grants elevated access ("sudo mode") to users who re-enter their password, so a stolen
+// session alone can't be used for destructive operations
+type SudoService struct {
+	userRepo       ports.UserRepository
+	passwordHasher ports.PasswordHasher
+	jwtService     *JWTService
+	auditRepo      ports.AuditLogRepository
+	riskEngine     RiskEngine
+	duration       time.Duration
+	logger         *zap.Logger
+}
+
+// NewSudoService creates a new instance of SudoService.
+// Failed elevations are counted by riskEngine like failed logins and elevations are denied by the same
+// risk policy as logins, so the password can't be guessed through sudo.
+// The elevated tokens expire after duration.
+func NewSudoService(
+	userRepo ports.UserRepository,
+	passwordHasher ports.PasswordHasher,
+	jwtService *JWTService,
+	auditRepo ports.AuditLogRepository,
+	riskEngine RiskEngine,
+	duration time.Duration,
+	logger *zap.Logger,
+) *SudoService {
+	return &SudoService{
+		userRepo:       userRepo,
+		passwordHasher: passwordHasher,
+		jwtService:     jwtService,
+		auditRepo:      auditRepo,
+		riskEngine:     riskEngine,
+		duration:       duration,
+		logger:         logger,
+	}
+}
+
+// Elevate verifies the password of the authenticated user and returns a short-lived elevated access token
+func (s *SudoService) Elevate(ctx context.Context, idCitizen int, password string) (*domain.SudoGrant, error) {
+	user, err := s.userRepo.GetByIDCitizen(ctx, idCitizen)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrUserNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to get user", zap.Error(err), zap.Int("id_citizen", idCitizen))
+		return nil, internalError(err)
+	}
+
+	if !user.IsActive() {
+		s.logger.Warn("sudo denied: user is not active", zap.String("user_id", user.ID), zap.String("status", user.Status.String()))
+		return nil, domainerrors.ErrUserSuspended
+	}
+
+	match, err := s.passwordHasher.Compare(ctx, user.Password, password)
+	if err != nil {
+		s.logger.Error("failed to compare password", zap.Error(err))
+		return nil, internalError(err)
+	}
+	if !match {
+		s.logger.Warn("sudo denied: invalid password", zap.String("user_id", user.ID))
+		s.riskEngine.RecordLoginFailure(ctx, user.Email, user)
+		return nil, domainerrors.ErrInvalidCredentials
+	}
+
+	// Users locked out by failed logins or elevations, or otherwise risky, are denied as at login. Locked
+	// out users get the answer of a wrong password, so guesses during the lockout can't tell they were right.
+	risk := s.riskEngine.AssessSudo(ctx, user)
+	if risk.LockedOut {
+		s.logger.Warn("sudo denied: locked out by failed logins", zap.String("user_id", user.ID), zap.Strings("rules", risk.Rules))
+		return nil, domainerrors.ErrInvalidCredentials
+	}
+	if risk.Decision == domain.RiskDecisionDeny {
+		s.logger.Warn("sudo denied: denied by risk policy", zap.String("user_id", user.ID), zap.Strings("rules", risk.Rules))
+		return nil, domainerrors.ErrAuthenticationDenied
+	}
+
+	grant, err := s.jwtService.GenerateSudoToken(user, s.duration)
+	if err != nil {
+		return nil, internalError(err)
+	}
+
+	actor := user.AuditActor()
+	record := domain.NewAuditRecord(domain.AuditActionSudoGranted, actor, user.ID, map[string]string{
+		"sudo_until": grant.SudoUntil.UTC().Format(time.RFC3339),
+	})
+	if err := s.auditRepo.Record(ctx, record); err != nil {
+		s.logger.Error("failed to write audit record", zap.Error(err), zap.String("user_id", user.ID), zap.String("actor", actor))
+	}
+
+	s.logger.Info("sudo mode granted", zap.String("user_id", user.ID), zap.Time("sudo_until", grant.SudoUntil))
+	return grant, nil
+}
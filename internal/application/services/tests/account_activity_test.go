@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestAuthService_LoginRecordsActivity(t *testing.T) {
+	var recorded string
+	userRepo := &MockUserRepository{
+		GetByEmailFunc: func(ctx context.Context, email string) (*domain.User, error) {
+			return newTestUser(), nil
+		},
+		RecordLoginFunc: func(ctx context.Context, id string, at time.Time) error {
+			recorded = id
+			return errors.New("connection reset")
+		},
+	}
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, zap.NewNop())
+	authService := services.NewAuthService(
+		userRepo,
+		&MockTokenRepository{},
+		jwtService,
+		&MockPasswordHasher{
+			CompareFunc: func(ctx context.Context, hash, password string) (bool, error) {
+				return true, nil
+			},
+		},
+		zap.NewNop(),
+	)
+
+	// A login that can't be recorded still succeeds
+	if _, err := authService.Login(context.Background(), "test@example.com", "password123"); err != nil {
+		t.Fatalf("Login() unexpected error: %v", err)
+	}
+	if recorded != newTestUser().ID {
+		t.Errorf("recorded login of %q, want %q", recorded, newTestUser().ID)
+	}
+}
+
+func TestAuthService_GetAccountActivity(t *testing.T) {
+	lastLogin := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	passwordChanged := lastLogin.AddDate(0, -1, 0)
+
+	tests := []struct {
+		name        string
+		getErr      error
+		sessionsErr error
+		wantErr     error
+	}{
+		{name: "activity"},
+		{name: "user not found", getErr: domainerrors.ErrUserNotFound, wantErr: domainerrors.ErrUserNotFound},
+		{name: "sessions unavailable", sessionsErr: errors.New("connection refused"), wantErr: domainerrors.ErrInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := &MockUserRepository{
+				GetByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+					if tt.getErr != nil {
+						return nil, tt.getErr
+					}
+					user := newTestUser()
+					user.LastLoginAt = &lastLogin
+					user.PasswordChangedAt = &passwordChanged
+					return user, nil
+				},
+			}
+			tokenRepo := &MockTokenRepository{
+				CountActiveSessionsFunc: func(ctx context.Context, idCitizen int) (int, error) {
+					return 3, tt.sessionsErr
+				},
+			}
+			authService := services.NewAuthService(userRepo, tokenRepo, nil, &MockPasswordHasher{}, zap.NewNop())
+
+			activity, err := authService.GetAccountActivity(context.Background(), 12345)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetAccountActivity() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if !activity.LastLoginAt.Equal(lastLogin) || !activity.PasswordChangedAt.Equal(passwordChanged) ||
+				activity.ActiveSessionsCount != 3 || activity.MFAEnabled {
+				t.Errorf("GetAccountActivity() = %+v, want the times of the user and 3 sessions", activity)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	"github.com/kristianrpo/auth-microservice/internal/domain/events"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestAnonymizationService_AnonymizeUser(t *testing.T) {
+	tests := []struct {
+		name            string
+		getErr          error
+		status          domain.UserStatus
+		phoneDeleteErr  error
+		avatarDeleteErr error
+		updateErr       error
+		wantErr         error
+		wantUpdated     bool
+	}{
+		{name: "anonymizes user", wantUpdated: true},
+		{name: "user without phone number", phoneDeleteErr: domainerrors.ErrPhoneNotFound, wantUpdated: true},
+		{name: "user not found", getErr: domainerrors.ErrUserNotFound, wantErr: domainerrors.ErrUserNotFound},
+		{name: "already anonymized", status: domain.UserStatusAnonymized, wantErr: domainerrors.ErrUserAlreadyAnonymized},
+		{name: "phone deletion fails", phoneDeleteErr: errors.New("db down"), wantErr: domainerrors.ErrInternal},
+		{name: "avatar deletion fails", avatarDeleteErr: errors.New("db down"), wantErr: domainerrors.ErrInternal},
+		{name: "update fails", updateErr: errors.New("db down"), wantErr: domainerrors.ErrInternal, wantUpdated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updated *domain.User
+			userRepo := &MockUserRepository{
+				GetByIDFunc: func(ctx context.Context, id string) (*domain.User, error) {
+					if tt.getErr != nil {
+						return nil, tt.getErr
+					}
+					user := newTestUser()
+					if tt.status != "" {
+						user.Status = tt.status
+					}
+					return user, nil
+				},
+				UpdateFunc: func(ctx context.Context, user *domain.User) error {
+					updated = user
+					return tt.updateErr
+				},
+			}
+			revoked := 0
+			tokenRepo := &MockTokenRepository{
+				DeleteUserTokensFunc: func(ctx context.Context, userID string, idCitizen int) error {
+					revoked = idCitizen
+					return nil
+				},
+			}
+			phoneRepo := &MockPhoneNumberRepository{
+				DeleteFunc: func(ctx context.Context, userID string) error {
+					return tt.phoneDeleteErr
+				},
+			}
+			avatarRepo := &MockAvatarRepository{
+				DeleteByUserIDFunc: func(ctx context.Context, userID string) error {
+					return tt.avatarDeleteErr
+				},
+			}
+			var audit *domain.AuditRecord
+			auditRepo := &MockAuditLogRepository{
+				RecordFunc: func(ctx context.Context, record *domain.AuditRecord) error {
+					audit = record
+					return nil
+				},
+			}
+			var published []byte
+			publisher := &MockMessagePublisher{
+				PublishFunc: func(ctx context.Context, queueName string, message []byte) error {
+					if queueName != "test.user.anonymized" {
+						t.Errorf("Publish() queue = %v, want test.user.anonymized", queueName)
+					}
+					published = message
+					return nil
+				},
+			}
+
+			service := services.NewAnonymizationService(userRepo, tokenRepo, phoneRepo, &MockUserEmailRepository{}, avatarRepo, auditRepo, publisher, "test.user.anonymized", zap.NewNop())
+			user, err := service.AnonymizeUser(context.Background(), "user-123", "authctl:root")
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AnonymizeUser() error = %v, want %v", err, tt.wantErr)
+			}
+			if (updated != nil) != tt.wantUpdated {
+				t.Errorf("user updated = %v, want %v", updated != nil, tt.wantUpdated)
+			}
+			if tt.wantErr != nil {
+				if revoked != 0 || audit != nil || published != nil {
+					t.Errorf("side effects after failure: revoked = %v, audit = %+v, published = %s", revoked, audit, published)
+				}
+				return
+			}
+
+			if !updated.IsAnonymized() || updated.Email == "test@example.com" || updated.Name != domain.AnonymizedName {
+				t.Errorf("updated user = %+v, want scrubbed user", updated)
+			}
+			if user.Email != updated.Email {
+				t.Errorf("AnonymizeUser() email = %v, want %v", user.Email, updated.Email)
+			}
+			if revoked != 12345 {
+				t.Errorf("DeleteUserTokens() idCitizen = %v, want 12345", revoked)
+			}
+			if audit == nil || audit.Action != domain.AuditActionUserAnonymized || audit.Actor != "authctl:root" || audit.TargetID != "user-123" {
+				t.Errorf("audit record = %+v", audit)
+			}
+
+			var event events.UserAnonymizedEvent
+			if err := json.Unmarshal(published, &event); err != nil {
+				t.Fatalf("failed to decode event: %v", err)
+			}
+			if event.UserID != "user-123" || event.IDCitizen != 12345 || event.MessageID == "" {
+				t.Errorf("event = %+v", event)
+			}
+		})
+	}
+}
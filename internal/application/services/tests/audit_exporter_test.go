@@ -0,0 +1,200 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func newAuditBatch(prefix string, n int) []*domain.AuditRecord {
+	records := make([]*domain.AuditRecord, 0, n)
+	for i := 0; i < n; i++ {
+		record := domain.NewAuditRecord(domain.AuditActionUserUpdated, "admin:1", "user-123", nil)
+		record.ID = fmt.Sprintf("%s-%d", prefix, i)
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestAuditExporter_ExportPending(t *testing.T) {
+	policy := services.AuditExportPolicy{
+		PollInterval:   time.Second,
+		BatchSize:      2,
+		InitialBackoff: time.Minute,
+		MaxBackoff:     10 * time.Minute,
+	}
+
+	tests := []struct {
+		name          string
+		batches       [][]*domain.AuditRecord
+		sendErr       error
+		wantExported  int
+		wantErr       bool
+		wantMarked    int
+		wantFailed    int
+		wantSendCalls int
+	}{
+		{
+			name:          "drains every batch",
+			batches:       [][]*domain.AuditRecord{newAuditBatch("a", 2), newAuditBatch("b", 1)},
+			wantExported:  3,
+			wantMarked:    3,
+			wantSendCalls: 2,
+		},
+		{
+			name:          "nothing to export",
+			wantSendCalls: 0,
+		},
+		{
+			name:          "sink failure reschedules the batch",
+			batches:       [][]*domain.AuditRecord{newAuditBatch("a", 2), newAuditBatch("b", 1)},
+			sendErr:       errors.New("collector unavailable"),
+			wantErr:       true,
+			wantFailed:    2,
+			wantSendCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batches := tt.batches
+			var marked, failed []string
+			var nextAttemptAt time.Time
+			repo := &MockAuditExportRepository{
+				ClaimUnexportedFunc: func(ctx context.Context, limit int, lease time.Duration) ([]*domain.AuditRecord, error) {
+					if limit != policy.BatchSize {
+						t.Errorf("ClaimUnexported() limit = %d, want %d", limit, policy.BatchSize)
+					}
+					if len(batches) == 0 {
+						return nil, nil
+					}
+					batch := batches[0]
+					batches = batches[1:]
+					return batch, nil
+				},
+				MarkExportedFunc: func(ctx context.Context, ids []string) error {
+					marked = append(marked, ids...)
+					return nil
+				},
+				MarkExportFailedFunc: func(ctx context.Context, ids []string, at time.Time) error {
+					failed = append(failed, ids...)
+					nextAttemptAt = at
+					return nil
+				},
+			}
+			sendCalls := 0
+			sink := &MockAuditSink{
+				SendFunc: func(ctx context.Context, records []*domain.AuditRecord) error {
+					sendCalls++
+					return tt.sendErr
+				},
+			}
+
+			exporter := services.NewAuditExporter(sink, repo, policy, zap.NewNop())
+			start := time.Now()
+			exported, err := exporter.ExportPending(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExportPending() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if exported != tt.wantExported {
+				t.Errorf("exported = %d, want %d", exported, tt.wantExported)
+			}
+			if sendCalls != tt.wantSendCalls {
+				t.Errorf("Send() calls = %d, want %d", sendCalls, tt.wantSendCalls)
+			}
+			if len(marked) != tt.wantMarked {
+				t.Errorf("marked exported = %v, want %d records", marked, tt.wantMarked)
+			}
+			if len(failed) != tt.wantFailed {
+				t.Errorf("marked failed = %v, want %d records", failed, tt.wantFailed)
+			}
+			if tt.wantFailed > 0 {
+				if got := nextAttemptAt.Sub(start); got < policy.InitialBackoff || got > policy.InitialBackoff+time.Minute {
+					t.Errorf("next attempt in %v, want %v", got, policy.InitialBackoff)
+				}
+			}
+		})
+	}
+}
+
+func TestAuditExporter_ExportPending_BackoffGrows(t *testing.T) {
+	policy := services.AuditExportPolicy{BatchSize: 10, InitialBackoff: time.Minute, MaxBackoff: 3 * time.Minute}
+
+	var delays []time.Duration
+	repo := &MockAuditExportRepository{
+		ClaimUnexportedFunc: func(ctx context.Context, limit int, lease time.Duration) ([]*domain.AuditRecord, error) {
+			return newAuditBatch("a", 1), nil
+		},
+		MarkExportFailedFunc: func(ctx context.Context, ids []string, nextAttemptAt time.Time) error {
+			delays = append(delays, time.Until(nextAttemptAt).Round(time.Minute))
+			return nil
+		},
+	}
+	sink := &MockAuditSink{
+		SendFunc: func(ctx context.Context, records []*domain.AuditRecord) error {
+			return errors.New("connection refused")
+		},
+	}
+
+	exporter := services.NewAuditExporter(sink, repo, policy, zap.NewNop())
+	for i := 0; i < 4; i++ {
+		if _, err := exporter.ExportPending(context.Background()); err == nil {
+			t.Fatal("ExportPending() error = nil, want the sink error")
+		}
+	}
+
+	// The backoff doubles with every consecutive failure, up to the maximum
+	want := []time.Duration{time.Minute, 2 * time.Minute, 3 * time.Minute, 3 * time.Minute}
+	for i := range want {
+		if delays[i] != want[i] {
+			t.Errorf("delays = %v, want %v", delays, want)
+			break
+		}
+	}
+}
+
+func TestExportingAuditLog_Record(t *testing.T) {
+	exported := make(chan struct{}, 1)
+	repo := &MockAuditExportRepository{
+		ClaimUnexportedFunc: func(ctx context.Context, limit int, lease time.Duration) ([]*domain.AuditRecord, error) {
+			return nil, nil
+		},
+		CountUnexportedFunc: func(ctx context.Context) (int, error) {
+			select {
+			case exported <- struct{}{}:
+			default:
+			}
+			return 0, nil
+		},
+	}
+	exporter := services.NewAuditExporter(&MockAuditSink{}, repo, services.AuditExportPolicy{
+		PollInterval:   time.Hour,
+		BatchSize:      10,
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+	}, zap.NewNop())
+	if err := exporter.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer func() { _ = exporter.Stop(context.Background()) }()
+
+	auditLog := services.NewExportingAuditLog(&MockAuditLogRepository{}, exporter)
+	if err := auditLog.Record(context.Background(), domain.NewAuditRecord(domain.AuditActionUserUpdated, "admin:1", "user-123", nil)); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	// The exporter runs right away instead of waiting for the poll interval
+	select {
+	case <-exported:
+	case <-time.After(time.Second):
+		t.Error("exporter not notified of the new record")
+	}
+}
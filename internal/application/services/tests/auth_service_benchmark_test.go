@@ -0,0 +1,47 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/hashing"
+)
+
+// BenchmarkAuthService_Login measures the in-process cost of a successful login: user lookup
+// (mocked), bcrypt comparison at the production default cost, token pair generation and the
+// refresh token store (mocked). Compare it with BenchmarkLogin_Stack in loadtest/ to see how
+// much of the latency comes from Postgres, Redis and the network.
+func BenchmarkAuthService_Login(b *testing.B) {
+	hasher := hashing.NewBcryptHasher(bcrypt.DefaultCost)
+	ctx := context.Background()
+
+	user := newTestUser()
+	hash, err := hasher.Hash(ctx, "password123")
+	if err != nil {
+		b.Fatalf("Hash() unexpected error: %v", err)
+	}
+	user.Password = hash
+
+	userRepo := &MockUserRepository{
+		GetByEmailFunc: func(ctx context.Context, email string) (*domain.User, error) {
+			return user, nil
+		},
+	}
+	authService := services.NewAuthService(userRepo, &MockTokenRepository{}, newBenchJWTService(), hasher, zap.NewNop())
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := authService.Login(ctx, user.Email, "password123"); err != nil {
+				b.Errorf("Login() unexpected error: %v", err)
+				return
+			}
+		}
+	})
+}
@@ -2,7 +2,9 @@ package tests
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -10,6 +12,7 @@ import (
 
 	"github.com/kristianrpo/auth-microservice/internal/application/services"
 	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	"github.com/kristianrpo/auth-microservice/internal/domain/events"
 	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
 )
 
@@ -24,6 +27,7 @@ func TestAuthService_Register(t *testing.T) {
 		idCitizen   int
 		existsFunc  func(ctx context.Context, email string) (bool, error)
 		createFunc  func(ctx context.Context, user *domain.User) error
+		checkErr    error
 		wantErr     bool
 		expectedErr error
 	}{
@@ -54,6 +58,18 @@ func TestAuthService_Register(t *testing.T) {
 			wantErr:     true,
 			expectedErr: domainerrors.ErrUserAlreadyExists,
 		},
+		{
+			name:      "name too long",
+			email:     "test@example.com",
+			password:  "password123",
+			userName:  strings.Repeat("a", domain.DefaultNameMaxLength+1),
+			idCitizen: 12345,
+			existsFunc: func(ctx context.Context, email string) (bool, error) {
+				return false, nil
+			},
+			wantErr:     true,
+			expectedErr: domainerrors.ErrInvalidName,
+		},
 		{
 			name:      "repository error on exists check",
 			email:     "test@example.com",
@@ -81,6 +97,26 @@ func TestAuthService_Register(t *testing.T) {
 			wantErr:     true,
 			expectedErr: domainerrors.ErrInternal,
 		},
+		{
+			name:        "centralizer busy",
+			email:       "test@example.com",
+			password:    "password123",
+			userName:    "Test User",
+			idCitizen:   12345,
+			checkErr:    domainerrors.ErrCentralizerBusy,
+			wantErr:     true,
+			expectedErr: domainerrors.ErrCentralizerBusy,
+		},
+		{
+			name:        "centralizer error",
+			email:       "test@example.com",
+			password:    "password123",
+			userName:    "Test User",
+			idCitizen:   12345,
+			checkErr:    errors.New("connection refused"),
+			wantErr:     true,
+			expectedErr: domainerrors.ErrInternal,
+		},
 	}
 
 	for _, tt := range tests {
@@ -91,9 +127,13 @@ func TestAuthService_Register(t *testing.T) {
 			}
 			mockTokenRepo := &MockTokenRepository{}
 			mockPublisher := &MockMessagePublisher{}
-			mockExternalClient := &MockExternalConnectivityClient{}
-			jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, logger)
-			authService := services.NewAuthService(mockUserRepo, mockTokenRepo, jwtService, mockPublisher, mockExternalClient, "test.user.registered", logger)
+			mockExternalClient := &MockExternalConnectivityClient{
+				CheckCitizenExistsFunc: func(ctx context.Context, idCitizen int) (bool, error) {
+					return false, tt.checkErr
+				},
+			}
+			tokenIssuer := &MockTokenIssuer{}
+			authService := services.NewAuthService(mockUserRepo, mockTokenRepo, tokenIssuer, &MockPasswordHasher{}, logger, services.WithPublisher(mockPublisher, "test.user.registered"), services.WithConnectivityClient(mockExternalClient))
 
 			user, err := authService.Register(context.Background(), tt.email, tt.password, tt.userName, tt.idCitizen)
 
@@ -102,7 +142,7 @@ func TestAuthService_Register(t *testing.T) {
 					t.Errorf("Register() expected error but got none")
 					return
 				}
-				if tt.expectedErr != nil && err != tt.expectedErr {
+				if tt.expectedErr != nil && !errors.Is(err, tt.expectedErr) {
 					t.Errorf("Register() error = %v, want %v", err, tt.expectedErr)
 				}
 				return
@@ -178,8 +218,8 @@ func TestAuthService_Login(t *testing.T) {
 			}
 			mockTokenRepo := &MockTokenRepository{}
 			mockPublisher := &MockMessagePublisher{}
-			jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, logger)
-			authService := services.NewAuthService(mockUserRepo, mockTokenRepo, jwtService, mockPublisher, &MockExternalConnectivityClient{}, "test.user.registered", logger)
+			tokenIssuer := &MockTokenIssuer{}
+			authService := services.NewAuthService(mockUserRepo, mockTokenRepo, tokenIssuer, &MockPasswordHasher{}, logger, services.WithPublisher(mockPublisher, "test.user.registered"))
 
 			tokenPair, err := authService.Login(context.Background(), tt.email, tt.password)
 
@@ -217,7 +257,7 @@ func TestAuthService_Login(t *testing.T) {
 
 func TestAuthService_RefreshToken(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
-	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, logger)
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, logger)
 
 	// Generate a valid refresh token
 	validRefreshToken, _ := jwtService.GenerateRefreshToken(12345, "test@example.com", domain.RoleUser)
@@ -274,13 +314,17 @@ func TestAuthService_RefreshToken(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mockUserRepo := &MockUserRepository{}
+			mockUserRepo := &MockUserRepository{
+				GetByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+					return newTestUser(), nil
+				},
+			}
 			mockTokenRepo := &MockTokenRepository{
 				IsTokenBlacklistedFunc: tt.isTokenBlacklistedFunc,
 				GetRefreshTokenFunc:    tt.getRefreshTokenFunc,
 			}
 			mockPublisher := &MockMessagePublisher{}
-			authService := services.NewAuthService(mockUserRepo, mockTokenRepo, jwtService, mockPublisher, &MockExternalConnectivityClient{}, "test.user.registered", logger)
+			authService := services.NewAuthService(mockUserRepo, mockTokenRepo, jwtService, &MockPasswordHasher{}, logger, services.WithPublisher(mockPublisher, "test.user.registered"))
 
 			tokenPair, err := authService.RefreshToken(context.Background(), tt.refreshToken)
 
@@ -309,7 +353,7 @@ func TestAuthService_RefreshToken(t *testing.T) {
 
 func TestAuthService_Logout(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
-	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, logger)
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, logger)
 
 	// Generate valid tokens
 	validAccessToken, _ := jwtService.GenerateAccessToken(12345, "test@example.com", domain.RoleUser)
@@ -340,7 +384,7 @@ func TestAuthService_Logout(t *testing.T) {
 			mockUserRepo := &MockUserRepository{}
 			mockTokenRepo := &MockTokenRepository{}
 			mockPublisher := &MockMessagePublisher{}
-			authService := services.NewAuthService(mockUserRepo, mockTokenRepo, jwtService, mockPublisher, &MockExternalConnectivityClient{}, "test.user.registered", logger)
+			authService := services.NewAuthService(mockUserRepo, mockTokenRepo, jwtService, &MockPasswordHasher{}, logger, services.WithPublisher(mockPublisher, "test.user.registered"))
 
 			err := authService.Logout(context.Background(), tt.accessToken, tt.refreshToken)
 
@@ -392,8 +436,8 @@ func TestAuthService_GetUserByID(t *testing.T) {
 			}
 			mockTokenRepo := &MockTokenRepository{}
 			mockPublisher := &MockMessagePublisher{}
-			jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, logger)
-			authService := services.NewAuthService(mockUserRepo, mockTokenRepo, jwtService, mockPublisher, &MockExternalConnectivityClient{}, "test.user.registered", logger)
+			jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, logger)
+			authService := services.NewAuthService(mockUserRepo, mockTokenRepo, jwtService, &MockPasswordHasher{}, logger, services.WithPublisher(mockPublisher, "test.user.registered"))
 
 			user, err := authService.GetUserByIDCitizen(context.Background(), tt.idCitizen)
 
@@ -432,8 +476,8 @@ func TestAuthService_Register_DuplicateIDCitizen(t *testing.T) {
 
 	mockTokenRepo := &MockTokenRepository{}
 	mockPublisher := &MockMessagePublisher{}
-	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, logger)
-	authService := services.NewAuthService(mockUserRepo, mockTokenRepo, jwtService, mockPublisher, &MockExternalConnectivityClient{}, "test.user.registered", logger)
+	tokenIssuer := &MockTokenIssuer{}
+	authService := services.NewAuthService(mockUserRepo, mockTokenRepo, tokenIssuer, &MockPasswordHasher{}, logger, services.WithPublisher(mockPublisher, "test.user.registered"))
 
 	_, err := authService.Register(context.Background(), "test@example.com", "password123", "Name", 123)
 	if err == nil {
@@ -461,8 +505,8 @@ func TestAuthService_Register_CreateReturnsAlreadyExists(t *testing.T) {
 
 	mockTokenRepo := &MockTokenRepository{}
 	mockPublisher := &MockMessagePublisher{}
-	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, logger)
-	authService := services.NewAuthService(mockUserRepo, mockTokenRepo, jwtService, mockPublisher, &MockExternalConnectivityClient{}, "test.user.registered", logger)
+	tokenIssuer := &MockTokenIssuer{}
+	authService := services.NewAuthService(mockUserRepo, mockTokenRepo, tokenIssuer, &MockPasswordHasher{}, logger, services.WithPublisher(mockPublisher, "test.user.registered"))
 
 	_, err := authService.Register(context.Background(), "test@example.com", "password123", "Name", 123)
 	if err == nil {
@@ -472,3 +516,1032 @@ func TestAuthService_Register_CreateReturnsAlreadyExists(t *testing.T) {
 		t.Fatalf("expected ErrUserAlreadyExists, got %v", err)
 	}
 }
+
+func TestAuthService_RefreshToken_RotatesRefreshToken(t *testing.T) {
+	logger := zap.NewNop()
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, logger)
+	refreshToken, _ := jwtService.GenerateRefreshToken(12345, "test@example.com", domain.RoleUser)
+
+	var rotatedFrom, rotatedTo, rotatedFamily string
+	mockTokenRepo := &MockTokenRepository{
+		GetActiveRefreshTokenFunc: func(ctx context.Context, token string) (*domain.RefreshTokenData, error) {
+			return &domain.RefreshTokenData{IDCitizen: 12345, Email: "test@example.com", FamilyID: "family-1"}, nil
+		},
+		RotateRefreshTokenFunc: func(ctx context.Context, oldToken, newToken string, data *domain.RefreshTokenData, ttl time.Duration) error {
+			rotatedFrom, rotatedTo, rotatedFamily = oldToken, newToken, data.FamilyID
+			return nil
+		},
+		DeleteRefreshTokenFunc: func(ctx context.Context, token string) error {
+			t.Errorf("DeleteRefreshToken() should not be called, tokens are rotated in one operation")
+			return nil
+		},
+	}
+	userRepo := &MockUserRepository{
+		GetByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+			return newTestUser(), nil
+		},
+	}
+	authService := services.NewAuthService(userRepo, mockTokenRepo, jwtService, &MockPasswordHasher{}, logger)
+
+	tokenPair, err := authService.RefreshToken(context.Background(), refreshToken)
+	if err != nil {
+		t.Fatalf("RefreshToken() unexpected error: %v", err)
+	}
+	if rotatedFrom != refreshToken || rotatedTo != tokenPair.RefreshToken {
+		t.Errorf("RotateRefreshToken() from %q to %q, want from old to new refresh token", rotatedFrom, rotatedTo)
+	}
+	if rotatedFamily != "family-1" {
+		t.Errorf("rotated FamilyID = %q, want the family of the old refresh token", rotatedFamily)
+	}
+}
+
+func TestAuthService_RiskEngine(t *testing.T) {
+	stepUp := &domain.RiskAssessment{Decision: domain.RiskDecisionStepUp, Rules: []string{"new-device"}}
+	deny := &domain.RiskAssessment{Decision: domain.RiskDecisionDeny, Rules: []string{"blocked-ip"}}
+
+	tests := []struct {
+		name           string
+		loginRisk      *domain.RiskAssessment
+		refreshRisk    *domain.RiskAssessment
+		wantLoginErr   error
+		wantRefreshErr error
+	}{
+		{name: "step-up is stored on the session", loginRisk: stepUp, refreshRisk: stepUp},
+		{name: "login denied", loginRisk: deny, wantLoginErr: domainerrors.ErrAuthenticationDenied},
+		{name: "refresh denied", loginRisk: stepUp, refreshRisk: deny, wantRefreshErr: domainerrors.ErrAuthenticationDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zap.NewNop()
+			jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, logger)
+			testUser, _ := domain.NewUser("test@example.com", "password123", "Test User", 12345)
+
+			var stored *domain.RefreshTokenData
+			revoked := false
+			mockTokenRepo := &MockTokenRepository{
+				StoreRefreshTokenFunc: func(ctx context.Context, token string, data *domain.RefreshTokenData, ttl time.Duration) error {
+					stored = data
+					return nil
+				},
+				GetActiveRefreshTokenFunc: func(ctx context.Context, token string) (*domain.RefreshTokenData, error) {
+					return stored, nil
+				},
+				RotateRefreshTokenFunc: func(ctx context.Context, oldToken, newToken string, data *domain.RefreshTokenData, ttl time.Duration) error {
+					stored = data
+					return nil
+				},
+				DeleteRefreshTokenFunc: func(ctx context.Context, token string) error {
+					revoked = true
+					return nil
+				},
+			}
+			userRepo := &MockUserRepository{
+				GetByEmailFunc: func(ctx context.Context, email string) (*domain.User, error) {
+					return testUser, nil
+				},
+				GetByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+					return testUser, nil
+				},
+			}
+			var refreshedSession *domain.RefreshTokenData
+			engine := &MockRiskEngine{
+				AssessLoginFunc: func(ctx context.Context, user *domain.User) *domain.RiskAssessment {
+					return tt.loginRisk
+				},
+				AssessRefreshFunc: func(ctx context.Context, user *domain.User, session *domain.RefreshTokenData) *domain.RiskAssessment {
+					refreshedSession = session
+					return tt.refreshRisk
+				},
+			}
+			authService := services.NewAuthService(userRepo, mockTokenRepo, jwtService, &MockPasswordHasher{}, logger, services.WithRiskEngine(engine))
+
+			tokenPair, err := authService.Login(context.Background(), "test@example.com", "password123")
+			if !errors.Is(err, tt.wantLoginErr) {
+				t.Fatalf("Login() error = %v, want %v", err, tt.wantLoginErr)
+			}
+			if tt.wantLoginErr != nil {
+				if stored != nil {
+					t.Errorf("refresh token stored for denied login")
+				}
+				return
+			}
+			if stored == nil || stored.Risk != tt.loginRisk {
+				t.Fatalf("stored refresh token risk = %+v, want %+v", stored, tt.loginRisk)
+			}
+
+			_, err = authService.RefreshToken(context.Background(), tokenPair.RefreshToken)
+			if !errors.Is(err, tt.wantRefreshErr) {
+				t.Fatalf("RefreshToken() error = %v, want %v", err, tt.wantRefreshErr)
+			}
+			if refreshedSession == nil || refreshedSession.Risk != tt.loginRisk {
+				t.Errorf("AssessRefresh() session risk = %+v, want %+v", refreshedSession, tt.loginRisk)
+			}
+			if revoked != (tt.wantRefreshErr != nil) {
+				t.Errorf("refresh token revoked = %v, want %v", revoked, tt.wantRefreshErr != nil)
+			}
+			if tt.wantRefreshErr == nil && stored.Risk != tt.refreshRisk {
+				t.Errorf("rotated refresh token risk = %+v, want %+v", stored.Risk, tt.refreshRisk)
+			}
+		})
+	}
+}
+
+func TestAuthService_Login_RecordsFailureOnInvalidPassword(t *testing.T) {
+	logger := zap.NewNop()
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, logger)
+	testUser, _ := domain.NewUser("test@example.com", "password123", "Test User", 12345)
+
+	userRepo := &MockUserRepository{
+		GetByEmailFunc: func(ctx context.Context, email string) (*domain.User, error) {
+			return testUser, nil
+		},
+	}
+	failures := 0
+	engine := &MockRiskEngine{
+		RecordLoginFailureFunc: func(ctx context.Context, email string, user *domain.User) {
+			failures++
+		},
+	}
+	authService := services.NewAuthService(userRepo, &MockTokenRepository{}, jwtService, &MockPasswordHasher{}, logger, services.WithRiskEngine(engine))
+
+	if _, err := authService.Login(context.Background(), "test@example.com", "wrongpassword"); !errors.Is(err, domainerrors.ErrInvalidCredentials) {
+		t.Fatalf("Login() error = %v, want %v", err, domainerrors.ErrInvalidCredentials)
+	}
+	if failures != 1 {
+		t.Errorf("RecordLoginFailure() called %d times, want 1", failures)
+	}
+}
+
+func TestAuthService_Login_LockedOutAnswersLikeWrongPassword(t *testing.T) {
+	logger := zap.NewNop()
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, logger)
+	testUser, _ := domain.NewUser("test@example.com", "password123", "Test User", 12345)
+
+	userRepo := &MockUserRepository{
+		GetByEmailFunc: func(ctx context.Context, email string) (*domain.User, error) {
+			return testUser, nil
+		},
+	}
+	engine := &MockRiskEngine{
+		AssessLoginFunc: func(ctx context.Context, user *domain.User) *domain.RiskAssessment {
+			return &domain.RiskAssessment{Decision: domain.RiskDecisionDeny, Rules: []string{"lockout"}, LockedOut: true}
+		},
+	}
+	authService := services.NewAuthService(userRepo, &MockTokenRepository{}, jwtService, &MockPasswordHasher{}, logger, services.WithRiskEngine(engine))
+
+	// Guesses during the lockout can't tell whether they were right
+	for _, password := range []string{"password123", "wrongpassword"} {
+		tokenPair, err := authService.Login(context.Background(), "test@example.com", password)
+		if !errors.Is(err, domainerrors.ErrInvalidCredentials) || tokenPair != nil {
+			t.Errorf("Login(%q) = %v, %v, want %v", password, tokenPair, err, domainerrors.ErrInvalidCredentials)
+		}
+	}
+}
+
+func TestAuthService_Login_RecordsFailureOnUnknownUser(t *testing.T) {
+	logger := zap.NewNop()
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, logger)
+
+	userRepo := &MockUserRepository{
+		GetByEmailFunc: func(ctx context.Context, email string) (*domain.User, error) {
+			return nil, domainerrors.ErrUserNotFound
+		},
+	}
+	var failedEmail string
+	var failedUser *domain.User
+	engine := &MockRiskEngine{
+		RecordLoginFailureFunc: func(ctx context.Context, email string, user *domain.User) {
+			failedEmail, failedUser = email, user
+		},
+	}
+	authService := services.NewAuthService(userRepo, &MockTokenRepository{}, jwtService, &MockPasswordHasher{}, logger, services.WithRiskEngine(engine))
+
+	if _, err := authService.Login(context.Background(), "unknown@example.com", "password123"); !errors.Is(err, domainerrors.ErrInvalidCredentials) {
+		t.Fatalf("Login() error = %v, want %v", err, domainerrors.ErrInvalidCredentials)
+	}
+	if failedEmail != "unknown@example.com" || failedUser != nil {
+		t.Errorf("RecordLoginFailure() email = %q, user = %+v, want unknown@example.com without user", failedEmail, failedUser)
+	}
+}
+
+func TestAuthService_RefreshToken_RepositoryError(t *testing.T) {
+	logger := zap.NewNop()
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, logger)
+	refreshToken, _ := jwtService.GenerateRefreshToken(12345, "test@example.com", domain.RoleUser)
+
+	mockTokenRepo := &MockTokenRepository{
+		GetActiveRefreshTokenFunc: func(ctx context.Context, token string) (*domain.RefreshTokenData, error) {
+			return nil, errors.New("redis down")
+		},
+	}
+	authService := services.NewAuthService(&MockUserRepository{}, mockTokenRepo, jwtService, &MockPasswordHasher{}, logger)
+
+	if _, err := authService.RefreshToken(context.Background(), refreshToken); !errors.Is(err, domainerrors.ErrInternal) {
+		t.Errorf("RefreshToken() error = %v, want %v", err, domainerrors.ErrInternal)
+	}
+}
+
+func TestAuthService_Logout_RevokesSession(t *testing.T) {
+	logger := zap.NewNop()
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, logger)
+	accessToken, _ := jwtService.GenerateAccessToken(12345, "test@example.com", domain.RoleUser)
+	refreshToken, _ := jwtService.GenerateRefreshToken(12345, "test@example.com", domain.RoleUser)
+
+	calls := 0
+	mockTokenRepo := &MockTokenRepository{
+		RevokeSessionFunc: func(ctx context.Context, token string, ttl time.Duration, refresh string) error {
+			calls++
+			if token != accessToken || refresh != refreshToken {
+				t.Errorf("RevokeSession() called with unexpected tokens")
+			}
+			if ttl <= 0 || ttl > 15*time.Minute {
+				t.Errorf("RevokeSession() ttl = %v, want remaining access token lifetime", ttl)
+			}
+			return nil
+		},
+	}
+	authService := services.NewAuthService(&MockUserRepository{}, mockTokenRepo, jwtService, &MockPasswordHasher{}, logger)
+
+	if err := authService.Logout(context.Background(), accessToken, refreshToken); err != nil {
+		t.Fatalf("Logout() unexpected error: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("RevokeSession() called %d times, want 1", calls)
+	}
+}
+
+func TestAuthService_Login_HasherError(t *testing.T) {
+	logger := zap.NewNop()
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, logger)
+
+	userRepo := &MockUserRepository{
+		GetByEmailFunc: func(ctx context.Context, email string) (*domain.User, error) {
+			return newTestUser(), nil
+		},
+	}
+	hasher := &MockPasswordHasher{
+		CompareFunc: func(ctx context.Context, hash, password string) (bool, error) {
+			return false, context.DeadlineExceeded
+		},
+	}
+	authService := services.NewAuthService(userRepo, &MockTokenRepository{}, jwtService, hasher, logger)
+
+	if _, err := authService.Login(context.Background(), "test@example.com", "password123"); !errors.Is(err, domainerrors.ErrInternal) {
+		t.Errorf("Login() error = %v, want %v", err, domainerrors.ErrInternal)
+	}
+}
+
+func TestAuthService_Register_UsesPasswordHasher(t *testing.T) {
+	logger := zap.NewNop()
+	tokenIssuer := &MockTokenIssuer{}
+
+	var created *domain.User
+	userRepo := &MockUserRepository{
+		CreateFunc: func(ctx context.Context, user *domain.User) error {
+			created = user
+			return nil
+		},
+	}
+	hasher := &MockPasswordHasher{
+		HashFunc: func(ctx context.Context, password string) (string, error) {
+			return "hashed:" + password, nil
+		},
+	}
+	authService := services.NewAuthService(userRepo, &MockTokenRepository{}, tokenIssuer, hasher, logger)
+
+	if _, err := authService.Register(context.Background(), "new@example.com", "password123", "New User", 54321); err != nil {
+		t.Fatalf("Register() unexpected error = %v", err)
+	}
+	if created == nil || created.Password != "hashed:password123" {
+		t.Errorf("created user password = %+v, want hashed by the password hasher", created)
+	}
+}
+
+func TestAuthService_RefreshToken_ReloadsUser(t *testing.T) {
+	logger := zap.NewNop()
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, logger)
+	refreshToken, _ := jwtService.GenerateRefreshToken(12345, "test@example.com", domain.RoleAdmin)
+
+	tests := []struct {
+		name        string
+		getUserFunc func(ctx context.Context, idCitizen int) (*domain.User, error)
+		wantErr     error
+		wantRole    domain.Role
+		wantRevoked bool
+	}{
+		{
+			name: "demoted admin gets current role",
+			getUserFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+				return newTestUser(), nil
+			},
+			wantRole: domain.RoleUser,
+		},
+		{
+			name: "deleted user",
+			getUserFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+				return nil, domainerrors.ErrUserNotFound
+			},
+			wantErr:     domainerrors.ErrInvalidToken,
+			wantRevoked: true,
+		},
+		{
+			name: "suspended user",
+			getUserFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+				user := newTestUser()
+				user.Status = domain.UserStatusSuspended
+				return user, nil
+			},
+			wantErr:     domainerrors.ErrUserSuspended,
+			wantRevoked: true,
+		},
+		{
+			name: "repository error",
+			getUserFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+				return nil, errors.New("db down")
+			},
+			wantErr: domainerrors.ErrInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			revoked := false
+			tokenRepo := &MockTokenRepository{
+				GetActiveRefreshTokenFunc: func(ctx context.Context, token string) (*domain.RefreshTokenData, error) {
+					return &domain.RefreshTokenData{IDCitizen: 12345, Email: "test@example.com"}, nil
+				},
+				DeleteRefreshTokenFunc: func(ctx context.Context, token string) error {
+					revoked = token == refreshToken
+					return nil
+				},
+				RotateRefreshTokenFunc: func(ctx context.Context, oldToken, newToken string, data *domain.RefreshTokenData, ttl time.Duration) error {
+					return nil
+				},
+			}
+			userRepo := &MockUserRepository{GetByIDCitizenFunc: tt.getUserFunc}
+			authService := services.NewAuthService(userRepo, tokenRepo, jwtService, &MockPasswordHasher{}, logger)
+
+			tokenPair, err := authService.RefreshToken(context.Background(), refreshToken)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RefreshToken() error = %v, want %v", err, tt.wantErr)
+			}
+			if revoked != tt.wantRevoked {
+				t.Errorf("refresh token revoked = %v, want %v", revoked, tt.wantRevoked)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			claims, err := jwtService.ValidateAccessToken(tokenPair.AccessToken)
+			if err != nil {
+				t.Fatalf("ValidateAccessToken() unexpected error: %v", err)
+			}
+			if claims.Role != tt.wantRole {
+				t.Errorf("access token role = %v, want %v", claims.Role, tt.wantRole)
+			}
+		})
+	}
+}
+
+func TestAuthService_LoginWithUser(t *testing.T) {
+	logger := zap.NewNop()
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, logger)
+
+	user, _ := domain.NewUser("test@example.com", "password123", "Test User", 12345)
+	user.ID = "user-123"
+
+	userRepo := &MockUserRepository{
+		GetByEmailFunc: func(ctx context.Context, email string) (*domain.User, error) {
+			return user, nil
+		},
+	}
+	authService := services.NewAuthService(userRepo, &MockTokenRepository{}, jwtService, &MockPasswordHasher{}, logger)
+
+	tokenPair, publicUser, err := authService.LoginWithUser(context.Background(), "test@example.com", "password123")
+	if err != nil {
+		t.Fatalf("LoginWithUser() unexpected error: %v", err)
+	}
+	if tokenPair == nil || tokenPair.AccessToken == "" {
+		t.Errorf("LoginWithUser() returned no access token")
+	}
+	if publicUser == nil || publicUser.ID != "user-123" || publicUser.IDCitizen != 12345 {
+		t.Errorf("LoginWithUser() user = %+v, want user-123 with IDCitizen 12345", publicUser)
+	}
+
+	if _, publicUser, err := authService.LoginWithUser(context.Background(), "test@example.com", "wrongpassword"); !errors.Is(err, domainerrors.ErrInvalidCredentials) || publicUser != nil {
+		t.Errorf("LoginWithUser() = %v, %v, want nil user and %v", publicUser, err, domainerrors.ErrInvalidCredentials)
+	}
+}
+
+func TestAuthService_Login_SuspendedUser(t *testing.T) {
+	logger := zap.NewNop()
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, logger)
+
+	user, _ := domain.NewUser("test@example.com", "password123", "Test User", 12345)
+	user.Status = domain.UserStatusSuspended
+
+	userRepo := &MockUserRepository{
+		GetByEmailFunc: func(ctx context.Context, email string) (*domain.User, error) {
+			return user, nil
+		},
+	}
+	authService := services.NewAuthService(userRepo, &MockTokenRepository{}, jwtService, &MockPasswordHasher{}, logger)
+
+	if _, err := authService.Login(context.Background(), "test@example.com", "password123"); !errors.Is(err, domainerrors.ErrUserSuspended) {
+		t.Errorf("Login() error = %v, want %v", err, domainerrors.ErrUserSuspended)
+	}
+}
+
+func TestAuthService_Login_ServiceAccount(t *testing.T) {
+	logger := zap.NewNop()
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, logger)
+
+	user, _ := domain.NewServiceAccount("CI pipeline", 90001)
+	user.ID = "sa-123"
+
+	userRepo := &MockUserRepository{
+		GetByEmailFunc: func(ctx context.Context, email string) (*domain.User, error) {
+			return user, nil
+		},
+	}
+	authService := services.NewAuthService(userRepo, &MockTokenRepository{}, jwtService, &MockPasswordHasher{}, logger)
+
+	if _, err := authService.Login(context.Background(), user.Email, ""); !errors.Is(err, domainerrors.ErrInvalidCredentials) {
+		t.Errorf("Login() error = %v, want %v", err, domainerrors.ErrInvalidCredentials)
+	}
+}
+
+func TestAuthService_Login_RegistrationApproval(t *testing.T) {
+	logger := zap.NewNop()
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, logger)
+
+	tests := []struct {
+		name    string
+		status  domain.UserStatus
+		wantErr error
+	}{
+		{name: "pending approval", status: domain.UserStatusPendingApproval, wantErr: domainerrors.ErrUserPendingApproval},
+		{name: "rejected", status: domain.UserStatusRejected, wantErr: domainerrors.ErrUserRejected},
+		{name: "approved", status: domain.UserStatusActive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, _ := domain.NewUser("test@example.com", "password123", "Test User", 12345)
+			user.Status = tt.status
+
+			userRepo := &MockUserRepository{
+				GetByEmailFunc: func(ctx context.Context, email string) (*domain.User, error) {
+					return user, nil
+				},
+			}
+			authService := services.NewAuthService(userRepo, &MockTokenRepository{}, jwtService, &MockPasswordHasher{}, logger, services.WithRegistrationApproval(true))
+
+			if _, err := authService.Login(context.Background(), "test@example.com", "password123"); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Login() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAuthService_Register_RequireApproval(t *testing.T) {
+	logger := zap.NewNop()
+	tokenIssuer := &MockTokenIssuer{}
+
+	tests := []struct {
+		name            string
+		requireApproval bool
+		wantStatus      domain.UserStatus
+	}{
+		{name: "approval required", requireApproval: true, wantStatus: domain.UserStatusPendingApproval},
+		{name: "approval not required", requireApproval: false, wantStatus: domain.UserStatusActive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created *domain.User
+			userRepo := &MockUserRepository{
+				CreateFunc: func(ctx context.Context, user *domain.User) error {
+					created = user
+					return nil
+				},
+			}
+			var event events.UserRegisteredEvent
+			publisher := &MockMessagePublisher{
+				PublishFunc: func(ctx context.Context, queue string, message []byte) error {
+					return json.Unmarshal(message, &event)
+				},
+			}
+			authService := services.NewAuthService(userRepo, &MockTokenRepository{}, tokenIssuer, &MockPasswordHasher{}, logger, services.WithPublisher(publisher, "test.user.registered"), services.WithRegistrationApproval(tt.requireApproval))
+
+			if _, err := authService.Register(context.Background(), "new@example.com", "password123", "New User", 54321); err != nil {
+				t.Fatalf("Register() unexpected error = %v", err)
+			}
+			if created == nil || created.Status != tt.wantStatus {
+				t.Errorf("created user = %+v, want status %q", created, tt.wantStatus)
+			}
+			if event.Status != tt.wantStatus.String() {
+				t.Errorf("registered event status = %q, want %q", event.Status, tt.wantStatus)
+			}
+			if event.IDCitizen != 54321 || event.Email != "new@example.com" || event.Role != domain.RoleUser {
+				t.Errorf("registered event = %+v, want the id_citizen, email and role of the user", event)
+			}
+		})
+	}
+}
+
+func TestAuthService_QuotaEnforcement(t *testing.T) {
+	logger := zap.NewNop()
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, logger)
+	testUser, _ := domain.NewUser("test@example.com", "password123", "Test User", 12345)
+	refreshToken, _ := jwtService.GenerateRefreshToken(12345, "test@example.com", domain.RoleUser)
+
+	userRepo := &MockUserRepository{
+		GetByEmailFunc: func(ctx context.Context, email string) (*domain.User, error) {
+			return testUser, nil
+		},
+		GetByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+			return testUser, nil
+		},
+	}
+	issued := 0
+	tokenRepo := &MockTokenRepository{
+		StoreRefreshTokenFunc: func(ctx context.Context, token string, data *domain.RefreshTokenData, ttl time.Duration) error {
+			issued++
+			return nil
+		},
+		GetActiveRefreshTokenFunc: func(ctx context.Context, token string) (*domain.RefreshTokenData, error) {
+			return &domain.RefreshTokenData{IDCitizen: 12345, FamilyID: "family-1"}, nil
+		},
+		RotateRefreshTokenFunc: func(ctx context.Context, oldToken, newToken string, data *domain.RefreshTokenData, ttl time.Duration) error {
+			issued++
+			return nil
+		},
+	}
+	var newSessions []bool
+	enforcer := &MockQuotaEnforcer{
+		EnforceUserIssuanceFunc: func(ctx context.Context, user *domain.User, newSession bool) error {
+			newSessions = append(newSessions, newSession)
+			if newSession {
+				return &domainerrors.QuotaExceededError{Err: domainerrors.ErrSessionQuotaExceeded, Subject: domain.QuotaSubjectUser, Limit: 3}
+			}
+			return &domainerrors.QuotaExceededError{Err: domainerrors.ErrTokenQuotaExceeded, Subject: domain.QuotaSubjectUser, Limit: 20, RetryAfter: time.Minute}
+		},
+	}
+	authService := services.NewAuthService(userRepo, tokenRepo, jwtService, &MockPasswordHasher{}, logger, services.WithQuotaEnforcer(enforcer))
+
+	if _, err := authService.Login(context.Background(), "test@example.com", "password123"); !errors.Is(err, domainerrors.ErrSessionQuotaExceeded) {
+		t.Errorf("Login() error = %v, want %v", err, domainerrors.ErrSessionQuotaExceeded)
+	}
+	if _, err := authService.RefreshToken(context.Background(), refreshToken); !errors.Is(err, domainerrors.ErrTokenQuotaExceeded) {
+		t.Errorf("RefreshToken() error = %v, want %v", err, domainerrors.ErrTokenQuotaExceeded)
+	}
+	if issued != 0 {
+		t.Errorf("refresh tokens stored = %d, want 0", issued)
+	}
+	if len(newSessions) != 2 || !newSessions[0] || newSessions[1] {
+		t.Errorf("EnforceUserIssuance() newSession = %v, want [true false]", newSessions)
+	}
+}
+
+func TestAuthService_OpaqueRefreshTokens(t *testing.T) {
+	logger := zap.NewNop()
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, true, nil, services.TokenSigningPolicy{}, logger)
+
+	stored := map[string]*domain.RefreshTokenData{}
+	mockTokenRepo := &MockTokenRepository{
+		StoreRefreshTokenFunc: func(ctx context.Context, token string, data *domain.RefreshTokenData, ttl time.Duration) error {
+			stored[token] = data
+			return nil
+		},
+		GetActiveRefreshTokenFunc: func(ctx context.Context, token string) (*domain.RefreshTokenData, error) {
+			data, ok := stored[token]
+			if !ok {
+				return nil, domainerrors.ErrInvalidToken
+			}
+			return data, nil
+		},
+		RotateRefreshTokenFunc: func(ctx context.Context, oldToken, newToken string, data *domain.RefreshTokenData, ttl time.Duration) error {
+			delete(stored, oldToken)
+			stored[newToken] = data
+			return nil
+		},
+		DeleteRefreshTokenFunc: func(ctx context.Context, token string) error {
+			delete(stored, token)
+			return nil
+		},
+	}
+	userRepo := &MockUserRepository{
+		GetByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+			return newTestUser(), nil
+		},
+	}
+	authService := services.NewAuthService(userRepo, mockTokenRepo, jwtService, &MockPasswordHasher{}, logger)
+
+	tokenPair, err := authService.IssueTokenPair(context.Background(), 12345, domain.TokenProfileStandard, nil)
+	if err != nil {
+		t.Fatalf("IssueTokenPair() unexpected error: %v", err)
+	}
+	if !domain.IsOpaqueRefreshToken(tokenPair.RefreshToken) {
+		t.Fatalf("refresh token = %q, want an opaque token", tokenPair.RefreshToken)
+	}
+	if domain.IsOpaqueRefreshToken(tokenPair.AccessToken) {
+		t.Errorf("access token = %q, want a JWT", tokenPair.AccessToken)
+	}
+	data := stored[tokenPair.RefreshToken]
+	if data == nil || data.UserID != "user-123" || data.Role != domain.RoleUser {
+		t.Fatalf("stored data = %+v, want the claims of the refresh token", data)
+	}
+
+	refreshed, err := authService.RefreshToken(context.Background(), tokenPair.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshToken() unexpected error: %v", err)
+	}
+	if !domain.IsOpaqueRefreshToken(refreshed.RefreshToken) || refreshed.RefreshToken == tokenPair.RefreshToken {
+		t.Errorf("rotated refresh token = %q, want a new opaque token", refreshed.RefreshToken)
+	}
+
+	if _, err := authService.RefreshToken(context.Background(), tokenPair.RefreshToken); !errors.Is(err, domainerrors.ErrInvalidToken) {
+		t.Errorf("RefreshToken() with the rotated token error = %v, want %v", err, domainerrors.ErrInvalidToken)
+	}
+
+	stored[refreshed.RefreshToken].ExpiresAt = time.Now().Add(-time.Minute)
+	if _, err := authService.RefreshToken(context.Background(), refreshed.RefreshToken); !errors.Is(err, domainerrors.ErrExpiredToken) {
+		t.Errorf("RefreshToken() with an expired token error = %v, want %v", err, domainerrors.ErrExpiredToken)
+	}
+}
+
+func TestAuthService_IssueTokenPair_Delegation(t *testing.T) {
+	logger := zap.NewNop()
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, true, nil, services.TokenSigningPolicy{}, logger)
+
+	stored := map[string]*domain.RefreshTokenData{}
+	tokenRepo := &MockTokenRepository{
+		StoreRefreshTokenFunc: func(ctx context.Context, token string, data *domain.RefreshTokenData, ttl time.Duration) error {
+			stored[token] = data
+			return nil
+		},
+		GetActiveRefreshTokenFunc: func(ctx context.Context, token string) (*domain.RefreshTokenData, error) {
+			data, ok := stored[token]
+			if !ok {
+				return nil, domainerrors.ErrInvalidToken
+			}
+			return data, nil
+		},
+		RotateRefreshTokenFunc: func(ctx context.Context, oldToken, newToken string, data *domain.RefreshTokenData, ttl time.Duration) error {
+			delete(stored, oldToken)
+			stored[newToken] = data
+			return nil
+		},
+	}
+	userRepo := &MockUserRepository{
+		GetByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+			return newTestUser(), nil
+		},
+	}
+	authService := services.NewAuthService(userRepo, tokenRepo, jwtService, &MockPasswordHasher{}, logger)
+
+	delegation := &domain.DelegatedAccess{ClientID: "tv-app", Scopes: []string{"profile"}}
+	tokenPair, err := authService.IssueTokenPair(context.Background(), 12345, domain.TokenProfileStandard, delegation)
+	if err != nil {
+		t.Fatalf("IssueTokenPair() unexpected error: %v", err)
+	}
+
+	// The refreshed tokens keep the limits of the session
+	refreshed, err := authService.RefreshToken(context.Background(), tokenPair.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshToken() unexpected error: %v", err)
+	}
+
+	for name, accessToken := range map[string]string{"issued": tokenPair.AccessToken, "refreshed": refreshed.AccessToken} {
+		claims, err := jwtService.ValidateAccessToken(accessToken)
+		if err != nil {
+			t.Fatalf("ValidateAccessToken() of the %s token unexpected error: %v", name, err)
+		}
+		if !claims.IsDelegated() || claims.ClientID != "tv-app" || len(claims.Scopes) != 1 || claims.Scopes[0] != "profile" {
+			t.Errorf("%s token claims = %+v, want delegated to tv-app with the profile scope", name, claims)
+		}
+	}
+}
+
+func TestAuthService_IssueTokenPair_ChecksTheUser(t *testing.T) {
+	logger := zap.NewNop()
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, logger)
+
+	tests := []struct {
+		name     string
+		modify   func(user *domain.User)
+		decision domain.RiskDecision
+		wantErr  error
+	}{
+		{name: "active user", modify: func(user *domain.User) {}},
+		{name: "suspended user", modify: func(user *domain.User) { user.Status = domain.UserStatusSuspended }, wantErr: domainerrors.ErrUserSuspended},
+		{name: "pending approval", modify: func(user *domain.User) { user.Status = domain.UserStatusPendingApproval }, wantErr: domainerrors.ErrUserPendingApproval},
+		{name: "rejected user", modify: func(user *domain.User) { user.Status = domain.UserStatusRejected }, wantErr: domainerrors.ErrUserRejected},
+		{name: "temporary password", modify: func(user *domain.User) { user.MustChangePassword = true }, wantErr: domainerrors.ErrPasswordChangeRequired},
+		{name: "service account", modify: func(user *domain.User) { user.Type = domain.UserTypeServiceAccount }, wantErr: domainerrors.ErrInvalidGrant},
+		{name: "denied by the risk policy", modify: func(user *domain.User) {}, decision: domain.RiskDecisionDeny, wantErr: domainerrors.ErrAuthenticationDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored := false
+			recorded := false
+			userRepo := &MockUserRepository{
+				GetByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+					user := newTestUser()
+					tt.modify(user)
+					return user, nil
+				},
+				RecordLoginFunc: func(ctx context.Context, userID string, at time.Time) error {
+					recorded = true
+					return nil
+				},
+			}
+			tokenRepo := &MockTokenRepository{
+				StoreRefreshTokenFunc: func(ctx context.Context, token string, data *domain.RefreshTokenData, ttl time.Duration) error {
+					stored = true
+					return nil
+				},
+			}
+			engine := &MockRiskEngine{
+				AssessLoginFunc: func(ctx context.Context, user *domain.User) *domain.RiskAssessment {
+					if tt.decision != "" {
+						return &domain.RiskAssessment{Decision: tt.decision}
+					}
+					return &domain.RiskAssessment{Decision: domain.RiskDecisionAllow}
+				},
+			}
+			authService := services.NewAuthService(userRepo, tokenRepo, jwtService, &MockPasswordHasher{}, logger, services.WithRiskEngine(engine))
+
+			_, err := authService.IssueTokenPair(context.Background(), 12345, domain.TokenProfileStandard, nil)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("IssueTokenPair() error = %v, want %v", err, tt.wantErr)
+			}
+			if stored != (tt.wantErr == nil) {
+				t.Errorf("refresh token stored = %v, want %v", stored, tt.wantErr == nil)
+			}
+			if recorded {
+				t.Errorf("IssueTokenPair() recorded a login")
+			}
+		})
+	}
+}
+
+func TestAuthService_MinimalTokenProfile(t *testing.T) {
+	logger := zap.NewNop()
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, logger)
+
+	stored := map[string]*domain.RefreshTokenData{}
+	mockTokenRepo := &MockTokenRepository{
+		StoreRefreshTokenFunc: func(ctx context.Context, token string, data *domain.RefreshTokenData, ttl time.Duration) error {
+			stored[token] = data
+			return nil
+		},
+		GetActiveRefreshTokenFunc: func(ctx context.Context, token string) (*domain.RefreshTokenData, error) {
+			data, ok := stored[token]
+			if !ok {
+				return nil, domainerrors.ErrInvalidToken
+			}
+			return data, nil
+		},
+		RotateRefreshTokenFunc: func(ctx context.Context, oldToken, newToken string, data *domain.RefreshTokenData, ttl time.Duration) error {
+			delete(stored, oldToken)
+			stored[newToken] = data
+			return nil
+		},
+	}
+	userRepo := &MockUserRepository{
+		GetByEmailFunc: func(ctx context.Context, email string) (*domain.User, error) {
+			return newTestUser(), nil
+		},
+		GetByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+			return newTestUser(), nil
+		},
+	}
+	authService := services.NewAuthService(
+		userRepo,
+		mockTokenRepo,
+		jwtService,
+		&MockPasswordHasher{
+			CompareFunc: func(ctx context.Context, hash, password string) (bool, error) {
+				return true, nil
+			},
+		},
+		logger,
+	)
+
+	tokenPair, err := authService.LoginForClient(context.Background(), "test@example.com", "password123", domain.TokenProfileMinimal)
+	if err != nil {
+		t.Fatalf("LoginForClient() unexpected error: %v", err)
+	}
+	if data := stored[tokenPair.RefreshToken]; data == nil || data.TokenProfile != domain.TokenProfileMinimal {
+		t.Fatalf("stored data = %+v, want the minimal token profile", data)
+	}
+
+	claims, err := authService.ValidateAccessToken(context.Background(), tokenPair.AccessToken)
+	if err != nil {
+		t.Fatalf("ValidateAccessToken() unexpected error: %v", err)
+	}
+	if claims.IDCitizen != 12345 || claims.Email != "" || claims.UserID != "" {
+		t.Errorf("ValidateAccessToken() claims = %+v, want a minimal access token", claims)
+	}
+
+	// The session keeps its token profile across refreshes
+	refreshed, err := authService.RefreshToken(context.Background(), tokenPair.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshToken() unexpected error: %v", err)
+	}
+	claims, err = authService.ValidateAccessToken(context.Background(), refreshed.AccessToken)
+	if err != nil {
+		t.Fatalf("ValidateAccessToken() unexpected error: %v", err)
+	}
+	if claims.Email != "" {
+		t.Errorf("refreshed access token claims = %+v, want a minimal access token", claims)
+	}
+}
+
+func TestAuthService_TokenVersion(t *testing.T) {
+	logger := zap.NewNop()
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, logger)
+
+	tests := []struct {
+		name           string
+		tokenVersion   int
+		currentVersion int
+		wantErr        error
+	}{
+		{name: "current token version", tokenVersion: 1, currentVersion: 1},
+		{name: "no revocation", tokenVersion: 0, currentVersion: 0},
+		{name: "tokens revoked after issuance", tokenVersion: 0, currentVersion: 1, wantErr: domainerrors.ErrTokenRevoked},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issued := newTestUser()
+			issued.TokenVersion = tt.tokenVersion
+			tokenPair, err := jwtService.GenerateUserTokenPair(issued)
+			if err != nil {
+				t.Fatalf("GenerateUserTokenPair() error = %v", err)
+			}
+
+			userRepo := &MockUserRepository{
+				GetByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+					user := newTestUser()
+					user.TokenVersion = tt.currentVersion
+					return user, nil
+				},
+			}
+			var rotated *domain.RefreshTokenData
+			tokenRepo := &MockTokenRepository{
+				GetRefreshTokenFunc: func(ctx context.Context, token string) (*domain.RefreshTokenData, error) {
+					return &domain.RefreshTokenData{IDCitizen: 12345, Email: "test@example.com", TokenVersion: tt.tokenVersion}, nil
+				},
+				RotateRefreshTokenFunc: func(ctx context.Context, oldToken, newToken string, data *domain.RefreshTokenData, ttl time.Duration) error {
+					rotated = data
+					return nil
+				},
+				GetTokenVersionFunc: func(ctx context.Context, idCitizen int) (int, error) {
+					return tt.currentVersion, nil
+				},
+			}
+			authService := services.NewAuthService(userRepo, tokenRepo, jwtService, &MockPasswordHasher{}, logger)
+
+			claims, err := authService.ValidateAccessToken(context.Background(), tokenPair.AccessToken)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateAccessToken() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && claims.TokenVersion != tt.tokenVersion {
+				t.Errorf("ValidateAccessToken() token version = %d, want %d", claims.TokenVersion, tt.tokenVersion)
+			}
+
+			_, err = authService.RefreshToken(context.Background(), tokenPair.RefreshToken)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("RefreshToken() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (rotated == nil || rotated.TokenVersion != tt.currentVersion) {
+				t.Errorf("rotated session = %+v, want token version %d", rotated, tt.currentVersion)
+			}
+		})
+	}
+}
+
+func TestAuthService_Login_UsesTokenIssuer(t *testing.T) {
+	testUser, _ := domain.NewUser("test@example.com", "password123", "Test User", 12345)
+	testUser.ID = "user-123"
+	userRepo := &MockUserRepository{
+		GetByEmailFunc: func(ctx context.Context, email string) (*domain.User, error) {
+			return testUser, nil
+		},
+	}
+
+	var storedToken string
+	var storedTTL time.Duration
+	tokenRepo := &MockTokenRepository{
+		StoreRefreshTokenFunc: func(ctx context.Context, token string, data *domain.RefreshTokenData, ttl time.Duration) error {
+			storedToken, storedTTL = token, ttl
+			return nil
+		},
+	}
+	tokenIssuer := &MockTokenIssuer{RefreshTokenDurationValue: time.Hour}
+	authService := services.NewAuthService(userRepo, tokenRepo, tokenIssuer, &MockPasswordHasher{}, zap.NewNop())
+
+	tokenPair, err := authService.Login(context.Background(), "test@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if tokenPair.AccessToken != "access-user-123" || tokenPair.RefreshToken != "refresh-user-123" {
+		t.Errorf("Login() = %+v, want the tokens of the issuer", tokenPair)
+	}
+	if storedToken != "refresh-user-123" || storedTTL != time.Hour {
+		t.Errorf("stored refresh token %q for %s, want refresh-user-123 for the refresh token duration of the issuer", storedToken, storedTTL)
+	}
+
+	// A failing issuer fails the login
+	tokenIssuer.GenerateUserTokenPairWithProfileFunc = func(user *domain.User, profile domain.TokenProfile, delegation *domain.DelegatedAccess) (*domain.TokenPair, error) {
+		return nil, errors.New("signing key unavailable")
+	}
+	if _, err := authService.Login(context.Background(), "test@example.com", "password123"); !errors.Is(err, domainerrors.ErrInternal) {
+		t.Errorf("Login() error = %v, want ErrInternal", err)
+	}
+}
+
+func TestAuthService_ValidateAccessToken_UsesTokenIssuer(t *testing.T) {
+	tokenIssuer := &MockTokenIssuer{
+		ValidateAccessTokenFunc: func(token string) (*domain.TokenClaims, error) {
+			if token != "valid-token" {
+				return nil, domainerrors.ErrInvalidToken
+			}
+			return &domain.TokenClaims{IDCitizen: 12345, Type: domain.TokenTypeAccess}, nil
+		},
+	}
+	authService := services.NewAuthService(&MockUserRepository{}, &MockTokenRepository{}, tokenIssuer, &MockPasswordHasher{}, zap.NewNop())
+
+	claims, err := authService.ValidateAccessToken(context.Background(), "valid-token")
+	if err != nil || claims.IDCitizen != 12345 {
+		t.Errorf("ValidateAccessToken() = %+v, %v, want the claims of the issuer", claims, err)
+	}
+	if _, err := authService.ValidateAccessToken(context.Background(), "forged-token"); !errors.Is(err, domainerrors.ErrInvalidToken) {
+		t.Errorf("ValidateAccessToken() error = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestAuthService_WithClock(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	testUser, _ := domain.NewUser("test@example.com", "password123", "Test User", 12345)
+	testUser.ID = "user-123"
+
+	var recordedAt time.Time
+	userRepo := &MockUserRepository{
+		GetByEmailFunc: func(ctx context.Context, email string) (*domain.User, error) {
+			return testUser, nil
+		},
+		RecordLoginFunc: func(ctx context.Context, id string, at time.Time) error {
+			recordedAt = at
+			return nil
+		},
+	}
+	var session *domain.RefreshTokenData
+	tokenRepo := &MockTokenRepository{
+		StoreRefreshTokenFunc: func(ctx context.Context, token string, data *domain.RefreshTokenData, ttl time.Duration) error {
+			session = data
+			return nil
+		},
+	}
+	authService := services.NewAuthService(userRepo, tokenRepo, &MockTokenIssuer{RefreshTokenDurationValue: time.Hour}, &MockPasswordHasher{}, zap.NewNop(),
+		services.WithClock(func() time.Time { return now }))
+
+	if _, err := authService.Login(context.Background(), "test@example.com", "password123"); err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if !recordedAt.Equal(now) {
+		t.Errorf("login recorded at %s, want %s", recordedAt, now)
+	}
+	if session == nil || !session.IssuedAt.Equal(now) || !session.ExpiresAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("session = %+v, want issued at %s for an hour", session, now)
+	}
+}
+
+func TestAuthService_Register_WithoutOptionalDependencies(t *testing.T) {
+	userRepo := &MockUserRepository{
+		ExistsFunc: func(ctx context.Context, email string) (bool, error) {
+			return false, nil
+		},
+		GetByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+			return nil, domainerrors.ErrUserNotFound
+		},
+	}
+	// Without a connectivity client nor a publisher the centralizer is not checked and no event is published
+	authService := services.NewAuthService(userRepo, &MockTokenRepository{}, &MockTokenIssuer{}, &MockPasswordHasher{}, zap.NewNop())
+
+	user, err := authService.Register(context.Background(), "new@example.com", "password123", "New User", 54321)
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if user.Email != "new@example.com" {
+		t.Errorf("Register() = %+v", user)
+	}
+}
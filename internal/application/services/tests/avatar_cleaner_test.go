@@ -0,0 +1,96 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// newListingStorage returns a MockBlobStorage listing objects in key order, like S3
+func newListingStorage(objects []domain.BlobObject, deleted *[]string) *MockBlobStorage {
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+	return &MockBlobStorage{
+		ListFunc: func(ctx context.Context, prefix, startAfter string, limit int) ([]domain.BlobObject, error) {
+			var page []domain.BlobObject
+			for _, object := range objects {
+				if strings.HasPrefix(object.Key, prefix) && object.Key > startAfter && len(page) < limit {
+					page = append(page, object)
+				}
+			}
+			return page, nil
+		},
+		DeleteFunc: func(ctx context.Context, key string) error {
+			if key == "avatars/failing.png" {
+				return errors.New("s3 down")
+			}
+			*deleted = append(*deleted, key)
+			return nil
+		},
+	}
+}
+
+func TestAvatarCleaner_RemoveOrphans(t *testing.T) {
+	old := time.Now().Add(-2 * time.Hour)
+	objects := []domain.BlobObject{
+		{Key: "avatars/a.png", LastModified: old},
+		{Key: "avatars/b.png", LastModified: old},
+		{Key: "avatars/c.png", LastModified: time.Now()},
+		{Key: "avatars/d.png", LastModified: old},
+		{Key: "avatars/e.png", LastModified: old},
+		{Key: "avatars/failing.png", LastModified: old},
+	}
+
+	var deleted []string
+	var checked []string
+	avatarRepo := &MockAvatarRepository{
+		ReferencedKeysFunc: func(ctx context.Context, keys []string) (map[string]bool, error) {
+			checked = append(checked, keys...)
+			return map[string]bool{"avatars/b.png": true, "avatars/e.png": true}, nil
+		},
+	}
+
+	cleaner := services.NewAvatarCleaner(newListingStorage(objects, &deleted), avatarRepo, services.AvatarCleanupPolicy{
+		Interval:    time.Hour,
+		GracePeriod: time.Hour,
+		BatchSize:   2,
+	}, zap.NewNop())
+
+	removed, err := cleaner.RemoveOrphans(context.Background())
+	if err != nil {
+		t.Fatalf("RemoveOrphans() error = %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("RemoveOrphans() = %v, want 2", removed)
+	}
+	if got := strings.Join(deleted, ","); got != "avatars/a.png,avatars/d.png" {
+		t.Errorf("deleted = %v, want the unreferenced images older than the grace period", got)
+	}
+	if strings.Contains(strings.Join(checked, ","), "avatars/c.png") {
+		t.Errorf("checked keys = %v, the recent image must be skipped", checked)
+	}
+}
+
+func TestAvatarCleaner_RemoveOrphans_ListFailure(t *testing.T) {
+	storage := &MockBlobStorage{
+		ListFunc: func(ctx context.Context, prefix, startAfter string, limit int) ([]domain.BlobObject, error) {
+			return nil, errors.New("s3 down")
+		},
+	}
+	cleaner := services.NewAvatarCleaner(storage, &MockAvatarRepository{}, services.AvatarCleanupPolicy{
+		Interval:    time.Hour,
+		GracePeriod: time.Hour,
+		BatchSize:   10,
+	}, zap.NewNop())
+
+	if _, err := cleaner.RemoveOrphans(context.Background()); err == nil {
+		t.Error("RemoveOrphans() error = nil, want the list error")
+	}
+}
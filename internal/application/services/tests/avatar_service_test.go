@@ -0,0 +1,152 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+var pngImage = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func newTestAvatarService(avatarRepo *MockAvatarRepository, storage *MockBlobStorage) *services.AvatarService {
+	userRepo := &MockUserRepository{
+		GetByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+			return newTestUser(), nil
+		},
+	}
+	return services.NewAvatarService(userRepo, avatarRepo, storage, 1024, 15*time.Minute, zap.NewNop())
+}
+
+func TestAvatarService_UploadAvatar(t *testing.T) {
+	previous := &domain.Avatar{UserID: "user-123", ObjectKey: "avatars/user-123/old.png", ContentType: "image/png"}
+
+	tests := []struct {
+		name        string
+		data        []byte
+		previous    *domain.Avatar
+		putErr      error
+		upsertErr   error
+		wantErr     error
+		wantStored  bool
+		wantDeleted []string
+	}{
+		{name: "first avatar", data: pngImage, wantStored: true},
+		{name: "replaces the previous avatar", data: pngImage, previous: previous, wantStored: true, wantDeleted: []string{previous.ObjectKey}},
+		{name: "too large", data: append(append([]byte{}, pngImage...), make([]byte, 1024)...), wantErr: domainerrors.ErrAvatarTooLarge},
+		{name: "not an image", data: []byte("GIF89a not supported"), wantErr: domainerrors.ErrUnsupportedAvatarType},
+		{name: "empty", data: nil, wantErr: domainerrors.ErrUnsupportedAvatarType},
+		{name: "storage failure", data: pngImage, putErr: errors.New("s3 down"), wantErr: domainerrors.ErrInternal},
+		{name: "repository failure removes the new image", data: pngImage, upsertErr: errors.New("db down"), wantErr: domainerrors.ErrInternal, wantDeleted: []string{"new"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var storedKey string
+			var deleted []string
+			storage := &MockBlobStorage{
+				PutFunc: func(ctx context.Context, key, contentType string, body io.Reader, size int64) error {
+					if tt.putErr != nil {
+						return tt.putErr
+					}
+					data, _ := io.ReadAll(body)
+					if contentType != "image/png" || size != int64(len(data)) {
+						t.Errorf("Put() contentType = %v, size = %v (read %v)", contentType, size, len(data))
+					}
+					storedKey = key
+					return nil
+				},
+				DeleteFunc: func(ctx context.Context, key string) error {
+					if key == storedKey {
+						key = "new"
+					}
+					deleted = append(deleted, key)
+					return nil
+				},
+			}
+			var saved *domain.Avatar
+			avatarRepo := &MockAvatarRepository{
+				GetFunc: func(ctx context.Context, userID string) (*domain.Avatar, error) {
+					if tt.previous == nil {
+						return nil, domainerrors.ErrAvatarNotFound
+					}
+					return tt.previous, nil
+				},
+				UpsertFunc: func(ctx context.Context, avatar *domain.Avatar) error {
+					saved = avatar
+					return tt.upsertErr
+				},
+			}
+
+			avatar, url, err := newTestAvatarService(avatarRepo, storage).UploadAvatar(context.Background(), 12345, tt.data)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UploadAvatar() error = %v, want %v", err, tt.wantErr)
+			}
+			if strings.Join(deleted, ",") != strings.Join(tt.wantDeleted, ",") {
+				t.Errorf("deleted objects = %v, want %v", deleted, tt.wantDeleted)
+			}
+			if !tt.wantStored {
+				return
+			}
+			if !strings.HasPrefix(storedKey, "avatars/user-123/") || !strings.HasSuffix(storedKey, ".png") {
+				t.Errorf("stored key = %v, want avatars/user-123/<id>.png", storedKey)
+			}
+			if saved == nil || saved.ObjectKey != storedKey || avatar.ContentType != "image/png" || avatar.Size != int64(len(pngImage)) {
+				t.Errorf("saved avatar = %+v, want the stored image", saved)
+			}
+			if !strings.Contains(url, storedKey) {
+				t.Errorf("url = %v, want a signed URL of %v", url, storedKey)
+			}
+		})
+	}
+}
+
+func TestAvatarService_AvatarURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		avatar  *domain.Avatar
+		getErr  error
+		wantURL string
+		wantErr error
+	}{
+		{name: "no avatar", getErr: domainerrors.ErrAvatarNotFound},
+		{name: "signed url", avatar: &domain.Avatar{ObjectKey: "avatars/user-123/a.png"}, wantURL: "https://storage.example.com/avatars/user-123/a.png?signature=abc"},
+		{name: "repository failure", getErr: errors.New("db down"), wantErr: domainerrors.ErrInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			avatarRepo := &MockAvatarRepository{
+				GetFunc: func(ctx context.Context, userID string) (*domain.Avatar, error) {
+					return tt.avatar, tt.getErr
+				},
+			}
+			storage := &MockBlobStorage{
+				SignedURLFunc: func(key string, expiresIn time.Duration) (string, error) {
+					if expiresIn != 15*time.Minute {
+						t.Errorf("SignedURL() expiresIn = %v, want 15m", expiresIn)
+					}
+					return "https://storage.example.com/" + key + "?signature=abc", nil
+				},
+			}
+
+			url, err := newTestAvatarService(avatarRepo, storage).AvatarURL(context.Background(), "user-123")
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AvatarURL() error = %v, want %v", err, tt.wantErr)
+			}
+			if url != tt.wantURL {
+				t.Errorf("AvatarURL() = %v, want %v", url, tt.wantURL)
+			}
+		})
+	}
+}
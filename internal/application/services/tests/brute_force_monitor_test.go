@@ -0,0 +1,65 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestBruteForceMonitor_RecordLoginFailure(t *testing.T) {
+	counts := map[string]int{}
+	failureCounter := &MockRateLimiter{
+		HitFunc: func(ctx context.Context, key string) (*domain.RateLimitStatus, error) {
+			counts[key]++
+			return &domain.RateLimitStatus{Used: counts[key]}, nil
+		},
+	}
+	core, logs := observer.New(zapcore.ErrorLevel)
+	monitor := services.NewBruteForceMonitor(failureCounter, 2, 15*time.Minute, zap.New(core))
+
+	ctx := domain.ContextWithClientInfo(context.Background(), domain.ClientInfo{IP: "198.51.100.1"})
+	for range 4 {
+		monitor.RecordLoginFailure(ctx, "Test@Example.com", services.LoginFailureInvalidPassword)
+	}
+
+	ipKey := domain.LoginFailureIPRateLimitKey("198.51.100.1")
+	emailKey := domain.LoginFailureEmailRateLimitKey("test@example.com")
+	if counts[ipKey] != 4 || counts[emailKey] != 4 {
+		t.Fatalf("counts = %v, want 4 failures for %q and %q", counts, ipKey, emailKey)
+	}
+
+	// The alert fires once per dimension, when the failures first exceed the threshold
+	alerts := logs.FilterMessage("brute force alert threshold exceeded").AllUntimed()
+	if len(alerts) != 2 {
+		t.Fatalf("alerts = %d, want 2", len(alerts))
+	}
+	for i, dimension := range []string{"ip", "email"} {
+		fields := alerts[i].ContextMap()
+		if fields["dimension"] != dimension || fields["severity"] != "high" || fields["failures"] != int64(3) || fields["threshold"] != int64(2) {
+			t.Errorf("alert %d fields = %v, want the %s dimension exceeding the threshold", i, fields, dimension)
+		}
+	}
+}
+
+func TestBruteForceMonitor_AlertsDisabled(t *testing.T) {
+	failureCounter := &MockRateLimiter{
+		HitFunc: func(ctx context.Context, key string) (*domain.RateLimitStatus, error) {
+			return &domain.RateLimitStatus{Used: 1}, nil
+		},
+	}
+	core, logs := observer.New(zapcore.ErrorLevel)
+	monitor := services.NewBruteForceMonitor(failureCounter, 0, 15*time.Minute, zap.New(core))
+
+	monitor.RecordLoginFailure(context.Background(), "test@example.com", services.LoginFailureUnknownUser)
+
+	if logs.Len() != 0 {
+		t.Errorf("logged %d entries, want none with the alerts disabled", logs.Len())
+	}
+}
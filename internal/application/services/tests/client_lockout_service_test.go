@@ -0,0 +1,169 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+var lockoutTestPolicy = services.ClientLockoutPolicy{Threshold: 3, Window: 5 * time.Minute, Cooldown: 15 * time.Minute}
+
+// newCountingRateLimiter returns a rate limiter keeping its counters in memory, with windows of the given length
+func newCountingRateLimiter(window time.Duration) *MockRateLimiter {
+	counts := map[string]int{}
+	resetAt := time.Now().Add(window)
+	return &MockRateLimiter{
+		HitFunc: func(ctx context.Context, key string) (*domain.RateLimitStatus, error) {
+			counts[key]++
+			return &domain.RateLimitStatus{Used: counts[key], ResetAt: resetAt}, nil
+		},
+		PeekFunc: func(ctx context.Context, key string) (*domain.RateLimitStatus, error) {
+			return &domain.RateLimitStatus{Used: counts[key], ResetAt: resetAt}, nil
+		},
+	}
+}
+
+func TestClientLockoutService_LocksOutAfterThreshold(t *testing.T) {
+	core, logs := observer.New(zapcore.ErrorLevel)
+	service := services.NewClientLockoutService(
+		newCountingRateLimiter(lockoutTestPolicy.Window),
+		newCountingRateLimiter(lockoutTestPolicy.Cooldown),
+		lockoutTestPolicy,
+		zap.New(core),
+	)
+	ctx := context.Background()
+
+	for i := 1; i <= 5; i++ {
+		service.RecordAuthFailure(ctx, "client-123")
+
+		err := service.CheckClientLockout(ctx, "client-123")
+		if i < lockoutTestPolicy.Threshold {
+			if err != nil {
+				t.Fatalf("CheckClientLockout() after %d failures error = %v, want nil", i, err)
+			}
+			continue
+		}
+
+		var lockedOutErr *domainerrors.ClientLockedOutError
+		if !errors.As(err, &lockedOutErr) || !errors.Is(err, domainerrors.ErrClientLockedOut) {
+			t.Fatalf("CheckClientLockout() after %d failures error = %v, want ClientLockedOutError", i, err)
+		}
+		if seconds := lockedOutErr.RetryAfterSeconds(); seconds <= 0 || seconds > int64(lockoutTestPolicy.Cooldown.Seconds()) {
+			t.Errorf("RetryAfterSeconds() = %d, want within the cooldown", seconds)
+		}
+	}
+
+	if err := service.CheckClientLockout(ctx, "other-client"); err != nil {
+		t.Errorf("CheckClientLockout() of another client error = %v, want nil", err)
+	}
+
+	// The alert fires once per lockout, not on every failure while locked out
+	alerts := logs.FilterMessage("oauth client locked out after repeated authentication failures").AllUntimed()
+	if len(alerts) != 1 {
+		t.Fatalf("alerts = %d, want 1", len(alerts))
+	}
+	fields := alerts[0].ContextMap()
+	if fields["alert"] != "client_lockout" || fields["severity"] != "high" || fields["client_id"] != "client-123" || fields["failures"] != int64(3) {
+		t.Errorf("alert fields = %v, want a high severity client_lockout alert for client-123", fields)
+	}
+}
+
+func TestClientLockoutService_FailsOpen(t *testing.T) {
+	unavailable := &MockRateLimiter{
+		HitFunc: func(ctx context.Context, key string) (*domain.RateLimitStatus, error) {
+			return nil, errors.New("redis unavailable")
+		},
+		PeekFunc: func(ctx context.Context, key string) (*domain.RateLimitStatus, error) {
+			return nil, errors.New("redis unavailable")
+		},
+	}
+	service := services.NewClientLockoutService(unavailable, unavailable, lockoutTestPolicy, zap.NewNop())
+	ctx := context.Background()
+
+	for range lockoutTestPolicy.Threshold + 1 {
+		service.RecordAuthFailure(ctx, "client-123")
+	}
+	if err := service.CheckClientLockout(ctx, "client-123"); err != nil {
+		t.Errorf("CheckClientLockout() error = %v, want nil when the counters are unavailable", err)
+	}
+	if _, err := service.ListLockouts(ctx, []string{"client-123"}); err == nil {
+		t.Error("ListLockouts() error = nil, want the counter error")
+	}
+}
+
+func TestClientLockoutService_ListLockouts(t *testing.T) {
+	service := services.NewClientLockoutService(
+		newCountingRateLimiter(lockoutTestPolicy.Window),
+		newCountingRateLimiter(lockoutTestPolicy.Cooldown),
+		lockoutTestPolicy,
+		zap.NewNop(),
+	)
+	ctx := context.Background()
+
+	service.RecordAuthFailure(ctx, "failing-client")
+	for range lockoutTestPolicy.Threshold {
+		service.RecordAuthFailure(ctx, "locked-client")
+	}
+
+	lockouts, err := service.ListLockouts(ctx, []string{"failing-client", "locked-client", "healthy-client"})
+	if err != nil {
+		t.Fatalf("ListLockouts() error = %v", err)
+	}
+	if len(lockouts) != 2 {
+		t.Fatalf("ListLockouts() = %v, want the failing and locked out clients only", lockouts)
+	}
+	if failing := lockouts["failing-client"]; failing.RecentFailures != 1 || failing.LockedUntil != nil {
+		t.Errorf("failing-client lockout = %+v, want 1 failure and not locked out", failing)
+	}
+	if locked := lockouts["locked-client"]; locked.RecentFailures != 3 || locked.LockedUntil == nil {
+		t.Errorf("locked-client lockout = %+v, want 3 failures and locked out", locked)
+	}
+}
+
+func TestOAuth2Service_AuthenticateClientLockout(t *testing.T) {
+	client, err := domain.NewOAuthClient("client-123", "secret123", "Test Client", "", []string{"read"})
+	if err != nil {
+		t.Fatalf("NewOAuthClient() error = %v", err)
+	}
+	clientRepo := &MockOAuthClientRepository{
+		GetByClientIDFunc: func(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+			return client, nil
+		},
+	}
+	lockout := services.NewClientLockoutService(
+		newCountingRateLimiter(lockoutTestPolicy.Window),
+		newCountingRateLimiter(lockoutTestPolicy.Cooldown),
+		lockoutTestPolicy,
+		zap.NewNop(),
+	)
+	oauth2Service := services.NewOAuth2Service(clientRepo, &MockScopeRepository{}, "test-secret-key-at-least-32-chars-long", 15*time.Minute, services.TokenSigningPolicy{}, zap.NewNop(), services.WithClientLockout(lockout))
+	ctx := context.Background()
+
+	for range lockoutTestPolicy.Threshold {
+		if _, err := oauth2Service.AuthenticateClient(ctx, "client-123", "wrong-secret"); !errors.Is(err, domainerrors.ErrInvalidCredentials) {
+			t.Fatalf("AuthenticateClient() with a wrong secret error = %v, want %v", err, domainerrors.ErrInvalidCredentials)
+		}
+	}
+
+	// Locked out, even the right secret is rejected until the end of the cooldown
+	if _, err := oauth2Service.AuthenticateClient(ctx, "client-123", "secret123"); !errors.Is(err, domainerrors.ErrClientLockedOut) {
+		t.Errorf("AuthenticateClient() while locked out error = %v, want %v", err, domainerrors.ErrClientLockedOut)
+	}
+
+	lockouts, err := oauth2Service.ListClientLockouts(ctx, []string{"client-123"})
+	if err != nil {
+		t.Fatalf("ListClientLockouts() error = %v", err)
+	}
+	if lockouts["client-123"] == nil || lockouts["client-123"].LockedUntil == nil {
+		t.Errorf("ListClientLockouts() = %v, want client-123 locked out", lockouts)
+	}
+}
@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	"github.com/kristianrpo/auth-microservice/internal/domain/events"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestClientSecretReminder_SendReminders(t *testing.T) {
+	now := time.Now()
+	withExpiry := func(clientID string, expiresIn time.Duration) *domain.OAuthClient {
+		expiresAt := now.Add(expiresIn)
+		return &domain.OAuthClient{ClientID: clientID, Name: clientID, Active: true, Tags: []string{"team:payments"}, SecretExpiresAt: &expiresAt}
+	}
+	clientRepo := &MockOAuthClientRepository{
+		ListFunc: func(ctx context.Context) ([]*domain.OAuthClient, error) {
+			return []*domain.OAuthClient{
+				withExpiry("in-3-days", 3*24*time.Hour),
+				withExpiry("in-30-days", 30*24*time.Hour),
+				withExpiry("expired-yesterday", -24*time.Hour),
+				withExpiry("expired-last-month", -30*24*time.Hour),
+				{ClientID: "never", Active: true},
+			}, nil
+		},
+	}
+
+	published := map[string]*events.ClientSecretExpiringEvent{}
+	publisher := &MockMessagePublisher{
+		PublishFunc: func(ctx context.Context, queueName string, message []byte) error {
+			if queueName != "auth.oauth_client.secret_expiring" {
+				t.Errorf("Publish() queue = %s", queueName)
+			}
+			var event events.ClientSecretExpiringEvent
+			if err := json.Unmarshal(message, &event); err != nil {
+				t.Fatalf("invalid event: %v", err)
+			}
+			published[event.ClientID] = &event
+			return nil
+		},
+	}
+
+	reminder := services.NewClientSecretReminder(clientRepo, publisher, "auth.oauth_client.secret_expiring", services.ClientSecretReminderPolicy{
+		Interval: time.Hour,
+		Window:   7 * 24 * time.Hour,
+	}, zap.NewNop())
+	sent, err := reminder.SendReminders(context.Background())
+	if err != nil {
+		t.Fatalf("SendReminders() error = %v", err)
+	}
+
+	if sent != 2 || len(published) != 2 {
+		t.Fatalf("SendReminders() = %d, published %v, want the reminders of in-3-days and expired-yesterday", sent, published)
+	}
+	if event := published["in-3-days"]; event == nil || event.Expired || len(event.Tags) != 1 {
+		t.Errorf("reminder of in-3-days = %+v, want not expired with the tags of the client", event)
+	}
+	if event := published["expired-yesterday"]; event == nil || !event.Expired {
+		t.Errorf("reminder of expired-yesterday = %+v, want expired", event)
+	}
+}
+
+func TestClientSecretReminder_PublishFailure(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	clientRepo := &MockOAuthClientRepository{
+		ListFunc: func(ctx context.Context) ([]*domain.OAuthClient, error) {
+			return []*domain.OAuthClient{
+				{ClientID: "first", Active: true, SecretExpiresAt: &expiresAt},
+				{ClientID: "second", Active: true, SecretExpiresAt: &expiresAt},
+			}, nil
+		},
+	}
+	attempts := 0
+	publisher := &MockMessagePublisher{
+		PublishFunc: func(ctx context.Context, queueName string, message []byte) error {
+			attempts++
+			if attempts == 1 {
+				return errors.New("broker unavailable")
+			}
+			return nil
+		},
+	}
+
+	reminder := services.NewClientSecretReminder(clientRepo, publisher, "reminders", services.ClientSecretReminderPolicy{Interval: time.Hour, Window: 24 * time.Hour}, zap.NewNop())
+	sent, err := reminder.SendReminders(context.Background())
+	if err != nil {
+		t.Fatalf("SendReminders() error = %v", err)
+	}
+	if sent != 1 || attempts != 2 {
+		t.Errorf("SendReminders() = %d after %d attempts, want the second reminder sent", sent, attempts)
+	}
+}
@@ -0,0 +1,133 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// newTrackingClientTokenRepository returns a client token repository keeping the tracked and revoked
+// tokens in memory
+func newTrackingClientTokenRepository() *MockClientTokenRepository {
+	tracked := map[string][]string{}
+	revoked := map[string]bool{}
+	return &MockClientTokenRepository{
+		TrackFunc: func(ctx context.Context, clientID, tokenID string, expiresAt time.Time) error {
+			tracked[clientID] = append(tracked[clientID], tokenID)
+			return nil
+		},
+		RevokeAllFunc: func(ctx context.Context, clientID string) (int, error) {
+			count := len(tracked[clientID])
+			for _, tokenID := range tracked[clientID] {
+				revoked[clientID+":"+tokenID] = true
+			}
+			delete(tracked, clientID)
+			return count, nil
+		},
+		IsRevokedFunc: func(ctx context.Context, clientID, tokenID string) (bool, error) {
+			return revoked[clientID+":"+tokenID], nil
+		},
+	}
+}
+
+func newClientTokenRevocationService(t *testing.T, clientTokenRepo *MockClientTokenRepository) *services.OAuth2Service {
+	t.Helper()
+
+	client, err := domain.NewOAuthClient("client-123", "secret123", "Test Client", "", []string{"read"})
+	if err != nil {
+		t.Fatalf("NewOAuthClient() error = %v", err)
+	}
+	client.ID = "id-123"
+	clientRepo := &MockOAuthClientRepository{
+		GetByClientIDFunc: func(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+			return client, nil
+		},
+		GetByIDFunc: func(ctx context.Context, id string) (*domain.OAuthClient, error) {
+			if id != client.ID {
+				return nil, domainerrors.ErrClientNotFound
+			}
+			return client, nil
+		},
+	}
+	return services.NewOAuth2Service(clientRepo, &MockScopeRepository{}, "test-secret-key-at-least-32-chars-long", 15*time.Minute, services.TokenSigningPolicy{}, zap.NewNop(), services.WithClientTokenRepository(clientTokenRepo))
+}
+
+func TestOAuth2Service_RevokeClientTokens(t *testing.T) {
+	ctx := context.Background()
+	service := newClientTokenRevocationService(t, newTrackingClientTokenRepository())
+
+	var issued []string
+	for range 2 {
+		token, _, err := service.ClientCredentials(ctx, "client-123", "secret123", nil, "")
+		if err != nil {
+			t.Fatalf("ClientCredentials() unexpected error = %v", err)
+		}
+		issued = append(issued, token)
+	}
+
+	revoked, err := service.RevokeClientTokens(ctx, "id-123")
+	if err != nil {
+		t.Fatalf("RevokeClientTokens() unexpected error = %v", err)
+	}
+	if revoked != 2 {
+		t.Errorf("RevokeClientTokens() = %d, want 2", revoked)
+	}
+
+	for _, token := range issued {
+		if _, err := service.ValidateAccessToken(ctx, token); !errors.Is(err, domainerrors.ErrTokenRevoked) {
+			t.Errorf("ValidateAccessToken() of a revoked token error = %v, want %v", err, domainerrors.ErrTokenRevoked)
+		}
+	}
+
+	// Tokens issued after the revocation are valid
+	token, _, err := service.ClientCredentials(ctx, "client-123", "secret123", nil, "")
+	if err != nil {
+		t.Fatalf("ClientCredentials() unexpected error = %v", err)
+	}
+	if _, err := service.ValidateAccessToken(ctx, token); err != nil {
+		t.Errorf("ValidateAccessToken() of a new token error = %v", err)
+	}
+
+	if _, err := service.RevokeClientTokens(ctx, "unknown"); !errors.Is(err, domainerrors.ErrClientNotFound) {
+		t.Errorf("RevokeClientTokens() of an unknown client error = %v, want %v", err, domainerrors.ErrClientNotFound)
+	}
+}
+
+func TestOAuth2Service_ClientTokenTrackingFailures(t *testing.T) {
+	ctx := context.Background()
+	storeErr := errors.New("connection refused")
+
+	untracked := newClientTokenRevocationService(t, &MockClientTokenRepository{
+		TrackFunc: func(ctx context.Context, clientID, tokenID string, expiresAt time.Time) error {
+			return storeErr
+		},
+	})
+	if _, _, err := untracked.ClientCredentials(ctx, "client-123", "secret123", nil, ""); !errors.Is(err, domainerrors.ErrInternal) {
+		t.Errorf("ClientCredentials() with a failing tracking error = %v, want %v", err, domainerrors.ErrInternal)
+	}
+
+	unchecked := newClientTokenRevocationService(t, &MockClientTokenRepository{
+		IsRevokedFunc: func(ctx context.Context, clientID, tokenID string) (bool, error) {
+			return false, storeErr
+		},
+	})
+	token, _, err := unchecked.ClientCredentials(ctx, "client-123", "secret123", nil, "")
+	if err != nil {
+		t.Fatalf("ClientCredentials() unexpected error = %v", err)
+	}
+	if _, err := unchecked.ValidateAccessToken(ctx, token); !errors.Is(err, domainerrors.ErrInternal) {
+		t.Errorf("ValidateAccessToken() with a failing revocation check error = %v, want %v", err, domainerrors.ErrInternal)
+	}
+
+	disabled := services.NewOAuth2Service(&MockOAuthClientRepository{}, &MockScopeRepository{}, "test-secret-key-at-least-32-chars-long", 15*time.Minute, services.TokenSigningPolicy{}, zap.NewNop())
+	if _, err := disabled.RevokeClientTokens(ctx, "id-123"); !errors.Is(err, domainerrors.ErrInternal) {
+		t.Errorf("RevokeClientTokens() without tracking error = %v, want %v", err, domainerrors.ErrInternal)
+	}
+}
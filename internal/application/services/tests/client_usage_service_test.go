@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+var clientUsageTestPolicy = services.ClientUsagePolicy{FlushInterval: time.Minute, ErrorWindow: 24 * time.Hour}
+
+func TestClientUsageService_Flush(t *testing.T) {
+	deltas := []*domain.ClientUsageDelta{
+		{ClientID: "client-a", TokensIssued: 3, LastUsedAt: time.Now()},
+		{ClientID: "client-b", Errors: 2},
+		{ClientID: "client-c", TokensIssued: 1, LastUsedAt: time.Now()},
+	}
+	counter := &MockClientUsageCounter{
+		DrainFunc: func(ctx context.Context) ([]*domain.ClientUsageDelta, error) {
+			return deltas, nil
+		},
+	}
+	var added []string
+	repo := &MockClientUsageRepository{
+		AddFunc: func(ctx context.Context, delta *domain.ClientUsageDelta, errorWindow time.Duration) error {
+			if errorWindow != clientUsageTestPolicy.ErrorWindow {
+				t.Errorf("Add() errorWindow = %v, want %v", errorWindow, clientUsageTestPolicy.ErrorWindow)
+			}
+			if delta.ClientID == "client-b" {
+				return errors.New("db down")
+			}
+			added = append(added, delta.ClientID)
+			return nil
+		},
+	}
+
+	service := services.NewClientUsageService(counter, repo, clientUsageTestPolicy, zap.NewNop())
+	flushed, err := service.Flush(context.Background())
+	if err == nil {
+		t.Error("Flush() error = nil, want the failed addition")
+	}
+	if flushed != 2 || len(added) != 2 || added[0] != "client-a" || added[1] != "client-c" {
+		t.Errorf("Flush() = %d, added %v, want client-a and client-c flushed", flushed, added)
+	}
+}
+
+func TestOAuth2Service_RecordsClientUsage(t *testing.T) {
+	tests := []struct {
+		name         string
+		clientID     string
+		clientSecret string
+		wantIssued   []string
+		wantErrors   []string
+	}{
+		{name: "token issued", clientID: "client-123", clientSecret: "secret123", wantIssued: []string{"client-123"}},
+		{name: "invalid secret", clientID: "client-123", clientSecret: "wrong", wantErrors: []string{"client-123"}},
+		{name: "unknown client", clientID: "unknown", clientSecret: "secret123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientRepo := &MockOAuthClientRepository{
+				GetByClientIDFunc: func(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+					if clientID != "client-123" {
+						return nil, errors.New("not found")
+					}
+					return domain.NewOAuthClient("client-123", "secret123", "Test Client", "Test Description", []string{"read"})
+				},
+			}
+			var issued, failed []string
+			counter := &MockClientUsageCounter{
+				RecordIssuanceFunc: func(ctx context.Context, clientID string, at time.Time) error {
+					issued = append(issued, clientID)
+					return nil
+				},
+				RecordErrorFunc: func(ctx context.Context, clientID string) error {
+					failed = append(failed, clientID)
+					return nil
+				},
+			}
+			usage := services.NewClientUsageService(counter, &MockClientUsageRepository{}, clientUsageTestPolicy, zap.NewNop())
+			oauth2Service := services.NewOAuth2Service(clientRepo, &MockScopeRepository{}, "test-secret-key-at-least-32-chars-long", 15*time.Minute, services.TokenSigningPolicy{}, zap.NewNop(), services.WithClientUsageTracker(usage))
+
+			_, _, _ = oauth2Service.ClientCredentials(context.Background(), tt.clientID, tt.clientSecret, nil, "")
+
+			if len(issued) != len(tt.wantIssued) || len(failed) != len(tt.wantErrors) {
+				t.Fatalf("issued = %v, errors = %v, want %v and %v", issued, failed, tt.wantIssued, tt.wantErrors)
+			}
+			for i := range issued {
+				if issued[i] != tt.wantIssued[i] {
+					t.Errorf("issued = %v, want %v", issued, tt.wantIssued)
+				}
+			}
+			for i := range failed {
+				if failed[i] != tt.wantErrors[i] {
+					t.Errorf("errors = %v, want %v", failed, tt.wantErrors)
+				}
+			}
+		})
+	}
+}
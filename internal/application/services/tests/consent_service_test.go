@@ -0,0 +1,222 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func newTestConsentUserRepository() *MockUserRepository {
+	return &MockUserRepository{
+		GetByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+			return newTestUser(), nil
+		},
+	}
+}
+
+func TestConsentService_ListConsents(t *testing.T) {
+	logger := zap.NewNop()
+
+	tests := []struct {
+		name      string
+		userRepo  *MockUserRepository
+		listFunc  func(ctx context.Context, userID string) ([]*domain.Consent, error)
+		wantCount int
+		wantErr   error
+	}{
+		{
+			name:     "lists consents",
+			userRepo: newTestConsentUserRepository(),
+			listFunc: func(ctx context.Context, userID string) ([]*domain.Consent, error) {
+				return []*domain.Consent{{UserID: userID, ClientID: "cli", Scopes: []string{"read"}}}, nil
+			},
+			wantCount: 1,
+		},
+		{
+			name:     "user not found",
+			userRepo: &MockUserRepository{},
+			wantErr:  domainerrors.ErrUserNotFound,
+		},
+		{
+			name:     "repository error",
+			userRepo: newTestConsentUserRepository(),
+			listFunc: func(ctx context.Context, userID string) ([]*domain.Consent, error) {
+				return nil, errors.New("db down")
+			},
+			wantErr: domainerrors.ErrInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			consentRepo := &MockConsentRepository{ListByUserFunc: tt.listFunc}
+			service := services.NewConsentService(tt.userRepo, consentRepo, logger)
+
+			consents, err := service.ListConsents(context.Background(), 12345)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ListConsents() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ListConsents() unexpected error = %v", err)
+			}
+			if len(consents) != tt.wantCount {
+				t.Errorf("ListConsents() returned %d consents, want %d", len(consents), tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestConsentService_RevokeConsent(t *testing.T) {
+	logger := zap.NewNop()
+
+	tests := []struct {
+		name      string
+		deleteErr error
+		wantErr   error
+	}{
+		{name: "revokes consent"},
+		{name: "consent not found", deleteErr: domainerrors.ErrConsentNotFound, wantErr: domainerrors.ErrConsentNotFound},
+		{name: "repository error", deleteErr: errors.New("db down"), wantErr: domainerrors.ErrInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			consentRepo := &MockConsentRepository{
+				DeleteFunc: func(ctx context.Context, userID, clientID string) error {
+					if userID != "user-123" || clientID != "cli" {
+						t.Errorf("Delete() userID = %v, clientID = %v", userID, clientID)
+					}
+					return tt.deleteErr
+				},
+			}
+			service := services.NewConsentService(newTestConsentUserRepository(), consentRepo, logger)
+
+			err := service.RevokeConsent(context.Background(), 12345, "cli")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("RevokeConsent() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConsentService_RequiresConsent(t *testing.T) {
+	logger := zap.NewNop()
+
+	tests := []struct {
+		name    string
+		getFunc func(ctx context.Context, userID, clientID string) (*domain.Consent, error)
+		scopes  []string
+		want    bool
+		wantErr error
+	}{
+		{name: "no previous consent", scopes: []string{"read"}, want: true},
+		{
+			name: "scopes unchanged",
+			getFunc: func(ctx context.Context, userID, clientID string) (*domain.Consent, error) {
+				return &domain.Consent{UserID: userID, ClientID: clientID, Scopes: []string{"read", "write"}}, nil
+			},
+			scopes: []string{"read"},
+			want:   false,
+		},
+		{
+			name: "new scope requested",
+			getFunc: func(ctx context.Context, userID, clientID string) (*domain.Consent, error) {
+				return &domain.Consent{UserID: userID, ClientID: clientID, Scopes: []string{"read"}}, nil
+			},
+			scopes: []string{"read", "write"},
+			want:   true,
+		},
+		{
+			name: "repository error",
+			getFunc: func(ctx context.Context, userID, clientID string) (*domain.Consent, error) {
+				return nil, errors.New("db down")
+			},
+			scopes:  []string{"read"},
+			wantErr: domainerrors.ErrInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			consentRepo := &MockConsentRepository{GetFunc: tt.getFunc}
+			service := services.NewConsentService(newTestConsentUserRepository(), consentRepo, logger)
+
+			got, err := service.RequiresConsent(context.Background(), 12345, "cli", tt.scopes)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("RequiresConsent() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RequiresConsent() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RequiresConsent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConsentService_RecordConsent(t *testing.T) {
+	logger := zap.NewNop()
+
+	tests := []struct {
+		name       string
+		getFunc    func(ctx context.Context, userID, clientID string) (*domain.Consent, error)
+		upsertErr  error
+		wantScopes []string
+		wantErr    error
+	}{
+		{name: "first consent", wantScopes: []string{"write"}},
+		{
+			name: "merges with previous consent",
+			getFunc: func(ctx context.Context, userID, clientID string) (*domain.Consent, error) {
+				return &domain.Consent{UserID: userID, ClientID: clientID, Scopes: []string{"read"}}, nil
+			},
+			wantScopes: []string{"read", "write"},
+		},
+		{name: "upsert error", upsertErr: errors.New("db down"), wantErr: domainerrors.ErrInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved *domain.Consent
+			consentRepo := &MockConsentRepository{
+				GetFunc: tt.getFunc,
+				UpsertFunc: func(ctx context.Context, consent *domain.Consent) error {
+					saved = consent
+					return tt.upsertErr
+				},
+			}
+			service := services.NewConsentService(newTestConsentUserRepository(), consentRepo, logger)
+
+			err := service.RecordConsent(context.Background(), 12345, "cli", []string{"write"})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("RecordConsent() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RecordConsent() unexpected error = %v", err)
+			}
+			if saved == nil || saved.UserID != "user-123" || saved.ClientID != "cli" {
+				t.Fatalf("Upsert() consent = %+v", saved)
+			}
+			if !slices.Equal(saved.Scopes, tt.wantScopes) {
+				t.Errorf("Scopes = %v, want %v", saved.Scopes, tt.wantScopes)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestDependencyManager_WaitForStartup(t *testing.T) {
+	policy := services.DependencyStartupPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     2 * time.Millisecond,
+		CheckTimeout:   time.Second,
+	}
+	down := func(ctx context.Context) error { return errors.New("connection refused") }
+	up := func(ctx context.Context) error { return nil }
+
+	tests := []struct {
+		name         string
+		dependencies []services.Dependency
+		wantErr      bool
+		wantHealthy  []bool
+	}{
+		{
+			name: "all dependencies available",
+			dependencies: []services.Dependency{
+				{Name: "database", Criticality: domain.DependencyRequired, Check: up},
+				{Name: "rabbitmq", Criticality: domain.DependencyDegradedOK, Check: up},
+			},
+			wantHealthy: []bool{true, true},
+		},
+		{
+			name: "degraded-ok dependency down",
+			dependencies: []services.Dependency{
+				{Name: "database", Criticality: domain.DependencyRequired, Check: up},
+				{Name: "rabbitmq", Criticality: domain.DependencyDegradedOK, Check: down},
+			},
+			wantHealthy: []bool{true, false},
+		},
+		{
+			name: "required dependency down",
+			dependencies: []services.Dependency{
+				{Name: "database", Criticality: domain.DependencyRequired, Check: down},
+				{Name: "rabbitmq", Criticality: domain.DependencyDegradedOK, Check: up},
+			},
+			wantErr:     true,
+			wantHealthy: []bool{false, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := services.NewDependencyManager(policy, zap.NewNop(), tt.dependencies...)
+			statuses, err := manager.WaitForStartup(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WaitForStartup() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for i, status := range statuses {
+				if status.Name != tt.dependencies[i].Name {
+					t.Errorf("statuses[%d].Name = %v, want %v", i, status.Name, tt.dependencies[i].Name)
+				}
+				if status.Healthy != tt.wantHealthy[i] {
+					t.Errorf("%s Healthy = %v, want %v", status.Name, status.Healthy, tt.wantHealthy[i])
+				}
+				if !status.Healthy && status.Attempts != policy.MaxAttempts {
+					t.Errorf("%s Attempts = %d, want %d", status.Name, status.Attempts, policy.MaxAttempts)
+				}
+			}
+		})
+	}
+}
+
+func TestDependencyManager_WaitForStartup_RetriesUntilAvailable(t *testing.T) {
+	calls := 0
+	manager := services.NewDependencyManager(services.DependencyStartupPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+	}, zap.NewNop(), services.Dependency{
+		Name:        "redis",
+		Criticality: domain.DependencyRequired,
+		Check: func(ctx context.Context) error {
+			calls++
+			if calls < 3 {
+				return errors.New("loading dataset")
+			}
+			return nil
+		},
+	})
+
+	statuses, err := manager.WaitForStartup(context.Background())
+	if err != nil {
+		t.Fatalf("WaitForStartup() error = %v", err)
+	}
+	if statuses[0].Attempts != 3 || !statuses[0].Healthy {
+		t.Errorf("status = %+v, want healthy after 3 attempts", statuses[0])
+	}
+}
+
+func TestDependencyManager_CheckDependencies_AppliesTimeout(t *testing.T) {
+	manager := services.NewDependencyManager(services.DependencyStartupPolicy{
+		MaxAttempts:  3,
+		CheckTimeout: 10 * time.Millisecond,
+	}, zap.NewNop(), services.Dependency{
+		Name:        "database",
+		Criticality: domain.DependencyRequired,
+		Check: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	})
+
+	statuses := manager.CheckDependencies(context.Background())
+	if statuses[0].Healthy || statuses[0].Attempts != 1 {
+		t.Errorf("status = %+v, want a single failed attempt", statuses[0])
+	}
+}
@@ -0,0 +1,400 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// deviceClientSecretHash is the hash of the secret of the device clients, "secret"
+var deviceClientSecretHash = func() string {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		panic(err)
+	}
+	return string(hash)
+}()
+
+func newTestDeviceAuthorizationService(clientRepo *MockOAuthClientRepository, deviceRepo *MockDeviceAuthorizationRepository, userRepo *MockUserRepository, consentRepo *MockConsentRepository) *services.DeviceAuthorizationService {
+	logger := zap.NewNop()
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, logger)
+	authService := services.NewAuthService(userRepo, &MockTokenRepository{}, jwtService, &MockPasswordHasher{}, logger)
+	consentService := services.NewConsentService(userRepo, consentRepo, logger)
+	oauth2Service := services.NewOAuth2Service(clientRepo, &MockScopeRepository{}, "test-secret-key-at-least-32-chars-long", 15*time.Minute, services.TokenSigningPolicy{}, logger)
+	return services.NewDeviceAuthorizationService(oauth2Service, deviceRepo, authService, consentService, 10*time.Minute, 5*time.Second, "https://auth.example.com/device", logger)
+}
+
+func TestDeviceAuthorizationService_RequestDeviceCode(t *testing.T) {
+	clients := map[string]*domain.OAuthClient{
+		"cli":      {ClientID: "cli", ClientSecret: deviceClientSecretHash, Scopes: []string{"read", "write"}, Active: true},
+		"cc-only":  {ClientID: "cc-only", ClientSecret: deviceClientSecretHash, Scopes: []string{"read"}, Active: true, GrantTypes: []string{domain.GrantTypeClientCredentials}},
+		"inactive": {ClientID: "inactive", ClientSecret: deviceClientSecretHash, Scopes: []string{"read"}},
+	}
+
+	tests := []struct {
+		name        string
+		clientID    string
+		secret      string
+		scopes      []string
+		storeErr    error
+		wantScopes  []string
+		expectedErr error
+	}{
+		{name: "defaults to client scopes", clientID: "cli", wantScopes: []string{"read", "write"}},
+		{name: "subset of client scopes", clientID: "cli", scopes: []string{"read"}, wantScopes: []string{"read"}},
+		{name: "scope not granted to client", clientID: "cli", scopes: []string{"admin"}, expectedErr: domainerrors.ErrBadRequest},
+		{name: "unknown client", clientID: "unknown", expectedErr: domainerrors.ErrInvalidCredentials},
+		{name: "invalid client secret", clientID: "cli", secret: "wrong", expectedErr: domainerrors.ErrInvalidCredentials},
+		{name: "inactive client", clientID: "inactive", expectedErr: domainerrors.ErrInvalidClient},
+		{name: "grant not allowed to client", clientID: "cc-only", expectedErr: domainerrors.ErrUnauthorizedClient},
+		{name: "store error", clientID: "cli", storeErr: errors.New("redis down"), expectedErr: domainerrors.ErrInternal},
+	}
+
+	userCodePattern := regexp.MustCompile(`^[BCDFGHJKLMNPQRSTVWXZ]{4}-[BCDFGHJKLMNPQRSTVWXZ]{4}$`)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientRepo := &MockOAuthClientRepository{
+				GetByClientIDFunc: func(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+					if client, ok := clients[clientID]; ok {
+						return client, nil
+					}
+					return nil, domainerrors.ErrClientNotFound
+				},
+			}
+			var stored *domain.DeviceAuthorization
+			deviceRepo := &MockDeviceAuthorizationRepository{
+				StoreFunc: func(ctx context.Context, auth *domain.DeviceAuthorization) error {
+					stored = auth
+					return tt.storeErr
+				},
+			}
+
+			service := newTestDeviceAuthorizationService(clientRepo, deviceRepo, &MockUserRepository{}, &MockConsentRepository{})
+			secret := tt.secret
+			if secret == "" {
+				secret = "secret"
+			}
+			auth, err := service.RequestDeviceCode(context.Background(), tt.clientID, secret, tt.scopes)
+
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("RequestDeviceCode() error = %v, want %v", err, tt.expectedErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RequestDeviceCode() unexpected error = %v", err)
+			}
+
+			if stored != auth {
+				t.Error("RequestDeviceCode() did not store the authorization")
+			}
+			if auth.Status != domain.DeviceAuthorizationPending {
+				t.Errorf("Status = %v, want pending", auth.Status)
+			}
+			if !userCodePattern.MatchString(auth.UserCode) {
+				t.Errorf("UserCode = %q, want XXXX-XXXX format", auth.UserCode)
+			}
+			if len(auth.DeviceCode) < 32 {
+				t.Errorf("DeviceCode = %q, want a high-entropy code", auth.DeviceCode)
+			}
+			if auth.Interval != 5*time.Second {
+				t.Errorf("Interval = %v, want 5s", auth.Interval)
+			}
+			if len(auth.Scopes) != len(tt.wantScopes) {
+				t.Errorf("Scopes = %v, want %v", auth.Scopes, tt.wantScopes)
+			}
+		})
+	}
+}
+
+func TestDeviceAuthorizationService_Verify(t *testing.T) {
+	tests := []struct {
+		name        string
+		userCode    string
+		approve     bool
+		status      domain.DeviceAuthorizationStatus
+		expiresAt   time.Time
+		wantStatus  domain.DeviceAuthorizationStatus
+		expectedErr error
+	}{
+		{name: "approve", userCode: "BCDF-GHJK", approve: true, status: domain.DeviceAuthorizationPending, expiresAt: time.Now().Add(time.Minute), wantStatus: domain.DeviceAuthorizationApproved},
+		{name: "deny", userCode: "BCDF-GHJK", approve: false, status: domain.DeviceAuthorizationPending, expiresAt: time.Now().Add(time.Minute), wantStatus: domain.DeviceAuthorizationDenied},
+		{name: "user code is normalized", userCode: "bcdfghjk", approve: true, status: domain.DeviceAuthorizationPending, expiresAt: time.Now().Add(time.Minute), wantStatus: domain.DeviceAuthorizationApproved},
+		{name: "already decided", userCode: "BCDF-GHJK", approve: true, status: domain.DeviceAuthorizationDenied, expiresAt: time.Now().Add(time.Minute), expectedErr: domainerrors.ErrInvalidUserCode},
+		{name: "expired", userCode: "BCDF-GHJK", approve: true, status: domain.DeviceAuthorizationPending, expiresAt: time.Now().Add(-time.Minute), expectedErr: domainerrors.ErrInvalidUserCode},
+		{name: "unknown user code", userCode: "ZZZZ-ZZZZ", approve: true, expectedErr: domainerrors.ErrInvalidUserCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth := &domain.DeviceAuthorization{DeviceCode: "device-code", UserCode: "BCDF-GHJK", ClientID: "cli", Status: tt.status, ExpiresAt: tt.expiresAt}
+			var updated *domain.DeviceAuthorization
+			deviceRepo := &MockDeviceAuthorizationRepository{
+				GetByUserCodeFunc: func(ctx context.Context, userCode string) (*domain.DeviceAuthorization, error) {
+					if userCode != auth.UserCode {
+						return nil, domainerrors.ErrInvalidUserCode
+					}
+					return auth, nil
+				},
+				UpdateFunc: func(ctx context.Context, a *domain.DeviceAuthorization) error {
+					updated = a
+					return nil
+				},
+			}
+
+			userRepo := &MockUserRepository{
+				GetByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+					return newTestUser(), nil
+				},
+			}
+			var recorded *domain.Consent
+			consentRepo := &MockConsentRepository{
+				UpsertFunc: func(ctx context.Context, consent *domain.Consent) error {
+					recorded = consent
+					return nil
+				},
+			}
+
+			service := newTestDeviceAuthorizationService(&MockOAuthClientRepository{}, deviceRepo, userRepo, consentRepo)
+			err := service.Verify(context.Background(), tt.userCode, 12345, tt.approve)
+
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("Verify() error = %v, want %v", err, tt.expectedErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify() unexpected error = %v", err)
+			}
+
+			if updated == nil || updated.Status != tt.wantStatus {
+				t.Fatalf("Verify() updated = %+v, want status %v", updated, tt.wantStatus)
+			}
+			if tt.approve && updated.IDCitizen != 12345 {
+				t.Errorf("IDCitizen = %v, want 12345", updated.IDCitizen)
+			}
+			if tt.approve != (recorded != nil) {
+				t.Errorf("consent recorded = %v, want %v", recorded != nil, tt.approve)
+			}
+			if recorded != nil && recorded.ClientID != "cli" {
+				t.Errorf("consent ClientID = %v, want cli", recorded.ClientID)
+			}
+		})
+	}
+}
+
+func TestDeviceAuthorizationService_PollToken(t *testing.T) {
+	tests := []struct {
+		name         string
+		clientID     string
+		status       domain.DeviceAuthorizationStatus
+		lastPolledAt time.Time
+		expiresAt    time.Time
+		wantDeleted  bool
+		grantTypes   []string
+		secret       string
+		inactive     bool
+		wantInterval time.Duration
+		expectedErr  error
+	}{
+		{name: "pending", clientID: "cli", status: domain.DeviceAuthorizationPending, expiresAt: time.Now().Add(time.Minute), wantInterval: 5 * time.Second, expectedErr: domainerrors.ErrAuthorizationPending},
+		{name: "polled too soon", clientID: "cli", status: domain.DeviceAuthorizationPending, lastPolledAt: time.Now(), expiresAt: time.Now().Add(time.Minute), wantInterval: 10 * time.Second, expectedErr: domainerrors.ErrSlowDown},
+		{name: "denied", clientID: "cli", status: domain.DeviceAuthorizationDenied, expiresAt: time.Now().Add(time.Minute), wantDeleted: true, expectedErr: domainerrors.ErrAccessDenied},
+		{name: "expired", clientID: "cli", status: domain.DeviceAuthorizationPending, expiresAt: time.Now().Add(-time.Minute), expectedErr: domainerrors.ErrDeviceCodeExpired},
+		{name: "different client", clientID: "other", status: domain.DeviceAuthorizationApproved, expiresAt: time.Now().Add(time.Minute), expectedErr: domainerrors.ErrInvalidClient},
+		{name: "approved", clientID: "cli", status: domain.DeviceAuthorizationApproved, expiresAt: time.Now().Add(time.Minute), wantDeleted: true},
+		{name: "invalid client secret", clientID: "cli", status: domain.DeviceAuthorizationApproved, expiresAt: time.Now().Add(time.Minute), secret: "wrong", expectedErr: domainerrors.ErrInvalidCredentials},
+		{name: "client deactivated", clientID: "cli", status: domain.DeviceAuthorizationApproved, expiresAt: time.Now().Add(time.Minute), inactive: true, expectedErr: domainerrors.ErrInvalidClient},
+		{name: "grant no longer allowed", clientID: "cli", status: domain.DeviceAuthorizationApproved, expiresAt: time.Now().Add(time.Minute), grantTypes: []string{domain.GrantTypeClientCredentials}, expectedErr: domainerrors.ErrUnauthorizedClient},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth := &domain.DeviceAuthorization{
+				DeviceCode:   "device-code",
+				UserCode:     "BCDF-GHJK",
+				ClientID:     "cli",
+				Status:       tt.status,
+				IDCitizen:    12345,
+				Interval:     5 * time.Second,
+				LastPolledAt: tt.lastPolledAt,
+				ExpiresAt:    tt.expiresAt,
+			}
+			deleted := false
+			deviceRepo := &MockDeviceAuthorizationRepository{
+				GetByDeviceCodeFunc: func(ctx context.Context, deviceCode string) (*domain.DeviceAuthorization, error) {
+					return auth, nil
+				},
+				ConsumeFunc: func(ctx context.Context, deviceCode string) (*domain.DeviceAuthorization, error) {
+					if deleted {
+						return nil, domainerrors.ErrDeviceCodeExpired
+					}
+					deleted = true
+					return auth, nil
+				},
+				RecordPollFunc: func(ctx context.Context, deviceCode string, polledAt time.Time, interval time.Duration) error {
+					auth.Interval = interval
+					auth.LastPolledAt = polledAt
+					return nil
+				},
+				DeleteFunc: func(ctx context.Context, a *domain.DeviceAuthorization) error {
+					deleted = true
+					return nil
+				},
+			}
+			userRepo := &MockUserRepository{
+				GetByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+					return newTestUser(), nil
+				},
+			}
+
+			clientRepo := &MockOAuthClientRepository{
+				GetByClientIDFunc: func(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+					return &domain.OAuthClient{ClientID: clientID, ClientSecret: deviceClientSecretHash, Active: !tt.inactive, GrantTypes: tt.grantTypes}, nil
+				},
+			}
+
+			service := newTestDeviceAuthorizationService(clientRepo, deviceRepo, userRepo, &MockConsentRepository{})
+			secret := tt.secret
+			if secret == "" {
+				secret = "secret"
+			}
+			tokenPair, err := service.PollToken(context.Background(), tt.clientID, secret, "device-code")
+
+			if deleted != tt.wantDeleted {
+				t.Errorf("deleted = %v, want %v", deleted, tt.wantDeleted)
+			}
+			if tt.wantInterval != 0 && auth.Interval != tt.wantInterval {
+				t.Errorf("Interval = %v, want %v", auth.Interval, tt.wantInterval)
+			}
+
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("PollToken() error = %v, want %v", err, tt.expectedErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PollToken() unexpected error = %v", err)
+			}
+			if tokenPair == nil || tokenPair.AccessToken == "" || tokenPair.RefreshToken == "" {
+				t.Errorf("PollToken() tokenPair = %+v, want issued tokens", tokenPair)
+			}
+		})
+	}
+}
+
+func TestDeviceAuthorizationService_PollToken_ApprovedCodeIssuesTokensOnce(t *testing.T) {
+	auth := &domain.DeviceAuthorization{
+		DeviceCode: "device-code",
+		UserCode:   "BCDF-GHJK",
+		ClientID:   "cli",
+		Status:     domain.DeviceAuthorizationApproved,
+		IDCitizen:  12345,
+		Interval:   5 * time.Second,
+		ExpiresAt:  time.Now().Add(time.Minute),
+	}
+	consumed := false
+	deviceRepo := &MockDeviceAuthorizationRepository{
+		// Both polls read the approved code before either of them consumes it
+		GetByDeviceCodeFunc: func(ctx context.Context, deviceCode string) (*domain.DeviceAuthorization, error) {
+			copied := *auth
+			return &copied, nil
+		},
+		ConsumeFunc: func(ctx context.Context, deviceCode string) (*domain.DeviceAuthorization, error) {
+			if consumed {
+				return nil, domainerrors.ErrDeviceCodeExpired
+			}
+			consumed = true
+			return auth, nil
+		},
+	}
+	userRepo := &MockUserRepository{
+		GetByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+			return newTestUser(), nil
+		},
+	}
+	clientRepo := &MockOAuthClientRepository{
+		GetByClientIDFunc: func(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+			return &domain.OAuthClient{ClientID: clientID, ClientSecret: deviceClientSecretHash, Active: true}, nil
+		},
+	}
+	service := newTestDeviceAuthorizationService(clientRepo, deviceRepo, userRepo, &MockConsentRepository{})
+
+	if _, err := service.PollToken(context.Background(), "cli", "secret", "device-code"); err != nil {
+		t.Fatalf("first PollToken() unexpected error = %v", err)
+	}
+	tokenPair, err := service.PollToken(context.Background(), "cli", "secret", "device-code")
+	if !errors.Is(err, domainerrors.ErrDeviceCodeExpired) {
+		t.Errorf("second PollToken() error = %v, want %v", err, domainerrors.ErrDeviceCodeExpired)
+	}
+	if tokenPair != nil {
+		t.Errorf("second PollToken() tokenPair = %+v, want nil", tokenPair)
+	}
+}
+
+func TestDeviceAuthorizationService_PollToken_LimitsTokensToApprovedScopes(t *testing.T) {
+	for _, profile := range []domain.TokenProfile{domain.TokenProfileStandard, domain.TokenProfileMinimal} {
+		t.Run(string(profile), func(t *testing.T) {
+			deviceRepo := &MockDeviceAuthorizationRepository{
+				GetByDeviceCodeFunc: func(ctx context.Context, deviceCode string) (*domain.DeviceAuthorization, error) {
+					return &domain.DeviceAuthorization{ClientID: "cli", Status: domain.DeviceAuthorizationApproved, ExpiresAt: time.Now().Add(time.Minute)}, nil
+				},
+				ConsumeFunc: func(ctx context.Context, deviceCode string) (*domain.DeviceAuthorization, error) {
+					return &domain.DeviceAuthorization{ClientID: "cli", Scopes: []string{"profile"}, Status: domain.DeviceAuthorizationApproved, IDCitizen: 12345}, nil
+				},
+			}
+			userRepo := &MockUserRepository{
+				GetByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+					return newTestUser(), nil
+				},
+			}
+			clientRepo := &MockOAuthClientRepository{
+				GetByClientIDFunc: func(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+					return &domain.OAuthClient{ClientID: clientID, ClientSecret: deviceClientSecretHash, Active: true, Scopes: []string{"profile", "documents"}, TokenProfile: profile}, nil
+				},
+			}
+			service := newTestDeviceAuthorizationService(clientRepo, deviceRepo, userRepo, &MockConsentRepository{})
+
+			tokenPair, err := service.PollToken(context.Background(), "cli", "secret", "device-code")
+			if err != nil {
+				t.Fatalf("PollToken() unexpected error = %v", err)
+			}
+
+			jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, zap.NewNop())
+			claims, err := jwtService.ValidateAccessToken(tokenPair.AccessToken)
+			if err != nil {
+				t.Fatalf("ValidateAccessToken() unexpected error = %v", err)
+			}
+			if claims.ClientID != "cli" || len(claims.Scopes) != 1 || claims.Scopes[0] != "profile" {
+				t.Errorf("claims = %+v, want limited to the profile scope approved for cli", claims)
+			}
+		})
+	}
+}
+
+func TestDeviceAuthorizationService_PollToken_UnknownDeviceCode(t *testing.T) {
+	clientRepo := &MockOAuthClientRepository{
+		GetByClientIDFunc: func(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+			return &domain.OAuthClient{ClientID: clientID, ClientSecret: deviceClientSecretHash, Active: true}, nil
+		},
+	}
+	service := newTestDeviceAuthorizationService(clientRepo, &MockDeviceAuthorizationRepository{}, &MockUserRepository{}, &MockConsentRepository{})
+
+	_, err := service.PollToken(context.Background(), "cli", "secret", "unknown")
+	if !errors.Is(err, domainerrors.ErrDeviceCodeExpired) {
+		t.Errorf("PollToken() error = %v, want %v", err, domainerrors.ErrDeviceCodeExpired)
+	}
+}
@@ -0,0 +1,234 @@
+package tests
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestDurableTokenRepository_StoreRefreshToken(t *testing.T) {
+	data := &domain.RefreshTokenData{IDCitizen: 12345, UserID: "user-123"}
+
+	tests := []struct {
+		name       string
+		storeErr   error
+		cacheErr   error
+		wantErr    bool
+		wantCached bool
+	}{
+		{name: "stored and cached", wantCached: true},
+		{name: "cache failure", cacheErr: errors.New("redis down"), wantCached: true},
+		{name: "store failure", storeErr: errors.New("db down"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var storedHash string
+			store := &MockRefreshTokenStore{
+				StoreFunc: func(ctx context.Context, tokenHash string, d *domain.RefreshTokenData, expiresAt time.Time) error {
+					storedHash = tokenHash
+					if time.Until(expiresAt) <= 0 {
+						t.Errorf("Store() expiresAt = %v, want in the future", expiresAt)
+					}
+					return tt.storeErr
+				},
+			}
+			cached := false
+			cache := &MockTokenRepository{
+				StoreRefreshTokenFunc: func(ctx context.Context, token string, d *domain.RefreshTokenData, ttl time.Duration) error {
+					cached = true
+					return tt.cacheErr
+				},
+			}
+
+			repo := services.NewDurableTokenRepository(cache, store, zap.NewNop())
+			err := repo.StoreRefreshToken(context.Background(), "refresh-token", data, time.Hour)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("StoreRefreshToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if storedHash != hashToken("refresh-token") {
+				t.Errorf("stored hash = %q, want the SHA-256 of the token", storedHash)
+			}
+			if cached != tt.wantCached {
+				t.Errorf("cached = %v, want %v", cached, tt.wantCached)
+			}
+		})
+	}
+}
+
+func TestDurableTokenRepository_GetActiveRefreshToken(t *testing.T) {
+	data := &domain.RefreshTokenData{IDCitizen: 12345, UserID: "user-123"}
+
+	tests := []struct {
+		name       string
+		cacheErr   error
+		storeErr   error
+		wantErr    error
+		wantLoaded bool
+		wantCached bool
+	}{
+		{name: "cache hit"},
+		{name: "revoked", cacheErr: domainerrors.ErrTokenRevoked, wantErr: domainerrors.ErrTokenRevoked},
+		{name: "cache failure", cacheErr: errors.New("redis down"), wantErr: errors.New("redis down")},
+		{name: "restored from store", cacheErr: domainerrors.ErrInvalidToken, wantLoaded: true, wantCached: true},
+		{name: "unknown token", cacheErr: domainerrors.ErrInvalidToken, storeErr: domainerrors.ErrInvalidToken, wantErr: domainerrors.ErrInvalidToken, wantLoaded: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loaded := false
+			store := &MockRefreshTokenStore{
+				GetFunc: func(ctx context.Context, tokenHash string) (*domain.RefreshTokenData, time.Time, error) {
+					loaded = true
+					if tokenHash != hashToken("refresh-token") {
+						t.Errorf("Get() hash = %q, want the SHA-256 of the token", tokenHash)
+					}
+					if tt.storeErr != nil {
+						return nil, time.Time{}, tt.storeErr
+					}
+					return data, time.Now().Add(time.Hour), nil
+				},
+			}
+			var cachedTTL time.Duration
+			cache := &MockTokenRepository{
+				GetActiveRefreshTokenFunc: func(ctx context.Context, token string) (*domain.RefreshTokenData, error) {
+					if tt.cacheErr != nil {
+						return nil, tt.cacheErr
+					}
+					return data, nil
+				},
+				StoreRefreshTokenFunc: func(ctx context.Context, token string, d *domain.RefreshTokenData, ttl time.Duration) error {
+					cachedTTL = ttl
+					return nil
+				},
+			}
+
+			repo := services.NewDurableTokenRepository(cache, store, zap.NewNop())
+			got, err := repo.GetActiveRefreshToken(context.Background(), "refresh-token")
+			if tt.wantErr != nil {
+				if err == nil || (!errors.Is(err, tt.wantErr) && err.Error() != tt.wantErr.Error()) {
+					t.Fatalf("GetActiveRefreshToken() error = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil || got != data {
+				t.Fatalf("GetActiveRefreshToken() = %v, %v, want the token data", got, err)
+			}
+			if loaded != tt.wantLoaded {
+				t.Errorf("loaded from store = %v, want %v", loaded, tt.wantLoaded)
+			}
+			if (cachedTTL > 0) != tt.wantCached || cachedTTL > time.Hour {
+				t.Errorf("cached TTL = %v, want cached %v for the rest of the lifetime", cachedTTL, tt.wantCached)
+			}
+		})
+	}
+}
+
+func TestDurableTokenRepository_Revocation(t *testing.T) {
+	var storeCalls, cacheCalls []string
+	store := &MockRefreshTokenStore{
+		DeleteFunc: func(ctx context.Context, tokenHash string) error {
+			storeCalls = append(storeCalls, "delete:"+tokenHash)
+			return nil
+		},
+		RotateFunc: func(ctx context.Context, oldTokenHash, newTokenHash string, data *domain.RefreshTokenData, expiresAt time.Time) error {
+			storeCalls = append(storeCalls, "rotate:"+oldTokenHash+">"+newTokenHash)
+			return nil
+		},
+		DeleteByUserFunc: func(ctx context.Context, userID string, idCitizen int) error {
+			storeCalls = append(storeCalls, "delete_by_user:"+userID)
+			return nil
+		},
+		CountActiveFunc: func(ctx context.Context, idCitizen int) (int, error) {
+			return 3, nil
+		},
+	}
+	cache := &MockTokenRepository{
+		DeleteRefreshTokenFunc: func(ctx context.Context, token string) error {
+			cacheCalls = append(cacheCalls, "delete:"+token)
+			return nil
+		},
+		RotateRefreshTokenFunc: func(ctx context.Context, oldToken, newToken string, data *domain.RefreshTokenData, ttl time.Duration) error {
+			cacheCalls = append(cacheCalls, "rotate:"+oldToken+">"+newToken)
+			return nil
+		},
+		RevokeSessionFunc: func(ctx context.Context, accessToken string, ttl time.Duration, refreshToken string) error {
+			cacheCalls = append(cacheCalls, "revoke:"+refreshToken)
+			return nil
+		},
+		DeleteUserTokensFunc: func(ctx context.Context, userID string, idCitizen int) error {
+			cacheCalls = append(cacheCalls, "delete_by_user:"+userID)
+			return nil
+		},
+		CountActiveSessionsFunc: func(ctx context.Context, idCitizen int) (int, error) {
+			t.Error("CountActiveSessions() should count the sessions in the store")
+			return 0, nil
+		},
+	}
+
+	repo := services.NewDurableTokenRepository(cache, store, zap.NewNop())
+	ctx := context.Background()
+	data := &domain.RefreshTokenData{IDCitizen: 12345, UserID: "user-123"}
+	if err := repo.DeleteRefreshToken(ctx, "a"); err != nil {
+		t.Fatalf("DeleteRefreshToken() error = %v", err)
+	}
+	if err := repo.RotateRefreshToken(ctx, "b", "c", data, time.Hour); err != nil {
+		t.Fatalf("RotateRefreshToken() error = %v", err)
+	}
+	if err := repo.RevokeSession(ctx, "access", time.Minute, "d"); err != nil {
+		t.Fatalf("RevokeSession() error = %v", err)
+	}
+	if err := repo.DeleteUserTokens(ctx, "user-123", 12345); err != nil {
+		t.Fatalf("DeleteUserTokens() error = %v", err)
+	}
+	if count, err := repo.CountActiveSessions(ctx, 12345); err != nil || count != 3 {
+		t.Errorf("CountActiveSessions() = %d, %v, want 3", count, err)
+	}
+
+	wantStore := []string{
+		"delete:" + hashToken("a"),
+		"rotate:" + hashToken("b") + ">" + hashToken("c"),
+		"delete:" + hashToken("d"),
+		"delete_by_user:user-123",
+	}
+	wantCache := []string{"delete:a", "rotate:b>c", "revoke:d", "delete_by_user:user-123"}
+	if len(storeCalls) != len(wantStore) || len(cacheCalls) != len(wantCache) {
+		t.Fatalf("store calls = %v, cache calls = %v", storeCalls, cacheCalls)
+	}
+	for i := range wantStore {
+		if storeCalls[i] != wantStore[i] || cacheCalls[i] != wantCache[i] {
+			t.Errorf("call %d = %q, %q, want %q, %q", i, storeCalls[i], cacheCalls[i], wantStore[i], wantCache[i])
+		}
+	}
+}
+
+func TestDurableTokenRepository_StoreFailureKeepsCache(t *testing.T) {
+	store := &MockRefreshTokenStore{
+		DeleteFunc: func(ctx context.Context, tokenHash string) error {
+			return errors.New("db down")
+		},
+	}
+	cache := &MockTokenRepository{
+		DeleteRefreshTokenFunc: func(ctx context.Context, token string) error {
+			t.Error("DeleteRefreshToken() should not touch the cache when the store fails")
+			return nil
+		},
+	}
+
+	repo := services.NewDurableTokenRepository(cache, store, zap.NewNop())
+	if err := repo.DeleteRefreshToken(context.Background(), "a"); err == nil {
+		t.Error("DeleteRefreshToken() error = nil, want the store error")
+	}
+}
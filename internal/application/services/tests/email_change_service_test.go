@@ -0,0 +1,186 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	"github.com/kristianrpo/auth-microservice/internal/domain/events"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// emailChangeFixture holds the mocks of an EmailChangeService and what they recorded
+type emailChangeFixture struct {
+	user      *domain.User
+	events    []*events.SecurityNotificationEvent
+	updateErr error
+	userRepo  *MockUserRepository
+	emailRepo *MockUserEmailRepository
+	limiter   *MockRateLimiter
+}
+
+func newEmailChangeFixture() *emailChangeFixture {
+	f := &emailChangeFixture{user: newTestUser(), limiter: &MockRateLimiter{}}
+	f.userRepo = &MockUserRepository{
+		GetByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+			return f.user, nil
+		},
+		GetByPendingEmailTokenFunc: func(ctx context.Context, tokenHash string) (*domain.User, error) {
+			if f.user.PendingEmailTokenHash == "" || tokenHash != f.user.PendingEmailTokenHash {
+				return nil, domainerrors.ErrUserNotFound
+			}
+			return f.user, nil
+		},
+		ExistsFunc: func(ctx context.Context, email string) (bool, error) {
+			return email == "taken@example.org", nil
+		},
+		UpdateFunc: func(ctx context.Context, user *domain.User) error {
+			return f.updateErr
+		},
+	}
+	f.emailRepo = &MockUserEmailRepository{
+		GetVerifiedFunc: func(ctx context.Context, email string) (*domain.UserEmail, error) {
+			switch email {
+			case "recovery@example.org":
+				return &domain.UserEmail{UserID: f.user.ID, Email: email}, nil
+			case "other-recovery@example.org":
+				return &domain.UserEmail{UserID: "other-user", Email: email}, nil
+			}
+			return nil, domainerrors.ErrEmailNotFound
+		},
+	}
+	return f
+}
+
+func (f *emailChangeFixture) service() *services.EmailChangeService {
+	publisher := &MockMessagePublisher{
+		PublishFunc: func(ctx context.Context, queueName string, message []byte) error {
+			event := &events.SecurityNotificationEvent{}
+			f.events = append(f.events, event)
+			return json.Unmarshal(message, event)
+		},
+	}
+	return services.NewEmailChangeService(f.userRepo, f.emailRepo, publisher, "test.security", f.limiter, time.Hour, zap.NewNop())
+}
+
+func TestEmailChangeService_RequestEmailChange(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		limited bool
+		wantErr error
+	}{
+		{name: "sends confirmation and alert", email: " New@Example.org"},
+		{name: "own verified secondary email", email: "recovery@example.org"},
+		{name: "invalid email", email: "not-an-email", wantErr: domainerrors.ErrInvalidEmail},
+		{name: "current email", email: "TEST@example.com", wantErr: domainerrors.ErrEmailAlreadyRegistered},
+		{name: "email of another user", email: "taken@example.org", wantErr: domainerrors.ErrEmailAlreadyRegistered},
+		{name: "verified email of another user", email: "other-recovery@example.org", wantErr: domainerrors.ErrEmailAlreadyRegistered},
+		{name: "rate limited", email: "new@example.org", limited: true, wantErr: domainerrors.ErrEmailRateLimited},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newEmailChangeFixture()
+			if tt.limited {
+				f.limiter = exceededLimiter()
+			}
+
+			user, err := f.service().RequestEmailChange(context.Background(), 12345, tt.email)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RequestEmailChange() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if len(f.events) != 0 || f.user.PendingEmail != "" {
+					t.Errorf("side effects after failure: events = %v, pending email = %q", f.events, f.user.PendingEmail)
+				}
+				return
+			}
+
+			if !user.HasPendingEmail(time.Now()) || user.Email != "test@example.com" {
+				t.Fatalf("user = %+v, want a pending change keeping the current email", user)
+			}
+			if len(f.events) != 2 {
+				t.Fatalf("published %d events, want 2", len(f.events))
+			}
+			confirmation, alert := f.events[0], f.events[1]
+			if confirmation.Type != string(domain.NotificationEmailChangeConfirmation) || confirmation.Email != user.PendingEmail {
+				t.Errorf("confirmation = %+v, want email_change_confirmation to %v", confirmation, user.PendingEmail)
+			}
+			if confirmation.Details["token"] == "" || confirmation.Details["token"] == user.PendingEmailTokenHash {
+				t.Errorf("confirmation token = %q, want a token whose hash is stored", confirmation.Details["token"])
+			}
+			if alert.Type != string(domain.NotificationEmailChangeRequested) || alert.Email != "test@example.com" {
+				t.Errorf("alert = %+v, want email_change_requested to the current email", alert)
+			}
+		})
+	}
+}
+
+func TestEmailChangeService_ConfirmEmailChange(t *testing.T) {
+	tests := []struct {
+		name        string
+		badToken    bool
+		expired     bool
+		inactive    bool
+		updateErr   error
+		wantErr     error
+		wantEmail   string
+		wantPending bool
+	}{
+		{name: "changes email", wantEmail: "new@example.org"},
+		{name: "invalid token", badToken: true, wantErr: domainerrors.ErrInvalidEmailChangeToken, wantEmail: "test@example.com", wantPending: true},
+		{name: "expired token discards the change", expired: true, wantErr: domainerrors.ErrInvalidEmailChangeToken, wantEmail: "test@example.com"},
+		{name: "inactive user", inactive: true, wantErr: domainerrors.ErrInvalidEmailChangeToken, wantEmail: "test@example.com", wantPending: true},
+		{name: "email taken meanwhile", updateErr: domainerrors.ErrUserAlreadyExists, wantErr: domainerrors.ErrEmailAlreadyRegistered, wantEmail: "new@example.org"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newEmailChangeFixture()
+			service := f.service()
+			if _, err := service.RequestEmailChange(context.Background(), 12345, "new@example.org"); err != nil {
+				t.Fatalf("RequestEmailChange() error = %v", err)
+			}
+			token := f.events[0].Details["token"]
+			if tt.badToken {
+				token = "forged-token"
+			}
+			if tt.expired {
+				expiredAt := time.Now().Add(-time.Minute)
+				f.user.PendingEmailExpiresAt = &expiredAt
+			}
+			if tt.inactive {
+				f.user.Status = domain.UserStatusSuspended
+			}
+			f.updateErr = tt.updateErr
+
+			err := service.ConfirmEmailChange(context.Background(), token)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ConfirmEmailChange() error = %v, want %v", err, tt.wantErr)
+			}
+			if f.user.Email != tt.wantEmail {
+				t.Errorf("email = %v, want %v", f.user.Email, tt.wantEmail)
+			}
+			if (f.user.PendingEmail != "") != tt.wantPending {
+				t.Errorf("pending email = %q, want pending %v", f.user.PendingEmail, tt.wantPending)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			// Tokens are single use
+			if err := service.ConfirmEmailChange(context.Background(), token); !errors.Is(err, domainerrors.ErrInvalidEmailChangeToken) {
+				t.Errorf("second ConfirmEmailChange() error = %v, want %v", err, domainerrors.ErrInvalidEmailChangeToken)
+			}
+		})
+	}
+}
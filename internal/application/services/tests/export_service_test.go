@@ -0,0 +1,174 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestExportService_ExportUsers(t *testing.T) {
+	errWrite := errors.New("client disconnected")
+	now := time.Now()
+
+	tests := []struct {
+		name          string
+		filter        domain.UserExportFilter
+		streamErr     error
+		fnErr         error
+		wantErr       error
+		wantExported  int
+		wantAudit     bool
+		wantCompleted string
+	}{
+		{
+			name:          "exports every user",
+			filter:        domain.UserExportFilter{Role: domain.RoleUser, Status: domain.UserStatusActive},
+			wantExported:  2,
+			wantAudit:     true,
+			wantCompleted: "true",
+		},
+		{
+			name:    "invalid role",
+			filter:  domain.UserExportFilter{Role: "ROOT"},
+			wantErr: domainerrors.ErrInvalidExportFilter,
+		},
+		{
+			name:    "inverted time range",
+			filter:  domain.UserExportFilter{CreatedAfter: now, CreatedBefore: now.Add(-time.Hour)},
+			wantErr: domainerrors.ErrInvalidExportFilter,
+		},
+		{
+			name:          "repository failure",
+			streamErr:     errors.New("connection reset"),
+			wantErr:       domainerrors.ErrInternal,
+			wantExported:  2,
+			wantAudit:     true,
+			wantCompleted: "false",
+		},
+		{
+			name:          "write failure is returned as is",
+			fnErr:         errWrite,
+			wantErr:       errWrite,
+			wantExported:  1,
+			wantAudit:     true,
+			wantCompleted: "false",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := &MockUserExportRepository{
+				StreamUsersFunc: func(ctx context.Context, filter domain.UserExportFilter, fn func(*domain.User) error) error {
+					if filter != tt.filter {
+						t.Errorf("StreamUsers() filter = %+v, want %+v", filter, tt.filter)
+					}
+					for _, id := range []string{"user-1", "user-2"} {
+						if err := fn(&domain.User{ID: id, Password: "hash"}); err != nil {
+							return err
+						}
+					}
+					return tt.streamErr
+				},
+			}
+			var audit *domain.AuditRecord
+			auditRepo := &MockAuditLogRepository{
+				RecordFunc: func(ctx context.Context, record *domain.AuditRecord) error {
+					audit = record
+					return nil
+				},
+			}
+			service := services.NewExportService(userRepo, &MockAuditLogQueryRepository{}, auditRepo, zap.NewNop())
+
+			var exported []*domain.User
+			err := service.ExportUsers(context.Background(), tt.filter, "admin:999", func(user *domain.User) error {
+				exported = append(exported, user)
+				if tt.fnErr != nil {
+					return tt.fnErr
+				}
+				return nil
+			})
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ExportUsers() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(exported) != tt.wantExported {
+				t.Errorf("exported users = %v, want %v", len(exported), tt.wantExported)
+			}
+			for _, user := range exported {
+				if user.Password != "" {
+					t.Errorf("exported user %s has a password", user.ID)
+				}
+			}
+
+			if (audit != nil) != tt.wantAudit {
+				t.Fatalf("audit record = %v, want audit %v", audit, tt.wantAudit)
+			}
+			if audit == nil {
+				return
+			}
+			if audit.Action != domain.AuditActionDatasetExported || audit.Actor != "admin:999" || audit.TargetID != "users" {
+				t.Errorf("audit record = %+v, want dataset.exported of users by admin:999", audit)
+			}
+			if audit.Details["completed"] != tt.wantCompleted {
+				t.Errorf("audit completed = %v, want %v", audit.Details["completed"], tt.wantCompleted)
+			}
+		})
+	}
+}
+
+func TestExportService_ExportAuditLog(t *testing.T) {
+	after := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	filter := domain.AuditLogFilter{Action: domain.AuditActionUserAnonymized, Actor: "admin:1", After: after}
+
+	auditLog := &MockAuditLogQueryRepository{
+		StreamRecordsFunc: func(ctx context.Context, got domain.AuditLogFilter, fn func(*domain.AuditRecord) error) error {
+			if got != filter {
+				t.Errorf("StreamRecords() filter = %+v, want %+v", got, filter)
+			}
+			return fn(domain.NewAuditRecord(domain.AuditActionUserAnonymized, "admin:1", "user-1", nil))
+		},
+	}
+	var audit *domain.AuditRecord
+	auditRepo := &MockAuditLogRepository{
+		RecordFunc: func(ctx context.Context, record *domain.AuditRecord) error {
+			audit = record
+			return nil
+		},
+	}
+	service := services.NewExportService(&MockUserExportRepository{}, auditLog, auditRepo, zap.NewNop())
+
+	records := 0
+	err := service.ExportAuditLog(context.Background(), filter, "admin:999", func(record *domain.AuditRecord) error {
+		records++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ExportAuditLog() error = %v", err)
+	}
+	if records != 1 {
+		t.Errorf("exported records = %v, want 1", records)
+	}
+
+	if audit == nil || audit.TargetID != "audit_log" {
+		t.Fatalf("audit record = %+v, want an export of audit_log", audit)
+	}
+	wantDetails := map[string]string{
+		"action":    "user.anonymized",
+		"actor":     "admin:1",
+		"after":     "2026-01-01T00:00:00Z",
+		"records":   "1",
+		"completed": "true",
+	}
+	for key, want := range wantDetails {
+		if audit.Details[key] != want {
+			t.Errorf("audit details[%s] = %v, want %v", key, audit.Details[key], want)
+		}
+	}
+}
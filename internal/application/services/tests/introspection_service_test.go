@@ -0,0 +1,150 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+const introspectionTestSecret = "test-secret-key-at-least-32-chars-long"
+
+func newTestIntrospectionService(t *testing.T, rateLimiter *MockRateLimiter) (*services.IntrospectionService, *services.JWTService, *services.OAuth2Service) {
+	t.Helper()
+	logger := zap.NewNop()
+
+	gateway, err := domain.NewOAuthClient("gateway", "gateway-secret", "API Gateway", "", []string{"read"})
+	if err != nil {
+		t.Fatalf("NewOAuthClient() error = %v", err)
+	}
+	clientRepo := &MockOAuthClientRepository{
+		GetByClientIDFunc: func(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+			if clientID != gateway.ClientID {
+				return nil, domainerrors.ErrClientNotFound
+			}
+			return gateway, nil
+		},
+	}
+
+	jwtService := services.NewJWTService(introspectionTestSecret, 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, logger)
+	userRepo := &MockUserRepository{
+		GetByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+			return newTestUser(), nil
+		},
+	}
+	authService := services.NewAuthService(userRepo, &MockTokenRepository{}, jwtService, &MockPasswordHasher{}, logger)
+	oauth2Service := services.NewOAuth2Service(clientRepo, &MockScopeRepository{}, introspectionTestSecret, 15*time.Minute, services.TokenSigningPolicy{}, logger)
+
+	return services.NewIntrospectionService(authService, oauth2Service, rateLimiter, logger), jwtService, oauth2Service
+}
+
+func TestIntrospectionService_Introspect(t *testing.T) {
+	var peekedKey string
+	rateLimiter := &MockRateLimiter{
+		PeekFunc: func(ctx context.Context, key string) (*domain.RateLimitStatus, error) {
+			peekedKey = key
+			return &domain.RateLimitStatus{Limit: 100, Used: 25, ResetAt: time.Now().Add(time.Minute)}, nil
+		},
+	}
+	service, jwtService, oauth2Service := newTestIntrospectionService(t, rateLimiter)
+
+	userToken, err := jwtService.GenerateAccessToken(12345, "test@example.com", domain.RoleUser)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken() error = %v", err)
+	}
+	minimalPair, err := jwtService.GenerateUserTokenPairWithProfile(newTestUser(), domain.TokenProfileMinimal, nil)
+	if err != nil {
+		t.Fatalf("GenerateUserTokenPairWithProfile() error = %v", err)
+	}
+	clientToken, _, err := oauth2Service.ClientCredentials(context.Background(), "gateway", "gateway-secret", nil, "")
+	if err != nil {
+		t.Fatalf("ClientCredentials() error = %v", err)
+	}
+	boundToken, _, err := oauth2Service.ClientCredentials(context.Background(), "gateway", "gateway-secret", nil, "thumbprint")
+	if err != nil {
+		t.Fatalf("ClientCredentials() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		clientSecret string
+		token        string
+		wantErr      error
+		wantActive   bool
+		wantSubject  string
+		wantEmail    string
+		wantKey      string
+		wantCnf      string
+	}{
+		{name: "active user token", clientSecret: "gateway-secret", token: userToken, wantActive: true, wantSubject: "12345", wantEmail: "test@example.com", wantKey: "user:12345"},
+		{name: "minimal user token gets the email of the user", clientSecret: "gateway-secret", token: minimalPair.AccessToken, wantActive: true, wantSubject: "12345", wantEmail: "test@example.com", wantKey: "user:12345"},
+		{name: "active client token", clientSecret: "gateway-secret", token: clientToken, wantActive: true, wantSubject: "gateway", wantKey: "client:gateway"},
+		{name: "certificate-bound client token", clientSecret: "gateway-secret", token: boundToken, wantActive: true, wantSubject: "gateway", wantKey: "client:gateway", wantCnf: "thumbprint"},
+		{name: "invalid token is inactive", clientSecret: "gateway-secret", token: "not-a-token"},
+		{name: "invalid caller credentials", clientSecret: "wrong", token: userToken, wantErr: domainerrors.ErrInvalidCredentials},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			peekedKey = ""
+
+			introspection, err := service.Introspect(context.Background(), "gateway", tt.clientSecret, tt.token)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Introspect() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Introspect() unexpected error = %v", err)
+			}
+
+			if introspection.Active != tt.wantActive {
+				t.Fatalf("Active = %v, want %v", introspection.Active, tt.wantActive)
+			}
+			if introspection.Subject != tt.wantSubject {
+				t.Errorf("Subject = %v, want %v", introspection.Subject, tt.wantSubject)
+			}
+			if introspection.Email != tt.wantEmail {
+				t.Errorf("Email = %v, want %v", introspection.Email, tt.wantEmail)
+			}
+			if introspection.CertificateThumbprint != tt.wantCnf {
+				t.Errorf("CertificateThumbprint = %q, want %q", introspection.CertificateThumbprint, tt.wantCnf)
+			}
+			if peekedKey != tt.wantKey {
+				t.Errorf("rate limit key = %q, want %q", peekedKey, tt.wantKey)
+			}
+			if tt.wantActive && (introspection.RateLimit == nil || introspection.RateLimit.Remaining() != 75) {
+				t.Errorf("RateLimit = %+v, want 75 remaining", introspection.RateLimit)
+			}
+		})
+	}
+}
+
+func TestIntrospectionService_Introspect_RateLimiterError(t *testing.T) {
+	rateLimiter := &MockRateLimiter{
+		PeekFunc: func(ctx context.Context, key string) (*domain.RateLimitStatus, error) {
+			return nil, errors.New("redis down")
+		},
+	}
+	service, jwtService, _ := newTestIntrospectionService(t, rateLimiter)
+
+	userToken, err := jwtService.GenerateAccessToken(12345, "test@example.com", domain.RoleUser)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken() error = %v", err)
+	}
+
+	introspection, err := service.Introspect(context.Background(), "gateway", "gateway-secret", userToken)
+	if err != nil {
+		t.Fatalf("Introspect() unexpected error = %v", err)
+	}
+	if !introspection.Active || introspection.RateLimit != nil {
+		t.Errorf("Introspect() = %+v, want active without rate limit", introspection)
+	}
+}
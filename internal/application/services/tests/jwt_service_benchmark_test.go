@@ -0,0 +1,70 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// Run with:
+//
+//	go test -run '^$' -bench JWTService -benchmem ./internal/application/services/tests/
+
+func newBenchJWTService() *services.JWTService {
+	return services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, zap.NewNop())
+}
+
+func BenchmarkJWTService_GenerateTokenPair(b *testing.B) {
+	jwtService := newBenchJWTService()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := jwtService.GenerateTokenPair(12345, "test@example.com", domain.RoleUser); err != nil {
+			b.Fatalf("GenerateTokenPair() unexpected error: %v", err)
+		}
+	}
+}
+
+func BenchmarkJWTService_ValidateAccessToken(b *testing.B) {
+	jwtService := newBenchJWTService()
+
+	token, err := jwtService.GenerateAccessToken(12345, "test@example.com", domain.RoleUser)
+	if err != nil {
+		b.Fatalf("GenerateAccessToken() unexpected error: %v", err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := jwtService.ValidateAccessToken(token); err != nil {
+			b.Fatalf("ValidateAccessToken() unexpected error: %v", err)
+		}
+	}
+}
+
+// BenchmarkJWTService_ValidateAccessToken_Parallel mirrors the authentication middleware,
+// which validates a token on every protected request
+func BenchmarkJWTService_ValidateAccessToken_Parallel(b *testing.B) {
+	jwtService := newBenchJWTService()
+
+	token, err := jwtService.GenerateAccessToken(12345, "test@example.com", domain.RoleUser)
+	if err != nil {
+		b.Fatalf("GenerateAccessToken() unexpected error: %v", err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := jwtService.ValidateAccessToken(token); err != nil {
+				b.Errorf("ValidateAccessToken() unexpected error: %v", err)
+				return
+			}
+		}
+	})
+}
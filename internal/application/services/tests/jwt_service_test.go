@@ -1,18 +1,25 @@
 package tests
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
 	"go.uber.org/zap"
 
 	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
 	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
 )
 
 func TestJWTService_GenerateAccessToken(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
-	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, logger)
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, logger)
 
 	tests := []struct {
 		name      string
@@ -67,7 +74,7 @@ func TestJWTService_GenerateAccessToken(t *testing.T) {
 
 func TestJWTService_GenerateRefreshToken(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
-	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, logger)
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, logger)
 
 	token, err := jwtService.GenerateRefreshToken(123, "test@example.com", domain.RoleUser)
 
@@ -82,7 +89,7 @@ func TestJWTService_GenerateRefreshToken(t *testing.T) {
 
 func TestJWTService_GenerateTokenPair(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
-	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, logger)
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, logger)
 
 	tokenPair, err := jwtService.GenerateTokenPair(123, "test@example.com", domain.RoleUser)
 
@@ -112,9 +119,29 @@ func TestJWTService_GenerateTokenPair(t *testing.T) {
 	}
 }
 
+func TestJWTService_GenerateUserTokenPair(t *testing.T) {
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, zap.NewNop())
+	user := newTestUser()
+
+	tokenPair, err := jwtService.GenerateUserTokenPair(user)
+	if err != nil {
+		t.Fatalf("GenerateUserTokenPair() unexpected error: %v", err)
+	}
+
+	for name, token := range map[string]string{"access": tokenPair.AccessToken, "refresh": tokenPair.RefreshToken} {
+		claims, err := jwtService.ValidateToken(token)
+		if err != nil {
+			t.Fatalf("ValidateToken(%s) unexpected error: %v", name, err)
+		}
+		if claims.UserID != user.ID || claims.IDCitizen != user.IDCitizen {
+			t.Errorf("%s claims = %+v, want UserID %v and IDCitizen %v", name, claims, user.ID, user.IDCitizen)
+		}
+	}
+}
+
 func TestJWTService_ValidateToken(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
-	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, logger)
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, logger)
 
 	// Generate a valid token
 	validToken, _ := jwtService.GenerateAccessToken(123, "test@example.com", domain.RoleUser)
@@ -184,7 +211,7 @@ func TestJWTService_ValidateToken(t *testing.T) {
 
 func TestJWTService_ValidateAccessToken(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
-	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, logger)
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, logger)
 
 	accessToken, _ := jwtService.GenerateAccessToken(123, "test@example.com", domain.RoleUser)
 	refreshToken, _ := jwtService.GenerateRefreshToken(123, "test@example.com", domain.RoleUser)
@@ -236,7 +263,7 @@ func TestJWTService_ValidateAccessToken(t *testing.T) {
 
 func TestJWTService_ValidateRefreshToken(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
-	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, logger)
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, logger)
 
 	accessToken, _ := jwtService.GenerateAccessToken(123, "test@example.com", domain.RoleUser)
 	refreshToken, _ := jwtService.GenerateRefreshToken(123, "test@example.com", domain.RoleUser)
@@ -288,7 +315,7 @@ func TestJWTService_ValidateRefreshToken(t *testing.T) {
 
 func TestJWTService_GetTokenExpiration(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
-	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, logger)
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, logger)
 
 	token, _ := jwtService.GenerateAccessToken(123, "test@example.com", domain.RoleUser)
 
@@ -314,7 +341,7 @@ func TestJWTService_GetTokenExpiration(t *testing.T) {
 func TestJWTService_ExpiredToken(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
 	// Create service with very short expiration
-	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 1*time.Millisecond, 1*time.Millisecond, logger)
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 1*time.Millisecond, 1*time.Millisecond, false, nil, services.TokenSigningPolicy{}, logger)
 
 	token, _ := jwtService.GenerateAccessToken(123, "test@example.com", domain.RoleUser)
 
@@ -327,3 +354,209 @@ func TestJWTService_ExpiredToken(t *testing.T) {
 		t.Errorf("ValidateToken() expected error for expired token but got none")
 	}
 }
+
+func TestJWTService_OpaqueRefreshTokens(t *testing.T) {
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, true, nil, services.TokenSigningPolicy{}, zap.NewNop())
+
+	tokenPair, err := jwtService.GenerateUserTokenPair(&domain.User{ID: "user-123", IDCitizen: 12345, Email: "test@example.com", Role: domain.RoleUser})
+	if err != nil {
+		t.Fatalf("GenerateUserTokenPair() error = %v", err)
+	}
+	if !domain.IsOpaqueRefreshToken(tokenPair.RefreshToken) || strings.Contains(tokenPair.RefreshToken, "12345") {
+		t.Errorf("refresh token = %q, want an opaque token leaking no claims", tokenPair.RefreshToken)
+	}
+	if _, err := jwtService.ValidateAccessToken(tokenPair.AccessToken); err != nil {
+		t.Errorf("ValidateAccessToken() error = %v, access tokens must remain JWTs", err)
+	}
+
+	other, _ := jwtService.GenerateRefreshToken(12345, "test@example.com", domain.RoleUser)
+	if other == tokenPair.RefreshToken {
+		t.Error("GenerateRefreshToken() returned the same opaque token twice")
+	}
+}
+
+func TestJWTService_MinimalTokenProfile(t *testing.T) {
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, zap.NewNop())
+	user := &domain.User{ID: "user-123", IDCitizen: 12345, Email: "test@example.com", Role: domain.RoleAdmin}
+
+	standard, err := jwtService.GenerateUserTokenPairWithProfile(user, domain.TokenProfileStandard, nil)
+	if err != nil {
+		t.Fatalf("GenerateUserTokenPairWithProfile() error = %v", err)
+	}
+	minimal, err := jwtService.GenerateUserTokenPairWithProfile(user, domain.TokenProfileMinimal, nil)
+	if err != nil {
+		t.Fatalf("GenerateUserTokenPairWithProfile() error = %v", err)
+	}
+	if len(minimal.AccessToken) >= len(standard.AccessToken) {
+		t.Errorf("minimal access token length = %d, want less than %d", len(minimal.AccessToken), len(standard.AccessToken))
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.Split(minimal.AccessToken, ".")[1])
+	if err != nil {
+		t.Fatalf("failed to decode access token payload: %v", err)
+	}
+	var claims map[string]interface{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		t.Fatalf("failed to unmarshal access token payload: %v", err)
+	}
+	keys := make([]string, 0, len(claims))
+	for key := range claims {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if want := []string{"exp", "jti", "role", "sub"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("access token claims = %v, want %v", keys, want)
+	}
+
+	validated, err := jwtService.ValidateAccessToken(minimal.AccessToken)
+	if err != nil {
+		t.Fatalf("ValidateAccessToken() error = %v", err)
+	}
+	if validated.IDCitizen != 12345 || validated.Role != domain.RoleAdmin || validated.Type != domain.TokenTypeAccess || validated.Email != "" {
+		t.Errorf("ValidateAccessToken() claims = %+v, want citizen 12345 with role only", validated)
+	}
+
+	if _, err := jwtService.ValidateRefreshToken(minimal.AccessToken); !errors.Is(err, domainerrors.ErrInvalidTokenType) {
+		t.Errorf("ValidateRefreshToken() with a minimal access token error = %v, want %v", err, domainerrors.ErrInvalidTokenType)
+	}
+	if refreshClaims, err := jwtService.ValidateRefreshToken(minimal.RefreshToken); err != nil || refreshClaims.Email != "test@example.com" {
+		t.Errorf("ValidateRefreshToken() = %+v, %v, want the standard refresh token", refreshClaims, err)
+	}
+}
+
+func TestJWTService_MetadataClaims(t *testing.T) {
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, []string{"department", "tenant"}, services.TokenSigningPolicy{}, zap.NewNop())
+	user := newTestUser()
+	user.Metadata = domain.UserMetadata{"department": "sales", "employee_level": float64(3)}
+
+	tokenPair, err := jwtService.GenerateUserTokenPair(user)
+	if err != nil {
+		t.Fatalf("GenerateUserTokenPair() unexpected error: %v", err)
+	}
+
+	decodeClaims := func(token string) map[string]interface{} {
+		payload, err := base64.RawURLEncoding.DecodeString(strings.Split(token, ".")[1])
+		if err != nil {
+			t.Fatalf("failed to decode token payload: %v", err)
+		}
+		var claims map[string]interface{}
+		if err := json.Unmarshal(payload, &claims); err != nil {
+			t.Fatalf("failed to unmarshal token payload: %v", err)
+		}
+		return claims
+	}
+
+	want := map[string]interface{}{"department": "sales"}
+	if got := decodeClaims(tokenPair.AccessToken)["metadata"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("access token metadata claim = %v, want %v", got, want)
+	}
+	if got, ok := decodeClaims(tokenPair.RefreshToken)["metadata"]; ok {
+		t.Errorf("refresh token metadata claim = %v, want none", got)
+	}
+
+	user.Metadata = domain.UserMetadata{"employee_level": float64(3)}
+	tokenPair, err = jwtService.GenerateUserTokenPair(user)
+	if err != nil {
+		t.Fatalf("GenerateUserTokenPair() unexpected error: %v", err)
+	}
+	if got, ok := decodeClaims(tokenPair.AccessToken)["metadata"]; ok {
+		t.Errorf("access token metadata claim = %v, want none without allowlisted keys", got)
+	}
+}
+
+func TestJWTService_GenerateSudoToken(t *testing.T) {
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, zap.NewNop())
+	user := newTestUser()
+
+	grant, err := jwtService.GenerateSudoToken(user, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("GenerateSudoToken() unexpected error: %v", err)
+	}
+
+	claims, err := jwtService.ValidateAccessToken(grant.AccessToken)
+	if err != nil {
+		t.Fatalf("ValidateAccessToken() unexpected error: %v", err)
+	}
+	if claims.IDCitizen != user.IDCitizen || claims.SudoUntil != grant.SudoUntil.Unix() {
+		t.Errorf("claims = %+v, want IDCitizen %v and sudo_until %v", claims, user.IDCitizen, grant.SudoUntil.Unix())
+	}
+	if !claims.IsElevated(time.Now()) || claims.IsElevated(grant.SudoUntil) {
+		t.Errorf("IsElevated() must hold only until %v", grant.SudoUntil)
+	}
+
+	// The elevated access ends with the token
+	expiresAt, err := jwtService.GetTokenExpiration(grant.AccessToken)
+	if err != nil {
+		t.Fatalf("GetTokenExpiration() unexpected error: %v", err)
+	}
+	if !expiresAt.Equal(grant.SudoUntil) {
+		t.Errorf("token expiration = %v, want %v", expiresAt, grant.SudoUntil)
+	}
+
+	// Regular access tokens grant no elevated access
+	accessToken, _ := jwtService.GenerateAccessToken(user.IDCitizen, user.Email, user.Role)
+	if claims, _ := jwtService.ValidateAccessToken(accessToken); claims.IsElevated(time.Now()) {
+		t.Errorf("regular access token claims = %+v, want no elevated access", claims)
+	}
+}
+
+// decodeTokenClaims returns the claims of a JWT without validating it
+func decodeTokenClaims(t *testing.T, token string) map[string]interface{} {
+	t.Helper()
+	payload, err := base64.RawURLEncoding.DecodeString(strings.Split(token, ".")[1])
+	if err != nil {
+		t.Fatalf("failed to decode token payload: %v", err)
+	}
+	var claims map[string]interface{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		t.Fatalf("failed to unmarshal token payload: %v", err)
+	}
+	return claims
+}
+
+func TestJWTService_Region(t *testing.T) {
+	user := newTestUser()
+
+	t.Run("region claim and jti prefix", func(t *testing.T) {
+		jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{Region: "eu-west-1"}, zap.NewNop())
+
+		pair, err := jwtService.GenerateUserTokenPair(user)
+		if err != nil {
+			t.Fatalf("GenerateUserTokenPair() error = %v", err)
+		}
+		for name, token := range map[string]string{"access": pair.AccessToken, "refresh": pair.RefreshToken} {
+			claims := decodeTokenClaims(t, token)
+			jti, _ := claims["jti"].(string)
+			if claims["region"] != "eu-west-1" || domain.TokenIDRegion(jti) != "eu-west-1" {
+				t.Errorf("%s token claims = %v, want region eu-west-1 in the claim and the jti", name, claims)
+			}
+		}
+		if _, err := jwtService.ValidateAccessToken(pair.AccessToken); err != nil {
+			t.Errorf("ValidateAccessToken() error = %v", err)
+		}
+
+		// Minimal access tokens only carry the region in their jti
+		minimal, err := jwtService.GenerateUserTokenPairWithProfile(user, domain.TokenProfileMinimal, nil)
+		if err != nil {
+			t.Fatalf("GenerateUserTokenPairWithProfile() error = %v", err)
+		}
+		claims := decodeTokenClaims(t, minimal.AccessToken)
+		if jti, _ := claims["jti"].(string); domain.TokenIDRegion(jti) != "eu-west-1" || claims["region"] != nil {
+			t.Errorf("minimal access token claims = %v, want the region in the jti only", claims)
+		}
+	})
+
+	t.Run("single region", func(t *testing.T) {
+		jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, zap.NewNop())
+
+		accessToken, err := jwtService.GenerateAccessToken(user.IDCitizen, user.Email, user.Role)
+		if err != nil {
+			t.Fatalf("GenerateAccessToken() error = %v", err)
+		}
+		claims := decodeTokenClaims(t, accessToken)
+		jti, _ := claims["jti"].(string)
+		if jti == "" || domain.TokenIDRegion(jti) != "" || claims["region"] != nil {
+			t.Errorf("access token claims = %v, want a jti without region", claims)
+		}
+	})
+}
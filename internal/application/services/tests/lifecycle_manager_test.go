@@ -0,0 +1,133 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+)
+
+// recordingComponent records its start and stop calls in a shared log
+type recordingComponent struct {
+	name     string
+	calls    *[]string
+	startErr error
+	stopErr  error
+}
+
+func (c *recordingComponent) Start(ctx context.Context) error {
+	*c.calls = append(*c.calls, "start "+c.name)
+	return c.startErr
+}
+
+func (c *recordingComponent) Stop(ctx context.Context) error {
+	*c.calls = append(*c.calls, "stop "+c.name)
+	return c.stopErr
+}
+
+func TestLifecycleManager(t *testing.T) {
+	tests := []struct {
+		name         string
+		startErr     map[string]error
+		stopErr      map[string]error
+		wantStartErr bool
+		wantStopErr  bool
+		wantCalls    []string
+	}{
+		{
+			name:      "starts in order and stops in reverse order",
+			wantCalls: []string{"start relay", "start consumers", "start scheduler", "stop scheduler", "stop consumers", "stop relay"},
+		},
+		{
+			name:         "start failure stops the started components",
+			startErr:     map[string]error{"consumers": errors.New("channel unavailable")},
+			wantStartErr: true,
+			wantCalls:    []string{"start relay", "start consumers", "stop relay"},
+		},
+		{
+			name:        "stop failure still stops the other components",
+			stopErr:     map[string]error{"consumers": errors.New("timeout")},
+			wantStopErr: true,
+			wantCalls:   []string{"start relay", "start consumers", "start scheduler", "stop scheduler", "stop consumers", "stop relay"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			manager := services.NewLifecycleManager(zap.NewNop())
+			for _, name := range []string{"relay", "consumers", "scheduler"} {
+				manager.Register(name, &recordingComponent{
+					name:     name,
+					calls:    &calls,
+					startErr: tt.startErr[name],
+					stopErr:  tt.stopErr[name],
+				})
+			}
+
+			err := manager.Start(context.Background())
+			if (err != nil) != tt.wantStartErr {
+				t.Fatalf("Start() error = %v, wantStartErr %v", err, tt.wantStartErr)
+			}
+			if !tt.wantStartErr {
+				err = manager.Stop(context.Background())
+				if (err != nil) != tt.wantStopErr {
+					t.Fatalf("Stop() error = %v, wantStopErr %v", err, tt.wantStopErr)
+				}
+			}
+
+			if !reflect.DeepEqual(calls, tt.wantCalls) {
+				t.Errorf("calls = %v, want %v", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestLifecycleManager_Shutdown(t *testing.T) {
+	manager := services.NewLifecycleManager(zap.NewNop())
+	manager.Register("relay", &drainingComponent{})
+	manager.Register("consumers", &drainingComponent{stats: ports.DrainStats{Drained: 2, Cancelled: 1}, stopErr: context.DeadlineExceeded})
+
+	if err := manager.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	steps := manager.Shutdown(context.Background())
+
+	if len(steps) != 2 || steps[0].Name != "consumers" || steps[1].Name != "relay" {
+		t.Fatalf("Shutdown() steps = %+v, want consumers then relay", steps)
+	}
+	if steps[0].Drained != 2 || steps[0].Cancelled != 1 || !errors.Is(steps[0].Err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown() consumers step = %+v", steps[0])
+	}
+	if steps[1].Err != nil {
+		t.Errorf("Shutdown() relay step error = %v", steps[1].Err)
+	}
+	if steps := manager.Shutdown(context.Background()); len(steps) != 0 {
+		t.Errorf("second Shutdown() steps = %+v, want none", steps)
+	}
+}
+
+func TestOutboxRelay_StartStop(t *testing.T) {
+	relay := services.NewOutboxRelay(&MockMessagePublisher{}, &MockOutboxRepository{}, services.OutboxRelayPolicy{
+		PollInterval:   time.Hour,
+		BatchSize:      10,
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+	}, zap.NewNop())
+
+	if err := relay.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := relay.Stop(ctx); err != nil {
+		t.Errorf("Stop() error = %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+var (
+	bogota = domain.GeoLocation{Country: "CO", City: "Bogotá", Latitude: 4.711, Longitude: -74.0721}
+	madrid = domain.GeoLocation{Country: "ES", City: "Madrid", Latitude: 40.4168, Longitude: -3.7038}
+)
+
+func TestLoginAnomalyService_EvaluateLogin(t *testing.T) {
+	tests := []struct {
+		name         string
+		noClientInfo bool
+		location     *domain.GeoLocation
+		history      []*domain.LoginLocation
+		wantRisk     bool
+		wantRisky    bool
+		wantReason   string
+	}{
+		{name: "no client info", noClientInfo: true, location: &madrid},
+		{name: "location not found"},
+		{name: "first login", location: &madrid, wantRisk: true},
+		{
+			name:       "impossible travel",
+			location:   &madrid,
+			history:    []*domain.LoginLocation{{IP: "190.0.0.1", Location: bogota, At: time.Now().Add(-time.Hour)}},
+			wantRisk:   true,
+			wantRisky:  true,
+			wantReason: domain.RiskReasonImpossibleTravel,
+		},
+		{
+			name:     "plausible travel",
+			location: &madrid,
+			history:  []*domain.LoginLocation{{IP: "190.0.0.1", Location: bogota, At: time.Now().Add(-20 * time.Hour)}},
+			wantRisk: true,
+		},
+		{
+			name:       "new country without coordinates",
+			location:   &domain.GeoLocation{Country: "ES"},
+			history:    []*domain.LoginLocation{{IP: "190.0.0.1", Location: domain.GeoLocation{Country: "CO"}, At: time.Now().Add(-time.Hour)}},
+			wantRisk:   true,
+			wantRisky:  true,
+			wantReason: domain.RiskReasonNewCountry,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locator := &MockGeoLocator{}
+			if tt.location != nil {
+				locator.LocateFunc = func(ctx context.Context, ip string) (*domain.GeoLocation, error) {
+					return tt.location, nil
+				}
+			}
+
+			var recorded *domain.LoginLocation
+			historyRepo := &MockLoginHistoryRepository{
+				RecentFunc: func(ctx context.Context, userID string, since time.Time) ([]*domain.LoginLocation, error) {
+					return tt.history, nil
+				},
+				RecordFunc: func(ctx context.Context, userID string, login *domain.LoginLocation) error {
+					recorded = login
+					return nil
+				},
+			}
+
+			var notified domain.NotificationType
+			notifier := &MockNotificationService{
+				NotifyFunc: func(ctx context.Context, user *domain.User, notificationType domain.NotificationType, details map[string]string) error {
+					notified = notificationType
+					return nil
+				},
+			}
+
+			service := services.NewLoginAnomalyService(
+				locator, historyRepo, notifier,
+				domain.TravelPolicy{MaxSpeedKmh: 1000, MinDistanceKm: 500},
+				24*time.Hour, zap.NewNop(),
+			)
+
+			ctx := context.Background()
+			if !tt.noClientInfo {
+				ctx = domain.ContextWithClientInfo(ctx, domain.ClientInfo{IP: "85.0.0.1", UserAgent: "test"})
+			}
+			risk := service.EvaluateLogin(ctx, newTestUser())
+
+			if (risk != nil) != tt.wantRisk {
+				t.Fatalf("EvaluateLogin() = %+v, want risk %v", risk, tt.wantRisk)
+			}
+			if (recorded != nil) != tt.wantRisk {
+				t.Errorf("login recorded = %v, want %v", recorded != nil, tt.wantRisk)
+			}
+			if risk == nil {
+				return
+			}
+
+			if risk.Risky != tt.wantRisky {
+				t.Errorf("Risky = %v, want %v", risk.Risky, tt.wantRisky)
+			}
+			if tt.wantRisky && (len(risk.Reasons) != 1 || risk.Reasons[0] != tt.wantReason) {
+				t.Errorf("Reasons = %v, want [%s]", risk.Reasons, tt.wantReason)
+			}
+			if tt.wantRisky != (notified == domain.NotificationSuspiciousLogin) {
+				t.Errorf("notified = %q, want suspicious login notification %v", notified, tt.wantRisky)
+			}
+			if risk.IP != "85.0.0.1" || risk.Country != tt.location.Country {
+				t.Errorf("risk location = %s %s, want 85.0.0.1 %s", risk.IP, risk.Country, tt.location.Country)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	"github.com/kristianrpo/auth-microservice/internal/domain/events"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+const firefoxOnLinux = "Mozilla/5.0 (X11; Linux x86_64; rv:127.0) Gecko/20100101 Firefox/127.0"
+
+func TestLoginNotificationService_NotifyLogin(t *testing.T) {
+	bothChannels := []domain.LoginNotificationChannel{domain.LoginNotificationChannelEvent, domain.LoginNotificationChannelEmail}
+
+	tests := []struct {
+		name      string
+		channels  []domain.LoginNotificationChannel
+		newDevice bool
+		knownIP   bool
+		allows    bool
+		wantEvent bool
+		wantEmail bool
+		wantNewIP bool
+		wantRemIP bool
+	}{
+		{name: "new device through both channels", channels: bothChannels, newDevice: true, knownIP: true, allows: true, wantEvent: true, wantEmail: true},
+		{name: "new IP address", channels: bothChannels, knownIP: false, allows: true, wantEvent: true, wantEmail: true, wantNewIP: true, wantRemIP: true},
+		{name: "event channel only", channels: []domain.LoginNotificationChannel{domain.LoginNotificationChannelEvent}, newDevice: true, knownIP: true, allows: true, wantEvent: true},
+		{name: "known device and IP address", channels: bothChannels, knownIP: true, allows: true},
+		{name: "opted out", channels: bothChannels, newDevice: true, knownIP: true, allows: false},
+		{name: "no channels", newDevice: true, knownIP: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var event *events.LoginNotificationEvent
+			publisher := &MockMessagePublisher{
+				PublishFunc: func(ctx context.Context, queueName string, message []byte) error {
+					if queueName != "auth.login.notification" {
+						t.Errorf("Publish() queue = %s", queueName)
+					}
+					event = &events.LoginNotificationEvent{}
+					return json.Unmarshal(message, event)
+				},
+			}
+
+			var emailDetails map[string]string
+			notifier := &MockNotificationService{
+				AllowsFunc: func(ctx context.Context, user *domain.User, notificationType domain.NotificationType) (bool, error) {
+					if notificationType != domain.NotificationNewDevice {
+						t.Errorf("Allows() type = %s, want new_device", notificationType)
+					}
+					return tt.allows, nil
+				},
+				NotifyFunc: func(ctx context.Context, user *domain.User, notificationType domain.NotificationType, details map[string]string) error {
+					if notificationType != domain.NotificationNewDevice {
+						t.Errorf("Notify() type = %s, want new_device", notificationType)
+					}
+					emailDetails = details
+					return nil
+				},
+			}
+
+			rememberedIP := false
+			ipRepo := &MockKnownDeviceRepository{
+				IsKnownFunc: func(ctx context.Context, userID, deviceID string) (bool, error) {
+					if deviceID != domain.KnownIPDeviceID("203.0.113.42") {
+						t.Errorf("IsKnown() device = %s, want the ID of the IP address", deviceID)
+					}
+					return tt.knownIP, nil
+				},
+				RememberFunc: func(ctx context.Context, userID, deviceID string) error {
+					rememberedIP = true
+					return nil
+				},
+			}
+
+			service := services.NewLoginNotificationService(notifier, ipRepo, publisher, "auth.login.notification", tt.channels, zap.NewNop())
+			ctx := domain.ContextWithClientInfo(context.Background(), domain.ClientInfo{IP: "203.0.113.42", UserAgent: firefoxOnLinux})
+			assessment := &domain.RiskAssessment{
+				Decision: domain.RiskDecisionAllow,
+				Signals:  domain.RiskSignals{Flow: domain.RiskFlowLogin, NewDevice: tt.newDevice},
+				Geo:      &domain.LoginRisk{IP: "203.0.113.42", Country: "CO", City: "Bogotá"},
+			}
+			service.NotifyLogin(ctx, newTestUser(), assessment)
+
+			if (event != nil) != tt.wantEvent {
+				t.Fatalf("published event = %+v, want event %v", event, tt.wantEvent)
+			}
+			if (emailDetails != nil) != tt.wantEmail {
+				t.Fatalf("email details = %v, want email %v", emailDetails, tt.wantEmail)
+			}
+			if rememberedIP != tt.wantRemIP {
+				t.Errorf("remembered IP = %v, want %v", rememberedIP, tt.wantRemIP)
+			}
+
+			if event != nil {
+				if event.UserID != "user-123" || event.NewDevice != tt.newDevice || event.NewIP != tt.wantNewIP {
+					t.Errorf("event = %+v", event)
+				}
+				if event.Device != "Firefox on Linux" || event.Network != "203.0.113.0/24" || event.Country != "CO" || event.City != "Bogotá" {
+					t.Errorf("event = %+v, want the redacted device and location", event)
+				}
+			}
+			if emailDetails != nil && (emailDetails["network"] != "203.0.113.0/24" || emailDetails["device"] != "Firefox on Linux") {
+				t.Errorf("email details = %v, want the redacted device and location", emailDetails)
+			}
+		})
+	}
+}
+
+func TestLoginNotificationService_NotifyLogin_FailuresAreBestEffort(t *testing.T) {
+	emailSent := false
+	notifier := &MockNotificationService{
+		NotifyFunc: func(ctx context.Context, user *domain.User, notificationType domain.NotificationType, details map[string]string) error {
+			emailSent = true
+			return nil
+		},
+	}
+	publisher := &MockMessagePublisher{
+		PublishFunc: func(ctx context.Context, queueName string, message []byte) error {
+			return errors.New("broker down")
+		},
+	}
+	// A failing store takes the IP address as known
+	ipRepo := &MockKnownDeviceRepository{
+		IsKnownFunc: func(ctx context.Context, userID, deviceID string) (bool, error) {
+			return false, errors.New("redis down")
+		},
+	}
+
+	service := services.NewLoginNotificationService(notifier, ipRepo, publisher, "auth.login.notification",
+		[]domain.LoginNotificationChannel{domain.LoginNotificationChannelEvent, domain.LoginNotificationChannelEmail}, zap.NewNop())
+	ctx := domain.ContextWithClientInfo(context.Background(), domain.ClientInfo{IP: "203.0.113.42", UserAgent: firefoxOnLinux})
+
+	service.NotifyLogin(ctx, newTestUser(), &domain.RiskAssessment{Signals: domain.RiskSignals{NewDevice: true}})
+	if !emailSent {
+		t.Error("email not sent after the event failed to publish")
+	}
+
+	emailSent = false
+	service.NotifyLogin(ctx, newTestUser(), &domain.RiskAssessment{})
+	if emailSent {
+		t.Error("email sent for a known device whose IP address could not be checked")
+	}
+}
@@ -2,8 +2,11 @@ package tests
 
 import (
 	"context"
+	"io"
 	"time"
 
+	"golang.org/x/crypto/bcrypt"
+
 	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
 	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
 )
@@ -17,6 +20,10 @@ type MockUserRepository struct {
 	UpdateFunc         func(ctx context.Context, user *domain.User) error
 	DeleteFunc         func(ctx context.Context, id string) error
 	ExistsFunc         func(ctx context.Context, email string) (bool, error)
+	ListByStatusFunc   func(ctx context.Context, status domain.UserStatus, limit, offset int) ([]*domain.User, error)
+
+	GetByPendingEmailTokenFunc func(ctx context.Context, tokenHash string) (*domain.User, error)
+	RecordLoginFunc            func(ctx context.Context, id string, at time.Time) error
 }
 
 func (m *MockUserRepository) Create(ctx context.Context, user *domain.User) error {
@@ -55,6 +62,13 @@ func (m *MockUserRepository) Update(ctx context.Context, user *domain.User) erro
 	return nil
 }
 
+func (m *MockUserRepository) RecordLogin(ctx context.Context, id string, at time.Time) error {
+	if m.RecordLoginFunc != nil {
+		return m.RecordLoginFunc(ctx, id, at)
+	}
+	return nil
+}
+
 func (m *MockUserRepository) Delete(ctx context.Context, id string) error {
 	if m.DeleteFunc != nil {
 		return m.DeleteFunc(ctx, id)
@@ -69,6 +83,20 @@ func (m *MockUserRepository) Exists(ctx context.Context, email string) (bool, er
 	return false, nil
 }
 
+func (m *MockUserRepository) ListByStatus(ctx context.Context, status domain.UserStatus, limit, offset int) ([]*domain.User, error) {
+	if m.ListByStatusFunc != nil {
+		return m.ListByStatusFunc(ctx, status, limit, offset)
+	}
+	return nil, nil
+}
+
+func (m *MockUserRepository) GetByPendingEmailToken(ctx context.Context, tokenHash string) (*domain.User, error) {
+	if m.GetByPendingEmailTokenFunc != nil {
+		return m.GetByPendingEmailTokenFunc(ctx, tokenHash)
+	}
+	return nil, domainerrors.ErrUserNotFound
+}
+
 // MockTokenRepository is a mock implementation of ports.TokenRepository
 type MockTokenRepository struct {
 	StoreRefreshTokenFunc  func(ctx context.Context, token string, data *domain.RefreshTokenData, ttl time.Duration) error
@@ -76,7 +104,14 @@ type MockTokenRepository struct {
 	DeleteRefreshTokenFunc func(ctx context.Context, token string) error
 	BlacklistTokenFunc     func(ctx context.Context, token string, ttl time.Duration) error
 	IsTokenBlacklistedFunc func(ctx context.Context, token string) (bool, error)
-	DeleteUserTokensFunc   func(ctx context.Context, idCitizen int) error
+	DeleteUserTokensFunc   func(ctx context.Context, userID string, idCitizen int) error
+
+	GetActiveRefreshTokenFunc func(ctx context.Context, token string) (*domain.RefreshTokenData, error)
+	RotateRefreshTokenFunc    func(ctx context.Context, oldToken, newToken string, data *domain.RefreshTokenData, ttl time.Duration) error
+	RevokeSessionFunc         func(ctx context.Context, accessToken string, ttl time.Duration, refreshToken string) error
+	CountActiveSessionsFunc   func(ctx context.Context, idCitizen int) (int, error)
+	SetTokenVersionFunc       func(ctx context.Context, idCitizen int, version int, ttl time.Duration) error
+	GetTokenVersionFunc       func(ctx context.Context, idCitizen int) (int, error)
 }
 
 func (m *MockTokenRepository) StoreRefreshToken(ctx context.Context, token string, data *domain.RefreshTokenData, ttl time.Duration) error {
@@ -114,13 +149,76 @@ func (m *MockTokenRepository) IsTokenBlacklisted(ctx context.Context, token stri
 	return false, nil
 }
 
-func (m *MockTokenRepository) DeleteUserTokens(ctx context.Context, idCitizen int) error {
+func (m *MockTokenRepository) GetActiveRefreshToken(ctx context.Context, token string) (*domain.RefreshTokenData, error) {
+	if m.GetActiveRefreshTokenFunc != nil {
+		return m.GetActiveRefreshTokenFunc(ctx, token)
+	}
+	// Default behavior: compose the single-operation mocks
+	blacklisted, err := m.IsTokenBlacklisted(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if blacklisted {
+		return nil, domainerrors.ErrTokenRevoked
+	}
+	return m.GetRefreshToken(ctx, token)
+}
+
+func (m *MockTokenRepository) RotateRefreshToken(ctx context.Context, oldToken, newToken string, data *domain.RefreshTokenData, ttl time.Duration) error {
+	if m.RotateRefreshTokenFunc != nil {
+		return m.RotateRefreshTokenFunc(ctx, oldToken, newToken, data, ttl)
+	}
+	// Default behavior: compose the single-operation mocks
+	if err := m.DeleteRefreshToken(ctx, oldToken); err != nil {
+		return err
+	}
+	return m.StoreRefreshToken(ctx, newToken, data, ttl)
+}
+
+func (m *MockTokenRepository) RevokeSession(ctx context.Context, accessToken string, ttl time.Duration, refreshToken string) error {
+	if m.RevokeSessionFunc != nil {
+		return m.RevokeSessionFunc(ctx, accessToken, ttl, refreshToken)
+	}
+	// Default behavior: compose the single-operation mocks
+	if ttl > 0 {
+		if err := m.BlacklistToken(ctx, accessToken, ttl); err != nil {
+			return err
+		}
+	}
+	if refreshToken != "" {
+		return m.DeleteRefreshToken(ctx, refreshToken)
+	}
+	return nil
+}
+
+func (m *MockTokenRepository) DeleteUserTokens(ctx context.Context, userID string, idCitizen int) error {
 	if m.DeleteUserTokensFunc != nil {
-		return m.DeleteUserTokensFunc(ctx, idCitizen)
+		return m.DeleteUserTokensFunc(ctx, userID, idCitizen)
+	}
+	return nil
+}
+
+func (m *MockTokenRepository) CountActiveSessions(ctx context.Context, idCitizen int) (int, error) {
+	if m.CountActiveSessionsFunc != nil {
+		return m.CountActiveSessionsFunc(ctx, idCitizen)
+	}
+	return 0, nil
+}
+
+func (m *MockTokenRepository) SetTokenVersion(ctx context.Context, idCitizen int, version int, ttl time.Duration) error {
+	if m.SetTokenVersionFunc != nil {
+		return m.SetTokenVersionFunc(ctx, idCitizen, version, ttl)
 	}
 	return nil
 }
 
+func (m *MockTokenRepository) GetTokenVersion(ctx context.Context, idCitizen int) (int, error) {
+	if m.GetTokenVersionFunc != nil {
+		return m.GetTokenVersionFunc(ctx, idCitizen)
+	}
+	return 0, nil
+}
+
 // MockMessagePublisher is a mock implementation of ports.MessagePublisher
 type MockMessagePublisher struct {
 	PublishFunc func(ctx context.Context, queueName string, message []byte) error
@@ -209,3 +307,1139 @@ func (m *MockOAuthClientRepository) List(ctx context.Context) ([]*domain.OAuthCl
 	}
 	return nil, nil
 }
+
+// MockNotificationPreferencesRepository is a mock implementation of ports.NotificationPreferencesRepository
+type MockNotificationPreferencesRepository struct {
+	GetByUserIDFunc func(ctx context.Context, userID string) (*domain.NotificationPreferences, error)
+	UpsertFunc      func(ctx context.Context, prefs *domain.NotificationPreferences) error
+}
+
+func (m *MockNotificationPreferencesRepository) GetByUserID(ctx context.Context, userID string) (*domain.NotificationPreferences, error) {
+	if m.GetByUserIDFunc != nil {
+		return m.GetByUserIDFunc(ctx, userID)
+	}
+	// Default behavior: no stored preferences
+	return nil, domainerrors.ErrPreferencesNotFound
+}
+
+func (m *MockNotificationPreferencesRepository) Upsert(ctx context.Context, prefs *domain.NotificationPreferences) error {
+	if m.UpsertFunc != nil {
+		return m.UpsertFunc(ctx, prefs)
+	}
+	return nil
+}
+
+// MockConsentRepository is a mock implementation of ports.ConsentRepository
+type MockConsentRepository struct {
+	GetFunc        func(ctx context.Context, userID, clientID string) (*domain.Consent, error)
+	ListByUserFunc func(ctx context.Context, userID string) ([]*domain.Consent, error)
+	UpsertFunc     func(ctx context.Context, consent *domain.Consent) error
+	DeleteFunc     func(ctx context.Context, userID, clientID string) error
+}
+
+func (m *MockConsentRepository) Get(ctx context.Context, userID, clientID string) (*domain.Consent, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, userID, clientID)
+	}
+	// Default behavior: no consent granted yet
+	return nil, domainerrors.ErrConsentNotFound
+}
+
+func (m *MockConsentRepository) ListByUser(ctx context.Context, userID string) ([]*domain.Consent, error) {
+	if m.ListByUserFunc != nil {
+		return m.ListByUserFunc(ctx, userID)
+	}
+	return []*domain.Consent{}, nil
+}
+
+func (m *MockConsentRepository) Upsert(ctx context.Context, consent *domain.Consent) error {
+	if m.UpsertFunc != nil {
+		return m.UpsertFunc(ctx, consent)
+	}
+	return nil
+}
+
+func (m *MockConsentRepository) Delete(ctx context.Context, userID, clientID string) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, userID, clientID)
+	}
+	return nil
+}
+
+// MockDeviceAuthorizationRepository is a mock implementation of ports.DeviceAuthorizationRepository
+type MockDeviceAuthorizationRepository struct {
+	StoreFunc           func(ctx context.Context, auth *domain.DeviceAuthorization) error
+	GetByDeviceCodeFunc func(ctx context.Context, deviceCode string) (*domain.DeviceAuthorization, error)
+	GetByUserCodeFunc   func(ctx context.Context, userCode string) (*domain.DeviceAuthorization, error)
+	UpdateFunc          func(ctx context.Context, auth *domain.DeviceAuthorization) error
+	RecordPollFunc      func(ctx context.Context, deviceCode string, polledAt time.Time, interval time.Duration) error
+	ConsumeFunc         func(ctx context.Context, deviceCode string) (*domain.DeviceAuthorization, error)
+	DeleteFunc          func(ctx context.Context, auth *domain.DeviceAuthorization) error
+}
+
+func (m *MockDeviceAuthorizationRepository) Store(ctx context.Context, auth *domain.DeviceAuthorization) error {
+	if m.StoreFunc != nil {
+		return m.StoreFunc(ctx, auth)
+	}
+	return nil
+}
+
+func (m *MockDeviceAuthorizationRepository) GetByDeviceCode(ctx context.Context, deviceCode string) (*domain.DeviceAuthorization, error) {
+	if m.GetByDeviceCodeFunc != nil {
+		return m.GetByDeviceCodeFunc(ctx, deviceCode)
+	}
+	// Default behavior: not found
+	return nil, domainerrors.ErrDeviceCodeExpired
+}
+
+func (m *MockDeviceAuthorizationRepository) GetByUserCode(ctx context.Context, userCode string) (*domain.DeviceAuthorization, error) {
+	if m.GetByUserCodeFunc != nil {
+		return m.GetByUserCodeFunc(ctx, userCode)
+	}
+	// Default behavior: not found
+	return nil, domainerrors.ErrInvalidUserCode
+}
+
+func (m *MockDeviceAuthorizationRepository) Update(ctx context.Context, auth *domain.DeviceAuthorization) error {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, auth)
+	}
+	return nil
+}
+
+func (m *MockDeviceAuthorizationRepository) RecordPoll(ctx context.Context, deviceCode string, polledAt time.Time, interval time.Duration) error {
+	if m.RecordPollFunc != nil {
+		return m.RecordPollFunc(ctx, deviceCode, polledAt, interval)
+	}
+	return nil
+}
+
+func (m *MockDeviceAuthorizationRepository) Consume(ctx context.Context, deviceCode string) (*domain.DeviceAuthorization, error) {
+	if m.ConsumeFunc != nil {
+		return m.ConsumeFunc(ctx, deviceCode)
+	}
+	// Default behavior: already consumed
+	return nil, domainerrors.ErrDeviceCodeExpired
+}
+
+func (m *MockDeviceAuthorizationRepository) Delete(ctx context.Context, auth *domain.DeviceAuthorization) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, auth)
+	}
+	return nil
+}
+
+// MockScopeRepository is a mock implementation of ports.ScopeRepository
+type MockScopeRepository struct {
+	CreateFunc     func(ctx context.Context, scope *domain.Scope) error
+	GetByNameFunc  func(ctx context.Context, name string) (*domain.Scope, error)
+	GetByNamesFunc func(ctx context.Context, names []string) ([]*domain.Scope, error)
+	ListFunc       func(ctx context.Context) ([]*domain.Scope, error)
+	UpdateFunc     func(ctx context.Context, scope *domain.Scope) error
+	DeleteFunc     func(ctx context.Context, name string) error
+}
+
+func (m *MockScopeRepository) Create(ctx context.Context, scope *domain.Scope) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, scope)
+	}
+	return nil
+}
+
+func (m *MockScopeRepository) GetByName(ctx context.Context, name string) (*domain.Scope, error) {
+	if m.GetByNameFunc != nil {
+		return m.GetByNameFunc(ctx, name)
+	}
+	return nil, domainerrors.ErrScopeNotFound
+}
+
+func (m *MockScopeRepository) GetByNames(ctx context.Context, names []string) ([]*domain.Scope, error) {
+	if m.GetByNamesFunc != nil {
+		return m.GetByNamesFunc(ctx, names)
+	}
+	// Default behavior: every requested scope is registered
+	scopes := make([]*domain.Scope, 0, len(names))
+	for _, name := range names {
+		scopes = append(scopes, &domain.Scope{Name: name})
+	}
+	return scopes, nil
+}
+
+func (m *MockScopeRepository) List(ctx context.Context) ([]*domain.Scope, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *MockScopeRepository) Update(ctx context.Context, scope *domain.Scope) error {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, scope)
+	}
+	return nil
+}
+
+func (m *MockScopeRepository) Delete(ctx context.Context, name string) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, name)
+	}
+	return nil
+}
+
+// MockRateLimiter is a mock implementation of ports.RateLimiter
+type MockRateLimiter struct {
+	HitFunc  func(ctx context.Context, key string) (*domain.RateLimitStatus, error)
+	PeekFunc func(ctx context.Context, key string) (*domain.RateLimitStatus, error)
+}
+
+func (m *MockRateLimiter) Hit(ctx context.Context, key string) (*domain.RateLimitStatus, error) {
+	if m.HitFunc != nil {
+		return m.HitFunc(ctx, key)
+	}
+	return &domain.RateLimitStatus{Limit: 100, Used: 1, ResetAt: time.Now().Add(time.Minute)}, nil
+}
+
+func (m *MockRateLimiter) Peek(ctx context.Context, key string) (*domain.RateLimitStatus, error) {
+	if m.PeekFunc != nil {
+		return m.PeekFunc(ctx, key)
+	}
+	// Default behavior: nothing counted in the current window
+	return &domain.RateLimitStatus{Limit: 100, Used: 0, ResetAt: time.Now().Add(time.Minute)}, nil
+}
+
+// MockPasswordHasher is a mock implementation of ports.PasswordHasher
+type MockPasswordHasher struct {
+	HashFunc    func(ctx context.Context, password string) (string, error)
+	CompareFunc func(ctx context.Context, hash, password string) (bool, error)
+}
+
+func (m *MockPasswordHasher) Hash(ctx context.Context, password string) (string, error) {
+	if m.HashFunc != nil {
+		return m.HashFunc(ctx, password)
+	}
+	// Default behavior: bcrypt at the minimum cost to keep tests fast
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	return string(hash), err
+}
+
+func (m *MockPasswordHasher) Compare(ctx context.Context, hash, password string) (bool, error) {
+	if m.CompareFunc != nil {
+		return m.CompareFunc(ctx, hash, password)
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil, nil
+}
+
+// MockPhoneNumberRepository is a mock implementation of ports.PhoneNumberRepository
+type MockPhoneNumberRepository struct {
+	GetByUserIDFunc func(ctx context.Context, userID string) (*domain.PhoneNumber, error)
+	GetByNumberFunc func(ctx context.Context, number string) (*domain.PhoneNumber, error)
+	UpsertFunc      func(ctx context.Context, phone *domain.PhoneNumber) error
+	DeleteFunc      func(ctx context.Context, userID string) error
+}
+
+func (m *MockPhoneNumberRepository) GetByUserID(ctx context.Context, userID string) (*domain.PhoneNumber, error) {
+	if m.GetByUserIDFunc != nil {
+		return m.GetByUserIDFunc(ctx, userID)
+	}
+	return nil, domainerrors.ErrPhoneNotFound
+}
+
+func (m *MockPhoneNumberRepository) GetByNumber(ctx context.Context, number string) (*domain.PhoneNumber, error) {
+	if m.GetByNumberFunc != nil {
+		return m.GetByNumberFunc(ctx, number)
+	}
+	return nil, domainerrors.ErrPhoneNotFound
+}
+
+func (m *MockPhoneNumberRepository) Upsert(ctx context.Context, phone *domain.PhoneNumber) error {
+	if m.UpsertFunc != nil {
+		return m.UpsertFunc(ctx, phone)
+	}
+	return nil
+}
+
+func (m *MockPhoneNumberRepository) Delete(ctx context.Context, userID string) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, userID)
+	}
+	return nil
+}
+
+// MockPhoneVerificationRepository is a mock implementation of ports.PhoneVerificationRepository
+type MockPhoneVerificationRepository struct {
+	StoreFunc   func(ctx context.Context, verification *domain.PhoneVerification) error
+	ConsumeFunc func(ctx context.Context, userID string) (*domain.PhoneVerification, error)
+	RestoreFunc func(ctx context.Context, verification *domain.PhoneVerification) error
+	DeleteFunc  func(ctx context.Context, userID string) error
+}
+
+func (m *MockPhoneVerificationRepository) Store(ctx context.Context, verification *domain.PhoneVerification) error {
+	if m.StoreFunc != nil {
+		return m.StoreFunc(ctx, verification)
+	}
+	return nil
+}
+
+func (m *MockPhoneVerificationRepository) Consume(ctx context.Context, userID string) (*domain.PhoneVerification, error) {
+	if m.ConsumeFunc != nil {
+		return m.ConsumeFunc(ctx, userID)
+	}
+	return nil, domainerrors.ErrInvalidVerificationCode
+}
+
+func (m *MockPhoneVerificationRepository) Restore(ctx context.Context, verification *domain.PhoneVerification) error {
+	if m.RestoreFunc != nil {
+		return m.RestoreFunc(ctx, verification)
+	}
+	return nil
+}
+
+func (m *MockPhoneVerificationRepository) Delete(ctx context.Context, userID string) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, userID)
+	}
+	return nil
+}
+
+// MockSMSSender is a mock implementation of ports.SMSSender
+type MockSMSSender struct {
+	SendFunc func(ctx context.Context, phoneNumber, message string) error
+}
+
+func (m *MockSMSSender) Send(ctx context.Context, phoneNumber, message string) error {
+	if m.SendFunc != nil {
+		return m.SendFunc(ctx, phoneNumber, message)
+	}
+	return nil
+}
+
+func (m *MockSMSSender) Provider() string {
+	return "mock"
+}
+
+// MockGeoLocator is a mock implementation of ports.GeoLocator
+type MockGeoLocator struct {
+	LocateFunc func(ctx context.Context, ip string) (*domain.GeoLocation, error)
+}
+
+func (m *MockGeoLocator) Locate(ctx context.Context, ip string) (*domain.GeoLocation, error) {
+	if m.LocateFunc != nil {
+		return m.LocateFunc(ctx, ip)
+	}
+	return nil, domainerrors.ErrLocationNotFound
+}
+
+// MockLoginHistoryRepository is a mock implementation of ports.LoginHistoryRepository
+type MockLoginHistoryRepository struct {
+	RecordFunc func(ctx context.Context, userID string, login *domain.LoginLocation) error
+	RecentFunc func(ctx context.Context, userID string, since time.Time) ([]*domain.LoginLocation, error)
+}
+
+func (m *MockLoginHistoryRepository) Record(ctx context.Context, userID string, login *domain.LoginLocation) error {
+	if m.RecordFunc != nil {
+		return m.RecordFunc(ctx, userID, login)
+	}
+	return nil
+}
+
+func (m *MockLoginHistoryRepository) Recent(ctx context.Context, userID string, since time.Time) ([]*domain.LoginLocation, error) {
+	if m.RecentFunc != nil {
+		return m.RecentFunc(ctx, userID, since)
+	}
+	return nil, nil
+}
+
+// MockNotificationService is a mock implementation of services.NotificationServiceInterface
+type MockNotificationService struct {
+	GetPreferencesFunc    func(ctx context.Context, idCitizen int) (*domain.NotificationPreferences, error)
+	UpdatePreferencesFunc func(ctx context.Context, idCitizen int, newDevice, passwordChange, loginAlert *bool) (*domain.NotificationPreferences, error)
+	NotifyFunc            func(ctx context.Context, user *domain.User, notificationType domain.NotificationType, details map[string]string) error
+	AllowsFunc            func(ctx context.Context, user *domain.User, notificationType domain.NotificationType) (bool, error)
+}
+
+func (m *MockNotificationService) GetPreferences(ctx context.Context, idCitizen int) (*domain.NotificationPreferences, error) {
+	if m.GetPreferencesFunc != nil {
+		return m.GetPreferencesFunc(ctx, idCitizen)
+	}
+	return nil, nil
+}
+
+func (m *MockNotificationService) UpdatePreferences(ctx context.Context, idCitizen int, newDevice, passwordChange, loginAlert *bool) (*domain.NotificationPreferences, error) {
+	if m.UpdatePreferencesFunc != nil {
+		return m.UpdatePreferencesFunc(ctx, idCitizen, newDevice, passwordChange, loginAlert)
+	}
+	return nil, nil
+}
+
+func (m *MockNotificationService) Notify(ctx context.Context, user *domain.User, notificationType domain.NotificationType, details map[string]string) error {
+	if m.NotifyFunc != nil {
+		return m.NotifyFunc(ctx, user, notificationType, details)
+	}
+	return nil
+}
+
+// Allows accepts every notification by default, like the default preferences
+func (m *MockNotificationService) Allows(ctx context.Context, user *domain.User, notificationType domain.NotificationType) (bool, error) {
+	if m.AllowsFunc != nil {
+		return m.AllowsFunc(ctx, user, notificationType)
+	}
+	return true, nil
+}
+
+// MockLoginRiskEvaluator is a mock implementation of services.LoginRiskEvaluator
+type MockLoginRiskEvaluator struct {
+	EvaluateLoginFunc func(ctx context.Context, user *domain.User) *domain.LoginRisk
+}
+
+func (m *MockLoginRiskEvaluator) EvaluateLogin(ctx context.Context, user *domain.User) *domain.LoginRisk {
+	if m.EvaluateLoginFunc != nil {
+		return m.EvaluateLoginFunc(ctx, user)
+	}
+	return nil
+}
+
+// MockRefreshAnomalyDetector is a mock implementation of services.RefreshAnomalyDetector
+type MockRefreshAnomalyDetector struct {
+	EvaluateRefreshFunc func(ctx context.Context, user *domain.User, session *domain.RefreshTokenData) []string
+}
+
+func (m *MockRefreshAnomalyDetector) EvaluateRefresh(ctx context.Context, user *domain.User, session *domain.RefreshTokenData) []string {
+	if m.EvaluateRefreshFunc != nil {
+		return m.EvaluateRefreshFunc(ctx, user, session)
+	}
+	return nil
+}
+
+// MockRefreshActivityRepository is a mock implementation of ports.RefreshActivityRepository
+type MockRefreshActivityRepository struct {
+	RecordFunc func(ctx context.Context, familyID, ip string) (*domain.RefreshActivity, error)
+}
+
+func (m *MockRefreshActivityRepository) Record(ctx context.Context, familyID, ip string) (*domain.RefreshActivity, error) {
+	if m.RecordFunc != nil {
+		return m.RecordFunc(ctx, familyID, ip)
+	}
+	return &domain.RefreshActivity{}, nil
+}
+
+// MockRiskEngine is a mock implementation of services.RiskEngine
+type MockRiskEngine struct {
+	AssessLoginFunc        func(ctx context.Context, user *domain.User) *domain.RiskAssessment
+	AssessRefreshFunc      func(ctx context.Context, user *domain.User, session *domain.RefreshTokenData) *domain.RiskAssessment
+	AssessSudoFunc         func(ctx context.Context, user *domain.User) *domain.RiskAssessment
+	RecordLoginFailureFunc func(ctx context.Context, email string, user *domain.User)
+}
+
+func (m *MockRiskEngine) AssessLogin(ctx context.Context, user *domain.User) *domain.RiskAssessment {
+	if m.AssessLoginFunc != nil {
+		return m.AssessLoginFunc(ctx, user)
+	}
+	return &domain.RiskAssessment{Decision: domain.RiskDecisionAllow}
+}
+
+func (m *MockRiskEngine) AssessRefresh(ctx context.Context, user *domain.User, session *domain.RefreshTokenData) *domain.RiskAssessment {
+	if m.AssessRefreshFunc != nil {
+		return m.AssessRefreshFunc(ctx, user, session)
+	}
+	return &domain.RiskAssessment{Decision: domain.RiskDecisionAllow}
+}
+
+func (m *MockRiskEngine) AssessSudo(ctx context.Context, user *domain.User) *domain.RiskAssessment {
+	if m.AssessSudoFunc != nil {
+		return m.AssessSudoFunc(ctx, user)
+	}
+	return &domain.RiskAssessment{Decision: domain.RiskDecisionAllow}
+}
+
+func (m *MockRiskEngine) RecordLoginFailure(ctx context.Context, email string, user *domain.User) {
+	if m.RecordLoginFailureFunc != nil {
+		m.RecordLoginFailureFunc(ctx, email, user)
+	}
+}
+
+// MockKnownDeviceRepository is a mock implementation of ports.KnownDeviceRepository
+type MockKnownDeviceRepository struct {
+	IsKnownFunc  func(ctx context.Context, userID, deviceID string) (bool, error)
+	RememberFunc func(ctx context.Context, userID, deviceID string) error
+}
+
+func (m *MockKnownDeviceRepository) IsKnown(ctx context.Context, userID, deviceID string) (bool, error) {
+	if m.IsKnownFunc != nil {
+		return m.IsKnownFunc(ctx, userID, deviceID)
+	}
+	return true, nil
+}
+
+func (m *MockKnownDeviceRepository) Remember(ctx context.Context, userID, deviceID string) error {
+	if m.RememberFunc != nil {
+		return m.RememberFunc(ctx, userID, deviceID)
+	}
+	return nil
+}
+
+// MockTokenIssuer is a fake implementation of ports.TokenIssuer that issues fixed tokens
+type MockTokenIssuer struct {
+	GenerateUserTokenPairWithProfileFunc func(user *domain.User, profile domain.TokenProfile, delegation *domain.DelegatedAccess) (*domain.TokenPair, error)
+	ValidateAccessTokenFunc              func(token string) (*domain.TokenClaims, error)
+	ValidateRefreshTokenFunc             func(token string) (*domain.TokenClaims, error)
+	GetTokenExpirationFunc               func(token string) (time.Time, error)
+	RefreshTokenDurationValue            time.Duration
+}
+
+func (m *MockTokenIssuer) GenerateUserTokenPairWithProfile(user *domain.User, profile domain.TokenProfile, delegation *domain.DelegatedAccess) (*domain.TokenPair, error) {
+	if m.GenerateUserTokenPairWithProfileFunc != nil {
+		return m.GenerateUserTokenPairWithProfileFunc(user, profile, delegation)
+	}
+	// Default behavior: tokens naming the user
+	return &domain.TokenPair{
+		AccessToken:  "access-" + user.ID,
+		RefreshToken: "refresh-" + user.ID,
+		TokenType:    "Bearer",
+		ExpiresIn:    900,
+	}, nil
+}
+
+func (m *MockTokenIssuer) ValidateAccessToken(token string) (*domain.TokenClaims, error) {
+	if m.ValidateAccessTokenFunc != nil {
+		return m.ValidateAccessTokenFunc(token)
+	}
+	return nil, domainerrors.ErrInvalidToken
+}
+
+func (m *MockTokenIssuer) ValidateRefreshToken(token string) (*domain.TokenClaims, error) {
+	if m.ValidateRefreshTokenFunc != nil {
+		return m.ValidateRefreshTokenFunc(token)
+	}
+	return nil, domainerrors.ErrInvalidToken
+}
+
+func (m *MockTokenIssuer) GetTokenExpiration(token string) (time.Time, error) {
+	if m.GetTokenExpirationFunc != nil {
+		return m.GetTokenExpirationFunc(token)
+	}
+	return time.Now().Add(15 * time.Minute), nil
+}
+
+func (m *MockTokenIssuer) RefreshTokenDuration() time.Duration {
+	if m.RefreshTokenDurationValue != 0 {
+		return m.RefreshTokenDurationValue
+	}
+	return 7 * 24 * time.Hour
+}
+
+// MockLoginNotifier is a mock implementation of services.LoginNotifier
+type MockLoginNotifier struct {
+	NotifyLoginFunc func(ctx context.Context, user *domain.User, assessment *domain.RiskAssessment)
+}
+
+func (m *MockLoginNotifier) NotifyLogin(ctx context.Context, user *domain.User, assessment *domain.RiskAssessment) {
+	if m.NotifyLoginFunc != nil {
+		m.NotifyLoginFunc(ctx, user, assessment)
+	}
+}
+
+// MockAuditLogRepository is a mock implementation of ports.AuditLogRepository
+type MockAuditLogRepository struct {
+	RecordFunc func(ctx context.Context, record *domain.AuditRecord) error
+}
+
+func (m *MockAuditLogRepository) Record(ctx context.Context, record *domain.AuditRecord) error {
+	if m.RecordFunc != nil {
+		return m.RecordFunc(ctx, record)
+	}
+	return nil
+}
+
+// MockAuditReferenceRepository is a mock implementation of ports.AuditReferenceRepository
+type MockAuditReferenceRepository struct {
+	CountByTargetFunc  func(ctx context.Context, targetID string) (int, error)
+	ReassignTargetFunc func(ctx context.Context, fromTargetID, toTargetID string) (int, error)
+}
+
+func (m *MockAuditReferenceRepository) CountByTarget(ctx context.Context, targetID string) (int, error) {
+	if m.CountByTargetFunc != nil {
+		return m.CountByTargetFunc(ctx, targetID)
+	}
+	return 0, nil
+}
+
+func (m *MockAuditReferenceRepository) ReassignTarget(ctx context.Context, fromTargetID, toTargetID string) (int, error) {
+	if m.ReassignTargetFunc != nil {
+		return m.ReassignTargetFunc(ctx, fromTargetID, toTargetID)
+	}
+	return 0, nil
+}
+
+// MockOutboxRepository is a mock implementation of ports.OutboxRepository
+type MockOutboxRepository struct {
+	EnqueueFunc    func(ctx context.Context, message *domain.OutboxMessage) error
+	ClaimDueFunc   func(ctx context.Context, limit int, lease time.Duration) ([]*domain.OutboxMessage, error)
+	DeleteFunc     func(ctx context.Context, id string) error
+	MarkFailedFunc func(ctx context.Context, id, lastError string, nextAttemptAt time.Time) error
+}
+
+func (m *MockOutboxRepository) Enqueue(ctx context.Context, message *domain.OutboxMessage) error {
+	if m.EnqueueFunc != nil {
+		return m.EnqueueFunc(ctx, message)
+	}
+	return nil
+}
+
+func (m *MockOutboxRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*domain.OutboxMessage, error) {
+	if m.ClaimDueFunc != nil {
+		return m.ClaimDueFunc(ctx, limit, lease)
+	}
+	return nil, nil
+}
+
+func (m *MockOutboxRepository) Delete(ctx context.Context, id string) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockOutboxRepository) MarkFailed(ctx context.Context, id, lastError string, nextAttemptAt time.Time) error {
+	if m.MarkFailedFunc != nil {
+		return m.MarkFailedFunc(ctx, id, lastError, nextAttemptAt)
+	}
+	return nil
+}
+
+// MockProcessedMessageRepository is a mock implementation of ports.ProcessedMessageRepository
+type MockProcessedMessageRepository struct {
+	MarkProcessedFunc func(ctx context.Context, queue, messageID string) (bool, error)
+	UnmarkFunc        func(ctx context.Context, queue, messageID string) error
+}
+
+func (m *MockProcessedMessageRepository) MarkProcessed(ctx context.Context, queue, messageID string) (bool, error) {
+	if m.MarkProcessedFunc != nil {
+		return m.MarkProcessedFunc(ctx, queue, messageID)
+	}
+	return true, nil
+}
+
+func (m *MockProcessedMessageRepository) Unmark(ctx context.Context, queue, messageID string) error {
+	if m.UnmarkFunc != nil {
+		return m.UnmarkFunc(ctx, queue, messageID)
+	}
+	return nil
+}
+
+// MockQuotaRepository is a mock implementation of ports.QuotaRepository
+type MockQuotaRepository struct {
+	GetFunc    func(ctx context.Context, subjectType, subjectID string) (*domain.IssuanceQuota, error)
+	UpsertFunc func(ctx context.Context, quota *domain.IssuanceQuota) error
+	DeleteFunc func(ctx context.Context, subjectType, subjectID string) error
+	ListFunc   func(ctx context.Context) ([]*domain.IssuanceQuota, error)
+}
+
+func (m *MockQuotaRepository) Get(ctx context.Context, subjectType, subjectID string) (*domain.IssuanceQuota, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, subjectType, subjectID)
+	}
+	// Default behavior: no override, the default quota applies
+	return nil, domainerrors.ErrQuotaNotFound
+}
+
+func (m *MockQuotaRepository) Upsert(ctx context.Context, quota *domain.IssuanceQuota) error {
+	if m.UpsertFunc != nil {
+		return m.UpsertFunc(ctx, quota)
+	}
+	return nil
+}
+
+func (m *MockQuotaRepository) Delete(ctx context.Context, subjectType, subjectID string) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, subjectType, subjectID)
+	}
+	return nil
+}
+
+func (m *MockQuotaRepository) List(ctx context.Context) ([]*domain.IssuanceQuota, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx)
+	}
+	return nil, nil
+}
+
+// MockQuotaEnforcer is a mock implementation of services.QuotaEnforcer
+type MockQuotaEnforcer struct {
+	EnforceClientIssuanceFunc func(ctx context.Context, clientID string) error
+	EnforceUserIssuanceFunc   func(ctx context.Context, user *domain.User, newSession bool) error
+}
+
+func (m *MockQuotaEnforcer) EnforceClientIssuance(ctx context.Context, clientID string) error {
+	if m.EnforceClientIssuanceFunc != nil {
+		return m.EnforceClientIssuanceFunc(ctx, clientID)
+	}
+	return nil
+}
+
+func (m *MockQuotaEnforcer) EnforceUserIssuance(ctx context.Context, user *domain.User, newSession bool) error {
+	if m.EnforceUserIssuanceFunc != nil {
+		return m.EnforceUserIssuanceFunc(ctx, user, newSession)
+	}
+	return nil
+}
+
+// MockAuditSink is a mock implementation of ports.AuditSink
+type MockAuditSink struct {
+	SendFunc func(ctx context.Context, records []*domain.AuditRecord) error
+}
+
+func (m *MockAuditSink) Send(ctx context.Context, records []*domain.AuditRecord) error {
+	if m.SendFunc != nil {
+		return m.SendFunc(ctx, records)
+	}
+	return nil
+}
+
+func (m *MockAuditSink) Name() string {
+	return "mock"
+}
+
+// MockAuditExportRepository is a mock implementation of ports.AuditExportRepository
+type MockAuditExportRepository struct {
+	ClaimUnexportedFunc  func(ctx context.Context, limit int, lease time.Duration) ([]*domain.AuditRecord, error)
+	MarkExportedFunc     func(ctx context.Context, ids []string) error
+	MarkExportFailedFunc func(ctx context.Context, ids []string, nextAttemptAt time.Time) error
+	CountUnexportedFunc  func(ctx context.Context) (int, error)
+}
+
+func (m *MockAuditExportRepository) ClaimUnexported(ctx context.Context, limit int, lease time.Duration) ([]*domain.AuditRecord, error) {
+	if m.ClaimUnexportedFunc != nil {
+		return m.ClaimUnexportedFunc(ctx, limit, lease)
+	}
+	return nil, nil
+}
+
+func (m *MockAuditExportRepository) MarkExported(ctx context.Context, ids []string) error {
+	if m.MarkExportedFunc != nil {
+		return m.MarkExportedFunc(ctx, ids)
+	}
+	return nil
+}
+
+func (m *MockAuditExportRepository) MarkExportFailed(ctx context.Context, ids []string, nextAttemptAt time.Time) error {
+	if m.MarkExportFailedFunc != nil {
+		return m.MarkExportFailedFunc(ctx, ids, nextAttemptAt)
+	}
+	return nil
+}
+
+func (m *MockAuditExportRepository) CountUnexported(ctx context.Context) (int, error) {
+	if m.CountUnexportedFunc != nil {
+		return m.CountUnexportedFunc(ctx)
+	}
+	return 0, nil
+}
+
+// MockNonceRepository is a mock implementation of ports.NonceRepository
+type MockNonceRepository struct {
+	UseFunc func(ctx context.Context, clientID, nonce string, ttl time.Duration) (bool, error)
+}
+
+func (m *MockNonceRepository) Use(ctx context.Context, clientID, nonce string, ttl time.Duration) (bool, error) {
+	if m.UseFunc != nil {
+		return m.UseFunc(ctx, clientID, nonce, ttl)
+	}
+	return true, nil
+}
+
+// MockUserCache is a mock implementation of ports.UserCache
+type MockUserCache struct {
+	GetFunc    func(ctx context.Context, idCitizen int) (*domain.UserPublic, error)
+	SetFunc    func(ctx context.Context, user *domain.UserPublic) error
+	DeleteFunc func(ctx context.Context, idCitizen int) error
+}
+
+func (m *MockUserCache) Get(ctx context.Context, idCitizen int) (*domain.UserPublic, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, idCitizen)
+	}
+	return nil, nil
+}
+
+func (m *MockUserCache) Set(ctx context.Context, user *domain.UserPublic) error {
+	if m.SetFunc != nil {
+		return m.SetFunc(ctx, user)
+	}
+	return nil
+}
+
+func (m *MockUserCache) Delete(ctx context.Context, idCitizen int) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, idCitizen)
+	}
+	return nil
+}
+
+// MockClientTokenRepository is a mock implementation of ports.ClientTokenRepository
+type MockClientTokenRepository struct {
+	TrackFunc     func(ctx context.Context, clientID, tokenID string, expiresAt time.Time) error
+	RevokeAllFunc func(ctx context.Context, clientID string) (int, error)
+	IsRevokedFunc func(ctx context.Context, clientID, tokenID string) (bool, error)
+}
+
+func (m *MockClientTokenRepository) Track(ctx context.Context, clientID, tokenID string, expiresAt time.Time) error {
+	if m.TrackFunc != nil {
+		return m.TrackFunc(ctx, clientID, tokenID, expiresAt)
+	}
+	return nil
+}
+
+func (m *MockClientTokenRepository) RevokeAll(ctx context.Context, clientID string) (int, error) {
+	if m.RevokeAllFunc != nil {
+		return m.RevokeAllFunc(ctx, clientID)
+	}
+	return 0, nil
+}
+
+func (m *MockClientTokenRepository) IsRevoked(ctx context.Context, clientID, tokenID string) (bool, error) {
+	if m.IsRevokedFunc != nil {
+		return m.IsRevokedFunc(ctx, clientID, tokenID)
+	}
+	return false, nil
+}
+
+// MockUserExportRepository is a mock implementation of ports.UserExportRepository
+type MockUserExportRepository struct {
+	StreamUsersFunc func(ctx context.Context, filter domain.UserExportFilter, fn func(*domain.User) error) error
+}
+
+func (m *MockUserExportRepository) StreamUsers(ctx context.Context, filter domain.UserExportFilter, fn func(*domain.User) error) error {
+	if m.StreamUsersFunc != nil {
+		return m.StreamUsersFunc(ctx, filter, fn)
+	}
+	return nil
+}
+
+// MockAuditLogQueryRepository is a mock implementation of ports.AuditLogQueryRepository
+type MockAuditLogQueryRepository struct {
+	StreamRecordsFunc func(ctx context.Context, filter domain.AuditLogFilter, fn func(*domain.AuditRecord) error) error
+}
+
+func (m *MockAuditLogQueryRepository) StreamRecords(ctx context.Context, filter domain.AuditLogFilter, fn func(*domain.AuditRecord) error) error {
+	if m.StreamRecordsFunc != nil {
+		return m.StreamRecordsFunc(ctx, filter, fn)
+	}
+	return nil
+}
+
+// MockUserEmailRepository is a mock implementation of ports.UserEmailRepository
+type MockUserEmailRepository struct {
+	CreateFunc         func(ctx context.Context, email *domain.UserEmail) error
+	GetByIDFunc        func(ctx context.Context, userID, id string) (*domain.UserEmail, error)
+	ListByUserIDFunc   func(ctx context.Context, userID string) ([]*domain.UserEmail, error)
+	GetVerifiedFunc    func(ctx context.Context, email string) (*domain.UserEmail, error)
+	UpdateFunc         func(ctx context.Context, email *domain.UserEmail) error
+	DeleteFunc         func(ctx context.Context, userID, id string) error
+	DeleteByUserIDFunc func(ctx context.Context, userID string) error
+}
+
+func (m *MockUserEmailRepository) Create(ctx context.Context, email *domain.UserEmail) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, email)
+	}
+	return nil
+}
+
+func (m *MockUserEmailRepository) GetByID(ctx context.Context, userID, id string) (*domain.UserEmail, error) {
+	if m.GetByIDFunc != nil {
+		return m.GetByIDFunc(ctx, userID, id)
+	}
+	return nil, domainerrors.ErrEmailNotFound
+}
+
+func (m *MockUserEmailRepository) ListByUserID(ctx context.Context, userID string) ([]*domain.UserEmail, error) {
+	if m.ListByUserIDFunc != nil {
+		return m.ListByUserIDFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockUserEmailRepository) GetVerified(ctx context.Context, email string) (*domain.UserEmail, error) {
+	if m.GetVerifiedFunc != nil {
+		return m.GetVerifiedFunc(ctx, email)
+	}
+	return nil, domainerrors.ErrEmailNotFound
+}
+
+func (m *MockUserEmailRepository) Update(ctx context.Context, email *domain.UserEmail) error {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, email)
+	}
+	return nil
+}
+
+func (m *MockUserEmailRepository) Delete(ctx context.Context, userID, id string) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, userID, id)
+	}
+	return nil
+}
+
+func (m *MockUserEmailRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	if m.DeleteByUserIDFunc != nil {
+		return m.DeleteByUserIDFunc(ctx, userID)
+	}
+	return nil
+}
+
+// MockPasswordResetRepository is a mock implementation of ports.PasswordResetRepository
+type MockPasswordResetRepository struct {
+	StoreFunc   func(ctx context.Context, tokenHash string, reset *domain.PasswordReset, ttl time.Duration) error
+	ConsumeFunc func(ctx context.Context, tokenHash string) (*domain.PasswordReset, error)
+}
+
+func (m *MockPasswordResetRepository) Store(ctx context.Context, tokenHash string, reset *domain.PasswordReset, ttl time.Duration) error {
+	if m.StoreFunc != nil {
+		return m.StoreFunc(ctx, tokenHash, reset, ttl)
+	}
+	return nil
+}
+
+func (m *MockPasswordResetRepository) Consume(ctx context.Context, tokenHash string) (*domain.PasswordReset, error) {
+	if m.ConsumeFunc != nil {
+		return m.ConsumeFunc(ctx, tokenHash)
+	}
+	return nil, domainerrors.ErrInvalidResetToken
+}
+
+// MockAvatarRepository is a mock implementation of ports.AvatarRepository
+type MockAvatarRepository struct {
+	GetFunc            func(ctx context.Context, userID string) (*domain.Avatar, error)
+	UpsertFunc         func(ctx context.Context, avatar *domain.Avatar) error
+	DeleteByUserIDFunc func(ctx context.Context, userID string) error
+	ReferencedKeysFunc func(ctx context.Context, keys []string) (map[string]bool, error)
+}
+
+func (m *MockAvatarRepository) Get(ctx context.Context, userID string) (*domain.Avatar, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, userID)
+	}
+	return nil, domainerrors.ErrAvatarNotFound
+}
+
+func (m *MockAvatarRepository) Upsert(ctx context.Context, avatar *domain.Avatar) error {
+	if m.UpsertFunc != nil {
+		return m.UpsertFunc(ctx, avatar)
+	}
+	return nil
+}
+
+func (m *MockAvatarRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	if m.DeleteByUserIDFunc != nil {
+		return m.DeleteByUserIDFunc(ctx, userID)
+	}
+	return nil
+}
+
+func (m *MockAvatarRepository) ReferencedKeys(ctx context.Context, keys []string) (map[string]bool, error) {
+	if m.ReferencedKeysFunc != nil {
+		return m.ReferencedKeysFunc(ctx, keys)
+	}
+	return map[string]bool{}, nil
+}
+
+// MockBlobStorage is a mock implementation of ports.BlobStorage
+type MockBlobStorage struct {
+	PutFunc       func(ctx context.Context, key, contentType string, body io.Reader, size int64) error
+	DeleteFunc    func(ctx context.Context, key string) error
+	ListFunc      func(ctx context.Context, prefix, startAfter string, limit int) ([]domain.BlobObject, error)
+	SignedURLFunc func(key string, expiresIn time.Duration) (string, error)
+}
+
+func (m *MockBlobStorage) Put(ctx context.Context, key, contentType string, body io.Reader, size int64) error {
+	if m.PutFunc != nil {
+		return m.PutFunc(ctx, key, contentType, body, size)
+	}
+	return nil
+}
+
+func (m *MockBlobStorage) Delete(ctx context.Context, key string) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, key)
+	}
+	return nil
+}
+
+func (m *MockBlobStorage) List(ctx context.Context, prefix, startAfter string, limit int) ([]domain.BlobObject, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, prefix, startAfter, limit)
+	}
+	return nil, nil
+}
+
+func (m *MockBlobStorage) SignedURL(key string, expiresIn time.Duration) (string, error) {
+	if m.SignedURLFunc != nil {
+		return m.SignedURLFunc(key, expiresIn)
+	}
+	return "https://storage.example.com/" + key + "?signature=abc", nil
+}
+
+// MockRefreshTokenStore is a mock implementation of ports.RefreshTokenStore
+type MockRefreshTokenStore struct {
+	StoreFunc         func(ctx context.Context, tokenHash string, data *domain.RefreshTokenData, expiresAt time.Time) error
+	GetFunc           func(ctx context.Context, tokenHash string) (*domain.RefreshTokenData, time.Time, error)
+	DeleteFunc        func(ctx context.Context, tokenHash string) error
+	RotateFunc        func(ctx context.Context, oldTokenHash, newTokenHash string, data *domain.RefreshTokenData, expiresAt time.Time) error
+	DeleteByUserFunc  func(ctx context.Context, userID string, idCitizen int) error
+	CountActiveFunc   func(ctx context.Context, idCitizen int) (int, error)
+	DeleteExpiredFunc func(ctx context.Context, before time.Time, limit int) (int, error)
+}
+
+func (m *MockRefreshTokenStore) Store(ctx context.Context, tokenHash string, data *domain.RefreshTokenData, expiresAt time.Time) error {
+	if m.StoreFunc != nil {
+		return m.StoreFunc(ctx, tokenHash, data, expiresAt)
+	}
+	return nil
+}
+
+func (m *MockRefreshTokenStore) Get(ctx context.Context, tokenHash string) (*domain.RefreshTokenData, time.Time, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, tokenHash)
+	}
+	return nil, time.Time{}, domainerrors.ErrInvalidToken
+}
+
+func (m *MockRefreshTokenStore) Delete(ctx context.Context, tokenHash string) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, tokenHash)
+	}
+	return nil
+}
+
+func (m *MockRefreshTokenStore) Rotate(ctx context.Context, oldTokenHash, newTokenHash string, data *domain.RefreshTokenData, expiresAt time.Time) error {
+	if m.RotateFunc != nil {
+		return m.RotateFunc(ctx, oldTokenHash, newTokenHash, data, expiresAt)
+	}
+	return nil
+}
+
+func (m *MockRefreshTokenStore) DeleteByUser(ctx context.Context, userID string, idCitizen int) error {
+	if m.DeleteByUserFunc != nil {
+		return m.DeleteByUserFunc(ctx, userID, idCitizen)
+	}
+	return nil
+}
+
+func (m *MockRefreshTokenStore) CountActive(ctx context.Context, idCitizen int) (int, error) {
+	if m.CountActiveFunc != nil {
+		return m.CountActiveFunc(ctx, idCitizen)
+	}
+	return 0, nil
+}
+
+func (m *MockRefreshTokenStore) DeleteExpired(ctx context.Context, before time.Time, limit int) (int, error) {
+	if m.DeleteExpiredFunc != nil {
+		return m.DeleteExpiredFunc(ctx, before, limit)
+	}
+	return 0, nil
+}
+
+// MockClientUsageCounter is a mock implementation of ports.ClientUsageCounter
+type MockClientUsageCounter struct {
+	RecordIssuanceFunc func(ctx context.Context, clientID string, at time.Time) error
+	RecordErrorFunc    func(ctx context.Context, clientID string) error
+	DrainFunc          func(ctx context.Context) ([]*domain.ClientUsageDelta, error)
+}
+
+func (m *MockClientUsageCounter) RecordIssuance(ctx context.Context, clientID string, at time.Time) error {
+	if m.RecordIssuanceFunc != nil {
+		return m.RecordIssuanceFunc(ctx, clientID, at)
+	}
+	return nil
+}
+
+func (m *MockClientUsageCounter) RecordError(ctx context.Context, clientID string) error {
+	if m.RecordErrorFunc != nil {
+		return m.RecordErrorFunc(ctx, clientID)
+	}
+	return nil
+}
+
+func (m *MockClientUsageCounter) Drain(ctx context.Context) ([]*domain.ClientUsageDelta, error) {
+	if m.DrainFunc != nil {
+		return m.DrainFunc(ctx)
+	}
+	return nil, nil
+}
+
+// MockClientUsageRepository is a mock implementation of ports.ClientUsageRepository
+type MockClientUsageRepository struct {
+	AddFunc  func(ctx context.Context, delta *domain.ClientUsageDelta, errorWindow time.Duration) error
+	ListFunc func(ctx context.Context) (map[string]*domain.ClientUsage, error)
+}
+
+func (m *MockClientUsageRepository) Add(ctx context.Context, delta *domain.ClientUsageDelta, errorWindow time.Duration) error {
+	if m.AddFunc != nil {
+		return m.AddFunc(ctx, delta, errorWindow)
+	}
+	return nil
+}
+
+func (m *MockClientUsageRepository) List(ctx context.Context) (map[string]*domain.ClientUsage, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx)
+	}
+	return map[string]*domain.ClientUsage{}, nil
+}
+
+// MockBlacklistReplicator is a mock implementation of ports.BlacklistReplicator
+type MockBlacklistReplicator struct {
+	PublishFunc   func(ctx context.Context, token *domain.BlacklistedToken) error
+	SubscribeFunc func(ctx context.Context, handle func(ctx context.Context, token *domain.BlacklistedToken) error) error
+}
+
+func (m *MockBlacklistReplicator) Publish(ctx context.Context, token *domain.BlacklistedToken) error {
+	if m.PublishFunc != nil {
+		return m.PublishFunc(ctx, token)
+	}
+	return nil
+}
+
+func (m *MockBlacklistReplicator) Subscribe(ctx context.Context, handle func(ctx context.Context, token *domain.BlacklistedToken) error) error {
+	if m.SubscribeFunc != nil {
+		return m.SubscribeFunc(ctx, handle)
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// MockUserExportJobRepository is a mock implementation of ports.UserExportJobRepository
+type MockUserExportJobRepository struct {
+	StoreFunc           func(ctx context.Context, job *domain.UserExportJob) error
+	GetFunc             func(ctx context.Context, id string) (*domain.UserExportJob, error)
+	GetLatestByUserFunc func(ctx context.Context, userID string) (*domain.UserExportJob, error)
+	UpdateFunc          func(ctx context.Context, job *domain.UserExportJob) error
+}
+
+func (m *MockUserExportJobRepository) Store(ctx context.Context, job *domain.UserExportJob) error {
+	if m.StoreFunc != nil {
+		return m.StoreFunc(ctx, job)
+	}
+	return nil
+}
+
+func (m *MockUserExportJobRepository) Get(ctx context.Context, id string) (*domain.UserExportJob, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, id)
+	}
+	return nil, domainerrors.ErrExportJobNotFound
+}
+
+func (m *MockUserExportJobRepository) GetLatestByUser(ctx context.Context, userID string) (*domain.UserExportJob, error) {
+	if m.GetLatestByUserFunc != nil {
+		return m.GetLatestByUserFunc(ctx, userID)
+	}
+	return nil, domainerrors.ErrExportJobNotFound
+}
+
+func (m *MockUserExportJobRepository) Update(ctx context.Context, job *domain.UserExportJob) error {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, job)
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	"github.com/kristianrpo/auth-microservice/internal/domain/events"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func newTestUser() *domain.User {
+	return &domain.User{ID: "user-123", IDCitizen: 12345, Email: "test@example.com", Name: "Test User", Role: domain.RoleUser, Status: domain.UserStatusActive}
+}
+
+func TestNotificationService_GetPreferences(t *testing.T) {
+	logger := zap.NewNop()
+
+	tests := []struct {
+		name            string
+		getUserFunc     func(ctx context.Context, idCitizen int) (*domain.User, error)
+		getPrefsFunc    func(ctx context.Context, userID string) (*domain.NotificationPreferences, error)
+		wantErr         error
+		wantLoginAlerts bool
+	}{
+		{
+			name: "returns defaults when none stored",
+			getUserFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+				return newTestUser(), nil
+			},
+			wantLoginAlerts: true,
+		},
+		{
+			name: "returns stored preferences",
+			getUserFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+				return newTestUser(), nil
+			},
+			getPrefsFunc: func(ctx context.Context, userID string) (*domain.NotificationPreferences, error) {
+				return &domain.NotificationPreferences{UserID: userID, NewDevice: true, LoginAlert: false}, nil
+			},
+			wantLoginAlerts: false,
+		},
+		{
+			name: "user not found",
+			getUserFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+				return nil, domainerrors.ErrUserNotFound
+			},
+			wantErr: domainerrors.ErrUserNotFound,
+		},
+		{
+			name: "repository error",
+			getUserFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+				return newTestUser(), nil
+			},
+			getPrefsFunc: func(ctx context.Context, userID string) (*domain.NotificationPreferences, error) {
+				return nil, errors.New("db down")
+			},
+			wantErr: domainerrors.ErrInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := &MockUserRepository{GetByIDCitizenFunc: tt.getUserFunc}
+			prefsRepo := &MockNotificationPreferencesRepository{GetByUserIDFunc: tt.getPrefsFunc}
+			service := services.NewNotificationService(userRepo, prefsRepo, &MockMessagePublisher{}, "test.notifications", logger)
+
+			prefs, err := service.GetPreferences(context.Background(), 12345)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GetPreferences() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetPreferences() unexpected error = %v", err)
+			}
+			if prefs.LoginAlert != tt.wantLoginAlerts {
+				t.Errorf("LoginAlert = %v, want %v", prefs.LoginAlert, tt.wantLoginAlerts)
+			}
+		})
+	}
+}
+
+func TestNotificationService_UpdatePreferences(t *testing.T) {
+	logger := zap.NewNop()
+	disabled := false
+
+	var saved *domain.NotificationPreferences
+	userRepo := &MockUserRepository{
+		GetByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+			return newTestUser(), nil
+		},
+	}
+	prefsRepo := &MockNotificationPreferencesRepository{
+		UpsertFunc: func(ctx context.Context, prefs *domain.NotificationPreferences) error {
+			saved = prefs
+			return nil
+		},
+	}
+	service := services.NewNotificationService(userRepo, prefsRepo, &MockMessagePublisher{}, "test.notifications", logger)
+
+	prefs, err := service.UpdatePreferences(context.Background(), 12345, nil, nil, &disabled)
+	if err != nil {
+		t.Fatalf("UpdatePreferences() unexpected error = %v", err)
+	}
+	if saved == nil || saved.UserID != "user-123" {
+		t.Fatalf("UpdatePreferences() did not persist preferences for the user, got %+v", saved)
+	}
+	if prefs.LoginAlert {
+		t.Error("LoginAlert should be disabled")
+	}
+	if !prefs.NewDevice || !prefs.PasswordChange {
+		t.Error("omitted fields should keep their default value")
+	}
+
+	prefsRepo.UpsertFunc = func(ctx context.Context, prefs *domain.NotificationPreferences) error {
+		return errors.New("db down")
+	}
+	if _, err := service.UpdatePreferences(context.Background(), 12345, nil, nil, &disabled); !errors.Is(err, domainerrors.ErrInternal) {
+		t.Errorf("UpdatePreferences() error = %v, want %v", err, domainerrors.ErrInternal)
+	}
+}
+
+func TestNotificationService_Notify(t *testing.T) {
+	logger := zap.NewNop()
+
+	tests := []struct {
+		name          string
+		prefs         *domain.NotificationPreferences
+		publishErr    error
+		wantPublished bool
+		wantErr       bool
+	}{
+		{
+			name:          "publishes when allowed by defaults",
+			wantPublished: true,
+		},
+		{
+			name:          "suppressed by preferences",
+			prefs:         &domain.NotificationPreferences{UserID: "user-123", LoginAlert: false},
+			wantPublished: false,
+		},
+		{
+			name:          "publish failure is returned",
+			publishErr:    errors.New("broker down"),
+			wantPublished: true,
+			wantErr:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefsRepo := &MockNotificationPreferencesRepository{}
+			if tt.prefs != nil {
+				prefsRepo.GetByUserIDFunc = func(ctx context.Context, userID string) (*domain.NotificationPreferences, error) {
+					return tt.prefs, nil
+				}
+			}
+
+			published := false
+			publisher := &MockMessagePublisher{
+				PublishFunc: func(ctx context.Context, queueName string, message []byte) error {
+					published = true
+					if queueName != "test.notifications" {
+						t.Errorf("queue = %v, want test.notifications", queueName)
+					}
+					var event events.SecurityNotificationEvent
+					if err := json.Unmarshal(message, &event); err != nil {
+						t.Fatalf("invalid event payload: %v", err)
+					}
+					if event.Type != string(domain.NotificationLoginAlert) || event.IDCitizen != 12345 {
+						t.Errorf("unexpected event %+v", event)
+					}
+					return tt.publishErr
+				},
+			}
+
+			service := services.NewNotificationService(&MockUserRepository{}, prefsRepo, publisher, "test.notifications", logger)
+			err := service.Notify(context.Background(), newTestUser(), domain.NotificationLoginAlert, map[string]string{"ip": "127.0.0.1"})
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Notify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if published != tt.wantPublished {
+				t.Errorf("published = %v, want %v", published, tt.wantPublished)
+			}
+		})
+	}
+}
+
+func TestNotificationService_Allows(t *testing.T) {
+	prefsRepo := &MockNotificationPreferencesRepository{
+		GetByUserIDFunc: func(ctx context.Context, userID string) (*domain.NotificationPreferences, error) {
+			return &domain.NotificationPreferences{UserID: userID, NewDevice: false, LoginAlert: true}, nil
+		},
+	}
+	service := services.NewNotificationService(&MockUserRepository{}, prefsRepo, &MockMessagePublisher{}, "test.notifications", zap.NewNop())
+
+	if allowed, err := service.Allows(context.Background(), newTestUser(), domain.NotificationNewDevice); err != nil || allowed {
+		t.Errorf("Allows(new_device) = %v, %v, want false", allowed, err)
+	}
+	if allowed, err := service.Allows(context.Background(), newTestUser(), domain.NotificationLoginAlert); err != nil || !allowed {
+		t.Errorf("Allows(login_alert) = %v, %v, want true", allowed, err)
+	}
+
+	prefsRepo.GetByUserIDFunc = func(ctx context.Context, userID string) (*domain.NotificationPreferences, error) {
+		return nil, errors.New("db down")
+	}
+	if _, err := service.Allows(context.Background(), newTestUser(), domain.NotificationNewDevice); !errors.Is(err, domainerrors.ErrInternal) {
+		t.Errorf("Allows() error = %v, want ErrInternal", err)
+	}
+}
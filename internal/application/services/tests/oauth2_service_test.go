@@ -2,6 +2,9 @@ package tests
 
 import (
 	"context"
+	"errors"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -10,6 +13,7 @@ import (
 	"github.com/kristianrpo/auth-microservice/internal/application/services"
 	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
 	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/hashing"
 )
 
 func TestOAuth2Service_ClientCredentials(t *testing.T) {
@@ -67,6 +71,44 @@ func TestOAuth2Service_ClientCredentials(t *testing.T) {
 			wantErr:     true,
 			expectedErr: domainerrors.ErrInvalidClient,
 		},
+		{
+			name:         "grant not allowed to client",
+			clientID:     "client-123",
+			clientSecret: "secret123",
+			getByClientIDFunc: func(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+				deviceClient, _ := domain.NewOAuthClient("client-123", "secret123", "Test Client", "Test Description", []string{"read"})
+				deviceClient.GrantTypes = []string{domain.GrantTypeDeviceCode}
+				return deviceClient, nil
+			},
+			wantErr:     true,
+			expectedErr: domainerrors.ErrUnauthorizedClient,
+		},
+		{
+			name:         "expired secret",
+			clientID:     "client-123",
+			clientSecret: "secret123",
+			getByClientIDFunc: func(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+				expiredClient, _ := domain.NewOAuthClient("client-123", "secret123", "Test Client", "Test Description", []string{"read"})
+				expiresAt := time.Now().Add(-time.Minute)
+				expiredClient.SecretExpiresAt = &expiresAt
+				return expiredClient, nil
+			},
+			wantErr:     true,
+			expectedErr: domainerrors.ErrClientSecretExpired,
+		},
+		{
+			name:         "wrong secret of a client with an expired secret",
+			clientID:     "client-123",
+			clientSecret: "wrongsecret",
+			getByClientIDFunc: func(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+				expiredClient, _ := domain.NewOAuthClient("client-123", "secret123", "Test Client", "Test Description", []string{"read"})
+				expiresAt := time.Now().Add(-time.Minute)
+				expiredClient.SecretExpiresAt = &expiresAt
+				return expiredClient, nil
+			},
+			wantErr:     true,
+			expectedErr: domainerrors.ErrInvalidCredentials,
+		},
 	}
 
 	for _, tt := range tests {
@@ -74,9 +116,9 @@ func TestOAuth2Service_ClientCredentials(t *testing.T) {
 			mockClientRepo := &MockOAuthClientRepository{
 				GetByClientIDFunc: tt.getByClientIDFunc,
 			}
-			oauth2Service := services.NewOAuth2Service(mockClientRepo, "test-secret-key-at-least-32-chars-long", 15*time.Minute, logger)
+			oauth2Service := services.NewOAuth2Service(mockClientRepo, &MockScopeRepository{}, "test-secret-key-at-least-32-chars-long", 15*time.Minute, services.TokenSigningPolicy{}, logger)
 
-			token, expiresIn, err := oauth2Service.ClientCredentials(context.Background(), tt.clientID, tt.clientSecret)
+			token, expiresAt, err := oauth2Service.ClientCredentials(context.Background(), tt.clientID, tt.clientSecret, nil, "")
 
 			if tt.wantErr {
 				if err == nil {
@@ -98,13 +140,128 @@ func TestOAuth2Service_ClientCredentials(t *testing.T) {
 				t.Errorf("ClientCredentials() returned empty token")
 			}
 
-			if expiresIn <= 0 {
-				t.Errorf("ClientCredentials() expiresIn = %v, want > 0", expiresIn)
+			if !expiresAt.After(time.Now()) {
+				t.Errorf("ClientCredentials() expiresAt = %v, want in the future", expiresAt)
+			}
+		})
+	}
+}
+
+func TestOAuth2Service_ClientCredentialsTTLJitter(t *testing.T) {
+	tests := []struct {
+		name          string
+		jitterPercent int
+		wantMin       time.Duration
+	}{
+		{name: "no jitter", jitterPercent: 0, wantMin: 15 * time.Minute},
+		{name: "jitter shortens the lifetime", jitterPercent: 20, wantMin: 12 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientRepo := &MockOAuthClientRepository{
+				GetByClientIDFunc: func(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+					return domain.NewOAuthClient("client-123", "secret123", "Test Client", "Test Description", []string{"read"})
+				},
+			}
+			oauth2Service := services.NewOAuth2Service(clientRepo, &MockScopeRepository{}, "test-secret-key-at-least-32-chars-long", 15*time.Minute, services.TokenSigningPolicy{}, zap.NewNop(), services.WithClientTokenTTLJitter(tt.jitterPercent))
+
+			lifetimes := make(map[int64]bool)
+			for i := 0; i < 5; i++ {
+				before := time.Now()
+				token, expiresAt, err := oauth2Service.ClientCredentials(context.Background(), "client-123", "secret123", nil, "")
+				if err != nil {
+					t.Fatalf("ClientCredentials() unexpected error: %v", err)
+				}
+				after := time.Now()
+
+				// Expirations have a precision of seconds, allow for the truncation
+				if expiresAt.Before(before.Add(tt.wantMin-time.Second)) || expiresAt.After(after.Add(15*time.Minute)) {
+					t.Errorf("token expires at %v, want between %v and %v after issuance", expiresAt, tt.wantMin, 15*time.Minute)
+				}
+				lifetimes[expiresAt.Unix()] = true
+
+				claims, err := oauth2Service.ValidateAccessToken(context.Background(), token)
+				if err != nil {
+					t.Fatalf("ValidateAccessToken() unexpected error: %v", err)
+				}
+				if claims.ExpireAt != expiresAt.Unix() {
+					t.Errorf("exp claim = %v, want %v", claims.ExpireAt, expiresAt.Unix())
+				}
+			}
+
+			if tt.jitterPercent > 0 && len(lifetimes) < 2 {
+				t.Errorf("token expirations = %v, want them spread by the jitter", lifetimes)
 			}
 		})
 	}
 }
 
+func TestOAuth2Service_ClientCredentialsRegion(t *testing.T) {
+	clientRepo := &MockOAuthClientRepository{
+		GetByClientIDFunc: func(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+			return domain.NewOAuthClient("client-123", "secret123", "Test Client", "Test Description", []string{"read"})
+		},
+	}
+	var trackedID string
+	clientTokenRepo := &MockClientTokenRepository{
+		TrackFunc: func(ctx context.Context, clientID, tokenID string, expiresAt time.Time) error {
+			trackedID = tokenID
+			return nil
+		},
+	}
+	oauth2Service := services.NewOAuth2Service(clientRepo, &MockScopeRepository{}, "test-secret-key-at-least-32-chars-long", 15*time.Minute, services.TokenSigningPolicy{Region: "us-east-1"}, zap.NewNop(), services.WithClientTokenRepository(clientTokenRepo))
+
+	token, _, err := oauth2Service.ClientCredentials(context.Background(), "client-123", "secret123", nil, "")
+	if err != nil {
+		t.Fatalf("ClientCredentials() unexpected error: %v", err)
+	}
+
+	claims := decodeTokenClaims(t, token)
+	if claims["region"] != "us-east-1" || claims["jti"] != trackedID || domain.TokenIDRegion(trackedID) != "us-east-1" {
+		t.Errorf("token claims = %v, tracked ID = %q, want the region in the claim and the tracked jti", claims, trackedID)
+	}
+}
+
+func TestOAuth2Service_ClientCredentialsCertificateBound(t *testing.T) {
+	clientRepo := &MockOAuthClientRepository{
+		GetByClientIDFunc: func(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+			return domain.NewOAuthClient("client-123", "secret123", "Test Client", "Test Description", []string{"read"})
+		},
+	}
+	oauth2Service := services.NewOAuth2Service(clientRepo, &MockScopeRepository{}, "test-secret-key-at-least-32-chars-long", 15*time.Minute, services.TokenSigningPolicy{}, zap.NewNop())
+
+	token, _, err := oauth2Service.ClientCredentials(context.Background(), "client-123", "secret123", nil, "thumbprint")
+	if err != nil {
+		t.Fatalf("ClientCredentials() unexpected error: %v", err)
+	}
+	cnf, ok := decodeTokenClaims(t, token)["cnf"].(map[string]interface{})
+	if !ok || cnf["x5t#S256"] != "thumbprint" {
+		t.Errorf("cnf claim = %v, want the x5t#S256 thumbprint", cnf)
+	}
+
+	claims, err := oauth2Service.ValidateAccessToken(context.Background(), token)
+	if err != nil {
+		t.Fatalf("ValidateAccessToken() unexpected error: %v", err)
+	}
+	if claims.Confirmation == nil || claims.Confirmation.CertificateThumbprint != "thumbprint" {
+		t.Errorf("Confirmation = %+v, want the thumbprint of the certificate", claims.Confirmation)
+	}
+
+	// Tokens requested without a certificate are not bound
+	token, _, err = oauth2Service.ClientCredentials(context.Background(), "client-123", "secret123", nil, "")
+	if err != nil {
+		t.Fatalf("ClientCredentials() unexpected error: %v", err)
+	}
+	if _, ok := decodeTokenClaims(t, token)["cnf"]; ok {
+		t.Error("unexpected cnf claim in a token requested without a certificate")
+	}
+	claims, err = oauth2Service.ValidateAccessToken(context.Background(), token)
+	if err != nil || claims.Confirmation != nil {
+		t.Errorf("ValidateAccessToken() = %+v, %v, want no confirmation", claims, err)
+	}
+}
+
 func TestOAuth2Service_CreateClient(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
 
@@ -115,8 +272,12 @@ func TestOAuth2Service_CreateClient(t *testing.T) {
 		clientName        string
 		description       string
 		scopes            []string
+		grantTypes        []string
+		tags              []string
 		getByClientIDFunc func(ctx context.Context, clientID string) (*domain.OAuthClient, error)
 		createFunc        func(ctx context.Context, client *domain.OAuthClient) error
+		wantGrantTypes    []string
+		wantTags          []string
 		wantErr           bool
 	}{
 		{
@@ -132,7 +293,64 @@ func TestOAuth2Service_CreateClient(t *testing.T) {
 			createFunc: func(ctx context.Context, client *domain.OAuthClient) error {
 				return nil
 			},
-			wantErr: false,
+			wantGrantTypes: domain.DefaultGrantTypes(),
+			wantTags:       []string{},
+			wantErr:        false,
+		},
+		{
+			name:         "tags normalized",
+			clientID:     "new-client",
+			clientSecret: "newsecret123",
+			clientName:   "New Client",
+			scopes:       []string{"read"},
+			tags:         []string{"Team:Payments", " env:staging ", "team:payments"},
+			getByClientIDFunc: func(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+				return nil, domainerrors.ErrClientNotFound
+			},
+			createFunc: func(ctx context.Context, client *domain.OAuthClient) error {
+				return nil
+			},
+			wantGrantTypes: domain.DefaultGrantTypes(),
+			wantTags:       []string{"team:payments", "env:staging"},
+		},
+		{
+			name:         "invalid tag",
+			clientID:     "new-client",
+			clientSecret: "newsecret123",
+			clientName:   "New Client",
+			scopes:       []string{"read"},
+			tags:         []string{"team payments"},
+			getByClientIDFunc: func(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+				return nil, domainerrors.ErrClientNotFound
+			},
+			wantErr: true,
+		},
+		{
+			name:         "grant types with short names",
+			clientID:     "new-client",
+			clientSecret: "newsecret123",
+			clientName:   "New Client",
+			scopes:       []string{"read"},
+			grantTypes:   []string{"authorization_code", "token_exchange", "device_code", "authorization_code"},
+			getByClientIDFunc: func(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+				return nil, domainerrors.ErrClientNotFound
+			},
+			createFunc: func(ctx context.Context, client *domain.OAuthClient) error {
+				return nil
+			},
+			wantGrantTypes: []string{domain.GrantTypeAuthorizationCode, domain.GrantTypeTokenExchange, domain.GrantTypeDeviceCode},
+		},
+		{
+			name:         "unknown grant type",
+			clientID:     "new-client",
+			clientSecret: "newsecret123",
+			clientName:   "New Client",
+			scopes:       []string{"read"},
+			grantTypes:   []string{"password"},
+			getByClientIDFunc: func(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+				return nil, domainerrors.ErrClientNotFound
+			},
+			wantErr: true,
 		},
 		{
 			name:         "client already exists",
@@ -170,9 +388,9 @@ func TestOAuth2Service_CreateClient(t *testing.T) {
 				GetByClientIDFunc: tt.getByClientIDFunc,
 				CreateFunc:        tt.createFunc,
 			}
-			oauth2Service := services.NewOAuth2Service(mockClientRepo, "test-secret-key-at-least-32-chars-long", 15*time.Minute, logger)
+			oauth2Service := services.NewOAuth2Service(mockClientRepo, &MockScopeRepository{}, "test-secret-key-at-least-32-chars-long", 15*time.Minute, services.TokenSigningPolicy{}, logger)
 
-			client, err := oauth2Service.CreateClient(context.Background(), tt.clientID, tt.clientSecret, tt.clientName, tt.description, tt.scopes)
+			client, err := oauth2Service.CreateClient(context.Background(), tt.clientID, tt.clientSecret, tt.clientName, tt.description, tt.scopes, tt.grantTypes, nil, tt.tags, nil)
 
 			if tt.wantErr {
 				if err == nil {
@@ -194,6 +412,12 @@ func TestOAuth2Service_CreateClient(t *testing.T) {
 			if client.ClientID != tt.clientID {
 				t.Errorf("CreateClient() ClientID = %v, want %v", client.ClientID, tt.clientID)
 			}
+			if !reflect.DeepEqual(client.GrantTypes, tt.wantGrantTypes) {
+				t.Errorf("CreateClient() GrantTypes = %v, want %v", client.GrantTypes, tt.wantGrantTypes)
+			}
+			if tt.wantTags != nil && !reflect.DeepEqual(client.Tags, tt.wantTags) {
+				t.Errorf("CreateClient() Tags = %v, want %v", client.Tags, tt.wantTags)
+			}
 		})
 	}
 }
@@ -203,10 +427,13 @@ func TestOAuth2Service_ListClients(t *testing.T) {
 
 	client1, _ := domain.NewOAuthClient("client-1", "secret1", "Client 1", "Desc 1", []string{"read"})
 	client2, _ := domain.NewOAuthClient("client-2", "secret2", "Client 2", "Desc 2", []string{"write"})
+	client1.Tags = []string{"team:payments", "env:staging"}
+	client2.Tags = []string{"team:payments", "env:production"}
 
 	tests := []struct {
 		name     string
 		listFunc func(ctx context.Context) ([]*domain.OAuthClient, error)
+		tags     []string
 		wantErr  bool
 		wantLen  int
 	}{
@@ -218,6 +445,30 @@ func TestOAuth2Service_ListClients(t *testing.T) {
 			wantErr: false,
 			wantLen: 2,
 		},
+		{
+			name: "filter by tag",
+			listFunc: func(ctx context.Context) ([]*domain.OAuthClient, error) {
+				return []*domain.OAuthClient{client1, client2}, nil
+			},
+			tags:    []string{"team:payments"},
+			wantLen: 2,
+		},
+		{
+			name: "filter by all the tags",
+			listFunc: func(ctx context.Context) ([]*domain.OAuthClient, error) {
+				return []*domain.OAuthClient{client1, client2}, nil
+			},
+			tags:    []string{"team:payments", "ENV:staging"},
+			wantLen: 1,
+		},
+		{
+			name: "no client with the tag",
+			listFunc: func(ctx context.Context) ([]*domain.OAuthClient, error) {
+				return []*domain.OAuthClient{client1, client2}, nil
+			},
+			tags:    []string{"team:identity"},
+			wantLen: 0,
+		},
 		{
 			name: "empty list",
 			listFunc: func(ctx context.Context) ([]*domain.OAuthClient, error) {
@@ -240,9 +491,9 @@ func TestOAuth2Service_ListClients(t *testing.T) {
 			mockClientRepo := &MockOAuthClientRepository{
 				ListFunc: tt.listFunc,
 			}
-			oauth2Service := services.NewOAuth2Service(mockClientRepo, "test-secret-key-at-least-32-chars-long", 15*time.Minute, logger)
+			oauth2Service := services.NewOAuth2Service(mockClientRepo, &MockScopeRepository{}, "test-secret-key-at-least-32-chars-long", 15*time.Minute, services.TokenSigningPolicy{}, logger)
 
-			clients, err := oauth2Service.ListClients(context.Background())
+			clients, err := oauth2Service.ListClients(context.Background(), tt.tags)
 
 			if tt.wantErr {
 				if err == nil {
@@ -297,7 +548,7 @@ func TestOAuth2Service_GetClient(t *testing.T) {
 			mockClientRepo := &MockOAuthClientRepository{
 				GetByIDFunc: tt.getByIDFunc,
 			}
-			oauth2Service := services.NewOAuth2Service(mockClientRepo, "test-secret-key-at-least-32-chars-long", 15*time.Minute, logger)
+			oauth2Service := services.NewOAuth2Service(mockClientRepo, &MockScopeRepository{}, "test-secret-key-at-least-32-chars-long", 15*time.Minute, services.TokenSigningPolicy{}, logger)
 
 			client, err := oauth2Service.GetClient(context.Background(), tt.clientID)
 
@@ -352,7 +603,7 @@ func TestOAuth2Service_DeleteClient(t *testing.T) {
 			mockClientRepo := &MockOAuthClientRepository{
 				DeleteFunc: tt.deleteFunc,
 			}
-			oauth2Service := services.NewOAuth2Service(mockClientRepo, "test-secret-key-at-least-32-chars-long", 15*time.Minute, logger)
+			oauth2Service := services.NewOAuth2Service(mockClientRepo, &MockScopeRepository{}, "test-secret-key-at-least-32-chars-long", 15*time.Minute, services.TokenSigningPolicy{}, logger)
 
 			err := oauth2Service.DeleteClient(context.Background(), tt.clientID)
 
@@ -366,3 +617,304 @@ func TestOAuth2Service_DeleteClient(t *testing.T) {
 		})
 	}
 }
+
+func TestOAuth2Service_CreateClient_UnregisteredScope(t *testing.T) {
+	logger := zap.NewNop()
+
+	mockClientRepo := &MockOAuthClientRepository{
+		GetByClientIDFunc: func(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+			return nil, domainerrors.ErrClientNotFound
+		},
+		CreateFunc: func(ctx context.Context, client *domain.OAuthClient) error {
+			t.Error("Create() should not be called with unregistered scopes")
+			return nil
+		},
+	}
+	mockScopeRepo := &MockScopeRepository{
+		GetByNamesFunc: func(ctx context.Context, names []string) ([]*domain.Scope, error) {
+			return []*domain.Scope{{Name: "read", System: true}}, nil
+		},
+	}
+	oauth2Service := services.NewOAuth2Service(mockClientRepo, mockScopeRepo, "test-secret-key-at-least-32-chars-long", 15*time.Minute, services.TokenSigningPolicy{}, logger)
+
+	_, err := oauth2Service.CreateClient(context.Background(), "new-client", "newsecret123", "New Client", "", []string{"read", "admin"}, nil, nil, nil, nil)
+	if !errors.Is(err, domainerrors.ErrUnknownScope) {
+		t.Errorf("CreateClient() error = %v, want %v", err, domainerrors.ErrUnknownScope)
+	}
+}
+
+func TestOAuth2Service_ClientSecretHasher(t *testing.T) {
+	var stored *domain.OAuthClient
+	clientRepo := &MockOAuthClientRepository{
+		GetByClientIDFunc: func(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+			if stored == nil {
+				return nil, domainerrors.ErrClientNotFound
+			}
+			return stored, nil
+		},
+		CreateFunc: func(ctx context.Context, client *domain.OAuthClient) error {
+			stored = client
+			return nil
+		},
+	}
+	oauth2Service := services.NewOAuth2Service(clientRepo, registeredScopeRepository(), "test-secret-key-at-least-32-chars-long", 15*time.Minute, services.TokenSigningPolicy{}, zap.NewNop(),
+		services.WithClientSecretHasher(hashing.NewPBKDF2Hasher(1000, nil)))
+
+	if _, err := oauth2Service.CreateClient(context.Background(), "new-client", "newsecret123", "New Client", "", []string{"read"}, nil, nil, nil, nil); err != nil {
+		t.Fatalf("CreateClient() unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(stored.ClientSecret, hashing.PBKDF2Prefix) {
+		t.Errorf("client secret hash = %q, want a PBKDF2 hash", stored.ClientSecret)
+	}
+
+	if _, err := oauth2Service.AuthenticateClient(context.Background(), "new-client", "newsecret123"); err != nil {
+		t.Errorf("AuthenticateClient() unexpected error: %v", err)
+	}
+	if _, err := oauth2Service.AuthenticateClient(context.Background(), "new-client", "wrong-secret"); !errors.Is(err, domainerrors.ErrInvalidCredentials) {
+		t.Errorf("AuthenticateClient() error = %v, want %v", err, domainerrors.ErrInvalidCredentials)
+	}
+}
+
+func TestOAuth2Service_CountUnapprovedClientSecrets(t *testing.T) {
+	bcryptClient, _ := domain.NewOAuthClient("bcrypt-client", "secret123", "Bcrypt Client", "", []string{"read"})
+	pbkdf2Client, _ := domain.NewOAuthClientWithHasher("pbkdf2-client", "secret123", "PBKDF2 Client", "", []string{"read"}, func(secret string) (string, error) {
+		return hashing.NewPBKDF2Hasher(1000, nil).Hash(context.Background(), secret)
+	})
+	clientRepo := &MockOAuthClientRepository{
+		ListFunc: func(ctx context.Context) ([]*domain.OAuthClient, error) {
+			return []*domain.OAuthClient{bcryptClient, pbkdf2Client}, nil
+		},
+	}
+	oauth2Service := services.NewOAuth2Service(clientRepo, &MockScopeRepository{}, "test-secret-key-at-least-32-chars-long", 15*time.Minute, services.TokenSigningPolicy{}, zap.NewNop())
+
+	unapproved, err := oauth2Service.CountUnapprovedClientSecrets(context.Background(), func(hash string) bool {
+		return strings.HasPrefix(hash, hashing.PBKDF2Prefix)
+	})
+	if err != nil {
+		t.Fatalf("CountUnapprovedClientSecrets() unexpected error: %v", err)
+	}
+	if unapproved != 1 {
+		t.Errorf("CountUnapprovedClientSecrets() = %d, want 1", unapproved)
+	}
+}
+
+func TestOAuth2Service_UpdateClient(t *testing.T) {
+	logger := zap.NewNop()
+	newName := "Renamed Client"
+	emptyName := ""
+	minimalProfile := domain.TokenProfileMinimal
+	unknownProfile := domain.TokenProfile("tiny")
+
+	tests := []struct {
+		name         string
+		clientName   *string
+		scopes       []string
+		tokenProfile *domain.TokenProfile
+		grantTypes   []string
+		redirectURIs []string
+		getByIDErr   error
+		expectedErr  error
+		wantName     string
+		wantScopes   []string
+		wantProfile  domain.TokenProfile
+		wantGrants   []string
+	}{
+		{name: "update name keeps scopes", clientName: &newName, wantName: "Renamed Client", wantScopes: []string{"read"}, wantProfile: domain.TokenProfileStandard},
+		{name: "update scopes", scopes: []string{"read", "write"}, wantName: "Test Client", wantScopes: []string{"read", "write"}, wantProfile: domain.TokenProfileStandard},
+		{name: "update token profile", tokenProfile: &minimalProfile, wantName: "Test Client", wantScopes: []string{"read"}, wantProfile: domain.TokenProfileMinimal},
+		{name: "update grant types", grantTypes: []string{"client_credentials", "token_exchange"}, wantName: "Test Client", wantScopes: []string{"read"}, wantProfile: domain.TokenProfileStandard,
+			wantGrants: []string{domain.GrantTypeClientCredentials, domain.GrantTypeTokenExchange}},
+		{name: "no grant types", grantTypes: []string{}, expectedErr: domainerrors.ErrBadRequest},
+		{name: "unknown grant type", grantTypes: []string{"implicit"}, expectedErr: domainerrors.ErrBadRequest},
+		{name: "update redirect uris", redirectURIs: []string{"https://app.example.com/cb", "http://127.0.0.1/cb"}, wantName: "Test Client", wantScopes: []string{"read"}, wantProfile: domain.TokenProfileStandard},
+		{name: "invalid redirect uri", redirectURIs: []string{"http://app.example.com/cb"}, expectedErr: domainerrors.ErrInvalidRedirectURI},
+		{name: "unknown token profile", tokenProfile: &unknownProfile, expectedErr: domainerrors.ErrBadRequest},
+		{name: "unregistered scope", scopes: []string{"admin"}, expectedErr: domainerrors.ErrUnknownScope},
+		{name: "empty name", clientName: &emptyName, expectedErr: domainerrors.ErrBadRequest},
+		{name: "client not found", getByIDErr: domainerrors.ErrClientNotFound, expectedErr: domainerrors.ErrClientNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := domain.NewOAuthClient("client-123", "secret123", "Test Client", "", []string{"read"})
+			client.ID = "id-123"
+			client.Version = 2
+
+			mockClientRepo := &MockOAuthClientRepository{
+				GetByIDFunc: func(ctx context.Context, id string) (*domain.OAuthClient, error) {
+					if tt.getByIDErr != nil {
+						return nil, tt.getByIDErr
+					}
+					return client, nil
+				},
+			}
+			mockScopeRepo := &MockScopeRepository{
+				GetByNamesFunc: func(ctx context.Context, names []string) ([]*domain.Scope, error) {
+					return []*domain.Scope{{Name: "read"}, {Name: "write"}}, nil
+				},
+			}
+			oauth2Service := services.NewOAuth2Service(mockClientRepo, mockScopeRepo, "test-secret-key-at-least-32-chars-long", 15*time.Minute, services.TokenSigningPolicy{}, logger)
+
+			updated, err := oauth2Service.UpdateClient(context.Background(), "id-123", 2, tt.clientName, nil, tt.scopes, tt.tokenProfile, tt.grantTypes, tt.redirectURIs, nil)
+
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("UpdateClient() error = %v, want %v", err, tt.expectedErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateClient() unexpected error = %v", err)
+			}
+			if updated.Name != tt.wantName {
+				t.Errorf("UpdateClient() Name = %v, want %v", updated.Name, tt.wantName)
+			}
+			if len(updated.Scopes) != len(tt.wantScopes) {
+				t.Errorf("UpdateClient() Scopes = %v, want %v", updated.Scopes, tt.wantScopes)
+			}
+			if updated.TokenProfile != tt.wantProfile {
+				t.Errorf("UpdateClient() TokenProfile = %v, want %v", updated.TokenProfile, tt.wantProfile)
+			}
+			if tt.wantGrants != nil && !reflect.DeepEqual(updated.GrantTypes, tt.wantGrants) {
+				t.Errorf("UpdateClient() GrantTypes = %v, want %v", updated.GrantTypes, tt.wantGrants)
+			}
+			if tt.redirectURIs != nil && !reflect.DeepEqual(updated.RedirectURIs, tt.redirectURIs) {
+				t.Errorf("UpdateClient() RedirectURIs = %v, want %v", updated.RedirectURIs, tt.redirectURIs)
+			}
+		})
+	}
+}
+
+func TestOAuth2Service_RedirectURIs(t *testing.T) {
+	const registered = "https://app.example.com/callback"
+
+	tests := []struct {
+		name        string
+		add         bool
+		redirectURI string
+		expectedErr error
+		wantURIs    []string
+	}{
+		{name: "add", add: true, redirectURI: "com.example.app:/oauth2redirect", wantURIs: []string{registered, "com.example.app:/oauth2redirect"}},
+		{name: "add registered", add: true, redirectURI: registered, wantURIs: []string{registered}},
+		{name: "add invalid", add: true, redirectURI: "http://app.example.com/callback", expectedErr: domainerrors.ErrInvalidRedirectURI},
+		{name: "remove", redirectURI: registered, wantURIs: []string{}},
+		{name: "remove unregistered", redirectURI: "https://other.example.com/callback", expectedErr: domainerrors.ErrRedirectURINotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := domain.NewOAuthClient("client-123", "secret123", "Test Client", "", []string{"read"})
+			client.ID = "id-123"
+			client.RedirectURIs = []string{registered}
+
+			var saved *domain.OAuthClient
+			mockClientRepo := &MockOAuthClientRepository{
+				GetByIDFunc: func(ctx context.Context, id string) (*domain.OAuthClient, error) {
+					return client, nil
+				},
+				UpdateFunc: func(ctx context.Context, c *domain.OAuthClient) error {
+					saved = c
+					return nil
+				},
+			}
+			oauth2Service := services.NewOAuth2Service(mockClientRepo, &MockScopeRepository{}, "test-secret-key-at-least-32-chars-long", 15*time.Minute, services.TokenSigningPolicy{}, zap.NewNop())
+
+			update := oauth2Service.RemoveRedirectURI
+			if tt.add {
+				update = oauth2Service.AddRedirectURI
+			}
+			updated, err := update(context.Background(), "id-123", tt.redirectURI)
+
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("error = %v, want %v", err, tt.expectedErr)
+				}
+				if saved != nil {
+					t.Errorf("client saved after failure")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error = %v", err)
+			}
+			if saved == nil || !reflect.DeepEqual(updated.RedirectURIs, tt.wantURIs) {
+				t.Errorf("RedirectURIs = %v (saved = %v), want %v", updated.RedirectURIs, saved != nil, tt.wantURIs)
+			}
+		})
+	}
+}
+
+func TestOAuth2Service_ResolveRedirectURI(t *testing.T) {
+	tests := []struct {
+		name         string
+		clientID     string
+		redirectURIs []string
+		requested    string
+		want         string
+		expectedErr  error
+	}{
+		{name: "registered", clientID: "client-123", redirectURIs: []string{"https://a.example.com/cb", "https://b.example.com/cb"}, requested: "https://b.example.com/cb", want: "https://b.example.com/cb"},
+		{name: "loopback with port", clientID: "client-123", redirectURIs: []string{"http://127.0.0.1/cb"}, requested: "http://127.0.0.1:50000/cb", want: "http://127.0.0.1:50000/cb"},
+		{name: "only registered one by default", clientID: "client-123", redirectURIs: []string{"https://a.example.com/cb"}, want: "https://a.example.com/cb"},
+		{name: "no default among several", clientID: "client-123", redirectURIs: []string{"https://a.example.com/cb", "https://b.example.com/cb"}, expectedErr: domainerrors.ErrInvalidRedirectURI},
+		{name: "unregistered", clientID: "client-123", redirectURIs: []string{"https://a.example.com/cb"}, requested: "https://evil.example.com/cb", expectedErr: domainerrors.ErrInvalidRedirectURI},
+		{name: "no redirect uris", clientID: "client-123", requested: "https://a.example.com/cb", expectedErr: domainerrors.ErrInvalidRedirectURI},
+		{name: "unknown client", clientID: "unknown", requested: "https://a.example.com/cb", expectedErr: domainerrors.ErrInvalidClient},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClientRepo := &MockOAuthClientRepository{
+				GetByClientIDFunc: func(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+					if clientID != "client-123" {
+						return nil, domainerrors.ErrInvalidCredentials
+					}
+					return &domain.OAuthClient{ClientID: clientID, Active: true, RedirectURIs: tt.redirectURIs}, nil
+				},
+			}
+			oauth2Service := services.NewOAuth2Service(mockClientRepo, &MockScopeRepository{}, "test-secret-key-at-least-32-chars-long", 15*time.Minute, services.TokenSigningPolicy{}, zap.NewNop())
+
+			got, err := oauth2Service.ResolveRedirectURI(context.Background(), tt.clientID, tt.requested)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("ResolveRedirectURI() error = %v, want %v", err, tt.expectedErr)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveRedirectURI() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOAuth2Service_ListExpiringSecrets(t *testing.T) {
+	now := time.Now()
+	withExpiry := func(clientID string, expiresIn time.Duration) *domain.OAuthClient {
+		expiresAt := now.Add(expiresIn)
+		return &domain.OAuthClient{ClientID: clientID, Active: true, SecretExpiresAt: &expiresAt}
+	}
+	mockClientRepo := &MockOAuthClientRepository{
+		ListFunc: func(ctx context.Context) ([]*domain.OAuthClient, error) {
+			return []*domain.OAuthClient{
+				withExpiry("in-20-days", 20*24*time.Hour),
+				{ClientID: "never", Active: true},
+				withExpiry("expired", -time.Hour),
+				withExpiry("in-60-days", 60*24*time.Hour),
+				withExpiry("in-2-days", 2*24*time.Hour),
+			}, nil
+		},
+	}
+	oauth2Service := services.NewOAuth2Service(mockClientRepo, &MockScopeRepository{}, "test-secret-key-at-least-32-chars-long", 15*time.Minute, services.TokenSigningPolicy{}, zap.NewNop())
+
+	clients, err := oauth2Service.ListExpiringSecrets(context.Background(), 30*24*time.Hour)
+	if err != nil {
+		t.Fatalf("ListExpiringSecrets() error = %v", err)
+	}
+
+	var got []string
+	for _, client := range clients {
+		got = append(got, client.ClientID)
+	}
+	if want := []string{"expired", "in-2-days", "in-20-days"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListExpiringSecrets() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,159 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestOutboxPublisher_Publish(t *testing.T) {
+	brokerDown := errors.New("publisher channel unavailable")
+
+	tests := []struct {
+		name         string
+		publishErr   error
+		enqueueErr   error
+		wantErr      bool
+		wantEnqueued bool
+	}{
+		{name: "published", wantErr: false},
+		{name: "failed publish stored in the outbox", publishErr: brokerDown, wantEnqueued: true},
+		{name: "outbox unavailable", publishErr: brokerDown, enqueueErr: errors.New("db down"), wantErr: true, wantEnqueued: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var enqueued *domain.OutboxMessage
+			publisher := services.NewOutboxPublisher(
+				&MockMessagePublisher{
+					PublishFunc: func(ctx context.Context, queueName string, message []byte) error {
+						return tt.publishErr
+					},
+				},
+				&MockOutboxRepository{
+					EnqueueFunc: func(ctx context.Context, message *domain.OutboxMessage) error {
+						enqueued = message
+						return tt.enqueueErr
+					},
+				},
+				zap.NewNop(),
+			)
+
+			err := publisher.Publish(context.Background(), "auth.user.registered", []byte(`{"messageId":"m-1"}`))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Publish() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (enqueued != nil) != tt.wantEnqueued {
+				t.Fatalf("message enqueued = %v, want %v", enqueued != nil, tt.wantEnqueued)
+			}
+			if enqueued != nil {
+				if enqueued.Queue != "auth.user.registered" || string(enqueued.Payload) != `{"messageId":"m-1"}` {
+					t.Errorf("enqueued = %+v, want the published message", enqueued)
+				}
+				if enqueued.LastError != brokerDown.Error() {
+					t.Errorf("LastError = %q, want %q", enqueued.LastError, brokerDown.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestOutboxPublisher_Publish_SurvivesCancelledRequest(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	publisher := services.NewOutboxPublisher(
+		&MockMessagePublisher{
+			PublishFunc: func(ctx context.Context, queueName string, message []byte) error {
+				return ctx.Err()
+			},
+		},
+		&MockOutboxRepository{
+			EnqueueFunc: func(ctx context.Context, message *domain.OutboxMessage) error {
+				return ctx.Err()
+			},
+		},
+		zap.NewNop(),
+	)
+
+	if err := publisher.Publish(ctx, "auth.user.registered", []byte(`{}`)); err != nil {
+		t.Errorf("Publish() error = %v, want the message stored in the outbox", err)
+	}
+}
+
+func TestOutboxRelay_RelayDue(t *testing.T) {
+	policy := services.OutboxRelayPolicy{
+		PollInterval:   time.Second,
+		BatchSize:      10,
+		InitialBackoff: time.Minute,
+		MaxBackoff:     10 * time.Minute,
+	}
+	messages := []*domain.OutboxMessage{
+		{ID: "delivered", Queue: "auth.user.registered", Payload: []byte(`{}`)},
+		{ID: "failing", Queue: "auth.security.notification", Payload: []byte(`{}`), Attempts: 2},
+		{ID: "failing-long", Queue: "auth.security.notification", Payload: []byte(`{}`), Attempts: 10},
+	}
+
+	var deleted []string
+	failed := map[string]time.Time{}
+	repo := &MockOutboxRepository{
+		ClaimDueFunc: func(ctx context.Context, limit int, lease time.Duration) ([]*domain.OutboxMessage, error) {
+			if limit != policy.BatchSize {
+				t.Errorf("ClaimDue() limit = %d, want %d", limit, policy.BatchSize)
+			}
+			return messages, nil
+		},
+		DeleteFunc: func(ctx context.Context, id string) error {
+			deleted = append(deleted, id)
+			return nil
+		},
+		MarkFailedFunc: func(ctx context.Context, id, lastError string, nextAttemptAt time.Time) error {
+			failed[id] = nextAttemptAt
+			return nil
+		},
+	}
+	publisher := &MockMessagePublisher{
+		PublishFunc: func(ctx context.Context, queueName string, message []byte) error {
+			if queueName == "auth.security.notification" {
+				return errors.New("message nacked by the broker")
+			}
+			return nil
+		},
+	}
+
+	relay := services.NewOutboxRelay(publisher, repo, policy, zap.NewNop())
+	start := time.Now()
+	delivered, err := relay.RelayDue(context.Background())
+	if err != nil {
+		t.Fatalf("RelayDue() error = %v", err)
+	}
+
+	if delivered != 1 || len(deleted) != 1 || deleted[0] != "delivered" {
+		t.Errorf("delivered = %d, deleted = %v, want only the delivered message", delivered, deleted)
+	}
+	// The backoff doubles with every failed attempt, up to the maximum
+	if got := failed["failing"].Sub(start); got < 4*time.Minute || got > 5*time.Minute {
+		t.Errorf("failing next attempt in %v, want 4m", got)
+	}
+	if got := failed["failing-long"].Sub(start); got < policy.MaxBackoff || got > policy.MaxBackoff+time.Minute {
+		t.Errorf("failing-long next attempt in %v, want %v", got, policy.MaxBackoff)
+	}
+}
+
+func TestOutboxRelay_RelayDue_ClaimError(t *testing.T) {
+	relay := services.NewOutboxRelay(&MockMessagePublisher{}, &MockOutboxRepository{
+		ClaimDueFunc: func(ctx context.Context, limit int, lease time.Duration) ([]*domain.OutboxMessage, error) {
+			return nil, errors.New("db down")
+		},
+	}, services.OutboxRelayPolicy{BatchSize: 10, InitialBackoff: time.Second, MaxBackoff: time.Minute}, zap.NewNop())
+
+	if _, err := relay.RelayDue(context.Background()); err == nil {
+		t.Error("RelayDue() error = nil, want the claim error")
+	}
+}
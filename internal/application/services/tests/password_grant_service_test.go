@@ -0,0 +1,127 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestPasswordGrantService_PasswordGrant(t *testing.T) {
+	logger := zap.NewNop()
+
+	legacyClient, _ := domain.NewOAuthClient("legacy-app", "legacy-secret", "Legacy App", "", []string{"read"})
+	otherClient, _ := domain.NewOAuthClient("other-app", "other-secret", "Other App", "", []string{"read"})
+	inactiveClient, _ := domain.NewOAuthClient("inactive-app", "inactive-secret", "Inactive App", "", []string{"read"})
+	inactiveClient.Active = false
+	clients := map[string]*domain.OAuthClient{
+		legacyClient.ClientID:   legacyClient,
+		otherClient.ClientID:    otherClient,
+		inactiveClient.ClientID: inactiveClient,
+	}
+	allowlist := []string{"legacy-app", "inactive-app"}
+
+	testUser, _ := domain.NewUser("test@example.com", "password123", "Test User", 12345)
+	testUser.ID = "user-123"
+
+	tests := []struct {
+		name         string
+		enabled      bool
+		clientID     string
+		clientSecret string
+		password     string
+		expectedErr  error
+	}{
+		{name: "successful password grant", enabled: true, clientID: "legacy-app", clientSecret: "legacy-secret", password: "password123"},
+		{name: "grant disabled", enabled: false, clientID: "legacy-app", clientSecret: "legacy-secret", password: "password123", expectedErr: domainerrors.ErrUnsupportedGrantType},
+		{name: "client not allowlisted", enabled: true, clientID: "other-app", clientSecret: "other-secret", password: "password123", expectedErr: domainerrors.ErrUnauthorizedClient},
+		{name: "unknown client", enabled: true, clientID: "unknown", clientSecret: "secret", password: "password123", expectedErr: domainerrors.ErrInvalidCredentials},
+		{name: "invalid client secret", enabled: true, clientID: "legacy-app", clientSecret: "wrong", password: "password123", expectedErr: domainerrors.ErrInvalidCredentials},
+		{name: "inactive client", enabled: true, clientID: "inactive-app", clientSecret: "inactive-secret", password: "password123", expectedErr: domainerrors.ErrInvalidClient},
+		// The deactivation is not disclosed to whoever guesses the client ID
+		{name: "inactive client with a wrong secret", enabled: true, clientID: "inactive-app", clientSecret: "wrong", password: "password123", expectedErr: domainerrors.ErrInvalidCredentials},
+		{name: "invalid user password", enabled: true, clientID: "legacy-app", clientSecret: "legacy-secret", password: "wrong", expectedErr: domainerrors.ErrInvalidGrant},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientRepo := &MockOAuthClientRepository{
+				GetByClientIDFunc: func(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+					if client, ok := clients[clientID]; ok {
+						return client, nil
+					}
+					return nil, domainerrors.ErrClientNotFound
+				},
+			}
+			userRepo := &MockUserRepository{
+				GetByEmailFunc: func(ctx context.Context, email string) (*domain.User, error) {
+					return testUser, nil
+				},
+			}
+
+			jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, logger)
+			authService := services.NewAuthService(userRepo, &MockTokenRepository{}, jwtService, &MockPasswordHasher{}, logger)
+			oauth2Service := services.NewOAuth2Service(clientRepo, &MockScopeRepository{}, "test-secret-key-at-least-32-chars-long", 15*time.Minute, services.TokenSigningPolicy{}, logger)
+			service := services.NewPasswordGrantService(oauth2Service, authService, tt.enabled, allowlist, nil, logger)
+
+			tokenPair, err := service.PasswordGrant(context.Background(), tt.clientID, tt.clientSecret, "test@example.com", tt.password)
+
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("PasswordGrant() error = %v, want %v", err, tt.expectedErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PasswordGrant() unexpected error = %v", err)
+			}
+			if tokenPair == nil || tokenPair.AccessToken == "" {
+				t.Errorf("PasswordGrant() tokenPair = %+v, want issued tokens", tokenPair)
+			}
+		})
+	}
+}
+
+func TestPasswordGrantService_PasswordGrant_ClientLockout(t *testing.T) {
+	logger := zap.NewNop()
+	client, _ := domain.NewOAuthClient("legacy-app", "legacy-secret", "Legacy App", "", []string{"read"})
+	clientRepo := &MockOAuthClientRepository{
+		GetByClientIDFunc: func(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+			return client, nil
+		},
+	}
+	lockout := services.NewClientLockoutService(
+		newCountingRateLimiter(lockoutTestPolicy.Window),
+		newCountingRateLimiter(lockoutTestPolicy.Cooldown),
+		lockoutTestPolicy,
+		logger,
+	)
+	oauth2Service := services.NewOAuth2Service(clientRepo, &MockScopeRepository{}, "test-secret-key-at-least-32-chars-long", 15*time.Minute, services.TokenSigningPolicy{}, logger, services.WithClientLockout(lockout))
+	testUser, _ := domain.NewUser("test@example.com", "password123", "Test User", 12345)
+	userRepo := &MockUserRepository{
+		GetByEmailFunc: func(ctx context.Context, email string) (*domain.User, error) {
+			return testUser, nil
+		},
+	}
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, logger)
+	authService := services.NewAuthService(userRepo, &MockTokenRepository{}, jwtService, &MockPasswordHasher{}, logger)
+	service := services.NewPasswordGrantService(oauth2Service, authService, true, []string{"legacy-app"}, nil, logger)
+	ctx := context.Background()
+
+	// Wrong secrets count towards the lockout of the client, like at the token endpoint
+	for range lockoutTestPolicy.Threshold {
+		if _, err := service.PasswordGrant(ctx, "legacy-app", "wrong", "test@example.com", "password123"); !errors.Is(err, domainerrors.ErrInvalidCredentials) {
+			t.Fatalf("PasswordGrant() with a wrong secret error = %v, want %v", err, domainerrors.ErrInvalidCredentials)
+		}
+	}
+
+	if _, err := service.PasswordGrant(ctx, "legacy-app", "legacy-secret", "test@example.com", "password123"); !errors.Is(err, domainerrors.ErrClientLockedOut) {
+		t.Errorf("PasswordGrant() while locked out error = %v, want %v", err, domainerrors.ErrClientLockedOut)
+	}
+}
@@ -0,0 +1,208 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	"github.com/kristianrpo/auth-microservice/internal/domain/events"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// passwordResetFixture holds the mocks of a PasswordResetService and what they recorded
+type passwordResetFixture struct {
+	user      *domain.User
+	resets    map[string]*domain.PasswordReset
+	event     *events.SecurityNotificationEvent
+	updated   *domain.User
+	revoked   int
+	notified  domain.NotificationType
+	userRepo  *MockUserRepository
+	emailRepo *MockUserEmailRepository
+	limiter   *MockRateLimiter
+}
+
+func newPasswordResetFixture() *passwordResetFixture {
+	f := &passwordResetFixture{user: newTestUser(), resets: map[string]*domain.PasswordReset{}, limiter: &MockRateLimiter{}}
+	f.userRepo = &MockUserRepository{
+		GetByEmailFunc: func(ctx context.Context, email string) (*domain.User, error) {
+			if email != f.user.Email {
+				return nil, domainerrors.ErrUserNotFound
+			}
+			return f.user, nil
+		},
+		GetByIDFunc: func(ctx context.Context, id string) (*domain.User, error) {
+			if id != f.user.ID {
+				return nil, domainerrors.ErrUserNotFound
+			}
+			return f.user, nil
+		},
+		UpdateFunc: func(ctx context.Context, user *domain.User) error {
+			f.updated = user
+			return nil
+		},
+	}
+	f.emailRepo = &MockUserEmailRepository{
+		GetVerifiedFunc: func(ctx context.Context, email string) (*domain.UserEmail, error) {
+			if email != "recovery@example.org" {
+				return nil, domainerrors.ErrEmailNotFound
+			}
+			verifiedAt := time.Now()
+			return &domain.UserEmail{ID: "email-1", UserID: f.user.ID, Email: email, VerifiedAt: &verifiedAt}, nil
+		},
+	}
+	return f
+}
+
+func (f *passwordResetFixture) service() *services.PasswordResetService {
+	resetRepo := &MockPasswordResetRepository{
+		StoreFunc: func(ctx context.Context, tokenHash string, reset *domain.PasswordReset, ttl time.Duration) error {
+			f.resets[tokenHash] = reset
+			return nil
+		},
+		ConsumeFunc: func(ctx context.Context, tokenHash string) (*domain.PasswordReset, error) {
+			reset, ok := f.resets[tokenHash]
+			if !ok {
+				return nil, domainerrors.ErrInvalidResetToken
+			}
+			delete(f.resets, tokenHash)
+			return reset, nil
+		},
+	}
+	tokenRepo := &MockTokenRepository{
+		DeleteUserTokensFunc: func(ctx context.Context, userID string, idCitizen int) error {
+			f.revoked = idCitizen
+			return nil
+		},
+	}
+	publisher := &MockMessagePublisher{
+		PublishFunc: func(ctx context.Context, queueName string, message []byte) error {
+			f.event = &events.SecurityNotificationEvent{}
+			return json.Unmarshal(message, f.event)
+		},
+	}
+	notifier := &MockNotificationService{
+		NotifyFunc: func(ctx context.Context, user *domain.User, notificationType domain.NotificationType, details map[string]string) error {
+			f.notified = notificationType
+			return nil
+		},
+	}
+	return services.NewPasswordResetService(
+		f.userRepo, f.emailRepo, resetRepo, tokenRepo, &MockPasswordHasher{}, publisher, "test.security",
+		f.limiter, notifier, 30*time.Minute, zap.NewNop(),
+	)
+}
+
+func TestPasswordResetService_RequestReset(t *testing.T) {
+	tests := []struct {
+		name          string
+		email         string
+		inactive      bool
+		limited       bool
+		wantErr       error
+		wantRecipient string
+	}{
+		{name: "primary email", email: "test@example.com", wantRecipient: "test@example.com"},
+		{name: "verified secondary email", email: " Recovery@Example.org", wantRecipient: "recovery@example.org"},
+		{name: "unknown email is not revealed", email: "unknown@example.org"},
+		{name: "inactive user is not revealed", email: "test@example.com", inactive: true},
+		{name: "invalid email", email: "not-an-email", wantErr: domainerrors.ErrInvalidEmail},
+		{name: "rate limited", email: "unknown@example.org", limited: true, wantErr: domainerrors.ErrEmailRateLimited},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newPasswordResetFixture()
+			if tt.inactive {
+				f.user.Status = domain.UserStatusSuspended
+			}
+			if tt.limited {
+				f.limiter = exceededLimiter()
+			}
+
+			err := f.service().RequestReset(context.Background(), tt.email)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RequestReset() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantRecipient == "" {
+				if f.event != nil || len(f.resets) != 0 {
+					t.Errorf("reset token sent to %+v, want none", f.event)
+				}
+				return
+			}
+
+			if f.event == nil || f.event.Email != tt.wantRecipient || f.event.Type != string(domain.NotificationPasswordReset) {
+				t.Fatalf("event = %+v, want password_reset to %v", f.event, tt.wantRecipient)
+			}
+			if _, ok := f.resets[f.event.Details["token"]]; ok || len(f.resets) != 1 {
+				t.Errorf("stored resets = %v, want one keyed by the token hash", f.resets)
+			}
+		})
+	}
+}
+
+func TestPasswordResetService_ResetPassword(t *testing.T) {
+	tests := []struct {
+		name        string
+		newPassword string
+		badToken    bool
+		wantErr     error
+		wantReset   bool
+		wantPending bool
+	}{
+		{name: "resets password", newPassword: "new-password", wantReset: true},
+		{name: "weak password keeps the token", newPassword: "short", wantErr: domainerrors.ErrWeakPassword, wantPending: true},
+		{name: "invalid token", newPassword: "new-password", badToken: true, wantErr: domainerrors.ErrInvalidResetToken, wantPending: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newPasswordResetFixture()
+			service := f.service()
+			if err := service.RequestReset(context.Background(), "recovery@example.org"); err != nil {
+				t.Fatalf("RequestReset() error = %v", err)
+			}
+			token := f.event.Details["token"]
+			if tt.badToken {
+				token = "forged-token"
+			}
+
+			err := service.ResetPassword(context.Background(), token, tt.newPassword)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ResetPassword() error = %v, want %v", err, tt.wantErr)
+			}
+			if (len(f.resets) == 1) != tt.wantPending {
+				t.Errorf("token pending = %v, want %v", len(f.resets) == 1, tt.wantPending)
+			}
+			if !tt.wantReset {
+				if f.updated != nil || f.revoked != 0 {
+					t.Errorf("side effects after failure: updated = %+v, revoked = %v", f.updated, f.revoked)
+				}
+				return
+			}
+
+			if ok, _ := (&MockPasswordHasher{}).Compare(context.Background(), f.updated.Password, tt.newPassword); !ok {
+				t.Errorf("updated password doesn't match %q", tt.newPassword)
+			}
+			if f.revoked != 12345 {
+				t.Errorf("DeleteUserTokens() idCitizen = %v, want 12345", f.revoked)
+			}
+			if f.notified != domain.NotificationPasswordChange {
+				t.Errorf("notified = %v, want %v", f.notified, domain.NotificationPasswordChange)
+			}
+
+			// Tokens are single use
+			if err := service.ResetPassword(context.Background(), token, tt.newPassword); !errors.Is(err, domainerrors.ErrInvalidResetToken) {
+				t.Errorf("second ResetPassword() error = %v, want %v", err, domainerrors.ErrInvalidResetToken)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// MockPhoneLoginCodes is a mock implementation of services.PhoneLoginCodes
+type MockPhoneLoginCodes struct {
+	StartEnrollmentFunc func(ctx context.Context, idCitizen int, phoneNumber string) (*domain.PhoneVerification, error)
+	SendLoginCodeFunc   func(ctx context.Context, user *domain.User, number string) error
+	VerifyLoginCodeFunc func(ctx context.Context, user *domain.User, code string) error
+}
+
+func (m *MockPhoneLoginCodes) StartEnrollment(ctx context.Context, idCitizen int, phoneNumber string) (*domain.PhoneVerification, error) {
+	if m.StartEnrollmentFunc != nil {
+		return m.StartEnrollmentFunc(ctx, idCitizen, phoneNumber)
+	}
+	return &domain.PhoneVerification{Number: phoneNumber}, nil
+}
+
+func (m *MockPhoneLoginCodes) SendLoginCode(ctx context.Context, user *domain.User, number string) error {
+	if m.SendLoginCodeFunc != nil {
+		return m.SendLoginCodeFunc(ctx, user, number)
+	}
+	return nil
+}
+
+func (m *MockPhoneLoginCodes) VerifyLoginCode(ctx context.Context, user *domain.User, code string) error {
+	if m.VerifyLoginCodeFunc != nil {
+		return m.VerifyLoginCodeFunc(ctx, user, code)
+	}
+	return nil
+}
+
+// MockPhoneLoginAuthenticator is a mock implementation of services.PhoneLoginAuthenticator
+type MockPhoneLoginAuthenticator struct {
+	LoginWithUserFunc     func(ctx context.Context, email, password string) (*domain.TokenPair, *domain.UserPublic, error)
+	LoginVerifiedUserFunc func(ctx context.Context, user *domain.User) (*domain.TokenPair, *domain.UserPublic, error)
+}
+
+func (m *MockPhoneLoginAuthenticator) LoginWithUser(ctx context.Context, email, password string) (*domain.TokenPair, *domain.UserPublic, error) {
+	if m.LoginWithUserFunc != nil {
+		return m.LoginWithUserFunc(ctx, email, password)
+	}
+	return &domain.TokenPair{AccessToken: "access"}, &domain.UserPublic{Email: email}, nil
+}
+
+func (m *MockPhoneLoginAuthenticator) LoginVerifiedUser(ctx context.Context, user *domain.User) (*domain.TokenPair, *domain.UserPublic, error) {
+	if m.LoginVerifiedUserFunc != nil {
+		return m.LoginVerifiedUserFunc(ctx, user)
+	}
+	return &domain.TokenPair{AccessToken: "access"}, user.ToPublic(), nil
+}
+
+// newTestPhoneLoginService builds a PhoneLoginService where +573001234567 is the verified number of newTestUser
+func newTestPhoneLoginService(user *domain.User, codes *MockPhoneLoginCodes, auth *MockPhoneLoginAuthenticator, riskEngine *MockRiskEngine, enabled bool) *services.PhoneLoginService {
+	userRepo := &MockUserRepository{
+		GetByIDFunc: func(ctx context.Context, id string) (*domain.User, error) {
+			if id != user.ID {
+				return nil, domainerrors.ErrUserNotFound
+			}
+			return user, nil
+		},
+	}
+	phoneRepo := &MockPhoneNumberRepository{
+		GetByNumberFunc: func(ctx context.Context, number string) (*domain.PhoneNumber, error) {
+			if number != "+573001234567" {
+				return nil, domainerrors.ErrPhoneNotFound
+			}
+			return &domain.PhoneNumber{UserID: user.ID, Number: number}, nil
+		},
+	}
+	return services.NewPhoneLoginService(userRepo, phoneRepo, codes, auth, riskEngine, enabled, zap.NewNop())
+}
+
+func TestPhoneLoginService_RequestLoginCode(t *testing.T) {
+	tests := []struct {
+		name        string
+		phoneNumber string
+		disabled    bool
+		inactive    bool
+		sendErr     error
+		wantErr     error
+		wantSent    bool
+	}{
+		{name: "sends code", phoneNumber: "+57 300 123 4567", wantSent: true},
+		{name: "unknown number is not revealed", phoneNumber: "+573009999999"},
+		{name: "inactive user is not revealed", phoneNumber: "+573001234567", inactive: true},
+		{name: "invalid number", phoneNumber: "3001234567", wantErr: domainerrors.ErrInvalidPhoneNumber},
+		{name: "disabled", phoneNumber: "+573001234567", disabled: true, wantErr: domainerrors.ErrPhoneLoginDisabled},
+		{name: "rate limited", phoneNumber: "+573001234567", sendErr: domainerrors.ErrSMSRateLimited, wantErr: domainerrors.ErrSMSRateLimited, wantSent: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := newTestUser()
+			if tt.inactive {
+				user.Status = domain.UserStatusSuspended
+			}
+			sent := false
+			codes := &MockPhoneLoginCodes{
+				SendLoginCodeFunc: func(ctx context.Context, u *domain.User, number string) error {
+					if u.ID != user.ID || number != "+573001234567" {
+						t.Errorf("SendLoginCode() = (%v, %v), want (%v, +573001234567)", u.ID, number, user.ID)
+					}
+					sent = true
+					return tt.sendErr
+				},
+			}
+
+			err := newTestPhoneLoginService(user, codes, &MockPhoneLoginAuthenticator{}, &MockRiskEngine{}, !tt.disabled).RequestLoginCode(context.Background(), tt.phoneNumber)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RequestLoginCode() error = %v, want %v", err, tt.wantErr)
+			}
+			if sent != tt.wantSent {
+				t.Errorf("code sent = %v, want %v", sent, tt.wantSent)
+			}
+		})
+	}
+}
+
+func TestPhoneLoginService_LoginWithPassword(t *testing.T) {
+	tests := []struct {
+		name        string
+		phoneNumber string
+		disabled    bool
+		wantErr     error
+		wantLogin   bool
+	}{
+		{name: "logs in with the email of the owner", phoneNumber: "+573001234567", wantLogin: true},
+		{name: "unknown number", phoneNumber: "+573009999999", wantErr: domainerrors.ErrInvalidCredentials},
+		{name: "disabled", phoneNumber: "+573001234567", disabled: true, wantErr: domainerrors.ErrPhoneLoginDisabled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loggedIn := false
+			auth := &MockPhoneLoginAuthenticator{
+				LoginWithUserFunc: func(ctx context.Context, email, password string) (*domain.TokenPair, *domain.UserPublic, error) {
+					if email != "test@example.com" || password != "secret-password" {
+						t.Errorf("LoginWithUser() = (%v, %v), want (test@example.com, secret-password)", email, password)
+					}
+					loggedIn = true
+					return &domain.TokenPair{AccessToken: "access"}, &domain.UserPublic{Email: email}, nil
+				},
+			}
+
+			tokens, _, err := newTestPhoneLoginService(newTestUser(), &MockPhoneLoginCodes{}, auth, &MockRiskEngine{}, !tt.disabled).
+				LoginWithPassword(context.Background(), tt.phoneNumber, "secret-password")
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("LoginWithPassword() error = %v, want %v", err, tt.wantErr)
+			}
+			if loggedIn != tt.wantLogin || (tt.wantLogin && tokens.AccessToken != "access") {
+				t.Errorf("logged in = %v, tokens = %+v, want %v", loggedIn, tokens, tt.wantLogin)
+			}
+		})
+	}
+}
+
+func TestPhoneLoginService_LoginWithCode(t *testing.T) {
+	tests := []struct {
+		name         string
+		verifyErr    error
+		wantErr      error
+		wantLogin    bool
+		wantFailures int
+	}{
+		{name: "logs in with a valid code", wantLogin: true},
+		{name: "wrong code", verifyErr: domainerrors.ErrInvalidVerificationCode, wantErr: domainerrors.ErrInvalidCredentials, wantFailures: 1},
+		{name: "repository failure", verifyErr: domainerrors.ErrInternal, wantErr: domainerrors.ErrInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codes := &MockPhoneLoginCodes{
+				VerifyLoginCodeFunc: func(ctx context.Context, user *domain.User, code string) error {
+					if code != "123456" {
+						t.Errorf("VerifyLoginCode() code = %v, want 123456", code)
+					}
+					return tt.verifyErr
+				},
+			}
+			loggedIn := false
+			auth := &MockPhoneLoginAuthenticator{
+				LoginVerifiedUserFunc: func(ctx context.Context, user *domain.User) (*domain.TokenPair, *domain.UserPublic, error) {
+					loggedIn = true
+					return &domain.TokenPair{AccessToken: "access"}, user.ToPublic(), nil
+				},
+			}
+
+			// Wrong codes count as failed logins, like wrong passwords
+			var failures []string
+			riskEngine := &MockRiskEngine{
+				RecordLoginFailureFunc: func(ctx context.Context, email string, user *domain.User) {
+					if user == nil || user.ID != "user-123" {
+						t.Errorf("RecordLoginFailure() user = %+v, want user-123", user)
+					}
+					failures = append(failures, email)
+				},
+			}
+
+			_, user, err := newTestPhoneLoginService(newTestUser(), codes, auth, riskEngine, true).LoginWithCode(context.Background(), "+573001234567", "123456")
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("LoginWithCode() error = %v, want %v", err, tt.wantErr)
+			}
+			if loggedIn != tt.wantLogin {
+				t.Errorf("logged in = %v, want %v", loggedIn, tt.wantLogin)
+			}
+			if tt.wantLogin && user.ID != "user-123" {
+				t.Errorf("LoginWithCode() user = %+v, want user-123", user)
+			}
+			if len(failures) != tt.wantFailures {
+				t.Errorf("recorded login failures = %v, want %d", failures, tt.wantFailures)
+			}
+		})
+	}
+}
+
+func TestPhoneLoginService_EnrollPhone(t *testing.T) {
+	if _, err := newTestPhoneLoginService(newTestUser(), &MockPhoneLoginCodes{}, &MockPhoneLoginAuthenticator{}, &MockRiskEngine{}, false).
+		EnrollPhone(context.Background(), 12345, "+573001234567"); !errors.Is(err, domainerrors.ErrPhoneLoginDisabled) {
+		t.Errorf("EnrollPhone() disabled error = %v, want %v", err, domainerrors.ErrPhoneLoginDisabled)
+	}
+
+	verification, err := newTestPhoneLoginService(newTestUser(), &MockPhoneLoginCodes{}, &MockPhoneLoginAuthenticator{}, &MockRiskEngine{}, true).
+		EnrollPhone(context.Background(), 12345, "+573001234567")
+	if err != nil || verification.Number != "+573001234567" {
+		t.Errorf("EnrollPhone() = (%+v, %v), want enrollment started", verification, err)
+	}
+}
@@ -0,0 +1,314 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+var smsCodePattern = regexp.MustCompile(`\b[0-9]{6}\b`)
+
+// newTestPhoneService builds a PhoneService whose user repository always returns newTestUser.
+// Login codes share the verification repository with the enrollment codes.
+func newTestPhoneService(
+	phoneRepo *MockPhoneNumberRepository,
+	verificationRepo *MockPhoneVerificationRepository,
+	sender *MockSMSSender,
+	numberLimiter, quotaLimiter *MockRateLimiter,
+) *services.PhoneService {
+	userRepo := &MockUserRepository{
+		GetByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+			return newTestUser(), nil
+		},
+	}
+	return services.NewPhoneService(userRepo, phoneRepo, verificationRepo, verificationRepo, sender, numberLimiter, quotaLimiter, 10*time.Minute, zap.NewNop())
+}
+
+func exceededLimiter() *MockRateLimiter {
+	return &MockRateLimiter{
+		HitFunc: func(ctx context.Context, key string) (*domain.RateLimitStatus, error) {
+			return &domain.RateLimitStatus{Limit: 3, Used: 4, ResetAt: time.Now().Add(time.Hour)}, nil
+		},
+	}
+}
+
+func TestPhoneService_StartEnrollment(t *testing.T) {
+	tests := []struct {
+		name          string
+		phoneNumber   string
+		numberLimiter *MockRateLimiter
+		quotaLimiter  *MockRateLimiter
+		owner         string
+		sendErr       error
+		wantErr       error
+		wantSent      bool
+		wantDeleted   bool
+	}{
+		{name: "sends code", phoneNumber: "+57 300 123 4567", wantSent: true},
+		{name: "invalid phone number", phoneNumber: "3001234567", wantErr: domainerrors.ErrInvalidPhoneNumber},
+		{name: "per-number rate limit exceeded", phoneNumber: "+573001234567", numberLimiter: exceededLimiter(), wantErr: domainerrors.ErrSMSRateLimited},
+		{name: "quota exceeded", phoneNumber: "+573001234567", quotaLimiter: exceededLimiter(), wantErr: domainerrors.ErrSMSQuotaExceeded},
+		{name: "provider failure", phoneNumber: "+573001234567", sendErr: errors.New("provider down"), wantErr: domainerrors.ErrSMSDeliveryFailed, wantSent: true, wantDeleted: true},
+		{name: "number of another user", phoneNumber: "+573001234567", owner: "user-456", wantErr: domainerrors.ErrPhoneAlreadyRegistered},
+		{name: "re-enrolls own number", phoneNumber: "+573001234567", owner: "user-123", wantSent: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.numberLimiter == nil {
+				tt.numberLimiter = &MockRateLimiter{}
+			}
+			if tt.quotaLimiter == nil {
+				tt.quotaLimiter = &MockRateLimiter{}
+			}
+
+			var stored *domain.PhoneVerification
+			var sentCode string
+			deleted := false
+			verificationRepo := &MockPhoneVerificationRepository{
+				StoreFunc: func(ctx context.Context, v *domain.PhoneVerification) error {
+					stored = v
+					return nil
+				},
+				DeleteFunc: func(ctx context.Context, userID string) error {
+					deleted = true
+					return nil
+				},
+			}
+			sender := &MockSMSSender{
+				SendFunc: func(ctx context.Context, phoneNumber, message string) error {
+					if phoneNumber != "+573001234567" {
+						t.Errorf("Send() phoneNumber = %v, want +573001234567", phoneNumber)
+					}
+					sentCode = smsCodePattern.FindString(message)
+					return tt.sendErr
+				},
+			}
+
+			phoneRepo := &MockPhoneNumberRepository{
+				GetByNumberFunc: func(ctx context.Context, number string) (*domain.PhoneNumber, error) {
+					if tt.owner == "" {
+						return nil, domainerrors.ErrPhoneNotFound
+					}
+					return &domain.PhoneNumber{UserID: tt.owner, Number: number}, nil
+				},
+			}
+
+			service := newTestPhoneService(phoneRepo, verificationRepo, sender, tt.numberLimiter, tt.quotaLimiter)
+			verification, err := service.StartEnrollment(context.Background(), 12345, tt.phoneNumber)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("StartEnrollment() error = %v, want %v", err, tt.wantErr)
+			}
+			if (sentCode != "") != tt.wantSent {
+				t.Errorf("SMS sent = %v, want %v", sentCode != "", tt.wantSent)
+			}
+			if deleted != tt.wantDeleted {
+				t.Errorf("verification deleted = %v, want %v", deleted, tt.wantDeleted)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if verification.Number != "+573001234567" || verification.UserID != "user-123" {
+				t.Errorf("StartEnrollment() = %+v", verification)
+			}
+			if stored == nil || stored.CodeHash == "" || stored.CodeHash == sentCode {
+				t.Errorf("stored verification = %+v, want hashed code", stored)
+			}
+		})
+	}
+}
+
+func TestPhoneService_VerifyEnrollment(t *testing.T) {
+	ctx := context.Background()
+
+	// Enroll once to obtain a verification with a known code
+	var pending *domain.PhoneVerification
+	var code string
+	service := newTestPhoneService(
+		&MockPhoneNumberRepository{},
+		&MockPhoneVerificationRepository{
+			StoreFunc: func(ctx context.Context, v *domain.PhoneVerification) error {
+				pending = v
+				return nil
+			},
+		},
+		&MockSMSSender{
+			SendFunc: func(ctx context.Context, phoneNumber, message string) error {
+				code = smsCodePattern.FindString(message)
+				return nil
+			},
+		},
+		&MockRateLimiter{}, &MockRateLimiter{},
+	)
+	if _, err := service.StartEnrollment(ctx, 12345, "+573001234567"); err != nil {
+		t.Fatalf("StartEnrollment() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		code         string
+		attempts     int
+		expired      bool
+		missing      bool
+		wantErr      error
+		wantSaved    bool
+		wantAttempts int // attempts of the verification stored back, 0 when it stays consumed
+	}{
+		{name: "correct code", code: code, wantSaved: true},
+		{name: "wrong code", code: "000000x", wantErr: domainerrors.ErrInvalidVerificationCode, wantAttempts: 1},
+		{name: "last attempt", code: "000000x", attempts: domain.MaxPhoneVerificationAttempts - 1, wantErr: domainerrors.ErrInvalidVerificationCode},
+		{name: "expired", code: code, expired: true, wantErr: domainerrors.ErrInvalidVerificationCode},
+		{name: "no pending verification", code: code, missing: true, wantErr: domainerrors.ErrInvalidVerificationCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verification := *pending
+			verification.Attempts = tt.attempts
+			if tt.expired {
+				verification.ExpiresAt = time.Now().Add(-time.Second)
+			}
+
+			var saved *domain.PhoneNumber
+			var restored *domain.PhoneVerification
+			phoneRepo := &MockPhoneNumberRepository{
+				UpsertFunc: func(ctx context.Context, phone *domain.PhoneNumber) error {
+					saved = phone
+					return nil
+				},
+			}
+			verificationRepo := &MockPhoneVerificationRepository{
+				ConsumeFunc: func(ctx context.Context, userID string) (*domain.PhoneVerification, error) {
+					if tt.missing {
+						return nil, domainerrors.ErrInvalidVerificationCode
+					}
+					return &verification, nil
+				},
+				RestoreFunc: func(ctx context.Context, v *domain.PhoneVerification) error {
+					restored = v
+					return nil
+				},
+			}
+
+			service := newTestPhoneService(phoneRepo, verificationRepo, &MockSMSSender{}, &MockRateLimiter{}, &MockRateLimiter{})
+			phone, err := service.VerifyEnrollment(ctx, 12345, tt.code)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("VerifyEnrollment() error = %v, want %v", err, tt.wantErr)
+			}
+			if (saved != nil) != tt.wantSaved {
+				t.Errorf("phone saved = %v, want %v", saved != nil, tt.wantSaved)
+			}
+			if tt.wantAttempts == 0 && restored != nil {
+				t.Errorf("verification stored back = %+v, want it consumed", restored)
+			}
+			if tt.wantAttempts > 0 && (restored == nil || restored.Attempts != tt.wantAttempts) {
+				t.Errorf("stored attempts = %+v, want %d", restored, tt.wantAttempts)
+			}
+			if tt.wantSaved && (phone.Number != "+573001234567" || phone.VerifiedAt.IsZero()) {
+				t.Errorf("VerifyEnrollment() = %+v", phone)
+			}
+		})
+	}
+}
+
+func TestPhoneService_RemovePhoneNumber(t *testing.T) {
+	tests := []struct {
+		name      string
+		deleteErr error
+		wantErr   error
+	}{
+		{name: "removes phone number"},
+		{name: "no phone number", deleteErr: domainerrors.ErrPhoneNotFound, wantErr: domainerrors.ErrPhoneNotFound},
+		{name: "repository error", deleteErr: errors.New("db down"), wantErr: domainerrors.ErrInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			phoneRepo := &MockPhoneNumberRepository{
+				DeleteFunc: func(ctx context.Context, userID string) error {
+					if userID != "user-123" {
+						t.Errorf("Delete() userID = %v, want user-123", userID)
+					}
+					return tt.deleteErr
+				},
+			}
+
+			service := newTestPhoneService(phoneRepo, &MockPhoneVerificationRepository{}, &MockSMSSender{}, &MockRateLimiter{}, &MockRateLimiter{})
+			if err := service.RemovePhoneNumber(context.Background(), 12345); !errors.Is(err, tt.wantErr) {
+				t.Errorf("RemovePhoneNumber() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPhoneService_LoginCode(t *testing.T) {
+	ctx := context.Background()
+	user := newTestUser()
+
+	var stored *domain.PhoneVerification
+	codeRepo := &MockPhoneVerificationRepository{
+		StoreFunc: func(ctx context.Context, v *domain.PhoneVerification) error {
+			stored = v
+			return nil
+		},
+		ConsumeFunc: func(ctx context.Context, userID string) (*domain.PhoneVerification, error) {
+			if stored == nil || userID != user.ID {
+				return nil, domainerrors.ErrInvalidVerificationCode
+			}
+			consumed := stored
+			stored = nil
+			return consumed, nil
+		},
+		RestoreFunc: func(ctx context.Context, v *domain.PhoneVerification) error {
+			if stored == nil {
+				stored = v
+			}
+			return nil
+		},
+	}
+	var code string
+	sender := &MockSMSSender{
+		SendFunc: func(ctx context.Context, phoneNumber, message string) error {
+			code = smsCodePattern.FindString(message)
+			return nil
+		},
+	}
+	service := newTestPhoneService(&MockPhoneNumberRepository{}, codeRepo, sender, &MockRateLimiter{}, &MockRateLimiter{})
+
+	if err := service.SendLoginCode(ctx, user, "+573001234567"); err != nil {
+		t.Fatalf("SendLoginCode() error = %v", err)
+	}
+	if code == "" || stored == nil || stored.CodeHash == code {
+		t.Fatalf("code = %q, stored = %+v, want code sent and stored hashed", code, stored)
+	}
+
+	if err := service.VerifyLoginCode(ctx, user, "000000x"); !errors.Is(err, domainerrors.ErrInvalidVerificationCode) {
+		t.Errorf("VerifyLoginCode() wrong code error = %v, want %v", err, domainerrors.ErrInvalidVerificationCode)
+	}
+	if stored == nil || stored.Attempts != 1 {
+		t.Errorf("stored login code = %+v, want 1 attempt", stored)
+	}
+
+	if err := service.VerifyLoginCode(ctx, user, code); err != nil {
+		t.Fatalf("VerifyLoginCode() error = %v", err)
+	}
+	if stored != nil {
+		t.Error("login code not consumed by its use")
+	}
+
+	// Codes are single use
+	if err := service.VerifyLoginCode(ctx, user, code); !errors.Is(err, domainerrors.ErrInvalidVerificationCode) {
+		t.Errorf("VerifyLoginCode() reused code error = %v, want %v", err, domainerrors.ErrInvalidVerificationCode)
+	}
+}
@@ -0,0 +1,312 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func newTestQuotaService(quotaRepo *MockQuotaRepository, counter *MockRateLimiter, tokenRepo *MockTokenRepository, auditRepo *MockAuditLogRepository, defaults services.QuotaDefaults) *services.QuotaService {
+	clientRepo := &MockOAuthClientRepository{
+		GetByClientIDFunc: func(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+			if clientID != "client-123" {
+				return nil, domainerrors.ErrClientNotFound
+			}
+			return &domain.OAuthClient{ClientID: clientID, Active: true, Tags: []string{"team:payments", "env:staging"}}, nil
+		},
+	}
+	userRepo := &MockUserRepository{
+		GetByIDFunc: func(ctx context.Context, id string) (*domain.User, error) {
+			if id != "user-123" {
+				return nil, domainerrors.ErrUserNotFound
+			}
+			return newTestUser(), nil
+		},
+	}
+	return services.NewQuotaService(quotaRepo, counter, tokenRepo, clientRepo, userRepo, auditRepo, defaults, zap.NewNop())
+}
+
+func TestQuotaService_EnforceClientIssuance(t *testing.T) {
+	tests := []struct {
+		name           string
+		defaults       services.QuotaDefaults
+		override       *domain.IssuanceQuota
+		quotaErr       error
+		used           int
+		hitErr         error
+		wantErr        error
+		wantLimit      int
+		wantNoCounting bool
+	}{
+		{name: "unlimited by default", wantNoCounting: true},
+		{name: "within default quota", defaults: services.QuotaDefaults{ClientMaxTokensPerHour: 10}, used: 10},
+		{name: "default quota exhausted", defaults: services.QuotaDefaults{ClientMaxTokensPerHour: 10}, used: 11, wantErr: domainerrors.ErrTokenQuotaExceeded, wantLimit: 10},
+		{
+			name:     "override takes precedence over default",
+			defaults: services.QuotaDefaults{ClientMaxTokensPerHour: 10},
+			override: &domain.IssuanceQuota{SubjectType: domain.QuotaSubjectClient, SubjectID: "client-123", MaxTokensPerHour: 100},
+			used:     11,
+		},
+		{
+			name:           "unlimited override",
+			defaults:       services.QuotaDefaults{ClientMaxTokensPerHour: 10},
+			override:       &domain.IssuanceQuota{SubjectType: domain.QuotaSubjectClient, SubjectID: "client-123"},
+			wantNoCounting: true,
+		},
+		{name: "quota store failure fails open", defaults: services.QuotaDefaults{ClientMaxTokensPerHour: 10}, quotaErr: errors.New("db down"), wantNoCounting: true},
+		{name: "counter failure fails open", defaults: services.QuotaDefaults{ClientMaxTokensPerHour: 10}, hitErr: errors.New("redis down")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quotaRepo := &MockQuotaRepository{
+				GetFunc: func(ctx context.Context, subjectType, subjectID string) (*domain.IssuanceQuota, error) {
+					if tt.quotaErr != nil {
+						return nil, tt.quotaErr
+					}
+					if tt.override == nil {
+						return nil, domainerrors.ErrQuotaNotFound
+					}
+					return tt.override, nil
+				},
+			}
+			counted := false
+			counter := &MockRateLimiter{
+				HitFunc: func(ctx context.Context, key string) (*domain.RateLimitStatus, error) {
+					counted = true
+					if key != "quota_tokens:client:client-123" {
+						t.Errorf("Hit() key = %v, want quota_tokens:client:client-123", key)
+					}
+					if tt.hitErr != nil {
+						return nil, tt.hitErr
+					}
+					return &domain.RateLimitStatus{Used: tt.used, ResetAt: time.Now().Add(30 * time.Minute)}, nil
+				},
+			}
+
+			service := newTestQuotaService(quotaRepo, counter, &MockTokenRepository{}, &MockAuditLogRepository{}, tt.defaults)
+			err := service.EnforceClientIssuance(context.Background(), "client-123")
+
+			if counted == tt.wantNoCounting {
+				t.Errorf("issuance counted = %v, want %v", counted, !tt.wantNoCounting)
+			}
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("EnforceClientIssuance() error = %v", err)
+				}
+				return
+			}
+
+			var quotaErr *domainerrors.QuotaExceededError
+			if !errors.Is(err, tt.wantErr) || !errors.As(err, &quotaErr) {
+				t.Fatalf("EnforceClientIssuance() error = %v, want %v", err, tt.wantErr)
+			}
+			if quotaErr.Limit != tt.wantLimit || quotaErr.Subject != domain.QuotaSubjectClient {
+				t.Errorf("quota error subject = %v, limit = %v, want client, %v", quotaErr.Subject, quotaErr.Limit, tt.wantLimit)
+			}
+			if quotaErr.RetryAfter <= 29*time.Minute || quotaErr.RetryAfter > 30*time.Minute {
+				t.Errorf("RetryAfter = %v, want about 30m", quotaErr.RetryAfter)
+			}
+		})
+	}
+}
+
+func TestQuotaService_EnforceUserIssuance(t *testing.T) {
+	defaults := services.QuotaDefaults{UserMaxTokensPerHour: 20, UserMaxActiveSessions: 3}
+
+	tests := []struct {
+		name           string
+		newSession     bool
+		activeSessions int
+		countErr       error
+		used           int
+		wantErr        error
+	}{
+		{name: "new session within quotas", newSession: true, activeSessions: 2, used: 5},
+		{name: "too many active sessions", newSession: true, activeSessions: 3, wantErr: domainerrors.ErrSessionQuotaExceeded},
+		{name: "refresh ignores the session quota", activeSessions: 3, used: 5},
+		{name: "token quota exhausted on refresh", used: 21, wantErr: domainerrors.ErrTokenQuotaExceeded},
+		{name: "session count failure fails open", newSession: true, countErr: errors.New("redis down"), used: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokenRepo := &MockTokenRepository{
+				CountActiveSessionsFunc: func(ctx context.Context, idCitizen int) (int, error) {
+					if !tt.newSession {
+						t.Error("CountActiveSessions() called on refresh")
+					}
+					if idCitizen != 12345 {
+						t.Errorf("CountActiveSessions() idCitizen = %v, want 12345", idCitizen)
+					}
+					return tt.activeSessions, tt.countErr
+				},
+			}
+			counter := &MockRateLimiter{
+				HitFunc: func(ctx context.Context, key string) (*domain.RateLimitStatus, error) {
+					if key != "quota_tokens:user:user-123" {
+						t.Errorf("Hit() key = %v, want quota_tokens:user:user-123", key)
+					}
+					return &domain.RateLimitStatus{Used: tt.used, ResetAt: time.Now().Add(time.Minute)}, nil
+				},
+			}
+
+			service := newTestQuotaService(&MockQuotaRepository{}, counter, tokenRepo, &MockAuditLogRepository{}, defaults)
+			err := service.EnforceUserIssuance(context.Background(), newTestUser(), tt.newSession)
+
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("EnforceUserIssuance() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("EnforceUserIssuance() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestQuotaService_GetQuota(t *testing.T) {
+	resetAt := time.Now().Add(20 * time.Minute)
+	counter := &MockRateLimiter{
+		PeekFunc: func(ctx context.Context, key string) (*domain.RateLimitStatus, error) {
+			return &domain.RateLimitStatus{Used: 7, ResetAt: resetAt}, nil
+		},
+	}
+	tokenRepo := &MockTokenRepository{
+		CountActiveSessionsFunc: func(ctx context.Context, idCitizen int) (int, error) {
+			return 2, nil
+		},
+	}
+	service := newTestQuotaService(&MockQuotaRepository{}, counter, tokenRepo, &MockAuditLogRepository{}, services.QuotaDefaults{UserMaxTokensPerHour: 20, UserMaxActiveSessions: 3})
+
+	usage, err := service.GetQuota(context.Background(), domain.QuotaSubjectUser, "user-123")
+	if err != nil {
+		t.Fatalf("GetQuota() error = %v", err)
+	}
+	if !usage.Default || usage.Quota.MaxTokensPerHour != 20 || usage.Quota.MaxActiveSessions != 3 {
+		t.Errorf("GetQuota() quota = %+v, default = %v, want the default quota", usage.Quota, usage.Default)
+	}
+	if usage.TokensIssued != 7 || !usage.ResetAt.Equal(resetAt) || usage.ActiveSessions != 2 {
+		t.Errorf("GetQuota() usage = %+v, want 7 tokens issued and 2 active sessions", usage)
+	}
+
+	if _, err := service.GetQuota(context.Background(), domain.QuotaSubjectUser, "unknown"); !errors.Is(err, domainerrors.ErrUserNotFound) {
+		t.Errorf("GetQuota() unknown user error = %v, want %v", err, domainerrors.ErrUserNotFound)
+	}
+	if _, err := service.GetQuota(context.Background(), "tenant", "user-123"); !errors.Is(err, domainerrors.ErrInvalidQuota) {
+		t.Errorf("GetQuota() unknown subject type error = %v, want %v", err, domainerrors.ErrInvalidQuota)
+	}
+}
+
+func TestQuotaService_SetQuota(t *testing.T) {
+	tests := []struct {
+		name      string
+		quota     *domain.IssuanceQuota
+		wantErr   error
+		wantAudit bool
+		wantTags  string
+	}{
+		{
+			name:      "client quota",
+			quota:     &domain.IssuanceQuota{SubjectType: domain.QuotaSubjectClient, SubjectID: "client-123", MaxTokensPerHour: 500},
+			wantAudit: true,
+			wantTags:  "team:payments,env:staging",
+		},
+		{
+			name:      "user quota",
+			quota:     &domain.IssuanceQuota{SubjectType: domain.QuotaSubjectUser, SubjectID: "user-123", MaxTokensPerHour: 50, MaxActiveSessions: 5},
+			wantAudit: true,
+		},
+		{
+			name:    "unknown client",
+			quota:   &domain.IssuanceQuota{SubjectType: domain.QuotaSubjectClient, SubjectID: "unknown", MaxTokensPerHour: 500},
+			wantErr: domainerrors.ErrClientNotFound,
+		},
+		{
+			name:    "negative limit",
+			quota:   &domain.IssuanceQuota{SubjectType: domain.QuotaSubjectUser, SubjectID: "user-123", MaxTokensPerHour: -1},
+			wantErr: domainerrors.ErrInvalidQuota,
+		},
+		{
+			name:    "session limit on a client",
+			quota:   &domain.IssuanceQuota{SubjectType: domain.QuotaSubjectClient, SubjectID: "client-123", MaxActiveSessions: 2},
+			wantErr: domainerrors.ErrInvalidQuota,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stored *domain.IssuanceQuota
+			quotaRepo := &MockQuotaRepository{
+				UpsertFunc: func(ctx context.Context, quota *domain.IssuanceQuota) error {
+					stored = quota
+					return nil
+				},
+			}
+			var audit *domain.AuditRecord
+			auditRepo := &MockAuditLogRepository{
+				RecordFunc: func(ctx context.Context, record *domain.AuditRecord) error {
+					audit = record
+					return nil
+				},
+			}
+
+			service := newTestQuotaService(quotaRepo, &MockRateLimiter{}, &MockTokenRepository{}, auditRepo, services.QuotaDefaults{})
+			_, err := service.SetQuota(context.Background(), tt.quota, "admin:999")
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SetQuota() error = %v, want %v", err, tt.wantErr)
+			}
+			if (stored != nil) != (tt.wantErr == nil) {
+				t.Errorf("quota stored = %v, want %v", stored != nil, tt.wantErr == nil)
+			}
+			if (audit != nil) != tt.wantAudit {
+				t.Fatalf("audit recorded = %v, want %v", audit != nil, tt.wantAudit)
+			}
+			if audit != nil && (audit.Action != domain.AuditActionQuotaUpdated || audit.Actor != "admin:999" || audit.TargetID != tt.quota.SubjectID) {
+				t.Errorf("audit record = %+v", audit)
+			}
+			if audit != nil && audit.Details["client_tags"] != tt.wantTags {
+				t.Errorf("audit client_tags = %q, want %q", audit.Details["client_tags"], tt.wantTags)
+			}
+		})
+	}
+}
+
+func TestQuotaService_DeleteQuota(t *testing.T) {
+	quotaRepo := &MockQuotaRepository{
+		DeleteFunc: func(ctx context.Context, subjectType, subjectID string) error {
+			if subjectID != "client-123" {
+				return domainerrors.ErrQuotaNotFound
+			}
+			return nil
+		},
+	}
+	audits := 0
+	auditRepo := &MockAuditLogRepository{
+		RecordFunc: func(ctx context.Context, record *domain.AuditRecord) error {
+			audits++
+			return nil
+		},
+	}
+	service := newTestQuotaService(quotaRepo, &MockRateLimiter{}, &MockTokenRepository{}, auditRepo, services.QuotaDefaults{})
+
+	if err := service.DeleteQuota(context.Background(), domain.QuotaSubjectClient, "client-123", "admin:999"); err != nil {
+		t.Errorf("DeleteQuota() error = %v", err)
+	}
+	if err := service.DeleteQuota(context.Background(), domain.QuotaSubjectClient, "client-456", "admin:999"); !errors.Is(err, domainerrors.ErrQuotaNotFound) {
+		t.Errorf("DeleteQuota() without override error = %v, want %v", err, domainerrors.ErrQuotaNotFound)
+	}
+	if audits != 1 {
+		t.Errorf("audit records = %v, want 1", audits)
+	}
+}
@@ -0,0 +1,55 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+)
+
+func TestReadinessGate(t *testing.T) {
+	tests := []struct {
+		name        string
+		steps       []string
+		complete    []string
+		wantPending []string
+	}{
+		{
+			name:        "every step pending",
+			steps:       []string{"database schema", "message consumers"},
+			wantPending: []string{"database schema", "message consumers"},
+		},
+		{
+			name:        "pending steps keep declaration order",
+			steps:       []string{"database schema", "message consumers", "signing keys"},
+			complete:    []string{"message consumers"},
+			wantPending: []string{"database schema", "signing keys"},
+		},
+		{
+			name:     "every step completed",
+			steps:    []string{"database schema", "message consumers"},
+			complete: []string{"message consumers", "database schema", "database schema"},
+		},
+		{
+			name:        "unknown step is ignored",
+			steps:       []string{"database schema"},
+			complete:    []string{"signing keys"},
+			wantPending: []string{"database schema"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gate := services.NewReadinessGate(zap.NewNop(), tt.steps...)
+			for _, step := range tt.complete {
+				gate.Complete(step)
+			}
+
+			if got := gate.PendingSteps(); !reflect.DeepEqual(got, tt.wantPending) {
+				t.Errorf("PendingSteps() = %v, want %v", got, tt.wantPending)
+			}
+		})
+	}
+}
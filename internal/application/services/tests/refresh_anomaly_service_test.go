@@ -0,0 +1,92 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestRefreshAnomalyService_EvaluateRefresh(t *testing.T) {
+	policy := domain.RefreshAnomalyPolicy{MaxDistinctIPs: 3, MaxRefreshes: 10, Window: time.Hour}
+
+	tests := []struct {
+		name        string
+		session     *domain.RefreshTokenData
+		activity    *domain.RefreshActivity
+		activityErr error
+		wantReasons []string
+		wantAudit   bool
+	}{
+		{
+			name:     "normal activity",
+			session:  &domain.RefreshTokenData{FamilyID: "family-1"},
+			activity: &domain.RefreshActivity{DistinctIPs: 2, Refreshes: 4},
+		},
+		{
+			name:        "too many IPs",
+			session:     &domain.RefreshTokenData{FamilyID: "family-1"},
+			activity:    &domain.RefreshActivity{DistinctIPs: 4, Refreshes: 4},
+			wantReasons: []string{domain.RiskReasonRefreshManyIPs},
+			wantAudit:   true,
+		},
+		{
+			name:        "too many IPs and refreshes",
+			session:     &domain.RefreshTokenData{FamilyID: "family-1"},
+			activity:    &domain.RefreshActivity{DistinctIPs: 5, Refreshes: 11},
+			wantReasons: []string{domain.RiskReasonRefreshManyIPs, domain.RiskReasonRefreshHighFrequency},
+			wantAudit:   true,
+		},
+		{
+			name:    "session without family is not evaluated",
+			session: &domain.RefreshTokenData{},
+		},
+		{
+			name:        "activity store failure fails open",
+			session:     &domain.RefreshTokenData{FamilyID: "family-1"},
+			activityErr: errors.New("redis down"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var recordedFamily, recordedIP string
+			activityRepo := &MockRefreshActivityRepository{
+				RecordFunc: func(ctx context.Context, familyID, ip string) (*domain.RefreshActivity, error) {
+					recordedFamily, recordedIP = familyID, ip
+					return tt.activity, tt.activityErr
+				},
+			}
+			var audit *domain.AuditRecord
+			auditRepo := &MockAuditLogRepository{
+				RecordFunc: func(ctx context.Context, record *domain.AuditRecord) error {
+					audit = record
+					return nil
+				},
+			}
+
+			service := services.NewRefreshAnomalyService(activityRepo, auditRepo, policy, zap.NewNop())
+			ctx := domain.ContextWithClientInfo(context.Background(), domain.ClientInfo{IP: "198.51.100.1"})
+			reasons := service.EvaluateRefresh(ctx, newTestUser(), tt.session)
+
+			if !slices.Equal(reasons, tt.wantReasons) {
+				t.Errorf("EvaluateRefresh() = %v, want %v", reasons, tt.wantReasons)
+			}
+			if tt.session.FamilyID != "" && (recordedFamily != tt.session.FamilyID || recordedIP != "198.51.100.1") {
+				t.Errorf("Record() family = %q ip = %q", recordedFamily, recordedIP)
+			}
+			if (audit != nil) != tt.wantAudit {
+				t.Fatalf("audit recorded = %v, want %v", audit != nil, tt.wantAudit)
+			}
+			if audit != nil && (audit.Action != domain.AuditActionRefreshAnomaly || audit.TargetID != "user-123" || audit.Details["family_id"] != "family-1") {
+				t.Errorf("audit record = %+v", audit)
+			}
+		})
+	}
+}
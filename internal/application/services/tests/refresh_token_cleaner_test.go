@@ -0,0 +1,53 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+)
+
+func TestRefreshTokenCleaner_RemoveExpired(t *testing.T) {
+	remaining := 250
+	var batches []int
+	store := &MockRefreshTokenStore{
+		DeleteExpiredFunc: func(ctx context.Context, before time.Time, limit int) (int, error) {
+			if before.After(time.Now()) {
+				t.Errorf("DeleteExpired() before = %v, want not in the future", before)
+			}
+			deleted := min(limit, remaining)
+			remaining -= deleted
+			batches = append(batches, deleted)
+			return deleted, nil
+		},
+	}
+
+	cleaner := services.NewRefreshTokenCleaner(store, services.RefreshTokenCleanupPolicy{Interval: time.Hour, BatchSize: 100}, zap.NewNop())
+	removed, err := cleaner.RemoveExpired(context.Background())
+	if err != nil {
+		t.Fatalf("RemoveExpired() error = %v", err)
+	}
+	if removed != 250 {
+		t.Errorf("RemoveExpired() = %d, want 250", removed)
+	}
+	if len(batches) != 3 {
+		t.Errorf("batches = %v, want 3 batches", batches)
+	}
+}
+
+func TestRefreshTokenCleaner_RemoveExpired_StoreError(t *testing.T) {
+	store := &MockRefreshTokenStore{
+		DeleteExpiredFunc: func(ctx context.Context, before time.Time, limit int) (int, error) {
+			return 0, errors.New("db down")
+		},
+	}
+
+	cleaner := services.NewRefreshTokenCleaner(store, services.RefreshTokenCleanupPolicy{Interval: time.Hour, BatchSize: 100}, zap.NewNop())
+	if _, err := cleaner.RemoveExpired(context.Background()); err == nil {
+		t.Error("RemoveExpired() error = nil, want the store error")
+	}
+}
@@ -0,0 +1,190 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestRegistrationApprovalService_Decisions(t *testing.T) {
+	tests := []struct {
+		name             string
+		reject           bool
+		reason           string
+		status           domain.UserStatus
+		getErr           error
+		updateErr        error
+		wantErr          error
+		wantStatus       domain.UserStatus
+		wantAction       domain.AuditAction
+		wantNotification domain.NotificationType
+		wantReason       string
+	}{
+		{
+			name:             "approve pending user",
+			status:           domain.UserStatusPendingApproval,
+			wantStatus:       domain.UserStatusActive,
+			wantAction:       domain.AuditActionUserApproved,
+			wantNotification: domain.NotificationRegistrationApproved,
+		},
+		{
+			name:             "reject pending user with reason",
+			reject:           true,
+			reason:           "unverified identity",
+			status:           domain.UserStatusPendingApproval,
+			wantStatus:       domain.UserStatusRejected,
+			wantAction:       domain.AuditActionUserRejected,
+			wantNotification: domain.NotificationRegistrationRejected,
+			wantReason:       "unverified identity",
+		},
+		{
+			name:             "reject pending user without reason",
+			reject:           true,
+			status:           domain.UserStatusPendingApproval,
+			wantStatus:       domain.UserStatusRejected,
+			wantAction:       domain.AuditActionUserRejected,
+			wantNotification: domain.NotificationRegistrationRejected,
+		},
+		{name: "approve active user", status: domain.UserStatusActive, wantErr: domainerrors.ErrUserNotPendingApproval},
+		{name: "reject rejected user", reject: true, status: domain.UserStatusRejected, wantErr: domainerrors.ErrUserNotPendingApproval},
+		{name: "user not found", getErr: domainerrors.ErrUserNotFound, wantErr: domainerrors.ErrUserNotFound},
+		{name: "get fails", getErr: errors.New("db down"), wantErr: domainerrors.ErrInternal},
+		{name: "update fails", status: domain.UserStatusPendingApproval, updateErr: errors.New("db down"), wantErr: domainerrors.ErrInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updated *domain.User
+			userRepo := &MockUserRepository{
+				GetByIDFunc: func(ctx context.Context, id string) (*domain.User, error) {
+					if tt.getErr != nil {
+						return nil, tt.getErr
+					}
+					user := newTestUser()
+					user.Status = tt.status
+					return user, nil
+				},
+				UpdateFunc: func(ctx context.Context, user *domain.User) error {
+					updated = user
+					return tt.updateErr
+				},
+			}
+			var audit *domain.AuditRecord
+			auditRepo := &MockAuditLogRepository{
+				RecordFunc: func(ctx context.Context, record *domain.AuditRecord) error {
+					audit = record
+					return nil
+				},
+			}
+			var notification domain.NotificationType
+			var details map[string]string
+			notifier := &MockNotificationService{
+				NotifyFunc: func(ctx context.Context, user *domain.User, notificationType domain.NotificationType, d map[string]string) error {
+					notification = notificationType
+					details = d
+					return nil
+				},
+			}
+
+			service := services.NewRegistrationApprovalService(userRepo, auditRepo, notifier, zap.NewNop())
+			var user *domain.User
+			var err error
+			if tt.reject {
+				user, err = service.RejectUser(context.Background(), "user-123", tt.reason, "admin:1")
+			} else {
+				user, err = service.ApproveUser(context.Background(), "user-123", "admin:1")
+			}
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("decision error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if audit != nil || notification != "" {
+					t.Errorf("audit record = %+v, notification = %q after failure, want none", audit, notification)
+				}
+				return
+			}
+
+			if user.Status != tt.wantStatus || updated == nil || updated.Status != tt.wantStatus {
+				t.Errorf("status = %q, want %q stored", user.Status, tt.wantStatus)
+			}
+			if audit == nil || audit.Action != tt.wantAction || audit.Actor != "admin:1" || audit.TargetID != "user-123" {
+				t.Errorf("audit record = %+v, want %q by admin:1 on user-123", audit, tt.wantAction)
+			}
+			if notification != tt.wantNotification {
+				t.Errorf("notification = %q, want %q", notification, tt.wantNotification)
+			}
+			if details["reason"] != tt.wantReason {
+				t.Errorf("notification reason = %q, want %q", details["reason"], tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestRegistrationApprovalService_NotificationFailureKeepsDecision(t *testing.T) {
+	userRepo := &MockUserRepository{
+		GetByIDFunc: func(ctx context.Context, id string) (*domain.User, error) {
+			user := newTestUser()
+			user.Status = domain.UserStatusPendingApproval
+			return user, nil
+		},
+	}
+	notifier := &MockNotificationService{
+		NotifyFunc: func(ctx context.Context, user *domain.User, notificationType domain.NotificationType, details map[string]string) error {
+			return errors.New("broker down")
+		},
+	}
+
+	service := services.NewRegistrationApprovalService(userRepo, &MockAuditLogRepository{}, notifier, zap.NewNop())
+	user, err := service.ApproveUser(context.Background(), "user-123", "admin:1")
+	if err != nil {
+		t.Fatalf("ApproveUser() error = %v, want nil", err)
+	}
+	if user.Status != domain.UserStatusActive {
+		t.Errorf("status = %q, want %q", user.Status, domain.UserStatusActive)
+	}
+}
+
+func TestRegistrationApprovalService_ListUsers(t *testing.T) {
+	tests := []struct {
+		name    string
+		listErr error
+		wantErr error
+		want    int
+	}{
+		{name: "lists users", want: 2},
+		{name: "list fails", listErr: errors.New("db down"), wantErr: domainerrors.ErrInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := &MockUserRepository{
+				ListByStatusFunc: func(ctx context.Context, status domain.UserStatus, limit, offset int) ([]*domain.User, error) {
+					if status != domain.UserStatusPendingApproval || limit != 10 || offset != 20 {
+						t.Errorf("ListByStatus(%q, %d, %d), want (%q, 10, 20)", status, limit, offset, domain.UserStatusPendingApproval)
+					}
+					if tt.listErr != nil {
+						return nil, tt.listErr
+					}
+					return []*domain.User{newTestUser(), newTestUser()}, nil
+				},
+			}
+
+			service := services.NewRegistrationApprovalService(userRepo, &MockAuditLogRepository{}, &MockNotificationService{}, zap.NewNop())
+			users, err := service.ListUsers(context.Background(), domain.UserStatusPendingApproval, 10, 20)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ListUsers() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(users) != tt.want {
+				t.Errorf("ListUsers() returned %d users, want %d", len(users), tt.want)
+			}
+		})
+	}
+}
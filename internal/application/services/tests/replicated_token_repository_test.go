@@ -0,0 +1,178 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestReplicatedTokenRepository_BlacklistToken(t *testing.T) {
+	tests := []struct {
+		name          string
+		blacklistErr  error
+		publishErr    error
+		wantErr       bool
+		wantPublished bool
+	}{
+		{name: "blacklisted and published", wantPublished: true},
+		{name: "publish failure does not fail the revocation", publishErr: errors.New("stream down"), wantPublished: true},
+		{name: "blacklist failure is not published", blacklistErr: errors.New("redis down"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokenRepo := &MockTokenRepository{
+				BlacklistTokenFunc: func(ctx context.Context, token string, ttl time.Duration) error {
+					return tt.blacklistErr
+				},
+			}
+			var published *domain.BlacklistedToken
+			replicator := &MockBlacklistReplicator{
+				PublishFunc: func(ctx context.Context, token *domain.BlacklistedToken) error {
+					published = token
+					return tt.publishErr
+				},
+			}
+
+			repo := services.NewReplicatedTokenRepository(tokenRepo, replicator, "us-east-1", zap.NewNop())
+			err := repo.BlacklistToken(context.Background(), "access-token", time.Hour)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BlacklistToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if (published != nil) != tt.wantPublished {
+				t.Fatalf("published = %+v, want published %v", published, tt.wantPublished)
+			}
+			if published != nil {
+				if published.Token != "access-token" || published.Region != "us-east-1" || time.Until(published.ExpiresAt) <= 59*time.Minute {
+					t.Errorf("published = %+v, want the token, the region and its expiration", published)
+				}
+			}
+		})
+	}
+}
+
+func TestReplicatedTokenRepository_RevokeSession(t *testing.T) {
+	tests := []struct {
+		name          string
+		ttl           time.Duration
+		wantPublished bool
+	}{
+		{name: "access token blacklisted", ttl: time.Hour, wantPublished: true},
+		{name: "expired access token", ttl: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			revoked := false
+			tokenRepo := &MockTokenRepository{
+				RevokeSessionFunc: func(ctx context.Context, accessToken string, ttl time.Duration, refreshToken string) error {
+					revoked = true
+					return nil
+				},
+			}
+			published := false
+			replicator := &MockBlacklistReplicator{
+				PublishFunc: func(ctx context.Context, token *domain.BlacklistedToken) error {
+					published = true
+					return nil
+				},
+			}
+
+			repo := services.NewReplicatedTokenRepository(tokenRepo, replicator, "us-east-1", zap.NewNop())
+			if err := repo.RevokeSession(context.Background(), "access-token", tt.ttl, "refresh-token"); err != nil {
+				t.Fatalf("RevokeSession() error = %v", err)
+			}
+			if !revoked || published != tt.wantPublished {
+				t.Errorf("revoked = %v, published = %v, want revoked and published %v", revoked, published, tt.wantPublished)
+			}
+		})
+	}
+}
+
+func TestReplicatedTokenRepository_Apply(t *testing.T) {
+	tests := []struct {
+		name        string
+		token       *domain.BlacklistedToken
+		wantApplied bool
+	}{
+		{name: "token of another region", token: &domain.BlacklistedToken{Token: "access-token", ExpiresAt: time.Now().Add(time.Hour), Region: "eu-west-1"}, wantApplied: true},
+		{name: "token of this region", token: &domain.BlacklistedToken{Token: "access-token", ExpiresAt: time.Now().Add(time.Hour), Region: "us-east-1"}},
+		{name: "expired token", token: &domain.BlacklistedToken{Token: "access-token", ExpiresAt: time.Now().Add(-time.Minute), Region: "eu-west-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var appliedTTL time.Duration
+			tokenRepo := &MockTokenRepository{
+				BlacklistTokenFunc: func(ctx context.Context, token string, ttl time.Duration) error {
+					appliedTTL = ttl
+					return nil
+				},
+			}
+			published := false
+			replicator := &MockBlacklistReplicator{
+				PublishFunc: func(ctx context.Context, token *domain.BlacklistedToken) error {
+					published = true
+					return nil
+				},
+			}
+
+			repo := services.NewReplicatedTokenRepository(tokenRepo, replicator, "us-east-1", zap.NewNop())
+			if err := repo.Apply(context.Background(), tt.token); err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if (appliedTTL > 0) != tt.wantApplied {
+				t.Errorf("applied ttl = %v, want applied %v", appliedTTL, tt.wantApplied)
+			}
+			if published {
+				t.Error("replicated tokens must not be published again")
+			}
+		})
+	}
+}
+
+func TestReplicatedTokenRepository_StartAppliesSubscribedTokens(t *testing.T) {
+	applied := make(chan string, 1)
+	tokenRepo := &MockTokenRepository{
+		BlacklistTokenFunc: func(ctx context.Context, token string, ttl time.Duration) error {
+			applied <- token
+			return nil
+		},
+	}
+	replicator := &MockBlacklistReplicator{
+		SubscribeFunc: func(ctx context.Context, handle func(ctx context.Context, token *domain.BlacklistedToken) error) error {
+			if err := handle(ctx, &domain.BlacklistedToken{Token: "remote-token", ExpiresAt: time.Now().Add(time.Hour), Region: "eu-west-1"}); err != nil {
+				return err
+			}
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+
+	repo := services.NewReplicatedTokenRepository(tokenRepo, replicator, "us-east-1", zap.NewNop())
+	if err := repo.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	select {
+	case token := <-applied:
+		if token != "remote-token" {
+			t.Errorf("applied token = %q, want remote-token", token)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("replicated token was not applied")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := repo.Stop(ctx); err != nil {
+		t.Errorf("Stop() error = %v", err)
+	}
+}
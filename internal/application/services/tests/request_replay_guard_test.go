@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestRequestReplayGuard_VerifyClientRequest(t *testing.T) {
+	const key = "request-signing-key"
+	const nonce = "0123456789abcdef"
+	now := time.Now().Unix()
+	sign := func(timestamp int64, nonce string) *domain.SignedClientRequest {
+		return &domain.SignedClientRequest{
+			Timestamp: timestamp,
+			Nonce:     nonce,
+			Signature: domain.ClientRequestSignature(key, "svc", timestamp, nonce),
+		}
+	}
+
+	tests := []struct {
+		name     string
+		required bool
+		signed   *domain.SignedClientRequest
+		used     bool
+		useErr   error
+		wantErr  error
+	}{
+		{name: "unsigned request of an optional client", signed: nil},
+		{name: "unsigned request of a requiring client", required: true, wantErr: domainerrors.ErrSignedRequestRequired},
+		{name: "valid signed request", required: true, signed: sign(now, nonce)},
+		{name: "invalid signature", required: true, signed: &domain.SignedClientRequest{Timestamp: now, Nonce: nonce, Signature: "forged"}, wantErr: domainerrors.ErrInvalidRequestSignature},
+		{name: "nonce too short", required: true, signed: sign(now, "short"), wantErr: domainerrors.ErrInvalidRequestSignature},
+		{name: "stale timestamp", required: true, signed: sign(now-600, nonce), wantErr: domainerrors.ErrStaleRequest},
+		{name: "replayed nonce", required: true, signed: sign(now, nonce), used: true, wantErr: domainerrors.ErrReplayedRequest},
+		{name: "nonce store unavailable", required: true, signed: sign(now, nonce), useErr: errors.New("redis down"), wantErr: domainerrors.ErrInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nonceRepo := &MockNonceRepository{
+				UseFunc: func(ctx context.Context, clientID, nonce string, ttl time.Duration) (bool, error) {
+					if clientID != "svc" || ttl != 10*time.Minute {
+						t.Errorf("Use() clientID = %v, ttl = %v, want svc, 10m", clientID, ttl)
+					}
+					return !tt.used, tt.useErr
+				},
+			}
+			client := &domain.OAuthClient{ClientID: "svc", RequireSignedRequests: tt.required, RequestSigningKey: key}
+
+			guard := services.NewRequestReplayGuard(nonceRepo, 5*time.Minute, zap.NewNop())
+			err := guard.VerifyClientRequest(context.Background(), client, tt.signed)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifyClientRequest() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOAuth2Service_SetSignedRequests(t *testing.T) {
+	var updated *domain.OAuthClient
+	clientRepo := &MockOAuthClientRepository{
+		GetByIDFunc: func(ctx context.Context, id string) (*domain.OAuthClient, error) {
+			return &domain.OAuthClient{ID: id, ClientID: "svc", RequestSigningKey: "previous-key"}, nil
+		},
+		UpdateFunc: func(ctx context.Context, client *domain.OAuthClient) error {
+			updated = client
+			return nil
+		},
+	}
+	oauth2Service := services.NewOAuth2Service(clientRepo, &MockScopeRepository{}, "test-secret-key-at-least-32-chars-long", 15*time.Minute, services.TokenSigningPolicy{}, zap.NewNop())
+
+	client, err := oauth2Service.SetSignedRequests(context.Background(), "id-123", true)
+	if err != nil {
+		t.Fatalf("SetSignedRequests() error = %v", err)
+	}
+	if !updated.RequireSignedRequests || updated.RequestSigningKey == "" || updated.RequestSigningKey == "previous-key" {
+		t.Errorf("updated client = %+v, want signed requests required with a new key", updated)
+	}
+	if client.RequestSigningKey != updated.RequestSigningKey {
+		t.Error("SetSignedRequests() must return the new key")
+	}
+
+	if _, err := oauth2Service.SetSignedRequests(context.Background(), "id-123", false); err != nil {
+		t.Fatalf("SetSignedRequests() error = %v", err)
+	}
+	if updated.RequireSignedRequests || updated.RequestSigningKey != "" {
+		t.Errorf("updated client = %+v, want signed requests no longer required and the key deleted", updated)
+	}
+}
@@ -0,0 +1,61 @@
+package tests
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+)
+
+func TestResponseSigningService_SignDetached(t *testing.T) {
+	policy := services.TokenSigningPolicy{Algorithm: "HS384", KeyID: "key-2"}
+	signer := services.NewResponseSigningService("response-signing-key-at-least-32-chars", policy, zap.NewNop())
+	payload := []byte(`{"access_token":"abc","token_type":"Bearer","expires_in":900}`)
+
+	jws, err := signer.SignDetached(payload)
+	if err != nil {
+		t.Fatalf("SignDetached() error = %v", err)
+	}
+	parts := strings.Split(jws, ".")
+	if len(parts) != 3 || parts[0] == "" || parts[1] != "" || parts[2] == "" {
+		t.Fatalf("SignDetached() = %q, want a compact JWS with a detached payload", jws)
+	}
+
+	var header map[string]string
+	rawHeader, _ := base64.RawURLEncoding.DecodeString(parts[0])
+	if err := json.Unmarshal(rawHeader, &header); err != nil {
+		t.Fatalf("failed to decode JWS header: %v", err)
+	}
+	if header["alg"] != "HS384" || header["kid"] != "key-2" {
+		t.Errorf("JWS header = %v, want the algorithm and key ID of the signing policy", header)
+	}
+
+	tests := []struct {
+		name    string
+		signer  *services.ResponseSigningService
+		jws     string
+		payload []byte
+		wantErr bool
+	}{
+		{name: "valid signature", signer: signer, jws: jws, payload: payload},
+		{name: "tampered payload", signer: signer, jws: jws, payload: []byte(`{"access_token":"abc","token_type":"Bearer","expires_in":90000}`), wantErr: true},
+		{name: "other signing key", signer: services.NewResponseSigningService("another-response-key-at-least-32-chars", policy, zap.NewNop()), jws: jws, payload: payload, wantErr: true},
+		{name: "rotated key ID", signer: services.NewResponseSigningService("response-signing-key-at-least-32-chars", services.TokenSigningPolicy{Algorithm: "HS384", KeyID: "key-3"}, zap.NewNop()), jws: jws, payload: payload, wantErr: true},
+		{name: "algorithm not accepted", signer: services.NewResponseSigningService("response-signing-key-at-least-32-chars", services.TokenSigningPolicy{KeyID: "key-2"}, zap.NewNop()), jws: jws, payload: payload, wantErr: true},
+		{name: "attached payload", signer: signer, jws: strings.Replace(jws, "..", ".e30.", 1), payload: payload, wantErr: true},
+		{name: "malformed", signer: signer, jws: "not-a-jws", payload: payload, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.signer.VerifyDetached(tt.jws, tt.payload)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyDetached() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
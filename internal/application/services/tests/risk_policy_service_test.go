@@ -0,0 +1,262 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+const testRiskPolicy = `{
+	"default_decision": "allow",
+	"ip_lists": {"blocklist": ["203.0.113.0/24"]},
+	"rules": [
+		{"name": "blocked-ip", "decision": "deny", "when": {"ip_reputation": ["blocklist"]}},
+		{"name": "new-device", "decision": "step_up", "when": {"flows": ["login"], "new_device": true}},
+		{"name": "brute-force", "decision": "step_up", "when": {"min_recent_failures": 3}},
+		{"name": "lockout", "decision": "deny", "when": {"min_recent_failures": 10}},
+		{"name": "impossible-travel", "decision": "step_up", "when": {"geo_reasons": ["impossible_travel"]}}
+	]
+}`
+
+func TestRiskPolicyService_AssessLogin(t *testing.T) {
+	policy, err := domain.ParseRiskPolicy([]byte(testRiskPolicy))
+	if err != nil {
+		t.Fatalf("ParseRiskPolicy() error = %v", err)
+	}
+	impossibleTravel := &domain.LoginRisk{Risky: true, Reasons: []string{domain.RiskReasonImpossibleTravel}}
+
+	tests := []struct {
+		name           string
+		ip             string
+		knownDevice    bool
+		deviceErr      error
+		failures       int
+		geo            *domain.LoginRisk
+		wantDecision   domain.RiskDecision
+		wantRules      []string
+		wantRemembered bool
+		wantLockedOut  bool
+	}{
+		{name: "known device", ip: "198.51.100.1", knownDevice: true, wantDecision: domain.RiskDecisionAllow},
+		{name: "new device", ip: "198.51.100.1", wantDecision: domain.RiskDecisionStepUp, wantRules: []string{"new-device"}, wantRemembered: true},
+		{name: "blocklisted IP", ip: "203.0.113.9", wantDecision: domain.RiskDecisionDeny, wantRules: []string{"blocked-ip", "new-device"}},
+		{name: "recent failures", ip: "198.51.100.1", knownDevice: true, failures: 5, wantDecision: domain.RiskDecisionStepUp, wantRules: []string{"brute-force"}},
+		{name: "locked out", ip: "198.51.100.1", knownDevice: true, failures: 10, wantDecision: domain.RiskDecisionDeny, wantRules: []string{"brute-force", "lockout"}, wantLockedOut: true},
+		{name: "impossible travel", ip: "198.51.100.1", knownDevice: true, geo: impossibleTravel, wantDecision: domain.RiskDecisionStepUp, wantRules: []string{"impossible-travel"}},
+		{name: "device store failure fails open", ip: "198.51.100.1", deviceErr: errors.New("redis down"), wantDecision: domain.RiskDecisionAllow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remembered := false
+			deviceRepo := &MockKnownDeviceRepository{
+				IsKnownFunc: func(ctx context.Context, userID, deviceID string) (bool, error) {
+					return tt.knownDevice, tt.deviceErr
+				},
+				RememberFunc: func(ctx context.Context, userID, deviceID string) error {
+					remembered = true
+					return nil
+				},
+			}
+			failureCounter := &MockRateLimiter{
+				PeekFunc: func(ctx context.Context, key string) (*domain.RateLimitStatus, error) {
+					if key != domain.LoginFailureRateLimitKey("user-123") {
+						t.Errorf("Peek() key = %v", key)
+					}
+					return &domain.RateLimitStatus{Used: tt.failures, ResetAt: time.Now().Add(time.Minute)}, nil
+				},
+			}
+			geo := &MockLoginRiskEvaluator{
+				EvaluateLoginFunc: func(ctx context.Context, user *domain.User) *domain.LoginRisk {
+					return tt.geo
+				},
+			}
+
+			service := services.NewRiskPolicyService(policy, geo, nil, deviceRepo, failureCounter, nil, nil, zap.NewNop())
+			ctx := domain.ContextWithClientInfo(context.Background(), domain.ClientInfo{IP: tt.ip, UserAgent: "test-agent"})
+			assessment := service.AssessLogin(ctx, newTestUser())
+
+			if assessment.Decision != tt.wantDecision {
+				t.Errorf("Decision = %v, want %v", assessment.Decision, tt.wantDecision)
+			}
+			if !slices.Equal(assessment.Rules, tt.wantRules) {
+				t.Errorf("Rules = %v, want %v", assessment.Rules, tt.wantRules)
+			}
+			if remembered != tt.wantRemembered {
+				t.Errorf("device remembered = %v, want %v", remembered, tt.wantRemembered)
+			}
+			if assessment.LockedOut != tt.wantLockedOut {
+				t.Errorf("LockedOut = %v, want %v", assessment.LockedOut, tt.wantLockedOut)
+			}
+			if assessment.Geo != tt.geo {
+				t.Errorf("Geo = %+v, want %+v", assessment.Geo, tt.geo)
+			}
+		})
+	}
+}
+
+func TestRiskPolicyService_AssessRefresh(t *testing.T) {
+	policy, err := domain.ParseRiskPolicy([]byte(testRiskPolicy))
+	if err != nil {
+		t.Fatalf("ParseRiskPolicy() error = %v", err)
+	}
+
+	geo := &domain.LoginRisk{Risky: true, Reasons: []string{domain.RiskReasonImpossibleTravel}}
+	session := &domain.RefreshTokenData{Risk: &domain.RiskAssessment{Decision: domain.RiskDecisionStepUp, Geo: geo}}
+
+	// New devices only step up logins, the anomalies detected at login are carried over
+	service := services.NewRiskPolicyService(policy, nil, nil, &MockKnownDeviceRepository{
+		IsKnownFunc: func(ctx context.Context, userID, deviceID string) (bool, error) {
+			return false, nil
+		},
+	}, &MockRateLimiter{}, nil, nil, zap.NewNop())
+	ctx := domain.ContextWithClientInfo(context.Background(), domain.ClientInfo{IP: "198.51.100.1", UserAgent: "test-agent"})
+	assessment := service.AssessRefresh(ctx, newTestUser(), session)
+
+	if assessment.Decision != domain.RiskDecisionStepUp || !slices.Equal(assessment.Rules, []string{"impossible-travel"}) {
+		t.Errorf("AssessRefresh() = %s %v, want step_up [impossible-travel]", assessment.Decision, assessment.Rules)
+	}
+	if assessment.Geo != geo {
+		t.Errorf("Geo = %+v, want session geo risk", assessment.Geo)
+	}
+}
+
+func TestRiskPolicyService_AssessSudo(t *testing.T) {
+	policy, err := domain.ParseRiskPolicy([]byte(testRiskPolicy))
+	if err != nil {
+		t.Fatalf("ParseRiskPolicy() error = %v", err)
+	}
+
+	// Failed logins lock out elevations too, new devices only step up logins and no login is notified
+	notifier := &MockLoginNotifier{
+		NotifyLoginFunc: func(ctx context.Context, user *domain.User, assessment *domain.RiskAssessment) {
+			t.Errorf("NotifyLogin() called for a sudo elevation")
+		},
+	}
+	service := services.NewRiskPolicyService(policy, nil, nil, &MockKnownDeviceRepository{
+		IsKnownFunc: func(ctx context.Context, userID, deviceID string) (bool, error) {
+			return false, nil
+		},
+	}, &MockRateLimiter{
+		PeekFunc: func(ctx context.Context, key string) (*domain.RateLimitStatus, error) {
+			return &domain.RateLimitStatus{Used: 5, ResetAt: time.Now().Add(time.Minute)}, nil
+		},
+	}, nil, notifier, zap.NewNop())
+	ctx := domain.ContextWithClientInfo(context.Background(), domain.ClientInfo{IP: "198.51.100.1", UserAgent: "test-agent"})
+	assessment := service.AssessSudo(ctx, newTestUser())
+
+	if assessment.Decision != domain.RiskDecisionStepUp || !slices.Equal(assessment.Rules, []string{"brute-force"}) {
+		t.Errorf("AssessSudo() = %s %v, want step_up [brute-force]", assessment.Decision, assessment.Rules)
+	}
+	if assessment.Signals.Flow != domain.RiskFlowSudo {
+		t.Errorf("Flow = %v, want %v", assessment.Signals.Flow, domain.RiskFlowSudo)
+	}
+}
+
+func TestRiskPolicyService_AssessRefresh_RefreshAnomalies(t *testing.T) {
+	policy, err := domain.ParseRiskPolicy([]byte(`{
+		"rules": [{"name": "shared-refresh-token", "decision": "deny", "when": {"refresh_reasons": ["refresh_many_ips"]}}]
+	}`))
+	if err != nil {
+		t.Fatalf("ParseRiskPolicy() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		reasons      []string
+		wantDecision domain.RiskDecision
+	}{
+		{name: "normal refresh pattern", wantDecision: domain.RiskDecisionAllow},
+		{name: "refresh from many IPs", reasons: []string{domain.RiskReasonRefreshManyIPs}, wantDecision: domain.RiskDecisionDeny},
+		{name: "unmatched refresh reason", reasons: []string{domain.RiskReasonRefreshHighFrequency}, wantDecision: domain.RiskDecisionAllow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := &domain.RefreshTokenData{FamilyID: "family-1"}
+			detector := &MockRefreshAnomalyDetector{
+				EvaluateRefreshFunc: func(ctx context.Context, user *domain.User, got *domain.RefreshTokenData) []string {
+					if got != session {
+						t.Errorf("EvaluateRefresh() session = %+v, want %+v", got, session)
+					}
+					return tt.reasons
+				},
+			}
+
+			service := services.NewRiskPolicyService(policy, nil, detector, &MockKnownDeviceRepository{}, &MockRateLimiter{}, nil, nil, zap.NewNop())
+			assessment := service.AssessRefresh(context.Background(), newTestUser(), session)
+
+			if assessment.Decision != tt.wantDecision {
+				t.Errorf("Decision = %v, want %v", assessment.Decision, tt.wantDecision)
+			}
+			if !slices.Equal(assessment.Signals.RefreshReasons, tt.reasons) {
+				t.Errorf("RefreshReasons = %v, want %v", assessment.Signals.RefreshReasons, tt.reasons)
+			}
+		})
+	}
+}
+
+func TestRiskPolicyService_RecordLoginFailure(t *testing.T) {
+	var hitKey string
+	failureCounter := &MockRateLimiter{
+		HitFunc: func(ctx context.Context, key string) (*domain.RateLimitStatus, error) {
+			hitKey = key
+			return &domain.RateLimitStatus{Used: 1}, nil
+		},
+	}
+
+	service := services.NewRiskPolicyService(domain.DefaultRiskPolicy(), nil, nil, &MockKnownDeviceRepository{}, failureCounter, nil, nil, zap.NewNop())
+	service.RecordLoginFailure(context.Background(), "test@example.com", newTestUser())
+
+	if hitKey != domain.LoginFailureRateLimitKey("user-123") {
+		t.Errorf("Hit() key = %q, want %q", hitKey, domain.LoginFailureRateLimitKey("user-123"))
+	}
+}
+
+func TestRiskPolicyService_RecordLoginFailureUnknownUser(t *testing.T) {
+	var hitKeys []string
+	failureCounter := &MockRateLimiter{
+		HitFunc: func(ctx context.Context, key string) (*domain.RateLimitStatus, error) {
+			hitKeys = append(hitKeys, key)
+			return &domain.RateLimitStatus{Used: 1}, nil
+		},
+	}
+	monitor := services.NewBruteForceMonitor(failureCounter, 0, 15*time.Minute, zap.NewNop())
+
+	service := services.NewRiskPolicyService(domain.DefaultRiskPolicy(), nil, nil, &MockKnownDeviceRepository{}, failureCounter, monitor, nil, zap.NewNop())
+	service.RecordLoginFailure(context.Background(), "unknown@example.com", nil)
+
+	// Only the brute force monitor counts failures without a user
+	if !slices.Equal(hitKeys, []string{domain.LoginFailureEmailRateLimitKey("unknown@example.com")}) {
+		t.Errorf("Hit() keys = %v, want only the email key", hitKeys)
+	}
+}
+
+func TestRiskPolicyService_AssessLogin_NotifiesAllowedLogins(t *testing.T) {
+	policy, err := domain.ParseRiskPolicy([]byte(testRiskPolicy))
+	if err != nil {
+		t.Fatalf("ParseRiskPolicy() error = %v", err)
+	}
+
+	var notified []*domain.RiskAssessment
+	notifier := &MockLoginNotifier{
+		NotifyLoginFunc: func(ctx context.Context, user *domain.User, assessment *domain.RiskAssessment) {
+			notified = append(notified, assessment)
+		},
+	}
+	service := services.NewRiskPolicyService(policy, nil, nil, &MockKnownDeviceRepository{}, &MockRateLimiter{}, nil, notifier, zap.NewNop())
+
+	allowed := service.AssessLogin(domain.ContextWithClientInfo(context.Background(), domain.ClientInfo{IP: "198.51.100.1"}), newTestUser())
+	service.AssessLogin(domain.ContextWithClientInfo(context.Background(), domain.ClientInfo{IP: "203.0.113.9"}), newTestUser())
+
+	if len(notified) != 1 || notified[0] != allowed {
+		t.Errorf("notified %v, want only the allowed login", notified)
+	}
+}
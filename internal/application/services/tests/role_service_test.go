@@ -0,0 +1,139 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	"github.com/kristianrpo/auth-microservice/internal/domain/events"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func newTestRoleService(userRepo *MockUserRepository, tokenRepo *MockTokenRepository, auditRepo *MockAuditLogRepository) *services.RoleService {
+	return services.NewRoleService(userRepo, tokenRepo, auditRepo, &MockMessagePublisher{}, "auth.user.role_changed", 15*time.Minute, zap.NewNop())
+}
+
+func TestRoleService_ChangeUserRole(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      domain.UserStatus
+		role        domain.Role
+		version     int // If-Match version, 0 for none
+		getErr      error
+		updateErr   error
+		revokeErr   error
+		wantErr     error
+		wantChanged bool
+	}{
+		{name: "promotes user", version: 4, status: domain.UserStatusActive, role: domain.RoleAdmin, wantChanged: true},
+		{name: "revocation failure keeps the change", version: 4, status: domain.UserStatusActive, role: domain.RoleAdmin, revokeErr: errors.New("redis down"), wantChanged: true},
+		{name: "same role", version: 4, status: domain.UserStatusActive, role: domain.RoleUser},
+		{name: "user not found", getErr: domainerrors.ErrUserNotFound, role: domain.RoleAdmin, wantErr: domainerrors.ErrUserNotFound},
+		{name: "get fails", getErr: errors.New("db down"), role: domain.RoleAdmin, wantErr: domainerrors.ErrInternal},
+		{name: "anonymized user", version: 4, status: domain.UserStatusAnonymized, role: domain.RoleAdmin, wantErr: domainerrors.ErrUserAlreadyAnonymized},
+		{name: "update fails", version: 4, status: domain.UserStatusActive, role: domain.RoleAdmin, updateErr: errors.New("db down"), wantErr: domainerrors.ErrInternal},
+		{name: "expected version", status: domain.UserStatusActive, role: domain.RoleAdmin, version: 4, wantChanged: true},
+		{name: "no expected version", status: domain.UserStatusActive, role: domain.RoleAdmin, wantErr: domainerrors.ErrVersionRequired},
+		{name: "stale expected version", status: domain.UserStatusActive, role: domain.RoleAdmin, version: 3, wantErr: domainerrors.ErrVersionConflict},
+		{name: "concurrent update", version: 4, status: domain.UserStatusActive, role: domain.RoleAdmin, updateErr: domainerrors.ErrVersionConflict, wantErr: domainerrors.ErrVersionConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updated *domain.User
+			userRepo := &MockUserRepository{
+				GetByIDFunc: func(ctx context.Context, id string) (*domain.User, error) {
+					if tt.getErr != nil {
+						return nil, tt.getErr
+					}
+					user := newTestUser()
+					user.Status = tt.status
+					user.TokenVersion = 2
+					user.Version = 4
+					return user, nil
+				},
+				UpdateFunc: func(ctx context.Context, user *domain.User) error {
+					updated = user
+					return tt.updateErr
+				},
+			}
+			var minVersion int
+			var minVersionTTL time.Duration
+			deleted := false
+			tokenRepo := &MockTokenRepository{
+				SetTokenVersionFunc: func(ctx context.Context, idCitizen int, version int, ttl time.Duration) error {
+					minVersion, minVersionTTL = version, ttl
+					return tt.revokeErr
+				},
+				DeleteUserTokensFunc: func(ctx context.Context, userID string, idCitizen int) error {
+					deleted = true
+					return tt.revokeErr
+				},
+			}
+			var audit *domain.AuditRecord
+			auditRepo := &MockAuditLogRepository{
+				RecordFunc: func(ctx context.Context, record *domain.AuditRecord) error {
+					audit = record
+					return nil
+				},
+			}
+			var queue string
+			var published []byte
+			publisher := &MockMessagePublisher{
+				PublishFunc: func(ctx context.Context, queueName string, message []byte) error {
+					queue, published = queueName, message
+					return nil
+				},
+			}
+
+			service := services.NewRoleService(userRepo, tokenRepo, auditRepo, publisher, "auth.user.role_changed", 15*time.Minute, zap.NewNop())
+			user, err := service.ChangeUserRole(context.Background(), "user-123", tt.version, tt.role, "admin:1")
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ChangeUserRole() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if deleted || audit != nil || published != nil {
+					t.Errorf("tokens revoked = %v, audit = %+v, event = %s after failure, want none", deleted, audit, published)
+				}
+				return
+			}
+
+			if user.Role != tt.role {
+				t.Errorf("role = %v, want %v", user.Role, tt.role)
+			}
+			if !tt.wantChanged {
+				if updated != nil || deleted || audit != nil || published != nil {
+					t.Errorf("user updated = %+v, tokens revoked = %v, audit = %+v, event = %s, want no changes", updated, deleted, audit, published)
+				}
+				return
+			}
+
+			if updated == nil || updated.TokenVersion != 3 {
+				t.Fatalf("updated = %+v, want token version 3", updated)
+			}
+			if minVersion != 3 || minVersionTTL != 15*time.Minute || !deleted {
+				t.Errorf("minimum token version = %d for %v, refresh tokens deleted = %v, want 3 for 15m and deleted", minVersion, minVersionTTL, deleted)
+			}
+			if audit == nil || audit.Action != domain.AuditActionUserRoleChanged || audit.Actor != "admin:1" ||
+				audit.Details["previous_role"] != "USER" || audit.Details["role"] != "ADMIN" {
+				t.Errorf("audit = %+v, want the role change by admin:1 recorded", audit)
+			}
+
+			var event events.UserRoleChangedEvent
+			if err := json.Unmarshal(published, &event); err != nil {
+				t.Fatalf("failed to decode published event: %v", err)
+			}
+			if queue != "auth.user.role_changed" || event.UserID != "user-123" || event.IDCitizen != 12345 ||
+				event.PreviousRole != domain.RoleUser || event.Role != domain.RoleAdmin || event.MessageID == "" {
+				t.Errorf("published %+v to %q, want the role change on auth.user.role_changed", event, queue)
+			}
+		})
+	}
+}
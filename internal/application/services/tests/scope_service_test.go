@@ -0,0 +1,154 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestScopeService_CreateScope(t *testing.T) {
+	logger := zap.NewNop()
+
+	tests := []struct {
+		name        string
+		scopeName   string
+		createErr   error
+		expectedErr error
+	}{
+		{name: "successful creation", scopeName: "users:read"},
+		{name: "invalid name", scopeName: "Users Read", expectedErr: domainerrors.ErrInvalidScopeName},
+		{name: "already exists", scopeName: "read", createErr: domainerrors.ErrScopeAlreadyExists, expectedErr: domainerrors.ErrScopeAlreadyExists},
+		{name: "repository error", scopeName: "users:read", createErr: errors.New("db down"), expectedErr: domainerrors.ErrInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scopeRepo := &MockScopeRepository{
+				CreateFunc: func(ctx context.Context, scope *domain.Scope) error {
+					return tt.createErr
+				},
+			}
+			service := services.NewScopeService(scopeRepo, logger)
+
+			scope, err := service.CreateScope(context.Background(), tt.scopeName, "description")
+
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("CreateScope() error = %v, want %v", err, tt.expectedErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateScope() unexpected error = %v", err)
+			}
+			if scope.Name != tt.scopeName || scope.System {
+				t.Errorf("CreateScope() = %+v", scope)
+			}
+		})
+	}
+}
+
+func TestScopeService_UpdateScope(t *testing.T) {
+	logger := zap.NewNop()
+
+	tests := []struct {
+		name        string
+		stored      *domain.Scope
+		expectedErr error
+	}{
+		{name: "successful update", stored: &domain.Scope{Name: "users:read"}},
+		{name: "system scope", stored: &domain.Scope{Name: "read", System: true}, expectedErr: domainerrors.ErrSystemScope},
+		{name: "not found", expectedErr: domainerrors.ErrScopeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scopeRepo := &MockScopeRepository{
+				GetByNameFunc: func(ctx context.Context, name string) (*domain.Scope, error) {
+					if tt.stored == nil {
+						return nil, domainerrors.ErrScopeNotFound
+					}
+					return tt.stored, nil
+				},
+			}
+			service := services.NewScopeService(scopeRepo, logger)
+
+			scope, err := service.UpdateScope(context.Background(), "users:read", "new description")
+
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("UpdateScope() error = %v, want %v", err, tt.expectedErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateScope() unexpected error = %v", err)
+			}
+			if scope.Description != "new description" {
+				t.Errorf("UpdateScope() Description = %v, want new description", scope.Description)
+			}
+		})
+	}
+}
+
+func TestScopeService_DeleteScope(t *testing.T) {
+	logger := zap.NewNop()
+
+	tests := []struct {
+		name        string
+		stored      *domain.Scope
+		deleteErr   error
+		wantDeleted bool
+		expectedErr error
+	}{
+		{name: "successful deletion", stored: &domain.Scope{Name: "users:read"}, wantDeleted: true},
+		{name: "system scope", stored: &domain.Scope{Name: "users:read", System: true}, expectedErr: domainerrors.ErrSystemScope},
+		{name: "scope in use", stored: &domain.Scope{Name: "users:read"}, deleteErr: domainerrors.ErrScopeInUse, expectedErr: domainerrors.ErrScopeInUse},
+		{name: "deleted concurrently", stored: &domain.Scope{Name: "users:read"}, deleteErr: domainerrors.ErrScopeNotFound, expectedErr: domainerrors.ErrScopeNotFound},
+		{name: "repository failure", stored: &domain.Scope{Name: "users:read"}, deleteErr: errors.New("db down"), expectedErr: domainerrors.ErrInternal},
+		{name: "not found", expectedErr: domainerrors.ErrScopeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deleted := false
+			scopeRepo := &MockScopeRepository{
+				GetByNameFunc: func(ctx context.Context, name string) (*domain.Scope, error) {
+					if tt.stored == nil {
+						return nil, domainerrors.ErrScopeNotFound
+					}
+					return tt.stored, nil
+				},
+				DeleteFunc: func(ctx context.Context, name string) error {
+					if tt.deleteErr != nil {
+						return tt.deleteErr
+					}
+					deleted = true
+					return nil
+				},
+			}
+			service := services.NewScopeService(scopeRepo, logger)
+
+			err := service.DeleteScope(context.Background(), "users:read")
+
+			if deleted != tt.wantDeleted {
+				t.Errorf("deleted = %v, want %v", deleted, tt.wantDeleted)
+			}
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("DeleteScope() error = %v, want %v", err, tt.expectedErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("DeleteScope() unexpected error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestServiceAccountService_CreateServiceAccount(t *testing.T) {
+	tests := []struct {
+		name        string
+		accountName string
+		idCitizen   int
+		createErr   error
+		wantErr     error
+		wantAudit   bool
+	}{
+		{name: "created", accountName: "CI pipeline", idCitizen: 90001, wantAudit: true},
+		{name: "missing name", idCitizen: 90001, wantErr: domainerrors.ErrBadRequest},
+		{name: "invalid citizen ID", accountName: "CI pipeline", wantErr: domainerrors.ErrBadRequest},
+		{name: "citizen ID taken", accountName: "CI pipeline", idCitizen: 90001, createErr: domainerrors.ErrUserAlreadyExists, wantErr: domainerrors.ErrUserAlreadyExists},
+		{name: "repository error", accountName: "CI pipeline", idCitizen: 90001, createErr: errors.New("db down"), wantErr: domainerrors.ErrInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created *domain.User
+			userRepo := &MockUserRepository{
+				CreateFunc: func(ctx context.Context, user *domain.User) error {
+					if tt.createErr != nil {
+						return tt.createErr
+					}
+					user.ID = "sa-123"
+					created = user
+					return nil
+				},
+			}
+			var audited *domain.AuditRecord
+			auditRepo := &MockAuditLogRepository{
+				RecordFunc: func(ctx context.Context, record *domain.AuditRecord) error {
+					audited = record
+					return nil
+				},
+			}
+
+			service := services.NewServiceAccountService(userRepo, auditRepo, zap.NewNop())
+			user, err := service.CreateServiceAccount(context.Background(), tt.accountName, tt.idCitizen, "admin:999")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateServiceAccount() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if audited != nil {
+					t.Errorf("audit record written on error: %+v", audited)
+				}
+				return
+			}
+
+			if user != created || !user.IsServiceAccount() || user.Password != "" {
+				t.Errorf("CreateServiceAccount() = %+v, want a service account without password", user)
+			}
+			if audited == nil || audited.Action != domain.AuditActionServiceAccountCreated || audited.Actor != "admin:999" || audited.TargetID != "sa-123" {
+				t.Errorf("audit record = %+v, want %q by admin:999 on sa-123", audited, domain.AuditActionServiceAccountCreated)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+)
+
+// drainingComponent reports fixed drain statistics when stopped
+type drainingComponent struct {
+	stats   ports.DrainStats
+	stopErr error
+}
+
+func (c *drainingComponent) Start(ctx context.Context) error {
+	return nil
+}
+
+func (c *drainingComponent) Stop(ctx context.Context) error {
+	return c.stopErr
+}
+
+func (c *drainingComponent) DrainStats() ports.DrainStats {
+	return c.stats
+}
+
+func TestStopComponent(t *testing.T) {
+	tests := []struct {
+		name          string
+		component     services.Component
+		wantDrained   int
+		wantCancelled int
+		wantErr       bool
+	}{
+		{
+			name:        "reports drain statistics",
+			component:   &drainingComponent{stats: ports.DrainStats{Drained: 3}},
+			wantDrained: 3,
+		},
+		{
+			name:          "reports stop error and cancelled work",
+			component:     &drainingComponent{stats: ports.DrainStats{Drained: 1, Cancelled: 2}, stopErr: context.DeadlineExceeded},
+			wantDrained:   1,
+			wantCancelled: 2,
+			wantErr:       true,
+		},
+		{
+			name:      "component without drain statistics",
+			component: &recordingComponent{name: "relay", calls: &[]string{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step := services.StopComponent(context.Background(), "component", tt.component)
+
+			if step.Name != "component" {
+				t.Errorf("StopComponent() name = %v, want component", step.Name)
+			}
+			if step.Drained != tt.wantDrained || step.Cancelled != tt.wantCancelled {
+				t.Errorf("StopComponent() drained = %d, cancelled = %d, want %d, %d", step.Drained, step.Cancelled, tt.wantDrained, tt.wantCancelled)
+			}
+			if (step.Err != nil) != tt.wantErr {
+				t.Errorf("StopComponent() error = %v, wantErr %v", step.Err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestShutdownReport_Totals(t *testing.T) {
+	report := services.NewShutdownReport()
+	report.Add(services.ShutdownStep{Name: "http server", Drained: 4, Cancelled: 1, Err: context.DeadlineExceeded})
+	report.Add(
+		services.ShutdownStep{Name: "message consumers", Drained: 2},
+		services.ShutdownStep{Name: "outbox relay", Err: errors.New("timeout")},
+	)
+
+	if got := len(report.Steps()); got != 3 {
+		t.Errorf("Steps() = %d steps, want 3", got)
+	}
+	if got := report.Drained(); got != 6 {
+		t.Errorf("Drained() = %d, want 6", got)
+	}
+	if got := report.Cancelled(); got != 1 {
+		t.Errorf("Cancelled() = %d, want 1", got)
+	}
+	if got := report.Failed(); got != 2 {
+		t.Errorf("Failed() = %d, want 2", got)
+	}
+}
@@ -0,0 +1,114 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestSudoService_Elevate(t *testing.T) {
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, zap.NewNop())
+
+	tests := []struct {
+		name       string
+		getErr     error
+		status     domain.UserStatus
+		match      bool
+		compareErr error
+		decision   domain.RiskDecision
+		lockedOut  bool
+		wantErr    error
+	}{
+		{name: "valid password", match: true},
+		{name: "invalid password", wantErr: domainerrors.ErrInvalidCredentials},
+		{name: "denied by the risk policy", match: true, decision: domain.RiskDecisionDeny, wantErr: domainerrors.ErrAuthenticationDenied},
+		// Guesses during the lockout get the same answer whether they are right or not
+		{name: "locked out with the right password", match: true, decision: domain.RiskDecisionDeny, lockedOut: true, wantErr: domainerrors.ErrInvalidCredentials},
+		{name: "locked out with a wrong password", decision: domain.RiskDecisionDeny, lockedOut: true, wantErr: domainerrors.ErrInvalidCredentials},
+		{name: "suspended user", status: domain.UserStatusSuspended, match: true, wantErr: domainerrors.ErrUserSuspended},
+		{name: "user not found", getErr: domainerrors.ErrUserNotFound, wantErr: domainerrors.ErrUserNotFound},
+		{name: "repository error", getErr: errors.New("db down"), wantErr: domainerrors.ErrInternal},
+		{name: "hasher error", compareErr: errors.New("pool closed"), wantErr: domainerrors.ErrInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := &MockUserRepository{
+				GetByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+					if tt.getErr != nil {
+						return nil, tt.getErr
+					}
+					user := newTestUser()
+					if tt.status != "" {
+						user.Status = tt.status
+					}
+					return user, nil
+				},
+			}
+			hasher := &MockPasswordHasher{
+				CompareFunc: func(ctx context.Context, hash, password string) (bool, error) {
+					return tt.match, tt.compareErr
+				},
+			}
+			var audit *domain.AuditRecord
+			auditRepo := &MockAuditLogRepository{
+				RecordFunc: func(ctx context.Context, record *domain.AuditRecord) error {
+					audit = record
+					return nil
+				},
+			}
+
+			failures := 0
+			riskEngine := &MockRiskEngine{
+				AssessSudoFunc: func(ctx context.Context, user *domain.User) *domain.RiskAssessment {
+					if tt.decision != "" {
+						return &domain.RiskAssessment{Decision: tt.decision, LockedOut: tt.lockedOut}
+					}
+					return &domain.RiskAssessment{Decision: domain.RiskDecisionAllow}
+				},
+				RecordLoginFailureFunc: func(ctx context.Context, email string, user *domain.User) {
+					failures++
+				},
+			}
+
+			service := services.NewSudoService(userRepo, hasher, jwtService, auditRepo, riskEngine, 5*time.Minute, zap.NewNop())
+			grant, err := service.Elevate(context.Background(), 12345, "password123")
+
+			wantFailures := 0
+			if !tt.match && errors.Is(tt.wantErr, domainerrors.ErrInvalidCredentials) {
+				wantFailures = 1
+			}
+			if failures != wantFailures {
+				t.Errorf("recorded failures = %d, want %d", failures, wantFailures)
+			}
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Elevate() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if audit != nil {
+					t.Errorf("audit record = %+v after failure, want none", audit)
+				}
+				return
+			}
+
+			claims, err := jwtService.ValidateAccessToken(grant.AccessToken)
+			if err != nil {
+				t.Fatalf("ValidateAccessToken() unexpected error: %v", err)
+			}
+			if !claims.IsElevated(time.Now()) || claims.IDCitizen != 12345 {
+				t.Errorf("claims = %+v, want elevated access of 12345", claims)
+			}
+			if audit == nil || audit.Action != domain.AuditActionSudoGranted || audit.Actor != "user:12345" {
+				t.Errorf("audit record = %+v, want %q by user:12345", audit, domain.AuditActionSudoGranted)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+const tokenDebugSecret = "test-secret-key-at-least-32-chars-long"
+
+func signDebugToken(t *testing.T, secret string, claims jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func checkStatuses(report *domain.TokenDebugReport) map[string]domain.TokenCheckStatus {
+	statuses := make(map[string]domain.TokenCheckStatus, len(report.Checks))
+	for _, check := range report.Checks {
+		statuses[check.Name] = check.Status
+	}
+	return statuses
+}
+
+func TestTokenDebugService_DecodeToken(t *testing.T) {
+	jwtService := services.NewJWTService(tokenDebugSecret, 15*time.Minute, time.Hour, false, nil, services.TokenSigningPolicy{}, zap.NewNop())
+	valid, err := jwtService.GenerateAccessToken(12345, "user@example.com", domain.RoleUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	refresh, err := jwtService.GenerateRefreshToken(12345, "user@example.com", domain.RoleUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	past := time.Now().Add(-time.Hour).Unix()
+
+	tests := []struct {
+		name      string
+		token     string
+		tokenRepo *MockTokenRepository
+		expected  map[string]domain.TokenCheckStatus
+		valid     bool
+	}{
+		{
+			name:      "valid access token",
+			token:     valid,
+			tokenRepo: &MockTokenRepository{},
+			expected: map[string]domain.TokenCheckStatus{
+				domain.TokenCheckSignature:  domain.TokenCheckPassed,
+				domain.TokenCheckExpiration: domain.TokenCheckPassed,
+				domain.TokenCheckType:       domain.TokenCheckPassed,
+				domain.TokenCheckBlacklist:  domain.TokenCheckPassed,
+				domain.TokenCheckVersion:    domain.TokenCheckPassed,
+			},
+			valid: true,
+		},
+		{
+			name:      "every step runs after a failure",
+			token:     signDebugToken(t, "another-secret-key-at-least-32-chars", jwt.MapClaims{"id_citizen": 12345, "type": "access", "exp": past, "tv": 1}),
+			tokenRepo: &MockTokenRepository{GetTokenVersionFunc: func(ctx context.Context, idCitizen int) (int, error) { return 2, nil }},
+			expected: map[string]domain.TokenCheckStatus{
+				domain.TokenCheckSignature:  domain.TokenCheckFailed,
+				domain.TokenCheckExpiration: domain.TokenCheckFailed,
+				domain.TokenCheckType:       domain.TokenCheckPassed,
+				domain.TokenCheckBlacklist:  domain.TokenCheckPassed,
+				domain.TokenCheckVersion:    domain.TokenCheckFailed,
+			},
+		},
+		{
+			name:  "refresh token revoked on logout",
+			token: refresh,
+			tokenRepo: &MockTokenRepository{IsTokenBlacklistedFunc: func(ctx context.Context, token string) (bool, error) {
+				return true, nil
+			}},
+			expected: map[string]domain.TokenCheckStatus{
+				domain.TokenCheckSignature: domain.TokenCheckPassed,
+				domain.TokenCheckType:      domain.TokenCheckFailed,
+				domain.TokenCheckBlacklist: domain.TokenCheckFailed,
+			},
+		},
+		{
+			name:  "client token and unavailable blacklist",
+			token: signDebugToken(t, tokenDebugSecret, jwt.MapClaims{"client_id": "billing", "type": "client_credentials"}),
+			tokenRepo: &MockTokenRepository{IsTokenBlacklistedFunc: func(ctx context.Context, token string) (bool, error) {
+				return false, errors.New("redis down")
+			}},
+			expected: map[string]domain.TokenCheckStatus{
+				domain.TokenCheckExpiration: domain.TokenCheckPassed,
+				domain.TokenCheckType:       domain.TokenCheckFailed,
+				domain.TokenCheckBlacklist:  domain.TokenCheckError,
+				domain.TokenCheckVersion:    domain.TokenCheckSkipped,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := services.NewTokenDebugService(jwtService, tt.tokenRepo, zap.NewNop())
+
+			report, err := service.DecodeToken(context.Background(), tt.token)
+			if err != nil {
+				t.Fatalf("DecodeToken() error = %v", err)
+			}
+			if len(report.Checks) != 5 {
+				t.Errorf("len(Checks) = %d, want every step", len(report.Checks))
+			}
+			statuses := checkStatuses(report)
+			for name, want := range tt.expected {
+				if statuses[name] != want {
+					t.Errorf("%s = %s, want %s", name, statuses[name], want)
+				}
+			}
+			if report.Valid() != tt.valid {
+				t.Errorf("Valid() = %v, want %v", report.Valid(), tt.valid)
+			}
+		})
+	}
+}
+
+func TestTokenDebugService_DecodeToken_Claims(t *testing.T) {
+	jwtService := services.NewJWTService(tokenDebugSecret, 15*time.Minute, time.Hour, false, nil, services.TokenSigningPolicy{KeyID: "k1"}, zap.NewNop())
+	service := services.NewTokenDebugService(jwtService, &MockTokenRepository{}, zap.NewNop())
+
+	token, err := jwtService.GenerateAccessToken(12345, "user@example.com", domain.RoleAdmin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := service.DecodeToken(context.Background(), token)
+	if err != nil {
+		t.Fatalf("DecodeToken() error = %v", err)
+	}
+	if report.Header["kid"] != "k1" || report.Claims["email"] != "user@example.com" || report.Claims["role"] != "ADMIN" {
+		t.Errorf("report = %+v, want the header and claims of the token", report)
+	}
+	for _, check := range report.Checks {
+		if check.Name == domain.TokenCheckExpiration && !strings.HasPrefix(check.Detail, "expires at") {
+			t.Errorf("expiration detail = %q", check.Detail)
+		}
+	}
+}
+
+func TestTokenDebugService_DecodeToken_Malformed(t *testing.T) {
+	jwtService := services.NewJWTService(tokenDebugSecret, 15*time.Minute, time.Hour, false, nil, services.TokenSigningPolicy{}, zap.NewNop())
+	service := services.NewTokenDebugService(jwtService, &MockTokenRepository{}, zap.NewNop())
+
+	for _, token := range []string{"not-a-jwt", "rt_opaque", "a.b.c"} {
+		if _, err := service.DecodeToken(context.Background(), token); !errors.Is(err, domainerrors.ErrInvalidToken) {
+			t.Errorf("DecodeToken(%q) error = %v, want %v", token, err, domainerrors.ErrInvalidToken)
+		}
+	}
+}
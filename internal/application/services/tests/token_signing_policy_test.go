@@ -0,0 +1,267 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+const signingPolicyTestSecret = "test-secret-key-at-least-32-chars-long"
+
+// forgeToken signs the claims with the given algorithm and key ID, "none" producing an unsigned token
+func forgeToken(t *testing.T, method jwt.SigningMethod, kid string, claims jwt.Claims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	var key interface{} = []byte(signingPolicyTestSecret)
+	if method == jwt.SigningMethodNone {
+		key = jwt.UnsafeAllowNoneSignatureType
+	}
+	tokenString, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("failed to forge token: %v", err)
+	}
+	return tokenString
+}
+
+func TestJWTService_SigningPolicy(t *testing.T) {
+	userClaims := func() jwt.Claims {
+		return services.CustomClaims{
+			IDCitizen: 12345,
+			Email:     "test@example.com",
+			Role:      domain.RoleAdmin,
+			Type:      domain.TokenTypeAccess,
+			RegisteredClaims: jwt.RegisteredClaims{
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		policy  services.TokenSigningPolicy
+		method  jwt.SigningMethod
+		kid     string
+		wantErr bool
+	}{
+		{name: "expected algorithm", method: jwt.SigningMethodHS256},
+		{name: "alg none", method: jwt.SigningMethodNone, wantErr: true},
+		{name: "alg none with key id", policy: services.TokenSigningPolicy{KeyID: "key-1"}, method: jwt.SigningMethodNone, kid: "key-1", wantErr: true},
+		{name: "unexpected algorithm", method: jwt.SigningMethodHS512, wantErr: true},
+		{name: "configured algorithm", policy: services.TokenSigningPolicy{Algorithm: "HS512"}, method: jwt.SigningMethodHS512},
+		{name: "previous algorithm accepted", policy: services.TokenSigningPolicy{Algorithm: "HS512", AcceptedAlgorithms: []string{"HS512", "HS256"}}, method: jwt.SigningMethodHS256},
+		{name: "key id", policy: services.TokenSigningPolicy{KeyID: "key-1"}, method: jwt.SigningMethodHS256, kid: "key-1"},
+		{name: "missing key id", policy: services.TokenSigningPolicy{KeyID: "key-1"}, method: jwt.SigningMethodHS256, wantErr: true},
+		{name: "unknown key id", policy: services.TokenSigningPolicy{KeyID: "key-1"}, method: jwt.SigningMethodHS256, kid: "key-0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jwtService := services.NewJWTService(signingPolicyTestSecret, 15*time.Minute, 7*24*time.Hour, false, nil, tt.policy, zap.NewNop())
+			token := forgeToken(t, tt.method, tt.kid, userClaims())
+
+			claims, err := jwtService.ValidateAccessToken(token)
+			if tt.wantErr {
+				if !errors.Is(err, domainerrors.ErrInvalidToken) {
+					t.Errorf("ValidateAccessToken() error = %v, want %v", err, domainerrors.ErrInvalidToken)
+				}
+				if _, err := jwtService.GetTokenExpiration(token); err == nil {
+					t.Errorf("GetTokenExpiration() expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ValidateAccessToken() unexpected error: %v", err)
+			}
+			if claims.IDCitizen != 12345 {
+				t.Errorf("IDCitizen = %v, want 12345", claims.IDCitizen)
+			}
+		})
+	}
+}
+
+func TestJWTService_SigningPolicyIssuedTokens(t *testing.T) {
+	policy := services.TokenSigningPolicy{Algorithm: "HS384", KeyID: "key-1"}
+	jwtService := services.NewJWTService(signingPolicyTestSecret, 15*time.Minute, 7*24*time.Hour, false, nil, policy, zap.NewNop())
+	user := newTestUser()
+
+	tokenPair, err := jwtService.GenerateUserTokenPair(user)
+	if err != nil {
+		t.Fatalf("GenerateUserTokenPair() unexpected error: %v", err)
+	}
+
+	for _, tokenString := range []string{tokenPair.AccessToken, tokenPair.RefreshToken} {
+		token, _, err := jwt.NewParser().ParseUnverified(tokenString, &services.CustomClaims{})
+		if err != nil {
+			t.Fatalf("failed to parse token: %v", err)
+		}
+		if token.Header["alg"] != "HS384" || token.Header["kid"] != "key-1" {
+			t.Errorf("header = %v, want alg HS384 and kid key-1", token.Header)
+		}
+	}
+
+	if _, err := jwtService.ValidateAccessToken(tokenPair.AccessToken); err != nil {
+		t.Errorf("ValidateAccessToken() unexpected error: %v", err)
+	}
+
+	// A service still on the previous key rejects the tokens of the new one
+	previous := services.NewJWTService(signingPolicyTestSecret, 15*time.Minute, 7*24*time.Hour, false, nil,
+		services.TokenSigningPolicy{Algorithm: "HS384", KeyID: "key-0"}, zap.NewNop())
+	if _, err := previous.ValidateAccessToken(tokenPair.AccessToken); !errors.Is(err, domainerrors.ErrInvalidToken) {
+		t.Errorf("ValidateAccessToken() with another key ID error = %v, want %v", err, domainerrors.ErrInvalidToken)
+	}
+}
+
+func TestOAuth2Service_SigningPolicy(t *testing.T) {
+	clientClaims := func() jwt.Claims {
+		return jwt.MapClaims{
+			"client_id": "client-123",
+			"scopes":    []string{"read"},
+			"jti":       "token-1",
+			"exp":       time.Now().Add(time.Hour).Unix(),
+			"type":      "client_credentials",
+		}
+	}
+
+	tests := []struct {
+		name    string
+		policy  services.TokenSigningPolicy
+		method  jwt.SigningMethod
+		kid     string
+		wantErr bool
+	}{
+		{name: "expected algorithm", method: jwt.SigningMethodHS256},
+		{name: "alg none", method: jwt.SigningMethodNone, wantErr: true},
+		{name: "unexpected algorithm", method: jwt.SigningMethodHS384, wantErr: true},
+		{name: "key id", policy: services.TokenSigningPolicy{KeyID: "key-1"}, method: jwt.SigningMethodHS256, kid: "key-1"},
+		{name: "missing key id", policy: services.TokenSigningPolicy{KeyID: "key-1"}, method: jwt.SigningMethodHS256, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oauth2Service := services.NewOAuth2Service(&MockOAuthClientRepository{}, &MockScopeRepository{}, signingPolicyTestSecret, 15*time.Minute, tt.policy, zap.NewNop())
+			token := forgeToken(t, tt.method, tt.kid, clientClaims())
+
+			claims, err := oauth2Service.ValidateAccessToken(context.Background(), token)
+			if tt.wantErr {
+				if !errors.Is(err, domainerrors.ErrInvalidToken) {
+					t.Errorf("ValidateAccessToken() error = %v, want %v", err, domainerrors.ErrInvalidToken)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ValidateAccessToken() unexpected error: %v", err)
+			}
+			if claims.ClientID != "client-123" {
+				t.Errorf("ClientID = %v, want client-123", claims.ClientID)
+			}
+		})
+	}
+}
+
+func TestOAuth2Service_SigningPolicyIssuedTokens(t *testing.T) {
+	testClient, _ := domain.NewOAuthClient("client-123", "secret123", "Test Client", "Test Description", []string{"read"})
+	clientRepo := &MockOAuthClientRepository{
+		GetByClientIDFunc: func(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+			return testClient, nil
+		},
+	}
+	policy := services.TokenSigningPolicy{Algorithm: "HS512", KeyID: "key-1"}
+	oauth2Service := services.NewOAuth2Service(clientRepo, &MockScopeRepository{}, signingPolicyTestSecret, 15*time.Minute, policy, zap.NewNop())
+
+	tokenString, _, err := oauth2Service.ClientCredentials(context.Background(), "client-123", "secret123", nil, "")
+	if err != nil {
+		t.Fatalf("ClientCredentials() unexpected error: %v", err)
+	}
+
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, jwt.MapClaims{})
+	if err != nil {
+		t.Fatalf("failed to parse token: %v", err)
+	}
+	if token.Header["alg"] != "HS512" || token.Header["kid"] != "key-1" {
+		t.Errorf("header = %v, want alg HS512 and kid key-1", token.Header)
+	}
+	if _, err := oauth2Service.ValidateAccessToken(context.Background(), tokenString); err != nil {
+		t.Errorf("ValidateAccessToken() unexpected error: %v", err)
+	}
+}
+
+func TestJWTService_TokenCompatibility(t *testing.T) {
+	// A token of a previous version: no key ID, issued-at or token version
+	previousClaims := services.CustomClaims{
+		IDCitizen: 12345,
+		Email:     "test@example.com",
+		Role:      domain.RoleUser,
+		Type:      domain.TokenTypeAccess,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			Subject:   "12345",
+		},
+	}
+
+	tests := []struct {
+		name    string
+		policy  services.TokenSigningPolicy
+		kid     string
+		wantErr bool
+	}{
+		{name: "required claims present", policy: services.TokenSigningPolicy{RequiredClaims: []string{"exp", "sub"}}},
+		{name: "required claim missing", policy: services.TokenSigningPolicy{RequiredClaims: []string{"exp", "iat"}}, wantErr: true},
+		{name: "required key id missing", policy: services.TokenSigningPolicy{RequiredClaims: []string{"kid"}}, wantErr: true},
+		{name: "required key id present", policy: services.TokenSigningPolicy{RequiredClaims: []string{"kid"}}, kid: "key-1"},
+		{name: "required claims in compatibility mode", policy: services.TokenSigningPolicy{RequiredClaims: []string{"iat", "tv"}, CompatibilityMode: true}},
+		{name: "missing key id in compatibility mode", policy: services.TokenSigningPolicy{KeyID: "key-1", CompatibilityMode: true}},
+		{name: "unknown key id in compatibility mode", policy: services.TokenSigningPolicy{KeyID: "key-1", CompatibilityMode: true}, kid: "key-0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jwtService := services.NewJWTService(signingPolicyTestSecret, 15*time.Minute, 7*24*time.Hour, false, nil, tt.policy, zap.NewNop())
+			token := forgeToken(t, jwt.SigningMethodHS256, tt.kid, previousClaims)
+
+			_, err := jwtService.ValidateAccessToken(token)
+			if tt.wantErr {
+				if !errors.Is(err, domainerrors.ErrInvalidToken) {
+					t.Errorf("ValidateAccessToken() error = %v, want %v", err, domainerrors.ErrInvalidToken)
+				}
+			} else if err != nil {
+				t.Errorf("ValidateAccessToken() unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestTokenSigningPolicy_CompatibilityWarnings(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       services.TokenSigningPolicy
+		wantWarnings int
+	}{
+		{name: "default policy", policy: services.TokenSigningPolicy{}},
+		{name: "key id", policy: services.TokenSigningPolicy{KeyID: "key-1"}, wantWarnings: 1},
+		{name: "required claims issued in every token", policy: services.TokenSigningPolicy{RequiredClaims: []string{"exp", "sub", "jti"}}, wantWarnings: 1},
+		{name: "required claims not issued in every token", policy: services.TokenSigningPolicy{RequiredClaims: []string{"exp", "uid"}}, wantWarnings: 2},
+		{name: "required key id without key id", policy: services.TokenSigningPolicy{RequiredClaims: []string{"kid"}}, wantWarnings: 2},
+		{name: "default algorithm not accepted", policy: services.TokenSigningPolicy{Algorithm: "HS512"}, wantWarnings: 1},
+		{name: "compatibility mode", policy: services.TokenSigningPolicy{KeyID: "key-1", RequiredClaims: []string{"jti"}, CompatibilityMode: true}, wantWarnings: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.CompatibilityWarnings(); len(got) != tt.wantWarnings {
+				t.Errorf("CompatibilityWarnings() = %q, want %d warnings", got, tt.wantWarnings)
+			}
+		})
+	}
+}
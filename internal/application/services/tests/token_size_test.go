@@ -0,0 +1,143 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// registeredScopeRepository returns a MockScopeRepository where every scope is registered
+func registeredScopeRepository() *MockScopeRepository {
+	return &MockScopeRepository{
+		GetByNamesFunc: func(ctx context.Context, names []string) ([]*domain.Scope, error) {
+			scopes := make([]*domain.Scope, len(names))
+			for i, name := range names {
+				scopes[i] = &domain.Scope{Name: name}
+			}
+			return scopes, nil
+		},
+	}
+}
+
+func manyScopes(n int) []string {
+	scopes := make([]string, n)
+	for i := range scopes {
+		scopes[i] = fmt.Sprintf("resource-%03d:read", i)
+	}
+	return scopes
+}
+
+func TestOAuth2Service_CreateClient_MaxTokenSize(t *testing.T) {
+	tests := []struct {
+		name         string
+		maxTokenSize int
+		scopes       []string
+		wantErr      error
+	}{
+		{name: "within the limit", maxTokenSize: 4096, scopes: manyScopes(5)},
+		{name: "over the limit", maxTokenSize: 4096, scopes: manyScopes(300), wantErr: domainerrors.ErrAccessTokenTooLarge},
+		{name: "check disabled", scopes: manyScopes(300)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created := false
+			clientRepo := &MockOAuthClientRepository{
+				CreateFunc: func(ctx context.Context, client *domain.OAuthClient) error {
+					created = true
+					return nil
+				},
+			}
+			policy := services.TokenSigningPolicy{MaxTokenSize: tt.maxTokenSize}
+			oauth2Service := services.NewOAuth2Service(clientRepo, registeredScopeRepository(), signingPolicyTestSecret, 15*time.Minute, policy, zap.NewNop())
+
+			_, err := oauth2Service.CreateClient(context.Background(), "client-123", "secret123", "Test Client", "", tt.scopes, nil, nil, nil, nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateClient() error = %v, want %v", err, tt.wantErr)
+			}
+			if created != (tt.wantErr == nil) {
+				t.Errorf("client created = %v, want %v", created, tt.wantErr == nil)
+			}
+		})
+	}
+}
+
+func TestOAuth2Service_UpdateClient_MaxTokenSize(t *testing.T) {
+	client, _ := domain.NewOAuthClient("client-123", "secret123", "Test Client", "", []string{"read"})
+	client.Version = 1
+	clientRepo := &MockOAuthClientRepository{
+		GetByIDFunc: func(ctx context.Context, id string) (*domain.OAuthClient, error) {
+			return client, nil
+		},
+		UpdateFunc: func(ctx context.Context, client *domain.OAuthClient) error {
+			t.Error("Update() should not be called for a client whose tokens are too large")
+			return nil
+		},
+	}
+	policy := services.TokenSigningPolicy{MaxTokenSize: 4096}
+	oauth2Service := services.NewOAuth2Service(clientRepo, registeredScopeRepository(), signingPolicyTestSecret, 15*time.Minute, policy, zap.NewNop())
+
+	_, err := oauth2Service.UpdateClient(context.Background(), "id-123", 1, nil, nil, manyScopes(300), nil, nil, nil, nil)
+	if !errors.Is(err, domainerrors.ErrAccessTokenTooLarge) {
+		t.Errorf("UpdateClient() error = %v, want %v", err, domainerrors.ErrAccessTokenTooLarge)
+	}
+}
+
+func TestOAuth2Service_CheckClientTokenSizes(t *testing.T) {
+	small, _ := domain.NewOAuthClient("small", "secret123", "Small", "", []string{"read"})
+	large, _ := domain.NewOAuthClient("large", "secret123", "Large", "", manyScopes(300))
+	clientRepo := &MockOAuthClientRepository{
+		ListFunc: func(ctx context.Context) ([]*domain.OAuthClient, error) {
+			return []*domain.OAuthClient{small, large}, nil
+		},
+	}
+	policy := services.TokenSigningPolicy{MaxTokenSize: 4096}
+	oauth2Service := services.NewOAuth2Service(clientRepo, &MockScopeRepository{}, signingPolicyTestSecret, 15*time.Minute, policy, zap.NewNop())
+
+	oversized, err := oauth2Service.CheckClientTokenSizes(context.Background())
+	if err != nil || oversized != 1 {
+		t.Errorf("CheckClientTokenSizes() = %d, %v, want 1 oversized client", oversized, err)
+	}
+}
+
+func TestJWTService_CheckTokenSize(t *testing.T) {
+	tests := []struct {
+		name         string
+		maxTokenSize int
+		wantErr      bool
+	}{
+		{name: "within the limit", maxTokenSize: 4096},
+		{name: "over the limit", maxTokenSize: 256, wantErr: true},
+		{name: "check disabled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := services.TokenSigningPolicy{MaxTokenSize: tt.maxTokenSize}
+			jwtService := services.NewJWTService(signingPolicyTestSecret, 15*time.Minute, 7*24*time.Hour, false, []string{"department"}, policy, zap.NewNop())
+
+			if err := jwtService.CheckTokenSize(); (err != nil) != tt.wantErr {
+				t.Errorf("CheckTokenSize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestJWTService_OversizedTokensAreIssued(t *testing.T) {
+	policy := services.TokenSigningPolicy{MaxTokenSize: 64}
+	jwtService := services.NewJWTService(signingPolicyTestSecret, 15*time.Minute, 7*24*time.Hour, false, nil, policy, zap.NewNop())
+
+	user, _ := domain.NewUser("test@example.com", "password123", "Test User", 12345)
+	pair, err := jwtService.GenerateUserTokenPair(user)
+	if err != nil || len(pair.AccessToken) <= policy.MaxTokenSize {
+		t.Errorf("GenerateUserTokenPair() = %v, %v, want an oversized token still issued", pair, err)
+	}
+}
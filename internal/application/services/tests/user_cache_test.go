@@ -0,0 +1,159 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestAuthService_GetUserByIDCitizen_Cache(t *testing.T) {
+	logger := zap.NewNop()
+	cachedUser := &domain.UserPublic{ID: "user-123", IDCitizen: 12345, Email: "cached@example.com"}
+
+	tests := []struct {
+		name          string
+		bypass        bool
+		cached        *domain.UserPublic
+		cacheErr      error
+		wantEmail     string
+		wantDBLookups int
+		wantCached    bool
+	}{
+		{name: "cache hit skips the database", cached: cachedUser, wantEmail: "cached@example.com"},
+		{name: "cache miss reads the database and caches the user", wantEmail: "test@example.com", wantDBLookups: 1, wantCached: true},
+		{name: "bypass reads the database and refreshes the cache", bypass: true, cached: cachedUser, wantEmail: "test@example.com", wantDBLookups: 1, wantCached: true},
+		{name: "failing cache falls back to the database", cacheErr: errors.New("connection refused"), wantEmail: "test@example.com", wantDBLookups: 1, wantCached: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbLookups := 0
+			userRepo := &MockUserRepository{
+				GetByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+					dbLookups++
+					return newTestUser(), nil
+				},
+			}
+			var stored *domain.UserPublic
+			cache := &MockUserCache{
+				GetFunc: func(ctx context.Context, idCitizen int) (*domain.UserPublic, error) {
+					return tt.cached, tt.cacheErr
+				},
+				SetFunc: func(ctx context.Context, user *domain.UserPublic) error {
+					stored = user
+					return nil
+				},
+			}
+			jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, logger)
+			authService := services.NewAuthService(userRepo, &MockTokenRepository{}, jwtService, &MockPasswordHasher{}, logger, services.WithUserCache(cache))
+
+			ctx := context.Background()
+			if tt.bypass {
+				ctx = domain.ContextWithCacheBypass(ctx)
+			}
+			user, err := authService.GetUserByIDCitizen(ctx, 12345)
+			if err != nil {
+				t.Fatalf("GetUserByIDCitizen() unexpected error = %v", err)
+			}
+
+			if user.Email != tt.wantEmail {
+				t.Errorf("Email = %v, want %v", user.Email, tt.wantEmail)
+			}
+			if dbLookups != tt.wantDBLookups {
+				t.Errorf("database lookups = %d, want %d", dbLookups, tt.wantDBLookups)
+			}
+			if (stored != nil) != tt.wantCached {
+				t.Errorf("cached user = %+v, want cached %v", stored, tt.wantCached)
+			}
+		})
+	}
+}
+
+func TestAuthService_GetUserByIDCitizen_CacheNotFound(t *testing.T) {
+	userRepo := &MockUserRepository{
+		GetByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+			return nil, domainerrors.ErrUserNotFound
+		},
+	}
+	cache := &MockUserCache{
+		SetFunc: func(ctx context.Context, user *domain.UserPublic) error {
+			t.Error("Set() called for a user that does not exist")
+			return nil
+		},
+	}
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, zap.NewNop())
+	authService := services.NewAuthService(userRepo, &MockTokenRepository{}, jwtService, &MockPasswordHasher{}, zap.NewNop(), services.WithUserCache(cache))
+
+	if _, err := authService.GetUserByIDCitizen(context.Background(), 12345); !errors.Is(err, domainerrors.ErrUserNotFound) {
+		t.Errorf("GetUserByIDCitizen() error = %v, want %v", err, domainerrors.ErrUserNotFound)
+	}
+}
+
+func TestUserCacheInvalidator(t *testing.T) {
+	tests := []struct {
+		name       string
+		updateErr  error
+		deleteErr  error
+		getByIDErr error
+		delete     bool
+		wantErr    bool
+		wantEvict  bool
+	}{
+		{name: "update evicts the user", wantEvict: true},
+		{name: "failed update keeps the cache", updateErr: errors.New("connection refused"), wantErr: true},
+		{name: "delete evicts the user", delete: true, wantEvict: true},
+		{name: "failed delete keeps the cache", delete: true, deleteErr: errors.New("connection refused"), wantErr: true},
+		{name: "delete of a user that can't be read leaves the cache to the TTL", delete: true, getByIDErr: domainerrors.ErrUserNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := &MockUserRepository{
+				GetByIDFunc: func(ctx context.Context, id string) (*domain.User, error) {
+					if tt.getByIDErr != nil {
+						return nil, tt.getByIDErr
+					}
+					return newTestUser(), nil
+				},
+				UpdateFunc: func(ctx context.Context, user *domain.User) error {
+					return tt.updateErr
+				},
+				DeleteFunc: func(ctx context.Context, id string) error {
+					return tt.deleteErr
+				},
+			}
+			var evicted []int
+			cache := &MockUserCache{
+				DeleteFunc: func(ctx context.Context, idCitizen int) error {
+					evicted = append(evicted, idCitizen)
+					return nil
+				},
+			}
+			invalidator := services.NewUserCacheInvalidator(userRepo, cache, zap.NewNop())
+
+			var err error
+			if tt.delete {
+				err = invalidator.Delete(context.Background(), "user-123")
+			} else {
+				err = invalidator.Update(context.Background(), newTestUser())
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantEvict && (len(evicted) != 1 || evicted[0] != 12345) {
+				t.Errorf("evicted = %v, want [12345]", evicted)
+			}
+			if !tt.wantEvict && len(evicted) != 0 {
+				t.Errorf("evicted = %v, want none", evicted)
+			}
+		})
+	}
+}
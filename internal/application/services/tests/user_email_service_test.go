@@ -0,0 +1,267 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	"github.com/kristianrpo/auth-microservice/internal/domain/events"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// newTestUserEmailService builds a UserEmailService whose user repository always returns newTestUser
+func newTestUserEmailService(emailRepo *MockUserEmailRepository, publisher *MockMessagePublisher, limiter *MockRateLimiter) *services.UserEmailService {
+	userRepo := &MockUserRepository{
+		GetByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+			return newTestUser(), nil
+		},
+		ExistsFunc: func(ctx context.Context, email string) (bool, error) {
+			return email == "test@example.com", nil
+		},
+	}
+	return services.NewUserEmailService(userRepo, emailRepo, publisher, "test.security", limiter, 15*time.Minute, zap.NewNop())
+}
+
+func TestUserEmailService_AddEmail(t *testing.T) {
+	tests := []struct {
+		name        string
+		email       string
+		existing    int
+		limiter     *MockRateLimiter
+		publishErr  error
+		wantErr     error
+		wantSent    bool
+		wantDeleted bool
+	}{
+		{name: "sends code", email: " Recovery@Example.org ", wantSent: true},
+		{name: "invalid email", email: "Recovery <recovery@example.org>", wantErr: domainerrors.ErrInvalidEmail},
+		{name: "primary email of a user", email: "TEST@example.com", wantErr: domainerrors.ErrEmailAlreadyRegistered},
+		{name: "too many emails", email: "recovery@example.org", existing: domain.MaxUserEmails, wantErr: domainerrors.ErrTooManyEmails},
+		{name: "rate limited", email: "recovery@example.org", limiter: exceededLimiter(), wantErr: domainerrors.ErrEmailRateLimited},
+		{name: "publish failure", email: "recovery@example.org", publishErr: errors.New("broker down"), wantErr: domainerrors.ErrInternal, wantSent: true, wantDeleted: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.limiter == nil {
+				tt.limiter = &MockRateLimiter{}
+			}
+
+			var created *domain.UserEmail
+			deleted := false
+			emailRepo := &MockUserEmailRepository{
+				ListByUserIDFunc: func(ctx context.Context, userID string) ([]*domain.UserEmail, error) {
+					return make([]*domain.UserEmail, tt.existing), nil
+				},
+				CreateFunc: func(ctx context.Context, email *domain.UserEmail) error {
+					email.ID = "email-1"
+					created = email
+					return nil
+				},
+				DeleteFunc: func(ctx context.Context, userID, id string) error {
+					deleted = true
+					return nil
+				},
+			}
+			var event *events.SecurityNotificationEvent
+			publisher := &MockMessagePublisher{
+				PublishFunc: func(ctx context.Context, queueName string, message []byte) error {
+					event = &events.SecurityNotificationEvent{}
+					if err := json.Unmarshal(message, event); err != nil {
+						t.Fatalf("failed to decode event: %v", err)
+					}
+					return tt.publishErr
+				},
+			}
+
+			email, err := newTestUserEmailService(emailRepo, publisher, tt.limiter).AddEmail(context.Background(), 12345, tt.email)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AddEmail() error = %v, want %v", err, tt.wantErr)
+			}
+			if (event != nil) != tt.wantSent {
+				t.Errorf("code sent = %v, want %v", event != nil, tt.wantSent)
+			}
+			if deleted != tt.wantDeleted {
+				t.Errorf("email deleted = %v, want %v", deleted, tt.wantDeleted)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if email.Email != "recovery@example.org" || email.IsVerified() || email.UserID != "user-123" {
+				t.Errorf("AddEmail() = %+v, want unverified recovery@example.org", email)
+			}
+			if event.Email != "recovery@example.org" || event.Type != string(domain.NotificationEmailVerification) {
+				t.Errorf("event = %+v, want email_verification to recovery@example.org", event)
+			}
+			code := event.Details["code"]
+			if len(code) != 6 || created.CodeHash == "" || created.CodeHash == code {
+				t.Errorf("code = %q, hash = %q, want 6-digit code stored hashed", code, created.CodeHash)
+			}
+		})
+	}
+}
+
+func TestUserEmailService_VerifyEmail(t *testing.T) {
+	// The code hash is taken from a real AddEmail call
+	var pending *domain.UserEmail
+	var code string
+	setup := newTestUserEmailService(
+		&MockUserEmailRepository{
+			CreateFunc: func(ctx context.Context, email *domain.UserEmail) error {
+				email.ID = "email-1"
+				pending = email
+				return nil
+			},
+		},
+		&MockMessagePublisher{
+			PublishFunc: func(ctx context.Context, queueName string, message []byte) error {
+				var event events.SecurityNotificationEvent
+				if err := json.Unmarshal(message, &event); err != nil {
+					return err
+				}
+				code = event.Details["code"]
+				return nil
+			},
+		},
+		&MockRateLimiter{},
+	)
+	if _, err := setup.AddEmail(context.Background(), 12345, "recovery@example.org"); err != nil {
+		t.Fatalf("AddEmail() error = %v", err)
+	}
+
+	verifiedAt := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name         string
+		email        func() *domain.UserEmail
+		code         string
+		wantErr      error
+		wantVerified bool
+		wantAttempts int
+		wantUpdated  bool
+	}{
+		{
+			name:         "correct code",
+			email:        func() *domain.UserEmail { e := *pending; return &e },
+			code:         code,
+			wantVerified: true,
+			wantUpdated:  true,
+		},
+		{
+			name:         "wrong code",
+			email:        func() *domain.UserEmail { e := *pending; return &e },
+			code:         "000000",
+			wantErr:      domainerrors.ErrInvalidVerificationCode,
+			wantAttempts: 1,
+			wantUpdated:  true,
+		},
+		{
+			name: "expired code",
+			email: func() *domain.UserEmail {
+				e := *pending
+				e.CodeExpiresAt = time.Now().Add(-time.Minute)
+				return &e
+			},
+			code:    code,
+			wantErr: domainerrors.ErrInvalidVerificationCode,
+		},
+		{
+			name: "exhausted code",
+			email: func() *domain.UserEmail {
+				e := *pending
+				e.CodeAttempts = domain.MaxEmailVerificationAttempts
+				return &e
+			},
+			code:         code,
+			wantErr:      domainerrors.ErrInvalidVerificationCode,
+			wantAttempts: domain.MaxEmailVerificationAttempts,
+		},
+		{
+			name: "already verified",
+			email: func() *domain.UserEmail {
+				return &domain.UserEmail{ID: "email-1", UserID: "user-123", Email: "recovery@example.org", VerifiedAt: &verifiedAt}
+			},
+			code:         "000000",
+			wantVerified: true,
+		},
+		{name: "email not found", email: func() *domain.UserEmail { return nil }, code: code, wantErr: domainerrors.ErrEmailNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored := tt.email()
+			var updated *domain.UserEmail
+			emailRepo := &MockUserEmailRepository{
+				GetByIDFunc: func(ctx context.Context, userID, id string) (*domain.UserEmail, error) {
+					if stored == nil || userID != "user-123" || id != "email-1" {
+						return nil, domainerrors.ErrEmailNotFound
+					}
+					return stored, nil
+				},
+				UpdateFunc: func(ctx context.Context, email *domain.UserEmail) error {
+					updated = email
+					return nil
+				},
+			}
+
+			email, err := newTestUserEmailService(emailRepo, &MockMessagePublisher{}, &MockRateLimiter{}).VerifyEmail(context.Background(), 12345, "email-1", tt.code)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("VerifyEmail() error = %v, want %v", err, tt.wantErr)
+			}
+			if (updated != nil) != tt.wantUpdated {
+				t.Errorf("email updated = %v, want %v", updated != nil, tt.wantUpdated)
+			}
+			if stored != nil && stored.CodeAttempts != tt.wantAttempts {
+				t.Errorf("code attempts = %v, want %v", stored.CodeAttempts, tt.wantAttempts)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if email.IsVerified() != tt.wantVerified {
+				t.Errorf("VerifyEmail() verified = %v, want %v", email.IsVerified(), tt.wantVerified)
+			}
+			if email.CodeHash != "" {
+				t.Errorf("VerifyEmail() kept code hash %q, codes are single use", email.CodeHash)
+			}
+		})
+	}
+}
+
+func TestUserEmailService_RemoveEmail(t *testing.T) {
+	tests := []struct {
+		name      string
+		deleteErr error
+		wantErr   error
+	}{
+		{name: "removes email"},
+		{name: "email not found", deleteErr: domainerrors.ErrEmailNotFound, wantErr: domainerrors.ErrEmailNotFound},
+		{name: "repository failure", deleteErr: errors.New("db down"), wantErr: domainerrors.ErrInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			emailRepo := &MockUserEmailRepository{
+				DeleteFunc: func(ctx context.Context, userID, id string) error {
+					if userID != "user-123" || id != "email-1" {
+						t.Errorf("Delete() = (%v, %v), want (user-123, email-1)", userID, id)
+					}
+					return tt.deleteErr
+				},
+			}
+
+			err := newTestUserEmailService(emailRepo, &MockMessagePublisher{}, &MockRateLimiter{}).RemoveEmail(context.Background(), 12345, "email-1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("RemoveEmail() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,455 @@
+package tests
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func newTestUserExportPolicy() services.UserExportPolicy {
+	return services.UserExportPolicy{
+		Workers:          1,
+		QueueSize:        1,
+		Timeout:          time.Minute,
+		Retention:        24 * time.Hour,
+		URLExpiry:        15 * time.Minute,
+		CleanupInterval:  time.Hour,
+		CleanupBatchSize: 2,
+	}
+}
+
+func newTestUserExportJobService(jobRepo *MockUserExportJobRepository, storage *MockBlobStorage, auditRepo *MockAuditLogRepository) *services.UserExportJobService {
+	userRepo := &MockUserRepository{
+		GetByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+			return newTestUser(), nil
+		},
+		GetByIDFunc: func(ctx context.Context, id string) (*domain.User, error) {
+			user := newTestUser()
+			user.Password = "$2a$10$hash"
+			return user, nil
+		},
+	}
+	emailRepo := &MockUserEmailRepository{
+		ListByUserIDFunc: func(ctx context.Context, userID string) ([]*domain.UserEmail, error) {
+			return []*domain.UserEmail{{ID: "email-1", UserID: userID, Email: "backup@example.com", CodeHash: "secret"}}, nil
+		},
+	}
+	consentRepo := &MockConsentRepository{
+		ListByUserFunc: func(ctx context.Context, userID string) ([]*domain.Consent, error) {
+			return []*domain.Consent{{UserID: userID, ClientID: "client-1", Scopes: []string{"profile"}}}, nil
+		},
+	}
+	auditLog := &MockAuditLogQueryRepository{
+		StreamRecordsFunc: func(ctx context.Context, filter domain.AuditLogFilter, fn func(*domain.AuditRecord) error) error {
+			if filter.TargetID != "user-123" {
+				return errors.New("unexpected target " + filter.TargetID)
+			}
+			for _, id := range []string{"audit-1", "audit-2"} {
+				if err := fn(&domain.AuditRecord{ID: id, Action: domain.AuditActionUserUpdated, TargetID: filter.TargetID}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	return services.NewUserExportJobService(userRepo, emailRepo, consentRepo, &MockNotificationPreferencesRepository{}, auditLog, auditRepo,
+		jobRepo, storage, newTestUserExportPolicy(), zap.NewNop())
+}
+
+func TestUserExportJobService_RequestExport(t *testing.T) {
+	running := &domain.UserExportJob{ID: "job-running", UserID: "user-123", Status: domain.UserExportJobRunning, CreatedAt: time.Now()}
+	// Older than the queue wait and the timeout of an export, lost by a restart
+	stale := &domain.UserExportJob{ID: "job-stale", UserID: "user-123", Status: domain.UserExportJobPending, CreatedAt: time.Now().Add(-time.Hour)}
+	completed := &domain.UserExportJob{ID: "job-completed", UserID: "user-123", Status: domain.UserExportJobCompleted}
+
+	tests := []struct {
+		name      string
+		latest    *domain.UserExportJob
+		latestErr error
+		queued    int
+		wantErr   error
+		wantID    string
+		wantNew   bool
+	}{
+		{name: "first export", wantNew: true},
+		{name: "previous export finished", latest: completed, wantNew: true},
+		{name: "export in progress is returned", latest: running, wantID: running.ID},
+		{name: "stale export is failed", latest: stale, wantNew: true},
+		{name: "queue full", queued: 1, wantErr: domainerrors.ErrExportsBusy},
+		{name: "repository failure", latestErr: errors.New("redis down"), wantErr: domainerrors.ErrInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stored *domain.UserExportJob
+			var updated []domain.UserExportJobStatus
+			jobRepo := &MockUserExportJobRepository{
+				GetLatestByUserFunc: func(ctx context.Context, userID string) (*domain.UserExportJob, error) {
+					if tt.latest == nil && tt.latestErr == nil {
+						return nil, domainerrors.ErrExportJobNotFound
+					}
+					return tt.latest, tt.latestErr
+				},
+				StoreFunc: func(ctx context.Context, job *domain.UserExportJob) error {
+					stored = job
+					return nil
+				},
+				UpdateFunc: func(ctx context.Context, job *domain.UserExportJob) error {
+					updated = append(updated, job.Status)
+					return nil
+				},
+			}
+			service := newTestUserExportJobService(jobRepo, &MockBlobStorage{}, &MockAuditLogRepository{})
+			// The workers are not started, requested exports stay queued
+			for i := 0; i < tt.queued; i++ {
+				if _, err := service.RequestExport(context.Background(), 99999); err != nil {
+					t.Fatalf("RequestExport() setup error = %v", err)
+				}
+			}
+			updated = nil
+
+			job, err := service.RequestExport(context.Background(), 12345)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RequestExport() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == domainerrors.ErrExportsBusy && (len(updated) != 1 || updated[0] != domain.UserExportJobFailed) {
+				t.Errorf("rejected job updates = %v, want [failed]", updated)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if tt.wantID != "" && job.ID != tt.wantID {
+				t.Errorf("RequestExport() job = %v, want %v", job.ID, tt.wantID)
+			}
+			if !tt.wantNew {
+				return
+			}
+			if tt.latest == stale && stale.Status != domain.UserExportJobFailed {
+				t.Errorf("stale job status = %v, want failed", tt.latest.Status)
+			}
+			if stored == nil || stored != job || job.Status != domain.UserExportJobPending || job.UserID != "user-123" {
+				t.Fatalf("stored job = %+v, want the returned pending job", stored)
+			}
+			if job.ObjectKey != "exports/user-123/"+job.ID+".zip" {
+				t.Errorf("object key = %v, want exports/user-123/<job>.zip", job.ObjectKey)
+			}
+			if got := job.ExpiresAt.Sub(job.CreatedAt); got != 24*time.Hour {
+				t.Errorf("retention = %v, want 24h", got)
+			}
+		})
+	}
+}
+
+func TestUserExportJobService_GetExport(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name          string
+		job           *domain.UserExportJob
+		wantErr       error
+		wantURL       bool
+		wantExpiresIn time.Duration
+	}{
+		{name: "not found", wantErr: domainerrors.ErrExportJobNotFound},
+		{
+			name:    "job of another user",
+			job:     &domain.UserExportJob{ID: "job-1", UserID: "user-456", Status: domain.UserExportJobCompleted, ExpiresAt: now.Add(time.Hour)},
+			wantErr: domainerrors.ErrExportJobNotFound,
+		},
+		{name: "pending", job: &domain.UserExportJob{ID: "job-1", UserID: "user-123", Status: domain.UserExportJobPending, ExpiresAt: now.Add(time.Hour)}},
+		{
+			name:          "completed",
+			job:           &domain.UserExportJob{ID: "job-1", UserID: "user-123", Status: domain.UserExportJobCompleted, ObjectKey: "exports/user-123/job-1.zip", ExpiresAt: now.Add(time.Hour)},
+			wantURL:       true,
+			wantExpiresIn: 15 * time.Minute,
+		},
+		{
+			name:          "url does not outlive the archive",
+			job:           &domain.UserExportJob{ID: "job-1", UserID: "user-123", Status: domain.UserExportJobCompleted, ObjectKey: "exports/user-123/job-1.zip", ExpiresAt: now.Add(5 * time.Minute)},
+			wantURL:       true,
+			wantExpiresIn: 5 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobRepo := &MockUserExportJobRepository{
+				GetFunc: func(ctx context.Context, id string) (*domain.UserExportJob, error) {
+					if tt.job == nil {
+						return nil, domainerrors.ErrExportJobNotFound
+					}
+					return tt.job, nil
+				},
+			}
+			var signedFor time.Duration
+			storage := &MockBlobStorage{
+				SignedURLFunc: func(key string, expiresIn time.Duration) (string, error) {
+					signedFor = expiresIn
+					return "https://storage.example.com/" + key + "?signature=abc", nil
+				},
+			}
+
+			job, download, err := newTestUserExportJobService(jobRepo, storage, &MockAuditLogRepository{}).GetExport(context.Background(), 12345, "job-1")
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetExport() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if job != tt.job {
+				t.Errorf("GetExport() job = %+v, want %+v", job, tt.job)
+			}
+			if (download != nil) != tt.wantURL {
+				t.Fatalf("GetExport() download = %+v, want a download %v", download, tt.wantURL)
+			}
+			if !tt.wantURL {
+				return
+			}
+			if !strings.Contains(download.URL, tt.job.ObjectKey) {
+				t.Errorf("download url = %v, want a signed URL of %v", download.URL, tt.job.ObjectKey)
+			}
+			if signedFor > tt.wantExpiresIn || signedFor < tt.wantExpiresIn-time.Second {
+				t.Errorf("SignedURL() expiresIn = %v, want %v", signedFor, tt.wantExpiresIn)
+			}
+		})
+	}
+}
+
+func TestUserExportJobService_Run(t *testing.T) {
+	var archive []byte
+	var storedKey string
+	storage := &MockBlobStorage{
+		PutFunc: func(ctx context.Context, key, contentType string, body io.Reader, size int64) error {
+			data, _ := io.ReadAll(body)
+			if contentType != "application/zip" || size != int64(len(data)) {
+				t.Errorf("Put() contentType = %v, size = %v (read %v)", contentType, size, len(data))
+			}
+			storedKey, archive = key, data
+			return nil
+		},
+	}
+	var statuses []domain.UserExportJobStatus
+	jobRepo := &MockUserExportJobRepository{
+		UpdateFunc: func(ctx context.Context, job *domain.UserExportJob) error {
+			statuses = append(statuses, job.Status)
+			return nil
+		},
+	}
+	var audited *domain.AuditRecord
+	auditRepo := &MockAuditLogRepository{
+		RecordFunc: func(ctx context.Context, record *domain.AuditRecord) error {
+			audited = record
+			return nil
+		},
+	}
+	job := domain.NewUserExportJob("user-123", 24*time.Hour)
+
+	newTestUserExportJobService(jobRepo, storage, auditRepo).Run(context.Background(), job)
+
+	if strings.Join(statusStrings(statuses), ",") != "running,completed" {
+		t.Errorf("job statuses = %v, want running then completed", statuses)
+	}
+	if storedKey != job.ObjectKey || job.Size != int64(len(archive)) || job.CompletedAt == nil {
+		t.Errorf("job = %+v, want the stored archive of %v bytes", job, len(archive))
+	}
+	if audited == nil || audited.Action != domain.AuditActionUserDataExported || audited.Actor != "user:12345" || audited.TargetID != "user-123" {
+		t.Errorf("audit record = %+v, want a user.data_exported record of user-123", audited)
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		t.Fatalf("archive is not a zip: %v", err)
+	}
+	files := map[string]string{}
+	for _, file := range reader.File {
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", file.Name, err)
+		}
+		data, _ := io.ReadAll(rc)
+		_ = rc.Close()
+		if !json.Valid(data) {
+			t.Errorf("%s is not valid JSON: %s", file.Name, data)
+		}
+		files[file.Name] = string(data)
+	}
+	for _, name := range []string{"profile.json", "emails.json", "consents.json", "notification_preferences.json", "audit_log.json"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("archive is missing %s, has %v", name, files)
+		}
+	}
+	if strings.Contains(files["profile.json"], "$2a$") || strings.Contains(files["emails.json"], "secret") {
+		t.Errorf("archive leaks secrets: %v", files)
+	}
+	if !strings.Contains(files["notification_preferences.json"], `"login_alert": true`) {
+		t.Errorf("notification_preferences.json = %s, want the defaults", files["notification_preferences.json"])
+	}
+	var records []domain.AuditRecord
+	if err := json.Unmarshal([]byte(files["audit_log.json"]), &records); err != nil || len(records) != 2 {
+		t.Errorf("audit_log.json = %s, want the 2 records about the user", files["audit_log.json"])
+	}
+}
+
+func TestUserExportJobService_RunFailure(t *testing.T) {
+	storage := &MockBlobStorage{
+		PutFunc: func(ctx context.Context, key, contentType string, body io.Reader, size int64) error {
+			return errors.New("s3 down")
+		},
+	}
+	var statuses []domain.UserExportJobStatus
+	jobRepo := &MockUserExportJobRepository{
+		UpdateFunc: func(ctx context.Context, job *domain.UserExportJob) error {
+			statuses = append(statuses, job.Status)
+			return nil
+		},
+	}
+	audited := false
+	auditRepo := &MockAuditLogRepository{
+		RecordFunc: func(ctx context.Context, record *domain.AuditRecord) error {
+			audited = true
+			return nil
+		},
+	}
+	job := domain.NewUserExportJob("user-123", 24*time.Hour)
+
+	newTestUserExportJobService(jobRepo, storage, auditRepo).Run(context.Background(), job)
+
+	if strings.Join(statusStrings(statuses), ",") != "running,failed" {
+		t.Errorf("job statuses = %v, want running then failed", statuses)
+	}
+	if audited {
+		t.Error("failed export was audited")
+	}
+}
+
+func TestUserExportJobService_RemoveExpiredArchives(t *testing.T) {
+	old := time.Now().Add(-25 * time.Hour)
+	recent := time.Now().Add(-time.Hour)
+	pages := map[string][]domain.BlobObject{
+		"": {
+			{Key: "exports/user-1/a.zip", LastModified: old},
+			{Key: "exports/user-1/b.zip", LastModified: recent},
+		},
+		"exports/user-1/b.zip": {
+			{Key: "exports/user-2/c.zip", LastModified: old},
+		},
+	}
+	var deleted []string
+	storage := &MockBlobStorage{
+		ListFunc: func(ctx context.Context, prefix, startAfter string, limit int) ([]domain.BlobObject, error) {
+			if prefix != domain.UserExportKeyPrefix || limit != 2 {
+				t.Errorf("List() prefix = %v, limit = %v", prefix, limit)
+			}
+			return pages[startAfter], nil
+		},
+		DeleteFunc: func(ctx context.Context, key string) error {
+			deleted = append(deleted, key)
+			return nil
+		},
+	}
+
+	removed, err := newTestUserExportJobService(&MockUserExportJobRepository{}, storage, &MockAuditLogRepository{}).RemoveExpiredArchives(context.Background())
+
+	if err != nil {
+		t.Fatalf("RemoveExpiredArchives() error = %v", err)
+	}
+	if removed != 2 || strings.Join(deleted, ",") != "exports/user-1/a.zip,exports/user-2/c.zip" {
+		t.Errorf("RemoveExpiredArchives() removed %v: %v, want the 2 archives past the retention", removed, deleted)
+	}
+}
+
+func TestUserExportJobService_StopFailsQueuedExports(t *testing.T) {
+	var statuses []domain.UserExportJobStatus
+	jobRepo := &MockUserExportJobRepository{
+		UpdateFunc: func(ctx context.Context, job *domain.UserExportJob) error {
+			statuses = append(statuses, job.Status)
+			return nil
+		},
+	}
+	policy := newTestUserExportPolicy()
+	policy.Workers = 0
+	service := services.NewUserExportJobService(&MockUserRepository{
+		GetByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+			return newTestUser(), nil
+		},
+	}, &MockUserEmailRepository{}, &MockConsentRepository{}, &MockNotificationPreferencesRepository{}, &MockAuditLogQueryRepository{},
+		&MockAuditLogRepository{}, jobRepo, &MockBlobStorage{}, policy, zap.NewNop())
+
+	if err := service.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if _, err := service.RequestExport(context.Background(), 12345); err != nil {
+		t.Fatalf("RequestExport() error = %v", err)
+	}
+	if err := service.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+
+	if strings.Join(statusStrings(statuses), ",") != "failed" {
+		t.Errorf("queued job statuses = %v, want failed", statuses)
+	}
+}
+
+func TestUserExportJobService_StopFailsQueuedExportsOnTimeout(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	policy := newTestUserExportPolicy()
+	policy.QueueSize = 2
+	service := services.NewUserExportJobService(&MockUserRepository{
+		GetByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+			return newTestUser(), nil
+		},
+		// The export in progress outlives the shutdown
+		GetByIDFunc: func(ctx context.Context, id string) (*domain.User, error) {
+			close(started)
+			<-release
+			return nil, errors.New("stopped")
+		},
+	}, &MockUserEmailRepository{}, &MockConsentRepository{}, &MockNotificationPreferencesRepository{}, &MockAuditLogQueryRepository{},
+		&MockAuditLogRepository{}, &MockUserExportJobRepository{}, &MockBlobStorage{}, policy, zap.NewNop())
+
+	if err := service.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if _, err := service.RequestExport(context.Background(), 12345); err != nil {
+		t.Fatalf("RequestExport() error = %v", err)
+	}
+	<-started
+	queued, err := service.RequestExport(context.Background(), 12345)
+	if err != nil {
+		t.Fatalf("RequestExport() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := service.Stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Stop() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	if queued.Status != domain.UserExportJobFailed {
+		t.Errorf("queued job status = %v, want failed", queued.Status)
+	}
+}
+
+func statusStrings(statuses []domain.UserExportJobStatus) []string {
+	values := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		values = append(values, status.String())
+	}
+	return values
+}
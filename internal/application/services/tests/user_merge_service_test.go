@@ -0,0 +1,327 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	"github.com/kristianrpo/auth-microservice/internal/domain/events"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// userMergeFixture holds the repositories of a merge of "merged" into "survivor", keeping what they were
+// asked to change
+type userMergeFixture struct {
+	users     map[string]*domain.User
+	consents  map[string]*domain.Consent // keyed by user ID and client ID
+	emails    map[string][]*domain.UserEmail
+	phones    map[string]*domain.PhoneNumber
+	deleted   []string
+	revoked   []int
+	audit     []*domain.AuditRecord
+	reassigns int
+	published [][]byte
+}
+
+func newUserMergeFixture() *userMergeFixture {
+	verifiedAt := time.Now().Add(-time.Hour)
+	return &userMergeFixture{
+		users: map[string]*domain.User{
+			"survivor": {ID: "survivor", IDCitizen: 1001, Email: "jane@example.com", Status: domain.UserStatusActive, Type: domain.UserTypeHuman},
+			"merged":   {ID: "merged", IDCitizen: 2002, Email: "jane.legacy@example.com", Status: domain.UserStatusActive, Type: domain.UserTypeHuman, TokenVersion: 4},
+		},
+		consents: map[string]*domain.Consent{
+			"survivor/shared-app": {UserID: "survivor", ClientID: "shared-app", Scopes: []string{"read"}},
+			"merged/shared-app":   {UserID: "merged", ClientID: "shared-app", Scopes: []string{"write"}},
+			"merged/legacy-app":   {UserID: "merged", ClientID: "legacy-app", Scopes: []string{"read"}},
+		},
+		emails: map[string][]*domain.UserEmail{
+			"survivor": {{UserID: "survivor", Email: "jane@work.example.com", VerifiedAt: &verifiedAt}},
+			"merged": {
+				{UserID: "merged", Email: "jane@work.example.com", VerifiedAt: &verifiedAt},
+				{UserID: "merged", Email: "jane@old.example.com", VerifiedAt: &verifiedAt},
+				{UserID: "merged", Email: "unverified@example.com"},
+			},
+		},
+		phones: map[string]*domain.PhoneNumber{
+			"merged": {UserID: "merged", Number: "+573001234567", VerifiedAt: verifiedAt},
+		},
+	}
+}
+
+func (f *userMergeFixture) service() *services.UserMergeService {
+	userRepo := &MockUserRepository{
+		GetByIDFunc: func(ctx context.Context, id string) (*domain.User, error) {
+			if user, ok := f.users[id]; ok {
+				return user, nil
+			}
+			return nil, domainerrors.ErrUserNotFound
+		},
+		DeleteFunc: func(ctx context.Context, id string) error {
+			f.deleted = append(f.deleted, id)
+			return nil
+		},
+	}
+	consentRepo := &MockConsentRepository{
+		GetFunc: func(ctx context.Context, userID, clientID string) (*domain.Consent, error) {
+			if consent, ok := f.consents[userID+"/"+clientID]; ok {
+				return consent, nil
+			}
+			return nil, domainerrors.ErrConsentNotFound
+		},
+		ListByUserFunc: func(ctx context.Context, userID string) ([]*domain.Consent, error) {
+			var consents []*domain.Consent
+			for _, consent := range f.consents {
+				if consent.UserID == userID {
+					consents = append(consents, consent)
+				}
+			}
+			slices.SortFunc(consents, func(a, b *domain.Consent) int { return strings.Compare(a.ClientID, b.ClientID) })
+			return consents, nil
+		},
+		UpsertFunc: func(ctx context.Context, consent *domain.Consent) error {
+			f.consents[consent.UserID+"/"+consent.ClientID] = consent
+			return nil
+		},
+		DeleteFunc: func(ctx context.Context, userID, clientID string) error {
+			delete(f.consents, userID+"/"+clientID)
+			return nil
+		},
+	}
+	emailRepo := &MockUserEmailRepository{
+		ListByUserIDFunc: func(ctx context.Context, userID string) ([]*domain.UserEmail, error) {
+			return f.emails[userID], nil
+		},
+		CreateFunc: func(ctx context.Context, email *domain.UserEmail) error {
+			f.emails[email.UserID] = append(f.emails[email.UserID], email)
+			return nil
+		},
+		DeleteByUserIDFunc: func(ctx context.Context, userID string) error {
+			delete(f.emails, userID)
+			return nil
+		},
+	}
+	phoneRepo := &MockPhoneNumberRepository{
+		GetByUserIDFunc: func(ctx context.Context, userID string) (*domain.PhoneNumber, error) {
+			if phone, ok := f.phones[userID]; ok {
+				return phone, nil
+			}
+			return nil, domainerrors.ErrPhoneNotFound
+		},
+		UpsertFunc: func(ctx context.Context, phone *domain.PhoneNumber) error {
+			f.phones[phone.UserID] = phone
+			return nil
+		},
+		DeleteFunc: func(ctx context.Context, userID string) error {
+			delete(f.phones, userID)
+			return nil
+		},
+	}
+	tokenRepo := &MockTokenRepository{
+		CountActiveSessionsFunc: func(ctx context.Context, idCitizen int) (int, error) {
+			return 2, nil
+		},
+		SetTokenVersionFunc: func(ctx context.Context, idCitizen int, version int, ttl time.Duration) error {
+			f.revoked = append(f.revoked, idCitizen)
+			return nil
+		},
+	}
+	auditRefs := &MockAuditReferenceRepository{
+		CountByTargetFunc: func(ctx context.Context, targetID string) (int, error) {
+			return 5, nil
+		},
+		ReassignTargetFunc: func(ctx context.Context, fromTargetID, toTargetID string) (int, error) {
+			f.reassigns++
+			return 5, nil
+		},
+	}
+	auditRepo := &MockAuditLogRepository{
+		RecordFunc: func(ctx context.Context, record *domain.AuditRecord) error {
+			f.audit = append(f.audit, record)
+			return nil
+		},
+	}
+	publisher := &MockMessagePublisher{
+		PublishFunc: func(ctx context.Context, queueName string, message []byte) error {
+			f.published = append(f.published, message)
+			return nil
+		},
+	}
+	return services.NewUserMergeService(userRepo, consentRepo, emailRepo, phoneRepo, tokenRepo, auditRefs, auditRepo, publisher, "auth.user.merged", 15*time.Minute, zap.NewNop())
+}
+
+func TestUserMergeService_DryRun(t *testing.T) {
+	f := newUserMergeFixture()
+
+	merge, err := f.service().MergeUsers(context.Background(), "survivor", "merged", "admin:1", true)
+	if err != nil {
+		t.Fatalf("MergeUsers() error = %v", err)
+	}
+
+	if !merge.DryRun {
+		t.Error("DryRun = false, want true")
+	}
+	if !slices.Equal(merge.Consents, []string{"legacy-app", "shared-app"}) {
+		t.Errorf("Consents = %v, want legacy-app and shared-app", merge.Consents)
+	}
+	// The address the survivor already has and the unverified one are not moved
+	if !slices.Equal(merge.Emails, []string{"jane.legacy@example.com", "jane@old.example.com"}) {
+		t.Errorf("Emails = %v, want the primary and the verified old address", merge.Emails)
+	}
+	if merge.PhoneNumber != "+573001234567" || merge.AuditRecords != 5 || merge.Sessions != 2 {
+		t.Errorf("merge = %+v, want the phone number, 5 audit records and 2 sessions", merge)
+	}
+
+	// Nothing changed
+	if len(f.deleted) != 0 || f.reassigns != 0 || len(f.audit) != 0 || len(f.published) != 0 || len(f.revoked) != 0 {
+		t.Errorf("dry run changed state: deleted %v, reassigns %d, audit %d, published %d", f.deleted, f.reassigns, len(f.audit), len(f.published))
+	}
+	if _, ok := f.consents["merged/legacy-app"]; !ok {
+		t.Error("dry run moved the consents")
+	}
+}
+
+func TestUserMergeService_Merge(t *testing.T) {
+	f := newUserMergeFixture()
+
+	merge, err := f.service().MergeUsers(context.Background(), "survivor", "merged", "admin:1", false)
+	if err != nil {
+		t.Fatalf("MergeUsers() error = %v", err)
+	}
+	if merge.DryRun {
+		t.Error("DryRun = true, want false")
+	}
+
+	// Consents moved, the shared one keeps the scopes granted by either account
+	for key := range f.consents {
+		if f.consents[key].UserID == "merged" {
+			t.Errorf("consent %s left on the merged user", key)
+		}
+	}
+	if shared := f.consents["survivor/shared-app"]; shared == nil || !shared.Covers([]string{"read", "write"}) {
+		t.Errorf("shared consent = %+v, want read and write", shared)
+	}
+	if _, ok := f.consents["survivor/legacy-app"]; !ok {
+		t.Error("legacy-app consent not moved to the survivor")
+	}
+
+	// Emails moved as verified secondary emails, the merged user has none left
+	if len(f.emails["merged"]) != 0 {
+		t.Errorf("merged user emails = %v, want none", f.emails["merged"])
+	}
+	var added []string
+	for _, email := range f.emails["survivor"][1:] {
+		if !email.IsVerified() {
+			t.Errorf("moved email %s is not verified", email.Email)
+		}
+		added = append(added, email.Email)
+	}
+	if !slices.Equal(added, []string{"jane.legacy@example.com", "jane@old.example.com"}) {
+		t.Errorf("survivor emails added = %v", added)
+	}
+
+	// Phone number moved
+	if _, ok := f.phones["merged"]; ok {
+		t.Error("phone number left on the merged user")
+	}
+	if phone := f.phones["survivor"]; phone == nil || phone.Number != "+573001234567" {
+		t.Errorf("survivor phone = %+v, want the merged number", phone)
+	}
+
+	if f.reassigns != 1 {
+		t.Errorf("audit reassigns = %d, want 1", f.reassigns)
+	}
+	if !slices.Equal(f.revoked, []int{2002}) {
+		t.Errorf("revoked access tokens of %v, want the merged citizen", f.revoked)
+	}
+	if !slices.Equal(f.deleted, []string{"merged"}) {
+		t.Errorf("deleted = %v, want the merged user", f.deleted)
+	}
+
+	if len(f.audit) != 1 || f.audit[0].Action != domain.AuditActionUserMerged || f.audit[0].TargetID != "survivor" || f.audit[0].Details["merged_user_id"] != "merged" {
+		t.Errorf("audit = %+v, want a user.merged record about the survivor", f.audit)
+	}
+
+	if len(f.published) != 1 {
+		t.Fatalf("published %d events, want 1", len(f.published))
+	}
+	var event events.UserMergedEvent
+	if err := json.Unmarshal(f.published[0], &event); err != nil {
+		t.Fatalf("failed to unmarshal event: %v", err)
+	}
+	if event.UserID != "survivor" || event.IDCitizen != 1001 || event.MergedUserID != "merged" || event.MergedIDCitizen != 2002 {
+		t.Errorf("event = %+v, want the merge of 2002 into 1001", event)
+	}
+}
+
+func TestUserMergeService_KeepsSurvivorPhoneNumber(t *testing.T) {
+	f := newUserMergeFixture()
+	f.phones["survivor"] = &domain.PhoneNumber{UserID: "survivor", Number: "+573009999999"}
+
+	merge, err := f.service().MergeUsers(context.Background(), "survivor", "merged", "admin:1", false)
+	if err != nil {
+		t.Fatalf("MergeUsers() error = %v", err)
+	}
+
+	if merge.PhoneNumber != "" {
+		t.Errorf("PhoneNumber = %q, want none moved", merge.PhoneNumber)
+	}
+	if f.phones["survivor"].Number != "+573009999999" {
+		t.Errorf("survivor phone = %q, want its own", f.phones["survivor"].Number)
+	}
+	if _, ok := f.phones["merged"]; ok {
+		t.Error("phone number left on the merged user")
+	}
+}
+
+func TestUserMergeService_InvalidMerge(t *testing.T) {
+	tests := []struct {
+		name       string
+		survivorID string
+		mergedID   string
+		setup      func(*userMergeFixture)
+		wantErr    error
+	}{
+		{name: "same user", survivorID: "survivor", mergedID: "survivor", wantErr: domainerrors.ErrInvalidUserMerge},
+		{name: "unknown merged user", survivorID: "survivor", mergedID: "missing", wantErr: domainerrors.ErrUserNotFound},
+		{
+			name:       "anonymized user",
+			survivorID: "survivor",
+			mergedID:   "merged",
+			setup:      func(f *userMergeFixture) { f.users["merged"].Status = domain.UserStatusAnonymized },
+			wantErr:    domainerrors.ErrInvalidUserMerge,
+		},
+		{
+			name:       "service account",
+			survivorID: "survivor",
+			mergedID:   "merged",
+			setup:      func(f *userMergeFixture) { f.users["survivor"].Type = domain.UserTypeServiceAccount },
+			wantErr:    domainerrors.ErrInvalidUserMerge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newUserMergeFixture()
+			if tt.setup != nil {
+				tt.setup(f)
+			}
+
+			_, err := f.service().MergeUsers(context.Background(), tt.survivorID, tt.mergedID, "admin:1", false)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("MergeUsers() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(f.deleted) != 0 {
+				t.Errorf("deleted = %v, want none", f.deleted)
+			}
+		})
+	}
+}
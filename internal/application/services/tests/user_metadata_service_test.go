@@ -0,0 +1,151 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// newTestUserMetadataPolicy returns a schema with a self-service key and an administrator-only key
+func newTestUserMetadataPolicy() services.UserMetadataPolicy {
+	return services.UserMetadataPolicy{
+		Schema: domain.UserMetadataSchema{
+			"department": domain.MetadataTypeString,
+			"newsletter": domain.MetadataTypeBoolean,
+		},
+		SelfServiceKeys: []string{"newsletter"},
+		MaxSize:         64,
+	}
+}
+
+func TestUserMetadataService_UpdateUserMetadata(t *testing.T) {
+	tests := []struct {
+		name         string
+		current      domain.UserMetadata
+		changes      domain.UserMetadata
+		getErr       error
+		status       domain.UserStatus
+		updateErr    error
+		wantErr      error
+		wantMetadata domain.UserMetadata
+	}{
+		{
+			name:         "sets and removes keys",
+			current:      domain.UserMetadata{"department": "sales", "newsletter": true},
+			changes:      domain.UserMetadata{"department": "support", "newsletter": nil},
+			wantMetadata: domain.UserMetadata{"department": "support"},
+		},
+		{name: "user not found", changes: domain.UserMetadata{"department": "sales"}, getErr: domainerrors.ErrUserNotFound, wantErr: domainerrors.ErrUserNotFound},
+		{name: "anonymized user", changes: domain.UserMetadata{"department": "sales"}, status: domain.UserStatusAnonymized, wantErr: domainerrors.ErrUserAlreadyAnonymized},
+		{name: "undeclared key", changes: domain.UserMetadata{"nickname": "bob"}, wantErr: domainerrors.ErrInvalidUserMetadata},
+		{name: "value of the wrong type", changes: domain.UserMetadata{"newsletter": "yes"}, wantErr: domainerrors.ErrInvalidUserMetadata},
+		{name: "metadata too large", changes: domain.UserMetadata{"department": "a department name long enough to exceed the limit"}, wantErr: domainerrors.ErrUserMetadataTooLarge},
+		{name: "update fails", changes: domain.UserMetadata{"department": "sales"}, updateErr: errors.New("db down"), wantErr: domainerrors.ErrInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updated *domain.User
+			userRepo := &MockUserRepository{
+				GetByIDFunc: func(ctx context.Context, id string) (*domain.User, error) {
+					if tt.getErr != nil {
+						return nil, tt.getErr
+					}
+					user := newTestUser()
+					user.Metadata = tt.current
+					user.Version = 1
+					if tt.status != "" {
+						user.Status = tt.status
+					}
+					return user, nil
+				},
+				UpdateFunc: func(ctx context.Context, user *domain.User) error {
+					updated = user
+					return tt.updateErr
+				},
+			}
+			var audit *domain.AuditRecord
+			auditRepo := &MockAuditLogRepository{
+				RecordFunc: func(ctx context.Context, record *domain.AuditRecord) error {
+					audit = record
+					return nil
+				},
+			}
+
+			service := services.NewUserMetadataService(userRepo, auditRepo, newTestUserMetadataPolicy(), zap.NewNop())
+			user, err := service.UpdateUserMetadata(context.Background(), "user-123", 1, tt.changes, "admin:1")
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateUserMetadata() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if audit != nil {
+					t.Errorf("audit record = %+v after failure, want none", audit)
+				}
+				return
+			}
+
+			if !reflect.DeepEqual(updated.Metadata, tt.wantMetadata) || !reflect.DeepEqual(user.Metadata, tt.wantMetadata) {
+				t.Errorf("metadata = %v (stored %v), want %v", user.Metadata, updated.Metadata, tt.wantMetadata)
+			}
+			if audit == nil || audit.Action != domain.AuditActionUserMetadataUpdated || audit.Actor != "admin:1" || audit.Details["keys"] != "department,newsletter" {
+				t.Errorf("audit record = %+v", audit)
+			}
+		})
+	}
+}
+
+func TestUserMetadataService_UpdateOwnMetadata(t *testing.T) {
+	tests := []struct {
+		name    string
+		changes domain.UserMetadata
+		wantErr error
+	}{
+		{name: "self-service key", changes: domain.UserMetadata{"newsletter": false}},
+		{name: "administrator-only key", changes: domain.UserMetadata{"newsletter": false, "department": "sales"}, wantErr: domainerrors.ErrMetadataKeyNotWritable},
+		{name: "undeclared key", changes: domain.UserMetadata{"nickname": "bob"}, wantErr: domainerrors.ErrMetadataKeyNotWritable},
+		{name: "value of the wrong type", changes: domain.UserMetadata{"newsletter": "no"}, wantErr: domainerrors.ErrInvalidUserMetadata},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updated *domain.User
+			userRepo := &MockUserRepository{
+				GetByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+					user := newTestUser()
+					user.Metadata = domain.UserMetadata{"department": "sales", "newsletter": true}
+					return user, nil
+				},
+				UpdateFunc: func(ctx context.Context, user *domain.User) error {
+					updated = user
+					return nil
+				},
+			}
+
+			service := services.NewUserMetadataService(userRepo, &MockAuditLogRepository{}, newTestUserMetadataPolicy(), zap.NewNop())
+			user, err := service.UpdateOwnMetadata(context.Background(), 12345, tt.changes)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateOwnMetadata() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if updated != nil {
+					t.Errorf("user updated after failure: %+v", updated)
+				}
+				return
+			}
+
+			want := domain.UserMetadata{"department": "sales", "newsletter": false}
+			if !reflect.DeepEqual(user.Metadata, want) {
+				t.Errorf("metadata = %v, want %v", user.Metadata, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// newProvisioningUserRepository returns a user repository keeping a single user in memory
+func newProvisioningUserRepository(stored **domain.User) *MockUserRepository {
+	return &MockUserRepository{
+		CreateFunc: func(ctx context.Context, user *domain.User) error {
+			user.ID = "user-123"
+			*stored = user
+			return nil
+		},
+		GetByEmailFunc: func(ctx context.Context, email string) (*domain.User, error) {
+			if *stored == nil || (*stored).Email != email {
+				return nil, domainerrors.ErrUserNotFound
+			}
+			return *stored, nil
+		},
+		UpdateFunc: func(ctx context.Context, user *domain.User) error {
+			*stored = user
+			return nil
+		},
+	}
+}
+
+func TestUserProvisioningService_CreateUserAndChangePassword(t *testing.T) {
+	var stored *domain.User
+	userRepo := newProvisioningUserRepository(&stored)
+	var audited *domain.AuditRecord
+	auditRepo := &MockAuditLogRepository{
+		RecordFunc: func(ctx context.Context, record *domain.AuditRecord) error {
+			audited = record
+			return nil
+		},
+	}
+	var publishedTo string
+	publisher := &MockMessagePublisher{
+		PublishFunc: func(ctx context.Context, queueName string, message []byte) error {
+			publishedTo = queueName
+			return nil
+		},
+	}
+	service := services.NewUserProvisioningService(userRepo, auditRepo, &MockPasswordHasher{}, &MockExternalConnectivityClient{}, publisher, "test.user.registered", domain.DefaultNameLimits(), zap.NewNop())
+	ctx := context.Background()
+
+	user, password, err := service.CreateUser(ctx, "citizen@example.com", "Citizen", 12345, "admin:999")
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	if len(password) < domain.MinPasswordLength || user.Password == password {
+		t.Errorf("temporary password = %q, want a generated password stored hashed", password)
+	}
+	if !user.MustChangePassword || !user.IsActive() {
+		t.Errorf("CreateUser() = %+v, want an active user who must change their password", user)
+	}
+	if audited == nil || audited.Action != domain.AuditActionUserCreated || audited.Actor != "admin:999" || audited.TargetID != "user-123" {
+		t.Errorf("audit record = %+v, want %q by admin:999 on user-123", audited, domain.AuditActionUserCreated)
+	}
+	if publishedTo != "test.user.registered" {
+		t.Errorf("event published to %q, want test.user.registered", publishedTo)
+	}
+
+	// The temporary password is rejected by login until it is changed
+	jwtService := services.NewJWTService("test-secret-key-at-least-32-chars-long", 15*time.Minute, 7*24*time.Hour, false, nil, services.TokenSigningPolicy{}, zap.NewNop())
+	authService := services.NewAuthService(userRepo, &MockTokenRepository{}, jwtService, &MockPasswordHasher{}, zap.NewNop())
+	if _, err := authService.Login(ctx, "citizen@example.com", password); !errors.Is(err, domainerrors.ErrPasswordChangeRequired) {
+		t.Fatalf("Login() with the temporary password error = %v, want %v", err, domainerrors.ErrPasswordChangeRequired)
+	}
+
+	if err := service.ChangeTemporaryPassword(ctx, "citizen@example.com", "wrong-password", "new-password-123"); !errors.Is(err, domainerrors.ErrInvalidCredentials) {
+		t.Errorf("ChangeTemporaryPassword() with a wrong password error = %v, want %v", err, domainerrors.ErrInvalidCredentials)
+	}
+	if err := service.ChangeTemporaryPassword(ctx, "citizen@example.com", password, password); !errors.Is(err, domainerrors.ErrWeakPassword) {
+		t.Errorf("ChangeTemporaryPassword() reusing the temporary password error = %v, want %v", err, domainerrors.ErrWeakPassword)
+	}
+	if err := service.ChangeTemporaryPassword(ctx, "citizen@example.com", password, "new-password-123"); err != nil {
+		t.Fatalf("ChangeTemporaryPassword() error = %v", err)
+	}
+	if stored.MustChangePassword {
+		t.Error("MustChangePassword = true after the change, want false")
+	}
+
+	if _, err := authService.Login(ctx, "citizen@example.com", "new-password-123"); err != nil {
+		t.Errorf("Login() with the new password error = %v", err)
+	}
+	if err := service.ChangeTemporaryPassword(ctx, "citizen@example.com", "new-password-123", "another-password-123"); !errors.Is(err, domainerrors.ErrPasswordChangeNotRequired) {
+		t.Errorf("ChangeTemporaryPassword() after the change error = %v, want %v", err, domainerrors.ErrPasswordChangeNotRequired)
+	}
+}
+
+func TestUserProvisioningService_CreateUserErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		email         string
+		citizenExists bool
+		userExists    bool
+		createErr     error
+		wantErr       error
+	}{
+		{name: "invalid email", email: "not-an-email", wantErr: domainerrors.ErrInvalidEmail},
+		{name: "citizen in centralizer", email: "citizen@example.com", citizenExists: true, wantErr: domainerrors.ErrCitizenExistsInCentralizer},
+		{name: "email taken", email: "citizen@example.com", userExists: true, wantErr: domainerrors.ErrUserAlreadyExists},
+		{name: "citizen ID taken", email: "citizen@example.com", createErr: domainerrors.ErrUserAlreadyExists, wantErr: domainerrors.ErrUserAlreadyExists},
+		{name: "repository error", email: "citizen@example.com", createErr: errors.New("db down"), wantErr: domainerrors.ErrInternal},
+		{name: "database busy", email: "citizen@example.com", createErr: fmt.Errorf("failed to create user: %w", domainerrors.ErrDatabaseBusy), wantErr: domainerrors.ErrDatabaseBusy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := &MockUserRepository{
+				ExistsFunc: func(ctx context.Context, email string) (bool, error) {
+					return tt.userExists, nil
+				},
+				CreateFunc: func(ctx context.Context, user *domain.User) error {
+					return tt.createErr
+				},
+			}
+			centralizer := &MockExternalConnectivityClient{
+				CheckCitizenExistsFunc: func(ctx context.Context, idCitizen int) (bool, error) {
+					return tt.citizenExists, nil
+				},
+			}
+			auditRepo := &MockAuditLogRepository{
+				RecordFunc: func(ctx context.Context, record *domain.AuditRecord) error {
+					t.Errorf("audit record written on error: %+v", record)
+					return nil
+				},
+			}
+			service := services.NewUserProvisioningService(userRepo, auditRepo, &MockPasswordHasher{}, centralizer, &MockMessagePublisher{}, "test.user.registered", domain.DefaultNameLimits(), zap.NewNop())
+
+			if _, _, err := service.CreateUser(context.Background(), tt.email, "Citizen", 12345, "admin:999"); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CreateUser() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestUserSyncConsumer_HandleUserUpdated(t *testing.T) {
+	tests := []struct {
+		name      string
+		message   string
+		user      *domain.User
+		getErr    error
+		updateErr error
+		wantErr   bool
+		wantEmail string
+		wantName  string
+		wantAudit string
+	}{
+		{
+			name:      "profile synchronized",
+			message:   `{"messageId": "m-1", "idCitizen": 12345, "email": "new@example.com", "name": "New Name"}`,
+			wantEmail: "new@example.com",
+			wantName:  "New Name",
+			wantAudit: "email,name",
+		},
+		{
+			name:      "only the name changes",
+			message:   `{"messageId": "m-1", "idCitizen": 12345, "email": "TEST@example.com", "name": "New Name"}`,
+			wantName:  "New Name",
+			wantAudit: "name",
+		},
+		{
+			name:      "name is normalized",
+			message:   `{"messageId": "m-1", "idCitizen": 12345, "name": "  New\u0000  Name "}`,
+			wantName:  "New Name",
+			wantAudit: "name",
+		},
+		{
+			name:      "invalid name is ignored",
+			message:   `{"messageId": "m-1", "idCitizen": 12345, "email": "new@example.com", "name": "\u0007"}`,
+			wantEmail: "new@example.com",
+			wantName:  "Test User",
+			wantAudit: "email",
+		},
+		{name: "no changes", message: `{"messageId": "m-1", "idCitizen": 12345, "email": "test@example.com"}`},
+		{name: "malformed message is acknowledged", message: `{"idCitizen": 12345, "email": "not-an-email"}`},
+		{name: "user not found", message: `{"messageId": "m-1", "idCitizen": 12345, "name": "New Name"}`, getErr: domainerrors.ErrUserNotFound},
+		{
+			name:    "anonymized user is not resurrected",
+			message: `{"messageId": "m-1", "idCitizen": 12345, "name": "New Name"}`,
+			user:    &domain.User{ID: "user-123", IDCitizen: 12345, Status: domain.UserStatusAnonymized},
+		},
+		{
+			name:      "email used by another user is a conflict",
+			message:   `{"messageId": "m-1", "idCitizen": 12345, "email": "taken@example.com"}`,
+			updateErr: domainerrors.ErrUserAlreadyExists,
+		},
+		{
+			name:      "transient update error is retried",
+			message:   `{"messageId": "m-1", "idCitizen": 12345, "name": "New Name"}`,
+			updateErr: errors.New("connection reset"),
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updated *domain.User
+			userRepo := &MockUserRepository{
+				GetByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+					if tt.getErr != nil {
+						return nil, tt.getErr
+					}
+					if tt.user != nil {
+						return tt.user, nil
+					}
+					user := newTestUser()
+					user.Name = "Test User"
+					return user, nil
+				},
+				UpdateFunc: func(ctx context.Context, user *domain.User) error {
+					if tt.updateErr != nil {
+						return tt.updateErr
+					}
+					updated = user
+					return nil
+				},
+			}
+			var audit *domain.AuditRecord
+			auditRepo := &MockAuditLogRepository{
+				RecordFunc: func(ctx context.Context, record *domain.AuditRecord) error {
+					audit = record
+					return nil
+				},
+			}
+			processedRepo, _ := newProcessedMessages()
+
+			consumer := services.NewUserSyncConsumer(userRepo, newTestRoleService(userRepo, &MockTokenRepository{}, auditRepo), auditRepo, processedRepo, "auth_user_updated", "auth_user_role_changed", domain.DefaultNameLimits(), zap.NewNop())
+			err := consumer.HandleUserUpdated(context.Background(), []byte(tt.message))
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("HandleUserUpdated() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantAudit == "" {
+				if updated != nil || audit != nil {
+					t.Errorf("user updated = %+v, audit = %+v, want no changes", updated, audit)
+				}
+				return
+			}
+			if updated == nil || audit == nil {
+				t.Fatalf("user updated = %+v, audit = %+v, want both", updated, audit)
+			}
+			if tt.wantEmail != "" && updated.Email != tt.wantEmail {
+				t.Errorf("Email = %v, want %v", updated.Email, tt.wantEmail)
+			}
+			if updated.Name != tt.wantName {
+				t.Errorf("Name = %v, want %v", updated.Name, tt.wantName)
+			}
+			if audit.Action != domain.AuditActionUserUpdated || audit.Details["fields"] != tt.wantAudit || audit.Actor != "event:auth_user_updated" {
+				t.Errorf("audit = %+v, want user.updated of %s by event:auth_user_updated", audit, tt.wantAudit)
+			}
+		})
+	}
+}
+
+func TestUserSyncConsumer_HandleUserRoleChanged(t *testing.T) {
+	tests := []struct {
+		name        string
+		message     string
+		wantRole    domain.Role
+		wantRevoked bool
+	}{
+		{name: "promoted to admin", message: `{"messageId": "m-1", "idCitizen": 12345, "role": "ADMIN"}`, wantRole: domain.RoleAdmin, wantRevoked: true},
+		{name: "same role", message: `{"messageId": "m-1", "idCitizen": 12345, "role": "USER"}`},
+		{name: "unknown role is acknowledged", message: `{"messageId": "m-1", "idCitizen": 12345, "role": "ROOT"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updated *domain.User
+			revoked := false
+			var audit *domain.AuditRecord
+			userRepo := &MockUserRepository{
+				GetByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+					user := newTestUser()
+					user.Role = domain.RoleUser
+					return user, nil
+				},
+				UpdateFunc: func(ctx context.Context, user *domain.User) error {
+					updated = user
+					return nil
+				},
+			}
+			tokenRepo := &MockTokenRepository{
+				DeleteUserTokensFunc: func(ctx context.Context, userID string, idCitizen int) error {
+					revoked = true
+					return nil
+				},
+			}
+			auditRepo := &MockAuditLogRepository{
+				RecordFunc: func(ctx context.Context, record *domain.AuditRecord) error {
+					audit = record
+					return nil
+				},
+			}
+			processedRepo, _ := newProcessedMessages()
+
+			consumer := services.NewUserSyncConsumer(userRepo, newTestRoleService(userRepo, tokenRepo, auditRepo), auditRepo, processedRepo, "auth_user_updated", "auth_user_role_changed", domain.DefaultNameLimits(), zap.NewNop())
+			if err := consumer.HandleUserRoleChanged(context.Background(), []byte(tt.message)); err != nil {
+				t.Fatalf("HandleUserRoleChanged() error = %v", err)
+			}
+
+			if revoked != tt.wantRevoked {
+				t.Errorf("tokens revoked = %v, want %v", revoked, tt.wantRevoked)
+			}
+			if tt.wantRole == "" {
+				if updated != nil || audit != nil {
+					t.Errorf("user updated = %+v, audit = %+v, want no changes", updated, audit)
+				}
+				return
+			}
+			if updated == nil || updated.Role != tt.wantRole || updated.TokenVersion != 1 {
+				t.Fatalf("updated = %+v, want role %v and token version 1", updated, tt.wantRole)
+			}
+			if audit == nil || audit.Action != domain.AuditActionUserRoleChanged || audit.Details["previous_role"] != "USER" || audit.Details["role"] != tt.wantRole.String() {
+				t.Errorf("audit = %+v, want the role change recorded", audit)
+			}
+		})
+	}
+}
+
+func TestUserSyncConsumer_HandleUserRoleChanged_Redelivery(t *testing.T) {
+	updates := 0
+	userRepo := &MockUserRepository{
+		GetByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+			user := newTestUser()
+			user.Role = domain.RoleUser // the role was demoted again after the event was applied
+			return user, nil
+		},
+		UpdateFunc: func(ctx context.Context, user *domain.User) error {
+			updates++
+			return nil
+		},
+	}
+	processedRepo, _ := newProcessedMessages()
+	consumer := services.NewUserSyncConsumer(userRepo, newTestRoleService(userRepo, &MockTokenRepository{}, &MockAuditLogRepository{}), &MockAuditLogRepository{}, processedRepo, "auth_user_updated", "auth_user_role_changed", domain.DefaultNameLimits(), zap.NewNop())
+
+	// A redelivery of the event must not promote the user again
+	message := []byte(`{"messageId": "m-1", "idCitizen": 12345, "role": "ADMIN"}`)
+	for i := 0; i < 2; i++ {
+		if err := consumer.HandleUserRoleChanged(context.Background(), message); err != nil {
+			t.Fatalf("HandleUserRoleChanged() error = %v", err)
+		}
+	}
+
+	if updates != 1 {
+		t.Errorf("user updated %d times, want 1", updates)
+	}
+}
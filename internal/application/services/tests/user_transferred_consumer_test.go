@@ -0,0 +1,183 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// newProcessedMessages returns a processed message repository backed by a map
+func newProcessedMessages() (*MockProcessedMessageRepository, map[string]bool) {
+	processed := map[string]bool{}
+	return &MockProcessedMessageRepository{
+		MarkProcessedFunc: func(ctx context.Context, queue, messageID string) (bool, error) {
+			key := queue + ":" + messageID
+			if processed[key] {
+				return false, nil
+			}
+			processed[key] = true
+			return true, nil
+		},
+		UnmarkFunc: func(ctx context.Context, queue, messageID string) error {
+			delete(processed, queue+":"+messageID)
+			return nil
+		},
+	}, processed
+}
+
+func TestUserTransferredConsumer_Handle(t *testing.T) {
+	tests := []struct {
+		name        string
+		message     string
+		getErr      error
+		tokensErr   error
+		versionErr  error
+		deleteErr   error
+		wantErr     bool
+		wantDeleted bool
+	}{
+		{name: "user deleted", message: `{"messageId": "m-1", "idCitizen": 12345}`, wantDeleted: true},
+		{name: "malformed message is acknowledged", message: `{"idCitizen": "nope"`},
+		{name: "user already gone", message: `{"messageId": "m-1", "idCitizen": 12345}`, getErr: domainerrors.ErrUserNotFound},
+		{name: "transient lookup error is retried", message: `{"messageId": "m-1", "idCitizen": 12345}`, getErr: errors.New("connection reset"), wantErr: true},
+		{name: "transient token error is retried", message: `{"messageId": "m-1", "idCitizen": 12345}`, tokensErr: errors.New("redis down"), wantErr: true},
+		{name: "transient token version error is retried", message: `{"messageId": "m-1", "idCitizen": 12345}`, versionErr: errors.New("redis down"), wantErr: true},
+		{name: "transient delete error is retried", message: `{"messageId": "m-1", "idCitizen": 12345}`, deleteErr: errors.New("connection reset"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deleted := false
+			userRepo := &MockUserRepository{
+				GetByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+					if tt.getErr != nil {
+						return nil, tt.getErr
+					}
+					return newTestUser(), nil
+				},
+				DeleteFunc: func(ctx context.Context, id string) error {
+					if tt.deleteErr != nil {
+						return tt.deleteErr
+					}
+					deleted = true
+					return nil
+				},
+			}
+			var tokensUserID string
+			var tokensIDCitizen, minVersion int
+			var minVersionTTL time.Duration
+			tokenRepo := &MockTokenRepository{
+				DeleteUserTokensFunc: func(ctx context.Context, userID string, idCitizen int) error {
+					tokensUserID, tokensIDCitizen = userID, idCitizen
+					return tt.tokensErr
+				},
+				SetTokenVersionFunc: func(ctx context.Context, idCitizen int, version int, ttl time.Duration) error {
+					minVersion, minVersionTTL = version, ttl
+					return tt.versionErr
+				},
+			}
+			processedRepo, processed := newProcessedMessages()
+
+			consumer := services.NewUserTransferredConsumer(userRepo, tokenRepo, processedRepo, "auth_user_transferred", 15*time.Minute, zap.NewNop())
+			err := consumer.Handle(context.Background(), []byte(tt.message))
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Handle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if deleted != tt.wantDeleted {
+				t.Errorf("user deleted = %v, want %v", deleted, tt.wantDeleted)
+			}
+			// The refresh tokens are matched by both identifiers and the access tokens revoked by version
+			if tt.wantDeleted && (tokensUserID != "user-123" || tokensIDCitizen != 12345 || minVersion != 1 || minVersionTTL != 15*time.Minute) {
+				t.Errorf("tokens deleted for %q/%d, minimum token version %d for %v, want user-123/12345 and version 1 for 15m",
+					tokensUserID, tokensIDCitizen, minVersion, minVersionTTL)
+			}
+			// A failed message must not be remembered, otherwise its redelivery would be skipped
+			if tt.wantErr && len(processed) != 0 {
+				t.Errorf("processed = %v, want the failed message forgotten", processed)
+			}
+		})
+	}
+}
+
+func TestUserTransferredConsumer_Handle_Redelivery(t *testing.T) {
+	deletes := 0
+	userRepo := &MockUserRepository{
+		GetByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+			// The citizen registered again after the transfer
+			return newTestUser(), nil
+		},
+		DeleteFunc: func(ctx context.Context, id string) error {
+			deletes++
+			return nil
+		},
+	}
+	processedRepo, _ := newProcessedMessages()
+	consumer := services.NewUserTransferredConsumer(userRepo, &MockTokenRepository{}, processedRepo, "auth_user_transferred", 15*time.Minute, zap.NewNop())
+
+	messages := []string{
+		`{"messageId": "m-1", "idCitizen": 12345}`,
+		`{"messageId": "m-1", "idCitizen": 12345}`, // redelivery
+		`{"idCitizen": 12345}`,
+		`{"idCitizen": 12345}`, // redelivery of a message without ID
+	}
+	for _, message := range messages {
+		if err := consumer.Handle(context.Background(), []byte(message)); err != nil {
+			t.Fatalf("Handle() error = %v", err)
+		}
+	}
+
+	if deletes != 2 {
+		t.Errorf("user deleted %d times, want 2 (redeliveries skipped)", deletes)
+	}
+}
+
+func TestUserTransferredConsumer_Handle_RetriedAfterTransientError(t *testing.T) {
+	failures := 1
+	deletes := 0
+	userRepo := &MockUserRepository{
+		GetByIDCitizenFunc: func(ctx context.Context, idCitizen int) (*domain.User, error) {
+			if failures > 0 {
+				failures--
+				return nil, errors.New("connection reset")
+			}
+			return newTestUser(), nil
+		},
+		DeleteFunc: func(ctx context.Context, id string) error {
+			deletes++
+			return nil
+		},
+	}
+	processedRepo, _ := newProcessedMessages()
+	consumer := services.NewUserTransferredConsumer(userRepo, &MockTokenRepository{}, processedRepo, "auth_user_transferred", 15*time.Minute, zap.NewNop())
+
+	message := []byte(`{"messageId": "m-1", "idCitizen": 12345}`)
+	if err := consumer.Handle(context.Background(), message); err == nil {
+		t.Fatal("Handle() error = nil, want the transient error so the message is requeued")
+	}
+	if err := consumer.Handle(context.Background(), message); err != nil {
+		t.Fatalf("Handle() on redelivery error = %v", err)
+	}
+	if deletes != 1 {
+		t.Errorf("user deleted %d times, want 1", deletes)
+	}
+}
+
+func TestUserTransferredConsumer_Handle_ProcessedStoreDown(t *testing.T) {
+	consumer := services.NewUserTransferredConsumer(&MockUserRepository{}, &MockTokenRepository{}, &MockProcessedMessageRepository{
+		MarkProcessedFunc: func(ctx context.Context, queue, messageID string) (bool, error) {
+			return false, errors.New("redis down")
+		},
+	}, "auth_user_transferred", 15*time.Minute, zap.NewNop())
+
+	if err := consumer.Handle(context.Background(), []byte(`{"messageId": "m-1", "idCitizen": 12345}`)); err == nil {
+		t.Error("Handle() error = nil, want an error so the message is requeued")
+	}
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// TokenDebugServiceInterface defines the methods of TokenDebugService used by handlers.
+type TokenDebugServiceInterface interface {
+	DecodeToken(ctx context.Context, token string) (*domain.TokenDebugReport, error)
+}
+
+// TokenDebugService explains why a token is accepted or rejected, for support investigations. It runs the
+// validation steps of the access tokens one by one instead of stopping at the first failure.
+type TokenDebugService struct {
+	jwtService *JWTService
+	tokenRepo  ports.TokenRepository
+	logger     *zap.Logger
+}
+
+// NewTokenDebugService creates a new instance of TokenDebugService
+func NewTokenDebugService(jwtService *JWTService, tokenRepo ports.TokenRepository, logger *zap.Logger) *TokenDebugService {
+	return &TokenDebugService{
+		jwtService: jwtService,
+		tokenRepo:  tokenRepo,
+		logger:     logger,
+	}
+}
+
+// DecodeToken decodes a JWT without validating it and reports what each validation step of the access
+// tokens decides about it. It returns ErrInvalidToken only when the token is not a JWT at all.
+func (s *TokenDebugService) DecodeToken(ctx context.Context, token string) (*domain.TokenDebugReport, error) {
+	header, claims, err := s.jwtService.Decode(token)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.TokenDebugReport{Header: header, Claims: claims}
+	report.Checks = append(report.Checks,
+		s.checkSignature(token),
+		checkExpiration(claims, time.Now()),
+		checkType(header, claims),
+		s.checkBlacklist(ctx, token),
+		s.checkVersion(ctx, header, claims),
+	)
+	return report, nil
+}
+
+func (s *TokenDebugService) checkSignature(token string) domain.TokenCheck {
+	if err := s.jwtService.VerifySignature(token); err != nil {
+		return domain.TokenCheck{Name: domain.TokenCheckSignature, Status: domain.TokenCheckFailed, Detail: err.Error()}
+	}
+	return domain.TokenCheck{Name: domain.TokenCheckSignature, Status: domain.TokenCheckPassed, Detail: "signed with an accepted algorithm and key"}
+}
+
+// checkExpiration checks the exp and nbf claims
+func checkExpiration(claims map[string]interface{}, now time.Time) domain.TokenCheck {
+	check := domain.TokenCheck{Name: domain.TokenCheckExpiration}
+
+	if nbf, ok := numericClaim(claims, "nbf"); ok && now.Before(time.Unix(nbf, 0)) {
+		check.Status = domain.TokenCheckFailed
+		check.Detail = fmt.Sprintf("not valid before %s", time.Unix(nbf, 0).UTC().Format(time.RFC3339))
+		return check
+	}
+
+	exp, ok := numericClaim(claims, "exp")
+	switch {
+	case !ok:
+		check.Status = domain.TokenCheckPassed
+		check.Detail = "the token does not expire"
+	case !now.Before(time.Unix(exp, 0)):
+		check.Status = domain.TokenCheckFailed
+		check.Detail = fmt.Sprintf("expired at %s, %s ago", time.Unix(exp, 0).UTC().Format(time.RFC3339), now.Sub(time.Unix(exp, 0)).Round(time.Second))
+	default:
+		check.Status = domain.TokenCheckPassed
+		check.Detail = fmt.Sprintf("expires at %s, in %s", time.Unix(exp, 0).UTC().Format(time.RFC3339), time.Unix(exp, 0).Sub(now).Round(time.Second))
+	}
+	return check
+}
+
+// checkType checks that the token is a user access token, the tokens accepted by the API
+func checkType(header, claims map[string]interface{}) domain.TokenCheck {
+	tokenType := accessTokenType(header, claims)
+	if tokenType != domain.TokenTypeAccess {
+		if tokenType == "" {
+			tokenType = "none"
+		}
+		return domain.TokenCheck{
+			Name:   domain.TokenCheckType,
+			Status: domain.TokenCheckFailed,
+			Detail: fmt.Sprintf("type is %s, only %s tokens are accepted", tokenType, domain.TokenTypeAccess),
+		}
+	}
+	return domain.TokenCheck{Name: domain.TokenCheckType, Status: domain.TokenCheckPassed, Detail: "user access token"}
+}
+
+func (s *TokenDebugService) checkBlacklist(ctx context.Context, token string) domain.TokenCheck {
+	blacklisted, err := s.tokenRepo.IsTokenBlacklisted(ctx, token)
+	switch {
+	case err != nil:
+		s.logger.Error("failed to check token blacklist", zap.Error(err))
+		return domain.TokenCheck{Name: domain.TokenCheckBlacklist, Status: domain.TokenCheckError, Detail: "the blacklist is unavailable"}
+	case blacklisted:
+		return domain.TokenCheck{Name: domain.TokenCheckBlacklist, Status: domain.TokenCheckFailed, Detail: "the token was revoked, e.g. on logout"}
+	default:
+		return domain.TokenCheck{Name: domain.TokenCheckBlacklist, Status: domain.TokenCheckPassed, Detail: "the token is not blacklisted"}
+	}
+}
+
+// checkVersion checks that the token was issued after the last revocation of the tokens of its user
+func (s *TokenDebugService) checkVersion(ctx context.Context, header, claims map[string]interface{}) domain.TokenCheck {
+	check := domain.TokenCheck{Name: domain.TokenCheckVersion}
+
+	idCitizen, ok := tokenCitizen(header, claims)
+	if !ok {
+		check.Status = domain.TokenCheckSkipped
+		check.Detail = "the token does not identify a user"
+		return check
+	}
+	tokenVersion, _ := numericClaim(claims, "tv")
+
+	version, err := s.tokenRepo.GetTokenVersion(ctx, idCitizen)
+	switch {
+	case err != nil:
+		s.logger.Error("failed to check token version", zap.Error(err))
+		check.Status = domain.TokenCheckError
+		check.Detail = "the token versions are unavailable"
+	case int(tokenVersion) < version:
+		check.Status = domain.TokenCheckFailed
+		check.Detail = fmt.Sprintf("token version %d is older than %d, the tokens of the user were revoked after it was issued", tokenVersion, version)
+	default:
+		check.Status = domain.TokenCheckPassed
+		check.Detail = fmt.Sprintf("token version %d is current", tokenVersion)
+	}
+	return check
+}
+
+// accessTokenType returns the type of a token like ValidateToken: minimal access tokens carry no type
+// claim and are recognized by their typ header
+func accessTokenType(header, claims map[string]interface{}) string {
+	if tokenType, _ := claims["type"].(string); tokenType != "" {
+		return tokenType
+	}
+	if header["typ"] == minimalAccessTokenType {
+		return domain.TokenTypeAccess
+	}
+	return ""
+}
+
+// tokenCitizen returns the citizen ID of the user of a token, from the subject of the minimal access tokens
+func tokenCitizen(header, claims map[string]interface{}) (int, bool) {
+	if idCitizen, ok := numericClaim(claims, "id_citizen"); ok && idCitizen > 0 {
+		return int(idCitizen), true
+	}
+	if header["typ"] == minimalAccessTokenType {
+		sub, _ := claims["sub"].(string)
+		if idCitizen, err := strconv.Atoi(sub); err == nil && idCitizen > 0 {
+			return idCitizen, true
+		}
+	}
+	return 0, false
+}
+
+// numericClaim returns a numeric claim, decoded from JSON as a float64
+func numericClaim(claims map[string]interface{}, name string) (int64, bool) {
+	value, ok := claims[name].(float64)
+	return int64(value), ok
+}
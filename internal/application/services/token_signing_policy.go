@@ -0,0 +1,209 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
+)
+
+// defaultSigningAlgorithm is the algorithm of the issued tokens when the policy sets none
+const defaultSigningAlgorithm = "HS256"
+
+// TokenSigningPolicy defines how the JWTs are signed and which ones are accepted. Tokens signed with
+// "none" or with any algorithm not accepted are rejected before their signature is even checked.
+type TokenSigningPolicy struct {
+	// Algorithm signs the issued tokens, HS256 when empty. Tokens are signed with a shared secret, so
+	// only the HMAC algorithms are supported.
+	Algorithm string
+
+	// AcceptedAlgorithms are the algorithms of the tokens accepted, only Algorithm when empty. Listing
+	// the previous algorithm allows changing it without invalidating the tokens already issued.
+	AcceptedAlgorithms []string
+
+	// KeyID is set in the "kid" header of the issued tokens and enables key rotation: tokens without
+	// it or with another key ID are rejected
+	KeyID string
+
+	// MaxTokenSize is the size in bytes the issued access tokens should stay under, e.g. to fit the
+	// header size limits of proxies. Larger tokens are still issued but reported. 0 disables the check.
+	MaxTokenSize int
+
+	// RequiredClaims are the claims the accepted user tokens must carry (exp, iat, nbf, iss, sub, jti, uid,
+	// tv, or kid for the key ID header). The other claims are optional.
+	RequiredClaims []string
+
+	// CompatibilityMode accepts the tokens issued by the previous version during a rollout: RequiredClaims
+	// are not enforced and tokens without a key ID are accepted, while a different key ID is still rejected
+	CompatibilityMode bool
+
+	// Region identifies the region issuing the tokens in multi-region deployments. It is set in the
+	// "region" claim of the tokens, except the minimal access tokens, and prefixes their jti, so every
+	// token can be traced to the region that issued it.
+	Region string
+}
+
+// alwaysIssuedClaims are the claims of every token issued, including the minimal access tokens
+var alwaysIssuedClaims = []string{"exp", "sub", "jti"}
+
+// algorithm returns the algorithm of the issued tokens
+func (p TokenSigningPolicy) algorithm() string {
+	if p.Algorithm == "" {
+		return defaultSigningAlgorithm
+	}
+	return p.Algorithm
+}
+
+// acceptedAlgorithms returns the algorithms of the tokens accepted
+func (p TokenSigningPolicy) acceptedAlgorithms() []string {
+	if len(p.AcceptedAlgorithms) == 0 {
+		return []string{p.algorithm()}
+	}
+	return p.AcceptedAlgorithms
+}
+
+// newToken returns an unsigned token of the claims, with the signing algorithm and key ID of the policy
+func (p TokenSigningPolicy) newToken(claims jwt.Claims) *jwt.Token {
+	method := jwt.GetSigningMethod(p.algorithm())
+	if method == nil {
+		method = jwt.SigningMethodHS256
+	}
+
+	token := jwt.NewWithClaims(method, claims)
+	if p.KeyID != "" {
+		token.Header["kid"] = p.KeyID
+	}
+	return token
+}
+
+// newTokenID returns a unique jti, prefixed with the region issuing the token
+func (p TokenSigningPolicy) newTokenID() string {
+	return domain.RegionalTokenID(p.Region, uuid.New().String())
+}
+
+// exceedsMaxSize returns true if the token is larger than the maximum token size
+func (p TokenSigningPolicy) exceedsMaxSize(token string) bool {
+	return p.MaxTokenSize > 0 && len(token) > p.MaxTokenSize
+}
+
+// checkSize reports an issued access token larger than the maximum token size, usually because of its
+// custom claims (scopes, metadata)
+func (p TokenSigningPolicy) checkSize(token, tokenType string, logger *zap.Logger, fields ...zap.Field) {
+	if !p.exceedsMaxSize(token) {
+		return
+	}
+	metrics.IncOversizedTokens(tokenType)
+	logger.Warn("issued access token exceeds the maximum token size", append(fields,
+		zap.String("type", tokenType),
+		zap.Int("size", len(token)),
+		zap.Int("max_size", p.MaxTokenSize))...)
+}
+
+// parser returns a parser that only accepts the algorithms of the policy
+func (p TokenSigningPolicy) parser(options ...jwt.ParserOption) *jwt.Parser {
+	return jwt.NewParser(append([]jwt.ParserOption{jwt.WithValidMethods(p.acceptedAlgorithms())}, options...)...)
+}
+
+// keyFunc returns the key verifying the tokens, after checking their key ID when rotation is enabled
+func (p TokenSigningPolicy) keyFunc(secret []byte) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+
+		if p.KeyID != "" {
+			kid, _ := token.Header["kid"].(string)
+			if kid == "" && p.CompatibilityMode {
+				return secret, nil
+			}
+			if kid == "" {
+				return nil, errors.New("missing key id")
+			}
+			if kid != p.KeyID {
+				return nil, fmt.Errorf("unknown key id: %s", kid)
+			}
+		}
+		return secret, nil
+	}
+}
+
+// missingClaim returns the first required claim the token does not carry, or "" when it has them all
+func (p TokenSigningPolicy) missingClaim(token *jwt.Token, claims *CustomClaims) string {
+	if p.CompatibilityMode {
+		return ""
+	}
+	for _, claim := range p.RequiredClaims {
+		if !hasClaim(token, claims, claim) {
+			return claim
+		}
+	}
+	return ""
+}
+
+// hasClaim checks if a token carries a claim, unknown claims are never carried
+func hasClaim(token *jwt.Token, claims *CustomClaims, claim string) bool {
+	switch claim {
+	case "exp":
+		return claims.ExpiresAt != nil
+	case "iat":
+		return claims.IssuedAt != nil
+	case "nbf":
+		return claims.NotBefore != nil
+	case "iss":
+		return claims.Issuer != ""
+	case "sub":
+		return claims.Subject != ""
+	case "jti":
+		return claims.ID != ""
+	case "uid":
+		return claims.UserID != ""
+	case "tv":
+		return claims.Version != 0
+	case "kid":
+		kid, _ := token.Header["kid"].(string)
+		return kid != ""
+	default:
+		return false
+	}
+}
+
+// CompatibilityWarnings describes the settings that can make the instances of a mixed-version cluster
+// reject the tokens of each other, e.g. during a rolling or blue/green deployment
+func (p TokenSigningPolicy) CompatibilityWarnings() []string {
+	var warnings []string
+	if p.CompatibilityMode {
+		warnings = append(warnings, "token compatibility mode is enabled: required claims are not enforced and tokens without a key ID are accepted, disable it once every instance runs this version")
+		return warnings
+	}
+
+	if p.KeyID != "" {
+		warnings = append(warnings, "tokens without a key ID are rejected: instances running without JWT_KEY_ID issue tokens this instance rejects, enable JWT_COMPATIBILITY_MODE during the rollout")
+	}
+	if len(p.RequiredClaims) > 0 {
+		warnings = append(warnings, fmt.Sprintf("tokens without the claims %s are rejected: instances of a previous version may not issue them, enable JWT_COMPATIBILITY_MODE during the rollout", strings.Join(p.RequiredClaims, ", ")))
+	}
+
+	// Some tokens of this version lack the claims only issued in full tokens, or the key ID without KeyID
+	var notIssued []string
+	for _, claim := range p.RequiredClaims {
+		if slices.Contains(alwaysIssuedClaims, claim) || (claim == "kid" && p.KeyID != "") {
+			continue
+		}
+		notIssued = append(notIssued, claim)
+	}
+	if len(notIssued) > 0 {
+		warnings = append(warnings, fmt.Sprintf("the claims %s are not carried by every token issued by this version, the tokens without them are rejected", strings.Join(notIssued, ", ")))
+	}
+
+	if !slices.Contains(p.acceptedAlgorithms(), defaultSigningAlgorithm) {
+		warnings = append(warnings, fmt.Sprintf("tokens signed with %s are rejected: instances running the default signing algorithm issue tokens this instance rejects, add it to JWT_ACCEPTED_ALGORITHMS during the rollout", defaultSigningAlgorithm))
+	}
+	return warnings
+}
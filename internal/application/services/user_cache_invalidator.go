@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// User cache lookup results reported to metrics
+const (
+	userCacheHit    = "hit"
+	userCacheMiss   = "miss"
+	userCacheBypass = "bypass"
+)
+
+// UserCacheInvalidator decorates a user repository so that every update and deletion of a user evicts
+// the user from the user cache, without the services writing users knowing about the cache
+type UserCacheInvalidator struct {
+	ports.UserRepository
+	cache  ports.UserCache
+	logger *zap.Logger
+}
+
+// NewUserCacheInvalidator creates a new instance of UserCacheInvalidator
+func NewUserCacheInvalidator(userRepo ports.UserRepository, cache ports.UserCache, logger *zap.Logger) *UserCacheInvalidator {
+	return &UserCacheInvalidator{
+		UserRepository: userRepo,
+		cache:          cache,
+		logger:         logger,
+	}
+}
+
+// Update updates the user and evicts it from the cache
+func (r *UserCacheInvalidator) Update(ctx context.Context, user *domain.User) error {
+	if err := r.UserRepository.Update(ctx, user); err != nil {
+		return err
+	}
+	r.evict(ctx, user.IDCitizen)
+	return nil
+}
+
+// Delete deletes the user and evicts it from the cache
+func (r *UserCacheInvalidator) Delete(ctx context.Context, id string) error {
+	// The cache is keyed by citizen ID, which is only known before the deletion
+	user, getErr := r.UserRepository.GetByID(ctx, id)
+
+	if err := r.UserRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	if getErr != nil {
+		r.logger.Warn("failed to get deleted user, its cache entry expires with the TTL", zap.Error(getErr), zap.String("user_id", id))
+		return nil
+	}
+	r.evict(ctx, user.IDCitizen)
+	return nil
+}
+
+// evict removes the user from the cache; a failure only delays the change until the cache TTL expires
+func (r *UserCacheInvalidator) evict(ctx context.Context, idCitizen int) {
+	if err := r.cache.Delete(ctx, idCitizen); err != nil {
+		r.logger.Warn("failed to evict user from cache", zap.Error(err), zap.Int("id_citizen", idCitizen))
+	}
+}
@@ -0,0 +1,257 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	"github.com/kristianrpo/auth-microservice/internal/domain/events"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+)
+
+// UserEmailServiceInterface defines the methods of UserEmailService used by handlers.
+type UserEmailServiceInterface interface {
+	ListEmails(ctx context.Context, idCitizen int) ([]*domain.UserEmail, error)
+	AddEmail(ctx context.Context, idCitizen int, email string) (*domain.UserEmail, error)
+	VerifyEmail(ctx context.Context, idCitizen int, id, code string) (*domain.UserEmail, error)
+	RemoveEmail(ctx context.Context, idCitizen int, id string) error
+	ListUserEmails(ctx context.Context, userID string) ([]*domain.UserEmail, error)
+}
+
+// UserEmailService manages the secondary email addresses of users. Addresses are confirmed with a code
+// sent to them, and verified addresses can receive password reset links when the primary email is
+// inaccessible. Codes are delivered by the notification service through the security notification queue.
+type UserEmailService struct {
+	userRepo          ports.UserRepository
+	emailRepo         ports.UserEmailRepository
+	publisher         ports.MessagePublisher
+	notificationQueue string
+	emailLimiter      ports.RateLimiter
+	codeDuration      time.Duration
+	logger            *zap.Logger
+}
+
+// NewUserEmailService creates a new instance of UserEmailService
+func NewUserEmailService(
+	userRepo ports.UserRepository,
+	emailRepo ports.UserEmailRepository,
+	publisher ports.MessagePublisher,
+	notificationQueue string,
+	emailLimiter ports.RateLimiter,
+	codeDuration time.Duration,
+	logger *zap.Logger,
+) *UserEmailService {
+	return &UserEmailService{
+		userRepo:          userRepo,
+		emailRepo:         emailRepo,
+		publisher:         publisher,
+		notificationQueue: notificationQueue,
+		emailLimiter:      emailLimiter,
+		codeDuration:      codeDuration,
+		logger:            logger,
+	}
+}
+
+// ListEmails retrieves the secondary email addresses of a user
+func (s *UserEmailService) ListEmails(ctx context.Context, idCitizen int) ([]*domain.UserEmail, error) {
+	user, err := s.userRepo.GetByIDCitizen(ctx, idCitizen)
+	if err != nil {
+		s.logger.Error("failed to get user", zap.Error(err), zap.Int("id_citizen", idCitizen))
+		return nil, err
+	}
+
+	return s.ListUserEmails(ctx, user.ID)
+}
+
+// ListUserEmails retrieves the secondary email addresses of a user by ID, for administrators
+func (s *UserEmailService) ListUserEmails(ctx context.Context, userID string) ([]*domain.UserEmail, error) {
+	emails, err := s.emailRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		s.logger.Error("failed to list user emails", zap.Error(err), zap.String("user_id", userID))
+		return nil, internalError(err)
+	}
+
+	return emails, nil
+}
+
+// AddEmail registers a secondary email address and sends it a verification code.
+// The address can't be used for password resets until it is confirmed with VerifyEmail.
+func (s *UserEmailService) AddEmail(ctx context.Context, idCitizen int, email string) (*domain.UserEmail, error) {
+	address, ok := domain.NormalizeEmail(email)
+	if !ok {
+		return nil, domainerrors.ErrInvalidEmail
+	}
+
+	user, err := s.userRepo.GetByIDCitizen(ctx, idCitizen)
+	if err != nil {
+		s.logger.Error("failed to get user", zap.Error(err), zap.Int("id_citizen", idCitizen))
+		return nil, err
+	}
+
+	// Primary emails can already receive reset links, they would make the owner ambiguous
+	exists, err := s.userRepo.Exists(ctx, address)
+	if err != nil {
+		s.logger.Error("failed to check user existence", zap.Error(err))
+		return nil, internalError(err)
+	}
+	if exists {
+		return nil, domainerrors.ErrEmailAlreadyRegistered
+	}
+
+	emails, err := s.emailRepo.ListByUserID(ctx, user.ID)
+	if err != nil {
+		s.logger.Error("failed to list user emails", zap.Error(err), zap.String("user_id", user.ID))
+		return nil, internalError(err)
+	}
+	if len(emails) >= domain.MaxUserEmails {
+		return nil, domainerrors.ErrTooManyEmails
+	}
+
+	if err := reserveEmail(ctx, s.emailLimiter, address, s.logger); err != nil {
+		return nil, err
+	}
+
+	code, err := generateVerificationCode()
+	if err != nil {
+		s.logger.Error("failed to generate verification code", zap.Error(err))
+		return nil, internalError(err)
+	}
+
+	userEmail := &domain.UserEmail{
+		UserID: user.ID,
+		Email:  address,
+	}
+	userEmail.SetVerificationCode(hashSecret(code), time.Now().Add(s.codeDuration))
+	if err := s.emailRepo.Create(ctx, userEmail); err != nil {
+		if errors.Is(err, domainerrors.ErrEmailAlreadyRegistered) {
+			return nil, err
+		}
+		s.logger.Error("failed to save user email", zap.Error(err), zap.String("user_id", user.ID))
+		return nil, internalError(err)
+	}
+
+	err = publishEmailNotification(ctx, s.publisher, s.notificationQueue, user, address, domain.NotificationEmailVerification, map[string]string{
+		"code":               code,
+		"expires_in_minutes": strconv.Itoa(int(s.codeDuration.Minutes())),
+	})
+	if err != nil {
+		s.logger.Error("failed to publish email verification", zap.Error(err), zap.String("user_id", user.ID))
+		if delErr := s.emailRepo.Delete(ctx, user.ID, userEmail.ID); delErr != nil {
+			s.logger.Error("failed to delete user email", zap.Error(delErr), zap.String("email_id", userEmail.ID))
+		}
+		return nil, internalError(err)
+	}
+
+	s.logger.Info("email verification code sent",
+		zap.String("user_id", user.ID),
+		logging.String("email", domain.MaskEmail(address)))
+	return userEmail, nil
+}
+
+// VerifyEmail checks the code sent by AddEmail and marks the address as verified.
+// The code is discarded after MaxEmailVerificationAttempts wrong codes.
+func (s *UserEmailService) VerifyEmail(ctx context.Context, idCitizen int, id, code string) (*domain.UserEmail, error) {
+	user, err := s.userRepo.GetByIDCitizen(ctx, idCitizen)
+	if err != nil {
+		s.logger.Error("failed to get user", zap.Error(err), zap.Int("id_citizen", idCitizen))
+		return nil, err
+	}
+
+	userEmail, err := s.emailRepo.GetByID(ctx, user.ID, id)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrEmailNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to get user email", zap.Error(err), zap.String("email_id", id))
+		return nil, internalError(err)
+	}
+
+	if userEmail.IsVerified() {
+		return userEmail, nil
+	}
+	if !userEmail.HasPendingCode() {
+		return nil, domainerrors.ErrInvalidVerificationCode
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashSecret(code)), []byte(userEmail.CodeHash)) != 1 {
+		userEmail.CodeAttempts++
+		if err := s.emailRepo.Update(ctx, userEmail); err != nil {
+			s.logger.Error("failed to update user email", zap.Error(err), zap.String("email_id", id))
+		}
+		return nil, domainerrors.ErrInvalidVerificationCode
+	}
+
+	userEmail.MarkVerified(time.Now())
+	if err := s.emailRepo.Update(ctx, userEmail); err != nil {
+		if errors.Is(err, domainerrors.ErrEmailAlreadyRegistered) || errors.Is(err, domainerrors.ErrEmailNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to update user email", zap.Error(err), zap.String("email_id", id))
+		return nil, internalError(err)
+	}
+
+	s.logger.Info("email verified",
+		zap.String("user_id", user.ID),
+		logging.String("email", domain.MaskEmail(userEmail.Email)))
+	return userEmail, nil
+}
+
+// RemoveEmail deletes a secondary email address of a user
+func (s *UserEmailService) RemoveEmail(ctx context.Context, idCitizen int, id string) error {
+	user, err := s.userRepo.GetByIDCitizen(ctx, idCitizen)
+	if err != nil {
+		s.logger.Error("failed to get user", zap.Error(err), zap.Int("id_citizen", idCitizen))
+		return err
+	}
+
+	if err := s.emailRepo.Delete(ctx, user.ID, id); err != nil {
+		if errors.Is(err, domainerrors.ErrEmailNotFound) {
+			return err
+		}
+		s.logger.Error("failed to delete user email", zap.Error(err), zap.String("email_id", id))
+		return internalError(err)
+	}
+
+	s.logger.Info("user email removed", zap.String("user_id", user.ID), zap.String("email_id", id))
+	return nil
+}
+
+// reserveEmail checks the rate limit of the emails sent to an address
+func reserveEmail(ctx context.Context, limiter ports.RateLimiter, address string, logger *zap.Logger) error {
+	status, err := limiter.Hit(ctx, domain.EmailRateLimitKey(address))
+	if err != nil {
+		logger.Error("failed to check email rate limit", zap.Error(err))
+		return internalError(err)
+	}
+	if status.Exceeded() {
+		logger.Warn("email rate limit exceeded", logging.String("email", domain.MaskEmail(address)))
+		return domainerrors.ErrEmailRateLimited
+	}
+	return nil
+}
+
+// publishEmailNotification publishes a mandatory notification to an address of the user, which may not be
+// their primary email
+func publishEmailNotification(
+	ctx context.Context,
+	publisher ports.MessagePublisher,
+	queue string,
+	user *domain.User,
+	address string,
+	notificationType domain.NotificationType,
+	details map[string]string,
+) error {
+	event := events.NewSecurityNotificationEvent(string(notificationType), user.IDCitizen, user.Name, address, details)
+	eventData, err := event.ToJSON()
+	if err != nil {
+		return err
+	}
+	return publisher.Publish(ctx, queue, eventData)
+}
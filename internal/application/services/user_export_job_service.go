@@ -0,0 +1,477 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
+)
+
+// userExportContentType is the content type of the personal data archives
+const userExportContentType = "application/zip"
+
+// UserExportPolicy controls how the personal data exports are run and kept
+type UserExportPolicy struct {
+	Workers   int           // exports assembled concurrently
+	QueueSize int           // exports waiting for a worker, more are rejected
+	Timeout   time.Duration // maximum duration of an export
+	Retention time.Duration // how long a job and its archive are kept
+	URLExpiry time.Duration // validity of the signed download URLs
+
+	// Removal of the archives past their retention
+	CleanupInterval  time.Duration
+	CleanupBatchSize int
+}
+
+// UserExportJobServiceInterface defines the methods of UserExportJobService used by handlers.
+type UserExportJobServiceInterface interface {
+	RequestExport(ctx context.Context, idCitizen int) (*domain.UserExportJob, error)
+	GetExport(ctx context.Context, idCitizen int, jobID string) (*domain.UserExportJob, *domain.UserExportDownload, error)
+}
+
+// UserExportJobService exports the personal data of users on their own request. The archive is assembled
+// in the background, since it can take longer than a request, and kept in blob storage for the retention
+// period, downloaded through short-lived signed URLs. Archives past their retention are removed.
+type UserExportJobService struct {
+	userRepo    ports.UserRepository
+	emailRepo   ports.UserEmailRepository
+	consentRepo ports.ConsentRepository
+	prefsRepo   ports.NotificationPreferencesRepository
+	auditLog    ports.AuditLogQueryRepository
+	auditRepo   ports.AuditLogRepository
+	jobRepo     ports.UserExportJobRepository
+	storage     ports.BlobStorage
+	policy      UserExportPolicy
+	logger      *zap.Logger
+
+	queue  chan *domain.UserExportJob
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewUserExportJobService creates a new instance of UserExportJobService
+func NewUserExportJobService(
+	userRepo ports.UserRepository,
+	emailRepo ports.UserEmailRepository,
+	consentRepo ports.ConsentRepository,
+	prefsRepo ports.NotificationPreferencesRepository,
+	auditLog ports.AuditLogQueryRepository,
+	auditRepo ports.AuditLogRepository,
+	jobRepo ports.UserExportJobRepository,
+	storage ports.BlobStorage,
+	policy UserExportPolicy,
+	logger *zap.Logger,
+) *UserExportJobService {
+	return &UserExportJobService{
+		userRepo:    userRepo,
+		emailRepo:   emailRepo,
+		consentRepo: consentRepo,
+		prefsRepo:   prefsRepo,
+		auditLog:    auditLog,
+		auditRepo:   auditRepo,
+		jobRepo:     jobRepo,
+		storage:     storage,
+		policy:      policy,
+		logger:      logger,
+		queue:       make(chan *domain.UserExportJob, policy.QueueSize),
+	}
+}
+
+// RequestExport starts an export of the personal data of a user and returns the pending job. While an
+// export of the user is in progress it is returned instead of starting another one, unless it is older than
+// an export can wait and run, then it was lost (e.g. by a restart) and is marked as failed. It returns
+// ErrExportsBusy when too many exports are waiting.
+func (s *UserExportJobService) RequestExport(ctx context.Context, idCitizen int) (*domain.UserExportJob, error) {
+	user, err := s.userRepo.GetByIDCitizen(ctx, idCitizen)
+	if err != nil {
+		s.logger.Error("failed to get user", zap.Error(err), zap.Int("id_citizen", idCitizen))
+		return nil, err
+	}
+
+	latest, err := s.jobRepo.GetLatestByUser(ctx, user.ID)
+	if err != nil && !errors.Is(err, domainerrors.ErrExportJobNotFound) {
+		s.logger.Error("failed to get latest export job", zap.Error(err), zap.String("user_id", user.ID))
+		return nil, internalError(err)
+	}
+	if latest != nil && !latest.Finished() {
+		if time.Since(latest.CreatedAt) < s.staleAfter() {
+			return latest, nil
+		}
+		s.logger.Warn("stale export job marked as failed", zap.String("user_id", user.ID), zap.String("job_id", latest.ID))
+		latest.Finish(domain.UserExportJobFailed, 0)
+		s.updateJob(ctx, latest)
+	}
+
+	job := domain.NewUserExportJob(user.ID, s.policy.Retention)
+	if err := s.jobRepo.Store(ctx, job); err != nil {
+		s.logger.Error("failed to store export job", zap.Error(err), zap.String("user_id", user.ID))
+		return nil, internalError(err)
+	}
+
+	select {
+	case s.queue <- job:
+	default:
+		metrics.IncUserExports("rejected")
+		s.logger.Warn("export queue full, export rejected", zap.String("user_id", user.ID))
+		job.Finish(domain.UserExportJobFailed, 0)
+		s.updateJob(ctx, job)
+		return nil, domainerrors.ErrExportsBusy
+	}
+
+	s.logger.Info("export requested", zap.String("user_id", user.ID), zap.String("job_id", job.ID))
+	return job, nil
+}
+
+// GetExport returns an export of a user, with a signed URL of its archive once completed. Jobs of other
+// users are reported as not found.
+func (s *UserExportJobService) GetExport(ctx context.Context, idCitizen int, jobID string) (*domain.UserExportJob, *domain.UserExportDownload, error) {
+	user, err := s.userRepo.GetByIDCitizen(ctx, idCitizen)
+	if err != nil {
+		s.logger.Error("failed to get user", zap.Error(err), zap.Int("id_citizen", idCitizen))
+		return nil, nil, err
+	}
+
+	job, err := s.jobRepo.Get(ctx, jobID)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrExportJobNotFound) {
+			return nil, nil, err
+		}
+		s.logger.Error("failed to get export job", zap.Error(err), zap.String("job_id", jobID))
+		return nil, nil, internalError(err)
+	}
+	if job.UserID != user.ID {
+		return nil, nil, domainerrors.ErrExportJobNotFound
+	}
+	if job.Status != domain.UserExportJobCompleted {
+		return job, nil, nil
+	}
+
+	// The URL must not outlive the archive
+	expiresIn := min(s.policy.URLExpiry, time.Until(job.ExpiresAt))
+	if expiresIn <= 0 {
+		return nil, nil, domainerrors.ErrExportJobNotFound
+	}
+	url, err := s.storage.SignedURL(job.ObjectKey, expiresIn)
+	if err != nil {
+		s.logger.Error("failed to sign export url", zap.Error(err), zap.String("job_id", job.ID))
+		return nil, nil, internalError(err)
+	}
+	return job, &domain.UserExportDownload{URL: url, ExpiresAt: time.Now().Add(expiresIn)}, nil
+}
+
+// Start runs the export workers and the archive cleanup in the background, it implements Component
+func (s *UserExportJobService) Start(ctx context.Context) error {
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	s.cancel = cancel
+	for i := 0; i < s.policy.Workers; i++ {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.work(runCtx)
+		}()
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.runCleanup(runCtx)
+	}()
+	return nil
+}
+
+// staleAfter returns how long an export can take at most, waiting behind a full queue and then running
+func (s *UserExportJobService) staleAfter() time.Duration {
+	workers := max(s.policy.Workers, 1)
+	queueWait := time.Duration((s.policy.QueueSize+workers-1)/workers) * s.policy.Timeout
+	return queueWait + s.policy.Timeout
+}
+
+// Stop stops the background workers and waits for the exports in progress, until the context is done.
+// The exports still waiting are marked as failed, even when the context is done first, so their users can
+// request them again.
+func (s *UserExportJobService) Stop(ctx context.Context) error {
+	if s.cancel == nil {
+		return nil
+	}
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	var err error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	for {
+		select {
+		case job := <-s.queue:
+			job.Finish(domain.UserExportJobFailed, 0)
+			s.updateJob(context.WithoutCancel(ctx), job)
+		default:
+			return err
+		}
+	}
+}
+
+// work runs the queued exports until the context is cancelled
+func (s *UserExportJobService) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-s.queue:
+			s.Run(ctx, job)
+		}
+	}
+}
+
+// Run assembles the archive of a job, stores it and records the result of the job
+func (s *UserExportJobService) Run(ctx context.Context, job *domain.UserExportJob) {
+	ctx, cancel := context.WithTimeout(ctx, s.policy.Timeout)
+	defer cancel()
+
+	job.Status = domain.UserExportJobRunning
+	s.updateJob(ctx, job)
+
+	user, err := s.userRepo.GetByID(ctx, job.UserID)
+	var size int64
+	if err == nil {
+		size, err = s.export(ctx, job, user)
+	}
+	if err != nil {
+		metrics.IncUserExports("failed")
+		s.logger.Error("failed to export user data", zap.Error(err), zap.String("user_id", job.UserID), zap.String("job_id", job.ID))
+		job.Finish(domain.UserExportJobFailed, 0)
+		s.updateJob(context.WithoutCancel(ctx), job)
+		return
+	}
+
+	job.Finish(domain.UserExportJobCompleted, size)
+	if err := s.jobRepo.Update(context.WithoutCancel(ctx), job); err != nil {
+		// Nobody can download the archive without its job
+		metrics.IncUserExports("failed")
+		s.logger.Error("failed to complete export job", zap.Error(err), zap.String("job_id", job.ID))
+		s.deleteObject(context.WithoutCancel(ctx), job.ObjectKey)
+		return
+	}
+	metrics.IncUserExports("completed")
+
+	record := domain.NewAuditRecord(domain.AuditActionUserDataExported, user.AuditActor(), job.UserID, map[string]string{
+		"job_id": job.ID,
+		"size":   strconv.FormatInt(size, 10),
+	})
+	if err := s.auditRepo.Record(context.WithoutCancel(ctx), record); err != nil {
+		s.logger.Error("failed to write audit record", zap.Error(err), zap.String("job_id", job.ID))
+	}
+
+	s.logger.Info("user data exported",
+		zap.String("user_id", job.UserID),
+		zap.String("job_id", job.ID),
+		zap.Int64("size", size))
+}
+
+// export writes the archive of a job to a temporary file, so large archives are not held in memory, and
+// stores it, returning the size of the archive
+func (s *UserExportJobService) export(ctx context.Context, job *domain.UserExportJob, user *domain.User) (int64, error) {
+	file, err := os.CreateTemp("", "user-export-*.zip")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create archive file: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+	}()
+
+	if err := s.writeArchive(ctx, file, user); err != nil {
+		return 0, err
+	}
+
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	if err := s.storage.Put(ctx, job.ObjectKey, userExportContentType, file, size); err != nil {
+		return 0, fmt.Errorf("failed to store archive: %w", err)
+	}
+	return size, nil
+}
+
+// writeArchive writes a zip archive of the personal data of a user, a JSON file per dataset: the profile,
+// the secondary emails, the consents, the notification preferences and the audit records about the user
+func (s *UserExportJobService) writeArchive(ctx context.Context, w io.Writer, user *domain.User) error {
+	emails, err := s.emailRepo.ListByUserID(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list emails: %w", err)
+	}
+	consents, err := s.consentRepo.ListByUser(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list consents: %w", err)
+	}
+	prefs, err := s.prefsRepo.GetByUserID(ctx, user.ID)
+	if errors.Is(err, domainerrors.ErrPreferencesNotFound) {
+		prefs, err = domain.DefaultNotificationPreferences(user.ID), nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+
+	archive := zip.NewWriter(w)
+	for _, entry := range []struct {
+		name  string
+		value interface{}
+	}{
+		{"profile.json", user},
+		{"emails.json", emails},
+		{"consents.json", consents},
+		{"notification_preferences.json", prefs},
+	} {
+		if err := writeArchiveJSON(archive, entry.name, entry.value); err != nil {
+			return err
+		}
+	}
+
+	if err := s.writeAuditLog(ctx, archive, user.ID); err != nil {
+		return err
+	}
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	return nil
+}
+
+// writeAuditLog streams the audit records about a user to a JSON array of the archive
+func (s *UserExportJobService) writeAuditLog(ctx context.Context, archive *zip.Writer, userID string) error {
+	file, err := archive.Create("audit_log.json")
+	if err != nil {
+		return fmt.Errorf("failed to write audit_log.json: %w", err)
+	}
+
+	records := 0
+	if _, err := io.WriteString(file, "["); err != nil {
+		return err
+	}
+	err = s.auditLog.StreamRecords(ctx, domain.AuditLogFilter{TargetID: userID}, func(record *domain.AuditRecord) error {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		if records > 0 {
+			if _, err := io.WriteString(file, ","); err != nil {
+				return err
+			}
+		}
+		records++
+		_, err = file.Write(data)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export audit log: %w", err)
+	}
+	_, err = io.WriteString(file, "]")
+	return err
+}
+
+// writeArchiveJSON adds a JSON file to the archive
+func writeArchiveJSON(archive *zip.Writer, name string, value interface{}) error {
+	file, err := archive.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// runCleanup removes the expired archives every interval until the context is cancelled
+func (s *UserExportJobService) runCleanup(ctx context.Context) {
+	ticker := time.NewTicker(s.policy.CleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.RemoveExpiredArchives(ctx); err != nil && ctx.Err() == nil {
+				s.logger.Error("failed to remove expired export archives", zap.Error(err))
+			}
+		}
+	}
+}
+
+// RemoveExpiredArchives scans the export archives in batches, removes the ones older than the retention
+// and returns how many were removed. Archives that fail to be removed are retried on the next run.
+func (s *UserExportJobService) RemoveExpiredArchives(ctx context.Context) (int, error) {
+	cutoff := time.Now().Add(-s.policy.Retention)
+	removed := 0
+	startAfter := ""
+
+	for ctx.Err() == nil {
+		objects, err := s.storage.List(ctx, domain.UserExportKeyPrefix, startAfter, s.policy.CleanupBatchSize)
+		if err != nil {
+			return removed, err
+		}
+		if len(objects) == 0 {
+			break
+		}
+		startAfter = objects[len(objects)-1].Key
+
+		for _, object := range objects {
+			if !object.LastModified.Before(cutoff) {
+				continue
+			}
+			if err := s.storage.Delete(ctx, object.Key); err != nil {
+				s.logger.Warn("failed to remove expired export archive", zap.Error(err), logging.String("key", object.Key))
+				continue
+			}
+			removed++
+		}
+
+		if len(objects) < s.policy.CleanupBatchSize {
+			break
+		}
+	}
+
+	if removed > 0 {
+		s.logger.Info("expired export archives removed", zap.Int("count", removed))
+	}
+	return removed, ctx.Err()
+}
+
+// updateJob saves the state of a job, failures are only logged
+func (s *UserExportJobService) updateJob(ctx context.Context, job *domain.UserExportJob) {
+	if err := s.jobRepo.Update(ctx, job); err != nil {
+		s.logger.Warn("failed to update export job", zap.Error(err), zap.String("job_id", job.ID))
+	}
+}
+
+// deleteObject removes an export archive from storage, failures are only logged
+func (s *UserExportJobService) deleteObject(ctx context.Context, key string) {
+	if err := s.storage.Delete(ctx, key); err != nil {
+		s.logger.Warn("failed to delete export archive", zap.Error(err), logging.String("key", key))
+	}
+}
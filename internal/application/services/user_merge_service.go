@@ -0,0 +1,364 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	"github.com/kristianrpo/auth-microservice/internal/domain/events"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+)
+
+// UserMergeServiceInterface defines the methods of UserMergeService used by handlers.
+type UserMergeServiceInterface interface {
+	MergeUsers(ctx context.Context, survivorID, mergedID, actor string, dryRun bool) (*domain.UserMerge, error)
+}
+
+// UserMergeService merges duplicate accounts, e.g. two citizen IDs of the same person from a legacy import.
+// The consents, emails, phone number and audit records of the duplicate move to the surviving account,
+// the sessions of the duplicate are ended and the duplicate is soft-deleted.
+type UserMergeService struct {
+	userRepo            ports.UserRepository
+	consentRepo         ports.ConsentRepository
+	emailRepo           ports.UserEmailRepository
+	phoneRepo           ports.PhoneNumberRepository
+	tokenRepo           ports.TokenRepository
+	auditRefs           ports.AuditReferenceRepository
+	auditRepo           ports.AuditLogRepository
+	publisher           ports.MessagePublisher
+	userMergedQueue     string
+	accessTokenDuration time.Duration
+	logger              *zap.Logger
+}
+
+// NewUserMergeService creates a new instance of UserMergeService.
+// accessTokenDuration is the lifetime of the access tokens, during which those of the duplicate are rejected.
+func NewUserMergeService(
+	userRepo ports.UserRepository,
+	consentRepo ports.ConsentRepository,
+	emailRepo ports.UserEmailRepository,
+	phoneRepo ports.PhoneNumberRepository,
+	tokenRepo ports.TokenRepository,
+	auditRefs ports.AuditReferenceRepository,
+	auditRepo ports.AuditLogRepository,
+	publisher ports.MessagePublisher,
+	userMergedQueue string,
+	accessTokenDuration time.Duration,
+	logger *zap.Logger,
+) *UserMergeService {
+	return &UserMergeService{
+		userRepo:            userRepo,
+		consentRepo:         consentRepo,
+		emailRepo:           emailRepo,
+		phoneRepo:           phoneRepo,
+		tokenRepo:           tokenRepo,
+		auditRefs:           auditRefs,
+		auditRepo:           auditRepo,
+		publisher:           publisher,
+		userMergedQueue:     userMergedQueue,
+		accessTokenDuration: accessTokenDuration,
+		logger:              logger,
+	}
+}
+
+// userMergePlan is what a merge moves, along with the records it moves
+type userMergePlan struct {
+	merge          *domain.UserMerge
+	consents       []*domain.Consent
+	mergedEmails   []*domain.UserEmail
+	mergedPhone    *domain.PhoneNumber
+	survivorPhone  *domain.PhoneNumber
+	survivorEmails map[string]bool
+}
+
+// MergeUsers merges the account mergedID into the account survivorID. With dryRun the merge is only
+// previewed. actor identifies who requested the merge.
+// Everything is moved before the duplicate is deleted, so a merge that fails midway can be retried.
+func (s *UserMergeService) MergeUsers(ctx context.Context, survivorID, mergedID, actor string, dryRun bool) (*domain.UserMerge, error) {
+	survivor, err := s.getUser(ctx, survivorID)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := s.getUser(ctx, mergedID)
+	if err != nil {
+		return nil, err
+	}
+	if !domain.CanMergeUsers(survivor, merged) {
+		return nil, domainerrors.ErrInvalidUserMerge
+	}
+
+	plan, err := s.plan(ctx, survivor, merged)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		plan.merge.DryRun = true
+		return plan.merge, nil
+	}
+
+	if err := s.moveConsents(ctx, plan); err != nil {
+		return nil, err
+	}
+	if err := s.moveEmails(ctx, plan); err != nil {
+		return nil, err
+	}
+	if err := s.movePhoneNumber(ctx, plan); err != nil {
+		return nil, err
+	}
+
+	moved, err := s.auditRefs.ReassignTarget(ctx, merged.ID, survivor.ID)
+	if err != nil {
+		return nil, internalError(err)
+	}
+	plan.merge.AuditRecords = moved
+
+	// Best effort: refreshes are rejected anyway once the duplicate is deleted
+	if err := s.tokenRepo.DeleteUserTokens(ctx, merged.ID, merged.IDCitizen); err != nil {
+		s.logger.Error("failed to revoke tokens of merged user", zap.Error(err), zap.String("user_id", merged.ID))
+	}
+	if err := s.tokenRepo.SetTokenVersion(ctx, merged.IDCitizen, merged.TokenVersion+1, s.accessTokenDuration); err != nil {
+		s.logger.Error("failed to revoke access tokens of merged user", zap.Error(err), zap.String("user_id", merged.ID))
+	}
+
+	if err := s.userRepo.Delete(ctx, merged.ID); err != nil {
+		if errors.Is(err, domainerrors.ErrUserNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to delete merged user", zap.Error(err), zap.String("user_id", merged.ID))
+		return nil, internalError(err)
+	}
+
+	record := domain.NewAuditRecord(domain.AuditActionUserMerged, actor, survivor.ID, map[string]string{
+		"merged_user_id":    merged.ID,
+		"merged_id_citizen": strconv.Itoa(merged.IDCitizen),
+		"audit_records":     strconv.Itoa(plan.merge.AuditRecords),
+	})
+	if err := s.auditRepo.Record(ctx, record); err != nil {
+		s.logger.Error("failed to write audit record", zap.Error(err), zap.String("user_id", survivor.ID), zap.String("actor", actor))
+	}
+
+	s.publishUserMerged(ctx, survivor, merged)
+
+	s.logger.Info("users merged",
+		zap.String("user_id", survivor.ID),
+		logging.String("merged_user_id", merged.ID),
+		zap.Int("consents", len(plan.merge.Consents)),
+		zap.Int("emails", len(plan.merge.Emails)),
+		zap.Int("audit_records", plan.merge.AuditRecords),
+		zap.String("actor", actor))
+	return plan.merge, nil
+}
+
+// getUser retrieves a user to merge
+func (s *UserMergeService) getUser(ctx context.Context, id string) (*domain.User, error) {
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrUserNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to get user", zap.Error(err), zap.String("user_id", id))
+		return nil, internalError(err)
+	}
+	return user, nil
+}
+
+// plan lists what the merge of the duplicate into the surviving account moves
+func (s *UserMergeService) plan(ctx context.Context, survivor, merged *domain.User) (*userMergePlan, error) {
+	plan := &userMergePlan{
+		merge:          &domain.UserMerge{Survivor: survivor, Merged: merged},
+		survivorEmails: map[string]bool{strings.ToLower(survivor.Email): true},
+	}
+
+	consents, err := s.consentRepo.ListByUser(ctx, merged.ID)
+	if err != nil {
+		s.logger.Error("failed to list consents", zap.Error(err), zap.String("user_id", merged.ID))
+		return nil, internalError(err)
+	}
+	plan.consents = consents
+	for _, consent := range consents {
+		plan.merge.Consents = append(plan.merge.Consents, consent.ClientID)
+	}
+
+	survivorEmails, err := s.emailRepo.ListByUserID(ctx, survivor.ID)
+	if err != nil {
+		s.logger.Error("failed to list user emails", zap.Error(err), zap.String("user_id", survivor.ID))
+		return nil, internalError(err)
+	}
+	for _, email := range survivorEmails {
+		plan.survivorEmails[email.Email] = true
+	}
+
+	plan.mergedEmails, err = s.emailRepo.ListByUserID(ctx, merged.ID)
+	if err != nil {
+		s.logger.Error("failed to list user emails", zap.Error(err), zap.String("user_id", merged.ID))
+		return nil, internalError(err)
+	}
+	// Unverified addresses were never proven to belong to the person, they are dropped
+	for _, address := range append([]string{strings.ToLower(merged.Email)}, verifiedAddresses(plan.mergedEmails)...) {
+		if !plan.survivorEmails[address] && !slices.Contains(plan.merge.Emails, address) {
+			plan.merge.Emails = append(plan.merge.Emails, address)
+		}
+	}
+
+	if plan.mergedPhone, err = s.getPhoneNumber(ctx, merged.ID); err != nil {
+		return nil, err
+	}
+	if plan.survivorPhone, err = s.getPhoneNumber(ctx, survivor.ID); err != nil {
+		return nil, err
+	}
+	if plan.mergedPhone != nil && plan.survivorPhone == nil {
+		plan.merge.PhoneNumber = plan.mergedPhone.Number
+	}
+
+	if plan.merge.AuditRecords, err = s.auditRefs.CountByTarget(ctx, merged.ID); err != nil {
+		return nil, internalError(err)
+	}
+
+	// Informative only, a failure doesn't prevent the merge
+	if plan.merge.Sessions, err = s.tokenRepo.CountActiveSessions(ctx, merged.IDCitizen); err != nil {
+		s.logger.Warn("failed to count sessions of merged user", zap.Error(err), zap.String("user_id", merged.ID))
+	}
+
+	return plan, nil
+}
+
+// getPhoneNumber retrieves the phone number of a user, nil when the user has none
+func (s *UserMergeService) getPhoneNumber(ctx context.Context, userID string) (*domain.PhoneNumber, error) {
+	phone, err := s.phoneRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrPhoneNotFound) {
+			return nil, nil
+		}
+		s.logger.Error("failed to get phone number", zap.Error(err), zap.String("user_id", userID))
+		return nil, internalError(err)
+	}
+	return phone, nil
+}
+
+// moveConsents moves the consents of the duplicate, a client both accounts consented to keeps the scopes
+// granted by either
+func (s *UserMergeService) moveConsents(ctx context.Context, plan *userMergePlan) error {
+	survivorID := plan.merge.Survivor.ID
+	for _, consent := range plan.consents {
+		existing, err := s.consentRepo.Get(ctx, survivorID, consent.ClientID)
+		switch {
+		case err == nil:
+			existing.Grant(consent.Scopes)
+			existing.UpdatedAt = time.Now()
+		case errors.Is(err, domainerrors.ErrConsentNotFound):
+			existing = &domain.Consent{
+				UserID:    survivorID,
+				ClientID:  consent.ClientID,
+				Scopes:    consent.Scopes,
+				GrantedAt: consent.GrantedAt,
+				UpdatedAt: time.Now(),
+			}
+		default:
+			s.logger.Error("failed to get consent", zap.Error(err), zap.String("user_id", survivorID), logging.String("client_id", consent.ClientID))
+			return internalError(err)
+		}
+
+		if err := s.consentRepo.Upsert(ctx, existing); err != nil {
+			s.logger.Error("failed to save consent", zap.Error(err), zap.String("user_id", survivorID), logging.String("client_id", consent.ClientID))
+			return internalError(err)
+		}
+		if err := s.consentRepo.Delete(ctx, consent.UserID, consent.ClientID); err != nil && !errors.Is(err, domainerrors.ErrConsentNotFound) {
+			s.logger.Error("failed to delete consent", zap.Error(err), zap.String("user_id", consent.UserID), logging.String("client_id", consent.ClientID))
+			return internalError(err)
+		}
+	}
+	return nil
+}
+
+// moveEmails adds the addresses of the duplicate to the surviving account as verified secondary emails.
+// A verified address can only belong to one user, so the addresses of the duplicate are removed first.
+func (s *UserMergeService) moveEmails(ctx context.Context, plan *userMergePlan) error {
+	merged := plan.merge.Merged
+	if err := s.emailRepo.DeleteByUserID(ctx, merged.ID); err != nil {
+		s.logger.Error("failed to delete user emails", zap.Error(err), zap.String("user_id", merged.ID))
+		return internalError(err)
+	}
+
+	var added []string
+	for _, address := range plan.merge.Emails {
+		now := time.Now()
+		err := s.emailRepo.Create(ctx, &domain.UserEmail{
+			UserID:     plan.merge.Survivor.ID,
+			Email:      address,
+			VerifiedAt: &now,
+		})
+		if err != nil {
+			// Another user verified the address in the meantime
+			if errors.Is(err, domainerrors.ErrEmailAlreadyRegistered) {
+				s.logger.Warn("email of merged user already registered", logging.String("email", domain.MaskEmail(address)))
+				continue
+			}
+			s.logger.Error("failed to save user email", zap.Error(err), zap.String("user_id", plan.merge.Survivor.ID))
+			return internalError(err)
+		}
+		added = append(added, address)
+	}
+	plan.merge.Emails = added
+	return nil
+}
+
+// movePhoneNumber moves the phone number of the duplicate, unless the surviving account has its own.
+// A number can only belong to one user, so the number of the duplicate is removed first.
+func (s *UserMergeService) movePhoneNumber(ctx context.Context, plan *userMergePlan) error {
+	if plan.mergedPhone == nil {
+		return nil
+	}
+
+	merged := plan.merge.Merged
+	if err := s.phoneRepo.Delete(ctx, merged.ID); err != nil && !errors.Is(err, domainerrors.ErrPhoneNotFound) {
+		s.logger.Error("failed to delete phone number", zap.Error(err), zap.String("user_id", merged.ID))
+		return internalError(err)
+	}
+	if plan.survivorPhone != nil {
+		return nil
+	}
+
+	phone := *plan.mergedPhone
+	phone.UserID = plan.merge.Survivor.ID
+	if err := s.phoneRepo.Upsert(ctx, &phone); err != nil {
+		s.logger.Error("failed to save phone number", zap.Error(err), zap.String("user_id", phone.UserID))
+		return internalError(err)
+	}
+	return nil
+}
+
+// publishUserMerged publishes the user.merged event (best effort)
+func (s *UserMergeService) publishUserMerged(ctx context.Context, survivor, merged *domain.User) {
+	event := events.NewUserMergedEvent(survivor.ID, survivor.IDCitizen, merged.ID, merged.IDCitizen)
+	eventData, err := event.ToJSON()
+	if err != nil {
+		s.logger.Error("failed to serialize user merged event", zap.Error(err))
+		return
+	}
+
+	if err := s.publisher.Publish(ctx, s.userMergedQueue, eventData); err != nil {
+		s.logger.Error("failed to publish user merged event", zap.Error(err), zap.String("user_id", survivor.ID))
+		return
+	}
+
+	s.logger.Info("user merged event published", zap.String("message_id", event.MessageID), zap.String("queue", s.userMergedQueue))
+}
+
+// verifiedAddresses returns the verified addresses among the emails
+func verifiedAddresses(emails []*domain.UserEmail) []string {
+	var addresses []string
+	for _, email := range emails {
+		if email.IsVerified() {
+			addresses = append(addresses, email.Email)
+		}
+	}
+	return addresses
+}
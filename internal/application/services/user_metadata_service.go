@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// UserMetadataServiceInterface defines the methods of UserMetadataService used by handlers.
+type UserMetadataServiceInterface interface {
+	UpdateUserMetadata(ctx context.Context, userID string, expectedVersion int, changes domain.UserMetadata, actor string) (*domain.UserPublic, error)
+	UpdateOwnMetadata(ctx context.Context, idCitizen int, changes domain.UserMetadata) (*domain.UserPublic, error)
+}
+
+// UserMetadataPolicy declares which custom metadata can be written and by whom
+type UserMetadataPolicy struct {
+	Schema          domain.UserMetadataSchema
+	SelfServiceKeys []string // keys users can change on their own account, administrators can change every key
+	MaxSize         int      // maximum size in bytes of the JSON encoding of the metadata of a user
+}
+
+// UserMetadataService manages the custom key-value metadata of users
+type UserMetadataService struct {
+	userRepo  ports.UserRepository
+	auditRepo ports.AuditLogRepository
+	policy    UserMetadataPolicy
+	logger    *zap.Logger
+}
+
+// NewUserMetadataService creates a new instance of UserMetadataService
+func NewUserMetadataService(
+	userRepo ports.UserRepository,
+	auditRepo ports.AuditLogRepository,
+	policy UserMetadataPolicy,
+	logger *zap.Logger,
+) *UserMetadataService {
+	return &UserMetadataService{
+		userRepo:  userRepo,
+		auditRepo: auditRepo,
+		policy:    policy,
+		logger:    logger,
+	}
+}
+
+// UpdateUserMetadata applies the changes to the metadata of a user on behalf of an administrator.
+// A nil value removes the key, keys not present in changes keep their value. expectedVersion is the version
+// of the user the changes were decided on, 0 for any.
+func (s *UserMetadataService) UpdateUserMetadata(ctx context.Context, userID string, expectedVersion int, changes domain.UserMetadata, actor string) (*domain.UserPublic, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrUserNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to get user", zap.Error(err), zap.String("user_id", userID))
+		return nil, internalError(err)
+	}
+
+	if err := checkExpectedVersion(expectedVersion, user.Version); err != nil {
+		return nil, err
+	}
+	if err := s.applyChanges(ctx, user, changes); err != nil {
+		return nil, err
+	}
+
+	record := domain.NewAuditRecord(domain.AuditActionUserMetadataUpdated, actor, user.ID, map[string]string{
+		"keys": strings.Join(slices.Sorted(maps.Keys(changes)), ","),
+	})
+	if err := s.auditRepo.Record(ctx, record); err != nil {
+		s.logger.Error("failed to write audit record", zap.Error(err), zap.String("user_id", user.ID), zap.String("actor", actor))
+	}
+
+	s.logger.Info("user metadata updated", zap.String("user_id", user.ID), zap.String("actor", actor))
+	return user.ToPublic(), nil
+}
+
+// UpdateOwnMetadata applies the changes of the authenticated user to their metadata.
+// Only the self-service keys can be changed.
+func (s *UserMetadataService) UpdateOwnMetadata(ctx context.Context, idCitizen int, changes domain.UserMetadata) (*domain.UserPublic, error) {
+	for key := range changes {
+		if !slices.Contains(s.policy.SelfServiceKeys, key) {
+			return nil, domainerrors.ErrMetadataKeyNotWritable
+		}
+	}
+
+	user, err := s.userRepo.GetByIDCitizen(ctx, idCitizen)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrUserNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to get user", zap.Error(err), zap.Int("id_citizen", idCitizen))
+		return nil, internalError(err)
+	}
+
+	if err := s.applyChanges(ctx, user, changes); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("user metadata updated by the user", zap.String("user_id", user.ID))
+	return user.ToPublic(), nil
+}
+
+// applyChanges validates the changes against the schema and the size limit and stores the new metadata
+func (s *UserMetadataService) applyChanges(ctx context.Context, user *domain.User, changes domain.UserMetadata) error {
+	if user.IsAnonymized() {
+		return domainerrors.ErrUserAlreadyAnonymized
+	}
+
+	if err := s.policy.Schema.ValidateChanges(changes); err != nil {
+		s.logger.Debug("invalid user metadata", zap.Error(err), zap.String("user_id", user.ID))
+		return domainerrors.ErrInvalidUserMetadata
+	}
+
+	metadata := user.Metadata.Apply(changes)
+	if s.policy.MaxSize > 0 && metadata.Size() > s.policy.MaxSize {
+		return domainerrors.ErrUserMetadataTooLarge
+	}
+
+	user.Metadata = metadata
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		if errors.Is(err, domainerrors.ErrUserNotFound) {
+			return err
+		}
+		s.logger.Error("failed to update user metadata", zap.Error(err), zap.String("user_id", user.ID))
+		return internalError(err)
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	"github.com/kristianrpo/auth-microservice/internal/domain/events"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// temporaryPasswordAlphabet avoids ambiguous characters (0/O, 1/l/I) so the password can be read out by phone
+const temporaryPasswordAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz23456789"
+
+// temporaryPasswordLength gives about 70 bits of entropy with temporaryPasswordAlphabet
+const temporaryPasswordLength = 12
+
+// UserProvisioningServiceInterface defines the methods of UserProvisioningService used by handlers.
+type UserProvisioningServiceInterface interface {
+	CreateUser(ctx context.Context, email, name string, idCitizen int, actor string) (*domain.User, string, error)
+	ChangeTemporaryPassword(ctx context.Context, email, temporaryPassword, newPassword string) error
+}
+
+// UserProvisioningService lets administrators create the accounts of people who can't register by themselves,
+// e.g. citizens onboarded by the helpdesk. The account gets a temporary password that is only returned once,
+// and it can't sign in until its owner replaces the password.
+type UserProvisioningService struct {
+	userRepo                   ports.UserRepository
+	auditRepo                  ports.AuditLogRepository
+	passwordHasher             ports.PasswordHasher
+	externalConnectivityClient ports.ExternalConnectivityClient
+	publisher                  ports.MessagePublisher
+	userRegisteredQueue        string
+	nameLimits                 domain.NameLimits
+	logger                     *zap.Logger
+}
+
+// NewUserProvisioningService creates a new instance of UserProvisioningService
+func NewUserProvisioningService(
+	userRepo ports.UserRepository,
+	auditRepo ports.AuditLogRepository,
+	passwordHasher ports.PasswordHasher,
+	externalConnectivityClient ports.ExternalConnectivityClient,
+	publisher ports.MessagePublisher,
+	userRegisteredQueue string,
+	nameLimits domain.NameLimits,
+	logger *zap.Logger,
+) *UserProvisioningService {
+	return &UserProvisioningService{
+		userRepo:                   userRepo,
+		auditRepo:                  auditRepo,
+		passwordHasher:             passwordHasher,
+		externalConnectivityClient: externalConnectivityClient,
+		publisher:                  publisher,
+		userRegisteredQueue:        userRegisteredQueue,
+		nameLimits:                 nameLimits,
+		logger:                     logger,
+	}
+}
+
+// CreateUser creates an active user with a generated temporary password, which is returned along with the user
+// and never stored in clear. The same checks as a registration apply. actor identifies the administrator who
+// requested it.
+func (s *UserProvisioningService) CreateUser(ctx context.Context, email, name string, idCitizen int, actor string) (*domain.User, string, error) {
+	if _, ok := domain.NormalizeEmail(email); !ok {
+		return nil, "", domainerrors.ErrInvalidEmail
+	}
+	if _, err := s.nameLimits.NormalizeName(name); err != nil {
+		return nil, "", err
+	}
+
+	citizenExists, err := s.externalConnectivityClient.CheckCitizenExists(ctx, idCitizen)
+	if err != nil {
+		s.logger.Error("failed to check citizen in centralizer", zap.Error(err), zap.Int("id_citizen", idCitizen))
+		if errors.Is(err, domainerrors.ErrCentralizerBusy) {
+			return nil, "", err
+		}
+		return nil, "", internalError(err)
+	}
+	if citizenExists {
+		return nil, "", domainerrors.ErrCitizenExistsInCentralizer
+	}
+
+	exists, err := s.userRepo.Exists(ctx, email)
+	if err != nil {
+		s.logger.Error("failed to check user existence", zap.Error(err))
+		return nil, "", internalError(err)
+	}
+	if exists {
+		return nil, "", domainerrors.ErrUserAlreadyExists
+	}
+
+	password, err := generateTemporaryPassword()
+	if err != nil {
+		s.logger.Error("failed to generate temporary password", zap.Error(err))
+		return nil, "", internalError(err)
+	}
+
+	user, err := domain.NewUserWithHasher(email, password, name, idCitizen, s.nameLimits, func(password string) (string, error) {
+		return s.passwordHasher.Hash(ctx, password)
+	})
+	if err != nil {
+		s.logger.Debug("invalid user", zap.Error(err))
+		return nil, "", domainerrors.ErrBadRequest
+	}
+	user.MustChangePassword = true
+
+	// The unique constraints also reject a user with the same citizen ID
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		if errors.Is(err, domainerrors.ErrUserAlreadyExists) {
+			return nil, "", err
+		}
+		s.logger.Error("failed to save user", zap.Error(err), zap.Int("id_citizen", idCitizen))
+		return nil, "", internalError(err)
+	}
+
+	record := domain.NewAuditRecord(domain.AuditActionUserCreated, actor, user.ID, map[string]string{
+		"id_citizen": strconv.Itoa(user.IDCitizen),
+		"email":      user.Email,
+	})
+	if err := s.auditRepo.Record(ctx, record); err != nil {
+		s.logger.Error("failed to write audit record", zap.Error(err), zap.String("user_id", user.ID), zap.String("actor", actor))
+	}
+
+	// Consumers learn about the user like about any registration
+	event := events.NewUserRegisteredEvent(user)
+	if eventData, err := event.ToJSON(); err != nil {
+		s.logger.Error("failed to serialize user registered event", zap.Error(err))
+	} else if err := s.publisher.Publish(ctx, s.userRegisteredQueue, eventData); err != nil {
+		s.logger.Error("failed to publish user registered event", zap.Error(err), zap.String("user_id", user.ID))
+	}
+
+	s.logger.Info("user created with a temporary password",
+		zap.String("user_id", user.ID), zap.Int("id_citizen", user.IDCitizen), zap.String("actor", actor))
+	return user, password, nil
+}
+
+// ChangeTemporaryPassword replaces the temporary password of a user created by an administrator, after which
+// the user signs in as usual. The temporary password authenticates the request, a wrong one is rejected like
+// a failed login.
+func (s *UserProvisioningService) ChangeTemporaryPassword(ctx context.Context, email, temporaryPassword, newPassword string) error {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrUserNotFound) {
+			return domainerrors.ErrInvalidCredentials
+		}
+		s.logger.Error("failed to get user", zap.Error(err))
+		return internalError(err)
+	}
+	if user.IsServiceAccount() {
+		return domainerrors.ErrInvalidCredentials
+	}
+
+	match, err := s.passwordHasher.Compare(ctx, user.Password, temporaryPassword)
+	if err != nil {
+		s.logger.Error("failed to compare password", zap.Error(err))
+		return internalError(err)
+	}
+	if !match {
+		s.logger.Warn("temporary password change failed: invalid password", zap.String("user_id", user.ID))
+		return domainerrors.ErrInvalidCredentials
+	}
+
+	if !user.MustChangePassword {
+		return domainerrors.ErrPasswordChangeNotRequired
+	}
+	if len(newPassword) < domain.MinPasswordLength || newPassword == temporaryPassword {
+		return domainerrors.ErrWeakPassword
+	}
+
+	hash, err := s.passwordHasher.Hash(ctx, newPassword)
+	if err != nil {
+		s.logger.Error("failed to hash password", zap.Error(err))
+		return internalError(err)
+	}
+
+	user.SetPassword(hash, time.Now())
+	user.MustChangePassword = false
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		s.logger.Error("failed to update user password", zap.Error(err), zap.String("user_id", user.ID))
+		return internalError(err)
+	}
+
+	s.logger.Info("temporary password changed", zap.String("user_id", user.ID))
+	return nil
+}
+
+// generateTemporaryPassword generates a random password that is easy to read out and type
+func generateTemporaryPassword() (string, error) {
+	var sb strings.Builder
+	alphabetSize := big.NewInt(int64(len(temporaryPasswordAlphabet)))
+	for i := 0; i < temporaryPasswordLength; i++ {
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate temporary password: %w", err)
+		}
+		sb.WriteByte(temporaryPasswordAlphabet[n.Int64()])
+	}
+	return sb.String(), nil
+}
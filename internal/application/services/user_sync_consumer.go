@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	"github.com/kristianrpo/auth-microservice/internal/domain/events"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// UserSyncConsumer applies the user.updated and user.role_changed events of the citizen registry.
+// Role changes are applied by the RoleService, which revokes the tokens of the user.
+// Each message is processed once. Conflicts are acknowledged without changes: users already gone or
+// anonymized, and emails already used by another user. Transient repository errors are returned so
+// the message is requeued.
+type UserSyncConsumer struct {
+	userRepo         ports.UserRepository
+	roleService      *RoleService
+	auditRepo        ports.AuditLogRepository
+	processedRepo    ports.ProcessedMessageRepository
+	userUpdatedQueue string
+	roleChangedQueue string
+	nameLimits       domain.NameLimits
+	logger           *zap.Logger
+}
+
+// NewUserSyncConsumer creates a new instance of UserSyncConsumer
+func NewUserSyncConsumer(
+	userRepo ports.UserRepository,
+	roleService *RoleService,
+	auditRepo ports.AuditLogRepository,
+	processedRepo ports.ProcessedMessageRepository,
+	userUpdatedQueue string,
+	roleChangedQueue string,
+	nameLimits domain.NameLimits,
+	logger *zap.Logger,
+) *UserSyncConsumer {
+	return &UserSyncConsumer{
+		userRepo:         userRepo,
+		roleService:      roleService,
+		auditRepo:        auditRepo,
+		processedRepo:    processedRepo,
+		userUpdatedQueue: userUpdatedQueue,
+		roleChangedQueue: roleChangedQueue,
+		nameLimits:       nameLimits,
+		logger:           logger,
+	}
+}
+
+// HandleUserUpdated processes a user.updated message, it implements ports.MessageHandler
+func (c *UserSyncConsumer) HandleUserUpdated(ctx context.Context, message []byte) error {
+	event, err := events.ParseUserUpdatedEvent(message)
+	if err != nil {
+		c.logger.Error("failed to parse user.updated payload", zap.Error(err))
+		return nil // ACK malformed messages to avoid infinite loop
+	}
+
+	return processOnce(ctx, c.processedRepo, c.userUpdatedQueue, event.DeduplicationKey(message), c.logger, func(ctx context.Context) error {
+		user, err := c.getSyncableUser(ctx, event.IDCitizen, "user.updated")
+		if err != nil || user == nil {
+			return err
+		}
+
+		var changed []string
+		if event.Email != "" && !strings.EqualFold(event.Email, user.Email) {
+			user.Email = domain.CanonicalEmail(event.Email)
+			changed = append(changed, "email")
+		}
+		if event.Name != "" {
+			// The rest of the event still applies when the name is rejected
+			name, err := c.nameLimits.NormalizeName(event.Name)
+			if err != nil {
+				c.logger.Warn("user.updated carries an invalid name, keeping the current one",
+					zap.Error(err), zap.String("user_id", user.ID))
+			} else if name != user.Name {
+				user.Name = name
+				changed = append(changed, "name")
+			}
+		}
+		if len(changed) == 0 {
+			return nil
+		}
+
+		if err := c.userRepo.Update(ctx, user); err != nil {
+			if errors.Is(err, domainerrors.ErrUserAlreadyExists) {
+				c.logger.Warn("user.updated conflicts with another user, skipping",
+					zap.String("user_id", user.ID), zap.Int("idCitizen", event.IDCitizen))
+				return nil
+			}
+			if errors.Is(err, domainerrors.ErrUserNotFound) {
+				return nil
+			}
+			return fmt.Errorf("failed to update user: %w", err)
+		}
+
+		c.recordAudit(ctx, domain.AuditActionUserUpdated, c.userUpdatedQueue, user, map[string]string{
+			"fields": strings.Join(changed, ","),
+		})
+		c.logger.Info("user synchronized from user.updated event", zap.String("user_id", user.ID), zap.Strings("fields", changed))
+		return nil
+	})
+}
+
+// HandleUserRoleChanged processes a user.role_changed message, it implements ports.MessageHandler.
+// The tokens of the user are revoked so the new role applies from the next login.
+func (c *UserSyncConsumer) HandleUserRoleChanged(ctx context.Context, message []byte) error {
+	event, err := events.ParseUserRoleChangedEvent(message)
+	if err != nil {
+		c.logger.Error("failed to parse user.role_changed payload", zap.Error(err))
+		return nil // ACK malformed messages to avoid infinite loop
+	}
+
+	return processOnce(ctx, c.processedRepo, c.roleChangedQueue, event.DeduplicationKey(message), c.logger, func(ctx context.Context) error {
+		user, err := c.getSyncableUser(ctx, event.IDCitizen, "user.role_changed")
+		if err != nil || user == nil {
+			return err
+		}
+		if err := c.roleService.changeUserRole(ctx, user, event.Role, "event:"+c.roleChangedQueue); err != nil {
+			if errors.Is(err, domainerrors.ErrUserNotFound) {
+				return nil
+			}
+			return fmt.Errorf("failed to change user role: %w", err)
+		}
+		return nil
+	})
+}
+
+// getSyncableUser returns the user of the citizen, or nil when the event must be skipped
+// because the user is gone or anonymized
+func (c *UserSyncConsumer) getSyncableUser(ctx context.Context, idCitizen int, eventType string) (*domain.User, error) {
+	user, err := c.userRepo.GetByIDCitizen(ctx, idCitizen)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrUserNotFound) {
+			c.logger.Info("user not found, skipping event", zap.String("event", eventType), zap.Int("idCitizen", idCitizen))
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if user.IsAnonymized() {
+		c.logger.Info("user is anonymized, skipping event", zap.String("event", eventType), zap.String("user_id", user.ID))
+		return nil, nil
+	}
+	return user, nil
+}
+
+// recordAudit writes the audit record of a synchronized change (best effort)
+func (c *UserSyncConsumer) recordAudit(ctx context.Context, action domain.AuditAction, queue string, user *domain.User, details map[string]string) {
+	details["id_citizen"] = strconv.Itoa(user.IDCitizen)
+	record := domain.NewAuditRecord(action, "event:"+queue, user.ID, details)
+	if err := c.auditRepo.Record(ctx, record); err != nil {
+		c.logger.Error("failed to write audit record", zap.Error(err), zap.String("user_id", user.ID), zap.String("action", action.String()))
+	}
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	"github.com/kristianrpo/auth-microservice/internal/domain/events"
+)
+
+// UserTransferredConsumer removes the users of the citizens transferred to another operator, along with
+// their refresh tokens. Their outstanding access tokens are revoked through the token version.
+// Each message is processed once: redeliveries are detected through the processed message repository.
+// Malformed messages and users already gone are acknowledged, while transient repository errors are
+// returned so the message is requeued.
+type UserTransferredConsumer struct {
+	userRepo            ports.UserRepository
+	tokenRepo           ports.TokenRepository
+	processedRepo       ports.ProcessedMessageRepository
+	queue               string
+	accessTokenDuration time.Duration
+	logger              *zap.Logger
+}
+
+// NewUserTransferredConsumer creates a new instance of UserTransferredConsumer.
+// accessTokenDuration is the lifetime of the access tokens, during which the revoked ones are rejected.
+func NewUserTransferredConsumer(
+	userRepo ports.UserRepository,
+	tokenRepo ports.TokenRepository,
+	processedRepo ports.ProcessedMessageRepository,
+	queue string,
+	accessTokenDuration time.Duration,
+	logger *zap.Logger,
+) *UserTransferredConsumer {
+	return &UserTransferredConsumer{
+		userRepo:            userRepo,
+		tokenRepo:           tokenRepo,
+		processedRepo:       processedRepo,
+		queue:               queue,
+		accessTokenDuration: accessTokenDuration,
+		logger:              logger,
+	}
+}
+
+// Handle processes a user.transferred message, it implements ports.MessageHandler
+func (c *UserTransferredConsumer) Handle(ctx context.Context, message []byte) error {
+	event, err := events.ParseUserTransferredEvent(message)
+	if err != nil {
+		c.logger.Error("failed to unmarshal user.transferred payload", zap.Error(err))
+		return nil // ACK malformed messages to avoid infinite loop
+	}
+
+	return processOnce(ctx, c.processedRepo, c.queue, event.DeduplicationKey(message), c.logger, func(ctx context.Context) error {
+		return c.removeUser(ctx, event)
+	})
+}
+
+// removeUser deletes the user of the transferred citizen and their tokens
+func (c *UserTransferredConsumer) removeUser(ctx context.Context, event *events.UserTransferredEvent) error {
+	user, err := c.userRepo.GetByIDCitizen(ctx, event.IDCitizen)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrUserNotFound) {
+			// If user doesn't exist, that's fine - they're already gone
+			c.logger.Info("user not found or already deleted", zap.Int("idCitizen", event.IDCitizen))
+			return nil
+		}
+		return fmt.Errorf("failed to get transferred user: %w", err)
+	}
+
+	// Tokens go first so a retry after a failed user deletion still finds the user
+	if err := c.tokenRepo.DeleteUserTokens(ctx, user.ID, user.IDCitizen); err != nil {
+		return fmt.Errorf("failed to delete tokens of transferred user: %w", err)
+	}
+	if err := c.tokenRepo.SetTokenVersion(ctx, user.IDCitizen, user.TokenVersion+1, c.accessTokenDuration); err != nil {
+		return fmt.Errorf("failed to revoke access tokens of transferred user: %w", err)
+	}
+
+	if err := c.userRepo.Delete(ctx, user.ID); err != nil {
+		if errors.Is(err, domainerrors.ErrUserNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to delete transferred user: %w", err)
+	}
+
+	c.logger.Info("successfully processed user.transferred event", zap.Int("idCitizen", event.IDCitizen), zap.String("user_id", user.ID))
+	return nil
+}
@@ -0,0 +1,18 @@
+package services
+
+import (
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+)
+
+// checkExpectedVersion rejects a change requested for another version of a resource than the current one,
+// e.g. an administrator saving a form loaded before someone else changed the resource. The expected version
+// is required, a change made without one could overwrite changes the caller never saw.
+func checkExpectedVersion(expectedVersion, version int) error {
+	if expectedVersion < 1 {
+		return domainerrors.ErrVersionRequired
+	}
+	if expectedVersion != version {
+		return domainerrors.ErrVersionConflict
+	}
+	return nil
+}
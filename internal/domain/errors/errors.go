@@ -10,8 +10,15 @@ var (
 	ErrCitizenExistsInCentralizer = errors.New("citizen already exists in centralizer")
 	ErrInvalidEmail            = errors.New("invalid email format")
 	ErrWeakPassword            = errors.New("password is too weak")
+	ErrInvalidName             = errors.New("invalid name")
 	ErrClientNotFound          = errors.New("oauth client not found")
 	ErrInvalidClient           = errors.New("invalid oauth client")
+	ErrUserSuspended           = errors.New("user account is suspended")
+	ErrAuthenticationDenied    = errors.New("authentication denied by risk policy")
+	ErrUserAlreadyAnonymized   = errors.New("user is already anonymized")
+	ErrInvalidUserMerge        = errors.New("users can't be merged")
+	ErrPasswordChangeRequired  = errors.New("password must be changed before signing in")
+	ErrPasswordChangeNotRequired = errors.New("password change is not required")
 )
 
 // Token errors
@@ -20,6 +27,131 @@ var (
 	ErrExpiredToken     = errors.New("token has expired")
 	ErrTokenRevoked     = errors.New("token has been revoked")
 	ErrInvalidTokenType = errors.New("invalid token type")
+	ErrCertificateMismatch = errors.New("token is bound to another client certificate")
+)
+
+// OAuth2 grant errors
+var (
+	ErrUnsupportedGrantType = errors.New("unsupported grant type")
+	ErrInvalidGrant         = errors.New("invalid authorization grant")
+	ErrUnauthorizedClient   = errors.New("client is not authorized to use this grant type")
+	ErrInvalidRedirectURI   = errors.New("invalid redirect uri")
+	ErrRedirectURINotFound  = errors.New("redirect uri is not registered")
+	ErrAccessTokenTooLarge  = errors.New("access token would exceed the maximum token size")
+	ErrClientLockedOut      = errors.New("client is locked out after repeated authentication failures")
+	ErrClientSecretExpired  = errors.New("client secret has expired")
+)
+
+// Scope errors
+var (
+	ErrScopeNotFound      = errors.New("scope not found")
+	ErrScopeAlreadyExists = errors.New("scope already exists")
+	ErrInvalidScopeName   = errors.New("invalid scope name")
+	ErrUnknownScope       = errors.New("scope is not registered")
+	ErrSystemScope        = errors.New("system scopes cannot be modified or deleted")
+	ErrScopeInUse         = errors.New("scope is assigned to one or more clients")
+)
+
+// Consent errors
+var (
+	ErrConsentNotFound = errors.New("consent not found")
+)
+
+// Device authorization errors
+var (
+	ErrAuthorizationPending = errors.New("authorization pending")
+	ErrSlowDown             = errors.New("polling too frequently, slow down")
+	ErrAccessDenied         = errors.New("access denied by user")
+	ErrDeviceCodeExpired    = errors.New("device code is invalid or has expired")
+	ErrInvalidUserCode      = errors.New("invalid or expired user code")
+)
+
+// Notification errors
+var (
+	ErrPreferencesNotFound = errors.New("notification preferences not found")
+)
+
+// Phone number and SMS errors
+var (
+	ErrInvalidPhoneNumber      = errors.New("invalid phone number")
+	ErrPhoneNotFound           = errors.New("phone number not found")
+	ErrInvalidVerificationCode = errors.New("invalid or expired verification code")
+	ErrSMSRateLimited          = errors.New("too many SMS sent to this phone number")
+	ErrSMSQuotaExceeded        = errors.New("SMS sending quota exceeded")
+	ErrSMSDeliveryFailed       = errors.New("failed to deliver SMS")
+	ErrPhoneAlreadyRegistered  = errors.New("phone number already registered")
+	ErrPhoneLoginDisabled      = errors.New("phone number login is disabled")
+)
+
+// Secondary email and password reset errors
+var (
+	ErrEmailNotFound          = errors.New("email address not found")
+	ErrEmailAlreadyRegistered = errors.New("email address is already registered")
+	ErrTooManyEmails          = errors.New("maximum number of email addresses reached")
+	ErrEmailRateLimited       = errors.New("too many emails sent to this address")
+	ErrInvalidResetToken      = errors.New("invalid or expired password reset token")
+	ErrInvalidEmailChangeToken = errors.New("invalid or expired email change token")
+)
+
+// Avatar errors
+var (
+	ErrAvatarNotFound        = errors.New("avatar not found")
+	ErrAvatarTooLarge        = errors.New("avatar exceeds the maximum size")
+	ErrUnsupportedAvatarType = errors.New("unsupported avatar content type")
+)
+
+// User metadata errors
+var (
+	ErrInvalidUserMetadata    = errors.New("invalid user metadata")
+	ErrUserMetadataTooLarge   = errors.New("user metadata exceeds the maximum size")
+	ErrMetadataKeyNotWritable = errors.New("metadata key cannot be changed by the user")
+)
+
+// Geolocation errors
+var (
+	ErrLocationNotFound = errors.New("location not found for IP address")
+)
+
+// Quota errors
+var (
+	ErrTokenQuotaExceeded   = errors.New("token issuance quota exceeded")
+	ErrSessionQuotaExceeded = errors.New("maximum number of active sessions reached")
+	ErrQuotaNotFound        = errors.New("quota not found")
+	ErrInvalidQuota         = errors.New("invalid quota")
+)
+
+// Signed request errors
+var (
+	ErrSignedRequestRequired   = errors.New("signed request required")
+	ErrInvalidRequestSignature = errors.New("invalid request signature")
+	ErrStaleRequest            = errors.New("request timestamp outside the allowed window")
+	ErrReplayedRequest         = errors.New("request nonce already used")
+)
+
+// Export errors
+var (
+	ErrInvalidExportFilter = errors.New("invalid export filter")
+	ErrExportJobNotFound   = errors.New("export job not found")
+	ErrExportsBusy         = errors.New("too many exports in progress")
+)
+
+// Registration approval errors
+var (
+	ErrUserPendingApproval    = errors.New("user registration is pending approval")
+	ErrUserRejected           = errors.New("user registration was rejected")
+	ErrUserNotPendingApproval = errors.New("user registration is not pending approval")
+)
+
+// External connectivity errors
+var (
+	ErrCentralizerBusy = errors.New("too many concurrent calls to the centralizer")
+)
+
+// Database errors
+var (
+	ErrDatabaseBusy    = errors.New("timed out waiting for a database connection")
+	ErrVersionConflict = errors.New("the resource was modified concurrently")
+	ErrVersionRequired = errors.New("the version of the resource to change is required")
 )
 
 // Generic errors
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// ClientLockedOutError is returned while an OAuth2 client is locked out after repeated authentication
+// failures. It wraps ErrClientLockedOut and tells the caller when the lockout ends.
+type ClientLockedOutError struct {
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface
+func (e *ClientLockedOutError) Error() string {
+	return fmt.Sprintf("%v, retry in %d seconds", ErrClientLockedOut, e.RetryAfterSeconds())
+}
+
+// Unwrap returns the lockout error sentinel
+func (e *ClientLockedOutError) Unwrap() error {
+	return ErrClientLockedOut
+}
+
+// RetryAfterSeconds returns the whole seconds until the lockout ends, rounded up
+func (e *ClientLockedOutError) RetryAfterSeconds() int64 {
+	return retrySeconds(e.RetryAfter)
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// QuotaExceededError is returned when an issuance quota is exhausted. It wraps ErrTokenQuotaExceeded
+// or ErrSessionQuotaExceeded and tells the caller which limit was reached.
+type QuotaExceededError struct {
+	Err        error
+	Subject    string        // "client" or "user"
+	Limit      int           // tokens per hour or active sessions
+	RetryAfter time.Duration // time until the hourly window resets, zero for the session quota
+}
+
+// Error implements the error interface
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("%v: %s", e.Err, e.Details())
+}
+
+// Unwrap returns the quota error sentinel
+func (e *QuotaExceededError) Unwrap() error {
+	return e.Err
+}
+
+// Details describes the limit reached, safe to return to the caller
+func (e *QuotaExceededError) Details() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%s quota of %d tokens per hour reached, retry in %d seconds", e.Subject, e.Limit, retrySeconds(e.RetryAfter))
+	}
+	return fmt.Sprintf("%s quota of %d active sessions reached, log out from another session first", e.Subject, e.Limit)
+}
+
+// RetryAfterSeconds returns the whole seconds to wait before retrying, rounded up
+func (e *QuotaExceededError) RetryAfterSeconds() int64 {
+	return retrySeconds(e.RetryAfter)
+}
+
+func retrySeconds(d time.Duration) int64 {
+	if d <= 0 {
+		return 0
+	}
+	return int64((d + time.Second - 1) / time.Second)
+}
@@ -0,0 +1,41 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// ClientSecretExpiringEvent represents the reminder published while the secret of an OAuth client is about
+// to expire or has expired, so its owners rotate it. The tags of the client identify its owners.
+type ClientSecretExpiringEvent struct {
+	MessageID       string    `json:"messageId"`
+	ClientID        string    `json:"clientId"`
+	Name            string    `json:"name"`
+	Tags            []string  `json:"tags"`
+	SecretExpiresAt time.Time `json:"secretExpiresAt"`
+	Expired         bool      `json:"expired"`
+	Timestamp       time.Time `json:"timestamp"`
+}
+
+// NewClientSecretExpiringEvent creates a new ClientSecretExpiringEvent with a unique message ID for a client
+// whose secret has an expiry
+func NewClientSecretExpiringEvent(client *domain.OAuthClient, now time.Time) *ClientSecretExpiringEvent {
+	return &ClientSecretExpiringEvent{
+		MessageID:       uuid.New().String(),
+		ClientID:        client.ClientID,
+		Name:            client.Name,
+		Tags:            client.Tags,
+		SecretExpiresAt: *client.SecretExpiresAt,
+		Expired:         client.SecretExpired(now),
+		Timestamp:       now,
+	}
+}
+
+// ToJSON converts the event to JSON bytes
+func (e *ClientSecretExpiringEvent) ToJSON() ([]byte, error) {
+	return json.Marshal(e)
+}
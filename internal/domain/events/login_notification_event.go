@@ -0,0 +1,52 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// LoginNotificationEvent represents a login from a device or IP address the user did not use recently.
+// The device and the address are redacted: only the browser, the operating system and the network are sent.
+type LoginNotificationEvent struct {
+	MessageID string    `json:"messageId"`
+	UserID    string    `json:"userId"`
+	IDCitizen int       `json:"idCitizen"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	NewDevice bool      `json:"newDevice"`
+	NewIP     bool      `json:"newIp"`
+	Device    string    `json:"device"`
+	Network   string    `json:"network,omitempty"`
+	Country   string    `json:"country,omitempty"`
+	City      string    `json:"city,omitempty"`
+	LoginAt   time.Time `json:"loginAt"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// NewLoginNotificationEvent creates a new LoginNotificationEvent with a unique message ID
+func NewLoginNotificationEvent(user *domain.User, notification *domain.LoginNotification) *LoginNotificationEvent {
+	return &LoginNotificationEvent{
+		MessageID: uuid.New().String(),
+		UserID:    user.ID,
+		IDCitizen: user.IDCitizen,
+		Name:      user.Name,
+		Email:     user.Email,
+		NewDevice: notification.NewDevice,
+		NewIP:     notification.NewIP,
+		Device:    notification.Device,
+		Network:   notification.Network,
+		Country:   notification.Country,
+		City:      notification.City,
+		LoginAt:   notification.At,
+		Timestamp: time.Now(),
+	}
+}
+
+// ToJSON converts the event to JSON bytes
+func (e *LoginNotificationEvent) ToJSON() ([]byte, error) {
+	return json.Marshal(e)
+}
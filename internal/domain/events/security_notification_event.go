@@ -0,0 +1,37 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SecurityNotificationEvent represents a security notification to be delivered to a user
+type SecurityNotificationEvent struct {
+	MessageID string            `json:"messageId"`
+	Type      string            `json:"type"`
+	IDCitizen int               `json:"idCitizen"`
+	Name      string            `json:"name"`
+	Email     string            `json:"email"`
+	Details   map[string]string `json:"details,omitempty"`
+	Timestamp time.Time         `json:"timestamp"`
+}
+
+// NewSecurityNotificationEvent creates a new SecurityNotificationEvent with a unique message ID
+func NewSecurityNotificationEvent(notificationType string, idCitizen int, name, email string, details map[string]string) *SecurityNotificationEvent {
+	return &SecurityNotificationEvent{
+		MessageID: uuid.New().String(),
+		Type:      notificationType,
+		IDCitizen: idCitizen,
+		Name:      name,
+		Email:     email,
+		Details:   details,
+		Timestamp: time.Now(),
+	}
+}
+
+// ToJSON converts the event to JSON bytes
+func (e *SecurityNotificationEvent) ToJSON() ([]byte, error) {
+	return json.Marshal(e)
+}
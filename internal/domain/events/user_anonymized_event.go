@@ -0,0 +1,32 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserAnonymizedEvent represents the event published when the personal data of a user is erased,
+// so other services can erase their copies
+type UserAnonymizedEvent struct {
+	MessageID string    `json:"messageId"`
+	UserID    string    `json:"userId"`
+	IDCitizen int       `json:"idCitizen"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// NewUserAnonymizedEvent creates a new UserAnonymizedEvent with a unique message ID
+func NewUserAnonymizedEvent(userID string, idCitizen int) *UserAnonymizedEvent {
+	return &UserAnonymizedEvent{
+		MessageID: uuid.New().String(),
+		UserID:    userID,
+		IDCitizen: idCitizen,
+		Timestamp: time.Now(),
+	}
+}
+
+// ToJSON converts the event to JSON bytes
+func (e *UserAnonymizedEvent) ToJSON() ([]byte, error) {
+	return json.Marshal(e)
+}
@@ -0,0 +1,36 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserMergedEvent represents the event published when a duplicate account is merged into the surviving
+// one, so other services can move their data of the duplicate to the surviving account
+type UserMergedEvent struct {
+	MessageID       string    `json:"messageId"`
+	UserID          string    `json:"userId"`
+	IDCitizen       int       `json:"idCitizen"`
+	MergedUserID    string    `json:"mergedUserId"`
+	MergedIDCitizen int       `json:"mergedIdCitizen"`
+	Timestamp       time.Time `json:"timestamp"`
+}
+
+// NewUserMergedEvent creates a new UserMergedEvent with a unique message ID
+func NewUserMergedEvent(userID string, idCitizen int, mergedUserID string, mergedIDCitizen int) *UserMergedEvent {
+	return &UserMergedEvent{
+		MessageID:       uuid.New().String(),
+		UserID:          userID,
+		IDCitizen:       idCitizen,
+		MergedUserID:    mergedUserID,
+		MergedIDCitizen: mergedIDCitizen,
+		Timestamp:       time.Now(),
+	}
+}
+
+// ToJSON converts the event to JSON bytes
+func (e *UserMergedEvent) ToJSON() ([]byte, error) {
+	return json.Marshal(e)
+}
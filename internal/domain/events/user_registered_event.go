@@ -5,24 +5,31 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
 )
 
-// UserRegisteredEvent represents the event published when a user registers
+// UserRegisteredEvent represents the event published when a user registers.
+// Status is PENDING_APPROVAL when registrations wait for an administrator.
 type UserRegisteredEvent struct {
-	MessageID string    `json:"messageId"`
-	IDCitizen int       `json:"idCitizen"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	Timestamp time.Time `json:"timestamp"`
+	MessageID string      `json:"messageId"`
+	IDCitizen int         `json:"idCitizen"`
+	Name      string      `json:"name"`
+	Email     string      `json:"email"`
+	Role      domain.Role `json:"role"`
+	Status    string      `json:"status"`
+	Timestamp time.Time   `json:"timestamp"`
 }
 
-// NewUserRegisteredEvent creates a new UserRegisteredEvent with a unique message ID
-func NewUserRegisteredEvent(idCitizen int, name, email string) *UserRegisteredEvent {
+// NewUserRegisteredEvent creates the UserRegisteredEvent of a new user with a unique message ID
+func NewUserRegisteredEvent(user *domain.User) *UserRegisteredEvent {
 	return &UserRegisteredEvent{
 		MessageID: uuid.New().String(),
-		IDCitizen: idCitizen,
-		Name:      name,
-		Email:     email,
+		IDCitizen: user.IDCitizen,
+		Name:      user.Name,
+		Email:     user.Email,
+		Role:      user.Role,
+		Status:    user.Status.String(),
 		Timestamp: time.Now(),
 	}
 }
@@ -0,0 +1,61 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// UserRoleChangedEvent represents the event consumed when the role of a citizen changes.
+// The same event is published once the role change is applied, with the user ID and the previous role.
+type UserRoleChangedEvent struct {
+	MessageID    string      `json:"messageId,omitempty"`
+	IDCitizen    int         `json:"idCitizen"`
+	Role         domain.Role `json:"role"`
+	UserID       string      `json:"userId,omitempty"`
+	PreviousRole domain.Role `json:"previousRole,omitempty"`
+	Timestamp    time.Time   `json:"timestamp,omitzero"`
+}
+
+// NewUserRoleChangedEvent creates a new UserRoleChangedEvent with a unique message ID
+func NewUserRoleChangedEvent(userID string, idCitizen int, previousRole, role domain.Role) *UserRoleChangedEvent {
+	return &UserRoleChangedEvent{
+		MessageID:    uuid.New().String(),
+		IDCitizen:    idCitizen,
+		Role:         role,
+		UserID:       userID,
+		PreviousRole: previousRole,
+		Timestamp:    time.Now(),
+	}
+}
+
+// ParseUserRoleChangedEvent parses and validates a user.role_changed message
+func ParseUserRoleChangedEvent(message []byte) (*UserRoleChangedEvent, error) {
+	var event UserRoleChangedEvent
+	if err := json.Unmarshal(message, &event); err != nil {
+		return nil, fmt.Errorf("invalid user.role_changed event: %w", err)
+	}
+
+	if event.IDCitizen <= 0 {
+		return nil, errors.New("invalid user.role_changed event: idCitizen is required")
+	}
+	if !event.Role.IsValid() {
+		return nil, fmt.Errorf("invalid user.role_changed event: unknown role %q", event.Role)
+	}
+	return &event, nil
+}
+
+// DeduplicationKey identifies the event across redeliveries
+func (e *UserRoleChangedEvent) DeduplicationKey(message []byte) string {
+	return deduplicationKey(e.MessageID, e.IDCitizen, message)
+}
+
+// ToJSON converts the event to JSON bytes
+func (e *UserRoleChangedEvent) ToJSON() ([]byte, error) {
+	return json.Marshal(e)
+}
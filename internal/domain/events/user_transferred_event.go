@@ -0,0 +1,39 @@
+package events
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// UserTransferredEvent represents the event consumed when a citizen is transferred to another operator,
+// whose user must be removed from this service
+type UserTransferredEvent struct {
+	MessageID string `json:"messageId,omitempty"`
+	IDCitizen int    `json:"idCitizen"`
+}
+
+// ParseUserTransferredEvent parses a user.transferred message
+func ParseUserTransferredEvent(message []byte) (*UserTransferredEvent, error) {
+	var event UserTransferredEvent
+	if err := json.Unmarshal(message, &event); err != nil {
+		return nil, fmt.Errorf("invalid user.transferred event: %w", err)
+	}
+	return &event, nil
+}
+
+// DeduplicationKey identifies the event across redeliveries
+func (e *UserTransferredEvent) DeduplicationKey(message []byte) string {
+	return deduplicationKey(e.MessageID, e.IDCitizen, message)
+}
+
+// deduplicationKey identifies a consumed event across redeliveries: its message ID when the producer
+// sets one, otherwise the citizen along with a hash of the message
+func deduplicationKey(messageID string, idCitizen int, message []byte) string {
+	if messageID != "" {
+		return messageID
+	}
+	sum := sha256.Sum256(message)
+	return fmt.Sprintf("%d:%s", idCitizen, hex.EncodeToString(sum[:16]))
+}
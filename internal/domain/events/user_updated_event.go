@@ -0,0 +1,46 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+)
+
+// UserUpdatedEvent represents the event consumed when the citizen registry changes the profile of a citizen.
+// Empty fields are left unchanged.
+type UserUpdatedEvent struct {
+	MessageID string `json:"messageId,omitempty"`
+	IDCitizen int    `json:"idCitizen"`
+	Email     string `json:"email,omitempty"`
+	Name      string `json:"name,omitempty"`
+}
+
+// ParseUserUpdatedEvent parses and validates a user.updated message
+func ParseUserUpdatedEvent(message []byte) (*UserUpdatedEvent, error) {
+	var event UserUpdatedEvent
+	if err := json.Unmarshal(message, &event); err != nil {
+		return nil, fmt.Errorf("invalid user.updated event: %w", err)
+	}
+
+	event.Email = strings.TrimSpace(event.Email)
+	event.Name = strings.TrimSpace(event.Name)
+	if event.IDCitizen <= 0 {
+		return nil, errors.New("invalid user.updated event: idCitizen is required")
+	}
+	if event.Email == "" && event.Name == "" {
+		return nil, errors.New("invalid user.updated event: email or name is required")
+	}
+	if event.Email != "" {
+		if _, err := mail.ParseAddress(event.Email); err != nil {
+			return nil, fmt.Errorf("invalid user.updated event: invalid email: %w", err)
+		}
+	}
+	return &event, nil
+}
+
+// DeduplicationKey identifies the event across redeliveries
+func (e *UserUpdatedEvent) DeduplicationKey(message []byte) string {
+	return deduplicationKey(e.MessageID, e.IDCitizen, message)
+}
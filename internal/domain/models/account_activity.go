@@ -0,0 +1,11 @@
+package domain
+
+import "time"
+
+// AccountActivity summarizes the security state of an account, for the security panels of client apps
+type AccountActivity struct {
+	LastLoginAt         *time.Time // nil when the user never signed in since logins are recorded
+	ActiveSessionsCount int
+	MFAEnabled          bool       // always false, the service has no second factor yet
+	PasswordChangedAt   *time.Time // nil for users created before password changes were recorded
+}
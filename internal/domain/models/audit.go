@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditAction identifies an administrative or security-relevant operation
+type AuditAction string
+
+const (
+	// AuditActionUserAnonymized is recorded when the personal data of a user is erased
+	AuditActionUserAnonymized AuditAction = "user.anonymized"
+	// AuditActionUserMerged is recorded when a duplicate account is merged into the surviving one
+	AuditActionUserMerged AuditAction = "user.merged"
+	// AuditActionUserUpdated is recorded when the profile of a user is synchronized from the citizen registry
+	AuditActionUserUpdated AuditAction = "user.updated"
+	// AuditActionUserRoleChanged is recorded when the role of a user changes
+	AuditActionUserRoleChanged AuditAction = "user.role_changed"
+	// AuditActionRefreshAnomaly is recorded when the refresh activity of a session looks anomalous
+	AuditActionRefreshAnomaly AuditAction = "session.refresh_anomaly"
+	// AuditActionQuotaUpdated is recorded when the issuance quota of a client or user is set or reset
+	AuditActionQuotaUpdated AuditAction = "quota.updated"
+	// AuditActionDatasetExported is recorded when an administrator exports the users or the audit log
+	AuditActionDatasetExported AuditAction = "dataset.exported"
+	// AuditActionUserMetadataUpdated is recorded when an administrator changes the custom metadata of a user
+	AuditActionUserMetadataUpdated AuditAction = "user.metadata_updated"
+	// AuditActionUserApproved is recorded when an administrator approves a pending registration
+	AuditActionUserApproved AuditAction = "user.approved"
+	// AuditActionUserRejected is recorded when an administrator rejects a pending registration
+	AuditActionUserRejected AuditAction = "user.rejected"
+	// AuditActionSudoGranted is recorded when a user re-authenticates to obtain elevated access
+	AuditActionSudoGranted AuditAction = "session.sudo_granted"
+	// AuditActionUserCreated is recorded when an administrator creates a user with a temporary password
+	AuditActionUserCreated AuditAction = "user.created"
+	// AuditActionServiceAccountCreated is recorded when an administrator creates a service account
+	AuditActionServiceAccountCreated AuditAction = "user.service_account_created"
+	// AuditActionUserDataExported is recorded when the archive of the personal data a user requested is ready
+	AuditActionUserDataExported AuditAction = "user.data_exported"
+)
+
+// String returns the string representation of the action
+func (a AuditAction) String() string {
+	return string(a)
+}
+
+// AuditRecord is an entry of the audit log
+type AuditRecord struct {
+	ID        string            `json:"id"`
+	Action    AuditAction       `json:"action"`
+	Actor     string            `json:"actor"`     // Who performed the operation, e.g. "admin:12345", "service-account:67890" or "authctl:root"
+	TargetID  string            `json:"target_id"` // ID of the affected resource
+	Details   map[string]string `json:"details,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// NewAuditRecord creates a new audit record with a unique ID
+func NewAuditRecord(action AuditAction, actor, targetID string, details map[string]string) *AuditRecord {
+	return &AuditRecord{
+		ID:        uuid.New().String(),
+		Action:    action,
+		Actor:     actor,
+		TargetID:  targetID,
+		Details:   details,
+		CreatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AvatarKeyPrefix is the prefix of the storage keys of avatar objects
+const AvatarKeyPrefix = "avatars/"
+
+// avatarSignatures maps the supported image formats to their magic bytes and file extension
+var avatarSignatures = []struct {
+	contentType string
+	extension   string
+	matches     func(data []byte) bool
+}{
+	{"image/png", "png", func(data []byte) bool { return bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")) }},
+	{"image/jpeg", "jpg", func(data []byte) bool { return bytes.HasPrefix(data, []byte("\xff\xd8\xff")) }},
+	{"image/webp", "webp", func(data []byte) bool {
+		return len(data) >= 12 && bytes.Equal(data[:4], []byte("RIFF")) && bytes.Equal(data[8:12], []byte("WEBP"))
+	}},
+}
+
+// Avatar is the profile picture of a user. The image is kept in blob storage under ObjectKey, a new key
+// is used for every upload so cached URLs of the previous picture are not served stale.
+type Avatar struct {
+	UserID      string    `json:"user_id"`
+	ObjectKey   string    `json:"object_key"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// BlobObject describes an object kept in blob storage
+type BlobObject struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// DetectAvatarContentType returns the content type of a PNG, JPEG or WebP image from its magic bytes.
+// The content type declared by the client is not trusted.
+func DetectAvatarContentType(data []byte) (string, bool) {
+	for _, signature := range avatarSignatures {
+		if signature.matches(data) {
+			return signature.contentType, true
+		}
+	}
+	return "", false
+}
+
+// NewAvatarObjectKey returns a new storage key for an avatar of the user
+func NewAvatarObjectKey(userID, contentType string) string {
+	extension := "bin"
+	for _, signature := range avatarSignatures {
+		if signature.contentType == contentType {
+			extension = signature.extension
+		}
+	}
+	return fmt.Sprintf("%s%s/%s.%s", AvatarKeyPrefix, userID, uuid.New().String(), extension)
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "context"
+
+type cacheBypassContextKey struct{}
+
+// ContextWithCacheBypass returns a copy of ctx whose lookups skip the caches and read the source of truth
+func ContextWithCacheBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheBypassContextKey{}, true)
+}
+
+// CacheBypassFromContext returns true if the lookups of ctx must skip the caches
+func CacheBypassFromContext(ctx context.Context) bool {
+	bypass, _ := ctx.Value(cacheBypassContextKey{}).(bool)
+	return bypass
+}
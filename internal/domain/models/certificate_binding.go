@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+)
+
+// Confirmation is the cnf claim of a token bound to the TLS client certificate of the client it was
+// issued to (RFC 8705 section 3.1)
+type Confirmation struct {
+	// CertificateThumbprint is the x5t#S256 confirmation method, see CertificateThumbprint
+	CertificateThumbprint string `json:"x5t#S256"`
+}
+
+// CertificateThumbprint returns the SHA-256 thumbprint of the DER encoding of a certificate, base64url
+// encoded without padding
+func CertificateThumbprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// VerifyCertificateBinding checks that the certificate presented with a token is the one the token is bound
+// to. Resource servers call it with the certificate of the mTLS connection the token was received on, nil
+// without one. A token with no thumbprint is not bound and is accepted with any certificate.
+func VerifyCertificateBinding(thumbprint string, cert *x509.Certificate) error {
+	if thumbprint == "" {
+		return nil
+	}
+	if cert == nil || subtle.ConstantTimeCompare([]byte(CertificateThumbprint(cert)), []byte(thumbprint)) != 1 {
+		return domainerrors.ErrCertificateMismatch
+	}
+	return nil
+}
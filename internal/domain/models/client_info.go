@@ -0,0 +1,22 @@
+package domain
+
+import "context"
+
+// ClientInfo describes the client that sent the current request
+type ClientInfo struct {
+	IP        string `json:"ip"`
+	UserAgent string `json:"user_agent"`
+}
+
+type clientInfoContextKey struct{}
+
+// ContextWithClientInfo returns a copy of ctx carrying the client information
+func ContextWithClientInfo(ctx context.Context, info ClientInfo) context.Context {
+	return context.WithValue(ctx, clientInfoContextKey{}, info)
+}
+
+// ClientInfoFromContext returns the client information carried by ctx, if any
+func ClientInfoFromContext(ctx context.Context) (ClientInfo, bool) {
+	info, ok := ctx.Value(clientInfoContextKey{}).(ClientInfo)
+	return info, ok
+}
@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// ClientLockout is the state of the authentication failure lockout of an OAuth2 client
+type ClientLockout struct {
+	ClientID       string
+	RecentFailures int        // failed authentications within the failure window
+	LockedUntil    *time.Time // nil when the client is not locked out
+}
+
+// ClientAuthFailureRateLimitKey returns the key counting the recent failed authentications of a client
+func ClientAuthFailureRateLimitKey(clientID string) string {
+	return fmt.Sprintf("client_auth_failures:%s", clientID)
+}
+
+// ClientLockoutRateLimitKey returns the key of the lockout of a client, set for the cooldown
+func ClientLockoutRateLimitKey(clientID string) string {
+	return fmt.Sprintf("client_lockout:%s", clientID)
+}
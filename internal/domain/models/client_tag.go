@@ -0,0 +1,82 @@
+package domain
+
+import "strings"
+
+// MaxClientTags is the maximum number of tags of a client
+const MaxClientTags = 20
+
+// maxClientTagLength is the maximum length of a tag
+const maxClientTagLength = 64
+
+// Keys of the tags reported in the metrics of the client tokens. Only these keys are used as labels, so
+// the cardinality of the metrics does not grow with free-form tags.
+const (
+	ClientTagTeam = "team"
+	ClientTagEnv  = "env"
+)
+
+// ParseClientTags validates the tags of a client and returns them in lowercase without duplicates.
+// A tag is free-form, by convention a key:value pair like team:payments or env:staging, of letters, digits
+// and the characters : _ - . /
+func ParseClientTags(tags []string) ([]string, error) {
+	if len(tags) > MaxClientTags {
+		return nil, ErrValidation
+	}
+
+	parsed := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if !isValidClientTag(tag) {
+			return nil, ErrValidation
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			parsed = append(parsed, tag)
+		}
+	}
+	return parsed, nil
+}
+
+func isValidClientTag(tag string) bool {
+	if tag == "" || len(tag) > maxClientTagLength {
+		return false
+	}
+	for _, r := range tag {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+		case strings.ContainsRune(":_-./", r):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// HasTags checks if the client has all the given tags, compared case-insensitively
+func (c *OAuthClient) HasTags(tags []string) bool {
+	for _, tag := range tags {
+		found := false
+		for _, t := range c.Tags {
+			if strings.EqualFold(t, tag) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// TagValue returns the value of the first key:value tag of the client with the given key, empty when the
+// client has none
+func (c *OAuthClient) TagValue(key string) string {
+	for _, tag := range c.Tags {
+		if k, value, ok := strings.Cut(tag, ":"); ok && k == key {
+			return value
+		}
+	}
+	return ""
+}
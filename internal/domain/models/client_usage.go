@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// ClientUsage is the usage of an OAuth2 client, to spot dead or misbehaving integrations
+type ClientUsage struct {
+	ClientID     string
+	LastUsedAt   *time.Time // Last token issued to the client, nil when none was
+	TokensIssued int64
+
+	// RecentErrors counts the failed requests of the client since RecentErrorsSince. The count restarts
+	// once it is older than the error window.
+	RecentErrors      int64
+	RecentErrorsSince *time.Time
+}
+
+// ClientUsageDelta is the usage of an OAuth2 client recorded since the last flush to the usage store
+type ClientUsageDelta struct {
+	ClientID     string
+	LastUsedAt   time.Time // zero when no token was issued
+	TokensIssued int64
+	Errors       int64
+}
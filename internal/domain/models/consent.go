@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// Consent records the scopes a user has granted to an OAuth2 client
+type Consent struct {
+	UserID    string    `json:"user_id"`
+	ClientID  string    `json:"client_id"`
+	Scopes    []string  `json:"scopes"`
+	GrantedAt time.Time `json:"granted_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Covers reports whether every requested scope has already been granted
+func (c *Consent) Covers(scopes []string) bool {
+	for _, scope := range scopes {
+		if !slices.Contains(c.Scopes, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+// Grant adds the given scopes to the consent, keeping previously granted ones
+func (c *Consent) Grant(scopes []string) {
+	for _, scope := range scopes {
+		if !slices.Contains(c.Scopes, scope) {
+			c.Scopes = append(c.Scopes, scope)
+		}
+	}
+}
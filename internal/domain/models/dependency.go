@@ -0,0 +1,55 @@
+package domain
+
+import "time"
+
+// DependencyCriticality declares how the service behaves when a dependency is unavailable
+type DependencyCriticality string
+
+const (
+	// DependencyRequired dependencies must be available: startup fails without them and the service is unhealthy
+	DependencyRequired DependencyCriticality = "required"
+	// DependencyDegradedOK dependencies may be unavailable: the service keeps running in degraded mode
+	DependencyDegradedOK DependencyCriticality = "degraded_ok"
+)
+
+// Health statuses of the service and of its dependencies
+const (
+	HealthStatusHealthy   = "healthy"
+	HealthStatusDegraded  = "degraded"
+	HealthStatusUnhealthy = "unhealthy"
+)
+
+// DependencyStatus is the result of checking a dependency
+type DependencyStatus struct {
+	Name        string                `json:"name"`
+	Criticality DependencyCriticality `json:"criticality"`
+	Healthy     bool                  `json:"healthy"`
+	Error       string                `json:"error,omitempty"`
+	Attempts    int                   `json:"attempts"`
+	CheckedAt   time.Time             `json:"checked_at"`
+	Latency     time.Duration         `json:"latency"` // duration of the last attempt
+}
+
+// Status returns the health status of the dependency
+func (s DependencyStatus) Status() string {
+	if s.Healthy {
+		return HealthStatusHealthy
+	}
+	return HealthStatusUnhealthy
+}
+
+// OverallHealthStatus returns the status of the service given the status of its dependencies:
+// unhealthy when a required dependency is down, degraded when only degraded-ok dependencies are down
+func OverallHealthStatus(statuses []DependencyStatus) string {
+	overall := HealthStatusHealthy
+	for _, status := range statuses {
+		if status.Healthy {
+			continue
+		}
+		if status.Criticality == DependencyRequired {
+			return HealthStatusUnhealthy
+		}
+		overall = HealthStatusDegraded
+	}
+	return overall
+}
@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+// GrantTypeDeviceCode is the grant_type used by devices polling the token endpoint (RFC 8628)
+const GrantTypeDeviceCode = "urn:ietf:params:oauth:grant-type:device_code"
+
+// DeviceAuthorizationStatus represents the state of a device authorization request
+type DeviceAuthorizationStatus string
+
+const (
+	// DeviceAuthorizationPending means the user has not approved or denied the request yet
+	DeviceAuthorizationPending DeviceAuthorizationStatus = "pending"
+
+	// DeviceAuthorizationApproved means the user approved the request
+	DeviceAuthorizationApproved DeviceAuthorizationStatus = "approved"
+
+	// DeviceAuthorizationDenied means the user denied the request
+	DeviceAuthorizationDenied DeviceAuthorizationStatus = "denied"
+)
+
+// DeviceAuthorization represents a pending device authorization grant (RFC 8628)
+type DeviceAuthorization struct {
+	DeviceCode   string                    `json:"device_code"`
+	UserCode     string                    `json:"user_code"`
+	ClientID     string                    `json:"client_id"`
+	Scopes       []string                  `json:"scopes"`
+	Status       DeviceAuthorizationStatus `json:"status"`
+	IDCitizen    int                       `json:"id_citizen,omitempty"` // Set once a user approves
+	Interval     time.Duration             `json:"interval"`
+	LastPolledAt time.Time                 `json:"last_polled_at"`
+	ExpiresAt    time.Time                 `json:"expires_at"`
+	CreatedAt    time.Time                 `json:"created_at"`
+}
+
+// IsExpired reports whether the device authorization has expired
+func (d *DeviceAuthorization) IsExpired() bool {
+	return time.Now().After(d.ExpiresAt)
+}
+
+// PolledTooSoon reports whether the device polled before its polling interval elapsed
+func (d *DeviceAuthorization) PolledTooSoon(now time.Time) bool {
+	return !d.LastPolledAt.IsZero() && now.Sub(d.LastPolledAt) < d.Interval
+}
@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+// SetPendingEmail records a change of the email of the user waiting for confirmation, replacing any
+// previous one
+func (u *User) SetPendingEmail(email, tokenHash string, expiresAt time.Time) {
+	u.PendingEmail = email
+	u.PendingEmailTokenHash = tokenHash
+	u.PendingEmailExpiresAt = &expiresAt
+}
+
+// HasPendingEmail checks if the user has an email change waiting for confirmation that is not expired
+func (u *User) HasPendingEmail(now time.Time) bool {
+	return u.PendingEmail != "" && u.PendingEmailTokenHash != "" &&
+		u.PendingEmailExpiresAt != nil && now.Before(*u.PendingEmailExpiresAt)
+}
+
+// ClearPendingEmail discards the pending email change of the user
+func (u *User) ClearPendingEmail() {
+	u.PendingEmail = ""
+	u.PendingEmailTokenHash = ""
+	u.PendingEmailExpiresAt = nil
+}
+
+// ConfirmPendingEmail replaces the email of the user with the pending one and discards the change,
+// confirmation tokens are single use. It returns the previous email.
+func (u *User) ConfirmPendingEmail() string {
+	previous := u.Email
+	u.Email = u.PendingEmail
+	u.ClearPendingEmail()
+	return previous
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ExportFormat is the serialization of the admin dataset exports
+type ExportFormat string
+
+const (
+	// ExportFormatCSV exports a header row followed by a row per record
+	ExportFormatCSV ExportFormat = "csv"
+
+	// ExportFormatNDJSON exports a JSON object per line
+	ExportFormatNDJSON ExportFormat = "ndjson"
+)
+
+// String returns the string representation of the format
+func (f ExportFormat) String() string {
+	return string(f)
+}
+
+// IsValid checks if the format is valid
+func (f ExportFormat) IsValid() bool {
+	switch f {
+	case ExportFormatCSV, ExportFormatNDJSON:
+		return true
+	default:
+		return false
+	}
+}
+
+// ParseExportFormat parses a string into an ExportFormat
+func ParseExportFormat(s string) (ExportFormat, error) {
+	format := ExportFormat(s)
+	if !format.IsValid() {
+		return "", fmt.Errorf("invalid export format: %s", s)
+	}
+	return format, nil
+}
+
+// UserExportFilter selects the users of an export. Zero values don't filter.
+type UserExportFilter struct {
+	Role          Role
+	Status        UserStatus
+	CreatedAfter  time.Time // Inclusive
+	CreatedBefore time.Time // Exclusive
+}
+
+// Validate checks that the filter values are known and the creation range is not inverted
+func (f UserExportFilter) Validate() error {
+	if f.Role != "" && !f.Role.IsValid() {
+		return fmt.Errorf("invalid role: %s", f.Role)
+	}
+	if f.Status != "" && !f.Status.IsValid() {
+		return fmt.Errorf("invalid user status: %s", f.Status)
+	}
+	return validateTimeRange(f.CreatedAfter, f.CreatedBefore)
+}
+
+// AuditLogFilter selects the audit records of an export. Zero values don't filter.
+type AuditLogFilter struct {
+	Action   AuditAction
+	Actor    string
+	TargetID string
+	After    time.Time // Inclusive
+	Before   time.Time // Exclusive
+}
+
+// Validate checks that the time range is not inverted
+func (f AuditLogFilter) Validate() error {
+	return validateTimeRange(f.After, f.Before)
+}
+
+// validateTimeRange checks that after precedes before when both are set
+func validateTimeRange(after, before time.Time) error {
+	if !after.IsZero() && !before.IsZero() && !after.Before(before) {
+		return errors.New("the start of the time range must precede its end")
+	}
+	return nil
+}
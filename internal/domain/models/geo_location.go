@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// earthRadiusKm is the mean radius of the Earth used for great-circle distances
+const earthRadiusKm = 6371.0
+
+// minTravelHours is the shortest elapsed time considered between two logins (one minute)
+const minTravelHours = 1.0 / 60
+
+// Login risk reasons
+const (
+	// RiskReasonImpossibleTravel is set when two logins are too far apart for the time between them
+	RiskReasonImpossibleTravel = "impossible_travel"
+
+	// RiskReasonNewCountry is set when the country changed within the window and no coordinates are available
+	RiskReasonNewCountry = "new_country"
+)
+
+// GeoLocation is the approximate location of an IP address
+type GeoLocation struct {
+	Country   string  `json:"country"` // ISO 3166-1 alpha-2 code
+	City      string  `json:"city,omitempty"`
+	Latitude  float64 `json:"latitude,omitempty"`
+	Longitude float64 `json:"longitude,omitempty"`
+}
+
+// HasCoordinates reports whether the location is more precise than the country
+func (l GeoLocation) HasCoordinates() bool {
+	return l.Latitude != 0 || l.Longitude != 0
+}
+
+// DistanceKm returns the great-circle distance between two locations (haversine formula)
+func (l GeoLocation) DistanceKm(other GeoLocation) float64 {
+	lat1, lat2 := toRadians(l.Latitude), toRadians(other.Latitude)
+	dLat := lat2 - lat1
+	dLon := toRadians(other.Longitude - l.Longitude)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// LoginLocation records where and when a user logged in
+type LoginLocation struct {
+	IP       string      `json:"ip"`
+	Location GeoLocation `json:"location"`
+	At       time.Time   `json:"at"`
+}
+
+// TravelPolicy defines when two consecutive logins are considered impossible travel.
+// MinDistanceKm absorbs the inaccuracy of IP geolocation for nearby cities.
+type TravelPolicy struct {
+	MaxSpeedKmh   float64
+	MinDistanceKm float64
+}
+
+// Evaluate compares a login with a previous one and returns the risk reason, if any,
+// along with the distance and the speed required to travel between them
+func (p TravelPolicy) Evaluate(previous, current LoginLocation) (reason string, distanceKm, speedKmh float64) {
+	if !previous.Location.HasCoordinates() || !current.Location.HasCoordinates() {
+		if previous.Location.Country != "" && current.Location.Country != "" && previous.Location.Country != current.Location.Country {
+			return RiskReasonNewCountry, 0, 0
+		}
+		return "", 0, 0
+	}
+
+	distanceKm = previous.Location.DistanceKm(current.Location)
+	if distanceKm < p.MinDistanceKm {
+		return "", distanceKm, 0
+	}
+
+	// Clamp the elapsed time so simultaneous logins yield a finite (and very high) speed
+	hours := math.Max(current.At.Sub(previous.At).Hours(), minTravelHours)
+	speedKmh = distanceKm / hours
+	if speedKmh > p.MaxSpeedKmh {
+		return RiskReasonImpossibleTravel, distanceKm, speedKmh
+	}
+	return "", distanceKm, speedKmh
+}
+
+// LoginRisk is the outcome of the risk assessment of a login, stored with the session
+type LoginRisk struct {
+	Risky           bool      `json:"risky"`
+	Reasons         []string  `json:"reasons,omitempty"`
+	IP              string    `json:"ip"`
+	Country         string    `json:"country,omitempty"`
+	City            string    `json:"city,omitempty"`
+	PreviousIP      string    `json:"previous_ip,omitempty"`
+	PreviousCountry string    `json:"previous_country,omitempty"`
+	DistanceKm      float64   `json:"distance_km,omitempty"`
+	SpeedKmh        float64   `json:"speed_kmh,omitempty"`
+	PreviousLoginAt time.Time `json:"previous_login_at,omitempty"`
+	AssessedAt      time.Time `json:"assessed_at"`
+}
+
+func toRadians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
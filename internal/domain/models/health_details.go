@@ -0,0 +1,17 @@
+package domain
+
+// HealthDetails is the detailed health of the service reported to operators: the status of the
+// dependencies with the latency of their checks, and gauges such as the depth of the queues
+type HealthDetails struct {
+	Status       string
+	Dependencies []DependencyStatus
+	Gauges       []GaugeReading
+	Goroutines   int
+}
+
+// GaugeReading is the value of an operational gauge, or the error that prevented reading it
+type GaugeReading struct {
+	Name  string
+	Value int64
+	Error string
+}
@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IDFormat is the format of the IDs generated for new users and OAuth clients
+type IDFormat string
+
+const (
+	// IDFormatUUIDv4 generates random UUIDs, the format of the existing rows
+	IDFormatUUIDv4 IDFormat = "uuidv4"
+
+	// IDFormatUUIDv7 generates UUIDs that start with a millisecond timestamp, so they sort by creation time
+	// and new rows are appended to the end of the primary key index
+	IDFormatUUIDv7 IDFormat = "uuidv7"
+
+	// IDFormatULID generates ULIDs: a millisecond timestamp and 80 random bits in 26 characters of
+	// Crockford's base32, sortable like UUIDv7
+	IDFormatULID IDFormat = "ulid"
+)
+
+// ErrInvalidID is returned by ParseID for a string that is neither a UUID nor a ULID
+var ErrInvalidID = errors.New("invalid ID")
+
+// ulidAlphabet is Crockford's base32 alphabet, without I, L, O and U
+const ulidAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ulidLength is the length of a ULID: 128 bits in 5-bit characters
+const ulidLength = 26
+
+// IDGenerator generates the ID of a new entity
+type IDGenerator func() string
+
+// String returns the string representation of the ID format
+func (f IDFormat) String() string {
+	return string(f)
+}
+
+// IsValid checks if the ID format is valid
+func (f IDFormat) IsValid() bool {
+	switch f {
+	case IDFormatUUIDv4, IDFormatUUIDv7, IDFormatULID:
+		return true
+	default:
+		return false
+	}
+}
+
+// ParseIDFormat parses a string into an IDFormat, case-insensitively
+func ParseIDFormat(s string) (IDFormat, error) {
+	format := IDFormat(strings.ToLower(s))
+	if !format.IsValid() {
+		return "", fmt.Errorf("invalid ID format: %s", s)
+	}
+	return format, nil
+}
+
+// Generator returns the generator of the IDs of the format, UUIDv4 when the format is not valid
+func (f IDFormat) Generator() IDGenerator {
+	switch f {
+	case IDFormatUUIDv7:
+		return func() string { return uuid.Must(uuid.NewV7()).String() }
+	case IDFormatULID:
+		return func() string { return NewULID(time.Now()) }
+	default:
+		return func() string { return uuid.New().String() }
+	}
+}
+
+// NewULID returns a ULID with the timestamp of t and random bits. ULIDs of the same millisecond are not
+// ordered among themselves.
+func NewULID(t time.Time) string {
+	var id [16]byte
+	ms := uint64(t.UnixMilli())
+	binary.BigEndian.PutUint16(id[0:2], uint16(ms>>32))
+	binary.BigEndian.PutUint32(id[2:6], uint32(ms))
+	if _, err := rand.Read(id[6:]); err != nil {
+		panic(fmt.Sprintf("failed to read random bytes: %v", err))
+	}
+
+	// The 128 bits are encoded from the least significant, 5 at a time; the first character holds the 3
+	// most significant bits
+	hi := binary.BigEndian.Uint64(id[0:8])
+	lo := binary.BigEndian.Uint64(id[8:16])
+	var out [ulidLength]byte
+	for i := ulidLength - 1; i >= 0; i-- {
+		out[i] = ulidAlphabet[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
+
+// ParseID parses the ID of a user or an OAuth client and returns it in its canonical form, so it matches
+// the stored ID: UUIDs of any version in lowercase, including the UUIDv4 of the existing rows, and ULIDs
+// in uppercase.
+func ParseID(s string) (string, error) {
+	if len(s) == ulidLength {
+		upper := strings.ToUpper(s)
+		if !isULID(upper) {
+			return "", ErrInvalidID
+		}
+		return upper, nil
+	}
+
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return "", ErrInvalidID
+	}
+	return id.String(), nil
+}
+
+// IsValidID checks if a string is a UUID or a ULID
+func IsValidID(s string) bool {
+	_, err := ParseID(s)
+	return err == nil
+}
+
+// isULID checks if an uppercase string is a ULID, whose first character can't exceed 7 so it fits 128 bits
+func isULID(s string) bool {
+	if len(s) != ulidLength || s[0] > '7' {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(ulidAlphabet, s[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
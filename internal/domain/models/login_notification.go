@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"fmt"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LoginNotificationChannel is a channel through which users are notified of logins from a new device or IP address
+type LoginNotificationChannel string
+
+const (
+	// LoginNotificationChannelEvent publishes a login notification event to RabbitMQ
+	LoginNotificationChannelEvent LoginNotificationChannel = "event"
+
+	// LoginNotificationChannelEmail sends a new_device security notification, delivered by email by the notifier
+	LoginNotificationChannelEmail LoginNotificationChannel = "email"
+)
+
+// ParseLoginNotificationChannels parses a list of channels, case-insensitively and without duplicates
+func ParseLoginNotificationChannels(values []string) ([]LoginNotificationChannel, error) {
+	channels := make([]LoginNotificationChannel, 0, len(values))
+	for _, value := range values {
+		channel := LoginNotificationChannel(strings.ToLower(strings.TrimSpace(value)))
+		switch channel {
+		case LoginNotificationChannelEvent, LoginNotificationChannelEmail:
+		default:
+			return nil, fmt.Errorf("invalid login notification channel: %s", value)
+		}
+		if !slices.Contains(channels, channel) {
+			channels = append(channels, channel)
+		}
+	}
+	return channels, nil
+}
+
+// LoginNotification describes a login from a device or IP address the user did not use recently.
+// The device and the address are redacted so the notification never carries the full user agent or IP.
+type LoginNotification struct {
+	NewDevice bool
+	NewIP     bool
+	Device    string // browser and operating system, e.g. "Firefox on Linux"
+	Network   string // network of the IP address, e.g. 203.0.113.0/24
+	Country   string // empty when the location is unknown
+	City      string
+	At        time.Time
+}
+
+// NewLoginNotification creates the notification of a login from the client, located by geo when not nil
+func NewLoginNotification(info ClientInfo, geo *LoginRisk, newDevice, newIP bool, at time.Time) *LoginNotification {
+	notification := &LoginNotification{
+		NewDevice: newDevice,
+		NewIP:     newIP,
+		Device:    DescribeDevice(info.UserAgent),
+		Network:   RedactIP(info.IP),
+		At:        at,
+	}
+	if geo != nil {
+		notification.Country = geo.Country
+		notification.City = geo.City
+	}
+	return notification
+}
+
+// Details returns the notification as the details of a security notification
+func (n *LoginNotification) Details() map[string]string {
+	details := map[string]string{
+		"new_device": strconv.FormatBool(n.NewDevice),
+		"new_ip":     strconv.FormatBool(n.NewIP),
+		"device":     n.Device,
+		"login_at":   n.At.UTC().Format(time.RFC3339),
+	}
+	if n.Network != "" {
+		details["network"] = n.Network
+	}
+	if n.Country != "" {
+		details["country"] = n.Country
+	}
+	if n.City != "" {
+		details["city"] = n.City
+	}
+	return details
+}
+
+// RedactIP returns the network of an IP address, /24 for IPv4 and /48 for IPv6, so it locates the login
+// without identifying the host. It returns an empty string for an invalid address.
+func RedactIP(ip string) string {
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil {
+		return ""
+	}
+	addr = addr.Unmap()
+
+	bits := 48
+	if addr.Is4() {
+		bits = 24
+	}
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		return ""
+	}
+	return prefix.String()
+}
+
+// userAgentBrowsers are matched in order, since most user agents also name the engines they are compatible with
+var userAgentBrowsers = []struct{ token, name string }{
+	{"Edg/", "Edge"},
+	{"OPR/", "Opera"},
+	{"Firefox/", "Firefox"},
+	{"Chrome/", "Chrome"},
+	{"Safari/", "Safari"},
+}
+
+// userAgentSystems are matched in order, Android user agents also name Linux
+var userAgentSystems = []struct{ token, name string }{
+	{"Android", "Android"},
+	{"iPhone", "iOS"},
+	{"iPad", "iOS"},
+	{"CrOS", "ChromeOS"},
+	{"Windows", "Windows"},
+	{"Macintosh", "macOS"},
+	{"Linux", "Linux"},
+}
+
+// DescribeDevice summarizes a user agent as its browser and operating system, e.g. "Chrome on Windows",
+// leaving out the versions and the rest of the user agent
+func DescribeDevice(userAgent string) string {
+	browser := matchUserAgent(userAgent, userAgentBrowsers)
+	system := matchUserAgent(userAgent, userAgentSystems)
+
+	switch {
+	case browser != "" && system != "":
+		return browser + " on " + system
+	case browser != "":
+		return browser
+	case system != "":
+		return system
+	default:
+		return "unknown device"
+	}
+}
+
+func matchUserAgent(userAgent string, candidates []struct{ token, name string }) string {
+	for _, candidate := range candidates {
+		if strings.Contains(userAgent, candidate.token) {
+			return candidate.name
+		}
+	}
+	return ""
+}
+
+// KnownIPDeviceID returns the identifier under which an IP address is remembered among the known devices
+// of a user, apart from the fingerprints of their user agents
+func KnownIPDeviceID(ip string) string {
+	return "ip:" + DeviceFingerprint(ip)
+}
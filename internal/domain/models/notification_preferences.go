@@ -0,0 +1,80 @@
+package domain
+
+import "time"
+
+// NotificationType identifies a security event a user can be notified about
+type NotificationType string
+
+const (
+	// NotificationNewDevice is sent when the account is used from a new device
+	NotificationNewDevice NotificationType = "new_device"
+
+	// NotificationPasswordChange is sent when the account password changes
+	NotificationPasswordChange NotificationType = "password_change"
+
+	// NotificationLoginAlert is sent on successful logins
+	NotificationLoginAlert NotificationType = "login_alert"
+
+	// NotificationSuspiciousLogin is sent when a login looks anomalous (e.g. impossible travel).
+	// It is mandatory and cannot be disabled through the preferences.
+	NotificationSuspiciousLogin NotificationType = "suspicious_login"
+
+	// NotificationEmailVerification carries the code confirming a secondary email address. It is mandatory.
+	NotificationEmailVerification NotificationType = "email_verification"
+
+	// NotificationPasswordReset carries a password reset token. It is mandatory.
+	NotificationPasswordReset NotificationType = "password_reset"
+
+	// NotificationRegistrationApproved is sent when an administrator approves the registration. It is mandatory.
+	NotificationRegistrationApproved NotificationType = "registration_approved"
+
+	// NotificationRegistrationRejected is sent when an administrator rejects the registration. It is mandatory.
+	NotificationRegistrationRejected NotificationType = "registration_rejected"
+
+	// NotificationEmailChangeConfirmation carries the link confirming a new email address, sent to the new
+	// address. It is mandatory.
+	NotificationEmailChangeConfirmation NotificationType = "email_change_confirmation"
+
+	// NotificationEmailChangeRequested alerts the current address that a change of the account email was
+	// requested. It is mandatory.
+	NotificationEmailChangeRequested NotificationType = "email_change_requested"
+)
+
+// NotificationPreferences holds the per-user opt-in flags for security notifications
+type NotificationPreferences struct {
+	UserID         string    `json:"user_id"`
+	NewDevice      bool      `json:"new_device"`
+	PasswordChange bool      `json:"password_change"`
+	LoginAlert     bool      `json:"login_alert"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// DefaultNotificationPreferences returns the preferences applied to users that never changed them.
+// Every notification is enabled by default so security events are not silently dropped.
+func DefaultNotificationPreferences(userID string) *NotificationPreferences {
+	return &NotificationPreferences{
+		UserID:         userID,
+		NewDevice:      true,
+		PasswordChange: true,
+		LoginAlert:     true,
+		UpdatedAt:      time.Now(),
+	}
+}
+
+// Allows reports whether the user accepts notifications of the given type
+func (p *NotificationPreferences) Allows(notificationType NotificationType) bool {
+	switch notificationType {
+	case NotificationNewDevice:
+		return p.NewDevice
+	case NotificationPasswordChange:
+		return p.PasswordChange
+	case NotificationLoginAlert:
+		return p.LoginAlert
+	case NotificationSuspiciousLogin, NotificationEmailVerification, NotificationPasswordReset,
+		NotificationRegistrationApproved, NotificationRegistrationRejected,
+		NotificationEmailChangeConfirmation, NotificationEmailChangeRequested:
+		return true
+	default:
+		return false
+	}
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"crypto/x509"
 	"errors"
 	"time"
 
@@ -12,6 +13,59 @@ var (
 	ErrValidation = errors.New("validation error")
 )
 
+// OAuth2 grant types accepted by the token endpoint
+const (
+	GrantTypeClientCredentials = "client_credentials"
+	// GrantTypePassword is the deprecated resource-owner password grant, kept for legacy clients
+	GrantTypePassword = "password"
+)
+
+// Grant types a client may be allowed to use besides client_credentials and device_code, with their
+// RFC 7591 names
+const (
+	GrantTypeAuthorizationCode = "authorization_code"
+	GrantTypeTokenExchange     = "urn:ietf:params:oauth:grant-type:token-exchange"
+)
+
+// grantTypeAliases are the short names accepted for the URN grant types
+var grantTypeAliases = map[string]string{
+	"device_code":    GrantTypeDeviceCode,
+	"token_exchange": GrantTypeTokenExchange,
+}
+
+// DefaultGrantTypes returns the grant types of the clients registered without any, the ones every
+// client could use before they were configurable
+func DefaultGrantTypes() []string {
+	return []string{GrantTypeClientCredentials, GrantTypeDeviceCode}
+}
+
+// ParseGrantTypes validates the grant types a client may use and returns them without duplicates.
+// The short names device_code and token_exchange are accepted for the URN grant types. The password
+// grant is not among them, it stays governed by the legacy clients allowlist.
+func ParseGrantTypes(grantTypes []string) ([]string, error) {
+	if len(grantTypes) == 0 {
+		return nil, ErrValidation
+	}
+
+	parsed := make([]string, 0, len(grantTypes))
+	seen := make(map[string]bool, len(grantTypes))
+	for _, grantType := range grantTypes {
+		if alias, ok := grantTypeAliases[grantType]; ok {
+			grantType = alias
+		}
+		switch grantType {
+		case GrantTypeClientCredentials, GrantTypeAuthorizationCode, GrantTypeDeviceCode, GrantTypeTokenExchange:
+		default:
+			return nil, ErrValidation
+		}
+		if !seen[grantType] {
+			seen[grantType] = true
+			parsed = append(parsed, grantType)
+		}
+	}
+	return parsed, nil
+}
+
 // OAuthClient represents an OAuth2 client application for service-to-service communication
 type OAuthClient struct {
 	ID           string    `json:"id"`
@@ -23,10 +77,62 @@ type OAuthClient struct {
 	Active       bool      `json:"active"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
+
+	// RequireSignedRequests rejects the client_credentials requests without a valid timestamp, nonce and
+	// signature, RequestSigningKey is the HMAC key of the signature
+	RequireSignedRequests bool   `json:"require_signed_requests"`
+	RequestSigningKey     string `json:"-"` // Never expose in JSON
+
+	// TokenProfile selects the claims of the user access tokens issued to the client
+	TokenProfile TokenProfile `json:"token_profile"`
+
+	// GrantTypes are the grant types the client may use at the token endpoint, the default ones when empty
+	GrantTypes []string `json:"grant_types"`
+
+	// RedirectURIs are the registered redirect URIs of the authorization requests of the client
+	RedirectURIs []string `json:"redirect_uris"`
+
+	// Tags are free-form labels of the owner of the client, e.g. team:payments or env:staging
+	Tags []string `json:"tags"`
+
+	// SecretExpiresAt is when the secret of the client stops being accepted, nil when it never expires
+	SecretExpiresAt *time.Time `json:"secret_expires_at,omitempty"`
+
+	// Version is incremented by every update, an update of a client read at an older version is rejected
+	Version int `json:"version"`
+}
+
+// TokenProfile is the set of claims carried by the user access tokens issued to a client
+type TokenProfile string
+
+const (
+	// TokenProfileStandard access tokens carry the citizen ID, user ID, email and role of the user
+	TokenProfileStandard TokenProfile = "standard"
+	// TokenProfileMinimal access tokens only carry sub, exp, jti and role, for clients constrained by the
+	// size of HTTP headers; the rest of the user data is fetched via /api/auth/me or token introspection
+	TokenProfileMinimal TokenProfile = "minimal"
+)
+
+// IsValid returns true if the token profile is a known one
+func (p TokenProfile) IsValid() bool {
+	return p == TokenProfileStandard || p == TokenProfileMinimal
 }
 
-// NewOAuthClient creates a new OAuth client with hashed secret
+// IsMinimal returns true if the token profile is the minimal claims profile
+func (p TokenProfile) IsMinimal() bool {
+	return p == TokenProfileMinimal
+}
+
+// NewOAuthClient creates a new OAuth client, hashing the secret with the default bcrypt cost
 func NewOAuthClient(clientID, clientSecret, name, description string, scopes []string) (*OAuthClient, error) {
+	return NewOAuthClientWithHasher(clientID, clientSecret, name, description, scopes, func(secret string) (string, error) {
+		hashedSecret, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+		return string(hashedSecret), err
+	})
+}
+
+// NewOAuthClientWithHasher creates a new OAuth client, hashing the secret with hashSecret
+func NewOAuthClientWithHasher(clientID, clientSecret, name, description string, scopes []string, hashSecret PasswordHashFunc) (*OAuthClient, error) {
 	if clientID == "" {
 		return nil, ErrValidation
 	}
@@ -37,7 +143,7 @@ func NewOAuthClient(clientID, clientSecret, name, description string, scopes []s
 		return nil, ErrValidation
 	}
 
-	hashedSecret, err := bcrypt.GenerateFromPassword([]byte(clientSecret), bcrypt.DefaultCost)
+	hashedSecret, err := hashSecret(clientSecret)
 	if err != nil {
 		return nil, err
 	}
@@ -48,22 +154,36 @@ func NewOAuthClient(clientID, clientSecret, name, description string, scopes []s
 
 	return &OAuthClient{
 		ClientID:     clientID,
-		ClientSecret: string(hashedSecret),
+		ClientSecret: hashedSecret,
 		Name:         name,
 		Description:  description,
 		Scopes:       scopes,
 		Active:       true,
+		TokenProfile: TokenProfileStandard,
+		GrantTypes:   DefaultGrantTypes(),
+		RedirectURIs: []string{},
+		Tags:         []string{},
 		CreatedAt:    time.Now(),
 		UpdatedAt:    time.Now(),
 	}, nil
 }
 
-// ValidateSecret checks if the provided secret matches the stored hash
+// ValidateSecret checks if the provided secret matches the stored bcrypt hash
 func (c *OAuthClient) ValidateSecret(secret string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(c.ClientSecret), []byte(secret))
 	return err == nil
 }
 
+// SecretExpired checks if the secret of the client has expired at now
+func (c *OAuthClient) SecretExpired(now time.Time) bool {
+	return c.SecretExpiresAt != nil && !now.Before(*c.SecretExpiresAt)
+}
+
+// SecretExpiresWithin checks if the secret of the client expires within window of now, or has already expired
+func (c *OAuthClient) SecretExpiresWithin(now time.Time, window time.Duration) bool {
+	return c.SecretExpiresAt != nil && c.SecretExpiresAt.Before(now.Add(window))
+}
+
 // HasScope checks if the client has a specific scope
 func (c *OAuthClient) HasScope(scope string) bool {
 	for _, s := range c.Scopes {
@@ -74,6 +194,24 @@ func (c *OAuthClient) HasScope(scope string) bool {
 	return false
 }
 
+// AllowedGrantTypes returns the grant types the client may use
+func (c *OAuthClient) AllowedGrantTypes() []string {
+	if len(c.GrantTypes) == 0 {
+		return DefaultGrantTypes()
+	}
+	return c.GrantTypes
+}
+
+// AllowsGrantType checks if the client may use a grant type
+func (c *OAuthClient) AllowsGrantType(grantType string) bool {
+	for _, g := range c.AllowedGrantTypes() {
+		if g == grantType {
+			return true
+		}
+	}
+	return false
+}
+
 // OAuthTokenClaims represents the claims for an OAuth access token
 type OAuthTokenClaims struct {
 	ClientID string   `json:"client_id"`
@@ -82,4 +220,15 @@ type OAuthTokenClaims struct {
 	IssuedAt int64    `json:"iat"`
 	ExpireAt int64    `json:"exp"`
 	Type     string   `json:"type"` // "client_credentials"
+
+	// Confirmation binds the token to the certificate of the client, nil when it was not requested over mTLS
+	Confirmation *Confirmation `json:"cnf,omitempty"`
+}
+
+// VerifyCertificate checks that the certificate presented with the token is the one it is bound to
+func (c *OAuthTokenClaims) VerifyCertificate(cert *x509.Certificate) error {
+	if c.Confirmation == nil {
+		return nil
+	}
+	return VerifyCertificateBinding(c.Confirmation.CertificateThumbprint, cert)
 }
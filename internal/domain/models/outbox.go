@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxMessage is a message that could not be delivered to the broker and is kept for later retry
+type OutboxMessage struct {
+	ID            string    `json:"id"`
+	Queue         string    `json:"queue"`
+	Payload       []byte    `json:"payload"`
+	Attempts      int       `json:"attempts"` // Delivery attempts made from the outbox
+	LastError     string    `json:"last_error,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+}
+
+// NewOutboxMessage creates a new outbox message due immediately
+func NewOutboxMessage(queue string, payload []byte, lastError string) *OutboxMessage {
+	now := time.Now()
+	return &OutboxMessage{
+		ID:            uuid.New().String(),
+		Queue:         queue,
+		Payload:       payload,
+		LastError:     lastError,
+		CreatedAt:     now,
+		NextAttemptAt: now,
+	}
+}
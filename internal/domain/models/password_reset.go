@@ -0,0 +1,10 @@
+package domain
+
+import "time"
+
+// PasswordReset is a pending password reset, stored under the hash of the token sent by email
+type PasswordReset struct {
+	UserID    string    `json:"user_id"`
+	Email     string    `json:"email"` // Address the token was sent to, the primary or a verified secondary email
+	CreatedAt time.Time `json:"created_at"`
+}
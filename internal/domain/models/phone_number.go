@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// MaxPhoneVerificationAttempts is the number of wrong codes accepted before a pending verification is discarded
+const MaxPhoneVerificationAttempts = 5
+
+// e164Pattern matches phone numbers in E.164 format (country code and subscriber number, up to 15 digits)
+var e164Pattern = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+
+// PhoneNumber is a verified phone number that can receive one-time codes by SMS
+type PhoneNumber struct {
+	UserID     string    `json:"user_id"`
+	Number     string    `json:"number"`
+	VerifiedAt time.Time `json:"verified_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// PhoneVerification is a pending phone-number enrollment waiting for the code sent by SMS.
+// Only the hash of the code is kept.
+type PhoneVerification struct {
+	UserID    string    `json:"user_id"`
+	Number    string    `json:"number"`
+	CodeHash  string    `json:"code_hash"`
+	Attempts  int       `json:"attempts"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// IsExpired checks if the verification code has expired
+func (v *PhoneVerification) IsExpired() bool {
+	return time.Now().After(v.ExpiresAt)
+}
+
+// AttemptsExhausted reports whether too many wrong codes were submitted
+func (v *PhoneVerification) AttemptsExhausted() bool {
+	return v.Attempts >= MaxPhoneVerificationAttempts
+}
+
+// NormalizePhoneNumber strips common separators and validates the number is in E.164 format
+func NormalizePhoneNumber(raw string) (string, bool) {
+	number := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '(', ')':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(raw))
+
+	if !e164Pattern.MatchString(number) {
+		return "", false
+	}
+	return number, true
+}
+
+// MaskPhoneNumber hides all but the last four digits of a phone number, for logs and responses
+func MaskPhoneNumber(number string) string {
+	if len(number) <= 4 {
+		return strings.Repeat("*", len(number))
+	}
+	return strings.Repeat("*", len(number)-4) + number[len(number)-4:]
+}
+
+// SMSNumberRateLimitKey returns the rate-limit key of the SMS sent to a phone number
+func SMSNumberRateLimitKey(number string) string {
+	return fmt.Sprintf("sms:number:%s", number)
+}
+
+// SMSQuotaRateLimitKey returns the rate-limit key of the global SMS sending quota
+func SMSQuotaRateLimitKey() string {
+	return "sms:quota"
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Quota subject types
+const (
+	// QuotaSubjectClient is an OAuth2 client (an integration), identified by its client_id
+	QuotaSubjectClient = "client"
+	// QuotaSubjectUser is a user, identified by its ID
+	QuotaSubjectUser = "user"
+)
+
+// IsValidQuotaSubject checks if the subject type is one of the known types
+func IsValidQuotaSubject(subjectType string) bool {
+	return subjectType == QuotaSubjectClient || subjectType == QuotaSubjectUser
+}
+
+// IssuanceQuota limits the tokens issued to a client or user. A zero limit means unlimited.
+type IssuanceQuota struct {
+	SubjectType       string    `json:"subject_type"`
+	SubjectID         string    `json:"subject_id"`
+	MaxTokensPerHour  int       `json:"max_tokens_per_hour"`
+	MaxActiveSessions int       `json:"max_active_sessions"` // users only
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// Validate checks the limits of the quota
+func (q *IssuanceQuota) Validate() error {
+	if !IsValidQuotaSubject(q.SubjectType) {
+		return fmt.Errorf("%w: unknown subject type %q", ErrValidation, q.SubjectType)
+	}
+	if q.MaxTokensPerHour < 0 || q.MaxActiveSessions < 0 {
+		return fmt.Errorf("%w: limits must not be negative", ErrValidation)
+	}
+	if q.SubjectType == QuotaSubjectClient && q.MaxActiveSessions > 0 {
+		return fmt.Errorf("%w: active sessions can only be limited for users", ErrValidation)
+	}
+	return nil
+}
+
+// QuotaUsage is the effective quota of a subject along with its current usage
+type QuotaUsage struct {
+	Quota          IssuanceQuota `json:"quota"`
+	Default        bool          `json:"default"` // no override is set, the default quota applies
+	TokensIssued   int           `json:"tokens_issued"`
+	ResetAt        time.Time     `json:"reset_at"`
+	ActiveSessions int           `json:"active_sessions"` // users only
+}
+
+// QuotaUsageKey returns the key counting the tokens issued to a subject within the current hour
+func QuotaUsageKey(subjectType, subjectID string) string {
+	return fmt.Sprintf("quota_tokens:%s:%s", subjectType, subjectID)
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// RateLimitStatus describes the usage of a principal within the current rate-limit window
+type RateLimitStatus struct {
+	Limit   int       `json:"limit"`
+	Used    int       `json:"used"`
+	ResetAt time.Time `json:"reset_at"`
+}
+
+// Remaining returns the number of requests left in the current window
+func (s *RateLimitStatus) Remaining() int {
+	if s.Used >= s.Limit {
+		return 0
+	}
+	return s.Limit - s.Used
+}
+
+// Exceeded reports whether the principal went over the limit in the current window
+func (s *RateLimitStatus) Exceeded() bool {
+	return s.Used > s.Limit
+}
+
+// ResetSeconds returns the whole seconds until the window resets, rounded up
+func (s *RateLimitStatus) ResetSeconds(now time.Time) int64 {
+	remaining := s.ResetAt.Sub(now)
+	if remaining <= 0 {
+		return 0
+	}
+	return int64((remaining + time.Second - 1) / time.Second)
+}
+
+// UserRateLimitKey returns the rate-limit key of an authenticated user
+func UserRateLimitKey(idCitizen int) string {
+	return fmt.Sprintf("user:%d", idCitizen)
+}
+
+// ClientRateLimitKey returns the rate-limit key of an OAuth2 client
+func ClientRateLimitKey(clientID string) string {
+	return fmt.Sprintf("client:%s", clientID)
+}
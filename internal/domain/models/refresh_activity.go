@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+// Refresh risk reasons
+const (
+	// RiskReasonRefreshManyIPs is set when a refresh token family is used from too many IP addresses
+	RiskReasonRefreshManyIPs = "refresh_many_ips"
+
+	// RiskReasonRefreshHighFrequency is set when a refresh token family is refreshed too often
+	RiskReasonRefreshHighFrequency = "refresh_high_frequency"
+)
+
+// RefreshActivity summarizes the refreshes of a refresh token family (the chain of refresh tokens
+// rotated from the same login) within the activity window
+type RefreshActivity struct {
+	DistinctIPs int `json:"distinct_ips"`
+	Refreshes   int `json:"refreshes"`
+}
+
+// RefreshAnomalyPolicy defines when the refresh activity of a token family is anomalous.
+// A zero threshold disables its check.
+type RefreshAnomalyPolicy struct {
+	MaxDistinctIPs int
+	MaxRefreshes   int
+	Window         time.Duration
+}
+
+// Enabled reports whether any check is enabled
+func (p RefreshAnomalyPolicy) Enabled() bool {
+	return p.MaxDistinctIPs > 0 || p.MaxRefreshes > 0
+}
+
+// Evaluate returns the risk reasons of the activity, empty when it is not anomalous
+func (p RefreshAnomalyPolicy) Evaluate(activity RefreshActivity) []string {
+	var reasons []string
+	if p.MaxDistinctIPs > 0 && activity.DistinctIPs > p.MaxDistinctIPs {
+		reasons = append(reasons, RiskReasonRefreshManyIPs)
+	}
+	if p.MaxRefreshes > 0 && activity.Refreshes > p.MaxRefreshes {
+		reasons = append(reasons, RiskReasonRefreshHighFrequency)
+	}
+	return reasons
+}
@@ -0,0 +1,43 @@
+package domain
+
+import "strings"
+
+// maxRegionLength is the maximum length of a region identifier
+const maxRegionLength = 32
+
+// regionTokenIDSeparator separates the region from the rest of the jti of the tokens issued in a region
+const regionTokenIDSeparator = "."
+
+// IsValidRegion checks that a region identifier is made of lowercase letters, digits and dashes, like
+// us-east-1 or eu-west, so it fits in the jti of the tokens
+func IsValidRegion(region string) bool {
+	if region == "" || len(region) > maxRegionLength {
+		return false
+	}
+	for _, r := range region {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// RegionalTokenID prefixes the ID of a token with the region issuing it, e.g. us-east-1.3f2a..., so the
+// token can be traced to that region. The ID is returned as is without a region.
+func RegionalTokenID(region, id string) string {
+	if region == "" {
+		return id
+	}
+	return region + regionTokenIDSeparator + id
+}
+
+// TokenIDRegion returns the region a token was issued in from its jti, empty when the jti has no region
+func TokenIDRegion(jti string) string {
+	region, _, ok := strings.Cut(jti, regionTokenIDSeparator)
+	if !ok || !IsValidRegion(region) {
+		return ""
+	}
+	return region
+}
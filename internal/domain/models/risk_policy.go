@@ -0,0 +1,299 @@
+package domain
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RiskDecision is the outcome of the risk policy for an authentication
+type RiskDecision string
+
+const (
+	// RiskDecisionAllow lets the authentication proceed
+	RiskDecisionAllow RiskDecision = "allow"
+	// RiskDecisionStepUp lets the authentication proceed but marks the session as requiring step-up
+	RiskDecisionStepUp RiskDecision = "step_up"
+	// RiskDecisionDeny rejects the authentication
+	RiskDecisionDeny RiskDecision = "deny"
+)
+
+// riskDecisionSeverity orders decisions from the most to the least permissive
+var riskDecisionSeverity = map[RiskDecision]int{
+	RiskDecisionAllow:  0,
+	RiskDecisionStepUp: 1,
+	RiskDecisionDeny:   2,
+}
+
+// IsValid checks if the decision is one of the known decisions
+func (d RiskDecision) IsValid() bool {
+	_, ok := riskDecisionSeverity[d]
+	return ok
+}
+
+// String returns the string representation of the decision
+func (d RiskDecision) String() string {
+	return string(d)
+}
+
+// Authentication flows evaluated by the risk policy
+const (
+	RiskFlowLogin   = "login"
+	RiskFlowRefresh = "refresh"
+	RiskFlowSudo    = "sudo"
+)
+
+// RiskSignals are the inputs of the risk policy for an authentication
+type RiskSignals struct {
+	Flow           string   `json:"flow"`
+	IP             string   `json:"ip,omitempty"`
+	IPReputation   []string `json:"ip_reputation,omitempty"`
+	DeviceID       string   `json:"device_id,omitempty"`
+	NewDevice      bool     `json:"new_device"`
+	RecentFailures int      `json:"recent_failures"`
+	GeoReasons     []string `json:"geo_reasons,omitempty"`
+	RefreshReasons []string `json:"refresh_reasons,omitempty"`
+}
+
+// RiskAssessment is the decision of the risk policy along with the rules and signals behind it
+type RiskAssessment struct {
+	Decision   RiskDecision `json:"decision"`
+	Rules      []string     `json:"rules,omitempty"`
+	Signals    RiskSignals  `json:"signals"`
+	Geo        *LoginRisk   `json:"geo,omitempty"`
+	AssessedAt time.Time    `json:"assessed_at"`
+
+	// LockedOut is set when the authentication is denied by rules on recent failed logins
+	LockedOut bool `json:"locked_out,omitempty"`
+}
+
+// RequiresStepUp checks if the session must be stepped up before sensitive operations
+func (a *RiskAssessment) RequiresStepUp() bool {
+	return a != nil && a.Decision == RiskDecisionStepUp
+}
+
+// RiskCondition describes the signals a rule applies to. Every set field must match;
+// list fields match when any of their values is present in the signals.
+type RiskCondition struct {
+	Flows             []string `json:"flows,omitempty"`
+	IPReputation      []string `json:"ip_reputation,omitempty"`
+	NewDevice         *bool    `json:"new_device,omitempty"`
+	MinRecentFailures int      `json:"min_recent_failures,omitempty"`
+	GeoReasons        []string `json:"geo_reasons,omitempty"`
+	RefreshReasons    []string `json:"refresh_reasons,omitempty"`
+}
+
+// Matches checks if the signals satisfy the condition
+func (c RiskCondition) Matches(signals RiskSignals) bool {
+	if len(c.Flows) > 0 && !slices.Contains(c.Flows, signals.Flow) {
+		return false
+	}
+	if len(c.IPReputation) > 0 && !containsAny(c.IPReputation, signals.IPReputation) {
+		return false
+	}
+	if c.NewDevice != nil && *c.NewDevice != signals.NewDevice {
+		return false
+	}
+	if c.MinRecentFailures > 0 && signals.RecentFailures < c.MinRecentFailures {
+		return false
+	}
+	if len(c.GeoReasons) > 0 && !containsAny(c.GeoReasons, signals.GeoReasons) {
+		return false
+	}
+	if len(c.RefreshReasons) > 0 && !containsAny(c.RefreshReasons, signals.RefreshReasons) {
+		return false
+	}
+	return true
+}
+
+// RiskRule applies a decision to the authentications matching its condition
+type RiskRule struct {
+	Name     string        `json:"name"`
+	Decision RiskDecision  `json:"decision"`
+	When     RiskCondition `json:"when"`
+}
+
+// RiskPolicy is the set of rules evaluated on every login and refresh.
+// IPLists maps reputation labels (e.g. "tor", "blocklist") to IP addresses or CIDR ranges.
+type RiskPolicy struct {
+	DefaultDecision RiskDecision        `json:"default_decision"`
+	IPLists         map[string][]string `json:"ip_lists,omitempty"`
+	Rules           []RiskRule          `json:"rules"`
+
+	networks map[string][]*net.IPNet
+}
+
+// DefaultRiskPolicy returns the policy used when none is configured, which allows every authentication
+func DefaultRiskPolicy() *RiskPolicy {
+	return &RiskPolicy{DefaultDecision: RiskDecisionAllow}
+}
+
+// ParseRiskPolicy parses and validates a JSON risk policy, e.g.
+//
+//	{
+//	  "default_decision": "allow",
+//	  "ip_lists": {"blocklist": ["203.0.113.0/24"]},
+//	  "rules": [
+//	    {"name": "blocked-ip", "decision": "deny", "when": {"ip_reputation": ["blocklist"]}},
+//	    {"name": "impossible-travel", "decision": "step_up", "when": {"geo_reasons": ["impossible_travel"]}},
+//	    {"name": "shared-refresh-token", "decision": "deny", "when": {"refresh_reasons": ["refresh_many_ips"]}}
+//	  ]
+//	}
+func ParseRiskPolicy(data []byte) (*RiskPolicy, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var policy RiskPolicy
+	if err := decoder.Decode(&policy); err != nil {
+		return nil, fmt.Errorf("invalid risk policy: %w", err)
+	}
+
+	if policy.DefaultDecision == "" {
+		policy.DefaultDecision = RiskDecisionAllow
+	}
+	if !policy.DefaultDecision.IsValid() {
+		return nil, fmt.Errorf("invalid risk policy: unknown default decision %q", policy.DefaultDecision)
+	}
+
+	policy.networks = make(map[string][]*net.IPNet, len(policy.IPLists))
+	for label, entries := range policy.IPLists {
+		for _, entry := range entries {
+			network, err := parseNetwork(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid risk policy: ip list %q: %w", label, err)
+			}
+			policy.networks[label] = append(policy.networks[label], network)
+		}
+	}
+
+	names := make(map[string]bool, len(policy.Rules))
+	for _, rule := range policy.Rules {
+		if rule.Name == "" || names[rule.Name] {
+			return nil, fmt.Errorf("invalid risk policy: rule names must be unique and not empty (%q)", rule.Name)
+		}
+		names[rule.Name] = true
+
+		if !rule.Decision.IsValid() {
+			return nil, fmt.Errorf("invalid risk policy: rule %q has unknown decision %q", rule.Name, rule.Decision)
+		}
+		for _, label := range rule.When.IPReputation {
+			if _, ok := policy.IPLists[label]; !ok {
+				return nil, fmt.Errorf("invalid risk policy: rule %q references unknown ip list %q", rule.Name, label)
+			}
+		}
+	}
+
+	return &policy, nil
+}
+
+// IPReputation returns the sorted labels of the IP lists containing the address
+func (p *RiskPolicy) IPReputation(ip string) []string {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return nil
+	}
+
+	var labels []string
+	for label, networks := range p.networks {
+		for _, network := range networks {
+			if network.Contains(addr) {
+				labels = append(labels, label)
+				break
+			}
+		}
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// Evaluate returns the strictest decision among the matching rules and the names of those rules.
+// The default decision applies when no rule matches.
+func (p *RiskPolicy) Evaluate(signals RiskSignals) (RiskDecision, []string) {
+	decision := p.DefaultDecision
+	var matched []string
+	for _, rule := range p.Rules {
+		if !rule.When.Matches(signals) {
+			continue
+		}
+		if len(matched) == 0 || riskDecisionSeverity[rule.Decision] > riskDecisionSeverity[decision] {
+			decision = rule.Decision
+		}
+		matched = append(matched, rule.Name)
+	}
+	return decision, matched
+}
+
+// TriggeredByFailures checks if any of the named rules applies on recent failed logins
+func (p *RiskPolicy) TriggeredByFailures(ruleNames []string) bool {
+	for _, rule := range p.Rules {
+		if rule.When.MinRecentFailures > 0 && slices.Contains(ruleNames, rule.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// DeviceFingerprint derives a stable device identifier from the user agent of the client
+func DeviceFingerprint(userAgent string) string {
+	userAgent = strings.TrimSpace(userAgent)
+	if userAgent == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(userAgent))
+	return hex.EncodeToString(sum[:16])
+}
+
+// LoginFailureRateLimitKey returns the key counting the recent failed logins of a user
+func LoginFailureRateLimitKey(userID string) string {
+	return fmt.Sprintf("login_failures:%s", userID)
+}
+
+// LoginFailureIPRateLimitKey returns the key counting the recent failed logins from an IP address
+func LoginFailureIPRateLimitKey(ip string) string {
+	return fmt.Sprintf("login_failures_ip:%s", ip)
+}
+
+// LoginFailureEmailRateLimitKey returns the key counting the recent failed logins for an email address,
+// whether or not a user has it
+func LoginFailureEmailRateLimitKey(email string) string {
+	return fmt.Sprintf("login_failures_email:%s", strings.ToLower(strings.TrimSpace(email)))
+}
+
+// parseNetwork parses a CIDR range or a single IP address
+func parseNetwork(entry string) (*net.IPNet, error) {
+	entry = strings.TrimSpace(entry)
+	if !strings.Contains(entry, "/") {
+		ip := net.ParseIP(entry)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid IP address %q", entry)
+		}
+		bits := 8 * net.IPv6len
+		if ip.To4() != nil {
+			ip = ip.To4()
+			bits = 8 * net.IPv4len
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+	}
+
+	_, network, err := net.ParseCIDR(entry)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CIDR range %q", entry)
+	}
+	return network, nil
+}
+
+func containsAny(want, have []string) bool {
+	for _, value := range want {
+		if slices.Contains(have, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"regexp"
+	"time"
+)
+
+// scopeNamePattern restricts scope names to lowercase tokens such as "read" or "users:write"
+var scopeNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_.:-]{0,99}$`)
+
+// Scope represents an OAuth2 scope that can be assigned to clients
+type Scope struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	System      bool      `json:"system"` // System scopes are built-in and cannot be modified or deleted
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// NewScope creates a new non-system scope
+func NewScope(name, description string) (*Scope, error) {
+	if !IsValidScopeName(name) {
+		return nil, ErrValidation
+	}
+
+	return &Scope{
+		Name:        name,
+		Description: description,
+		System:      false,
+	}, nil
+}
+
+// IsValidScopeName reports whether name is a well-formed scope name
+func IsValidScopeName(name string) bool {
+	return scopeNamePattern.MatchString(name)
+}
@@ -0,0 +1,57 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestDetectAvatarContentType(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   []byte
+		want   string
+		wantOK bool
+	}{
+		{name: "PNG", data: []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), want: "image/png", wantOK: true},
+		{name: "JPEG", data: []byte("\xff\xd8\xff\xe0\x00\x10JFIF"), want: "image/jpeg", wantOK: true},
+		{name: "WebP", data: []byte("RIFF\x24\x00\x00\x00WEBPVP8 "), want: "image/webp", wantOK: true},
+		{name: "RIFF that is not WebP", data: []byte("RIFF\x24\x00\x00\x00WAVEfmt "), wantOK: false},
+		{name: "GIF", data: []byte("GIF89a\x01\x00\x01\x00"), wantOK: false},
+		{name: "text", data: []byte("<svg xmlns=\"http://www.w3.org/2000/svg\"/>"), wantOK: false},
+		{name: "empty", data: nil, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := domain.DetectAvatarContentType(tt.data)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("DetectAvatarContentType() = (%v, %v), want (%v, %v)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestNewAvatarObjectKey(t *testing.T) {
+	tests := []struct {
+		contentType   string
+		wantExtension string
+	}{
+		{contentType: "image/png", wantExtension: ".png"},
+		{contentType: "image/jpeg", wantExtension: ".jpg"},
+		{contentType: "image/webp", wantExtension: ".webp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.contentType, func(t *testing.T) {
+			key := domain.NewAvatarObjectKey("user-123", tt.contentType)
+			if !strings.HasPrefix(key, domain.AvatarKeyPrefix+"user-123/") || !strings.HasSuffix(key, tt.wantExtension) {
+				t.Errorf("NewAvatarObjectKey() = %v, want %suser-123/*%s", key, domain.AvatarKeyPrefix, tt.wantExtension)
+			}
+			if other := domain.NewAvatarObjectKey("user-123", tt.contentType); other == key {
+				t.Errorf("NewAvatarObjectKey() returned %v twice, want a new key per upload", key)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// newCertificate creates a self-signed client certificate
+func newCertificate(t *testing.T, name string) *x509.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func TestCertificateThumbprint(t *testing.T) {
+	cert := newCertificate(t, "client-123")
+
+	sum := sha256.Sum256(cert.Raw)
+	want := base64.RawURLEncoding.EncodeToString(sum[:])
+	if got := domain.CertificateThumbprint(cert); got != want {
+		t.Errorf("CertificateThumbprint() = %q, want %q", got, want)
+	}
+}
+
+func TestVerifyCertificateBinding(t *testing.T) {
+	cert := newCertificate(t, "client-123")
+	other := newCertificate(t, "client-456")
+	thumbprint := domain.CertificateThumbprint(cert)
+
+	tests := []struct {
+		name       string
+		thumbprint string
+		cert       *x509.Certificate
+		wantErr    error
+	}{
+		{name: "same certificate", thumbprint: thumbprint, cert: cert},
+		{name: "another certificate", thumbprint: thumbprint, cert: other, wantErr: domainerrors.ErrCertificateMismatch},
+		{name: "no certificate", thumbprint: thumbprint, wantErr: domainerrors.ErrCertificateMismatch},
+		{name: "unbound token", cert: other},
+		{name: "unbound token without certificate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := domain.VerifyCertificateBinding(tt.thumbprint, tt.cert); !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifyCertificateBinding() error = %v, want %v", err, tt.wantErr)
+			}
+
+			claims := &domain.OAuthTokenClaims{ClientID: "client-123"}
+			if tt.thumbprint != "" {
+				claims.Confirmation = &domain.Confirmation{CertificateThumbprint: tt.thumbprint}
+			}
+			if err := claims.VerifyCertificate(tt.cert); !errors.Is(err, tt.wantErr) {
+				t.Errorf("OAuthTokenClaims.VerifyCertificate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
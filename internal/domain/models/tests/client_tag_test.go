@@ -0,0 +1,57 @@
+package tests
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestParseClientTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		tags    []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "key value tags", tags: []string{"team:payments", "env:staging"}, want: []string{"team:payments", "env:staging"}},
+		{name: "free-form tag", tags: []string{"legacy"}, want: []string{"legacy"}},
+		{name: "normalized", tags: []string{" Team:Payments", "team:payments", "owner:ops/billing"}, want: []string{"team:payments", "owner:ops/billing"}},
+		{name: "none", tags: []string{}, want: []string{}},
+		{name: "empty tag", tags: []string{"team:payments", " "}, wantErr: true},
+		{name: "space", tags: []string{"team: payments"}, wantErr: true},
+		{name: "too long", tags: []string{"team:" + strings.Repeat("a", 64)}, wantErr: true},
+		{name: "too many", tags: strings.Split(strings.Repeat("t,", domain.MaxClientTags)+"t", ","), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := domain.ParseClientTags(tt.tags)
+			if tt.wantErr != errors.Is(err, domain.ErrValidation) {
+				t.Fatalf("ParseClientTags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseClientTags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOAuthClient_Tags(t *testing.T) {
+	client := &domain.OAuthClient{Tags: []string{"team:payments", "env:staging", "legacy"}}
+
+	if !client.HasTags([]string{"env:staging", "TEAM:payments"}) {
+		t.Error("HasTags() = false, want true for tags of the client")
+	}
+	if client.HasTags([]string{"team:payments", "env:production"}) {
+		t.Error("HasTags() = true, want false when a tag is missing")
+	}
+	if got := client.TagValue(domain.ClientTagTeam); got != "payments" {
+		t.Errorf("TagValue(team) = %q, want payments", got)
+	}
+	if got := client.TagValue("owner"); got != "" {
+		t.Errorf("TagValue(owner) = %q, want empty", got)
+	}
+}
@@ -0,0 +1,41 @@
+package tests
+
+import (
+	"slices"
+	"testing"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestConsent_Covers(t *testing.T) {
+	consent := &domain.Consent{UserID: "user-123", ClientID: "cli", Scopes: []string{"read", "write"}}
+
+	tests := []struct {
+		name   string
+		scopes []string
+		want   bool
+	}{
+		{name: "same scopes", scopes: []string{"read", "write"}, want: true},
+		{name: "subset of scopes", scopes: []string{"read"}, want: true},
+		{name: "no scopes", scopes: nil, want: true},
+		{name: "new scope requested", scopes: []string{"read", "admin"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := consent.Covers(tt.scopes); got != tt.want {
+				t.Errorf("Covers(%v) = %v, want %v", tt.scopes, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConsent_Grant(t *testing.T) {
+	consent := &domain.Consent{UserID: "user-123", ClientID: "cli", Scopes: []string{"read"}}
+
+	consent.Grant([]string{"read", "write"})
+
+	if !slices.Equal(consent.Scopes, []string{"read", "write"}) {
+		t.Errorf("Scopes = %v, want [read write]", consent.Scopes)
+	}
+}
@@ -0,0 +1,36 @@
+package tests
+
+import (
+	"testing"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestOverallHealthStatus(t *testing.T) {
+	database := func(healthy bool) domain.DependencyStatus {
+		return domain.DependencyStatus{Name: "database", Criticality: domain.DependencyRequired, Healthy: healthy}
+	}
+	rabbitmq := func(healthy bool) domain.DependencyStatus {
+		return domain.DependencyStatus{Name: "rabbitmq", Criticality: domain.DependencyDegradedOK, Healthy: healthy}
+	}
+
+	tests := []struct {
+		name     string
+		statuses []domain.DependencyStatus
+		want     string
+	}{
+		{name: "no dependencies", want: domain.HealthStatusHealthy},
+		{name: "all healthy", statuses: []domain.DependencyStatus{database(true), rabbitmq(true)}, want: domain.HealthStatusHealthy},
+		{name: "degraded-ok down", statuses: []domain.DependencyStatus{database(true), rabbitmq(false)}, want: domain.HealthStatusDegraded},
+		{name: "required down", statuses: []domain.DependencyStatus{database(false), rabbitmq(true)}, want: domain.HealthStatusUnhealthy},
+		{name: "everything down", statuses: []domain.DependencyStatus{rabbitmq(false), database(false)}, want: domain.HealthStatusUnhealthy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := domain.OverallHealthStatus(tt.statuses); got != tt.want {
+				t.Errorf("OverallHealthStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestDeviceAuthorization_IsExpired(t *testing.T) {
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		want      bool
+	}{
+		{name: "not expired", expiresAt: time.Now().Add(time.Minute), want: false},
+		{name: "expired", expiresAt: time.Now().Add(-time.Minute), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth := &domain.DeviceAuthorization{ExpiresAt: tt.expiresAt}
+			if got := auth.IsExpired(); got != tt.want {
+				t.Errorf("IsExpired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeviceAuthorization_PolledTooSoon(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name         string
+		lastPolledAt time.Time
+		want         bool
+	}{
+		{name: "first poll", lastPolledAt: time.Time{}, want: false},
+		{name: "polled within interval", lastPolledAt: now.Add(-2 * time.Second), want: true},
+		{name: "polled after interval", lastPolledAt: now.Add(-6 * time.Second), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth := &domain.DeviceAuthorization{Interval: 5 * time.Second, LastPolledAt: tt.lastPolledAt}
+			if got := auth.PolledTooSoon(now); got != tt.want {
+				t.Errorf("PolledTooSoon() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestParseExportFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    domain.ExportFormat
+		wantErr bool
+	}{
+		{name: "parse csv", input: "csv", want: domain.ExportFormatCSV},
+		{name: "parse ndjson", input: "ndjson", want: domain.ExportFormatNDJSON},
+		{name: "parse invalid format", input: "xml", wantErr: true},
+		{name: "parse empty string", input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := domain.ParseExportFormat(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseExportFormat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseExportFormat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExportFilters_Validate(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		filter  interface{ Validate() error }
+		wantErr bool
+	}{
+		{name: "empty user filter", filter: domain.UserExportFilter{}},
+		{
+			name:   "complete user filter",
+			filter: domain.UserExportFilter{Role: domain.RoleAdmin, Status: domain.UserStatusActive, CreatedAfter: now.Add(-time.Hour), CreatedBefore: now},
+		},
+		{name: "unknown role", filter: domain.UserExportFilter{Role: "ROOT"}, wantErr: true},
+		{name: "unknown status", filter: domain.UserExportFilter{Status: "DELETED"}, wantErr: true},
+		{name: "inverted user range", filter: domain.UserExportFilter{CreatedAfter: now, CreatedBefore: now.Add(-time.Hour)}, wantErr: true},
+		{name: "empty user range", filter: domain.UserExportFilter{CreatedAfter: now, CreatedBefore: now}, wantErr: true},
+		{name: "open audit range", filter: domain.AuditLogFilter{Action: domain.AuditActionUserUpdated, After: now}},
+		{name: "inverted audit range", filter: domain.AuditLogFilter{After: now, Before: now.Add(-time.Minute)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.filter.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
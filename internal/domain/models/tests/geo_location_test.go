@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+var (
+	bogota   = domain.GeoLocation{Country: "CO", City: "Bogotá", Latitude: 4.711, Longitude: -74.0721}
+	medellin = domain.GeoLocation{Country: "CO", City: "Medellín", Latitude: 6.2442, Longitude: -75.5812}
+	madrid   = domain.GeoLocation{Country: "ES", City: "Madrid", Latitude: 40.4168, Longitude: -3.7038}
+)
+
+func TestGeoLocation_DistanceKm(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b domain.GeoLocation
+		want float64
+	}{
+		{name: "same location", a: bogota, b: bogota, want: 0},
+		{name: "Bogotá to Medellín", a: bogota, b: medellin, want: 240},
+		{name: "Bogotá to Madrid", a: bogota, b: madrid, want: 8030},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Allow 2% error, the haversine formula assumes a spherical Earth
+			if got := tt.a.DistanceKm(tt.b); math.Abs(got-tt.want) > tt.want*0.02+1 {
+				t.Errorf("DistanceKm() = %.0f, want ~%.0f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTravelPolicy_Evaluate(t *testing.T) {
+	policy := domain.TravelPolicy{MaxSpeedKmh: 1000, MinDistanceKm: 500}
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		previous domain.LoginLocation
+		current  domain.LoginLocation
+		want     string
+	}{
+		{
+			name:     "impossible travel",
+			previous: domain.LoginLocation{Location: bogota, At: now.Add(-2 * time.Hour)},
+			current:  domain.LoginLocation{Location: madrid, At: now},
+			want:     domain.RiskReasonImpossibleTravel,
+		},
+		{
+			name:     "simultaneous logins far apart",
+			previous: domain.LoginLocation{Location: bogota, At: now},
+			current:  domain.LoginLocation{Location: madrid, At: now},
+			want:     domain.RiskReasonImpossibleTravel,
+		},
+		{
+			name:     "plausible flight",
+			previous: domain.LoginLocation{Location: bogota, At: now.Add(-12 * time.Hour)},
+			current:  domain.LoginLocation{Location: madrid, At: now},
+		},
+		{
+			name:     "nearby city below minimum distance",
+			previous: domain.LoginLocation{Location: bogota, At: now.Add(-time.Minute)},
+			current:  domain.LoginLocation{Location: medellin, At: now},
+		},
+		{
+			name:     "different country without coordinates",
+			previous: domain.LoginLocation{Location: domain.GeoLocation{Country: "CO"}, At: now.Add(-time.Hour)},
+			current:  domain.LoginLocation{Location: domain.GeoLocation{Country: "ES"}, At: now},
+			want:     domain.RiskReasonNewCountry,
+		},
+		{
+			name:     "same country without coordinates",
+			previous: domain.LoginLocation{Location: domain.GeoLocation{Country: "CO"}, At: now.Add(-time.Hour)},
+			current:  domain.LoginLocation{Location: domain.GeoLocation{Country: "CO"}, At: now},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, _, speed := policy.Evaluate(tt.previous, tt.current)
+			if reason != tt.want {
+				t.Errorf("Evaluate() reason = %q, want %q", reason, tt.want)
+			}
+			if math.IsInf(speed, 0) || math.IsNaN(speed) {
+				t.Errorf("Evaluate() speed = %v, want a finite value", speed)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package tests
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestParseIDFormat(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    domain.IDFormat
+		wantErr bool
+	}{
+		{input: "uuidv4", want: domain.IDFormatUUIDv4},
+		{input: "UUIDv7", want: domain.IDFormatUUIDv7},
+		{input: "ulid", want: domain.IDFormatULID},
+		{input: "uuid", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := domain.ParseIDFormat(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseIDFormat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseIDFormat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIDFormat_Generator(t *testing.T) {
+	tests := []struct {
+		format  domain.IDFormat
+		version string // the version digit of the UUIDs
+		length  int
+	}{
+		{format: domain.IDFormatUUIDv4, version: "4", length: 36},
+		{format: domain.IDFormatUUIDv7, version: "7", length: 36},
+		{format: domain.IDFormatULID, length: 26},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format.String(), func(t *testing.T) {
+			newID := tt.format.Generator()
+			first, second := newID(), newID()
+
+			if len(first) != tt.length {
+				t.Errorf("len(%q) = %d, want %d", first, len(first), tt.length)
+			}
+			if first == second {
+				t.Errorf("generated the same ID twice: %q", first)
+			}
+			if tt.version != "" && first[14:15] != tt.version {
+				t.Errorf("%q is not a version %s UUID", first, tt.version)
+			}
+			if parsed, err := domain.ParseID(first); err != nil || parsed != first {
+				t.Errorf("ParseID(%q) = %q, %v, want the same ID", first, parsed, err)
+			}
+		})
+	}
+}
+
+func TestNewULID_SortsByTime(t *testing.T) {
+	earlier := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	previous := domain.NewULID(earlier)
+	for _, later := range []time.Time{earlier.Add(time.Millisecond), earlier.Add(time.Hour), earlier.AddDate(10, 0, 0)} {
+		next := domain.NewULID(later)
+		if next <= previous {
+			t.Errorf("ULID of %s = %q, want after %q", later, next, previous)
+		}
+		previous = next
+	}
+}
+
+func TestNewULID_EncodesTimestamp(t *testing.T) {
+	// The first 10 characters are the timestamp in milliseconds
+	if got := domain.NewULID(time.UnixMilli(0)); !strings.HasPrefix(got, "0000000000") {
+		t.Errorf("NewULID(epoch) = %q, want a zero timestamp", got)
+	}
+	if got := domain.NewULID(time.UnixMilli(1<<48 - 1)); !strings.HasPrefix(got, "7ZZZZZZZZZ") {
+		t.Errorf("NewULID(max) = %q, want the maximum timestamp", got)
+	}
+}
+
+func TestParseID(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "existing UUIDv4",
+			input: "3f2504e0-4f89-41d3-9a0c-0305e82c3301",
+			want:  "3f2504e0-4f89-41d3-9a0c-0305e82c3301",
+		},
+		{
+			name:  "UUID in uppercase",
+			input: "3F2504E0-4F89-41D3-9A0C-0305E82C3301",
+			want:  "3f2504e0-4f89-41d3-9a0c-0305e82c3301",
+		},
+		{
+			name:  "UUIDv7",
+			input: "01928f6e-8c3a-7b1e-9f3d-2a4b5c6d7e8f",
+			want:  "01928f6e-8c3a-7b1e-9f3d-2a4b5c6d7e8f",
+		},
+		{
+			name:  "ULID",
+			input: "01ARZ3NDEKTSV4RRFFQ69G5FAV",
+			want:  "01ARZ3NDEKTSV4RRFFQ69G5FAV",
+		},
+		{
+			name:  "ULID in lowercase",
+			input: "01arz3ndektsv4rrffq69g5fav",
+			want:  "01ARZ3NDEKTSV4RRFFQ69G5FAV",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := domain.ParseID(tt.input)
+			if err != nil {
+				t.Fatalf("ParseID() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseID_Invalid(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"42",
+		"not-an-id",
+		"3f2504e0-4f89-41d3-9a0c-0305e82c330",
+		"01ARZ3NDEKTSV4RRFFQ69G5FAU", // U is not in the alphabet
+		"81ARZ3NDEKTSV4RRFFQ69G5FAV", // overflows 128 bits
+	} {
+		if _, err := domain.ParseID(input); !errors.Is(err, domain.ErrInvalidID) {
+			t.Errorf("ParseID(%q) error = %v, want ErrInvalidID", input, err)
+		}
+		if domain.IsValidID(input) {
+			t.Errorf("IsValidID(%q) = true, want false", input)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestParseLoginNotificationChannels(t *testing.T) {
+	channels, err := domain.ParseLoginNotificationChannels([]string{"Email", " event ", "email"})
+	if err != nil {
+		t.Fatalf("ParseLoginNotificationChannels() error = %v", err)
+	}
+	want := []domain.LoginNotificationChannel{domain.LoginNotificationChannelEmail, domain.LoginNotificationChannelEvent}
+	if !slices.Equal(channels, want) {
+		t.Errorf("ParseLoginNotificationChannels() = %v, want %v", channels, want)
+	}
+
+	if channels, err := domain.ParseLoginNotificationChannels(nil); err != nil || len(channels) != 0 {
+		t.Errorf("ParseLoginNotificationChannels(nil) = %v, %v, want no channels", channels, err)
+	}
+	if _, err := domain.ParseLoginNotificationChannels([]string{"sms"}); err == nil {
+		t.Error("ParseLoginNotificationChannels(sms) error = nil, want an error")
+	}
+}
+
+func TestRedactIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want string
+	}{
+		{ip: "203.0.113.42", want: "203.0.113.0/24"},
+		{ip: "::ffff:203.0.113.42", want: "203.0.113.0/24"},
+		{ip: "2001:db8:85a3:8d3:1319:8a2e:370:7348", want: "2001:db8:85a3::/48"},
+		{ip: "not-an-ip", want: ""},
+		{ip: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			if got := domain.RedactIP(tt.ip); got != tt.want {
+				t.Errorf("RedactIP(%q) = %q, want %q", tt.ip, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescribeDevice(t *testing.T) {
+	tests := []struct {
+		name      string
+		userAgent string
+		want      string
+	}{
+		{
+			name:      "chrome on windows",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36",
+			want:      "Chrome on Windows",
+		},
+		{
+			name:      "edge on windows",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36 Edg/126.0.0.0",
+			want:      "Edge on Windows",
+		},
+		{
+			name:      "firefox on linux",
+			userAgent: "Mozilla/5.0 (X11; Linux x86_64; rv:127.0) Gecko/20100101 Firefox/127.0",
+			want:      "Firefox on Linux",
+		},
+		{
+			name:      "chrome on android",
+			userAgent: "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Mobile Safari/537.36",
+			want:      "Chrome on Android",
+		},
+		{
+			name:      "safari on ios",
+			userAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Mobile/15E148 Safari/604.1",
+			want:      "Safari on iOS",
+		},
+		{name: "command line client", userAgent: "curl/8.5.0", want: "unknown device"},
+		{name: "empty", userAgent: "", want: "unknown device"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := domain.DescribeDevice(tt.userAgent); got != tt.want {
+				t.Errorf("DescribeDevice() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewLoginNotification_Details(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	info := domain.ClientInfo{IP: "203.0.113.42", UserAgent: "Mozilla/5.0 (X11; Linux x86_64; rv:127.0) Gecko/20100101 Firefox/127.0"}
+	geo := &domain.LoginRisk{IP: "203.0.113.42", Country: "CO", City: "Medellín"}
+
+	details := domain.NewLoginNotification(info, geo, true, false, at).Details()
+
+	want := map[string]string{
+		"new_device": "true",
+		"new_ip":     "false",
+		"device":     "Firefox on Linux",
+		"network":    "203.0.113.0/24",
+		"country":    "CO",
+		"city":       "Medellín",
+		"login_at":   "2026-03-01T12:00:00Z",
+	}
+	if len(details) != len(want) {
+		t.Fatalf("Details() = %v, want %v", details, want)
+	}
+	for key, value := range want {
+		if details[key] != value {
+			t.Errorf("Details()[%s] = %q, want %q", key, details[key], value)
+		}
+	}
+
+	// The location is left out when it is unknown
+	details = domain.NewLoginNotification(info, nil, true, true, at).Details()
+	if _, ok := details["country"]; ok {
+		t.Errorf("Details() = %v, want no country without a location", details)
+	}
+}
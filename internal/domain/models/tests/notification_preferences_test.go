@@ -0,0 +1,46 @@
+package tests
+
+import (
+	"testing"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestDefaultNotificationPreferences(t *testing.T) {
+	prefs := domain.DefaultNotificationPreferences("user-123")
+
+	if prefs.UserID != "user-123" {
+		t.Errorf("UserID = %v, want user-123", prefs.UserID)
+	}
+	if !prefs.NewDevice || !prefs.PasswordChange || !prefs.LoginAlert {
+		t.Errorf("DefaultNotificationPreferences() = %+v, want all notifications enabled", prefs)
+	}
+}
+
+func TestNotificationPreferences_Allows(t *testing.T) {
+	prefs := &domain.NotificationPreferences{
+		NewDevice:      true,
+		PasswordChange: false,
+		LoginAlert:     true,
+	}
+
+	tests := []struct {
+		name             string
+		notificationType domain.NotificationType
+		want             bool
+	}{
+		{name: "new device enabled", notificationType: domain.NotificationNewDevice, want: true},
+		{name: "password change disabled", notificationType: domain.NotificationPasswordChange, want: false},
+		{name: "login alert enabled", notificationType: domain.NotificationLoginAlert, want: true},
+		{name: "suspicious login is mandatory", notificationType: domain.NotificationSuspiciousLogin, want: true},
+		{name: "unknown type", notificationType: domain.NotificationType("unknown"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := prefs.Allows(tt.notificationType); got != tt.want {
+				t.Errorf("Allows(%v) = %v, want %v", tt.notificationType, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package tests
 
 import (
+	"errors"
+	"reflect"
 	"testing"
 
 	"golang.org/x/crypto/bcrypt"
@@ -162,3 +164,61 @@ func TestOAuthClient_ValidateSecret(t *testing.T) {
 		})
 	}
 }
+
+func TestParseGrantTypes(t *testing.T) {
+	tests := []struct {
+		name       string
+		grantTypes []string
+		want       []string
+		wantErr    bool
+	}{
+		{name: "registered names", grantTypes: []string{"client_credentials", "authorization_code"}, want: []string{domain.GrantTypeClientCredentials, domain.GrantTypeAuthorizationCode}},
+		{name: "short names", grantTypes: []string{"device_code", "token_exchange"}, want: []string{domain.GrantTypeDeviceCode, domain.GrantTypeTokenExchange}},
+		{name: "duplicates", grantTypes: []string{"device_code", domain.GrantTypeDeviceCode}, want: []string{domain.GrantTypeDeviceCode}},
+		{name: "password grant", grantTypes: []string{"password"}, wantErr: true},
+		{name: "unknown grant type", grantTypes: []string{"client_credentials", "implicit"}, wantErr: true},
+		{name: "empty", grantTypes: []string{}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := domain.ParseGrantTypes(tt.grantTypes)
+			if tt.wantErr {
+				if !errors.Is(err, domain.ErrValidation) {
+					t.Errorf("ParseGrantTypes() error = %v, want %v", err, domain.ErrValidation)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseGrantTypes() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseGrantTypes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOAuthClient_AllowsGrantType(t *testing.T) {
+	tests := []struct {
+		name       string
+		grantTypes []string
+		grantType  string
+		want       bool
+	}{
+		{name: "allowed", grantTypes: []string{domain.GrantTypeClientCredentials}, grantType: domain.GrantTypeClientCredentials, want: true},
+		{name: "not allowed", grantTypes: []string{domain.GrantTypeClientCredentials}, grantType: domain.GrantTypeDeviceCode, want: false},
+		{name: "defaults allow client credentials", grantType: domain.GrantTypeClientCredentials, want: true},
+		{name: "defaults allow device code", grantType: domain.GrantTypeDeviceCode, want: true},
+		{name: "defaults deny token exchange", grantType: domain.GrantTypeTokenExchange, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &domain.OAuthClient{GrantTypes: tt.grantTypes}
+			if got := client.AllowsGrantType(tt.grantType); got != tt.want {
+				t.Errorf("AllowsGrantType(%q) = %v, want %v", tt.grantType, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestNormalizePhoneNumber(t *testing.T) {
+	tests := []struct {
+		name   string
+		raw    string
+		want   string
+		wantOK bool
+	}{
+		{name: "E.164", raw: "+573001234567", want: "+573001234567", wantOK: true},
+		{name: "with separators", raw: " +57 (300) 123-4567 ", want: "+573001234567", wantOK: true},
+		{name: "missing plus sign", raw: "573001234567", wantOK: false},
+		{name: "leading zero country code", raw: "+0573001234567", wantOK: false},
+		{name: "too short", raw: "+5730012", wantOK: false},
+		{name: "too long", raw: "+5730012345678901", wantOK: false},
+		{name: "letters", raw: "+57300ABC4567", wantOK: false},
+		{name: "empty", raw: "", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := domain.NormalizePhoneNumber(tt.raw)
+			if ok != tt.wantOK {
+				t.Fatalf("NormalizePhoneNumber(%q) ok = %v, want %v", tt.raw, ok, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizePhoneNumber(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaskPhoneNumber(t *testing.T) {
+	tests := []struct {
+		number string
+		want   string
+	}{
+		{number: "+573001234567", want: "*********4567"},
+		{number: "1234", want: "****"},
+		{number: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := domain.MaskPhoneNumber(tt.number); got != tt.want {
+			t.Errorf("MaskPhoneNumber(%q) = %q, want %q", tt.number, got, tt.want)
+		}
+	}
+}
+
+func TestPhoneVerification_IsExpired(t *testing.T) {
+	active := &domain.PhoneVerification{ExpiresAt: time.Now().Add(time.Minute)}
+	if active.IsExpired() {
+		t.Error("IsExpired() = true for a verification expiring in the future")
+	}
+
+	expired := &domain.PhoneVerification{ExpiresAt: time.Now().Add(-time.Minute)}
+	if !expired.IsExpired() {
+		t.Error("IsExpired() = false for an expired verification")
+	}
+}
+
+func TestPhoneVerification_AttemptsExhausted(t *testing.T) {
+	v := &domain.PhoneVerification{Attempts: domain.MaxPhoneVerificationAttempts - 1}
+	if v.AttemptsExhausted() {
+		t.Error("AttemptsExhausted() = true below the maximum")
+	}
+
+	v.Attempts++
+	if !v.AttemptsExhausted() {
+		t.Error("AttemptsExhausted() = false at the maximum")
+	}
+}
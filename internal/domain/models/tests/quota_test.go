@@ -0,0 +1,39 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestIssuanceQuota_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		quota   domain.IssuanceQuota
+		wantErr bool
+	}{
+		{name: "client token quota", quota: domain.IssuanceQuota{SubjectType: domain.QuotaSubjectClient, SubjectID: "client-123", MaxTokensPerHour: 100}},
+		{name: "user quotas", quota: domain.IssuanceQuota{SubjectType: domain.QuotaSubjectUser, SubjectID: "user-123", MaxTokensPerHour: 20, MaxActiveSessions: 3}},
+		{name: "unlimited", quota: domain.IssuanceQuota{SubjectType: domain.QuotaSubjectUser, SubjectID: "user-123"}},
+		{name: "unknown subject type", quota: domain.IssuanceQuota{SubjectType: "tenant", SubjectID: "acme"}, wantErr: true},
+		{name: "negative token quota", quota: domain.IssuanceQuota{SubjectType: domain.QuotaSubjectClient, SubjectID: "client-123", MaxTokensPerHour: -1}, wantErr: true},
+		{name: "negative session quota", quota: domain.IssuanceQuota{SubjectType: domain.QuotaSubjectUser, SubjectID: "user-123", MaxActiveSessions: -1}, wantErr: true},
+		{name: "session quota on a client", quota: domain.IssuanceQuota{SubjectType: domain.QuotaSubjectClient, SubjectID: "client-123", MaxActiveSessions: 2}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.quota.Validate()
+			if tt.wantErr {
+				if !errors.Is(err, domain.ErrValidation) {
+					t.Errorf("Validate() error = %v, want ErrValidation", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Validate() unexpected error = %v", err)
+			}
+		})
+	}
+}
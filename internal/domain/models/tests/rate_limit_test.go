@@ -0,0 +1,48 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestRateLimitStatus(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name          string
+		status        domain.RateLimitStatus
+		wantRemaining int
+		wantExceeded  bool
+		wantReset     int64
+	}{
+		{name: "within limit", status: domain.RateLimitStatus{Limit: 10, Used: 3, ResetAt: now.Add(30 * time.Second)}, wantRemaining: 7, wantReset: 30},
+		{name: "at limit", status: domain.RateLimitStatus{Limit: 10, Used: 10, ResetAt: now.Add(1500 * time.Millisecond)}, wantRemaining: 0, wantReset: 2},
+		{name: "over limit", status: domain.RateLimitStatus{Limit: 10, Used: 12, ResetAt: now.Add(time.Second)}, wantRemaining: 0, wantExceeded: true, wantReset: 1},
+		{name: "window already reset", status: domain.RateLimitStatus{Limit: 10, Used: 1, ResetAt: now.Add(-time.Second)}, wantRemaining: 9, wantReset: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.status.Remaining(); got != tt.wantRemaining {
+				t.Errorf("Remaining() = %v, want %v", got, tt.wantRemaining)
+			}
+			if got := tt.status.Exceeded(); got != tt.wantExceeded {
+				t.Errorf("Exceeded() = %v, want %v", got, tt.wantExceeded)
+			}
+			if got := tt.status.ResetSeconds(now); got != tt.wantReset {
+				t.Errorf("ResetSeconds() = %v, want %v", got, tt.wantReset)
+			}
+		})
+	}
+}
+
+func TestRateLimitKeys(t *testing.T) {
+	if got := domain.UserRateLimitKey(12345); got != "user:12345" {
+		t.Errorf("UserRateLimitKey() = %v, want user:12345", got)
+	}
+	if got := domain.ClientRateLimitKey("cli"); got != "client:cli" {
+		t.Errorf("ClientRateLimitKey() = %v, want client:cli", got)
+	}
+}
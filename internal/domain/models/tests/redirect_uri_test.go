@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestValidateRedirectURI(t *testing.T) {
+	tests := []struct {
+		name        string
+		redirectURI string
+		wantErr     bool
+	}{
+		{name: "https", redirectURI: "https://app.example.com/callback"},
+		{name: "https with query", redirectURI: "https://app.example.com/callback?tenant=1"},
+		{name: "loopback ipv4", redirectURI: "http://127.0.0.1/callback"},
+		{name: "loopback ipv6", redirectURI: "http://[::1]:8080/callback"},
+		{name: "private-use scheme", redirectURI: "com.example.app:/oauth2redirect"},
+		{name: "http", redirectURI: "http://app.example.com/callback", wantErr: true},
+		{name: "http localhost", redirectURI: "http://localhost/callback", wantErr: true},
+		{name: "fragment", redirectURI: "https://app.example.com/callback#section", wantErr: true},
+		{name: "empty fragment", redirectURI: "https://app.example.com/callback#", wantErr: true},
+		{name: "relative", redirectURI: "/callback", wantErr: true},
+		{name: "user info", redirectURI: "https://app.example.com@evil.example.com/callback", wantErr: true},
+		{name: "javascript", redirectURI: "javascript:alert(1)", wantErr: true},
+		{name: "https without host", redirectURI: "https:/callback", wantErr: true},
+		{name: "empty", redirectURI: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := domain.ValidateRedirectURI(tt.redirectURI)
+			if tt.wantErr != errors.Is(err, domain.ErrValidation) {
+				t.Errorf("ValidateRedirectURI(%q) error = %v, wantErr %v", tt.redirectURI, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseRedirectURIs(t *testing.T) {
+	tooMany := make([]string, domain.MaxRedirectURIs+1)
+	for i := range tooMany {
+		tooMany[i] = "https://app.example.com/callback/" + strconv.Itoa(i)
+	}
+
+	tests := []struct {
+		name         string
+		redirectURIs []string
+		want         []string
+		wantErr      bool
+	}{
+		{name: "none", redirectURIs: []string{}, want: []string{}},
+		{name: "duplicates", redirectURIs: []string{"https://a.example.com/cb", "https://a.example.com/cb"}, want: []string{"https://a.example.com/cb"}},
+		{name: "invalid", redirectURIs: []string{"https://a.example.com/cb", "http://a.example.com/cb"}, wantErr: true},
+		{name: "too many", redirectURIs: tooMany, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := domain.ParseRedirectURIs(tt.redirectURIs)
+			if tt.wantErr {
+				if !errors.Is(err, domain.ErrValidation) {
+					t.Errorf("ParseRedirectURIs() error = %v, want %v", err, domain.ErrValidation)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRedirectURIs() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRedirectURIs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOAuthClient_MatchesRedirectURI(t *testing.T) {
+	client := &domain.OAuthClient{RedirectURIs: []string{
+		"https://app.example.com/callback",
+		"http://127.0.0.1/native/callback",
+		"http://[::1]:8080/callback",
+		"com.example.app:/oauth2redirect",
+	}}
+
+	tests := []struct {
+		name      string
+		requested string
+		want      bool
+	}{
+		{name: "exact", requested: "https://app.example.com/callback", want: true},
+		{name: "private-use scheme", requested: "com.example.app:/oauth2redirect", want: true},
+		{name: "empty", requested: "", want: false},
+		{name: "other path", requested: "https://app.example.com/callback/other", want: false},
+		{name: "trailing slash", requested: "https://app.example.com/callback/", want: false},
+		{name: "extra query", requested: "https://app.example.com/callback?next=https://evil.example.com", want: false},
+		{name: "upper case host", requested: "https://APP.example.com/callback", want: false},
+		{name: "explicit default port", requested: "https://app.example.com:443/callback", want: false},
+		{name: "other host", requested: "https://evil.example.com/callback", want: false},
+		{name: "user info", requested: "https://app.example.com@evil.example.com/callback", want: false},
+		{name: "fragment", requested: "https://app.example.com/callback#x", want: false},
+		{name: "loopback any port", requested: "http://127.0.0.1:51234/native/callback", want: true},
+		{name: "loopback without port", requested: "http://127.0.0.1/native/callback", want: true},
+		{name: "loopback ipv6 other port", requested: "http://[::1]:9090/callback", want: true},
+		{name: "loopback other path", requested: "http://127.0.0.1:51234/other", want: false},
+		{name: "loopback other address", requested: "http://127.0.0.2:51234/native/callback", want: false},
+		{name: "loopback localhost", requested: "http://localhost:51234/native/callback", want: false},
+		{name: "loopback https", requested: "https://127.0.0.1:51234/native/callback", want: false},
+		{name: "loopback extra query", requested: "http://127.0.0.1:51234/native/callback?x=1", want: false},
+		{name: "loopback user info", requested: "http://evil@127.0.0.1:51234/native/callback", want: false},
+		{name: "loopback fragment", requested: "http://127.0.0.1:51234/native/callback#x", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := client.MatchesRedirectURI(tt.requested); got != tt.want {
+				t.Errorf("MatchesRedirectURI(%q) = %v, want %v", tt.requested, got, tt.want)
+			}
+		})
+	}
+}
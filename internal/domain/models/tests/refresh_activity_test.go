@@ -0,0 +1,48 @@
+package tests
+
+import (
+	"slices"
+	"testing"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestRefreshAnomalyPolicy_Evaluate(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   domain.RefreshAnomalyPolicy
+		activity domain.RefreshActivity
+		want     []string
+	}{
+		{
+			name:     "within thresholds",
+			policy:   domain.RefreshAnomalyPolicy{MaxDistinctIPs: 3, MaxRefreshes: 10},
+			activity: domain.RefreshActivity{DistinctIPs: 3, Refreshes: 10},
+		},
+		{
+			name:     "too many IPs",
+			policy:   domain.RefreshAnomalyPolicy{MaxDistinctIPs: 3, MaxRefreshes: 10},
+			activity: domain.RefreshActivity{DistinctIPs: 4, Refreshes: 1},
+			want:     []string{domain.RiskReasonRefreshManyIPs},
+		},
+		{
+			name:     "too many refreshes",
+			policy:   domain.RefreshAnomalyPolicy{MaxDistinctIPs: 3, MaxRefreshes: 10},
+			activity: domain.RefreshActivity{DistinctIPs: 1, Refreshes: 11},
+			want:     []string{domain.RiskReasonRefreshHighFrequency},
+		},
+		{
+			name:     "zero thresholds disable the checks",
+			policy:   domain.RefreshAnomalyPolicy{},
+			activity: domain.RefreshActivity{DistinctIPs: 50, Refreshes: 500},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Evaluate(tt.activity); !slices.Equal(got, tt.want) {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
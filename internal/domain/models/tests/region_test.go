@@ -0,0 +1,49 @@
+package tests
+
+import (
+	"testing"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestIsValidRegion(t *testing.T) {
+	tests := []struct {
+		region string
+		want   bool
+	}{
+		{region: "us-east-1", want: true},
+		{region: "eu", want: true},
+		{region: "", want: false},
+		{region: "US-East", want: false},
+		{region: "us.east", want: false},
+		{region: "us_east", want: false},
+		{region: "a-very-long-region-identifier-over-32", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.region, func(t *testing.T) {
+			if got := domain.IsValidRegion(tt.region); got != tt.want {
+				t.Errorf("IsValidRegion(%q) = %v, want %v", tt.region, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegionalTokenID(t *testing.T) {
+	jti := domain.RegionalTokenID("us-east-1", "3f2a6c1e-0d4b-4a8e-9c1f-2b7d5e8a9f01")
+	if jti != "us-east-1.3f2a6c1e-0d4b-4a8e-9c1f-2b7d5e8a9f01" {
+		t.Errorf("RegionalTokenID() = %q", jti)
+	}
+	if region := domain.TokenIDRegion(jti); region != "us-east-1" {
+		t.Errorf("TokenIDRegion(%q) = %q, want us-east-1", jti, region)
+	}
+
+	if jti := domain.RegionalTokenID("", "token-1"); jti != "token-1" {
+		t.Errorf("RegionalTokenID() without region = %q, want token-1", jti)
+	}
+	for _, jti := range []string{"3f2a6c1e-0d4b-4a8e-9c1f-2b7d5e8a9f01", "US.token", ".token"} {
+		if region := domain.TokenIDRegion(jti); region != "" {
+			t.Errorf("TokenIDRegion(%q) = %q, want no region", jti, region)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"slices"
+	"testing"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestParseRiskPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  string
+		wantErr bool
+	}{
+		{name: "valid policy", policy: `{"ip_lists": {"tor": ["198.51.100.7", "2001:db8::/32"]}, "rules": [{"name": "tor", "decision": "deny", "when": {"ip_reputation": ["tor"]}}]}`},
+		{name: "empty policy", policy: `{}`},
+		{name: "unknown field", policy: `{"rulez": []}`, wantErr: true},
+		{name: "unknown default decision", policy: `{"default_decision": "maybe"}`, wantErr: true},
+		{name: "unknown rule decision", policy: `{"rules": [{"name": "r", "decision": "block"}]}`, wantErr: true},
+		{name: "missing rule name", policy: `{"rules": [{"decision": "deny"}]}`, wantErr: true},
+		{name: "duplicate rule name", policy: `{"rules": [{"name": "r", "decision": "deny"}, {"name": "r", "decision": "allow"}]}`, wantErr: true},
+		{name: "invalid CIDR", policy: `{"ip_lists": {"bad": ["10.0.0.0/99"]}}`, wantErr: true},
+		{name: "unknown ip list", policy: `{"rules": [{"name": "r", "decision": "deny", "when": {"ip_reputation": ["tor"]}}]}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := domain.ParseRiskPolicy([]byte(tt.policy))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRiskPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && policy.DefaultDecision != domain.RiskDecisionAllow {
+				t.Errorf("DefaultDecision = %v, want allow", policy.DefaultDecision)
+			}
+		})
+	}
+}
+
+func TestRiskPolicy_IPReputation(t *testing.T) {
+	policy, err := domain.ParseRiskPolicy([]byte(`{"ip_lists": {"tor": ["198.51.100.7"], "cloud": ["198.51.100.0/24", "2001:db8::/32"]}}`))
+	if err != nil {
+		t.Fatalf("ParseRiskPolicy() error = %v", err)
+	}
+
+	tests := []struct {
+		ip   string
+		want []string
+	}{
+		{ip: "198.51.100.7", want: []string{"cloud", "tor"}},
+		{ip: "198.51.100.8", want: []string{"cloud"}},
+		{ip: "2001:db8::1", want: []string{"cloud"}},
+		{ip: "192.0.2.1", want: nil},
+		{ip: "not-an-ip", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			if got := policy.IPReputation(tt.ip); !slices.Equal(got, tt.want) {
+				t.Errorf("IPReputation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRiskPolicy_Evaluate(t *testing.T) {
+	policy, err := domain.ParseRiskPolicy([]byte(`{
+		"default_decision": "step_up",
+		"rules": [
+			{"name": "trusted-device", "decision": "allow", "when": {"new_device": false}},
+			{"name": "brute-force", "decision": "deny", "when": {"flows": ["login"], "min_recent_failures": 10}},
+			{"name": "new-country", "decision": "step_up", "when": {"geo_reasons": ["new_country"]}}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("ParseRiskPolicy() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		signals      domain.RiskSignals
+		wantDecision domain.RiskDecision
+		wantRules    []string
+	}{
+		{name: "default decision", signals: domain.RiskSignals{Flow: domain.RiskFlowLogin, NewDevice: true}, wantDecision: domain.RiskDecisionStepUp},
+		{name: "single rule", signals: domain.RiskSignals{Flow: domain.RiskFlowLogin}, wantDecision: domain.RiskDecisionAllow, wantRules: []string{"trusted-device"}},
+		{
+			name:         "strictest decision wins",
+			signals:      domain.RiskSignals{Flow: domain.RiskFlowLogin, RecentFailures: 12, GeoReasons: []string{domain.RiskReasonNewCountry}},
+			wantDecision: domain.RiskDecisionDeny,
+			wantRules:    []string{"trusted-device", "brute-force", "new-country"},
+		},
+		{name: "flow mismatch", signals: domain.RiskSignals{Flow: domain.RiskFlowRefresh, RecentFailures: 12}, wantDecision: domain.RiskDecisionAllow, wantRules: []string{"trusted-device"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision, rules := policy.Evaluate(tt.signals)
+			if decision != tt.wantDecision {
+				t.Errorf("Evaluate() decision = %v, want %v", decision, tt.wantDecision)
+			}
+			if !slices.Equal(rules, tt.wantRules) {
+				t.Errorf("Evaluate() rules = %v, want %v", rules, tt.wantRules)
+			}
+		})
+	}
+}
+
+func TestRiskPolicy_TriggeredByFailures(t *testing.T) {
+	policy, err := domain.ParseRiskPolicy([]byte(`{
+		"rules": [
+			{"name": "brute-force", "decision": "deny", "when": {"flows": ["login"], "min_recent_failures": 10}},
+			{"name": "new-country", "decision": "step_up", "when": {"geo_reasons": ["new_country"]}}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("ParseRiskPolicy() error = %v", err)
+	}
+
+	if !policy.TriggeredByFailures([]string{"new-country", "brute-force"}) {
+		t.Errorf("TriggeredByFailures() = false, want true when a failure rule matched")
+	}
+	if policy.TriggeredByFailures([]string{"new-country"}) || policy.TriggeredByFailures(nil) {
+		t.Errorf("TriggeredByFailures() = true, want false when no failure rule matched")
+	}
+}
@@ -0,0 +1,43 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestNewScope(t *testing.T) {
+	tests := []struct {
+		name      string
+		scopeName string
+		wantErr   bool
+	}{
+		{name: "simple name", scopeName: "read", wantErr: false},
+		{name: "namespaced name", scopeName: "users:write", wantErr: false},
+		{name: "dotted name", scopeName: "reports.export", wantErr: false},
+		{name: "empty name", scopeName: "", wantErr: true},
+		{name: "uppercase name", scopeName: "Read", wantErr: true},
+		{name: "name with spaces", scopeName: "read write", wantErr: true},
+		{name: "name starting with digit", scopeName: "1read", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scope, err := domain.NewScope(tt.scopeName, "description")
+
+			if tt.wantErr {
+				if !errors.Is(err, domain.ErrValidation) {
+					t.Errorf("NewScope() error = %v, want ErrValidation", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewScope() unexpected error = %v", err)
+			}
+			if scope.Name != tt.scopeName || scope.System {
+				t.Errorf("NewScope() = %+v", scope)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestSignedClientRequest_VerifySignature(t *testing.T) {
+	key, err := domain.GenerateRequestSigningKey()
+	if err != nil {
+		t.Fatalf("GenerateRequestSigningKey() error = %v", err)
+	}
+	signature := domain.ClientRequestSignature(key, "svc", 1760601600, "0123456789abcdef")
+
+	tests := []struct {
+		name     string
+		key      string
+		clientID string
+		request  domain.SignedClientRequest
+		want     bool
+	}{
+		{name: "valid signature", key: key, clientID: "svc", request: domain.SignedClientRequest{Timestamp: 1760601600, Nonce: "0123456789abcdef", Signature: signature}, want: true},
+		{name: "altered timestamp", key: key, clientID: "svc", request: domain.SignedClientRequest{Timestamp: 1760601601, Nonce: "0123456789abcdef", Signature: signature}},
+		{name: "altered nonce", key: key, clientID: "svc", request: domain.SignedClientRequest{Timestamp: 1760601600, Nonce: "0123456789abcdeX", Signature: signature}},
+		{name: "other client", key: key, clientID: "other", request: domain.SignedClientRequest{Timestamp: 1760601600, Nonce: "0123456789abcdef", Signature: signature}},
+		{name: "client without key", clientID: "svc", request: domain.SignedClientRequest{Timestamp: 1760601600, Nonce: "0123456789abcdef", Signature: signature}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.request.VerifySignature(tt.key, tt.clientID); got != tt.want {
+				t.Errorf("VerifySignature() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignedClientRequest_Fresh(t *testing.T) {
+	now := time.Unix(1760601600, 0)
+
+	tests := []struct {
+		name      string
+		timestamp time.Time
+		want      bool
+	}{
+		{name: "current", timestamp: now, want: true},
+		{name: "within skew in the past", timestamp: now.Add(-4 * time.Minute), want: true},
+		{name: "within skew in the future", timestamp: now.Add(4 * time.Minute), want: true},
+		{name: "too old", timestamp: now.Add(-6 * time.Minute)},
+		{name: "too far in the future", timestamp: now.Add(6 * time.Minute)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := domain.SignedClientRequest{Timestamp: tt.timestamp.Unix()}
+			if got := request.Fresh(now, 5*time.Minute); got != tt.want {
+				t.Errorf("Fresh() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestNormalizeEmail(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		want   string
+		wantOK bool
+	}{
+		{name: "bare address", input: "user@example.com", want: "user@example.com", wantOK: true},
+		{name: "trimmed and lowercased", input: "  User@Example.COM ", want: "user@example.com", wantOK: true},
+		{name: "display name", input: "User <user@example.com>", wantOK: false},
+		{name: "missing domain", input: "user@", wantOK: false},
+		{name: "empty string", input: "", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := domain.NormalizeEmail(tt.input)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("NormalizeEmail() = (%v, %v), want (%v, %v)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestMaskEmail(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "user@example.com", want: "u***@example.com"},
+		{input: "a@example.com", want: "a@example.com"},
+		{input: "invalid", want: "*******"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := domain.MaskEmail(tt.input); got != tt.want {
+				t.Errorf("MaskEmail() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserEmail_Verification(t *testing.T) {
+	email := &domain.UserEmail{Email: "user@example.com"}
+	if email.IsVerified() || email.HasPendingCode() {
+		t.Fatalf("new email: verified = %v, pending = %v, want neither", email.IsVerified(), email.HasPendingCode())
+	}
+
+	email.SetVerificationCode("hash", time.Now().Add(time.Minute))
+	if !email.HasPendingCode() {
+		t.Error("HasPendingCode() = false after SetVerificationCode")
+	}
+
+	email.CodeAttempts = domain.MaxEmailVerificationAttempts
+	if email.HasPendingCode() {
+		t.Error("HasPendingCode() = true after too many attempts")
+	}
+
+	email.MarkVerified(time.Now())
+	if !email.IsVerified() || email.CodeHash != "" || email.CodeAttempts != 0 {
+		t.Errorf("MarkVerified() = %+v, want verified without code", email)
+	}
+}
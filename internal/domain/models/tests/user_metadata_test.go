@@ -0,0 +1,118 @@
+package tests
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestParseUserMetadataSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    domain.UserMetadataSchema
+		wantErr bool
+	}{
+		{name: "empty", spec: "", want: domain.UserMetadataSchema{}},
+		{
+			name: "every type",
+			spec: "department:string, employee_level:number ,newsletter:boolean",
+			want: domain.UserMetadataSchema{
+				"department":     domain.MetadataTypeString,
+				"employee_level": domain.MetadataTypeNumber,
+				"newsletter":     domain.MetadataTypeBoolean,
+			},
+		},
+		{name: "missing type", spec: "department", wantErr: true},
+		{name: "unknown type", spec: "department:object", wantErr: true},
+		{name: "invalid key", spec: "Department:string", wantErr: true},
+		{name: "key too long", spec: strings.Repeat("a", domain.MaxMetadataKeyLength+1) + ":string", wantErr: true},
+		{name: "duplicate key", spec: "department:string,department:number", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := domain.ParseUserMetadataSchema(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseUserMetadataSchema() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, domain.ErrValidation) {
+					t.Errorf("ParseUserMetadataSchema() error = %v, want %v", err, domain.ErrValidation)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseUserMetadataSchema() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserMetadataSchema_ValidateChanges(t *testing.T) {
+	schema := domain.UserMetadataSchema{
+		"department":     domain.MetadataTypeString,
+		"employee_level": domain.MetadataTypeNumber,
+		"newsletter":     domain.MetadataTypeBoolean,
+	}
+
+	tests := []struct {
+		name    string
+		changes domain.UserMetadata
+		wantErr bool
+	}{
+		{name: "values of the declared types", changes: domain.UserMetadata{"department": "sales", "employee_level": float64(3), "newsletter": true}},
+		{name: "removal", changes: domain.UserMetadata{"department": nil}},
+		{name: "undeclared key", changes: domain.UserMetadata{"nickname": "bob"}, wantErr: true},
+		{name: "string for a number", changes: domain.UserMetadata{"employee_level": "3"}, wantErr: true},
+		{name: "number for a boolean", changes: domain.UserMetadata{"newsletter": float64(1)}, wantErr: true},
+		{name: "object value", changes: domain.UserMetadata{"department": map[string]interface{}{"name": "sales"}}, wantErr: true},
+		{name: "string too long", changes: domain.UserMetadata{"department": strings.Repeat("a", domain.MaxMetadataStringLength+1)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schema.ValidateChanges(tt.changes)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateChanges() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserMetadata_Apply(t *testing.T) {
+	metadata := domain.UserMetadata{"department": "sales", "newsletter": true}
+
+	got := metadata.Apply(domain.UserMetadata{"department": "support", "newsletter": nil, "employee_level": float64(2)})
+
+	want := domain.UserMetadata{"department": "support", "employee_level": float64(2)}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Apply() = %v, want %v", got, want)
+	}
+	if metadata["department"] != "sales" || metadata["newsletter"] != true {
+		t.Errorf("Apply() modified the original metadata: %v", metadata)
+	}
+}
+
+func TestUserMetadata_Select(t *testing.T) {
+	metadata := domain.UserMetadata{"department": "sales", "employee_level": float64(3)}
+
+	if got, want := metadata.Select([]string{"department", "tenant"}), (domain.UserMetadata{"department": "sales"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Select() = %v, want %v", got, want)
+	}
+	if got := metadata.Select([]string{"tenant"}); got != nil {
+		t.Errorf("Select() = %v, want nil", got)
+	}
+	if got := domain.UserMetadata(nil).Select([]string{"department"}); got != nil {
+		t.Errorf("Select() on nil metadata = %v, want nil", got)
+	}
+}
+
+func TestUserMetadata_Size(t *testing.T) {
+	if got := (domain.UserMetadata{"a": "b"}).Size(); got != len(`{"a":"b"}`) {
+		t.Errorf("Size() = %v, want %v", got, len(`{"a":"b"}`))
+	}
+}
@@ -0,0 +1,71 @@
+package tests
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestNameLimits_NormalizeName(t *testing.T) {
+	limits := domain.NameLimits{Min: 2, Max: 10}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "plain name", input: "Ana Gómez", want: "Ana Gómez"},
+		{name: "decomposed accent is composed", input: "Jose\u0301", want: "Jos\u00e9"},
+		{name: "accent separated by an invisible character", input: "Jose\u200b\u0301", want: "Jos\u00e9"},
+		{name: "control characters are removed", input: "Ana\x00\x1b[31m", want: "Ana[31m"},
+		{name: "bidi override is removed", input: "\u202eAna", want: "Ana"},
+		{name: "zero-width joiner is kept", input: "Ana \U0001F469\u200d\U0001F4BB", want: "Ana \U0001F469\u200d\U0001F4BB"},
+		{name: "whitespace is collapsed", input: "  Ana\t\n  Gómez ", want: "Ana Gómez"},
+		{name: "length counts characters, not bytes", input: "ÁÉÍÓÚáéíóú", want: "ÁÉÍÓÚáéíóú"},
+		{name: "empty", input: "", wantErr: true},
+		{name: "only control characters", input: "\x00\x07 \t", wantErr: true},
+		{name: "too short", input: "A", wantErr: true},
+		{name: "too long", input: strings.Repeat("a", 11), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := limits.NormalizeName(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, domainerrors.ErrInvalidName) {
+					t.Errorf("NormalizeName(%q) error = %v, want ErrInvalidName", tt.input, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeName(%q) unexpected error = %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeName(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNameLimits_Validate(t *testing.T) {
+	tests := []struct {
+		limits  domain.NameLimits
+		wantErr bool
+	}{
+		{limits: domain.DefaultNameLimits()},
+		{limits: domain.NameLimits{Min: 1, Max: domain.MaxNameLength}},
+		{limits: domain.NameLimits{Min: 0, Max: 10}, wantErr: true},
+		{limits: domain.NameLimits{Min: 5, Max: 4}, wantErr: true},
+		{limits: domain.NameLimits{Min: 1, Max: domain.MaxNameLength + 1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		if err := tt.limits.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%+v.Validate() error = %v, wantErr %v", tt.limits, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package tests
+
+import (
+	"testing"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestParseUserStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    domain.UserStatus
+		wantErr bool
+	}{
+		{name: "parse ACTIVE", input: "ACTIVE", want: domain.UserStatusActive},
+		{name: "parse SUSPENDED", input: "SUSPENDED", want: domain.UserStatusSuspended},
+		{name: "parse ANONYMIZED", input: "ANONYMIZED", want: domain.UserStatusAnonymized},
+		{name: "parse invalid status", input: "DELETED", wantErr: true},
+		{name: "parse empty string", input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := domain.ParseUserStatus(tt.input)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseUserStatus() expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("ParseUserStatus() unexpected error: %v", err)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("ParseUserStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUser_IsActive(t *testing.T) {
+	user, err := domain.NewUser("test@example.com", "password123", "Test User", 12345)
+	if err != nil {
+		t.Fatalf("NewUser() unexpected error: %v", err)
+	}
+
+	if !user.IsActive() {
+		t.Errorf("IsActive() = false for a new user, want true")
+	}
+
+	user.Status = domain.UserStatusSuspended
+	if user.IsActive() {
+		t.Errorf("IsActive() = true for a suspended user, want false")
+	}
+}
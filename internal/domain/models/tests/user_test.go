@@ -1,6 +1,8 @@
 package tests
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"golang.org/x/crypto/bcrypt"
@@ -60,7 +62,7 @@ func TestNewUser(t *testing.T) {
 			userName:  "",
 			idCitizen: 12345,
 			wantErr:   true,
-			errMsg:    "name is required",
+			errMsg:    "invalid name: name is required",
 		},
 		{
 			name:      "zero id_citizen",
@@ -200,3 +202,74 @@ func TestUser_ToPublic(t *testing.T) {
 		t.Errorf("ToPublic() UpdatedAt = %v, want %v", publicUser.UpdatedAt, user.UpdatedAt)
 	}
 }
+
+func TestNewUserWithHasher(t *testing.T) {
+	user, err := domain.NewUserWithHasher("test@example.com", "password123", "Test User", 12345, domain.DefaultNameLimits(), func(password string) (string, error) {
+		return "hashed:" + password, nil
+	})
+	if err != nil {
+		t.Fatalf("NewUserWithHasher() unexpected error = %v", err)
+	}
+	if user.Password != "hashed:password123" {
+		t.Errorf("Password = %v, want hashed:password123", user.Password)
+	}
+
+	hashErr := errors.New("hashing failed")
+	if _, err := domain.NewUserWithHasher("test@example.com", "password123", "Test User", 12345, domain.DefaultNameLimits(), func(password string) (string, error) {
+		return "", hashErr
+	}); !errors.Is(err, hashErr) {
+		t.Errorf("NewUserWithHasher() error = %v, want %v", err, hashErr)
+	}
+
+	if _, err := domain.NewUserWithHasher("", "password123", "Test User", 12345, domain.DefaultNameLimits(), func(password string) (string, error) {
+		t.Errorf("hasher should not be called for invalid input")
+		return "", nil
+	}); err == nil {
+		t.Errorf("NewUserWithHasher() expected validation error")
+	}
+}
+
+func TestNewUser_NormalizesEmail(t *testing.T) {
+	user, err := domain.NewUserWithHasher("  Test.User@Example.COM ", "password123", "Test User", 12345, domain.DefaultNameLimits(), func(password string) (string, error) {
+		return password, nil
+	})
+	if err != nil {
+		t.Fatalf("NewUserWithHasher() unexpected error = %v", err)
+	}
+	if user.Email != "test.user@example.com" {
+		t.Errorf("Email = %q, want test.user@example.com", user.Email)
+	}
+
+	if _, err := domain.NewUserWithHasher("   ", "password123", "Test User", 12345, domain.DefaultNameLimits(), func(password string) (string, error) {
+		return password, nil
+	}); err == nil {
+		t.Errorf("NewUserWithHasher() with a blank email expected validation error")
+	}
+}
+
+func TestUser_Anonymize(t *testing.T) {
+	user := &domain.User{ID: "user-123", IDCitizen: 12345, Email: "Test@Example.com", Name: "Test User", Password: "hash", Status: domain.UserStatusActive, Metadata: domain.UserMetadata{"department": "sales"}}
+	other := &domain.User{Email: "test@example.com"}
+
+	user.Anonymize()
+	other.Anonymize()
+
+	if !user.IsAnonymized() || user.IsActive() {
+		t.Errorf("Status = %v, want %v", user.Status, domain.UserStatusAnonymized)
+	}
+	if strings.Contains(user.Email, "test") || !strings.HasSuffix(user.Email, "@anonymized.invalid") {
+		t.Errorf("Email = %v, want hashed email", user.Email)
+	}
+	if user.Email != other.Email {
+		t.Errorf("Email = %v, want the same hash regardless of case (%v)", user.Email, other.Email)
+	}
+	if user.Name != domain.AnonymizedName || user.Password != "" {
+		t.Errorf("Name = %v, Password = %v, want redacted name and no password", user.Name, user.Password)
+	}
+	if user.Metadata != nil {
+		t.Errorf("Metadata = %v, want none", user.Metadata)
+	}
+	if user.ID != "user-123" || user.IDCitizen != 12345 {
+		t.Errorf("identifiers changed: ID = %v, IDCitizen = %v", user.ID, user.IDCitizen)
+	}
+}
@@ -0,0 +1,53 @@
+package tests
+
+import (
+	"testing"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+func TestNewServiceAccount(t *testing.T) {
+	user, err := domain.NewServiceAccount("CI pipeline", 90001)
+	if err != nil {
+		t.Fatalf("NewServiceAccount() error = %v", err)
+	}
+	if !user.IsServiceAccount() || user.Password != "" || user.Role != domain.RoleUser || !user.IsActive() {
+		t.Errorf("NewServiceAccount() = %+v, want an active service account without password", user)
+	}
+	if user.Email != "service-account-90001@service-accounts.invalid" {
+		t.Errorf("Email = %q, want the reserved address of the citizen ID", user.Email)
+	}
+
+	if _, err := domain.NewServiceAccount("", 90001); err == nil {
+		t.Error("NewServiceAccount() without name should fail")
+	}
+	if _, err := domain.NewServiceAccount("CI pipeline", 0); err == nil {
+		t.Error("NewServiceAccount() without citizen ID should fail")
+	}
+}
+
+func TestUser_AuditActor(t *testing.T) {
+	human, _ := domain.NewUser("test@example.com", "password123", "Test User", 12345)
+	if human.Type != domain.UserTypeHuman {
+		t.Errorf("NewUser() type = %q, want %q", human.Type, domain.UserTypeHuman)
+	}
+	if got := human.AuditActor(); got != "user:12345" {
+		t.Errorf("AuditActor() = %q, want user:12345", got)
+	}
+
+	serviceAccount, _ := domain.NewServiceAccount("CI pipeline", 90001)
+	if got := serviceAccount.AuditActor(); got != "service-account:90001" {
+		t.Errorf("AuditActor() = %q, want service-account:90001", got)
+	}
+}
+
+func TestParseUserType(t *testing.T) {
+	for _, s := range []string{"HUMAN", "SERVICE_ACCOUNT"} {
+		if userType, err := domain.ParseUserType(s); err != nil || userType.String() != s {
+			t.Errorf("ParseUserType(%q) = %q, %v", s, userType, err)
+		}
+	}
+	if _, err := domain.ParseUserType("robot"); err == nil {
+		t.Error("ParseUserType(robot) should fail")
+	}
+}
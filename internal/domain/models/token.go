@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"strings"
 	"time"
 )
 
@@ -15,23 +16,90 @@ type TokenPair struct {
 // TokenClaims representa los claims personalizados del JWT
 type TokenClaims struct {
 	IDCitizen int    `json:"id_citizen"`
+	UserID    string `json:"user_id,omitempty"` // absent in tokens issued before it was added
 	Email     string `json:"email"`
 	Role      Role   `json:"role"`
-	Type      string `json:"type"` // "access" o "refresh"
+	Type      string `json:"type"`                 // "access" o "refresh"
+	SudoUntil int64  `json:"sudo_until,omitempty"` // Unix time until which the token grants elevated access
+
+	// TokenVersion is the token version of the user when the token was issued
+	TokenVersion int `json:"token_version,omitempty"`
+
+	// ClientID and Scopes are set in the tokens issued to an OAuth client on behalf of the user
+	ClientID string   `json:"client_id,omitempty"`
+	Scopes   []string `json:"scopes,omitempty"`
+}
+
+// IsDelegated returns true if the token was issued to an OAuth client on behalf of the user, so it only
+// grants the scopes the user approved for the client
+func (c *TokenClaims) IsDelegated() bool {
+	return c.ClientID != ""
+}
+
+// DelegatedAccess limits the tokens issued to an OAuth client on behalf of a user, like the device
+// authorization grant does, to the client and the scopes the user approved
+type DelegatedAccess struct {
+	ClientID string   `json:"client_id"`
+	Scopes   []string `json:"scopes"`
 }
 
-// RefreshTokenData represents the data stored in Redis for a refresh token
+// IsElevated returns true if the token grants elevated access ("sudo mode") at the given time
+func (c *TokenClaims) IsElevated(now time.Time) bool {
+	return c.SudoUntil > 0 && now.Unix() < c.SudoUntil
+}
+
+// SudoGrant is a short-lived access token granting elevated access, obtained by re-entering the password
+type SudoGrant struct {
+	AccessToken string
+	SudoUntil   time.Time
+}
+
+// RefreshTokenData represents the data stored in Redis for a refresh token.
+// It holds the claims of opaque refresh tokens, which carry none themselves.
 type RefreshTokenData struct {
-	IDCitizen int       `json:"id_citizen"`
-	Email     string    `json:"email"`
-	IssuedAt  time.Time `json:"issued_at"`
-	ExpiresAt time.Time `json:"expires_at"`
+	IDCitizen int             `json:"id_citizen"`
+	UserID    string          `json:"user_id,omitempty"`
+	Email     string          `json:"email"`
+	Role      Role            `json:"role,omitempty"`      // absent in sessions stored before it was added
+	FamilyID  string          `json:"family_id,omitempty"` // Shared by the refresh tokens rotated from the same login
+	IssuedAt  time.Time       `json:"issued_at"`
+	ExpiresAt time.Time       `json:"expires_at"`
+	Risk      *RiskAssessment `json:"risk,omitempty"` // Latest risk assessment of the session
+
+	// TokenProfile is the profile of the access tokens of the session, kept across refreshes
+	TokenProfile TokenProfile `json:"token_profile,omitempty"`
+
+	// TokenVersion is the token version of the user when the session was created or last refreshed
+	TokenVersion int `json:"token_version,omitempty"`
+
+	// Delegation is set for the sessions of OAuth clients acting on behalf of the user, kept across refreshes
+	Delegation *DelegatedAccess `json:"delegation,omitempty"`
+}
+
+// Claims returns the claims of the refresh token the data is stored for
+func (d *RefreshTokenData) Claims() *TokenClaims {
+	return &TokenClaims{
+		IDCitizen: d.IDCitizen,
+		UserID:    d.UserID,
+		Email:     d.Email,
+		Role:      d.Role,
+		Type:      TokenTypeRefresh,
+
+		TokenVersion: d.TokenVersion,
+	}
 }
 
-// BlacklistedToken represents a revoked/blacklisted token
+// IsExpired returns true if the refresh token expired at the given time
+func (d *RefreshTokenData) IsExpired(now time.Time) bool {
+	return !d.ExpiresAt.IsZero() && now.After(d.ExpiresAt)
+}
+
+// BlacklistedToken represents a revoked/blacklisted token, along with the region where it was revoked
+// when it is replicated to the other regions
 type BlacklistedToken struct {
 	Token     string    `json:"token"`
 	ExpiresAt time.Time `json:"expires_at"`
+	Region    string    `json:"region,omitempty"`
 }
 
 const (
@@ -42,3 +110,12 @@ const (
 	// TokenTypeBearer represents the token type in the Authorization header
 	TokenTypeBearer = "Bearer"
 )
+
+// OpaqueRefreshTokenPrefix prefixes the opaque refresh tokens, so they are told apart from JWTs and
+// recognized by secret scanners
+const OpaqueRefreshTokenPrefix = "rt_"
+
+// IsOpaqueRefreshToken returns true if the refresh token is an opaque token rather than a JWT
+func IsOpaqueRefreshToken(token string) bool {
+	return strings.HasPrefix(token, OpaqueRefreshTokenPrefix) && !strings.Contains(token, ".")
+}
@@ -0,0 +1,49 @@
+package domain
+
+// TokenCheckStatus is the outcome of a validation step of a decoded token
+type TokenCheckStatus string
+
+const (
+	// TokenCheckPassed steps would accept the token
+	TokenCheckPassed TokenCheckStatus = "passed"
+	// TokenCheckFailed steps would reject the token
+	TokenCheckFailed TokenCheckStatus = "failed"
+	// TokenCheckSkipped steps don't apply to the token, e.g. the version of a token without a user
+	TokenCheckSkipped TokenCheckStatus = "skipped"
+	// TokenCheckError steps couldn't run, e.g. because Redis is unavailable
+	TokenCheckError TokenCheckStatus = "error"
+)
+
+// Validation steps of the access tokens, in the order they run
+const (
+	TokenCheckSignature  = "signature"
+	TokenCheckExpiration = "expiration"
+	TokenCheckType       = "type"
+	TokenCheckBlacklist  = "blacklist"
+	TokenCheckVersion    = "version"
+)
+
+// TokenCheck is the outcome of a validation step, with a human-readable explanation
+type TokenCheck struct {
+	Name   string
+	Status TokenCheckStatus
+	Detail string
+}
+
+// TokenDebugReport describes a token decoded without validating it, and what each validation step of
+// the access tokens would decide about it
+type TokenDebugReport struct {
+	Header map[string]interface{}
+	Claims map[string]interface{}
+	Checks []TokenCheck
+}
+
+// Valid returns true if every validation step that ran accepted the token
+func (r *TokenDebugReport) Valid() bool {
+	for _, check := range r.Checks {
+		if check.Status == TokenCheckFailed || check.Status == TokenCheckError {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,20 @@
+package domain
+
+// TokenIntrospection describes the state of a token as reported by the introspection endpoint (RFC 7662)
+type TokenIntrospection struct {
+	Active    bool
+	Subject   string
+	ClientID  string
+	Scopes    []string
+	Email     string
+	Role      Role
+	TokenType string
+	IssuedAt  int64
+	ExpiresAt int64
+
+	// CertificateThumbprint is the certificate a client token is bound to, empty when it is not bound
+	CertificateThumbprint string
+
+	// RateLimit is the rate-limit status of the token's principal, nil when unavailable
+	RateLimit *RateLimitStatus
+}
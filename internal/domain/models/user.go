@@ -1,43 +1,99 @@
 package domain
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	// AnonymizedName replaces the name of anonymized users
+	AnonymizedName = "[redacted]"
+
+	// MinPasswordLength is the minimum length of user passwords
+	MinPasswordLength = 8
+
+	// anonymizedEmailDomain is a reserved domain (RFC 2606) so hashed emails can never receive mail
+	anonymizedEmailDomain = "anonymized.invalid"
+)
+
 // User represents a user in the system
 type User struct {
-	ID        string    `json:"id"`
-	IDCitizen int       `json:"id_citizen"` // Global citizen ID (like national ID)
-	Email     string    `json:"email"`
-	Password  string    `json:"-"`
-	Name      string    `json:"name"`
-	Role      Role      `json:"role"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        string       `json:"id"`
+	IDCitizen int          `json:"id_citizen"` // Global citizen ID (like national ID)
+	Email     string       `json:"email"`
+	Password  string       `json:"-"`
+	Name      string       `json:"name"`
+	Role      Role         `json:"role"`
+	Status    UserStatus   `json:"status"`
+	Type      UserType     `json:"type"`
+	Metadata  UserMetadata `json:"metadata,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+
+	// TokenVersion is carried by the tokens of the user, bumping it revokes the tokens issued before
+	TokenVersion int `json:"-"`
+
+	// PendingEmail is the address the user asked to change their email to, applied once confirmed with the
+	// token sent to it. Only the hash of the token is kept.
+	PendingEmail          string     `json:"-"`
+	PendingEmailTokenHash string     `json:"-"`
+	PendingEmailExpiresAt *time.Time `json:"-"`
+
+	// MustChangePassword is set on users created by an administrator with a temporary password,
+	// they can't sign in until they replace it
+	MustChangePassword bool `json:"-"`
+
+	// Version is incremented by every update, an update of a user read at an older version is rejected
+	Version int `json:"version"`
+
+	// LastLoginAt is the time of the last successful login, nil when the user never signed in
+	LastLoginAt *time.Time `json:"-"`
+
+	// PasswordChangedAt is the time the password was last set, nil for users created before it was recorded
+	PasswordChangedAt *time.Time `json:"-"`
 }
 
-// NewUser creates a new instance of User with validations
+// PasswordHashFunc hashes a plain-text password
+type PasswordHashFunc func(password string) (string, error)
+
+// NewUser creates a new instance of User with validations, hashing the password with the default bcrypt cost
+// and checking the name against the default limits
 func NewUser(email, password, name string, idCitizen int) (*User, error) {
+	return NewUserWithHasher(email, password, name, idCitizen, DefaultNameLimits(), func(password string) (string, error) {
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		return string(hashedPassword), err
+	})
+}
+
+// NewUserWithHasher creates a new instance of User with validations, hashing the password with hashPassword.
+// The email is stored in its canonical form, see CanonicalEmail, and the name normalized within nameLimits,
+// see NameLimits.NormalizeName.
+func NewUserWithHasher(email, password, name string, idCitizen int, nameLimits NameLimits, hashPassword PasswordHashFunc) (*User, error) {
+	email = CanonicalEmail(email)
 	if email == "" {
 		return nil, errors.New("email is required")
 	}
 	if password == "" {
 		return nil, errors.New("password is required")
 	}
-	if len(password) < 8 {
-		return nil, errors.New("password must be at least 8 characters")
+	if len(password) < MinPasswordLength {
+		return nil, fmt.Errorf("password must be at least %d characters", MinPasswordLength)
 	}
-	if name == "" {
-		return nil, errors.New("name is required")
+	name, err := nameLimits.NormalizeName(name)
+	if err != nil {
+		return nil, err
 	}
 	if idCitizen <= 0 {
 		return nil, errors.New("id_citizen is required and must be positive")
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	hashedPassword, err := hashPassword(password)
 	if err != nil {
 		return nil, err
 	}
@@ -46,14 +102,55 @@ func NewUser(email, password, name string, idCitizen int) (*User, error) {
 	return &User{
 		IDCitizen: idCitizen,
 		Email:     email,
-		Password:  string(hashedPassword),
+		Password:  hashedPassword,
 		Name:      name,
 		Role:      RoleUser, // Default role is USER
+		Status:    UserStatusActive,
+		Type:      UserTypeHuman,
 		CreatedAt: now,
 		UpdatedAt: now,
+
+		PasswordChangedAt: &now,
 	}, nil
 }
 
+// SetPassword replaces the password hash of the user and records when it changed
+func (u *User) SetPassword(hash string, at time.Time) {
+	u.Password = hash
+	u.PasswordChangedAt = &at
+}
+
+// IsActive returns true if the account is allowed to obtain tokens
+func (u *User) IsActive() bool {
+	return u.Status == UserStatusActive
+}
+
+// IsPendingApproval returns true if the registration of the user waits for an administrator
+func (u *User) IsPendingApproval() bool {
+	return u.Status == UserStatusPendingApproval
+}
+
+// IsAnonymized returns true if the personal data of the user was erased
+func (u *User) IsAnonymized() bool {
+	return u.Status == UserStatusAnonymized
+}
+
+// Anonymize scrubs the personal data of the user. The email is replaced by its hash so it stays unique,
+// and the password is cleared so the account can no longer sign in. The custom metadata may hold personal data and is dropped.
+func (u *User) Anonymize() {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(u.Email))))
+	u.Email = hex.EncodeToString(sum[:]) + "@" + anonymizedEmailDomain
+	u.Name = AnonymizedName
+	u.Password = ""
+	u.Metadata = nil
+	u.Status = UserStatusAnonymized
+}
+
+// RevokeTokens bumps the token version of the user, so the tokens issued before are no longer accepted
+func (u *User) RevokeTokens() {
+	u.TokenVersion++
+}
+
 // ComparePassword compares the provided password with the stored hash
 func (u *User) ComparePassword(password string) error {
 	return bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password))
@@ -61,13 +158,16 @@ func (u *User) ComparePassword(password string) error {
 
 // UserPublic represents the public user data (without password)
 type UserPublic struct {
-	ID        string    `json:"id"`
-	IDCitizen int       `json:"id_citizen"`
-	Email     string    `json:"email"`
-	Name      string    `json:"name"`
-	Role      Role      `json:"role"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        string       `json:"id"`
+	IDCitizen int          `json:"id_citizen"`
+	Email     string       `json:"email"`
+	Name      string       `json:"name"`
+	Role      Role         `json:"role"`
+	Type      UserType     `json:"type"`
+	Metadata  UserMetadata `json:"metadata,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+	Version   int          `json:"version"`
 }
 
 // ToPublic converts a User to UserPublic
@@ -78,7 +178,10 @@ func (u *User) ToPublic() *UserPublic {
 		Email:     u.Email,
 		Name:      u.Name,
 		Role:      u.Role,
+		Type:      u.Type,
+		Metadata:  u.Metadata,
 		CreatedAt: u.CreatedAt,
 		UpdatedAt: u.UpdatedAt,
+		Version:   u.Version,
 	}
 }
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxUserEmails is the number of secondary email addresses a user can register
+	MaxUserEmails = 3
+
+	// MaxEmailVerificationAttempts is the number of wrong codes accepted before the code of an address is discarded
+	MaxEmailVerificationAttempts = 5
+)
+
+// UserEmail is a secondary email address of a user. Once verified it can receive password reset links,
+// so the account can be recovered when the primary email is inaccessible.
+// Only the hash of the pending verification code is kept.
+type UserEmail struct {
+	ID            string     `json:"id"`
+	UserID        string     `json:"user_id"`
+	Email         string     `json:"email"`
+	VerifiedAt    *time.Time `json:"verified_at,omitempty"`
+	CodeHash      string     `json:"-"`
+	CodeExpiresAt time.Time  `json:"-"`
+	CodeAttempts  int        `json:"-"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// IsVerified checks if the address was confirmed with its verification code
+func (e *UserEmail) IsVerified() bool {
+	return e.VerifiedAt != nil
+}
+
+// HasPendingCode checks if the address has a verification code that is neither expired nor exhausted
+func (e *UserEmail) HasPendingCode() bool {
+	return e.CodeHash != "" && time.Now().Before(e.CodeExpiresAt) && e.CodeAttempts < MaxEmailVerificationAttempts
+}
+
+// SetVerificationCode replaces the pending verification code of the address
+func (e *UserEmail) SetVerificationCode(codeHash string, expiresAt time.Time) {
+	e.CodeHash = codeHash
+	e.CodeExpiresAt = expiresAt
+	e.CodeAttempts = 0
+}
+
+// MarkVerified records the verification of the address and discards its code, codes are single use
+func (e *UserEmail) MarkVerified(at time.Time) {
+	e.VerifiedAt = &at
+	e.CodeHash = ""
+	e.CodeExpiresAt = time.Time{}
+	e.CodeAttempts = 0
+}
+
+// CanonicalEmail trims and lowercases an email address, the form emails are stored and compared in so
+// addresses differing only by case belong to the same account
+func CanonicalEmail(raw string) string {
+	return strings.ToLower(strings.TrimSpace(raw))
+}
+
+// NormalizeEmail trims and lowercases an email address and checks it is a bare address (no display name)
+func NormalizeEmail(raw string) (string, bool) {
+	email := CanonicalEmail(raw)
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		return "", false
+	}
+	return email, true
+}
+
+// EmailCaseDuplicate is a group of users whose emails differ only by case, left by the releases that
+// stored emails as entered
+type EmailCaseDuplicate struct {
+	Email   string   // canonical email of the group
+	UserIDs []string // oldest first
+	Emails  []string // stored emails, in the order of UserIDs
+}
+
+// MaskEmail hides the local part of an email address but its first character, for logs
+func MaskEmail(email string) string {
+	local, domainPart, ok := strings.Cut(email, "@")
+	if !ok || local == "" {
+		return strings.Repeat("*", len(email))
+	}
+	return local[:1] + strings.Repeat("*", len(local)-1) + "@" + domainPart
+}
+
+// EmailRateLimitKey returns the rate-limit key of the verification codes and reset links sent to an address
+func EmailRateLimitKey(email string) string {
+	return fmt.Sprintf("email:address:%s", email)
+}
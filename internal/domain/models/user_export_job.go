@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserExportKeyPrefix is the prefix of the storage keys of the personal data archives
+const UserExportKeyPrefix = "exports/"
+
+// UserExportJobStatus is the state of a personal data export
+type UserExportJobStatus string
+
+const (
+	UserExportJobPending   UserExportJobStatus = "pending"
+	UserExportJobRunning   UserExportJobStatus = "running"
+	UserExportJobCompleted UserExportJobStatus = "completed"
+	UserExportJobFailed    UserExportJobStatus = "failed"
+)
+
+// String returns the string representation of the status
+func (s UserExportJobStatus) String() string {
+	return string(s)
+}
+
+// UserExportJob is a background job assembling the archive of the personal data of a user. The archive is
+// kept in blob storage under ObjectKey until ExpiresAt, when the job is forgotten too.
+type UserExportJob struct {
+	ID          string              `json:"id"`
+	UserID      string              `json:"user_id"`
+	Status      UserExportJobStatus `json:"status"`
+	ObjectKey   string              `json:"object_key"`
+	Size        int64               `json:"size,omitempty"` // of the archive, in bytes
+	CreatedAt   time.Time           `json:"created_at"`
+	CompletedAt *time.Time          `json:"completed_at,omitempty"`
+	ExpiresAt   time.Time           `json:"expires_at"`
+}
+
+// NewUserExportJob creates a new pending export of a user, kept for the retention period
+func NewUserExportJob(userID string, retention time.Duration) *UserExportJob {
+	id := uuid.New().String()
+	now := time.Now()
+	return &UserExportJob{
+		ID:        id,
+		UserID:    userID,
+		Status:    UserExportJobPending,
+		ObjectKey: fmt.Sprintf("%s%s/%s.zip", UserExportKeyPrefix, userID, id),
+		CreatedAt: now,
+		ExpiresAt: now.Add(retention),
+	}
+}
+
+// Finished reports whether the job completed or failed
+func (j *UserExportJob) Finished() bool {
+	return j.Status == UserExportJobCompleted || j.Status == UserExportJobFailed
+}
+
+// Finish records the result of the job
+func (j *UserExportJob) Finish(status UserExportJobStatus, size int64) {
+	now := time.Now()
+	j.Status = status
+	j.Size = size
+	j.CompletedAt = &now
+}
+
+// UserExportDownload is the signed URL the archive of a completed export is downloaded from
+type UserExportDownload struct {
+	URL       string
+	ExpiresAt time.Time
+}
@@ -0,0 +1,33 @@
+package domain
+
+// UserMerge describes the merge of a duplicate account into the surviving one, e.g. two citizen IDs of the
+// same person from a legacy import. Everything listed moves to the surviving account before the duplicate
+// is soft-deleted.
+type UserMerge struct {
+	Survivor *User
+	Merged   *User
+	DryRun   bool // the merge was only previewed, nothing changed
+
+	// Consents are the client IDs of the consents moved, combined with the consent of the surviving
+	// account when both granted one to the same client
+	Consents []string
+	// Emails are the addresses of the duplicate, its primary and verified secondary ones, added to the
+	// surviving account as verified secondary emails
+	Emails []string
+	// PhoneNumber is the phone number moved, empty when the duplicate has none or the surviving account
+	// already has its own
+	PhoneNumber string
+	// AuditRecords is the number of audit records about the duplicate, moved to the surviving account
+	AuditRecords int
+	// Sessions is the number of active sessions of the duplicate. Its tokens carry its citizen ID, so its
+	// sessions are ended and the user signs in again to the surviving account.
+	Sessions int
+}
+
+// CanMergeUsers checks the merged account can be merged into the surviving one: two distinct human
+// accounts, neither anonymized
+func CanMergeUsers(survivor, merged *User) bool {
+	return survivor.ID != merged.ID &&
+		!survivor.IsServiceAccount() && !merged.IsServiceAccount() &&
+		!survivor.IsAnonymized() && !merged.IsAnonymized()
+}
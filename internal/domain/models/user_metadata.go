@@ -0,0 +1,144 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MetadataValueType is the type of the values of a user metadata key
+type MetadataValueType string
+
+// Value types of the user metadata schema
+const (
+	MetadataTypeString  MetadataValueType = "string"
+	MetadataTypeNumber  MetadataValueType = "number"
+	MetadataTypeBoolean MetadataValueType = "boolean"
+)
+
+const (
+	// MaxMetadataKeyLength is the maximum length of user metadata keys
+	MaxMetadataKeyLength = 64
+
+	// MaxMetadataStringLength is the maximum length of the string values of user metadata
+	MaxMetadataStringLength = 256
+)
+
+// metadataKeyPattern restricts metadata keys to snake_case identifiers, so they are safe as JWT claim names
+var metadataKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// UserMetadata holds the custom key-value attributes of a user. Values are strings, numbers or booleans.
+type UserMetadata map[string]interface{}
+
+// UserMetadataSchema declares the metadata keys that can be set and the type of their values
+type UserMetadataSchema map[string]MetadataValueType
+
+// ParseUserMetadataSchema parses a schema written as comma-separated key:type pairs,
+// e.g. "department:string,employee_level:number,newsletter:boolean"
+func ParseUserMetadataSchema(spec string) (UserMetadataSchema, error) {
+	schema := UserMetadataSchema{}
+	for _, field := range strings.Split(spec, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		key, valueType, ok := strings.Cut(field, ":")
+		key, valueType = strings.TrimSpace(key), strings.TrimSpace(valueType)
+		if !ok || !IsValidMetadataKey(key) {
+			return nil, fmt.Errorf("%w: invalid metadata field %q", ErrValidation, field)
+		}
+		switch MetadataValueType(valueType) {
+		case MetadataTypeString, MetadataTypeNumber, MetadataTypeBoolean:
+		default:
+			return nil, fmt.Errorf("%w: unknown type %q of metadata key %q", ErrValidation, valueType, key)
+		}
+		if _, exists := schema[key]; exists {
+			return nil, fmt.Errorf("%w: metadata key %q declared twice", ErrValidation, key)
+		}
+		schema[key] = MetadataValueType(valueType)
+	}
+	return schema, nil
+}
+
+// IsValidMetadataKey checks that the key is a snake_case identifier of at most MaxMetadataKeyLength characters
+func IsValidMetadataKey(key string) bool {
+	return len(key) <= MaxMetadataKeyLength && metadataKeyPattern.MatchString(key)
+}
+
+// ValidateChanges checks that every changed key is declared and its value has the declared type.
+// A nil value removes the key and is always accepted for declared keys.
+func (s UserMetadataSchema) ValidateChanges(changes UserMetadata) error {
+	for key, value := range changes {
+		valueType, ok := s[key]
+		if !ok {
+			return fmt.Errorf("%w: metadata key %q is not declared", ErrValidation, key)
+		}
+		if value == nil {
+			continue
+		}
+
+		switch v := value.(type) {
+		case string:
+			if valueType != MetadataTypeString {
+				return fmt.Errorf("%w: metadata key %q must be a %s", ErrValidation, key, valueType)
+			}
+			if len(v) > MaxMetadataStringLength {
+				return fmt.Errorf("%w: metadata key %q exceeds %d characters", ErrValidation, key, MaxMetadataStringLength)
+			}
+		case float64, int, int64:
+			if valueType != MetadataTypeNumber {
+				return fmt.Errorf("%w: metadata key %q must be a %s", ErrValidation, key, valueType)
+			}
+		case bool:
+			if valueType != MetadataTypeBoolean {
+				return fmt.Errorf("%w: metadata key %q must be a %s", ErrValidation, key, valueType)
+			}
+		default:
+			return fmt.Errorf("%w: metadata key %q must be a %s", ErrValidation, key, valueType)
+		}
+	}
+	return nil
+}
+
+// Apply returns a copy of the metadata with the changes applied, a nil value removes the key
+func (m UserMetadata) Apply(changes UserMetadata) UserMetadata {
+	result := make(UserMetadata, len(m)+len(changes))
+	for key, value := range m {
+		result[key] = value
+	}
+	for key, value := range changes {
+		if value == nil {
+			delete(result, key)
+			continue
+		}
+		result[key] = value
+	}
+	return result
+}
+
+// Size returns the size in bytes of the JSON encoding of the metadata, as stored
+func (m UserMetadata) Size() int {
+	encoded, err := json.Marshal(m)
+	if err != nil {
+		return 0
+	}
+	return len(encoded)
+}
+
+// Select returns the metadata restricted to the given keys, or nil when none of them is set
+func (m UserMetadata) Select(keys []string) UserMetadata {
+	var selected UserMetadata
+	for _, key := range keys {
+		value, ok := m[key]
+		if !ok {
+			continue
+		}
+		if selected == nil {
+			selected = UserMetadata{}
+		}
+		selected[key] = value
+	}
+	return selected
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+)
+
+const (
+	// DefaultNameMinLength is the default minimum length of user names, in characters
+	DefaultNameMinLength = 1
+
+	// DefaultNameMaxLength is the default maximum length of user names, in characters
+	DefaultNameMaxLength = 100
+
+	// MaxNameLength is the length of the name column, no limit can exceed it
+	MaxNameLength = 255
+)
+
+// NameLimits bounds the length of user names, counted in characters once the name is normalized
+type NameLimits struct {
+	Min int
+	Max int
+}
+
+// DefaultNameLimits returns the limits of the user names when none are configured
+func DefaultNameLimits() NameLimits {
+	return NameLimits{Min: DefaultNameMinLength, Max: DefaultNameMaxLength}
+}
+
+// Validate checks the limits are consistent and fit the name column
+func (l NameLimits) Validate() error {
+	if l.Min < 1 {
+		return fmt.Errorf("the minimum name length must be at least 1, got %d", l.Min)
+	}
+	if l.Max < l.Min || l.Max > MaxNameLength {
+		return fmt.Errorf("the maximum name length must be between %d and %d, got %d", l.Min, MaxNameLength, l.Max)
+	}
+	return nil
+}
+
+// NormalizeName returns a user name in Unicode NFC, so the same name typed on different keyboards is stored
+// the same way, without control or invisible formatting characters and with its whitespace collapsed.
+// It returns ErrInvalidName when the result is not within the limits.
+func (l NameLimits) NormalizeName(raw string) (string, error) {
+	var b strings.Builder
+	space := false
+	for _, r := range raw {
+		switch {
+		case unicode.IsSpace(r):
+			space = b.Len() > 0
+			continue
+		case r == utf8.RuneError, unicode.Is(unicode.Cc, r), unicode.Is(unicode.Cf, r) && !isJoiner(r):
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+	// Composed last, so characters that were separated by a removed one are composed too
+	name := norm.NFC.String(b.String())
+
+	length := utf8.RuneCountInString(name)
+	switch {
+	case length == 0:
+		return "", fmt.Errorf("%w: name is required", domainerrors.ErrInvalidName)
+	case length < l.Min:
+		return "", fmt.Errorf("%w: name must be at least %d characters", domainerrors.ErrInvalidName, l.Min)
+	case length > l.Max:
+		return "", fmt.Errorf("%w: name must be at most %d characters", domainerrors.ErrInvalidName, l.Max)
+	}
+	return name, nil
+}
+
+// isJoiner reports whether r is a zero-width joiner or non-joiner, formatting characters that change how
+// some scripts and emoji are rendered and so are kept
+func isJoiner(r rune) bool {
+	return r == '\u200c' || r == '\u200d'
+}
@@ -0,0 +1,47 @@
+package domain
+
+import "fmt"
+
+// UserStatus represents the lifecycle status of a user account
+type UserStatus string
+
+const (
+	// UserStatusActive is the status of accounts that can sign in
+	UserStatusActive UserStatus = "ACTIVE"
+
+	// UserStatusSuspended is the status of accounts blocked by an administrator
+	UserStatusSuspended UserStatus = "SUSPENDED"
+
+	// UserStatusAnonymized is the status of accounts whose personal data was erased
+	UserStatusAnonymized UserStatus = "ANONYMIZED"
+
+	// UserStatusPendingApproval is the status of self-registered accounts waiting for an administrator
+	UserStatusPendingApproval UserStatus = "PENDING_APPROVAL"
+
+	// UserStatusRejected is the status of self-registered accounts declined by an administrator
+	UserStatusRejected UserStatus = "REJECTED"
+)
+
+// String returns the string representation of the status
+func (s UserStatus) String() string {
+	return string(s)
+}
+
+// IsValid checks if the status is valid
+func (s UserStatus) IsValid() bool {
+	switch s {
+	case UserStatusActive, UserStatusSuspended, UserStatusAnonymized, UserStatusPendingApproval, UserStatusRejected:
+		return true
+	default:
+		return false
+	}
+}
+
+// ParseUserStatus parses a string into a UserStatus
+func ParseUserStatus(s string) (UserStatus, error) {
+	status := UserStatus(s)
+	if !status.IsValid() {
+		return "", fmt.Errorf("invalid user status: %s", s)
+	}
+	return status, nil
+}
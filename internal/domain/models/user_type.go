@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// UserType separates the accounts of people from the identities of automation
+type UserType string
+
+const (
+	// UserTypeHuman is the type of the accounts of people, registered or synchronized from the citizen registry
+	UserTypeHuman UserType = "HUMAN"
+
+	// UserTypeServiceAccount is the type of the identities of automation. They are created by administrators
+	// only and can't sign in with a password.
+	UserTypeServiceAccount UserType = "SERVICE_ACCOUNT"
+
+	// serviceAccountEmailDomain is a reserved domain (RFC 2606), service accounts can never receive mail
+	serviceAccountEmailDomain = "service-accounts.invalid"
+)
+
+// String returns the string representation of the user type
+func (t UserType) String() string {
+	return string(t)
+}
+
+// IsValid checks if the user type is valid
+func (t UserType) IsValid() bool {
+	switch t {
+	case UserTypeHuman, UserTypeServiceAccount:
+		return true
+	default:
+		return false
+	}
+}
+
+// ParseUserType parses a string into a UserType
+func ParseUserType(s string) (UserType, error) {
+	userType := UserType(s)
+	if !userType.IsValid() {
+		return "", fmt.Errorf("invalid user type: %s", s)
+	}
+	return userType, nil
+}
+
+// NewServiceAccount creates a new service account. It has no password, and its email is a reserved
+// address derived from the citizen ID, unique but unable to receive mail or password reset links.
+func NewServiceAccount(name string, idCitizen int) (*User, error) {
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+	if idCitizen <= 0 {
+		return nil, errors.New("id_citizen is required and must be positive")
+	}
+
+	now := time.Now()
+	return &User{
+		IDCitizen: idCitizen,
+		Email:     fmt.Sprintf("service-account-%d@%s", idCitizen, serviceAccountEmailDomain),
+		Name:      name,
+		Role:      RoleUser,
+		Status:    UserStatusActive,
+		Type:      UserTypeServiceAccount,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// IsServiceAccount returns true if the user is the identity of automation rather than a person
+func (u *User) IsServiceAccount() bool {
+	return u.Type == UserTypeServiceAccount
+}
+
+// AuditActor returns how the user is identified as the actor of audit records, e.g. "user:12345" or
+// "service-account:67890"
+func (u *User) AuditActor() string {
+	if u.IsServiceAccount() {
+		return fmt.Sprintf("service-account:%d", u.IDCitizen)
+	}
+	return fmt.Sprintf("user:%d", u.IDCitizen)
+}
@@ -0,0 +1,118 @@
+package auditsink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// HTTP sink payload formats
+const (
+	// FormatJSON posts the batch as a JSON array of audit records (Logstash http input, Fluent Bit, Vector, etc.)
+	FormatJSON = "json"
+	// FormatSplunkHEC posts the batch as Splunk HTTP Event Collector events
+	FormatSplunkHEC = "splunk_hec"
+)
+
+// splunkSourceType is the source type of the events sent to Splunk
+const splunkSourceType = "auth-microservice:audit"
+
+// splunkEvent is an event of the Splunk HTTP Event Collector
+type splunkEvent struct {
+	Time       float64             `json:"time"`
+	SourceType string              `json:"sourcetype"`
+	Event      *domain.AuditRecord `json:"event"`
+}
+
+// HTTPSink sends audit records to an HTTP collector (Splunk HEC, ELK, etc.)
+type HTTPSink struct {
+	url        string
+	authHeader string
+	format     string
+	httpClient *http.Client
+	logger     *zap.Logger
+}
+
+// NewHTTPSink creates a new HTTP sink posting to url.
+// authHeader is sent as the Authorization header when set, e.g. "Splunk <token>" or "Bearer <token>".
+func NewHTTPSink(url, authHeader, format string, logger *zap.Logger) *HTTPSink {
+	return &HTTPSink{
+		url:        url,
+		authHeader: authHeader,
+		format:     format,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		logger: logger,
+	}
+}
+
+// Name returns the name of the sink
+func (s *HTTPSink) Name() string {
+	return "http"
+}
+
+// Send posts the batch to the collector, every record is accepted when it answers 2xx
+func (s *HTTPSink) Send(ctx context.Context, records []*domain.AuditRecord) error {
+	body, err := s.encode(records)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit records: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create audit collector request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.authHeader != "" {
+		req.Header.Set("Authorization", s.authHeader)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call audit collector: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+		s.logger.Error("audit collector rejected records",
+			zap.Int("status_code", resp.StatusCode),
+			zap.String("body", string(respBody)),
+			zap.Int("records", len(records)))
+		return fmt.Errorf("audit collector returned status %d", resp.StatusCode)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	return nil
+}
+
+// encode serializes the batch in the configured format
+func (s *HTTPSink) encode(records []*domain.AuditRecord) ([]byte, error) {
+	if s.format != FormatSplunkHEC {
+		return json.Marshal(records)
+	}
+
+	// HEC accepts several events concatenated in a single request
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, record := range records {
+		event := splunkEvent{
+			Time:       float64(record.CreatedAt.UnixMilli()) / 1000,
+			SourceType: splunkSourceType,
+			Event:      record,
+		}
+		if err := encoder.Encode(event); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
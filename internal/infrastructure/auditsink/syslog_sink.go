@@ -0,0 +1,137 @@
+package auditsink
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// Syslog transports
+const (
+	NetworkUDP = "udp"
+	NetworkTCP = "tcp"
+	NetworkTLS = "tls"
+)
+
+// syslogPriority is the PRI of the audit messages: facility authpriv (10), severity notice (5)
+const syslogPriority = 10*8 + 5
+
+// syslogDialTimeout bounds the connection to the syslog server when the context has no deadline
+const syslogDialTimeout = 10 * time.Second
+
+// SyslogSink sends audit records to a syslog server as RFC 5424 messages whose body is the JSON record.
+// Messages are framed with octet counting (RFC 6587) over TCP and TLS. UDP gives no delivery guarantee,
+// TCP or TLS should be used when every record must reach the server.
+type SyslogSink struct {
+	network  string
+	address  string
+	appName  string
+	hostname string
+	logger   *zap.Logger
+}
+
+// NewSyslogSink creates a new syslog sink sending to address (host:port) over network (udp, tcp or tls)
+func NewSyslogSink(network, address, appName string, logger *zap.Logger) *SyslogSink {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	return &SyslogSink{
+		network:  network,
+		address:  address,
+		appName:  appName,
+		hostname: hostname,
+		logger:   logger,
+	}
+}
+
+// Name returns the name of the sink
+func (s *SyslogSink) Name() string {
+	return "syslog"
+}
+
+// Send writes every record of the batch to a new connection to the syslog server
+func (s *SyslogSink) Send(ctx context.Context, records []*domain.AuditRecord) error {
+	conn, err := s.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to syslog server: %w", err)
+	}
+	defer conn.Close()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(syslogDialTimeout)
+	}
+	if err := conn.SetWriteDeadline(deadline); err != nil {
+		return fmt.Errorf("failed to set syslog write deadline: %w", err)
+	}
+
+	for _, record := range records {
+		message, err := s.format(record)
+		if err != nil {
+			return fmt.Errorf("failed to format audit record: %w", err)
+		}
+		if s.network != NetworkUDP {
+			message = strconv.Itoa(len(message)) + " " + message
+		}
+		if _, err := conn.Write([]byte(message)); err != nil {
+			return fmt.Errorf("failed to write to syslog server: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// dial connects to the syslog server
+func (s *SyslogSink) dial(ctx context.Context) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: syslogDialTimeout}
+	if s.network == NetworkTLS {
+		tlsDialer := &tls.Dialer{NetDialer: dialer}
+		return tlsDialer.DialContext(ctx, "tcp", s.address)
+	}
+	return dialer.DialContext(ctx, s.network, s.address)
+}
+
+// format builds the RFC 5424 message of a record, the MSGID is the audit action
+func (s *SyslogSink) format(record *domain.AuditRecord) (string, error) {
+	body, err := json.Marshal(record)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("<%d>1 %s %s %s - %s - %s",
+		syslogPriority,
+		record.CreatedAt.UTC().Format(time.RFC3339Nano),
+		s.hostname,
+		headerField(s.appName, 48),
+		headerField(record.Action.String(), 32),
+		body,
+	), nil
+}
+
+// headerField returns the value as a valid RFC 5424 header field: printable ASCII without spaces, truncated to maxLen
+func headerField(value string, maxLen int) string {
+	value = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return '_'
+		}
+		return r
+	}, value)
+	if value == "" {
+		return "-"
+	}
+	if len(value) > maxLen {
+		value = value[:maxLen]
+	}
+	return value
+}
@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/auditsink"
+)
+
+func newAuditRecords() []*domain.AuditRecord {
+	return []*domain.AuditRecord{
+		domain.NewAuditRecord(domain.AuditActionUserUpdated, "admin:1", "user-123", map[string]string{"field": "email"}),
+		domain.NewAuditRecord(domain.AuditActionUserRoleChanged, "admin:1", "user-456", nil),
+	}
+}
+
+func TestHTTPSink_Send(t *testing.T) {
+	tests := []struct {
+		name       string
+		format     string
+		statusCode int
+		wantEvents int
+		wantErr    bool
+	}{
+		{name: "json array accepted", format: auditsink.FormatJSON, statusCode: http.StatusOK, wantEvents: 2},
+		{name: "splunk events accepted", format: auditsink.FormatSplunkHEC, statusCode: http.StatusOK, wantEvents: 2},
+		{name: "collector unavailable", format: auditsink.FormatJSON, statusCode: http.StatusServiceUnavailable, wantEvents: 2, wantErr: true},
+		{name: "bad token", format: auditsink.FormatSplunkHEC, statusCode: http.StatusForbidden, wantEvents: 2, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/services/collector" {
+					t.Errorf("request = %s %s", r.Method, r.URL.Path)
+				}
+				if got := r.Header.Get("Authorization"); got != "Splunk token" {
+					t.Errorf("Authorization = %q, want %q", got, "Splunk token")
+				}
+				body, _ := io.ReadAll(r.Body)
+				if got := countEvents(t, tt.format, body); got != tt.wantEvents {
+					t.Errorf("events = %d, want %d", got, tt.wantEvents)
+				}
+				w.WriteHeader(tt.statusCode)
+			}))
+			defer server.Close()
+
+			sink := auditsink.NewHTTPSink(server.URL+"/services/collector", "Splunk token", tt.format, zap.NewNop())
+			err := sink.Send(context.Background(), newAuditRecords())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Send() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// countEvents decodes the request body in the given format and returns the number of audit records
+func countEvents(t *testing.T, format string, body []byte) int {
+	t.Helper()
+
+	if format == auditsink.FormatJSON {
+		var records []domain.AuditRecord
+		if err := json.Unmarshal(body, &records); err != nil {
+			t.Fatalf("invalid JSON array: %v", err)
+		}
+		return len(records)
+	}
+
+	count := 0
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	for decoder.More() {
+		var event struct {
+			Time       float64             `json:"time"`
+			SourceType string              `json:"sourcetype"`
+			Event      *domain.AuditRecord `json:"event"`
+		}
+		if err := decoder.Decode(&event); err != nil {
+			t.Fatalf("invalid HEC event: %v", err)
+		}
+		if event.Time == 0 || event.SourceType == "" || event.Event == nil || event.Event.ID == "" {
+			t.Errorf("incomplete HEC event: %+v", event)
+		}
+		count++
+	}
+	return count
+}
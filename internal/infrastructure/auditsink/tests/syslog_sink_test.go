@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/auditsink"
+)
+
+func TestSyslogSink_Send_TCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer listener.Close()
+
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		// Messages are framed with octet counting: "<length> <message>"
+		var messages []string
+		reader := bufio.NewReader(conn)
+		for {
+			length, err := reader.ReadString(' ')
+			if err != nil {
+				break
+			}
+			n, err := strconv.Atoi(strings.TrimSpace(length))
+			if err != nil {
+				t.Errorf("invalid frame length %q", length)
+				break
+			}
+			message := make([]byte, n)
+			if _, err := io.ReadFull(reader, message); err != nil {
+				t.Errorf("truncated frame: %v", err)
+				break
+			}
+			messages = append(messages, string(message))
+		}
+		received <- messages
+	}()
+
+	records := newAuditRecords()
+	sink := auditsink.NewSyslogSink(auditsink.NetworkTCP, listener.Addr().String(), "auth microservice", zap.NewNop())
+	if err := sink.Send(context.Background(), records); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	messages := <-received
+	if len(messages) != len(records) {
+		t.Fatalf("messages = %d, want %d", len(messages), len(records))
+	}
+	for i, message := range messages {
+		fields := strings.SplitN(message, " ", 8)
+		if len(fields) != 8 {
+			t.Fatalf("message %q is not RFC 5424", message)
+		}
+		if fields[0] != "<85>1" {
+			t.Errorf("PRI and version = %q, want <85>1", fields[0])
+		}
+		if fields[3] != "auth_microservice" {
+			t.Errorf("APP-NAME = %q, want auth_microservice", fields[3])
+		}
+		if fields[5] != records[i].Action.String() {
+			t.Errorf("MSGID = %q, want %q", fields[5], records[i].Action)
+		}
+
+		var record domain.AuditRecord
+		if err := json.Unmarshal([]byte(fields[7]), &record); err != nil {
+			t.Fatalf("invalid JSON body: %v", err)
+		}
+		if record.ID != records[i].ID {
+			t.Errorf("record ID = %q, want %q", record.ID, records[i].ID)
+		}
+	}
+}
+
+func TestSyslogSink_Send_Unreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	sink := auditsink.NewSyslogSink(auditsink.NetworkTCP, address, "auth-microservice", zap.NewNop())
+	if err := sink.Send(context.Background(), newAuditRecords()); err == nil {
+		t.Error("Send() error = nil, want a connection error")
+	}
+}
@@ -0,0 +1,48 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/config"
+)
+
+func TestCookieConfig_Validate(t *testing.T) {
+	secure := config.CookieConfig{Enabled: true, Path: "/", SameSite: "strict", Secure: true}
+
+	tests := []struct {
+		name       string
+		cookieName string
+		production bool
+		modify     func(c *config.CookieConfig)
+		wantErr    bool
+	}{
+		{name: "secure settings", cookieName: "access_token", production: true},
+		{name: "plain HTTP outside production", cookieName: "access_token", modify: func(c *config.CookieConfig) { c.Secure, c.SameSite = false, "lax" }},
+		{name: "missing cookie name", wantErr: true},
+		{name: "unknown SameSite", cookieName: "access_token", modify: func(c *config.CookieConfig) { c.SameSite = "relaxed" }, wantErr: true},
+		{name: "relative path", cookieName: "access_token", modify: func(c *config.CookieConfig) { c.Path = "api" }, wantErr: true},
+		{name: "domain with scheme", cookieName: "access_token", modify: func(c *config.CookieConfig) { c.Domain = "https://example.com" }, wantErr: true},
+		{name: "parent domain", cookieName: "access_token", modify: func(c *config.CookieConfig) { c.Domain = ".example.com" }},
+		{name: "SameSite none without Secure", cookieName: "access_token", modify: func(c *config.CookieConfig) { c.SameSite, c.Secure = "none", false }, wantErr: true},
+		{name: "SameSite none with Secure", cookieName: "access_token", modify: func(c *config.CookieConfig) { c.SameSite = "none" }},
+		{name: "production without Secure", cookieName: "access_token", production: true, modify: func(c *config.CookieConfig) { c.Secure = false }, wantErr: true},
+		{name: "__Secure- prefix without Secure", cookieName: "__Secure-access_token", modify: func(c *config.CookieConfig) { c.Secure = false }, wantErr: true},
+		{name: "__Host- prefix", cookieName: "__Host-access_token"},
+		{name: "__Host- prefix with domain", cookieName: "__Host-access_token", modify: func(c *config.CookieConfig) { c.Domain = "example.com" }, wantErr: true},
+		{name: "__Host- prefix with path", cookieName: "__Host-access_token", modify: func(c *config.CookieConfig) { c.Path = "/api" }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cookie := secure
+			if tt.modify != nil {
+				tt.modify(&cookie)
+			}
+
+			err := cookie.Validate(tt.cookieName, tt.production)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/config"
+)
+
+func TestConfig_ValidateCrypto(t *testing.T) {
+	approved := func() *config.Config {
+		return &config.Config{
+			Crypto: config.CryptoConfig{Mode: config.CryptoModeFIPS},
+			PasswordHashing: config.PasswordHashingConfig{
+				Algorithm:        config.PasswordHashPBKDF2SHA256,
+				PBKDF2Iterations: config.FIPSMinPBKDF2Iterations,
+			},
+			JWT: config.JWTConfig{SigningAlgorithm: "HS256", AcceptedAlgorithms: []string{"HS256", "HS512"}},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(cfg *config.Config)
+		wantErr bool
+	}{
+		{name: "approved primitives", modify: func(cfg *config.Config) {}},
+		{name: "standard mode allows bcrypt", modify: func(cfg *config.Config) {
+			cfg.Crypto.Mode = config.CryptoModeStandard
+			cfg.PasswordHashing.Algorithm = config.PasswordHashBcrypt
+		}},
+		{name: "bcrypt", modify: func(cfg *config.Config) {
+			cfg.PasswordHashing.Algorithm = config.PasswordHashBcrypt
+		}, wantErr: true},
+		{name: "too few iterations", modify: func(cfg *config.Config) {
+			cfg.PasswordHashing.PBKDF2Iterations = 10000
+		}, wantErr: true},
+		{name: "unapproved accepted algorithm", modify: func(cfg *config.Config) {
+			cfg.JWT.AcceptedAlgorithms = []string{"HS256", "none"}
+		}, wantErr: true},
+		{name: "unknown mode", modify: func(cfg *config.Config) {
+			cfg.Crypto.Mode = "strict"
+		}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := approved()
+			tt.modify(cfg)
+			if err := cfg.ValidateCrypto(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCrypto() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
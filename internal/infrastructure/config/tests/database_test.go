@@ -0,0 +1,21 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/config"
+)
+
+func TestConfig_DatabaseConnectionString(t *testing.T) {
+	cfg := &config.Config{Database: config.DatabaseConfig{Host: "postgres", Port: 5432, User: "auth", DBName: "shared", SSLMode: "disable"}}
+
+	if got := cfg.DatabaseConnectionString(); strings.Contains(got, "search_path") {
+		t.Errorf("DatabaseConnectionString() = %q, want the server default search_path", got)
+	}
+
+	cfg.Database.Schema = "auth"
+	if got := cfg.DatabaseConnectionString(); !strings.HasSuffix(got, " search_path=auth") {
+		t.Errorf("DatabaseConnectionString() = %q, want search_path=auth", got)
+	}
+}
@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/config"
+)
+
+// reversingDecrypter "decrypts" a ciphertext by reversing it
+type reversingDecrypter struct{}
+
+func (reversingDecrypter) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) == 0 {
+		return nil, errors.New("empty ciphertext")
+	}
+	plaintext := make([]byte, len(ciphertext))
+	for i, b := range ciphertext {
+		plaintext[len(ciphertext)-1-i] = b
+	}
+	return plaintext, nil
+}
+
+func TestKMSConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		kms     config.KMSConfig
+		wantErr bool
+	}{
+		{name: "plain values", kms: config.KMSConfig{Provider: config.KMSProviderNone}},
+		{name: "aws", kms: config.KMSConfig{Provider: config.KMSProviderAWS, AWSRegion: "us-east-1", Timeout: 10 * time.Second}},
+		{name: "aws without region", kms: config.KMSConfig{Provider: config.KMSProviderAWS, Timeout: 10 * time.Second}, wantErr: true},
+		{name: "gcp", kms: config.KMSConfig{Provider: config.KMSProviderGCP, GCPKeyName: "projects/p/locations/global/keyRings/r/cryptoKeys/k", Timeout: 10 * time.Second}},
+		{name: "gcp without key", kms: config.KMSConfig{Provider: config.KMSProviderGCP, Timeout: 10 * time.Second}, wantErr: true},
+		{name: "missing timeout", kms: config.KMSConfig{Provider: config.KMSProviderAWS, AWSRegion: "us-east-1"}, wantErr: true},
+		{name: "unknown provider", kms: config.KMSConfig{Provider: "vault"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.kms.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_DecryptSecrets(t *testing.T) {
+	encrypted := "kms:" + base64.StdEncoding.EncodeToString([]byte("terces-twj"))
+
+	cfg := &config.Config{
+		JWT:      config.JWTConfig{Secret: encrypted},
+		Database: config.DatabaseConfig{Password: "plain-password"},
+	}
+	if err := cfg.DecryptSecrets(context.Background(), reversingDecrypter{}); err != nil {
+		t.Fatalf("DecryptSecrets() error = %v", err)
+	}
+	if cfg.JWT.Secret != "jwt-secret" {
+		t.Errorf("JWT secret = %q, want the decrypted value", cfg.JWT.Secret)
+	}
+	if cfg.Database.Password != "plain-password" {
+		t.Errorf("DB password = %q, want the plain value unchanged", cfg.Database.Password)
+	}
+
+	// Local development has no KMS, encrypted values are rejected instead of used as is
+	cfg = &config.Config{JWT: config.JWTConfig{Secret: encrypted}}
+	if err := cfg.DecryptSecrets(context.Background(), nil); err == nil {
+		t.Error("expected an error for an encrypted secret without KMS provider")
+	}
+
+	cfg = &config.Config{Database: config.DatabaseConfig{Password: "kms:"}}
+	if err := cfg.DecryptSecrets(context.Background(), reversingDecrypter{}); err == nil {
+		t.Error("expected an error when the decryption fails")
+	}
+}
@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/config"
+)
+
+func TestMessagingConfig_Validate(t *testing.T) {
+	kafka := config.KafkaConfig{
+		Brokers:               []string{"kafka:9092"},
+		PublishTimeout:        5 * time.Second,
+		PublishMaxAttempts:    3,
+		PublishInitialBackoff: 200 * time.Millisecond,
+		PublishMaxBackoff:     2 * time.Second,
+	}
+	withoutBrokers := kafka
+	withoutBrokers.Brokers = nil
+	invalidBackoff := kafka
+	invalidBackoff.PublishMaxBackoff = 100 * time.Millisecond
+
+	tests := []struct {
+		name      string
+		messaging config.MessagingConfig
+		wantErr   bool
+	}{
+		{name: "rabbitmq", messaging: config.MessagingConfig{Broker: config.MessageBrokerRabbitMQ}},
+		{name: "kafka", messaging: config.MessagingConfig{Broker: config.MessageBrokerKafka, Kafka: kafka}},
+		{name: "kafka without brokers", messaging: config.MessagingConfig{Broker: config.MessageBrokerKafka, Kafka: withoutBrokers}, wantErr: true},
+		{name: "kafka with invalid backoff", messaging: config.MessagingConfig{Broker: config.MessageBrokerKafka, Kafka: invalidBackoff}, wantErr: true},
+		{name: "unknown broker", messaging: config.MessagingConfig{Broker: "nats"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.messaging.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNATSConfig_Validate(t *testing.T) {
+	valid := config.NATSConfig{
+		URL:                   "nats://nats:4222",
+		Stream:                "AUTH",
+		StreamMaxAge:          24 * time.Hour,
+		SubjectPrefix:         "auth-service.",
+		AckWait:               30 * time.Second,
+		PublishTimeout:        5 * time.Second,
+		PublishMaxAttempts:    3,
+		PublishInitialBackoff: 200 * time.Millisecond,
+		PublishMaxBackoff:     2 * time.Second,
+	}
+	withoutURL := valid
+	withoutURL.URL = ""
+	withoutPrefix := valid
+	withoutPrefix.SubjectPrefix = ""
+	externalStreams := withoutPrefix
+	externalStreams.Stream = ""
+	wildcardSubject := valid
+	wildcardSubject.Subjects = map[string]string{"auth_user_updated": "users.*"}
+	withoutAckWait := valid
+	withoutAckWait.AckWait = 0
+
+	tests := []struct {
+		name    string
+		nats    config.NATSConfig
+		wantErr bool
+	}{
+		{name: "valid", nats: valid},
+		{name: "streams managed outside the service", nats: externalStreams},
+		{name: "missing URL", nats: withoutURL, wantErr: true},
+		{name: "stream without subject prefix", nats: withoutPrefix, wantErr: true},
+		{name: "wildcard subject", nats: wildcardSubject, wantErr: true},
+		{name: "missing ack wait", nats: withoutAckWait, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messaging := config.MessagingConfig{Broker: config.MessageBrokerNATS, NATS: tt.nats}
+			if err := messaging.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNATSConfig_SubjectAndDurableName(t *testing.T) {
+	cfg := config.NATSConfig{
+		SubjectPrefix: "auth-service.",
+		Subjects:      map[string]string{"auth_user_transferred": "citizens.transferred"},
+		DurablePrefix: "auth-service-",
+	}
+
+	if got := cfg.Subject("auth.user.registered"); got != "auth-service.auth.user.registered" {
+		t.Errorf("Subject() = %q, want the prefixed queue name", got)
+	}
+	if got := cfg.Subject("auth_user_transferred"); got != "citizens.transferred" {
+		t.Errorf("Subject() = %q, want the mapped subject", got)
+	}
+	if got := cfg.DurableName("auth.user.role_changed"); got != "auth-service-auth_user_role_changed" {
+		t.Errorf("DurableName() = %q, want dots replaced", got)
+	}
+}
@@ -0,0 +1,52 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/config"
+)
+
+func TestExchangeRoute_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		route   config.ExchangeRoute
+		wantErr bool
+	}{
+		{name: "default exchange", route: config.ExchangeRoute{Type: "unknown"}},
+		{name: "topic exchange", route: config.ExchangeRoute{Exchange: "auth.events", Type: "topic", RoutingKey: "user.registered"}},
+		{name: "fanout without routing key", route: config.ExchangeRoute{Exchange: "auth.events", Type: "fanout"}},
+		{name: "unknown type", route: config.ExchangeRoute{Exchange: "auth.events", Type: "headers", RoutingKey: "user.registered"}, wantErr: true},
+		{name: "topic without routing key", route: config.ExchangeRoute{Exchange: "auth.events", Type: "topic"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.route.Validate("RABBITMQ_USER_REGISTERED")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRabbitMQConfig_Route(t *testing.T) {
+	cfg := config.RabbitMQConfig{UserRegisteredQueue: "auth.user.registered", UserMergedQueue: "auth.user.merged"}
+
+	if _, ok := cfg.Route("auth.user.registered"); ok {
+		t.Error("Route() found a route without exchange")
+	}
+	if routes := cfg.Routes(); len(routes) != 0 {
+		t.Errorf("Routes() = %v, want none without exchange", routes)
+	}
+
+	cfg.UserRegisteredRoute = config.ExchangeRoute{Exchange: "auth.events", Type: "topic", RoutingKey: "user.registered"}
+	if route, ok := cfg.Route("auth.user.registered"); !ok || route != cfg.UserRegisteredRoute {
+		t.Errorf("Route() = %+v, %v, want the user registered route", route, ok)
+	}
+	if _, ok := cfg.Route("auth.user.merged"); ok {
+		t.Error("Route() found a route for a queue without exchange")
+	}
+	if routes := cfg.Routes(); len(routes) != 1 || routes["auth.user.registered"] != cfg.UserRegisteredRoute {
+		t.Errorf("Routes() = %v, want the user registered route only", routes)
+	}
+}
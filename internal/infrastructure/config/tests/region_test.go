@@ -0,0 +1,43 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/config"
+)
+
+func TestRegionConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		region  config.RegionConfig
+		wantErr bool
+	}{
+		{name: "single region", region: config.RegionConfig{BlacklistReplication: config.BlacklistReplicationNone}},
+		{name: "region without replication", region: config.RegionConfig{ID: "us-east-1", BlacklistReplication: config.BlacklistReplicationNone}},
+		{name: "invalid region", region: config.RegionConfig{ID: "US East"}, wantErr: true},
+		{name: "redis streams", region: config.RegionConfig{ID: "us-east-1", BlacklistReplication: config.BlacklistReplicationRedisStreams, ReplicationStream: "auth:blacklist", ReplicationStreamMaxLen: 1000}},
+		{name: "replication without region", region: config.RegionConfig{BlacklistReplication: config.BlacklistReplicationRedisStreams, ReplicationStream: "auth:blacklist", ReplicationStreamMaxLen: 1000}, wantErr: true},
+		{name: "replication without stream", region: config.RegionConfig{ID: "us-east-1", BlacklistReplication: config.BlacklistReplicationRedisStreams, ReplicationStreamMaxLen: 1000}, wantErr: true},
+		{name: "unknown replication", region: config.RegionConfig{ID: "us-east-1", BlacklistReplication: "gossip"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.region.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_ReplicationRedis(t *testing.T) {
+	cfg := &config.Config{Redis: config.RedisConfig{Host: "redis", Port: 6379, Password: "secret", DB: 2}}
+	if got := cfg.ReplicationRedis(); got.Address != "redis:6379" || got.Password != "secret" || got.DB != 2 {
+		t.Errorf("ReplicationRedis() = %+v, want the Redis of the service", got)
+	}
+
+	cfg.Region.ReplicationRedis = config.ReplicationRedisConfig{Address: "global-redis:6379"}
+	if got := cfg.ReplicationRedis(); got.Address != "global-redis:6379" || got.Password != "" {
+		t.Errorf("ReplicationRedis() = %+v, want the shared Redis", got)
+	}
+}
@@ -0,0 +1,68 @@
+package geoip
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/oschwald/geoip2-golang"
+	"go.uber.org/zap"
+
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// MaxMindLocator resolves IP addresses with a MaxMind GeoIP2/GeoLite2 City or Country database
+type MaxMindLocator struct {
+	reader *geoip2.Reader
+	logger *zap.Logger
+}
+
+// NewMaxMindLocator opens the MaxMind database at path
+func NewMaxMindLocator(path string, logger *zap.Logger) (*MaxMindLocator, error) {
+	reader, err := geoip2.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open GeoIP database: %w", err)
+	}
+
+	metadata := reader.Metadata()
+	logger.Info("GeoIP database loaded",
+		zap.String("type", metadata.DatabaseType),
+		zap.Uint("build_epoch", metadata.BuildEpoch))
+
+	return &MaxMindLocator{
+		reader: reader,
+		logger: logger,
+	}, nil
+}
+
+// Locate returns the approximate location of an IP address.
+// Country databases return locations without coordinates.
+func (l *MaxMindLocator) Locate(ctx context.Context, ip string) (*domain.GeoLocation, error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil || parsed.IsPrivate() || parsed.IsLoopback() || parsed.IsUnspecified() {
+		return nil, domainerrors.ErrLocationNotFound
+	}
+
+	record, err := l.reader.City(parsed)
+	if err != nil {
+		l.logger.Error("failed to look up IP address", zap.Error(err))
+		return nil, fmt.Errorf("failed to look up IP address: %w", err)
+	}
+
+	if record.Country.IsoCode == "" {
+		return nil, domainerrors.ErrLocationNotFound
+	}
+
+	return &domain.GeoLocation{
+		Country:   record.Country.IsoCode,
+		City:      record.City.Names["en"],
+		Latitude:  record.Location.Latitude,
+		Longitude: record.Location.Longitude,
+	}, nil
+}
+
+// Close releases the database
+func (l *MaxMindLocator) Close() error {
+	return l.reader.Close()
+}
@@ -0,0 +1,50 @@
+package hashing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
+)
+
+// BcryptHasher hashes passwords with bcrypt on the calling goroutine
+type BcryptHasher struct {
+	cost int
+}
+
+// NewBcryptHasher creates a new instance of BcryptHasher with the given cost
+func NewBcryptHasher(cost int) *BcryptHasher {
+	return &BcryptHasher{cost: cost}
+}
+
+// Hash returns the bcrypt hash of the password
+func (h *BcryptHasher) Hash(ctx context.Context, password string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	start := time.Now()
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	metrics.ObservePasswordHash("hash", time.Since(start))
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	return string(hashedPassword), nil
+}
+
+// Compare reports whether the password matches the bcrypt hash
+func (h *BcryptHasher) Compare(ctx context.Context, hash, password string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	start := time.Now()
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	metrics.ObservePasswordHash("compare", time.Since(start))
+
+	return err == nil, nil
+}
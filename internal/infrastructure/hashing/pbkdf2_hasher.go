@@ -0,0 +1,112 @@
+package hashing
+
+import (
+	"context"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
+)
+
+const (
+	// PBKDF2Prefix starts the hashes of PBKDF2Hasher, "$pbkdf2-sha256$i=<iterations>$<salt>$<key>"
+	PBKDF2Prefix = "$pbkdf2-sha256$"
+
+	// pbkdf2SaltLength and pbkdf2KeyLength are the lengths in bytes of the salt and the derived key, the
+	// salt is the 128 bits required by NIST SP 800-132
+	pbkdf2SaltLength = 16
+	pbkdf2KeyLength  = 32
+)
+
+// PBKDF2Hasher hashes passwords with PBKDF2-HMAC-SHA256 on the calling goroutine. Unlike bcrypt it is a
+// FIPS 140-3 approved key derivation function. Hashes of another format, e.g. the bcrypt hashes stored
+// before the algorithm changed, are verified by the legacy hasher, and never match without one.
+type PBKDF2Hasher struct {
+	iterations int
+	legacy     ports.PasswordHasher
+}
+
+// NewPBKDF2Hasher creates a new instance of PBKDF2Hasher with the given iterations, legacy may be nil
+func NewPBKDF2Hasher(iterations int, legacy ports.PasswordHasher) *PBKDF2Hasher {
+	return &PBKDF2Hasher{iterations: iterations, legacy: legacy}
+}
+
+// Hash returns the PBKDF2 hash of the password with a random salt
+func (h *PBKDF2Hasher) Hash(ctx context.Context, password string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	salt := make([]byte, pbkdf2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	start := time.Now()
+	key, err := pbkdf2.Key(sha256.New, password, salt, h.iterations, pbkdf2KeyLength)
+	metrics.ObservePasswordHash("hash", time.Since(start))
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	return fmt.Sprintf("%si=%d$%s$%s", PBKDF2Prefix, h.iterations,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// Compare reports whether the password matches the hash, using the iterations the hash was created with
+func (h *PBKDF2Hasher) Compare(ctx context.Context, hash, password string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	if !strings.HasPrefix(hash, PBKDF2Prefix) {
+		if h.legacy == nil {
+			return false, nil
+		}
+		return h.legacy.Compare(ctx, hash, password)
+	}
+
+	iterations, salt, key, ok := parsePBKDF2Hash(hash)
+	if !ok {
+		return false, nil
+	}
+
+	start := time.Now()
+	derived, err := pbkdf2.Key(sha256.New, password, salt, iterations, len(key))
+	metrics.ObservePasswordHash("compare", time.Since(start))
+	if err != nil {
+		return false, nil
+	}
+
+	return subtle.ConstantTimeCompare(derived, key) == 1, nil
+}
+
+// parsePBKDF2Hash returns the iterations, salt and derived key of a hash
+func parsePBKDF2Hash(hash string) (int, []byte, []byte, bool) {
+	parts := strings.Split(strings.TrimPrefix(hash, PBKDF2Prefix), "$")
+	if len(parts) != 3 {
+		return 0, nil, nil, false
+	}
+
+	iterations, err := strconv.Atoi(strings.TrimPrefix(parts[0], "i="))
+	if err != nil || !strings.HasPrefix(parts[0], "i=") || iterations < 1 {
+		return 0, nil, nil, false
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil || len(salt) == 0 {
+		return 0, nil, nil, false
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil || len(key) == 0 {
+		return 0, nil, nil, false
+	}
+	return iterations, salt, key, true
+}
@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/hashing"
+)
+
+// Run with:
+//
+//	go test -run '^$' -bench . -benchmem ./internal/infrastructure/hashing/tests/
+//
+// Each step of BCRYPT_COST doubles the comparison time; use these numbers to size
+// PASSWORD_HASH_WORKERS against the login latency SLO in loadtest/README.md.
+
+var benchCosts = []int{bcrypt.DefaultCost, bcrypt.DefaultCost + 1, bcrypt.DefaultCost + 2}
+
+func BenchmarkBcryptHasher_Compare(b *testing.B) {
+	ctx := context.Background()
+
+	for _, cost := range benchCosts {
+		b.Run(fmt.Sprintf("cost=%d", cost), func(b *testing.B) {
+			hasher := hashing.NewBcryptHasher(cost)
+			hash, err := hasher.Hash(ctx, "password123")
+			if err != nil {
+				b.Fatalf("Hash() unexpected error: %v", err)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if ok, err := hasher.Compare(ctx, hash, "password123"); err != nil || !ok {
+					b.Fatalf("Compare() = %v, %v, want true, nil", ok, err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkWorkerPool_Compare runs concurrent comparisons through the bounded pool,
+// as concurrent logins do when PASSWORD_HASH_WORKERS is set
+func BenchmarkWorkerPool_Compare(b *testing.B) {
+	ctx := context.Background()
+	hasher := hashing.NewBcryptHasher(bcrypt.DefaultCost)
+
+	hash, err := hasher.Hash(ctx, "password123")
+	if err != nil {
+		b.Fatalf("Hash() unexpected error: %v", err)
+	}
+
+	pool := hashing.NewWorkerPool(hasher, 4, 100, zap.NewNop())
+	defer pool.Close()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if ok, err := pool.Compare(ctx, hash, "password123"); err != nil || !ok {
+				b.Errorf("Compare() = %v, %v, want true, nil", ok, err)
+				return
+			}
+		}
+	})
+}
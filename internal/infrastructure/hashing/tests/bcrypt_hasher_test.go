@@ -0,0 +1,62 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/hashing"
+)
+
+func TestBcryptHasher_HashAndCompare(t *testing.T) {
+	hasher := hashing.NewBcryptHasher(bcrypt.MinCost)
+	ctx := context.Background()
+
+	hash, err := hasher.Hash(ctx, "password123")
+	if err != nil {
+		t.Fatalf("Hash() unexpected error = %v", err)
+	}
+
+	cost, err := bcrypt.Cost([]byte(hash))
+	if err != nil || cost != bcrypt.MinCost {
+		t.Errorf("hash cost = %v (err %v), want %v", cost, err, bcrypt.MinCost)
+	}
+
+	tests := []struct {
+		name     string
+		hash     string
+		password string
+		want     bool
+	}{
+		{name: "matching password", hash: hash, password: "password123", want: true},
+		{name: "wrong password", hash: hash, password: "wrongpassword", want: false},
+		{name: "malformed hash", hash: "not-a-hash", password: "password123", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := hasher.Compare(ctx, tt.hash, tt.password)
+			if err != nil {
+				t.Fatalf("Compare() unexpected error = %v", err)
+			}
+			if match != tt.want {
+				t.Errorf("Compare() = %v, want %v", match, tt.want)
+			}
+		})
+	}
+}
+
+func TestBcryptHasher_CanceledContext(t *testing.T) {
+	hasher := hashing.NewBcryptHasher(bcrypt.MinCost)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := hasher.Hash(ctx, "password123"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Hash() error = %v, want %v", err, context.Canceled)
+	}
+	if _, err := hasher.Compare(ctx, "hash", "password123"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Compare() error = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/hashing"
+)
+
+func TestPBKDF2Hasher_HashAndCompare(t *testing.T) {
+	hasher := hashing.NewPBKDF2Hasher(1000, nil)
+	ctx := context.Background()
+
+	hash, err := hasher.Hash(ctx, "password123")
+	if err != nil {
+		t.Fatalf("Hash() unexpected error = %v", err)
+	}
+	if !strings.HasPrefix(hash, hashing.PBKDF2Prefix+"i=1000$") {
+		t.Errorf("hash = %q, want the pbkdf2-sha256 format with 1000 iterations", hash)
+	}
+
+	other, err := hasher.Hash(ctx, "password123")
+	if err != nil {
+		t.Fatalf("Hash() unexpected error = %v", err)
+	}
+	if other == hash {
+		t.Error("hashes of the same password must differ by their salt")
+	}
+
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		hash     string
+		password string
+		want     bool
+	}{
+		{name: "matching password", hash: hash, password: "password123", want: true},
+		{name: "wrong password", hash: hash, password: "wrongpassword", want: false},
+		// The iterations of the hash are used, not the ones of the hasher
+		{name: "other iterations", hash: mustHash(t, hashing.NewPBKDF2Hasher(2000, nil), "password123"), password: "password123", want: true},
+		{name: "malformed hash", hash: hashing.PBKDF2Prefix + "i=x$salt$key", password: "password123", want: false},
+		{name: "bcrypt hash without legacy hasher", hash: string(bcryptHash), password: "password123", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := hasher.Compare(ctx, tt.hash, tt.password)
+			if err != nil {
+				t.Fatalf("Compare() unexpected error = %v", err)
+			}
+			if match != tt.want {
+				t.Errorf("Compare() = %v, want %v", match, tt.want)
+			}
+		})
+	}
+}
+
+func TestPBKDF2Hasher_LegacyHashes(t *testing.T) {
+	legacy := hashing.NewBcryptHasher(bcrypt.MinCost)
+	hasher := hashing.NewPBKDF2Hasher(1000, legacy)
+	ctx := context.Background()
+
+	bcryptHash := mustHash(t, legacy, "password123")
+	for password, want := range map[string]bool{"password123": true, "wrongpassword": false} {
+		match, err := hasher.Compare(ctx, bcryptHash, password)
+		if err != nil {
+			t.Fatalf("Compare() unexpected error = %v", err)
+		}
+		if match != want {
+			t.Errorf("Compare(%q) with a bcrypt hash = %v, want %v", password, match, want)
+		}
+	}
+}
+
+func TestPBKDF2Hasher_CanceledContext(t *testing.T) {
+	hasher := hashing.NewPBKDF2Hasher(1000, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := hasher.Hash(ctx, "password123"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Hash() error = %v, want %v", err, context.Canceled)
+	}
+	if _, err := hasher.Compare(ctx, "hash", "password123"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Compare() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func mustHash(t *testing.T, hasher ports.PasswordHasher, password string) string {
+	t.Helper()
+	hash, err := hasher.Hash(context.Background(), password)
+	if err != nil {
+		t.Fatalf("Hash() unexpected error = %v", err)
+	}
+	return hash
+}
@@ -0,0 +1,156 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/hashing"
+)
+
+// blockingHasher records concurrency and blocks until released
+type blockingHasher struct {
+	release   chan struct{}
+	running   atomic.Int32
+	maxActive atomic.Int32
+}
+
+func (h *blockingHasher) Hash(ctx context.Context, password string) (string, error) {
+	active := h.running.Add(1)
+	defer h.running.Add(-1)
+	for {
+		current := h.maxActive.Load()
+		if active <= current || h.maxActive.CompareAndSwap(current, active) {
+			break
+		}
+	}
+	<-h.release
+	return "hash:" + password, nil
+}
+
+func (h *blockingHasher) Compare(ctx context.Context, hash, password string) (bool, error) {
+	return hash == "hash:"+password, nil
+}
+
+func TestWorkerPool_HashAndCompare(t *testing.T) {
+	pool := hashing.NewWorkerPool(hashing.NewBcryptHasher(bcrypt.MinCost), 2, 10, zap.NewNop())
+	defer pool.Close()
+	ctx := context.Background()
+
+	hash, err := pool.Hash(ctx, "password123")
+	if err != nil {
+		t.Fatalf("Hash() unexpected error = %v", err)
+	}
+
+	match, err := pool.Compare(ctx, hash, "password123")
+	if err != nil || !match {
+		t.Errorf("Compare() = %v, %v, want true, nil", match, err)
+	}
+
+	match, err = pool.Compare(ctx, hash, "wrongpassword")
+	if err != nil || match {
+		t.Errorf("Compare() = %v, %v, want false, nil", match, err)
+	}
+}
+
+func TestWorkerPool_BoundsConcurrency(t *testing.T) {
+	hasher := &blockingHasher{release: make(chan struct{})}
+	pool := hashing.NewWorkerPool(hasher, 2, 10, zap.NewNop())
+	defer pool.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := pool.Hash(context.Background(), "password"); err != nil {
+				t.Errorf("Hash() unexpected error = %v", err)
+			}
+		}()
+	}
+
+	// Let the submissions queue up before releasing the workers
+	time.Sleep(50 * time.Millisecond)
+	close(hasher.release)
+	wg.Wait()
+
+	if got := hasher.maxActive.Load(); got > 2 {
+		t.Errorf("max concurrent operations = %d, want at most 2", got)
+	}
+}
+
+func TestWorkerPool_ContextCanceledWhileQueued(t *testing.T) {
+	hasher := &blockingHasher{release: make(chan struct{})}
+	pool := hashing.NewWorkerPool(hasher, 1, 10, zap.NewNop())
+	defer pool.Close()
+	defer close(hasher.release)
+
+	// Occupy the only worker
+	go func() { _, _ = pool.Hash(context.Background(), "busy") }()
+	time.Sleep(20 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := pool.Hash(ctx, "password"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Hash() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestWorkerPool_Closed(t *testing.T) {
+	pool := hashing.NewWorkerPool(hashing.NewBcryptHasher(bcrypt.MinCost), 1, 1, zap.NewNop())
+	pool.Close()
+	pool.Close()
+
+	if _, err := pool.Hash(context.Background(), "password"); !errors.Is(err, hashing.ErrPoolClosed) {
+		t.Errorf("Hash() error = %v, want %v", err, hashing.ErrPoolClosed)
+	}
+}
+
+func TestWorkerPool_StopDrainsRunningOperations(t *testing.T) {
+	hasher := &blockingHasher{release: make(chan struct{})}
+	pool := hashing.NewWorkerPool(hasher, 1, 10, zap.NewNop())
+
+	// Occupy the only worker and queue a second operation
+	for i := 0; i < 2; i++ {
+		go func() { _, _ = pool.Hash(context.Background(), "password") }()
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(hasher.release)
+	}()
+	if err := pool.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop() unexpected error = %v", err)
+	}
+
+	if stats := pool.DrainStats(); stats.Drained != 1 || stats.Cancelled != 1 {
+		t.Errorf("DrainStats() = %+v, want 1 drained and 1 cancelled", stats)
+	}
+}
+
+func TestWorkerPool_StopTimeout(t *testing.T) {
+	hasher := &blockingHasher{release: make(chan struct{})}
+	pool := hashing.NewWorkerPool(hasher, 1, 10, zap.NewNop())
+	defer close(hasher.release)
+
+	go func() { _, _ = pool.Hash(context.Background(), "busy") }()
+	time.Sleep(20 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := pool.Stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Stop() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if stats := pool.DrainStats(); stats.Cancelled != 1 {
+		t.Errorf("DrainStats() = %+v, want 1 cancelled", stats)
+	}
+}
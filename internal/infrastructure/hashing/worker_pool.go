@@ -0,0 +1,180 @@
+package hashing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
+)
+
+// ErrPoolClosed is returned when an operation is submitted to a closed worker pool
+var ErrPoolClosed = errors.New("password hashing worker pool is closed")
+
+// WorkerPool runs hash/compare operations on a bounded number of goroutines,
+// so bursts of logins queue up instead of saturating every CPU.
+type WorkerPool struct {
+	hasher    ports.PasswordHasher
+	jobs      chan func()
+	quit      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+	logger    *zap.Logger
+
+	// Drain statistics of Stop
+	running   atomic.Int64
+	stopping  atomic.Bool
+	drained   atomic.Int64
+	cancelled atomic.Int64
+}
+
+// NewWorkerPool creates a new WorkerPool with the given number of workers and queue size
+func NewWorkerPool(hasher ports.PasswordHasher, workers, queueSize int, logger *zap.Logger) *WorkerPool {
+	p := &WorkerPool{
+		hasher: hasher,
+		jobs:   make(chan func(), queueSize),
+		quit:   make(chan struct{}),
+		logger: logger,
+	}
+
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+
+	logger.Info("password hashing worker pool started", zap.Int("workers", workers), zap.Int("queue_size", queueSize))
+	return p
+}
+
+// Hash hashes the password on a pool worker
+func (p *WorkerPool) Hash(ctx context.Context, password string) (string, error) {
+	var hash string
+	err := p.submit(ctx, func() error {
+		var err error
+		hash, err = p.hasher.Hash(ctx, password)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return hash, nil
+}
+
+// Compare compares the password and hash on a pool worker
+func (p *WorkerPool) Compare(ctx context.Context, hash, password string) (bool, error) {
+	var match bool
+	err := p.submit(ctx, func() error {
+		var err error
+		match, err = p.hasher.Compare(ctx, hash, password)
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	return match, nil
+}
+
+// Start implements Component, the workers are already running since NewWorkerPool
+func (p *WorkerPool) Start(ctx context.Context) error {
+	return nil
+}
+
+// Stop stops the workers after they finish their current operation, until the context is done.
+// Operations still queued are abandoned and their callers get ErrPoolClosed.
+func (p *WorkerPool) Stop(ctx context.Context) error {
+	p.closeOnce.Do(func() {
+		p.stopping.Store(true)
+		close(p.quit)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		p.cancelled.Store(int64(len(p.jobs)))
+		p.logger.Info("password hashing worker pool stopped")
+		return nil
+	case <-ctx.Done():
+		p.cancelled.Store(int64(len(p.jobs)) + p.running.Load())
+		return fmt.Errorf("timeout waiting for password hashing workers to stop: %w", ctx.Err())
+	}
+}
+
+// DrainStats reports the operations completed and abandoned by Stop, it implements ports.DrainReporter
+func (p *WorkerPool) DrainStats() ports.DrainStats {
+	return ports.DrainStats{Drained: int(p.drained.Load()), Cancelled: int(p.cancelled.Load())}
+}
+
+// Close stops the workers after they finish their current operation
+func (p *WorkerPool) Close() {
+	_ = p.Stop(context.Background())
+}
+
+// submit queues the operation and waits for its result.
+// Operations whose context is done before a worker picks them up are skipped.
+func (p *WorkerPool) submit(ctx context.Context, operation func() error) error {
+	done := make(chan error, 1)
+	queuedAt := time.Now()
+
+	job := func() {
+		metrics.ObservePasswordHashQueueWait(time.Since(queuedAt))
+		if err := ctx.Err(); err != nil {
+			done <- err
+			return
+		}
+		done <- operation()
+	}
+
+	select {
+	case p.jobs <- job:
+		metrics.SetPasswordHashQueueDepth(len(p.jobs))
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-p.quit:
+		return ErrPoolClosed
+	}
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-p.quit:
+		return ErrPoolClosed
+	}
+}
+
+func (p *WorkerPool) work() {
+	defer p.wg.Done()
+	for {
+		// Stopping takes precedence over the queued operations
+		select {
+		case <-p.quit:
+			return
+		default:
+		}
+
+		select {
+		case job := <-p.jobs:
+			metrics.SetPasswordHashQueueDepth(len(p.jobs))
+			p.running.Add(1)
+			job()
+			p.running.Add(-1)
+			if p.stopping.Load() {
+				p.drained.Add(1)
+			}
+		case <-p.quit:
+			return
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package http
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
+)
+
+// LimitedExternalConnectivityClient bounds the concurrent calls to the external-connectivity service,
+// so a slow centralizer during a registration burst makes callers wait or fail fast
+// instead of piling up goroutines and connections.
+type LimitedExternalConnectivityClient struct {
+	ports.ExternalConnectivityClient
+	slots        chan struct{}
+	maxQueued    int64
+	queued       atomic.Int64
+	queueTimeout time.Duration
+	logger       *zap.Logger
+}
+
+// NewLimitedExternalConnectivityClient wraps the client allowing at most maxConcurrent calls in progress
+// and maxQueued calls waiting up to queueTimeout for a free slot
+func NewLimitedExternalConnectivityClient(client ports.ExternalConnectivityClient, maxConcurrent, maxQueued int, queueTimeout time.Duration, logger *zap.Logger) *LimitedExternalConnectivityClient {
+	logger.Info("external connectivity concurrency limit enabled",
+		zap.Int("max_concurrent_calls", maxConcurrent),
+		zap.Int("max_queued_calls", maxQueued),
+		zap.Duration("queue_timeout", queueTimeout))
+
+	return &LimitedExternalConnectivityClient{
+		ExternalConnectivityClient: client,
+		slots:                      make(chan struct{}, maxConcurrent),
+		maxQueued:                  int64(maxQueued),
+		queueTimeout:               queueTimeout,
+		logger:                     logger,
+	}
+}
+
+// CheckCitizenExists checks the citizen once a slot is free.
+// Returns ErrCentralizerBusy when the queue is full or the wait exceeds the queue timeout.
+func (c *LimitedExternalConnectivityClient) CheckCitizenExists(ctx context.Context, idCitizen int) (bool, error) {
+	if err := c.acquire(ctx); err != nil {
+		return false, err
+	}
+	defer c.release()
+
+	return c.ExternalConnectivityClient.CheckCitizenExists(ctx, idCitizen)
+}
+
+// acquire takes a free slot, waiting in the queue when all of them are in use
+func (c *LimitedExternalConnectivityClient) acquire(ctx context.Context) error {
+	select {
+	case c.slots <- struct{}{}:
+		metrics.SetExternalConnectivityInFlightCalls(len(c.slots))
+		return nil
+	default:
+	}
+
+	if c.queued.Add(1) > c.maxQueued {
+		c.queued.Add(-1)
+		metrics.IncExternalConnectivityRejectedCalls("queue_full")
+		c.logger.Warn("external connectivity queue is full, rejecting call", zap.Int64("max_queued_calls", c.maxQueued))
+		return domainerrors.ErrCentralizerBusy
+	}
+	metrics.SetExternalConnectivityQueuedCalls(int(c.queued.Load()))
+	defer func() {
+		metrics.SetExternalConnectivityQueuedCalls(int(c.queued.Add(-1)))
+	}()
+
+	queuedAt := time.Now()
+	timer := time.NewTimer(c.queueTimeout)
+	defer timer.Stop()
+
+	select {
+	case c.slots <- struct{}{}:
+		metrics.ObserveExternalConnectivityQueueWait(time.Since(queuedAt))
+		metrics.SetExternalConnectivityInFlightCalls(len(c.slots))
+		return nil
+	case <-timer.C:
+		metrics.ObserveExternalConnectivityQueueWait(time.Since(queuedAt))
+		metrics.IncExternalConnectivityRejectedCalls("queue_timeout")
+		c.logger.Warn("timed out waiting for a free external connectivity slot", zap.Duration("queue_timeout", c.queueTimeout))
+		return domainerrors.ErrCentralizerBusy
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release frees the slot taken by acquire
+func (c *LimitedExternalConnectivityClient) release() {
+	<-c.slots
+	metrics.SetExternalConnectivityInFlightCalls(len(c.slots))
+}
@@ -11,8 +11,13 @@ import (
 	"time"
 
 	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/retry"
 )
 
+// citizenCheckPolicy retries the citizen checks failing on network errors, 5xx responses or an expired token
+var citizenCheckPolicy = retry.Policy{MaxAttempts: 3, InitialBackoff: 200 * time.Millisecond, MaxBackoff: time.Second, Jitter: retry.FullJitter}
+
 // ExternalConnectivityClient implements the client for external-connectivity microservice
 type ExternalConnectivityClient struct {
 	baseURL      string
@@ -94,7 +99,11 @@ func (c *ExternalConnectivityClient) getAccessToken(ctx context.Context) (string
 	if resp.StatusCode != http.StatusOK {
 		c.logger.Error("token request failed",
 			zap.Int("status_code", resp.StatusCode))
-		return "", fmt.Errorf("token request failed with status: %d", resp.StatusCode)
+		err := fmt.Errorf("token request failed with status: %d", resp.StatusCode)
+		if !retryableStatus(resp.StatusCode) {
+			return "", retry.Permanent(err)
+		}
+		return "", err
 	}
 
 	var tokenResp TokenResponse
@@ -116,6 +125,23 @@ func (c *ExternalConnectivityClient) getAccessToken(ctx context.Context) (string
 // CheckCitizenExists verifies if a citizen exists in the centralizer
 // Returns true if citizen exists (HTTP 200), false if not exists (HTTP 204)
 func (c *ExternalConnectivityClient) CheckCitizenExists(ctx context.Context, idCitizen int) (bool, error) {
+	var exists bool
+	err := citizenCheckPolicy.DoNotify(ctx, func(ctx context.Context) error {
+		var err error
+		exists, err = c.checkCitizenExists(ctx, idCitizen)
+		return err
+	}, func(attempt int, err error, backoff time.Duration) {
+		c.logger.Warn("citizen check failed, retrying",
+			zap.Int("attempt", attempt),
+			zap.Duration("backoff", backoff),
+			zap.Error(err),
+			zap.Int("id_citizen", idCitizen))
+	})
+	return exists, err
+}
+
+// checkCitizenExists makes a single citizen check, errors that a retry can't fix are permanent
+func (c *ExternalConnectivityClient) checkCitizenExists(ctx context.Context, idCitizen int) (bool, error) {
 	// Get access token
 	token, err := c.getAccessToken(ctx)
 	if err != nil {
@@ -132,7 +158,7 @@ func (c *ExternalConnectivityClient) CheckCitizenExists(ctx context.Context, idC
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		c.logger.Error("failed to create request", zap.Error(err))
-		return false, fmt.Errorf("failed to create request: %w", err)
+		return false, retry.Permanent(fmt.Errorf("failed to create request: %w", err))
 	}
 
 	// Add authorization header
@@ -174,6 +200,10 @@ func (c *ExternalConnectivityClient) CheckCitizenExists(ctx context.Context, idC
 		c.logger.Warn("unexpected status code from external-connectivity",
 			zap.Int("status_code", resp.StatusCode),
 			zap.Int("id_citizen", idCitizen))
-		return false, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		err := fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		if !retryableStatus(resp.StatusCode) {
+			return false, retry.Permanent(err)
+		}
+		return false, err
 	}
 }
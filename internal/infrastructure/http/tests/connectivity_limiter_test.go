@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	httpClient "github.com/kristianrpo/auth-microservice/internal/infrastructure/http"
+)
+
+// blockingClient records concurrency and blocks until released
+type blockingClient struct {
+	release   chan struct{}
+	running   atomic.Int32
+	maxActive atomic.Int32
+}
+
+func (c *blockingClient) CheckCitizenExists(ctx context.Context, idCitizen int) (bool, error) {
+	active := c.running.Add(1)
+	defer c.running.Add(-1)
+	for {
+		current := c.maxActive.Load()
+		if active <= current || c.maxActive.CompareAndSwap(current, active) {
+			break
+		}
+	}
+	<-c.release
+	return idCitizen%2 == 0, nil
+}
+
+func TestLimitedExternalConnectivityClient_BoundsConcurrency(t *testing.T) {
+	client := &blockingClient{release: make(chan struct{})}
+	limited := httpClient.NewLimitedExternalConnectivityClient(client, 2, 10, time.Second, zap.NewNop())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func(idCitizen int) {
+			defer wg.Done()
+			exists, err := limited.CheckCitizenExists(context.Background(), idCitizen)
+			if err != nil || exists != (idCitizen%2 == 0) {
+				t.Errorf("CheckCitizenExists(%d) = %v, %v", idCitizen, exists, err)
+			}
+		}(i)
+	}
+
+	// Let the calls queue up before releasing the upstream
+	time.Sleep(50 * time.Millisecond)
+	close(client.release)
+	wg.Wait()
+
+	if got := client.maxActive.Load(); got > 2 {
+		t.Errorf("max concurrent calls = %d, want at most 2", got)
+	}
+}
+
+func TestLimitedExternalConnectivityClient_Saturated(t *testing.T) {
+	tests := []struct {
+		name         string
+		maxQueued    int
+		queueTimeout time.Duration
+		ctxTimeout   time.Duration
+		wantErr      error
+	}{
+		{name: "queue full", maxQueued: 0, queueTimeout: time.Second, ctxTimeout: time.Second, wantErr: domainerrors.ErrCentralizerBusy},
+		{name: "queue timeout", maxQueued: 1, queueTimeout: 20 * time.Millisecond, ctxTimeout: time.Second, wantErr: domainerrors.ErrCentralizerBusy},
+		{name: "context done while queued", maxQueued: 1, queueTimeout: time.Second, ctxTimeout: 20 * time.Millisecond, wantErr: context.DeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &blockingClient{release: make(chan struct{})}
+			limited := httpClient.NewLimitedExternalConnectivityClient(client, 1, tt.maxQueued, tt.queueTimeout, zap.NewNop())
+
+			// Occupy the only slot
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				_, _ = limited.CheckCitizenExists(context.Background(), 1)
+			}()
+			time.Sleep(20 * time.Millisecond)
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.ctxTimeout)
+			defer cancel()
+
+			if _, err := limited.CheckCitizenExists(ctx, 2); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckCitizenExists() error = %v, want %v", err, tt.wantErr)
+			}
+
+			close(client.release)
+			<-done
+
+			// The slot is free again once the upstream call returns
+			if _, err := limited.CheckCitizenExists(context.Background(), 2); err != nil {
+				t.Errorf("CheckCitizenExists() after release error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	httpClient "github.com/kristianrpo/auth-microservice/internal/infrastructure/http"
+)
+
+func TestWebhookAlertNotifier_NotifyPanic(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", r.Header.Get("Content-Type"))
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := httpClient.NewWebhookAlertNotifier(server.URL, "auth-microservice", time.Second)
+	err := notifier.NotifyPanic(context.Background(), ports.PanicAlert{
+		RequestID: "req-42",
+		Method:    http.MethodGet,
+		Endpoint:  "/api/auth/me",
+		Panic:     "boom",
+		Stack:     strings.Repeat("frame\n", 2000),
+		Time:      time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("NotifyPanic() error = %v", err)
+	}
+
+	if body["request_id"] != "req-42" || body["panic"] != "boom" || body["service"] != "auth-microservice" {
+		t.Errorf("webhook body = %v, want the alert fields", body)
+	}
+	if text, _ := body["text"].(string); !strings.Contains(text, "/api/auth/me") {
+		t.Errorf("text = %q, want a summary with the endpoint", text)
+	}
+	if stack, _ := body["stack"].(string); len(stack) > 4096 {
+		t.Errorf("stack length = %d, want it truncated to 4096", len(stack))
+	}
+}
+
+func TestWebhookAlertNotifier_FailedDelivery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	notifier := httpClient.NewWebhookAlertNotifier(server.URL, "auth-microservice", time.Second)
+	if err := notifier.NotifyPanic(context.Background(), ports.PanicAlert{Panic: "boom"}); err == nil {
+		t.Error("NotifyPanic() error = nil, want the webhook status error")
+	}
+}
+
+func TestWebhookAlertNotifier_Retries(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int // status of each call, the last one repeats
+		wantErr      bool
+		wantAttempts int
+	}{
+		{name: "transient failure", statuses: []int{http.StatusServiceUnavailable, http.StatusOK}, wantAttempts: 2},
+		{name: "rejected alert", statuses: []int{http.StatusBadRequest}, wantErr: true, wantAttempts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				call := int(attempts.Add(1)) - 1
+				w.WriteHeader(tt.statuses[min(call, len(tt.statuses)-1)])
+			}))
+			defer server.Close()
+
+			notifier := httpClient.NewWebhookAlertNotifier(server.URL, "auth-microservice", time.Second)
+			err := notifier.NotifyPanic(context.Background(), ports.PanicAlert{Panic: "boom"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NotifyPanic() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := int(attempts.Load()); got != tt.wantAttempts {
+				t.Errorf("webhook called %d times, want %d", got, tt.wantAttempts)
+			}
+		})
+	}
+}
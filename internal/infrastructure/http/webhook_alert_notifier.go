@@ -0,0 +1,136 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
+	"github.com/kristianrpo/auth-microservice/internal/retry"
+)
+
+// maxAlertStackLength bounds the stack sent in an alert, chat webhooks reject large messages
+const maxAlertStackLength = 4096
+
+// maxConcurrentAlerts bounds the alerts in flight, so a panic on every request can't pile up goroutines
+const maxConcurrentAlerts = 4
+
+// alertRetryPolicy retries the deliveries failing on network errors or 5xx responses
+var alertRetryPolicy = retry.Policy{MaxAttempts: 3, InitialBackoff: 500 * time.Millisecond, MaxBackoff: 2 * time.Second, Jitter: retry.FullJitter}
+
+// errAlertDropped is returned when too many alerts are already being sent
+var errAlertDropped = errors.New("alert dropped: too many alerts in flight")
+
+// webhookAlert is the JSON body posted to the webhook. Text holds a readable summary for chat webhooks
+// (Slack, Mattermost, Teams connectors), the other fields are meant for alerting pipelines.
+type webhookAlert struct {
+	Text      string    `json:"text"`
+	Alert     string    `json:"alert"`
+	Service   string    `json:"service"`
+	RequestID string    `json:"request_id,omitempty"`
+	Method    string    `json:"method"`
+	Endpoint  string    `json:"endpoint"`
+	Panic     string    `json:"panic"`
+	Stack     string    `json:"stack"`
+	Time      time.Time `json:"time"`
+}
+
+// WebhookAlertNotifier posts critical alerts as JSON to a webhook
+type WebhookAlertNotifier struct {
+	url        string
+	service    string
+	httpClient *http.Client
+	slots      chan struct{}
+}
+
+// NewWebhookAlertNotifier creates a notifier posting to the webhook URL, each call is bounded by the timeout
+func NewWebhookAlertNotifier(url, service string, timeout time.Duration) *WebhookAlertNotifier {
+	return &WebhookAlertNotifier{
+		url:     url,
+		service: service,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+		slots: make(chan struct{}, maxConcurrentAlerts),
+	}
+}
+
+// NotifyPanic posts the alert of a recovered panic, retrying transient failures. Alerts are dropped while
+// too many are in flight.
+func (n *WebhookAlertNotifier) NotifyPanic(ctx context.Context, alert ports.PanicAlert) error {
+	select {
+	case n.slots <- struct{}{}:
+		defer func() { <-n.slots }()
+	default:
+		metrics.IncAlertNotifications("dropped")
+		return errAlertDropped
+	}
+
+	payload := n.panicPayload(alert)
+	err := alertRetryPolicy.Do(ctx, func(ctx context.Context) error {
+		return n.post(ctx, payload)
+	})
+	if err != nil {
+		metrics.IncAlertNotifications("failed")
+		return err
+	}
+	metrics.IncAlertNotifications("sent")
+	return nil
+}
+
+func (n *WebhookAlertNotifier) panicPayload(alert ports.PanicAlert) webhookAlert {
+	stack := alert.Stack
+	if len(stack) > maxAlertStackLength {
+		stack = stack[:maxAlertStackLength]
+	}
+
+	return webhookAlert{
+		Text:      fmt.Sprintf("[%s] panic on %s %s (request %s): %s", n.service, alert.Method, alert.Endpoint, alert.RequestID, alert.Panic),
+		Alert:     "panic",
+		Service:   n.service,
+		RequestID: alert.RequestID,
+		Method:    alert.Method,
+		Endpoint:  alert.Endpoint,
+		Panic:     alert.Panic,
+		Stack:     stack,
+		Time:      alert.Time,
+	}
+}
+
+func (n *WebhookAlertNotifier) post(ctx context.Context, payload webhookAlert) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return retry.Permanent(fmt.Errorf("failed to encode alert: %w", err))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return retry.Permanent(fmt.Errorf("failed to create alert request: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call alert webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		err := fmt.Errorf("alert webhook failed with status: %d", resp.StatusCode)
+		if !retryableStatus(resp.StatusCode) {
+			return retry.Permanent(err)
+		}
+		return err
+	}
+	return nil
+}
+
+// retryableStatus checks if a failed response may succeed when the request is retried
+func retryableStatus(status int) bool {
+	return status >= 500 || status == http.StatusTooManyRequests
+}
@@ -0,0 +1,153 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/config"
+	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
+	"github.com/kristianrpo/auth-microservice/internal/retry"
+)
+
+// KafkaPublisher implements the MessagePublisher interface for Kafka.
+// The message is published unchanged, the same JSON event published to RabbitMQ, to the topic named after
+// the queue. The producer is idempotent and waits for all the in-sync replicas: a publish only succeeds once
+// the message is replicated, and failed or timed out produces are retried with backoff. As with RabbitMQ, a
+// message whose produce timed out may still have been written, so consumers must tolerate duplicates
+// (events carry a messageId).
+//
+// The messages are keyed by the user of the event, so the events of a user land in the same partition and
+// are consumed in order.
+type KafkaPublisher struct {
+	client *kgo.Client
+	cfg    config.KafkaConfig
+	logger *zap.Logger
+}
+
+// NewKafkaPublisher creates a new Kafka message publisher. The brokers are connected to on the first publish,
+// so the publisher is created while Kafka is down.
+func NewKafkaPublisher(cfg config.KafkaConfig, logger *zap.Logger) (*KafkaPublisher, error) {
+	client, err := kgo.NewClient(
+		kgo.SeedBrokers(cfg.Brokers...),
+		kgo.ClientID(cfg.ClientID),
+		kgo.RequiredAcks(kgo.AllISRAcks()),
+		kgo.RecordDeliveryTimeout(cfg.PublishTimeout),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kafka client: %w", err)
+	}
+
+	return &KafkaPublisher{
+		client: client,
+		cfg:    cfg,
+		logger: logger,
+	}, nil
+}
+
+// Publish sends a message to the topic of the queue and waits for the brokers to acknowledge it, retrying
+// according to the publish settings of the configuration
+func (p *KafkaPublisher) Publish(ctx context.Context, queueName string, message []byte) error {
+	policy := retry.Policy{
+		MaxAttempts:    max(p.cfg.PublishMaxAttempts, 1),
+		InitialBackoff: p.cfg.PublishInitialBackoff,
+		MaxBackoff:     p.cfg.PublishMaxBackoff,
+		Jitter:         retry.EqualJitter,
+	}
+
+	record := &kgo.Record{
+		Topic: p.cfg.TopicPrefix + queueName,
+		Key:   PartitionKey(message),
+		Value: message,
+		Headers: []kgo.RecordHeader{
+			{Key: "content-type", Value: []byte("application/json")},
+		},
+	}
+
+	err := policy.DoNotify(ctx, func(ctx context.Context) error {
+		err := p.produce(ctx, record)
+		if err != nil {
+			metrics.IncMessagePublishAttemptFailure(queueName, publishFailureReason(err))
+		}
+		return err
+	}, func(attempt int, err error, backoff time.Duration) {
+		p.logger.Warn("publish to Kafka failed, retrying",
+			zap.String("topic", record.Topic),
+			zap.Int("attempt", attempt),
+			zap.Int("max_attempts", policy.MaxAttempts),
+			zap.Duration("backoff", backoff),
+			zap.Error(err),
+		)
+	})
+	if err != nil {
+		metrics.IncMessagePublish(queueName, "failed")
+		return fmt.Errorf("failed to publish message to topic %s: %w", record.Topic, err)
+	}
+
+	metrics.IncMessagePublish(queueName, "confirmed")
+	p.logger.Debug("message published to Kafka", zap.String("topic", record.Topic))
+	return nil
+}
+
+// produce makes a single produce attempt and waits for its acknowledgement
+func (p *KafkaPublisher) produce(ctx context.Context, record *kgo.Record) error {
+	produceCtx, cancel := context.WithTimeout(ctx, p.cfg.PublishTimeout)
+	defer cancel()
+
+	// Each attempt produces a copy, a produced record must not be produced again
+	attempt := *record
+	return p.client.ProduceSync(produceCtx, &attempt).FirstErr()
+}
+
+// Close flushes the buffered messages and closes the client
+func (p *KafkaPublisher) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), p.cfg.PublishTimeout)
+	defer cancel()
+
+	err := p.client.Flush(ctx)
+	p.client.Close()
+	if err != nil {
+		return fmt.Errorf("failed to flush Kafka publisher: %w", err)
+	}
+	return nil
+}
+
+// eventKeys are the fields of the events identifying their user
+type eventKeys struct {
+	UserID    string `json:"userId"`
+	IDCitizen int    `json:"idCitizen"`
+}
+
+// PartitionKey returns the key of an event: the ID of its user, or the citizen ID for the events that
+// identify the user by it only. Events of no user are not keyed and spread over the partitions.
+func PartitionKey(message []byte) []byte {
+	var keys eventKeys
+	if err := json.Unmarshal(message, &keys); err != nil {
+		return nil
+	}
+
+	switch {
+	case keys.UserID != "":
+		return []byte(keys.UserID)
+	case keys.IDCitizen != 0:
+		return []byte(strconv.Itoa(keys.IDCitizen))
+	default:
+		return nil
+	}
+}
+
+// publishFailureReason classifies a failed publish attempt for metrics
+func publishFailureReason(err error) string {
+	switch {
+	case errors.Is(err, kgo.ErrRecordTimeout), errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	default:
+		return "error"
+	}
+}
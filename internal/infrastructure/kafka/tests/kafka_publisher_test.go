@@ -0,0 +1,28 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/kafka"
+)
+
+func TestPartitionKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{name: "user ID", message: `{"messageId":"m-1","userId":"user-1","idCitizen":42}`, want: "user-1"},
+		{name: "citizen ID", message: `{"messageId":"m-1","idCitizen":42}`, want: "42"},
+		{name: "event of no user", message: `{"messageId":"m-1","clientId":"client-1"}`},
+		{name: "invalid JSON", message: `not json`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := kafka.PartitionKey([]byte(tt.message)); string(got) != tt.want {
+				t.Errorf("PartitionKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package kms
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const (
+	// containerCredentialsHost serves the credentials of the ECS task role at a relative URI
+	containerCredentialsHost = "http://169.254.170.2"
+	// defaultIMDSEndpoint is the EC2 instance metadata service
+	defaultIMDSEndpoint = "http://169.254.169.254"
+	// webIdentitySessionName names the sessions of the role assumed with a web identity
+	webIdentitySessionName = "auth-microservice"
+)
+
+// awsCredentials are the credentials requests are signed with
+type awsCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// errNoCredentials is returned by a credentials source that does not apply to the environment
+var errNoCredentials = errors.New("no credentials")
+
+// resolveAWSCredentials resolves the credentials like the AWS SDKs, from the first source that applies:
+// the static keys of AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY (local development), the role assumed with
+// the web identity token of AWS_WEB_IDENTITY_TOKEN_FILE (EKS IAM roles for service accounts), the container
+// credentials endpoint (ECS task roles, EKS Pod Identity) and the instance profile (EC2).
+func resolveAWSCredentials(ctx context.Context, opts AWSOptions) (awsCredentials, error) {
+	sources := []func(context.Context, AWSOptions) (awsCredentials, error){
+		staticCredentials,
+		webIdentityCredentials,
+		containerCredentials,
+		instanceProfileCredentials,
+	}
+	for _, source := range sources {
+		creds, err := source(ctx, opts)
+		if errors.Is(err, errNoCredentials) {
+			continue
+		}
+		return creds, err
+	}
+	return awsCredentials{}, errNoCredentials
+}
+
+// staticCredentials reads the keys of the environment
+func staticCredentials(_ context.Context, _ AWSOptions) (awsCredentials, error) {
+	accessKeyID, secretAccessKey := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if accessKeyID == "" || secretAccessKey == "" {
+		return awsCredentials{}, errNoCredentials
+	}
+	return awsCredentials{
+		AccessKeyID:     accessKeyID,
+		SecretAccessKey: secretAccessKey,
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}, nil
+}
+
+// webIdentityCredentials assumes the role of AWS_ROLE_ARN with the web identity token of the service account
+func webIdentityCredentials(ctx context.Context, opts AWSOptions) (awsCredentials, error) {
+	roleARN, tokenFile := os.Getenv("AWS_ROLE_ARN"), os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE")
+	if roleARN == "" || tokenFile == "" {
+		return awsCredentials{}, errNoCredentials
+	}
+	token, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return awsCredentials{}, fmt.Errorf("failed to read web identity token: %w", err)
+	}
+
+	endpoint := opts.STSEndpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://sts.%s.amazonaws.com", opts.Region)
+	}
+	query := url.Values{}
+	query.Set("Action", "AssumeRoleWithWebIdentity")
+	query.Set("Version", "2011-06-15")
+	query.Set("RoleArn", roleARN)
+	query.Set("RoleSessionName", webIdentitySessionName)
+	query.Set("WebIdentityToken", strings.TrimSpace(string(token)))
+
+	// AssumeRoleWithWebIdentity is authenticated by the token, the request is not signed
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(endpoint, "/")+"/?"+query.Encode(), nil)
+	if err != nil {
+		return awsCredentials{}, err
+	}
+	body, err := doCredentialsRequest(opts.HTTPClient, req)
+	if err != nil {
+		return awsCredentials{}, fmt.Errorf("failed to assume role with web identity: %w", err)
+	}
+
+	var resp struct {
+		Credentials struct {
+			AccessKeyID     string `xml:"AccessKeyId"`
+			SecretAccessKey string `xml:"SecretAccessKey"`
+			SessionToken    string `xml:"SessionToken"`
+		} `xml:"AssumeRoleWithWebIdentityResult>Credentials"`
+	}
+	if err := xml.Unmarshal(body, &resp); err != nil {
+		return awsCredentials{}, fmt.Errorf("failed to decode sts response: %w", err)
+	}
+	return awsCredentials{
+		AccessKeyID:     resp.Credentials.AccessKeyID,
+		SecretAccessKey: resp.Credentials.SecretAccessKey,
+		SessionToken:    resp.Credentials.SessionToken,
+	}, nil
+}
+
+// containerCredentials reads the credentials of the task or pod from the container credentials endpoint
+func containerCredentials(ctx context.Context, opts AWSOptions) (awsCredentials, error) {
+	endpoint := os.Getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI")
+	if relative := os.Getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"); relative != "" {
+		endpoint = containerCredentialsHost + relative
+	}
+	if endpoint == "" {
+		return awsCredentials{}, errNoCredentials
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return awsCredentials{}, err
+	}
+	authorization := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN")
+	if tokenFile := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE"); tokenFile != "" {
+		token, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return awsCredentials{}, fmt.Errorf("failed to read container authorization token: %w", err)
+		}
+		authorization = strings.TrimSpace(string(token))
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	body, err := doCredentialsRequest(opts.HTTPClient, req)
+	if err != nil {
+		return awsCredentials{}, fmt.Errorf("failed to get container credentials: %w", err)
+	}
+	return decodeJSONCredentials(body)
+}
+
+// instanceProfileCredentials reads the credentials of the instance profile from IMDSv2
+func instanceProfileCredentials(ctx context.Context, opts AWSOptions) (awsCredentials, error) {
+	if os.Getenv("AWS_EC2_METADATA_DISABLED") == "true" {
+		return awsCredentials{}, errNoCredentials
+	}
+	endpoint := opts.IMDSEndpoint
+	if endpoint == "" {
+		endpoint = defaultIMDSEndpoint
+	}
+	endpoint = strings.TrimRight(endpoint, "/")
+
+	tokenReq, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint+"/latest/api/token", nil)
+	if err != nil {
+		return awsCredentials{}, err
+	}
+	tokenReq.Header.Set("X-aws-ec2-metadata-token-ttl-seconds", "60")
+	token, err := doCredentialsRequest(opts.HTTPClient, tokenReq)
+	if err != nil {
+		return awsCredentials{}, fmt.Errorf("failed to get instance metadata token: %w", err)
+	}
+
+	get := func(path string) ([]byte, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+path, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("X-aws-ec2-metadata-token", string(token))
+		return doCredentialsRequest(opts.HTTPClient, req)
+	}
+
+	role, err := get("/latest/meta-data/iam/security-credentials/")
+	if err != nil {
+		return awsCredentials{}, fmt.Errorf("failed to get instance profile role: %w", err)
+	}
+	body, err := get("/latest/meta-data/iam/security-credentials/" + strings.TrimSpace(string(role)))
+	if err != nil {
+		return awsCredentials{}, fmt.Errorf("failed to get instance profile credentials: %w", err)
+	}
+	return decodeJSONCredentials(body)
+}
+
+// decodeJSONCredentials decodes the credentials returned by the container and instance metadata endpoints
+func decodeJSONCredentials(body []byte) (awsCredentials, error) {
+	var resp struct {
+		AccessKeyID     string `json:"AccessKeyId"`
+		SecretAccessKey string `json:"SecretAccessKey"`
+		Token           string `json:"Token"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return awsCredentials{}, fmt.Errorf("failed to decode credentials: %w", err)
+	}
+	if resp.AccessKeyID == "" || resp.SecretAccessKey == "" {
+		return awsCredentials{}, errors.New("incomplete credentials")
+	}
+	return awsCredentials{
+		AccessKeyID:     resp.AccessKeyID,
+		SecretAccessKey: resp.SecretAccessKey,
+		SessionToken:    resp.Token,
+	}, nil
+}
+
+// doCredentialsRequest sends a request of a credentials source and returns its body
+func doCredentialsRequest(client *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return body, nil
+}
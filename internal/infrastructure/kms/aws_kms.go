@@ -0,0 +1,167 @@
+package kms
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	kmsService     = "kms"
+	sigV4Algo      = "AWS4-HMAC-SHA256"
+	kmsContentType = "application/x-amz-json-1.1"
+	kmsDecryptOp   = "TrentService.Decrypt"
+)
+
+// AWSOptions configures the AWS KMS decrypter. The endpoints default to the AWS ones, they are set to reach
+// a local KMS or in tests.
+type AWSOptions struct {
+	Region       string
+	Endpoint     string // defaults to the regional KMS endpoint
+	STSEndpoint  string // defaults to the regional STS endpoint, used with web identity credentials
+	IMDSEndpoint string // defaults to the EC2 instance metadata service
+	HTTPClient   *http.Client
+}
+
+// AWSDecrypter decrypts ciphertexts with the AWS KMS Decrypt API. The key is identified by the ciphertext.
+// Requests are signed with AWS Signature Version 4 using the credentials of the IAM role of the service,
+// resolved like the AWS SDKs do (see resolveAWSCredentials).
+type AWSDecrypter struct {
+	opts AWSOptions
+}
+
+// kmsDecryptResponse is the body returned by the KMS Decrypt API
+type kmsDecryptResponse struct {
+	Plaintext []byte `json:"Plaintext"`
+}
+
+// kmsErrorResponse is the error body returned by the KMS API
+type kmsErrorResponse struct {
+	Type    string `json:"__type"`
+	Message string `json:"message"`
+}
+
+// NewAWSDecrypter creates a new AWS KMS decrypter
+func NewAWSDecrypter(opts AWSOptions) *AWSDecrypter {
+	if opts.Endpoint == "" {
+		opts.Endpoint = fmt.Sprintf("https://kms.%s.amazonaws.com", opts.Region)
+	}
+	opts.Endpoint = strings.TrimRight(opts.Endpoint, "/")
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &AWSDecrypter{opts: opts}
+}
+
+// Decrypt decrypts a ciphertext blob produced by KMS Encrypt or GenerateDataKey
+func (d *AWSDecrypter) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	creds, err := resolveAWSCredentials(ctx, d.opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve aws credentials: %w", err)
+	}
+
+	// []byte fields are base64 encoded, as KMS expects
+	body, err := json.Marshal(map[string][]byte{"CiphertextBlob": ciphertext})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.opts.Endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kms request: %w", err)
+	}
+	req.Header.Set("Content-Type", kmsContentType)
+	req.Header.Set("X-Amz-Target", kmsDecryptOp)
+	signAWSRequest(req, string(body), creds, d.opts.Region, kmsService, time.Now().UTC())
+
+	resp, err := d.opts.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call kms: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp kmsErrorResponse
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&errResp)
+		return nil, fmt.Errorf("kms decrypt failed with status %d: %s %s", resp.StatusCode, errResp.Type, errResp.Message)
+	}
+
+	var decrypted kmsDecryptResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&decrypted); err != nil {
+		return nil, fmt.Errorf("failed to decode kms response: %w", err)
+	}
+	return decrypted.Plaintext, nil
+}
+
+// signAWSRequest adds the AWS Signature Version 4 headers to a request whose content type is set
+func signAWSRequest(req *http.Request, body string, creds awsCredentials, region, service string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	dateStamp := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	canonicalHeaders := "content-type:" + req.Header.Get("Content-Type") + "\n" +
+		"host:" + req.URL.Host + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+	signedHeaders := "content-type;host;x-amz-date"
+	if creds.SessionToken != "" {
+		canonicalHeaders += "x-amz-security-token:" + creds.SessionToken + "\n"
+		signedHeaders += ";x-amz-security-token"
+	}
+	if target := req.Header.Get("X-Amz-Target"); target != "" {
+		canonicalHeaders += "x-amz-target:" + target + "\n"
+		signedHeaders += ";x-amz-target"
+	}
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		hexSHA256(body),
+	}, "\n")
+
+	credentialScope := strings.Join([]string{dateStamp, region, service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		sigV4Algo,
+		amzDate,
+		credentialScope,
+		hexSHA256(canonicalRequest),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), dateStamp)
+	signingKey = hmacSHA256(signingKey, region)
+	signingKey = hmacSHA256(signingKey, service)
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algo, creds.AccessKeyID, credentialScope, signedHeaders, signature))
+}
+
+func hexSHA256(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
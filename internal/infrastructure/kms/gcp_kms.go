@@ -0,0 +1,132 @@
+package kms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	defaultGCPKMSEndpoint = "https://cloudkms.googleapis.com"
+	defaultGCPMetadataURL = "http://metadata.google.internal"
+	gcpMetadataTokenPath  = "/computeMetadata/v1/instance/service-accounts/default/token"
+	gcpAccessTokenEnv     = "GOOGLE_OAUTH_ACCESS_TOKEN"
+)
+
+// GCPOptions configures the Google Cloud KMS decrypter. The endpoints default to the Google ones, they are
+// set in tests.
+type GCPOptions struct {
+	KeyName     string // projects/<project>/locations/<location>/keyRings/<ring>/cryptoKeys/<key>
+	Endpoint    string // defaults to the Cloud KMS API
+	MetadataURL string // defaults to the metadata server
+	HTTPClient  *http.Client
+}
+
+// GCPDecrypter decrypts ciphertexts with the Cloud KMS decrypt API of a key. Requests are authenticated
+// with the access token of the service account of the workload, from the metadata server (GKE Workload
+// Identity, Compute Engine, Cloud Run), or with GOOGLE_OAUTH_ACCESS_TOKEN in local development.
+type GCPDecrypter struct {
+	opts GCPOptions
+}
+
+// gcpDecryptResponse is the body returned by the Cloud KMS decrypt API
+type gcpDecryptResponse struct {
+	Plaintext []byte `json:"plaintext"`
+}
+
+// gcpErrorResponse is the error body returned by the Google APIs
+type gcpErrorResponse struct {
+	Error struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// NewGCPDecrypter creates a new Cloud KMS decrypter of a key
+func NewGCPDecrypter(opts GCPOptions) *GCPDecrypter {
+	if opts.Endpoint == "" {
+		opts.Endpoint = defaultGCPKMSEndpoint
+	}
+	if opts.MetadataURL == "" {
+		opts.MetadataURL = defaultGCPMetadataURL
+	}
+	opts.Endpoint = strings.TrimRight(opts.Endpoint, "/")
+	opts.MetadataURL = strings.TrimRight(opts.MetadataURL, "/")
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &GCPDecrypter{opts: opts}
+}
+
+// Decrypt decrypts a ciphertext produced by the encrypt API of the key
+func (d *GCPDecrypter) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	token, err := d.accessToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gcp access token: %w", err)
+	}
+
+	// []byte fields are base64 encoded, as Cloud KMS expects
+	body, err := json.Marshal(map[string][]byte{"ciphertext": ciphertext})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.opts.Endpoint+"/v1/"+d.opts.KeyName+":decrypt", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cloud kms request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := d.opts.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call cloud kms: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp gcpErrorResponse
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&errResp)
+		return nil, fmt.Errorf("cloud kms decrypt failed with status %d: %s %s", resp.StatusCode, errResp.Error.Status, errResp.Error.Message)
+	}
+
+	var decrypted gcpDecryptResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&decrypted); err != nil {
+		return nil, fmt.Errorf("failed to decode cloud kms response: %w", err)
+	}
+	return decrypted.Plaintext, nil
+}
+
+// accessToken returns the access token of the environment, or of the service account of the workload
+func (d *GCPDecrypter) accessToken(ctx context.Context) (string, error) {
+	if token := os.Getenv(gcpAccessTokenEnv); token != "" {
+		return token, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.opts.MetadataURL+gcpMetadataTokenPath, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	body, err := doCredentialsRequest(d.opts.HTTPClient, req)
+	if err != nil {
+		return "", err
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return "", fmt.Errorf("failed to decode metadata token: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("metadata server returned no access token")
+	}
+	return token.AccessToken, nil
+}
@@ -0,0 +1,78 @@
+package kms
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// EncryptedPrefix marks the configuration values holding a KMS ciphertext instead of the plain value
+const EncryptedPrefix = "kms:"
+
+// Decrypter decrypts data encrypted with a KMS key, authenticating with the IAM identity of the service
+type Decrypter interface {
+	Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error)
+}
+
+// IsEncrypted reports whether a configuration value is KMS-encrypted
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, EncryptedPrefix)
+}
+
+// DecryptValue decrypts a KMS-encrypted configuration value. The value is either the ciphertext of the plain
+// value, "kms:<ciphertext>", or envelope encrypted, "kms:<encrypted data key>.<sealed value>": KMS decrypts
+// the AES-256 data key, which opens the sealed value, the 12-byte GCM nonce followed by the ciphertext.
+// Every part is base64 encoded. Envelope encryption lifts the 4 KB limit of the values KMS encrypts.
+func DecryptValue(ctx context.Context, decrypter Decrypter, value string) (string, error) {
+	encoded, ok := strings.CutPrefix(value, EncryptedPrefix)
+	if !ok {
+		return "", errors.New("value is not KMS-encrypted")
+	}
+
+	encryptedKey, sealed, envelope := strings.Cut(encoded, ".")
+	ciphertext, err := base64.StdEncoding.DecodeString(encryptedKey)
+	if err != nil {
+		return "", fmt.Errorf("invalid base64 ciphertext: %w", err)
+	}
+	plaintext, err := decrypter.Decrypt(ctx, ciphertext)
+	if err != nil {
+		return "", err
+	}
+	if !envelope {
+		return string(plaintext), nil
+	}
+
+	opened, err := openEnvelope(plaintext, sealed)
+	if err != nil {
+		return "", fmt.Errorf("failed to open envelope: %w", err)
+	}
+	return string(opened), nil
+}
+
+// openEnvelope decrypts the sealed value with the data key
+func openEnvelope(dataKey []byte, sealed string) ([]byte, error) {
+	if len(dataKey) != 32 {
+		return nil, fmt.Errorf("data key must be 32 bytes, got %d", len(dataKey))
+	}
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 sealed value: %w", err)
+	}
+
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("sealed value too short")
+	}
+	return gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+}
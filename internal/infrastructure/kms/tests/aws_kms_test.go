@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/kms"
+)
+
+var kmsSigV4Pattern = regexp.MustCompile(`^AWS4-HMAC-SHA256 Credential=(\w+)/\d{8}/us-east-1/kms/aws4_request, SignedHeaders=(\S+), Signature=[0-9a-f]{64}$`)
+
+// newKMSServer serves the KMS Decrypt API, checking the signature is made with the access key
+func newKMSServer(t *testing.T, accessKeyID, signedHeaders string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		match := kmsSigV4Pattern.FindStringSubmatch(r.Header.Get("Authorization"))
+		if match == nil || match[1] != accessKeyID || match[2] != signedHeaders {
+			t.Errorf("Authorization = %q, want a SigV4 signature of %s over %s", r.Header.Get("Authorization"), accessKeyID, signedHeaders)
+		}
+		if r.Header.Get("X-Amz-Target") != "TrentService.Decrypt" {
+			t.Errorf("X-Amz-Target = %q", r.Header.Get("X-Amz-Target"))
+		}
+
+		var req struct {
+			CiphertextBlob []byte
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || string(req.CiphertextBlob) != "ciphertext" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type":"InvalidCiphertextException","message":"invalid"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string][]byte{"Plaintext": []byte("plain-secret")})
+	}))
+}
+
+func TestAWSDecrypter_StaticCredentials(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+	server := newKMSServer(t, "AKIDEXAMPLE", "content-type;host;x-amz-date;x-amz-target")
+	defer server.Close()
+
+	decrypter := kms.NewAWSDecrypter(kms.AWSOptions{Region: "us-east-1", Endpoint: server.URL})
+	plaintext, err := decrypter.Decrypt(context.Background(), []byte("ciphertext"))
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	if string(plaintext) != "plain-secret" {
+		t.Errorf("Decrypt() = %q, want plain-secret", plaintext)
+	}
+
+	if _, err := decrypter.Decrypt(context.Background(), []byte("other")); err == nil {
+		t.Error("expected an error for a ciphertext rejected by KMS")
+	}
+}
+
+func TestAWSDecrypter_ContainerCredentials(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	t.Setenv("AWS_ROLE_ARN", "")
+	t.Setenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI", "")
+	t.Setenv("AWS_CONTAINER_AUTHORIZATION_TOKEN", "pod-token")
+	t.Setenv("AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE", "")
+
+	credentials := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "pod-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"AccessKeyId":"ASIAROLE","SecretAccessKey":"role-secret","Token":"role-session"}`))
+	}))
+	defer credentials.Close()
+	t.Setenv("AWS_CONTAINER_CREDENTIALS_FULL_URI", credentials.URL)
+
+	server := newKMSServer(t, "ASIAROLE", "content-type;host;x-amz-date;x-amz-security-token;x-amz-target")
+	defer server.Close()
+
+	decrypter := kms.NewAWSDecrypter(kms.AWSOptions{Region: "us-east-1", Endpoint: server.URL})
+	plaintext, err := decrypter.Decrypt(context.Background(), []byte("ciphertext"))
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	if string(plaintext) != "plain-secret" {
+		t.Errorf("Decrypt() = %q, want plain-secret", plaintext)
+	}
+}
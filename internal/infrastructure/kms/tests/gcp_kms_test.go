@@ -0,0 +1,61 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/kms"
+)
+
+func TestGCPDecrypter_Decrypt(t *testing.T) {
+	t.Setenv("GOOGLE_OAUTH_ACCESS_TOKEN", "")
+	const keyName = "projects/p/locations/global/keyRings/auth/cryptoKeys/config"
+
+	metadata := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token":"workload-token","expires_in":3599,"token_type":"Bearer"}`))
+	}))
+	defer metadata.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/"+keyName+":decrypt" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer workload-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":{"status":"UNAUTHENTICATED","message":"invalid token"}}`))
+			return
+		}
+
+		var req struct {
+			Ciphertext []byte `json:"ciphertext"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || string(req.Ciphertext) != "ciphertext" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string][]byte{"plaintext": []byte("plain-secret")})
+	}))
+	defer server.Close()
+
+	decrypter := kms.NewGCPDecrypter(kms.GCPOptions{KeyName: keyName, Endpoint: server.URL, MetadataURL: metadata.URL})
+	plaintext, err := decrypter.Decrypt(context.Background(), []byte("ciphertext"))
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	if string(plaintext) != "plain-secret" {
+		t.Errorf("Decrypt() = %q, want plain-secret", plaintext)
+	}
+
+	// An access token of the environment takes precedence over the metadata server
+	t.Setenv("GOOGLE_OAUTH_ACCESS_TOKEN", "developer-token")
+	if _, err := decrypter.Decrypt(context.Background(), []byte("ciphertext")); err == nil {
+		t.Error("expected the developer token to be used and rejected")
+	}
+}
@@ -0,0 +1,83 @@
+package tests
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/kms"
+)
+
+// fakeDecrypter "decrypts" the ciphertexts it knows
+type fakeDecrypter map[string][]byte
+
+func (d fakeDecrypter) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	plaintext, ok := d[string(ciphertext)]
+	if !ok {
+		return nil, errors.New("unknown ciphertext")
+	}
+	return plaintext, nil
+}
+
+// seal encrypts a value with a data key as the envelope format expects
+func seal(t *testing.T, dataKey []byte, value string) string {
+	t.Helper()
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(value), nil))
+}
+
+func TestDecryptValue(t *testing.T) {
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		t.Fatal(err)
+	}
+	decrypter := fakeDecrypter{
+		"encrypted-secret":   []byte("plain-secret"),
+		"encrypted-data-key": dataKey,
+		"short-data-key":     []byte("too short"),
+	}
+	encode := func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }
+	sealed := seal(t, dataKey, "enveloped-secret")
+
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{name: "ciphertext", value: "kms:" + encode("encrypted-secret"), want: "plain-secret"},
+		{name: "envelope", value: "kms:" + encode("encrypted-data-key") + "." + sealed, want: "enveloped-secret"},
+		{name: "not encrypted", value: "plain-secret", wantErr: true},
+		{name: "invalid base64", value: "kms:not base64!", wantErr: true},
+		{name: "decryption failure", value: "kms:" + encode("unknown"), wantErr: true},
+		{name: "invalid data key", value: "kms:" + encode("short-data-key") + "." + sealed, wantErr: true},
+		{name: "tampered envelope", value: "kms:" + encode("encrypted-data-key") + "." + seal(t, make([]byte, 32), "other"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := kms.DecryptValue(context.Background(), decrypter, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecryptValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("DecryptValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
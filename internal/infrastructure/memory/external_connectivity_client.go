@@ -0,0 +1,23 @@
+package memory
+
+import "context"
+
+// ExternalConnectivityClient is the in-memory implementation of the external-connectivity client, only the
+// citizens it is created with exist in the centralizer
+type ExternalConnectivityClient struct {
+	citizens map[int]bool
+}
+
+// NewExternalConnectivityClient creates a new instance of ExternalConnectivityClient
+func NewExternalConnectivityClient(citizens ...int) *ExternalConnectivityClient {
+	c := &ExternalConnectivityClient{citizens: make(map[int]bool, len(citizens))}
+	for _, idCitizen := range citizens {
+		c.citizens[idCitizen] = true
+	}
+	return c
+}
+
+// CheckCitizenExists reports whether the citizen is one of the citizens of the client
+func (c *ExternalConnectivityClient) CheckCitizenExists(ctx context.Context, idCitizen int) (bool, error) {
+	return c.citizens[idCitizen], nil
+}
@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// Fixtures is the sample data the in-memory repositories are seeded with in development mode
+type Fixtures struct {
+	Users []FixtureUser `json:"users"`
+}
+
+// FixtureUser is a sample user, active and able to sign in with its password
+type FixtureUser struct {
+	IDCitizen int    `json:"id_citizen"`
+	Email     string `json:"email"`
+	Password  string `json:"password"`
+	Name      string `json:"name"`
+	Role      string `json:"role,omitempty"` // USER when empty
+}
+
+// LoadFixtures reads the fixtures from a JSON file, unknown fields are rejected so typos are not ignored
+func LoadFixtures(path string) (*Fixtures, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open fixtures: %w", err)
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+
+	var fixtures Fixtures
+	if err := decoder.Decode(&fixtures); err != nil {
+		return nil, fmt.Errorf("failed to decode fixtures %s: %w", path, err)
+	}
+	return &fixtures, nil
+}
+
+// SeedUsers creates the users of the fixtures, hashing their passwords with hashPassword
+func (f *Fixtures) SeedUsers(ctx context.Context, users *UserRepository, hashPassword domain.PasswordHashFunc) error {
+	for i, fixture := range f.Users {
+		user, err := domain.NewUserWithHasher(fixture.Email, fixture.Password, fixture.Name, fixture.IDCitizen, domain.DefaultNameLimits(), hashPassword)
+		if err != nil {
+			return fmt.Errorf("invalid fixture user %d: %w", i, err)
+		}
+		if fixture.Role != "" {
+			role, err := domain.ParseRole(fixture.Role)
+			if err != nil {
+				return fmt.Errorf("invalid fixture user %d: %w", i, err)
+			}
+			user.Role = role
+		}
+
+		if err := users.Create(ctx, user); err != nil {
+			return fmt.Errorf("failed to seed fixture user %s: %w", fixture.Email, err)
+		}
+	}
+	return nil
+}
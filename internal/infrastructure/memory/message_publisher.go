@@ -0,0 +1,40 @@
+package memory
+
+import (
+	"context"
+	"sync"
+)
+
+// MessagePublisher is the in-memory implementation of the message publisher, it keeps the published
+// messages so they can be inspected
+type MessagePublisher struct {
+	mu       sync.Mutex
+	messages map[string][][]byte
+}
+
+// NewMessagePublisher creates a new instance of MessagePublisher
+func NewMessagePublisher() *MessagePublisher {
+	return &MessagePublisher{messages: make(map[string][][]byte)}
+}
+
+// Publish keeps a message published to a queue
+func (p *MessagePublisher) Publish(ctx context.Context, queueName string, message []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.messages[queueName] = append(p.messages[queueName], append([]byte(nil), message...))
+	return nil
+}
+
+// Messages returns the messages published to a queue, oldest first
+func (p *MessagePublisher) Messages(queueName string) [][]byte {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([][]byte(nil), p.messages[queueName]...)
+}
+
+// Close does nothing, there is no connection to close
+func (p *MessagePublisher) Close() error {
+	return nil
+}
@@ -0,0 +1,143 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// errClientIDTaken is returned by Create for a client_id already in use, like the unique constraint of the database
+var errClientIDTaken = errors.New("client_id already exists")
+
+// OAuthClientRepository is the in-memory implementation of the OAuth client repository.
+// Deleted clients are kept inactive, like the soft delete of the database.
+type OAuthClientRepository struct {
+	mu      sync.RWMutex
+	clients map[string]*domain.OAuthClient
+}
+
+// NewOAuthClientRepository creates a new instance of OAuthClientRepository
+func NewOAuthClientRepository() *OAuthClientRepository {
+	return &OAuthClientRepository{clients: make(map[string]*domain.OAuthClient)}
+}
+
+// Create creates a new OAuth client
+func (r *OAuthClientRepository) Create(ctx context.Context, client *domain.OAuthClient) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, other := range r.clients {
+		if other.ClientID == client.ClientID {
+			return errClientIDTaken
+		}
+	}
+
+	client.ID = uuid.New().String()
+	client.Version = 1
+	client.CreatedAt = time.Now()
+	client.UpdatedAt = client.CreatedAt
+	r.clients[client.ID] = copyClient(client)
+	return nil
+}
+
+// GetByClientID retrieves an active OAuth client by their client_id
+func (r *OAuthClientRepository) GetByClientID(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, client := range r.clients {
+		if client.ClientID == clientID && client.Active {
+			return copyClient(client), nil
+		}
+	}
+	return nil, domainerrors.ErrInvalidCredentials
+}
+
+// GetByID retrieves an OAuth client by their ID
+func (r *OAuthClientRepository) GetByID(ctx context.Context, id string) (*domain.OAuthClient, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	client, ok := r.clients[id]
+	if !ok {
+		return nil, domainerrors.ErrClientNotFound
+	}
+	return copyClient(client), nil
+}
+
+// Update updates an existing OAuth client, which must not have been modified since it was read. The
+// client_id and the secret are kept.
+func (r *OAuthClientRepository) Update(ctx context.Context, client *domain.OAuthClient) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.clients[client.ID]
+	if !ok {
+		return domainerrors.ErrClientNotFound
+	}
+	if stored.Version != client.Version {
+		return domainerrors.ErrVersionConflict
+	}
+
+	client.UpdatedAt = time.Now()
+	client.Version++
+	updated := copyClient(client)
+	updated.ClientID = stored.ClientID
+	updated.ClientSecret = stored.ClientSecret
+	updated.CreatedAt = stored.CreatedAt
+	r.clients[client.ID] = updated
+	return nil
+}
+
+// Delete deactivates an OAuth client (soft delete)
+func (r *OAuthClientRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	client, ok := r.clients[id]
+	if !ok {
+		return domainerrors.ErrClientNotFound
+	}
+	client.Active = false
+	client.UpdatedAt = time.Now()
+	client.Version++
+	return nil
+}
+
+// List retrieves all active OAuth clients, newest first
+func (r *OAuthClientRepository) List(ctx context.Context) ([]*domain.OAuthClient, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var clients []*domain.OAuthClient
+	for _, client := range r.clients {
+		if client.Active {
+			clients = append(clients, copyClient(client))
+		}
+	}
+	sort.Slice(clients, func(i, j int) bool {
+		if !clients[i].CreatedAt.Equal(clients[j].CreatedAt) {
+			return clients[i].CreatedAt.After(clients[j].CreatedAt)
+		}
+		return clients[i].ID < clients[j].ID
+	})
+	return clients, nil
+}
+
+// copyClient copies an OAuth client along with its slices
+func copyClient(client *domain.OAuthClient) *domain.OAuthClient {
+	c := *client
+	c.Scopes = slices.Clone(client.Scopes)
+	c.GrantTypes = slices.Clone(client.GrantTypes)
+	c.RedirectURIs = slices.Clone(client.RedirectURIs)
+	c.Tags = slices.Clone(client.Tags)
+	return &c
+}
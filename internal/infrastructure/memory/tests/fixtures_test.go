@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/memory"
+)
+
+func plainHash(password string) (string, error) {
+	return "hashed:" + password, nil
+}
+
+func writeFixtures(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "fixtures.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFixtures_SeedUsers(t *testing.T) {
+	fixtures, err := memory.LoadFixtures(writeFixtures(t, `{"users": [
+		{"id_citizen": 1, "email": "admin@example.com", "password": "admin-password", "name": "Admin", "role": "ADMIN"},
+		{"id_citizen": 2, "email": "user@example.com", "password": "user-password", "name": "User"}
+	]}`))
+	if err != nil {
+		t.Fatalf("LoadFixtures() error = %v", err)
+	}
+
+	users := memory.NewUserRepository()
+	if err := fixtures.SeedUsers(context.Background(), users, plainHash); err != nil {
+		t.Fatalf("SeedUsers() error = %v", err)
+	}
+
+	admin, err := users.GetByEmail(context.Background(), "admin@example.com")
+	if err != nil {
+		t.Fatalf("GetByEmail() error = %v", err)
+	}
+	if admin.Role != domain.RoleAdmin || admin.Password != "hashed:admin-password" || !admin.IsActive() {
+		t.Errorf("admin = %+v, want an active ADMIN with the hashed password", admin)
+	}
+	user, err := users.GetByIDCitizen(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("GetByIDCitizen() error = %v", err)
+	}
+	if user.Role != domain.RoleUser {
+		t.Errorf("user role = %s, want %s", user.Role, domain.RoleUser)
+	}
+}
+
+func TestFixtures_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "unknown field", content: `{"users": [{"id_citizen": 1, "mail": "a@example.com"}]}`},
+		{name: "short password", content: `{"users": [{"id_citizen": 1, "email": "a@example.com", "password": "short", "name": "A"}]}`},
+		{name: "unknown role", content: `{"users": [{"id_citizen": 1, "email": "a@example.com", "password": "a-password", "name": "A", "role": "ROOT"}]}`},
+		{name: "duplicated user", content: `{"users": [
+			{"id_citizen": 1, "email": "a@example.com", "password": "a-password", "name": "A"},
+			{"id_citizen": 1, "email": "b@example.com", "password": "b-password", "name": "B"}
+		]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixtures, err := memory.LoadFixtures(writeFixtures(t, tt.content))
+			if err == nil {
+				err = fixtures.SeedUsers(context.Background(), memory.NewUserRepository(), plainHash)
+			}
+			if err == nil {
+				t.Error("error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestFixtures_DevUsersFile(t *testing.T) {
+	fixtures, err := memory.LoadFixtures("../../../../fixtures/dev-users.json")
+	if err != nil {
+		t.Fatalf("LoadFixtures() error = %v", err)
+	}
+	if err := fixtures.SeedUsers(context.Background(), memory.NewUserRepository(), plainHash); err != nil {
+		t.Errorf("SeedUsers() error = %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/memory"
+)
+
+func TestOAuthClientRepository(t *testing.T) {
+	repo := memory.NewOAuthClientRepository()
+	ctx := context.Background()
+
+	client := &domain.OAuthClient{ClientID: "billing", ClientSecret: "hash", Name: "Billing", Scopes: []string{"read"}, Active: true}
+	if err := repo.Create(ctx, client); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if client.ID == "" || client.CreatedAt.IsZero() {
+		t.Fatalf("Create() = %+v, want the ID and timestamps set", client)
+	}
+	if err := repo.Create(ctx, &domain.OAuthClient{ClientID: "billing", Active: true}); err == nil {
+		t.Error("Create() with a taken client_id error = nil, want an error")
+	}
+
+	// The stored client is a copy
+	client.Scopes[0] = "write"
+	got, err := repo.GetByClientID(ctx, "billing")
+	if err != nil {
+		t.Fatalf("GetByClientID() error = %v", err)
+	}
+	if got.Scopes[0] != "read" {
+		t.Errorf("GetByClientID() scopes = %v, want [read]", got.Scopes)
+	}
+
+	got.Name = "Billing v2"
+	got.ClientSecret = "other"
+	if err := repo.Update(ctx, got); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	updated, err := repo.GetByID(ctx, client.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if updated.Name != "Billing v2" || updated.ClientSecret != "hash" {
+		t.Errorf("GetByID() = %+v, want the new name and the original secret", updated)
+	}
+
+	if err := repo.Delete(ctx, client.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := repo.GetByClientID(ctx, "billing"); !errors.Is(err, domainerrors.ErrInvalidCredentials) {
+		t.Errorf("GetByClientID() of a deleted client error = %v, want %v", err, domainerrors.ErrInvalidCredentials)
+	}
+	if clients, _ := repo.List(ctx); len(clients) != 0 {
+		t.Errorf("List() = %d clients, want none", len(clients))
+	}
+	if _, err := repo.GetByID(ctx, client.ID); err != nil {
+		t.Errorf("GetByID() of a deleted client error = %v, want it kept inactive", err)
+	}
+
+	for _, err := range []error{
+		repo.Update(ctx, &domain.OAuthClient{ID: "missing"}),
+		repo.Delete(ctx, "missing"),
+	} {
+		if !errors.Is(err, domainerrors.ErrClientNotFound) {
+			t.Errorf("error = %v, want %v", err, domainerrors.ErrClientNotFound)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/memory"
+)
+
+func TestTokenRepository_RefreshTokens(t *testing.T) {
+	repo := memory.NewTokenRepository()
+	ctx := context.Background()
+	data := &domain.RefreshTokenData{IDCitizen: 1, UserID: "user-1", Email: "a@example.com"}
+
+	if err := repo.StoreRefreshToken(ctx, "refresh-1", data, time.Hour); err != nil {
+		t.Fatalf("StoreRefreshToken() error = %v", err)
+	}
+	if err := repo.StoreRefreshToken(ctx, "expired", data, -time.Second); err != nil {
+		t.Fatalf("StoreRefreshToken() error = %v", err)
+	}
+
+	if got, err := repo.GetActiveRefreshToken(ctx, "refresh-1"); err != nil || got.UserID != "user-1" {
+		t.Errorf("GetActiveRefreshToken() = %+v, %v, want the stored data", got, err)
+	}
+	if _, err := repo.GetRefreshToken(ctx, "expired"); !errors.Is(err, domainerrors.ErrInvalidToken) {
+		t.Errorf("GetRefreshToken() of an expired token error = %v, want %v", err, domainerrors.ErrInvalidToken)
+	}
+	if count, _ := repo.CountActiveSessions(ctx, 1); count != 1 {
+		t.Errorf("CountActiveSessions() = %d, want 1", count)
+	}
+
+	if err := repo.RotateRefreshToken(ctx, "refresh-1", "refresh-2", data, time.Hour); err != nil {
+		t.Fatalf("RotateRefreshToken() error = %v", err)
+	}
+	if _, err := repo.GetRefreshToken(ctx, "refresh-1"); !errors.Is(err, domainerrors.ErrInvalidToken) {
+		t.Errorf("GetRefreshToken() of a rotated token error = %v, want %v", err, domainerrors.ErrInvalidToken)
+	}
+
+	if err := repo.BlacklistToken(ctx, "refresh-2", time.Hour); err != nil {
+		t.Fatalf("BlacklistToken() error = %v", err)
+	}
+	if _, err := repo.GetActiveRefreshToken(ctx, "refresh-2"); !errors.Is(err, domainerrors.ErrTokenRevoked) {
+		t.Errorf("GetActiveRefreshToken() of a blacklisted token error = %v, want %v", err, domainerrors.ErrTokenRevoked)
+	}
+
+	if err := repo.DeleteUserTokens(ctx, "user-1", 0); err != nil {
+		t.Fatalf("DeleteUserTokens() error = %v", err)
+	}
+	if count, _ := repo.CountActiveSessions(ctx, 1); count != 0 {
+		t.Errorf("CountActiveSessions() after DeleteUserTokens() = %d, want 0", count)
+	}
+}
+
+func TestTokenRepository_SessionsAndVersions(t *testing.T) {
+	repo := memory.NewTokenRepository()
+	ctx := context.Background()
+
+	_ = repo.StoreRefreshToken(ctx, "refresh", &domain.RefreshTokenData{IDCitizen: 1}, time.Hour)
+	if err := repo.RevokeSession(ctx, "access", time.Hour, "refresh"); err != nil {
+		t.Fatalf("RevokeSession() error = %v", err)
+	}
+	if blacklisted, _ := repo.IsTokenBlacklisted(ctx, "access"); !blacklisted {
+		t.Error("IsTokenBlacklisted() after RevokeSession() = false")
+	}
+	if _, err := repo.GetRefreshToken(ctx, "refresh"); !errors.Is(err, domainerrors.ErrInvalidToken) {
+		t.Errorf("GetRefreshToken() after RevokeSession() error = %v, want %v", err, domainerrors.ErrInvalidToken)
+	}
+
+	if version, _ := repo.GetTokenVersion(ctx, 1); version != 0 {
+		t.Errorf("GetTokenVersion() = %d, want 0 when none is set", version)
+	}
+	_ = repo.SetTokenVersion(ctx, 1, 4, time.Hour)
+	if version, _ := repo.GetTokenVersion(ctx, 1); version != 4 {
+		t.Errorf("GetTokenVersion() = %d, want 4", version)
+	}
+	_ = repo.SetTokenVersion(ctx, 2, 4, -time.Second)
+	if version, _ := repo.GetTokenVersion(ctx, 2); version != 0 {
+		t.Errorf("GetTokenVersion() of an expired version = %d, want 0", version)
+	}
+}
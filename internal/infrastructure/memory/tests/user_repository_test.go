@@ -0,0 +1,144 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/memory"
+)
+
+func TestUserRepository(t *testing.T) {
+	repo := memory.NewUserRepository()
+	ctx := context.Background()
+
+	user := &domain.User{IDCitizen: 1, Email: "a@example.com", Name: "A", Role: domain.RoleUser, Status: domain.UserStatusActive, TokenVersion: 3}
+	if err := repo.Create(ctx, user); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if user.ID == "" || user.CreatedAt.IsZero() {
+		t.Fatalf("Create() = %+v, want the ID and timestamps set", user)
+	}
+
+	tests := []struct {
+		name  string
+		user  *domain.User
+		error error
+	}{
+		{name: "email taken", user: &domain.User{IDCitizen: 2, Email: "a@example.com"}, error: domainerrors.ErrUserAlreadyExists},
+		{name: "email taken in another case", user: &domain.User{IDCitizen: 2, Email: "A@Example.com"}, error: domainerrors.ErrUserAlreadyExists},
+		{name: "citizen ID taken", user: &domain.User{IDCitizen: 1, Email: "b@example.com"}, error: domainerrors.ErrUserAlreadyExists},
+		{name: "new user", user: &domain.User{IDCitizen: 2, Email: "b@example.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := repo.Create(ctx, tt.user); !errors.Is(err, tt.error) {
+				t.Errorf("Create() error = %v, want %v", err, tt.error)
+			}
+		})
+	}
+
+	got, err := repo.GetByEmail(ctx, "a@example.com")
+	if err != nil || got.ID != user.ID {
+		t.Fatalf("GetByEmail() = %+v, %v, want %s", got, err, user.ID)
+	}
+	if other, err := repo.GetByEmail(ctx, "A@EXAMPLE.COM"); err != nil || other.ID != user.ID {
+		t.Errorf("GetByEmail() in uppercase = %+v, %v, want %s", other, err, user.ID)
+	}
+	if exists, _ := repo.Exists(ctx, " A@example.com "); !exists {
+		t.Error("Exists() in another case = false")
+	}
+
+	// Changing the returned user doesn't change the stored one
+	got.Name = "Changed"
+	if stored, _ := repo.GetByID(ctx, user.ID); stored.Name != "A" {
+		t.Errorf("stored name = %q after changing a returned user, want A", stored.Name)
+	}
+
+	got.TokenVersion = 1
+	if err := repo.Update(ctx, got); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if stored, _ := repo.GetByIDCitizen(ctx, 1); stored.Name != "Changed" || stored.TokenVersion != 3 {
+		t.Errorf("updated user = %+v, want the new name and the token version kept", stored)
+	}
+
+	got.Email = "b@example.com"
+	if err := repo.Update(ctx, got); !errors.Is(err, domainerrors.ErrUserAlreadyExists) {
+		t.Errorf("Update() to a taken email error = %v, want %v", err, domainerrors.ErrUserAlreadyExists)
+	}
+
+	if err := repo.Delete(ctx, user.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := repo.GetByID(ctx, user.ID); !errors.Is(err, domainerrors.ErrUserNotFound) {
+		t.Errorf("GetByID() of a deleted user error = %v, want %v", err, domainerrors.ErrUserNotFound)
+	}
+	if exists, _ := repo.Exists(ctx, "a@example.com"); exists {
+		t.Error("Exists() of a deleted user = true")
+	}
+	if err := repo.Delete(ctx, user.ID); !errors.Is(err, domainerrors.ErrUserNotFound) {
+		t.Errorf("Delete() twice error = %v, want %v", err, domainerrors.ErrUserNotFound)
+	}
+}
+
+func TestUserRepository_UpdateVersionConflict(t *testing.T) {
+	repo := memory.NewUserRepository()
+	ctx := context.Background()
+
+	if err := repo.Create(ctx, &domain.User{IDCitizen: 1, Email: "a@example.com", Name: "A"}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	first, _ := repo.GetByIDCitizen(ctx, 1)
+	second, _ := repo.GetByIDCitizen(ctx, 1)
+
+	first.Name = "First"
+	if err := repo.Update(ctx, first); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if first.Version != 2 {
+		t.Errorf("version after update = %d, want 2", first.Version)
+	}
+
+	second.Name = "Second"
+	if err := repo.Update(ctx, second); !errors.Is(err, domainerrors.ErrVersionConflict) {
+		t.Fatalf("Update() of a stale user error = %v, want ErrVersionConflict", err)
+	}
+	if stored, _ := repo.GetByIDCitizen(ctx, 1); stored.Name != "First" {
+		t.Errorf("stored name = %q, want the first update kept", stored.Name)
+	}
+}
+
+func TestUserRepository_RecordLogin(t *testing.T) {
+	repo := memory.NewUserRepository()
+	ctx := context.Background()
+
+	user := &domain.User{IDCitizen: 1, Email: "a@example.com", Name: "A"}
+	if err := repo.Create(ctx, user); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	stale, _ := repo.GetByID(ctx, user.ID)
+
+	at := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	if err := repo.RecordLogin(ctx, user.ID, at); err != nil {
+		t.Fatalf("RecordLogin() error = %v", err)
+	}
+
+	// Recording a login is not a change of the user, an update of a copy read before it still applies
+	// and doesn't erase it
+	stale.Name = "Changed"
+	if err := repo.Update(ctx, stale); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	stored, _ := repo.GetByID(ctx, user.ID)
+	if stored.LastLoginAt == nil || !stored.LastLoginAt.Equal(at) || stored.Name != "Changed" {
+		t.Errorf("stored user = %+v, want the login time and the new name", stored)
+	}
+
+	if err := repo.RecordLogin(ctx, "missing", at); !errors.Is(err, domainerrors.ErrUserNotFound) {
+		t.Errorf("RecordLogin() of a missing user error = %v, want %v", err, domainerrors.ErrUserNotFound)
+	}
+}
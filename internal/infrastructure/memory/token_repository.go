@@ -0,0 +1,192 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// expiring is a value that is dropped after its expiration, like a Redis key with a TTL
+type expiring[T any] struct {
+	value     T
+	expiresAt time.Time
+}
+
+func (e expiring[T]) expired(now time.Time) bool {
+	return !now.Before(e.expiresAt)
+}
+
+// TokenRepository is the in-memory implementation of the token repository, the entries expire like the
+// Redis keys of the Redis implementation
+type TokenRepository struct {
+	mu            sync.Mutex
+	refreshTokens map[string]expiring[domain.RefreshTokenData]
+	blacklist     map[string]expiring[struct{}]
+	tokenVersions map[int]expiring[int]
+}
+
+// NewTokenRepository creates a new instance of TokenRepository
+func NewTokenRepository() *TokenRepository {
+	return &TokenRepository{
+		refreshTokens: make(map[string]expiring[domain.RefreshTokenData]),
+		blacklist:     make(map[string]expiring[struct{}]),
+		tokenVersions: make(map[int]expiring[int]),
+	}
+}
+
+// StoreRefreshToken stores a refresh token
+func (r *TokenRepository) StoreRefreshToken(ctx context.Context, token string, data *domain.RefreshTokenData, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.refreshTokens[token] = expiring[domain.RefreshTokenData]{value: *data, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+// GetRefreshToken retrieves the data of a refresh token
+func (r *TokenRepository) GetRefreshToken(ctx context.Context, token string) (*domain.RefreshTokenData, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.refreshToken(token)
+}
+
+// DeleteRefreshToken deletes a refresh token
+func (r *TokenRepository) DeleteRefreshToken(ctx context.Context, token string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.refreshTokens, token)
+	return nil
+}
+
+// BlacklistToken adds a token to the blacklist
+func (r *TokenRepository) BlacklistToken(ctx context.Context, token string, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.blacklist[token] = expiring[struct{}]{expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+// IsTokenBlacklisted verifies if a token is in the blacklist
+func (r *TokenRepository) IsTokenBlacklisted(ctx context.Context, token string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.blacklisted(token), nil
+}
+
+// GetActiveRefreshToken retrieves the data of a refresh token that is stored and not blacklisted
+func (r *TokenRepository) GetActiveRefreshToken(ctx context.Context, token string) (*domain.RefreshTokenData, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.blacklisted(token) {
+		return nil, domainerrors.ErrTokenRevoked
+	}
+	return r.refreshToken(token)
+}
+
+// RotateRefreshToken atomically replaces a refresh token with a new one
+func (r *TokenRepository) RotateRefreshToken(ctx context.Context, oldToken, newToken string, data *domain.RefreshTokenData, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.refreshTokens, oldToken)
+	r.refreshTokens[newToken] = expiring[domain.RefreshTokenData]{value: *data, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+// RevokeSession blacklists an access token for ttl and deletes a refresh token.
+// The blacklist is skipped when ttl is not positive and the deletion when refreshToken is empty.
+func (r *TokenRepository) RevokeSession(ctx context.Context, accessToken string, ttl time.Duration, refreshToken string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if ttl > 0 {
+		r.blacklist[accessToken] = expiring[struct{}]{expiresAt: time.Now().Add(ttl)}
+	}
+	if refreshToken != "" {
+		delete(r.refreshTokens, refreshToken)
+	}
+	return nil
+}
+
+// DeleteUserTokens deletes all refresh tokens of a user, matched by either identifier
+func (r *TokenRepository) DeleteUserTokens(ctx context.Context, userID string, idCitizen int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for token, entry := range r.refreshTokens {
+		if entry.value.IDCitizen == idCitizen || (userID != "" && entry.value.UserID == userID) {
+			delete(r.refreshTokens, token)
+		}
+	}
+	return nil
+}
+
+// SetTokenVersion sets the minimum token version of the access tokens of a user for ttl
+func (r *TokenRepository) SetTokenVersion(ctx context.Context, idCitizen int, version int, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tokenVersions[idCitizen] = expiring[int]{value: version, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+// GetTokenVersion returns the minimum token version of the access tokens of a user, 0 when none is set
+func (r *TokenRepository) GetTokenVersion(ctx context.Context, idCitizen int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.tokenVersions[idCitizen]
+	if !ok || entry.expired(time.Now()) {
+		delete(r.tokenVersions, idCitizen)
+		return 0, nil
+	}
+	return entry.value, nil
+}
+
+// CountActiveSessions returns the number of refresh tokens of a user that are still stored
+func (r *TokenRepository) CountActiveSessions(ctx context.Context, idCitizen int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	count := 0
+	for token, entry := range r.refreshTokens {
+		if entry.expired(now) {
+			delete(r.refreshTokens, token)
+			continue
+		}
+		if entry.value.IDCitizen == idCitizen {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// refreshToken returns a copy of the data of a refresh token that hasn't expired. The caller must hold the lock.
+func (r *TokenRepository) refreshToken(token string) (*domain.RefreshTokenData, error) {
+	entry, ok := r.refreshTokens[token]
+	if !ok || entry.expired(time.Now()) {
+		delete(r.refreshTokens, token)
+		return nil, domainerrors.ErrInvalidToken
+	}
+	data := entry.value
+	return &data, nil
+}
+
+// blacklisted reports whether a token is in the blacklist. The caller must hold the lock.
+func (r *TokenRepository) blacklisted(token string) bool {
+	entry, ok := r.blacklist[token]
+	if !ok || entry.expired(time.Now()) {
+		delete(r.blacklist, token)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,204 @@
+// Package memory implements repositories that keep their data in the process memory, for the fakes used by
+// contract tests and for running the service without its data stores.
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sort"
+	"sync"
+	"time"
+
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// UserRepository is the in-memory implementation of the user repository.
+// It enforces the same unique emails and citizen IDs as the database, and returns copies of the users
+// so callers can't change the stored ones without Update.
+type UserRepository struct {
+	mu      sync.RWMutex
+	users   map[string]*domain.User
+	deleted map[string]bool
+	nextID  int
+}
+
+// NewUserRepository creates a new instance of UserRepository
+func NewUserRepository() *UserRepository {
+	return &UserRepository{
+		users:   make(map[string]*domain.User),
+		deleted: make(map[string]bool),
+	}
+}
+
+// Create creates a new user. IDs are sequential, so they are the same on every run.
+func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.conflicts(user, "") {
+		return domainerrors.ErrUserAlreadyExists
+	}
+
+	r.nextID++
+	user.ID = fmt.Sprintf("00000000-0000-4000-8000-%012d", r.nextID)
+	user.Version = 1
+	user.CreatedAt = time.Now()
+	user.UpdatedAt = user.CreatedAt
+	r.users[user.ID] = copyUser(user)
+	return nil
+}
+
+// GetByID retrieves a user by their ID
+func (r *UserRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	return r.find(func(user *domain.User) bool { return user.ID == id })
+}
+
+// GetByEmail retrieves a user by their email, case-insensitively
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	email = domain.CanonicalEmail(email)
+	return r.find(func(user *domain.User) bool { return domain.CanonicalEmail(user.Email) == email })
+}
+
+// GetByIDCitizen retrieves a user by their citizen ID
+func (r *UserRepository) GetByIDCitizen(ctx context.Context, idCitizen int) (*domain.User, error) {
+	return r.find(func(user *domain.User) bool { return user.IDCitizen == idCitizen })
+}
+
+// GetByPendingEmailToken retrieves the user with a pending email change confirmed by the token hash
+func (r *UserRepository) GetByPendingEmailToken(ctx context.Context, tokenHash string) (*domain.User, error) {
+	if tokenHash == "" {
+		return nil, domainerrors.ErrUserNotFound
+	}
+	return r.find(func(user *domain.User) bool { return user.PendingEmailTokenHash == tokenHash })
+}
+
+// Update updates an existing user, which must not have been modified since it was read. The token version
+// never decreases.
+func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.users[user.ID]
+	if !ok || r.deleted[user.ID] {
+		return domainerrors.ErrUserNotFound
+	}
+	if stored.Version != user.Version {
+		return domainerrors.ErrVersionConflict
+	}
+	if r.conflicts(user, user.ID) {
+		return domainerrors.ErrUserAlreadyExists
+	}
+
+	user.UpdatedAt = time.Now()
+	user.Version++
+	updated := copyUser(user)
+	updated.CreatedAt = stored.CreatedAt
+	updated.Type = stored.Type
+	updated.TokenVersion = max(stored.TokenVersion, user.TokenVersion)
+	updated.LastLoginAt = stored.LastLoginAt
+	r.users[user.ID] = updated
+	return nil
+}
+
+// RecordLogin records the time of a successful login of a user, without changing its version
+func (r *UserRepository) RecordLogin(ctx context.Context, id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok || r.deleted[id] {
+		return domainerrors.ErrUserNotFound
+	}
+	user.LastLoginAt = &at
+	return nil
+}
+
+// Delete deletes a user (soft delete)
+func (r *UserRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[id]; !ok || r.deleted[id] {
+		return domainerrors.ErrUserNotFound
+	}
+	r.deleted[id] = true
+	return nil
+}
+
+// Exists verifies if a user exists by email, case-insensitively
+func (r *UserRepository) Exists(ctx context.Context, email string) (bool, error) {
+	_, err := r.GetByEmail(ctx, email)
+	if errors.Is(err, domainerrors.ErrUserNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// ListByStatus retrieves a page of the users in a status, oldest first
+func (r *UserRepository) ListByStatus(ctx context.Context, status domain.UserStatus, limit, offset int) ([]*domain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var users []*domain.User
+	for id, user := range r.users {
+		if !r.deleted[id] && user.Status == status {
+			users = append(users, copyUser(user))
+		}
+	}
+	sort.Slice(users, func(i, j int) bool {
+		if !users[i].CreatedAt.Equal(users[j].CreatedAt) {
+			return users[i].CreatedAt.Before(users[j].CreatedAt)
+		}
+		return users[i].ID < users[j].ID
+	})
+
+	if offset >= len(users) {
+		return nil, nil
+	}
+	users = users[offset:]
+	if limit < len(users) {
+		users = users[:limit]
+	}
+	return users, nil
+}
+
+// find returns a copy of the first user that isn't deleted and matches
+func (r *UserRepository) find(match func(user *domain.User) bool) (*domain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for id, user := range r.users {
+		if !r.deleted[id] && match(user) {
+			return copyUser(user), nil
+		}
+	}
+	return nil, domainerrors.ErrUserNotFound
+}
+
+// conflicts reports whether another user has the email, in any case, or the citizen ID of user. Like the
+// unique constraints of the database, deleted users are included. The caller must hold the lock.
+func (r *UserRepository) conflicts(user *domain.User, exceptID string) bool {
+	for id, other := range r.users {
+		if id == exceptID {
+			continue
+		}
+		if domain.CanonicalEmail(other.Email) == domain.CanonicalEmail(user.Email) || other.IDCitizen == user.IDCitizen {
+			return true
+		}
+	}
+	return false
+}
+
+// copyUser copies a user along with the values it points to
+func copyUser(user *domain.User) *domain.User {
+	c := *user
+	c.Metadata = maps.Clone(user.Metadata)
+	if user.PendingEmailExpiresAt != nil {
+		expiresAt := *user.PendingEmailExpiresAt
+		c.PendingEmailExpiresAt = &expiresAt
+	}
+	return &c
+}
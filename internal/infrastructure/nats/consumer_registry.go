@@ -0,0 +1,385 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.uber.org/zap"
+
+	ports "github.com/kristianrpo/auth-microservice/internal/application/ports"
+	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
+	"github.com/kristianrpo/auth-microservice/internal/retry"
+)
+
+const (
+	// retryCountHeader counts how many times a dead-lettered message was retried
+	retryCountHeader = "X-Retry-Count"
+	// lastErrorHeader holds the error of the last failed processing
+	lastErrorHeader = "X-Last-Error"
+	// originalQueueHeader holds the queue a dead-lettered message comes from
+	originalQueueHeader = "X-Original-Queue"
+
+	// resubscribeDelay is the wait before consuming again after the subscription of a queue failed
+	resubscribeDelay = 2 * time.Second
+)
+
+// ConsumerRegistry implements the MessageConsumer interface for NATS JetStream.
+// Every registered queue is consumed through a durable pull consumer, shared by the replicas of the service,
+// by a pool of workers, and consumption is resumed when the subscription fails. A message whose handler
+// fails is redelivered by JetStream after the retry delay until the retries are exhausted, then it is
+// published to the subject of the dead letter queue. Redeliveries after the ack wait, e.g. when a replica
+// stops while processing the message, count as retries.
+type ConsumerRegistry struct {
+	client        *NATSClient
+	logger        *zap.Logger
+	mu            sync.Mutex
+	subscriptions []ports.QueueSubscription
+	cancel        context.CancelFunc
+	wg            sync.WaitGroup
+
+	subscribedQueues map[string]bool
+	subscribed       chan struct{}
+
+	// Drain statistics of Stop
+	processing atomic.Int64
+	stopping   atomic.Bool
+	drained    atomic.Int64
+	cancelled  atomic.Int64
+}
+
+// NewConsumerRegistry creates a new NATS JetStream consumer registry
+func NewConsumerRegistry(client *NATSClient, logger *zap.Logger) *ConsumerRegistry {
+	return &ConsumerRegistry{
+		client:           client,
+		logger:           logger,
+		subscribedQueues: make(map[string]bool),
+		subscribed:       make(chan struct{}),
+	}
+}
+
+// Register adds a queue subscription, it must be called before Start
+func (r *ConsumerRegistry) Register(subscription ports.QueueSubscription) error {
+	if subscription.Queue == "" {
+		return errors.New("queue name is required")
+	}
+	if subscription.Handler == nil {
+		return fmt.Errorf("handler is required for queue %s", subscription.Queue)
+	}
+	if subscription.Concurrency < 1 {
+		return fmt.Errorf("concurrency of queue %s must be at least 1", subscription.Queue)
+	}
+	if subscription.MaxRetries < 0 {
+		return fmt.Errorf("max retries of queue %s cannot be negative", subscription.Queue)
+	}
+	if subscription.DeadLetterQueue == subscription.Queue {
+		return fmt.Errorf("dead letter queue of queue %s must be a different queue", subscription.Queue)
+	}
+	if subscription.Prefetch < 1 {
+		subscription.Prefetch = subscription.Concurrency
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cancel != nil {
+		return errors.New("cannot register a queue after the consumers started")
+	}
+	for _, existing := range r.subscriptions {
+		if existing.Queue == subscription.Queue {
+			return fmt.Errorf("queue %s is already registered", subscription.Queue)
+		}
+	}
+	r.subscriptions = append(r.subscriptions, subscription)
+	return nil
+}
+
+// Start starts consuming every registered queue in the background. It does not wait for NATS: queues are
+// subscribed as soon as the server is available.
+func (r *ConsumerRegistry) Start(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cancel != nil {
+		return errors.New("consumers already started")
+	}
+
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	r.cancel = cancel
+	if len(r.subscriptions) == 0 {
+		close(r.subscribed)
+	}
+	for _, subscription := range r.subscriptions {
+		r.wg.Add(1)
+		go func(subscription ports.QueueSubscription) {
+			defer r.wg.Done()
+			r.run(runCtx, subscription)
+		}(subscription)
+	}
+	return nil
+}
+
+// Subscribed returns a channel closed once every registered queue has been subscribed at least once
+func (r *ConsumerRegistry) Subscribed() <-chan struct{} {
+	return r.subscribed
+}
+
+// markSubscribed records the first subscription of a queue
+func (r *ConsumerRegistry) markSubscribed(queue string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.subscribedQueues[queue] {
+		return
+	}
+	r.subscribedQueues[queue] = true
+	if len(r.subscribedQueues) == len(r.subscriptions) {
+		close(r.subscribed)
+	}
+}
+
+// Stop cancels the consumers and waits for the messages being processed, until the context is done
+func (r *ConsumerRegistry) Stop(ctx context.Context) error {
+	r.mu.Lock()
+	cancel := r.cancel
+	r.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	r.stopping.Store(true)
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		r.logger.Info("NATS consumers stopped")
+		return nil
+	case <-ctx.Done():
+		// Unacknowledged messages are redelivered by JetStream once their ack wait expires
+		r.cancelled.Store(r.processing.Load())
+		return fmt.Errorf("timeout waiting for NATS consumers to stop: %w", ctx.Err())
+	}
+}
+
+// DrainStats reports the messages processed and abandoned by Stop, it implements ports.DrainReporter
+func (r *ConsumerRegistry) DrainStats() ports.DrainStats {
+	return ports.DrainStats{Drained: int(r.drained.Load()), Cancelled: int(r.cancelled.Load())}
+}
+
+// run consumes a queue until the context is cancelled, subscribing again whenever the subscription fails
+func (r *ConsumerRegistry) run(ctx context.Context, subscription ports.QueueSubscription) {
+	for {
+		err := r.consume(ctx, subscription)
+		if ctx.Err() != nil {
+			return
+		}
+		r.logger.Warn("NATS consumer stopped, resubscribing",
+			zap.String("queue", subscription.Queue),
+			zap.Duration("delay", resubscribeDelay),
+			zap.Error(err),
+		)
+
+		if retry.Sleep(ctx, resubscribeDelay) != nil {
+			return
+		}
+	}
+}
+
+// consume creates or updates the durable consumer of the queue and processes its messages with the
+// configured number of workers. It returns when the subscription fails, or once the buffered messages
+// are processed when the context is cancelled.
+func (r *ConsumerRegistry) consume(ctx context.Context, subscription ports.QueueSubscription) error {
+	if err := r.client.EnsureStream(ctx); err != nil {
+		return err
+	}
+
+	cfg := r.client.GetConfig()
+	js := r.client.JetStream()
+	subject := cfg.Subject(subscription.Queue)
+	stream, err := js.StreamNameBySubject(ctx, subject)
+	if err != nil {
+		return fmt.Errorf("failed to find the stream of subject %s: %w", subject, err)
+	}
+
+	// Dead-lettering is done by the registry, JetStream must keep redelivering until then
+	consumer, err := js.CreateOrUpdateConsumer(ctx, stream, jetstream.ConsumerConfig{
+		Durable:       cfg.DurableName(subscription.Queue),
+		FilterSubject: subject,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		AckWait:       cfg.AckWait,
+		MaxDeliver:    -1,
+		MaxAckPending: subscription.Prefetch,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create consumer for queue %s: %w", subscription.Queue, err)
+	}
+
+	messages, err := consumer.Messages(jetstream.PullMaxMessages(subscription.Prefetch))
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to queue %s: %w", subscription.Queue, err)
+	}
+	r.markSubscribed(subscription.Queue)
+
+	r.logger.Info("NATS consumer subscribed",
+		zap.String("queue", subscription.Queue),
+		zap.String("stream", stream),
+		zap.String("subject", subject),
+		zap.Int("concurrency", subscription.Concurrency),
+		zap.Int("prefetch", subscription.Prefetch),
+	)
+
+	failed := make(chan error, 1)
+	var workers sync.WaitGroup
+	for i := 0; i < subscription.Concurrency; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for {
+				msg, err := messages.Next()
+				if err != nil {
+					if !errors.Is(err, jetstream.ErrMsgIteratorClosed) {
+						select {
+						case failed <- err:
+						default:
+						}
+					}
+					return
+				}
+
+				r.processing.Add(1)
+				r.process(ctx, subscription, msg)
+				r.processing.Add(-1)
+				if r.stopping.Load() {
+					r.drained.Add(1)
+				}
+			}
+		}()
+	}
+
+	select {
+	case <-ctx.Done():
+		// Draining stops pulling and closes the iterator once the buffered messages are handed to the workers
+		messages.Drain()
+		workers.Wait()
+		return nil
+	case err := <-failed:
+		messages.Stop()
+		workers.Wait()
+		return err
+	}
+}
+
+// process handles a single message: it is acknowledged on success, and retried, dead-lettered or dropped
+// on failure
+func (r *ConsumerRegistry) process(ctx context.Context, subscription ports.QueueSubscription, msg jetstream.Msg) {
+	queue := subscription.Queue
+
+	// In-flight messages are completed during shutdown
+	handlerErr := subscription.Handler(context.WithoutCancel(ctx), msg.Data())
+	if handlerErr == nil {
+		r.ack(queue, msg)
+		metrics.IncConsumedMessages(queue, "acked")
+		return
+	}
+
+	retries := retryCount(msg)
+	if retries < subscription.MaxRetries {
+		r.logger.Warn("error processing message, retrying",
+			zap.String("queue", queue),
+			zap.Int("retry", retries+1),
+			zap.Int("max_retries", subscription.MaxRetries),
+			zap.Duration("delay", subscription.RetryDelay),
+			zap.Error(handlerErr),
+		)
+		r.nak(queue, msg, subscription.RetryDelay)
+		metrics.IncConsumedMessages(queue, "retried")
+		return
+	}
+
+	if subscription.DeadLetterQueue == "" {
+		r.logger.Error("error processing message, retries exhausted, dropping message", zap.String("queue", queue), zap.Error(handlerErr))
+		if err := msg.Term(); err != nil {
+			r.logger.Warn("failed to terminate message", zap.String("queue", queue), zap.Error(err))
+		}
+		metrics.IncConsumedMessages(queue, "dropped")
+		return
+	}
+
+	if err := r.deadLetter(subscription, msg, retries, handlerErr); err != nil {
+		r.logger.Error("failed to move message to the dead letter queue",
+			zap.String("queue", queue),
+			zap.String("dead_letter_queue", subscription.DeadLetterQueue),
+			zap.Error(err),
+		)
+		r.nak(queue, msg, subscription.RetryDelay)
+		return
+	}
+	r.logger.Error("error processing message, retries exhausted, moved to the dead letter queue",
+		zap.String("queue", queue),
+		zap.String("dead_letter_queue", subscription.DeadLetterQueue),
+		zap.Error(handlerErr),
+	)
+	r.ack(queue, msg)
+	metrics.IncConsumedMessages(queue, "dead_lettered")
+}
+
+// deadLetter publishes a copy of the message to the subject of the dead letter queue with the retry headers
+// and waits for the stream to store it
+func (r *ConsumerRegistry) deadLetter(subscription ports.QueueSubscription, msg jetstream.Msg, retries int, handlerErr error) error {
+	header := nats.Header{}
+	for key, values := range msg.Headers() {
+		header[key] = values
+	}
+	// The dead-lettered copy must not be discarded as a duplicate of the original message
+	header.Del(jetstream.MsgIDHeader)
+	header.Set(retryCountHeader, strconv.Itoa(retries))
+	header.Set(lastErrorHeader, handlerErr.Error())
+	if header.Get(originalQueueHeader) == "" {
+		header.Set(originalQueueHeader, subscription.Queue)
+	}
+
+	cfg := r.client.GetConfig()
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.PublishTimeout)
+	defer cancel()
+
+	_, err := r.client.JetStream().PublishMsg(ctx, &nats.Msg{
+		Subject: cfg.Subject(subscription.DeadLetterQueue),
+		Header:  header,
+		Data:    msg.Data(),
+	})
+	return err
+}
+
+// ack acknowledges a message
+func (r *ConsumerRegistry) ack(queue string, msg jetstream.Msg) {
+	if err := msg.Ack(); err != nil {
+		r.logger.Warn("failed to acknowledge message", zap.String("queue", queue), zap.Error(err))
+	}
+}
+
+// nak asks JetStream to redeliver a message after the delay
+func (r *ConsumerRegistry) nak(queue string, msg jetstream.Msg, delay time.Duration) {
+	if err := msg.NakWithDelay(delay); err != nil {
+		r.logger.Warn("failed to negatively acknowledge message", zap.String("queue", queue), zap.Error(err))
+	}
+}
+
+// retryCount returns how many times a message was redelivered, 0 on its first delivery
+func retryCount(msg jetstream.Msg) int {
+	metadata, err := msg.Metadata()
+	if err != nil || metadata.NumDelivered == 0 {
+		return 0
+	}
+	return int(metadata.NumDelivered - 1)
+}
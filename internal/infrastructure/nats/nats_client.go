@@ -0,0 +1,129 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/config"
+)
+
+// NATSClient holds the connection to NATS and its JetStream context. The connection is retried in the
+// background while NATS is down, so the client is created without waiting for it.
+type NATSClient struct {
+	conn   *nats.Conn
+	js     jetstream.JetStream
+	cfg    config.NATSConfig
+	logger *zap.Logger
+
+	mu            sync.Mutex
+	streamEnsured bool
+}
+
+// NewNATSClient connects to NATS, reconnecting in the background whenever the connection is lost
+func NewNATSClient(cfg config.NATSConfig, logger *zap.Logger) (*NATSClient, error) {
+	conn, err := nats.Connect(cfg.URL,
+		nats.Name("auth-microservice"),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				logger.Warn("NATS connection lost, reconnecting", zap.Error(err))
+			}
+		}),
+		nats.ReconnectHandler(func(conn *nats.Conn) {
+			logger.Info("NATS connection restored", zap.String("server", conn.ConnectedUrlRedacted()))
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create JetStream context: %w", err)
+	}
+
+	return &NATSClient{
+		conn:   conn,
+		js:     js,
+		cfg:    cfg,
+		logger: logger,
+	}, nil
+}
+
+// JetStream returns the JetStream context of the connection
+func (c *NATSClient) JetStream() jetstream.JetStream {
+	return c.js
+}
+
+// GetConfig returns the NATS configuration
+func (c *NATSClient) GetConfig() config.NATSConfig {
+	return c.cfg
+}
+
+// IsConnected reports whether the connection to NATS is established
+func (c *NATSClient) IsConnected() bool {
+	return c.conn.IsConnected()
+}
+
+// EnsureStream creates the configured stream unless it exists. It is a no-op once the stream was ensured,
+// or when no stream is configured.
+func (c *NATSClient) EnsureStream(ctx context.Context) error {
+	if c.cfg.Stream == "" {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.streamEnsured {
+		return nil
+	}
+
+	_, err := c.js.CreateStream(ctx, jetstream.StreamConfig{
+		Name:      c.cfg.Stream,
+		Subjects:  []string{c.cfg.SubjectPrefix + ">"},
+		Storage:   jetstream.FileStorage,
+		Retention: jetstream.LimitsPolicy,
+		MaxAge:    c.cfg.StreamMaxAge,
+	})
+	if err != nil && !errors.Is(err, jetstream.ErrStreamNameAlreadyInUse) {
+		return fmt.Errorf("failed to create stream %s: %w", c.cfg.Stream, err)
+	}
+
+	c.streamEnsured = true
+	return nil
+}
+
+// QueueDepth returns the number of messages of a queue its durable consumer has not received yet
+func (c *NATSClient) QueueDepth(ctx context.Context, queueName string) (int64, error) {
+	stream, err := c.js.StreamNameBySubject(ctx, c.cfg.Subject(queueName))
+	if err != nil {
+		return 0, fmt.Errorf("failed to find the stream of queue %s: %w", queueName, err)
+	}
+
+	consumer, err := c.js.Consumer(ctx, stream, c.cfg.DurableName(queueName))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get the consumer of queue %s: %w", queueName, err)
+	}
+	info, err := consumer.Info(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get the consumer info of queue %s: %w", queueName, err)
+	}
+	return int64(info.NumPending), nil
+}
+
+// Close drains the subscriptions and closes the connection
+func (c *NATSClient) Close() error {
+	if err := c.conn.Drain(); err != nil && !errors.Is(err, nats.ErrConnectionClosed) {
+		c.conn.Close()
+		return fmt.Errorf("failed to drain NATS connection: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
+	"github.com/kristianrpo/auth-microservice/internal/retry"
+)
+
+// NATSPublisher implements the MessagePublisher interface for NATS JetStream.
+// The message is published unchanged, the same JSON event published to RabbitMQ, to the subject of the
+// queue. A publish only succeeds once the stream acknowledges it has stored the message, and failed or
+// timed out publishes are retried with backoff. The messageId of the event is set as the message ID, so
+// the stream discards the copies of a retried message within its duplicates window; consumers must still
+// tolerate duplicates published outside the window.
+type NATSPublisher struct {
+	client *NATSClient
+	logger *zap.Logger
+}
+
+// NewNATSPublisher creates a new NATS JetStream message publisher
+func NewNATSPublisher(client *NATSClient, logger *zap.Logger) *NATSPublisher {
+	return &NATSPublisher{
+		client: client,
+		logger: logger,
+	}
+}
+
+// Publish sends a message to the subject of the queue and waits for the stream to store it, retrying
+// according to the publish settings of the configuration
+func (p *NATSPublisher) Publish(ctx context.Context, queueName string, message []byte) error {
+	cfg := p.client.GetConfig()
+	policy := retry.Policy{
+		MaxAttempts:    max(cfg.PublishMaxAttempts, 1),
+		InitialBackoff: cfg.PublishInitialBackoff,
+		MaxBackoff:     cfg.PublishMaxBackoff,
+		Jitter:         retry.EqualJitter,
+	}
+
+	msg := &nats.Msg{
+		Subject: cfg.Subject(queueName),
+		Data:    message,
+		Header:  nats.Header{"Content-Type": []string{"application/json"}},
+	}
+	var opts []jetstream.PublishOpt
+	if id := MessageID(message); id != "" {
+		opts = append(opts, jetstream.WithMsgID(id))
+	}
+
+	err := policy.DoNotify(ctx, func(ctx context.Context) error {
+		err := p.publish(ctx, msg, opts)
+		if err != nil {
+			metrics.IncMessagePublishAttemptFailure(queueName, publishFailureReason(err))
+		}
+		return err
+	}, func(attempt int, err error, backoff time.Duration) {
+		p.logger.Warn("publish to NATS failed, retrying",
+			zap.String("subject", msg.Subject),
+			zap.Int("attempt", attempt),
+			zap.Int("max_attempts", policy.MaxAttempts),
+			zap.Duration("backoff", backoff),
+			zap.Error(err),
+		)
+	})
+	if err != nil {
+		metrics.IncMessagePublish(queueName, "failed")
+		return fmt.Errorf("failed to publish message to subject %s: %w", msg.Subject, err)
+	}
+
+	metrics.IncMessagePublish(queueName, "confirmed")
+	p.logger.Debug("message published to NATS", zap.String("subject", msg.Subject))
+	return nil
+}
+
+// publish makes a single publish attempt and waits for the acknowledgement of the stream
+func (p *NATSPublisher) publish(ctx context.Context, msg *nats.Msg, opts []jetstream.PublishOpt) error {
+	if err := p.client.EnsureStream(ctx); err != nil {
+		return err
+	}
+
+	publishCtx, cancel := context.WithTimeout(ctx, p.client.GetConfig().PublishTimeout)
+	defer cancel()
+
+	_, err := p.client.JetStream().PublishMsg(publishCtx, msg, opts...)
+	return err
+}
+
+// Close is a no-op, the connection is managed by NATSClient
+func (p *NATSPublisher) Close() error {
+	return nil
+}
+
+// MessageID returns the messageId of an event, empty when it has none
+func MessageID(message []byte) string {
+	var event struct {
+		MessageID string `json:"messageId"`
+	}
+	if err := json.Unmarshal(message, &event); err != nil {
+		return ""
+	}
+	return event.MessageID
+}
+
+// publishFailureReason classifies a failed publish attempt for metrics
+func publishFailureReason(err error) string {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, nats.ErrTimeout):
+		return "timeout"
+	default:
+		return "error"
+	}
+}
@@ -0,0 +1,50 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/application/ports"
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/nats"
+)
+
+func TestConsumerRegistry_Register(t *testing.T) {
+	handler := func(ctx context.Context, message []byte) error { return nil }
+
+	tests := []struct {
+		name         string
+		subscription ports.QueueSubscription
+		wantErr      bool
+	}{
+		{name: "valid", subscription: ports.QueueSubscription{Queue: "auth_user_updated", Handler: handler, Concurrency: 2, MaxRetries: 3, DeadLetterQueue: "auth_user_updated.dlq"}},
+		{name: "missing queue", subscription: ports.QueueSubscription{Handler: handler, Concurrency: 1}, wantErr: true},
+		{name: "missing handler", subscription: ports.QueueSubscription{Queue: "auth_user_updated", Concurrency: 1}, wantErr: true},
+		{name: "no workers", subscription: ports.QueueSubscription{Queue: "auth_user_updated", Handler: handler}, wantErr: true},
+		{name: "negative retries", subscription: ports.QueueSubscription{Queue: "auth_user_updated", Handler: handler, Concurrency: 1, MaxRetries: -1}, wantErr: true},
+		{name: "dead letter queue is the queue", subscription: ports.QueueSubscription{Queue: "auth_user_updated", Handler: handler, Concurrency: 1, DeadLetterQueue: "auth_user_updated"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := nats.NewConsumerRegistry(nil, zap.NewNop())
+			if err := registry.Register(tt.subscription); (err != nil) != tt.wantErr {
+				t.Errorf("Register() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConsumerRegistry_RegisterDuplicateQueue(t *testing.T) {
+	handler := func(ctx context.Context, message []byte) error { return nil }
+	registry := nats.NewConsumerRegistry(nil, zap.NewNop())
+
+	subscription := ports.QueueSubscription{Queue: "auth_user_updated", Handler: handler, Concurrency: 1}
+	if err := registry.Register(subscription); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := registry.Register(subscription); err == nil {
+		t.Error("expected an error registering the queue twice")
+	}
+}
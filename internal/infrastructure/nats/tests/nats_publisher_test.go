@@ -0,0 +1,27 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/nats"
+)
+
+func TestMessageID(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{name: "event with message ID", message: `{"messageId":"m-1","userId":"user-1"}`, want: "m-1"},
+		{name: "event without message ID", message: `{"idCitizen":42}`},
+		{name: "invalid JSON", message: `not json`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nats.MessageID([]byte(tt.message)); got != tt.want {
+				t.Errorf("MessageID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
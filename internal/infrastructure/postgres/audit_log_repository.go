@@ -0,0 +1,288 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// AuditLogRepository is the PostgreSQL implementation of the audit log repository
+type AuditLogRepository struct {
+	db      *DB
+	retrier *Retrier
+	logger  *zap.Logger
+}
+
+// NewAuditLogRepository creates a new instance of AuditLogRepository
+func NewAuditLogRepository(db *DB, retrier *Retrier, logger *zap.Logger) *AuditLogRepository {
+	return &AuditLogRepository{
+		db:      db,
+		retrier: retrier,
+		logger:  logger,
+	}
+}
+
+// Record appends a record to the audit log.
+// Records carry their own ID, so a retried insert never duplicates them.
+func (r *AuditLogRepository) Record(ctx context.Context, record *domain.AuditRecord) error {
+	details, err := json.Marshal(record.Details)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit details: %w", err)
+	}
+
+	query := `
+		INSERT INTO {audit_log} (id, action, actor, target_id, details, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (id) DO NOTHING
+	`
+
+	err = r.retrier.Do(ctx, "audit_log.record", func(ctx context.Context) error {
+		_, err := r.db.ExecContext(ctx, query,
+			record.ID,
+			record.Action.String(),
+			record.Actor,
+			record.TargetID,
+			details,
+			record.CreatedAt,
+		)
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to record audit log", zap.Error(err), zap.String("action", record.Action.String()))
+		return fmt.Errorf("failed to record audit log: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimUnexported returns the records due for export and pushes their next attempt past the lease.
+// Rows locked by a concurrent claim are skipped.
+func (r *AuditLogRepository) ClaimUnexported(ctx context.Context, limit int, lease time.Duration) ([]*domain.AuditRecord, error) {
+	query := `
+		UPDATE {audit_log}
+		SET export_next_attempt_at = $2
+		WHERE id IN (
+			SELECT id FROM {audit_log}
+			WHERE exported_at IS NULL AND export_next_attempt_at <= $1
+			ORDER BY created_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, action, actor, target_id, details, created_at
+	`
+
+	var records []*domain.AuditRecord
+	// A retried claim at worst hides records until the lease expires
+	err := r.retrier.Do(ctx, "audit_log.claim_unexported", func(ctx context.Context) error {
+		now := time.Now()
+		rows, err := r.db.QueryContext(ctx, query, now, now.Add(lease), limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		records = nil
+		for rows.Next() {
+			record := &domain.AuditRecord{}
+			var details []byte
+			if err := rows.Scan(
+				&record.ID,
+				&record.Action,
+				&record.Actor,
+				&record.TargetID,
+				&details,
+				&record.CreatedAt,
+			); err != nil {
+				return err
+			}
+			if err := json.Unmarshal(details, &record.Details); err != nil {
+				return fmt.Errorf("failed to unmarshal audit details: %w", err)
+			}
+			records = append(records, record)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		r.logger.Error("failed to claim unexported audit records", zap.Error(err))
+		return nil, fmt.Errorf("failed to claim unexported audit records: %w", err)
+	}
+
+	// RETURNING does not preserve the order of the subquery
+	slices.SortFunc(records, func(a, b *domain.AuditRecord) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return records, nil
+}
+
+// MarkExported records the delivery of the records
+func (r *AuditLogRepository) MarkExported(ctx context.Context, ids []string) error {
+	query := `UPDATE {audit_log} SET exported_at = $2 WHERE id = ANY($1)`
+
+	err := r.retrier.Do(ctx, "audit_log.mark_exported", func(ctx context.Context) error {
+		_, err := r.db.ExecContext(ctx, query, pq.Array(ids), time.Now())
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to mark audit records as exported", zap.Error(err), zap.Int("records", len(ids)))
+		return fmt.Errorf("failed to mark audit records as exported: %w", err)
+	}
+
+	return nil
+}
+
+// MarkExportFailed schedules the next export attempt of the records
+func (r *AuditLogRepository) MarkExportFailed(ctx context.Context, ids []string, nextAttemptAt time.Time) error {
+	query := `UPDATE {audit_log} SET export_next_attempt_at = $2 WHERE id = ANY($1) AND exported_at IS NULL`
+
+	err := r.retrier.Do(ctx, "audit_log.mark_export_failed", func(ctx context.Context) error {
+		_, err := r.db.ExecContext(ctx, query, pq.Array(ids), nextAttemptAt)
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to reschedule audit records export", zap.Error(err), zap.Int("records", len(ids)))
+		return fmt.Errorf("failed to reschedule audit records export: %w", err)
+	}
+
+	return nil
+}
+
+// CountUnexported returns the number of records not exported yet
+func (r *AuditLogRepository) CountUnexported(ctx context.Context) (int, error) {
+	query := `SELECT COUNT(*) FROM {audit_log} WHERE exported_at IS NULL`
+
+	var count int
+	err := r.retrier.Do(ctx, "audit_log.count_unexported", func(ctx context.Context) error {
+		return r.db.QueryRowContext(ctx, query).Scan(&count)
+	})
+	if err != nil {
+		r.logger.Error("failed to count unexported audit records", zap.Error(err))
+		return 0, fmt.Errorf("failed to count unexported audit records: %w", err)
+	}
+
+	return count, nil
+}
+
+// CountByTarget returns the number of audit records about the target
+func (r *AuditLogRepository) CountByTarget(ctx context.Context, targetID string) (int, error) {
+	query := `SELECT COUNT(*) FROM {audit_log} WHERE target_id = $1`
+
+	var count int
+	err := r.retrier.Do(ctx, "audit_log.count_by_target", func(ctx context.Context) error {
+		return r.db.QueryRowContext(ctx, query, targetID).Scan(&count)
+	})
+	if err != nil {
+		r.logger.Error("failed to count audit records", zap.Error(err), zap.String("target_id", targetID))
+		return 0, fmt.Errorf("failed to count audit records: %w", err)
+	}
+
+	return count, nil
+}
+
+// ReassignTarget moves the audit records about a target to another one and returns how many were moved.
+// A retried update finds the records already moved, so it is safe to retry.
+func (r *AuditLogRepository) ReassignTarget(ctx context.Context, fromTargetID, toTargetID string) (int, error) {
+	query := `UPDATE {audit_log} SET target_id = $2 WHERE target_id = $1`
+
+	var moved int64
+	err := r.retrier.Do(ctx, "audit_log.reassign_target", func(ctx context.Context) error {
+		result, err := r.db.ExecContext(ctx, query, fromTargetID, toTargetID)
+		if err != nil {
+			return err
+		}
+		moved, err = result.RowsAffected()
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to reassign audit records", zap.Error(err),
+			zap.String("from_target_id", fromTargetID), zap.String("to_target_id", toTargetID))
+		return 0, fmt.Errorf("failed to reassign audit records: %w", err)
+	}
+
+	return int(moved), nil
+}
+
+// StreamRecords calls fn for every audit record matching the filter, oldest first.
+// Only opening the query is retried, a failure mid-stream is returned to the caller.
+func (r *AuditLogRepository) StreamRecords(ctx context.Context, filter domain.AuditLogFilter, fn func(*domain.AuditRecord) error) error {
+	var conditions []string
+	var args []interface{}
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.Action != "" {
+		addCondition("action = $%d", filter.Action.String())
+	}
+	if filter.Actor != "" {
+		addCondition("actor = $%d", filter.Actor)
+	}
+	if filter.TargetID != "" {
+		addCondition("target_id = $%d", filter.TargetID)
+	}
+	if !filter.After.IsZero() {
+		addCondition("created_at >= $%d", filter.After)
+	}
+	if !filter.Before.IsZero() {
+		addCondition("created_at < $%d", filter.Before)
+	}
+
+	query := `SELECT id, action, actor, target_id, details, created_at FROM {audit_log}`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += ` ORDER BY created_at, id`
+
+	var rows *sql.Rows
+	err := r.retrier.DoStream(ctx, "audit_log.stream", func(ctx context.Context) (err error) {
+		rows, err = r.db.QueryContext(ctx, query, args...)
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to query audit records", zap.Error(err))
+		return fmt.Errorf("failed to query audit records: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			r.logger.Error("failed to close rows", zap.Error(closeErr))
+		}
+	}()
+
+	for rows.Next() {
+		record := &domain.AuditRecord{}
+		var details []byte
+		if err := rows.Scan(
+			&record.ID,
+			&record.Action,
+			&record.Actor,
+			&record.TargetID,
+			&details,
+			&record.CreatedAt,
+		); err != nil {
+			r.logger.Error("failed to scan audit record", zap.Error(err))
+			return fmt.Errorf("failed to scan audit record: %w", err)
+		}
+		if err := json.Unmarshal(details, &record.Details); err != nil {
+			return fmt.Errorf("failed to unmarshal audit details: %w", err)
+		}
+
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error("error iterating audit records", zap.Error(err))
+		return fmt.Errorf("error iterating audit records: %w", err)
+	}
+
+	return nil
+}
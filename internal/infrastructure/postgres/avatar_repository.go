@@ -0,0 +1,141 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// AvatarRepository is the PostgreSQL implementation of the avatar repository
+type AvatarRepository struct {
+	db      *DB
+	retrier *Retrier
+	logger  *zap.Logger
+}
+
+// NewAvatarRepository creates a new instance of AvatarRepository
+func NewAvatarRepository(db *DB, retrier *Retrier, logger *zap.Logger) *AvatarRepository {
+	return &AvatarRepository{
+		db:      db,
+		retrier: retrier,
+		logger:  logger,
+	}
+}
+
+// Get retrieves the avatar of a user
+func (r *AvatarRepository) Get(ctx context.Context, userID string) (*domain.Avatar, error) {
+	query := `
+		SELECT user_id, object_key, content_type, size_bytes, updated_at
+		FROM {user_avatars}
+		WHERE user_id = $1
+	`
+
+	avatar := &domain.Avatar{}
+	err := r.retrier.Do(ctx, "avatars.get", func(ctx context.Context) error {
+		return r.db.QueryRowContext(ctx, query, userID).Scan(
+			&avatar.UserID,
+			&avatar.ObjectKey,
+			&avatar.ContentType,
+			&avatar.Size,
+			&avatar.UpdatedAt,
+		)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, domainerrors.ErrAvatarNotFound
+	}
+	if err != nil {
+		r.logger.Error("failed to get avatar", zap.Error(err), zap.String("user_id", userID))
+		return nil, fmt.Errorf("failed to get avatar: %w", err)
+	}
+
+	return avatar, nil
+}
+
+// Upsert creates or replaces the avatar of a user
+func (r *AvatarRepository) Upsert(ctx context.Context, avatar *domain.Avatar) error {
+	avatar.UpdatedAt = time.Now()
+
+	query := `
+		INSERT INTO {user_avatars} (user_id, object_key, content_type, size_bytes, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE
+		SET object_key = EXCLUDED.object_key,
+			content_type = EXCLUDED.content_type,
+			size_bytes = EXCLUDED.size_bytes,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	err := r.retrier.Do(ctx, "avatars.upsert", func(ctx context.Context) error {
+		_, err := r.db.ExecContext(ctx, query,
+			avatar.UserID,
+			avatar.ObjectKey,
+			avatar.ContentType,
+			avatar.Size,
+			avatar.UpdatedAt,
+		)
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to upsert avatar", zap.Error(err), zap.String("user_id", avatar.UserID))
+		return fmt.Errorf("failed to upsert avatar: %w", err)
+	}
+
+	r.logger.Info("avatar updated successfully", zap.String("user_id", avatar.UserID))
+	return nil
+}
+
+// DeleteByUserID removes the avatar of a user, if any
+func (r *AvatarRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	err := r.retrier.Do(ctx, "avatars.delete_by_user_id", func(ctx context.Context) error {
+		_, err := r.db.ExecContext(ctx, `DELETE FROM {user_avatars} WHERE user_id = $1`, userID)
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to delete avatar", zap.Error(err), zap.String("user_id", userID))
+		return fmt.Errorf("failed to delete avatar: %w", err)
+	}
+
+	return nil
+}
+
+// ReferencedKeys returns which of the object keys are the avatar of a user
+func (r *AvatarRepository) ReferencedKeys(ctx context.Context, keys []string) (map[string]bool, error) {
+	referenced := make(map[string]bool, len(keys))
+	if len(keys) == 0 {
+		return referenced, nil
+	}
+
+	query := `SELECT object_key FROM {user_avatars} WHERE object_key = ANY($1)`
+
+	err := r.retrier.Do(ctx, "avatars.referenced_keys", func(ctx context.Context) error {
+		rows, err := r.db.QueryContext(ctx, query, pq.Array(keys))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		clear(referenced)
+		for rows.Next() {
+			var key string
+			if err := rows.Scan(&key); err != nil {
+				return err
+			}
+			referenced[key] = true
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		r.logger.Error("failed to get referenced avatar keys", zap.Error(err))
+		return nil, fmt.Errorf("failed to get referenced avatar keys: %w", err)
+	}
+
+	return referenced, nil
+}
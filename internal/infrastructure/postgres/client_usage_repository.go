@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// ClientUsageRepository is the PostgreSQL implementation of the client usage repository
+type ClientUsageRepository struct {
+	db      *DB
+	retrier *Retrier
+	logger  *zap.Logger
+}
+
+// NewClientUsageRepository creates a new instance of ClientUsageRepository
+func NewClientUsageRepository(db *DB, retrier *Retrier, logger *zap.Logger) *ClientUsageRepository {
+	return &ClientUsageRepository{
+		db:      db,
+		retrier: retrier,
+		logger:  logger,
+	}
+}
+
+// Add adds the usage to the totals of the client, in a single statement. The recent errors restart from
+// the delta when they were counted since before errorWindow ago.
+func (r *ClientUsageRepository) Add(ctx context.Context, delta *domain.ClientUsageDelta, errorWindow time.Duration) error {
+	query := `
+		INSERT INTO {oauth_client_usage} (client_id, last_used_at, tokens_issued, recent_errors, recent_errors_since, updated_at)
+		SELECT $1, $2, $3, $4, $5, $5
+		WHERE EXISTS (SELECT 1 FROM {oauth_clients} WHERE client_id = $1)
+		ON CONFLICT (client_id) DO UPDATE SET
+			last_used_at = GREATEST({oauth_client_usage}.last_used_at, EXCLUDED.last_used_at),
+			tokens_issued = {oauth_client_usage}.tokens_issued + EXCLUDED.tokens_issued,
+			recent_errors = CASE
+				WHEN {oauth_client_usage}.recent_errors_since <= $6 THEN EXCLUDED.recent_errors
+				ELSE {oauth_client_usage}.recent_errors + EXCLUDED.recent_errors
+			END,
+			recent_errors_since = CASE
+				WHEN {oauth_client_usage}.recent_errors_since <= $6 THEN EXCLUDED.recent_errors_since
+				ELSE {oauth_client_usage}.recent_errors_since
+			END,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	var lastUsedAt sql.NullTime
+	if !delta.LastUsedAt.IsZero() {
+		lastUsedAt = sql.NullTime{Time: delta.LastUsedAt, Valid: true}
+	}
+
+	now := time.Now()
+	// Counters are added, a retried statement could count the usage twice
+	err := r.retrier.DoNonIdempotent(ctx, "client_usage.add", func(ctx context.Context) error {
+		_, err := r.db.ExecContext(ctx, query,
+			delta.ClientID,
+			lastUsedAt,
+			delta.TokensIssued,
+			delta.Errors,
+			now,
+			now.Add(-errorWindow),
+		)
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to add client usage", zap.Error(err), zap.String("client_id", delta.ClientID))
+		return fmt.Errorf("failed to add client usage: %w", err)
+	}
+
+	return nil
+}
+
+// List returns the usage of every client that was used, keyed by client ID
+func (r *ClientUsageRepository) List(ctx context.Context) (map[string]*domain.ClientUsage, error) {
+	query := `SELECT client_id, last_used_at, tokens_issued, recent_errors, recent_errors_since FROM {oauth_client_usage}`
+
+	usage := make(map[string]*domain.ClientUsage)
+	err := r.retrier.Do(ctx, "client_usage.list", func(ctx context.Context) error {
+		rows, err := r.db.QueryContext(ctx, query)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		clear(usage)
+		for rows.Next() {
+			var clientUsage domain.ClientUsage
+			var lastUsedAt, recentErrorsSince sql.NullTime
+			if err := rows.Scan(
+				&clientUsage.ClientID,
+				&lastUsedAt,
+				&clientUsage.TokensIssued,
+				&clientUsage.RecentErrors,
+				&recentErrorsSince,
+			); err != nil {
+				return err
+			}
+			if lastUsedAt.Valid {
+				clientUsage.LastUsedAt = &lastUsedAt.Time
+			}
+			if recentErrorsSince.Valid {
+				clientUsage.RecentErrorsSince = &recentErrorsSince.Time
+			}
+			usage[clientUsage.ClientID] = &clientUsage
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		r.logger.Error("failed to list client usage", zap.Error(err))
+		return nil, fmt.Errorf("failed to list client usage: %w", err)
+	}
+
+	return usage, nil
+}
@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// ConsentRepository is the PostgreSQL implementation of the consent repository
+type ConsentRepository struct {
+	db      *DB
+	retrier *Retrier
+	logger  *zap.Logger
+}
+
+// NewConsentRepository creates a new instance of ConsentRepository
+func NewConsentRepository(db *DB, retrier *Retrier, logger *zap.Logger) *ConsentRepository {
+	return &ConsentRepository{
+		db:      db,
+		retrier: retrier,
+		logger:  logger,
+	}
+}
+
+// Get retrieves the consent a user granted to a client
+func (r *ConsentRepository) Get(ctx context.Context, userID, clientID string) (*domain.Consent, error) {
+	query := `
+		SELECT user_id, client_id, scopes, granted_at, updated_at
+		FROM {user_consents}
+		WHERE user_id = $1 AND client_id = $2
+	`
+
+	consent := &domain.Consent{}
+	var scopes pq.StringArray
+
+	err := r.retrier.Do(ctx, "consents.get", func(ctx context.Context) error {
+		return r.db.QueryRowContext(ctx, query, userID, clientID).Scan(
+			&consent.UserID,
+			&consent.ClientID,
+			&scopes,
+			&consent.GrantedAt,
+			&consent.UpdatedAt,
+		)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, domainerrors.ErrConsentNotFound
+	}
+	if err != nil {
+		r.logger.Error("failed to get consent", zap.Error(err), zap.String("user_id", userID), zap.String("client_id", clientID))
+		return nil, fmt.Errorf("failed to get consent: %w", err)
+	}
+
+	consent.Scopes = scopes
+	return consent, nil
+}
+
+// ListByUser retrieves every consent granted by a user
+func (r *ConsentRepository) ListByUser(ctx context.Context, userID string) ([]*domain.Consent, error) {
+	query := `
+		SELECT user_id, client_id, scopes, granted_at, updated_at
+		FROM {user_consents}
+		WHERE user_id = $1
+		ORDER BY updated_at DESC
+	`
+
+	var consents []*domain.Consent
+	err := r.retrier.Do(ctx, "consents.list_by_user", func(ctx context.Context) error {
+		rows, err := r.db.QueryContext(ctx, query, userID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		consents = nil
+		for rows.Next() {
+			consent := &domain.Consent{}
+			var scopes pq.StringArray
+
+			if err := rows.Scan(
+				&consent.UserID,
+				&consent.ClientID,
+				&scopes,
+				&consent.GrantedAt,
+				&consent.UpdatedAt,
+			); err != nil {
+				return err
+			}
+
+			consent.Scopes = scopes
+			consents = append(consents, consent)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		r.logger.Error("failed to list consents", zap.Error(err), zap.String("user_id", userID))
+		return nil, fmt.Errorf("failed to list consents: %w", err)
+	}
+
+	return consents, nil
+}
+
+// Upsert creates or replaces the consent of a user for a client
+func (r *ConsentRepository) Upsert(ctx context.Context, consent *domain.Consent) error {
+	consent.UpdatedAt = time.Now()
+	if consent.GrantedAt.IsZero() {
+		consent.GrantedAt = consent.UpdatedAt
+	}
+
+	query := `
+		INSERT INTO {user_consents} (user_id, client_id, scopes, granted_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, client_id) DO UPDATE
+		SET scopes = EXCLUDED.scopes,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	err := r.retrier.Do(ctx, "consents.upsert", func(ctx context.Context) error {
+		_, err := r.db.ExecContext(ctx, query,
+			consent.UserID,
+			consent.ClientID,
+			pq.Array(consent.Scopes),
+			consent.GrantedAt,
+			consent.UpdatedAt,
+		)
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to upsert consent", zap.Error(err), zap.String("user_id", consent.UserID), zap.String("client_id", consent.ClientID))
+		return fmt.Errorf("failed to upsert consent: %w", err)
+	}
+
+	r.logger.Info("consent saved successfully", zap.String("user_id", consent.UserID), zap.String("client_id", consent.ClientID))
+	return nil
+}
+
+// Delete revokes the consent of a user for a client
+func (r *ConsentRepository) Delete(ctx context.Context, userID, clientID string) error {
+	var result sql.Result
+	err := r.retrier.DoNonIdempotent(ctx, "consents.delete", func(ctx context.Context) (err error) {
+		result, err = r.db.ExecContext(ctx, `DELETE FROM {user_consents} WHERE user_id = $1 AND client_id = $2`, userID, clientID)
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to delete consent", zap.Error(err), zap.String("user_id", userID), zap.String("client_id", clientID))
+		return fmt.Errorf("failed to delete consent: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domainerrors.ErrConsentNotFound
+	}
+
+	r.logger.Info("consent revoked successfully", zap.String("user_id", userID), zap.String("client_id", clientID))
+	return nil
+}
@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// FindEmailCaseDuplicates returns the groups of users whose emails differ only by case, deleted users
+// included like the unique constraint on the email
+func FindEmailCaseDuplicates(ctx context.Context, db *DB) ([]domain.EmailCaseDuplicate, error) {
+	query := `
+		SELECT lower(email), array_agg(id ORDER BY created_at, id), array_agg(email ORDER BY created_at, id)
+		FROM {users}
+		GROUP BY lower(email)
+		HAVING COUNT(*) > 1
+		ORDER BY lower(email)
+	`
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find email case duplicates: %w", err)
+	}
+	defer rows.Close()
+
+	var duplicates []domain.EmailCaseDuplicate
+	for rows.Next() {
+		var duplicate domain.EmailCaseDuplicate
+		if err := rows.Scan(&duplicate.Email, pq.Array(&duplicate.UserIDs), pq.Array(&duplicate.Emails)); err != nil {
+			return nil, fmt.Errorf("failed to scan email case duplicate: %w", err)
+		}
+		duplicates = append(duplicates, duplicate)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to find email case duplicates: %w", err)
+	}
+	return duplicates, nil
+}
+
+// MigrateEmailCase creates the unique index on lower(email) that keeps emails differing only by case from
+// creating separate accounts. The index cannot be built while the tables hold such duplicates, so they are
+// returned instead and the index is created on a later start, once they are resolved.
+func MigrateEmailCase(ctx context.Context, db *DB) ([]domain.EmailCaseDuplicate, error) {
+	duplicates, err := FindEmailCaseDuplicates(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	if len(duplicates) > 0 {
+		return duplicates, nil
+	}
+
+	if _, err := db.ExecContext(ctx, `CREATE UNIQUE INDEX IF NOT EXISTS idx_{users}_email_lower ON {users}(lower(email))`); err != nil {
+		return nil, fmt.Errorf("failed to create the email case index: %w", err)
+	}
+	return nil, nil
+}
@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+)
+
+// tables are the tables of the service. Statements name them as {table} placeholders, which DB expands
+// with the table prefix; index names embed the placeholder of their table so they are prefixed too.
+var tables = []string{
+	"users",
+	"oauth_clients",
+	"scopes",
+	"user_consents",
+	"user_notification_preferences",
+	"user_phone_numbers",
+	"user_emails",
+	"user_avatars",
+	"audit_log",
+	"outbox_messages",
+	"issuance_quotas",
+	"oauth_client_usage",
+	"refresh_tokens",
+}
+
+// Naming places the tables of the service in a Postgres schema and prefixes their names, so several
+// services can share a database without collisions
+type Naming struct {
+	// Schema holds the tables, it must be the search_path of the connections. The server default when empty.
+	Schema string
+
+	// TablePrefix is prepended to the names of the tables and their indexes
+	TablePrefix string
+}
+
+// DB is the connection pool of the repositories. It expands the {table} placeholders of the statements
+// with the table prefix of its naming.
+type DB struct {
+	*sql.DB
+	naming Naming
+	tables *strings.Replacer
+}
+
+// NewNamedDB wraps a connection pool whose statements name the tables with placeholders
+func NewNamedDB(db *sql.DB, naming Naming) *DB {
+	oldnew := make([]string, 0, 2*len(tables))
+	for _, table := range tables {
+		oldnew = append(oldnew, "{"+table+"}", naming.TablePrefix+table)
+	}
+	return &DB{
+		DB:     db,
+		naming: naming,
+		tables: strings.NewReplacer(oldnew...),
+	}
+}
+
+// ExecContext executes a statement after expanding its table placeholders
+func (db *DB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return db.DB.ExecContext(ctx, db.tables.Replace(query), args...)
+}
+
+// Exec executes a statement after expanding its table placeholders
+func (db *DB) Exec(query string, args ...any) (sql.Result, error) {
+	return db.DB.Exec(db.tables.Replace(query), args...)
+}
+
+// QueryContext runs a query after expanding its table placeholders
+func (db *DB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return db.DB.QueryContext(ctx, db.tables.Replace(query), args...)
+}
+
+// QueryRowContext runs a query returning at most one row after expanding its table placeholders
+func (db *DB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return db.DB.QueryRowContext(ctx, db.tables.Replace(query), args...)
+}
@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// NotificationPreferencesRepository is the PostgreSQL implementation of the notification preferences repository
+type NotificationPreferencesRepository struct {
+	db      *DB
+	retrier *Retrier
+	logger  *zap.Logger
+}
+
+// NewNotificationPreferencesRepository creates a new instance of NotificationPreferencesRepository
+func NewNotificationPreferencesRepository(db *DB, retrier *Retrier, logger *zap.Logger) *NotificationPreferencesRepository {
+	return &NotificationPreferencesRepository{
+		db:      db,
+		retrier: retrier,
+		logger:  logger,
+	}
+}
+
+// GetByUserID retrieves the preferences of a user
+func (r *NotificationPreferencesRepository) GetByUserID(ctx context.Context, userID string) (*domain.NotificationPreferences, error) {
+	query := `
+		SELECT user_id, new_device, password_change, login_alert, updated_at
+		FROM {user_notification_preferences}
+		WHERE user_id = $1
+	`
+
+	prefs := &domain.NotificationPreferences{}
+	err := r.retrier.Do(ctx, "notification_preferences.get_by_user_id", func(ctx context.Context) error {
+		return r.db.QueryRowContext(ctx, query, userID).Scan(
+			&prefs.UserID,
+			&prefs.NewDevice,
+			&prefs.PasswordChange,
+			&prefs.LoginAlert,
+			&prefs.UpdatedAt,
+		)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, domainerrors.ErrPreferencesNotFound
+	}
+	if err != nil {
+		r.logger.Error("failed to get notification preferences", zap.Error(err), zap.String("user_id", userID))
+		return nil, fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+
+	return prefs, nil
+}
+
+// Upsert creates or replaces the preferences of a user
+func (r *NotificationPreferencesRepository) Upsert(ctx context.Context, prefs *domain.NotificationPreferences) error {
+	prefs.UpdatedAt = time.Now()
+
+	query := `
+		INSERT INTO {user_notification_preferences} (user_id, new_device, password_change, login_alert, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE
+		SET new_device = EXCLUDED.new_device,
+			password_change = EXCLUDED.password_change,
+			login_alert = EXCLUDED.login_alert,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	err := r.retrier.Do(ctx, "notification_preferences.upsert", func(ctx context.Context) error {
+		_, err := r.db.ExecContext(ctx, query,
+			prefs.UserID,
+			prefs.NewDevice,
+			prefs.PasswordChange,
+			prefs.LoginAlert,
+			prefs.UpdatedAt,
+		)
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to upsert notification preferences", zap.Error(err), zap.String("user_id", prefs.UserID))
+		return fmt.Errorf("failed to upsert notification preferences: %w", err)
+	}
+
+	r.logger.Info("notification preferences updated successfully", zap.String("user_id", prefs.UserID))
+	return nil
+}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/lib/pq"
 	"go.uber.org/zap"
 
@@ -16,40 +15,56 @@ import (
 
 // OAuthClientRepository is the PostgreSQL implementation of the OAuth client repository
 type OAuthClientRepository struct {
-	db     *sql.DB
-	logger *zap.Logger
+	db      *DB
+	retrier *Retrier
+	newID   domain.IDGenerator
+	logger  *zap.Logger
 }
 
-// NewOAuthClientRepository creates a new instance of OAuthClientRepository
-func NewOAuthClientRepository(db *sql.DB, logger *zap.Logger) *OAuthClientRepository {
+// NewOAuthClientRepository creates a new instance of OAuthClientRepository, whose new rows get IDs from newID
+func NewOAuthClientRepository(db *DB, retrier *Retrier, newID domain.IDGenerator, logger *zap.Logger) *OAuthClientRepository {
 	return &OAuthClientRepository{
-		db:     db,
-		logger: logger,
+		db:      db,
+		retrier: retrier,
+		newID:   newID,
+		logger:  logger,
 	}
 }
 
 // Create creates a new OAuth client in the database
 func (r *OAuthClientRepository) Create(ctx context.Context, client *domain.OAuthClient) error {
-	client.ID = uuid.New().String()
+	client.ID = r.newID()
+	client.Version = 1
 	client.CreatedAt = time.Now()
 	client.UpdatedAt = time.Now()
 
 	query := `
-		INSERT INTO oauth_clients (id, client_id, client_secret, name, description, scopes, active, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO {oauth_clients} (id, client_id, client_secret, name, description, scopes, active, created_at, updated_at, require_signed_requests, request_signing_key, token_profile, grant_types, redirect_uris, tags, secret_expires_at, version)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
-		client.ID,
-		client.ClientID,
-		client.ClientSecret,
-		client.Name,
-		client.Description,
-		pq.Array(client.Scopes),
-		client.Active,
-		client.CreatedAt,
-		client.UpdatedAt,
-	)
+	err := r.retrier.DoNonIdempotent(ctx, "oauth_clients.create", func(ctx context.Context) error {
+		_, err := r.db.ExecContext(ctx, query,
+			client.ID,
+			client.ClientID,
+			client.ClientSecret,
+			client.Name,
+			client.Description,
+			pq.Array(client.Scopes),
+			client.Active,
+			client.CreatedAt,
+			client.UpdatedAt,
+			client.RequireSignedRequests,
+			client.RequestSigningKey,
+			client.TokenProfile,
+			pq.Array(client.GrantTypes),
+			pq.Array(client.RedirectURIs),
+			pq.Array(client.Tags),
+			client.SecretExpiresAt,
+			client.Version,
+		)
+		return err
+	})
 
 	if err != nil {
 		r.logger.Error("failed to create oauth client", zap.Error(err), zap.String("client_id", client.ClientID))
@@ -65,25 +80,35 @@ func (r *OAuthClientRepository) Create(ctx context.Context, client *domain.OAuth
 //nolint:dupl // Similar to GetByID but queries by client_id instead of id
 func (r *OAuthClientRepository) GetByClientID(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
 	query := `
-		SELECT id, client_id, client_secret, name, description, scopes, active, created_at, updated_at
-		FROM oauth_clients
+		SELECT id, client_id, client_secret, name, description, scopes, active, created_at, updated_at, require_signed_requests, request_signing_key, token_profile, grant_types, redirect_uris, tags, secret_expires_at, version
+		FROM {oauth_clients}
 		WHERE client_id = $1 AND active = true
 	`
 
 	client := &domain.OAuthClient{}
-	var scopes pq.StringArray
-
-	err := r.db.QueryRowContext(ctx, query, clientID).Scan(
-		&client.ID,
-		&client.ClientID,
-		&client.ClientSecret,
-		&client.Name,
-		&client.Description,
-		&scopes,
-		&client.Active,
-		&client.CreatedAt,
-		&client.UpdatedAt,
-	)
+	var scopes, grantTypes, redirectURIs, tags pq.StringArray
+
+	err := r.retrier.Do(ctx, "oauth_clients.get_by_client_id", func(ctx context.Context) error {
+		return r.db.QueryRowContext(ctx, query, clientID).Scan(
+			&client.ID,
+			&client.ClientID,
+			&client.ClientSecret,
+			&client.Name,
+			&client.Description,
+			&scopes,
+			&client.Active,
+			&client.CreatedAt,
+			&client.UpdatedAt,
+			&client.RequireSignedRequests,
+			&client.RequestSigningKey,
+			&client.TokenProfile,
+			&grantTypes,
+			&redirectURIs,
+			&tags,
+			&client.SecretExpiresAt,
+			&client.Version,
+		)
+	})
 
 	if err == sql.ErrNoRows {
 		return nil, domainerrors.ErrInvalidCredentials
@@ -94,6 +119,9 @@ func (r *OAuthClientRepository) GetByClientID(ctx context.Context, clientID stri
 	}
 
 	client.Scopes = scopes
+	client.GrantTypes = grantTypes
+	client.RedirectURIs = redirectURIs
+	client.Tags = tags
 	return client, nil
 }
 
@@ -101,26 +129,42 @@ func (r *OAuthClientRepository) GetByClientID(ctx context.Context, clientID stri
 //
 //nolint:dupl // Similar to GetByClientID but queries by id instead of client_id
 func (r *OAuthClientRepository) GetByID(ctx context.Context, id string) (*domain.OAuthClient, error) {
+	// IDs that are neither UUIDs nor ULIDs can't exist, and UUIDs in uppercase match the stored ones
+	id, err := domain.ParseID(id)
+	if err != nil {
+		return nil, domainerrors.ErrClientNotFound
+	}
+
 	query := `
-		SELECT id, client_id, client_secret, name, description, scopes, active, created_at, updated_at
-		FROM oauth_clients
+		SELECT id, client_id, client_secret, name, description, scopes, active, created_at, updated_at, require_signed_requests, request_signing_key, token_profile, grant_types, redirect_uris, tags, secret_expires_at, version
+		FROM {oauth_clients}
 		WHERE id = $1
 	`
 
 	client := &domain.OAuthClient{}
-	var scopes pq.StringArray
-
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&client.ID,
-		&client.ClientID,
-		&client.ClientSecret,
-		&client.Name,
-		&client.Description,
-		&scopes,
-		&client.Active,
-		&client.CreatedAt,
-		&client.UpdatedAt,
-	)
+	var scopes, grantTypes, redirectURIs, tags pq.StringArray
+
+	err = r.retrier.Do(ctx, "oauth_clients.get_by_id", func(ctx context.Context) error {
+		return r.db.QueryRowContext(ctx, query, id).Scan(
+			&client.ID,
+			&client.ClientID,
+			&client.ClientSecret,
+			&client.Name,
+			&client.Description,
+			&scopes,
+			&client.Active,
+			&client.CreatedAt,
+			&client.UpdatedAt,
+			&client.RequireSignedRequests,
+			&client.RequestSigningKey,
+			&client.TokenProfile,
+			&grantTypes,
+			&redirectURIs,
+			&tags,
+			&client.SecretExpiresAt,
+			&client.Version,
+		)
+	})
 
 	if err == sql.ErrNoRows {
 		return nil, domainerrors.ErrClientNotFound
@@ -131,27 +175,44 @@ func (r *OAuthClientRepository) GetByID(ctx context.Context, id string) (*domain
 	}
 
 	client.Scopes = scopes
+	client.GrantTypes = grantTypes
+	client.RedirectURIs = redirectURIs
+	client.Tags = tags
 	return client, nil
 }
 
-// Update updates an existing OAuth client
+// Update updates an existing OAuth client, which must not have been modified since it was read
 func (r *OAuthClientRepository) Update(ctx context.Context, client *domain.OAuthClient) error {
 	client.UpdatedAt = time.Now()
 
 	query := `
-		UPDATE oauth_clients
-		SET name = $1, description = $2, scopes = $3, active = $4, updated_at = $5
-		WHERE id = $6
+		UPDATE {oauth_clients}
+		SET name = $1, description = $2, scopes = $3, active = $4, updated_at = $5,
+			require_signed_requests = $6, request_signing_key = $7, token_profile = $8, grant_types = $9, redirect_uris = $10,
+			tags = $11, secret_expires_at = $12, version = version + 1
+		WHERE id = $13 AND version = $14
 	`
 
-	result, err := r.db.ExecContext(ctx, query,
-		client.Name,
-		client.Description,
-		pq.Array(client.Scopes),
-		client.Active,
-		client.UpdatedAt,
-		client.ID,
-	)
+	var result sql.Result
+	err := r.retrier.DoNonIdempotent(ctx, "oauth_clients.update", func(ctx context.Context) (err error) {
+		result, err = r.db.ExecContext(ctx, query,
+			client.Name,
+			client.Description,
+			pq.Array(client.Scopes),
+			client.Active,
+			client.UpdatedAt,
+			client.RequireSignedRequests,
+			client.RequestSigningKey,
+			client.TokenProfile,
+			pq.Array(client.GrantTypes),
+			pq.Array(client.RedirectURIs),
+			pq.Array(client.Tags),
+			client.SecretExpiresAt,
+			client.ID,
+			client.Version,
+		)
+		return err
+	})
 
 	if err != nil {
 		r.logger.Error("failed to update oauth client", zap.Error(err), zap.String("id", client.ID))
@@ -164,9 +225,15 @@ func (r *OAuthClientRepository) Update(ctx context.Context, client *domain.OAuth
 	}
 
 	if rowsAffected == 0 {
-		return domainerrors.ErrClientNotFound
+		// Either the client doesn't exist or it was modified since it was read
+		if _, err := r.GetByID(ctx, client.ID); err != nil {
+			return err
+		}
+		r.logger.Warn("oauth client modified concurrently", zap.String("id", client.ID))
+		return domainerrors.ErrVersionConflict
 	}
 
+	client.Version++
 	r.logger.Info("oauth client updated successfully", zap.String("id", client.ID))
 	return nil
 }
@@ -174,12 +241,16 @@ func (r *OAuthClientRepository) Update(ctx context.Context, client *domain.OAuth
 // Delete deactivates an OAuth client (soft delete)
 func (r *OAuthClientRepository) Delete(ctx context.Context, id string) error {
 	query := `
-		UPDATE oauth_clients
-		SET active = false, updated_at = $1
+		UPDATE {oauth_clients}
+		SET active = false, updated_at = $1, version = version + 1
 		WHERE id = $2
 	`
 
-	result, err := r.db.ExecContext(ctx, query, time.Now(), id)
+	var result sql.Result
+	err := r.retrier.Do(ctx, "oauth_clients.delete", func(ctx context.Context) (err error) {
+		result, err = r.db.ExecContext(ctx, query, time.Now(), id)
+		return err
+	})
 	if err != nil {
 		r.logger.Error("failed to delete oauth client", zap.Error(err), zap.String("id", id))
 		return fmt.Errorf("failed to delete oauth client: %w", err)
@@ -201,51 +272,59 @@ func (r *OAuthClientRepository) Delete(ctx context.Context, id string) error {
 // List retrieves all active OAuth clients
 func (r *OAuthClientRepository) List(ctx context.Context) ([]*domain.OAuthClient, error) {
 	query := `
-		SELECT id, client_id, client_secret, name, description, scopes, active, created_at, updated_at
-		FROM oauth_clients
+		SELECT id, client_id, client_secret, name, description, scopes, active, created_at, updated_at, require_signed_requests, request_signing_key, token_profile, grant_types, redirect_uris, tags, secret_expires_at, version
+		FROM {oauth_clients}
 		WHERE active = true
 		ORDER BY created_at DESC
 	`
 
-	rows, err := r.db.QueryContext(ctx, query)
-	if err != nil {
-		r.logger.Error("failed to list oauth clients", zap.Error(err))
-		return nil, fmt.Errorf("failed to list oauth clients: %w", err)
-	}
-	defer func() {
-		if closeErr := rows.Close(); closeErr != nil {
-			r.logger.Error("failed to close rows", zap.Error(closeErr))
-		}
-	}()
-
 	var clients []*domain.OAuthClient
-	for rows.Next() {
-		client := &domain.OAuthClient{}
-		var scopes pq.StringArray
-
-		err := rows.Scan(
-			&client.ID,
-			&client.ClientID,
-			&client.ClientSecret,
-			&client.Name,
-			&client.Description,
-			&scopes,
-			&client.Active,
-			&client.CreatedAt,
-			&client.UpdatedAt,
-		)
+	err := r.retrier.Do(ctx, "oauth_clients.list", func(ctx context.Context) error {
+		rows, err := r.db.QueryContext(ctx, query)
 		if err != nil {
-			r.logger.Error("failed to scan oauth client", zap.Error(err))
-			return nil, fmt.Errorf("failed to scan oauth client: %w", err)
+			return err
 		}
-
-		client.Scopes = scopes
-		clients = append(clients, client)
-	}
-
-	if err = rows.Err(); err != nil {
-		r.logger.Error("error iterating oauth clients", zap.Error(err))
-		return nil, fmt.Errorf("error iterating oauth clients: %w", err)
+		defer rows.Close()
+
+		clients = nil
+		for rows.Next() {
+			client := &domain.OAuthClient{}
+			var scopes, grantTypes, redirectURIs, tags pq.StringArray
+
+			err := rows.Scan(
+				&client.ID,
+				&client.ClientID,
+				&client.ClientSecret,
+				&client.Name,
+				&client.Description,
+				&scopes,
+				&client.Active,
+				&client.CreatedAt,
+				&client.UpdatedAt,
+				&client.RequireSignedRequests,
+				&client.RequestSigningKey,
+				&client.TokenProfile,
+				&grantTypes,
+				&redirectURIs,
+				&tags,
+				&client.SecretExpiresAt,
+				&client.Version,
+			)
+			if err != nil {
+				return err
+			}
+
+			client.Scopes = scopes
+			client.GrantTypes = grantTypes
+			client.RedirectURIs = redirectURIs
+			client.Tags = tags
+			clients = append(clients, client)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		r.logger.Error("failed to list oauth clients", zap.Error(err))
+		return nil, fmt.Errorf("failed to list oauth clients: %w", err)
 	}
 
 	return clients, nil
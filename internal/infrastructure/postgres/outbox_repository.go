@@ -0,0 +1,160 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// OutboxRepository is the PostgreSQL implementation of the outbox repository
+type OutboxRepository struct {
+	db      *DB
+	retrier *Retrier
+	logger  *zap.Logger
+}
+
+// NewOutboxRepository creates a new instance of OutboxRepository
+func NewOutboxRepository(db *DB, retrier *Retrier, logger *zap.Logger) *OutboxRepository {
+	return &OutboxRepository{
+		db:      db,
+		retrier: retrier,
+		logger:  logger,
+	}
+}
+
+// Enqueue stores a message for later delivery.
+// Messages carry their own ID, so a retried insert never duplicates them.
+func (r *OutboxRepository) Enqueue(ctx context.Context, message *domain.OutboxMessage) error {
+	query := `
+		INSERT INTO {outbox_messages} (id, queue, payload, attempts, last_error, created_at, next_attempt_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (id) DO NOTHING
+	`
+
+	err := r.retrier.Do(ctx, "outbox.enqueue", func(ctx context.Context) error {
+		_, err := r.db.ExecContext(ctx, query,
+			message.ID,
+			message.Queue,
+			message.Payload,
+			message.Attempts,
+			message.LastError,
+			message.CreatedAt,
+			message.NextAttemptAt,
+		)
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to enqueue outbox message", zap.Error(err), zap.String("queue", message.Queue))
+		return fmt.Errorf("failed to enqueue outbox message: %w", err)
+	}
+
+	return nil
+}
+
+// Backlog returns the number of messages waiting for delivery, including those being retried
+func (r *OutboxRepository) Backlog(ctx context.Context) (int64, error) {
+	query := `SELECT COUNT(*) FROM {outbox_messages}`
+
+	var count int64
+	err := r.retrier.Do(ctx, "outbox.backlog", func(ctx context.Context) error {
+		return r.db.QueryRowContext(ctx, query).Scan(&count)
+	})
+	if err != nil {
+		r.logger.Error("failed to count outbox messages", zap.Error(err))
+		return 0, fmt.Errorf("failed to count outbox messages: %w", err)
+	}
+
+	return count, nil
+}
+
+// ClaimDue returns the messages due for delivery and pushes their next attempt past the lease.
+// Rows locked by a concurrent claim are skipped.
+func (r *OutboxRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*domain.OutboxMessage, error) {
+	query := `
+		UPDATE {outbox_messages}
+		SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT id FROM {outbox_messages}
+			WHERE next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, queue, payload, attempts, last_error, created_at, next_attempt_at
+	`
+
+	var messages []*domain.OutboxMessage
+	// A retried claim at worst hides messages until the lease expires
+	err := r.retrier.Do(ctx, "outbox.claim_due", func(ctx context.Context) error {
+		now := time.Now()
+		rows, err := r.db.QueryContext(ctx, query, now, now.Add(lease), limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		messages = nil
+		for rows.Next() {
+			message := &domain.OutboxMessage{}
+			if err := rows.Scan(
+				&message.ID,
+				&message.Queue,
+				&message.Payload,
+				&message.Attempts,
+				&message.LastError,
+				&message.CreatedAt,
+				&message.NextAttemptAt,
+			); err != nil {
+				return err
+			}
+			messages = append(messages, message)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		r.logger.Error("failed to claim outbox messages", zap.Error(err))
+		return nil, fmt.Errorf("failed to claim outbox messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+// Delete removes a delivered message
+func (r *OutboxRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM {outbox_messages} WHERE id = $1`
+
+	err := r.retrier.Do(ctx, "outbox.delete", func(ctx context.Context) error {
+		_, err := r.db.ExecContext(ctx, query, id)
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to delete outbox message", zap.Error(err), zap.String("id", id))
+		return fmt.Errorf("failed to delete outbox message: %w", err)
+	}
+
+	return nil
+}
+
+// MarkFailed records a failed delivery attempt and schedules the next one
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id, lastError string, nextAttemptAt time.Time) error {
+	query := `
+		UPDATE {outbox_messages}
+		SET attempts = attempts + 1, last_error = $2, next_attempt_at = $3
+		WHERE id = $1
+	`
+
+	err := r.retrier.DoNonIdempotent(ctx, "outbox.mark_failed", func(ctx context.Context) error {
+		_, err := r.db.ExecContext(ctx, query, id, lastError, nextAttemptAt)
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to mark outbox message as failed", zap.Error(err), zap.String("id", id))
+		return fmt.Errorf("failed to mark outbox message as failed: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// PhoneNumberRepository is the PostgreSQL implementation of the phone number repository
+type PhoneNumberRepository struct {
+	db      *DB
+	retrier *Retrier
+	logger  *zap.Logger
+}
+
+// NewPhoneNumberRepository creates a new instance of PhoneNumberRepository
+func NewPhoneNumberRepository(db *DB, retrier *Retrier, logger *zap.Logger) *PhoneNumberRepository {
+	return &PhoneNumberRepository{
+		db:      db,
+		retrier: retrier,
+		logger:  logger,
+	}
+}
+
+// GetByUserID retrieves the verified phone number of a user
+func (r *PhoneNumberRepository) GetByUserID(ctx context.Context, userID string) (*domain.PhoneNumber, error) {
+	query := `
+		SELECT user_id, phone_number, verified_at, updated_at
+		FROM {user_phone_numbers}
+		WHERE user_id = $1
+	`
+
+	phone := &domain.PhoneNumber{}
+	err := r.retrier.Do(ctx, "phone_numbers.get_by_user_id", func(ctx context.Context) error {
+		return r.db.QueryRowContext(ctx, query, userID).Scan(
+			&phone.UserID,
+			&phone.Number,
+			&phone.VerifiedAt,
+			&phone.UpdatedAt,
+		)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, domainerrors.ErrPhoneNotFound
+	}
+	if err != nil {
+		r.logger.Error("failed to get phone number", zap.Error(err), zap.String("user_id", userID))
+		return nil, fmt.Errorf("failed to get phone number: %w", err)
+	}
+
+	return phone, nil
+}
+
+// GetByNumber retrieves the verified phone number matching number, whoever it belongs to
+func (r *PhoneNumberRepository) GetByNumber(ctx context.Context, number string) (*domain.PhoneNumber, error) {
+	query := `
+		SELECT user_id, phone_number, verified_at, updated_at
+		FROM {user_phone_numbers}
+		WHERE phone_number = $1
+	`
+
+	phone := &domain.PhoneNumber{}
+	err := r.retrier.Do(ctx, "phone_numbers.get_by_number", func(ctx context.Context) error {
+		return r.db.QueryRowContext(ctx, query, number).Scan(
+			&phone.UserID,
+			&phone.Number,
+			&phone.VerifiedAt,
+			&phone.UpdatedAt,
+		)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, domainerrors.ErrPhoneNotFound
+	}
+	if err != nil {
+		r.logger.Error("failed to get phone number by number", zap.Error(err))
+		return nil, fmt.Errorf("failed to get phone number: %w", err)
+	}
+
+	return phone, nil
+}
+
+// Upsert creates or replaces the phone number of a user.
+// A number can only belong to one user, ErrPhoneAlreadyRegistered is returned otherwise.
+func (r *PhoneNumberRepository) Upsert(ctx context.Context, phone *domain.PhoneNumber) error {
+	phone.UpdatedAt = time.Now()
+
+	query := `
+		INSERT INTO {user_phone_numbers} (user_id, phone_number, verified_at, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO UPDATE
+		SET phone_number = EXCLUDED.phone_number,
+			verified_at = EXCLUDED.verified_at,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	err := r.retrier.Do(ctx, "phone_numbers.upsert", func(ctx context.Context) error {
+		_, err := r.db.ExecContext(ctx, query,
+			phone.UserID,
+			phone.Number,
+			phone.VerifiedAt,
+			phone.UpdatedAt,
+		)
+		return err
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && string(pqErr.Code) == "23505" {
+			return domainerrors.ErrPhoneAlreadyRegistered
+		}
+		r.logger.Error("failed to upsert phone number", zap.Error(err), zap.String("user_id", phone.UserID))
+		return fmt.Errorf("failed to upsert phone number: %w", err)
+	}
+
+	r.logger.Info("phone number updated successfully", zap.String("user_id", phone.UserID))
+	return nil
+}
+
+// Delete removes the phone number of a user
+func (r *PhoneNumberRepository) Delete(ctx context.Context, userID string) error {
+	var result sql.Result
+	err := r.retrier.DoNonIdempotent(ctx, "phone_numbers.delete", func(ctx context.Context) (err error) {
+		result, err = r.db.ExecContext(ctx, `DELETE FROM {user_phone_numbers} WHERE user_id = $1`, userID)
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to delete phone number", zap.Error(err), zap.String("user_id", userID))
+		return fmt.Errorf("failed to delete phone number: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domainerrors.ErrPhoneNotFound
+	}
+
+	r.logger.Info("phone number deleted successfully", zap.String("user_id", userID))
+	return nil
+}
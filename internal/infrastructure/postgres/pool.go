@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"go.uber.org/zap"
+
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
+)
+
+// PoolConfig sizes the connection pool
+type PoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+}
+
+// apply sets the limits of the pool, zero values keep the database/sql defaults
+func (c PoolConfig) apply(db *sql.DB) {
+	if c.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(c.MaxOpenConns)
+	}
+	if c.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(c.MaxIdleConns)
+	}
+	if c.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(c.ConnMaxLifetime)
+	}
+}
+
+// acquire takes one of the MaxConcurrentOperations slots for an attempt, waiting at most the pool wait timeout.
+// database/sql queues callers for a free connection without any bound but their context, so when the pool is
+// exhausted the attempt fails fast with ErrDatabaseBusy instead of holding the request.
+// Streamed rows are read after the slot is released, so they may briefly hold connections above the limit.
+func (r *Retrier) acquire(ctx context.Context, operation string) (func(), error) {
+	if r.slots == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case r.slots <- struct{}{}:
+		return r.release, nil
+	default:
+	}
+
+	queuedAt := time.Now()
+	timer := time.NewTimer(r.policy.PoolWaitTimeout)
+	defer timer.Stop()
+
+	select {
+	case r.slots <- struct{}{}:
+		metrics.ObserveDBPoolWait(time.Since(queuedAt))
+		return r.release, nil
+	case <-timer.C:
+		metrics.ObserveDBPoolWait(time.Since(queuedAt))
+		metrics.IncDBPoolRejectedOperation(operation)
+		r.logger.Warn("timed out waiting for a free database connection",
+			zap.String("operation", operation),
+			zap.Int("max_concurrent_operations", cap(r.slots)),
+			zap.Duration("wait_timeout", r.policy.PoolWaitTimeout))
+		return nil, domainerrors.ErrDatabaseBusy
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// release frees the slot taken by acquire
+func (r *Retrier) release() {
+	<-r.slots
+}
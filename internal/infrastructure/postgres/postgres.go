@@ -3,10 +3,11 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/lib/pq"
 	"go.uber.org/zap"
 
@@ -16,39 +17,60 @@ import (
 
 // UserRepository is the PostgreSQL implementation of the user repository
 type UserRepository struct {
-	db     *sql.DB
-	logger *zap.Logger
+	db      *DB
+	retrier *Retrier
+	newID   domain.IDGenerator
+	logger  *zap.Logger
 }
 
-// NewUserRepository creates a new instance of UserRepository
-func NewUserRepository(db *sql.DB, logger *zap.Logger) *UserRepository {
+// NewUserRepository creates a new instance of UserRepository, whose new rows get IDs from newID
+func NewUserRepository(db *DB, retrier *Retrier, newID domain.IDGenerator, logger *zap.Logger) *UserRepository {
 	return &UserRepository{
-		db:     db,
-		logger: logger,
+		db:      db,
+		retrier: retrier,
+		newID:   newID,
+		logger:  logger,
 	}
 }
 
 // Create creates a new user in the database
 func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
-	user.ID = uuid.New().String()
+	user.ID = r.newID()
+	user.Version = 1
 	user.CreatedAt = time.Now()
 	user.UpdatedAt = time.Now()
 
+	metadata, err := encodeUserMetadata(user.Metadata)
+	if err != nil {
+		return err
+	}
+
 	query := `
-		INSERT INTO users (id, id_citizen, email, password, name, role, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO {users} (id, id_citizen, email, password, name, role, status, metadata, token_version, created_at, updated_at, user_type,
+			must_change_password, version, password_changed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
-		user.ID,
-		user.IDCitizen,
-		user.Email,
-		user.Password,
-		user.Name,
-		user.Role.String(),
-		user.CreatedAt,
-		user.UpdatedAt,
-	)
+	err = r.retrier.DoNonIdempotent(ctx, "users.create", func(ctx context.Context) error {
+		_, err := r.db.ExecContext(ctx, query,
+			user.ID,
+			user.IDCitizen,
+			user.Email,
+			user.Password,
+			user.Name,
+			user.Role.String(),
+			user.Status.String(),
+			metadata,
+			user.TokenVersion,
+			user.CreatedAt,
+			user.UpdatedAt,
+			userType(user),
+			user.MustChangePassword,
+			user.Version,
+			user.PasswordChangedAt,
+		)
+		return err
+	})
 
 	if err != nil {
 		// Map Postgres unique constraint violation to domain error
@@ -71,24 +93,46 @@ func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 //
 //nolint:dupl // Similar to GetByEmail but queries by ID instead of email
 func (r *UserRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	// IDs that are neither UUIDs nor ULIDs can't exist, and UUIDs in uppercase match the stored ones
+	id, err := domain.ParseID(id)
+	if err != nil {
+		return nil, domainerrors.ErrUserNotFound
+	}
+
 	query := `
-		SELECT id, id_citizen, email, password, name, role, created_at, updated_at
-		FROM users
+		SELECT id, id_citizen, email, password, name, role, status, metadata, token_version, created_at, updated_at,
+			pending_email, pending_email_token_hash, pending_email_expires_at, user_type, must_change_password, version,
+			last_login_at, password_changed_at
+		FROM {users}
 		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	user := &domain.User{}
-	var roleStr string
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&user.ID,
-		&user.IDCitizen,
-		&user.Email,
-		&user.Password,
-		&user.Name,
-		&roleStr,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	var roleStr, statusStr, typeStr string
+	var metadata []byte
+	err = r.retrier.Do(ctx, "users.get_by_id", func(ctx context.Context) error {
+		return r.db.QueryRowContext(ctx, query, id).Scan(
+			&user.ID,
+			&user.IDCitizen,
+			&user.Email,
+			&user.Password,
+			&user.Name,
+			&roleStr,
+			&statusStr,
+			&metadata,
+			&user.TokenVersion,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+			&user.PendingEmail,
+			&user.PendingEmailTokenHash,
+			&user.PendingEmailExpiresAt,
+			&typeStr,
+			&user.MustChangePassword,
+			&user.Version,
+			&user.LastLoginAt,
+			&user.PasswordChangedAt,
+		)
+	})
 
 	if err == sql.ErrNoRows {
 		return nil, domainerrors.ErrUserNotFound
@@ -100,31 +144,57 @@ func (r *UserRepository) GetByID(ctx context.Context, id string) (*domain.User,
 
 	role, _ := domain.ParseRole(roleStr)
 	user.Role = role
+	status, _ := domain.ParseUserStatus(statusStr)
+	user.Status = status
+	user.Type = parseUserType(typeStr)
+	if user.Metadata, err = decodeUserMetadata(metadata); err != nil {
+		r.logger.Error("failed to decode user metadata", zap.Error(err), zap.String("user_id", user.ID))
+		return nil, err
+	}
 	return user, nil
 }
 
-// GetByEmail retrieves a user by their email
+// GetByEmail retrieves a user by their email, case-insensitively. Among the case duplicates left by earlier
+// releases, the user with the exact email wins, then the oldest.
 //
 //nolint:dupl // Similar to GetByID but queries by email instead of ID
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	query := `
-		SELECT id, id_citizen, email, password, name, role, created_at, updated_at
-		FROM users
-		WHERE email = $1 AND deleted_at IS NULL
+		SELECT id, id_citizen, email, password, name, role, status, metadata, token_version, created_at, updated_at,
+			pending_email, pending_email_token_hash, pending_email_expires_at, user_type, must_change_password, version,
+			last_login_at, password_changed_at
+		FROM {users}
+		WHERE lower(email) = lower($1) AND deleted_at IS NULL
+		ORDER BY email = $1 DESC, created_at
+		LIMIT 1
 	`
 
 	user := &domain.User{}
-	var roleStr string
-	err := r.db.QueryRowContext(ctx, query, email).Scan(
-		&user.ID,
-		&user.IDCitizen,
-		&user.Email,
-		&user.Password,
-		&user.Name,
-		&roleStr,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	var roleStr, statusStr, typeStr string
+	var metadata []byte
+	err := r.retrier.Do(ctx, "users.get_by_email", func(ctx context.Context) error {
+		return r.db.QueryRowContext(ctx, query, email).Scan(
+			&user.ID,
+			&user.IDCitizen,
+			&user.Email,
+			&user.Password,
+			&user.Name,
+			&roleStr,
+			&statusStr,
+			&metadata,
+			&user.TokenVersion,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+			&user.PendingEmail,
+			&user.PendingEmailTokenHash,
+			&user.PendingEmailExpiresAt,
+			&typeStr,
+			&user.MustChangePassword,
+			&user.Version,
+			&user.LastLoginAt,
+			&user.PasswordChangedAt,
+		)
+	})
 
 	if err == sql.ErrNoRows {
 		return nil, domainerrors.ErrUserNotFound
@@ -136,6 +206,13 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.
 
 	role, _ := domain.ParseRole(roleStr)
 	user.Role = role
+	status, _ := domain.ParseUserStatus(statusStr)
+	user.Status = status
+	user.Type = parseUserType(typeStr)
+	if user.Metadata, err = decodeUserMetadata(metadata); err != nil {
+		r.logger.Error("failed to decode user metadata", zap.Error(err), zap.String("user_id", user.ID))
+		return nil, err
+	}
 	return user, nil
 }
 
@@ -144,23 +221,39 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.
 //nolint:dupl // Similar to GetByID and GetByEmail but queries by id_citizen
 func (r *UserRepository) GetByIDCitizen(ctx context.Context, idCitizen int) (*domain.User, error) {
 	query := `
-		SELECT id, id_citizen, email, password, name, role, created_at, updated_at
-		FROM users
+		SELECT id, id_citizen, email, password, name, role, status, metadata, token_version, created_at, updated_at,
+			pending_email, pending_email_token_hash, pending_email_expires_at, user_type, must_change_password, version,
+			last_login_at, password_changed_at
+		FROM {users}
 		WHERE id_citizen = $1 AND deleted_at IS NULL
 	`
 
 	user := &domain.User{}
-	var roleStr string
-	err := r.db.QueryRowContext(ctx, query, idCitizen).Scan(
-		&user.ID,
-		&user.IDCitizen,
-		&user.Email,
-		&user.Password,
-		&user.Name,
-		&roleStr,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	var roleStr, statusStr, typeStr string
+	var metadata []byte
+	err := r.retrier.Do(ctx, "users.get_by_id_citizen", func(ctx context.Context) error {
+		return r.db.QueryRowContext(ctx, query, idCitizen).Scan(
+			&user.ID,
+			&user.IDCitizen,
+			&user.Email,
+			&user.Password,
+			&user.Name,
+			&roleStr,
+			&statusStr,
+			&metadata,
+			&user.TokenVersion,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+			&user.PendingEmail,
+			&user.PendingEmailTokenHash,
+			&user.PendingEmailExpiresAt,
+			&typeStr,
+			&user.MustChangePassword,
+			&user.Version,
+			&user.LastLoginAt,
+			&user.PasswordChangedAt,
+		)
+	})
 
 	if err == sql.ErrNoRows {
 		return nil, domainerrors.ErrUserNotFound
@@ -172,31 +265,126 @@ func (r *UserRepository) GetByIDCitizen(ctx context.Context, idCitizen int) (*do
 
 	role, _ := domain.ParseRole(roleStr)
 	user.Role = role
+	status, _ := domain.ParseUserStatus(statusStr)
+	user.Status = status
+	user.Type = parseUserType(typeStr)
+	if user.Metadata, err = decodeUserMetadata(metadata); err != nil {
+		r.logger.Error("failed to decode user metadata", zap.Error(err), zap.String("user_id", user.ID))
+		return nil, err
+	}
 
 	return user, nil
 }
 
+// GetByPendingEmailToken retrieves the user with a pending email change confirmed by the token hash,
+// expired or not
+//
+//nolint:dupl // Similar to GetByID but queries by the pending email change token
+func (r *UserRepository) GetByPendingEmailToken(ctx context.Context, tokenHash string) (*domain.User, error) {
+	query := `
+		SELECT id, id_citizen, email, password, name, role, status, metadata, token_version, created_at, updated_at,
+			pending_email, pending_email_token_hash, pending_email_expires_at, user_type, must_change_password, version,
+			last_login_at, password_changed_at
+		FROM {users}
+		WHERE pending_email_token_hash = $1 AND pending_email_token_hash <> '' AND deleted_at IS NULL
+	`
+
+	user := &domain.User{}
+	var roleStr, statusStr, typeStr string
+	var metadata []byte
+	err := r.retrier.Do(ctx, "users.get_by_pending_email_token", func(ctx context.Context) error {
+		return r.db.QueryRowContext(ctx, query, tokenHash).Scan(
+			&user.ID,
+			&user.IDCitizen,
+			&user.Email,
+			&user.Password,
+			&user.Name,
+			&roleStr,
+			&statusStr,
+			&metadata,
+			&user.TokenVersion,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+			&user.PendingEmail,
+			&user.PendingEmailTokenHash,
+			&user.PendingEmailExpiresAt,
+			&typeStr,
+			&user.MustChangePassword,
+			&user.Version,
+			&user.LastLoginAt,
+			&user.PasswordChangedAt,
+		)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, domainerrors.ErrUserNotFound
+	}
+	if err != nil {
+		r.logger.Error("failed to get user by pending email token", zap.Error(err))
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	role, _ := domain.ParseRole(roleStr)
+	user.Role = role
+	status, _ := domain.ParseUserStatus(statusStr)
+	user.Status = status
+	user.Type = parseUserType(typeStr)
+	if user.Metadata, err = decodeUserMetadata(metadata); err != nil {
+		r.logger.Error("failed to decode user metadata", zap.Error(err), zap.String("user_id", user.ID))
+		return nil, err
+	}
+	return user, nil
+}
+
 // Update updates an existing user
 func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
 	user.UpdatedAt = time.Now()
 
+	metadata, err := encodeUserMetadata(user.Metadata)
+	if err != nil {
+		return err
+	}
+
+	// The version the user was read with must still be the stored one, so concurrent read-modify-write
+	// cycles can't overwrite each other's changes
 	query := `
-		UPDATE users
-		SET id_citizen = $2, email = $3, password = $4, name = $5, role = $6, updated_at = $7
-		WHERE id = $1 AND deleted_at IS NULL
+		UPDATE {users}
+		SET id_citizen = $2, email = $3, password = $4, name = $5, role = $6, status = $7, metadata = $8, updated_at = $9,
+			token_version = GREATEST(token_version, $10),
+			pending_email = $11, pending_email_token_hash = $12, pending_email_expires_at = $13, must_change_password = $14,
+			password_changed_at = $16, version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL AND version = $15
 	`
 
-	result, err := r.db.ExecContext(ctx, query,
-		user.ID,
-		user.IDCitizen,
-		user.Email,
-		user.Password,
-		user.Name,
-		user.Role.String(),
-		user.UpdatedAt,
-	)
+	var result sql.Result
+	err = r.retrier.DoNonIdempotent(ctx, "users.update", func(ctx context.Context) (err error) {
+		result, err = r.db.ExecContext(ctx, query,
+			user.ID,
+			user.IDCitizen,
+			user.Email,
+			user.Password,
+			user.Name,
+			user.Role.String(),
+			user.Status.String(),
+			metadata,
+			user.UpdatedAt,
+			user.TokenVersion,
+			user.PendingEmail,
+			user.PendingEmailTokenHash,
+			user.PendingEmailExpiresAt,
+			user.MustChangePassword,
+			user.Version,
+			user.PasswordChangedAt,
+		)
+		return err
+	})
 
 	if err != nil {
+		// Map Postgres unique constraint violation to domain error (e.g. the email belongs to another user)
+		if pqErr, ok := err.(*pq.Error); ok && string(pqErr.Code) == "23505" {
+			r.logger.Warn("unique constraint violation while updating user", zap.Error(err), zap.String("user_id", user.ID))
+			return domainerrors.ErrUserAlreadyExists
+		}
 		r.logger.Error("failed to update user", zap.Error(err), zap.String("user_id", user.ID))
 		return fmt.Errorf("failed to update user: %w", err)
 	}
@@ -207,22 +395,52 @@ func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
 	}
 
 	if rowsAffected == 0 {
-		return domainerrors.ErrUserNotFound
+		// Either the user doesn't exist or it was modified since it was read
+		if _, err := r.GetByID(ctx, user.ID); err != nil {
+			return err
+		}
+		r.logger.Warn("user modified concurrently", zap.String("user_id", user.ID))
+		return domainerrors.ErrVersionConflict
 	}
 
+	user.Version++
 	r.logger.Info("user updated successfully", zap.String("user_id", user.ID))
 	return nil
 }
 
+// RecordLogin records the time of a successful login of a user. It is bookkeeping rather than a change of
+// the user, so neither the version nor updated_at change and concurrent updates don't conflict with it.
+func (r *UserRepository) RecordLogin(ctx context.Context, id string, at time.Time) error {
+	query := `
+		UPDATE {users}
+		SET last_login_at = $2
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	err := r.retrier.Do(ctx, "users.record_login", func(ctx context.Context) error {
+		_, err := r.db.ExecContext(ctx, query, id, at)
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to record login", zap.Error(err), zap.String("user_id", id))
+		return fmt.Errorf("failed to record login: %w", err)
+	}
+	return nil
+}
+
 // Delete performs a soft delete of a user
 func (r *UserRepository) Delete(ctx context.Context, id string) error {
 	query := `
-		UPDATE users
+		UPDATE {users}
 		SET deleted_at = $2
 		WHERE id = $1 AND deleted_at IS NULL
 	`
 
-	result, err := r.db.ExecContext(ctx, query, id, time.Now())
+	var result sql.Result
+	err := r.retrier.DoNonIdempotent(ctx, "users.delete", func(ctx context.Context) (err error) {
+		result, err = r.db.ExecContext(ctx, query, id, time.Now())
+		return err
+	})
 	if err != nil {
 		r.logger.Error("failed to delete user", zap.Error(err), zap.String("user_id", id))
 		return fmt.Errorf("failed to delete user: %w", err)
@@ -241,17 +459,19 @@ func (r *UserRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-// Exists verifies if a user exists by email
+// Exists verifies if a user exists by email, case-insensitively
 func (r *UserRepository) Exists(ctx context.Context, email string) (bool, error) {
 	query := `
 		SELECT EXISTS(
-			SELECT 1 FROM users
-			WHERE email = $1 AND deleted_at IS NULL
+			SELECT 1 FROM {users}
+			WHERE lower(email) = lower($1) AND deleted_at IS NULL
 		)
 	`
 
 	var exists bool
-	err := r.db.QueryRowContext(ctx, query, email).Scan(&exists)
+	err := r.retrier.Do(ctx, "users.exists", func(ctx context.Context) error {
+		return r.db.QueryRowContext(ctx, query, email).Scan(&exists)
+	})
 	if err != nil {
 		r.logger.Error("failed to check user existence", zap.Error(err), zap.String("email", email))
 		return false, fmt.Errorf("failed to check user existence: %w", err)
@@ -260,21 +480,188 @@ func (r *UserRepository) Exists(ctx context.Context, email string) (bool, error)
 	return exists, nil
 }
 
-// NewDB creates a new connection to PostgreSQL
-func NewDB(connectionString string, logger *zap.Logger) (*sql.DB, error) {
-	db, err := sql.Open("postgres", connectionString)
+// ListByStatus retrieves a page of the users in a status, oldest first
+func (r *UserRepository) ListByStatus(ctx context.Context, status domain.UserStatus, limit, offset int) ([]*domain.User, error) {
+	query := `
+		SELECT id, id_citizen, email, name, role, status, created_at, updated_at, user_type, version
+		FROM {users}
+		WHERE status = $1 AND deleted_at IS NULL
+		ORDER BY created_at, id
+		LIMIT $2 OFFSET $3
+	`
+
+	var users []*domain.User
+	err := r.retrier.Do(ctx, "users.list_by_status", func(ctx context.Context) error {
+		rows, err := r.db.QueryContext(ctx, query, status.String(), limit, offset)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		users = nil
+		for rows.Next() {
+			user := &domain.User{}
+			var roleStr, statusStr, typeStr string
+			if err := rows.Scan(
+				&user.ID,
+				&user.IDCitizen,
+				&user.Email,
+				&user.Name,
+				&roleStr,
+				&statusStr,
+				&user.CreatedAt,
+				&user.UpdatedAt,
+				&typeStr,
+				&user.Version,
+			); err != nil {
+				return err
+			}
+			user.Role, _ = domain.ParseRole(roleStr)
+			user.Status, _ = domain.ParseUserStatus(statusStr)
+			user.Type = parseUserType(typeStr)
+			users = append(users, user)
+		}
+		return rows.Err()
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to open database: %w", err)
+		r.logger.Error("failed to list users by status", zap.Error(err), zap.String("status", status.String()))
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	return users, nil
+}
+
+// StreamUsers calls fn for every user matching the filter, oldest first.
+// Only opening the query is retried, a failure mid-stream is returned to the caller.
+func (r *UserRepository) StreamUsers(ctx context.Context, filter domain.UserExportFilter, fn func(*domain.User) error) error {
+	conditions := []string{"deleted_at IS NULL"}
+	var args []interface{}
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.Role != "" {
+		addCondition("role = $%d", filter.Role.String())
+	}
+	if filter.Status != "" {
+		addCondition("status = $%d", filter.Status.String())
+	}
+	if !filter.CreatedAfter.IsZero() {
+		addCondition("created_at >= $%d", filter.CreatedAfter)
+	}
+	if !filter.CreatedBefore.IsZero() {
+		addCondition("created_at < $%d", filter.CreatedBefore)
+	}
+
+	query := `
+		SELECT id, id_citizen, email, name, role, status, created_at, updated_at, user_type
+		FROM {users}
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY created_at, id
+	`
+
+	var rows *sql.Rows
+	err := r.retrier.DoStream(ctx, "users.stream", func(ctx context.Context) (err error) {
+		rows, err = r.db.QueryContext(ctx, query, args...)
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to query users", zap.Error(err))
+		return fmt.Errorf("failed to query users: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			r.logger.Error("failed to close rows", zap.Error(closeErr))
+		}
+	}()
+
+	for rows.Next() {
+		user := &domain.User{}
+		var roleStr, statusStr, typeStr string
+		if err := rows.Scan(
+			&user.ID,
+			&user.IDCitizen,
+			&user.Email,
+			&user.Name,
+			&roleStr,
+			&statusStr,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+			&typeStr,
+		); err != nil {
+			r.logger.Error("failed to scan user", zap.Error(err))
+			return fmt.Errorf("failed to scan user: %w", err)
+		}
+		user.Role, _ = domain.ParseRole(roleStr)
+		user.Status, _ = domain.ParseUserStatus(statusStr)
+		user.Type = parseUserType(typeStr)
+
+		if err := fn(user); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error("error iterating users", zap.Error(err))
+		return fmt.Errorf("error iterating users: %w", err)
+	}
+
+	return nil
+}
+
+// encodeUserMetadata returns the JSONB value of the metadata of a user
+func encodeUserMetadata(metadata domain.UserMetadata) ([]byte, error) {
+	if metadata == nil {
+		metadata = domain.UserMetadata{}
+	}
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal user metadata: %w", err)
+	}
+	return encoded, nil
+}
+
+// decodeUserMetadata parses the JSONB metadata of a user, an empty object decodes to nil
+func decodeUserMetadata(raw []byte) (domain.UserMetadata, error) {
+	var metadata domain.UserMetadata
+	if err := json.Unmarshal(raw, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal user metadata: %w", err)
+	}
+	if len(metadata) == 0 {
+		return nil, nil
 	}
+	return metadata, nil
+}
 
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(5 * time.Minute)
+// userType returns the stored type of a user, users created without one are people
+func userType(user *domain.User) string {
+	if user.Type == "" {
+		return domain.UserTypeHuman.String()
+	}
+	return user.Type.String()
+}
+
+// parseUserType parses the stored type of a user, defaulting to a person
+func parseUserType(s string) domain.UserType {
+	userType, err := domain.ParseUserType(s)
+	if err != nil {
+		return domain.UserTypeHuman
+	}
+	return userType
+}
+
+// NewDB creates a new connection to PostgreSQL
+func NewDB(connectionString string, naming Naming, pool PoolConfig, logger *zap.Logger) (*DB, error) {
+	db, err := OpenDB(connectionString, naming, pool)
+	if err != nil {
+		return nil, err
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
@@ -282,23 +669,45 @@ func NewDB(connectionString string, logger *zap.Logger) (*sql.DB, error) {
 	return db, nil
 }
 
+// OpenDB creates the connection pool without checking connectivity,
+// which is left to the caller (e.g. the startup dependency checks)
+func OpenDB(connectionString string, naming Naming, pool PoolConfig) (*DB, error) {
+	db, err := sql.Open("postgres", connectionString)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	pool.apply(db)
+
+	return NewNamedDB(db, naming), nil
+}
+
 // InitSchema initializes the database schema
-func InitSchema(db *sql.DB) error {
+func InitSchema(db *DB) error {
+	// The schema must exist before the tables can be created in it
+	if db.naming.Schema != "" {
+		if _, err := db.Exec("CREATE SCHEMA IF NOT EXISTS " + pq.QuoteIdentifier(db.naming.Schema)); err != nil {
+			return err
+		}
+	}
+
 	// First, create tables
 	createTables := `
-		CREATE TABLE IF NOT EXISTS users (
+		CREATE TABLE IF NOT EXISTS {users} (
 			id VARCHAR(36) PRIMARY KEY,
 			id_citizen INTEGER UNIQUE NOT NULL,
 			email VARCHAR(255) UNIQUE NOT NULL,
 			password VARCHAR(255) NOT NULL,
 			name VARCHAR(255) NOT NULL,
 			role VARCHAR(50) NOT NULL DEFAULT 'USER',
+			status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE',
+			metadata JSONB NOT NULL DEFAULT '{}',
 			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
 			deleted_at TIMESTAMP
 		);
 
-		CREATE TABLE IF NOT EXISTS oauth_clients (
+		CREATE TABLE IF NOT EXISTS {oauth_clients} (
 			id VARCHAR(36) PRIMARY KEY,
 			client_id VARCHAR(255) UNIQUE NOT NULL,
 			client_secret VARCHAR(255) NOT NULL,
@@ -309,22 +718,178 @@ func InitSchema(db *sql.DB) error {
 			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
 		);
+
+		CREATE TABLE IF NOT EXISTS {scopes} (
+			name VARCHAR(100) PRIMARY KEY,
+			description TEXT NOT NULL DEFAULT '',
+			system BOOLEAN NOT NULL DEFAULT false,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS {user_consents} (
+			user_id VARCHAR(36) NOT NULL REFERENCES {users}(id),
+			client_id VARCHAR(255) NOT NULL,
+			scopes TEXT[] NOT NULL DEFAULT '{}',
+			granted_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (user_id, client_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS {user_notification_preferences} (
+			user_id VARCHAR(36) PRIMARY KEY REFERENCES {users}(id),
+			new_device BOOLEAN NOT NULL DEFAULT true,
+			password_change BOOLEAN NOT NULL DEFAULT true,
+			login_alert BOOLEAN NOT NULL DEFAULT true,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS {user_phone_numbers} (
+			user_id VARCHAR(36) PRIMARY KEY REFERENCES {users}(id),
+			phone_number VARCHAR(16) NOT NULL,
+			verified_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS {user_emails} (
+			id VARCHAR(36) PRIMARY KEY,
+			user_id VARCHAR(36) NOT NULL REFERENCES {users}(id),
+			email VARCHAR(255) NOT NULL,
+			verified_at TIMESTAMP,
+			code_hash VARCHAR(64) NOT NULL DEFAULT '',
+			code_expires_at TIMESTAMP,
+			code_attempts INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (user_id, email)
+		);
+
+		CREATE TABLE IF NOT EXISTS {user_avatars} (
+			user_id VARCHAR(36) PRIMARY KEY REFERENCES {users}(id),
+			object_key VARCHAR(255) NOT NULL,
+			content_type VARCHAR(50) NOT NULL,
+			size_bytes BIGINT NOT NULL,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS {audit_log} (
+			id VARCHAR(36) PRIMARY KEY,
+			action VARCHAR(100) NOT NULL,
+			actor VARCHAR(255) NOT NULL,
+			target_id VARCHAR(255) NOT NULL,
+			details JSONB NOT NULL DEFAULT '{}',
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS {outbox_messages} (
+			id VARCHAR(36) PRIMARY KEY,
+			queue VARCHAR(255) NOT NULL,
+			payload BYTEA NOT NULL,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS {issuance_quotas} (
+			subject_type VARCHAR(20) NOT NULL,
+			subject_id VARCHAR(255) NOT NULL,
+			max_tokens_per_hour INTEGER NOT NULL DEFAULT 0,
+			max_active_sessions INTEGER NOT NULL DEFAULT 0,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (subject_type, subject_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS {oauth_client_usage} (
+			client_id VARCHAR(255) PRIMARY KEY REFERENCES {oauth_clients}(client_id) ON DELETE CASCADE,
+			last_used_at TIMESTAMP,
+			tokens_issued BIGINT NOT NULL DEFAULT 0,
+			recent_errors BIGINT NOT NULL DEFAULT 0,
+			recent_errors_since TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS {refresh_tokens} (
+			token_hash VARCHAR(64) PRIMARY KEY,
+			id_citizen INTEGER NOT NULL,
+			user_id VARCHAR(36) NOT NULL DEFAULT '',
+			data JSONB NOT NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			expires_at TIMESTAMP NOT NULL
+		);
 	`
 
 	if _, err := db.Exec(createTables); err != nil {
 		return err
 	}
 
+	// Add columns introduced after the first release to existing tables
+	alterTables := `
+		ALTER TABLE {users} ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE';
+		ALTER TABLE {users} ADD COLUMN IF NOT EXISTS metadata JSONB NOT NULL DEFAULT '{}';
+		ALTER TABLE {audit_log} ADD COLUMN IF NOT EXISTS exported_at TIMESTAMP;
+		ALTER TABLE {audit_log} ADD COLUMN IF NOT EXISTS export_next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+		ALTER TABLE {oauth_clients} ADD COLUMN IF NOT EXISTS require_signed_requests BOOLEAN NOT NULL DEFAULT false;
+		ALTER TABLE {oauth_clients} ADD COLUMN IF NOT EXISTS request_signing_key VARCHAR(64) NOT NULL DEFAULT '';
+		ALTER TABLE {oauth_clients} ADD COLUMN IF NOT EXISTS token_profile VARCHAR(20) NOT NULL DEFAULT 'standard';
+		ALTER TABLE {oauth_clients} ADD COLUMN IF NOT EXISTS grant_types TEXT[] NOT NULL DEFAULT '{client_credentials,urn:ietf:params:oauth:grant-type:device_code}';
+		ALTER TABLE {oauth_clients} ADD COLUMN IF NOT EXISTS redirect_uris TEXT[] NOT NULL DEFAULT '{}';
+		ALTER TABLE {users} ADD COLUMN IF NOT EXISTS token_version INTEGER NOT NULL DEFAULT 0;
+		ALTER TABLE {users} ADD COLUMN IF NOT EXISTS pending_email VARCHAR(255) NOT NULL DEFAULT '';
+		ALTER TABLE {users} ADD COLUMN IF NOT EXISTS pending_email_token_hash VARCHAR(64) NOT NULL DEFAULT '';
+		ALTER TABLE {users} ADD COLUMN IF NOT EXISTS pending_email_expires_at TIMESTAMP;
+		ALTER TABLE {users} ADD COLUMN IF NOT EXISTS user_type VARCHAR(20) NOT NULL DEFAULT 'HUMAN';
+		ALTER TABLE {users} ADD COLUMN IF NOT EXISTS must_change_password BOOLEAN NOT NULL DEFAULT false;
+		ALTER TABLE {users} ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+		ALTER TABLE {oauth_clients} ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+		ALTER TABLE {users} ADD COLUMN IF NOT EXISTS last_login_at TIMESTAMP;
+		ALTER TABLE {users} ADD COLUMN IF NOT EXISTS password_changed_at TIMESTAMP;
+		ALTER TABLE {oauth_clients} ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+		ALTER TABLE {oauth_clients} ADD COLUMN IF NOT EXISTS secret_expires_at TIMESTAMP;
+	`
+
+	if _, err := db.Exec(alterTables); err != nil {
+		return err
+	}
+
 	// Then, create indexes
 	createIndexes := `
-		CREATE INDEX IF NOT EXISTS idx_users_id_citizen ON users(id_citizen);
-		CREATE INDEX IF NOT EXISTS idx_users_email ON users(email) WHERE deleted_at IS NULL;
-		CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users(deleted_at);
-		CREATE INDEX IF NOT EXISTS idx_users_role ON users(role);
-		CREATE INDEX IF NOT EXISTS idx_oauth_clients_client_id ON oauth_clients(client_id);
-		CREATE INDEX IF NOT EXISTS idx_oauth_clients_active ON oauth_clients(active);
+		CREATE INDEX IF NOT EXISTS idx_{users}_id_citizen ON {users}(id_citizen);
+		CREATE INDEX IF NOT EXISTS idx_{users}_email ON {users}(email) WHERE deleted_at IS NULL;
+		CREATE INDEX IF NOT EXISTS idx_{users}_deleted_at ON {users}(deleted_at);
+		CREATE INDEX IF NOT EXISTS idx_{users}_role ON {users}(role);
+		CREATE INDEX IF NOT EXISTS idx_{users}_pending_email_token_hash ON {users}(pending_email_token_hash) WHERE pending_email_token_hash <> '';
+		CREATE INDEX IF NOT EXISTS idx_{oauth_clients}_client_id ON {oauth_clients}(client_id);
+		CREATE INDEX IF NOT EXISTS idx_{oauth_clients}_active ON {oauth_clients}(active);
+		CREATE INDEX IF NOT EXISTS idx_{user_consents}_client_id ON {user_consents}(client_id);
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_{user_phone_numbers}_phone_number ON {user_phone_numbers}(phone_number);
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_{user_emails}_verified_email ON {user_emails}(email) WHERE verified_at IS NOT NULL;
+		CREATE INDEX IF NOT EXISTS idx_{user_avatars}_object_key ON {user_avatars}(object_key);
+		CREATE INDEX IF NOT EXISTS idx_{audit_log}_target_id ON {audit_log}(target_id);
+		CREATE INDEX IF NOT EXISTS idx_{audit_log}_created_at ON {audit_log}(created_at);
+		CREATE INDEX IF NOT EXISTS idx_{audit_log}_unexported ON {audit_log}(export_next_attempt_at) WHERE exported_at IS NULL;
+		CREATE INDEX IF NOT EXISTS idx_{outbox_messages}_next_attempt_at ON {outbox_messages}(next_attempt_at);
+		CREATE INDEX IF NOT EXISTS idx_{refresh_tokens}_id_citizen ON {refresh_tokens}(id_citizen);
+		CREATE INDEX IF NOT EXISTS idx_{refresh_tokens}_user_id ON {refresh_tokens}(user_id) WHERE user_id <> '';
+		CREATE INDEX IF NOT EXISTS idx_{refresh_tokens}_expires_at ON {refresh_tokens}(expires_at);
+	`
+
+	if _, err := db.Exec(createIndexes); err != nil {
+		return err
+	}
+
+	// Finally, seed the built-in scopes and register any scope already assigned to a client
+	seedScopes := `
+		INSERT INTO {scopes} (name, description, system) VALUES
+			('read', 'Read access to resources', true),
+			('write', 'Write access to resources', true)
+		ON CONFLICT (name) DO NOTHING;
+
+		INSERT INTO {scopes} (name)
+		SELECT DISTINCT unnest(scopes) FROM {oauth_clients}
+		ON CONFLICT (name) DO NOTHING;
 	`
 
-	_, err := db.Exec(createIndexes)
+	_, err := db.Exec(seedScopes)
 	return err
 }
@@ -0,0 +1,32 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
+)
+
+// observe runs one attempt of an operation, recording its duration and logging it when it is slower than the
+// slow query threshold. Only the operation name is logged, never the statement arguments, which may hold
+// personal data. Streamed queries are measured until their rows are ready to be read.
+func (r *Retrier) observe(ctx context.Context, operation string, fn func(ctx context.Context) error) error {
+	start := time.Now()
+	err := fn(ctx)
+	elapsed := time.Since(start)
+
+	metrics.ObserveDBQueryDuration(operation, elapsed)
+	if r.logger.Core().Enabled(zap.DebugLevel) {
+		r.logger.Debug("database query", zap.String("operation", operation), zap.Duration("duration", elapsed), zap.Bool("failed", err != nil))
+	}
+	if r.policy.SlowQueryThreshold > 0 && elapsed >= r.policy.SlowQueryThreshold {
+		r.logger.Warn("slow database query",
+			zap.String("operation", operation),
+			zap.Duration("duration", elapsed),
+			zap.Duration("threshold", r.policy.SlowQueryThreshold),
+			zap.Bool("failed", err != nil))
+	}
+	return err
+}
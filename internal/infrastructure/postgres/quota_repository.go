@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// QuotaRepository is the PostgreSQL implementation of the issuance quota repository
+type QuotaRepository struct {
+	db      *DB
+	retrier *Retrier
+	logger  *zap.Logger
+}
+
+// NewQuotaRepository creates a new instance of QuotaRepository
+func NewQuotaRepository(db *DB, retrier *Retrier, logger *zap.Logger) *QuotaRepository {
+	return &QuotaRepository{
+		db:      db,
+		retrier: retrier,
+		logger:  logger,
+	}
+}
+
+// Get retrieves the quota override of a subject
+func (r *QuotaRepository) Get(ctx context.Context, subjectType, subjectID string) (*domain.IssuanceQuota, error) {
+	query := `
+		SELECT subject_type, subject_id, max_tokens_per_hour, max_active_sessions, updated_at
+		FROM {issuance_quotas}
+		WHERE subject_type = $1 AND subject_id = $2
+	`
+
+	quota := &domain.IssuanceQuota{}
+	err := r.retrier.Do(ctx, "issuance_quotas.get", func(ctx context.Context) error {
+		return r.db.QueryRowContext(ctx, query, subjectType, subjectID).Scan(
+			&quota.SubjectType,
+			&quota.SubjectID,
+			&quota.MaxTokensPerHour,
+			&quota.MaxActiveSessions,
+			&quota.UpdatedAt,
+		)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, domainerrors.ErrQuotaNotFound
+	}
+	if err != nil {
+		r.logger.Error("failed to get quota", zap.Error(err), zap.String("subject_type", subjectType), zap.String("subject_id", subjectID))
+		return nil, fmt.Errorf("failed to get quota: %w", err)
+	}
+
+	return quota, nil
+}
+
+// Upsert creates or replaces the quota override of a subject
+func (r *QuotaRepository) Upsert(ctx context.Context, quota *domain.IssuanceQuota) error {
+	quota.UpdatedAt = time.Now()
+
+	query := `
+		INSERT INTO {issuance_quotas} (subject_type, subject_id, max_tokens_per_hour, max_active_sessions, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (subject_type, subject_id) DO UPDATE
+		SET max_tokens_per_hour = EXCLUDED.max_tokens_per_hour,
+			max_active_sessions = EXCLUDED.max_active_sessions,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	err := r.retrier.Do(ctx, "issuance_quotas.upsert", func(ctx context.Context) error {
+		_, err := r.db.ExecContext(ctx, query,
+			quota.SubjectType,
+			quota.SubjectID,
+			quota.MaxTokensPerHour,
+			quota.MaxActiveSessions,
+			quota.UpdatedAt,
+		)
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to upsert quota", zap.Error(err), zap.String("subject_type", quota.SubjectType), zap.String("subject_id", quota.SubjectID))
+		return fmt.Errorf("failed to upsert quota: %w", err)
+	}
+
+	r.logger.Info("quota updated successfully", zap.String("subject_type", quota.SubjectType), zap.String("subject_id", quota.SubjectID))
+	return nil
+}
+
+// Delete removes the quota override of a subject
+func (r *QuotaRepository) Delete(ctx context.Context, subjectType, subjectID string) error {
+	query := `DELETE FROM {issuance_quotas} WHERE subject_type = $1 AND subject_id = $2`
+
+	var result sql.Result
+	err := r.retrier.Do(ctx, "issuance_quotas.delete", func(ctx context.Context) (err error) {
+		result, err = r.db.ExecContext(ctx, query, subjectType, subjectID)
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to delete quota", zap.Error(err), zap.String("subject_type", subjectType), zap.String("subject_id", subjectID))
+		return fmt.Errorf("failed to delete quota: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domainerrors.ErrQuotaNotFound
+	}
+
+	r.logger.Info("quota deleted successfully", zap.String("subject_type", subjectType), zap.String("subject_id", subjectID))
+	return nil
+}
+
+// List retrieves every quota override
+func (r *QuotaRepository) List(ctx context.Context) ([]*domain.IssuanceQuota, error) {
+	query := `
+		SELECT subject_type, subject_id, max_tokens_per_hour, max_active_sessions, updated_at
+		FROM {issuance_quotas}
+		ORDER BY subject_type, subject_id
+	`
+
+	var quotas []*domain.IssuanceQuota
+	err := r.retrier.Do(ctx, "issuance_quotas.list", func(ctx context.Context) error {
+		rows, err := r.db.QueryContext(ctx, query)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		quotas = nil
+		for rows.Next() {
+			quota := &domain.IssuanceQuota{}
+			if err := rows.Scan(
+				&quota.SubjectType,
+				&quota.SubjectID,
+				&quota.MaxTokensPerHour,
+				&quota.MaxActiveSessions,
+				&quota.UpdatedAt,
+			); err != nil {
+				return err
+			}
+			quotas = append(quotas, quota)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		r.logger.Error("failed to list quotas", zap.Error(err))
+		return nil, fmt.Errorf("failed to list quotas: %w", err)
+	}
+
+	return quotas, nil
+}
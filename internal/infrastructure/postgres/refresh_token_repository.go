@@ -0,0 +1,191 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// RefreshTokenRepository is the PostgreSQL implementation of the refresh token store
+type RefreshTokenRepository struct {
+	db      *DB
+	retrier *Retrier
+	logger  *zap.Logger
+}
+
+// NewRefreshTokenRepository creates a new instance of RefreshTokenRepository
+func NewRefreshTokenRepository(db *DB, retrier *Retrier, logger *zap.Logger) *RefreshTokenRepository {
+	return &RefreshTokenRepository{
+		db:      db,
+		retrier: retrier,
+		logger:  logger,
+	}
+}
+
+// Store stores the data of a refresh token, replacing the data stored for the hash so a retried insert
+// is harmless
+func (r *RefreshTokenRepository) Store(ctx context.Context, tokenHash string, data *domain.RefreshTokenData, expiresAt time.Time) error {
+	query := `
+		INSERT INTO {refresh_tokens} (token_hash, id_citizen, user_id, data, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (token_hash) DO UPDATE
+		SET id_citizen = EXCLUDED.id_citizen, user_id = EXCLUDED.user_id, data = EXCLUDED.data, expires_at = EXCLUDED.expires_at
+	`
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal token data: %w", err)
+	}
+
+	err = r.retrier.Do(ctx, "refresh_tokens.store", func(ctx context.Context) error {
+		_, err := r.db.ExecContext(ctx, query, tokenHash, data.IDCitizen, data.UserID, jsonData, time.Now(), expiresAt)
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to store refresh token", zap.Error(err), zap.Int("id_citizen", data.IDCitizen))
+		return fmt.Errorf("failed to store refresh token: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns the data of a refresh token that has not expired and its expiration
+func (r *RefreshTokenRepository) Get(ctx context.Context, tokenHash string) (*domain.RefreshTokenData, time.Time, error) {
+	query := `SELECT data, expires_at FROM {refresh_tokens} WHERE token_hash = $1 AND expires_at > $2`
+
+	var jsonData []byte
+	var expiresAt time.Time
+	err := r.retrier.Do(ctx, "refresh_tokens.get", func(ctx context.Context) error {
+		return r.db.QueryRowContext(ctx, query, tokenHash, time.Now()).Scan(&jsonData, &expiresAt)
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, time.Time{}, domainerrors.ErrInvalidToken
+	}
+	if err != nil {
+		r.logger.Error("failed to get refresh token", zap.Error(err))
+		return nil, time.Time{}, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	var data domain.RefreshTokenData
+	if err := json.Unmarshal(jsonData, &data); err != nil {
+		r.logger.Error("failed to unmarshal refresh token data", zap.Error(err))
+		return nil, time.Time{}, fmt.Errorf("failed to unmarshal token data: %w", err)
+	}
+
+	return &data, expiresAt, nil
+}
+
+// Delete deletes a refresh token, if stored
+func (r *RefreshTokenRepository) Delete(ctx context.Context, tokenHash string) error {
+	query := `DELETE FROM {refresh_tokens} WHERE token_hash = $1`
+
+	err := r.retrier.Do(ctx, "refresh_tokens.delete", func(ctx context.Context) error {
+		_, err := r.db.ExecContext(ctx, query, tokenHash)
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to delete refresh token", zap.Error(err))
+		return fmt.Errorf("failed to delete refresh token: %w", err)
+	}
+
+	return nil
+}
+
+// Rotate deletes the old refresh token and stores the new one in a single statement
+func (r *RefreshTokenRepository) Rotate(ctx context.Context, oldTokenHash, newTokenHash string, data *domain.RefreshTokenData, expiresAt time.Time) error {
+	query := `
+		WITH rotated AS (
+			DELETE FROM {refresh_tokens} WHERE token_hash = $1
+		)
+		INSERT INTO {refresh_tokens} (token_hash, id_citizen, user_id, data, created_at, expires_at)
+		VALUES ($2, $3, $4, $5, $6, $7)
+		ON CONFLICT (token_hash) DO UPDATE
+		SET id_citizen = EXCLUDED.id_citizen, user_id = EXCLUDED.user_id, data = EXCLUDED.data, expires_at = EXCLUDED.expires_at
+	`
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal token data: %w", err)
+	}
+
+	err = r.retrier.Do(ctx, "refresh_tokens.rotate", func(ctx context.Context) error {
+		_, err := r.db.ExecContext(ctx, query, oldTokenHash, newTokenHash, data.IDCitizen, data.UserID, jsonData, time.Now(), expiresAt)
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to rotate refresh token", zap.Error(err), zap.Int("id_citizen", data.IDCitizen))
+		return fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteByUser deletes all refresh tokens issued to the user ID or to the citizen ID
+func (r *RefreshTokenRepository) DeleteByUser(ctx context.Context, userID string, idCitizen int) error {
+	query := `DELETE FROM {refresh_tokens} WHERE id_citizen = $1 OR ($2 <> '' AND user_id = $2)`
+
+	err := r.retrier.Do(ctx, "refresh_tokens.delete_by_user", func(ctx context.Context) error {
+		_, err := r.db.ExecContext(ctx, query, idCitizen, userID)
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to delete user refresh tokens", zap.Error(err), zap.Int("id_citizen", idCitizen))
+		return fmt.Errorf("failed to delete user refresh tokens: %w", err)
+	}
+
+	return nil
+}
+
+// CountActive returns the number of refresh tokens of a user that have not expired
+func (r *RefreshTokenRepository) CountActive(ctx context.Context, idCitizen int) (int, error) {
+	query := `SELECT COUNT(*) FROM {refresh_tokens} WHERE id_citizen = $1 AND expires_at > $2`
+
+	var count int
+	err := r.retrier.Do(ctx, "refresh_tokens.count_active", func(ctx context.Context) error {
+		return r.db.QueryRowContext(ctx, query, idCitizen, time.Now()).Scan(&count)
+	})
+	if err != nil {
+		r.logger.Error("failed to count refresh tokens", zap.Error(err), zap.Int("id_citizen", idCitizen))
+		return 0, fmt.Errorf("failed to count refresh tokens: %w", err)
+	}
+
+	return count, nil
+}
+
+// DeleteExpired deletes up to limit refresh tokens expired before the time, in a single statement so
+// concurrent cleanups of several instances don't conflict for long
+func (r *RefreshTokenRepository) DeleteExpired(ctx context.Context, before time.Time, limit int) (int, error) {
+	query := `
+		DELETE FROM {refresh_tokens}
+		WHERE token_hash IN (
+			SELECT token_hash FROM {refresh_tokens}
+			WHERE expires_at <= $1
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+	`
+
+	var deleted int64
+	err := r.retrier.Do(ctx, "refresh_tokens.delete_expired", func(ctx context.Context) error {
+		result, err := r.db.ExecContext(ctx, query, before, limit)
+		if err != nil {
+			return err
+		}
+		deleted, err = result.RowsAffected()
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to delete expired refresh tokens", zap.Error(err))
+		return 0, fmt.Errorf("failed to delete expired refresh tokens: %w", err)
+	}
+
+	return int(deleted), nil
+}
@@ -0,0 +1,253 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
+	"github.com/kristianrpo/auth-microservice/internal/retry"
+)
+
+// ErrorClass classifies a database error to decide whether it can be retried
+type ErrorClass int
+
+const (
+	// ErrorTerminal errors are returned to the caller as they are
+	ErrorTerminal ErrorClass = iota
+	// ErrorRetryable errors guarantee the statement was not applied, so any operation can be retried
+	ErrorRetryable
+	// ErrorConnectionLost errors happen after the statement may have been applied,
+	// so only idempotent operations are retried
+	ErrorConnectionLost
+)
+
+// String returns the metric label of the error class
+func (c ErrorClass) String() string {
+	switch c {
+	case ErrorRetryable:
+		return "retryable"
+	case ErrorConnectionLost:
+		return "connection_lost"
+	default:
+		return "terminal"
+	}
+}
+
+// Postgres error codes that abort the statement before it is applied
+var retryableCodes = map[pq.ErrorCode]bool{
+	"40001": true, // serialization_failure
+	"40P01": true, // deadlock_detected
+	"53300": true, // too_many_connections
+	"57P03": true, // cannot_connect_now (server starting up or in recovery)
+	"08001": true, // sqlclient_unable_to_establish_sqlconnection
+	"08004": true, // sqlserver_rejected_establishment_of_sqlconnection
+}
+
+// ClassifyError reports whether a database error is transient and, if so, whether the statement may have been applied
+func ClassifyError(err error) ErrorClass {
+	if err == nil || errors.Is(err, sql.ErrNoRows) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return ErrorTerminal
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		if retryableCodes[pqErr.Code] {
+			return ErrorRetryable
+		}
+		// Remaining connection exceptions and server shutdowns (failover) interrupt statements in flight
+		if pqErr.Code.Class() == "08" || pqErr.Code == "57P01" || pqErr.Code == "57P02" {
+			return ErrorConnectionLost
+		}
+		return ErrorTerminal
+	}
+
+	// database/sql only surfaces ErrBadConn when the statement was never sent
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, syscall.ECONNREFUSED) {
+		return ErrorRetryable
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return ErrorRetryable
+	}
+
+	if errors.As(err, &opErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) {
+		return ErrorConnectionLost
+	}
+
+	return ErrorTerminal
+}
+
+// RetryPolicy configures how transient database errors are retried
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts per call, 1 disables retries
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// BudgetRatio is the fraction of a retry each successful call earns for its operation.
+	// Each operation starts with retryBudgetMaxTokens retries, so a long outage cannot multiply the load on the database.
+	BudgetRatio float64
+
+	// OperationTimeout bounds each attempt, so a stuck database fails the call instead of holding the request.
+	// An attempt that times out is not retried. 0 disables it.
+	OperationTimeout time.Duration
+
+	// SlowQueryThreshold is the duration above which an attempt is logged as a slow query. 0 disables it.
+	SlowQueryThreshold time.Duration
+
+	// MaxConcurrentOperations caps the attempts in progress, it should match the size of the connection pool.
+	// Attempts above it wait at most PoolWaitTimeout and then fail with ErrDatabaseBusy. 0 disables the limit.
+	MaxConcurrentOperations int
+	PoolWaitTimeout         time.Duration
+}
+
+// retryBudgetMaxTokens is the number of retries an operation can burst through
+const retryBudgetMaxTokens = 10.0
+
+// Retrier retries repository operations on transient Postgres errors with exponential backoff
+type Retrier struct {
+	policy  RetryPolicy
+	backoff retry.Policy
+	logger  *zap.Logger
+	slots   chan struct{}
+
+	mu      sync.Mutex
+	budgets map[string]float64
+}
+
+// NewRetrier creates a new instance of Retrier
+func NewRetrier(policy RetryPolicy, logger *zap.Logger) *Retrier {
+	r := &Retrier{
+		policy: policy,
+		// Retries from many requests spread out
+		backoff: retry.Policy{InitialBackoff: policy.InitialBackoff, MaxBackoff: policy.MaxBackoff, Jitter: retry.EqualJitter},
+		logger:  logger,
+		budgets: make(map[string]float64),
+	}
+	if policy.MaxConcurrentOperations > 0 && policy.PoolWaitTimeout > 0 {
+		r.slots = make(chan struct{}, policy.MaxConcurrentOperations)
+	}
+	return r
+}
+
+// Do runs an idempotent operation, retrying it on any transient error
+func (r *Retrier) Do(ctx context.Context, operation string, fn func(ctx context.Context) error) error {
+	return r.run(ctx, operation, true, r.policy.OperationTimeout, fn)
+}
+
+// DoNonIdempotent runs an operation that must not be applied twice (e.g. inserts), retrying it only
+// on errors that guarantee the previous attempt was not applied
+func (r *Retrier) DoNonIdempotent(ctx context.Context, operation string, fn func(ctx context.Context) error) error {
+	return r.run(ctx, operation, false, r.policy.OperationTimeout, fn)
+}
+
+// DoStream runs an idempotent query whose rows are read by the caller after fn returns, so the
+// operation timeout is not applied and reading the rows is only bounded by ctx
+func (r *Retrier) DoStream(ctx context.Context, operation string, fn func(ctx context.Context) error) error {
+	return r.run(ctx, operation, true, 0, fn)
+}
+
+func (r *Retrier) run(ctx context.Context, operation string, idempotent bool, timeout time.Duration, fn func(ctx context.Context) error) error {
+	for attempt := 1; ; attempt++ {
+		err := r.attempt(ctx, operation, timeout, fn)
+		if err == nil {
+			r.deposit(operation)
+			if attempt > 1 {
+				metrics.IncDBRetryOutcome(operation, "recovered")
+			}
+			return nil
+		}
+
+		class := ClassifyError(err)
+		if class == ErrorTerminal || (class == ErrorConnectionLost && !idempotent) {
+			return err
+		}
+
+		if attempt >= r.policy.MaxAttempts {
+			if attempt > 1 {
+				metrics.IncDBRetryOutcome(operation, "exhausted")
+			}
+			return err
+		}
+
+		if !r.withdraw(operation) {
+			metrics.IncDBRetryOutcome(operation, "budget_exhausted")
+			r.logger.Warn("database retry budget exhausted", zap.String("operation", operation), zap.Error(err))
+			return err
+		}
+
+		backoff := r.backoff.Backoff(attempt)
+		metrics.IncDBRetry(operation, class.String())
+		r.logger.Warn("retrying transient database error",
+			zap.String("operation", operation),
+			zap.Int("attempt", attempt),
+			zap.String("class", class.String()),
+			zap.Duration("backoff", backoff),
+			zap.Error(err))
+
+		if retry.Sleep(ctx, backoff) != nil {
+			return err
+		}
+	}
+}
+
+// attempt runs fn once, bounded by timeout when it is set
+func (r *Retrier) attempt(ctx context.Context, operation string, timeout time.Duration, fn func(ctx context.Context) error) error {
+	release, err := r.acquire(ctx, operation)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if timeout <= 0 {
+		return r.observe(ctx, operation, fn)
+	}
+
+	attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err = r.observe(attemptCtx, operation, fn)
+	if err != nil && ctx.Err() == nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) {
+		metrics.IncDBOperationTimeout(operation)
+		r.logger.Warn("database operation timed out", zap.String("operation", operation), zap.Duration("timeout", timeout))
+	}
+	return err
+}
+
+// withdraw consumes a retry from the operation budget, reporting false when none is left
+func (r *Retrier) withdraw(operation string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tokens, ok := r.budgets[operation]
+	if !ok {
+		tokens = retryBudgetMaxTokens
+	}
+	if tokens < 1 {
+		return false
+	}
+	r.budgets[operation] = tokens - 1
+	return true
+}
+
+// deposit refills the operation budget after a successful call
+func (r *Retrier) deposit(operation string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if tokens, ok := r.budgets[operation]; ok {
+		r.budgets[operation] = min(tokens+r.policy.BudgetRatio, retryBudgetMaxTokens)
+	}
+}
@@ -0,0 +1,224 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// ScopeRepository is the PostgreSQL implementation of the scope registry repository
+type ScopeRepository struct {
+	db      *DB
+	retrier *Retrier
+	logger  *zap.Logger
+}
+
+// NewScopeRepository creates a new instance of ScopeRepository
+func NewScopeRepository(db *DB, retrier *Retrier, logger *zap.Logger) *ScopeRepository {
+	return &ScopeRepository{
+		db:      db,
+		retrier: retrier,
+		logger:  logger,
+	}
+}
+
+// Create registers a new scope
+func (r *ScopeRepository) Create(ctx context.Context, scope *domain.Scope) error {
+	scope.CreatedAt = time.Now()
+	scope.UpdatedAt = scope.CreatedAt
+
+	query := `
+		INSERT INTO {scopes} (name, description, system, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (name) DO NOTHING
+	`
+
+	var result sql.Result
+	err := r.retrier.DoNonIdempotent(ctx, "scopes.create", func(ctx context.Context) (err error) {
+		result, err = r.db.ExecContext(ctx, query,
+			scope.Name,
+			scope.Description,
+			scope.System,
+			scope.CreatedAt,
+			scope.UpdatedAt,
+		)
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to create scope", zap.Error(err), zap.String("scope", scope.Name))
+		return fmt.Errorf("failed to create scope: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domainerrors.ErrScopeAlreadyExists
+	}
+
+	r.logger.Info("scope created successfully", zap.String("scope", scope.Name))
+	return nil
+}
+
+// GetByName retrieves a scope by name
+func (r *ScopeRepository) GetByName(ctx context.Context, name string) (*domain.Scope, error) {
+	query := `
+		SELECT name, description, system, created_at, updated_at
+		FROM {scopes}
+		WHERE name = $1
+	`
+
+	scope := &domain.Scope{}
+	err := r.retrier.Do(ctx, "scopes.get_by_name", func(ctx context.Context) error {
+		return r.db.QueryRowContext(ctx, query, name).Scan(
+			&scope.Name,
+			&scope.Description,
+			&scope.System,
+			&scope.CreatedAt,
+			&scope.UpdatedAt,
+		)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, domainerrors.ErrScopeNotFound
+	}
+	if err != nil {
+		r.logger.Error("failed to get scope", zap.Error(err), zap.String("scope", name))
+		return nil, fmt.Errorf("failed to get scope: %w", err)
+	}
+
+	return scope, nil
+}
+
+// GetByNames retrieves the registered scopes among the given names
+func (r *ScopeRepository) GetByNames(ctx context.Context, names []string) ([]*domain.Scope, error) {
+	query := `
+		SELECT name, description, system, created_at, updated_at
+		FROM {scopes}
+		WHERE name = ANY($1)
+		ORDER BY name
+	`
+
+	return r.query(ctx, "scopes.get_by_names", query, pq.Array(names))
+}
+
+// List retrieves all registered scopes
+func (r *ScopeRepository) List(ctx context.Context) ([]*domain.Scope, error) {
+	query := `
+		SELECT name, description, system, created_at, updated_at
+		FROM {scopes}
+		ORDER BY name
+	`
+
+	return r.query(ctx, "scopes.list", query)
+}
+
+// Update updates the description of a scope
+func (r *ScopeRepository) Update(ctx context.Context, scope *domain.Scope) error {
+	scope.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE {scopes}
+		SET description = $1, updated_at = $2
+		WHERE name = $3
+	`
+
+	var result sql.Result
+	err := r.retrier.Do(ctx, "scopes.update", func(ctx context.Context) (err error) {
+		result, err = r.db.ExecContext(ctx, query, scope.Description, scope.UpdatedAt, scope.Name)
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to update scope", zap.Error(err), zap.String("scope", scope.Name))
+		return fmt.Errorf("failed to update scope: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domainerrors.ErrScopeNotFound
+	}
+
+	r.logger.Info("scope updated successfully", zap.String("scope", scope.Name))
+	return nil
+}
+
+// Delete removes a scope from the registry unless it is assigned to a client. The check and the removal
+// are a single statement, so a client can't be granted the scope in between.
+func (r *ScopeRepository) Delete(ctx context.Context, name string) error {
+	// The existence check reads the scopes as they were before the removal
+	query := `
+		WITH deleted AS (
+			DELETE FROM {scopes}
+			WHERE name = $1
+			  AND NOT EXISTS (SELECT 1 FROM {oauth_clients} WHERE $1 = ANY(scopes))
+			RETURNING name
+		)
+		SELECT EXISTS (SELECT 1 FROM deleted), EXISTS (SELECT 1 FROM {scopes} WHERE name = $1)
+	`
+
+	var deleted, exists bool
+	err := r.retrier.DoNonIdempotent(ctx, "scopes.delete", func(ctx context.Context) error {
+		return r.db.QueryRowContext(ctx, query, name).Scan(&deleted, &exists)
+	})
+	if err != nil {
+		r.logger.Error("failed to delete scope", zap.Error(err), zap.String("scope", name))
+		return fmt.Errorf("failed to delete scope: %w", err)
+	}
+
+	if !exists {
+		return domainerrors.ErrScopeNotFound
+	}
+	if !deleted {
+		return domainerrors.ErrScopeInUse
+	}
+
+	r.logger.Info("scope deleted successfully", zap.String("scope", name))
+	return nil
+}
+
+// query runs a scope listing query and scans the results
+func (r *ScopeRepository) query(ctx context.Context, operation, query string, args ...interface{}) ([]*domain.Scope, error) {
+	var scopes []*domain.Scope
+	err := r.retrier.Do(ctx, operation, func(ctx context.Context) error {
+		rows, err := r.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		scopes = nil
+		for rows.Next() {
+			scope := &domain.Scope{}
+			if err := rows.Scan(
+				&scope.Name,
+				&scope.Description,
+				&scope.System,
+				&scope.CreatedAt,
+				&scope.UpdatedAt,
+			); err != nil {
+				return err
+			}
+			scopes = append(scopes, scope)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		r.logger.Error("failed to list scopes", zap.Error(err))
+		return nil, fmt.Errorf("failed to list scopes: %w", err)
+	}
+
+	return scopes, nil
+}
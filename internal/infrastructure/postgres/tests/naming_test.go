@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/postgres"
+)
+
+// recordingDriver is a database driver that records the statements it is given
+type recordingDriver struct {
+	statements *[]string
+}
+
+func (d recordingDriver) Open(name string) (driver.Conn, error) {
+	return recordingConn(d), nil
+}
+
+type recordingConn recordingDriver
+
+func (c recordingConn) Prepare(query string) (driver.Stmt, error) {
+	*c.statements = append(*c.statements, query)
+	return recordingStmt{}, nil
+}
+
+func (c recordingConn) Close() error              { return nil }
+func (c recordingConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+type recordingStmt struct{}
+
+func (s recordingStmt) Close() error  { return nil }
+func (s recordingStmt) NumInput() int { return -1 }
+func (s recordingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return driver.RowsAffected(0), nil
+}
+func (s recordingStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return nil, errors.New("not supported")
+}
+
+func TestNamedDB_ExpandsTablePlaceholders(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix string
+		query  string
+		want   string
+	}{
+		{
+			name:   "prefixed tables",
+			prefix: "auth_",
+			query:  "UPDATE {users} SET email = $1 WHERE id IN (SELECT user_id FROM {user_emails})",
+			want:   "UPDATE auth_users SET email = $1 WHERE id IN (SELECT user_id FROM auth_user_emails)",
+		},
+		{
+			name:   "prefixed index",
+			prefix: "auth_",
+			query:  "CREATE INDEX IF NOT EXISTS idx_{audit_log}_target_id ON {audit_log}(target_id)",
+			want:   "CREATE INDEX IF NOT EXISTS idx_auth_audit_log_target_id ON auth_audit_log(target_id)",
+		},
+		{
+			name:  "no prefix",
+			query: "SELECT scopes FROM {oauth_clients}",
+			want:  "SELECT scopes FROM oauth_clients",
+		},
+		{
+			name:   "literals are kept",
+			prefix: "auth_",
+			query:  "ALTER TABLE {scopes} ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}'",
+			want:   "ALTER TABLE auth_scopes ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var statements []string
+			db := postgres.NewNamedDB(sql.OpenDB(connector{recordingDriver{statements: &statements}}), postgres.Naming{TablePrefix: tt.prefix})
+			defer func() { _ = db.Close() }()
+
+			if _, err := db.ExecContext(context.Background(), tt.query); err != nil {
+				t.Fatalf("ExecContext() error = %v", err)
+			}
+			if len(statements) != 1 || statements[0] != tt.want {
+				t.Errorf("statements = %q, want %q", statements, tt.want)
+			}
+		})
+	}
+}
+
+// connector opens connections of a driver instance, so each test records its own statements
+type connector struct {
+	driver recordingDriver
+}
+
+func (c connector) Connect(ctx context.Context) (driver.Conn, error) { return c.driver.Open("") }
+func (c connector) Driver() driver.Driver                            { return c.driver }
@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/postgres"
+)
+
+func TestRetrier_FailsFastWhenPoolIsExhausted(t *testing.T) {
+	retrier := postgres.NewRetrier(postgres.RetryPolicy{
+		MaxAttempts:             3,
+		InitialBackoff:          time.Millisecond,
+		MaxBackoff:              time.Millisecond,
+		MaxConcurrentOperations: 1,
+		PoolWaitTimeout:         20 * time.Millisecond,
+	}, zap.NewNop())
+	ctx := context.Background()
+
+	started := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		_ = retrier.Do(ctx, "users.update", func(ctx context.Context) error {
+			close(started)
+			<-done
+			return nil
+		})
+	}()
+	<-started
+
+	calls := 0
+	start := time.Now()
+	err := retrier.Do(ctx, "users.get_by_id", func(ctx context.Context) error {
+		calls++
+		return nil
+	})
+	if !errors.Is(err, domainerrors.ErrDatabaseBusy) {
+		t.Fatalf("Do() error = %v, want %v", err, domainerrors.ErrDatabaseBusy)
+	}
+	if calls != 0 {
+		t.Errorf("calls = %d, want the operation not to run", calls)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Do() took %v, want to fail after the wait timeout without retries", elapsed)
+	}
+
+	close(done)
+	// The slot is freed once the first operation completes
+	deadline := time.Now().Add(time.Second)
+	for {
+		err = retrier.Do(ctx, "users.get_by_id", func(ctx context.Context) error { return nil })
+		if err == nil || time.Now().After(deadline) {
+			break
+		}
+	}
+	if err != nil {
+		t.Errorf("Do() after the slot was freed error = %v", err)
+	}
+}
+
+func TestRetrier_WaitsForAFreeConnection(t *testing.T) {
+	retrier := postgres.NewRetrier(postgres.RetryPolicy{
+		MaxAttempts:             1,
+		MaxConcurrentOperations: 1,
+		PoolWaitTimeout:         time.Second,
+	}, zap.NewNop())
+	ctx := context.Background()
+
+	started := make(chan struct{})
+	go func() {
+		_ = retrier.Do(ctx, "users.update", func(ctx context.Context) error {
+			close(started)
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		})
+	}()
+	<-started
+
+	if err := retrier.Do(ctx, "users.get_by_id", func(ctx context.Context) error { return nil }); err != nil {
+		t.Errorf("Do() error = %v, want the operation to run once the connection is free", err)
+	}
+}
@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/postgres"
+)
+
+func TestRetrier_LogsSlowQueries(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	retrier := postgres.NewRetrier(postgres.RetryPolicy{
+		MaxAttempts:        1,
+		SlowQueryThreshold: 10 * time.Millisecond,
+	}, zap.New(core))
+	ctx := context.Background()
+
+	if err := retrier.Do(ctx, "users.get_by_id", func(ctx context.Context) error { return nil }); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if logs.Len() != 0 {
+		t.Fatalf("logs = %v, want no slow query for a fast query", logs.All())
+	}
+
+	queryErr := errors.New("boom")
+	err := retrier.Do(ctx, "users.update", func(ctx context.Context) error {
+		time.Sleep(15 * time.Millisecond)
+		return queryErr
+	})
+	if !errors.Is(err, queryErr) {
+		t.Fatalf("Do() error = %v, want %v", err, queryErr)
+	}
+
+	slow := logs.FilterMessage("slow database query").AllUntimed()
+	if len(slow) != 1 {
+		t.Fatalf("slow query logs = %d, want 1", len(slow))
+	}
+	fields := slow[0].ContextMap()
+	if fields["operation"] != "users.update" || fields["failed"] != true {
+		t.Errorf("slow query fields = %v, want the failed users.update operation", fields)
+	}
+	if duration, ok := fields["duration"].(time.Duration); !ok || duration < 10*time.Millisecond {
+		t.Errorf("duration = %v, want above the threshold", fields["duration"])
+	}
+}
+
+func TestRetrier_SlowQueryThresholdDisabled(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	retrier := postgres.NewRetrier(postgres.RetryPolicy{MaxAttempts: 1}, zap.New(core))
+
+	_ = retrier.Do(context.Background(), "users.update", func(ctx context.Context) error {
+		time.Sleep(2 * time.Millisecond)
+		return nil
+	})
+	if logs.Len() != 0 {
+		t.Errorf("logs = %v, want none without a threshold", logs.All())
+	}
+}
@@ -0,0 +1,220 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/postgres"
+)
+
+func newTestRetrier(maxAttempts int) *postgres.Retrier {
+	return postgres.NewRetrier(postgres.RetryPolicy{
+		MaxAttempts:    maxAttempts,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     2 * time.Millisecond,
+		BudgetRatio:    0.1,
+	}, zap.NewNop())
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want postgres.ErrorClass
+	}{
+		{name: "nil", err: nil, want: postgres.ErrorTerminal},
+		{name: "no rows", err: sql.ErrNoRows, want: postgres.ErrorTerminal},
+		{name: "context canceled", err: context.Canceled, want: postgres.ErrorTerminal},
+		{name: "unique violation", err: &pq.Error{Code: "23505"}, want: postgres.ErrorTerminal},
+		{name: "serialization failure", err: &pq.Error{Code: "40001"}, want: postgres.ErrorRetryable},
+		{name: "deadlock", err: &pq.Error{Code: "40P01"}, want: postgres.ErrorRetryable},
+		{name: "server in recovery", err: &pq.Error{Code: "57P03"}, want: postgres.ErrorRetryable},
+		{name: "admin shutdown", err: &pq.Error{Code: "57P01"}, want: postgres.ErrorConnectionLost},
+		{name: "connection failure", err: &pq.Error{Code: "08006"}, want: postgres.ErrorConnectionLost},
+		{name: "bad connection", err: driver.ErrBadConn, want: postgres.ErrorRetryable},
+		{name: "dial error", err: &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, want: postgres.ErrorRetryable},
+		{name: "connection reset", err: &net.OpError{Op: "read", Err: syscall.ECONNRESET}, want: postgres.ErrorConnectionLost},
+		{name: "unexpected EOF", err: io.ErrUnexpectedEOF, want: postgres.ErrorConnectionLost},
+		{name: "wrapped", err: fmt.Errorf("query: %w", &pq.Error{Code: "40001"}), want: postgres.ErrorRetryable},
+		{name: "unknown", err: errors.New("boom"), want: postgres.ErrorTerminal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := postgres.ClassifyError(tt.err); got != tt.want {
+				t.Errorf("ClassifyError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetrier_Do(t *testing.T) {
+	connReset := &net.OpError{Op: "read", Err: syscall.ECONNRESET}
+	serialization := &pq.Error{Code: "40001"}
+
+	tests := []struct {
+		name         string
+		idempotent   bool
+		errs         []error
+		wantErr      error
+		wantAttempts int
+	}{
+		{name: "success", idempotent: true, errs: nil, wantAttempts: 1},
+		{name: "recovers from connection loss", idempotent: true, errs: []error{connReset, connReset}, wantAttempts: 3},
+		{name: "gives up after max attempts", idempotent: true, errs: []error{connReset, connReset, connReset, connReset}, wantErr: connReset, wantAttempts: 3},
+		{name: "terminal error not retried", idempotent: true, errs: []error{sql.ErrNoRows}, wantErr: sql.ErrNoRows, wantAttempts: 1},
+		{name: "non-idempotent not retried on connection loss", errs: []error{connReset}, wantErr: connReset, wantAttempts: 1},
+		{name: "non-idempotent retried on serialization failure", errs: []error{serialization}, wantAttempts: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retrier := newTestRetrier(3)
+
+			attempts := 0
+			fn := func(ctx context.Context) error {
+				attempts++
+				if attempts <= len(tt.errs) {
+					return tt.errs[attempts-1]
+				}
+				return nil
+			}
+
+			var err error
+			if tt.idempotent {
+				err = retrier.Do(context.Background(), "test.op", fn)
+			} else {
+				err = retrier.DoNonIdempotent(context.Background(), "test.op", fn)
+			}
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Do() error = %v, want %v", err, tt.wantErr)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("attempts = %v, want %v", attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestRetrier_BudgetIsPerOperation(t *testing.T) {
+	retrier := newTestRetrier(2)
+
+	attempts := 0
+	counting := func(ctx context.Context) error {
+		attempts++
+		return driver.ErrBadConn
+	}
+
+	// Each failing call spends one retry and the budget holds ten
+	for i := 0; i < 15; i++ {
+		_ = retrier.Do(context.Background(), "test.exhausted", counting)
+	}
+	if attempts != 25 {
+		t.Errorf("attempts = %v, want 25 (10 calls retried once, 5 without budget)", attempts)
+	}
+
+	// Other operations keep their own budget
+	attempts = 0
+	_ = retrier.Do(context.Background(), "test.other", counting)
+	if attempts != 2 {
+		t.Errorf("attempts on other operation = %v, want 2", attempts)
+	}
+}
+
+func TestRetrier_StopsWhenContextDone(t *testing.T) {
+	retrier := postgres.NewRetrier(postgres.RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: time.Hour,
+		MaxBackoff:     time.Hour,
+	}, zap.NewNop())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	attempts := 0
+	err := retrier.Do(ctx, "test.op", func(ctx context.Context) error {
+		attempts++
+		return driver.ErrBadConn
+	})
+
+	if !errors.Is(err, driver.ErrBadConn) {
+		t.Errorf("Do() error = %v, want %v", err, driver.ErrBadConn)
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %v, want 1", attempts)
+	}
+}
+
+func TestRetrier_OperationTimeout(t *testing.T) {
+	retrier := postgres.NewRetrier(postgres.RetryPolicy{
+		MaxAttempts:      3,
+		InitialBackoff:   time.Millisecond,
+		MaxBackoff:       2 * time.Millisecond,
+		OperationTimeout: 10 * time.Millisecond,
+	}, zap.NewNop())
+
+	stuck := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	tests := []struct {
+		name         string
+		run          func(fn func(ctx context.Context) error) error
+		wantErr      error
+		wantAttempts int
+	}{
+		{
+			name: "attempt bounded and not retried",
+			run: func(fn func(ctx context.Context) error) error {
+				return retrier.Do(context.Background(), "test.timeout", fn)
+			},
+			wantErr:      context.DeadlineExceeded,
+			wantAttempts: 1,
+		},
+		{
+			name: "stream not bounded",
+			run: func(fn func(ctx context.Context) error) error {
+				ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+				defer cancel()
+				return retrier.DoStream(ctx, "test.stream", func(attemptCtx context.Context) error {
+					if attemptCtx != ctx {
+						return errors.New("stream attempt has its own deadline")
+					}
+					return fn(attemptCtx)
+				})
+			},
+			wantErr:      context.DeadlineExceeded,
+			wantAttempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			err := tt.run(func(ctx context.Context) error {
+				attempts++
+				return stuck(ctx)
+			})
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("attempts = %v, want %v", attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// userEmailColumns are the columns scanned by scanUserEmail
+const userEmailColumns = `id, user_id, email, verified_at, code_hash, code_expires_at, code_attempts, created_at, updated_at`
+
+// UserEmailRepository is the PostgreSQL implementation of the user email repository
+type UserEmailRepository struct {
+	db      *DB
+	retrier *Retrier
+	logger  *zap.Logger
+}
+
+// NewUserEmailRepository creates a new instance of UserEmailRepository
+func NewUserEmailRepository(db *DB, retrier *Retrier, logger *zap.Logger) *UserEmailRepository {
+	return &UserEmailRepository{
+		db:      db,
+		retrier: retrier,
+		logger:  logger,
+	}
+}
+
+// Create adds an address to a user
+func (r *UserEmailRepository) Create(ctx context.Context, email *domain.UserEmail) error {
+	email.ID = uuid.New().String()
+	email.CreatedAt = time.Now()
+	email.UpdatedAt = email.CreatedAt
+
+	query := `
+		INSERT INTO {user_emails} (` + userEmailColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	// The ID is generated above, so a retried insert fails on the primary key instead of duplicating the row
+	err := r.retrier.Do(ctx, "user_emails.create", func(ctx context.Context) error {
+		_, err := r.db.ExecContext(ctx, query,
+			email.ID,
+			email.UserID,
+			email.Email,
+			email.VerifiedAt,
+			email.CodeHash,
+			email.CodeExpiresAt,
+			email.CodeAttempts,
+			email.CreatedAt,
+			email.UpdatedAt,
+		)
+		return err
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && string(pqErr.Code) == "23505" {
+			return domainerrors.ErrEmailAlreadyRegistered
+		}
+		r.logger.Error("failed to create user email", zap.Error(err), zap.String("user_id", email.UserID))
+		return fmt.Errorf("failed to create user email: %w", err)
+	}
+
+	r.logger.Info("user email created successfully", zap.String("user_id", email.UserID), zap.String("email_id", email.ID))
+	return nil
+}
+
+// GetByID retrieves an address of a user
+func (r *UserEmailRepository) GetByID(ctx context.Context, userID, id string) (*domain.UserEmail, error) {
+	query := `SELECT ` + userEmailColumns + ` FROM {user_emails} WHERE user_id = $1 AND id = $2`
+
+	var email *domain.UserEmail
+	err := r.retrier.Do(ctx, "user_emails.get_by_id", func(ctx context.Context) (err error) {
+		email, err = scanUserEmail(r.db.QueryRowContext(ctx, query, userID, id))
+		return err
+	})
+	if err == sql.ErrNoRows {
+		return nil, domainerrors.ErrEmailNotFound
+	}
+	if err != nil {
+		r.logger.Error("failed to get user email", zap.Error(err), zap.String("user_id", userID), zap.String("email_id", id))
+		return nil, fmt.Errorf("failed to get user email: %w", err)
+	}
+
+	return email, nil
+}
+
+// ListByUserID retrieves the addresses of a user, oldest first
+func (r *UserEmailRepository) ListByUserID(ctx context.Context, userID string) ([]*domain.UserEmail, error) {
+	query := `SELECT ` + userEmailColumns + ` FROM {user_emails} WHERE user_id = $1 ORDER BY created_at, id`
+
+	var emails []*domain.UserEmail
+	err := r.retrier.Do(ctx, "user_emails.list_by_user_id", func(ctx context.Context) error {
+		rows, err := r.db.QueryContext(ctx, query, userID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		emails = nil
+		for rows.Next() {
+			email, err := scanUserEmail(rows)
+			if err != nil {
+				return err
+			}
+			emails = append(emails, email)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		r.logger.Error("failed to list user emails", zap.Error(err), zap.String("user_id", userID))
+		return nil, fmt.Errorf("failed to list user emails: %w", err)
+	}
+
+	return emails, nil
+}
+
+// GetVerified retrieves the verified address matching email, whoever it belongs to
+func (r *UserEmailRepository) GetVerified(ctx context.Context, email string) (*domain.UserEmail, error) {
+	query := `SELECT ` + userEmailColumns + ` FROM {user_emails} WHERE email = $1 AND verified_at IS NOT NULL`
+
+	var userEmail *domain.UserEmail
+	err := r.retrier.Do(ctx, "user_emails.get_verified", func(ctx context.Context) (err error) {
+		userEmail, err = scanUserEmail(r.db.QueryRowContext(ctx, query, email))
+		return err
+	})
+	if err == sql.ErrNoRows {
+		return nil, domainerrors.ErrEmailNotFound
+	}
+	if err != nil {
+		r.logger.Error("failed to get verified user email", zap.Error(err))
+		return nil, fmt.Errorf("failed to get verified user email: %w", err)
+	}
+
+	return userEmail, nil
+}
+
+// Update saves the verification state of an address
+func (r *UserEmailRepository) Update(ctx context.Context, email *domain.UserEmail) error {
+	email.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE {user_emails}
+		SET verified_at = $3, code_hash = $4, code_expires_at = $5, code_attempts = $6, updated_at = $7
+		WHERE user_id = $1 AND id = $2
+	`
+
+	var result sql.Result
+	err := r.retrier.Do(ctx, "user_emails.update", func(ctx context.Context) (err error) {
+		result, err = r.db.ExecContext(ctx, query,
+			email.UserID,
+			email.ID,
+			email.VerifiedAt,
+			email.CodeHash,
+			email.CodeExpiresAt,
+			email.CodeAttempts,
+			email.UpdatedAt,
+		)
+		return err
+	})
+	if err != nil {
+		// A verified address is unique, another user verified it first
+		if pqErr, ok := err.(*pq.Error); ok && string(pqErr.Code) == "23505" {
+			return domainerrors.ErrEmailAlreadyRegistered
+		}
+		r.logger.Error("failed to update user email", zap.Error(err), zap.String("email_id", email.ID))
+		return fmt.Errorf("failed to update user email: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domainerrors.ErrEmailNotFound
+	}
+
+	return nil
+}
+
+// Delete removes an address of a user
+func (r *UserEmailRepository) Delete(ctx context.Context, userID, id string) error {
+	var result sql.Result
+	err := r.retrier.DoNonIdempotent(ctx, "user_emails.delete", func(ctx context.Context) (err error) {
+		result, err = r.db.ExecContext(ctx, `DELETE FROM {user_emails} WHERE user_id = $1 AND id = $2`, userID, id)
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to delete user email", zap.Error(err), zap.String("email_id", id))
+		return fmt.Errorf("failed to delete user email: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domainerrors.ErrEmailNotFound
+	}
+
+	r.logger.Info("user email deleted successfully", zap.String("user_id", userID), zap.String("email_id", id))
+	return nil
+}
+
+// DeleteByUserID removes every address of a user
+func (r *UserEmailRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	err := r.retrier.Do(ctx, "user_emails.delete_by_user_id", func(ctx context.Context) error {
+		_, err := r.db.ExecContext(ctx, `DELETE FROM {user_emails} WHERE user_id = $1`, userID)
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to delete user emails", zap.Error(err), zap.String("user_id", userID))
+		return fmt.Errorf("failed to delete user emails: %w", err)
+	}
+
+	return nil
+}
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanUserEmail scans the userEmailColumns of a row
+func scanUserEmail(row rowScanner) (*domain.UserEmail, error) {
+	email := &domain.UserEmail{}
+	var verifiedAt, codeExpiresAt sql.NullTime
+	if err := row.Scan(
+		&email.ID,
+		&email.UserID,
+		&email.Email,
+		&verifiedAt,
+		&email.CodeHash,
+		&codeExpiresAt,
+		&email.CodeAttempts,
+		&email.CreatedAt,
+		&email.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if verifiedAt.Valid {
+		email.VerifiedAt = &verifiedAt.Time
+	}
+	email.CodeExpiresAt = codeExpiresAt.Time
+	return email, nil
+}
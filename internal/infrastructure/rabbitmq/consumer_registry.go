@@ -0,0 +1,403 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rabbitmq/amqp091-go"
+
+	ports "github.com/kristianrpo/auth-microservice/internal/application/ports"
+	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
+	"github.com/kristianrpo/auth-microservice/internal/retry"
+)
+
+const (
+	// retryCountHeader counts how many times a message was retried
+	retryCountHeader = "x-retry-count"
+	// lastErrorHeader holds the error of the last failed processing
+	lastErrorHeader = "x-last-error"
+	// originalQueueHeader holds the queue a dead-lettered message comes from
+	originalQueueHeader = "x-original-queue"
+
+	// resubscribeDelay is the wait before consuming again after the channel of a queue is lost
+	resubscribeDelay = 2 * time.Second
+)
+
+// ConsumerRegistry implements the MessageConsumer interface for RabbitMQ.
+// Every registered queue is consumed on its own channel by a pool of workers, and consumption is
+// resumed when the channel is lost. A message whose handler fails is republished to its queue with
+// an incremented retry count until the retries are exhausted, then it is moved to the dead letter queue.
+type ConsumerRegistry struct {
+	client        *RabbitMQClient
+	mu            sync.Mutex
+	subscriptions []ports.QueueSubscription
+	cancel        context.CancelFunc
+	wg            sync.WaitGroup
+
+	subscribedQueues map[string]bool
+	subscribed       chan struct{}
+
+	// Drain statistics of Stop
+	processing atomic.Int64
+	stopping   atomic.Bool
+	drained    atomic.Int64
+	cancelled  atomic.Int64
+}
+
+// NewConsumerRegistry creates a new RabbitMQ consumer registry
+func NewConsumerRegistry(client *RabbitMQClient) *ConsumerRegistry {
+	return &ConsumerRegistry{
+		client:           client,
+		subscribedQueues: make(map[string]bool),
+		subscribed:       make(chan struct{}),
+	}
+}
+
+// Register adds a queue subscription, it must be called before Start
+func (r *ConsumerRegistry) Register(subscription ports.QueueSubscription) error {
+	if subscription.Queue == "" {
+		return errors.New("queue name is required")
+	}
+	if subscription.Handler == nil {
+		return fmt.Errorf("handler is required for queue %s", subscription.Queue)
+	}
+	if subscription.Concurrency < 1 {
+		return fmt.Errorf("concurrency of queue %s must be at least 1", subscription.Queue)
+	}
+	if subscription.MaxRetries < 0 {
+		return fmt.Errorf("max retries of queue %s cannot be negative", subscription.Queue)
+	}
+	if subscription.DeadLetterQueue == subscription.Queue {
+		return fmt.Errorf("dead letter queue of queue %s must be a different queue", subscription.Queue)
+	}
+	if subscription.Prefetch < 1 {
+		subscription.Prefetch = subscription.Concurrency
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cancel != nil {
+		return errors.New("cannot register a queue after the consumers started")
+	}
+	for _, existing := range r.subscriptions {
+		if existing.Queue == subscription.Queue {
+			return fmt.Errorf("queue %s is already registered", subscription.Queue)
+		}
+	}
+	r.subscriptions = append(r.subscriptions, subscription)
+	return nil
+}
+
+// Start starts consuming every registered queue in the background. It does not wait for RabbitMQ:
+// queues are subscribed as soon as the broker is available.
+func (r *ConsumerRegistry) Start(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cancel != nil {
+		return errors.New("consumers already started")
+	}
+
+	if r.client.GetConfig().AutoAck {
+		log.Printf("RabbitMQ auto-ack is enabled: failed messages are lost, retries and dead letter queues do not apply")
+	}
+
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	r.cancel = cancel
+	if len(r.subscriptions) == 0 {
+		close(r.subscribed)
+	}
+	for _, subscription := range r.subscriptions {
+		r.wg.Add(1)
+		go func(subscription ports.QueueSubscription) {
+			defer r.wg.Done()
+			r.run(runCtx, subscription)
+		}(subscription)
+	}
+	return nil
+}
+
+// Subscribed returns a channel closed once every registered queue has been subscribed at least once
+func (r *ConsumerRegistry) Subscribed() <-chan struct{} {
+	return r.subscribed
+}
+
+// markSubscribed records the first subscription of a queue
+func (r *ConsumerRegistry) markSubscribed(queue string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.subscribedQueues[queue] {
+		return
+	}
+	r.subscribedQueues[queue] = true
+	if len(r.subscribedQueues) == len(r.subscriptions) {
+		close(r.subscribed)
+	}
+}
+
+// Stop cancels the consumers and waits for the messages being processed, until the context is done
+func (r *ConsumerRegistry) Stop(ctx context.Context) error {
+	r.mu.Lock()
+	cancel := r.cancel
+	r.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	r.stopping.Store(true)
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Printf("RabbitMQ consumers stopped")
+		return nil
+	case <-ctx.Done():
+		// Unacknowledged messages are redelivered by RabbitMQ once the connection is closed
+		r.cancelled.Store(r.processing.Load())
+		return fmt.Errorf("timeout waiting for RabbitMQ consumers to stop: %w", ctx.Err())
+	}
+}
+
+// DrainStats reports the messages processed and abandoned by Stop, it implements ports.DrainReporter
+func (r *ConsumerRegistry) DrainStats() ports.DrainStats {
+	return ports.DrainStats{Drained: int(r.drained.Load()), Cancelled: int(r.cancelled.Load())}
+}
+
+// run consumes a queue until the context is cancelled, subscribing again whenever the channel is lost
+func (r *ConsumerRegistry) run(ctx context.Context, subscription ports.QueueSubscription) {
+	for {
+		err := r.consume(ctx, subscription)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("Consumer of queue %s stopped: %v. Resubscribing in %v...", subscription.Queue, err, resubscribeDelay)
+
+		if retry.Sleep(ctx, resubscribeDelay) != nil {
+			return
+		}
+	}
+}
+
+// consume subscribes to the queue and processes its messages with the configured number of workers.
+// It returns when the channel is closed, or once the in-flight messages are processed when the
+// context is cancelled.
+func (r *ConsumerRegistry) consume(ctx context.Context, subscription ports.QueueSubscription) error {
+	// Confirm mode is needed to republish retried and dead-lettered messages safely
+	channel, err := openConfirmChannel(r.client)
+	if err != nil {
+		return fmt.Errorf("consumer channel unavailable: %w", err)
+	}
+	defer func() { _ = channel.Close() }()
+
+	if err := r.client.DeclareQueue(channel, subscription.Queue); err != nil {
+		return err
+	}
+	if subscription.DeadLetterQueue != "" {
+		if err := r.client.DeclareQueue(channel, subscription.DeadLetterQueue); err != nil {
+			return err
+		}
+	}
+	if err := channel.Qos(subscription.Prefetch, 0, false); err != nil {
+		return fmt.Errorf("failed to set QoS: %w", err)
+	}
+
+	autoAck := r.client.GetConfig().AutoAck
+	consumerTag := subscription.Queue + "-" + uuid.New().String()
+	deliveries, err := channel.Consume(
+		subscription.Queue, // queue
+		consumerTag,        // consumer tag
+		autoAck,            // auto-ack
+		false,              // exclusive
+		false,              // no-local
+		false,              // no-wait
+		nil,                // args
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register consumer for queue %s: %w", subscription.Queue, err)
+	}
+	closed := channel.NotifyClose(make(chan *amqp091.Error, 1))
+	r.markSubscribed(subscription.Queue)
+
+	log.Printf("RabbitMQ consumer subscribed to queue: %s (concurrency %d, prefetch %d)",
+		subscription.Queue, subscription.Concurrency, subscription.Prefetch)
+
+	var workers sync.WaitGroup
+	for i := 0; i < subscription.Concurrency; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for delivery := range deliveries {
+				r.processing.Add(1)
+				r.process(ctx, channel, subscription, autoAck, delivery)
+				r.processing.Add(-1)
+				if r.stopping.Load() {
+					r.drained.Add(1)
+				}
+			}
+		}()
+	}
+
+	select {
+	case <-ctx.Done():
+		// Cancelling closes the deliveries once the pending ones are handed to the workers
+		if err := channel.Cancel(consumerTag, false); err != nil {
+			log.Printf("Failed to cancel consumer of queue %s: %v", subscription.Queue, err)
+		}
+		workers.Wait()
+		return nil
+	case amqpErr := <-closed:
+		workers.Wait()
+		if amqpErr == nil {
+			return errors.New("channel closed")
+		}
+		return amqpErr
+	}
+}
+
+// process handles a single delivery: it is acknowledged on success, and retried, dead-lettered or
+// dropped on failure
+func (r *ConsumerRegistry) process(ctx context.Context, channel *amqp091.Channel, subscription ports.QueueSubscription, autoAck bool, delivery amqp091.Delivery) {
+	queue := subscription.Queue
+
+	// In-flight messages are completed during shutdown
+	handlerErr := subscription.Handler(context.WithoutCancel(ctx), delivery.Body)
+
+	if autoAck {
+		if handlerErr != nil {
+			log.Printf("Error processing message from queue %s: %v", queue, handlerErr)
+			metrics.IncConsumedMessages(queue, "dropped")
+			return
+		}
+		metrics.IncConsumedMessages(queue, "acked")
+		return
+	}
+
+	if handlerErr == nil {
+		r.ack(queue, delivery)
+		metrics.IncConsumedMessages(queue, "acked")
+		return
+	}
+
+	retries := retryCount(delivery.Headers)
+	if retries < subscription.MaxRetries {
+		log.Printf("Error processing message from queue %s (retry %d/%d in %v): %v",
+			queue, retries+1, subscription.MaxRetries, subscription.RetryDelay, handlerErr)
+
+		if retry.Sleep(ctx, subscription.RetryDelay) != nil {
+			// Shutting down: the broker redelivers the message to the next consumer
+			r.nack(queue, delivery, true)
+			return
+		}
+
+		if err := r.republish(channel, queue, delivery, retries+1, handlerErr); err != nil {
+			log.Printf("Failed to republish message for retry on queue %s: %v", queue, err)
+			r.nack(queue, delivery, true)
+			return
+		}
+		r.ack(queue, delivery)
+		metrics.IncConsumedMessages(queue, "retried")
+		return
+	}
+
+	if subscription.DeadLetterQueue == "" {
+		log.Printf("Error processing message from queue %s, retries exhausted, dropping message: %v", queue, handlerErr)
+		r.nack(queue, delivery, false)
+		metrics.IncConsumedMessages(queue, "dropped")
+		return
+	}
+
+	if err := r.republish(channel, subscription.DeadLetterQueue, delivery, retries, handlerErr); err != nil {
+		log.Printf("Failed to move message from queue %s to dead letter queue %s: %v", queue, subscription.DeadLetterQueue, err)
+		r.nack(queue, delivery, true)
+		return
+	}
+	log.Printf("Error processing message from queue %s, retries exhausted, moved to %s: %v", queue, subscription.DeadLetterQueue, handlerErr)
+	r.ack(queue, delivery)
+	metrics.IncConsumedMessages(queue, "dead_lettered")
+}
+
+// republish publishes a copy of the delivery to the queue with the retry headers and waits for its confirm
+func (r *ConsumerRegistry) republish(channel *amqp091.Channel, queue string, delivery amqp091.Delivery, retries int, handlerErr error) error {
+	headers := amqp091.Table{}
+	for key, value := range delivery.Headers {
+		headers[key] = value
+	}
+	headers[retryCountHeader] = int32(retries)
+	headers[lastErrorHeader] = handlerErr.Error()
+	if _, ok := headers[originalQueueHeader]; !ok {
+		headers[originalQueueHeader] = delivery.RoutingKey
+	}
+
+	cfg := r.client.GetConfig()
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.PublishConfirmTimeout)
+	defer cancel()
+
+	confirmation, err := channel.PublishWithDeferredConfirmWithContext(
+		ctx,
+		"",    // exchange (empty string means default exchange)
+		queue, // routing key (queue name)
+		false, // mandatory
+		false, // immediate
+		amqp091.Publishing{
+			Headers:      headers,
+			DeliveryMode: getDeliveryMode(cfg.Durable),
+			ContentType:  delivery.ContentType,
+			MessageId:    delivery.MessageId,
+			Body:         delivery.Body,
+			Timestamp:    delivery.Timestamp,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errConfirmTimeout, err)
+	}
+	if !acked {
+		return errPublishNacked
+	}
+	return nil
+}
+
+// ack acknowledges a delivery
+func (r *ConsumerRegistry) ack(queue string, delivery amqp091.Delivery) {
+	if err := delivery.Ack(false); err != nil {
+		log.Printf("Failed to ACK message from queue %s: %v", queue, err)
+	}
+}
+
+// nack rejects a delivery, requeueing it or discarding it
+func (r *ConsumerRegistry) nack(queue string, delivery amqp091.Delivery, requeue bool) {
+	if err := delivery.Nack(false, requeue); err != nil {
+		log.Printf("Failed to NACK message from queue %s: %v", queue, err)
+	}
+}
+
+// retryCount returns the retry count header of a delivery, 0 when it was never retried
+func retryCount(headers amqp091.Table) int {
+	switch value := headers[retryCountHeader].(type) {
+	case int32:
+		return int(value)
+	case int64:
+		return int(value)
+	case int:
+		return value
+	default:
+		return 0
+	}
+}
@@ -1,6 +1,7 @@
 package rabbitmq
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"sync"
@@ -9,6 +10,15 @@ import (
 	"github.com/rabbitmq/amqp091-go"
 
 	"github.com/kristianrpo/auth-microservice/internal/infrastructure/config"
+	"github.com/kristianrpo/auth-microservice/internal/retry"
+)
+
+var (
+	// connectPolicy retries the first connection, to handle RabbitMQ startup delays
+	connectPolicy = retry.Policy{MaxAttempts: 5, InitialBackoff: time.Second, MaxBackoff: 4 * time.Second, Jitter: retry.EqualJitter}
+	// reconnectPolicy retries a lost connection until it is re-established, the jitter keeps the
+	// replicas from reconnecting all at once after a broker restart
+	reconnectPolicy = retry.Policy{InitialBackoff: time.Second, MaxBackoff: 30 * time.Second, Jitter: retry.EqualJitter}
 )
 
 // RabbitMQClient manages a shared RabbitMQ connection with auto-reconnection
@@ -25,26 +35,18 @@ type RabbitMQClient struct {
 // Implements retry logic to handle RabbitMQ startup delays
 func NewRabbitMQClient(cfg config.RabbitMQConfig) (*RabbitMQClient, error) {
 	var conn *amqp091.Connection
-	var err error
-
-	maxRetries := 5
-	retryDelay := 2 * time.Second
-
-	for i := 0; i < maxRetries; i++ {
-		conn, err = amqp091.DialConfig(cfg.URL, amqp091.Config{
-			Heartbeat: 10 * time.Second,
-			Locale:    "en_US",
-		})
-		if err == nil {
-			log.Printf("Connected to RabbitMQ at %s (attempt %d/%d)", cfg.URL, i+1, maxRetries)
-			break
-		}
-
-		if i < maxRetries-1 {
-			log.Printf("Failed to connect to RabbitMQ (attempt %d/%d): %v. Retrying in %v...",
-				i+1, maxRetries, err, retryDelay)
-			time.Sleep(retryDelay)
-		}
+	attempts := 0
+	err := connectPolicy.DoNotify(context.Background(), func(context.Context) error {
+		attempts++
+		var dialErr error
+		conn, dialErr = dial(cfg.URL)
+		return dialErr
+	}, func(attempt int, err error, backoff time.Duration) {
+		log.Printf("Failed to connect to RabbitMQ (attempt %d/%d): %v. Retrying in %v...",
+			attempt, connectPolicy.MaxAttempts, err, backoff)
+	})
+	if err == nil {
+		log.Printf("Connected to RabbitMQ at %s (attempt %d/%d)", cfg.URL, attempts, connectPolicy.MaxAttempts)
 	}
 
 	c := &RabbitMQClient{
@@ -119,28 +121,34 @@ func (c *RabbitMQClient) reconnect() {
 		c.mu.Unlock()
 	}()
 
-	retryDelay := 5 * time.Second
-	attempt := 0
-	for {
-		attempt++
-		conn, err := amqp091.DialConfig(c.config.URL, amqp091.Config{
-			Heartbeat: 10 * time.Second,
-			Locale:    "en_US",
-		})
-		if err == nil {
-			c.mu.Lock()
-			// swap connection
-			if c.conn != nil && !c.conn.IsClosed() {
-				_ = c.conn.Close()
-			}
-			c.conn = conn
-			c.mu.Unlock()
-			log.Printf("Successfully reconnected to RabbitMQ (attempt %d)", attempt)
-			return
-		}
-		log.Printf("Reconnection attempt %d failed: %v. Retrying in %v...", attempt, err, retryDelay)
-		time.Sleep(retryDelay)
+	var conn *amqp091.Connection
+	attempts := 0
+	// Without a maximum of attempts the reconnection only ends once it succeeds
+	_ = reconnectPolicy.DoNotify(context.Background(), func(context.Context) error {
+		attempts++
+		var err error
+		conn, err = dial(c.config.URL)
+		return err
+	}, func(attempt int, err error, backoff time.Duration) {
+		log.Printf("Reconnection attempt %d failed: %v. Retrying in %v...", attempt, err, backoff)
+	})
+
+	c.mu.Lock()
+	// swap connection
+	if c.conn != nil && !c.conn.IsClosed() {
+		_ = c.conn.Close()
 	}
+	c.conn = conn
+	c.mu.Unlock()
+	log.Printf("Successfully reconnected to RabbitMQ (attempt %d)", attempts)
+}
+
+// dial opens a connection to RabbitMQ
+func dial(url string) (*amqp091.Connection, error) {
+	return amqp091.DialConfig(url, amqp091.Config{
+		Heartbeat: 10 * time.Second,
+		Locale:    "en_US",
+	})
 }
 
 // CreateChannel creates a new channel from the shared connection.
@@ -198,6 +206,52 @@ func (c *RabbitMQClient) DeclareQueue(channel *amqp091.Channel, queueName string
 	return nil
 }
 
+// BindQueue declares the exchange of a route and binds the queue to it with the routing key (idempotent
+// operation)
+func (c *RabbitMQClient) BindQueue(channel *amqp091.Channel, queueName string, route config.ExchangeRoute) error {
+	err := channel.ExchangeDeclare(
+		route.Exchange,   // name
+		route.Type,       // type
+		c.config.Durable, // durable
+		false,            // auto-deleted
+		false,            // internal
+		false,            // no-wait
+		nil,              // arguments
+	)
+	if err != nil {
+		return fmt.Errorf("failed to declare exchange %s: %w", route.Exchange, err)
+	}
+
+	if err := channel.QueueBind(queueName, route.RoutingKey, route.Exchange, false, nil); err != nil {
+		return fmt.Errorf("failed to bind queue %s to exchange %s: %w", queueName, route.Exchange, err)
+	}
+	return nil
+}
+
+// QueueDepth returns the number of messages ready for delivery in a queue. It fails right away while the
+// connection is down instead of waiting for the reconnection, and never declares the queue.
+func (c *RabbitMQClient) QueueDepth(queueName string) (int, error) {
+	c.mu.RLock()
+	conn := c.conn
+	c.mu.RUnlock()
+	if conn == nil || conn.IsClosed() {
+		return 0, fmt.Errorf("connection is closed")
+	}
+
+	// A passive declare of a missing queue closes the channel, so each inspection uses its own
+	channel, err := conn.Channel()
+	if err != nil {
+		return 0, fmt.Errorf("failed to create channel: %w", err)
+	}
+	defer channel.Close()
+
+	queue, err := channel.QueueDeclarePassive(queueName, c.config.Durable, false, false, false, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to inspect queue %s: %w", queueName, err)
+	}
+	return queue.Messages, nil
+}
+
 // GetConfig returns the RabbitMQ configuration
 func (c *RabbitMQClient) GetConfig() config.RabbitMQConfig {
 	return c.config
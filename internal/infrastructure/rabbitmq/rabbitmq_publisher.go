@@ -2,23 +2,39 @@ package rabbitmq
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/rabbitmq/amqp091-go"
+
+	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
+	"github.com/kristianrpo/auth-microservice/internal/retry"
+)
+
+var (
+	// errPublishNacked is returned when the broker rejects a message
+	errPublishNacked = errors.New("message nacked by the broker")
+	// errConfirmTimeout is returned when the broker does not confirm a message in time
+	errConfirmTimeout = errors.New("timeout waiting for publisher confirm")
 )
 
-// RabbitMQPublisher implements the MessagePublisher interface for RabbitMQ
+// RabbitMQPublisher implements the MessagePublisher interface for RabbitMQ.
+// The channel is in confirm mode: a publish only succeeds once the broker acknowledges the message,
+// and NACKs, confirm timeouts and channel errors are retried with backoff. A message whose confirm
+// timed out may still have been delivered, so consumers must tolerate duplicates (events carry a messageId).
 type RabbitMQPublisher struct {
 	client  *RabbitMQClient
+	mu      sync.Mutex
 	channel *amqp091.Channel
 }
 
 // NewRabbitMQPublisher creates a new RabbitMQ message publisher
 func NewRabbitMQPublisher(client *RabbitMQClient) (*RabbitMQPublisher, error) {
 	// Attempt to create a channel, but do not fail if RabbitMQ is down
-	channel, _ := client.CreateChannel()
+	channel, _ := openPublisherChannel(client)
 
 	r := &RabbitMQPublisher{
 		client:  client,
@@ -31,31 +47,63 @@ func NewRabbitMQPublisher(client *RabbitMQClient) (*RabbitMQPublisher, error) {
 	return r, nil
 }
 
-// Publish sends a message to the specified queue
+// Publish sends a message to the specified queue and waits for the broker to confirm it,
+// retrying according to the publish settings of the configuration. Queues with an exchange route are
+// published to through their exchange.
 func (r *RabbitMQPublisher) Publish(ctx context.Context, queueName string, message []byte) error {
-	if r.channel == nil || r.channel.IsClosed() {
-		// Attempt to get a channel lazily
-		ch, err := r.client.CreateChannel()
+	cfg := r.client.GetConfig()
+	policy := retry.Policy{
+		MaxAttempts:    max(cfg.PublishMaxAttempts, 1),
+		InitialBackoff: cfg.PublishInitialBackoff,
+		MaxBackoff:     cfg.PublishMaxBackoff,
+		Jitter:         retry.EqualJitter,
+	}
+
+	err := policy.DoNotify(ctx, func(ctx context.Context) error {
+		err := r.publishConfirmed(ctx, queueName, message)
 		if err != nil {
-			return fmt.Errorf("publisher channel unavailable: %w", err)
+			metrics.IncMessagePublishAttemptFailure(queueName, publishFailureReason(err))
 		}
-		r.channel = ch
+		return err
+	}, func(attempt int, err error, backoff time.Duration) {
+		log.Printf("Publish to queue %s failed (attempt %d/%d): %v. Retrying in %v...", queueName, attempt, policy.MaxAttempts, err, backoff)
+	})
+	if err != nil {
+		metrics.IncMessagePublish(queueName, "failed")
+		return fmt.Errorf("failed to publish message to queue %s: %w", queueName, err)
+	}
+
+	metrics.IncMessagePublish(queueName, "confirmed")
+	log.Printf("Message published to queue: %s", queueName)
+	return nil
+}
+
+// publishConfirmed makes a single publish attempt and waits for its confirm
+func (r *RabbitMQPublisher) publishConfirmed(ctx context.Context, queueName string, message []byte) error {
+	channel, err := r.getChannel()
+	if err != nil {
+		return err
 	}
 
 	// Declare the queue (idempotent operation)
-	if err := r.client.DeclareQueue(r.channel, queueName); err != nil {
+	if err := r.client.DeclareQueue(channel, queueName); err != nil {
 		return err
 	}
 
 	cfg := r.client.GetConfig()
 
-	// Publish the message
-	err := r.channel.PublishWithContext(
+	// The default exchange routes by queue name, the exchanges of the routes are declared with the channel
+	exchange, routingKey := "", queueName
+	if route, ok := cfg.Route(queueName); ok {
+		exchange, routingKey = route.Exchange, route.RoutingKey
+	}
+
+	confirmation, err := channel.PublishWithDeferredConfirmWithContext(
 		ctx,
-		"",        // exchange (empty string means default exchange)
-		queueName, // routing key (queue name)
-		false,     // mandatory
-		false,     // immediate
+		exchange,
+		routingKey,
+		false, // mandatory
+		false, // immediate
 		amqp091.Publishing{
 			DeliveryMode: getDeliveryMode(cfg.Durable),
 			ContentType:  "application/json",
@@ -63,15 +111,84 @@ func (r *RabbitMQPublisher) Publish(ctx context.Context, queueName string, messa
 			Timestamp:    time.Now(),
 		},
 	)
-
 	if err != nil {
-		return fmt.Errorf("failed to publish message to queue %s: %w", queueName, err)
+		return err
 	}
 
-	log.Printf("Message published to queue: %s", queueName)
+	confirmCtx, cancel := context.WithTimeout(ctx, cfg.PublishConfirmTimeout)
+	defer cancel()
+
+	acked, err := confirmation.WaitContext(confirmCtx)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errConfirmTimeout, err)
+	}
+	if !acked {
+		return errPublishNacked
+	}
 	return nil
 }
 
+// getChannel returns the publisher channel, opening a new one if it is closed
+func (r *RabbitMQPublisher) getChannel() (*amqp091.Channel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.channel == nil || r.channel.IsClosed() {
+		ch, err := openPublisherChannel(r.client)
+		if err != nil {
+			return nil, fmt.Errorf("publisher channel unavailable: %w", err)
+		}
+		r.channel = ch
+	}
+	return r.channel, nil
+}
+
+// openConfirmChannel creates a channel in confirm mode
+func openConfirmChannel(client *RabbitMQClient) (*amqp091.Channel, error) {
+	ch, err := client.CreateChannel()
+	if err != nil {
+		return nil, err
+	}
+	if err := ch.Confirm(false); err != nil {
+		_ = ch.Close()
+		return nil, fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+	return ch, nil
+}
+
+// openPublisherChannel creates a channel in confirm mode and declares the exchanges of the routed queues,
+// bound to their queues. The exchanges are declared once per channel, when it is opened or re-created after
+// a reconnection, instead of on every publish.
+func openPublisherChannel(client *RabbitMQClient) (*amqp091.Channel, error) {
+	ch, err := openConfirmChannel(client)
+	if err != nil {
+		return nil, err
+	}
+	for queueName, route := range client.GetConfig().Routes() {
+		if err := client.DeclareQueue(ch, queueName); err != nil {
+			_ = ch.Close()
+			return nil, err
+		}
+		if err := client.BindQueue(ch, queueName, route); err != nil {
+			_ = ch.Close()
+			return nil, err
+		}
+	}
+	return ch, nil
+}
+
+// publishFailureReason classifies a failed publish attempt for metrics
+func publishFailureReason(err error) string {
+	switch {
+	case errors.Is(err, errPublishNacked):
+		return "nack"
+	case errors.Is(err, errConfirmTimeout):
+		return "timeout"
+	default:
+		return "error"
+	}
+}
+
 // getDeliveryMode returns the appropriate delivery mode based on durability
 func getDeliveryMode(durable bool) uint8 {
 	if durable {
@@ -83,13 +200,15 @@ func getDeliveryMode(durable bool) uint8 {
 // monitorChannel watches the publisher channel and re-creates it on close
 func (r *RabbitMQPublisher) monitorChannel() {
 	for {
+		r.mu.Lock()
 		ch := r.channel
+		r.mu.Unlock()
 		if ch == nil {
 			time.Sleep(1 * time.Second)
 			continue
 		}
 
-		closeCh := ch.NotifyClose(make(chan *amqp091.Error))
+		closeCh := ch.NotifyClose(make(chan *amqp091.Error, 1))
 		if err := <-closeCh; err != nil {
 			log.Printf("Publisher channel closed: %v. Reconnecting...", err)
 		} else {
@@ -100,12 +219,19 @@ func (r *RabbitMQPublisher) monitorChannel() {
 		// Try to recreate channel until success
 		for {
 			time.Sleep(2 * time.Second)
-			newCh, err := r.client.CreateChannel()
+			newCh, err := openPublisherChannel(r.client)
 			if err != nil {
 				log.Printf("Failed to recreate publisher channel: %v", err)
 				continue
 			}
-			r.channel = newCh
+			r.mu.Lock()
+			if r.channel == nil || r.channel.IsClosed() {
+				r.channel = newCh
+			} else {
+				// A publish already reopened the channel
+				_ = newCh.Close()
+			}
+			r.mu.Unlock()
 			log.Printf("Publisher channel recreated successfully")
 			break
 		}
@@ -114,6 +240,9 @@ func (r *RabbitMQPublisher) monitorChannel() {
 
 // Close closes the publisher channel (connection is managed by RabbitMQClient)
 func (r *RabbitMQPublisher) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	if r.channel != nil && !r.channel.IsClosed() {
 		if err := r.channel.Close(); err != nil {
 			log.Printf("error closing publisher channel: %v", err)
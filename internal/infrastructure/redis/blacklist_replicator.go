@@ -0,0 +1,145 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+const (
+	// blacklistReplicationBlock is how long a read of the stream waits for new entries
+	blacklistReplicationBlock = 2 * time.Second
+
+	// blacklistReplicationBatchSize is the maximum number of entries read at once
+	blacklistReplicationBatchSize = 100
+)
+
+// BlacklistReplicator is the Redis Streams implementation of the blacklist replicator. The regions share
+// a stream, in a Redis reachable from all of them, to which each region appends the tokens it blacklists.
+// Each region reads the stream through its own consumer group, so every token is applied once per region
+// whatever the number of its replicas, and the stream is trimmed to about maxLen entries.
+type BlacklistReplicator struct {
+	client   *redis.Client
+	stream   string
+	group    string
+	consumer string
+	maxLen   int64
+	logger   *zap.Logger
+}
+
+// NewBlacklistReplicator creates a new instance of BlacklistReplicator reading the stream in the consumer
+// group of the region, as the given consumer (e.g. the hostname of the replica)
+func NewBlacklistReplicator(client *redis.Client, stream, region, consumer string, maxLen int64, logger *zap.Logger) *BlacklistReplicator {
+	return &BlacklistReplicator{
+		client:   client,
+		stream:   stream,
+		group:    region,
+		consumer: consumer,
+		maxLen:   maxLen,
+		logger:   logger,
+	}
+}
+
+// Publish appends a blacklisted token to the stream
+func (r *BlacklistReplicator) Publish(ctx context.Context, token *domain.BlacklistedToken) error {
+	err := r.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: r.stream,
+		MaxLen: r.maxLen,
+		Approx: true,
+		Values: map[string]interface{}{
+			"token":      token.Token,
+			"expires_at": token.ExpiresAt.Unix(),
+			"region":     token.Region,
+		},
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("failed to publish blacklisted token: %w", err)
+	}
+	return nil
+}
+
+// Subscribe reads the stream in the consumer group of the region until the context is cancelled. The
+// entries left pending by a previous subscription, e.g. after a crash, are handled first; an entry is
+// acknowledged once handled, a failed one stays pending until the next subscription.
+func (r *BlacklistReplicator) Subscribe(ctx context.Context, handle func(ctx context.Context, token *domain.BlacklistedToken) error) error {
+	err := r.client.XGroupCreateMkStream(ctx, r.stream, r.group, "$").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("failed to create consumer group: %w", err)
+	}
+
+	// "0" reads the pending entries of the consumer, ">" the entries never delivered to the group
+	id := "0"
+	for ctx.Err() == nil {
+		streams, err := r.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    r.group,
+			Consumer: r.consumer,
+			Streams:  []string{r.stream, id},
+			Count:    blacklistReplicationBatchSize,
+			Block:    blacklistReplicationBlock,
+		}).Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("failed to read blacklist stream: %w", err)
+		}
+
+		delivered := 0
+		for _, stream := range streams {
+			delivered += len(stream.Messages)
+			for _, message := range stream.Messages {
+				r.handle(ctx, message, handle)
+			}
+		}
+		if id == "0" && delivered == 0 {
+			id = ">"
+		}
+	}
+	return ctx.Err()
+}
+
+// handle applies an entry of the stream and acknowledges it, malformed entries are dropped
+func (r *BlacklistReplicator) handle(ctx context.Context, message redis.XMessage, handle func(ctx context.Context, token *domain.BlacklistedToken) error) {
+	token, err := parseBlacklistedToken(message.Values)
+	if err != nil {
+		r.logger.Warn("dropping malformed blacklist replication entry", zap.Error(err), zap.String("message_id", message.ID))
+	} else if err := handle(ctx, token); err != nil {
+		return
+	}
+
+	if err := r.client.XAck(ctx, r.stream, r.group, message.ID).Err(); err != nil {
+		r.logger.Warn("failed to acknowledge blacklist replication entry", zap.Error(err), zap.String("message_id", message.ID))
+	}
+}
+
+// parseBlacklistedToken reads a blacklisted token from the values of an entry of the stream
+func parseBlacklistedToken(values map[string]interface{}) (*domain.BlacklistedToken, error) {
+	token, _ := values["token"].(string)
+	region, _ := values["region"].(string)
+	expiresAt, _ := values["expires_at"].(string)
+	if token == "" {
+		return nil, errors.New("missing token")
+	}
+
+	unix, err := strconv.ParseInt(expiresAt, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expiration: %w", err)
+	}
+
+	return &domain.BlacklistedToken{
+		Token:     token,
+		ExpiresAt: time.Unix(unix, 0),
+		Region:    region,
+	}, nil
+}
@@ -0,0 +1,96 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// ClientTokenRepository is the Redis implementation of the client token repository.
+// The tokens issued to a client are kept in a sorted set scored by their expiration, pruned on every
+// issuance and expiring with the newest token. Revoked tokens are moved to a set of the client that
+// expires with the last revoked token.
+type ClientTokenRepository struct {
+	client *redis.Client
+	logger *zap.Logger
+}
+
+// NewClientTokenRepository creates a new instance of ClientTokenRepository
+func NewClientTokenRepository(client *redis.Client, logger *zap.Logger) *ClientTokenRepository {
+	return &ClientTokenRepository{
+		client: client,
+		logger: logger,
+	}
+}
+
+// Track records the ID of a token issued to the client until the token expires
+func (r *ClientTokenRepository) Track(ctx context.Context, clientID, tokenID string, expiresAt time.Time) error {
+	key := clientTokensKey(clientID)
+	pipe := r.client.TxPipeline()
+	pipe.ZAdd(ctx, key, redis.Z{Score: float64(expiresAt.Unix()), Member: tokenID})
+	pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(time.Now().Unix(), 10))
+	pipe.ExpireAt(ctx, key, expiresAt)
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Error("failed to track client token", zap.Error(err), zap.String("client_id", clientID))
+		return fmt.Errorf("failed to track client token: %w", err)
+	}
+	return nil
+}
+
+// RevokeAll revokes the unexpired tokens tracked for the client and returns how many were revoked.
+// Tokens issued after the tracked tokens are read are not revoked.
+func (r *ClientTokenRepository) RevokeAll(ctx context.Context, clientID string) (int, error) {
+	key := clientTokensKey(clientID)
+	tokens, err := r.client.ZRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{
+		Min: strconv.FormatInt(time.Now().Unix(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		r.logger.Error("failed to read client tokens", zap.Error(err), zap.String("client_id", clientID))
+		return 0, fmt.Errorf("failed to read client tokens: %w", err)
+	}
+	if len(tokens) == 0 {
+		return 0, nil
+	}
+
+	tokenIDs := make([]interface{}, len(tokens))
+	var lastExpiration int64
+	for i, token := range tokens {
+		tokenIDs[i] = token.Member
+		lastExpiration = max(lastExpiration, int64(token.Score))
+	}
+
+	// The tokens stay tracked until they are revoked, so a failed revocation can be retried
+	revokedKey := revokedClientTokensKey(clientID)
+	pipe := r.client.TxPipeline()
+	pipe.SAdd(ctx, revokedKey, tokenIDs...)
+	pipe.ExpireAt(ctx, revokedKey, time.Unix(lastExpiration, 0))
+	pipe.ZRem(ctx, key, tokenIDs...)
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Error("failed to revoke client tokens", zap.Error(err), zap.String("client_id", clientID))
+		return 0, fmt.Errorf("failed to revoke client tokens: %w", err)
+	}
+	return len(tokens), nil
+}
+
+// IsRevoked verifies if a token of the client was revoked
+func (r *ClientTokenRepository) IsRevoked(ctx context.Context, clientID, tokenID string) (bool, error) {
+	revoked, err := r.client.SIsMember(ctx, revokedClientTokensKey(clientID), tokenID).Result()
+	if err != nil {
+		r.logger.Error("failed to check revoked client token", zap.Error(err), zap.String("client_id", clientID))
+		return false, fmt.Errorf("failed to check revoked client token: %w", err)
+	}
+	return revoked, nil
+}
+
+func clientTokensKey(clientID string) string {
+	return fmt.Sprintf("client_tokens:%s", clientID)
+}
+
+func revokedClientTokensKey(clientID string) string {
+	return fmt.Sprintf("revoked_client_tokens:%s", clientID)
+}
@@ -0,0 +1,102 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// clientUsagePendingKey is the set of the clients with usage counted since the last drain
+const clientUsagePendingKey = "client_usage:pending"
+
+// ClientUsageCounter is the Redis implementation of the client usage counters. The usage of each client
+// is counted in a hash, and the clients with counted usage are kept in a set so a drain doesn't scan.
+type ClientUsageCounter struct {
+	client *redis.Client
+	logger *zap.Logger
+}
+
+// NewClientUsageCounter creates a new instance of ClientUsageCounter
+func NewClientUsageCounter(client *redis.Client, logger *zap.Logger) *ClientUsageCounter {
+	return &ClientUsageCounter{
+		client: client,
+		logger: logger,
+	}
+}
+
+// RecordIssuance counts a token issued to the client at the time
+func (c *ClientUsageCounter) RecordIssuance(ctx context.Context, clientID string, at time.Time) error {
+	key := clientUsageKey(clientID)
+	pipe := c.client.TxPipeline()
+	pipe.HIncrBy(ctx, key, "issued", 1)
+	pipe.HSet(ctx, key, "last_used_at", at.Unix())
+	pipe.SAdd(ctx, clientUsagePendingKey, clientID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		c.logger.Error("failed to record client token issuance", zap.Error(err), zap.String("client_id", clientID))
+		return fmt.Errorf("failed to record client usage: %w", err)
+	}
+	return nil
+}
+
+// RecordError counts a failed request of the client
+func (c *ClientUsageCounter) RecordError(ctx context.Context, clientID string) error {
+	pipe := c.client.TxPipeline()
+	pipe.HIncrBy(ctx, clientUsageKey(clientID), "errors", 1)
+	pipe.SAdd(ctx, clientUsagePendingKey, clientID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		c.logger.Error("failed to record client error", zap.Error(err), zap.String("client_id", clientID))
+		return fmt.Errorf("failed to record client usage: %w", err)
+	}
+	return nil
+}
+
+// Drain returns the usage counted since the last drain and resets the counters. The counters of each
+// client are read and deleted in a MULTI/EXEC transaction, so no usage recorded meanwhile is lost.
+func (c *ClientUsageCounter) Drain(ctx context.Context) ([]*domain.ClientUsageDelta, error) {
+	clientIDs, err := c.client.SMembers(ctx, clientUsagePendingKey).Result()
+	if err != nil {
+		c.logger.Error("failed to list pending client usage", zap.Error(err))
+		return nil, fmt.Errorf("failed to drain client usage: %w", err)
+	}
+
+	deltas := make([]*domain.ClientUsageDelta, 0, len(clientIDs))
+	for _, clientID := range clientIDs {
+		key := clientUsageKey(clientID)
+		pipe := c.client.TxPipeline()
+		fields := pipe.HGetAll(ctx, key)
+		pipe.Del(ctx, key)
+		pipe.SRem(ctx, clientUsagePendingKey, clientID)
+		if _, err := pipe.Exec(ctx); err != nil {
+			c.logger.Error("failed to drain client usage", zap.Error(err), zap.String("client_id", clientID))
+			return deltas, fmt.Errorf("failed to drain client usage: %w", err)
+		}
+
+		delta := parseClientUsageDelta(clientID, fields.Val())
+		if delta.TokensIssued > 0 || delta.Errors > 0 {
+			deltas = append(deltas, delta)
+		}
+	}
+
+	return deltas, nil
+}
+
+// parseClientUsageDelta converts the counters of a client, ignoring malformed fields
+func parseClientUsageDelta(clientID string, fields map[string]string) *domain.ClientUsageDelta {
+	delta := &domain.ClientUsageDelta{ClientID: clientID}
+	delta.TokensIssued, _ = strconv.ParseInt(fields["issued"], 10, 64)
+	delta.Errors, _ = strconv.ParseInt(fields["errors"], 10, 64)
+	if lastUsedAt, err := strconv.ParseInt(fields["last_used_at"], 10, 64); err == nil {
+		delta.LastUsedAt = time.Unix(lastUsedAt, 0)
+	}
+	return delta
+}
+
+func clientUsageKey(clientID string) string {
+	return fmt.Sprintf("client_usage:%s", clientID)
+}
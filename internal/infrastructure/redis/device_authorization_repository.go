@@ -0,0 +1,227 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// DeviceAuthorizationRepository is the Redis implementation of the device authorization repository
+type DeviceAuthorizationRepository struct {
+	client *redis.Client
+	logger *zap.Logger
+}
+
+// NewDeviceAuthorizationRepository creates a new instance of DeviceAuthorizationRepository
+func NewDeviceAuthorizationRepository(client *redis.Client, logger *zap.Logger) *DeviceAuthorizationRepository {
+	return &DeviceAuthorizationRepository{
+		client: client,
+		logger: logger,
+	}
+}
+
+// Store stores a new device authorization until it expires.
+// The user code is stored as a secondary key pointing to the device code.
+func (r *DeviceAuthorizationRepository) Store(ctx context.Context, auth *domain.DeviceAuthorization) error {
+	ttl := time.Until(auth.ExpiresAt)
+	if ttl <= 0 {
+		return domainerrors.ErrDeviceCodeExpired
+	}
+
+	jsonData, err := json.Marshal(auth)
+	if err != nil {
+		r.logger.Error("failed to marshal device authorization", zap.Error(err))
+		return fmt.Errorf("failed to marshal device authorization: %w", err)
+	}
+
+	pipe := r.client.TxPipeline()
+	pipe.Set(ctx, deviceCodeKey(auth.DeviceCode), jsonData, ttl)
+	pipe.Set(ctx, userCodeKey(auth.UserCode), auth.DeviceCode, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Error("failed to store device authorization", zap.Error(err), zap.String("client_id", auth.ClientID))
+		return fmt.Errorf("failed to store device authorization: %w", err)
+	}
+
+	r.logger.Debug("device authorization stored successfully", zap.String("client_id", auth.ClientID))
+	return nil
+}
+
+// GetByDeviceCode retrieves a device authorization by its device code
+func (r *DeviceAuthorizationRepository) GetByDeviceCode(ctx context.Context, deviceCode string) (*domain.DeviceAuthorization, error) {
+	jsonData, err := r.client.Get(ctx, deviceCodeKey(deviceCode)).Result()
+	if err == redis.Nil {
+		return nil, domainerrors.ErrDeviceCodeExpired
+	}
+	if err != nil {
+		r.logger.Error("failed to get device authorization", zap.Error(err))
+		return nil, fmt.Errorf("failed to get device authorization: %w", err)
+	}
+
+	var auth domain.DeviceAuthorization
+	if err := json.Unmarshal([]byte(jsonData), &auth); err != nil {
+		r.logger.Error("failed to unmarshal device authorization", zap.Error(err))
+		return nil, fmt.Errorf("failed to unmarshal device authorization: %w", err)
+	}
+
+	return &auth, nil
+}
+
+// GetByUserCode retrieves a device authorization by its user code
+func (r *DeviceAuthorizationRepository) GetByUserCode(ctx context.Context, userCode string) (*domain.DeviceAuthorization, error) {
+	deviceCode, err := r.client.Get(ctx, userCodeKey(userCode)).Result()
+	if err == redis.Nil {
+		return nil, domainerrors.ErrInvalidUserCode
+	}
+	if err != nil {
+		r.logger.Error("failed to resolve user code", zap.Error(err))
+		return nil, fmt.Errorf("failed to resolve user code: %w", err)
+	}
+
+	auth, err := r.GetByDeviceCode(ctx, deviceCode)
+	if err == domainerrors.ErrDeviceCodeExpired {
+		return nil, domainerrors.ErrInvalidUserCode
+	}
+	return auth, err
+}
+
+// Update replaces a device authorization keeping its expiration.
+// The write only succeeds while the stored authorization is still pending, so a decision
+// cannot overwrite another decision or a code that was already consumed.
+func (r *DeviceAuthorizationRepository) Update(ctx context.Context, auth *domain.DeviceAuthorization) error {
+	err := r.updatePending(ctx, auth.DeviceCode, func(stored *domain.DeviceAuthorization) {
+		*stored = *auth
+	})
+	if err == errDeviceAuthorizationDecided {
+		return domainerrors.ErrDeviceCodeExpired
+	}
+	return err
+}
+
+// RecordPoll stores the polling time and interval of a pending device authorization.
+// Authorizations decided in the meantime are left untouched.
+func (r *DeviceAuthorizationRepository) RecordPoll(ctx context.Context, deviceCode string, polledAt time.Time, interval time.Duration) error {
+	err := r.updatePending(ctx, deviceCode, func(stored *domain.DeviceAuthorization) {
+		stored.LastPolledAt = polledAt
+		stored.Interval = interval
+	})
+	if err == errDeviceAuthorizationDecided {
+		return nil
+	}
+	return err
+}
+
+// Consume atomically removes a device authorization and returns it.
+// Only the first caller gets the authorization; later callers get ErrDeviceCodeExpired.
+func (r *DeviceAuthorizationRepository) Consume(ctx context.Context, deviceCode string) (*domain.DeviceAuthorization, error) {
+	jsonData, err := r.client.GetDel(ctx, deviceCodeKey(deviceCode)).Result()
+	if err == redis.Nil {
+		return nil, domainerrors.ErrDeviceCodeExpired
+	}
+	if err != nil {
+		r.logger.Error("failed to consume device authorization", zap.Error(err))
+		return nil, fmt.Errorf("failed to consume device authorization: %w", err)
+	}
+
+	var auth domain.DeviceAuthorization
+	if err := json.Unmarshal([]byte(jsonData), &auth); err != nil {
+		r.logger.Error("failed to unmarshal device authorization", zap.Error(err))
+		return nil, fmt.Errorf("failed to unmarshal device authorization: %w", err)
+	}
+
+	// The user code only points to the device code, a leftover would resolve to nothing
+	if err := r.client.Del(ctx, userCodeKey(auth.UserCode)).Err(); err != nil {
+		r.logger.Warn("failed to delete device user code", zap.Error(err))
+	}
+
+	return &auth, nil
+}
+
+// Delete removes a device authorization
+func (r *DeviceAuthorizationRepository) Delete(ctx context.Context, auth *domain.DeviceAuthorization) error {
+	if err := r.client.Del(ctx, deviceCodeKey(auth.DeviceCode), userCodeKey(auth.UserCode)).Err(); err != nil {
+		r.logger.Error("failed to delete device authorization", zap.Error(err))
+		return fmt.Errorf("failed to delete device authorization: %w", err)
+	}
+
+	r.logger.Debug("device authorization deleted successfully")
+	return nil
+}
+
+// errDeviceAuthorizationDecided reports that a compare-and-set found the authorization no longer pending
+var errDeviceAuthorizationDecided = errors.New("device authorization is no longer pending")
+
+// maxDeviceAuthorizationUpdateAttempts bounds the retries of a compare-and-set under contention
+const maxDeviceAuthorizationUpdateAttempts = 5
+
+// updatePending applies mutate to the stored authorization and writes it back only if it is still
+// pending and was not modified concurrently (WATCH/MULTI/EXEC), keeping its expiration.
+func (r *DeviceAuthorizationRepository) updatePending(ctx context.Context, deviceCode string, mutate func(*domain.DeviceAuthorization)) error {
+	key := deviceCodeKey(deviceCode)
+
+	txf := func(tx *redis.Tx) error {
+		jsonData, err := tx.Get(ctx, key).Result()
+		if err == redis.Nil {
+			return domainerrors.ErrDeviceCodeExpired
+		}
+		if err != nil {
+			return err
+		}
+
+		var stored domain.DeviceAuthorization
+		if err := json.Unmarshal([]byte(jsonData), &stored); err != nil {
+			return fmt.Errorf("failed to unmarshal device authorization: %w", err)
+		}
+		if stored.Status != domain.DeviceAuthorizationPending {
+			return errDeviceAuthorizationDecided
+		}
+
+		mutate(&stored)
+		updated, err := json.Marshal(&stored)
+		if err != nil {
+			return fmt.Errorf("failed to marshal device authorization: %w", err)
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.SetXX(ctx, key, updated, redis.KeepTTL)
+			return nil
+		})
+		return err
+	}
+
+	for attempt := 0; attempt < maxDeviceAuthorizationUpdateAttempts; attempt++ {
+		err := r.client.Watch(ctx, txf, key)
+		switch {
+		case err == nil:
+			return nil
+		case errors.Is(err, redis.TxFailedErr):
+			// The authorization changed between the read and the write, re-evaluate it
+			continue
+		case errors.Is(err, domainerrors.ErrDeviceCodeExpired):
+			return err
+		case errors.Is(err, errDeviceAuthorizationDecided):
+			return err
+		default:
+			r.logger.Error("failed to update device authorization", zap.Error(err))
+			return fmt.Errorf("failed to update device authorization: %w", err)
+		}
+	}
+
+	r.logger.Warn("device authorization update abandoned under contention")
+	return fmt.Errorf("failed to update device authorization: %w", redis.TxFailedErr)
+}
+
+func deviceCodeKey(deviceCode string) string {
+	return fmt.Sprintf("device_code:%s", deviceCode)
+}
+
+func userCodeKey(userCode string) string {
+	return fmt.Sprintf("device_user_code:%s", userCode)
+}
@@ -0,0 +1,54 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// KnownDeviceRepository is the Redis implementation of the known device repository.
+// Each user keeps a set of device fingerprints that expires when no new device is remembered within the TTL.
+type KnownDeviceRepository struct {
+	client *redis.Client
+	ttl    time.Duration
+	logger *zap.Logger
+}
+
+// NewKnownDeviceRepository creates a new instance of KnownDeviceRepository
+func NewKnownDeviceRepository(client *redis.Client, ttl time.Duration, logger *zap.Logger) *KnownDeviceRepository {
+	return &KnownDeviceRepository{
+		client: client,
+		ttl:    ttl,
+		logger: logger,
+	}
+}
+
+// IsKnown checks if the user has recently authenticated from the device
+func (r *KnownDeviceRepository) IsKnown(ctx context.Context, userID, deviceID string) (bool, error) {
+	known, err := r.client.SIsMember(ctx, knownDevicesKey(userID), deviceID).Result()
+	if err != nil {
+		r.logger.Error("failed to check known device", zap.Error(err), zap.String("user_id", userID))
+		return false, fmt.Errorf("failed to check known device: %w", err)
+	}
+	return known, nil
+}
+
+// Remember marks the device as known for the user
+func (r *KnownDeviceRepository) Remember(ctx context.Context, userID, deviceID string) error {
+	key := knownDevicesKey(userID)
+	pipe := r.client.TxPipeline()
+	pipe.SAdd(ctx, key, deviceID)
+	pipe.Expire(ctx, key, r.ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Error("failed to remember device", zap.Error(err), zap.String("user_id", userID))
+		return fmt.Errorf("failed to remember device: %w", err)
+	}
+	return nil
+}
+
+func knownDevicesKey(userID string) string {
+	return fmt.Sprintf("known_devices:%s", userID)
+}
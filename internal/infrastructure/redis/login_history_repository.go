@@ -0,0 +1,81 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// LoginHistoryRepository is the Redis implementation of the login history repository.
+// Each user keeps a capped list of recent login locations that expires after the history window.
+type LoginHistoryRepository struct {
+	client     *redis.Client
+	maxEntries int64
+	ttl        time.Duration
+	logger     *zap.Logger
+}
+
+// NewLoginHistoryRepository creates a new instance of LoginHistoryRepository
+func NewLoginHistoryRepository(client *redis.Client, maxEntries int, ttl time.Duration, logger *zap.Logger) *LoginHistoryRepository {
+	return &LoginHistoryRepository{
+		client:     client,
+		maxEntries: int64(maxEntries),
+		ttl:        ttl,
+		logger:     logger,
+	}
+}
+
+// Record adds a login location to the history of a user
+func (r *LoginHistoryRepository) Record(ctx context.Context, userID string, location *domain.LoginLocation) error {
+	jsonData, err := json.Marshal(location)
+	if err != nil {
+		r.logger.Error("failed to marshal login location", zap.Error(err))
+		return fmt.Errorf("failed to marshal login location: %w", err)
+	}
+
+	key := loginHistoryKey(userID)
+	pipe := r.client.TxPipeline()
+	pipe.LPush(ctx, key, jsonData)
+	pipe.LTrim(ctx, key, 0, r.maxEntries-1)
+	pipe.Expire(ctx, key, r.ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Error("failed to record login location", zap.Error(err), zap.String("user_id", userID))
+		return fmt.Errorf("failed to record login location: %w", err)
+	}
+
+	return nil
+}
+
+// Recent returns the login locations of a user since the given time, most recent first
+func (r *LoginHistoryRepository) Recent(ctx context.Context, userID string, since time.Time) ([]*domain.LoginLocation, error) {
+	entries, err := r.client.LRange(ctx, loginHistoryKey(userID), 0, -1).Result()
+	if err != nil {
+		r.logger.Error("failed to get login history", zap.Error(err), zap.String("user_id", userID))
+		return nil, fmt.Errorf("failed to get login history: %w", err)
+	}
+
+	locations := make([]*domain.LoginLocation, 0, len(entries))
+	for _, entry := range entries {
+		var location domain.LoginLocation
+		if err := json.Unmarshal([]byte(entry), &location); err != nil {
+			r.logger.Warn("skipping malformed login history entry", zap.Error(err), zap.String("user_id", userID))
+			continue
+		}
+		if location.At.Before(since) {
+			continue
+		}
+		locations = append(locations, &location)
+	}
+
+	return locations, nil
+}
+
+func loginHistoryKey(userID string) string {
+	return fmt.Sprintf("login_history:%s", userID)
+}
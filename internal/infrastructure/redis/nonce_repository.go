@@ -0,0 +1,38 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// NonceRepository is the Redis implementation of the nonce repository
+type NonceRepository struct {
+	client *redis.Client
+	logger *zap.Logger
+}
+
+// NewNonceRepository creates a new instance of NonceRepository
+func NewNonceRepository(client *redis.Client, logger *zap.Logger) *NonceRepository {
+	return &NonceRepository{
+		client: client,
+		logger: logger,
+	}
+}
+
+// Use marks the nonce of the client as used and returns false if it already was
+func (r *NonceRepository) Use(ctx context.Context, clientID, nonce string, ttl time.Duration) (bool, error) {
+	first, err := r.client.SetNX(ctx, clientNonceKey(clientID, nonce), time.Now().Unix(), ttl).Result()
+	if err != nil {
+		r.logger.Error("failed to record request nonce", zap.Error(err), zap.String("client_id", clientID))
+		return false, fmt.Errorf("failed to record request nonce: %w", err)
+	}
+	return first, nil
+}
+
+func clientNonceKey(clientID, nonce string) string {
+	return fmt.Sprintf("client_nonce:%s:%s", clientID, nonce)
+}
@@ -0,0 +1,69 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// PasswordResetRepository is the Redis implementation of the password reset repository
+type PasswordResetRepository struct {
+	client *redis.Client
+	logger *zap.Logger
+}
+
+// NewPasswordResetRepository creates a new instance of PasswordResetRepository
+func NewPasswordResetRepository(client *redis.Client, logger *zap.Logger) *PasswordResetRepository {
+	return &PasswordResetRepository{
+		client: client,
+		logger: logger,
+	}
+}
+
+// Store stores a pending reset under the hash of its token until it expires
+func (r *PasswordResetRepository) Store(ctx context.Context, tokenHash string, reset *domain.PasswordReset, ttl time.Duration) error {
+	jsonData, err := json.Marshal(reset)
+	if err != nil {
+		r.logger.Error("failed to marshal password reset", zap.Error(err))
+		return fmt.Errorf("failed to marshal password reset: %w", err)
+	}
+
+	if err := r.client.Set(ctx, passwordResetKey(tokenHash), jsonData, ttl).Err(); err != nil {
+		r.logger.Error("failed to store password reset", zap.Error(err), zap.String("user_id", reset.UserID))
+		return fmt.Errorf("failed to store password reset: %w", err)
+	}
+
+	return nil
+}
+
+// Consume retrieves and deletes a pending reset in a single command, so concurrent uses of a token
+// can't both succeed
+func (r *PasswordResetRepository) Consume(ctx context.Context, tokenHash string) (*domain.PasswordReset, error) {
+	jsonData, err := r.client.GetDel(ctx, passwordResetKey(tokenHash)).Result()
+	if err == redis.Nil {
+		return nil, domainerrors.ErrInvalidResetToken
+	}
+	if err != nil {
+		r.logger.Error("failed to consume password reset", zap.Error(err))
+		return nil, fmt.Errorf("failed to consume password reset: %w", err)
+	}
+
+	var reset domain.PasswordReset
+	if err := json.Unmarshal([]byte(jsonData), &reset); err != nil {
+		r.logger.Error("failed to unmarshal password reset", zap.Error(err))
+		return nil, fmt.Errorf("failed to unmarshal password reset: %w", err)
+	}
+
+	return &reset, nil
+}
+
+func passwordResetKey(tokenHash string) string {
+	return fmt.Sprintf("password_reset:%s", tokenHash)
+}
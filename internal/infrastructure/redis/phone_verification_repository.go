@@ -0,0 +1,122 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// PhoneVerificationRepository is the Redis implementation of the phone verification repository
+type PhoneVerificationRepository struct {
+	client    *redis.Client
+	keyPrefix string
+	logger    *zap.Logger
+}
+
+// NewPhoneVerificationRepository creates a new instance of PhoneVerificationRepository for the codes
+// confirming phone-number enrollments
+func NewPhoneVerificationRepository(client *redis.Client, logger *zap.Logger) *PhoneVerificationRepository {
+	return &PhoneVerificationRepository{
+		client:    client,
+		keyPrefix: "phone_verification",
+		logger:    logger,
+	}
+}
+
+// NewPhoneLoginCodeRepository creates a new instance of PhoneVerificationRepository for the one-time
+// login codes sent to verified phone numbers, kept apart from the enrollment codes
+func NewPhoneLoginCodeRepository(client *redis.Client, logger *zap.Logger) *PhoneVerificationRepository {
+	return &PhoneVerificationRepository{
+		client:    client,
+		keyPrefix: "phone_login_code",
+		logger:    logger,
+	}
+}
+
+// Store stores a pending verification until it expires, replacing any previous one of the user
+func (r *PhoneVerificationRepository) Store(ctx context.Context, verification *domain.PhoneVerification) error {
+	ttl := time.Until(verification.ExpiresAt)
+	if ttl <= 0 {
+		return domainerrors.ErrInvalidVerificationCode
+	}
+
+	jsonData, err := json.Marshal(verification)
+	if err != nil {
+		r.logger.Error("failed to marshal phone verification", zap.Error(err))
+		return fmt.Errorf("failed to marshal phone verification: %w", err)
+	}
+
+	if err := r.client.Set(ctx, r.key(verification.UserID), jsonData, ttl).Err(); err != nil {
+		r.logger.Error("failed to store phone verification", zap.Error(err), zap.String("user_id", verification.UserID))
+		return fmt.Errorf("failed to store phone verification: %w", err)
+	}
+
+	r.logger.Debug("phone verification stored successfully", zap.String("user_id", verification.UserID))
+	return nil
+}
+
+// Consume atomically removes the pending verification of a user and returns it.
+// Only the first caller gets the verification; later callers get ErrInvalidVerificationCode.
+func (r *PhoneVerificationRepository) Consume(ctx context.Context, userID string) (*domain.PhoneVerification, error) {
+	jsonData, err := r.client.GetDel(ctx, r.key(userID)).Result()
+	if err == redis.Nil {
+		return nil, domainerrors.ErrInvalidVerificationCode
+	}
+	if err != nil {
+		r.logger.Error("failed to consume phone verification", zap.Error(err), zap.String("user_id", userID))
+		return nil, fmt.Errorf("failed to consume phone verification: %w", err)
+	}
+
+	var verification domain.PhoneVerification
+	if err := json.Unmarshal([]byte(jsonData), &verification); err != nil {
+		r.logger.Error("failed to unmarshal phone verification", zap.Error(err))
+		return nil, fmt.Errorf("failed to unmarshal phone verification: %w", err)
+	}
+
+	return &verification, nil
+}
+
+// Restore stores back a verification taken by Consume until it expires. A verification stored in the
+// meantime, e.g. a new code sent to the user, is kept.
+func (r *PhoneVerificationRepository) Restore(ctx context.Context, verification *domain.PhoneVerification) error {
+	ttl := time.Until(verification.ExpiresAt)
+	if ttl <= 0 {
+		return domainerrors.ErrInvalidVerificationCode
+	}
+
+	jsonData, err := json.Marshal(verification)
+	if err != nil {
+		r.logger.Error("failed to marshal phone verification", zap.Error(err))
+		return fmt.Errorf("failed to marshal phone verification: %w", err)
+	}
+
+	if err := r.client.SetNX(ctx, r.key(verification.UserID), jsonData, ttl).Err(); err != nil {
+		r.logger.Error("failed to restore phone verification", zap.Error(err), zap.String("user_id", verification.UserID))
+		return fmt.Errorf("failed to restore phone verification: %w", err)
+	}
+
+	r.logger.Debug("phone verification restored successfully", zap.String("user_id", verification.UserID))
+	return nil
+}
+
+// Delete removes the pending verification of a user
+func (r *PhoneVerificationRepository) Delete(ctx context.Context, userID string) error {
+	if err := r.client.Del(ctx, r.key(userID)).Err(); err != nil {
+		r.logger.Error("failed to delete phone verification", zap.Error(err), zap.String("user_id", userID))
+		return fmt.Errorf("failed to delete phone verification: %w", err)
+	}
+
+	r.logger.Debug("phone verification deleted successfully", zap.String("user_id", userID))
+	return nil
+}
+
+func (r *PhoneVerificationRepository) key(userID string) string {
+	return fmt.Sprintf("%s:%s", r.keyPrefix, userID)
+}
@@ -0,0 +1,50 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// ProcessedMessageRepository is the Redis implementation of the processed message repository.
+// Messages are remembered for the TTL, which must exceed the time a message can be redelivered within.
+type ProcessedMessageRepository struct {
+	client *redis.Client
+	ttl    time.Duration
+	logger *zap.Logger
+}
+
+// NewProcessedMessageRepository creates a new instance of ProcessedMessageRepository
+func NewProcessedMessageRepository(client *redis.Client, ttl time.Duration, logger *zap.Logger) *ProcessedMessageRepository {
+	return &ProcessedMessageRepository{
+		client: client,
+		ttl:    ttl,
+		logger: logger,
+	}
+}
+
+// MarkProcessed marks the message as processed and returns false if it already was
+func (r *ProcessedMessageRepository) MarkProcessed(ctx context.Context, queue, messageID string) (bool, error) {
+	first, err := r.client.SetNX(ctx, processedMessageKey(queue, messageID), time.Now().Unix(), r.ttl).Result()
+	if err != nil {
+		r.logger.Error("failed to mark message as processed", zap.Error(err), zap.String("queue", queue), zap.String("message_id", messageID))
+		return false, fmt.Errorf("failed to mark message as processed: %w", err)
+	}
+	return first, nil
+}
+
+// Unmark forgets the message so its redelivery is processed again
+func (r *ProcessedMessageRepository) Unmark(ctx context.Context, queue, messageID string) error {
+	if err := r.client.Del(ctx, processedMessageKey(queue, messageID)).Err(); err != nil {
+		r.logger.Error("failed to unmark processed message", zap.Error(err), zap.String("queue", queue), zap.String("message_id", messageID))
+		return fmt.Errorf("failed to unmark processed message: %w", err)
+	}
+	return nil
+}
+
+func processedMessageKey(queue, messageID string) string {
+	return fmt.Sprintf("processed_message:%s:%s", queue, messageID)
+}
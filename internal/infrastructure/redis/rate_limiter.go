@@ -0,0 +1,82 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// RateLimiter is the Redis implementation of a fixed-window rate limiter
+type RateLimiter struct {
+	client *redis.Client
+	limit  int
+	window time.Duration
+	logger *zap.Logger
+}
+
+// NewRateLimiter creates a new instance of RateLimiter allowing limit requests per window
+func NewRateLimiter(client *redis.Client, limit int, window time.Duration, logger *zap.Logger) *RateLimiter {
+	return &RateLimiter{
+		client: client,
+		limit:  limit,
+		window: window,
+		logger: logger,
+	}
+}
+
+// Hit increments the request counter of the key, starting a new window when none is active
+func (r *RateLimiter) Hit(ctx context.Context, key string) (*domain.RateLimitStatus, error) {
+	redisKey := rateLimitKey(key)
+
+	pipe := r.client.TxPipeline()
+	incr := pipe.Incr(ctx, redisKey)
+	pipe.ExpireNX(ctx, redisKey, r.window)
+	ttl := pipe.PTTL(ctx, redisKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Error("failed to record rate limit hit", zap.Error(err), zap.String("key", key))
+		return nil, fmt.Errorf("failed to record rate limit hit: %w", err)
+	}
+
+	return r.status(incr.Val(), ttl.Val()), nil
+}
+
+// Peek returns the usage of the key in the current window without counting a request
+func (r *RateLimiter) Peek(ctx context.Context, key string) (*domain.RateLimitStatus, error) {
+	redisKey := rateLimitKey(key)
+
+	pipe := r.client.Pipeline()
+	count := pipe.Get(ctx, redisKey)
+	ttl := pipe.PTTL(ctx, redisKey)
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		r.logger.Error("failed to get rate limit status", zap.Error(err), zap.String("key", key))
+		return nil, fmt.Errorf("failed to get rate limit status: %w", err)
+	}
+
+	used, err := count.Int64()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to parse rate limit counter: %w", err)
+	}
+
+	return r.status(used, ttl.Val()), nil
+}
+
+// status builds the rate-limit status, treating keys without TTL as a fresh window
+func (r *RateLimiter) status(used int64, ttl time.Duration) *domain.RateLimitStatus {
+	if ttl <= 0 {
+		ttl = r.window
+	}
+	return &domain.RateLimitStatus{
+		Limit:   r.limit,
+		Used:    int(used),
+		ResetAt: time.Now().Add(ttl),
+	}
+}
+
+func rateLimitKey(key string) string {
+	return fmt.Sprintf("rate_limit:%s", key)
+}
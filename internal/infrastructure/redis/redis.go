@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -11,6 +12,7 @@ import (
 
 	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
 	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/retry"
 )
 
 // TokenRepository is the Redis implementation of the token repository
@@ -37,8 +39,10 @@ func (r *TokenRepository) StoreRefreshToken(ctx context.Context, token string, d
 		return fmt.Errorf("failed to marshal token data: %w", err)
 	}
 
-	err = r.client.Set(ctx, key, jsonData, ttl).Err()
-	if err != nil {
+	pipe := r.client.TxPipeline()
+	pipe.Set(ctx, key, jsonData, ttl)
+	r.indexSession(ctx, pipe, data.IDCitizen, token, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
 		r.logger.Error("failed to store refresh token", zap.Error(err), zap.String("key", key))
 		return fmt.Errorf("failed to store refresh token: %w", err)
 	}
@@ -110,60 +114,276 @@ func (r *TokenRepository) IsTokenBlacklisted(ctx context.Context, token string)
 	return exists > 0, nil
 }
 
-// DeleteUserTokens deletes all refresh tokens of a user
-func (r *TokenRepository) DeleteUserTokens(ctx context.Context, idCitizen int) error {
-	pattern := "refresh_token:*"
+// GetActiveRefreshToken retrieves a refresh token and checks its blacklist entry in a single round-trip
+func (r *TokenRepository) GetActiveRefreshToken(ctx context.Context, token string) (*domain.RefreshTokenData, error) {
+	key := fmt.Sprintf("refresh_token:%s", token)
+
+	pipe := r.client.Pipeline()
+	blacklisted := pipe.Exists(ctx, fmt.Sprintf("blacklist:%s", token))
+	get := pipe.Get(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		r.logger.Error("failed to get active refresh token", zap.Error(err), zap.String("key", key))
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	if blacklisted.Val() > 0 {
+		return nil, domainerrors.ErrTokenRevoked
+	}
+
+	jsonData, err := get.Result()
+	if err == redis.Nil {
+		return nil, domainerrors.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	var data domain.RefreshTokenData
+	if err := json.Unmarshal([]byte(jsonData), &data); err != nil {
+		r.logger.Error("failed to unmarshal refresh token data", zap.Error(err))
+		return nil, fmt.Errorf("failed to unmarshal token data: %w", err)
+	}
+
+	return &data, nil
+}
+
+// RotateRefreshToken deletes the old refresh token and stores the new one in a MULTI/EXEC transaction
+func (r *TokenRepository) RotateRefreshToken(ctx context.Context, oldToken, newToken string, data *domain.RefreshTokenData, ttl time.Duration) error {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		r.logger.Error("failed to marshal refresh token data", zap.Error(err))
+		return fmt.Errorf("failed to marshal token data: %w", err)
+	}
+
+	pipe := r.client.TxPipeline()
+	pipe.Del(ctx, fmt.Sprintf("refresh_token:%s", oldToken))
+	pipe.Set(ctx, fmt.Sprintf("refresh_token:%s", newToken), jsonData, ttl)
+	pipe.ZRem(ctx, userSessionsKey(data.IDCitizen), oldToken)
+	r.indexSession(ctx, pipe, data.IDCitizen, newToken, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Error("failed to rotate refresh token", zap.Error(err), zap.Int("id_citizen", data.IDCitizen))
+		return fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+
+	r.logger.Debug("refresh token rotated successfully", zap.Int("id_citizen", data.IDCitizen))
+	return nil
+}
 
-	iter := r.client.Scan(ctx, 0, pattern, 0).Iterator()
-	for iter.Next(ctx) {
-		key := iter.Val()
+// RevokeSession blacklists the access token and deletes the refresh token in a MULTI/EXEC transaction
+func (r *TokenRepository) RevokeSession(ctx context.Context, accessToken string, ttl time.Duration, refreshToken string) error {
+	if ttl <= 0 && refreshToken == "" {
+		return nil
+	}
 
-		jsonData, err := r.client.Get(ctx, key).Result()
+	pipe := r.client.TxPipeline()
+	if ttl > 0 {
+		pipe.Set(ctx, fmt.Sprintf("blacklist:%s", accessToken), "1", ttl)
+	}
+	if refreshToken != "" {
+		pipe.Del(ctx, fmt.Sprintf("refresh_token:%s", refreshToken))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Error("failed to revoke session", zap.Error(err))
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	r.logger.Debug("session revoked successfully")
+	return nil
+}
+
+// deleteUserTokensScanCount is the number of keys requested per SCAN page when deleting user tokens
+const deleteUserTokensScanCount = 100
+
+// DeleteUserTokens deletes all refresh tokens of a user, those issued to the user ID or to the citizen ID.
+// Each SCAN page is read with a single MGET and the matching keys are deleted with a single DEL.
+func (r *TokenRepository) DeleteUserTokens(ctx context.Context, userID string, idCitizen int) error {
+	pattern := "refresh_token:*"
+	deleted := 0
+
+	var cursor uint64
+	for {
+		keys, next, err := r.client.Scan(ctx, cursor, pattern, deleteUserTokensScanCount).Result()
 		if err != nil {
-			continue
+			r.logger.Error("failed to iterate user tokens", zap.Error(err), zap.Int("id_citizen", idCitizen))
+			return fmt.Errorf("failed to delete user tokens: %w", err)
 		}
 
-		var data domain.RefreshTokenData
-		if err := json.Unmarshal([]byte(jsonData), &data); err != nil {
-			continue
-		}
+		if len(keys) > 0 {
+			values, err := r.client.MGet(ctx, keys...).Result()
+			if err != nil {
+				r.logger.Error("failed to read user tokens", zap.Error(err), zap.Int("id_citizen", idCitizen))
+				return fmt.Errorf("failed to delete user tokens: %w", err)
+			}
 
-		if data.IDCitizen == idCitizen {
-			if err := r.client.Del(ctx, key).Err(); err != nil {
-				r.logger.Error("failed to delete user token", zap.Error(err), zap.String("key", key))
+			var userKeys []string
+			for i, value := range values {
+				jsonData, ok := value.(string)
+				if !ok {
+					continue
+				}
+
+				var data domain.RefreshTokenData
+				if err := json.Unmarshal([]byte(jsonData), &data); err != nil {
+					continue
+				}
+
+				if data.IDCitizen == idCitizen || (userID != "" && data.UserID == userID) {
+					userKeys = append(userKeys, keys[i])
+				}
 			}
+
+			if len(userKeys) > 0 {
+				if err := r.client.Del(ctx, userKeys...).Err(); err != nil {
+					r.logger.Error("failed to delete user tokens", zap.Error(err), zap.Int("id_citizen", idCitizen))
+				} else {
+					deleted += len(userKeys)
+				}
+			}
+		}
+
+		cursor = next
+		if cursor == 0 {
+			break
 		}
 	}
 
-	if err := iter.Err(); err != nil {
-		r.logger.Error("failed to iterate user tokens", zap.Error(err), zap.Int("id_citizen", idCitizen))
-		return fmt.Errorf("failed to delete user tokens: %w", err)
+	if err := r.client.Del(ctx, userSessionsKey(idCitizen)).Err(); err != nil {
+		r.logger.Error("failed to delete user session index", zap.Error(err), zap.Int("id_citizen", idCitizen))
+	}
+
+	r.logger.Info("user tokens deleted successfully", zap.String("user_id", userID), zap.Int("id_citizen", idCitizen), zap.Int("deleted", deleted))
+	return nil
+}
+
+// SetTokenVersion sets the minimum token version of the access tokens of a user for ttl
+func (r *TokenRepository) SetTokenVersion(ctx context.Context, idCitizen int, version int, ttl time.Duration) error {
+	key := tokenVersionKey(idCitizen)
+
+	if err := r.client.Set(ctx, key, version, ttl).Err(); err != nil {
+		r.logger.Error("failed to set token version", zap.Error(err), zap.Int("id_citizen", idCitizen))
+		return fmt.Errorf("failed to set token version: %w", err)
 	}
 
-	r.logger.Info("user tokens deleted successfully", zap.Int("id_citizen", idCitizen))
+	r.logger.Debug("token version set successfully", zap.Int("id_citizen", idCitizen), zap.Int("version", version))
 	return nil
 }
 
+// GetTokenVersion returns the minimum token version of the access tokens of a user, 0 when none is set
+func (r *TokenRepository) GetTokenVersion(ctx context.Context, idCitizen int) (int, error) {
+	version, err := r.client.Get(ctx, tokenVersionKey(idCitizen)).Int()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		r.logger.Error("failed to get token version", zap.Error(err), zap.Int("id_citizen", idCitizen))
+		return 0, fmt.Errorf("failed to get token version: %w", err)
+	}
+
+	return version, nil
+}
+
+// CountActiveSessions counts the refresh tokens of the session index of a user that are still stored.
+// Entries of expired or deleted refresh tokens are pruned from the index, so every revocation path
+// (logout, rotation, revoke all) is reflected without maintaining the index there.
+func (r *TokenRepository) CountActiveSessions(ctx context.Context, idCitizen int) (int, error) {
+	key := userSessionsKey(idCitizen)
+
+	pipe := r.client.TxPipeline()
+	pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(time.Now().Unix(), 10))
+	members := pipe.ZRange(ctx, key, 0, -1)
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Error("failed to get user session index", zap.Error(err), zap.Int("id_citizen", idCitizen))
+		return 0, fmt.Errorf("failed to count active sessions: %w", err)
+	}
+
+	tokens := members.Val()
+	if len(tokens) == 0 {
+		return 0, nil
+	}
+
+	existsPipe := r.client.Pipeline()
+	exists := make([]*redis.IntCmd, len(tokens))
+	for i, token := range tokens {
+		exists[i] = existsPipe.Exists(ctx, fmt.Sprintf("refresh_token:%s", token))
+	}
+	if _, err := existsPipe.Exec(ctx); err != nil {
+		r.logger.Error("failed to check user sessions", zap.Error(err), zap.Int("id_citizen", idCitizen))
+		return 0, fmt.Errorf("failed to count active sessions: %w", err)
+	}
+
+	active := 0
+	var stale []interface{}
+	for i, token := range tokens {
+		if exists[i].Val() > 0 {
+			active++
+		} else {
+			stale = append(stale, token)
+		}
+	}
+	if len(stale) > 0 {
+		if err := r.client.ZRem(ctx, key, stale...).Err(); err != nil {
+			r.logger.Warn("failed to prune user session index", zap.Error(err), zap.Int("id_citizen", idCitizen))
+		}
+	}
+
+	return active, nil
+}
+
+// indexSession adds a refresh token to the session index of its user, scored by its expiration
+func (r *TokenRepository) indexSession(ctx context.Context, pipe redis.Pipeliner, idCitizen int, token string, ttl time.Duration) {
+	key := userSessionsKey(idCitizen)
+	pipe.ZAdd(ctx, key, redis.Z{Score: float64(time.Now().Add(ttl).Unix()), Member: token})
+	pipe.Expire(ctx, key, ttl)
+}
+
+func userSessionsKey(idCitizen int) string {
+	return fmt.Sprintf("user_sessions:%d", idCitizen)
+}
+
+func tokenVersionKey(idCitizen int) string {
+	return fmt.Sprintf("token_version:%d", idCitizen)
+}
+
+// commandRetryPolicy is how the client retries commands, the go-redis defaults. The client applies its
+// own jitter to the backoff.
+var commandRetryPolicy = retry.Policy{MaxAttempts: 4, InitialBackoff: 8 * time.Millisecond, MaxBackoff: 512 * time.Millisecond}
+
 // NewRedisClient creates a new connection to Redis
-func NewRedisClient(address, password string, db int, logger *zap.Logger) (*redis.Client, error) {
-	client := redis.NewClient(&redis.Options{
-		Addr:         address,
-		Password:     password,
-		DB:           db,
-		DialTimeout:  5 * time.Second,
-		ReadTimeout:  3 * time.Second,
-		WriteTimeout: 3 * time.Second,
-		PoolSize:     10,
-		MinIdleConns: 5,
-	})
+func NewRedisClient(address, password string, db int, commandTimeout time.Duration, logger *zap.Logger) (*redis.Client, error) {
+	client := OpenRedisClient(address, password, db, commandTimeout)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
 		return nil, fmt.Errorf("failed to ping redis: %w", err)
 	}
 
 	logger.Info("redis connection established successfully")
 	return client, nil
 }
+
+// OpenRedisClient creates the Redis client without checking connectivity,
+// which is left to the caller (e.g. the startup dependency checks).
+// Each command is bounded by commandTimeout, 0 disables it.
+func OpenRedisClient(address, password string, db int, commandTimeout time.Duration) *redis.Client {
+	client := redis.NewClient(&redis.Options{
+		Addr:         address,
+		Password:     password,
+		DB:           db,
+		DialTimeout:  5 * time.Second,
+		ReadTimeout:  3 * time.Second,
+		WriteTimeout: 3 * time.Second,
+		PoolSize:     10,
+		MinIdleConns: 5,
+		// Commands failing on network errors are retried by the client
+		MaxRetries:      commandRetryPolicy.MaxAttempts - 1,
+		MinRetryBackoff: commandRetryPolicy.InitialBackoff,
+		MaxRetryBackoff: commandRetryPolicy.MaxBackoff,
+		// Deadlines of the command context are applied to the connection
+		ContextTimeoutEnabled: true,
+	})
+	client.AddHook(NewTimeoutHook(commandTimeout))
+	return client
+}
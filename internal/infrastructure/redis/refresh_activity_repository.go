@@ -0,0 +1,70 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// RefreshActivityRepository is the Redis implementation of the refresh activity repository.
+// Each token family keeps two sorted sets scored by time, the IP addresses it was refreshed from and
+// its refreshes, trimmed to the activity window on every refresh.
+type RefreshActivityRepository struct {
+	client *redis.Client
+	window time.Duration
+	logger *zap.Logger
+}
+
+// NewRefreshActivityRepository creates a new instance of RefreshActivityRepository
+func NewRefreshActivityRepository(client *redis.Client, window time.Duration, logger *zap.Logger) *RefreshActivityRepository {
+	return &RefreshActivityRepository{
+		client: client,
+		window: window,
+		logger: logger,
+	}
+}
+
+// Record adds a refresh of the family and returns the activity of the family within the window
+func (r *RefreshActivityRepository) Record(ctx context.Context, familyID, ip string) (*domain.RefreshActivity, error) {
+	now := time.Now()
+	score := float64(now.UnixMilli())
+	cutoff := strconv.FormatInt(now.Add(-r.window).UnixMilli(), 10)
+	ipsKey := refreshFamilyIPsKey(familyID)
+	refreshesKey := refreshFamilyRefreshesKey(familyID)
+
+	pipe := r.client.TxPipeline()
+	if ip != "" {
+		pipe.ZAdd(ctx, ipsKey, redis.Z{Score: score, Member: ip})
+	}
+	pipe.ZAdd(ctx, refreshesKey, redis.Z{Score: score, Member: uuid.New().String()})
+	pipe.ZRemRangeByScore(ctx, ipsKey, "-inf", "("+cutoff)
+	pipe.ZRemRangeByScore(ctx, refreshesKey, "-inf", "("+cutoff)
+	distinctIPs := pipe.ZCard(ctx, ipsKey)
+	refreshes := pipe.ZCard(ctx, refreshesKey)
+	pipe.Expire(ctx, ipsKey, r.window)
+	pipe.Expire(ctx, refreshesKey, r.window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Error("failed to record refresh activity", zap.Error(err), zap.String("family_id", familyID))
+		return nil, fmt.Errorf("failed to record refresh activity: %w", err)
+	}
+
+	return &domain.RefreshActivity{
+		DistinctIPs: int(distinctIPs.Val()),
+		Refreshes:   int(refreshes.Val()),
+	}, nil
+}
+
+func refreshFamilyIPsKey(familyID string) string {
+	return fmt.Sprintf("refresh_family:%s:ips", familyID)
+}
+
+func refreshFamilyRefreshesKey(familyID string) string {
+	return fmt.Sprintf("refresh_family:%s:refreshes", familyID)
+}
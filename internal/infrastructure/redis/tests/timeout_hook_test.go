@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/redis"
+)
+
+func TestTimeoutHook(t *testing.T) {
+	tests := []struct {
+		name         string
+		timeout      time.Duration
+		wantDeadline bool
+	}{
+		{name: "bounds command", timeout: time.Second, wantDeadline: true},
+		{name: "disabled", timeout: 0, wantDeadline: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hook := redis.NewTimeoutHook(tt.timeout)
+
+			var gotDeadline bool
+			process := hook.ProcessHook(func(ctx context.Context, cmd goredis.Cmder) error {
+				_, gotDeadline = ctx.Deadline()
+				return nil
+			})
+			if err := process(context.Background(), goredis.NewStatusCmd(context.Background(), "ping")); err != nil {
+				t.Fatalf("ProcessHook() error = %v", err)
+			}
+			if gotDeadline != tt.wantDeadline {
+				t.Errorf("command deadline = %v, want %v", gotDeadline, tt.wantDeadline)
+			}
+
+			gotDeadline = false
+			pipeline := hook.ProcessPipelineHook(func(ctx context.Context, cmds []goredis.Cmder) error {
+				_, gotDeadline = ctx.Deadline()
+				return nil
+			})
+			if err := pipeline(context.Background(), nil); err != nil {
+				t.Fatalf("ProcessPipelineHook() error = %v", err)
+			}
+			if gotDeadline != tt.wantDeadline {
+				t.Errorf("pipeline deadline = %v, want %v", gotDeadline, tt.wantDeadline)
+			}
+		})
+	}
+}
+
+func TestTimeoutHook_StuckCommand(t *testing.T) {
+	hook := redis.NewTimeoutHook(10 * time.Millisecond)
+
+	process := hook.ProcessHook(func(ctx context.Context, cmd goredis.Cmder) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	err := process(context.Background(), goredis.NewStringCmd(context.Background(), "get", "key"))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("ProcessHook() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
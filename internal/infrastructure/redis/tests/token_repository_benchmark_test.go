@@ -0,0 +1,149 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/redis"
+)
+
+// The benchmarks compare the sequential token operations with the pipelined ones used by
+// refresh and logout. They need a running Redis and are skipped otherwise:
+//
+//	REDIS_BENCH_ADDR=localhost:6379 go test -run '^$' -bench . -benchmem ./internal/infrastructure/redis/tests/
+//
+// Use -cpu to simulate concurrent load, e.g. -cpu 1,8,32.
+
+const benchTokenTTL = time.Minute
+
+var benchTokenSeq atomic.Int64
+
+func newBenchTokenRepository(b *testing.B) *redis.TokenRepository {
+	b.Helper()
+
+	addr := os.Getenv("REDIS_BENCH_ADDR")
+	if addr == "" {
+		b.Skip("REDIS_BENCH_ADDR not set, skipping Redis benchmarks")
+	}
+
+	client, err := redis.NewRedisClient(addr, os.Getenv("REDIS_BENCH_PASSWORD"), 0, 0, zap.NewNop())
+	if err != nil {
+		b.Skipf("redis not available at %s: %v", addr, err)
+	}
+	b.Cleanup(func() { _ = client.Close() })
+
+	return redis.NewTokenRepository(client, zap.NewNop())
+}
+
+// newBenchRefreshToken stores a fresh refresh token, safe to call from RunParallel goroutines
+func newBenchRefreshToken(ctx context.Context, repo *redis.TokenRepository, data *domain.RefreshTokenData) (string, error) {
+	token := fmt.Sprintf("bench-%d-%d", time.Now().UnixNano(), benchTokenSeq.Add(1))
+	return token, repo.StoreRefreshToken(ctx, token, data, benchTokenTTL)
+}
+
+// BenchmarkRefreshRotation_Sequential issues the four round-trips refresh used to perform
+func BenchmarkRefreshRotation_Sequential(b *testing.B) {
+	repo := newBenchTokenRepository(b)
+	ctx := context.Background()
+	data := &domain.RefreshTokenData{IDCitizen: 1, Email: "bench@example.com"}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		token, err := newBenchRefreshToken(ctx, repo, data)
+		if err != nil {
+			b.Error(err)
+			return
+		}
+		for pb.Next() {
+			if _, err := repo.IsTokenBlacklisted(ctx, token); err != nil {
+				b.Error(err)
+				return
+			}
+			if _, err := repo.GetRefreshToken(ctx, token); err != nil {
+				b.Error(err)
+				return
+			}
+			if err := repo.DeleteRefreshToken(ctx, token); err != nil {
+				b.Error(err)
+				return
+			}
+			if token, err = newBenchRefreshToken(ctx, repo, data); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
+
+// BenchmarkRefreshRotation_Pipelined performs the same work in two round-trips
+func BenchmarkRefreshRotation_Pipelined(b *testing.B) {
+	repo := newBenchTokenRepository(b)
+	ctx := context.Background()
+	data := &domain.RefreshTokenData{IDCitizen: 1, Email: "bench@example.com"}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		token, err := newBenchRefreshToken(ctx, repo, data)
+		if err != nil {
+			b.Error(err)
+			return
+		}
+		for pb.Next() {
+			if _, err := repo.GetActiveRefreshToken(ctx, token); err != nil {
+				b.Error(err)
+				return
+			}
+			newToken := fmt.Sprintf("bench-%d-%d", time.Now().UnixNano(), benchTokenSeq.Add(1))
+			if err := repo.RotateRefreshToken(ctx, token, newToken, data, benchTokenTTL); err != nil {
+				b.Error(err)
+				return
+			}
+			token = newToken
+		}
+	})
+}
+
+// BenchmarkLogout_Sequential blacklists the access token and deletes the refresh token separately
+func BenchmarkLogout_Sequential(b *testing.B) {
+	repo := newBenchTokenRepository(b)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			n := benchTokenSeq.Add(1)
+			if err := repo.BlacklistToken(ctx, fmt.Sprintf("bench-access-%d", n), benchTokenTTL); err != nil {
+				b.Error(err)
+				return
+			}
+			if err := repo.DeleteRefreshToken(ctx, fmt.Sprintf("bench-refresh-%d", n)); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
+
+// BenchmarkLogout_Pipelined revokes both tokens in a single round-trip
+func BenchmarkLogout_Pipelined(b *testing.B) {
+	repo := newBenchTokenRepository(b)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			n := benchTokenSeq.Add(1)
+			if err := repo.RevokeSession(ctx, fmt.Sprintf("bench-access-%d", n), benchTokenTTL, fmt.Sprintf("bench-refresh-%d", n)); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
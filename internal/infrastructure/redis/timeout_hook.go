@@ -0,0 +1,63 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/kristianrpo/auth-microservice/internal/observability/metrics"
+)
+
+// timeoutHook bounds every command and pipeline with a timeout, so a stuck Redis fails the call
+// instead of holding the request until the server write timeout
+type timeoutHook struct {
+	timeout time.Duration
+}
+
+// NewTimeoutHook creates a hook that bounds each command or pipeline with timeout, 0 disables it
+func NewTimeoutHook(timeout time.Duration) redis.Hook {
+	return timeoutHook{timeout: timeout}
+}
+
+// DialHook leaves dialing to the client dial timeout
+func (h timeoutHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+// ProcessHook bounds a single command
+func (h timeoutHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		return h.run(ctx, cmd.Name(), func(ctx context.Context) error {
+			return next(ctx, cmd)
+		})
+	}
+}
+
+// ProcessPipelineHook bounds a whole pipeline or transaction, which is a single round-trip
+func (h timeoutHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		return h.run(ctx, "pipeline", func(ctx context.Context) error {
+			return next(ctx, cmds)
+		})
+	}
+}
+
+func (h timeoutHook) run(ctx context.Context, command string, fn func(ctx context.Context) error) error {
+	if h.timeout <= 0 {
+		return fn(ctx)
+	}
+
+	cmdCtx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	err := fn(cmdCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(cmdCtx.Err(), context.DeadlineExceeded) {
+		metrics.IncRedisCommandTimeout(command)
+	}
+	return err
+}
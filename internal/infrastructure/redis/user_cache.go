@@ -0,0 +1,76 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// UserCache is the Redis implementation of the user cache.
+// Users are stored as JSON and expire after the TTL, which bounds how stale a cached user can be
+// when an invalidation is missed.
+type UserCache struct {
+	client *redis.Client
+	ttl    time.Duration
+	logger *zap.Logger
+}
+
+// NewUserCache creates a new instance of UserCache
+func NewUserCache(client *redis.Client, ttl time.Duration, logger *zap.Logger) *UserCache {
+	return &UserCache{
+		client: client,
+		ttl:    ttl,
+		logger: logger,
+	}
+}
+
+// Get returns the cached user with the citizen ID, nil when it is not cached
+func (c *UserCache) Get(ctx context.Context, idCitizen int) (*domain.UserPublic, error) {
+	data, err := c.client.Get(ctx, userCacheKey(idCitizen)).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		c.logger.Error("failed to get cached user", zap.Error(err), zap.Int("id_citizen", idCitizen))
+		return nil, fmt.Errorf("failed to get cached user: %w", err)
+	}
+
+	var user domain.UserPublic
+	if err := json.Unmarshal(data, &user); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cached user: %w", err)
+	}
+	return &user, nil
+}
+
+// Set caches the user until the TTL expires
+func (c *UserCache) Set(ctx context.Context, user *domain.UserPublic) error {
+	data, err := json.Marshal(user)
+	if err != nil {
+		return fmt.Errorf("failed to marshal user: %w", err)
+	}
+
+	if err := c.client.Set(ctx, userCacheKey(user.IDCitizen), data, c.ttl).Err(); err != nil {
+		c.logger.Error("failed to cache user", zap.Error(err), zap.Int("id_citizen", user.IDCitizen))
+		return fmt.Errorf("failed to cache user: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the cached user with the citizen ID
+func (c *UserCache) Delete(ctx context.Context, idCitizen int) error {
+	if err := c.client.Del(ctx, userCacheKey(idCitizen)).Err(); err != nil {
+		c.logger.Error("failed to delete cached user", zap.Error(err), zap.Int("id_citizen", idCitizen))
+		return fmt.Errorf("failed to delete cached user: %w", err)
+	}
+	return nil
+}
+
+func userCacheKey(idCitizen int) string {
+	return fmt.Sprintf("user_cache:%d", idCitizen)
+}
@@ -0,0 +1,116 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	domainerrors "github.com/kristianrpo/auth-microservice/internal/domain/errors"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// UserExportJobRepository is the Redis implementation of the user export job repository
+type UserExportJobRepository struct {
+	client *redis.Client
+	logger *zap.Logger
+}
+
+// NewUserExportJobRepository creates a new instance of UserExportJobRepository
+func NewUserExportJobRepository(client *redis.Client, logger *zap.Logger) *UserExportJobRepository {
+	return &UserExportJobRepository{
+		client: client,
+		logger: logger,
+	}
+}
+
+// Store stores a new job until it expires.
+// The user ID is stored as a secondary key pointing to the latest job of the user.
+func (r *UserExportJobRepository) Store(ctx context.Context, job *domain.UserExportJob) error {
+	ttl := time.Until(job.ExpiresAt)
+	if ttl <= 0 {
+		return domainerrors.ErrExportJobNotFound
+	}
+
+	jsonData, err := json.Marshal(job)
+	if err != nil {
+		r.logger.Error("failed to marshal export job", zap.Error(err))
+		return fmt.Errorf("failed to marshal export job: %w", err)
+	}
+
+	pipe := r.client.TxPipeline()
+	pipe.Set(ctx, exportJobKey(job.ID), jsonData, ttl)
+	pipe.Set(ctx, latestExportJobKey(job.UserID), job.ID, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Error("failed to store export job", zap.Error(err), zap.String("user_id", job.UserID))
+		return fmt.Errorf("failed to store export job: %w", err)
+	}
+
+	r.logger.Debug("export job stored successfully", zap.String("job_id", job.ID))
+	return nil
+}
+
+// Get retrieves a job by its ID
+func (r *UserExportJobRepository) Get(ctx context.Context, id string) (*domain.UserExportJob, error) {
+	jsonData, err := r.client.Get(ctx, exportJobKey(id)).Result()
+	if err == redis.Nil {
+		return nil, domainerrors.ErrExportJobNotFound
+	}
+	if err != nil {
+		r.logger.Error("failed to get export job", zap.Error(err))
+		return nil, fmt.Errorf("failed to get export job: %w", err)
+	}
+
+	var job domain.UserExportJob
+	if err := json.Unmarshal([]byte(jsonData), &job); err != nil {
+		r.logger.Error("failed to unmarshal export job", zap.Error(err))
+		return nil, fmt.Errorf("failed to unmarshal export job: %w", err)
+	}
+
+	return &job, nil
+}
+
+// GetLatestByUser retrieves the latest job of a user
+func (r *UserExportJobRepository) GetLatestByUser(ctx context.Context, userID string) (*domain.UserExportJob, error) {
+	id, err := r.client.Get(ctx, latestExportJobKey(userID)).Result()
+	if err == redis.Nil {
+		return nil, domainerrors.ErrExportJobNotFound
+	}
+	if err != nil {
+		r.logger.Error("failed to resolve latest export job", zap.Error(err), zap.String("user_id", userID))
+		return nil, fmt.Errorf("failed to resolve latest export job: %w", err)
+	}
+
+	return r.Get(ctx, id)
+}
+
+// Update replaces a job keeping its expiration
+func (r *UserExportJobRepository) Update(ctx context.Context, job *domain.UserExportJob) error {
+	jsonData, err := json.Marshal(job)
+	if err != nil {
+		r.logger.Error("failed to marshal export job", zap.Error(err))
+		return fmt.Errorf("failed to marshal export job: %w", err)
+	}
+
+	ok, err := r.client.SetXX(ctx, exportJobKey(job.ID), jsonData, redis.KeepTTL).Result()
+	if err != nil {
+		r.logger.Error("failed to update export job", zap.Error(err), zap.String("job_id", job.ID))
+		return fmt.Errorf("failed to update export job: %w", err)
+	}
+	if !ok {
+		return domainerrors.ErrExportJobNotFound
+	}
+
+	return nil
+}
+
+func exportJobKey(id string) string {
+	return fmt.Sprintf("user_export_job:%s", id)
+}
+
+func latestExportJobKey(userID string) string {
+	return fmt.Sprintf("user_export_latest:%s", userID)
+}
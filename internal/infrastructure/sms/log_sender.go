@@ -0,0 +1,31 @@
+package sms
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// LogSender writes messages to the log instead of sending them, for local development
+type LogSender struct {
+	logger *zap.Logger
+}
+
+// NewLogSender creates a new log sender
+func NewLogSender(logger *zap.Logger) *LogSender {
+	return &LogSender{logger: logger}
+}
+
+// Provider returns the name of the provider
+func (s *LogSender) Provider() string {
+	return "log"
+}
+
+// Send logs the message; the body is only logged at debug level since it carries one-time codes
+func (s *LogSender) Send(ctx context.Context, phoneNumber, message string) error {
+	s.logger.Info("sms not sent, log provider configured", zap.String("to", domain.MaskPhoneNumber(phoneNumber)))
+	s.logger.Debug("sms message", zap.String("to", phoneNumber), zap.String("message", message))
+	return nil
+}
@@ -0,0 +1,180 @@
+package sms
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+const (
+	snsService    = "sns"
+	snsAPIVersion = "2010-03-31"
+	sigV4Algo     = "AWS4-HMAC-SHA256"
+	formMediaType = "application/x-www-form-urlencoded; charset=utf-8"
+)
+
+// SNSSender sends SMS through the Amazon SNS Publish API.
+// Requests are signed with AWS Signature Version 4 using static credentials.
+type SNSSender struct {
+	endpoint        string
+	region          string
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+	senderID        string
+	httpClient      *http.Client
+	logger          *zap.Logger
+}
+
+// snsErrorResponse is the error body returned by the SNS query API
+type snsErrorResponse struct {
+	Error struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	} `xml:"Error"`
+}
+
+// NewSNSSender creates a new SNS sender.
+// An empty endpoint defaults to the regional SNS endpoint; sessionToken and senderID are optional.
+func NewSNSSender(endpoint, region, accessKeyID, secretAccessKey, sessionToken, senderID string, logger *zap.Logger) *SNSSender {
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://sns.%s.amazonaws.com", region)
+	}
+	return &SNSSender{
+		endpoint:        strings.TrimRight(endpoint, "/"),
+		region:          region,
+		accessKeyID:     accessKeyID,
+		secretAccessKey: secretAccessKey,
+		sessionToken:    sessionToken,
+		senderID:        senderID,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		logger: logger,
+	}
+}
+
+// Provider returns the name of the provider
+func (s *SNSSender) Provider() string {
+	return "sns"
+}
+
+// Send delivers a text message to a phone number in E.164 format as a transactional SMS
+func (s *SNSSender) Send(ctx context.Context, phoneNumber, message string) error {
+	form := url.Values{}
+	form.Set("Action", "Publish")
+	form.Set("Version", snsAPIVersion)
+	form.Set("PhoneNumber", phoneNumber)
+	form.Set("Message", message)
+	// Transactional messages are optimized for reliability, as required for one-time codes
+	form.Set("MessageAttributes.entry.1.Name", "AWS.SNS.SMS.SMSType")
+	form.Set("MessageAttributes.entry.1.Value.DataType", "String")
+	form.Set("MessageAttributes.entry.1.Value.StringValue", "Transactional")
+	if s.senderID != "" {
+		form.Set("MessageAttributes.entry.2.Name", "AWS.SNS.SMS.SenderID")
+		form.Set("MessageAttributes.entry.2.Value.DataType", "String")
+		form.Set("MessageAttributes.entry.2.Value.StringValue", s.senderID)
+	}
+	body := form.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint+"/", strings.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create sns request: %w", err)
+	}
+	req.Header.Set("Content-Type", formMediaType)
+	s.sign(req, body, time.Now().UTC())
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		s.logger.Error("failed to call sns", zap.Error(err))
+		return fmt.Errorf("failed to call sns: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var errResp snsErrorResponse
+		_ = xml.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&errResp)
+		s.logger.Error("sns rejected message",
+			zap.Int("status_code", resp.StatusCode),
+			zap.String("sns_code", errResp.Error.Code),
+			zap.String("sns_message", errResp.Error.Message),
+			zap.String("to", domain.MaskPhoneNumber(phoneNumber)))
+		return fmt.Errorf("sns request failed with status %d: %s", resp.StatusCode, errResp.Error.Code)
+	}
+
+	s.logger.Debug("sms sent through sns", zap.String("to", domain.MaskPhoneNumber(phoneNumber)))
+	return nil
+}
+
+// sign adds the AWS Signature Version 4 headers to the request
+func (s *SNSSender) sign(req *http.Request, body string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	dateStamp := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if s.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.sessionToken)
+	}
+
+	canonicalHeaders := "content-type:" + formMediaType + "\n" +
+		"host:" + req.URL.Host + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+	signedHeaders := "content-type;host;x-amz-date"
+	if s.sessionToken != "" {
+		canonicalHeaders += "x-amz-security-token:" + s.sessionToken + "\n"
+		signedHeaders += ";x-amz-security-token"
+	}
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		hexSHA256(body),
+	}, "\n")
+
+	credentialScope := strings.Join([]string{dateStamp, s.region, snsService, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		sigV4Algo,
+		amzDate,
+		credentialScope,
+		hexSHA256(canonicalRequest),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.secretAccessKey), dateStamp)
+	signingKey = hmacSHA256(signingKey, s.region)
+	signingKey = hmacSHA256(signingKey, snsService)
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algo, s.accessKeyID, credentialScope, signedHeaders, signature))
+}
+
+func hexSHA256(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
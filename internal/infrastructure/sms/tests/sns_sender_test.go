@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/sms"
+)
+
+var sigV4Pattern = regexp.MustCompile(`^AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/(\d{8})/us-east-1/sns/aws4_request, SignedHeaders=(\S+), Signature=[0-9a-f]{64}$`)
+
+func TestSNSSender_Send(t *testing.T) {
+	tests := []struct {
+		name          string
+		sessionToken  string
+		senderID      string
+		statusCode    int
+		body          string
+		wantErr       bool
+		signedHeaders string
+	}{
+		{
+			name:          "message published",
+			statusCode:    http.StatusOK,
+			body:          `<PublishResponse><PublishResult><MessageId>abc</MessageId></PublishResult></PublishResponse>`,
+			signedHeaders: "content-type;host;x-amz-date",
+		},
+		{
+			name:          "temporary credentials and sender id",
+			sessionToken:  "session",
+			senderID:      "AUTH",
+			statusCode:    http.StatusOK,
+			body:          `<PublishResponse/>`,
+			signedHeaders: "content-type;host;x-amz-date;x-amz-security-token",
+		},
+		{
+			name:          "rejected",
+			statusCode:    http.StatusBadRequest,
+			body:          `<ErrorResponse><Error><Code>InvalidParameter</Code><Message>Invalid phone number</Message></Error></ErrorResponse>`,
+			wantErr:       true,
+			signedHeaders: "content-type;host;x-amz-date",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				match := sigV4Pattern.FindStringSubmatch(r.Header.Get("Authorization"))
+				if match == nil {
+					t.Errorf("Authorization = %q, want a SigV4 signature", r.Header.Get("Authorization"))
+				} else {
+					if match[1] != time.Now().UTC().Format("20060102") {
+						t.Errorf("credential date = %v", match[1])
+					}
+					if match[2] != tt.signedHeaders {
+						t.Errorf("SignedHeaders = %v, want %v", match[2], tt.signedHeaders)
+					}
+				}
+				if r.Header.Get("X-Amz-Date") == "" {
+					t.Error("X-Amz-Date header missing")
+				}
+				if got := r.Header.Get("X-Amz-Security-Token"); got != tt.sessionToken {
+					t.Errorf("X-Amz-Security-Token = %v, want %v", got, tt.sessionToken)
+				}
+
+				if err := r.ParseForm(); err != nil {
+					t.Fatalf("failed to parse form: %v", err)
+				}
+				if r.PostForm.Get("Action") != "Publish" || r.PostForm.Get("PhoneNumber") != "+573001234567" || r.PostForm.Get("Message") != "hello" {
+					t.Errorf("form = %v", r.PostForm)
+				}
+				if r.PostForm.Get("MessageAttributes.entry.1.Value.StringValue") != "Transactional" {
+					t.Errorf("SMSType = %v, want Transactional", r.PostForm.Get("MessageAttributes.entry.1.Value.StringValue"))
+				}
+				if got := r.PostForm.Get("MessageAttributes.entry.2.Value.StringValue"); got != tt.senderID {
+					t.Errorf("SenderID = %v, want %v", got, tt.senderID)
+				}
+
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			sender := sms.NewSNSSender(server.URL, "us-east-1", "AKIDEXAMPLE", "secret", tt.sessionToken, tt.senderID, zap.NewNop())
+			err := sender.Send(context.Background(), "+573001234567", "hello")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Send() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/sms"
+)
+
+func TestTwilioSender_Send(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		wantErr    bool
+	}{
+		{name: "message queued", statusCode: http.StatusCreated, body: `{"sid":"SM123","status":"queued"}`},
+		{name: "invalid number", statusCode: http.StatusBadRequest, body: `{"code":21211,"message":"Invalid 'To' Phone Number"}`, wantErr: true},
+		{name: "bad credentials", statusCode: http.StatusUnauthorized, body: `{"code":20003,"message":"Authenticate"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/2010-04-01/Accounts/AC123/Messages.json" {
+					t.Errorf("request = %s %s", r.Method, r.URL.Path)
+				}
+				user, pass, ok := r.BasicAuth()
+				if !ok || user != "AC123" || pass != "secret" {
+					t.Errorf("basic auth = %v/%v/%v, want AC123/secret", user, pass, ok)
+				}
+				if err := r.ParseForm(); err != nil {
+					t.Fatalf("failed to parse form: %v", err)
+				}
+				if r.PostForm.Get("To") != "+573001234567" || r.PostForm.Get("From") != "+15005550006" || r.PostForm.Get("Body") != "hello" {
+					t.Errorf("form = %v", r.PostForm)
+				}
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			sender := sms.NewTwilioSender(server.URL+"/", "AC123", "secret", "+15005550006", zap.NewNop())
+			err := sender.Send(context.Background(), "+573001234567", "hello")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Send() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
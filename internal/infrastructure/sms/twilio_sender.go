@@ -0,0 +1,90 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+// TwilioSender sends SMS through the Twilio Programmable Messaging API
+type TwilioSender struct {
+	baseURL    string
+	accountSID string
+	authToken  string
+	from       string
+	httpClient *http.Client
+	logger     *zap.Logger
+}
+
+// twilioErrorResponse is the error body returned by the Twilio API
+type twilioErrorResponse struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// NewTwilioSender creates a new Twilio sender.
+// baseURL is the API root, https://api.twilio.com in production.
+func NewTwilioSender(baseURL, accountSID, authToken, from string, logger *zap.Logger) *TwilioSender {
+	return &TwilioSender{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		accountSID: accountSID,
+		authToken:  authToken,
+		from:       from,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		logger: logger,
+	}
+}
+
+// Provider returns the name of the provider
+func (s *TwilioSender) Provider() string {
+	return "twilio"
+}
+
+// Send delivers a text message to a phone number in E.164 format
+func (s *TwilioSender) Send(ctx context.Context, phoneNumber, message string) error {
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", s.baseURL, url.PathEscape(s.accountSID))
+
+	form := url.Values{}
+	form.Set("To", phoneNumber)
+	form.Set("From", s.from)
+	form.Set("Body", message)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create twilio request: %w", err)
+	}
+	req.SetBasicAuth(s.accountSID, s.authToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		s.logger.Error("failed to call twilio", zap.Error(err))
+		return fmt.Errorf("failed to call twilio: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var errResp twilioErrorResponse
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&errResp)
+		s.logger.Error("twilio rejected message",
+			zap.Int("status_code", resp.StatusCode),
+			zap.Int("twilio_code", errResp.Code),
+			zap.String("twilio_message", errResp.Message),
+			zap.String("to", domain.MaskPhoneNumber(phoneNumber)))
+		return fmt.Errorf("twilio request failed with status %d: %s", resp.StatusCode, errResp.Message)
+	}
+
+	s.logger.Debug("sms sent through twilio", zap.String("to", domain.MaskPhoneNumber(phoneNumber)))
+	return nil
+}
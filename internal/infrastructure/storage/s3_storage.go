@@ -0,0 +1,352 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+)
+
+const (
+	s3Service = "s3"
+	sigV4Algo = "AWS4-HMAC-SHA256"
+
+	// unsignedPayload skips the hash of uploaded bodies, which are streamed and can't be read twice
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+
+	// maxSignedURLExpiry is the longest validity of a presigned URL accepted by S3
+	maxSignedURLExpiry = 7 * 24 * time.Hour
+)
+
+// S3Storage stores objects in an S3-compatible bucket (Amazon S3, MinIO) through the S3 REST API.
+// Requests are signed with AWS Signature Version 4 using static credentials.
+type S3Storage struct {
+	endpoint        *url.URL
+	region          string
+	bucket          string
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+	usePathStyle    bool
+	httpClient      *http.Client
+	logger          *zap.Logger
+}
+
+// s3ErrorResponse is the error body returned by the S3 REST API
+type s3ErrorResponse struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+// s3ListResponse is the body returned by ListObjectsV2
+type s3ListResponse struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+}
+
+// NewS3Storage creates a new S3 storage.
+// An empty endpoint defaults to the regional S3 endpoint; sessionToken is optional. MinIO and most
+// S3-compatible servers require path-style addressing (endpoint/bucket/key) instead of bucket subdomains.
+func NewS3Storage(endpoint, region, bucket, accessKeyID, secretAccessKey, sessionToken string, usePathStyle bool, logger *zap.Logger) (*S3Storage, error) {
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+	}
+	endpointURL, err := url.Parse(strings.TrimRight(endpoint, "/"))
+	if err != nil || endpointURL.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", endpoint)
+	}
+
+	return &S3Storage{
+		endpoint:        endpointURL,
+		region:          region,
+		bucket:          bucket,
+		accessKeyID:     accessKeyID,
+		secretAccessKey: secretAccessKey,
+		sessionToken:    sessionToken,
+		usePathStyle:    usePathStyle,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		logger: logger,
+	}, nil
+}
+
+// Put stores size bytes read from body under key
+func (s *S3Storage) Put(ctx context.Context, key, contentType string, body io.Reader, size int64) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key, nil), body)
+	if err != nil {
+		return fmt.Errorf("failed to create s3 request: %w", err)
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+	s.sign(req, unsignedPayload, time.Now().UTC())
+
+	if err := s.do(req, http.StatusOK); err != nil {
+		return fmt.Errorf("failed to put object: %w", err)
+	}
+
+	s.logger.Debug("object stored", zap.String("bucket", s.bucket), zap.String("key", key), zap.Int64("size", size))
+	return nil
+}
+
+// Delete removes the object stored under key
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(key, nil), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create s3 request: %w", err)
+	}
+	s.sign(req, hexSHA256(""), time.Now().UTC())
+
+	// S3 answers 204 whether the object existed or not, some compatible servers answer 404
+	if err := s.do(req, http.StatusNoContent, http.StatusOK, http.StatusNotFound); err != nil {
+		return fmt.Errorf("failed to delete object: %w", err)
+	}
+
+	s.logger.Debug("object deleted", zap.String("bucket", s.bucket), zap.String("key", key))
+	return nil
+}
+
+// List returns up to limit objects whose key starts with prefix, in key order after startAfter
+func (s *S3Storage) List(ctx context.Context, prefix, startAfter string, limit int) ([]domain.BlobObject, error) {
+	query := url.Values{}
+	query.Set("list-type", "2")
+	query.Set("prefix", prefix)
+	query.Set("max-keys", strconv.Itoa(limit))
+	if startAfter != "" {
+		query.Set("start-after", startAfter)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.objectURL("", query), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create s3 request: %w", err)
+	}
+	s.sign(req, hexSHA256(""), time.Now().UTC())
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		s.logger.Error("failed to call s3", zap.Error(err))
+		return nil, fmt.Errorf("failed to call s3: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, s.responseError(resp)
+	}
+
+	var list s3ListResponse
+	if err := xml.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("failed to decode s3 list response: %w", err)
+	}
+
+	objects := make([]domain.BlobObject, 0, len(list.Contents))
+	for _, content := range list.Contents {
+		objects = append(objects, domain.BlobObject{
+			Key:          content.Key,
+			Size:         content.Size,
+			LastModified: content.LastModified,
+		})
+	}
+	return objects, nil
+}
+
+// SignedURL returns a presigned GET URL of the object stored under key, valid for up to 7 days
+func (s *S3Storage) SignedURL(key string, expiresIn time.Duration) (string, error) {
+	if expiresIn <= 0 || expiresIn > maxSignedURLExpiry {
+		return "", fmt.Errorf("signed url expiry must be between 1s and %s", maxSignedURLExpiry)
+	}
+
+	now := time.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	credentialScope := s.credentialScope(now)
+
+	objectURL, err := url.Parse(s.objectURL(key, nil))
+	if err != nil {
+		return "", fmt.Errorf("failed to build object url: %w", err)
+	}
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", sigV4Algo)
+	query.Set("X-Amz-Credential", s.accessKeyID+"/"+credentialScope)
+	query.Set("X-Amz-Date", amzDate)
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expiresIn.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+	if s.sessionToken != "" {
+		query.Set("X-Amz-Security-Token", s.sessionToken)
+	}
+	canonicalQuery := canonicalQueryString(query)
+
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		objectURL.EscapedPath(),
+		canonicalQuery,
+		"host:" + objectURL.Host + "\n",
+		"host",
+		unsignedPayload,
+	}, "\n")
+
+	objectURL.RawQuery = canonicalQuery + "&X-Amz-Signature=" + s.signature(now, canonicalRequest)
+	return objectURL.String(), nil
+}
+
+// objectURL returns the URL of the object stored under key, or of the bucket when key is empty
+func (s *S3Storage) objectURL(key string, query url.Values) string {
+	host := s.endpoint.Host
+	path := "/" + uriEncode(key, false)
+	if s.usePathStyle {
+		path = "/" + s.bucket + path
+	} else {
+		host = s.bucket + "." + host
+	}
+
+	objectURL := s.endpoint.Scheme + "://" + host + strings.TrimRight(s.endpoint.Path, "/") + path
+	if len(query) > 0 {
+		objectURL += "?" + canonicalQueryString(query)
+	}
+	return objectURL
+}
+
+// do sends the request and checks the response has one of the expected status codes
+func (s *S3Storage) do(req *http.Request, expected ...int) error {
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		s.logger.Error("failed to call s3", zap.Error(err))
+		return fmt.Errorf("failed to call s3: %w", err)
+	}
+	defer resp.Body.Close()
+
+	for _, status := range expected {
+		if resp.StatusCode == status {
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			return nil
+		}
+	}
+	return s.responseError(resp)
+}
+
+// responseError logs and returns the error of a failed S3 response
+func (s *S3Storage) responseError(resp *http.Response) error {
+	var errResp s3ErrorResponse
+	_ = xml.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&errResp)
+	s.logger.Error("s3 rejected request",
+		zap.Int("status_code", resp.StatusCode),
+		zap.String("s3_code", errResp.Code),
+		zap.String("s3_message", errResp.Message),
+		zap.String("bucket", s.bucket))
+	return fmt.Errorf("s3 request failed with status %d: %s", resp.StatusCode, errResp.Code)
+}
+
+// sign adds the AWS Signature Version 4 headers to the request
+func (s *S3Storage) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.sessionToken)
+	}
+
+	canonicalHeaders := "host:" + req.URL.Host + "\n" +
+		"x-amz-content-sha256:" + payloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	if s.sessionToken != "" {
+		canonicalHeaders += "x-amz-security-token:" + s.sessionToken + "\n"
+		signedHeaders += ";x-amz-security-token"
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQueryString(req.URL.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algo, s.accessKeyID, s.credentialScope(now), signedHeaders, s.signature(now, canonicalRequest)))
+}
+
+// credentialScope returns the scope of the signing key of the day
+func (s *S3Storage) credentialScope(now time.Time) string {
+	return strings.Join([]string{now.Format("20060102"), s.region, s3Service, "aws4_request"}, "/")
+}
+
+// signature signs the canonical request with the signing key of the day
+func (s *S3Storage) signature(now time.Time, canonicalRequest string) string {
+	stringToSign := strings.Join([]string{
+		sigV4Algo,
+		now.Format("20060102T150405Z"),
+		s.credentialScope(now),
+		hexSHA256(canonicalRequest),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.secretAccessKey), now.Format("20060102"))
+	signingKey = hmacSHA256(signingKey, s.region)
+	signingKey = hmacSHA256(signingKey, s3Service)
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+}
+
+// canonicalQueryString encodes the query sorted by parameter name, as required by Signature Version 4
+func canonicalQueryString(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			parts = append(parts, uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode percent-encodes every byte but the unreserved characters, and the slashes unless encodeSlash
+func uriEncode(value string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hexSHA256(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,197 @@
+package tests
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/storage"
+)
+
+var s3SigV4Pattern = regexp.MustCompile(`^AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/\d{8}/us-east-1/s3/aws4_request, SignedHeaders=(\S+), Signature=[0-9a-f]{64}$`)
+
+func newTestS3Storage(t *testing.T, handler http.HandlerFunc) *storage.S3Storage {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	s3Storage, err := storage.NewS3Storage(server.URL, "us-east-1", "avatars-bucket", "AKIDEXAMPLE", "secret", "", true, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewS3Storage() error = %v", err)
+	}
+	return s3Storage
+}
+
+func checkSignature(t *testing.T, r *http.Request) {
+	t.Helper()
+	match := s3SigV4Pattern.FindStringSubmatch(r.Header.Get("Authorization"))
+	if match == nil {
+		t.Errorf("Authorization = %q, want a SigV4 signature", r.Header.Get("Authorization"))
+		return
+	}
+	if match[1] != "host;x-amz-content-sha256;x-amz-date" {
+		t.Errorf("SignedHeaders = %v", match[1])
+	}
+	if r.Header.Get("X-Amz-Date") == "" || r.Header.Get("X-Amz-Content-Sha256") == "" {
+		t.Error("missing X-Amz-Date or X-Amz-Content-Sha256 header")
+	}
+}
+
+func TestS3Storage_Put(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		wantErr    bool
+	}{
+		{name: "object stored", statusCode: http.StatusOK},
+		{name: "rejected", statusCode: http.StatusForbidden, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s3Storage := newTestS3Storage(t, func(w http.ResponseWriter, r *http.Request) {
+				checkSignature(t, r)
+				if r.Method != http.MethodPut || r.URL.Path != "/avatars-bucket/avatars/user-123/a.png" {
+					t.Errorf("request = %v %v, want PUT /avatars-bucket/avatars/user-123/a.png", r.Method, r.URL.Path)
+				}
+				if r.Header.Get("Content-Type") != "image/png" || r.ContentLength != 5 {
+					t.Errorf("Content-Type = %v, Content-Length = %v", r.Header.Get("Content-Type"), r.ContentLength)
+				}
+				body, _ := io.ReadAll(r.Body)
+				if string(body) != "image" {
+					t.Errorf("body = %q, want image", body)
+				}
+				w.WriteHeader(tt.statusCode)
+				if tt.statusCode != http.StatusOK {
+					_, _ = w.Write([]byte(`<Error><Code>AccessDenied</Code><Message>Access Denied</Message></Error>`))
+				}
+			})
+
+			err := s3Storage.Put(context.Background(), "avatars/user-123/a.png", "image/png", strings.NewReader("image"), 5)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Put() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestS3Storage_Delete(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		wantErr    bool
+	}{
+		{name: "object deleted", statusCode: http.StatusNoContent},
+		{name: "missing object", statusCode: http.StatusNotFound},
+		{name: "server error", statusCode: http.StatusInternalServerError, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s3Storage := newTestS3Storage(t, func(w http.ResponseWriter, r *http.Request) {
+				checkSignature(t, r)
+				if r.Method != http.MethodDelete || r.URL.Path != "/avatars-bucket/avatars/user-123/a.png" {
+					t.Errorf("request = %v %v", r.Method, r.URL.Path)
+				}
+				w.WriteHeader(tt.statusCode)
+			})
+
+			err := s3Storage.Delete(context.Background(), "avatars/user-123/a.png")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Delete() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestS3Storage_List(t *testing.T) {
+	s3Storage := newTestS3Storage(t, func(w http.ResponseWriter, r *http.Request) {
+		checkSignature(t, r)
+		query := r.URL.Query()
+		if r.URL.Path != "/avatars-bucket/" || query.Get("list-type") != "2" || query.Get("prefix") != "avatars/" ||
+			query.Get("start-after") != "avatars/a.png" || query.Get("max-keys") != "2" {
+			t.Errorf("request = %v?%v", r.URL.Path, r.URL.RawQuery)
+		}
+		_, _ = w.Write([]byte(`<ListBucketResult>
+			<Contents><Key>avatars/b.png</Key><Size>120</Size><LastModified>2024-01-02T03:04:05.000Z</LastModified></Contents>
+			<Contents><Key>avatars/c.png</Key><Size>80</Size><LastModified>2024-01-03T03:04:05.000Z</LastModified></Contents>
+		</ListBucketResult>`))
+	})
+
+	objects, err := s3Storage.List(context.Background(), "avatars/", "avatars/a.png", 2)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(objects) != 2 || objects[0].Key != "avatars/b.png" || objects[0].Size != 120 || objects[1].Key != "avatars/c.png" {
+		t.Fatalf("List() = %+v", objects)
+	}
+	if want := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC); !objects[0].LastModified.Equal(want) {
+		t.Errorf("LastModified = %v, want %v", objects[0].LastModified, want)
+	}
+}
+
+func TestS3Storage_SignedURL(t *testing.T) {
+	tests := []struct {
+		name         string
+		usePathStyle bool
+		expiresIn    time.Duration
+		wantHost     string
+		wantPath     string
+		wantErr      bool
+	}{
+		{name: "path style", usePathStyle: true, expiresIn: 15 * time.Minute, wantHost: "minio.example.com:9000", wantPath: "/avatars-bucket/avatars/user-123/a.png"},
+		{name: "virtual host", expiresIn: time.Hour, wantHost: "avatars-bucket.minio.example.com:9000", wantPath: "/avatars/user-123/a.png"},
+		{name: "expiry too long", usePathStyle: true, expiresIn: 8 * 24 * time.Hour, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s3Storage, err := storage.NewS3Storage("http://minio.example.com:9000", "us-east-1", "avatars-bucket", "AKIDEXAMPLE", "secret", "token", tt.usePathStyle, zap.NewNop())
+			if err != nil {
+				t.Fatalf("NewS3Storage() error = %v", err)
+			}
+
+			signedURL, err := s3Storage.SignedURL("avatars/user-123/a.png", tt.expiresIn)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SignedURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			parsed, err := url.Parse(signedURL)
+			if err != nil {
+				t.Fatalf("invalid signed url %q: %v", signedURL, err)
+			}
+			if parsed.Host != tt.wantHost || parsed.Path != tt.wantPath {
+				t.Errorf("signed url = %v, want host %v and path %v", signedURL, tt.wantHost, tt.wantPath)
+			}
+			query := parsed.Query()
+			if query.Get("X-Amz-Algorithm") != "AWS4-HMAC-SHA256" || query.Get("X-Amz-SignedHeaders") != "host" ||
+				query.Get("X-Amz-Security-Token") != "token" || len(query.Get("X-Amz-Signature")) != 64 {
+				t.Errorf("signed url query = %v", parsed.RawQuery)
+			}
+			if query.Get("X-Amz-Expires") != strconv.Itoa(int(tt.expiresIn.Seconds())) {
+				t.Errorf("X-Amz-Expires = %v, want %v", query.Get("X-Amz-Expires"), tt.expiresIn.Seconds())
+			}
+			if !strings.HasPrefix(query.Get("X-Amz-Credential"), "AKIDEXAMPLE/") {
+				t.Errorf("X-Amz-Credential = %v", query.Get("X-Amz-Credential"))
+			}
+		})
+	}
+}
+
+func TestNewS3Storage_InvalidEndpoint(t *testing.T) {
+	if _, err := storage.NewS3Storage("not a url", "us-east-1", "bucket", "id", "secret", "", true, zap.NewNop()); err == nil {
+		t.Error("NewS3Storage() error = nil, want invalid endpoint")
+	}
+}
@@ -0,0 +1,101 @@
+// Package logging sanitizes the user-controlled values written to the logs (emails, names, client_ids,
+// paths...), so a value can't forge log lines or inject terminal escape sequences in the console output.
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+)
+
+// MaxValueLength is the maximum length in runes of a sanitized value, longer values are truncated
+const MaxValueLength = 256
+
+// truncatedSuffix marks a value truncated to MaxValueLength
+const truncatedSuffix = "...(truncated)"
+
+// String returns a zap field of the sanitized value
+func String(key, value string) zap.Field {
+	return zap.String(key, Sanitize(value))
+}
+
+// Strings returns a zap field of the sanitized values
+func Strings(key string, values []string) zap.Field {
+	sanitized := make([]string, len(values))
+	for i, value := range values {
+		sanitized[i] = Sanitize(value)
+	}
+	return zap.Strings(key, sanitized)
+}
+
+// Sanitize escapes the control characters of a value, including CR, LF and ESC, the Unicode line
+// separators, the bidirectional formatting characters and the invalid UTF-8 bytes, the way Go escapes them
+// in string literals (e.g. \n, \x1b, \u2028), and truncates it to MaxValueLength runes
+func Sanitize(value string) string {
+	if isSafe(value) {
+		return value
+	}
+
+	var b strings.Builder
+	b.Grow(len(value))
+	runes := 0
+	for i := 0; i < len(value); {
+		if runes == MaxValueLength {
+			b.WriteString(truncatedSuffix)
+			break
+		}
+
+		r, size := utf8.DecodeRuneInString(value[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			fmt.Fprintf(&b, `\x%02x`, value[i])
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r < 0x80 && isUnsafe(r):
+			fmt.Fprintf(&b, `\x%02x`, r)
+		case isUnsafe(r):
+			fmt.Fprintf(&b, `\u%04x`, r)
+		default:
+			b.WriteRune(r)
+		}
+		i += size
+		runes++
+	}
+	return b.String()
+}
+
+// isSafe checks if a value is short enough and has nothing to escape, so it is logged as it is
+func isSafe(value string) bool {
+	if len(value) > MaxValueLength && utf8.RuneCountInString(value) > MaxValueLength {
+		return false
+	}
+	for i := 0; i < len(value); {
+		r, size := utf8.DecodeRuneInString(value[i:])
+		if (r == utf8.RuneError && size == 1) || isUnsafe(r) {
+			return false
+		}
+		i += size
+	}
+	return true
+}
+
+// isUnsafe checks if a rune can alter how a log line is displayed or split: the C0 and C1 control
+// characters, DEL, the line and paragraph separators and the bidirectional formatting characters
+func isUnsafe(r rune) bool {
+	switch {
+	case r < 0x20, r >= 0x7f && r <= 0x9f:
+		return true
+	case r == 0x2028, r == 0x2029:
+		return true
+	case r >= 0x202a && r <= 0x202e, r >= 0x2066 && r <= 0x2069, r == 0x200e, r == 0x200f:
+		return true
+	default:
+		return false
+	}
+}
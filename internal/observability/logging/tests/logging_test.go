@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/kristianrpo/auth-microservice/internal/observability/logging"
+)
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "plain email", value: "user@example.com", want: "user@example.com"},
+		{name: "unicode name", value: "José Pérez 日本", want: "José Pérez 日本"},
+		{name: "CRLF forging a log line", value: "user@example.com\r\n{\"level\":\"info\",\"msg\":\"login successful\"}", want: `user@example.com\r\n{"level":"info","msg":"login successful"}`},
+		{name: "bare LF", value: "client\nadmin", want: `client\nadmin`},
+		{name: "tab", value: "a\tb", want: `a\tb`},
+		{name: "ANSI escape sequence", value: "\x1b[31mred\x1b[0m", want: `\x1b[31mred\x1b[0m`},
+		{name: "NUL and DEL", value: "a\x00b\x7f", want: `a\x00b\x7f`},
+		{name: "C1 control character", value: "a\u0085b", want: `a\u0085b`},
+		{name: "Unicode line separator", value: "a\u2028b\u2029c", want: `a\u2028b\u2029c`},
+		{name: "bidirectional override", value: "evil\u202etxt.exe", want: `evil\u202etxt.exe`},
+		{name: "invalid UTF-8", value: "a\xffb", want: `a\xffb`},
+		{name: "empty", value: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := logging.Sanitize(tt.value); got != tt.want {
+				t.Errorf("Sanitize(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitize_Truncates(t *testing.T) {
+	value := strings.Repeat("é", logging.MaxValueLength+10)
+
+	got := logging.Sanitize(value)
+	if !strings.HasSuffix(got, "...(truncated)") {
+		t.Fatalf("Sanitize() = %q, want a truncated value", got)
+	}
+	if n := utf8.RuneCountInString(strings.TrimSuffix(got, "...(truncated)")); n != logging.MaxValueLength {
+		t.Errorf("Sanitize() kept %d runes, want %d", n, logging.MaxValueLength)
+	}
+
+	// A value of exactly the maximum length is kept
+	value = strings.Repeat("é", logging.MaxValueLength)
+	if got := logging.Sanitize(value); got != value {
+		t.Errorf("Sanitize() = %q, want the value unchanged", got)
+	}
+}
+
+func TestStrings(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	zap.New(core).Info("test",
+		logging.String("email", "user@example.com\nforged"),
+		logging.Strings("redirect_uris", []string{"https://app.example.com/cb", "https://evil.example.com/\x1b[2J"}))
+
+	fields := logs.All()[0].ContextMap()
+	if fields["email"] != `user@example.com\nforged` {
+		t.Errorf("email = %q", fields["email"])
+	}
+	uris, _ := fields["redirect_uris"].([]interface{})
+	if len(uris) != 2 || uris[1] != `https://evil.example.com/\x1b[2J` {
+		t.Errorf("redirect_uris = %v", fields["redirect_uris"])
+	}
+}
+
+func TestString_ConsoleOutputStaysOnOneLine(t *testing.T) {
+	var buf bytes.Buffer
+	encoder := zapcore.NewConsoleEncoder(zap.NewDevelopmentEncoderConfig())
+	logger := zap.New(zapcore.NewCore(encoder, zapcore.AddSync(&buf), zap.DebugLevel))
+
+	logger.Warn("login failed", logging.String("email", "x@example.com\r\n2026-01-01T00:00:00Z\tINFO\tlogin successful"))
+
+	if lines := strings.Count(buf.String(), "\n"); lines != 1 {
+		t.Errorf("console output has %d lines, want 1: %q", lines, buf.String())
+	}
+}
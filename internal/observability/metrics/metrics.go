@@ -1,9 +1,11 @@
 package metrics
 
 import (
+	"database/sql"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
@@ -19,6 +21,16 @@ var (
 		Buckets: prometheus.DefBuckets,
 	}, []string{"method", "endpoint"})
 
+	httpPanicsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_service_http_panics_total",
+		Help: "Total number of HTTP handler panics recovered, by method and endpoint",
+	}, []string{"method", "endpoint"})
+
+	alertNotificationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_service_alert_notifications_total",
+		Help: "Total number of critical alerts sent to the alert webhook, by outcome (sent, failed or dropped)",
+	}, []string{"outcome"})
+
 	loginRequestsTotal = promauto.NewCounter(prometheus.CounterOpts{
 		Name: "auth_service_login_requests_total",
 		Help: "Total number of login requests received",
@@ -48,6 +60,245 @@ var (
 		Name: "auth_service_jwt_tokens_generated_total",
 		Help: "Total number of JWT tokens generated by the service",
 	})
+
+	passwordGrantRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_service_oauth_password_grant_requests_total",
+		Help: "Total number of deprecated OAuth password grant requests, by client and outcome",
+	}, []string{"client_id", "outcome"})
+
+	rateLimitExceededTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "auth_service_rate_limit_exceeded_total",
+		Help: "Total number of authenticated requests served over the soft rate limit",
+	})
+
+	passwordHashDurationSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "auth_service_password_hash_duration_seconds",
+		Help:    "Duration of password hash and compare operations",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"operation"})
+
+	passwordHashQueueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "auth_service_password_hash_queue_depth",
+		Help: "Number of password hashing operations waiting for a worker",
+	})
+
+	passwordHashQueueWaitSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "auth_service_password_hash_queue_wait_seconds",
+		Help:    "Time password hashing operations wait in the queue before a worker picks them up",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	externalConnectivityInFlightCalls = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "auth_service_external_connectivity_in_flight_calls",
+		Help: "Number of calls to the external-connectivity service in progress",
+	})
+
+	externalConnectivityQueuedCalls = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "auth_service_external_connectivity_queued_calls",
+		Help: "Number of calls to the external-connectivity service waiting for a free slot",
+	})
+
+	externalConnectivityQueueWaitSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "auth_service_external_connectivity_queue_wait_seconds",
+		Help:    "Time calls to the external-connectivity service wait for a free slot",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	externalConnectivityRejectedCallsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_service_external_connectivity_rejected_calls_total",
+		Help: "Total number of calls to the external-connectivity service rejected because the service is saturated",
+	}, []string{"reason"})
+
+	dbRetriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_service_db_retries_total",
+		Help: "Total number of database operations retried after a transient error, by operation and error class",
+	}, []string{"operation", "class"})
+
+	dbRetryOutcomesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_service_db_retry_outcomes_total",
+		Help: "Total number of retried database operations, by operation and outcome",
+	}, []string{"operation", "outcome"})
+
+	dbOperationTimeoutsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_service_db_operation_timeouts_total",
+		Help: "Total number of database operation attempts aborted by the per-operation timeout, by operation",
+	}, []string{"operation"})
+
+	dbQueryDurationSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "auth_service_db_query_duration_seconds",
+		Help:    "Duration of database query attempts, by operation",
+		Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"operation"})
+
+	dbPoolWaitSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "auth_service_db_pool_wait_seconds",
+		Help:    "Time database operations wait for a free connection when the pool is exhausted",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	dbPoolRejectedOperationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_service_db_pool_rejected_operations_total",
+		Help: "Total number of database operations rejected after waiting too long for a free connection, by operation",
+	}, []string{"operation"})
+
+	redisCommandTimeoutsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_service_redis_command_timeouts_total",
+		Help: "Total number of Redis commands aborted by the per-command timeout, by command",
+	}, []string{"command"})
+
+	oversizedTokensTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_service_oversized_tokens_total",
+		Help: "Total number of access tokens issued above the maximum token size, by token type",
+	}, []string{"type"})
+
+	smsMessagesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_service_sms_messages_total",
+		Help: "Total number of SMS handed to the provider, by provider and outcome",
+	}, []string{"provider", "outcome"})
+
+	smsRejectedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_service_sms_rejected_total",
+		Help: "Total number of SMS not sent because of a rate limit or the sending quota, by reason",
+	}, []string{"reason"})
+
+	loginAnomaliesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_service_login_anomalies_total",
+		Help: "Total number of logins flagged as risky by the geographic anomaly detection, by reason",
+	}, []string{"reason"})
+
+	riskDecisionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_service_risk_decisions_total",
+		Help: "Total number of risk policy decisions, by authentication flow and decision",
+	}, []string{"flow", "decision"})
+
+	loginFailuresTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_service_login_failures_total",
+		Help: "Total number of failed logins, by reason (unknown_user or invalid_password)",
+	}, []string{"reason"})
+
+	loginFailuresPerIP = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "auth_service_login_failures_per_ip",
+		Help:    "Failed logins from the same IP address within the failure window, observed on every failed login",
+		Buckets: []float64{1, 2, 5, 10, 20, 50, 100},
+	})
+
+	loginFailuresPerEmail = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "auth_service_login_failures_per_email",
+		Help:    "Failed logins for the same email address within the failure window, observed on every failed login",
+		Buckets: []float64{1, 2, 5, 10, 20, 50, 100},
+	})
+
+	loginLockoutsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "auth_service_login_lockouts_total",
+		Help: "Total number of logins denied by a risk policy rule on recent failed logins",
+	})
+
+	loginChallengesTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "auth_service_login_challenges_total",
+		Help: "Total number of step-up challenges required by a risk policy rule on recent failed logins",
+	})
+
+	bruteForceAlertsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_service_brute_force_alerts_total",
+		Help: "Total number of times the failed logins from an IP address or for an email address exceeded the alert threshold, by dimension (ip or email)",
+	}, []string{"dimension"})
+
+	oauthClientLockoutsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "auth_service_oauth_client_lockouts_total",
+		Help: "Total number of OAuth2 clients locked out after repeated authentication failures",
+	})
+
+	oauthClientTokensIssuedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_service_oauth_client_tokens_issued_total",
+		Help: "Total number of client credentials tokens issued, by the team and env tags of the client",
+	}, []string{"team", "env"})
+
+	loginNotificationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_service_login_notifications_total",
+		Help: "Total number of notifications of logins from a new device or IP address, by channel and result",
+	}, []string{"channel", "result"})
+
+	blacklistReplicationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_service_blacklist_replications_total",
+		Help: "Total number of blacklisted tokens replicated between regions, by direction (published or applied) and result",
+	}, []string{"direction", "result"})
+
+	refreshAnomaliesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_service_refresh_anomalies_total",
+		Help: "Total number of refreshes flagged as anomalous by the refresh token family analytics, by reason",
+	}, []string{"reason"})
+
+	refreshFamilyDistinctIPs = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "auth_service_refresh_family_distinct_ips",
+		Help:    "Distinct IP addresses a refresh token family was refreshed from within the activity window, observed on every refresh",
+		Buckets: []float64{1, 2, 3, 5, 8, 13},
+	})
+
+	refreshFamilyRefreshes = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "auth_service_refresh_family_refreshes",
+		Help:    "Refreshes of a refresh token family within the activity window, observed on every refresh",
+		Buckets: []float64{1, 2, 5, 10, 20, 50, 100},
+	})
+
+	quotaRejectionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_service_quota_rejections_total",
+		Help: "Total number of token issuances rejected by an issuance quota, by subject type (client or user) and quota (tokens_per_hour or active_sessions)",
+	}, []string{"subject_type", "quota"})
+
+	auditExportRecordsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_service_audit_export_records_total",
+		Help: "Total number of audit records sent to the external audit sink, by sink and outcome (exported or failed)",
+	}, []string{"sink", "outcome"})
+
+	auditExportBacklog = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "auth_service_audit_export_backlog",
+		Help: "Number of audit records waiting to be exported to the external audit sink",
+	})
+
+	signedRequestRejectionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_service_signed_request_rejections_total",
+		Help: "Total number of client_credentials requests rejected by the replay protection, by reason (unsigned, invalid_signature, stale, replayed)",
+	}, []string{"reason"})
+
+	userCacheLookupsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_service_user_cache_lookups_total",
+		Help: "Total number of user lookups by cache result (hit, miss or bypass), the hit ratio is hit / (hit + miss)",
+	}, []string{"result"})
+
+	adminExportsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_service_admin_exports_total",
+		Help: "Total number of admin dataset exports, by dataset (users or audit_log) and result (completed or failed)",
+	}, []string{"dataset", "result"})
+
+	adminExportRecordsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_service_admin_export_records_total",
+		Help: "Total number of records streamed by the admin dataset exports, by dataset",
+	}, []string{"dataset"})
+
+	userExportsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_service_user_exports_total",
+		Help: "Total number of personal data exports requested by users, by result (completed, failed or rejected)",
+	}, []string{"result"})
+
+	messagePublishesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_service_message_publishes_total",
+		Help: "Total number of messages published to the broker, by queue and result (confirmed or failed)",
+	}, []string{"queue", "result"})
+
+	messagePublishAttemptFailuresTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_service_message_publish_attempt_failures_total",
+		Help: "Total number of failed publish attempts, by queue and reason (nack, timeout or error)",
+	}, []string{"queue", "reason"})
+
+	outboxMessagesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_service_outbox_messages_total",
+		Help: "Total number of outbox operations on messages whose publication failed, by outcome",
+	}, []string{"outcome"})
+
+	consumedMessagesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_service_consumed_messages_total",
+		Help: "Total number of messages consumed from the broker, by queue and outcome",
+	}, []string{"queue", "outcome"})
 )
 
 // ObserveHTTPRequest records the number of HTTP requests and their duration.
@@ -98,3 +349,259 @@ func AddJWTTokensGenerated(count int) {
 	}
 	jwtTokensGeneratedTotal.Add(float64(count))
 }
+
+// IncPasswordGrantRequests increments the deprecated password grant counter for a client and outcome.
+// Requests without a registered client are counted with an empty clientID, labeled as unknown.
+func IncPasswordGrantRequests(clientID, outcome string) {
+	if clientID == "" {
+		clientID = "unknown"
+	}
+	passwordGrantRequestsTotal.WithLabelValues(clientID, outcome).Inc()
+}
+
+// IncRateLimitExceeded increments the counter of requests served over the soft rate limit.
+func IncRateLimitExceeded() {
+	rateLimitExceededTotal.Inc()
+}
+
+// ObservePasswordHash records the duration of a password hash or compare operation.
+func ObservePasswordHash(operation string, duration time.Duration) {
+	passwordHashDurationSeconds.WithLabelValues(operation).Observe(duration.Seconds())
+}
+
+// SetPasswordHashQueueDepth sets the number of password hashing operations waiting for a worker.
+func SetPasswordHashQueueDepth(depth int) {
+	passwordHashQueueDepth.Set(float64(depth))
+}
+
+// ObservePasswordHashQueueWait records how long a password hashing operation waited for a worker.
+func ObservePasswordHashQueueWait(duration time.Duration) {
+	passwordHashQueueWaitSeconds.Observe(duration.Seconds())
+}
+
+// SetExternalConnectivityInFlightCalls sets the number of calls to the external-connectivity service in progress.
+func SetExternalConnectivityInFlightCalls(calls int) {
+	externalConnectivityInFlightCalls.Set(float64(calls))
+}
+
+// SetExternalConnectivityQueuedCalls sets the number of calls to the external-connectivity service waiting for a slot.
+func SetExternalConnectivityQueuedCalls(calls int) {
+	externalConnectivityQueuedCalls.Set(float64(calls))
+}
+
+// ObserveExternalConnectivityQueueWait records how long a call to the external-connectivity service waited for a slot.
+func ObserveExternalConnectivityQueueWait(duration time.Duration) {
+	externalConnectivityQueueWaitSeconds.Observe(duration.Seconds())
+}
+
+// IncExternalConnectivityRejectedCalls increments the calls to the external-connectivity service rejected by reason
+// (queue_full, queue_timeout).
+func IncExternalConnectivityRejectedCalls(reason string) {
+	externalConnectivityRejectedCallsTotal.WithLabelValues(reason).Inc()
+}
+
+// IncDBRetry increments the counter of database operations retried after a transient error.
+func IncDBRetry(operation, class string) {
+	dbRetriesTotal.WithLabelValues(operation, class).Inc()
+}
+
+// IncDBRetryOutcome increments the counter of retried database operations by outcome
+// (recovered, exhausted or budget_exhausted).
+func IncDBRetryOutcome(operation, outcome string) {
+	dbRetryOutcomesTotal.WithLabelValues(operation, outcome).Inc()
+}
+
+// IncDBOperationTimeout increments the counter of database operation attempts aborted by the
+// per-operation timeout.
+func IncDBOperationTimeout(operation string) {
+	dbOperationTimeoutsTotal.WithLabelValues(operation).Inc()
+}
+
+// ObserveDBQueryDuration records how long an attempt of a database operation took.
+func ObserveDBQueryDuration(operation string, duration time.Duration) {
+	dbQueryDurationSeconds.WithLabelValues(operation).Observe(duration.Seconds())
+}
+
+// ObserveDBPoolWait records how long a database operation waited for a free connection.
+func ObserveDBPoolWait(duration time.Duration) {
+	dbPoolWaitSeconds.Observe(duration.Seconds())
+}
+
+// IncDBPoolRejectedOperation increments the counter of database operations rejected because the pool is exhausted.
+func IncDBPoolRejectedOperation(operation string) {
+	dbPoolRejectedOperationsTotal.WithLabelValues(operation).Inc()
+}
+
+// RegisterDBPoolStats exports the statistics of a connection pool (open, in use and idle connections, and the
+// count and total duration of the waits for a connection) as the go_sql_* metrics labelled with dbName.
+func RegisterDBPoolStats(db *sql.DB, dbName string) {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db, dbName))
+}
+
+// IncRedisCommandTimeout increments the counter of Redis commands aborted by the per-command timeout.
+func IncRedisCommandTimeout(command string) {
+	redisCommandTimeoutsTotal.WithLabelValues(command).Inc()
+}
+
+// IncHTTPPanics increments the counter of recovered HTTP handler panics.
+func IncHTTPPanics(method, endpoint string) {
+	httpPanicsTotal.WithLabelValues(method, endpoint).Inc()
+}
+
+// IncAlertNotifications increments the counter of critical alerts by outcome (sent, failed or dropped).
+func IncAlertNotifications(outcome string) {
+	alertNotificationsTotal.WithLabelValues(outcome).Inc()
+}
+
+// IncOversizedTokens increments the counter of access tokens issued above the maximum token size.
+func IncOversizedTokens(tokenType string) {
+	oversizedTokensTotal.WithLabelValues(tokenType).Inc()
+}
+
+// IncSMSMessages increments the counter of SMS handed to a provider by outcome (sent or failed).
+func IncSMSMessages(provider, outcome string) {
+	smsMessagesTotal.WithLabelValues(provider, outcome).Inc()
+}
+
+// IncSMSRejected increments the counter of SMS not sent because of a rate limit or the quota.
+func IncSMSRejected(reason string) {
+	smsRejectedTotal.WithLabelValues(reason).Inc()
+}
+
+// IncLoginAnomalies increments the counter of logins flagged as risky.
+func IncLoginAnomalies(reason string) {
+	loginAnomaliesTotal.WithLabelValues(reason).Inc()
+}
+
+// IncRiskDecision increments the counter of risk policy decisions.
+func IncRiskDecision(flow, decision string) {
+	riskDecisionsTotal.WithLabelValues(flow, decision).Inc()
+}
+
+// IncMessagePublish increments the counter of published messages by result (confirmed or failed).
+func IncMessagePublish(queue, result string) {
+	messagePublishesTotal.WithLabelValues(queue, result).Inc()
+}
+
+// IncMessagePublishAttemptFailure increments the counter of failed publish attempts by reason.
+func IncMessagePublishAttemptFailure(queue, reason string) {
+	messagePublishAttemptFailuresTotal.WithLabelValues(queue, reason).Inc()
+}
+
+// IncOutboxMessages increments the counter of outbox operations by outcome
+// (enqueued, enqueue_failed, delivered or delivery_failed).
+func IncOutboxMessages(outcome string) {
+	outboxMessagesTotal.WithLabelValues(outcome).Inc()
+}
+
+// IncConsumedMessages increments the counter of consumed messages by outcome
+// (acked, retried, dead_lettered or dropped).
+func IncConsumedMessages(queue, outcome string) {
+	consumedMessagesTotal.WithLabelValues(queue, outcome).Inc()
+}
+
+// IncLoginFailures increments the counter of failed logins by reason (unknown_user or invalid_password).
+func IncLoginFailures(reason string) {
+	loginFailuresTotal.WithLabelValues(reason).Inc()
+}
+
+// ObserveLoginFailuresPerIP records the failed logins from an IP address within the failure window.
+func ObserveLoginFailuresPerIP(failures int) {
+	loginFailuresPerIP.Observe(float64(failures))
+}
+
+// ObserveLoginFailuresPerEmail records the failed logins for an email address within the failure window.
+func ObserveLoginFailuresPerEmail(failures int) {
+	loginFailuresPerEmail.Observe(float64(failures))
+}
+
+// IncLoginLockouts increments the counter of logins denied on recent failed logins.
+func IncLoginLockouts() {
+	loginLockoutsTotal.Inc()
+}
+
+// IncLoginChallenges increments the counter of step-up challenges required on recent failed logins.
+func IncLoginChallenges() {
+	loginChallengesTotal.Inc()
+}
+
+// IncBruteForceAlerts increments the counter of brute force alerts by dimension (ip or email).
+func IncBruteForceAlerts(dimension string) {
+	bruteForceAlertsTotal.WithLabelValues(dimension).Inc()
+}
+
+// IncOAuthClientLockouts increments the counter of OAuth2 clients locked out after repeated authentication failures.
+func IncOAuthClientLockouts() {
+	oauthClientLockoutsTotal.Inc()
+}
+
+// IncOAuthClientTokensIssued increments the counter of client credentials tokens issued, by the values of the
+// team and env tags of the client, "none" for a client without the tag.
+func IncOAuthClientTokensIssued(team, env string) {
+	if team == "" {
+		team = "none"
+	}
+	if env == "" {
+		env = "none"
+	}
+	oauthClientTokensIssuedTotal.WithLabelValues(team, env).Inc()
+}
+
+// IncLoginNotifications increments the counter of login notifications sent through a channel, the result
+// being "sent", "suppressed" by the preferences of the user or "failed".
+func IncLoginNotifications(channel, result string) {
+	loginNotificationsTotal.WithLabelValues(channel, result).Inc()
+}
+
+// IncBlacklistReplications increments the counter of blacklisted tokens replicated between regions, the
+// direction being "published" to or "applied" from the other regions and the result "success" or "failed".
+func IncBlacklistReplications(direction, result string) {
+	blacklistReplicationsTotal.WithLabelValues(direction, result).Inc()
+}
+
+// IncRefreshAnomalies increments the counter of refreshes flagged as anomalous.
+func IncRefreshAnomalies(reason string) {
+	refreshAnomaliesTotal.WithLabelValues(reason).Inc()
+}
+
+// ObserveRefreshFamilyActivity records the activity of a refresh token family within the activity window.
+func ObserveRefreshFamilyActivity(distinctIPs, refreshes int) {
+	refreshFamilyDistinctIPs.Observe(float64(distinctIPs))
+	refreshFamilyRefreshes.Observe(float64(refreshes))
+}
+
+// IncQuotaRejections increments the counter of token issuances rejected by a quota.
+func IncQuotaRejections(subjectType, quota string) {
+	quotaRejectionsTotal.WithLabelValues(subjectType, quota).Inc()
+}
+
+// IncAuditExportRecords adds the audit records sent to the external audit sink by outcome (exported or failed).
+func IncAuditExportRecords(sink, outcome string, count int) {
+	auditExportRecordsTotal.WithLabelValues(sink, outcome).Add(float64(count))
+}
+
+// SetAuditExportBacklog sets the number of audit records waiting for export.
+func SetAuditExportBacklog(count int) {
+	auditExportBacklog.Set(float64(count))
+}
+
+// IncSignedRequestRejections increments the counter of token requests rejected by the replay protection.
+func IncSignedRequestRejections(reason string) {
+	signedRequestRejectionsTotal.WithLabelValues(reason).Inc()
+}
+
+// IncUserCacheLookups increments the counter of user lookups by cache result (hit, miss or bypass).
+func IncUserCacheLookups(result string) {
+	userCacheLookupsTotal.WithLabelValues(result).Inc()
+}
+
+// IncAdminExports increments the counter of admin dataset exports and adds the records they streamed.
+func IncAdminExports(dataset, result string, records int) {
+	adminExportsTotal.WithLabelValues(dataset, result).Inc()
+	adminExportRecordsTotal.WithLabelValues(dataset).Add(float64(records))
+}
+
+// IncUserExports increments the counter of personal data exports by result (completed, failed or rejected).
+func IncUserExports(result string) {
+	userExportsTotal.WithLabelValues(result).Inc()
+}
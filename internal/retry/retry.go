@@ -0,0 +1,139 @@
+// Package retry implements the exponential backoff with jitter shared by the clients of external
+// systems: the database, the message broker, HTTP services and webhooks.
+package retry
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// Jitter randomizes the backoff so that retries from many callers spread out
+type Jitter int
+
+const (
+	// NoJitter waits the exact backoff
+	NoJitter Jitter = iota
+	// EqualJitter waits a random duration in [d/2, d], keeping at least half of the backoff
+	EqualJitter
+	// FullJitter waits a random duration in [0, d], the widest spread for many concurrent callers
+	FullJitter
+)
+
+// Policy configures how an operation is retried. The backoff starts at InitialBackoff and doubles with
+// every retry up to MaxBackoff.
+type Policy struct {
+	// MaxAttempts is the total number of attempts, 1 disables retries and 0 retries until the context is done
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration // 0 leaves the backoff uncapped
+	Jitter         Jitter
+}
+
+// permanentError marks an error that must not be retried
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks an error returned by the operation of Do as not retryable, e.g. a 4xx response. The
+// mark survives wrapping, and Do returns the error without it.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent checks if an error was marked as not retryable
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+// Backoff returns the delay before the given retry, 1 for the first one, with the jitter applied
+func (p Policy) Backoff(retry int) time.Duration {
+	backoff := p.InitialBackoff
+	for i := 1; i < retry && backoff > 0 && (p.MaxBackoff <= 0 || backoff < p.MaxBackoff); i++ {
+		if backoff > math.MaxInt64/2 {
+			break
+		}
+		backoff *= 2
+	}
+	if p.MaxBackoff > 0 {
+		backoff = min(backoff, p.MaxBackoff)
+	}
+	return p.Jitter.apply(backoff)
+}
+
+// Do calls fn until it succeeds, returns a permanent error, the attempts are exhausted or ctx is done,
+// and returns the last error of fn
+func (p Policy) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return p.DoNotify(ctx, fn, nil)
+}
+
+// DoNotify is like Do, and calls notify with the failed attempt, its error and the backoff before each retry
+func (p Policy) DoNotify(ctx context.Context, fn func(ctx context.Context) error, notify func(attempt int, err error, backoff time.Duration)) error {
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+
+		// A wrapped permanent error is returned as it is, only the mark is transparent
+		if permanent, ok := err.(*permanentError); ok {
+			return permanent.err
+		}
+		if IsPermanent(err) {
+			return err
+		}
+		if (p.MaxAttempts > 0 && attempt >= p.MaxAttempts) || ctx.Err() != nil {
+			return err
+		}
+
+		backoff := p.Backoff(attempt)
+		if notify != nil {
+			notify(attempt, err, backoff)
+		}
+		if Sleep(ctx, backoff) != nil {
+			return err
+		}
+	}
+}
+
+// Sleep waits for the duration or until the context is done, returning the error of the context
+func Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// apply returns the backoff with the jitter applied
+func (j Jitter) apply(d time.Duration) time.Duration {
+	if d <= 1 {
+		return d
+	}
+	switch j {
+	case EqualJitter:
+		half := d / 2
+		return half + rand.N(d-half+1)
+	case FullJitter:
+		return rand.N(d + 1)
+	default:
+		return d
+	}
+}
@@ -0,0 +1,153 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kristianrpo/auth-microservice/internal/retry"
+)
+
+func TestPolicy_Backoff(t *testing.T) {
+	policy := retry.Policy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	tests := []struct {
+		retry int
+		want  time.Duration
+	}{
+		{retry: 1, want: 100 * time.Millisecond},
+		{retry: 2, want: 200 * time.Millisecond},
+		{retry: 4, want: 800 * time.Millisecond},
+		{retry: 5, want: time.Second},
+		{retry: 100, want: time.Second},
+	}
+	for _, tt := range tests {
+		if got := policy.Backoff(tt.retry); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.retry, got, tt.want)
+		}
+	}
+
+	uncapped := retry.Policy{InitialBackoff: time.Second}
+	if got := uncapped.Backoff(1000); got <= 0 {
+		t.Errorf("uncapped Backoff(1000) = %v, want no overflow", got)
+	}
+}
+
+func TestPolicy_BackoffJitter(t *testing.T) {
+	tests := []struct {
+		jitter retry.Jitter
+		min    time.Duration
+	}{
+		{jitter: retry.EqualJitter, min: 400 * time.Millisecond},
+		{jitter: retry.FullJitter, min: 0},
+	}
+	for _, tt := range tests {
+		policy := retry.Policy{InitialBackoff: 800 * time.Millisecond, Jitter: tt.jitter}
+		for i := 0; i < 100; i++ {
+			if got := policy.Backoff(1); got < tt.min || got > 800*time.Millisecond {
+				t.Fatalf("Backoff() with jitter %d = %v, want in [%v, 800ms]", tt.jitter, got, tt.min)
+			}
+		}
+	}
+}
+
+func TestPolicy_Do(t *testing.T) {
+	errTransient := errors.New("transient")
+	errRejected := errors.New("rejected")
+
+	tests := []struct {
+		name         string
+		maxAttempts  int
+		failures     int   // attempts failing before success
+		failWith     error // the error of the failed attempts
+		wantAttempts int
+		wantErr      error
+	}{
+		{name: "first attempt succeeds", maxAttempts: 3, wantAttempts: 1},
+		{name: "recovers after retries", maxAttempts: 3, failures: 2, failWith: errTransient, wantAttempts: 3},
+		{name: "attempts exhausted", maxAttempts: 3, failures: 5, failWith: errTransient, wantAttempts: 3, wantErr: errTransient},
+		{name: "permanent error", maxAttempts: 3, failures: 5, failWith: retry.Permanent(errRejected), wantAttempts: 1, wantErr: errRejected},
+		{name: "unlimited attempts", failures: 5, failWith: errTransient, wantAttempts: 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := retry.Policy{MaxAttempts: tt.maxAttempts, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+			attempts, notified := 0, 0
+			err := policy.DoNotify(context.Background(), func(ctx context.Context) error {
+				attempts++
+				if attempts <= tt.failures {
+					return tt.failWith
+				}
+				return nil
+			}, func(attempt int, err error, backoff time.Duration) {
+				notified++
+			})
+
+			if attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+			if notified != attempts-1 {
+				t.Errorf("notified %d retries, want %d", notified, attempts-1)
+			}
+			if err != tt.wantErr {
+				t.Errorf("Do() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPolicy_DoStopsWhenContextIsDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	errTransient := errors.New("transient")
+
+	attempts := 0
+	err := retry.Policy{InitialBackoff: time.Hour}.Do(ctx, func(ctx context.Context) error {
+		attempts++
+		cancel()
+		return errTransient
+	})
+	if !errors.Is(err, errTransient) || attempts != 1 {
+		t.Errorf("Do() = %v after %d attempts, want the error of the only attempt", err, attempts)
+	}
+}
+
+func TestSleep(t *testing.T) {
+	if err := retry.Sleep(context.Background(), time.Millisecond); err != nil {
+		t.Errorf("Sleep() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := retry.Sleep(ctx, time.Hour); !errors.Is(err, context.Canceled) {
+		t.Errorf("Sleep() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestIsPermanent(t *testing.T) {
+	err := retry.Permanent(errors.New("rejected"))
+	if !retry.IsPermanent(err) || retry.IsPermanent(errors.New("transient")) {
+		t.Error("IsPermanent() does not recognize permanent errors")
+	}
+	if retry.Permanent(nil) != nil {
+		t.Error("Permanent(nil) != nil")
+	}
+}
+
+func TestPolicy_DoWrappedPermanentError(t *testing.T) {
+	errRejected := errors.New("rejected")
+
+	attempts := 0
+	err := retry.Policy{MaxAttempts: 3}.Do(context.Background(), func(ctx context.Context) error {
+		attempts++
+		return fmt.Errorf("failed to get token: %w", retry.Permanent(errRejected))
+	})
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+	if !errors.Is(err, errRejected) || err.Error() != "failed to get token: rejected" {
+		t.Errorf("Do() error = %v, want the wrapped error", err)
+	}
+}
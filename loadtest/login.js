@@ -0,0 +1,66 @@
+// Login load test for the auth microservice.
+//
+//   k6 run -e BASE_URL=http://localhost:8080 \
+//          -e EMAIL=bench@example.com -e PASSWORD=password123 loadtest/login.js
+//
+// The thresholds below are the release SLOs for the auth path (see loadtest/README.md);
+// k6 exits with a non-zero code when any of them is not met.
+import http from 'k6/http';
+import { check, fail } from 'k6';
+
+const BASE_URL = __ENV.BASE_URL || 'http://localhost:8080';
+const EMAIL = __ENV.EMAIL;
+const PASSWORD = __ENV.PASSWORD;
+const RATE = parseInt(__ENV.RATE || '50', 10);
+const DURATION = __ENV.DURATION || '2m';
+
+export const options = {
+  scenarios: {
+    login: {
+      executor: 'constant-arrival-rate',
+      rate: RATE,
+      timeUnit: '1s',
+      duration: DURATION,
+      preAllocatedVUs: Math.max(RATE, 10),
+      maxVUs: RATE * 4,
+    },
+  },
+  thresholds: {
+    'http_req_duration{name:login}': ['p(95)<300', 'p(99)<500'],
+    'http_req_duration{name:me}': ['p(95)<50', 'p(99)<100'],
+    http_req_failed: ['rate<0.01'],
+    checks: ['rate>0.99'],
+    dropped_iterations: ['count<1'],
+  },
+};
+
+export function setup() {
+  if (!EMAIL || !PASSWORD) {
+    fail('EMAIL and PASSWORD must be set to an existing user');
+  }
+}
+
+export default function () {
+  const loginRes = http.post(
+    `${BASE_URL}/api/auth/login`,
+    JSON.stringify({ email: EMAIL, password: PASSWORD }),
+    { headers: { 'Content-Type': 'application/json' }, tags: { name: 'login' } },
+  );
+
+  const ok = check(loginRes, {
+    'login status is 200': (r) => r.status === 200,
+    'login returns an access token': (r) => !!r.json('access_token'),
+  });
+  if (!ok) {
+    return;
+  }
+
+  const meRes = http.get(`${BASE_URL}/api/auth/me`, {
+    headers: { Authorization: `Bearer ${loginRes.json('access_token')}` },
+    tags: { name: 'me' },
+  });
+
+  check(meRes, {
+    'me status is 200': (r) => r.status === 200,
+  });
+}
@@ -0,0 +1,116 @@
+package loadtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/request"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/dto/response"
+)
+
+// BenchmarkLogin_Stack runs the login endpoint end to end against a running stack
+// (docker compose up) and is skipped otherwise:
+//
+//	AUTH_BENCH_BASE_URL=http://localhost:8080 \
+//	AUTH_BENCH_EMAIL=bench@example.com AUTH_BENCH_PASSWORD=password123 \
+//	go test -run '^$' -bench Login_Stack -cpu 1,8,32 ./loadtest/
+//
+// The user must exist beforehand. Latency percentiles are reported next to ns/op so they
+// can be compared with the SLOs in README.md.
+func BenchmarkLogin_Stack(b *testing.B) {
+	baseURL := os.Getenv("AUTH_BENCH_BASE_URL")
+	if baseURL == "" {
+		b.Skip("AUTH_BENCH_BASE_URL not set, skipping stack benchmarks")
+	}
+
+	body, err := json.Marshal(request.LoginRequest{
+		Email:    os.Getenv("AUTH_BENCH_EMAIL"),
+		Password: os.Getenv("AUTH_BENCH_PASSWORD"),
+	})
+	if err != nil {
+		b.Fatalf("failed to encode login request: %v", err)
+	}
+
+	client := &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: &http.Transport{MaxIdleConnsPerHost: 256},
+	}
+	url := baseURL + "/api/auth/login"
+
+	// Fail fast on bad credentials instead of benchmarking 401s
+	if _, err := login(client, url, body); err != nil {
+		b.Fatalf("login against %s failed: %v", baseURL, err)
+	}
+
+	var (
+		mu        sync.Mutex
+		latencies = make([]time.Duration, 0, b.N)
+	)
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		local := make([]time.Duration, 0, 64)
+		for pb.Next() {
+			elapsed, err := login(client, url, body)
+			if err != nil {
+				b.Errorf("login failed: %v", err)
+				return
+			}
+			local = append(local, elapsed)
+		}
+
+		mu.Lock()
+		latencies = append(latencies, local...)
+		mu.Unlock()
+	})
+	b.StopTimer()
+
+	reportPercentiles(b, latencies)
+}
+
+// login performs a single login request and returns its latency
+func login(client *http.Client, url string, body []byte) (time.Duration, error) {
+	start := time.Now()
+
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return 0, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var tokens response.TokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tokens); err != nil {
+		return 0, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if tokens.AccessToken == "" {
+		return 0, fmt.Errorf("empty access token")
+	}
+
+	return time.Since(start), nil
+}
+
+// reportPercentiles adds p50, p95 and p99 latencies in milliseconds to the benchmark output
+func reportPercentiles(b *testing.B, latencies []time.Duration) {
+	if len(latencies) == 0 {
+		return
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	for _, p := range []int{50, 95, 99} {
+		idx := (len(latencies)*p+99)/100 - 1
+		b.ReportMetric(float64(latencies[idx])/float64(time.Millisecond), fmt.Sprintf("p%d-ms", p))
+	}
+}
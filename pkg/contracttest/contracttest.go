@@ -0,0 +1,145 @@
+// Package contracttest runs an in-memory fake of the auth-microservice, so the services that call it can run
+// their contract tests without docker. The fake serves the register, login and validate endpoints with the
+// handlers and the services of the real API, backed by in-memory stores instead of Postgres, Redis, RabbitMQ
+// and the centralizer, so requests, responses and error codes are the same.
+//
+// Access tokens are HS256 JWTs signed with Secret, and users get sequential IDs, so the same test produces
+// the same users and claims on every run.
+//
+//	srv := contracttest.NewServer(t)
+//	resp, err := http.Post(srv.Endpoint("/login"), "application/json", body)
+package contracttest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/auth"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/handler/shared"
+	"github.com/kristianrpo/auth-microservice/internal/adapters/http/middleware"
+	"github.com/kristianrpo/auth-microservice/internal/application/services"
+	domain "github.com/kristianrpo/auth-microservice/internal/domain/models"
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/hashing"
+	"github.com/kristianrpo/auth-microservice/internal/infrastructure/memory"
+)
+
+// Secret is the HS256 secret the access tokens of the fake are signed with
+const Secret = "contracttest-secret-not-for-production-use"
+
+// BasePath is the path prefix of the routes, like the API behind the gateway
+const BasePath = "/api/auth"
+
+// UserRegisteredQueue is the queue the user registered events are published to
+const UserRegisteredQueue = "auth.user.registered"
+
+// Token lifetimes of the fake, the same as the defaults of the service
+const (
+	AccessTokenDuration  = 15 * time.Minute
+	RefreshTokenDuration = 7 * 24 * time.Hour
+)
+
+// User is a user added to the fake before the test
+type User struct {
+	IDCitizen int
+	Email     string
+	Password  string
+	Name      string
+	Admin     bool
+}
+
+// Server is a running fake of the auth-microservice
+type Server struct {
+	*httptest.Server
+
+	users      *memory.UserRepository
+	publisher  *memory.MessagePublisher
+	hasher     *hashing.BcryptHasher
+	jwtService *services.JWTService
+}
+
+// NewServer starts a fake of the auth-microservice, which is closed when the test ends
+func NewServer(tb testing.TB) *Server {
+	tb.Helper()
+
+	logger := zap.NewNop()
+	s := &Server{
+		users:     memory.NewUserRepository(),
+		publisher: memory.NewMessagePublisher(),
+		// The minimum cost keeps the tests fast, the hashes are the same format as in production
+		hasher:     hashing.NewBcryptHasher(bcrypt.MinCost),
+		jwtService: services.NewJWTService(Secret, AccessTokenDuration, RefreshTokenDuration, false, nil, services.TokenSigningPolicy{}, logger),
+	}
+
+	authService := services.NewAuthService(
+		s.users,
+		memory.NewTokenRepository(),
+		s.jwtService,
+		s.hasher,
+		logger,
+		services.WithPublisher(s.publisher, UserRegisteredQueue),
+		services.WithConnectivityClient(memory.NewExternalConnectivityClient()),
+	)
+	authHandler := shared.NewAuthHandler(authService, nil, nil, false, nil, logger)
+
+	router := mux.NewRouter()
+	router.Use(middleware.RequestIDMiddleware)
+	api := router.PathPrefix(BasePath).Subrouter()
+	api.HandleFunc("/register", auth.Register(authHandler)).Methods(http.MethodPost)
+	api.HandleFunc("/login", auth.Login(authHandler)).Methods(http.MethodPost)
+	api.HandleFunc("/validate", auth.Validate(authHandler)).Methods(http.MethodGet, http.MethodHead)
+
+	s.Server = httptest.NewServer(router)
+	tb.Cleanup(s.Close)
+	return s
+}
+
+// Endpoint returns the URL of a route, e.g. Endpoint("/login")
+func (s *Server) Endpoint(path string) string {
+	return s.URL + BasePath + path
+}
+
+// AddUser adds an active user that can sign in right away, and returns its ID
+func (s *Server) AddUser(user User) (string, error) {
+	ctx := context.Background()
+	u, err := domain.NewUserWithHasher(user.Email, user.Password, user.Name, user.IDCitizen, domain.DefaultNameLimits(), func(password string) (string, error) {
+		return s.hasher.Hash(ctx, password)
+	})
+	if err != nil {
+		return "", fmt.Errorf("invalid user: %w", err)
+	}
+	if user.Admin {
+		u.Role = domain.RoleAdmin
+	}
+
+	if err := s.users.Create(ctx, u); err != nil {
+		return "", fmt.Errorf("failed to add user: %w", err)
+	}
+	return u.ID, nil
+}
+
+// AccessToken issues an access token for a user, like a login without the password
+func (s *Server) AccessToken(email string) (string, error) {
+	user, err := s.users.GetByEmail(context.Background(), email)
+	if err != nil {
+		return "", fmt.Errorf("failed to get user: %w", err)
+	}
+
+	tokens, err := s.jwtService.GenerateUserTokenPair(user)
+	if err != nil {
+		return "", err
+	}
+	return tokens.AccessToken, nil
+}
+
+// RegisteredEvents returns the user registered events published so far, oldest first
+func (s *Server) RegisteredEvents() [][]byte {
+	return s.publisher.Messages(UserRegisteredQueue)
+}
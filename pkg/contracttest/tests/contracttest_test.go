@@ -0,0 +1,130 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/kristianrpo/auth-microservice/pkg/contracttest"
+)
+
+func post(t *testing.T, url string, body interface{}) *http.Response {
+	t.Helper()
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("POST %s error = %v", url, err)
+	}
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+func validate(t *testing.T, srv *contracttest.Server, token string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, srv.Endpoint("/validate"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /validate error = %v", err)
+	}
+	_ = resp.Body.Close()
+	return resp
+}
+
+func TestServer_RegisterLoginValidate(t *testing.T) {
+	srv := contracttest.NewServer(t)
+
+	resp := post(t, srv.Endpoint("/register"), map[string]interface{}{
+		"id_citizen": 12345, "email": "citizen@example.com", "password": "password123", "name": "Citizen",
+	})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("register status = %d, want %d", resp.StatusCode, http.StatusCreated)
+	}
+	var user struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		t.Fatal(err)
+	}
+	if user.ID != "00000000-0000-4000-8000-000000000001" {
+		t.Errorf("user ID = %q, want the first sequential ID", user.ID)
+	}
+	if len(srv.RegisteredEvents()) != 1 {
+		t.Errorf("registered events = %d, want 1", len(srv.RegisteredEvents()))
+	}
+
+	resp = post(t, srv.Endpoint("/register"), map[string]interface{}{
+		"id_citizen": 12345, "email": "citizen@example.com", "password": "password123", "name": "Citizen",
+	})
+	var errResp struct {
+		Code string `json:"code"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusConflict || errResp.Code != "USER_ALREADY_EXISTS" {
+		t.Errorf("duplicate register = %d %q, want 409 USER_ALREADY_EXISTS", resp.StatusCode, errResp.Code)
+	}
+
+	resp = post(t, srv.Endpoint("/login"), map[string]string{"email": "citizen@example.com", "password": "wrong-password"})
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("login with a wrong password status = %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+
+	resp = post(t, srv.Endpoint("/login"), map[string]string{"email": "citizen@example.com", "password": "password123"})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("login status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	var tokens struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokens); err != nil {
+		t.Fatal(err)
+	}
+
+	resp = validate(t, srv, tokens.AccessToken)
+	if resp.StatusCode != http.StatusNoContent || resp.Header.Get("X-User-Id") != user.ID {
+		t.Errorf("validate = %d X-User-Id %q, want 204 for %s", resp.StatusCode, resp.Header.Get("X-User-Id"), user.ID)
+	}
+	if resp := validate(t, srv, "not-a-token"); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("validate with an invalid token status = %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+}
+
+func TestServer_AddUserAndAccessToken(t *testing.T) {
+	srv := contracttest.NewServer(t)
+
+	if _, err := srv.AddUser(contracttest.User{IDCitizen: 1, Email: "admin@example.com", Password: "password123", Name: "Admin", Admin: true}); err != nil {
+		t.Fatalf("AddUser() error = %v", err)
+	}
+	if _, err := srv.AddUser(contracttest.User{IDCitizen: 1, Email: "other@example.com", Password: "password123", Name: "Other"}); err == nil {
+		t.Error("AddUser() with a taken citizen ID error = nil")
+	}
+
+	token, err := srv.AccessToken("admin@example.com")
+	if err != nil {
+		t.Fatalf("AccessToken() error = %v", err)
+	}
+
+	// Consumers can verify the tokens with the well-known secret
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) { return []byte(contracttest.Secret), nil }); err != nil {
+		t.Fatalf("token signed with Secret error = %v", err)
+	}
+	if claims["role"] != "ADMIN" {
+		t.Errorf("role claim = %v, want ADMIN", claims["role"])
+	}
+
+	if resp := validate(t, srv, token); resp.StatusCode != http.StatusNoContent || resp.Header.Get("X-User-Role") != "ADMIN" {
+		t.Errorf("validate = %d X-User-Role %q, want 204 ADMIN", resp.StatusCode, resp.Header.Get("X-User-Role"))
+	}
+}